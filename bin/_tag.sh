@@ -6,6 +6,20 @@ git_sha_head() {
     git rev-parse --short=8 HEAD
 }
 
+git_sha_full() {
+    git rev-parse HEAD
+}
+
+build_date() {
+    date -u +%Y-%m-%dT%H:%M:%SZ
+}
+
+# The ldflags that set the version and the build info of the Go binaries
+version_ldflags() {
+    pkg=github.com/linkerd/linkerd2/pkg/version
+    echo "-X $pkg.Version=$(head_root_tag) -X $pkg.GitSHA=$(git_sha_full) -X $pkg.BuildDate=$(build_date)"
+}
+
 go_deps_sha() {
     bindir="$( cd "$( dirname "${BASH_SOURCE[0]}" )" && pwd )"
     rootdir="$( cd $bindir/.. && pwd )"
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/linkerd/linkerd2/cli/install"
+	"github.com/linkerd/linkerd2/pkg/healthcheck"
+	"github.com/spf13/cobra"
+)
+
+// addOn is an optional bundle of control plane components, which `linkerd
+// install` renders when its flag is set. Its template is executed with an
+// addOnConfig, whose Values start from the add-on's defaults and can be
+// overridden with --addon-set.
+type addOn struct {
+	name        string
+	description string
+	template    string
+
+	// enabled is whether the add-on is installed unless its flag is unset
+	enabled bool
+
+	// defaults returns the default values of the add-on
+	defaults func(options *installOptions) map[string]string
+
+	// checks are the health checks that `linkerd check --addon` runs for the
+	// add-on
+	checks healthcheck.CategoryID
+}
+
+// addOnConfig is the data that the templates of the add-ons are executed
+// with.
+type addOnConfig struct {
+	installConfig
+	Values map[string]string
+}
+
+// addOns are the add-ons of `linkerd install`, in the order in which they're
+// rendered.
+var addOns = []addOn{
+	{
+		name:        "grafana",
+		description: "Install the grafana add-on, which serves the dashboards of the web UI",
+		template:    install.GrafanaTemplate,
+		enabled:     true,
+		defaults: func(options *installOptions) map[string]string {
+			return map[string]string{
+				"image": options.imageRef(options.dockerRegistry+"/grafana", options.linkerdVersion),
+			}
+		},
+		checks: healthcheck.LinkerdGrafanaChecks,
+	},
+	{
+		name:        "tracing",
+		description: "Experimental: Install the tracing add-on, an OpenCensus collector and Jaeger; applications send their spans to the collector at linkerd-collector.<namespace>:55678",
+		template:    install.TracingTemplate,
+		defaults: func(options *installOptions) map[string]string {
+			return map[string]string{
+				"collectorImage": options.thirdPartyImageRef("omnition/opencensus-collector:0.1.10"),
+				"jaegerImage":    options.thirdPartyImageRef("jaegertracing/all-in-one:1.8"),
+			}
+		},
+		checks: healthcheck.LinkerdTracingChecks,
+	},
+	{
+		name:        "flagger",
+		description: "Experimental: Install the flagger add-on, which progressively shifts the traffic of Canary resources to their new versions with TrafficSplits, based on their metrics",
+		template:    install.FlaggerTemplate,
+		defaults: func(options *installOptions) map[string]string {
+			return map[string]string{
+				"image":    options.thirdPartyImageRef("weaveworks/flagger:0.13.2"),
+				"logLevel": "info",
+			}
+		},
+		checks: healthcheck.LinkerdFlaggerChecks,
+	},
+}
+
+// newAddOnOptions returns whether each add-on is enabled by default, by name.
+func newAddOnOptions() map[string]*bool {
+	enabled := map[string]*bool{}
+	for _, a := range addOns {
+		e := a.enabled
+		enabled[a.name] = &e
+	}
+	return enabled
+}
+
+func findAddOn(name string) (addOn, bool) {
+	for _, a := range addOns {
+		if a.name == name {
+			return a, true
+		}
+	}
+	return addOn{}, false
+}
+
+func addOnNames() []string {
+	names := make([]string, len(addOns))
+	for i, a := range addOns {
+		names[i] = a.name
+	}
+	return names
+}
+
+// addAddOnFlags adds an enable flag for each add-on to cmd, along with
+// --addon-set.
+func addAddOnFlags(cmd *cobra.Command, options *installOptions) {
+	for _, a := range addOns {
+		description := a.description
+		if !a.enabled {
+			description += " (default false)"
+		}
+		cmd.PersistentFlags().BoolVar(options.addOns[a.name], a.name, a.enabled, description)
+	}
+	cmd.PersistentFlags().StringArrayVar(&options.addOnValues, "addon-set", options.addOnValues, fmt.Sprintf("Override a value of an enabled add-on, as <add-on>.<key>=<value>, for example \"tracing.jaegerImage=jaegertracing/all-in-one:1.9\" (may be repeated); the add-ons are: %s", strings.Join(addOnNames(), ", ")))
+}
+
+// parseAddOnValues returns the values of the enabled add-ons, by add-on, with
+// the --addon-set overrides applied to their defaults.
+func parseAddOnValues(options *installOptions) (map[string]map[string]string, error) {
+	values := map[string]map[string]string{}
+	for _, a := range addOns {
+		if *options.addOns[a.name] {
+			values[a.name] = a.defaults(options)
+		}
+	}
+
+	for _, override := range options.addOnValues {
+		kv := strings.SplitN(override, "=", 2)
+		path := strings.SplitN(kv[0], ".", 2)
+		if len(kv) != 2 || len(path) != 2 {
+			return nil, fmt.Errorf("Invalid value '%s' for --addon-set flag: must be of the form <add-on>.<key>=<value>", override)
+		}
+
+		a, ok := findAddOn(path[0])
+		if !ok {
+			return nil, fmt.Errorf("Invalid value '%s' for --addon-set flag: unknown add-on '%s', must be one of: %s", override, path[0], strings.Join(addOnNames(), ", "))
+		}
+		addOnValues, enabled := values[a.name]
+		if !enabled {
+			return nil, fmt.Errorf("Invalid value '%s' for --addon-set flag: the %s add-on isn't enabled", override, a.name)
+		}
+		if _, ok := addOnValues[path[1]]; !ok {
+			keys := []string{}
+			for key := range a.defaults(options) {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			return nil, fmt.Errorf("Invalid value '%s' for --addon-set flag: unknown key '%s' of the %s add-on, must be one of: %s", override, path[1], a.name, strings.Join(keys, ", "))
+		}
+		addOnValues[path[1]] = kv[1]
+	}
+	return values, nil
+}
+
+// renderAddOns renders the templates of the add-ons enabled in config.
+func renderAddOns(config installConfig, w io.Writer) error {
+	for _, a := range addOns {
+		values, ok := config.AddOns[a.name]
+		if !ok {
+			continue
+		}
+		addOnTemplate, err := template.New("linkerd").Parse(a.template)
+		if err != nil {
+			return err
+		}
+		if _, err := addOnTemplate.New("placement").Parse(install.PlacementTemplate); err != nil {
+			return err
+		}
+		if err := addOnTemplate.Execute(w, addOnConfig{config, values}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
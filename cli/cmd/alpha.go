@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// alphaEnvVar opts in to the alpha commands, as --enable-alpha does.
+const alphaEnvVar = "LINKERD_ENABLE_ALPHA"
+
+// alphaCommands build the experimental subcommands of `linkerd alpha`. They
+// can change or go away in any release, and only run once the user has opted
+// in to them.
+var alphaCommands = []func() *cobra.Command{}
+
+func newCmdAlpha() *cobra.Command {
+	enableAlpha := false
+
+	cmd := &cobra.Command{
+		Use:   "alpha [flags] COMMAND",
+		Short: "Experimental commands, which may change or be removed in any release",
+		Long: `Experimental commands, which may change or be removed in any release.
+
+The alpha commands only run with --enable-alpha, or with the ` + alphaEnvVar + `
+environment variable set to true, so that scripts don't come to depend on them
+by accident.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// cobra only runs the closest PersistentPreRunE
+			if err := RootCmd.PersistentPreRunE(cmd, args); err != nil {
+				return err
+			}
+			return checkAlphaEnabled(enableAlpha, os.Getenv(alphaEnvVar))
+		},
+	}
+
+	cmd.PersistentFlags().BoolVar(&enableAlpha, "enable-alpha", enableAlpha, "Opt in to the experimental commands [$"+alphaEnvVar+"]")
+
+	for _, newCmd := range alphaCommands {
+		cmd.AddCommand(newCmd())
+	}
+
+	return cmd
+}
+
+func checkAlphaEnabled(enableAlpha bool, env string) error {
+	if enableAlpha {
+		return nil
+	}
+	if enabled, err := strconv.ParseBool(env); err == nil && enabled {
+		return nil
+	}
+	return errors.New("The alpha commands are experimental, and may change or be removed in any release; run them with --enable-alpha, or with " + alphaEnvVar + "=true, to opt in")
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCheckAlphaEnabled(t *testing.T) {
+	testCases := []struct {
+		enableAlpha bool
+		env         string
+		enabled     bool
+	}{
+		{enableAlpha: true, env: "", enabled: true},
+		{enableAlpha: false, env: "true", enabled: true},
+		{enableAlpha: false, env: "1", enabled: true},
+		{enableAlpha: false, env: "", enabled: false},
+		{enableAlpha: false, env: "false", enabled: false},
+		{enableAlpha: false, env: "yes please", enabled: false},
+	}
+
+	for _, tc := range testCases {
+		err := checkAlphaEnabled(tc.enableAlpha, tc.env)
+		if tc.enabled && err != nil {
+			t.Fatalf("Unexpected error for --enable-alpha=%t and %s=%q: %s", tc.enableAlpha, alphaEnvVar, tc.env, err)
+		}
+		if !tc.enabled && err == nil {
+			t.Fatalf("Expected an error for --enable-alpha=%t and %s=%q", tc.enableAlpha, alphaEnvVar, tc.env)
+		}
+	}
+}
+
+func TestAlphaCommands(t *testing.T) {
+	ran := false
+	defer func(commands []func() *cobra.Command) { alphaCommands = commands }(alphaCommands)
+	alphaCommands = []func() *cobra.Command{
+		func() *cobra.Command {
+			return &cobra.Command{
+				Use: "experiment",
+				RunE: func(cmd *cobra.Command, args []string) error {
+					ran = true
+					return nil
+				},
+			}
+		},
+	}
+	os.Unsetenv(alphaEnvVar)
+
+	run := func(args ...string) error {
+		ran = false
+		cmd := newCmdAlpha()
+		cmd.SetArgs(args)
+		cmd.SetOutput(ioutil.Discard)
+		return cmd.Execute()
+	}
+
+	if err := run("experiment"); err == nil || ran {
+		t.Fatal("Expected the alpha command not to run without opting in")
+	}
+	if err := run("experiment", "--enable-alpha"); err != nil || !ran {
+		t.Fatalf("Expected the alpha command to run with --enable-alpha, got: %v", err)
+	}
+
+	os.Setenv(alphaEnvVar, "true")
+	defer os.Unsetenv(alphaEnvVar)
+	if err := run("experiment"); err != nil || !ran {
+		t.Fatalf("Expected the alpha command to run with %s=true, got: %v", alphaEnvVar, err)
+	}
+}
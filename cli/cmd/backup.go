@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/ghodss/yaml"
+	spclient "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/scrypt"
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// backupMagic starts every backup archive, followed by the scrypt salt, the
+	// AES-GCM nonce, and the encrypted YAML stream of the backed up resources.
+	backupMagic = "linkerd-backup-v1\n"
+
+	backupSaltSize = 16
+	backupKeySize  = 32
+
+	// the scrypt cost parameters recommended for interactive use
+	backupScryptN = 1 << 15
+	backupScryptR = 8
+	backupScryptP = 1
+)
+
+type backupOptions struct {
+	passphraseFile  string
+	tlsIssuerSecret string
+}
+
+func newBackupOptions() *backupOptions {
+	return &backupOptions{
+		passphraseFile:  "",
+		tlsIssuerSecret: defaultTLSIssuerSecret,
+	}
+}
+
+func newCmdBackup() *cobra.Command {
+	options := newBackupOptions()
+
+	cmd := &cobra.Command{
+		Use:   "backup [flags] FILE",
+		Short: "Export the state of the control plane to an encrypted archive",
+		Long: `Export the state of the control plane to an encrypted archive.
+
+The archive holds the CA's issuer secret, the trust anchors, and the service
+profiles of the control plane, encrypted with a key derived from the contents of
+--passphrase-file. "linkerd restore" outputs them again, to recover the mesh in
+a fresh cluster without changing its identity.
+
+Only a CA that signs with an issuer secret (see "linkerd install
+--tls-issuer-secret") can be restored: a CA that generated its own credentials
+never stores its private key, so its certificates can't be issued again.`,
+		Example: `  # Back up the control plane.
+  linkerd backup --passphrase-file passphrase.txt linkerd-backup.enc`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			passphrase, err := readPassphrase(options.passphraseFile)
+			if err != nil {
+				return err
+			}
+
+			kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+			if err != nil {
+				return err
+			}
+			clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+			if err != nil {
+				return err
+			}
+			spClientset, err := spclient.NewForConfig(kubeAPI.Config)
+			if err != nil {
+				return err
+			}
+
+			resources, err := collectBackup(clientset, spClientset, controlPlaneNamespace, options.tlsIssuerSecret, os.Stderr)
+			if err != nil {
+				return err
+			}
+			archive, err := encryptBackup(resources, passphrase)
+			if err != nil {
+				return err
+			}
+			return ioutil.WriteFile(args[0], archive, 0600)
+		},
+	}
+
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.PersistentFlags().StringVar(&options.passphraseFile, "passphrase-file", options.passphraseFile, "Path to a file with the passphrase to encrypt the archive with (required)")
+	cmd.PersistentFlags().StringVar(&options.tlsIssuerSecret, "tls-issuer-secret", options.tlsIssuerSecret, "Name of the secret in the control plane namespace that the CA signs certificates with")
+
+	return cmd
+}
+
+func newCmdRestore() *cobra.Command {
+	var passphraseFile string
+
+	cmd := &cobra.Command{
+		Use:   "restore [flags] FILE",
+		Short: "Output Kubernetes configs to restore a control plane backup",
+		Long: `Output Kubernetes configs to restore a control plane backup.
+
+The resources of an archive made with "linkerd backup" are output in the control
+plane namespace, which may differ from the one they were backed up from. Restore
+them right after installing the control plane with --tls-issuer-secret set to
+the name of the backed up issuer secret: the CA doesn't start until the secret
+exists, and then issues certificates that the restored trust anchors validate.`,
+		Example: `  # Install the control plane in a fresh cluster, and restore the backup.
+  linkerd install --tls=optional --tls-issuer-secret linkerd-ca-issuer | kubectl apply -f -
+  linkerd restore --passphrase-file passphrase.txt linkerd-backup.enc | kubectl apply -f -`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			passphrase, err := readPassphrase(passphraseFile)
+			if err != nil {
+				return err
+			}
+			archive, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			resources, err := decryptBackup(archive, passphrase)
+			if err != nil {
+				return err
+			}
+			return renderRestore(os.Stdout, resources, controlPlaneNamespace)
+		},
+	}
+
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.PersistentFlags().StringVar(&passphraseFile, "passphrase-file", passphraseFile, "Path to a file with the passphrase that the archive was encrypted with (required)")
+
+	return cmd
+}
+
+func readPassphrase(file string) ([]byte, error) {
+	if file == "" {
+		return nil, errors.New("The --passphrase-file flag is required")
+	}
+	passphrase, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	passphrase = bytes.TrimRight(passphrase, "\r\n")
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("The passphrase file %s is empty", file)
+	}
+	return passphrase, nil
+}
+
+// collectBackup returns the YAML stream of the control plane resources to back
+// up from namespace, without their namespace and server-managed metadata, so
+// that they can be restored in another cluster. The resources that don't exist
+// are reported to warn.
+func collectBackup(clientset kubernetes.Interface, spClientset spclient.Interface, namespace, issuerSecret string, warn io.Writer) ([]byte, error) {
+	resources := []interface{}{}
+
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(issuerSecret, metaV1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		fmt.Fprintf(warn, "The \"%s\" issuer secret doesn't exist, so the CA's credentials aren't backed up and the restored mesh will have a new identity\n", issuerSecret)
+	case err != nil:
+		return nil, err
+	default:
+		resources = append(resources, &v1.Secret{
+			TypeMeta:   metaV1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: backupObjectMeta(secret.ObjectMeta),
+			Type:       secret.Type,
+			Data:       secret.Data,
+		})
+	}
+
+	configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(k8s.TLSTrustAnchorConfigMapName, metaV1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		fmt.Fprintf(warn, "The \"%s\" trust anchors don't exist, so they aren't backed up\n", k8s.TLSTrustAnchorConfigMapName)
+	case err != nil:
+		return nil, err
+	default:
+		resources = append(resources, &v1.ConfigMap{
+			TypeMeta:   metaV1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: backupObjectMeta(configMap.ObjectMeta),
+			Data:       configMap.Data,
+		})
+	}
+
+	profiles, err := spClientset.LinkerdV1alpha1().ServiceProfiles(namespace).List(metaV1.ListOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		// the custom resource definition isn't installed
+		fmt.Fprintln(warn, "Service profiles aren't installed, so they aren't backed up")
+	case err != nil:
+		return nil, err
+	default:
+		for i := range profiles.Items {
+			profile := profiles.Items[i]
+			profile.TypeMeta = metaV1.TypeMeta{APIVersion: "linkerd.io/v1alpha1", Kind: "ServiceProfile"}
+			profile.ObjectMeta = backupObjectMeta(profile.ObjectMeta)
+			resources = append(resources, &profile)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	for _, resource := range resources {
+		out, err := yaml.Marshal(resource)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString("---\n")
+		buf.Write(out)
+	}
+	return buf.Bytes(), nil
+}
+
+func backupObjectMeta(meta metaV1.ObjectMeta) metaV1.ObjectMeta {
+	return metaV1.ObjectMeta{
+		Name:        meta.Name,
+		Labels:      meta.Labels,
+		Annotations: meta.Annotations,
+	}
+}
+
+// renderRestore writes the backed up resources to w, in namespace.
+func renderRestore(w io.Writer, resources []byte, namespace string) error {
+	reader := yamlDecoder.NewYAMLReader(bufio.NewReader(bytes.NewReader(resources)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var resource map[string]interface{}
+		if err := yaml.Unmarshal(doc, &resource); err != nil {
+			return err
+		}
+		if resource == nil {
+			continue
+		}
+		metadata, ok := resource["metadata"].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("backed up %v has no metadata", resource["kind"])
+		}
+		metadata["namespace"] = namespace
+
+		out, err := yaml.Marshal(resource)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", out); err != nil {
+			return err
+		}
+	}
+}
+
+// encryptBackup encrypts the backed up resources with AES-256-GCM, with a key
+// derived from the passphrase with scrypt.
+func encryptBackup(resources, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, backupSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := backupCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	archive := append([]byte(backupMagic), salt...)
+	archive = append(archive, nonce...)
+	return gcm.Seal(archive, nonce, resources, []byte(backupMagic)), nil
+}
+
+// decryptBackup returns the backed up resources of an archive made by
+// encryptBackup.
+func decryptBackup(archive, passphrase []byte) ([]byte, error) {
+	if !bytes.HasPrefix(archive, []byte(backupMagic)) {
+		return nil, errors.New("The file isn't a Linkerd backup")
+	}
+	archive = archive[len(backupMagic):]
+	if len(archive) < backupSaltSize {
+		return nil, errors.New("The backup is truncated")
+	}
+	gcm, err := backupCipher(passphrase, archive[:backupSaltSize])
+	if err != nil {
+		return nil, err
+	}
+	archive = archive[backupSaltSize:]
+	if len(archive) < gcm.NonceSize() {
+		return nil, errors.New("The backup is truncated")
+	}
+
+	resources, err := gcm.Open(nil, archive[:gcm.NonceSize()], archive[gcm.NonceSize():], []byte(backupMagic))
+	if err != nil {
+		return nil, errors.New("The backup can't be decrypted: the passphrase is wrong or the file is corrupted")
+	}
+	return resources, nil
+}
+
+func backupCipher(passphrase, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, backupScryptN, backupScryptR, backupScryptP, backupKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
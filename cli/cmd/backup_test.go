@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	spfake "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned/fake"
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Secret{
+			ObjectMeta: metaV1.ObjectMeta{Name: "linkerd-ca-issuer", Namespace: "linkerd", ResourceVersion: "12"},
+			Type:       v1.SecretTypeTLS,
+			Data:       map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+		},
+		&v1.ConfigMap{
+			ObjectMeta: metaV1.ObjectMeta{Name: "linkerd-ca-bundle", Namespace: "linkerd", UID: "1234"},
+			Data:       map[string]string{"trust-anchors.pem": "anchors"},
+		},
+	)
+	spClientset := spfake.NewSimpleClientset(
+		&v1alpha1.ServiceProfile{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web.emojivoto.svc.cluster.local", Namespace: "linkerd"},
+			Spec: v1alpha1.ServiceProfileSpec{
+				Routes: []*v1alpha1.RouteSpec{{Name: "/api"}},
+			},
+		},
+	)
+
+	warnings := &bytes.Buffer{}
+	resources, err := collectBackup(clientset, spClientset, "linkerd", "linkerd-ca-issuer", warnings)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if warnings.Len() != 0 {
+		t.Fatalf("Unexpected warnings: %s", warnings)
+	}
+
+	archive, err := encryptBackup(resources, []byte("correct horse"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if bytes.Contains(archive, []byte("anchors")) {
+		t.Fatalf("The archive isn't encrypted")
+	}
+	decrypted, err := decryptBackup(archive, []byte("correct horse"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	output := &bytes.Buffer{}
+	if err := renderRestore(output, decrypted, "linkerd-restored"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := `---
+apiVersion: v1
+data:
+  tls.crt: Y2VydA==
+  tls.key: a2V5
+kind: Secret
+metadata:
+  creationTimestamp: null
+  name: linkerd-ca-issuer
+  namespace: linkerd-restored
+type: kubernetes.io/tls
+---
+apiVersion: v1
+data:
+  trust-anchors.pem: anchors
+kind: ConfigMap
+metadata:
+  creationTimestamp: null
+  name: linkerd-ca-bundle
+  namespace: linkerd-restored
+---
+apiVersion: linkerd.io/v1alpha1
+kind: ServiceProfile
+metadata:
+  creationTimestamp: null
+  name: web.emojivoto.svc.cluster.local
+  namespace: linkerd-restored
+spec:
+  retryBudget: null
+  routes:
+  - condition: null
+    name: /api
+`
+	diffCompare(t, output.String(), expected)
+}
+
+func TestBackupWarnsAboutMissingResources(t *testing.T) {
+	warnings := &bytes.Buffer{}
+	resources, err := collectBackup(fake.NewSimpleClientset(), spfake.NewSimpleClientset(), "linkerd", "linkerd-ca-issuer", warnings)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(resources) != 0 {
+		t.Fatalf("Unexpected resources: %s", resources)
+	}
+
+	expected := "The \"linkerd-ca-issuer\" issuer secret doesn't exist, so the CA's credentials aren't backed up and the restored mesh will have a new identity\n" +
+		"The \"linkerd-ca-bundle\" trust anchors don't exist, so they aren't backed up\n"
+	diffCompare(t, warnings.String(), expected)
+}
+
+func TestDecryptBackup(t *testing.T) {
+	archive, err := encryptBackup([]byte("resources"), []byte("correct horse"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		title      string
+		archive    []byte
+		passphrase string
+		err        string
+	}{
+		{"rejects other files", []byte("apiVersion: v1"), "correct horse", "The file isn't a Linkerd backup"},
+		{"rejects truncated archives", archive[:len(backupMagic)+4], "correct horse", "The backup is truncated"},
+		{"rejects wrong passphrases", archive, "battery staple", "The backup can't be decrypted: the passphrase is wrong or the file is corrupted"},
+		{"rejects corrupted archives", append(append([]byte{}, archive[:len(archive)-1]...), archive[len(archive)-1]^0xff), "correct horse", "The backup can't be decrypted: the passphrase is wrong or the file is corrupted"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			_, err := decryptBackup(tc.archive, []byte(tc.passphrase))
+			if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+				t.Fatalf("Expected error %q, got: %v", tc.err, err)
+			}
+		})
+	}
+}
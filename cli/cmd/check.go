@@ -1,15 +1,19 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/briandowns/spinner"
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/version"
 	"github.com/spf13/cobra"
 )
 
@@ -20,6 +24,10 @@ type checkOptions struct {
 	wait            time.Duration
 	namespace       string
 	singleNamespace bool
+	fix             bool
+	drift           string
+	multicluster    bool
+	addOns          []string
 }
 
 func newCheckOptions() *checkOptions {
@@ -30,9 +38,25 @@ func newCheckOptions() *checkOptions {
 		wait:            300 * time.Second,
 		namespace:       "",
 		singleNamespace: false,
+		fix:             false,
+		drift:           "",
+		multicluster:    false,
+		addOns:          defaultAddOns(),
 	}
 }
 
+// defaultAddOns returns the names of the add-ons that `linkerd install`
+// installs by default.
+func defaultAddOns() []string {
+	names := []string{}
+	for _, a := range addOns {
+		if a.enabled {
+			names = append(names, a.name)
+		}
+	}
+	return names
+}
+
 func newCmdCheck() *cobra.Command {
 	options := newCheckOptions()
 
@@ -44,7 +68,25 @@ func newCmdCheck() *cobra.Command {
 The check command will perform a series of checks to validate that the linkerd
 CLI and control plane are configured correctly. If the command encounters a
 failure it will print additional information about the failure and exit with a
-non-zero exit code.`,
+non-zero exit code.
+
+With --drift, the control plane resources in the cluster are also compared with
+the manifests they were installed from, as rendered by "linkerd install", to
+report the changes made to them out of band, such as with "kubectl edit". Only
+the fields that the manifests set are compared, since Kubernetes adds many
+fields to the resources it stores.
+
+With --fix, the proxy injector is also checked: the CA bundle of its webhook,
+the exclusion of the control plane namespace from auto-injection, and its RBAC
+permissions. The failed checks are fixed after confirmation.
+
+With --multicluster, the clusters linked by "linkerd multicluster link" are also
+checked: the credentials of the service mirrors, the identity and reachability
+of the gateways, as probed by the service mirrors, and whether the mirrored
+services are up to date with the remote services.
+
+The pods of the add-ons installed with the control plane, such as tracing or
+flagger, are checked too when they're listed with --addon.`,
 		Example: `  # Check that the Linkerd control plane is up and running
   linkerd check
 
@@ -52,7 +94,21 @@ non-zero exit code.`,
   linkerd check --pre --linkerd-namespace test
 
   # Check that the Linkerd data plane proxies in the "app" namespace are up and running
-  linkerd check --proxy --namespace app`,
+  linkerd check --proxy --namespace app
+
+  # Check the Linkerd installation and its proxy injector, and fix the problems that have a known remediation
+  linkerd check --fix
+
+  # Check that the control plane still matches the manifests it was installed from
+  linkerd install --ha > linkerd.yml
+  kubectl apply -f linkerd.yml
+  linkerd check --drift linkerd.yml
+
+  # Check the links to other clusters and their gateways
+  linkerd check --multicluster
+
+  # Check the control plane along with its grafana and tracing add-ons
+  linkerd check --addon grafana,tracing`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return configureAndRunChecks(options)
@@ -66,6 +122,10 @@ non-zero exit code.`,
 	cmd.PersistentFlags().DurationVar(&options.wait, "wait", options.wait, "Retry and wait for some checks to succeed if they don't pass the first time")
 	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace to use for --proxy checks (default: all namespaces)")
 	cmd.PersistentFlags().BoolVar(&options.singleNamespace, "single-namespace", options.singleNamespace, "When running pre-installation checks (--pre), only check the permissions required to operate the control plane in a single namespace")
+	cmd.PersistentFlags().BoolVar(&options.fix, "fix", options.fix, "Also check the proxy injector, and apply the known remediation of failed checks, after confirmation")
+	cmd.PersistentFlags().BoolVar(&options.multicluster, "multicluster", options.multicluster, "Also check the links to other clusters, their gateways and their mirrored services")
+	cmd.PersistentFlags().StringSliceVar(&options.addOns, "addon", options.addOns, fmt.Sprintf("Also check the add-ons installed with the control plane, among: %s", strings.Join(addOnNames(), ", ")))
+	cmd.PersistentFlags().StringVar(&options.drift, "drift", options.drift, "Check that the control plane resources match the manifests in this file or directory, as rendered by \"linkerd install\" (\"-\" for stdin)")
 
 	return cmd
 }
@@ -75,10 +135,24 @@ func configureAndRunChecks(options *checkOptions) error {
 	if err != nil {
 		return fmt.Errorf("Validation error when executing check command: %v", err)
 	}
+	var driftManifests []byte
+	if options.drift != "" {
+		driftManifests, err = readManifests(options.drift)
+		if err != nil {
+			return fmt.Errorf("Failed to read the manifests to check for drift: %s", err)
+		}
+	}
+
 	checks := []healthcheck.CategoryID{
 		healthcheck.KubernetesAPIChecks,
 		healthcheck.KubernetesVersionChecks,
-		healthcheck.LinkerdVersionChecks,
+	}
+
+	// version checks can be disabled at build time, unless a version to check
+	// against is provided explicitly
+	versionChecks := version.CheckEnabled() || options.versionOverride != ""
+	if versionChecks {
+		checks = append(checks, healthcheck.LinkerdVersionChecks)
 	}
 
 	if options.preInstallOnly {
@@ -88,6 +162,7 @@ func configureAndRunChecks(options *checkOptions) error {
 			checks = append(checks, healthcheck.LinkerdPreInstallClusterChecks)
 		}
 		checks = append(checks, healthcheck.LinkerdPreInstallChecks)
+		checks = append(checks, healthcheck.LinkerdPreInstallCapabilityChecks)
 	} else {
 		checks = append(checks, healthcheck.LinkerdControlPlaneExistenceChecks)
 		checks = append(checks, healthcheck.LinkerdAPIChecks)
@@ -96,9 +171,28 @@ func configureAndRunChecks(options *checkOptions) error {
 			checks = append(checks, healthcheck.LinkerdServiceProfileChecks)
 		}
 
+		// the proxy injector is optional, so its checks, which are the ones
+		// that can be fixed, only run with --fix
+		if options.fix && !options.singleNamespace {
+			checks = append(checks, healthcheck.LinkerdProxyInjectorChecks)
+		}
+
+		if options.drift != "" {
+			checks = append(checks, healthcheck.LinkerdDriftChecks)
+		}
+
+		if options.multicluster {
+			checks = append(checks, healthcheck.LinkerdMulticlusterChecks)
+		}
+
+		for _, name := range options.addOns {
+			a, _ := findAddOn(name)
+			checks = append(checks, a.checks)
+		}
+
 		if options.dataPlaneOnly {
 			checks = append(checks, healthcheck.LinkerdDataPlaneChecks)
-		} else {
+		} else if versionChecks {
 			checks = append(checks, healthcheck.LinkerdControlPlaneVersionChecks)
 		}
 	}
@@ -108,9 +202,15 @@ func configureAndRunChecks(options *checkOptions) error {
 		DataPlaneNamespace:    options.namespace,
 		KubeConfig:            kubeconfigPath,
 		KubeContext:           kubeContext,
+		Impersonate:           impersonate,
+		ImpersonateGroup:      impersonateGroup,
 		APIAddr:               apiAddr,
+		MutualTLS:             apiTLS,
 		VersionOverride:       options.versionOverride,
 		RetryDeadline:         time.Now().Add(options.wait),
+		Fix:                   options.fix,
+		ConfirmFix:            confirmFix(os.Stdin, os.Stdout),
+		DriftManifests:        driftManifests,
 	})
 
 	success := runChecks(os.Stdout, hc)
@@ -132,11 +232,43 @@ func (o *checkOptions) validate() error {
 	if o.preInstallOnly && o.dataPlaneOnly {
 		return errors.New("--pre and --proxy flags are mutually exclusive")
 	}
+	if o.drift != "" && (o.preInstallOnly || o.dataPlaneOnly) {
+		return errors.New("--drift can't be used with the --pre or --proxy flags")
+	}
+	if o.multicluster && (o.preInstallOnly || o.dataPlaneOnly) {
+		return errors.New("--multicluster can't be used with the --pre or --proxy flags")
+	}
+	for _, name := range o.addOns {
+		if _, ok := findAddOn(name); !ok {
+			return fmt.Errorf("Invalid value '%s' for --addon flag: must be one of: %s", name, strings.Join(addOnNames(), ", "))
+		}
+	}
 	return nil
 }
 
+// readManifests returns the contents of the manifests in path, which can be a
+// file, a directory or stdin, as a single multi-document YAML.
+func readManifests(path string) ([]byte, error) {
+	in, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := &bytes.Buffer{}
+	for _, r := range in {
+		content, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		manifests.WriteString("\n---\n")
+		manifests.Write(content)
+	}
+	return manifests.Bytes(), nil
+}
+
 func runChecks(w io.Writer, hc *healthcheck.HealthChecker) bool {
 	var lastCategory healthcheck.CategoryID
+	var fixes []string
 	spin := spinner.New(spinner.CharSets[21], 100*time.Millisecond)
 	spin.Writer = w
 
@@ -168,6 +300,10 @@ func runChecks(w io.Writer, hc *healthcheck.HealthChecker) bool {
 		}
 
 		fmt.Fprintf(w, "%s %s\n", status, result.Description)
+		if result.Fixed != "" {
+			fmt.Fprintf(w, "    fixed: %s\n", result.Fixed)
+			fixes = append(fixes, fmt.Sprintf("%s: %s", result.Description, result.Fixed))
+		}
 		if result.Err != nil {
 			fmt.Fprintf(w, "    %s\n", result.Err)
 			if result.HintURL != "" {
@@ -176,5 +312,27 @@ func runChecks(w io.Writer, hc *healthcheck.HealthChecker) bool {
 		}
 	}
 
-	return hc.RunChecks(prettyPrintResults)
+	success := hc.RunChecks(prettyPrintResults)
+
+	if len(fixes) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Changes applied by --fix:")
+		for _, fix := range fixes {
+			fmt.Fprintf(w, "  * %s\n", fix)
+		}
+	}
+
+	return success
+}
+
+// confirmFix returns a function that prompts the user to confirm the
+// remediation of a failed check.
+func confirmFix(in io.Reader, out io.Writer) func(string, string) bool {
+	reader := bufio.NewReader(in)
+	return func(description, remediation string) bool {
+		fmt.Fprintf(out, "\"%s\" failed, %s? [y/N] ", description, remediation)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes"
+	}
 }
@@ -27,15 +27,25 @@ func newCmdCompletion() *cobra.Command {
   source <(linkerd completion zsh)
 
   # zsh on osx / oh-my-zsh
-  linkerd completion zsh > "${fpath[1]}/_linkerd"`
+  linkerd completion zsh > "${fpath[1]}/_linkerd"
+
+  # fish
+  linkerd completion fish > ~/.config/fish/completions/linkerd.fish
+
+  # PowerShell
+  linkerd completion powershell | Out-String | Invoke-Expression`
 
 	cmd := &cobra.Command{
-		Use:       "completion [bash|zsh]",
-		Short:     "Shell completion",
-		Long:      "Output completion code for the specified shell (bash or zsh).",
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Shell completion",
+		Long: `Output completion code for the specified shell (bash, zsh, fish or powershell).
+
+With bash, fish and powershell, the namespaces, and the resource types and names
+of the stat, tap, top, routes and edges commands, are completed from the
+cluster, giving up after a couple of seconds if it can't be reached.`,
 		Example:   example,
 		Args:      cobra.ExactArgs(1),
-		ValidArgs: []string{"bash", "zsh"},
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out, err := getCompletion(args[0], cmd.Parent())
 			if err != nil {
@@ -56,11 +66,16 @@ func getCompletion(sh string, parent *cobra.Command) (string, error) {
 
 	switch sh {
 	case "bash":
+		addBashCompletions(parent)
 		err = parent.GenBashCompletion(&buf)
 	case "zsh":
 		err = parent.GenZshCompletion(&buf)
+	case "fish":
+		err = genFishCompletion(&buf, parent)
+	case "powershell":
+		err = genPowerShellCompletion(&buf, parent)
 	default:
-		err = errors.New("unsupported shell type (must be bash or zsh): " + sh)
+		err = errors.New("unsupported shell type (must be bash, zsh, fish or powershell): " + sh)
 	}
 
 	if err != nil {
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// completeResourcesCommand is the hidden command that the completion
+	// scripts run to complete the resource types and names from the cluster
+	completeResourcesCommand = "__complete-resources"
+
+	// completionTimeout bounds the requests of completeResourcesCommand, so
+	// that an unreachable cluster doesn't hang the shell
+	completionTimeout = 2 * time.Second
+)
+
+// resourceCompletionCommands are the commands whose arguments are resources,
+// as TYPE [NAME], whose types and names are completed.
+var resourceCompletionCommands = []string{"stat", "tap", "top", "routes", "edges"}
+
+func newCmdCompleteResources() *cobra.Command {
+	namespace := "default"
+
+	cmd := &cobra.Command{
+		Use:    completeResourcesCommand + " [flags] [TYPE]",
+		Short:  "List the resource types, or the names of the resources of TYPE, for shell completion",
+		Hidden: true,
+		Args:   cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				for _, resourceType := range k8s.AllResources {
+					fmt.Println(resourceType)
+				}
+				return nil
+			}
+
+			kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+			if err != nil {
+				return err
+			}
+			kubeAPI.Config.Timeout = completionTimeout
+			clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+			if err != nil {
+				return err
+			}
+			names, err := resourceNames(clientset, args[0], namespace)
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				fmt.Println(name)
+			}
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", namespace, "Namespace of the resources")
+
+	return cmd
+}
+
+// resourceNames returns the sorted names of the resources of the type in the
+// namespace, or of the namespaces.
+func resourceNames(clientset kubernetes.Interface, resourceType, namespace string) ([]string, error) {
+	canonicalType, err := k8s.CanonicalResourceNameFromFriendlyName(resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	options := metaV1.ListOptions{}
+	switch canonicalType {
+	case k8s.Namespace:
+		list, err := clientset.CoreV1().Namespaces().List(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+	case k8s.Pod:
+		list, err := clientset.CoreV1().Pods(namespace).List(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+	case k8s.Service:
+		list, err := clientset.CoreV1().Services(namespace).List(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+	case k8s.ReplicationController:
+		list, err := clientset.CoreV1().ReplicationControllers(namespace).List(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+	case k8s.Deployment:
+		list, err := clientset.AppsV1().Deployments(namespace).List(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+	case k8s.DaemonSet:
+		list, err := clientset.AppsV1().DaemonSets(namespace).List(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+	case k8s.ReplicaSet:
+		list, err := clientset.AppsV1().ReplicaSets(namespace).List(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+	case k8s.StatefulSet:
+		list, err := clientset.AppsV1().StatefulSets(namespace).List(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+	case k8s.Job:
+		list, err := clientset.BatchV1().Jobs(namespace).List(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+	case k8s.CronJob:
+		list, err := clientset.BatchV1beta1().CronJobs(namespace).List(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+	default:
+		return nil, fmt.Errorf("The names of the %s resources can't be completed", canonicalType)
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
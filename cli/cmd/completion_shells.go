@@ -0,0 +1,391 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// namespaceCompletionFlags are the flags whose values are namespaces, which
+// are completed from the cluster.
+var namespaceCompletionFlags = []string{"namespace", "linkerd-namespace"}
+
+// bashCompletionFunction completes the namespaces and the resources of the
+// resourceCompletionCommands, as kubectl's does. Cobra calls __custom_func,
+// or __linkerd_custom_func in later versions, for the arguments that it has
+// no completions for.
+const bashCompletionFunction = `
+__linkerd_override_flags()
+{
+    local prev="" word
+    for word in "${words[@]}"; do
+        case "${prev}" in
+            --kubeconfig|--context|--as)
+                printf -- '%s=%s ' "${prev}" "${word}"
+                ;;
+        esac
+        case "${word}" in
+            --kubeconfig=*|--context=*|--as=*)
+                printf -- '%s ' "${word}"
+                ;;
+        esac
+        prev="${word}"
+    done
+}
+
+__linkerd_get_namespaces()
+{
+    local out
+    if out=$(linkerd $(__linkerd_override_flags) ` + completeResourcesCommand + ` namespace 2>/dev/null); then
+        COMPREPLY=( $( compgen -W "${out[*]}" -- "$cur" ) )
+    fi
+}
+
+__linkerd_get_resources()
+{
+    local namespace="default" prev="" word out
+    for word in "${words[@]}"; do
+        case "${prev}" in
+            -n|--namespace)
+                namespace="${word}"
+                ;;
+        esac
+        case "${word}" in
+            --namespace=*)
+                namespace="${word#--namespace=}"
+                ;;
+        esac
+        prev="${word}"
+    done
+
+    if [[ ${#nouns[@]} -eq 0 ]]; then
+        out=$(linkerd ` + completeResourcesCommand + ` 2>/dev/null)
+    else
+        out=$(linkerd $(__linkerd_override_flags) ` + completeResourcesCommand + ` --namespace="${namespace}" "${nouns[${#nouns[@]} -1]}" 2>/dev/null)
+    fi
+    if [[ $? -eq 0 ]]; then
+        COMPREPLY=( $( compgen -W "${out[*]}" -- "$cur" ) )
+    fi
+}
+
+__linkerd_custom_func()
+{
+    case ${last_command} in
+        __LINKERD_RESOURCE_COMMANDS__)
+            __linkerd_get_resources
+            return
+            ;;
+        *)
+            ;;
+    esac
+}
+
+__custom_func()
+{
+    __linkerd_custom_func
+}
+`
+
+// addBashCompletions sets up the dynamic completions of the bash completion
+// of root.
+func addBashCompletions(root *cobra.Command) {
+	lastCommands := []string{}
+	for _, name := range resourceCompletionCommands {
+		lastCommands = append(lastCommands, root.Name()+"_"+name)
+	}
+	root.BashCompletionFunction = strings.Replace(bashCompletionFunction, "__LINKERD_RESOURCE_COMMANDS__", strings.Join(lastCommands, " | "), 1)
+
+	for _, cmd := range completionCommands(root) {
+		for _, flags := range []*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags()} {
+			for _, name := range namespaceCompletionFlags {
+				if flags.Lookup(name) != nil {
+					flags.SetAnnotation(name, cobra.BashCompCustom, []string{"__linkerd_get_namespaces"})
+				}
+			}
+		}
+	}
+}
+
+// fishCompletionFunctions track the subcommand being completed, as the path
+// of the subcommands in the command line, and complete the namespaces and the
+// resources of the resourceCompletionCommands.
+const fishCompletionFunctions = `function __linkerd_command
+    set -l path linkerd
+    for word in (commandline -opc)[2..-1]
+        if contains -- "$path $word" $__linkerd_commands
+            set path "$path $word"
+        end
+    end
+    echo $path
+end
+
+function __linkerd_is
+    test (__linkerd_command) = "$argv"
+end
+
+function __linkerd_override_flags
+    set -l prev
+    for word in (commandline -opc)
+        if contains -- $prev --kubeconfig --context --as
+            echo "$prev=$word"
+        else if string match -q -r -- '^--(kubeconfig|context|as)=' $word
+            echo $word
+        end
+        set prev $word
+    end
+end
+
+function __linkerd_namespaces
+    linkerd (__linkerd_override_flags) ` + completeResourcesCommand + ` namespace 2>/dev/null
+end
+
+function __linkerd_resources
+    set -l path linkerd
+    set -l namespace default
+    set -l nouns
+    set -l prev
+    for word in (commandline -opc)[2..-1]
+        if contains -- "$path $word" $__linkerd_commands
+            set path "$path $word"
+        else if contains -- $prev -n --namespace
+            set namespace $word
+        else if string match -q -- '--namespace=*' $word
+            set namespace (string replace -- '--namespace=' '' $word)
+        else if not string match -q -- '-*' $word
+            set nouns $nouns $word
+        end
+        set prev $word
+    end
+
+    if test (count $nouns) -eq 0
+        linkerd ` + completeResourcesCommand + ` 2>/dev/null
+    else
+        linkerd (__linkerd_override_flags) ` + completeResourcesCommand + ` --namespace=$namespace $nouns[-1] 2>/dev/null
+    end
+end
+`
+
+// genFishCompletion writes the fish completion of root to w.
+func genFishCompletion(w io.Writer, root *cobra.Command) error {
+	commands := completionCommands(root)
+
+	paths := []string{}
+	for _, cmd := range commands {
+		paths = append(paths, fishQuote(cmd.CommandPath()))
+	}
+
+	fmt.Fprintf(w, "# fish completion for %s\n\n", root.Name())
+	fmt.Fprintf(w, "set -g __linkerd_commands %s\n\n", strings.Join(paths, " "))
+	fmt.Fprint(w, fishCompletionFunctions)
+	fmt.Fprintf(w, "complete -c %s -f\n", root.Name())
+
+	root.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		if !flag.Hidden {
+			fmt.Fprintf(w, "complete -c %s%s\n", root.Name(), fishFlagCompletion(flag))
+		}
+	})
+
+	for _, cmd := range commands {
+		condition := fmt.Sprintf(" -n %s", fishQuote("__linkerd_is "+cmd.CommandPath()))
+
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() {
+				fmt.Fprintf(w, "complete -c %s%s -a %s -d %s\n", root.Name(), condition, fishQuote(sub.Name()), fishQuote(sub.Short))
+			}
+		}
+		visitCommandFlags(cmd, root, func(flag *pflag.Flag) {
+			fmt.Fprintf(w, "complete -c %s%s%s\n", root.Name(), condition, fishFlagCompletion(flag))
+		})
+		if isResourceCompletionCommand(cmd, root) {
+			fmt.Fprintf(w, "complete -c %s%s -a '(__linkerd_resources)'\n", root.Name(), condition)
+		}
+	}
+	return nil
+}
+
+func fishFlagCompletion(flag *pflag.Flag) string {
+	completion := " -l " + flag.Name
+	if flag.Shorthand != "" {
+		completion += " -s " + flag.Shorthand
+	}
+	if isNamespaceCompletionFlag(flag) {
+		completion += " -x -a '(__linkerd_namespaces)'"
+	} else if flag.NoOptDefVal == "" {
+		completion += " -r"
+	}
+	return completion + " -d " + fishQuote(firstLine(flag.Usage))
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// powerShellCompletion registers the completer of the linkerd command, given
+// the completions of the subcommands and the resourceCompletionCommands. It
+// tracks the subcommand being completed as the path of the subcommands in the
+// command line, and completes the namespaces and the resources of the
+// resourceCompletionCommands.
+const powerShellCompletion = `# powershell completion for linkerd
+
+Register-ArgumentCompleter -Native -CommandName 'linkerd' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $commands = @{
+%s    }
+    $resourceCommands = @(%s)
+    $namespaceFlags = @(%s)
+
+    $path = 'linkerd'
+    $namespace = 'default'
+    $overrides = @()
+    $nouns = @()
+    $prev = ''
+    $elements = $commandAst.CommandElements | Select-Object -Skip 1 | Where-Object { $_.Extent.EndOffset -lt $cursorPosition }
+    foreach ($element in $elements) {
+        $word = $element.Extent.Text
+        if ($commands.ContainsKey("$path $word")) {
+            $path = "$path $word"
+        } elseif ($prev -eq '-n' -or $prev -eq '--namespace') {
+            $namespace = $word
+        } elseif ($prev -eq '--kubeconfig' -or $prev -eq '--context' -or $prev -eq '--as') {
+            $overrides += "$prev=$word"
+        } elseif ($word -like '--namespace=*') {
+            $namespace = $word.Substring(12)
+        } elseif ($word -like '--kubeconfig=*' -or $word -like '--context=*' -or $word -like '--as=*') {
+            $overrides += $word
+        } elseif (-not $word.StartsWith('-')) {
+            $nouns += $word
+        }
+        $prev = $word
+    }
+
+    if ($namespaceFlags -contains $prev) {
+        $candidates = linkerd @overrides ` + completeResourcesCommand + ` namespace 2>$null
+    } elseif ($resourceCommands -contains $path -and -not $wordToComplete.StartsWith('-')) {
+        if ($nouns.Count -eq 0) {
+            $candidates = linkerd ` + completeResourcesCommand + ` 2>$null
+        } else {
+            $candidates = linkerd @overrides ` + completeResourcesCommand + ` "--namespace=$namespace" $nouns[-1] 2>$null
+        }
+    } else {
+        $candidates = $commands[$path]
+    }
+
+    $candidates | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`
+
+// genPowerShellCompletion writes the PowerShell completion of root to w.
+func genPowerShellCompletion(w io.Writer, root *cobra.Command) error {
+	commands := &bytes.Buffer{}
+	resourceCommands := []string{}
+	namespaceFlags := []string{}
+
+	root.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		if isNamespaceCompletionFlag(flag) {
+			namespaceFlags = append(namespaceFlags, powerShellFlagNames(flag)...)
+		}
+	})
+
+	for _, cmd := range completionCommands(root) {
+		completions := []string{}
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() {
+				completions = append(completions, powerShellQuote(sub.Name()))
+			}
+		}
+		addFlag := func(flag *pflag.Flag) {
+			for _, name := range powerShellFlagNames(flag) {
+				completions = append(completions, powerShellQuote(name))
+			}
+		}
+		root.PersistentFlags().VisitAll(addFlag)
+		visitCommandFlags(cmd, root, func(flag *pflag.Flag) {
+			addFlag(flag)
+			if isNamespaceCompletionFlag(flag) {
+				namespaceFlags = append(namespaceFlags, powerShellFlagNames(flag)...)
+			}
+		})
+		fmt.Fprintf(commands, "        %s = @(%s)\n", powerShellQuote(cmd.CommandPath()), strings.Join(completions, ", "))
+
+		if isResourceCompletionCommand(cmd, root) {
+			resourceCommands = append(resourceCommands, powerShellQuote(cmd.CommandPath()))
+		}
+	}
+
+	quotedFlags := []string{}
+	seen := map[string]bool{}
+	for _, name := range namespaceFlags {
+		if !seen[name] {
+			seen[name] = true
+			quotedFlags = append(quotedFlags, powerShellQuote(name))
+		}
+	}
+
+	_, err := fmt.Fprintf(w, powerShellCompletion, commands.String(), strings.Join(resourceCommands, ", "), strings.Join(quotedFlags, ", "))
+	return err
+}
+
+func powerShellFlagNames(flag *pflag.Flag) []string {
+	names := []string{"--" + flag.Name}
+	if flag.Shorthand != "" {
+		names = append(names, "-"+flag.Shorthand)
+	}
+	return names
+}
+
+func powerShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// completionCommands returns root and its available subcommands, depth first.
+func completionCommands(root *cobra.Command) []*cobra.Command {
+	commands := []*cobra.Command{root}
+	for _, cmd := range root.Commands() {
+		if cmd.IsAvailableCommand() {
+			commands = append(commands, completionCommands(cmd)...)
+		}
+	}
+	return commands
+}
+
+// visitCommandFlags calls fn for the flags of cmd, other than the persistent
+// flags of root, which apply to all the commands.
+func visitCommandFlags(cmd, root *cobra.Command, fn func(*pflag.Flag)) {
+	visit := func(flag *pflag.Flag) {
+		if flag.Hidden || root.PersistentFlags().Lookup(flag.Name) != nil {
+			return
+		}
+		fn(flag)
+	}
+	cmd.NonInheritedFlags().VisitAll(visit)
+	cmd.InheritedFlags().VisitAll(visit)
+}
+
+func isResourceCompletionCommand(cmd, root *cobra.Command) bool {
+	for _, name := range resourceCompletionCommands {
+		if cmd.CommandPath() == root.Name()+" "+name {
+			return true
+		}
+	}
+	return false
+}
+
+func isNamespaceCompletionFlag(flag *pflag.Flag) bool {
+	for _, name := range namespaceCompletionFlags {
+		if flag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func firstLine(s string) string {
+	return strings.SplitN(s, "\n", 2)[0]
+}
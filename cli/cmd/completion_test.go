@@ -1,8 +1,14 @@
 package cmd
 
 import (
+	"reflect"
 	"strings"
 	"testing"
+
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestCompletion(t *testing.T) {
@@ -25,6 +31,72 @@ func TestCompletion(t *testing.T) {
 		if !strings.Contains(zsh, "#compdef linkerd") {
 			t.Fatalf("Unexpected zsh output: %+v", zsh)
 		}
+
+		fish, err := getCompletion("fish", RootCmd)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		powershell, err := getCompletion("powershell", RootCmd)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if !strings.Contains(fish, "# fish completion for linkerd") {
+			t.Fatalf("Unexpected fish output: %+v", fish)
+		}
+
+		if !strings.Contains(powershell, "Register-ArgumentCompleter -Native -CommandName 'linkerd'") {
+			t.Fatalf("Unexpected powershell output: %+v", powershell)
+		}
+	})
+
+	t.Run("Completes the namespaces and the resources from the cluster", func(t *testing.T) {
+		bash, err := getCompletion("bash", RootCmd)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		fish, err := getCompletion("fish", RootCmd)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		powershell, err := getCompletion("powershell", RootCmd)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		for _, expected := range []string{
+			"linkerd_stat | linkerd_tap | linkerd_top | linkerd_routes | linkerd_edges)",
+			`flags_completion+=("__linkerd_get_namespaces")`,
+		} {
+			if !strings.Contains(bash, expected) {
+				t.Fatalf("Expected the bash output to contain %q", expected)
+			}
+		}
+
+		for _, expected := range []string{
+			"complete -c linkerd -n '__linkerd_is linkerd stat' -a '(__linkerd_resources)'",
+			"complete -c linkerd -n '__linkerd_is linkerd stat' -l namespace -s n -x -a '(__linkerd_namespaces)' -d 'Namespace of the specified resource'",
+			"complete -c linkerd -n '__linkerd_is linkerd' -a 'stat' -d ",
+		} {
+			if !strings.Contains(fish, expected) {
+				t.Fatalf("Expected the fish output to contain %q", expected)
+			}
+		}
+
+		for _, expected := range []string{
+			"$resourceCommands = @('linkerd edges', 'linkerd routes', 'linkerd stat', 'linkerd tap', 'linkerd top')",
+			"'linkerd stat' = @(",
+		} {
+			if !strings.Contains(powershell, expected) {
+				t.Fatalf("Expected the powershell output to contain %q", expected)
+			}
+		}
+		if strings.Contains(fish+powershell, completeResourcesCommand+"'") {
+			t.Fatalf("Expected the hidden %s command not to be completed", completeResourcesCommand)
+		}
 	})
 
 	t.Run("Fails with invalid shell type", func(t *testing.T) {
@@ -34,3 +106,42 @@ func TestCompletion(t *testing.T) {
 		}
 	})
 }
+
+func TestResourceNames(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "emojivoto"}},
+		&v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "books"}},
+		&appsV1.Deployment{ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "emojivoto"}},
+		&appsV1.Deployment{ObjectMeta: metaV1.ObjectMeta{Name: "emoji", Namespace: "emojivoto"}},
+		&appsV1.Deployment{ObjectMeta: metaV1.ObjectMeta{Name: "webapp", Namespace: "books"}},
+	)
+
+	testCases := []struct {
+		resourceType string
+		namespace    string
+		names        []string
+		err          string
+	}{
+		{resourceType: "ns", namespace: "default", names: []string{"books", "emojivoto"}},
+		{resourceType: "deploy", namespace: "emojivoto", names: []string{"emoji", "web"}},
+		{resourceType: "deployments", namespace: "default", names: []string{}},
+		{resourceType: "au", namespace: "emojivoto", err: "The names of the authority resources can't be completed"},
+		{resourceType: "foo", namespace: "emojivoto", err: "cannot find Kubernetes canonical name from friendly name [foo]"},
+	}
+
+	for _, tc := range testCases {
+		names, err := resourceNames(clientset, tc.resourceType, tc.namespace)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error %q for %s, got: %v", tc.err, tc.resourceType, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %s", tc.resourceType, err)
+		}
+		if !reflect.DeepEqual(names, tc.names) {
+			t.Fatalf("Expected names %v for %s, got %v", tc.names, tc.resourceType, names)
+		}
+	}
+}
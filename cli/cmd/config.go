@@ -0,0 +1,370 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// linkerdConfigMap is the name of the ConfigMap in cli/install/template.go
+	// that stores the linkerdConfig of the control plane, under the
+	// linkerdConfigKey key
+	linkerdConfigMap = "linkerd-config"
+	linkerdConfigKey = "config"
+
+	// proxyInjectorSidecarConfigMap is the name of the ConfigMap in
+	// cli/install/template.go with the proxy specs that the proxy injector
+	// injects, which it reads on each injection
+	proxyInjectorSidecarConfigMap = "linkerd-proxy-injector-sidecar-config"
+	proxyInjectorDeployment       = "linkerd-proxy-injector"
+
+	// restartedAtAnnotation is the pod template annotation that `kubectl
+	// rollout restart` sets to roll out a deployment
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+)
+
+// linkerdConfig is the install-time configuration of the control plane, which
+// `linkerd install` stores in the linkerd-config ConfigMap, so that `linkerd
+// config` can read and update it without the original command.
+type linkerdConfig struct {
+	// Version is the version of the installed control plane
+	Version string `json:"version"`
+
+	// Flags are the flags of `linkerd install` that differ from their
+	// defaults, as arguments
+	Flags []string `json:"flags"`
+}
+
+// unrecordedInstallFlags are the flags of `linkerd install` and `linkerd
+// upgrade` that only change how the configs are output, which aren't stored
+// in the linkerdConfig.
+var unrecordedInstallFlags = []string{"interactive", "output-dir", "snapshot", "skip-crds", "crds", "skip-checks", "diff", "from-manifests", "values", "set"}
+
+type configSetOptions struct {
+	restart bool
+	timeout time.Duration
+}
+
+func newCmdConfig() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config [flags]",
+		Short: "View or change the configuration of the control plane",
+		Long: `View or change the configuration of the control plane.
+
+"linkerd install" stores the flags it was run with in the linkerd-config
+ConfigMap of the control plane namespace, which these commands read and update.`,
+	}
+
+	cmd.AddCommand(newCmdConfigView())
+	cmd.AddCommand(newCmdConfigSet())
+
+	return cmd
+}
+
+func newCmdConfigView() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "view [flags]",
+		Short: "Display the flags that the control plane was installed with",
+		Long: `Display the flags that the control plane was installed with.
+
+The flags that differ from their defaults are displayed one per line, in a form
+that can be pasted in a shell, e.g. to preview an upgrade with "linkerd upgrade
+--diff".`,
+		Example: `  # Preview the upgrade of the control plane with the same flags.
+  linkerd upgrade --diff $(linkerd config view)`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientset, err := newMeshClientset()
+			if err != nil {
+				return err
+			}
+			config, err := getLinkerdConfig(clientset, controlPlaneNamespace)
+			if err != nil {
+				return err
+			}
+			return renderLinkerdConfig(os.Stdout, config)
+		},
+	}
+
+	return cmd
+}
+
+func newCmdConfigSet() *cobra.Command {
+	options := &configSetOptions{
+		restart: false,
+		timeout: 5 * time.Minute,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "set [flags] NAME=VALUE...",
+		Short: "Change the proxy defaults of the control plane",
+		Long: `Change the proxy defaults of the control plane.
+
+Each NAME is a proxy flag of "linkerd install", such as proxy-log-level or
+proxy-cpu, without the leading dashes. The flags stored in the linkerd-config
+ConfigMap are updated, and the proxy specs of the proxy injector are rendered
+again from them, so that the proxies injected from then on get the new
+defaults, without rendering and applying the whole control plane again.
+
+The proxies of the running pods, and of the control plane, keep their
+configuration until their pods are recreated. With --restart, the proxy
+injector, then the meshed deployments of the namespaces with auto-injection
+enabled, are rolled out, waiting for their rollouts.`,
+		Example: `  # Lower the CPU that the injected proxies request.
+  linkerd config set proxy-cpu=5m
+
+  # Turn on the debug logs of the proxies, and restart the meshed deployments.
+  linkerd config set proxy-log-level=warn,linkerd2_proxy=debug --restart`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientset, err := newMeshClientset()
+			if err != nil {
+				return err
+			}
+			return runConfigSet(os.Stdout, clientset, controlPlaneNamespace, args, options)
+		},
+	}
+
+	cmd.PersistentFlags().BoolVar(&options.restart, "restart", options.restart, "Roll out the proxy injector and the meshed deployments, so that they get the new proxy defaults (default false)")
+	cmd.PersistentFlags().DurationVar(&options.timeout, "timeout", options.timeout, "How long to wait for each rollout with --restart")
+
+	return cmd
+}
+
+// recordedInstallFlags returns the flags of `linkerd install` to store in the
+// linkerdConfig.
+func recordedInstallFlags(flags *pflag.FlagSet) []string {
+	return installFlagArgs(flags, func(value string) string { return value }, unrecordedInstallFlags...)
+}
+
+func getLinkerdConfig(clientset kubernetes.Interface, namespace string) (*linkerdConfig, error) {
+	configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(linkerdConfigMap, metaV1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("The %s namespace has no %s ConfigMap; the control plane must be installed or upgraded with this version of the CLI first", namespace, linkerdConfigMap)
+		}
+		return nil, err
+	}
+
+	config := &linkerdConfig{}
+	if err := json.Unmarshal([]byte(configMap.Data[linkerdConfigKey]), config); err != nil {
+		return nil, fmt.Errorf("Invalid %s ConfigMap: %s", linkerdConfigMap, err)
+	}
+	return config, nil
+}
+
+func renderLinkerdConfig(w io.Writer, config *linkerdConfig) error {
+	for _, flag := range config.Flags {
+		if kv := strings.SplitN(flag, "=", 2); len(kv) == 2 {
+			flag = kv[0] + "=" + shellQuote(kv[1])
+		}
+		if _, err := fmt.Fprintln(w, flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runConfigSet(w io.Writer, clientset kubernetes.Interface, namespace string, settings []string, options *configSetOptions) error {
+	config, err := getLinkerdConfig(clientset, namespace)
+	if err != nil {
+		return err
+	}
+	installOptions, flags, err := parseLinkerdConfig(config)
+	if err != nil {
+		return err
+	}
+	if err := applyConfigSettings(flags, settings); err != nil {
+		return err
+	}
+
+	installOptions.recordedFlags = recordedInstallFlags(flags)
+	// keep the images of the installed version, rather than the CLI's
+	if !flags.Changed("linkerd-version") && config.Version != "" {
+		installOptions.linkerdVersion = config.Version
+	}
+	// the issuer files are only read to render the issuer secret, which is
+	// already in the cluster
+	if installOptions.tlsIssuerCertFile != "" {
+		installOptions.tlsIssuerSecret = defaultTLSIssuerSecret
+		installOptions.tlsIssuerCertFile = ""
+		installOptions.tlsIssuerKeyFile = ""
+	}
+	installConfig, err := validateAndBuildConfig(installOptions)
+	if err != nil {
+		return err
+	}
+	rendered := &bytes.Buffer{}
+	if err := render(*installConfig, rendered, installOptions); err != nil {
+		return err
+	}
+	configMaps, err := renderedConfigMaps(rendered)
+	if err != nil {
+		return err
+	}
+
+	if err := updateConfigMapData(clientset, namespace, linkerdConfigMap, configMaps[linkerdConfigMap]); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Updated the %s ConfigMap\n", linkerdConfigMap)
+
+	sidecarConfig, ok := configMaps[proxyInjectorSidecarConfigMap]
+	if !ok {
+		fmt.Fprintln(w, "The proxy injector isn't installed: the new proxy defaults apply to the workloads injected with \"linkerd inject\" from now on")
+		return nil
+	}
+	if err := updateConfigMapData(clientset, namespace, proxyInjectorSidecarConfigMap, sidecarConfig); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Updated the %s ConfigMap\n", proxyInjectorSidecarConfigMap)
+
+	if !options.restart {
+		fmt.Fprintln(w, "The running proxies keep their configuration until their pods are recreated; run the command with --restart to roll out the meshed deployments")
+		return nil
+	}
+	return restartMeshedDeployments(w, clientset, namespace, options.timeout, time.Now())
+}
+
+// parseLinkerdConfig returns the install options of the stored config, along
+// with the flags that they're parsed from.
+func parseLinkerdConfig(config *linkerdConfig) (*installOptions, *pflag.FlagSet, error) {
+	options := newInstallOptions()
+	cmd := &cobra.Command{}
+	addInstallFlags(cmd, options)
+	flags := cmd.PersistentFlags()
+	if err := flags.Parse(config.Flags); err != nil {
+		return nil, nil, fmt.Errorf("Invalid %s ConfigMap: %s", linkerdConfigMap, err)
+	}
+	return options, flags, nil
+}
+
+// applyConfigSettings sets the proxy flags of the NAME=VALUE settings.
+func applyConfigSettings(flags *pflag.FlagSet, settings []string) error {
+	proxyFlags := &cobra.Command{}
+	addProxyConfigFlags(proxyFlags, newProxyConfigOptions())
+
+	for _, setting := range settings {
+		kv := strings.SplitN(setting, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("Invalid setting '%s': must be of the form NAME=VALUE", setting)
+		}
+		if proxyFlags.PersistentFlags().Lookup(kv[0]) == nil {
+			names := []string{}
+			proxyFlags.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+				names = append(names, flag.Name)
+			})
+			sort.Strings(names)
+			return fmt.Errorf("Invalid setting '%s': %s isn't a proxy flag, must be one of: %s", setting, kv[0], strings.Join(names, ", "))
+		}
+		if err := flags.Set(kv[0], kv[1]); err != nil {
+			return fmt.Errorf("Invalid setting '%s': %s", setting, err)
+		}
+	}
+	return nil
+}
+
+// renderedConfigMaps returns the data of the ConfigMaps of the rendered
+// configs, by name.
+func renderedConfigMaps(rendered io.Reader) (map[string]map[string]string, error) {
+	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(rendered, 4096))
+	configMaps := map[string]map[string]string{}
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return configMaps, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var resource installResource
+		if err := yaml.Unmarshal(doc, &resource); err != nil {
+			return nil, err
+		}
+		if resource.Kind != "ConfigMap" {
+			continue
+		}
+		var configMap v1.ConfigMap
+		if err := yaml.Unmarshal(doc, &configMap); err != nil {
+			return nil, err
+		}
+		configMaps[configMap.Name] = configMap.Data
+	}
+}
+
+func updateConfigMapData(clientset kubernetes.Interface, namespace, name string, data map[string]string) error {
+	configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(name, metaV1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	configMap.Data = data
+	_, err = clientset.CoreV1().ConfigMaps(namespace).Update(configMap)
+	return err
+}
+
+// restartMeshedDeployments rolls out the proxy injector, so that it reads its
+// updated proxy specs right away, then the deployments with the proxy in the
+// namespaces with auto-injection enabled, waiting for each rollout.
+func restartMeshedDeployments(w io.Writer, clientset kubernetes.Interface, namespace string, timeout time.Duration, now time.Time) error {
+	restartedAt := now.Format(time.RFC3339)
+	restart := func(deployment *appsV1.Deployment) (bool, error) {
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = map[string]string{}
+		}
+		deployment.Spec.Template.Annotations[restartedAtAnnotation] = restartedAt
+		return true, nil
+	}
+
+	rolledOut, err := rolloutDeployments(w, clientset, namespace, func(deployment *appsV1.Deployment) (bool, error) {
+		if deployment.Name != proxyInjectorDeployment {
+			return false, nil
+		}
+		return restart(deployment)
+	}, false)
+	if err != nil {
+		return err
+	}
+	if err := waitForRollouts(w, clientset, namespace, rolledOut, timeout); err != nil {
+		return err
+	}
+
+	namespaces, err := clientset.CoreV1().Namespaces().List(metaV1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", k8s.ProxyAutoInjectLabel, k8s.ProxyAutoInjectEnabled),
+	})
+	if err != nil {
+		return err
+	}
+	for _, ns := range namespaces.Items {
+		rolledOut, err := rolloutDeployments(w, clientset, ns.Name, func(deployment *appsV1.Deployment) (bool, error) {
+			if !hasProxyContainer(&deployment.Spec.Template.Spec) {
+				return false, nil
+			}
+			return restart(deployment)
+		}, false)
+		if err != nil {
+			return err
+		}
+		if err := waitForRollouts(w, clientset, ns.Name, rolledOut, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func configTestConfigMap(name, config string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: controlPlaneNamespace},
+		Data:       map[string]string{linkerdConfigKey: config},
+	}
+}
+
+func TestRecordedInstallFlags(t *testing.T) {
+	options := newInstallOptions()
+	cmd := newCmdInstall()
+	flags := cmd.PersistentFlags()
+	for name, value := range map[string]string{
+		"ha":                "true",
+		"proxy-log-level":   "warn,linkerd2_proxy=debug",
+		"output-dir":        "manifests",
+		"metric-pod-labels": "version,team",
+	} {
+		if err := flags.Set(name, value); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	expected := []string{"--ha", "--metric-pod-labels=version,team", "--proxy-log-level=warn,linkerd2_proxy=debug"}
+	if flags := recordedInstallFlags(flags); strings.Join(flags, " ") != strings.Join(expected, " ") {
+		t.Fatalf("Expected flags %v, got %v", expected, flags)
+	}
+
+	options.recordedFlags = expected
+	config, err := validateAndBuildConfig(options)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if config.LinkerdConfig != `{"version":"undefined","flags":["--ha","--metric-pod-labels=version,team","--proxy-log-level=warn,linkerd2_proxy=debug"]}` {
+		t.Fatalf("Unexpected config: %s", config.LinkerdConfig)
+	}
+}
+
+func TestRenderLinkerdConfig(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := renderLinkerdConfig(buf, &linkerdConfig{Version: "stable-2.2.1", Flags: []string{"--ha", "--proxy-log-level=warn,linkerd2_proxy=debug", "--enforced-host=^dashboard$"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := `--ha
+--proxy-log-level=warn,linkerd2_proxy=debug
+--enforced-host='^dashboard$'
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected output:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestRunConfigSet(t *testing.T) {
+	rolloutPollInterval = time.Millisecond
+
+	t.Run("Updates the stored flags and the proxy specs of the proxy injector", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(
+			configTestConfigMap(linkerdConfigMap, `{"version":"stable-2.2.1","flags":["--proxy-auto-inject","--ha","--tls=optional"]}`),
+			configTestConfigMap(proxyInjectorSidecarConfigMap, ""),
+		)
+
+		buf := &bytes.Buffer{}
+		err := runConfigSet(buf, clientset, controlPlaneNamespace, []string{"proxy-log-level=debug", "proxy-cpu=5m"}, &configSetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		config, err := getLinkerdConfig(clientset, controlPlaneNamespace)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := []string{"--ha", "--proxy-auto-inject", "--proxy-cpu=5m", "--proxy-log-level=debug", "--tls=optional"}
+		if config.Version != "stable-2.2.1" || strings.Join(config.Flags, " ") != strings.Join(expected, " ") {
+			t.Fatalf("Unexpected config: %+v", config)
+		}
+
+		sidecar, err := clientset.CoreV1().ConfigMaps(controlPlaneNamespace).Get(proxyInjectorSidecarConfigMap, metaV1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		spec := strings.Join([]string{sidecar.Data["proxy.yaml"], sidecar.Data["proxy-init.yaml"]}, "\n")
+		for _, expected := range []string{"value: debug", "cpu: 5m", "gcr.io/linkerd-io/proxy:stable-2.2.1"} {
+			if !strings.Contains(spec, expected) {
+				t.Fatalf("Expected the proxy specs to contain %q, got:\n%s", expected, spec)
+			}
+		}
+
+		if !strings.Contains(buf.String(), "run the command with --restart") {
+			t.Fatalf("Unexpected output:\n%s", buf.String())
+		}
+	})
+
+	t.Run("Restarts the proxy injector and the meshed deployments", func(t *testing.T) {
+		injector := meshTestDeployment(proxyInjectorDeployment, "proxy-injector", k8s.ProxyContainerName)
+		injector.Namespace = controlPlaneNamespace
+		clientset := fake.NewSimpleClientset(
+			configTestConfigMap(linkerdConfigMap, `{"version":"stable-2.2.1","flags":["--proxy-auto-inject","--tls=optional"]}`),
+			configTestConfigMap(proxyInjectorSidecarConfigMap, ""),
+			&v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "emojivoto", Labels: map[string]string{k8s.ProxyAutoInjectLabel: k8s.ProxyAutoInjectEnabled}}},
+			injector,
+			meshTestDeployment("web", "web", k8s.ProxyContainerName),
+			meshTestDeployment("vote-bot", "vote-bot"),
+		)
+
+		buf := &bytes.Buffer{}
+		err := runConfigSet(buf, clientset, controlPlaneNamespace, []string{"proxy-log-level=debug"}, &configSetOptions{restart: true, timeout: time.Second})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := `Updated the linkerd-config ConfigMap
+Updated the linkerd-proxy-injector-sidecar-config ConfigMap
+deployment/linkerd-proxy-injector is rolling out
+deployment/linkerd-proxy-injector: successfully rolled out
+deployment/web is rolling out
+deployment/web: successfully rolled out
+`
+		if buf.String() != expected {
+			t.Fatalf("Expected output:\n%s\ngot:\n%s", expected, buf.String())
+		}
+
+		bot, err := clientset.AppsV1().Deployments("emojivoto").Get("vote-bot", metaV1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if _, ok := bot.Spec.Template.Annotations[restartedAtAnnotation]; ok {
+			t.Fatal("Expected the deployment without the proxy to be left untouched")
+		}
+	})
+
+	t.Run("Rejects the flags other than the proxy's", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(configTestConfigMap(linkerdConfigMap, `{"version":"stable-2.2.1","flags":[]}`))
+
+		err := runConfigSet(&bytes.Buffer{}, clientset, controlPlaneNamespace, []string{"ha=true"}, &configSetOptions{})
+		if err == nil || !strings.HasPrefix(err.Error(), "Invalid setting 'ha=true': ha isn't a proxy flag, must be one of: ") {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		err = runConfigSet(&bytes.Buffer{}, clientset, controlPlaneNamespace, []string{"proxy-log-level"}, &configSetOptions{})
+		if err == nil || err.Error() != "Invalid setting 'proxy-log-level': must be of the form NAME=VALUE" {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Fails without a linkerd-config ConfigMap", func(t *testing.T) {
+		err := runConfigSet(&bytes.Buffer{}, fake.NewSimpleClientset(), controlPlaneNamespace, []string{"proxy-log-level=debug"}, &configSetOptions{})
+		expected := "The linkerd namespace has no linkerd-config ConfigMap; the control plane must be installed or upgraded with this version of the CLI first"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q, got: %v", expected, err)
+		}
+	})
+}
+
+func TestParseLinkerdConfig(t *testing.T) {
+	_, _, err := parseLinkerdConfig(&linkerdConfig{Flags: []string{"--no-such-flag"}})
+	if err == nil || err.Error() != "Invalid linkerd-config ConfigMap: unknown flag: --no-such-flag" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	options, _, err := parseLinkerdConfig(&linkerdConfig{Version: "stable-2.2.1", Flags: []string{"--controller-replicas=3"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if options.controllerReplicas != 3 {
+		t.Fatalf("Unexpected options: %+v", options)
+	}
+}
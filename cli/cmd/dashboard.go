@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"os/signal"
 	"time"
@@ -30,33 +32,52 @@ const (
 )
 
 type dashboardOptions struct {
-	port int
-	show string
-	wait time.Duration
+	address string
+	port    int
+	show    string
+	wait    time.Duration
 }
 
 func newDashboardOptions() *dashboardOptions {
 	return &dashboardOptions{
-		port: 0,
-		show: showLinkerd,
-		wait: 300 * time.Second,
+		address: "localhost",
+		port:    0,
+		show:    showLinkerd,
+		wait:    300 * time.Second,
 	}
 }
 
+func (options *dashboardOptions) validate() error {
+	if options.address != "localhost" && net.ParseIP(options.address) == nil {
+		return fmt.Errorf("Invalid value '%s' for --address flag: must be localhost or an IP address", options.address)
+	}
+
+	if options.port < 0 {
+		return fmt.Errorf("port must be greater than or equal to zero, was %d", options.port)
+	}
+
+	if options.show != showLinkerd && options.show != showGrafana && options.show != showURL {
+		return fmt.Errorf("unknown value for 'show' param, was: %s, must be one of: %s, %s, %s",
+			options.show, showLinkerd, showGrafana, showURL)
+	}
+
+	return nil
+}
+
 func newCmdDashboard() *cobra.Command {
 	options := newDashboardOptions()
 
 	cmd := &cobra.Command{
 		Use:   "dashboard [flags]",
 		Short: "Open the Linkerd dashboard in a web browser",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if options.port < 0 {
-				return fmt.Errorf("port must be greater than or equal to zero, was %d", options.port)
-			}
+		Example: `  # Print the URLs of the dashboards without opening a browser, e.g. on a headless workstation.
+  linkerd dashboard --show url --port 50750
 
-			if options.show != showLinkerd && options.show != showGrafana && options.show != showURL {
-				return fmt.Errorf("unknown value for 'show' param, was: %s, must be one of: %s, %s, %s",
-					options.show, showLinkerd, showGrafana, showURL)
+  # Serve the dashboards to other hosts.
+  linkerd dashboard --address 0.0.0.0 --port 50750 --show url`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.validate(); err != nil {
+				return err
 			}
 
 			// ensure we can connect to the public API before starting the proxy
@@ -67,11 +88,14 @@ func newCmdDashboard() *cobra.Command {
 			signal.Notify(signals, os.Interrupt)
 			defer signal.Stop(signals)
 
-			portforward, err := k8s.NewPortForward(
+			portforward, err := k8s.NewPortForwardOnAddress(
 				kubeconfigPath,
 				kubeContext,
+				impersonate,
+				impersonateGroup,
 				controlPlaneNamespace,
 				webDeployment,
+				options.address,
 				options.port,
 				webPort,
 				verbose,
@@ -80,6 +104,9 @@ func newCmdDashboard() *cobra.Command {
 				fmt.Fprintf(os.Stderr, "Failed to initialize port-forward: %s\n", err)
 				os.Exit(1)
 			}
+			portforward.OnEvent(func(event k8s.PortForwardEvent) {
+				printPortForwardEvent(os.Stderr, event)
+			})
 
 			go func() {
 				err := portforward.Run()
@@ -131,9 +158,27 @@ func newCmdDashboard() *cobra.Command {
 
 	cmd.Args = cobra.NoArgs
 	// This is identical to what `kubectl proxy --help` reports, `--port 0` indicates a random port.
+	cmd.PersistentFlags().StringVar(&options.address, "address", options.address, "The local address on which to serve requests, e.g. 0.0.0.0 to reach the dashboard from other hosts, which must then match the --enforced-host of \"linkerd install\"")
 	cmd.PersistentFlags().IntVarP(&options.port, "port", "p", options.port, "The local port on which to serve requests (when set to 0, a random port will be used)")
 	cmd.PersistentFlags().StringVar(&options.show, "show", options.show, "Open a dashboard in a browser or show URLs in the CLI (one of: linkerd, grafana, url)")
 	cmd.PersistentFlags().DurationVar(&options.wait, "wait", options.wait, "Wait for dashboard to become available if it's not available when the command is run")
 
 	return cmd
 }
+
+// printPortForwardEvent tells the user that the port-forward connection was
+// lost, e.g. because the pod restarted, and that it's being re-established.
+func printPortForwardEvent(w io.Writer, event k8s.PortForwardEvent) {
+	switch event.Type {
+	case k8s.PortForwardDisconnected:
+		if event.Err != nil {
+			fmt.Fprintf(w, "Lost the port-forward connection to %s: %s; reconnecting...\n", event.Pod, event.Err)
+		} else {
+			fmt.Fprintf(w, "Lost the port-forward connection to %s; reconnecting...\n", event.Pod)
+		}
+	case k8s.PortForwardRetrying:
+		fmt.Fprintf(w, "Failed to reconnect: %s; retrying in %s\n", event.Err, event.Backoff)
+	case k8s.PortForwardReconnected:
+		fmt.Fprintf(w, "Reconnected to %s\n", event.Pod)
+	}
+}
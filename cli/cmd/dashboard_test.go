@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestDashboardOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		address string
+		port    int
+		show    string
+		err     string
+	}{
+		{address: "localhost", port: 0, show: showLinkerd},
+		{address: "0.0.0.0", port: 50750, show: showURL},
+		{address: "::", port: 50750, show: showGrafana},
+		{address: "dashboard.example.com", port: 0, show: showLinkerd, err: "Invalid value 'dashboard.example.com' for --address flag: must be localhost or an IP address"},
+		{address: "localhost", port: -1, show: showLinkerd, err: "port must be greater than or equal to zero, was -1"},
+		{address: "localhost", port: 0, show: "prometheus", err: "unknown value for 'show' param, was: prometheus, must be one of: linkerd, grafana, url"},
+	}
+
+	for _, tc := range testCases {
+		options := newDashboardOptions()
+		options.address = tc.address
+		options.port = tc.port
+		options.show = tc.show
+
+		err := options.validate()
+		if tc.err == "" {
+			if err != nil {
+				t.Fatalf("Unexpected error for %+v: %s", tc, err)
+			}
+		} else if err == nil || err.Error() != tc.err {
+			t.Fatalf("Expected error %q for %+v, got: %v", tc.err, tc, err)
+		}
+	}
+}
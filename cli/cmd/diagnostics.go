@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	"k8s.io/api/core/v1"
+)
+
+// controllerAdminPorts are the ports of the admin servers of the control plane
+// containers, served by pkg/admin, by container name.
+var controllerAdminPorts = map[string]int32{
+	"public-api":     9995,
+	"proxy-api":      9996,
+	"ca":             9997,
+	"tap":            9998,
+	"proxy-injector": 9995,
+	"service-mirror": 9999,
+	"web":            9994,
+}
+
+// controllerStatePath is the path of the admin endpoint that reports the
+// internal state of a controller, served by pkg/admin.
+const controllerStatePath = "/state"
+
+type controllerStateDump struct {
+	Namespace   string            `json:"namespace"`
+	CollectedAt time.Time         `json:"collectedAt"`
+	Controllers []controllerState `json:"controllers"`
+}
+
+type controllerState struct {
+	Pod       string          `json:"pod"`
+	Container string          `json:"container"`
+	State     json.RawMessage `json:"state,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// podPortFunc returns the body of the response to a GET request for path on
+// the given port of a pod.
+type podPortFunc func(namespace, pod string, port int32, path string) ([]byte, error)
+
+func newCmdDiagnostics() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diagnostics [flags]",
+		Short: "Collect diagnostic information about the control plane",
+		Long:  "Collect diagnostic information about the control plane.",
+	}
+
+	cmd.AddCommand(newCmdDiagnosticsBundle())
+	cmd.AddCommand(newCmdDiagnosticsControllerState())
+	cmd.AddCommand(newCmdDiagnosticsLoadTest())
+	cmd.AddCommand(newCmdDiagnosticsLogLevel())
+	cmd.AddCommand(newCmdDiagnosticsProfile())
+	cmd.AddCommand(newCmdDiagnosticsProxy())
+	cmd.AddCommand(newCmdDiagnosticsVersionCheck())
+
+	return cmd
+}
+
+func newCmdDiagnosticsControllerState() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "controller-state [flags]",
+		Short: "Output the internal state of each controller as JSON",
+		Long: `Output the internal state of each controller as JSON.
+
+The state is read from the admin endpoint of each running controller container,
+through the Kubernetes API, and includes the destination service's watches and
+subscribed authorities, the number of admission reviews that the proxy
+injector is processing, and the certificate issuance counts and rates of the
+CA. The containers that can't be reached are reported with their errors, so
+that the document can still be attached to a bug report.`,
+		Example: `  # Save the state of the controllers, e.g. to attach it to a performance bug report.
+  linkerd diagnostics controller-state > controller-state.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+			if err != nil {
+				return err
+			}
+			client, err := kubeAPI.NewClient()
+			if err != nil {
+				return err
+			}
+			pods, err := kubeAPI.GetPodsByNamespace(client, controlPlaneNamespace)
+			if err != nil {
+				return err
+			}
+
+			get := func(namespace, pod string, port int32, path string) ([]byte, error) {
+				return kubeAPI.GetPodPort(client, namespace, pod, port, path)
+			}
+			dump := collectControllerState(controlPlaneNamespace, pods, get, time.Now())
+			return renderControllerState(os.Stdout, dump)
+		},
+	}
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+// collectControllerState reads the state of each controller container of the
+// running pods.
+func collectControllerState(namespace string, pods []v1.Pod, get podPortFunc, now time.Time) controllerStateDump {
+	dump := controllerStateDump{
+		Namespace:   namespace,
+		CollectedAt: now.UTC(),
+		Controllers: []controllerState{},
+	}
+
+	sorted := append([]v1.Pod{}, pods...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	for _, pod := range sorted {
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			port, ok := controllerAdminPorts[container.Name]
+			if !ok {
+				continue
+			}
+
+			state := controllerState{Pod: pod.Name, Container: container.Name}
+			body, err := get(pod.Namespace, pod.Name, port, controllerStatePath)
+			switch {
+			case err != nil:
+				state.Error = err.Error()
+			case !json.Valid(body):
+				state.Error = fmt.Sprintf("invalid state: %q", body)
+			default:
+				state.State = json.RawMessage(body)
+			}
+			dump.Controllers = append(dump.Controllers, state)
+		}
+	}
+	return dump
+}
+
+func renderControllerState(w io.Writer, dump controllerStateDump) error {
+	out, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", out)
+	return err
+}
@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/linkerd/linkerd2/pkg/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/version"
+	"github.com/spf13/cobra"
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// bundleDir is the directory that the files of a diagnostics bundle are
+// archived in.
+const bundleDir = "linkerd-diagnostics"
+
+// defaultBundleProxyMetricsPort is the port that the proxies serve their
+// metrics on, unless they were injected with another --metrics-port.
+const defaultBundleProxyMetricsPort = 4191
+
+type bundleOptions struct {
+	pods      []string
+	tailLines int64
+}
+
+func newBundleOptions() *bundleOptions {
+	return &bundleOptions{
+		pods:      []string{},
+		tailLines: 1000,
+	}
+}
+
+// bundleFile is a file of a diagnostics bundle, named relative to bundleDir.
+type bundleFile struct {
+	name    string
+	content []byte
+}
+
+// podLogsFunc returns the logs of a container of a pod.
+type podLogsFunc func(namespace, pod, container string) ([]byte, error)
+
+func newCmdDiagnosticsBundle() *cobra.Command {
+	options := newBundleOptions()
+
+	cmd := &cobra.Command{
+		Use:   "bundle [flags] FILE",
+		Short: "Collect the state of the control plane in a tarball for bug reports",
+		Long: `Collect the state of the control plane in a tarball for bug reports.
+
+The gzipped tarball holds:
+  * the version of the CLI and the output of "linkerd check"
+  * the logs and metrics of every container of the control plane pods
+  * the internal state of the controllers, as "linkerd diagnostics
+    controller-state" outputs it
+  * the pods, deployments and config maps of the control plane namespace
+  * the proxy logs and metrics of the pods selected with --pod
+
+The secrets of the control plane aren't collected. The logs and metrics that
+can't be read are replaced with the errors that prevented it, so that the
+bundle can still be attached to a bug report.`,
+		Example: `  # Collect the state of the control plane.
+  linkerd diagnostics bundle linkerd-diagnostics.tar.gz
+
+  # Also collect the proxy logs and metrics of two pods.
+  linkerd diagnostics bundle --pod emojivoto/web-5f86686c4d-58p7k --pod emojivoto/voting-6f7bf9b8c7-s4zjn linkerd-diagnostics.tar.gz`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			refs, err := parseBundlePods(options.pods)
+			if err != nil {
+				return err
+			}
+
+			kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+			if err != nil {
+				return err
+			}
+			client, err := kubeAPI.NewClient()
+			if err != nil {
+				return err
+			}
+			clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+			if err != nil {
+				return err
+			}
+
+			controlPlanePods, err := kubeAPI.GetPodsByNamespace(client, controlPlaneNamespace)
+			if err != nil {
+				return err
+			}
+			proxyPods := []v1.Pod{}
+			for _, ref := range refs {
+				pod, err := clientset.CoreV1().Pods(ref[0]).Get(ref[1], metaV1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				proxyPods = append(proxyPods, *pod)
+			}
+
+			get := func(namespace, pod string, port int32, path string) ([]byte, error) {
+				return kubeAPI.GetPodPort(client, namespace, pod, port, path)
+			}
+			logs := func(namespace, pod, container string) ([]byte, error) {
+				return kubeAPI.GetPodLogs(client, namespace, pod, container, options.tailLines)
+			}
+
+			now := time.Now()
+			files := []bundleFile{
+				{"version.txt", []byte(fmt.Sprintf("Client version: %s\n", version.Version))},
+				{"check.txt", bundleCheckOutput()},
+			}
+			resources, err := collectBundleResources(clientset, controlPlaneNamespace)
+			if err != nil {
+				return err
+			}
+			files = append(files, resources...)
+
+			state := &bytes.Buffer{}
+			if err := renderControllerState(state, collectControllerState(controlPlaneNamespace, controlPlanePods, get, now)); err != nil {
+				return err
+			}
+			files = append(files, bundleFile{"controller-state.json", state.Bytes()})
+
+			files = append(files, collectBundleLogs(controlPlanePods, false, logs)...)
+			files = append(files, collectBundleMetrics(controlPlanePods, get)...)
+			files = append(files, collectBundleLogs(proxyPods, true, logs)...)
+			files = append(files, collectBundleMetrics(proxyPods, get)...)
+
+			out, err := os.Create(args[0])
+			if err != nil {
+				return err
+			}
+			defer out.Close()
+			if err := writeBundle(out, files, now); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Wrote the diagnostics bundle to %s\n", args[0])
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringArrayVar(&options.pods, "pod", options.pods, "Also collect the proxy logs and metrics of this pod, given as namespace/name (may be repeated)")
+	cmd.PersistentFlags().Int64Var(&options.tailLines, "tail", options.tailLines, "Number of the most recent log lines to collect from each container, or 0 for all of them")
+
+	return cmd
+}
+
+// parseBundlePods parses the namespace and name of each --pod flag.
+func parseBundlePods(pods []string) ([][2]string, error) {
+	refs := make([][2]string, 0, len(pods))
+	for _, pod := range pods {
+		parts := strings.Split(pod, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid pod \"%s\", must be of the form namespace/name", pod)
+		}
+		refs = append(refs, [2]string{parts[0], parts[1]})
+	}
+	return refs, nil
+}
+
+// bundleCheckOutput returns the output of the checks that `linkerd check` runs
+// by default, without waiting for the failed checks to pass.
+func bundleCheckOutput() []byte {
+	checks := []healthcheck.CategoryID{
+		healthcheck.KubernetesAPIChecks,
+		healthcheck.KubernetesVersionChecks,
+		healthcheck.LinkerdControlPlaneExistenceChecks,
+		healthcheck.LinkerdAPIChecks,
+		healthcheck.LinkerdServiceProfileChecks,
+	}
+	hc := healthcheck.NewHealthChecker(checks, &healthcheck.Options{
+		ControlPlaneNamespace: controlPlaneNamespace,
+		KubeConfig:            kubeconfigPath,
+		KubeContext:           kubeContext,
+		Impersonate:           impersonate,
+		ImpersonateGroup:      impersonateGroup,
+		APIAddr:               apiAddr,
+		MutualTLS:             apiTLS,
+		RetryDeadline:         time.Now(),
+	})
+
+	buf := &bytes.Buffer{}
+	status := okStatus
+	if !runChecks(buf, hc) {
+		status = failStatus
+	}
+	fmt.Fprintf(buf, "\nStatus check results are %s\n", status)
+	return buf.Bytes()
+}
+
+// collectBundleResources returns the pods, deployments and config maps of the
+// namespace as YAML files.
+func collectBundleResources(clientset kubernetes.Interface, namespace string) ([]bundleFile, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	deployments, err := clientset.ExtensionsV1beta1().Deployments(namespace).List(metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	configMaps, err := clientset.CoreV1().ConfigMaps(namespace).List(metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	files := []bundleFile{}
+	for _, resources := range []struct {
+		name string
+		list interface{}
+	}{
+		{"pods.yaml", pods},
+		{"deployments.yaml", deployments},
+		{"configmaps.yaml", configMaps},
+	} {
+		out, err := yaml.Marshal(resources.list)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, bundleFile{path.Join("resources", resources.name), out})
+	}
+	return files, nil
+}
+
+// collectBundleLogs returns the logs of the containers of the pods, or only
+// of their proxy containers if proxyOnly is set, in one file per container.
+func collectBundleLogs(pods []v1.Pod, proxyOnly bool, logs podLogsFunc) []bundleFile {
+	files := []bundleFile{}
+	for _, pod := range sortedBundlePods(pods) {
+		for _, container := range pod.Spec.Containers {
+			if proxyOnly && container.Name != k8s.ProxyContainerName {
+				continue
+			}
+			content, err := logs(pod.Namespace, pod.Name, container.Name)
+			if err != nil {
+				content = []byte(fmt.Sprintf("error: %s\n", err))
+			}
+			files = append(files, bundleFile{path.Join("logs", pod.Namespace, pod.Name, container.Name+".log"), content})
+		}
+	}
+	return files
+}
+
+// collectBundleMetrics returns the metrics of the controller and proxy
+// containers of the running pods, in one file per container.
+func collectBundleMetrics(pods []v1.Pod, get podPortFunc) []bundleFile {
+	files := []bundleFile{}
+	for _, pod := range sortedBundlePods(pods) {
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			port, ok := controllerAdminPorts[container.Name]
+			if container.Name == k8s.ProxyContainerName {
+				port, ok = bundleProxyMetricsPort(container), true
+			}
+			if !ok {
+				continue
+			}
+
+			content, err := get(pod.Namespace, pod.Name, port, "/metrics")
+			if err != nil {
+				content = []byte(fmt.Sprintf("error: %s\n", err))
+			}
+			files = append(files, bundleFile{path.Join("metrics", pod.Namespace, pod.Name, container.Name+".prom"), content})
+		}
+	}
+	return files
+}
+
+func sortedBundlePods(pods []v1.Pod) []v1.Pod {
+	sorted := append([]v1.Pod{}, pods...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Namespace != sorted[j].Namespace {
+			return sorted[i].Namespace < sorted[j].Namespace
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func bundleProxyMetricsPort(proxy v1.Container) int32 {
+	for _, port := range proxy.Ports {
+		if port.Name == "linkerd-metrics" {
+			return port.ContainerPort
+		}
+	}
+	return defaultBundleProxyMetricsPort
+}
+
+// writeBundle writes the files to w as a gzipped tarball, under bundleDir.
+func writeBundle(w io.Writer, files []bundleFile, now time.Time) error {
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+
+	for _, file := range files {
+		header := &tar.Header{
+			Name:    path.Join(bundleDir, file.name),
+			Mode:    0644,
+			Size:    int64(len(file.content)),
+			ModTime: now,
+		}
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := archive.Write(file.content); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
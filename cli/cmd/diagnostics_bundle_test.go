@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseBundlePods(t *testing.T) {
+	refs, err := parseBundlePods([]string{"emojivoto/web-1", "linkerd/linkerd-ca-1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := [][2]string{{"emojivoto", "web-1"}, {"linkerd", "linkerd-ca-1"}}
+	if !reflect.DeepEqual(refs, expected) {
+		t.Fatalf("Expected %v, got %v", expected, refs)
+	}
+
+	for _, pod := range []string{"web-1", "emojivoto/", "emojivoto/web/1"} {
+		expected := fmt.Sprintf("invalid pod \"%s\", must be of the form namespace/name", pod)
+		if _, err := parseBundlePods([]string{pod}); err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q, got: %v", expected, err)
+		}
+	}
+}
+
+func TestCollectBundle(t *testing.T) {
+	pod := func(namespace, name string, phase v1.PodPhase, containers ...v1.Container) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       v1.PodSpec{Containers: containers},
+			Status:     v1.PodStatus{Phase: phase},
+		}
+	}
+	proxy := v1.Container{Name: "linkerd-proxy"}
+	pods := []v1.Pod{
+		pod("linkerd", "linkerd-prometheus-1", v1.PodRunning, v1.Container{Name: "prometheus"}, proxy),
+		pod("linkerd", "linkerd-ca-1", v1.PodRunning, v1.Container{Name: "ca"}, proxy),
+		pod("linkerd", "linkerd-ca-2", v1.PodPending, v1.Container{Name: "ca"}, proxy),
+	}
+	proxyPods := []v1.Pod{
+		pod("emojivoto", "web-1", v1.PodRunning, v1.Container{Name: "web-svc"}, v1.Container{
+			Name:  "linkerd-proxy",
+			Ports: []v1.ContainerPort{{Name: "linkerd-metrics", ContainerPort: 4192}},
+		}),
+	}
+
+	logs := func(namespace, pod, container string) ([]byte, error) {
+		if pod == "linkerd-ca-2" {
+			return nil, errors.New("container is waiting to start")
+		}
+		return []byte(fmt.Sprintf("%s/%s/%s\n", namespace, pod, container)), nil
+	}
+	get := func(namespace, pod string, port int32, path string) ([]byte, error) {
+		if port == 9997 {
+			return nil, errors.New("connection refused")
+		}
+		return []byte(fmt.Sprintf("%s/%s:%d%s\n", namespace, pod, port, path)), nil
+	}
+
+	t.Run("Collects the logs of the containers", func(t *testing.T) {
+		files := append(collectBundleLogs(pods, false, logs), collectBundleLogs(proxyPods, true, logs)...)
+		expected := []bundleFile{
+			{"logs/linkerd/linkerd-ca-1/ca.log", []byte("linkerd/linkerd-ca-1/ca\n")},
+			{"logs/linkerd/linkerd-ca-1/linkerd-proxy.log", []byte("linkerd/linkerd-ca-1/linkerd-proxy\n")},
+			{"logs/linkerd/linkerd-ca-2/ca.log", []byte("error: container is waiting to start\n")},
+			{"logs/linkerd/linkerd-ca-2/linkerd-proxy.log", []byte("error: container is waiting to start\n")},
+			{"logs/linkerd/linkerd-prometheus-1/prometheus.log", []byte("linkerd/linkerd-prometheus-1/prometheus\n")},
+			{"logs/linkerd/linkerd-prometheus-1/linkerd-proxy.log", []byte("linkerd/linkerd-prometheus-1/linkerd-proxy\n")},
+			{"logs/emojivoto/web-1/linkerd-proxy.log", []byte("emojivoto/web-1/linkerd-proxy\n")},
+		}
+		if !reflect.DeepEqual(files, expected) {
+			t.Fatalf("Expected %q, got %q", expected, files)
+		}
+	})
+
+	t.Run("Collects the metrics of the running containers", func(t *testing.T) {
+		files := append(collectBundleMetrics(pods, get), collectBundleMetrics(proxyPods, get)...)
+		expected := []bundleFile{
+			{"metrics/linkerd/linkerd-ca-1/ca.prom", []byte("error: connection refused\n")},
+			{"metrics/linkerd/linkerd-ca-1/linkerd-proxy.prom", []byte("linkerd/linkerd-ca-1:4191/metrics\n")},
+			{"metrics/linkerd/linkerd-prometheus-1/linkerd-proxy.prom", []byte("linkerd/linkerd-prometheus-1:4191/metrics\n")},
+			{"metrics/emojivoto/web-1/linkerd-proxy.prom", []byte("emojivoto/web-1:4192/metrics\n")},
+		}
+		if !reflect.DeepEqual(files, expected) {
+			t.Fatalf("Expected %q, got %q", expected, files)
+		}
+	})
+}
+
+func TestWriteBundle(t *testing.T) {
+	files := []bundleFile{
+		{"version.txt", []byte("Client version: dev\n")},
+		{"logs/linkerd/linkerd-ca-1/ca.log", []byte("time=\"2019-01-02T03:04:05Z\" level=info msg=\"starting CA\"\n")},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := writeBundle(buf, files, time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	gz, err := gzip.NewReader(buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	archive := tar.NewReader(gz)
+	for _, file := range files {
+		header, err := archive.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if header.Name != "linkerd-diagnostics/"+file.name {
+			t.Fatalf("Expected file %s, got %s", file.name, header.Name)
+		}
+		content, err := ioutil.ReadAll(archive)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !bytes.Equal(content, file.content) {
+			t.Fatalf("Expected %s to contain %q, got %q", file.name, file.content, content)
+		}
+	}
+	if _, err := archive.Next(); err != io.EOF {
+		t.Fatalf("Expected the end of the archive, got: %v", err)
+	}
+}
@@ -0,0 +1,590 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	destinationPb "github.com/linkerd/linkerd2-proxy-api/go/destination"
+	"github.com/linkerd/linkerd2/controller/api/util"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	addrUtil "github.com/linkerd/linkerd2/pkg/addr"
+	"github.com/linkerd/linkerd2/pkg/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// loadTestLabel identifies the sandbox namespaces of the load test, in case
+	// one is left behind by an interrupted test.
+	loadTestLabel = "linkerd.io/loadtest"
+
+	loadTestServicePort = 8080
+
+	// the proxy API is served on this port by the linkerd-controller pods
+	proxyAPIDeployment = "linkerd-controller"
+	proxyAPIPort       = 8086
+)
+
+type loadTestOptions struct {
+	namespace     string
+	services      uint
+	watchers      uint
+	apiClients    uint
+	churnInterval time.Duration
+	duration      time.Duration
+}
+
+// loadTestReport holds the results of a load test.
+type loadTestReport struct {
+	Namespace   string
+	Services    uint
+	Watchers    uint
+	APIClients  uint
+	Duration    time.Duration
+	Propagation latencySummary
+	ListPods    latencySummary
+	StatSummary latencySummary
+	Usage       []controllerUsage
+}
+
+// latencySummary summarizes the latencies of a kind of operation. Missed
+// counts the operations that failed, or that didn't complete before the end of
+// the test.
+type latencySummary struct {
+	Count  int
+	Missed int
+	P50    time.Duration
+	P95    time.Duration
+	P99    time.Duration
+	Max    time.Duration
+}
+
+// controllerUsage is the resource usage of a controller container during the
+// test, read from the process metrics that its admin server reports.
+type controllerUsage struct {
+	Pod        string
+	Container  string
+	CPUSeconds float64
+	RSSBytes   float64
+	Error      string
+}
+
+func newLoadTestOptions() *loadTestOptions {
+	return &loadTestOptions{
+		namespace:     "linkerd-loadtest",
+		services:      10,
+		watchers:      10,
+		apiClients:    2,
+		churnInterval: time.Second,
+		duration:      time.Minute,
+	}
+}
+
+func (options *loadTestOptions) validate() error {
+	if options.services == 0 {
+		return fmt.Errorf("--services must be greater than 0")
+	}
+	if options.churnInterval <= 0 {
+		return fmt.Errorf("--churn-interval must be greater than 0")
+	}
+	if options.duration < options.churnInterval {
+		return fmt.Errorf("--duration must be at least --churn-interval (%s)", options.churnInterval)
+	}
+	return nil
+}
+
+func newCmdDiagnosticsLoadTest() *cobra.Command {
+	options := newLoadTestOptions()
+
+	cmd := &cobra.Command{
+		Use:   "loadtest [flags]",
+		Short: "Measure how the control plane performs under synthetic load",
+		Long: `Measure how the control plane performs under synthetic load.
+
+The load test creates a sandbox namespace with services that have no pods, and
+replaces the address of one of their endpoints every --churn-interval. Each
+service is watched by --watchers clients of the destination API, which report
+how long each endpoint change took to reach them, and --api-clients clients
+call ListPods and StatSummary on the public API in a loop. The report includes
+the CPU time and memory used by the controllers during the test, so that the
+headroom of the control plane can be validated before growing the mesh.
+
+The sandbox namespace must not exist, and it is deleted after the test.`,
+		Example: `  # Watch 50 services with 20 clients each for 5 minutes.
+  linkerd diagnostics loadtest --services 50 --watchers 20 --duration 5m`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.validate(); err != nil {
+				return err
+			}
+
+			kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+			if err != nil {
+				return err
+			}
+			clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+			if err != nil {
+				return err
+			}
+			client, err := kubeAPI.NewClient()
+			if err != nil {
+				return err
+			}
+			publicAPI := cliPublicAPIClient()
+
+			conn, closeConn, err := dialProxyAPI()
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			if err := createLoadTestNamespace(clientset, options); err != nil {
+				return err
+			}
+			defer func() {
+				if err := clientset.CoreV1().Namespaces().Delete(options.namespace, &metaV1.DeleteOptions{}); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to delete the %s namespace: %s\n", options.namespace, err)
+				}
+			}()
+
+			getMetrics := func() map[controllerContainer][]byte {
+				pods, err := kubeAPI.GetPodsByNamespace(client, controlPlaneNamespace)
+				if err != nil {
+					return nil
+				}
+				return collectControllerMetrics(pods, func(namespace, pod string, port int32, path string) ([]byte, error) {
+					return kubeAPI.GetPodPort(client, namespace, pod, port, path)
+				})
+			}
+
+			fmt.Fprintf(os.Stderr, "Running the load test for %s\n", options.duration)
+			before := getMetrics()
+			report := runLoadTest(clientset, destinationPb.NewDestinationClient(conn), publicAPI, options)
+			report.Usage = controllerUsageBetween(before, getMetrics())
+
+			return renderLoadTestReport(os.Stdout, report)
+		},
+	}
+
+	cmd.Args = cobra.NoArgs
+	cmd.PersistentFlags().StringVar(&options.namespace, "namespace", options.namespace, "Name of the sandbox namespace to create the services in")
+	cmd.PersistentFlags().UintVar(&options.services, "services", options.services, "Number of services to create and watch")
+	cmd.PersistentFlags().UintVar(&options.watchers, "watchers", options.watchers, "Number of destination API clients that watch each service")
+	cmd.PersistentFlags().UintVar(&options.apiClients, "api-clients", options.apiClients, "Number of public API clients that call ListPods and StatSummary in a loop")
+	cmd.PersistentFlags().DurationVar(&options.churnInterval, "churn-interval", options.churnInterval, "Interval between endpoint changes")
+	cmd.PersistentFlags().DurationVar(&options.duration, "duration", options.duration, "Duration of the load test")
+
+	return cmd
+}
+
+// createLoadTestNamespace creates the sandbox namespace, with a service and
+// its endpoints for each of the watched services. The namespace is deleted
+// again if the services can't be created.
+func createLoadTestNamespace(clientset kubernetes.Interface, options *loadTestOptions) error {
+	ns := &v1.Namespace{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:   options.namespace,
+			Labels: map[string]string{loadTestLabel: "true"},
+		},
+	}
+	if _, err := clientset.CoreV1().Namespaces().Create(ns); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("the %s namespace already exists; delete it or use --namespace", options.namespace)
+		}
+		return err
+	}
+
+	for i := uint(0); i < options.services; i++ {
+		svc := &v1.Service{
+			ObjectMeta: metaV1.ObjectMeta{Name: loadTestServiceName(i), Namespace: options.namespace},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{
+					Name:       "http",
+					Port:       loadTestServicePort,
+					TargetPort: intstr.FromInt(loadTestServicePort),
+				}},
+			},
+		}
+		_, err := clientset.CoreV1().Services(options.namespace).Create(svc)
+		if err == nil {
+			_, err = clientset.CoreV1().Endpoints(options.namespace).Create(loadTestEndpoints(options.namespace, i, loadTestAddress(i)))
+		}
+		if err != nil {
+			clientset.CoreV1().Namespaces().Delete(options.namespace, &metaV1.DeleteOptions{})
+			return err
+		}
+	}
+	return nil
+}
+
+// runLoadTest watches the services of the sandbox namespace and changes their
+// endpoints until the end of the test, while calling the public API.
+func runLoadTest(clientset kubernetes.Interface, destination destinationPb.DestinationClient, publicAPI pb.ApiClient, options *loadTestOptions) loadTestReport {
+	ctx, cancel := context.WithTimeout(context.Background(), options.duration)
+	defer cancel()
+
+	tracker := newPropagationTracker()
+	wg := sync.WaitGroup{}
+
+	for i := uint(0); i < options.services; i++ {
+		authority := fmt.Sprintf("%s.%s.svc.cluster.local:%d", loadTestServiceName(i), options.namespace, loadTestServicePort)
+		service := loadTestServiceName(i)
+		for j := uint(0); j < options.watchers; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				watchLoadTestService(ctx, destination, authority, service, tracker)
+			}()
+		}
+	}
+
+	listPods := newLatencyRecorder()
+	statSummary := newLatencyRecorder()
+	for i := uint(0); i < options.apiClients; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			callPublicAPI(ctx, publicAPI, options.namespace, listPods, statSummary)
+		}()
+	}
+
+	churnEndpoints(ctx, clientset, options, tracker)
+	wg.Wait()
+
+	return loadTestReport{
+		Namespace:   options.namespace,
+		Services:    options.services,
+		Watchers:    options.watchers,
+		APIClients:  options.apiClients,
+		Duration:    options.duration,
+		Propagation: tracker.summary(int(options.watchers)),
+		ListPods:    listPods.summary(),
+		StatSummary: statSummary.summary(),
+	}
+}
+
+// churnEndpoints replaces the address of the services' endpoints, one service
+// at a time, every churn interval.
+func churnEndpoints(ctx context.Context, clientset kubernetes.Interface, options *loadTestOptions, tracker *propagationTracker) {
+	ticker := time.NewTicker(options.churnInterval)
+	defer ticker.Stop()
+
+	for change := options.services; ; change++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		i := change % options.services
+		address := loadTestAddress(change)
+		tracker.sent(loadTestServiceName(i), address, time.Now())
+		_, err := clientset.CoreV1().Endpoints(options.namespace).Update(loadTestEndpoints(options.namespace, i, address))
+		if err != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Failed to update the endpoints of %s: %s\n", loadTestServiceName(i), err)
+		}
+	}
+}
+
+func watchLoadTestService(ctx context.Context, destination destinationPb.DestinationClient, authority, service string, tracker *propagationTracker) {
+	rsp, err := destination.Get(ctx, &destinationPb.GetDestination{Scheme: "k8s", Path: authority})
+	if err != nil {
+		return
+	}
+	for {
+		update, err := rsp.Recv()
+		if err != nil {
+			return
+		}
+		if add, ok := update.Update.(*destinationPb.Update_Add); ok {
+			now := time.Now()
+			for _, addr := range add.Add.Addrs {
+				tracker.received(service, addrUtil.ProxyIPToString(addr.GetAddr().GetIp()), now)
+			}
+		}
+	}
+}
+
+func callPublicAPI(ctx context.Context, publicAPI pb.ApiClient, namespace string, listPods, statSummary *latencyRecorder) {
+	statReq, err := util.BuildStatSummaryRequest(util.StatsSummaryRequestParams{
+		StatsBaseRequestParams: util.StatsBaseRequestParams{
+			Namespace:    namespace,
+			ResourceType: k8s.Namespace,
+			ResourceName: namespace,
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	for ctx.Err() == nil {
+		start := time.Now()
+		_, err := publicAPI.ListPods(ctx, &pb.ListPodsRequest{Namespace: namespace})
+		listPods.record(time.Since(start), err)
+
+		start = time.Now()
+		_, err = publicAPI.StatSummary(ctx, statReq)
+		statSummary.record(time.Since(start), err)
+	}
+}
+
+func loadTestServiceName(i uint) string {
+	return fmt.Sprintf("loadtest-%d", i)
+}
+
+// loadTestAddress returns the n-th address of 198.18.0.0/15, the range
+// reserved for benchmarking.
+func loadTestAddress(n uint) string {
+	n %= 1 << 17
+	return fmt.Sprintf("198.%d.%d.%d", 18+(n>>16), (n>>8)&0xff, n&0xff)
+}
+
+func loadTestEndpoints(namespace string, i uint, address string) *v1.Endpoints {
+	return &v1.Endpoints{
+		ObjectMeta: metaV1.ObjectMeta{Name: loadTestServiceName(i), Namespace: namespace},
+		Subsets: []v1.EndpointSubset{{
+			Addresses: []v1.EndpointAddress{{IP: address}},
+			Ports:     []v1.EndpointPort{{Name: "http", Port: loadTestServicePort}},
+		}},
+	}
+}
+
+// propagationTracker matches the endpoint changes made by the load test with
+// the updates that the watchers receive.
+type propagationTracker struct {
+	sync.Mutex
+	changes   map[string]time.Time
+	latencies []time.Duration
+}
+
+func newPropagationTracker() *propagationTracker {
+	return &propagationTracker{changes: make(map[string]time.Time)}
+}
+
+func (t *propagationTracker) sent(service, address string, at time.Time) {
+	t.Lock()
+	defer t.Unlock()
+	t.changes[service+"/"+address] = at
+}
+
+// received records the latency of an update, unless it isn't for an endpoint
+// change, such as the initial endpoints of a service.
+func (t *propagationTracker) received(service, address string, at time.Time) {
+	t.Lock()
+	defer t.Unlock()
+	if sent, ok := t.changes[service+"/"+address]; ok {
+		t.latencies = append(t.latencies, at.Sub(sent))
+	}
+}
+
+// summary summarizes the latencies of the updates, given that each change
+// should reach each of the watchers of its service.
+func (t *propagationTracker) summary(watchers int) latencySummary {
+	t.Lock()
+	defer t.Unlock()
+	summary := summarizeLatencies(t.latencies)
+	summary.Missed = len(t.changes)*watchers - len(t.latencies)
+	if summary.Missed < 0 {
+		summary.Missed = 0
+	}
+	return summary
+}
+
+type latencyRecorder struct {
+	sync.Mutex
+	latencies []time.Duration
+	failed    int
+}
+
+func newLatencyRecorder() *latencyRecorder {
+	return &latencyRecorder{}
+}
+
+func (r *latencyRecorder) record(latency time.Duration, err error) {
+	r.Lock()
+	defer r.Unlock()
+	if err != nil {
+		r.failed++
+		return
+	}
+	r.latencies = append(r.latencies, latency)
+}
+
+func (r *latencyRecorder) summary() latencySummary {
+	r.Lock()
+	defer r.Unlock()
+	summary := summarizeLatencies(r.latencies)
+	summary.Missed = r.failed
+	return summary
+}
+
+func summarizeLatencies(latencies []time.Duration) latencySummary {
+	summary := latencySummary{Count: len(latencies)}
+	if len(latencies) == 0 {
+		return summary
+	}
+
+	sorted := append([]time.Duration{}, latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	quantile := func(q float64) time.Duration {
+		return sorted[int(math.Ceil(q*float64(len(sorted))))-1]
+	}
+
+	summary.P50 = quantile(0.5)
+	summary.P95 = quantile(0.95)
+	summary.P99 = quantile(0.99)
+	summary.Max = sorted[len(sorted)-1]
+	return summary
+}
+
+type controllerContainer struct {
+	pod       string
+	container string
+}
+
+// collectControllerMetrics reads the metrics of each controller container of
+// the running pods.
+func collectControllerMetrics(pods []v1.Pod, get podPortFunc) map[controllerContainer][]byte {
+	metrics := make(map[controllerContainer][]byte)
+	for _, pod := range pods {
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			port, ok := controllerAdminPorts[container.Name]
+			if !ok {
+				continue
+			}
+			body, err := get(pod.Namespace, pod.Name, port, "/metrics")
+			if err != nil {
+				continue
+			}
+			metrics[controllerContainer{pod.Name, container.Name}] = body
+		}
+	}
+	return metrics
+}
+
+// controllerUsageBetween returns the CPU time used by each controller between
+// two reads of their metrics, and their resident memory at the second one.
+func controllerUsageBetween(before, after map[controllerContainer][]byte) []controllerUsage {
+	keys := []controllerContainer{}
+	for key := range after {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].pod != keys[j].pod {
+			return keys[i].pod < keys[j].pod
+		}
+		return keys[i].container < keys[j].container
+	})
+
+	usage := []controllerUsage{}
+	for _, key := range keys {
+		u := controllerUsage{Pod: key.pod, Container: key.container}
+
+		cpuAfter, rss, err := parseProcessUsage(after[key])
+		if err != nil {
+			u.Error = err.Error()
+			usage = append(usage, u)
+			continue
+		}
+		cpuBefore := 0.0
+		if body, ok := before[key]; ok {
+			cpuBefore, _, _ = parseProcessUsage(body)
+		}
+		u.CPUSeconds = cpuAfter - cpuBefore
+		u.RSSBytes = rss
+		usage = append(usage, u)
+	}
+	return usage
+}
+
+// parseProcessUsage returns the CPU seconds and resident memory reported by
+// the process collector of the Prometheus client.
+func parseProcessUsage(body []byte) (float64, float64, error) {
+	families, err := healthcheck.ParseProxyMetrics(body)
+	if err != nil {
+		return 0, 0, err
+	}
+	value := func(name string) (float64, error) {
+		family, ok := families[name]
+		if !ok || len(family.GetMetric()) == 0 {
+			return 0, fmt.Errorf("%s not found", name)
+		}
+		metric := family.GetMetric()[0]
+		if metric.GetCounter() != nil {
+			return metric.GetCounter().GetValue(), nil
+		}
+		return metric.GetGauge().GetValue(), nil
+	}
+
+	cpu, err := value("process_cpu_seconds_total")
+	if err != nil {
+		return 0, 0, err
+	}
+	rss, err := value("process_resident_memory_bytes")
+	if err != nil {
+		return 0, 0, err
+	}
+	return cpu, rss, nil
+}
+
+func renderLoadTestReport(w io.Writer, report loadTestReport) error {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "Load test of %d services with %d watchers each and %d public API clients in the %s namespace, for %s\n\n",
+		report.Services, report.Watchers, report.APIClients, report.Namespace, report.Duration)
+
+	tw := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(tw, "OPERATION\tCOUNT\tMISSED\tP50\tP95\tP99\tMAX")
+	for _, row := range []struct {
+		name    string
+		summary latencySummary
+	}{
+		{"endpoint propagation", report.Propagation},
+		{"ListPods", report.ListPods},
+		{"StatSummary", report.StatSummary},
+	} {
+		s := row.summary
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\n", row.name, s.Count, s.Missed,
+			formatLatency(s.P50), formatLatency(s.P95), formatLatency(s.P99), formatLatency(s.Max))
+	}
+	tw.Flush()
+
+	if len(report.Usage) > 0 {
+		fmt.Fprintln(&buffer)
+		tw = tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', 0)
+		fmt.Fprintln(tw, "POD\tCONTAINER\tCPU\tMEMORY")
+		for _, u := range report.Usage {
+			if u.Error != "" {
+				fmt.Fprintf(tw, "%s\t%s\t-\t-\n", u.Pod, u.Container)
+				continue
+			}
+			cores := u.CPUSeconds / report.Duration.Seconds()
+			fmt.Fprintf(tw, "%s\t%s\t%dm\t%.0fMi\n", u.Pod, u.Container, int(math.Round(cores*1000)), u.RSSBytes/(1<<20))
+		}
+		tw.Flush()
+	}
+
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+func formatLatency(latency time.Duration) string {
+	if latency == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%dms", latency.Round(time.Millisecond)/time.Millisecond)
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreateLoadTestNamespace(t *testing.T) {
+	options := newLoadTestOptions()
+	options.services = 3
+	clientset := fake.NewSimpleClientset()
+
+	if err := createLoadTestNamespace(clientset, options); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	endpoints, err := clientset.CoreV1().Endpoints("linkerd-loadtest").List(metaV1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(endpoints.Items) != 3 {
+		t.Fatalf("Expected 3 endpoints, got %d", len(endpoints.Items))
+	}
+	ep, err := clientset.CoreV1().Endpoints("linkerd-loadtest").Get("loadtest-2", metaV1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if ip := ep.Subsets[0].Addresses[0].IP; ip != "198.18.0.2" {
+		t.Fatalf("Expected address 198.18.0.2, got %s", ip)
+	}
+
+	err = createLoadTestNamespace(clientset, options)
+	expected := "the linkerd-loadtest namespace already exists; delete it or use --namespace"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected error %q, got %v", expected, err)
+	}
+}
+
+func TestLoadTestAddress(t *testing.T) {
+	testCases := map[uint]string{
+		0:           "198.18.0.0",
+		257:         "198.18.1.1",
+		1<<16 + 3:   "198.19.0.3",
+		1<<17 + 300: "198.18.1.44",
+	}
+	for n, expected := range testCases {
+		if address := loadTestAddress(n); address != expected {
+			t.Errorf("Expected address %d to be %s, got %s", n, expected, address)
+		}
+	}
+}
+
+func TestPropagationTracker(t *testing.T) {
+	start := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	tracker := newPropagationTracker()
+
+	// the initial endpoints of a service aren't a change
+	tracker.received("loadtest-0", "198.18.0.0", start)
+
+	tracker.sent("loadtest-0", "198.18.0.2", start)
+	tracker.sent("loadtest-1", "198.18.0.3", start)
+	tracker.received("loadtest-0", "198.18.0.2", start.Add(10*time.Millisecond))
+	tracker.received("loadtest-0", "198.18.0.2", start.Add(30*time.Millisecond))
+	tracker.received("loadtest-1", "198.18.0.3", start.Add(20*time.Millisecond))
+
+	summary := tracker.summary(2)
+	expected := latencySummary{
+		Count:  3,
+		Missed: 1,
+		P50:    20 * time.Millisecond,
+		P95:    30 * time.Millisecond,
+		P99:    30 * time.Millisecond,
+		Max:    30 * time.Millisecond,
+	}
+	if summary != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, summary)
+	}
+}
+
+func TestSummarizeLatencies(t *testing.T) {
+	latencies := []time.Duration{}
+	for i := 100; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	summary := summarizeLatencies(latencies)
+	expected := latencySummary{
+		Count: 100,
+		P50:   50 * time.Millisecond,
+		P95:   95 * time.Millisecond,
+		P99:   99 * time.Millisecond,
+		Max:   100 * time.Millisecond,
+	}
+	if summary != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, summary)
+	}
+
+	if summary := summarizeLatencies(nil); summary != (latencySummary{}) {
+		t.Fatalf("Expected an empty summary, got %+v", summary)
+	}
+}
+
+func TestRenderLoadTestReport(t *testing.T) {
+	metrics := func(cpu, rss string) []byte {
+		return []byte(`# TYPE process_cpu_seconds_total counter
+process_cpu_seconds_total ` + cpu + `
+# TYPE process_resident_memory_bytes gauge
+process_resident_memory_bytes ` + rss + `
+`)
+	}
+	controller := controllerContainer{"linkerd-controller-6f78cbd47-bc557", "proxy-api"}
+	ca := controllerContainer{"linkerd-ca-5c9ff8b7b-qw2fk", "ca"}
+	web := controllerContainer{"linkerd-controller-6f78cbd47-bc557", "public-api"}
+
+	before := map[controllerContainer][]byte{
+		controller: metrics("10", "1e+07"),
+	}
+	after := map[controllerContainer][]byte{
+		controller: metrics("16", "5.24288e+07"),
+		ca:         metrics("0.5", "2.097152e+07"),
+		web:        []byte("404 page not found"),
+	}
+
+	report := loadTestReport{
+		Namespace:  "linkerd-loadtest",
+		Services:   10,
+		Watchers:   10,
+		APIClients: 2,
+		Duration:   time.Minute,
+		Propagation: latencySummary{
+			Count: 600, Missed: 2,
+			P50: 12 * time.Millisecond, P95: 40 * time.Millisecond, P99: 85 * time.Millisecond, Max: 120 * time.Millisecond,
+		},
+		ListPods: latencySummary{
+			Count: 300,
+			P50:   3 * time.Millisecond, P95: 5 * time.Millisecond, P99: 9 * time.Millisecond, Max: 11 * time.Millisecond,
+		},
+		StatSummary: latencySummary{Missed: 4},
+		Usage:       controllerUsageBetween(before, after),
+	}
+
+	output := &bytes.Buffer{}
+	if err := renderLoadTestReport(output, report); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := `Load test of 10 services with 10 watchers each and 2 public API clients in the linkerd-loadtest namespace, for 1m0s
+
+OPERATION              COUNT   MISSED   P50    P95    P99    MAX
+endpoint propagation   600     2        12ms   40ms   85ms   120ms
+ListPods               300     0        3ms    5ms    9ms    11ms
+StatSummary            0       4        -      -      -      -
+
+POD                                  CONTAINER    CPU    MEMORY
+linkerd-ca-5c9ff8b7b-qw2fk           ca           8m     20Mi
+linkerd-controller-6f78cbd47-bc557   proxy-api    100m   50Mi
+linkerd-controller-6f78cbd47-bc557   public-api   -      -
+`
+	diffCompare(t, output.String(), expected)
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/api/core/v1"
+)
+
+// controllerLogLevelPath is the path of the admin endpoint that reports and
+// changes the log level of a controller, served by pkg/admin.
+const controllerLogLevelPath = "/log-level"
+
+type logLevelOptions struct {
+	components []string
+}
+
+// controllerLogLevel is the log level of a controller container, or the error
+// that prevented reading or changing it.
+type controllerLogLevel struct {
+	pod       string
+	container string
+	level     string
+	err       error
+}
+
+// podPortPutFunc returns the body of the response to a PUT request with body
+// for path on the given port of a pod.
+type podPortPutFunc func(namespace, pod string, port int32, path string, body []byte) ([]byte, error)
+
+func newCmdDiagnosticsLogLevel() *cobra.Command {
+	options := &logLevelOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "log-level [flags] [LEVEL]",
+		Short: "Display or change the log level of the controllers",
+		Long: `Display or change the log level of the controllers.
+
+Without a LEVEL, the current log level of each running controller container is
+displayed. With a LEVEL (one of panic, fatal, error, warn, info or debug), the
+log level of the containers is changed at runtime, through their admin
+endpoint, so that debug logs can be captured during an incident without
+restarting the pods. The log level returns to the one of the --log-level flag
+of the containers when they restart.`,
+		Example: `  # Display the log level of all the controllers.
+  linkerd diagnostics log-level
+
+  # Capture the debug logs of the destination service, then revert.
+  linkerd diagnostics log-level debug --component proxy-api
+  linkerd diagnostics log-level info --component proxy-api`,
+		Args: cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var level string
+			if len(args) == 1 {
+				parsed, err := log.ParseLevel(args[0])
+				if err != nil {
+					return err
+				}
+				level = parsed.String()
+			}
+			for _, component := range options.components {
+				if _, ok := controllerAdminPorts[component]; !ok {
+					return fmt.Errorf("invalid component \"%s\", must be one of: %s", component, strings.Join(controllerNames(), ", "))
+				}
+			}
+
+			kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+			if err != nil {
+				return err
+			}
+			client, err := kubeAPI.NewClient()
+			if err != nil {
+				return err
+			}
+			pods, err := kubeAPI.GetPodsByNamespace(client, controlPlaneNamespace)
+			if err != nil {
+				return err
+			}
+
+			get := func(namespace, pod string, port int32, path string) ([]byte, error) {
+				return kubeAPI.GetPodPort(client, namespace, pod, port, path)
+			}
+			put := func(namespace, pod string, port int32, path string, body []byte) ([]byte, error) {
+				return kubeAPI.PutPodPort(client, namespace, pod, port, path, body)
+			}
+			levels := updateControllerLogLevels(pods, options.components, level, get, put)
+			renderControllerLogLevels(os.Stdout, levels)
+
+			for _, l := range levels {
+				if l.err != nil {
+					return fmt.Errorf("failed to read or change the log level of some controllers")
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringSliceVarP(&options.components, "component", "c", options.components,
+		fmt.Sprintf("Only display or change the log level of these controller containers (one of: %s)", strings.Join(controllerNames(), ", ")))
+
+	return cmd
+}
+
+func controllerNames() []string {
+	names := make([]string, 0, len(controllerAdminPorts))
+	for name := range controllerAdminPorts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// updateControllerLogLevels reads the log level of each controller container
+// of the running pods, or changes it to level if it isn't empty. If components
+// isn't empty, only the containers with these names are considered.
+func updateControllerLogLevels(pods []v1.Pod, components []string, level string, get podPortFunc, put podPortPutFunc) []controllerLogLevel {
+	selected := make(map[string]bool, len(components))
+	for _, component := range components {
+		selected[component] = true
+	}
+
+	sorted := append([]v1.Pod{}, pods...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	levels := make([]controllerLogLevel, 0)
+	for _, pod := range sorted {
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			port, ok := controllerAdminPorts[container.Name]
+			if !ok || (len(selected) > 0 && !selected[container.Name]) {
+				continue
+			}
+
+			var body []byte
+			var err error
+			if level == "" {
+				body, err = get(pod.Namespace, pod.Name, port, controllerLogLevelPath)
+			} else {
+				body, err = put(pod.Namespace, pod.Name, port, controllerLogLevelPath, []byte(level))
+			}
+			levels = append(levels, controllerLogLevel{
+				pod:       pod.Name,
+				container: container.Name,
+				level:     strings.TrimSpace(string(body)),
+				err:       err,
+			})
+		}
+	}
+	return levels
+}
+
+func renderControllerLogLevels(w io.Writer, levels []controllerLogLevel) {
+	if len(levels) == 0 {
+		fmt.Fprintln(w, "No running controllers found.")
+		return
+	}
+
+	var buffer bytes.Buffer
+	t := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(t, strings.Join([]string{"POD", "CONTAINER", "LEVEL"}, "\t"))
+	for _, l := range levels {
+		level := l.level
+		if l.err != nil {
+			level = fmt.Sprintf("error: %s", l.err)
+		}
+		fmt.Fprintf(t, "%s\t%s\t%s\n", l.pod, l.container, level)
+	}
+	t.Flush()
+
+	w.Write(buffer.Bytes())
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpdateControllerLogLevels(t *testing.T) {
+	pod := func(name string, phase v1.PodPhase, containers ...string) v1.Pod {
+		pod := v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "linkerd"},
+			Status:     v1.PodStatus{Phase: phase},
+		}
+		for _, container := range containers {
+			pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: container})
+		}
+		return pod
+	}
+	pods := []v1.Pod{
+		pod("linkerd-controller-6f78cbd47-bc557", v1.PodRunning, "public-api", "proxy-api", "tap", "linkerd-proxy"),
+		pod("linkerd-ca-5c9ff8b7b-qw2fk", v1.PodRunning, "ca", "linkerd-proxy"),
+		pod("linkerd-controller-6f78cbd47-old12", v1.PodFailed, "public-api", "proxy-api", "tap", "linkerd-proxy"),
+		pod("linkerd-prometheus-74d6879cd6-bbdk6", v1.PodRunning, "prometheus", "linkerd-proxy"),
+	}
+
+	get := func(namespace, pod string, port int32, path string) ([]byte, error) {
+		if port == 9995 {
+			return nil, errors.New("connection refused")
+		}
+		return []byte("info\n"), nil
+	}
+	var puts []string
+	put := func(namespace, pod string, port int32, path string, body []byte) ([]byte, error) {
+		puts = append(puts, fmt.Sprintf("%s/%s:%d%s %s", namespace, pod, port, path, body))
+		return append(body, '\n'), nil
+	}
+
+	t.Run("Displays the log level of all the controllers", func(t *testing.T) {
+		levels := updateControllerLogLevels(pods, nil, "", get, put)
+		output := &bytes.Buffer{}
+		renderControllerLogLevels(output, levels)
+
+		expected := `POD                                  CONTAINER    LEVEL
+linkerd-ca-5c9ff8b7b-qw2fk           ca           info
+linkerd-controller-6f78cbd47-bc557   public-api   error: connection refused
+linkerd-controller-6f78cbd47-bc557   proxy-api    info
+linkerd-controller-6f78cbd47-bc557   tap          info
+`
+		diffCompare(t, output.String(), expected)
+		if len(puts) != 0 {
+			t.Fatalf("Expected no changes, got: %v", puts)
+		}
+	})
+
+	t.Run("Changes the log level of the selected controllers", func(t *testing.T) {
+		levels := updateControllerLogLevels(pods, []string{"proxy-api"}, "debug", get, put)
+		output := &bytes.Buffer{}
+		renderControllerLogLevels(output, levels)
+
+		expected := `POD                                  CONTAINER   LEVEL
+linkerd-controller-6f78cbd47-bc557   proxy-api   debug
+`
+		diffCompare(t, output.String(), expected)
+		if len(puts) != 1 || puts[0] != "linkerd/linkerd-controller-6f78cbd47-bc557:9996/log-level debug" {
+			t.Fatalf("Unexpected changes: %v", puts)
+		}
+	})
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	"k8s.io/api/core/v1"
+)
+
+// controllerProfilePath is the path of the profiling endpoints of the admin
+// servers, served by pkg/admin when the controllers run with the -enable-pprof
+// flag.
+const controllerProfilePath = "/debug/pprof/"
+
+// maxProfileDuration bounds the duration of CPU profiles, which the admin
+// servers must respond to within their write timeout.
+const maxProfileDuration = time.Minute
+
+// profileTypes are the profiles that can be captured, with the pprof endpoint
+// that serves each of them.
+var profileTypes = map[string]string{
+	"cpu":       "profile",
+	"heap":      "heap",
+	"goroutine": "goroutine",
+}
+
+type diagProfileOptions struct {
+	profileType string
+	duration    time.Duration
+	pod         string
+	output      string
+}
+
+func newDiagProfileOptions() *diagProfileOptions {
+	return &diagProfileOptions{
+		profileType: "cpu",
+		duration:    30 * time.Second,
+	}
+}
+
+func (o *diagProfileOptions) validate(component string) error {
+	if _, ok := controllerAdminPorts[component]; !ok {
+		return fmt.Errorf("invalid component \"%s\", must be one of: %s", component, strings.Join(controllerNames(), ", "))
+	}
+	if _, ok := profileTypes[o.profileType]; !ok {
+		return fmt.Errorf("--type must be one of: %s", strings.Join(profileTypeNames(), ", "))
+	}
+	if o.duration < time.Second || o.duration > maxProfileDuration {
+		return fmt.Errorf("--duration must be between 1s and %s", maxProfileDuration)
+	}
+	return nil
+}
+
+func newCmdDiagnosticsProfile() *cobra.Command {
+	options := newDiagProfileOptions()
+
+	cmd := &cobra.Command{
+		Use:   "profile [flags] COMPONENT",
+		Short: "Capture a CPU, heap or goroutine profile of a controller",
+		Long: `Capture a CPU, heap or goroutine profile of a controller.
+
+The admin port of a running pod of the controller container is port-forwarded
+to, and the profile is fetched from its net/http/pprof endpoints and saved to a
+file, for analysis with "go tool pprof". The controller must be running with
+the -enable-pprof flag, which serves these endpoints; they aren't served by
+default, and aren't built into the binaries built with the nopprof tag.
+
+COMPONENT is the name of the controller container, one of: ` + strings.Join(controllerNames(), ", ") + `.`,
+		Example: `  # Capture a 30 second CPU profile of the destination service.
+  linkerd diagnostics profile proxy-api
+
+  # Capture a heap profile of the public API of a given pod, then inspect it.
+  linkerd diagnostics profile public-api --type heap --pod linkerd-controller-5f86686c4d-58p7k -o heap.pprof
+  go tool pprof heap.pprof`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			component := args[0]
+			if err := options.validate(component); err != nil {
+				return err
+			}
+
+			kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+			if err != nil {
+				return err
+			}
+			client, err := kubeAPI.NewClient()
+			if err != nil {
+				return err
+			}
+			pods, err := kubeAPI.GetPodsByNamespace(client, controlPlaneNamespace)
+			if err != nil {
+				return err
+			}
+			podName, err := selectControllerPod(pods, component, options.pod)
+			if err != nil {
+				return err
+			}
+
+			output := options.output
+			if output == "" {
+				output = fmt.Sprintf("%s-%s.pprof", component, options.profileType)
+			}
+			if options.profileType == "cpu" {
+				fmt.Fprintf(os.Stderr, "Capturing a %s CPU profile of %s in %s...\n", options.duration, component, podName)
+			}
+			profile, err := fetchControllerProfile(podName, component, profilePath(options.profileType, options.duration), options.duration)
+			if err != nil {
+				return err
+			}
+			if err := ioutil.WriteFile(output, profile, 0644); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "Wrote the %s profile of %s in %s to %s\n", options.profileType, component, podName, output)
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&options.profileType, "type", "t", options.profileType,
+		fmt.Sprintf("Type of the profile (one of: %s)", strings.Join(profileTypeNames(), ", ")))
+	cmd.PersistentFlags().DurationVarP(&options.duration, "duration", "d", options.duration, "Duration of CPU profiles, of at most "+maxProfileDuration.String())
+	cmd.PersistentFlags().StringVar(&options.pod, "pod", options.pod, "Name of the control plane pod to profile (default the first running pod of the controller)")
+	cmd.PersistentFlags().StringVarP(&options.output, "output", "o", options.output, "Path of the file to save the profile to (default \"COMPONENT-TYPE.pprof\")")
+
+	return cmd
+}
+
+func profileTypeNames() []string {
+	names := make([]string, 0, len(profileTypes))
+	for name := range profileTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// profilePath returns the path of the pprof endpoint that serves the profile.
+// CPU profiles are captured for duration.
+func profilePath(profileType string, duration time.Duration) string {
+	path := controllerProfilePath + profileTypes[profileType]
+	if profileType == "cpu" {
+		path += fmt.Sprintf("?seconds=%d", int(duration.Seconds()))
+	}
+	return path
+}
+
+// selectControllerPod returns the name of the running pod with the controller
+// container to profile: podName if it's set, or else the first such pod by
+// name.
+func selectControllerPod(pods []v1.Pod, component, podName string) (string, error) {
+	sorted := append([]v1.Pod{}, pods...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	for _, pod := range sorted {
+		if podName != "" && pod.Name != podName {
+			continue
+		}
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			if container.Name == component {
+				return pod.Name, nil
+			}
+		}
+	}
+
+	if podName != "" {
+		return "", fmt.Errorf("The pod %s/%s isn't running or has no %s container", controlPlaneNamespace, podName, component)
+	}
+	return "", fmt.Errorf("No running pod with a %s container found in the %s namespace", component, controlPlaneNamespace)
+}
+
+// fetchControllerProfile port-forwards to the admin port of the controller
+// container of the pod, and returns the profile served at path.
+func fetchControllerProfile(podName, component, path string, duration time.Duration) ([]byte, error) {
+	pf, err := k8s.NewPodPortForward(kubeconfigPath, kubeContext, impersonate, impersonateGroup, controlPlaneNamespace, podName, 0, int(controllerAdminPorts[component]), verbose)
+	if err != nil {
+		return nil, err
+	}
+	defer pf.Stop()
+	go func() {
+		if err := pf.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running port-forward: %s\n", err)
+			os.Exit(1)
+		}
+	}()
+	<-pf.Ready()
+
+	client := &http.Client{Timeout: duration + 30*time.Second}
+	rsp, err := client.Get(pf.URLFor(path))
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	content, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case rsp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("The %s container of %s/%s doesn't serve profiles: it must run with the -enable-pprof flag", component, controlPlaneNamespace, podName)
+	case rsp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("The %s container of %s/%s responded to GET %s with %s: %s", component, controlPlaneNamespace, podName, path, rsp.Status, strings.TrimSpace(string(content)))
+	}
+	return content, nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestProfileOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		component   string
+		profileType string
+		duration    time.Duration
+		expected    string
+	}{
+		{"proxy-api", "cpu", 30 * time.Second, ""},
+		{"public-api", "heap", 0, "--duration must be between 1s and 1m0s"},
+		{"proxy-api", "cpu", 2 * time.Minute, "--duration must be between 1s and 1m0s"},
+		{"proxy-api", "mutex", 30 * time.Second, "--type must be one of: cpu, goroutine, heap"},
+		{"prometheus", "cpu", 30 * time.Second, "invalid component \"prometheus\", must be one of: ca, proxy-api, proxy-injector, public-api, service-mirror, tap, web"},
+	}
+
+	for _, tc := range testCases {
+		options := newDiagProfileOptions()
+		options.profileType = tc.profileType
+		options.duration = tc.duration
+
+		err := options.validate(tc.component)
+		if tc.expected == "" {
+			if err != nil {
+				t.Fatalf("Unexpected error for %+v: %s", tc, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.expected {
+			t.Fatalf("Expected error \"%s\" for %+v, got %v", tc.expected, tc, err)
+		}
+	}
+}
+
+func TestProfilePath(t *testing.T) {
+	if path := profilePath("cpu", 15*time.Second); path != "/debug/pprof/profile?seconds=15" {
+		t.Fatalf("Unexpected CPU profile path %s", path)
+	}
+	if path := profilePath("heap", 15*time.Second); path != "/debug/pprof/heap" {
+		t.Fatalf("Unexpected heap profile path %s", path)
+	}
+}
+
+func TestSelectControllerPod(t *testing.T) {
+	pod := func(name string, phase v1.PodPhase, containers ...string) v1.Pod {
+		pod := v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "linkerd"},
+			Status:     v1.PodStatus{Phase: phase},
+		}
+		for _, container := range containers {
+			pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: container})
+		}
+		return pod
+	}
+	pods := []v1.Pod{
+		pod("linkerd-controller-6f78cbd47-zz123", v1.PodRunning, "public-api", "proxy-api", "tap", "linkerd-proxy"),
+		pod("linkerd-ca-5c9ff8b7b-qw2fk", v1.PodRunning, "ca", "linkerd-proxy"),
+		pod("linkerd-controller-6f78cbd47-bc557", v1.PodRunning, "public-api", "proxy-api", "tap", "linkerd-proxy"),
+		pod("linkerd-controller-6f78cbd47-aa000", v1.PodFailed, "public-api", "proxy-api", "tap", "linkerd-proxy"),
+	}
+
+	testCases := []struct {
+		component string
+		podName   string
+		expected  string
+		err       string
+	}{
+		{component: "proxy-api", expected: "linkerd-controller-6f78cbd47-bc557"},
+		{component: "proxy-api", podName: "linkerd-controller-6f78cbd47-zz123", expected: "linkerd-controller-6f78cbd47-zz123"},
+		{component: "ca", expected: "linkerd-ca-5c9ff8b7b-qw2fk"},
+		{component: "proxy-api", podName: "linkerd-controller-6f78cbd47-aa000", err: "The pod linkerd/linkerd-controller-6f78cbd47-aa000 isn't running or has no proxy-api container"},
+		{component: "proxy-api", podName: "linkerd-ca-5c9ff8b7b-qw2fk", err: "The pod linkerd/linkerd-ca-5c9ff8b7b-qw2fk isn't running or has no proxy-api container"},
+		{component: "service-mirror", err: "No running pod with a service-mirror container found in the linkerd namespace"},
+	}
+
+	for _, tc := range testCases {
+		name, err := selectControllerPod(pods, tc.component, tc.podName)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error \"%s\", got %v", tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if name != tc.expected {
+			t.Fatalf("Expected pod %s for %s, got %s", tc.expected, tc.component, name)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// proxyLogLevelPath is the path of the admin endpoint that reports and changes
+// the log level of a proxy.
+const proxyLogLevelPath = "/proxy-log-level"
+
+type proxyAdminOptions struct {
+	namespace string
+}
+
+func newProxyAdminOptions() *proxyAdminOptions {
+	return &proxyAdminOptions{
+		namespace: "default",
+	}
+}
+
+func newCmdDiagnosticsProxy() *cobra.Command {
+	options := newProxyAdminOptions()
+
+	cmd := &cobra.Command{
+		Use:   "proxy [flags] POD PATH",
+		Short: "Fetch an endpoint of the admin API of a proxy",
+		Long: `Fetch an endpoint of the admin API of a proxy.
+
+The admin port of the proxy of the pod is port-forwarded to, and the body of
+the response to a GET request for PATH is output, e.g. /metrics, /ready or
+` + proxyLogLevelPath + `.`,
+		Example: `  # Check whether the proxy of a pod is ready.
+  linkerd diagnostics proxy -n emojivoto web-5f86686c4d-58p7k /ready
+
+  # Fetch the metrics of the proxy of a pod.
+  linkerd diagnostics proxy -n emojivoto web-5f86686c4d-58p7k /metrics`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body, err := proxyAdminRequest(options.namespace, args[0], http.MethodGet, args[1], nil)
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(body)
+			return err
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the pod")
+
+	cmd.AddCommand(newCmdDiagnosticsProxyLogLevel(options))
+
+	return cmd
+}
+
+func newCmdDiagnosticsProxyLogLevel(options *proxyAdminOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "log-level [flags] POD [LEVEL]",
+		Short: "Display or change the log level of a proxy",
+		Long: `Display or change the log level of a proxy.
+
+Without a LEVEL, the current log level of the proxy of the pod is displayed.
+With a LEVEL, e.g. debug or warn,linkerd2_proxy=debug, the log level of the
+proxy is changed at runtime, through its admin API, so that debug logs can be
+captured without restarting the pod. The log level returns to the one that the
+proxy was injected with when the pod restarts.`,
+		Example: `  # Display the log level of the proxy of a pod.
+  linkerd diagnostics proxy log-level -n emojivoto web-5f86686c4d-58p7k
+
+  # Capture the debug logs of the proxy, then revert.
+  linkerd diagnostics proxy log-level -n emojivoto web-5f86686c4d-58p7k warn,linkerd2_proxy=debug
+  linkerd diagnostics proxy log-level -n emojivoto web-5f86686c4d-58p7k warn,linkerd2_proxy=info`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			method, body := http.MethodGet, []byte(nil)
+			if len(args) == 2 {
+				level := strings.TrimSpace(args[1])
+				if level == "" {
+					return fmt.Errorf("Invalid log level '%s': the log level can't be empty", args[1])
+				}
+				method, body = http.MethodPut, []byte(level)
+			}
+
+			rsp, err := proxyAdminRequest(options.namespace, args[0], method, proxyLogLevelPath, body)
+			if err != nil {
+				return err
+			}
+			if method == http.MethodPut {
+				fmt.Printf("Changed the log level of the proxy of %s/%s to %s\n", options.namespace, args[0], body)
+				return nil
+			}
+			fmt.Println(strings.TrimSpace(string(rsp)))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// proxyAdminRequest port-forwards to the admin port of the proxy of the pod,
+// and returns the body of the response to the request for path.
+func proxyAdminRequest(namespace, podName, method, path string, body []byte) ([]byte, error) {
+	kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+	if err != nil {
+		return nil, err
+	}
+	pod, err := clientset.CoreV1().Pods(namespace).Get(podName, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	port, err := proxyAdminPort(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	pf, err := k8s.NewPodPortForward(kubeconfigPath, kubeContext, impersonate, impersonateGroup, namespace, podName, 0, int(port), verbose)
+	if err != nil {
+		return nil, err
+	}
+	defer pf.Stop()
+	go func() {
+		if err := pf.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running port-forward: %s\n", err)
+			os.Exit(1)
+		}
+	}()
+	<-pf.Ready()
+
+	req, err := http.NewRequest(method, pf.URLFor(proxyAdminPath(path)), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	content, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return nil, fmt.Errorf("The proxy of %s/%s responded to %s %s with %s: %s", namespace, podName, method, proxyAdminPath(path), rsp.Status, strings.TrimSpace(string(content)))
+	}
+	return content, nil
+}
+
+// proxyAdminPort returns the admin port of the proxy of the pod, which serves
+// its metrics.
+func proxyAdminPort(pod *v1.Pod) (int32, error) {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == k8s.ProxyContainerName {
+			return bundleProxyMetricsPort(container), nil
+		}
+	}
+	return 0, fmt.Errorf("The pod %s/%s has no %s container", pod.Namespace, pod.Name, k8s.ProxyContainerName)
+}
+
+// proxyAdminPath returns the path with a leading slash.
+func proxyAdminPath(path string) string {
+	if strings.HasPrefix(path, "/") {
+		return path
+	}
+	return "/" + path
+}
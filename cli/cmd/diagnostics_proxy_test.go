@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestProxyAdminPort(t *testing.T) {
+	testCases := []struct {
+		containers []v1.Container
+		port       int32
+		err        bool
+	}{
+		{
+			containers: []v1.Container{{Name: "web"}, {Name: k8s.ProxyContainerName}},
+			port:       defaultBundleProxyMetricsPort,
+		},
+		{
+			containers: []v1.Container{{Name: k8s.ProxyContainerName, Ports: []v1.ContainerPort{{Name: "linkerd-metrics", ContainerPort: 9998}}}},
+			port:       9998,
+		},
+		{
+			containers: []v1.Container{{Name: "web"}},
+			err:        true,
+		},
+	}
+
+	for i, tc := range testCases {
+		pod := &v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web-1", Namespace: "emojivoto"},
+			Spec:       v1.PodSpec{Containers: tc.containers},
+		}
+		port, err := proxyAdminPort(pod)
+		if tc.err {
+			if err == nil {
+				t.Fatalf("test case %d: expected an error, got port %d", i, port)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test case %d: unexpected error: %s", i, err)
+		}
+		if port != tc.port {
+			t.Fatalf("test case %d: expected port %d, got %d", i, tc.port, port)
+		}
+	}
+}
+
+func TestProxyAdminPath(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/metrics": "/metrics",
+		"ready":    "/ready",
+		"":         "/",
+	} {
+		if actual := proxyAdminPath(path); actual != expected {
+			t.Fatalf("Expected path %s for %q, got %s", expected, path, actual)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestControllerState(t *testing.T) {
+	pod := func(name string, phase v1.PodPhase, containers ...string) v1.Pod {
+		pod := v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "linkerd"},
+			Status:     v1.PodStatus{Phase: phase},
+		}
+		for _, container := range containers {
+			pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: container})
+		}
+		return pod
+	}
+	pods := []v1.Pod{
+		pod("linkerd-controller-6f78cbd47-bc557", v1.PodRunning, "public-api", "proxy-api", "tap", "linkerd-proxy"),
+		pod("linkerd-ca-5c9ff8b7b-qw2fk", v1.PodRunning, "ca", "linkerd-proxy"),
+		pod("linkerd-controller-6f78cbd47-old12", v1.PodFailed, "public-api", "proxy-api", "tap", "linkerd-proxy"),
+		pod("linkerd-prometheus-74d6879cd6-bbdk6", v1.PodRunning, "prometheus", "linkerd-proxy"),
+	}
+
+	get := func(namespace, pod string, port int32, path string) ([]byte, error) {
+		key := fmt.Sprintf("%s/%s:%d%s", namespace, pod, port, path)
+		switch key {
+		case "linkerd/linkerd-controller-6f78cbd47-bc557:9996/state":
+			return []byte(`{"destination":{"endpoints":{"watches":1}}}`), nil
+		case "linkerd/linkerd-controller-6f78cbd47-bc557:9998/state":
+			return []byte("404 page not found"), nil
+		case "linkerd/linkerd-ca-5c9ff8b7b-qw2fk:9997/state":
+			return []byte(`{"identity":{"issued":2}}`), nil
+		}
+		return nil, errors.New("connection refused")
+	}
+
+	dump := collectControllerState("linkerd", pods, get, time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC))
+	output := &bytes.Buffer{}
+	if err := renderControllerState(output, dump); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := `{
+  "namespace": "linkerd",
+  "collectedAt": "2019-01-02T03:04:05Z",
+  "controllers": [
+    {
+      "pod": "linkerd-ca-5c9ff8b7b-qw2fk",
+      "container": "ca",
+      "state": {
+        "identity": {
+          "issued": 2
+        }
+      }
+    },
+    {
+      "pod": "linkerd-controller-6f78cbd47-bc557",
+      "container": "public-api",
+      "error": "connection refused"
+    },
+    {
+      "pod": "linkerd-controller-6f78cbd47-bc557",
+      "container": "proxy-api",
+      "state": {
+        "destination": {
+          "endpoints": {
+            "watches": 1
+          }
+        }
+      }
+    },
+    {
+      "pod": "linkerd-controller-6f78cbd47-bc557",
+      "container": "tap",
+      "error": "invalid state: \"404 page not found\""
+    }
+  ]
+}
+`
+	diffCompare(t, output.String(), expected)
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/version"
+	"github.com/spf13/cobra"
+	appsV1 "k8s.io/api/apps/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type versionCheckOptions struct {
+	dryRun bool
+}
+
+// versionCheckRequest is a version check that the CLI or the dashboard sends,
+// with the URL that carries all its data, or the reason it's disabled.
+type versionCheckRequest struct {
+	source   string
+	url      string
+	disabled string
+}
+
+func newCmdDiagnosticsVersionCheck() *cobra.Command {
+	options := &versionCheckOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "version-check [flags]",
+		Short: "Display the data that the version checks send",
+		Long: `Display the data that the version checks send.
+
+The CLI, when running "linkerd check", and the dashboard, from the browsers of
+its users, query the version check endpoint for the latest Linkerd versions.
+Their queries carry the running Linkerd version, the UUID generated by
+"linkerd install" and the source of the query, and nothing else. This command
+displays the exact URLs of the queries, and sends the CLI's unless --dry-run is
+set.
+
+The dashboard's version checks are disabled by "linkerd install
+--disable-version-check", and the CLI's are skipped by "linkerd check
+--expected-version".`,
+		Example: `  # Display what the version checks send, without sending anything.
+  linkerd diagnostics version-check --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+			if err != nil {
+				return err
+			}
+			clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+			if err != nil {
+				return err
+			}
+			web, err := clientset.AppsV1().Deployments(controlPlaneNamespace).Get(webDeployment, metaV1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			requests, err := versionCheckRequests(web, version.CheckURL, version.Version)
+			if err != nil {
+				return err
+			}
+			renderVersionCheckRequests(os.Stdout, requests)
+			if options.dryRun || version.CheckURL == "" {
+				return nil
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			versions, err := version.DefaultChecker.LatestVersions(ctx, webUUID(web), "cli")
+			if err != nil {
+				return err
+			}
+			out, err := json.MarshalIndent(versions, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Printf("\nThe version check endpoint responded to the CLI with:\n%s\n", out)
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().BoolVar(&options.dryRun, "dry-run", options.dryRun, "Only display what the version checks send, without sending the CLI's")
+
+	return cmd
+}
+
+// versionCheckRequests returns the version checks of the CLI and of the
+// dashboard of the web deployment, given the endpoint and the version of the
+// CLI.
+func versionCheckRequests(web *appsV1.Deployment, checkURL, cliVersion string) ([]versionCheckRequest, error) {
+	requests := []versionCheckRequest{}
+
+	cli := versionCheckRequest{source: "cli"}
+	if checkURL == "" {
+		cli.disabled = "this build of the CLI has no version check endpoint"
+	} else {
+		url, err := version.CheckRequestURL(checkURL, cliVersion, webUUID(web), cli.source)
+		if err != nil {
+			return nil, err
+		}
+		cli.url = url
+	}
+	requests = append(requests, cli)
+
+	dashboard := versionCheckRequest{source: "web"}
+	dashboardURL := version.CheckURL
+	dashboardVersion := ""
+	for _, container := range web.Spec.Template.Spec.Containers {
+		if container.Name != "web" {
+			continue
+		}
+		if i := strings.LastIndex(container.Image, ":"); i >= 0 {
+			dashboardVersion = container.Image[i+1:]
+		}
+		for _, arg := range container.Args {
+			if strings.HasPrefix(arg, "-version-check-url=") {
+				dashboardURL = strings.TrimPrefix(arg, "-version-check-url=")
+			}
+		}
+	}
+	if dashboardURL == "" {
+		dashboard.disabled = "the dashboard was installed with --disable-version-check"
+	} else {
+		url, err := version.CheckRequestURL(dashboardURL, dashboardVersion, webUUID(web), dashboard.source)
+		if err != nil {
+			return nil, err
+		}
+		dashboard.url = url
+	}
+	requests = append(requests, dashboard)
+
+	return requests, nil
+}
+
+// webUUID returns the install UUID, which is only known to the web deployment.
+func webUUID(web *appsV1.Deployment) string {
+	for _, container := range web.Spec.Template.Spec.Containers {
+		for _, arg := range container.Args {
+			if strings.HasPrefix(arg, "-uuid=") {
+				return strings.TrimPrefix(arg, "-uuid=")
+			}
+		}
+	}
+	return "unknown"
+}
+
+func renderVersionCheckRequests(w io.Writer, requests []versionCheckRequest) {
+	for i, request := range requests {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		name := "The CLI"
+		if request.source == "web" {
+			name = "The dashboard"
+		}
+		if request.disabled != "" {
+			fmt.Fprintf(w, "%s doesn't check for new versions: %s\n", name, request.disabled)
+			continue
+		}
+		fmt.Fprintf(w, "%s checks for new versions with:\nGET %s\n", name, request.url)
+	}
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+)
+
+func TestVersionCheckRequests(t *testing.T) {
+	web := func(args ...string) *appsV1.Deployment {
+		deployment := &appsV1.Deployment{}
+		deployment.Spec.Template.Spec.Containers = []v1.Container{{
+			Name:  "web",
+			Image: "gcr.io/linkerd-io/web:stable-2.2.1",
+			Args:  append([]string{"-uuid=a1b2c3"}, args...),
+		}}
+		return deployment
+	}
+
+	t.Run("Displays the URLs of the version checks", func(t *testing.T) {
+		requests, err := versionCheckRequests(web(), "https://versioncheck.example.com/version.json", "edge-19.3.1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		var buf bytes.Buffer
+		renderVersionCheckRequests(&buf, requests)
+		expected := `The CLI checks for new versions with:
+GET https://versioncheck.example.com/version.json?source=cli&uuid=a1b2c3&version=edge-19.3.1
+
+The dashboard checks for new versions with:
+GET https://versioncheck.linkerd.io/version.json?source=web&uuid=a1b2c3&version=stable-2.2.1
+`
+		if buf.String() != expected {
+			t.Fatalf("Expected:\n%s\nGot:\n%s", expected, buf.String())
+		}
+	})
+
+	t.Run("Displays the disabled version checks", func(t *testing.T) {
+		requests, err := versionCheckRequests(web("-version-check-url="), "", "edge-19.3.1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		var buf bytes.Buffer
+		renderVersionCheckRequests(&buf, requests)
+		expected := `The CLI doesn't check for new versions: this build of the CLI has no version check endpoint
+
+The dashboard doesn't check for new versions: the dashboard was installed with --disable-version-check
+`
+		if buf.String() != expected {
+			t.Fatalf("Expected:\n%s\nGot:\n%s", expected, buf.String())
+		}
+	})
+}
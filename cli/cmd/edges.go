@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/util"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// unmeshedSrc is displayed as the source of the edges from clients without an
+// identity.
+const unmeshedSrc = "(unmeshed)"
+
+type edgesOptions struct {
+	namespace     string
+	timeWindow    string
+	outputFormat  string
+	allNamespaces bool
+	watch         bool
+}
+
+func newEdgesOptions() *edgesOptions {
+	return &edgesOptions{
+		namespace:     "default",
+		timeWindow:    "1m",
+		outputFormat:  "",
+		allNamespaces: false,
+		watch:         false,
+	}
+}
+
+func (o *edgesOptions) validate() error {
+	switch o.outputFormat {
+	case "table", "wide", "json", "":
+	default:
+		return fmt.Errorf("--output currently only supports table, wide, and json")
+	}
+	if o.watch && o.outputFormat != "" {
+		return fmt.Errorf("--output is not supported with --watch")
+	}
+	return nil
+}
+
+func newCmdEdges() *cobra.Command {
+	options := newEdgesOptions()
+
+	cmd := &cobra.Command{
+		Use:   "edges [flags] (RESOURCE)",
+		Short: "Display connections between resources, and whether they are secured by TLS",
+		Long: `Display connections between resources, and whether they are secured by TLS.
+
+  The RESOURCE argument specifies the resource(s) whose inbound and outbound
+  edges are displayed:
+  (TYPE [NAME] | TYPE/NAME)
+
+  Examples:
+  * deploy
+  * deploy/my-deploy
+  * ns/my-ns
+
+  Valid resource types include:
+  * deployments
+  * namespaces
+  * pods
+  * replicationcontrollers
+
+An edge is displayed if traffic was observed between two meshed resources in
+the stat window. The requests that a resource received from clients without an
+identity are displayed as an edge from "(unmeshed)", to track down the
+remaining plaintext callers; this requires TLS to be enabled. With --watch, edges are displayed as they are added or
+removed, and when their TLS status changes.
+
+The wide output also displays the TLS identities of the client and the server
+of the secured edges, how long ago traffic was last seen over each edge, and
+why an edge has no identities, e.g. because its source isn't meshed.`,
+		Example: `  # Get all edges between deployments in the test namespace.
+  linkerd edges deploy -n test
+
+  # Get all edges of the web deployment, with their identities.
+  linkerd edges deploy/web -o wide
+
+  # Get all edges of the web deployment, and watch for changes.
+  linkerd edges deploy/web --watch`,
+		Args:      cobra.RangeArgs(1, 2),
+		ValidArgs: util.ValidTargets,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.validate(); err != nil {
+				return err
+			}
+
+			req, err := buildEdgesRequest(strings.Join(args, "/"), options)
+			if err != nil {
+				return fmt.Errorf("error creating edges request: %v", err)
+			}
+
+			if options.watch {
+				return watchEdgesFromAPI(os.Stdout, cliPublicAPIClient(), req)
+			}
+
+			output, err := requestEdgesFromAPI(cliPublicAPIClient(), req, options)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Print(output)
+			return err
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the specified resource")
+	cmd.PersistentFlags().StringVarP(&options.timeWindow, "time-window", "t", options.timeWindow, "Stat window over which traffic is considered (for example: \"10s\", \"1m\", \"10m\", \"1h\")")
+	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; currently only \"table\" (default), \"wide\", and \"json\" are supported")
+	cmd.PersistentFlags().BoolVar(&options.allNamespaces, "all-namespaces", options.allNamespaces, "If present, returns edges across all namespaces, ignoring the \"--namespace\" flag")
+	cmd.PersistentFlags().BoolVarP(&options.watch, "watch", "w", options.watch, "After listing the current edges, watch for changes")
+
+	return cmd
+}
+
+func buildEdgesRequest(resource string, options *edgesOptions) (*pb.EdgesRequest, error) {
+	target, err := util.BuildResource(options.namespace, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.BuildEdgesRequest(util.StatsBaseRequestParams{
+		TimeWindow:    options.timeWindow,
+		ResourceName:  target.Name,
+		ResourceType:  target.Type,
+		Namespace:     options.namespace,
+		AllNamespaces: options.allNamespaces,
+	})
+}
+
+func requestEdgesFromAPI(client pb.ApiClient, req *pb.EdgesRequest, options *edgesOptions) (string, error) {
+	resp, err := client.Edges(context.Background(), req)
+	if err != nil {
+		return "", fmt.Errorf("Edges API error: %v", err)
+	}
+	if e := resp.GetError(); e != nil {
+		return "", errors.New(e.Error)
+	}
+
+	return renderEdges(resp.GetOk().GetEdges(), options.outputFormat, time.Now())
+}
+
+// renderEdges renders the edges in the output format. The last-seen times of
+// the wide output are relative to now.
+func renderEdges(edges []*pb.Edge, outputFormat string, now time.Time) (string, error) {
+	if outputFormat == "json" {
+		return renderEdgesJSON(edges)
+	}
+	if len(edges) == 0 {
+		return "No edges found.\n", nil
+	}
+
+	wide := outputFormat == "wide"
+	headers := []string{"SRC", "DST", "SRC_NS", "DST_NS", "SECURED"}
+	if wide {
+		headers = append(headers, "CLIENT_ID", "SERVER_ID", "LAST_SEEN", "MSG")
+	}
+
+	var buffer bytes.Buffer
+	w := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, edge := range edges {
+		srcName, srcNamespace := edge.GetSrc().GetName(), edge.GetSrc().GetNamespace()
+		if edge.GetUnmeshedSrc() {
+			srcName, srcNamespace = unmeshedSrc, "-"
+		}
+		values := []string{
+			srcName,
+			edge.GetDst().GetName(),
+			srcNamespace,
+			edge.GetDst().GetNamespace(),
+			edgeSecured(edge),
+		}
+		if wide {
+			values = append(values,
+				orDash(edge.GetClientId()),
+				orDash(edge.GetServerId()),
+				formatLastSeen(edge.GetLastSeen(), now),
+				orDash(edge.GetNoIdentityMsg()),
+			)
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	w.Flush()
+
+	return buffer.String(), nil
+}
+
+type jsonEdge struct {
+	Src           string `json:"src"`
+	SrcNamespace  string `json:"src_namespace"`
+	Dst           string `json:"dst"`
+	DstNamespace  string `json:"dst_namespace"`
+	Kind          string `json:"kind"`
+	Secured       bool   `json:"secured"`
+	ClientID      string `json:"client_id"`
+	ServerID      string `json:"server_id"`
+	NoIdentityMsg string `json:"no_identity_msg"`
+	// LastSeen is a Unix time in seconds, and null if unknown
+	LastSeen *int64 `json:"last_seen"`
+}
+
+func renderEdgesJSON(edges []*pb.Edge) (string, error) {
+	// avoid nil initialization so that no edges are marshalled as an empty array
+	entries := []*jsonEdge{}
+	for _, edge := range edges {
+		entry := &jsonEdge{
+			Src:           edge.GetSrc().GetName(),
+			SrcNamespace:  edge.GetSrc().GetNamespace(),
+			Dst:           edge.GetDst().GetName(),
+			DstNamespace:  edge.GetDst().GetNamespace(),
+			Kind:          edge.GetDst().GetType(),
+			Secured:       edge.GetTls(),
+			ClientID:      edge.GetClientId(),
+			ServerID:      edge.GetServerId(),
+			NoIdentityMsg: edge.GetNoIdentityMsg(),
+		}
+		if edge.GetUnmeshedSrc() {
+			entry.Src = unmeshedSrc
+		}
+		if lastSeen := edge.GetLastSeen(); lastSeen > 0 {
+			entry.LastSeen = &lastSeen
+		}
+		entries = append(entries, entry)
+	}
+
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
+
+// formatLastSeen formats how long ago traffic was last seen over an edge.
+func formatLastSeen(lastSeen int64, now time.Time) string {
+	if lastSeen <= 0 {
+		return "-"
+	}
+	age := now.Sub(time.Unix(lastSeen, 0)).Round(time.Second)
+	if age < 0 {
+		age = 0
+	}
+	return age.String()
+}
+
+func watchEdgesFromAPI(w io.Writer, client pb.ApiClient, req *pb.EdgesRequest) error {
+	rsp, err := client.WatchEdges(context.Background(), req)
+	if err != nil {
+		return fmt.Errorf("WatchEdges API error: %v", err)
+	}
+
+	for {
+		log.Debug("Waiting for data...")
+		event, err := rsp.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(w, renderEdgeEvent(event))
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// renderEdgeEvent renders a Public API EdgeEvent to a string.
+func renderEdgeEvent(event *pb.EdgeEvent) string {
+	edge := event.GetEdge()
+	src := formatEdgeResource(edge.GetSrc())
+	if edge.GetUnmeshedSrc() {
+		src = unmeshedSrc
+	}
+	return fmt.Sprintf("%-6s %s -> %s secured=%s",
+		event.GetType().String(),
+		src,
+		formatEdgeResource(edge.GetDst()),
+		edgeSecured(edge),
+	)
+}
+
+func formatEdgeResource(resource *pb.Resource) string {
+	if resource.GetNamespace() == "" {
+		return fmt.Sprintf("%s/%s", resource.GetType(), resource.GetName())
+	}
+	return fmt.Sprintf("%s/%s.%s", resource.GetType(), resource.GetName(), resource.GetNamespace())
+}
+
+func edgeSecured(edge *pb.Edge) string {
+	if edge.GetTls() {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+func edge(src, dst string, tls bool) *pb.Edge {
+	return &pb.Edge{
+		Src: &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: src},
+		Dst: &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: dst},
+		Tls: tls,
+	}
+}
+
+func unmeshedEdge(dst string) *pb.Edge {
+	return &pb.Edge{
+		Src:         &pb.Resource{Type: k8s.Deployment},
+		Dst:         &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: dst},
+		UnmeshedSrc: true,
+	}
+}
+
+func TestRequestEdgesFromAPI(t *testing.T) {
+	t.Run("Renders the returned edges", func(t *testing.T) {
+		mockClient := &public.MockAPIClient{
+			EdgesResponseToReturn: &pb.EdgesResponse{
+				Response: &pb.EdgesResponse_Ok_{
+					Ok: &pb.EdgesResponse_Ok{
+						Edges: []*pb.Edge{
+							unmeshedEdge("web"),
+							edge("web", "emoji", true),
+							edge("vote-bot", "web", false),
+						},
+					},
+				},
+			},
+		}
+
+		req, err := buildEdgesRequest("deploy", newEdgesOptions())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		output, err := requestEdgesFromAPI(mockClient, req, newEdgesOptions())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := `SRC          DST     SRC_NS      DST_NS      SECURED
+(unmeshed)   web     -           emojivoto   no
+web          emoji   emojivoto   emojivoto   yes
+vote-bot     web     emojivoto   emojivoto   no
+`
+		if output != expected {
+			t.Fatalf("Expected output:\n%s\nGot:\n%s", expected, output)
+		}
+	})
+
+	t.Run("Returns an error from the API", func(t *testing.T) {
+		mockClient := &public.MockAPIClient{
+			EdgesResponseToReturn: &pb.EdgesResponse{
+				Response: &pb.EdgesResponse_Error{
+					Error: &pb.ResourceError{Error: "resource type 'authority' is not supported for edges"},
+				},
+			},
+		}
+
+		_, err := requestEdgesFromAPI(mockClient, &pb.EdgesRequest{}, newEdgesOptions())
+		if err == nil || err.Error() != "resource type 'authority' is not supported for edges" {
+			t.Fatalf("Expected API error to be returned, got %v", err)
+		}
+	})
+}
+
+func TestRenderEdges(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	secured := edge("web", "emoji", true)
+	secured.ClientId = "web.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local"
+	secured.ServerId = "emoji.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local"
+	secured.LastSeen = 990
+	unmeshed := unmeshedEdge("web")
+	unmeshed.NoIdentityMsg = "source not meshed"
+	unmeshed.LastSeen = 880
+	plaintext := edge("vote-bot", "web", false)
+	plaintext.NoIdentityMsg = "not all requests secured"
+	edges := []*pb.Edge{unmeshed, secured, plaintext}
+
+	t.Run("Renders the identities in the wide output", func(t *testing.T) {
+		output, err := renderEdges(edges, "wide", now)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := `SRC          DST     SRC_NS      DST_NS      SECURED   CLIENT_ID                                                            SERVER_ID                                                              LAST_SEEN   MSG
+(unmeshed)   web     -           emojivoto   no        -                                                                    -                                                                      2m0s        source not meshed
+web          emoji   emojivoto   emojivoto   yes       web.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local   emoji.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local   10s         -
+vote-bot     web     emojivoto   emojivoto   no        -                                                                    -                                                                      -           not all requests secured
+`
+		if output != expected {
+			t.Fatalf("Expected output:\n%s\nGot:\n%s", expected, output)
+		}
+	})
+
+	t.Run("Renders the edges as JSON", func(t *testing.T) {
+		output, err := renderEdges(edges[:2], "json", now)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := `[
+  {
+    "src": "(unmeshed)",
+    "src_namespace": "",
+    "dst": "web",
+    "dst_namespace": "emojivoto",
+    "kind": "deployment",
+    "secured": false,
+    "client_id": "",
+    "server_id": "",
+    "no_identity_msg": "source not meshed",
+    "last_seen": 880
+  },
+  {
+    "src": "web",
+    "src_namespace": "emojivoto",
+    "dst": "emoji",
+    "dst_namespace": "emojivoto",
+    "kind": "deployment",
+    "secured": true,
+    "client_id": "web.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local",
+    "server_id": "emoji.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local",
+    "no_identity_msg": "",
+    "last_seen": 990
+  }
+]
+`
+		if output != expected {
+			t.Fatalf("Expected output:\n%s\nGot:\n%s", expected, output)
+		}
+	})
+
+	t.Run("Renders no edges as an empty JSON array", func(t *testing.T) {
+		output, err := renderEdges([]*pb.Edge{}, "json", now)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if output != "[]\n" {
+			t.Fatalf("Expected an empty array, got [%s]", output)
+		}
+	})
+}
+
+func TestWatchEdgesFromAPI(t *testing.T) {
+	mockClient := &public.MockAPIClient{
+		APIWatchEdgesClientToReturn: &public.MockAPIWatchEdgesClient{
+			EdgeEventsToReturn: []pb.EdgeEvent{
+				{Type: pb.EdgeEvent_ADD, Edge: edge("web", "emoji", false)},
+				{Type: pb.EdgeEvent_UPDATE, Edge: edge("web", "emoji", true)},
+				{Type: pb.EdgeEvent_REMOVE, Edge: edge("web", "emoji", true)},
+				{Type: pb.EdgeEvent_ADD, Edge: unmeshedEdge("web")},
+			},
+		},
+	}
+
+	writer := bytes.NewBufferString("")
+	err := watchEdgesFromAPI(writer, mockClient, &pb.EdgesRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := `ADD    deployment/web.emojivoto -> deployment/emoji.emojivoto secured=no
+UPDATE deployment/web.emojivoto -> deployment/emoji.emojivoto secured=yes
+REMOVE deployment/web.emojivoto -> deployment/emoji.emojivoto secured=yes
+ADD    (unmeshed) -> deployment/web.emojivoto secured=no
+`
+	if writer.String() != expected {
+		t.Fatalf("Expected output:\n%s\nGot:\n%s", expected, writer.String())
+	}
+}
@@ -0,0 +1,384 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	destinationPb "github.com/linkerd/linkerd2-proxy-api/go/destination"
+	"github.com/linkerd/linkerd2/controller/api/proxy"
+	addrUtil "github.com/linkerd/linkerd2/pkg/addr"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type endpointsOptions struct {
+	outputFormat string
+	watch        bool
+	zoneLabel    string
+}
+
+// endpointsInfo is an endpoint of an authority, as resolved by the
+// destination API.
+type endpointsInfo struct {
+	Namespace string `json:"namespace"`
+	IP        string `json:"ip"`
+	Port      uint32 `json:"port"`
+	Pod       string `json:"pod"`
+	Service   string `json:"service"`
+	Identity  string `json:"identity,omitempty"`
+	Zone      string `json:"zone,omitempty"`
+	Weight    uint32 `json:"weight"`
+}
+
+// endpointsEvent is an update of the endpoints of an authority, output by
+// --watch. Removed endpoints only have their IP and port.
+type endpointsEvent struct {
+	Authority string           `json:"authority"`
+	Type      string           `json:"type"`
+	Endpoints []*endpointsInfo `json:"endpoints,omitempty"`
+	Exists    *bool            `json:"exists,omitempty"`
+}
+
+// Types of the endpointsEvents.
+const (
+	endpointsAdded       = "add"
+	endpointsRemoved     = "remove"
+	endpointsNoEndpoints = "no_endpoints"
+)
+
+// zoneFunc returns the zone of the node of a pod, or an empty string if it's
+// unknown.
+type zoneFunc func(namespace, pod string) string
+
+func newEndpointsOptions() *endpointsOptions {
+	return &endpointsOptions{
+		outputFormat: "",
+		watch:        false,
+		zoneLabel:    proxy.DefaultZoneLabel,
+	}
+}
+
+func newCmdEndpoints() *cobra.Command {
+	options := newEndpointsOptions()
+
+	cmd := &cobra.Command{
+		Use:   "endpoints [flags] AUTHORITY...",
+		Short: "Introspect Linkerd's service discovery state",
+		Long: `Introspect Linkerd's service discovery state.
+
+This command resolves the authorities the same way the proxies do, through the
+destination API of the control plane, and shows their endpoints with the TLS
+identity, weight and zone that the proxies are sent. The zone is read from the
+label of the node of each pod.
+
+With --watch, the updates of the endpoints are shown as the destination API
+streams them, until the command is interrupted.`,
+		Example: `  # get all endpoints for the authorities emoji-svc.emojivoto.svc.cluster.local:8080 and web-svc.emojivoto.svc.cluster.local:80
+  linkerd endpoints emoji-svc.emojivoto.svc.cluster.local:8080 web-svc.emojivoto.svc.cluster.local:80
+
+  # get that same information in json format
+  linkerd endpoints -o json emoji-svc.emojivoto.svc.cluster.local:8080 web-svc.emojivoto.svc.cluster.local:80
+
+  # stream the updates of the endpoints of emoji-svc
+  linkerd endpoints --watch emoji-svc.emojivoto.svc.cluster.local:8080`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.validate(); err != nil {
+				return err
+			}
+
+			kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+			if err != nil {
+				return err
+			}
+			clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+			if err != nil {
+				return err
+			}
+
+			conn, closeConn, err := dialProxyAPI()
+			if err != nil {
+				return err
+			}
+			defer closeConn()
+
+			client := destinationPb.NewDestinationClient(conn)
+			zones := nodeZones(clientset, options.zoneLabel)
+
+			if options.watch {
+				return watchEndpoints(context.Background(), client, args, zones, os.Stdout, options)
+			}
+
+			endpoints, err := requestEndpoints(context.Background(), client, args, zones)
+			if err != nil {
+				return err
+			}
+			return renderEndpoints(os.Stdout, endpoints, options)
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; one of: \"table\" or \"json\"")
+	cmd.PersistentFlags().BoolVarP(&options.watch, "watch", "w", options.watch, "Stream the updates of the endpoints")
+	cmd.PersistentFlags().StringVar(&options.zoneLabel, "zone-label", options.zoneLabel, "Label of the nodes that holds their zone")
+
+	return cmd
+}
+
+func (o *endpointsOptions) validate() error {
+	switch o.outputFormat {
+	case "table", "json", "":
+		return nil
+	default:
+		return fmt.Errorf("--output currently only supports table and json")
+	}
+}
+
+// dialProxyAPI port-forwards to the proxy API of the controller, and returns a
+// connection to it and a function that closes them.
+func dialProxyAPI() (*grpc.ClientConn, func(), error) {
+	pf, err := k8s.NewPortForward(kubeconfigPath, kubeContext, impersonate, impersonateGroup, controlPlaneNamespace, proxyAPIDeployment, 0, proxyAPIPort, verbose)
+	if err != nil {
+		return nil, nil, err
+	}
+	pf.OnEvent(func(event k8s.PortForwardEvent) {
+		printPortForwardEvent(os.Stderr, event)
+	})
+	go func() {
+		if err := pf.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running port-forward: %s\n", err)
+			os.Exit(1)
+		}
+	}()
+	<-pf.Ready()
+
+	addr, err := url.Parse(pf.URLFor(""))
+	if err != nil {
+		pf.Stop()
+		return nil, nil, err
+	}
+	conn, err := grpc.Dial(addr.Host, grpc.WithInsecure())
+	if err != nil {
+		pf.Stop()
+		return nil, nil, err
+	}
+	return conn, func() {
+		conn.Close()
+		pf.Stop()
+	}, nil
+}
+
+// requestEndpoints returns the endpoints of each authority, from the first
+// update that the destination API sends for it.
+func requestEndpoints(ctx context.Context, client destinationPb.DestinationClient, authorities []string, zones zoneFunc) (map[string][]*endpointsInfo, error) {
+	endpoints := make(map[string][]*endpointsInfo)
+	for _, authority := range authorities {
+		ctx, cancel := context.WithCancel(ctx)
+		rsp, err := client.Get(ctx, &destinationPb.GetDestination{Scheme: "k8s", Path: authority})
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("Destination API error: %s", err)
+		}
+
+		update, err := rsp.Recv()
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("Destination API error for %s: %s", authority, err)
+		}
+
+		endpoints[authority] = []*endpointsInfo{}
+		if add, ok := update.GetUpdate().(*destinationPb.Update_Add); ok {
+			endpoints[authority] = toEndpointsInfo(add.Add, zones)
+		}
+	}
+	return endpoints, nil
+}
+
+// watchEndpoints streams the updates of the endpoints of the authorities to w
+// until ctx is done or the destination API closes a stream.
+func watchEndpoints(ctx context.Context, client destinationPb.DestinationClient, authorities []string, zones zoneFunc, w io.Writer, options *endpointsOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	errs := make(chan error, len(authorities))
+	for _, authority := range authorities {
+		go func(authority string) {
+			rsp, err := client.Get(ctx, &destinationPb.GetDestination{Scheme: "k8s", Path: authority})
+			if err != nil {
+				errs <- fmt.Errorf("Destination API error: %s", err)
+				return
+			}
+			for {
+				update, err := rsp.Recv()
+				if err != nil {
+					errs <- fmt.Errorf("Destination API error for %s: %s", authority, err)
+					return
+				}
+				event := toEndpointsEvent(authority, update, zones)
+				if event == nil {
+					continue
+				}
+				mu.Lock()
+				renderEndpointsEvent(w, event, options)
+				mu.Unlock()
+			}
+		}(authority)
+	}
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+func toEndpointsInfo(set *destinationPb.WeightedAddrSet, zones zoneFunc) []*endpointsInfo {
+	endpoints := []*endpointsInfo{}
+	for _, addr := range set.GetAddrs() {
+		labels := addr.GetMetricLabels()
+		endpoint := &endpointsInfo{
+			Namespace: set.GetMetricLabels()["namespace"],
+			IP:        addrUtil.ProxyIPToString(addr.GetAddr().GetIp()),
+			Port:      addr.GetAddr().GetPort(),
+			Pod:       labels["pod"],
+			Service:   set.GetMetricLabels()["service"],
+			Identity:  addr.GetTlsIdentity().GetK8SPodIdentity().GetPodIdentity(),
+			Weight:    addr.GetWeight(),
+		}
+		if endpoint.Pod != "" {
+			endpoint.Zone = zones(endpoint.Namespace, endpoint.Pod)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Pod+endpoints[i].IP < endpoints[j].Pod+endpoints[j].IP
+	})
+	return endpoints
+}
+
+// toEndpointsEvent converts an update of the destination API, or returns nil
+// if it isn't an update of the endpoints.
+func toEndpointsEvent(authority string, update *destinationPb.Update, zones zoneFunc) *endpointsEvent {
+	switch u := update.GetUpdate().(type) {
+	case *destinationPb.Update_Add:
+		return &endpointsEvent{Authority: authority, Type: endpointsAdded, Endpoints: toEndpointsInfo(u.Add, zones)}
+	case *destinationPb.Update_Remove:
+		endpoints := []*endpointsInfo{}
+		for _, addr := range u.Remove.GetAddrs() {
+			endpoints = append(endpoints, &endpointsInfo{
+				IP:   addrUtil.ProxyIPToString(addr.GetIp()),
+				Port: addr.GetPort(),
+			})
+		}
+		return &endpointsEvent{Authority: authority, Type: endpointsRemoved, Endpoints: endpoints}
+	case *destinationPb.Update_NoEndpoints:
+		exists := u.NoEndpoints.GetExists()
+		return &endpointsEvent{Authority: authority, Type: endpointsNoEndpoints, Exists: &exists}
+	}
+	return nil
+}
+
+func renderEndpoints(w io.Writer, endpoints map[string][]*endpointsInfo, options *endpointsOptions) error {
+	if options.outputFormat == "json" {
+		b, err := json.MarshalIndent(endpoints, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+
+	authorities := []string{}
+	for authority := range endpoints {
+		authorities = append(authorities, authority)
+	}
+	sort.Strings(authorities)
+
+	var buffer bytes.Buffer
+	tw := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(tw, strings.Join([]string{"AUTHORITY", "NAMESPACE", "IP", "PORT", "POD", "SERVICE", "IDENTITY", "ZONE", "WEIGHT"}, "\t"))
+	for _, authority := range authorities {
+		if len(endpoints[authority]) == 0 {
+			fmt.Fprintf(os.Stderr, "No endpoints found for %s.\n", authority)
+		}
+		for _, e := range endpoints[authority] {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%d\n",
+				authority, e.Namespace, e.IP, e.Port, orDash(e.Pod), e.Service, orDash(e.Identity), orDash(e.Zone), e.Weight)
+		}
+	}
+	tw.Flush()
+
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+func renderEndpointsEvent(w io.Writer, event *endpointsEvent, options *endpointsOptions) {
+	if options.outputFormat == "json" {
+		b, err := json.Marshal(event)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to render the update of %s: %s\n", event.Authority, err)
+			return
+		}
+		fmt.Fprintf(w, "%s\n", b)
+		return
+	}
+
+	switch event.Type {
+	case endpointsNoEndpoints:
+		fmt.Fprintf(w, "%s: no endpoints (exists: %t)\n", event.Authority, *event.Exists)
+	case endpointsRemoved:
+		for _, e := range event.Endpoints {
+			fmt.Fprintf(w, "%s: - %s:%d\n", event.Authority, e.IP, e.Port)
+		}
+	default:
+		for _, e := range event.Endpoints {
+			fmt.Fprintf(w, "%s: + %s:%d pod=%s identity=%s zone=%s weight=%d\n",
+				event.Authority, e.IP, e.Port, orDash(e.Pod), orDash(e.Identity), orDash(e.Zone), e.Weight)
+		}
+	}
+}
+
+// nodeZones returns a zoneFunc that reads the zone from the label of the node
+// of the pod, caching the zones of the nodes.
+func nodeZones(clientset kubernetes.Interface, zoneLabel string) zoneFunc {
+	var mu sync.Mutex
+	cache := make(map[string]string)
+	return func(namespace, pod string) string {
+		p, err := clientset.CoreV1().Pods(namespace).Get(pod, metaV1.GetOptions{})
+		if err != nil || p.Spec.NodeName == "" {
+			return ""
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if zone, ok := cache[p.Spec.NodeName]; ok {
+			return zone
+		}
+		node, err := clientset.CoreV1().Nodes().Get(p.Spec.NodeName, metaV1.GetOptions{})
+		if err != nil {
+			return ""
+		}
+		cache[p.Spec.NodeName] = node.Labels[zoneLabel]
+		return cache[p.Spec.NodeName]
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
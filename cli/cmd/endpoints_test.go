@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	destinationPb "github.com/linkerd/linkerd2-proxy-api/go/destination"
+	"github.com/linkerd/linkerd2-proxy-api/go/net"
+	addrUtil "github.com/linkerd/linkerd2/pkg/addr"
+)
+
+func emojiAddr(pod string, ip *net.IPAddress) *destinationPb.WeightedAddr {
+	return &destinationPb.WeightedAddr{
+		Addr:         &net.TcpAddress{Ip: ip, Port: 8080},
+		Weight:       1,
+		MetricLabels: map[string]string{"pod": pod, "deployment": "emoji"},
+		TlsIdentity: &destinationPb.TlsIdentity{
+			Strategy: &destinationPb.TlsIdentity_K8SPodIdentity_{
+				K8SPodIdentity: &destinationPb.TlsIdentity_K8SPodIdentity{
+					PodIdentity:  "emoji.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local",
+					ControllerNs: "linkerd",
+				},
+			},
+		},
+	}
+}
+
+func TestRenderEndpoints(t *testing.T) {
+	set := &destinationPb.WeightedAddrSet{
+		Addrs: []*destinationPb.WeightedAddr{
+			emojiAddr("emoji-1", addrUtil.ProxyIPV4(10, 1, 0, 2)),
+			emojiAddr("emoji-0", addrUtil.ProxyIPV4(10, 1, 0, 1)),
+		},
+		MetricLabels: map[string]string{"namespace": "emojivoto", "service": "emoji-svc"},
+	}
+	zones := func(namespace, pod string) string {
+		if namespace == "emojivoto" && pod == "emoji-0" {
+			return "us-east-1a"
+		}
+		return ""
+	}
+	endpoints := map[string][]*endpointsInfo{
+		"emoji-svc.emojivoto.svc.cluster.local:8080": toEndpointsInfo(set, zones),
+		"web-svc.emojivoto.svc.cluster.local:80":     {},
+	}
+
+	options := newEndpointsOptions()
+	t.Run("Returns the endpoints of the authorities", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		if err := renderEndpoints(buf, endpoints, options); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		diffCompareFile(t, buf.String(), "endpoints_output.golden")
+	})
+
+	options.outputFormat = "json"
+	t.Run("Returns the endpoints of the authorities (json)", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		if err := renderEndpoints(buf, endpoints, options); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		diffCompareFile(t, buf.String(), "endpoints_output_json.golden")
+	})
+}
+
+func TestRenderEndpointsEvent(t *testing.T) {
+	authority := "emoji-svc.emojivoto.svc.cluster.local:8080"
+	noZones := func(string, string) string { return "" }
+
+	testCases := []struct {
+		update       *destinationPb.Update
+		outputFormat string
+		expected     string
+	}{
+		{
+			update: &destinationPb.Update{Update: &destinationPb.Update_Add{Add: &destinationPb.WeightedAddrSet{
+				Addrs:        []*destinationPb.WeightedAddr{emojiAddr("emoji-0", addrUtil.ProxyIPV4(10, 1, 0, 1))},
+				MetricLabels: map[string]string{"namespace": "emojivoto", "service": "emoji-svc"},
+			}}},
+			expected: "emoji-svc.emojivoto.svc.cluster.local:8080: + 10.1.0.1:8080 pod=emoji-0 identity=emoji.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local zone=- weight=1\n",
+		},
+		{
+			update: &destinationPb.Update{Update: &destinationPb.Update_Remove{Remove: &destinationPb.AddrSet{
+				Addrs: []*net.TcpAddress{{Ip: addrUtil.ProxyIPV4(10, 1, 0, 1), Port: 8080}},
+			}}},
+			expected: "emoji-svc.emojivoto.svc.cluster.local:8080: - 10.1.0.1:8080\n",
+		},
+		{
+			update: &destinationPb.Update{Update: &destinationPb.Update_Remove{Remove: &destinationPb.AddrSet{
+				Addrs: []*net.TcpAddress{{Ip: addrUtil.ProxyIPV4(10, 1, 0, 1), Port: 8080}},
+			}}},
+			outputFormat: "json",
+			expected:     `{"authority":"emoji-svc.emojivoto.svc.cluster.local:8080","type":"remove","endpoints":[{"namespace":"","ip":"10.1.0.1","port":8080,"pod":"","service":"","weight":0}]}` + "\n",
+		},
+		{
+			update:       &destinationPb.Update{Update: &destinationPb.Update_NoEndpoints{NoEndpoints: &destinationPb.NoEndpoints{Exists: true}}},
+			outputFormat: "json",
+			expected:     `{"authority":"emoji-svc.emojivoto.svc.cluster.local:8080","type":"no_endpoints","exists":true}` + "\n",
+		},
+	}
+
+	for i, tc := range testCases {
+		buf := &bytes.Buffer{}
+		options := newEndpointsOptions()
+		options.outputFormat = tc.outputFormat
+		renderEndpointsEvent(buf, toEndpointsEvent(authority, tc.update, noZones), options)
+		if buf.String() != tc.expected {
+			t.Fatalf("test case %d: expected %q, got %q", i, tc.expected, buf.String())
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/linkerd/linkerd2/controller/api/public"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/spf13/cobra"
@@ -84,13 +85,13 @@ func getPods(apiClient pb.ApiClient, options *getOptions) ([]string, error) {
 		req.Namespace = options.namespace
 	}
 
-	resp, err := apiClient.ListPods(context.Background(), req)
+	pods, err := public.ListAllPods(context.Background(), apiClient, req)
 	if err != nil {
 		return nil, err
 	}
 
 	names := make([]string, 0)
-	for _, pod := range resp.GetPods() {
+	for _, pod := range pods {
 		names = append(names, pod.Name)
 	}
 
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/spf13/cobra"
+)
+
+func newCmdIdentity() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "identity [flags]",
+		Short: "Inspect the identity of the mesh",
+		Long:  "Inspect the identity of the mesh.",
+	}
+
+	cmd.AddCommand(newCmdIdentityTrustBundle())
+
+	return cmd
+}
+
+func newCmdIdentityTrustBundle() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trust-bundle [flags]",
+		Short: "Output the trust anchors of the mesh as a SPIFFE trust bundle",
+		Long: `Output the trust anchors of the mesh as a SPIFFE trust bundle.
+
+The bundle lets SPIRE deployments and other clusters trust the certificates
+issued by the mesh, to federate with it. Requires the control plane to be
+installed with --tls=optional.
+
+The public API also serves the bundle as plain JSON to HTTP GET requests on
+/api/v1/TrustBundle, for SPIFFE implementations that fetch it periodically.`,
+		Example: `  # Save the trust bundle, e.g. to load it into a SPIRE server as the bundle
+  # of the trust domain that the mesh is federated as.
+  linkerd identity trust-bundle > linkerd-bundle.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return writeTrustBundle(os.Stdout, cliPublicAPIClient())
+		},
+	}
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func writeTrustBundle(w io.Writer, client pb.ApiClient) error {
+	rsp, err := client.TrustBundle(context.Background(), &pb.Empty{})
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, rsp.GetBundle())
+	return err
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+)
+
+func TestWriteTrustBundle(t *testing.T) {
+	t.Run("Writes the trust bundle", func(t *testing.T) {
+		client := &public.MockAPIClient{
+			TrustBundleResponseToReturn: &pb.TrustBundleResponse{Bundle: `{"keys": []}`},
+		}
+
+		buf := &bytes.Buffer{}
+		if err := writeTrustBundle(buf, client); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if buf.String() != "{\"keys\": []}\n" {
+			t.Fatalf("Unexpected output: %s", buf.String())
+		}
+	})
+
+	t.Run("Returns the API's errors", func(t *testing.T) {
+		client := &public.MockAPIClient{
+			ErrorToReturn: errors.New("No trust anchors found"),
+		}
+
+		err := writeTrustBundle(&bytes.Buffer{}, client)
+		if err == nil || err.Error() != "No trust anchors found" {
+			t.Fatalf("Expected the API's error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// imageDigest matches the sha256 digests of images, which are the only ones
+// that registries compute.
+var imageDigest = regexp.MustCompile("^sha256:[a-f0-9]{64}$")
+
+// readImageDigests reads the --image-digests-file, a YAML map of the image
+// repositories, such as gcr.io/linkerd-io/proxy, or of the repositories and
+// their tags, such as gcr.io/linkerd-io/proxy:stable-2.2.1, to their digests.
+func readImageDigests(file string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	digests := map[string]string{}
+	if err := yaml.Unmarshal(content, &digests); err != nil {
+		return nil, fmt.Errorf("Invalid --image-digests-file %s: %s", file, err)
+	}
+	for image, digest := range digests {
+		if !imageDigest.MatchString(digest) {
+			return nil, fmt.Errorf("Invalid --image-digests-file %s: the digest '%s' of %s must be of the form sha256:<64 hex digits>", file, digest, image)
+		}
+	}
+	return digests, nil
+}
+
+// imageRef returns the reference of the image of the repository with the
+// tag, which is pinned to its digest if the --image-digests-file has one for
+// the repository and tag, or else for the repository. The pinned references
+// keep their tag, e.g. gcr.io/linkerd-io/proxy:stable-2.2.1@sha256:..., so
+// that the version of the proxies can still be read from their image; the
+// images are pulled by digest.
+func (options *proxyConfigOptions) imageRef(repository, tag string) string {
+	ref := repository + ":" + tag
+	if digest, ok := options.imageDigests[ref]; ok {
+		return ref + "@" + digest
+	}
+	if digest, ok := options.imageDigests[repository]; ok {
+		return ref + "@" + digest
+	}
+	return ref
+}
+
+// thirdPartyImageRef returns the reference of a third-party image of the
+// control plane, such as prom/prometheus:v2.4.0. It's pulled from its public
+// repository with the default --registry, and otherwise from the repository
+// of the same name in the registry, e.g. my.registry/prometheus:v2.4.0.
+func (options *proxyConfigOptions) thirdPartyImageRef(image string) string {
+	repository, tag := image, "latest"
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		repository, tag = image[:i], image[i+1:]
+	}
+	if options.dockerRegistry != defaultDockerRegistry {
+		repository = options.dockerRegistry + "/" + path.Base(repository)
+	}
+	return options.imageRef(repository, tag)
+}
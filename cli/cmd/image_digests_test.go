@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+const testImageDigest = "sha256:ec5e5a8b0d9ac2a9e1a3cd3e2dbf1f8f9a8b0d9ac2a9e1a3cd3e2dbf1f8f9a8b"
+
+func writeImageDigestsFile(t *testing.T, content string) string {
+	f, err := ioutil.TempFile("", "image-digests")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return f.Name()
+}
+
+func TestImageDigests(t *testing.T) {
+	t.Run("Pins the images of the digests file", func(t *testing.T) {
+		file := writeImageDigestsFile(t, `my.registry/linkerd/proxy:stable-2.2.1: `+testImageDigest+`
+my.registry/linkerd/prometheus: `+testImageDigest+`
+`)
+		defer os.Remove(file)
+
+		options := newProxyConfigOptions()
+		options.linkerdVersion = "stable-2.2.1"
+		options.dockerRegistry = "my.registry/linkerd"
+		options.imageDigestsFile = file
+		if err := options.validate(); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		for _, tc := range []struct {
+			image    string
+			expected string
+		}{
+			{options.taggedProxyImage(), "my.registry/linkerd/proxy:stable-2.2.1@" + testImageDigest},
+			{options.taggedProxyInitImage(), "my.registry/linkerd/proxy-init:stable-2.2.1"},
+			{options.thirdPartyImageRef("prom/prometheus:v2.4.0"), "my.registry/linkerd/prometheus:v2.4.0@" + testImageDigest},
+			{options.thirdPartyImageRef("jaegertracing/all-in-one:1.8"), "my.registry/linkerd/all-in-one:1.8"},
+		} {
+			if tc.image != tc.expected {
+				t.Fatalf("Expected image %s, got %s", tc.expected, tc.image)
+			}
+		}
+	})
+
+	t.Run("Pulls the third-party images from their repositories by default", func(t *testing.T) {
+		options := newProxyConfigOptions()
+		if image := options.thirdPartyImageRef("prom/prometheus:v2.4.0"); image != "prom/prometheus:v2.4.0" {
+			t.Fatalf("Expected image prom/prometheus:v2.4.0, got %s", image)
+		}
+	})
+
+	t.Run("Rejects invalid digests", func(t *testing.T) {
+		file := writeImageDigestsFile(t, "gcr.io/linkerd-io/proxy: stable-2.2.1\n")
+		defer os.Remove(file)
+
+		_, err := readImageDigests(file)
+		if err == nil || !strings.Contains(err.Error(), "the digest 'stable-2.2.1' of gcr.io/linkerd-io/proxy must be of the form sha256:<64 hex digits>") {
+			t.Fatalf("Expected an invalid digest error, got: %v", err)
+		}
+	})
+}
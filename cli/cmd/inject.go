@@ -12,6 +12,7 @@ import (
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"k8s.io/api/core/v1"
 	k8sMeta "k8s.io/apimachinery/pkg/api/meta"
 	k8sResource "k8s.io/apimachinery/pkg/api/resource"
@@ -32,12 +33,20 @@ const (
 
 	hostNetworkDesc = "pods do not use host networking"
 	sidecarDesc     = "pods do not have a 3rd party proxy or initContainer already injected"
+	windowsDesc     = "pods do not require Windows nodes"
 	unsupportedDesc = "at least one resource injected"
 	udpDesc         = "pod specs do not include UDP ports"
 )
 
 type injectOptions struct {
 	*proxyConfigOptions
+	dryRunReport       bool
+	diff               bool
+	enableDebugSidecar bool
+
+	// changedFlags are the names of the flags set on the command line, which
+	// the dry-run report attributes the proxy configuration to
+	changedFlags map[string]bool
 }
 
 type resourceTransformerInject struct{}
@@ -48,6 +57,9 @@ func InjectYAML(in io.Reader, out io.Writer, report io.Writer, options *injectOp
 }
 
 func runInjectCmd(inputs []io.Reader, errWriter, outWriter io.Writer, options *injectOptions) int {
+	if options.dryRunReport {
+		return runInjectDryRunCmd(inputs, errWriter, outWriter, options)
+	}
 	return transformInput(inputs, errWriter, outWriter, options, resourceTransformerInject{})
 }
 
@@ -59,6 +71,7 @@ type objMeta struct {
 func newInjectOptions() *injectOptions {
 	return &injectOptions{
 		proxyConfigOptions: newProxyConfigOptions(),
+		changedFlags:       map[string]bool{},
 	}
 }
 
@@ -79,7 +92,14 @@ sub-folders, or coming from stdin.`,
   curl http://url.to/yml | linkerd inject - | kubectl apply -f -
 
   # Inject all the resources inside a folder and its sub-folders.
-  linkerd inject <folder> | kubectl apply -f -`,
+  linkerd inject <folder> | kubectl apply -f -
+
+  # Explain why each resource would or wouldn't be injected, and with which
+  # proxy configuration, without outputting the injected resources.
+  linkerd inject --dry-run-report <folder>
+
+  # Review the changes that injection makes to the resources inside a folder.
+  linkerd inject --diff <folder>`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 
 			if len(args) < 1 {
@@ -89,6 +109,12 @@ sub-folders, or coming from stdin.`,
 			if err := options.validate(); err != nil {
 				return err
 			}
+			if options.diff && options.dryRunReport {
+				return fmt.Errorf("--diff and --dry-run-report can't be used together")
+			}
+			cmd.Flags().Visit(func(f *pflag.Flag) {
+				options.changedFlags[f.Name] = true
+			})
 
 			in, err := read(args[0])
 			if err != nil {
@@ -102,15 +128,39 @@ sub-folders, or coming from stdin.`,
 	}
 
 	addProxyConfigFlags(cmd, options.proxyConfigOptions)
+	cmd.PersistentFlags().BoolVar(&options.enableDebugSidecar, "enable-debug-sidecar", options.enableDebugSidecar, "Inject a debug container with tshark, iproute2 and curl alongside the proxy, to troubleshoot the pod's network")
+	cmd.PersistentFlags().BoolVar(&options.dryRunReport, "dry-run-report", options.dryRunReport, "Report why each resource would or wouldn't be injected, and the proxy configuration it would be injected with, instead of outputting the injected resources")
+	cmd.PersistentFlags().BoolVar(&options.diff, "diff", options.diff, "Output a unified diff between each resource and its injected version, instead of the injected resources")
 	return cmd
 }
 
+// uninjectAndInject streams the uninjected inputs into inject through a pipe,
+// so that neither the inputs nor the uninjected output have to fit in memory.
 func uninjectAndInject(inputs []io.Reader, errWriter, outWriter io.Writer, options *injectOptions) int {
-	var out bytes.Buffer
-	if exitCode := runUninjectSilentCmd(inputs, errWriter, &out, nil); exitCode != 0 {
-		return exitCode
+	if options.diff {
+		return runInjectDiffCmd(inputs, errWriter, outWriter, options)
 	}
-	return runInjectCmd([]io.Reader{&out}, errWriter, outWriter, options)
+
+	r, w := io.Pipe()
+
+	// uninject errors are only reported if inject succeeds, since an inject
+	// failure closes the pipe and would also fail uninject
+	var uninjectErr bytes.Buffer
+	uninjectExitCode := make(chan int, 1)
+	go func() {
+		exitCode := runUninjectSilentCmd(inputs, &uninjectErr, w, nil)
+		w.Close()
+		uninjectExitCode <- exitCode
+	}()
+
+	exitCode := runInjectCmd([]io.Reader{r}, errWriter, outWriter, options)
+	r.Close()
+
+	if code := <-uninjectExitCode; exitCode == 0 && code != 0 {
+		io.Copy(errWriter, &uninjectErr)
+		return code
+	}
+	return exitCode
 }
 
 /* Given a ObjectMeta, update ObjectMeta in place with the new labels and
@@ -139,6 +189,7 @@ func injectObjectMeta(t *metaV1.ObjectMeta, k8sLabels map[string]string, options
 func injectPodSpec(t *v1.PodSpec, identity k8s.TLSIdentity, controlPlaneDNSNameOverride string, options *injectOptions, report *injectReport) bool {
 	report.hostNetwork = t.HostNetwork
 	report.sidecar = healthcheck.HasExistingSidecars(t)
+	report.windows = k8s.RequiresWindowsNodes(t)
 	report.udp = checkUDPPorts(t)
 
 	// Skip injection if:
@@ -146,21 +197,22 @@ func injectPodSpec(t *v1.PodSpec, identity k8s.TLSIdentity, controlPlaneDNSNameO
 	//    The init-container would destroy the iptables configuration on the host.
 	// OR
 	// 2) Known 3rd party sidecars already present.
-	if report.hostNetwork || report.sidecar {
+	// OR
+	// 3) The pods can only run on Windows nodes, where the proxy can't run.
+	if report.hostNetwork || report.sidecar || report.windows {
 		return false
 	}
 
 	f := false
-	inboundSkipPorts := append(options.ignoreInboundPorts, options.proxyControlPort, options.proxyMetricsPort)
-	inboundSkipPortsStr := make([]string, len(inboundSkipPorts))
-	for i, p := range inboundSkipPorts {
-		inboundSkipPortsStr[i] = strconv.Itoa(int(p))
-	}
+	// copy the ignored ports, as pod specs may be injected concurrently with
+	// the same options
+	inboundSkipPortsStr := append([]string{}, options.ignoreInboundPorts...)
+	inboundSkipPortsStr = append(inboundSkipPortsStr,
+		strconv.Itoa(int(options.proxyControlPort)),
+		strconv.Itoa(int(options.proxyMetricsPort)),
+	)
 
-	outboundSkipPortsStr := make([]string, len(options.ignoreOutboundPorts))
-	for i, p := range options.ignoreOutboundPorts {
-		outboundSkipPortsStr[i] = strconv.Itoa(int(p))
-	}
+	outboundSkipPortsStr := options.ignoreOutboundPorts
 
 	initArgs := []string{
 		"--incoming-proxy-port", fmt.Sprintf("%d", options.inboundPort),
@@ -335,11 +387,31 @@ func injectPodSpec(t *v1.PodSpec, identity k8s.TLSIdentity, controlPlaneDNSNameO
 	}
 
 	t.Containers = append(t.Containers, sidecar)
+	if options.enableDebugSidecar {
+		t.Containers = append(t.Containers, debugContainer(options))
+	}
 	t.InitContainers = append(t.InitContainers, initContainer)
 
 	return true
 }
 
+// debugContainer returns the debug container, which shares the pod's network
+// namespace with the proxy. It needs the NET_ADMIN and NET_RAW capabilities to
+// capture traffic.
+func debugContainer(options *injectOptions) v1.Container {
+	return v1.Container{
+		Name:                     k8s.DebugContainerName,
+		Image:                    options.taggedDebugImage(),
+		ImagePullPolicy:          v1.PullPolicy(options.imagePullPolicy),
+		TerminationMessagePolicy: v1.TerminationMessageFallbackToLogsOnError,
+		SecurityContext: &v1.SecurityContext{
+			Capabilities: &v1.Capabilities{
+				Add: []v1.Capability{v1.Capability("NET_ADMIN"), v1.Capability("NET_RAW")},
+			},
+		},
+	}
+}
+
 func (rt resourceTransformerInject) transform(bytes []byte, options *injectOptions) ([]byte, []injectReport, error) {
 	conf := &resourceConfig{}
 	output, reports, err := conf.parse(bytes, options, rt)
@@ -372,6 +444,15 @@ func (rt resourceTransformerInject) transform(bytes []byte, options *injectOptio
 			ControllerNamespace: controlPlaneNamespace,
 		}
 
+		if err := checkProxyConflicts(conf, options); err != nil {
+			// the dry-run report explains the conflicts instead of failing
+			if !options.dryRunReport {
+				return nil, nil, err
+			}
+			report.conflict = err.Error()
+			return bytes, []injectReport{report}, nil
+		}
+
 		if injectPodSpec(conf.podSpec, identity, conf.dnsNameOverride, options, &report) {
 			injectObjectMeta(conf.objectMeta, conf.k8sLabels, options)
 			var err error
@@ -391,11 +472,12 @@ func (resourceTransformerInject) generateReport(injectReports []injectReport, ou
 	injected := []injectReport{}
 	hostNetwork := []string{}
 	sidecar := []string{}
+	windows := []string{}
 	udp := []string{}
 	warningsPrinted := verbose
 
 	for _, r := range injectReports {
-		if !r.hostNetwork && !r.sidecar && !r.unsupportedResource {
+		if !r.hostNetwork && !r.sidecar && !r.windows && !r.unsupportedResource {
 			injected = append(injected, r)
 		}
 
@@ -409,6 +491,11 @@ func (resourceTransformerInject) generateReport(injectReports []injectReport, ou
 			warningsPrinted = true
 		}
 
+		if r.windows {
+			windows = append(windows, r.resName())
+			warningsPrinted = true
+		}
+
 		if r.udp {
 			udp = append(udp, r.resName())
 			warningsPrinted = true
@@ -434,6 +521,12 @@ func (resourceTransformerInject) generateReport(injectReports []injectReport, ou
 		output.Write([]byte(fmt.Sprintf("%s %s\n", okStatus, sidecarDesc)))
 	}
 
+	if len(windows) > 0 {
+		output.Write([]byte(fmt.Sprintf("%s Windows node selector detected in %s\n", warnStatus, strings.Join(windows, ", "))))
+	} else if verbose {
+		output.Write([]byte(fmt.Sprintf("%s %s\n", okStatus, windowsDesc)))
+	}
+
 	if len(injected) == 0 {
 		output.Write([]byte(fmt.Sprintf("%s no supported objects found\n", warnStatus)))
 		warningsPrinted = true
@@ -459,7 +552,7 @@ func (resourceTransformerInject) generateReport(injectReports []injectReport, ou
 	}
 
 	for _, r := range injectReports {
-		if !r.hostNetwork && !r.sidecar && !r.unsupportedResource {
+		if !r.hostNetwork && !r.sidecar && !r.windows && !r.unsupportedResource {
 			output.Write([]byte(fmt.Sprintf("%s \"%s\" injected\n", r.kind, r.name)))
 		} else {
 			output.Write([]byte(fmt.Sprintf("%s \"%s\" skipped\n", r.kind, r.name)))
@@ -470,6 +563,33 @@ func (resourceTransformerInject) generateReport(injectReports []injectReport, ou
 	output.Write([]byte("\n"))
 }
 
+// checkProxyConflicts returns an error if the containers of the resource's
+// pod spec use the proxy's ports or its user ID, unless the pod template has
+// the ProxyIgnoreConflictsAnnotation. Pods that are skipped by injectPodSpec
+// aren't checked.
+func checkProxyConflicts(conf *resourceConfig, options *injectOptions) error {
+	if conf.podSpec.HostNetwork || healthcheck.HasExistingSidecars(conf.podSpec) || k8s.RequiresWindowsNodes(conf.podSpec) {
+		return nil
+	}
+	if conf.objectMeta != nil && conf.objectMeta.Annotations[k8s.ProxyIgnoreConflictsAnnotation] == "true" {
+		return nil
+	}
+
+	proxyPorts := []int32{
+		int32(options.inboundPort),
+		int32(options.outboundPort),
+		int32(options.proxyControlPort),
+		int32(options.proxyMetricsPort),
+	}
+	conflicts := healthcheck.ProxyConflicts(conf.podSpec, proxyPorts, options.proxyUID)
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s \"%s\" conflicts with the proxy: %s (configure the proxy with different ports or user ID, or set the \"%s: true\" annotation on the pod template to inject it anyway)",
+		strings.ToLower(conf.meta.Kind), conf.om.Name, strings.Join(conflicts, "; "), k8s.ProxyIgnoreConflictsAnnotation)
+}
+
 func checkUDPPorts(t *v1.PodSpec) bool {
 	// check for ports with `protocol: UDP`, which will not be routed by Linkerd
 	for _, container := range t.Containers {
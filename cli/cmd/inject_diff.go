@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// diffContextLines is the number of unchanged lines around each change in the
+// unified diff, as in `diff -u`.
+const diffContextLines = 3
+
+// resourceTransformerNormalize serializes resources the same way as inject,
+// without changing them, so that the injected resources can be diffed against
+// them line by line.
+type resourceTransformerNormalize struct{}
+
+func (rt resourceTransformerNormalize) transform(bytes []byte, options *injectOptions) ([]byte, []injectReport, error) {
+	conf := &resourceConfig{}
+	output, reports, err := conf.parse(bytes, options, rt)
+	if output != nil || err != nil {
+		return output, reports, err
+	}
+
+	if conf.podSpec == nil {
+		return bytes, nil, nil
+	}
+	output, err = yaml.Marshal(conf.obj)
+	if err != nil {
+		return nil, nil, err
+	}
+	return output, nil, nil
+}
+
+func (resourceTransformerNormalize) generateReport([]injectReport, io.Writer) {}
+
+// runInjectDiffCmd injects each input like uninjectAndInject, but writes a
+// unified diff between each input resource and its injected version to
+// outWriter instead of the injected YAML. Unlike the rest of inject, each
+// input is read in memory, since it's needed for both sides of the diff.
+func runInjectDiffCmd(inputs []io.Reader, errWriter, outWriter io.Writer, options *injectOptions) int {
+	injectOptions := *options
+	injectOptions.diff = false
+
+	for _, input := range inputs {
+		data, err := ioutil.ReadAll(input)
+		if err != nil {
+			fmt.Fprintf(errWriter, "Error reading resources: %v\n", err)
+			return 1
+		}
+
+		original := &bytes.Buffer{}
+		if err := ProcessYAML(bytes.NewReader(data), original, ioutil.Discard, options, resourceTransformerNormalize{}); err != nil {
+			fmt.Fprintf(errWriter, "Error transforming resources: %v\n", err)
+			return 1
+		}
+
+		injected := &bytes.Buffer{}
+		if exitCode := uninjectAndInject([]io.Reader{bytes.NewReader(data)}, errWriter, injected, &injectOptions); exitCode != 0 {
+			return exitCode
+		}
+
+		originalDocs := splitYAMLDocuments(original.String())
+		injectedDocs := splitYAMLDocuments(injected.String())
+		if len(originalDocs) != len(injectedDocs) {
+			fmt.Fprintf(errWriter, "Error diffing resources: %d resources were read, but %d were injected\n", len(originalDocs), len(injectedDocs))
+			return 1
+		}
+
+		for i := range originalDocs {
+			name := resourceName(originalDocs[i])
+			fmt.Fprint(outWriter, unifiedDiff("a/"+name, "b/"+name, originalDocs[i], injectedDocs[i]))
+		}
+	}
+	return 0
+}
+
+// splitYAMLDocuments splits the output of ProcessYAML into its documents,
+// each of which ends with a newline.
+func splitYAMLDocuments(output string) []string {
+	docs := []string{}
+	for _, doc := range strings.Split(output, "---\n") {
+		if strings.TrimSpace(doc) != "" {
+			docs = append(docs, doc)
+		}
+	}
+	return docs
+}
+
+// resourceName returns the "<kind>/<name>" of the resource in doc, which
+// names it in the diff headers.
+func resourceName(doc string) string {
+	var resource struct {
+		metaV1.TypeMeta
+		objMeta
+	}
+	if err := yaml.Unmarshal([]byte(doc), &resource); err != nil || resource.Kind == "" {
+		return "resource"
+	}
+	if resource.Name == "" {
+		return strings.ToLower(resource.Kind)
+	}
+	return fmt.Sprintf("%s/%s", strings.ToLower(resource.Kind), resource.Name)
+}
+
+// diffLine is a line of a unified diff: op is ' ' for an unchanged line, '-'
+// for a removed one and '+' for an added one.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// unifiedDiff returns the unified diff between a and b, or an empty string if
+// they're equal.
+func unifiedDiff(aName, bName, a, b string) string {
+	if a == b {
+		return ""
+	}
+
+	dmp := diffmatchpatch.New()
+	aChars, bChars, lines := dmp.DiffLinesToChars(a, b)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(aChars, bChars, false), lines)
+
+	diffLines := []diffLine{}
+	for _, diff := range diffs {
+		op := byte(' ')
+		switch diff.Type {
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		}
+		for _, text := range strings.SplitAfter(diff.Text, "\n") {
+			if text != "" {
+				diffLines = append(diffLines, diffLine{op, text})
+			}
+		}
+	}
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", aName, bName)
+
+	// aLine and bLine are the numbers of the lines of a and b before each diff
+	// line
+	aLine, bLine := make([]int, len(diffLines)+1), make([]int, len(diffLines)+1)
+	for i, line := range diffLines {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if line.op != '+' {
+			aLine[i+1]++
+		}
+		if line.op != '-' {
+			bLine[i+1]++
+		}
+	}
+
+	for start := 0; start < len(diffLines); {
+		// find the next change, and the last change of its hunk, which ends
+		// when more than twice the context lines in a row are unchanged
+		first := start
+		for first < len(diffLines) && diffLines[first].op == ' ' {
+			first++
+		}
+		if first == len(diffLines) {
+			break
+		}
+		last := first
+		for i := first; i < len(diffLines) && i <= last+2*diffContextLines+1; i++ {
+			if diffLines[i].op != ' ' {
+				last = i
+			}
+		}
+
+		from := first - diffContextLines
+		if from < start {
+			from = start
+		}
+		to := last + diffContextLines + 1
+		if to > len(diffLines) {
+			to = len(diffLines)
+		}
+
+		fmt.Fprintf(out, "@@ -%s +%s @@\n",
+			hunkRange(aLine[from], aLine[to]-aLine[from]),
+			hunkRange(bLine[from], bLine[to]-bLine[from]))
+		for _, line := range diffLines[from:to] {
+			out.WriteByte(line.op)
+			out.WriteString(line.text)
+			if !strings.HasSuffix(line.text, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		start = to
+	}
+
+	return out.String()
+}
+
+// hunkRange formats the range of a hunk header, for a hunk of count lines
+// after line before.
+func hunkRange(before, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	testCases := []struct {
+		title    string
+		a        string
+		b        string
+		expected string
+	}{
+		{
+			title: "equal documents",
+			a:     "a\nb\n",
+			b:     "a\nb\n",
+		},
+		{
+			title: "nearby changes share a hunk",
+			a:     "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:     "1\nx\n3\n4\n5\n6\n7\n8\ny\n",
+			expected: "--- a/doc\n+++ b/doc\n" +
+				"@@ -1,9 +1,9 @@\n 1\n-2\n+x\n 3\n 4\n 5\n 6\n 7\n 8\n-9\n+y\n",
+		},
+		{
+			title: "distant changes get their own hunks",
+			a:     "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			b:     "x\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n",
+			expected: "--- a/doc\n+++ b/doc\n" +
+				"@@ -1,3 +1,4 @@\n+x\n 1\n 2\n 3\n" +
+				"@@ -9,4 +10,3 @@\n 9\n 10\n 11\n-12\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			actual := unifiedDiff("a/doc", "b/doc", tc.a, tc.b)
+			if actual != tc.expected {
+				t.Fatalf("Unexpected diff\nExpected:\n%s\nActual:\n%s", tc.expected, actual)
+			}
+		})
+	}
+}
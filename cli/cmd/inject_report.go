@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// resourceTransformerInjectDryRun injects resources like
+// resourceTransformerInject, but its report explains why each resource would
+// or wouldn't be injected, and with which proxy configuration.
+type resourceTransformerInjectDryRun struct {
+	resourceTransformerInject
+	config []proxyConfigValue
+}
+
+// proxyConfigValue is a value of the proxy configuration, along with the flags
+// or default that supplied it.
+type proxyConfigValue struct {
+	name   string
+	value  string
+	source string
+}
+
+// runInjectDryRunCmd discards the injected YAML, and writes the dry-run report
+// to outWriter instead.
+func runInjectDryRunCmd(inputs []io.Reader, errWriter, outWriter io.Writer, options *injectOptions) int {
+	rt := resourceTransformerInjectDryRun{config: options.proxyConfigValues()}
+	for _, input := range inputs {
+		if err := ProcessYAML(input, ioutil.Discard, outWriter, options, rt); err != nil {
+			fmt.Fprintf(errWriter, "Error transforming resources: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// skipReason returns why the resource wouldn't be injected, or an empty
+// string if it would be.
+func (i injectReport) skipReason() string {
+	switch {
+	case i.unsupportedResource:
+		return fmt.Sprintf("%s resources aren't supported", i.kind)
+	case i.hostNetwork:
+		return "pods use host networking (\"hostNetwork: true\")"
+	case i.sidecar:
+		return "pods already have a 3rd party proxy or initContainer"
+	case i.windows:
+		return "pods can only run on Windows nodes, where the proxy can't run"
+	}
+	return ""
+}
+
+func (rt resourceTransformerInjectDryRun) generateReport(injectReports []injectReport, output io.Writer) {
+	injected := false
+	for _, r := range injectReports {
+		// the conflict already names the resource
+		if r.conflict != "" {
+			fmt.Fprintf(output, "%s, so it would fail to be injected\n", r.conflict)
+			continue
+		}
+
+		reason := r.skipReason()
+		if reason != "" {
+			fmt.Fprintf(output, "%s \"%s\" would be skipped: %s\n", r.kind, r.name, reason)
+			continue
+		}
+
+		injected = true
+		if r.udp {
+			fmt.Fprintf(output, "%s \"%s\" would be injected, but its \"protocol: UDP\" ports won't be proxied\n", r.kind, r.name)
+		} else {
+			fmt.Fprintf(output, "%s \"%s\" would be injected\n", r.kind, r.name)
+		}
+	}
+
+	if !injected {
+		return
+	}
+
+	fmt.Fprintln(output, "\nProxy configuration:")
+	w := tabwriter.NewWriter(output, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(w, "  NAME\tVALUE\tSOURCE")
+	for _, c := range rt.config {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", c.name, c.value, c.source)
+	}
+	w.Flush()
+}
+
+// proxyConfigValues lists the configuration of the injected proxy, attributing
+// each value to the flags that changed it, or to the defaults.
+func (options *injectOptions) proxyConfigValues() []proxyConfigValue {
+	source := func(flags ...string) string {
+		changed := []string{}
+		for _, flag := range flags {
+			if options.changedFlags[flag] {
+				changed = append(changed, "--"+flag)
+			}
+		}
+		if len(changed) == 0 {
+			return "default"
+		}
+		return strings.Join(changed, ", ")
+	}
+	valueOrNone := func(value string) string {
+		if value == "" {
+			return "none"
+		}
+		return value
+	}
+
+	tls := "disabled"
+	if options.enableTLS() {
+		tls = options.tls
+	}
+	externalProfiles := "enabled"
+	if options.disableExternalProfiles {
+		externalProfiles = "disabled"
+	}
+	debugSidecar := "disabled"
+	if options.enableDebugSidecar {
+		debugSidecar = "enabled"
+	}
+
+	// the control and metrics ports are always skipped, as in injectPodSpec
+	inboundSkipPorts := append([]string{}, options.ignoreInboundPorts...)
+	inboundSkipPorts = append(inboundSkipPorts,
+		strconv.Itoa(int(options.proxyControlPort)),
+		strconv.Itoa(int(options.proxyMetricsPort)),
+	)
+
+	values := []proxyConfigValue{
+		{"proxy image", options.taggedProxyImage(), source("proxy-image", "registry", "linkerd-version", "image-digests-file")},
+		{"proxy-init image", options.taggedProxyInitImage(), source("init-image", "registry", "linkerd-version", "image-digests-file")},
+		{"image pull policy", options.imagePullPolicy, source("image-pull-policy")},
+		{"proxy UID", strconv.FormatInt(options.proxyUID, 10), source("proxy-uid")},
+		{"proxy log level", options.proxyLogLevel, source("proxy-log-level")},
+		{"proxy bind timeout", options.proxyBindTimeout, source("proxy-bind-timeout")},
+		{"inbound port", strconv.Itoa(int(options.inboundPort)), source("inbound-port")},
+		{"outbound port", strconv.Itoa(int(options.outboundPort)), source("outbound-port")},
+		{"control port", strconv.Itoa(int(options.proxyControlPort)), source("control-port")},
+		{"metrics port", strconv.Itoa(int(options.proxyMetricsPort)), source("metrics-port")},
+		{"controller API port", strconv.Itoa(int(options.proxyAPIPort)), source("api-port")},
+		{"TLS", tls, source("tls")},
+		{"proxy CPU request", valueOrNone(options.proxyCPURequest), source("proxy-cpu")},
+		{"proxy memory request", valueOrNone(options.proxyMemoryRequest), source("proxy-memory")},
+		{"skipped inbound ports", strings.Join(inboundSkipPorts, ","), source("skip-inbound-ports", "control-port", "metrics-port")},
+		{"skipped outbound ports", valueOrNone(strings.Join(options.ignoreOutboundPorts, ",")), source("skip-outbound-ports")},
+		{"external profiles", externalProfiles, source("disable-external-profiles")},
+		{"debug sidecar", debugSidecar, source("enable-debug-sidecar")},
+	}
+	if options.enableDebugSidecar {
+		values = append(values, proxyConfigValue{"debug image", options.taggedDebugImage(), source("debug-image", "registry", "linkerd-version", "image-digests-file")})
+	}
+	return values
+}
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -63,6 +64,19 @@ func TestUninjectAndInject(t *testing.T) {
 	proxyRequestOptions.proxyCPURequest = "110m"
 	proxyRequestOptions.proxyMemoryRequest = "100Mi"
 
+	skipPortsOptions := newInjectOptions()
+	skipPortsOptions.linkerdVersion = "testinjectversion"
+	skipPortsOptions.ignoreInboundPorts = []string{"7070"}
+	skipPortsOptions.ignoreOutboundPorts = []string{"25", "4000-4100"}
+
+	debugSidecarOptions := newInjectOptions()
+	debugSidecarOptions.linkerdVersion = "testinjectversion"
+	debugSidecarOptions.enableDebugSidecar = true
+
+	diffOptions := newInjectOptions()
+	diffOptions.linkerdVersion = "testinjectversion"
+	diffOptions.diff = true
+
 	testCases := []injectYAML{
 		{
 			inputFileName:     "inject_emojivoto_deployment.input.yml",
@@ -88,6 +102,12 @@ func TestUninjectAndInject(t *testing.T) {
 			reportFileName:    "inject_emojivoto_deployment_hostNetwork_true.report",
 			testInjectOptions: defaultOptions,
 		},
+		{
+			inputFileName:     "inject_emojivoto_deployment_windows.input.yml",
+			goldenFileName:    "inject_emojivoto_deployment_windows.golden.yml",
+			reportFileName:    "inject_emojivoto_deployment_windows.report",
+			testInjectOptions: defaultOptions,
+		},
 		{
 			inputFileName:     "inject_emojivoto_deployment_controller_name.input.yml",
 			goldenFileName:    "inject_emojivoto_deployment_controller_name.golden.yml",
@@ -100,6 +120,18 @@ func TestUninjectAndInject(t *testing.T) {
 			reportFileName:    "inject_emojivoto_statefulset.report",
 			testInjectOptions: defaultOptions,
 		},
+		{
+			inputFileName:     "inject_emojivoto_cronjob.input.yml",
+			goldenFileName:    "inject_emojivoto_cronjob.golden.yml",
+			reportFileName:    "inject_emojivoto_cronjob.report",
+			testInjectOptions: defaultOptions,
+		},
+		{
+			inputFileName:     "inject_emojivoto_rollout.input.yml",
+			goldenFileName:    "inject_emojivoto_rollout.golden.yml",
+			reportFileName:    "inject_emojivoto_rollout.report",
+			testInjectOptions: defaultOptions,
+		},
 		{
 			inputFileName:     "inject_emojivoto_pod.input.yml",
 			goldenFileName:    "inject_emojivoto_pod.golden.yml",
@@ -124,12 +156,48 @@ func TestUninjectAndInject(t *testing.T) {
 			reportFileName:    "inject_emojivoto_pod.report",
 			testInjectOptions: tlsOptions,
 		},
+		{
+			inputFileName:     "inject_emojivoto_deployment.input.yml",
+			goldenFileName:    "inject_emojivoto_deployment_skip_ports.golden.yml",
+			reportFileName:    "inject_emojivoto_deployment.report",
+			testInjectOptions: skipPortsOptions,
+		},
+		{
+			inputFileName:     "inject_emojivoto_deployment.input.yml",
+			goldenFileName:    "inject_emojivoto_deployment_debug.golden.yml",
+			reportFileName:    "inject_emojivoto_deployment.report",
+			testInjectOptions: debugSidecarOptions,
+		},
+		{
+			inputFileName:     "inject_emojivoto_deployment.input.yml",
+			goldenFileName:    "inject_emojivoto_deployment.diff",
+			reportFileName:    "inject_emojivoto_deployment.report",
+			testInjectOptions: diffOptions,
+		},
+		{
+			inputFileName:     "inject_emojivoto_list.input.yml",
+			goldenFileName:    "inject_emojivoto_list.diff",
+			reportFileName:    "inject_emojivoto_list.report",
+			testInjectOptions: diffOptions,
+		},
+		{
+			inputFileName:     "inject_emojivoto_istio.input.yml",
+			goldenFileName:    "",
+			reportFileName:    "inject_emojivoto_istio.report",
+			testInjectOptions: diffOptions,
+		},
 		{
 			inputFileName:     "inject_emojivoto_deployment_udp.input.yml",
 			goldenFileName:    "inject_emojivoto_deployment_udp.golden.yml",
 			reportFileName:    "inject_emojivoto_deployment_udp.report",
 			testInjectOptions: defaultOptions,
 		},
+		{
+			inputFileName:     "inject_emojivoto_deployment_ignore_conflicts.input.yml",
+			goldenFileName:    "inject_emojivoto_deployment_ignore_conflicts.golden.yml",
+			reportFileName:    "inject_emojivoto_deployment_ignore_conflicts.report",
+			testInjectOptions: defaultOptions,
+		},
 		{
 			inputFileName:     "inject_emojivoto_already_injected.input.yml",
 			goldenFileName:    "inject_emojivoto_already_injected.golden.yml",
@@ -208,9 +276,15 @@ func TestRunInjectCmd(t *testing.T) {
 	testCases := []injectCmd{
 		{
 			inputFileName:        "inject_gettest_deployment.bad.input.yml",
+			stdOutGoldenFileName: "inject_gettest_deployment.bad.golden.yml",
 			stdErrGoldenFileName: "inject_gettest_deployment.bad.golden",
 			exitCode:             1,
 		},
+		{
+			inputFileName:        "inject_emojivoto_deployment_port_conflict.input.yml",
+			stdErrGoldenFileName: "inject_emojivoto_deployment_port_conflict.golden",
+			exitCode:             1,
+		},
 		{
 			inputFileName:        "inject_gettest_deployment.good.input.yml",
 			stdOutGoldenFileName: "inject_gettest_deployment.good.golden.yml",
@@ -231,6 +305,43 @@ func TestRunInjectCmd(t *testing.T) {
 	}
 }
 
+func TestRunInjectDryRunReport(t *testing.T) {
+	testCases := []struct {
+		inputFileName  string
+		goldenFileName string
+	}{
+		{"inject_emojivoto_list.input.yml", "inject_emojivoto_list.dry_run.report"},
+		{"inject_emojivoto_deployment_hostNetwork_true.input.yml", "inject_emojivoto_deployment_hostNetwork_true.dry_run.report"},
+		{"inject_emojivoto_deployment_windows.input.yml", "inject_emojivoto_deployment_windows.dry_run.report"},
+		{"inject_emojivoto_deployment_port_conflict.input.yml", "inject_emojivoto_deployment_port_conflict.dry_run.report"},
+		{"inject_emojivoto_deployment_udp.input.yml", "inject_emojivoto_deployment_udp.dry_run.report"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d: %s", i, tc.inputFileName), func(t *testing.T) {
+			options := newInjectOptions()
+			options.dryRunReport = true
+			options.linkerdVersion = "testinjectversion"
+			options.proxyCPURequest = "250m"
+			options.changedFlags["linkerd-version"] = true
+			options.changedFlags["proxy-cpu"] = true
+
+			in, err := os.Open(fmt.Sprintf("testdata/%s", tc.inputFileName))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			errBuffer := &bytes.Buffer{}
+			outBuffer := &bytes.Buffer{}
+			if exitCode := runInjectCmd([]io.Reader{in}, errBuffer, outBuffer, options); exitCode != 0 {
+				t.Fatalf("Unexpected error: %s", errBuffer)
+			}
+
+			diffCompareFile(t, outBuffer.String(), tc.goldenFileName)
+		})
+	}
+}
+
 type injectFilePath struct {
 	resource     string
 	resourceFile string
@@ -377,3 +488,45 @@ func TestWalk(t *testing.T) {
 		}
 	}
 }
+
+func TestUninjectAndInjectStream(t *testing.T) {
+	defer func(workers int) { transformWorkers = workers }(transformWorkers)
+	transformWorkers = 8
+
+	deployment := readOptionalTestFile(t, "inject_emojivoto_deployment.input.yml")
+
+	// stream more documents than there are workers, so that the output order
+	// doesn't depend on the order in which the workers finish
+	count := 100
+	r, w := io.Pipe()
+	go func() {
+		for i := 0; i < count; i++ {
+			fmt.Fprint(w, strings.Replace(deployment, "name: web\n", fmt.Sprintf("name: web-%d\n", i), 1))
+		}
+		w.Close()
+	}()
+
+	options := newInjectOptions()
+	options.linkerdVersion = "testinjectversion"
+
+	output := new(bytes.Buffer)
+	report := new(bytes.Buffer)
+	if exitCode := uninjectAndInject([]io.Reader{r}, report, output, options); exitCode != 0 {
+		t.Fatalf("Unexpected error injecting YAML: %v\n", report)
+	}
+
+	injected := strings.Count(report.String(), " injected\n")
+	if injected != count {
+		t.Fatalf("Expected %d resources to be injected, got %d:\n%s", count, injected, report)
+	}
+
+	last := -1
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("linkerd.io/proxy-deployment: web-%d\n", i)
+		index := strings.Index(output.String(), name)
+		if index <= last {
+			t.Fatalf("Expected web-%d to be output after web-%d", i, i-1)
+		}
+		last = index
+	}
+}
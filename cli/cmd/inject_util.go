@@ -7,14 +7,17 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	goruntime "runtime"
 
 	"github.com/ghodss/yaml"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	appsV1 "k8s.io/api/apps/v1"
 	batchV1 "k8s.io/api/batch/v1"
+	batchV1beta1 "k8s.io/api/batch/v1beta1"
 	"k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
 )
@@ -29,8 +32,10 @@ type injectReport struct {
 	name                string
 	hostNetwork         bool
 	sidecar             bool
+	windows             bool // true if the pods can only run on Windows nodes
 	udp                 bool // true if any port in any container has `protocol: UDP`
 	unsupportedResource bool
+	conflict            string // set instead of failing in dry-run reports
 }
 
 type resourceConfig struct {
@@ -47,56 +52,96 @@ func (i injectReport) resName() string {
 	return fmt.Sprintf("%s/%s", i.kind, i.name)
 }
 
+// transformWorkers is the number of YAML documents that ProcessYAML
+// transforms concurrently.
+var transformWorkers = goruntime.NumCPU()
+
 // Returns the integer representation of os.Exit code; 0 on success and 1 on failure.
 func transformInput(inputs []io.Reader, errWriter, outWriter io.Writer, options *injectOptions, rt resourceTransformer) int {
-	postInjectBuf := &bytes.Buffer{}
 	reportBuf := &bytes.Buffer{}
 
 	for _, input := range inputs {
-		err := ProcessYAML(input, postInjectBuf, reportBuf, options, rt)
+		// the YAML output is streamed to outWriter as it's transformed, so
+		// that large inputs are never held in memory in their entirety
+		err := ProcessYAML(input, outWriter, reportBuf, options, rt)
 		if err != nil {
 			fmt.Fprintf(errWriter, "Error transforming resources: %v\n", err)
 			return 1
 		}
-		_, err = io.Copy(outWriter, postInjectBuf)
 
 		// print error report after yaml output, for better visibility
 		io.Copy(errWriter, reportBuf)
-
-		if err != nil {
-			fmt.Fprintf(errWriter, "Error printing YAML: %v\n", err)
-			return 1
-		}
 	}
 	return 0
 }
 
+type transformResult struct {
+	output  []byte
+	reports []injectReport
+	err     error
+}
+
 // ProcessYAML takes an input stream of YAML, outputting injected/uninjected YAML to out.
+// Each YAML object is read and transformed on its own, and up to
+// transformWorkers objects are transformed concurrently. The transformed
+// objects are written to out in the order in which they were read.
 func ProcessYAML(in io.Reader, out io.Writer, report io.Writer, options *injectOptions, rt resourceTransformer) error {
 	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(in, 4096))
 
+	// results holds the pending result of each YAML object, in input order.
+	// Its capacity bounds the number of objects held in memory at once.
+	results := make(chan chan transformResult, transformWorkers)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(results)
+
+		// Iterate over all YAML objects in the input
+		for {
+			result := make(chan transformResult, 1)
+
+			// Read a single YAML object
+			bytes, err := reader.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				result <- transformResult{err: err}
+			} else {
+				go func() {
+					output, irs, err := rt.transform(bytes, options)
+					result <- transformResult{output: output, reports: irs, err: err}
+				}()
+			}
+
+			select {
+			case results <- result:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
 	injectReports := []injectReport{}
 
-	// Iterate over all YAML objects in the input
-	for {
-		// Read a single YAML object
-		bytes, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return err
+	for result := range results {
+		r := <-result
+		if r.err != nil {
+			return r.err
 		}
 
-		result, irs, err := rt.transform(bytes, options)
-		if err != nil {
-			return err
+		if _, err := out.Write(r.output); err != nil {
+			return fmt.Errorf("error printing YAML: %v", err)
+		}
+		if _, err := out.Write([]byte("---\n")); err != nil {
+			return fmt.Errorf("error printing YAML: %v", err)
 		}
 
-		out.Write(result)
-		out.Write([]byte("---\n"))
-
-		injectReports = append(injectReports, irs...)
+		injectReports = append(injectReports, r.reports...)
 	}
 
 	rt.generateReport(injectReports, report)
@@ -247,6 +292,40 @@ func (conf *resourceConfig) parse(bytes []byte, options *injectOptions, rt resou
 		conf.podSpec = &statefulset.Spec.Template.Spec
 		conf.objectMeta = &statefulset.Spec.Template.ObjectMeta
 
+	case "CronJob":
+		var cronJob batchV1beta1.CronJob
+		if err := yaml.Unmarshal(bytes, &cronJob); err != nil {
+			return nil, nil, err
+		}
+
+		conf.obj = &cronJob
+		conf.k8sLabels[k8s.ProxyCronJobLabel] = cronJob.Name
+		conf.podSpec = &cronJob.Spec.JobTemplate.Spec.Template.Spec
+		conf.objectMeta = &cronJob.Spec.JobTemplate.Spec.Template.ObjectMeta
+
+	case "Rollout":
+		// Argo Rollouts have no Go type we can depend on, so only their pod
+		// template is decoded, and written back when the rollout is marshaled.
+		var rollout unstructuredWorkload
+		if err := yaml.Unmarshal(bytes, &rollout.Object); err != nil {
+			return nil, nil, err
+		}
+		tmpl, found, err := unstructured.NestedMap(rollout.Object, "spec", "template")
+		if err != nil {
+			return nil, nil, err
+		}
+		if !found {
+			return nil, nil, fmt.Errorf("rollout \"%s\" has no spec.template", conf.om.Name)
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(tmpl, &rollout.template); err != nil {
+			return nil, nil, err
+		}
+
+		conf.obj = &rollout
+		conf.k8sLabels[k8s.ProxyRolloutLabel] = conf.om.Name
+		conf.podSpec = &rollout.template.Spec
+		conf.objectMeta = &rollout.template.ObjectMeta
+
 	case "Pod":
 		var pod v1.Pod
 		if err := yaml.Unmarshal(bytes, &pod); err != nil {
@@ -267,6 +346,26 @@ func (conf *resourceConfig) parse(bytes []byte, options *injectOptions, rt resou
 	return nil, nil, nil
 }
 
+// unstructuredWorkload is a workload whose kind has no Go type, with its pod
+// template decoded from spec.template.
+type unstructuredWorkload struct {
+	unstructured.Unstructured
+	template v1.PodTemplateSpec
+}
+
+// MarshalJSON writes the (possibly modified) pod template back into
+// spec.template before marshaling the workload.
+func (w *unstructuredWorkload) MarshalJSON() ([]byte, error) {
+	tmpl, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&w.template)
+	if err != nil {
+		return nil, err
+	}
+	if err := unstructured.SetNestedMap(w.Object, tmpl, "spec", "template"); err != nil {
+		return nil, err
+	}
+	return w.Unstructured.MarshalJSON()
+}
+
 // Read all the resource files found in path into a slice of readers.
 // path can be either a file, directory or stdin.
 func read(path string) ([]io.Reader, error) {
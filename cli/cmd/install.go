@@ -2,18 +2,26 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/url"
 	"os"
+	"regexp"
 	"strings"
 	"text/template"
 
 	"github.com/linkerd/linkerd2/cli/install"
+	"github.com/linkerd/linkerd2/pkg/flags"
 	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/tls"
 	uuid "github.com/satori/go.uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 type installConfig struct {
@@ -22,13 +30,19 @@ type installConfig struct {
 	WebImage                         string
 	PrometheusImage                  string
 	PrometheusVolumeName             string
-	GrafanaImage                     string
 	GrafanaVolumeName                string
+	GrafanaURL                       string
+	EnforcedHost                     string
+	DisableVersionCheck              bool
+	NodeSelector                     map[string]string
+	Tolerations                      []v1.Toleration
+	PriorityClassName                string
 	ControllerReplicas               uint
 	ImagePullPolicy                  string
 	UUID                             string
 	CliVersion                       string
 	ControllerLogLevel               string
+	ControllerLogFormat              string
 	ControllerComponentLabel         string
 	CreatedByAnnotation              string
 	ProxyAPIPort                     uint
@@ -38,8 +52,14 @@ type installConfig struct {
 	TLSTrustAnchorFileName           string
 	TLSCertFileName                  string
 	TLSPrivateKeyFileName            string
+	TLSCertPEMFileName               string
+	TLSPrivateKeyPEMFileName         string
 	TLSTrustAnchorVolumeSpecFileName string
 	TLSIdentityVolumeSpecFileName    string
+	TLSIssuerSecret                  string
+	TLSIssuerVault                   *vaultIssuerConfig
+	TLSIssuerCertificate             string
+	TLSIssuerKey                     string
 	InboundPort                      uint
 	OutboundPort                     uint
 	IgnoreInboundPorts               string
@@ -49,48 +69,214 @@ type installConfig struct {
 	ProxyUID                         int64
 	ProxyMetricsPort                 uint
 	ProxyControlPort                 uint
+	APIAuth                          bool
+	PublicAPITLSPort                 int
+	PublicAPITLSIdentity             string
+	PublicAPITLSSecret               string
+	APIClientTLSIdentity             string
+	APIClientTLSSecret               string
+	TapTLSPort                       int
+	TapAPIService                    bool
+	TapAPIPort                       int
+	TapAPIGroup                      string
+	TapAPIVersion                    string
+	PrometheusURL                    string
+	PrometheusBearerTokenSecret      string
+	PrometheusCASecret               string
+	PrometheusFederationURLs         string
+	PrometheusRetention              string
+	PrometheusRemoteWriteURLs        []string
+	PrometheusRemoteWriteSecret      string
 	ProxyInjectorTLSSecret           string
+	ProxyInjectorFailurePolicy       string
+	ProxyInjectorNamespaceSelector   string
+	ProxyNetworkPolicies             bool
 	ProxySpecFileName                string
 	ProxyInitSpecFileName            string
 	ProxyInitImage                   string
+	DebugSpecFileName                string
+	DebugContainerName               string
+	DebugImage                       string
 	ProxyImage                       string
 	ProxyResourceRequestCPU          string
 	ProxyResourceRequestMemory       string
 	ProxyBindTimeout                 string
+	ProxyLogLevel                    string
 	SingleNamespace                  bool
+	SkipCRDs                         bool
 	EnableHA                         bool
+	CALeaderElection                 bool
+	ControllerAntiAffinity           string
+	ControllerTopologyKeys           []string
+	ControllerMaxUnavailable         uint
 	ControllerUID                    int64
 	ProfileSuffixes                  string
 	EnableH2Upgrade                  bool
+	EnableNetworkPolicies            bool
+	EnableTopologyAwareRouting       bool
+	MetricPodLabels                  string
+	MetricPodLabelNames              string
+	EventWebhookURL                  string
+	AddOns                           map[string]map[string]string
+	LinkerdConfig                    string
 }
 
 type installOptions struct {
-	controllerReplicas uint
-	controllerLogLevel string
-	proxyAutoInject    bool
-	singleNamespace    bool
-	highAvailability   bool
-	controllerUID      int64
-	disableH2Upgrade   bool
+	controllerReplicas             uint
+	controllerLogLevel             string
+	controllerLogFormat            string
+	proxyAutoInject                bool
+	proxyInjectorFailurePolicy     string
+	proxyInjectorNamespaceSelector string
+	proxyNetworkPolicies           bool
+	tlsIssuerSecret                string
+	tlsIssuerVault                 vaultIssuerConfig
+	tlsIssuerCertFile              string
+	tlsIssuerKeyFile               string
+	singleNamespace                bool
+	skipCRDs                       bool
+	highAvailability               bool
+	controllerAntiAffinity         string
+	controllerTopologyKeys         []string
+	controllerMaxUnavailable       uint
+	controllerUID                  int64
+	disableH2Upgrade               bool
+	networkPolicies                bool
+	topologyRouting                bool
+	metricPodLabels                []string
+	prometheusURL                  string
+	prometheusBearerTokenSecret    string
+	prometheusCASecret             string
+	prometheusFederationURLs       []string
+	prometheusRetention            string
+	prometheusRemoteWriteURLs      []string
+	prometheusRemoteWriteSecret    string
+	grafanaURL                     string
+	enforcedHost                   string
+	disableVersionCheck            bool
+	nodeSelector                   []string
+	tolerations                    []string
+	priorityClassName              string
+	eventWebhookURL                string
+	apiAuth                        string
+	tapAPIService                  bool
+	addOns                         map[string]*bool
+	addOnValues                    []string
+	valuesFiles                    []string
+	setValues                      []string
+	outputDir                      string
+	snapshot                       bool
+	interactive                    bool
+	recordedFlags                  []string
 	*proxyConfigOptions
 }
 
+// vaultIssuerConfig configures the CA to have the PKI secrets engine of
+// HashiCorp Vault sign the certificates of the pods.
+type vaultIssuerConfig struct {
+	Addr     string
+	PKIPath  string
+	Role     string
+	AuthPath string
+	AuthRole string
+}
+
+// reservedMetricLabels are the names of the metric labels set by the proxies
+// and Prometheus, which can't be overridden by pod labels.
+var reservedMetricLabels = map[string]struct{}{
+	"authority":             {},
+	"classification":        {},
+	"component":             {},
+	"control_plane_ns":      {},
+	"daemonset":             {},
+	"deployment":            {},
+	"direction":             {},
+	"grpc_status_code":      {},
+	"instance":              {},
+	"job":                   {},
+	"k8s_job":               {},
+	"namespace":             {},
+	"pod":                   {},
+	"pod_template_hash":     {},
+	"replicaset":            {},
+	"replicationcontroller": {},
+	"service":               {},
+	"statefulset":           {},
+	"status_code":           {},
+	"tls":                   {},
+}
+
 const (
 	prometheusProxyOutboundCapacity = 10000
 	defaultControllerReplicas       = 1
 	defaultHAControllerReplicas     = 3
+
+	// the --controller-anti-affinity settings, for whether the controller
+	// replicas can share a topology domain if they can't be scheduled apart
+	antiAffinityNone      = "none"
+	antiAffinityPreferred = "preferred"
+	antiAffinityRequired  = "required"
+
+	// defaultTLSIssuerSecret is the name of the secret that holds the issuer
+	// credentials given with --tls-issuer-cert-file and --tls-issuer-key-file.
+	defaultTLSIssuerSecret = "linkerd-ca-issuer"
+
+	defaultPrometheusRetention = "6h"
 )
 
+// prometheusDurationRegexp matches the durations that Prometheus accepts for
+// its flags, such as "6h" or "15d".
+var prometheusDurationRegexp = regexp.MustCompile(`^[0-9]+(ms|s|m|h|d|w|y)$`)
+
 func newInstallOptions() *installOptions {
 	return &installOptions{
-		controllerReplicas: defaultControllerReplicas,
-		controllerLogLevel: "info",
-		proxyAutoInject:    false,
-		singleNamespace:    false,
-		highAvailability:   false,
-		controllerUID:      2103,
-		disableH2Upgrade:   false,
-		proxyConfigOptions: newProxyConfigOptions(),
+		controllerReplicas:             defaultControllerReplicas,
+		controllerLogLevel:             "info",
+		controllerLogFormat:            flags.PlainLogFormat,
+		proxyAutoInject:                false,
+		proxyInjectorFailurePolicy:     "Ignore",
+		proxyInjectorNamespaceSelector: k8s.ProxyInjectorNamespaceSelectorOptOut,
+		proxyNetworkPolicies:           false,
+		singleNamespace:                false,
+		skipCRDs:                       false,
+		highAvailability:               false,
+		controllerAntiAffinity:         antiAffinityPreferred,
+		controllerTopologyKeys:         []string{"kubernetes.io/hostname"},
+		controllerMaxUnavailable:       1,
+		controllerUID:                  2103,
+		disableH2Upgrade:               false,
+		networkPolicies:                false,
+		topologyRouting:                false,
+		metricPodLabels:                []string{},
+		prometheusURL:                  "",
+		prometheusBearerTokenSecret:    "",
+		prometheusCASecret:             "",
+		prometheusFederationURLs:       []string{},
+		prometheusRetention:            defaultPrometheusRetention,
+		prometheusRemoteWriteURLs:      []string{},
+		prometheusRemoteWriteSecret:    "",
+		grafanaURL:                     "",
+		enforcedHost:                   "",
+		disableVersionCheck:            false,
+		nodeSelector:                   []string{},
+		tolerations:                    []string{},
+		priorityClassName:              "",
+		eventWebhookURL:                "",
+		apiAuth:                        "",
+		tapAPIService:                  false,
+		addOns:                         newAddOnOptions(),
+		addOnValues:                    []string{},
+		valuesFiles:                    []string{},
+		setValues:                      []string{},
+		outputDir:                      "",
+		snapshot:                       false,
+		interactive:                    false,
+		recordedFlags:                  []string{},
+		proxyConfigOptions:             newProxyConfigOptions(),
+		tlsIssuerVault: vaultIssuerConfig{
+			PKIPath:  "pki",
+			AuthPath: "kubernetes",
+		},
 	}
 }
 
@@ -102,24 +288,98 @@ func newCmdInstall() *cobra.Command {
 		Short: "Output Kubernetes configs to install Linkerd",
 		Long:  "Output Kubernetes configs to install Linkerd.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyInstallValues(cmd.PersistentFlags(), options); err != nil {
+				return err
+			}
+			if options.interactive {
+				if err := installInteractive(os.Stdin, os.Stderr, cmd.Flags(), options); err != nil {
+					return err
+				}
+			}
+
+			options.recordedFlags = recordedInstallFlags(cmd.PersistentFlags())
 			config, err := validateAndBuildConfig(options)
 			if err != nil {
 				return err
 			}
 
-			return render(*config, os.Stdout, options)
+			if options.snapshot {
+				config.UUID = snapshotUUID
+				buf := &bytes.Buffer{}
+				if err := render(*config, buf, options); err != nil {
+					return err
+				}
+				return writeInstallSnapshot(buf, os.Stdout)
+			}
+
+			if options.outputDir == "" {
+				return render(*config, os.Stdout, options)
+			}
+
+			buf := &bytes.Buffer{}
+			if err := render(*config, buf, options); err != nil {
+				return err
+			}
+			return writeInstallDir(*config, buf, options.outputDir)
 		},
 	}
 
+	addInstallFlags(cmd, options)
+	cmd.PersistentFlags().BoolVar(&options.skipCRDs, "skip-crds", options.skipCRDs, "Don't output the custom resource definitions, which are then managed separately with \"linkerd upgrade --crds\" (default false)")
+	cmd.PersistentFlags().StringVar(&options.outputDir, "output-dir", options.outputDir, "Write the configs to one file per control plane component in this directory, along with a kustomization.yaml, instead of printing them")
+	cmd.PersistentFlags().BoolVar(&options.snapshot, "snapshot", options.snapshot, "Print a normalized manifest for diffing against a checked-in copy, with the install UUID fixed and the secret values replaced by their digests; it can't be applied as is (default false)")
+	cmd.PersistentFlags().BoolVar(&options.interactive, "interactive", options.interactive, "Prompt for the main settings of the control plane, starting from the values of the other flags, then print the equivalent command to stderr (default false)")
+	return cmd
+}
+
+// addInstallFlags adds the flags that configure the rendered control plane to
+// cmd, which `linkerd upgrade --diff` shares with `linkerd install`.
+func addInstallFlags(cmd *cobra.Command, options *installOptions) {
 	addProxyConfigFlags(cmd, options.proxyConfigOptions)
+	addAddOnFlags(cmd, options)
 	cmd.PersistentFlags().UintVar(&options.controllerReplicas, "controller-replicas", options.controllerReplicas, "Replicas of the controller to deploy")
 	cmd.PersistentFlags().StringVar(&options.controllerLogLevel, "controller-log-level", options.controllerLogLevel, "Log level for the controller and web components")
-	cmd.PersistentFlags().BoolVar(&options.proxyAutoInject, "proxy-auto-inject", options.proxyAutoInject, "Experimental: Enable proxy sidecar auto-injection webhook (default false)")
+	cmd.PersistentFlags().StringVar(&options.controllerLogFormat, "controller-log-format", options.controllerLogFormat, "Log format for the controller and web components, one of: plain, json")
+	cmd.PersistentFlags().BoolVar(&options.proxyAutoInject, "proxy-auto-inject", options.proxyAutoInject, "Experimental: Enable proxy sidecar auto-injection webhook, which also validates the ServiceProfiles when they are created or updated (default false)")
+	cmd.PersistentFlags().StringVar(&options.proxyInjectorFailurePolicy, "proxy-injector-failure-policy", options.proxyInjectorFailurePolicy, "Experimental: What happens to pod creation when the auto-injection webhook fails: Ignore (never block pod creation) or Fail (never miss injection)")
+	cmd.PersistentFlags().StringVar(&options.proxyInjectorNamespaceSelector, "proxy-injector-namespace-selector", options.proxyInjectorNamespaceSelector, fmt.Sprintf("Experimental: Which namespaces the auto-injection webhook injects: opt-out (all but those labeled %s=%s) or opt-in (only those labeled %s=%s)", k8s.ProxyAutoInjectLabel, k8s.ProxyAutoInjectDisabled, k8s.ProxyAutoInjectLabel, k8s.ProxyAutoInjectEnabled))
+	cmd.PersistentFlags().BoolVar(&options.proxyNetworkPolicies, "proxy-network-policies", options.proxyNetworkPolicies, "Experimental: Have the auto-injection webhook create a NetworkPolicy in the namespaces of the pods it injects, which lets their proxies reach the control plane and be scraped and tapped by it, for the namespaces that deny traffic by default; requires --proxy-auto-inject (default false)")
+	cmd.PersistentFlags().StringVar(&options.tlsIssuerSecret, "tls-issuer-secret", options.tlsIssuerSecret, "Experimental: Name of a kubernetes.io/tls secret in the control plane namespace, such as one managed by cert-manager, with the CA certificate and ECDSA P-256 key that the CA signs certificates with, instead of generating its own; requires --tls=optional")
+	cmd.PersistentFlags().StringVar(&options.tlsIssuerVault.Addr, "tls-issuer-vault-addr", options.tlsIssuerVault.Addr, "Experimental: Address of a HashiCorp Vault server, such as https://vault.vault.svc.cluster.local:8200, whose PKI secrets engine signs the certificates instead of the CA; requires --tls=optional")
+	cmd.PersistentFlags().StringVar(&options.tlsIssuerVault.PKIPath, "tls-issuer-vault-pki-path", options.tlsIssuerVault.PKIPath, "Experimental: Path where the Vault PKI secrets engine is mounted")
+	cmd.PersistentFlags().StringVar(&options.tlsIssuerVault.Role, "tls-issuer-vault-role", options.tlsIssuerVault.Role, "Experimental: Vault PKI role that signs the certificates, which must allow the DNS names of the pod identities")
+	cmd.PersistentFlags().StringVar(&options.tlsIssuerVault.AuthPath, "tls-issuer-vault-auth-path", options.tlsIssuerVault.AuthPath, "Experimental: Path where the Vault Kubernetes auth method is mounted")
+	cmd.PersistentFlags().StringVar(&options.tlsIssuerVault.AuthRole, "tls-issuer-vault-auth-role", options.tlsIssuerVault.AuthRole, "Experimental: Vault Kubernetes auth role that the CA logs in with its service account")
+	cmd.PersistentFlags().StringVar(&options.tlsIssuerCertFile, "tls-issuer-cert-file", options.tlsIssuerCertFile, "Experimental: Path to a PEM-encoded CA certificate that the CA signs certificates with, instead of generating its own; requires --tls-issuer-key-file and --tls=optional")
+	cmd.PersistentFlags().StringVar(&options.tlsIssuerKeyFile, "tls-issuer-key-file", options.tlsIssuerKeyFile, "Experimental: Path to the PEM-encoded ECDSA P-256 private key of the --tls-issuer-cert-file certificate")
 	cmd.PersistentFlags().BoolVar(&options.singleNamespace, "single-namespace", options.singleNamespace, "Experimental: Configure the control plane to only operate in the installed namespace (default false)")
 	cmd.PersistentFlags().BoolVar(&options.highAvailability, "ha", options.highAvailability, "Experimental: Enable HA deployment config for the control plane")
+	cmd.PersistentFlags().StringVar(&options.controllerAntiAffinity, "controller-anti-affinity", options.controllerAntiAffinity, fmt.Sprintf("Experimental: With --ha, whether the controller replicas are kept apart in each of the --controller-topology-keys domains: %s, %s (never schedule them together) or %s", antiAffinityPreferred, antiAffinityRequired, antiAffinityNone))
+	cmd.PersistentFlags().StringSliceVar(&options.controllerTopologyKeys, "controller-topology-keys", options.controllerTopologyKeys, "Experimental: With --ha, the node labels of the topology domains to spread the controller replicas across, for example \"kubernetes.io/hostname,failure-domain.beta.kubernetes.io/zone\"")
+	cmd.PersistentFlags().UintVar(&options.controllerMaxUnavailable, "controller-max-unavailable", options.controllerMaxUnavailable, "Experimental: With --ha, the number of controller replicas that voluntary disruptions, such as node drains, may take down at once")
 	cmd.PersistentFlags().Int64Var(&options.controllerUID, "controller-uid", options.controllerUID, "Run the control plane components under this user ID")
 	cmd.PersistentFlags().BoolVar(&options.disableH2Upgrade, "disable-h2-upgrade", options.disableH2Upgrade, "Prevents the controller from instructing proxies to perform transparent HTTP/2 ugprading")
-	return cmd
+	cmd.PersistentFlags().BoolVar(&options.networkPolicies, "control-plane-network-policies", options.networkPolicies, "Experimental: Restrict ingress to the control plane namespace to the ports required between components, Prometheus, webhooks, and proxies (default false)")
+	cmd.PersistentFlags().BoolVar(&options.topologyRouting, "topology-aware-routing", options.topologyRouting, "Experimental: Prefer sending proxies the endpoints in their own zone, to reduce cross-zone traffic (default false)")
+	cmd.PersistentFlags().StringSliceVar(&options.metricPodLabels, "metric-pod-labels", options.metricPodLabels, "Pod label keys to add to the metrics of meshed pods, for example \"version,team\"")
+	cmd.PersistentFlags().StringVar(&options.prometheusURL, "prometheus-url", options.prometheusURL, "Experimental: URL of an existing Prometheus, which must scrape the proxies like the bundled one does, for the public API and Grafana to query instead of installing the bundled Prometheus")
+	cmd.PersistentFlags().StringVar(&options.prometheusBearerTokenSecret, "prometheus-bearer-token-secret", options.prometheusBearerTokenSecret, "Experimental: Name of a secret in the control plane namespace with the bearer token that the public API authenticates to the --prometheus-url with, under the \"token\" key")
+	cmd.PersistentFlags().StringVar(&options.prometheusCASecret, "prometheus-ca-secret", options.prometheusCASecret, "Experimental: Name of a secret in the control plane namespace with the PEM-encoded CA bundle that the public API verifies the TLS certificate of the --prometheus-url with, under the \"ca.crt\" key")
+	cmd.PersistentFlags().StringVar(&options.prometheusRetention, "prometheus-retention", options.prometheusRetention, "Experimental: How long the bundled Prometheus keeps the metrics for, for example \"2d\"")
+	cmd.PersistentFlags().StringArrayVar(&options.prometheusFederationURLs, "prometheus-federation-url", options.prometheusFederationURLs, "Experimental: URL of an additional Prometheus, such as the one of a zone or a shard of the mesh, that the public API queries along with the main one, merging their results, with the same credentials (may be repeated)")
+	cmd.PersistentFlags().StringArrayVar(&options.prometheusRemoteWriteURLs, "prometheus-remote-write-url", options.prometheusRemoteWriteURLs, "Experimental: URL of a remote storage, such as Thanos or Cortex, that the bundled Prometheus ships its metrics to (may be repeated)")
+	cmd.PersistentFlags().StringVar(&options.prometheusRemoteWriteSecret, "prometheus-remote-write-secret", options.prometheusRemoteWriteSecret, "Experimental: Name of a secret in the control plane namespace with the bearer token that the bundled Prometheus authenticates to the --prometheus-remote-write-url with, under the \"token\" key")
+	cmd.PersistentFlags().StringVar(&options.grafanaURL, "grafana-url", options.grafanaURL, "Experimental: URL of an existing Grafana that the dashboard links to, which has the Linkerd dashboards imported with their UIDs, instead of the grafana add-on")
+	cmd.PersistentFlags().StringVar(&options.enforcedHost, "enforced-host", options.enforcedHost, "Regexp of the hosts that the dashboard can be reached with, to protect it against DNS rebinding, for example \"^dashboard\\.example\\.com$\" (default: localhost and the linkerd-web service)")
+	cmd.PersistentFlags().BoolVar(&options.disableVersionCheck, "disable-version-check", options.disableVersionCheck, "Disable the dashboard's version checks, which send the install UUID and the Linkerd version to the version check endpoint; run \"linkerd diagnostics version-check --dry-run\" to see what they send (default false)")
+	cmd.PersistentFlags().StringArrayVar(&options.nodeSelector, "control-plane-node-selector", options.nodeSelector, "Node label, as <key>=<value>, that the nodes of the control plane pods must have, for example \"node-role.kubernetes.io/system=true\" (may be repeated)")
+	cmd.PersistentFlags().StringArrayVar(&options.tolerations, "control-plane-toleration", options.tolerations, "Taint that the control plane pods tolerate, as <key>[=<value>][:<effect>], for example \"dedicated=system:NoSchedule\"; without a value, any value of the key is tolerated, and without an effect, any effect (may be repeated)")
+	cmd.PersistentFlags().StringVar(&options.priorityClassName, "control-plane-priority-class-name", options.priorityClassName, "Name of the PriorityClass of the control plane pods, for example \"system-cluster-critical\"")
+	cmd.PersistentFlags().StringVar(&options.apiAuth, "api-auth", options.apiAuth, fmt.Sprintf("Experimental: Serve the public API only over mutual TLS on port %d, to the clients that authenticate with the certificate that the CA issues to the API client identity, such as the web dashboard and \"linkerd --api-tls\"; valid settings: \"tls\" (requires --tls=optional)", k8s.PublicAPITLSPort))
+	cmd.PersistentFlags().BoolVar(&options.tapAPIService, "tap-api-service", options.tapAPIService, fmt.Sprintf("Experimental: Also serve tap as the %s APIService of the Kubernetes API, so that the Kubernetes credentials, audit logging and RBAC apply to the taps of \"linkerd tap\" and \"linkerd top\"; the users that can tap must be bound to the linkerd-<namespace>-tap-admin ClusterRole (default false)", k8s.TapAPIServiceName))
+	cmd.PersistentFlags().StringArrayVar(&options.valuesFiles, "values", options.valuesFiles, "YAML file of the settings of the control plane, mapping the names of the flags to their values, such as \"controller-replicas: 3\", with lists for the flags that may be repeated; the later files and --set take precedence, and the flags given on the command line take precedence over all of them (may be repeated)")
+	cmd.PersistentFlags().StringArrayVar(&options.setValues, "set", options.setValues, "Setting of the control plane, as <flag>=<value>, for example \"controller-replicas=3\"; it takes precedence over the --values files (may be repeated)")
+	cmd.PersistentFlags().StringVar(&options.eventWebhookURL, "event-webhook-url", options.eventWebhookURL, "Experimental: URL that the control plane posts its lifecycle events to as JSON: proxy injections, issuer certificate rotations, spikes of denied injections and completed upgrades")
 }
 
 func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
@@ -131,13 +391,8 @@ func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
 		fmt.Sprintf("%d", options.proxyControlPort),
 		fmt.Sprintf("%d", options.proxyMetricsPort),
 	}
-	for _, p := range options.ignoreInboundPorts {
-		ignoreInboundPorts = append(ignoreInboundPorts, fmt.Sprintf("%d", p))
-	}
-	ignoreOutboundPorts := []string{}
-	for _, p := range options.ignoreOutboundPorts {
-		ignoreOutboundPorts = append(ignoreOutboundPorts, fmt.Sprintf("%d", p))
-	}
+	ignoreInboundPorts = append(ignoreInboundPorts, options.ignoreInboundPorts...)
+	ignoreOutboundPorts := append([]string{}, options.ignoreOutboundPorts...)
 
 	if options.highAvailability && options.controllerReplicas == defaultControllerReplicas {
 		options.controllerReplicas = defaultHAControllerReplicas
@@ -151,6 +406,65 @@ func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
 		options.proxyMemoryRequest = "20Mi"
 	}
 
+	var tlsIssuerVault *vaultIssuerConfig
+	if options.tlsIssuerVault.Addr != "" {
+		tlsIssuerVault = &options.tlsIssuerVault
+	}
+
+	controllerAntiAffinity := ""
+	var controllerMaxUnavailable uint
+	if options.highAvailability {
+		if options.controllerMaxUnavailable >= options.controllerReplicas {
+			return nil, fmt.Errorf("--controller-max-unavailable must be lower than the number of controller replicas (%d)", options.controllerReplicas)
+		}
+		controllerMaxUnavailable = options.controllerMaxUnavailable
+		if options.controllerAntiAffinity != antiAffinityNone {
+			controllerAntiAffinity = options.controllerAntiAffinity
+		}
+	}
+
+	nodeSelector, err := parseNodeSelector(options.nodeSelector)
+	if err != nil {
+		return nil, err
+	}
+	tolerations, err := parseTolerations(options.tolerations)
+	if err != nil {
+		return nil, err
+	}
+	linkerdConfig, err := json.Marshal(linkerdConfig{Version: options.linkerdVersion, Flags: options.recordedFlags})
+	if err != nil {
+		return nil, err
+	}
+
+	metricPodLabelNames := []string{}
+	for _, key := range options.metricPodLabels {
+		metricPodLabelNames = append(metricPodLabelNames, k8s.ToMetricLabelName(key))
+	}
+
+	publicAPIIdentity := k8s.TLSIdentity{
+		ControllerNamespace: controlPlaneNamespace,
+	}.ToPublicAPIIdentity()
+	apiClientIdentity := k8s.TLSIdentity{
+		ControllerNamespace: controlPlaneNamespace,
+	}.ToAPIClientIdentity()
+
+	tlsIssuerSecret := options.tlsIssuerSecret
+	var tlsIssuerCert, tlsIssuerKey string
+	if options.tlsIssuerCertFile != "" {
+		certPEM, keyPEM, err := readTLSIssuer(options.tlsIssuerCertFile, options.tlsIssuerKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsIssuerSecret = defaultTLSIssuerSecret
+		tlsIssuerCert = base64.StdEncoding.EncodeToString(certPEM)
+		tlsIssuerKey = base64.StdEncoding.EncodeToString(keyPEM)
+	}
+
+	addOnValues, err := parseAddOnValues(options)
+	if err != nil {
+		return nil, err
+	}
+
 	profileSuffixes := "."
 	if options.proxyConfigOptions.disableExternalProfiles {
 		profileSuffixes = "svc.cluster.local."
@@ -158,17 +472,24 @@ func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
 
 	return &installConfig{
 		Namespace:                        controlPlaneNamespace,
-		ControllerImage:                  fmt.Sprintf("%s/controller:%s", options.dockerRegistry, options.linkerdVersion),
-		WebImage:                         fmt.Sprintf("%s/web:%s", options.dockerRegistry, options.linkerdVersion),
-		PrometheusImage:                  "prom/prometheus:v2.4.0",
+		ControllerImage:                  options.imageRef(options.dockerRegistry+"/controller", options.linkerdVersion),
+		WebImage:                         options.imageRef(options.dockerRegistry+"/web", options.linkerdVersion),
+		PrometheusImage:                  options.thirdPartyImageRef("prom/prometheus:v2.4.0"),
 		PrometheusVolumeName:             "data",
-		GrafanaImage:                     fmt.Sprintf("%s/grafana:%s", options.dockerRegistry, options.linkerdVersion),
 		GrafanaVolumeName:                "data",
+		GrafanaURL:                       options.grafanaURL,
+		EnforcedHost:                     options.enforcedHost,
+		DisableVersionCheck:              options.disableVersionCheck,
+		NodeSelector:                     nodeSelector,
+		Tolerations:                      tolerations,
+		PriorityClassName:                options.priorityClassName,
+		LinkerdConfig:                    string(linkerdConfig),
 		ControllerReplicas:               options.controllerReplicas,
 		ImagePullPolicy:                  options.imagePullPolicy,
 		UUID:                             uuid.NewV4().String(),
 		CliVersion:                       k8s.CreatedByAnnotationValue(),
 		ControllerLogLevel:               options.controllerLogLevel,
+		ControllerLogFormat:              options.controllerLogFormat,
 		ControllerComponentLabel:         k8s.ControllerComponentLabel,
 		ControllerUID:                    options.controllerUID,
 		CreatedByAnnotation:              k8s.CreatedByAnnotation,
@@ -179,8 +500,14 @@ func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
 		TLSTrustAnchorFileName:           k8s.TLSTrustAnchorFileName,
 		TLSCertFileName:                  k8s.TLSCertFileName,
 		TLSPrivateKeyFileName:            k8s.TLSPrivateKeyFileName,
+		TLSCertPEMFileName:               k8s.TLSCertPEMFileName,
+		TLSPrivateKeyPEMFileName:         k8s.TLSPrivateKeyPEMFileName,
 		TLSTrustAnchorVolumeSpecFileName: k8s.TLSTrustAnchorVolumeSpecFileName,
 		TLSIdentityVolumeSpecFileName:    k8s.TLSIdentityVolumeSpecFileName,
+		TLSIssuerSecret:                  tlsIssuerSecret,
+		TLSIssuerVault:                   tlsIssuerVault,
+		TLSIssuerCertificate:             tlsIssuerCert,
+		TLSIssuerKey:                     tlsIssuerKey,
 		InboundPort:                      options.inboundPort,
 		OutboundPort:                     options.outboundPort,
 		IgnoreInboundPorts:               strings.Join(ignoreInboundPorts, ","),
@@ -190,18 +517,54 @@ func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
 		ProxyUID:                         options.proxyUID,
 		ProxyMetricsPort:                 options.proxyMetricsPort,
 		ProxyControlPort:                 options.proxyControlPort,
+		APIAuth:                          options.apiAuth == k8s.APIAuthTLS,
+		PublicAPITLSPort:                 k8s.PublicAPITLSPort,
+		PublicAPITLSIdentity:             publicAPIIdentity.ToDNSName(),
+		PublicAPITLSSecret:               publicAPIIdentity.ToSecretName(),
+		APIClientTLSIdentity:             apiClientIdentity.ToDNSName(),
+		APIClientTLSSecret:               apiClientIdentity.ToSecretName(),
+		TapTLSPort:                       k8s.TapTLSPort,
+		TapAPIService:                    options.tapAPIService,
+		TapAPIPort:                       k8s.TapAPIPort,
+		TapAPIGroup:                      k8s.TapAPIGroup,
+		TapAPIVersion:                    k8s.TapAPIVersion,
+		PrometheusURL:                    options.prometheusURL,
+		PrometheusBearerTokenSecret:      options.prometheusBearerTokenSecret,
+		PrometheusCASecret:               options.prometheusCASecret,
+		PrometheusFederationURLs:         strings.Join(options.prometheusFederationURLs, ","),
+		PrometheusRetention:              options.prometheusRetention,
+		PrometheusRemoteWriteURLs:        options.prometheusRemoteWriteURLs,
+		PrometheusRemoteWriteSecret:      options.prometheusRemoteWriteSecret,
 		ProxyInjectorTLSSecret:           k8s.ProxyInjectorTLSSecret,
+		ProxyInjectorFailurePolicy:       options.proxyInjectorFailurePolicy,
+		ProxyInjectorNamespaceSelector:   options.proxyInjectorNamespaceSelector,
+		ProxyNetworkPolicies:             options.proxyNetworkPolicies,
 		ProxySpecFileName:                k8s.ProxySpecFileName,
 		ProxyInitSpecFileName:            k8s.ProxyInitSpecFileName,
 		ProxyInitImage:                   options.taggedProxyInitImage(),
+		DebugSpecFileName:                k8s.DebugSpecFileName,
+		DebugContainerName:               k8s.DebugContainerName,
+		DebugImage:                       options.taggedDebugImage(),
 		ProxyImage:                       options.taggedProxyImage(),
 		ProxyResourceRequestCPU:          options.proxyCPURequest,
 		ProxyResourceRequestMemory:       options.proxyMemoryRequest,
 		ProxyBindTimeout:                 "1m",
+		ProxyLogLevel:                    options.proxyLogLevel,
 		SingleNamespace:                  options.singleNamespace,
+		SkipCRDs:                         options.skipCRDs,
 		EnableHA:                         options.highAvailability,
+		CALeaderElection:                 options.highAvailability && (tlsIssuerSecret != "" || tlsIssuerVault != nil),
+		ControllerAntiAffinity:           controllerAntiAffinity,
+		ControllerTopologyKeys:           options.controllerTopologyKeys,
+		ControllerMaxUnavailable:         controllerMaxUnavailable,
 		ProfileSuffixes:                  profileSuffixes,
 		EnableH2Upgrade:                  !options.disableH2Upgrade,
+		EnableNetworkPolicies:            options.networkPolicies,
+		EnableTopologyAwareRouting:       options.topologyRouting,
+		MetricPodLabels:                  strings.Join(options.metricPodLabels, ","),
+		MetricPodLabelNames:              strings.Join(metricPodLabelNames, "|"),
+		EventWebhookURL:                  options.eventWebhookURL,
+		AddOns:                           addOnValues,
 	}, nil
 }
 
@@ -210,11 +573,20 @@ func render(config installConfig, w io.Writer, options *installOptions) error {
 	if err != nil {
 		return err
 	}
+	if _, err := template.New("crds").Parse(install.CRDTemplate); err != nil {
+		return err
+	}
+	if _, err := template.New("placement").Parse(install.PlacementTemplate); err != nil {
+		return err
+	}
 	buf := &bytes.Buffer{}
 	err = template.Execute(buf, config)
 	if err != nil {
 		return err
 	}
+	if err := renderAddOns(config, buf); err != nil {
+		return err
+	}
 
 	if config.EnableTLS {
 		tlsTemplate, err := template.New("linkerd").Parse(install.TLSTemplate)
@@ -238,6 +610,17 @@ func render(config installConfig, w io.Writer, options *installOptions) error {
 		}
 	}
 
+	if config.EnableNetworkPolicies {
+		networkPolicyTemplate, err := template.New("linkerd").Parse(install.NetworkPolicyTemplate)
+		if err != nil {
+			return err
+		}
+		err = networkPolicyTemplate.Execute(buf, config)
+		if err != nil {
+			return err
+		}
+	}
+
 	injectOptions := newInjectOptions()
 	injectOptions.proxyConfigOptions = options.proxyConfigOptions
 
@@ -252,9 +635,276 @@ func (options *installOptions) validate() error {
 		return fmt.Errorf("--controller-log-level must be one of: panic, fatal, error, warn, info, debug")
 	}
 
+	if options.controllerLogFormat != flags.PlainLogFormat && options.controllerLogFormat != flags.JSONLogFormat {
+		return fmt.Errorf("--controller-log-format must be one of: %s, %s", flags.PlainLogFormat, flags.JSONLogFormat)
+	}
+
+	if options.snapshot && options.outputDir != "" {
+		return fmt.Errorf("The --snapshot and --output-dir flags cannot both be specified together")
+	}
+
 	if options.proxyAutoInject && options.singleNamespace {
 		return fmt.Errorf("The --proxy-auto-inject and --single-namespace flags cannot both be specified together")
 	}
 
+	if options.proxyInjectorFailurePolicy != "Ignore" && options.proxyInjectorFailurePolicy != "Fail" {
+		return fmt.Errorf("--proxy-injector-failure-policy must be one of: Ignore, Fail")
+	}
+
+	if options.proxyInjectorNamespaceSelector != k8s.ProxyInjectorNamespaceSelectorOptOut && options.proxyInjectorNamespaceSelector != k8s.ProxyInjectorNamespaceSelectorOptIn {
+		return fmt.Errorf("--proxy-injector-namespace-selector must be one of: %s, %s", k8s.ProxyInjectorNamespaceSelectorOptOut, k8s.ProxyInjectorNamespaceSelectorOptIn)
+	}
+
+	if options.proxyNetworkPolicies && !options.proxyAutoInject {
+		return fmt.Errorf("The --proxy-network-policies flag requires --proxy-auto-inject")
+	}
+
+	if options.tlsIssuerSecret != "" {
+		if !options.enableTLS() {
+			return fmt.Errorf("The --tls-issuer-secret flag requires --tls=optional")
+		}
+		if errs := validation.IsDNS1123Subdomain(options.tlsIssuerSecret); len(errs) > 0 {
+			return fmt.Errorf("Invalid value '%s' for --tls-issuer-secret flag: %s", options.tlsIssuerSecret, strings.Join(errs, "; "))
+		}
+	}
+
+	if options.tlsIssuerVault.Addr != "" {
+		if !options.enableTLS() {
+			return fmt.Errorf("The --tls-issuer-vault-addr flag requires --tls=optional")
+		}
+		if options.tlsIssuerSecret != "" {
+			return fmt.Errorf("The --tls-issuer-vault-addr and --tls-issuer-secret flags cannot both be specified together")
+		}
+		if options.tlsIssuerVault.PKIPath == "" || options.tlsIssuerVault.Role == "" || options.tlsIssuerVault.AuthPath == "" || options.tlsIssuerVault.AuthRole == "" {
+			return fmt.Errorf("The --tls-issuer-vault-addr flag requires the --tls-issuer-vault-pki-path, --tls-issuer-vault-role, --tls-issuer-vault-auth-path and --tls-issuer-vault-auth-role flags")
+		}
+		if options.tlsIssuerCertFile != "" {
+			return fmt.Errorf("The --tls-issuer-vault-addr and --tls-issuer-cert-file flags cannot both be specified together")
+		}
+	}
+
+	if (options.tlsIssuerCertFile == "") != (options.tlsIssuerKeyFile == "") {
+		return fmt.Errorf("The --tls-issuer-cert-file and --tls-issuer-key-file flags must be specified together")
+	}
+
+	if options.tlsIssuerCertFile != "" {
+		if !options.enableTLS() {
+			return fmt.Errorf("The --tls-issuer-cert-file flag requires --tls=optional")
+		}
+		if options.tlsIssuerSecret != "" {
+			return fmt.Errorf("The --tls-issuer-cert-file and --tls-issuer-secret flags cannot both be specified together")
+		}
+	}
+
+	if options.controllerAntiAffinity != antiAffinityNone && options.controllerAntiAffinity != antiAffinityPreferred && options.controllerAntiAffinity != antiAffinityRequired {
+		return fmt.Errorf("--controller-anti-affinity must be one of: %s, %s, %s", antiAffinityPreferred, antiAffinityRequired, antiAffinityNone)
+	}
+
+	if len(options.controllerTopologyKeys) == 0 && options.controllerAntiAffinity != antiAffinityNone {
+		return fmt.Errorf("--controller-topology-keys must not be empty unless --controller-anti-affinity=%s", antiAffinityNone)
+	}
+	for _, key := range options.controllerTopologyKeys {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("Invalid value '%s' for --controller-topology-keys flag: %s", key, strings.Join(errs, "; "))
+		}
+	}
+
+	if options.controllerMaxUnavailable == 0 {
+		return fmt.Errorf("--controller-max-unavailable must be at least 1, so that the nodes of the controller replicas can be drained")
+	}
+
+	if *options.addOns["flagger"] && options.singleNamespace {
+		return fmt.Errorf("The --flagger and --single-namespace flags cannot both be specified together")
+	}
+
+	if options.topologyRouting && options.singleNamespace {
+		return fmt.Errorf("The --topology-aware-routing and --single-namespace flags cannot both be specified together")
+	}
+
+	if options.tapAPIService && options.singleNamespace {
+		return fmt.Errorf("The --tap-api-service and --single-namespace flags cannot both be specified together")
+	}
+
+	for _, key := range options.metricPodLabels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("Invalid value '%s' for --metric-pod-labels flag: %s", key, strings.Join(errs, "; "))
+		}
+		if _, reserved := reservedMetricLabels[k8s.ToMetricLabelName(key)]; reserved || strings.HasPrefix(key, "linkerd.io/") {
+			return fmt.Errorf("Invalid value '%s' for --metric-pod-labels flag: the label is reserved for Linkerd's metrics", key)
+		}
+	}
+
+	if _, err := parseNodeSelector(options.nodeSelector); err != nil {
+		return err
+	}
+	if _, err := parseTolerations(options.tolerations); err != nil {
+		return err
+	}
+	if options.priorityClassName != "" {
+		if errs := validation.IsDNS1123Subdomain(options.priorityClassName); len(errs) > 0 {
+			return fmt.Errorf("Invalid value '%s' for --control-plane-priority-class-name flag: %s", options.priorityClassName, strings.Join(errs, "; "))
+		}
+	}
+
+	if options.apiAuth != "" {
+		if options.apiAuth != k8s.APIAuthTLS {
+			return fmt.Errorf("--api-auth must be blank or set to \"%s\"", k8s.APIAuthTLS)
+		}
+		if !options.enableTLS() {
+			return fmt.Errorf("The --api-auth flag requires --tls=optional")
+		}
+	}
+
+	if options.prometheusURL != "" {
+		u, err := url.Parse(options.prometheusURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("Invalid value '%s' for --prometheus-url flag: must be an absolute http or https URL", options.prometheusURL)
+		}
+		if options.prometheusRetention != defaultPrometheusRetention || len(options.prometheusRemoteWriteURLs) > 0 {
+			return fmt.Errorf("The --prometheus-retention and --prometheus-remote-write-url flags configure the bundled Prometheus, and cannot be specified with --prometheus-url")
+		}
+	}
+
+	if !prometheusDurationRegexp.MatchString(options.prometheusRetention) {
+		return fmt.Errorf("Invalid value '%s' for --prometheus-retention flag: must be a Prometheus duration, such as \"6h\" or \"15d\"", options.prometheusRetention)
+	}
+	for _, federationURL := range options.prometheusFederationURLs {
+		u, err := url.Parse(federationURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.Contains(federationURL, ",") {
+			return fmt.Errorf("Invalid value '%s' for --prometheus-federation-url flag: must be an absolute http or https URL", federationURL)
+		}
+	}
+	for _, remoteWriteURL := range options.prometheusRemoteWriteURLs {
+		u, err := url.Parse(remoteWriteURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("Invalid value '%s' for --prometheus-remote-write-url flag: must be an absolute http or https URL", remoteWriteURL)
+		}
+	}
+
+	if options.grafanaURL != "" {
+		u, err := url.Parse(options.grafanaURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("Invalid value '%s' for --grafana-url flag: must be an absolute http or https URL", options.grafanaURL)
+		}
+	}
+
+	if _, err := regexp.Compile(options.enforcedHost); err != nil {
+		return fmt.Errorf("Invalid value '%s' for --enforced-host flag: %s", options.enforcedHost, err)
+	}
+
+	if options.eventWebhookURL != "" {
+		u, err := url.Parse(options.eventWebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("Invalid value '%s' for --event-webhook-url flag: must be an absolute http or https URL", options.eventWebhookURL)
+		}
+	}
+
+	for _, secret := range []struct {
+		flag, name, requiredFlag string
+		requiredSet              bool
+	}{
+		{"--prometheus-bearer-token-secret", options.prometheusBearerTokenSecret, "--prometheus-url", options.prometheusURL != ""},
+		{"--prometheus-ca-secret", options.prometheusCASecret, "--prometheus-url", options.prometheusURL != ""},
+		{"--prometheus-remote-write-secret", options.prometheusRemoteWriteSecret, "--prometheus-remote-write-url", len(options.prometheusRemoteWriteURLs) > 0},
+	} {
+		if secret.name == "" {
+			continue
+		}
+		if !secret.requiredSet {
+			return fmt.Errorf("The %s flag requires %s", secret.flag, secret.requiredFlag)
+		}
+		if errs := validation.IsDNS1123Subdomain(secret.name); len(errs) > 0 {
+			return fmt.Errorf("Invalid value '%s' for %s flag: %s", secret.name, secret.flag, strings.Join(errs, "; "))
+		}
+	}
+
 	return options.proxyConfigOptions.validate()
 }
+
+// readTLSIssuer reads the issuer certificate and ECDSA P-256 private key from
+// the given PEM files, and checks that they can issue certificates. It returns
+// them re-encoded, so that the CA gets them in the formats it expects.
+func readTLSIssuer(certFile, keyFile string) ([]byte, []byte, error) {
+	certPEM, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := tls.DecodePEMCert(certPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid --tls-issuer-cert-file %s: %s", certFile, err)
+	}
+
+	keyPEM, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := tls.DecodePEMECDSAKey(keyPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid --tls-issuer-key-file %s: %s", keyFile, err)
+	}
+
+	if err := tls.ValidateECDSAIssuer(cert, key); err != nil {
+		return nil, nil, fmt.Errorf("Invalid TLS issuer: %s", err)
+	}
+
+	certPEM, err = tls.PEMEncodeCert(cert.Raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err = tls.EncodeECDSAKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return certPEM, keyPEM, nil
+}
+
+// parseNodeSelector returns the node selector of the control plane pods, from
+// the values of --control-plane-node-selector.
+func parseNodeSelector(values []string) (map[string]string, error) {
+	nodeSelector := map[string]string{}
+	for _, value := range values {
+		kv := strings.SplitN(value, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("Invalid value '%s' for --control-plane-node-selector flag: must be of the form <key>=<value>", value)
+		}
+		errs := append(validation.IsQualifiedName(kv[0]), validation.IsValidLabelValue(kv[1])...)
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("Invalid value '%s' for --control-plane-node-selector flag: %s", value, strings.Join(errs, "; "))
+		}
+		nodeSelector[kv[0]] = kv[1]
+	}
+	return nodeSelector, nil
+}
+
+// parseTolerations returns the tolerations of the control plane pods, from the
+// values of --control-plane-toleration.
+func parseTolerations(values []string) ([]v1.Toleration, error) {
+	tolerations := []v1.Toleration{}
+	for _, value := range values {
+		toleration := v1.Toleration{Operator: v1.TolerationOpExists}
+		keyValue := value
+		if i := strings.LastIndex(value, ":"); i >= 0 {
+			keyValue = value[:i]
+			toleration.Effect = v1.TaintEffect(value[i+1:])
+			switch toleration.Effect {
+			case v1.TaintEffectNoSchedule, v1.TaintEffectPreferNoSchedule, v1.TaintEffectNoExecute:
+			default:
+				return nil, fmt.Errorf("Invalid value '%s' for --control-plane-toleration flag: the effect must be one of: %s, %s, %s", value, v1.TaintEffectNoSchedule, v1.TaintEffectPreferNoSchedule, v1.TaintEffectNoExecute)
+			}
+		}
+
+		kv := strings.SplitN(keyValue, "=", 2)
+		toleration.Key = kv[0]
+		errs := validation.IsQualifiedName(toleration.Key)
+		if len(kv) == 2 {
+			toleration.Operator = v1.TolerationOpEqual
+			toleration.Value = kv[1]
+			errs = append(errs, validation.IsValidLabelValue(toleration.Value)...)
+		}
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("Invalid value '%s' for --control-plane-toleration flag: %s", value, strings.Join(errs, "; "))
+		}
+		tolerations = append(tolerations, toleration)
+	}
+	return tolerations, nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// installComponents are the control plane components that `linkerd install
+// --output-dir` writes separate files for, matched against the names of the
+// resources that don't have the ControllerComponentLabel.
+var installComponents = []string{"proxy-injector", "controller", "ca", "web", "prometheus", "grafana", "collector", "jaeger", "flagger"}
+
+// installResource provides a generic struct to parse the kind, name and labels
+// of the rendered resources.
+type installResource struct {
+	metaV1.TypeMeta   `json:",inline"`
+	metaV1.ObjectMeta `json:"metadata,omitempty"`
+}
+
+// writeInstallDir splits the install manifest rendered with config in one
+// file per control plane component in dir, in the order in which the
+// components first appear, along with a kustomization.yaml that lists them in
+// that order.
+func writeInstallDir(config installConfig, rendered io.Reader, dir string) error {
+	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(rendered, 4096))
+
+	components := []string{}
+	docs := map[string]*bytes.Buffer{}
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		doc = bytes.TrimPrefix(bytes.TrimSpace(doc), []byte("---"))
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		component, err := installComponent(config, doc)
+		if err != nil {
+			return err
+		}
+		buf, ok := docs[component]
+		if !ok {
+			buf = &bytes.Buffer{}
+			docs[component] = buf
+			components = append(components, component)
+		}
+		buf.WriteString("---\n")
+		buf.Write(bytes.TrimLeft(doc, "\n"))
+		buf.WriteString("\n")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	kustomization := &bytes.Buffer{}
+	kustomization.WriteString("apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n")
+	for _, component := range components {
+		file := component + ".yaml"
+		if err := ioutil.WriteFile(filepath.Join(dir, file), docs[component].Bytes(), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(kustomization, "- %s\n", file)
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "kustomization.yaml"), kustomization.Bytes(), 0644)
+}
+
+// installComponent returns the name of the control plane component that the
+// rendered resource belongs to. The resources that aren't specific to a
+// component, such as the namespace, get a file of their own.
+func installComponent(config installConfig, doc []byte) (string, error) {
+	var resource installResource
+	if err := yaml.Unmarshal(doc, &resource); err != nil {
+		return "", err
+	}
+
+	switch resource.Kind {
+	case "Namespace":
+		return "namespace", nil
+	case "CustomResourceDefinition":
+		return "crds", nil
+	case "NetworkPolicy":
+		return "network-policies", nil
+	}
+
+	if component, ok := resource.Labels[config.ControllerComponentLabel]; ok {
+		return component, nil
+	}
+
+	// the cluster-wide resources are named after the control plane namespace
+	name := strings.TrimPrefix(resource.Name, "linkerd-")
+	name = strings.TrimPrefix(name, config.Namespace+"-")
+	switch {
+	case name == "proxy-api":
+		return "controller", nil
+	case config.TLSIssuerSecret != "" && resource.Name == config.TLSIssuerSecret:
+		return "ca", nil
+	}
+	for _, component := range installComponents {
+		if name == component || strings.HasPrefix(name, component+"-") {
+			return component, nil
+		}
+	}
+	return "", fmt.Errorf("%s \"%s\" doesn't belong to any control plane component", resource.Kind, resource.Name)
+}
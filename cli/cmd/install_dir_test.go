@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+func TestWriteInstallDir(t *testing.T) {
+	defaultConfig := installConfig{
+		Namespace:                controlPlaneNamespace,
+		ControllerComponentLabel: k8s.ControllerComponentLabel,
+	}
+	metaConfig := installConfig{
+		Namespace:                "Namespace",
+		ControllerComponentLabel: "ControllerComponentLabel",
+		TLSIssuerSecret:          "TLSIssuerSecret",
+	}
+
+	testCases := []struct {
+		goldenFileName string
+		config         installConfig
+		resources      []string
+	}{
+		{
+			"testdata/install_default.golden",
+			defaultConfig,
+			[]string{"namespace", "controller", "prometheus", "crds", "web", "grafana"},
+		},
+		{
+			"testdata/install_output.golden",
+			metaConfig,
+			[]string{"namespace", "controller", "prometheus", "crds", "web", "grafana", "ca", "proxy-injector"},
+		},
+		{
+			"testdata/install_network_policies_output.golden",
+			defaultConfig,
+			[]string{"namespace", "controller", "prometheus", "crds", "web", "grafana", "ca", "proxy-injector", "network-policies"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.goldenFileName, func(t *testing.T) {
+			rendered, err := ioutil.ReadFile(tc.goldenFileName)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			dir, err := ioutil.TempDir("", "linkerd-install")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			if err := writeInstallDir(tc.config, bytes.NewReader(rendered), dir); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			kustomization, err := ioutil.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected := "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n"
+			for _, resource := range tc.resources {
+				expected += "- " + resource + ".yaml\n"
+			}
+			diffCompare(t, string(kustomization), expected)
+
+			// every resource is written exactly once
+			kinds := 0
+			for _, resource := range tc.resources {
+				content, err := ioutil.ReadFile(filepath.Join(dir, resource+".yaml"))
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				kinds += strings.Count(string(content), "\nkind: ")
+			}
+			if expected := strings.Count(string(rendered), "\nkind: "); kinds != expected {
+				t.Fatalf("Expected %d resources to be written, got %d", expected, kinds)
+			}
+		})
+	}
+}
+
+func TestInstallComponent(t *testing.T) {
+	config := installConfig{
+		Namespace:                "linkerd",
+		ControllerComponentLabel: k8s.ControllerComponentLabel,
+		TLSIssuerSecret:          "my-issuer",
+	}
+
+	testCases := []struct {
+		doc       string
+		component string
+		err       string
+	}{
+		{"kind: ClusterRole\nmetadata:\n  name: linkerd-linkerd-prometheus", "prometheus", ""},
+		{"kind: Service\nmetadata:\n  name: linkerd-proxy-api", "controller", ""},
+		{"kind: ConfigMap\nmetadata:\n  name: linkerd-proxy-injector-sidecar-config", "proxy-injector", ""},
+		{"kind: Deployment\nmetadata:\n  name: my-ca\n  labels:\n    linkerd.io/control-plane-component: ca", "ca", ""},
+		{"kind: Secret\nmetadata:\n  name: my-issuer", "ca", ""},
+		{"kind: ConfigMap\nmetadata:\n  name: unknown", "", "ConfigMap \"unknown\" doesn't belong to any control plane component"},
+	}
+
+	for _, tc := range testCases {
+		component, err := installComponent(config, []byte(tc.doc))
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error %q, got: %v", tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if component != tc.component {
+			t.Fatalf("Expected component %s for %q, got %s", tc.component, tc.doc, component)
+		}
+	}
+}
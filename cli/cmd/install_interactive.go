@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// noneAnswer clears the value of a setting at an interactive prompt.
+const noneAnswer = "none"
+
+var errInstallAborted = errors.New("The interactive install was aborted before all the settings were answered")
+
+// shellSafeRegexp matches the flag values that don't need quoting in a shell.
+var shellSafeRegexp = regexp.MustCompile(`^[A-Za-z0-9_./:=,@+-]+$`)
+
+// installPrompter asks the questions of `linkerd install --interactive`.
+type installPrompter struct {
+	reader *bufio.Reader
+	out    io.Writer
+}
+
+// installInteractive prompts for the main settings of the control plane,
+// starting from the values of the flags, and sets the flags to the answers.
+// Each answer is validated before moving to the next question. It then prints
+// the `linkerd install` command that renders the same manifest.
+func installInteractive(in io.Reader, out io.Writer, flags *pflag.FlagSet, options *installOptions) error {
+	if err := validateInteractive(options); err != nil {
+		return err
+	}
+
+	p := &installPrompter{reader: bufio.NewReader(in), out: out}
+	steps := []func(*installPrompter, *pflag.FlagSet, *installOptions) error{
+		promptHA,
+		promptCNI,
+		promptPrometheus,
+		promptTLS,
+		promptRegistry,
+	}
+	for _, step := range steps {
+		if err := step(p, flags, options); err != nil {
+			return err
+		}
+	}
+
+	command := strings.Join(append([]string{"linkerd install"}, equivalentInstallFlags(flags)...), " ")
+	fmt.Fprintf(out, "\nThe same manifest can be rendered without prompts with:\n  %s\n\n", command)
+	return nil
+}
+
+func promptHA(p *installPrompter, flags *pflag.FlagSet, options *installOptions) error {
+	for {
+		ha, err := p.confirm("Deploy the control plane in high availability mode, with replicated controllers kept on separate nodes?", options.highAvailability)
+		if err != nil {
+			return err
+		}
+		if p.set(flags, options, [2]string{"ha", fmt.Sprintf("%t", ha)}) {
+			return nil
+		}
+	}
+}
+
+func promptCNI(p *installPrompter, _ *pflag.FlagSet, _ *installOptions) error {
+	fmt.Fprintln(p.out, "The iptables rules of the meshed pods are set up by their linkerd-init container, which requires the NET_ADMIN capability; a CNI plugin isn't available to do it instead.")
+	return nil
+}
+
+func promptPrometheus(p *installPrompter, flags *pflag.FlagSet, options *installOptions) error {
+	for {
+		url, err := p.ask(fmt.Sprintf("URL of an existing Prometheus to use instead of installing the bundled one (%q for the bundled one)", noneAnswer), options.prometheusURL)
+		if err != nil {
+			return err
+		}
+		if p.set(flags, options, [2]string{"prometheus-url", url}) {
+			return nil
+		}
+	}
+}
+
+func promptTLS(p *installPrompter, flags *pflag.FlagSet, options *installOptions) error {
+	for {
+		enable, err := p.confirm("Enable TLS between the meshed pods?", options.enableTLS())
+		if err != nil {
+			return err
+		}
+		tls := ""
+		if enable {
+			tls = optionalTLS
+		}
+		if p.set(flags, options, [2]string{"tls", tls}) {
+			break
+		}
+	}
+
+	// An issuer managed in a secret, such as by cert-manager, replaces the
+	// trust anchors in the files.
+	if !options.enableTLS() || options.tlsIssuerSecret != "" {
+		return nil
+	}
+
+	for {
+		certFile, err := p.ask(fmt.Sprintf("Path to the PEM-encoded certificate of an existing trust anchor, for the CA to sign the proxy certificates with (%q to generate one)", noneAnswer), options.tlsIssuerCertFile)
+		if err != nil {
+			return err
+		}
+		keyFile := ""
+		if certFile != "" {
+			keyFile, err = p.ask("Path to its PEM-encoded ECDSA P-256 private key", options.tlsIssuerKeyFile)
+			if err != nil {
+				return err
+			}
+		}
+		if p.set(flags, options, [2]string{"tls-issuer-cert-file", certFile}, [2]string{"tls-issuer-key-file", keyFile}) {
+			return nil
+		}
+	}
+}
+
+func promptRegistry(p *installPrompter, flags *pflag.FlagSet, options *installOptions) error {
+	for {
+		registry, err := p.ask("Docker registry to pull the images from", options.dockerRegistry)
+		if err != nil {
+			return err
+		}
+		if registry == "" {
+			registry = defaultDockerRegistry
+		}
+		if p.set(flags, options, [2]string{"registry", registry}) {
+			return nil
+		}
+	}
+}
+
+// ask prompts for a value, which defaults to current. Answering noneAnswer
+// returns an empty value.
+func (p *installPrompter) ask(question, current string) (string, error) {
+	if current == "" {
+		fmt.Fprintf(p.out, "%s: ", question)
+	} else {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, current)
+	}
+
+	answer, err := p.reader.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if err != nil && answer == "" {
+		return "", errInstallAborted
+	}
+	switch answer {
+	case "":
+		return current, nil
+	case noneAnswer:
+		return "", nil
+	default:
+		return answer, nil
+	}
+}
+
+// confirm prompts for a yes or no answer, which defaults to current.
+func (p *installPrompter) confirm(question string, current bool) (bool, error) {
+	choices := "y/N"
+	if current {
+		choices = "Y/n"
+	}
+
+	for {
+		fmt.Fprintf(p.out, "%s [%s] ", question, choices)
+		answer, err := p.reader.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if err != nil && answer == "" {
+			return false, errInstallAborted
+		}
+		switch answer {
+		case "":
+			return current, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "  Please answer yes or no")
+	}
+}
+
+// set sets the flags to the answered values, and keeps them if the options
+// are still valid. Otherwise it prints the validation error and restores the
+// previous values, so that the question can be asked again.
+func (p *installPrompter) set(flags *pflag.FlagSet, options *installOptions, values ...[2]string) bool {
+	previous := make([][2]string, 0, len(values))
+	for _, value := range values {
+		previous = append(previous, [2]string{value[0], flags.Lookup(value[0]).Value.String()})
+	}
+
+	err := setFlags(flags, values)
+	if err == nil {
+		err = validateInteractive(options)
+	}
+	if err == nil {
+		return true
+	}
+
+	fmt.Fprintf(p.out, "  %s\n", err)
+	if err := setFlags(flags, previous); err != nil {
+		fmt.Fprintf(p.out, "  %s\n", err)
+	}
+	return false
+}
+
+func setFlags(flags *pflag.FlagSet, values [][2]string) error {
+	for _, value := range values {
+		if err := flags.Set(value[0], value[1]); err != nil {
+			return fmt.Errorf("Invalid value '%s' for --%s flag: %s", value[1], value[0], err)
+		}
+	}
+	return nil
+}
+
+// validateInteractive checks the options like validateAndBuildConfig does,
+// including the issuer files, without building the config.
+func validateInteractive(options *installOptions) error {
+	if err := options.validate(); err != nil {
+		return err
+	}
+	if options.tlsIssuerCertFile != "" {
+		if _, _, err := readTLSIssuer(options.tlsIssuerCertFile, options.tlsIssuerKeyFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// equivalentInstallFlags returns the flags that differ from their defaults,
+// other than --interactive, in a form that can be pasted in a shell.
+func equivalentInstallFlags(flags *pflag.FlagSet) []string {
+	return installFlagArgs(flags, shellQuote, "interactive")
+}
+
+// installFlagArgs returns the flags that differ from their defaults, other
+// than the skipped ones, as arguments whose values are quoted with quote.
+func installFlagArgs(flags *pflag.FlagSet, quote func(string) string, skip ...string) []string {
+	args := []string{}
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Value.String() == flag.DefValue {
+			return
+		}
+		for _, name := range skip {
+			if flag.Name == name {
+				return
+			}
+		}
+
+		switch flag.Value.Type() {
+		case "bool":
+			if flag.Value.String() == "true" {
+				args = append(args, "--"+flag.Name)
+			} else {
+				args = append(args, "--"+flag.Name+"=false")
+			}
+		case "stringArray":
+			values, _ := flags.GetStringArray(flag.Name)
+			for _, value := range values {
+				args = append(args, "--"+flag.Name+"="+quote(value))
+			}
+		case "stringSlice":
+			values, _ := flags.GetStringSlice(flag.Name)
+			args = append(args, "--"+flag.Name+"="+quote(strings.Join(values, ",")))
+		default:
+			args = append(args, "--"+flag.Name+"="+quote(flag.Value.String()))
+		}
+	})
+	return args
+}
+
+func shellQuote(value string) string {
+	if shellSafeRegexp.MatchString(value) {
+		return value
+	}
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
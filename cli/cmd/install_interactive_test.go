@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestInstallInteractive(t *testing.T) {
+	newFlags := func() (*cobra.Command, *installOptions) {
+		options := newInstallOptions()
+		cmd := &cobra.Command{}
+		addInstallFlags(cmd, options)
+		return cmd, options
+	}
+
+	t.Run("Sets the validated answers and prints the equivalent command", func(t *testing.T) {
+		cmd, options := newFlags()
+		answers := strings.Join([]string{
+			"maybe",
+			"y",
+			"ftp://prometheus.monitoring:9090",
+			"http://prometheus.monitoring:9090",
+			"y",
+			"/nonexistent/ca.crt",
+			"/nonexistent/ca.key",
+			"none",
+			"registry.example.com/linkerd",
+		}, "\n") + "\n"
+
+		out := &bytes.Buffer{}
+		if err := installInteractive(strings.NewReader(answers), out, cmd.PersistentFlags(), options); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if !options.highAvailability {
+			t.Fatal("Expected HA to be enabled")
+		}
+		if options.prometheusURL != "http://prometheus.monitoring:9090" {
+			t.Fatalf("Unexpected Prometheus URL: %s", options.prometheusURL)
+		}
+		if !options.enableTLS() {
+			t.Fatal("Expected TLS to be enabled")
+		}
+		if options.tlsIssuerCertFile != "" || options.tlsIssuerKeyFile != "" {
+			t.Fatalf("Expected the invalid issuer files to be cleared, got %s and %s", options.tlsIssuerCertFile, options.tlsIssuerKeyFile)
+		}
+		if options.dockerRegistry != "registry.example.com/linkerd" {
+			t.Fatalf("Unexpected registry: %s", options.dockerRegistry)
+		}
+
+		for _, expected := range []string{
+			"  Please answer yes or no\n",
+			"  Invalid value 'ftp://prometheus.monitoring:9090' for --prometheus-url flag: must be an absolute http or https URL\n",
+			"  open /nonexistent/ca.crt: no such file or directory\n",
+			"  linkerd install --ha --prometheus-url=http://prometheus.monitoring:9090 --registry=registry.example.com/linkerd --tls=optional\n",
+		} {
+			if !strings.Contains(out.String(), expected) {
+				t.Fatalf("Expected the output to contain %q, got:\n%s", expected, out.String())
+			}
+		}
+	})
+
+	t.Run("Starts from the values of the flags", func(t *testing.T) {
+		cmd, options := newFlags()
+		flags := cmd.PersistentFlags()
+		for name, value := range map[string]string{
+			"controller-replicas": "5",
+			"metric-pod-labels":   "version,team",
+			"tls":                 "optional",
+			"tls-issuer-secret":   "linkerd-issuer",
+		} {
+			if err := flags.Set(name, value); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		}
+
+		out := &bytes.Buffer{}
+		if err := installInteractive(strings.NewReader("\n\n\n\n"), out, flags, options); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := "  linkerd install --controller-replicas=5 --metric-pod-labels=version,team --tls=optional --tls-issuer-secret=linkerd-issuer\n"
+		if !strings.Contains(out.String(), expected) {
+			t.Fatalf("Expected the output to contain %q, got:\n%s", expected, out.String())
+		}
+	})
+
+	t.Run("Fails when the answers run out", func(t *testing.T) {
+		cmd, options := newFlags()
+		err := installInteractive(strings.NewReader("y\n"), &bytes.Buffer{}, cmd.PersistentFlags(), options)
+		if err != errInstallAborted {
+			t.Fatalf("Expected %q, got: %v", errInstallAborted, err)
+		}
+	})
+
+	t.Run("Fails when the flags are invalid", func(t *testing.T) {
+		cmd, options := newFlags()
+		options.controllerLogFormat = "xml"
+		err := installInteractive(strings.NewReader(""), &bytes.Buffer{}, cmd.PersistentFlags(), options)
+		if err == nil {
+			t.Fatal("Expected an error for the invalid flags")
+		}
+	})
+}
+
+func TestShellQuote(t *testing.T) {
+	for value, expected := range map[string]string{
+		"gcr.io/linkerd-io": "gcr.io/linkerd-io",
+		"a b":               "'a b'",
+		"it's":              `'it'\''s'`,
+	} {
+		if quoted := shellQuote(value); quoted != expected {
+			t.Fatalf("Expected %s to be quoted as %s, got %s", value, expected, quoted)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/ghodss/yaml"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// snapshotUUID replaces the random install UUID in the manifests printed by
+// `linkerd install --snapshot`, so that they only change with the
+// configuration.
+const snapshotUUID = "00000000-0000-0000-0000-000000000000"
+
+// writeInstallSnapshot writes the rendered install manifest to w, normalized
+// for diffing against a previous snapshot: each resource is re-serialized with
+// sorted keys, without the fields that Kubernetes sets, such as
+// creationTimestamp, and with the values of secrets replaced by their
+// digests. The resources are kept in the order in which they're rendered,
+// which is stable across runs.
+func writeInstallSnapshot(rendered io.Reader, w io.Writer) error {
+	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(rendered, 4096))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var resource map[string]interface{}
+		if err := yaml.Unmarshal(doc, &resource); err != nil {
+			return err
+		}
+		if resource == nil {
+			continue
+		}
+
+		normalized, err := normalizeUpgradeResource(resource)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "---\n%s", normalized)
+	}
+}
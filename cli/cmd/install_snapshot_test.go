@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteInstallSnapshot(t *testing.T) {
+	t.Run("Renders the same snapshot every time", func(t *testing.T) {
+		snapshot := func() string {
+			options := newInstallOptions()
+			config, err := validateAndBuildConfig(options)
+			if err != nil {
+				t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+			}
+			config.UUID = snapshotUUID
+
+			rendered := &bytes.Buffer{}
+			if err := render(*config, rendered, options); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			buf := &bytes.Buffer{}
+			if err := writeInstallSnapshot(rendered, buf); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			return buf.String()
+		}
+
+		first := snapshot()
+		if strings.Contains(first, "creationTimestamp") {
+			t.Fatalf("Expected the creation timestamps to be removed, got:\n%s", first)
+		}
+		if !strings.Contains(first, "-uuid="+snapshotUUID) {
+			t.Fatalf("Expected the UUID to be %s, got:\n%s", snapshotUUID, first)
+		}
+		diffCompare(t, snapshot(), first)
+	})
+
+	t.Run("Normalizes the resources", func(t *testing.T) {
+		rendered := `---
+kind: Secret
+apiVersion: v1
+metadata:
+  name: linkerd-ca-issuer
+  namespace: linkerd
+data:
+  tls.key: a2V5
+---
+---
+kind: Deployment
+apiVersion: extensions/v1beta1
+metadata:
+  name: linkerd-ca
+  creationTimestamp: null
+spec:
+  template:
+    metadata:
+      creationTimestamp: null
+      labels:
+        linkerd.io/control-plane-component: ca
+status: {}
+`
+		expected := `---
+apiVersion: v1
+data:
+  tls.key: <redacted, sha256 aedbcece349eeb04>
+kind: Secret
+metadata:
+  name: linkerd-ca-issuer
+  namespace: linkerd
+---
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: linkerd-ca
+spec:
+  template:
+    metadata:
+      labels:
+        linkerd.io/control-plane-component: ca
+`
+
+		buf := &bytes.Buffer{}
+		if err := writeInstallSnapshot(strings.NewReader(rendered), buf); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		diffCompare(t, buf.String(), expected)
+	})
+}
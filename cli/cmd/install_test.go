@@ -2,9 +2,19 @@ package cmd
 
 import (
 	"bytes"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
 	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/tls"
 )
 
 func TestRender(t *testing.T) {
@@ -28,13 +38,13 @@ func TestRender(t *testing.T) {
 		WebImage:                         "WebImage",
 		PrometheusImage:                  "PrometheusImage",
 		PrometheusVolumeName:             "data",
-		GrafanaImage:                     "GrafanaImage",
 		GrafanaVolumeName:                "data",
 		ControllerReplicas:               1,
 		ImagePullPolicy:                  "ImagePullPolicy",
 		UUID:                             "UUID",
 		CliVersion:                       "CliVersion",
 		ControllerLogLevel:               "ControllerLogLevel",
+		ControllerLogFormat:              "ControllerLogFormat",
 		ControllerComponentLabel:         "ControllerComponentLabel",
 		CreatedByAnnotation:              "CreatedByAnnotation",
 		ProxyAPIPort:                     123,
@@ -44,8 +54,13 @@ func TestRender(t *testing.T) {
 		TLSTrustAnchorFileName:           "TLSTrustAnchorFileName",
 		TLSCertFileName:                  "TLSCertFileName",
 		TLSPrivateKeyFileName:            "TLSPrivateKeyFileName",
+		TLSCertPEMFileName:               "TLSCertPEMFileName",
+		TLSPrivateKeyPEMFileName:         "TLSPrivateKeyPEMFileName",
 		TLSTrustAnchorVolumeSpecFileName: "TLSTrustAnchorVolumeSpecFileName",
 		TLSIdentityVolumeSpecFileName:    "TLSIdentityVolumeSpecFileName",
+		TLSIssuerSecret:                  "TLSIssuerSecret",
+		TLSIssuerCertificate:             "TLSIssuerCertificate",
+		TLSIssuerKey:                     "TLSIssuerKey",
 		ProxyAutoInjectEnabled:           true,
 		ProxyAutoInjectLabel:             "ProxyAutoInjectLabel",
 		ProxyUID:                         2102,
@@ -57,15 +72,30 @@ func TestRender(t *testing.T) {
 		ProxyInitImage:                   "ProxyInitImage",
 		ProxyImage:                       "ProxyImage",
 		ProxyInjectorTLSSecret:           "ProxyInjectorTLSSecret",
+		ProxyInjectorFailurePolicy:       "ProxyInjectorFailurePolicy",
+		ProxyInjectorNamespaceSelector:   "ProxyInjectorNamespaceSelector",
 		ProxySpecFileName:                "ProxySpecFileName",
 		ProxyInitSpecFileName:            "ProxyInitSpecFileName",
+		DebugSpecFileName:                "DebugSpecFileName",
+		DebugContainerName:               "DebugContainerName",
+		DebugImage:                       "DebugImage",
 		IgnoreInboundPorts:               "4190,4191,1,2,3",
 		IgnoreOutboundPorts:              "2,3,4",
 		ProxyResourceRequestCPU:          "RequestCPU",
 		ProxyResourceRequestMemory:       "RequestMemory",
 		ProxyBindTimeout:                 "1m",
+		ProxyLogLevel:                    "ProxyLogLevel",
+		ControllerAntiAffinity:           "required",
+		ControllerTopologyKeys:           []string{"ControllerTopologyKey"},
+		ControllerMaxUnavailable:         1,
+		CALeaderElection:                 true,
 		ProfileSuffixes:                  "suffix.",
 		EnableH2Upgrade:                  true,
+		MetricPodLabels:                  "MetricPodLabels",
+		MetricPodLabelNames:              "MetricPodLabelNames",
+		PrometheusRetention:              "PrometheusRetention",
+		AddOns:                           map[string]map[string]string{"grafana": {"image": "GrafanaImage"}},
+		LinkerdConfig:                    "LinkerdConfig",
 	}
 
 	singleNamespaceConfig := installConfig{
@@ -74,13 +104,13 @@ func TestRender(t *testing.T) {
 		WebImage:                         "WebImage",
 		PrometheusImage:                  "PrometheusImage",
 		PrometheusVolumeName:             "data",
-		GrafanaImage:                     "GrafanaImage",
 		GrafanaVolumeName:                "data",
 		ControllerReplicas:               1,
 		ImagePullPolicy:                  "ImagePullPolicy",
 		UUID:                             "UUID",
 		CliVersion:                       "CliVersion",
 		ControllerLogLevel:               "ControllerLogLevel",
+		ControllerLogFormat:              "ControllerLogFormat",
 		ControllerComponentLabel:         "ControllerComponentLabel",
 		CreatedByAnnotation:              "CreatedByAnnotation",
 		ProxyAPIPort:                     123,
@@ -96,6 +126,9 @@ func TestRender(t *testing.T) {
 		TLSIdentityVolumeSpecFileName:    "TLSIdentityVolumeSpecFileName",
 		SingleNamespace:                  true,
 		EnableH2Upgrade:                  true,
+		PrometheusRetention:              "6h",
+		AddOns:                           map[string]map[string]string{"grafana": {"image": "GrafanaImage"}},
+		LinkerdConfig:                    "LinkerdConfig",
 	}
 
 	haOptions := newInstallOptions()
@@ -108,9 +141,33 @@ func TestRender(t *testing.T) {
 	haWithOverridesOptions.controllerReplicas = 2
 	haWithOverridesOptions.proxyCPURequest = "400m"
 	haWithOverridesOptions.proxyMemoryRequest = "300Mi"
+	haWithOverridesOptions.controllerTopologyKeys = []string{"kubernetes.io/hostname", "failure-domain.beta.kubernetes.io/zone"}
+	haWithOverridesOptions.controllerAntiAffinity = "required"
 	haWithOverridesConfig, _ := validateAndBuildConfig(haWithOverridesOptions)
 	haWithOverridesConfig.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
 
+	networkPoliciesOptions := newInstallOptions()
+	networkPoliciesOptions.networkPolicies = true
+	networkPoliciesOptions.tls = optionalTLS
+	networkPoliciesOptions.proxyAutoInject = true
+	networkPoliciesConfig, _ := validateAndBuildConfig(networkPoliciesOptions)
+	networkPoliciesConfig.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+
+	externalPrometheusOptions := newInstallOptions()
+	externalPrometheusOptions.prometheusURL = "https://prometheus.monitoring.svc.cluster.local:9090"
+	externalPrometheusOptions.prometheusBearerTokenSecret = "prometheus-token"
+	externalPrometheusOptions.prometheusCASecret = "prometheus-ca"
+	externalPrometheusOptions.networkPolicies = true
+	externalPrometheusConfig, _ := validateAndBuildConfig(externalPrometheusOptions)
+	externalPrometheusConfig.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+
+	remoteWriteOptions := newInstallOptions()
+	remoteWriteOptions.prometheusRetention = "2d"
+	remoteWriteOptions.prometheusRemoteWriteURLs = []string{"http://thanos-receive.monitoring.svc.cluster.local:19291/api/v1/receive", "https://cortex.example.com/api/prom/push"}
+	remoteWriteOptions.prometheusRemoteWriteSecret = "prometheus-remote-write"
+	remoteWriteConfig, _ := validateAndBuildConfig(remoteWriteOptions)
+	remoteWriteConfig.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+
 	testCases := []struct {
 		config                installConfig
 		options               *installOptions
@@ -122,6 +179,9 @@ func TestRender(t *testing.T) {
 		{singleNamespaceConfig, defaultOptions, singleNamespaceConfig.Namespace, "testdata/install_single_namespace_output.golden"},
 		{*haConfig, haOptions, haConfig.Namespace, "testdata/install_ha_output.golden"},
 		{*haWithOverridesConfig, haWithOverridesOptions, haWithOverridesConfig.Namespace, "testdata/install_ha_with_overrides_output.golden"},
+		{*networkPoliciesConfig, networkPoliciesOptions, networkPoliciesConfig.Namespace, "testdata/install_network_policies_output.golden"},
+		{*externalPrometheusConfig, externalPrometheusOptions, externalPrometheusConfig.Namespace, "testdata/install_external_prometheus_output.golden"},
+		{*remoteWriteConfig, remoteWriteOptions, remoteWriteConfig.Namespace, "testdata/install_prometheus_remote_write_output.golden"},
 	}
 
 	for i, tc := range testCases {
@@ -145,6 +205,263 @@ func TestRender(t *testing.T) {
 	}
 }
 
+func TestRenderEventWebhook(t *testing.T) {
+	options := newInstallOptions()
+	options.tls = optionalTLS
+	options.proxyAutoInject = true
+	options.eventWebhookURL = "https://hooks.example.com/linkerd"
+	config, err := validateAndBuildConfig(options)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := render(*config, &buf, options); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// the public API, the CA and the proxy injector post events
+	arg := "- -event-webhook-url=https://hooks.example.com/linkerd"
+	if count := strings.Count(buf.String(), arg); count != 3 {
+		t.Fatalf("Expected the %s arg in 3 containers, got %d", arg, count)
+	}
+}
+
+func TestRenderTracing(t *testing.T) {
+	options := newInstallOptions()
+	*options.addOns["tracing"] = true
+	options.networkPolicies = true
+	config, err := validateAndBuildConfig(options)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := render(*config, &buf, options); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{
+		"name: linkerd-collector\n",
+		"name: linkerd-jaeger\n",
+		fmt.Sprintf("collector-endpoint: http://linkerd-jaeger.%s.svc.cluster.local:14268/api/traces", config.Namespace),
+		"kind: NetworkPolicy\napiVersion: networking.k8s.io/v1\nmetadata:\n  name: linkerd-collector\n",
+		"kind: NetworkPolicy\napiVersion: networking.k8s.io/v1\nmetadata:\n  name: linkerd-jaeger\n",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Fatalf("Expected the manifest to contain %q", expected)
+		}
+	}
+	// the add-on's components are meshed like the rest of the control plane
+	if count := strings.Count(buf.String(), "image: gcr.io/linkerd-io/proxy:"); count != 6 {
+		t.Fatalf("Expected 6 injected proxies, got %d", count)
+	}
+}
+
+func TestRenderAddOns(t *testing.T) {
+	t.Run("Doesn't render the disabled add-ons", func(t *testing.T) {
+		options := newInstallOptions()
+		*options.addOns["grafana"] = false
+		options.networkPolicies = true
+		config, err := validateAndBuildConfig(options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		if err := render(*config, &buf, options); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, unexpected := range []string{"### Grafana ###", "name: linkerd-grafana\n", "name: linkerd-flagger\n"} {
+			if strings.Contains(buf.String(), unexpected) {
+				t.Fatalf("Expected the manifest not to contain %q", unexpected)
+			}
+		}
+	})
+
+	t.Run("Renders the enabled add-ons with their values", func(t *testing.T) {
+		options := newInstallOptions()
+		*options.addOns["flagger"] = true
+		options.addOnValues = []string{"flagger.logLevel=debug", "grafana.image=grafana/grafana:5.4.2"}
+		config, err := validateAndBuildConfig(options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		if err := render(*config, &buf, options); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, expected := range []string{
+			"image: grafana/grafana:5.4.2",
+			"image: weaveworks/flagger:0.13.2",
+			"- -log-level=debug\n",
+			fmt.Sprintf("- -metrics-server=http://linkerd-prometheus.%s.svc.cluster.local:9090\n", config.Namespace),
+			"name: canaries.flagger.app",
+		} {
+			if !strings.Contains(buf.String(), expected) {
+				t.Fatalf("Expected the manifest to contain %q", expected)
+			}
+		}
+	})
+}
+
+func TestRenderExternalGrafana(t *testing.T) {
+	options := newInstallOptions()
+	*options.addOns["grafana"] = false
+	options.grafanaURL = "https://grafana.example.com"
+	config, err := validateAndBuildConfig(options)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := render(*config, &buf, options); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	arg := "- -grafana-url=https://grafana.example.com"
+	if !strings.Contains(buf.String(), arg) {
+		t.Fatalf("Expected the web container to have the %s arg", arg)
+	}
+	if strings.Contains(buf.String(), "### Grafana ###") {
+		t.Fatal("Expected the grafana add-on not to be installed")
+	}
+}
+
+func TestRenderPrometheusFederation(t *testing.T) {
+	options := newInstallOptions()
+	options.prometheusFederationURLs = []string{"http://prometheus-zone-a:9090", "https://prometheus-zone-b.example.com"}
+	config, err := validateAndBuildConfig(options)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := render(*config, &buf, options); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	arg := "- -prometheus-federation-urls=http://prometheus-zone-a:9090,https://prometheus-zone-b.example.com"
+	if !strings.Contains(buf.String(), arg) {
+		t.Fatalf("Expected the public-api container to have the %s arg", arg)
+	}
+}
+
+func TestRenderEnforcedHost(t *testing.T) {
+	options := newInstallOptions()
+	options.enforcedHost = `^dashboard\.example\.com$`
+	config, err := validateAndBuildConfig(options)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := render(*config, &buf, options); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	arg := `- -enforced-host=^dashboard\.example\.com$`
+	if !strings.Contains(buf.String(), arg) {
+		t.Fatalf("Expected the web container to have the %s arg", arg)
+	}
+}
+
+func TestRenderDisableVersionCheck(t *testing.T) {
+	options := newInstallOptions()
+	options.disableVersionCheck = true
+	config, err := validateAndBuildConfig(options)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := render(*config, &buf, options); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	arg := "- -version-check-url=\n"
+	if !strings.Contains(buf.String(), arg) {
+		t.Fatalf("Expected the web container to have the %s arg", arg)
+	}
+}
+
+func TestRenderPlacement(t *testing.T) {
+	options := newInstallOptions()
+	*options.addOns["tracing"] = true
+	options.nodeSelector = []string{"node-role.kubernetes.io/system=true"}
+	options.tolerations = []string{"dedicated=system:NoSchedule", "node-role.kubernetes.io/system"}
+	options.priorityClassName = "system-cluster-critical"
+	config, err := validateAndBuildConfig(options)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := render(*config, &buf, options); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// the injection sorts the fields of the pod specs
+	nodeSelector := `
+      nodeSelector:
+        node-role.kubernetes.io/system: "true"
+      priorityClassName: system-cluster-critical
+`
+	tolerations := `
+      tolerations:
+      - effect: NoSchedule
+        key: dedicated
+        operator: Equal
+        value: system
+      - key: node-role.kubernetes.io/system
+        operator: Exists
+`
+	// controller, web, prometheus, grafana, collector and jaeger
+	for _, placement := range []string{nodeSelector, tolerations} {
+		if count := strings.Count(buf.String(), placement); count != 6 {
+			t.Fatalf("Expected the placement of the 6 control plane deployments, got %d:\n%s", count, buf.String())
+		}
+	}
+}
+
+func TestParseAddOnValues(t *testing.T) {
+	testCases := []struct {
+		values []string
+		err    string
+	}{
+		{
+			values: []string{"grafana.image"},
+			err:    "Invalid value 'grafana.image' for --addon-set flag: must be of the form <add-on>.<key>=<value>",
+		},
+		{
+			values: []string{"image=grafana/grafana:5.4.2"},
+			err:    "Invalid value 'image=grafana/grafana:5.4.2' for --addon-set flag: must be of the form <add-on>.<key>=<value>",
+		},
+		{
+			values: []string{"kiali.image=kiali/kiali"},
+			err:    "Invalid value 'kiali.image=kiali/kiali' for --addon-set flag: unknown add-on 'kiali', must be one of: grafana, tracing, flagger",
+		},
+		{
+			values: []string{"tracing.jaegerImage=jaegertracing/all-in-one:1.9"},
+			err:    "Invalid value 'tracing.jaegerImage=jaegertracing/all-in-one:1.9' for --addon-set flag: the tracing add-on isn't enabled",
+		},
+		{
+			values: []string{"grafana.tag=5.4.2"},
+			err:    "Invalid value 'grafana.tag=5.4.2' for --addon-set flag: unknown key 'tag' of the grafana add-on, must be one of: image",
+		},
+	}
+
+	for i, tc := range testCases {
+		options := newInstallOptions()
+		options.addOnValues = tc.values
+		_, err := parseAddOnValues(options)
+		if err == nil || err.Error() != tc.err {
+			t.Fatalf("test case %d: expected error %q, got: %v", i, tc.err, err)
+		}
+	}
+}
+
 func TestValidate(t *testing.T) {
 	t.Run("Accepts the default options as valid", func(t *testing.T) {
 		if err := newInstallOptions().validate(); err != nil {
@@ -180,4 +497,414 @@ func TestValidate(t *testing.T) {
 			t.Fatalf("Expected error string\"%s\", got \"%s\"", expected, err)
 		}
 	})
+
+	t.Run("Rejects invalid proxy injector webhook settings", func(t *testing.T) {
+		options := newInstallOptions()
+		options.proxyInjectorFailurePolicy = "Sometimes"
+		expected := "--proxy-injector-failure-policy must be one of: Ignore, Fail"
+
+		err := options.validate()
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error string \"%s\", got \"%v\"", expected, err)
+		}
+
+		options = newInstallOptions()
+		options.proxyInjectorNamespaceSelector = "opt-sideways"
+		expected = "--proxy-injector-namespace-selector must be one of: opt-out, opt-in"
+
+		err = options.validate()
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error string \"%s\", got \"%v\"", expected, err)
+		}
+	})
+
+	t.Run("Rejects a snapshot written to a directory", func(t *testing.T) {
+		options := newInstallOptions()
+		options.snapshot = true
+		options.outputDir = "linkerd"
+		expected := "The --snapshot and --output-dir flags cannot both be specified together"
+
+		err := options.validate()
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error string \"%s\", got \"%v\"", expected, err)
+		}
+	})
+
+	t.Run("Rejects an issuer secret without TLS", func(t *testing.T) {
+		options := newInstallOptions()
+		options.tlsIssuerSecret = "linkerd-issuer"
+		expected := "The --tls-issuer-secret flag requires --tls=optional"
+
+		err := options.validate()
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error string \"%s\", got \"%v\"", expected, err)
+		}
+	})
+
+	t.Run("Rejects incomplete or conflicting Vault issuer flags", func(t *testing.T) {
+		for _, tc := range []struct {
+			tls      string
+			secret   string
+			role     string
+			expected string
+		}{
+			{"", "", "linkerd", "The --tls-issuer-vault-addr flag requires --tls=optional"},
+			{"optional", "linkerd-issuer", "linkerd", "The --tls-issuer-vault-addr and --tls-issuer-secret flags cannot both be specified together"},
+			{"optional", "", "", "The --tls-issuer-vault-addr flag requires the --tls-issuer-vault-pki-path, --tls-issuer-vault-role, --tls-issuer-vault-auth-path and --tls-issuer-vault-auth-role flags"},
+		} {
+			options := newInstallOptions()
+			options.tls = tc.tls
+			options.tlsIssuerSecret = tc.secret
+			options.tlsIssuerVault.Addr = "https://vault.vault.svc.cluster.local:8200"
+			options.tlsIssuerVault.Role = tc.role
+			options.tlsIssuerVault.AuthRole = "linkerd-ca"
+
+			err := options.validate()
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("Expected error string \"%s\", got \"%v\"", tc.expected, err)
+			}
+		}
+
+		options := newInstallOptions()
+		options.tls = "optional"
+		options.tlsIssuerVault.Addr = "https://vault.vault.svc.cluster.local:8200"
+		options.tlsIssuerVault.Role = "linkerd"
+		options.tlsIssuerVault.AuthRole = "linkerd-ca"
+		if err := options.validate(); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Rejects invalid issuer file settings", func(t *testing.T) {
+		for _, tc := range []struct {
+			tls      string
+			secret   string
+			certFile string
+			keyFile  string
+			expected string
+		}{
+			{"optional", "", "issuer.crt", "", "The --tls-issuer-cert-file and --tls-issuer-key-file flags must be specified together"},
+			{"", "", "issuer.crt", "issuer.key", "The --tls-issuer-cert-file flag requires --tls=optional"},
+			{"optional", "linkerd-issuer", "issuer.crt", "issuer.key", "The --tls-issuer-cert-file and --tls-issuer-secret flags cannot both be specified together"},
+		} {
+			options := newInstallOptions()
+			options.tls = tc.tls
+			options.tlsIssuerSecret = tc.secret
+			options.tlsIssuerCertFile = tc.certFile
+			options.tlsIssuerKeyFile = tc.keyFile
+
+			err := options.validate()
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("Expected error string \"%s\", got \"%v\"", tc.expected, err)
+			}
+		}
+	})
+
+	t.Run("Rejects the tap APIService in single namespace mode", func(t *testing.T) {
+		options := newInstallOptions()
+		options.tapAPIService = true
+		options.singleNamespace = true
+
+		expected := "The --tap-api-service and --single-namespace flags cannot both be specified together"
+		err := options.validate()
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error string \"%s\", got \"%v\"", expected, err)
+		}
+	})
+
+	t.Run("Rejects invalid API authentication settings", func(t *testing.T) {
+		for _, tc := range []struct {
+			tls      string
+			apiAuth  string
+			expected string
+		}{
+			{"optional", "token", "--api-auth must be blank or set to \"tls\""},
+			{"", "tls", "The --api-auth flag requires --tls=optional"},
+		} {
+			options := newInstallOptions()
+			options.tls = tc.tls
+			options.apiAuth = tc.apiAuth
+
+			err := options.validate()
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("Expected error string \"%s\", got \"%v\"", tc.expected, err)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid external Prometheus settings", func(t *testing.T) {
+		for _, tc := range []struct {
+			url         string
+			tokenSecret string
+			caSecret    string
+			expected    string
+		}{
+			{"prometheus:9090", "", "", "Invalid value 'prometheus:9090' for --prometheus-url flag: must be an absolute http or https URL"},
+			{"", "prometheus-token", "", "The --prometheus-bearer-token-secret flag requires --prometheus-url"},
+			{"", "", "prometheus-ca", "The --prometheus-ca-secret flag requires --prometheus-url"},
+			{"http://prometheus:9090", "", "Prometheus_CA", "Invalid value 'Prometheus_CA' for --prometheus-ca-secret flag: "},
+		} {
+			options := newInstallOptions()
+			options.prometheusURL = tc.url
+			options.prometheusBearerTokenSecret = tc.tokenSecret
+			options.prometheusCASecret = tc.caSecret
+
+			err := options.validate()
+			if err == nil || !strings.HasPrefix(err.Error(), tc.expected) {
+				t.Fatalf("Expected error string \"%s\", got \"%v\"", tc.expected, err)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid bundled Prometheus settings", func(t *testing.T) {
+		for _, tc := range []struct {
+			url          string
+			retention    string
+			remoteWrite  []string
+			remoteSecret string
+			expected     string
+		}{
+			{"", "two days", nil, "", "Invalid value 'two days' for --prometheus-retention flag: must be a Prometheus duration, such as \"6h\" or \"15d\""},
+			{"", "6h", []string{"thanos:19291"}, "", "Invalid value 'thanos:19291' for --prometheus-remote-write-url flag: must be an absolute http or https URL"},
+			{"", "6h", nil, "remote-write", "The --prometheus-remote-write-secret flag requires --prometheus-remote-write-url"},
+			{"", "6h", []string{"http://thanos:19291"}, "Remote_Write", "Invalid value 'Remote_Write' for --prometheus-remote-write-secret flag: "},
+			{"http://prometheus:9090", "2d", nil, "", "The --prometheus-retention and --prometheus-remote-write-url flags configure the bundled Prometheus, and cannot be specified with --prometheus-url"},
+			{"http://prometheus:9090", "6h", []string{"http://thanos:19291"}, "", "The --prometheus-retention and --prometheus-remote-write-url flags configure the bundled Prometheus, and cannot be specified with --prometheus-url"},
+		} {
+			options := newInstallOptions()
+			options.prometheusURL = tc.url
+			options.prometheusRetention = tc.retention
+			options.prometheusRemoteWriteURLs = tc.remoteWrite
+			options.prometheusRemoteWriteSecret = tc.remoteSecret
+
+			err := options.validate()
+			if err == nil || !strings.HasPrefix(err.Error(), tc.expected) {
+				t.Fatalf("Expected error string \"%s\", got \"%v\"", tc.expected, err)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid Prometheus federation URLs", func(t *testing.T) {
+		for _, federationURL := range []string{"prometheus-zone-b:9090", "http://a:9090,http://b:9090"} {
+			options := newInstallOptions()
+			options.prometheusFederationURLs = []string{"http://prometheus-zone-a:9090", federationURL}
+
+			expected := fmt.Sprintf("Invalid value '%s' for --prometheus-federation-url flag: must be an absolute http or https URL", federationURL)
+			err := options.validate()
+			if err == nil || err.Error() != expected {
+				t.Fatalf("Expected error string \"%s\", got \"%v\"", expected, err)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid or reserved metric pod labels", func(t *testing.T) {
+		for _, tc := range []struct {
+			key      string
+			expected string
+		}{
+			{"team/", "Invalid value 'team/' for --metric-pod-labels flag: "},
+			{"linkerd.io/proxy-deployment", "Invalid value 'linkerd.io/proxy-deployment' for --metric-pod-labels flag: the label is reserved for Linkerd's metrics"},
+			{"direction", "Invalid value 'direction' for --metric-pod-labels flag: the label is reserved for Linkerd's metrics"},
+		} {
+			options := newInstallOptions()
+			options.metricPodLabels = []string{"version", tc.key}
+
+			err := options.validate()
+			if err == nil {
+				t.Fatalf("Expected error for [%s], got nothing", tc.key)
+			}
+			if !strings.HasPrefix(err.Error(), tc.expected) {
+				t.Fatalf("Expected error string \"%s\", got \"%s\"", tc.expected, err)
+			}
+		}
+	})
+
+	t.Run("Requires auto-injection for the proxy network policies", func(t *testing.T) {
+		options := newInstallOptions()
+		options.proxyNetworkPolicies = true
+
+		expected := "The --proxy-network-policies flag requires --proxy-auto-inject"
+		err := options.validate()
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error string \"%s\", got \"%v\"", expected, err)
+		}
+	})
+
+	t.Run("Rejects invalid event webhook URLs", func(t *testing.T) {
+		for _, webhookURL := range []string{"hooks.example.com/linkerd", "ftp://hooks.example.com", "https://"} {
+			options := newInstallOptions()
+			options.eventWebhookURL = webhookURL
+
+			expected := fmt.Sprintf("Invalid value '%s' for --event-webhook-url flag: must be an absolute http or https URL", webhookURL)
+			err := options.validate()
+			if err == nil || err.Error() != expected {
+				t.Fatalf("Expected error string \"%s\", got \"%v\"", expected, err)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid Grafana URLs", func(t *testing.T) {
+		for _, grafanaURL := range []string{"grafana.example.com", "ftp://grafana.example.com", "https://"} {
+			options := newInstallOptions()
+			options.grafanaURL = grafanaURL
+
+			expected := fmt.Sprintf("Invalid value '%s' for --grafana-url flag: must be an absolute http or https URL", grafanaURL)
+			err := options.validate()
+			if err == nil || err.Error() != expected {
+				t.Fatalf("Expected error string \"%s\", got \"%v\"", expected, err)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid enforced host regexps", func(t *testing.T) {
+		options := newInstallOptions()
+		options.enforcedHost = "dashboard.(example"
+
+		expected := "Invalid value 'dashboard.(example' for --enforced-host flag: error parsing regexp: missing closing ): `dashboard.(example`"
+		err := options.validate()
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error string \"%s\", got \"%v\"", expected, err)
+		}
+	})
+
+	t.Run("Rejects invalid placement settings", func(t *testing.T) {
+		for _, tc := range []struct {
+			nodeSelector      string
+			toleration        string
+			priorityClassName string
+			expected          string
+		}{
+			{nodeSelector: "node-role", expected: "Invalid value 'node-role' for --control-plane-node-selector flag: must be of the form <key>=<value>"},
+			{toleration: "dedicated=system:NoPlace", expected: "Invalid value 'dedicated=system:NoPlace' for --control-plane-toleration flag: the effect must be one of: NoSchedule, PreferNoSchedule, NoExecute"},
+			{priorityClassName: "System_Critical", expected: "Invalid value 'System_Critical' for --control-plane-priority-class-name flag: a DNS-1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')"},
+		} {
+			options := newInstallOptions()
+			if tc.nodeSelector != "" {
+				options.nodeSelector = []string{tc.nodeSelector}
+			}
+			if tc.toleration != "" {
+				options.tolerations = []string{tc.toleration}
+			}
+			options.priorityClassName = tc.priorityClassName
+
+			err := options.validate()
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("Expected error string \"%s\", got \"%v\"", tc.expected, err)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid HA topology settings", func(t *testing.T) {
+		for _, tc := range []struct {
+			antiAffinity   string
+			topologyKeys   []string
+			maxUnavailable uint
+			expected       string
+		}{
+			{"sometimes", []string{"kubernetes.io/hostname"}, 1, "--controller-anti-affinity must be one of: preferred, required, none"},
+			{"required", []string{}, 1, "--controller-topology-keys must not be empty unless --controller-anti-affinity=none"},
+			{"required", []string{"zone/"}, 1, "Invalid value 'zone/' for --controller-topology-keys flag: "},
+			{"preferred", []string{"kubernetes.io/hostname"}, 0, "--controller-max-unavailable must be at least 1, so that the nodes of the controller replicas can be drained"},
+		} {
+			options := newInstallOptions()
+			options.highAvailability = true
+			options.controllerAntiAffinity = tc.antiAffinity
+			options.controllerTopologyKeys = tc.topologyKeys
+			options.controllerMaxUnavailable = tc.maxUnavailable
+
+			err := options.validate()
+			if err == nil || !strings.HasPrefix(err.Error(), tc.expected) {
+				t.Fatalf("Expected error string \"%s\", got \"%v\"", tc.expected, err)
+			}
+		}
+
+		options := newInstallOptions()
+		options.highAvailability = true
+		options.controllerMaxUnavailable = 3
+		expected := "--controller-max-unavailable must be lower than the number of controller replicas (3)"
+
+		_, err := validateAndBuildConfig(options)
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error string \"%s\", got \"%v\"", expected, err)
+		}
+	})
+}
+
+func TestReadTLSIssuer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "linkerd-issuer")
+	if err != nil {
+		t.Fatalf("TempDir returned an error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeIssuer := func(name string, isCA bool) (string, string) {
+		key, err := tls.GenerateECDSAKey()
+		if err != nil {
+			t.Fatalf("GenerateECDSAKey returned an error: %s", err)
+		}
+		template := x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: name},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  isCA,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+		if err != nil {
+			t.Fatalf("CreateCertificate returned an error: %s", err)
+		}
+		certPEM, err := tls.PEMEncodeCert(der)
+		if err != nil {
+			t.Fatalf("PEMEncodeCert returned an error: %s", err)
+		}
+		keyPEM, err := tls.EncodeECDSAKey(key)
+		if err != nil {
+			t.Fatalf("EncodeECDSAKey returned an error: %s", err)
+		}
+
+		certFile := filepath.Join(dir, name+".crt")
+		keyFile := filepath.Join(dir, name+".key")
+		if err := ioutil.WriteFile(certFile, certPEM, 0600); err != nil {
+			t.Fatalf("WriteFile returned an error: %s", err)
+		}
+		if err := ioutil.WriteFile(keyFile, keyPEM, 0600); err != nil {
+			t.Fatalf("WriteFile returned an error: %s", err)
+		}
+		return certFile, keyFile
+	}
+
+	issuerCert, issuerKey := writeIssuer("issuer", true)
+	leafCert, leafKey := writeIssuer("leaf", false)
+
+	t.Run("Reads valid issuer credentials", func(t *testing.T) {
+		certPEM, keyPEM, err := readTLSIssuer(issuerCert, issuerKey)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expectedCert, _ := ioutil.ReadFile(issuerCert)
+		expectedKey, _ := ioutil.ReadFile(issuerKey)
+		if !bytes.Equal(certPEM, expectedCert) || !bytes.Equal(keyPEM, expectedKey) {
+			t.Fatalf("Issuer credentials differ from the files they were read from")
+		}
+	})
+
+	for _, tc := range []struct {
+		certFile string
+		keyFile  string
+		expected string
+	}{
+		{leafCert, leafKey, "Invalid TLS issuer: certificate \"leaf\" is not a CA certificate"},
+		{issuerCert, leafKey, "Invalid TLS issuer: the private key doesn't match the certificate"},
+		{issuerKey, issuerKey, fmt.Sprintf("Invalid --tls-issuer-cert-file %s: no PEM certificate found", issuerKey)},
+	} {
+		tc := tc
+		t.Run(fmt.Sprintf("Rejects %s", tc.expected), func(t *testing.T) {
+			_, _, err := readTLSIssuer(tc.certFile, tc.keyFile)
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("Expected error string \"%s\", got \"%v\"", tc.expected, err)
+			}
+		})
+	}
 }
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/pflag"
+)
+
+// installValue is a value of a setting of the control plane, given with
+// --values or --set. Each setting is a flag of `linkerd install`, so that the
+// values are parsed and validated like the flags are.
+type installValue struct {
+	name   string
+	values []string
+	source string
+}
+
+// applyInstallValues sets the flags to the values of the --values files, in
+// order, then to those of --set, each source replacing the values of the
+// previous ones. The flags given on the command line take precedence over
+// both.
+func applyInstallValues(flags *pflag.FlagSet, options *installOptions) error {
+	if len(options.valuesFiles) == 0 && len(options.setValues) == 0 {
+		return nil
+	}
+
+	explicit := map[string]bool{}
+	flags.Visit(func(flag *pflag.Flag) {
+		explicit[flag.Name] = true
+	})
+
+	values := map[string]*installValue{}
+	for _, file := range options.valuesFiles {
+		fileValues, err := readInstallValues(flags, file)
+		if err != nil {
+			return err
+		}
+		for _, value := range fileValues {
+			values[value.name] = value
+		}
+	}
+	setValues, err := parseSetValues(flags, options.setValues)
+	if err != nil {
+		return err
+	}
+	for _, value := range setValues {
+		values[value.name] = value
+	}
+
+	names := []string{}
+	for name := range values {
+		if !explicit[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := values[name]
+		for _, v := range value.values {
+			if err := flags.Set(name, v); err != nil {
+				return fmt.Errorf("Invalid value '%s' for %s in %s: %s", v, name, value.source, err)
+			}
+		}
+	}
+	return nil
+}
+
+// readInstallValues reads a --values file, a YAML map of the names of the
+// flags to their values. The values of the flags that may be repeated are
+// lists.
+func readInstallValues(flags *pflag.FlagSet, file string) ([]*installValue, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	raw := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("Invalid --values file %s: %s", file, err)
+	}
+
+	names := []string{}
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := []*installValue{}
+	for _, name := range names {
+		v := raw[name]
+		if err := checkInstallValueName(flags, name); err != nil {
+			return nil, fmt.Errorf("Invalid --values file %s: %s", file, err)
+		}
+
+		value := &installValue{name: name, source: file}
+		switch v := v.(type) {
+		case []interface{}:
+			if !isListFlag(flags.Lookup(name)) {
+				return nil, fmt.Errorf("Invalid --values file %s: %s takes a single value, not a list", file, name)
+			}
+			for _, item := range v {
+				s, err := scalarInstallValue(item)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid --values file %s: the items of %s %s", file, name, err)
+				}
+				value.values = append(value.values, s)
+			}
+		default:
+			s, err := scalarInstallValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid --values file %s: %s %s", file, name, err)
+			}
+			value.values = []string{s}
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// parseSetValues parses the --set values, of the form <name>=<value>. The
+// values of a flag that may be repeated are added to each other.
+func parseSetValues(flags *pflag.FlagSet, settings []string) ([]*installValue, error) {
+	values := []*installValue{}
+	byName := map[string]*installValue{}
+	for _, setting := range settings {
+		kv := strings.SplitN(setting, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("Invalid value '%s' for --set flag: must be of the form <name>=<value>", setting)
+		}
+		if err := checkInstallValueName(flags, kv[0]); err != nil {
+			return nil, fmt.Errorf("Invalid value '%s' for --set flag: %s", setting, err)
+		}
+
+		value, ok := byName[kv[0]]
+		if !ok {
+			value = &installValue{name: kv[0], source: "--set"}
+			byName[kv[0]] = value
+			values = append(values, value)
+		}
+		if isListFlag(flags.Lookup(kv[0])) {
+			value.values = append(value.values, kv[1])
+		} else {
+			value.values = []string{kv[1]}
+		}
+	}
+	return values, nil
+}
+
+// checkInstallValueName returns an error if name isn't a flag that
+// configures the control plane, suggesting the flag it may be a typo of.
+func checkInstallValueName(flags *pflag.FlagSet, name string) error {
+	for _, unrecorded := range unrecordedInstallFlags {
+		if name == unrecorded {
+			return fmt.Errorf("%s isn't a setting of the control plane, and can only be given as a flag", name)
+		}
+	}
+	if flags.Lookup(name) != nil {
+		return nil
+	}
+
+	suggestion, distance := "", 0
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if d := editDistance(name, flag.Name); suggestion == "" || d < distance {
+			suggestion, distance = flag.Name, d
+		}
+	})
+	if suggestion != "" && distance <= 3 {
+		return fmt.Errorf("unknown setting %s, did you mean %s? The settings are the flags of \"linkerd install\"", name, suggestion)
+	}
+	return fmt.Errorf("unknown setting %s: the settings are the flags of \"linkerd install\"", name)
+}
+
+func isListFlag(flag *pflag.Flag) bool {
+	switch flag.Value.Type() {
+	case "stringArray", "stringSlice":
+		return true
+	}
+	return false
+}
+
+// scalarInstallValue returns the flag value of a YAML scalar.
+func scalarInstallValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", errors.New("must be a string, a number or a boolean")
+	}
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j] + 1
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+			if previous[j-1]+cost < current[j] {
+				current[j] = previous[j-1] + cost
+			}
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
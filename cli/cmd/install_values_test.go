@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestApplyInstallValues(t *testing.T) {
+	newFlags := func(args ...string) (*cobra.Command, *installOptions) {
+		options := newInstallOptions()
+		cmd := &cobra.Command{}
+		addInstallFlags(cmd, options)
+		if err := cmd.PersistentFlags().Parse(args); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return cmd, options
+	}
+	valuesFile := func(content string) string {
+		f, err := ioutil.TempFile("", "linkerd-values")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(content); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return f.Name()
+	}
+
+	file := valuesFile(`ha: true
+controller-replicas: 5
+controller-log-level: debug
+control-plane-toleration:
+- dedicated=system:NoSchedule
+- critical:NoExecute
+`)
+	defer os.Remove(file)
+
+	t.Run("Sets the flags to the values", func(t *testing.T) {
+		cmd, options := newFlags("--values", file, "--set", "controller-log-level=warn", "--controller-replicas=4")
+		if err := applyInstallValues(cmd.PersistentFlags(), options); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if !options.highAvailability {
+			t.Fatal("Expected HA to be enabled")
+		}
+		if options.controllerReplicas != 4 {
+			t.Fatalf("Expected the --controller-replicas flag to take precedence, got %d replicas", options.controllerReplicas)
+		}
+		if options.controllerLogLevel != "warn" {
+			t.Fatalf("Expected --set to take precedence over the values file, got log level %s", options.controllerLogLevel)
+		}
+		expectedTolerations := []string{"dedicated=system:NoSchedule", "critical:NoExecute"}
+		if !reflect.DeepEqual(options.tolerations, expectedTolerations) {
+			t.Fatalf("Expected tolerations %v, got %v", expectedTolerations, options.tolerations)
+		}
+
+		recorded := recordedInstallFlags(cmd.PersistentFlags())
+		expectedRecorded := []string{
+			"--control-plane-toleration=dedicated=system:NoSchedule",
+			"--control-plane-toleration=critical:NoExecute",
+			"--controller-log-level=warn",
+			"--controller-replicas=4",
+			"--ha",
+		}
+		if !reflect.DeepEqual(recorded, expectedRecorded) {
+			t.Fatalf("Expected the recorded flags %v, got %v", expectedRecorded, recorded)
+		}
+	})
+
+	t.Run("Repeats the --set values of the flags that may be repeated", func(t *testing.T) {
+		cmd, options := newFlags("--values", file, "--set", "control-plane-toleration=a:NoSchedule", "--set", "control-plane-toleration=b:NoSchedule")
+		if err := applyInstallValues(cmd.PersistentFlags(), options); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expectedTolerations := []string{"a:NoSchedule", "b:NoSchedule"}
+		if !reflect.DeepEqual(options.tolerations, expectedTolerations) {
+			t.Fatalf("Expected tolerations %v, got %v", expectedTolerations, options.tolerations)
+		}
+	})
+
+	testCases := []struct {
+		title    string
+		args     func() []string
+		expected func(file string) string
+	}{
+		{
+			"rejects --set values without a name",
+			func() []string { return []string{"--set", "ha"} },
+			func(string) string {
+				return "Invalid value 'ha' for --set flag: must be of the form <name>=<value>"
+			},
+		},
+		{
+			"suggests the flag of a misspelled setting",
+			func() []string { return []string{"--set", "controller-replica=3"} },
+			func(string) string {
+				return "Invalid value 'controller-replica=3' for --set flag: unknown setting controller-replica, did you mean controller-replicas? The settings are the flags of \"linkerd install\""
+			},
+		},
+		{
+			"rejects the flags that aren't settings",
+			func() []string { return []string{"--set", "output-dir=manifests"} },
+			func(string) string {
+				return "Invalid value 'output-dir=manifests' for --set flag: output-dir isn't a setting of the control plane, and can only be given as a flag"
+			},
+		},
+		{
+			"rejects the values of the wrong type",
+			func() []string { return []string{"--set", "controller-replicas=three"} },
+			func(string) string {
+				return "Invalid value 'three' for controller-replicas in --set: "
+			},
+		},
+		{
+			"rejects the lists of single values",
+			func() []string { return []string{"--values", valuesFile("controller-replicas: [1, 2]\n")} },
+			func(file string) string {
+				return "Invalid --values file " + file + ": controller-replicas takes a single value, not a list"
+			},
+		},
+		{
+			"rejects the maps",
+			func() []string { return []string{"--values", valuesFile("controller-log-level:\n  level: debug\n")} },
+			func(file string) string {
+				return "Invalid --values file " + file + ": controller-log-level must be a string, a number or a boolean"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			args := tc.args()
+			if args[0] == "--values" {
+				defer os.Remove(args[1])
+			}
+			cmd, options := newFlags(args...)
+
+			err := applyInstallValues(cmd.PersistentFlags(), options)
+			expected := tc.expected(args[1])
+			if err == nil || !strings.HasPrefix(err.Error(), expected) {
+				t.Fatalf("Expected error [%s], got [%v]", expected, err)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/linkerd/linkerd2/pkg/k8s"
@@ -29,6 +30,22 @@ type logsOptions struct {
 	sinceSeconds          time.Duration
 	tail                  int64
 	timestamps            bool
+	level                 string
+}
+
+// logLevels are the levels that --level accepts, from the most to the least
+// verbose, along with the names that the control plane containers log them
+// with: logfmt or JSON from the controllers and Prometheus, "lvl=" from
+// Grafana, and the upper-case prefixes of the proxy.
+var logLevels = []struct {
+	level string
+	names []string
+	proxy []string
+}{
+	{"debug", []string{"trace", "debug", "dbug"}, []string{"TRCE", "DBUG"}},
+	{"info", []string{"info"}, []string{"INFO"}},
+	{"warn", []string{"warn", "warning"}, []string{"WARN"}},
+	{"error", []string{"error", "eror", "fatal", "panic", "crit"}, []string{"ERR!"}},
 }
 
 func newLogsOptions() *logsOptions {
@@ -38,7 +55,42 @@ func newLogsOptions() *logsOptions {
 		sinceSeconds:          48 * time.Hour,
 		tail:                  -1,
 		timestamps:            false,
+		level:                 "",
+	}
+}
+
+// levelExcludes returns the regexps that match the log lines below level, so
+// that stern doesn't print them. The lines without a level, such as stack
+// traces, are always printed.
+func levelExcludes(level string) ([]*regexp.Regexp, error) {
+	if level == "" {
+		return nil, nil
+	}
+
+	names, proxy := []string{}, []string{}
+	for _, l := range logLevels {
+		if l.level != level {
+			names = append(names, l.names...)
+			for _, p := range l.proxy {
+				proxy = append(proxy, regexp.QuoteMeta(p))
+			}
+			continue
+		}
+
+		if len(names) == 0 {
+			return nil, nil
+		}
+		return []*regexp.Regexp{
+			regexp.MustCompile(fmt.Sprintf(`(\blevel=|\blvl=|"level":")(%s)\b`, strings.Join(names, "|"))),
+			regexp.MustCompile(fmt.Sprintf(`(^|\s)(%s)\s`, strings.Join(proxy, "|"))),
+		}, nil
+	}
+
+	valid := []string{}
+	for _, l := range logLevels {
+		valid = append(valid, l.level)
 	}
+	return nil, fmt.Errorf("invalid log level [%s]. Must be one of %v", level, valid)
 }
 
 func (o *logsOptions) toSternConfig(controlPlaneComponents, availableContainers []string) (*stern.Config, error) {
@@ -95,6 +147,13 @@ func (o *logsOptions) toSternConfig(controlPlaneComponents, availableContainers
 		return nil, err
 	}
 	config.PodQuery = podFilterRgx
+
+	excludes, err := levelExcludes(o.level)
+	if err != nil {
+		return nil, err
+	}
+	config.Exclude = excludes
+
 	config.Since = o.sinceSeconds
 	config.Timestamps = o.timestamps
 	config.Namespace = controlPlaneNamespace
@@ -113,8 +172,8 @@ func getControlPlaneComponentsAndContainers(pods *v1.PodList) ([]string, []strin
 	return controlPlaneComponents, containers
 }
 
-func newLogCmdConfig(options *logsOptions, kubeconfigPath, kubeContext string) (*logCmdConfig, error) {
-	kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext)
+func newLogCmdConfig(options *logsOptions, kubeconfigPath, kubeContext, impersonate string, impersonateGroup []string) (*logCmdConfig, error) {
+	kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +207,12 @@ func newCmdLogs() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "logs [flags]",
 		Short: "Tail logs from containers in the Linkerd control plane",
-		Long:  `Tail logs from containers in the Linkerd control plane.`,
+		Long: `Tail logs from containers in the Linkerd control plane.
+
+The logs of all the matching containers are multiplexed, each line prefixed
+with its pod and container in a color of their own. With --level, only the
+lines logged at that level or above are shown; the lines without a level, such
+as stack traces, are always shown.`,
 		Example: `  # Tail logs from all containers in the prometheus control plane component
   linkerd logs --control-plane-component prometheus
 
@@ -160,9 +224,12 @@ func newCmdLogs() *cobra.Command {
 
   # Tail logs from the linkerd-proxy container in the controller component showing timestamps for each line
   linkerd logs --control-plane-component controller --container linkerd-proxy --timestamps
+
+  # Tail the warnings and errors of all the control plane containers
+  linkerd logs --level warn
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts, err := newLogCmdConfig(options, kubeconfigPath, kubeContext)
+			opts, err := newLogCmdConfig(options, kubeconfigPath, kubeContext, impersonate, impersonateGroup)
 
 			if err != nil {
 				return err
@@ -177,6 +244,7 @@ func newCmdLogs() *cobra.Command {
 	cmd.PersistentFlags().DurationVarP(&options.sinceSeconds, "since", "s", options.sinceSeconds, "Duration of how far back logs should be retrieved")
 	cmd.PersistentFlags().Int64Var(&options.tail, "tail", options.tail, "Last number of log lines to show for a given container. -1 does not show previous log lines")
 	cmd.PersistentFlags().BoolVarP(&options.timestamps, "timestamps", "t", options.timestamps, "Print timestamps for each given log line")
+	cmd.PersistentFlags().StringVar(&options.level, "level", options.level, "Only show the log lines at this level or above: debug, info, warn or error (default: all lines)")
 
 	return cmd
 }
@@ -209,6 +277,7 @@ func runLogOutput(opts *logCmdConfig) error {
 				SinceSeconds: int64(opts.Since.Seconds()),
 				Timestamps:   opts.Timestamps,
 				TailLines:    opts.TailLines,
+				Exclude:      opts.Exclude,
 				Namespace:    true,
 			}
 
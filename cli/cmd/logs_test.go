@@ -103,3 +103,56 @@ func TestNewSternConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestLevelExcludes(t *testing.T) {
+	lines := []string{
+		`time="2019-01-02T03:04:05Z" level=debug msg="syncObject(web.deployment.emojivoto)"`,
+		`time="2019-01-02T03:04:05Z" level=info msg="starting CA"`,
+		`{"level":"info","msg":"starting admin server on :9995","time":"2019-01-02T03:04:05Z"}`,
+		`t=2019-01-02T03:04:05+0000 lvl=info msg="Starting Grafana" logger=server`,
+		`level=warn ts=2019-01-02T03:04:05.000Z caller=main.go:288 msg="config file changed"`,
+		`INFO admin={bg=resolver} linkerd2_proxy::control::destination using destination service`,
+		`WARN proxy={server=in listen=0.0.0.0:4143} linkerd2_proxy::proxy::http::router service unavailable`,
+		`time="2019-01-02T03:04:05Z" level=error msg="error syncing object"`,
+		`goroutine 1 [running]:`,
+	}
+
+	testCases := []struct {
+		level    string
+		expected []int
+	}{
+		{"", []int{0, 1, 2, 3, 4, 5, 6, 7, 8}},
+		{"debug", []int{0, 1, 2, 3, 4, 5, 6, 7, 8}},
+		{"info", []int{1, 2, 3, 4, 5, 6, 7, 8}},
+		{"warn", []int{4, 6, 7, 8}},
+		{"error", []int{7, 8}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("level %s", tc.level), func(t *testing.T) {
+			excludes, err := levelExcludes(tc.level)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			shown := []int{}
+			for i, line := range lines {
+				excluded := false
+				for _, exclude := range excludes {
+					excluded = excluded || exclude.MatchString(line)
+				}
+				if !excluded {
+					shown = append(shown, i)
+				}
+			}
+			if fmt.Sprint(shown) != fmt.Sprint(tc.expected) {
+				t.Fatalf("Expected lines %v to be shown, got %v", tc.expected, shown)
+			}
+		})
+	}
+
+	if _, err := levelExcludes("verbose"); err == nil || err.Error() != "invalid log level [verbose]. Must be one of [debug info warn error]" {
+		t.Fatalf("Expected an error for an invalid level, got: %v", err)
+	}
+}
@@ -0,0 +1,405 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+// rolloutPollInterval is the interval between the polls of the deployments
+// that `linkerd mesh` waits for the rollout of.
+var rolloutPollInterval = time.Second
+
+type meshOptions struct {
+	restart    bool
+	skipChecks bool
+	timeout    time.Duration
+	*injectOptions
+}
+
+func newMeshOptions() *meshOptions {
+	return &meshOptions{
+		restart:       false,
+		skipChecks:    false,
+		timeout:       5 * time.Minute,
+		injectOptions: newInjectOptions(),
+	}
+}
+
+func newCmdMesh() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mesh [flags]",
+		Short: "Add the namespaces to the mesh, or remove them from it",
+		Long: `Add the namespaces to the mesh, or remove them from it.
+
+A namespace is added to the mesh by labeling it with
+"linkerd.io/auto-inject=enabled", so that the proxy injector injects the
+deployments created in it, whether it selects the namespaces to inject with the
+opt-in or the opt-out namespace selector. The proxy injector only injects the
+deployments when they're created: the deployments that already exist in the
+namespace are injected by rolling them out with the proxy, with --restart.`,
+	}
+
+	cmd.AddCommand(newCmdMeshEnable())
+	cmd.AddCommand(newCmdMeshDisable())
+
+	return cmd
+}
+
+func newCmdMeshEnable() *cobra.Command {
+	options := newMeshOptions()
+
+	cmd := &cobra.Command{
+		Use:   "enable [flags] (ns/NAME | NAME)",
+		Short: "Add a namespace to the mesh",
+		Long: `Add a namespace to the mesh.
+
+The namespace is labeled with "linkerd.io/auto-inject=enabled", so that the
+deployments created in it are injected with the proxy. With --restart, the
+deployments that already exist in the namespace and don't have the proxy are
+injected with it, with the same configuration as "linkerd inject", and the
+command waits for their rollout to complete.
+
+The data-plane checks of the namespace are then run, as "linkerd check --proxy"
+does, unless --skip-checks is set.`,
+		Example: `  # Inject the deployments created in the emojivoto namespace from now on.
+  linkerd mesh enable ns/emojivoto
+
+  # Also roll out the existing deployments of the namespace with the proxy.
+  linkerd mesh enable ns/emojivoto --restart`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := parseMeshNamespace(args[0])
+			if err != nil {
+				return err
+			}
+			if err := options.validate(); err != nil {
+				return err
+			}
+
+			clientset, err := newMeshClientset()
+			if err != nil {
+				return err
+			}
+
+			err = runMesh(os.Stdout, clientset, namespace, k8s.ProxyAutoInjectEnabled, options, func(d *appsV1.Deployment) (bool, error) {
+				return injectDeployment(d, options.injectOptions)
+			})
+			if err != nil {
+				return err
+			}
+
+			if options.skipChecks {
+				return nil
+			}
+			fmt.Println("")
+			if !runMeshChecks(os.Stdout, namespace, options.timeout) {
+				return fmt.Errorf("The data-plane checks of the %s namespace failed", namespace)
+			}
+			return nil
+		},
+	}
+
+	addProxyConfigFlags(cmd, options.proxyConfigOptions)
+	cmd.PersistentFlags().BoolVar(&options.restart, "restart", options.restart, "Roll out the existing deployments of the namespace with the proxy, and wait for their rollout")
+	cmd.PersistentFlags().BoolVar(&options.skipChecks, "skip-checks", options.skipChecks, "Don't run the data-plane checks of the namespace")
+	cmd.PersistentFlags().DurationVar(&options.timeout, "timeout", options.timeout, "How long to wait for the rollouts, and for the data-plane checks to succeed")
+
+	return cmd
+}
+
+func newCmdMeshDisable() *cobra.Command {
+	options := newMeshOptions()
+
+	cmd := &cobra.Command{
+		Use:   "disable [flags] (ns/NAME | NAME)",
+		Short: "Remove a namespace from the mesh",
+		Long: `Remove a namespace from the mesh.
+
+The namespace is labeled with "linkerd.io/auto-inject=disabled", so that the
+deployments created in it aren't injected with the proxy. With --restart, the
+proxy is removed from the deployments of the namespace that have it, and the
+command waits for their rollout to complete.`,
+		Example: `  # Stop injecting the deployments created in the emojivoto namespace.
+  linkerd mesh disable ns/emojivoto
+
+  # Also roll out the existing deployments of the namespace without the proxy.
+  linkerd mesh disable ns/emojivoto --restart`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := parseMeshNamespace(args[0])
+			if err != nil {
+				return err
+			}
+
+			clientset, err := newMeshClientset()
+			if err != nil {
+				return err
+			}
+
+			return runMesh(os.Stdout, clientset, namespace, k8s.ProxyAutoInjectDisabled, options, func(d *appsV1.Deployment) (bool, error) {
+				return uninjectDeployment(d), nil
+			})
+		},
+	}
+
+	cmd.PersistentFlags().BoolVar(&options.restart, "restart", options.restart, "Roll out the existing deployments of the namespace without the proxy, and wait for their rollout")
+	cmd.PersistentFlags().DurationVar(&options.timeout, "timeout", options.timeout, "How long to wait for the rollouts")
+
+	return cmd
+}
+
+// parseMeshNamespace returns the name of the namespace given as "ns/NAME",
+// "namespace/NAME" or "NAME".
+func parseMeshNamespace(arg string) (string, error) {
+	name := arg
+	if parts := strings.SplitN(arg, "/", 2); len(parts) == 2 {
+		resource, err := k8s.CanonicalResourceNameFromFriendlyName(parts[0])
+		if err != nil || resource != k8s.Namespace {
+			return "", fmt.Errorf("Invalid namespace '%s': must be of the form ns/NAME or NAME", arg)
+		}
+		name = parts[1]
+	}
+
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return "", fmt.Errorf("Invalid namespace '%s': %s", name, errs[0])
+	}
+	if name == controlPlaneNamespace {
+		return "", fmt.Errorf("The %s namespace is the control plane namespace, whose proxies are managed by \"linkerd install\"", name)
+	}
+	return name, nil
+}
+
+func newMeshClientset() (kubernetes.Interface, error) {
+	kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(kubeAPI.Config)
+}
+
+// runMesh labels the namespace with the auto-inject value and, with
+// --restart, rolls out the deployments of the namespace that transform
+// changes, waiting for their rollout.
+func runMesh(w io.Writer, clientset kubernetes.Interface, namespace, autoInject string, options *meshOptions, transform func(*appsV1.Deployment) (bool, error)) error {
+	if err := labelNamespace(clientset, namespace, autoInject); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Labeled the %s namespace with %s=%s\n", namespace, k8s.ProxyAutoInjectLabel, autoInject)
+
+	rolledOut, err := rolloutDeployments(w, clientset, namespace, transform, !options.restart)
+	if err != nil {
+		return err
+	}
+	if !options.restart {
+		if len(rolledOut) > 0 {
+			fmt.Fprintf(w, "%d existing deployment(s) of the namespace aren't rolled out yet; run the command with --restart to roll them out\n", len(rolledOut))
+		}
+		return nil
+	}
+	if len(rolledOut) == 0 {
+		fmt.Fprintln(w, "No deployments of the namespace had to be rolled out")
+		return nil
+	}
+	return waitForRollouts(w, clientset, namespace, rolledOut, options.timeout)
+}
+
+// labelNamespace sets the ProxyAutoInjectLabel label of the namespace.
+func labelNamespace(clientset kubernetes.Interface, namespace, autoInject string) error {
+	ns, err := clientset.CoreV1().Namespaces().Get(namespace, metaV1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return fmt.Errorf("The %s namespace doesn't exist", namespace)
+		}
+		return err
+	}
+
+	if ns.Labels[k8s.ProxyAutoInjectLabel] == autoInject {
+		return nil
+	}
+	if ns.Labels == nil {
+		ns.Labels = map[string]string{}
+	}
+	ns.Labels[k8s.ProxyAutoInjectLabel] = autoInject
+	_, err = clientset.CoreV1().Namespaces().Update(ns)
+	return err
+}
+
+// rolloutDeployments updates the deployments of the namespace that transform
+// changes, and returns their names. With dryRun, the deployments aren't
+// updated.
+func rolloutDeployments(w io.Writer, clientset kubernetes.Interface, namespace string, transform func(*appsV1.Deployment) (bool, error), dryRun bool) ([]string, error) {
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	rolledOut := []string{}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		changed, err := transform(deployment)
+		if err != nil {
+			return nil, err
+		}
+		if !changed {
+			continue
+		}
+		if !dryRun {
+			if _, err := clientset.AppsV1().Deployments(namespace).Update(deployment); err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(w, "deployment/%s is rolling out\n", deployment.Name)
+		}
+		rolledOut = append(rolledOut, deployment.Name)
+	}
+	return rolledOut, nil
+}
+
+// injectDeployment injects the pod template of the deployment as `linkerd
+// inject` does, and returns true if it was injected. Deployments that
+// already have the proxy are left untouched.
+func injectDeployment(deployment *appsV1.Deployment, options *injectOptions) (bool, error) {
+	if hasProxyContainer(&deployment.Spec.Template.Spec) {
+		return false, nil
+	}
+
+	conf := &resourceConfig{
+		meta:       metaV1.TypeMeta{Kind: "Deployment"},
+		om:         objMeta{deployment.ObjectMeta},
+		podSpec:    &deployment.Spec.Template.Spec,
+		objectMeta: &deployment.Spec.Template.ObjectMeta,
+	}
+	if err := checkProxyConflicts(conf, options); err != nil {
+		return false, err
+	}
+
+	identity := k8s.TLSIdentity{
+		Name:                deployment.Name,
+		Kind:                "deployment",
+		Namespace:           "$" + PodNamespaceEnvVarName,
+		ControllerNamespace: controlPlaneNamespace,
+	}
+	report := injectReport{kind: "deployment", name: deployment.Name}
+	if !injectPodSpec(&deployment.Spec.Template.Spec, identity, "", options, &report) {
+		return false, nil
+	}
+	injectObjectMeta(&deployment.Spec.Template.ObjectMeta, map[string]string{k8s.ProxyDeploymentLabel: deployment.Name}, options)
+	return true, nil
+}
+
+// uninjectDeployment removes the proxy from the pod template of the
+// deployment, and returns true if it had one.
+func uninjectDeployment(deployment *appsV1.Deployment) bool {
+	if !hasProxyContainer(&deployment.Spec.Template.Spec) {
+		return false
+	}
+
+	report := injectReport{kind: "deployment", name: deployment.Name}
+	uninjectPodSpec(&deployment.Spec.Template.Spec, nil, &report)
+	uninjectObjectMeta(&deployment.Spec.Template.ObjectMeta, nil)
+	return true
+}
+
+func hasProxyContainer(spec *v1.PodSpec) bool {
+	for _, container := range spec.Containers {
+		if container.Name == k8s.ProxyContainerName {
+			return true
+		}
+	}
+	return false
+}
+
+// waitForRollouts polls the deployments until their rollout completes,
+// printing their progress whenever it changes.
+func waitForRollouts(w io.Writer, clientset kubernetes.Interface, namespace string, names []string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	pending := names
+	lastStatus := map[string]string{}
+
+	for {
+		stillPending := []string{}
+		for _, name := range pending {
+			deployment, err := clientset.AppsV1().Deployments(namespace).Get(name, metaV1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			status, done := rolloutStatus(deployment)
+			if status != lastStatus[name] {
+				fmt.Fprintf(w, "deployment/%s: %s\n", name, status)
+				lastStatus[name] = status
+			}
+			if !done {
+				stillPending = append(stillPending, name)
+			}
+		}
+
+		pending = stillPending
+		if len(pending) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Timed out waiting for the rollout of deployment/%s", strings.Join(pending, ", deployment/"))
+		}
+		time.Sleep(rolloutPollInterval)
+	}
+}
+
+// rolloutStatus describes the progress of the rollout of the deployment, as
+// `kubectl rollout status` does, and returns true if it's complete.
+func rolloutStatus(deployment *appsV1.Deployment) (string, bool) {
+	if deployment.Generation > deployment.Status.ObservedGeneration {
+		return "waiting for the rollout to start", false
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	switch {
+	case status.UpdatedReplicas < replicas:
+		return fmt.Sprintf("%d of %d new replicas have been updated", status.UpdatedReplicas, replicas), false
+	case status.Replicas > status.UpdatedReplicas:
+		return fmt.Sprintf("%d old replicas are pending termination", status.Replicas-status.UpdatedReplicas), false
+	case status.AvailableReplicas < status.UpdatedReplicas:
+		return fmt.Sprintf("%d of %d updated replicas are available", status.AvailableReplicas, status.UpdatedReplicas), false
+	}
+	return "successfully rolled out", true
+}
+
+// runMeshChecks runs the checks of `linkerd check --proxy` on the namespace.
+func runMeshChecks(w io.Writer, namespace string, timeout time.Duration) bool {
+	checks := []healthcheck.CategoryID{
+		healthcheck.KubernetesAPIChecks,
+		healthcheck.LinkerdControlPlaneExistenceChecks,
+		healthcheck.LinkerdAPIChecks,
+		healthcheck.LinkerdDataPlaneChecks,
+	}
+
+	hc := healthcheck.NewHealthChecker(checks, &healthcheck.Options{
+		ControlPlaneNamespace: controlPlaneNamespace,
+		DataPlaneNamespace:    namespace,
+		KubeConfig:            kubeconfigPath,
+		KubeContext:           kubeContext,
+		Impersonate:           impersonate,
+		ImpersonateGroup:      impersonateGroup,
+		APIAddr:               apiAddr,
+		MutualTLS:             apiTLS,
+		RetryDeadline:         time.Now().Add(timeout),
+	})
+
+	return runChecks(w, hc)
+}
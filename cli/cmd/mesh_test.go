@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseMeshNamespace(t *testing.T) {
+	testCases := []struct {
+		arg       string
+		namespace string
+		err       string
+	}{
+		{arg: "emojivoto", namespace: "emojivoto"},
+		{arg: "ns/emojivoto", namespace: "emojivoto"},
+		{arg: "namespace/emojivoto", namespace: "emojivoto"},
+		{arg: "deploy/web", err: "Invalid namespace 'deploy/web': must be of the form ns/NAME or NAME"},
+		{arg: "ns/Emojivoto", err: "Invalid namespace 'Emojivoto': a DNS-1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')"},
+		{arg: "ns/linkerd", err: "The linkerd namespace is the control plane namespace, whose proxies are managed by \"linkerd install\""},
+	}
+
+	for _, tc := range testCases {
+		namespace, err := parseMeshNamespace(tc.arg)
+		if tc.err == "" {
+			if err != nil {
+				t.Fatalf("Unexpected error for %s: %s", tc.arg, err)
+			}
+			if namespace != tc.namespace {
+				t.Fatalf("Expected namespace %s for %s, got %s", tc.namespace, tc.arg, namespace)
+			}
+		} else if err == nil || err.Error() != tc.err {
+			t.Fatalf("Expected error %q for %s, got: %v", tc.err, tc.arg, err)
+		}
+	}
+}
+
+func meshTestDeployment(name string, containers ...string) *appsV1.Deployment {
+	deployment := &appsV1.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "emojivoto"},
+		Status:     appsV1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+	}
+	for _, container := range containers {
+		deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers, v1.Container{Name: container, Image: container})
+	}
+	return deployment
+}
+
+func TestRunMesh(t *testing.T) {
+	rolloutPollInterval = time.Millisecond
+
+	t.Run("Labels the namespace and injects the existing deployments", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(
+			&v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "emojivoto"}},
+			meshTestDeployment("web", "web"),
+			meshTestDeployment("emoji", "emoji", k8s.ProxyContainerName),
+		)
+		options := newMeshOptions()
+		options.restart = true
+
+		buf := &bytes.Buffer{}
+		err := runMesh(buf, clientset, "emojivoto", k8s.ProxyAutoInjectEnabled, options, func(d *appsV1.Deployment) (bool, error) {
+			return injectDeployment(d, options.injectOptions)
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		ns, err := clientset.CoreV1().Namespaces().Get("emojivoto", metaV1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if ns.Labels[k8s.ProxyAutoInjectLabel] != k8s.ProxyAutoInjectEnabled {
+			t.Fatalf("Expected the namespace to be labeled, got %v", ns.Labels)
+		}
+
+		web, err := clientset.AppsV1().Deployments("emojivoto").Get("web", metaV1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !hasProxyContainer(&web.Spec.Template.Spec) {
+			t.Fatal("Expected the web deployment to be injected")
+		}
+		if web.Spec.Template.Labels[k8s.ProxyDeploymentLabel] != "web" {
+			t.Fatalf("Expected the pod template of the web deployment to be labeled, got %v", web.Spec.Template.Labels)
+		}
+
+		expected := `Labeled the emojivoto namespace with linkerd.io/auto-inject=enabled
+deployment/web is rolling out
+deployment/web: successfully rolled out
+`
+		if buf.String() != expected {
+			t.Fatalf("Expected output:\n%s\ngot:\n%s", expected, buf.String())
+		}
+	})
+
+	t.Run("Labels the namespace without rolling out the deployments", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(
+			&v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "emojivoto"}},
+			meshTestDeployment("web", "web", k8s.ProxyContainerName),
+		)
+
+		buf := &bytes.Buffer{}
+		err := runMesh(buf, clientset, "emojivoto", k8s.ProxyAutoInjectDisabled, newMeshOptions(), func(d *appsV1.Deployment) (bool, error) {
+			return uninjectDeployment(d), nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		web, err := clientset.AppsV1().Deployments("emojivoto").Get("web", metaV1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !hasProxyContainer(&web.Spec.Template.Spec) {
+			t.Fatal("Expected the web deployment not to be updated without --restart")
+		}
+
+		expected := `Labeled the emojivoto namespace with linkerd.io/auto-inject=disabled
+1 existing deployment(s) of the namespace aren't rolled out yet; run the command with --restart to roll them out
+`
+		if buf.String() != expected {
+			t.Fatalf("Expected output:\n%s\ngot:\n%s", expected, buf.String())
+		}
+	})
+
+	t.Run("Fails if the namespace doesn't exist", func(t *testing.T) {
+		err := runMesh(&bytes.Buffer{}, fake.NewSimpleClientset(), "emojivoto", k8s.ProxyAutoInjectEnabled, newMeshOptions(), func(d *appsV1.Deployment) (bool, error) {
+			return false, nil
+		})
+		expected := "The emojivoto namespace doesn't exist"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q, got: %v", expected, err)
+		}
+	})
+}
+
+func TestRolloutStatus(t *testing.T) {
+	three := int32(3)
+
+	testCases := []struct {
+		generation int64
+		status     appsV1.DeploymentStatus
+		expected   string
+		done       bool
+	}{
+		{
+			generation: 2,
+			status:     appsV1.DeploymentStatus{ObservedGeneration: 1},
+			expected:   "waiting for the rollout to start",
+		},
+		{
+			status:   appsV1.DeploymentStatus{Replicas: 4, UpdatedReplicas: 1},
+			expected: "1 of 3 new replicas have been updated",
+		},
+		{
+			status:   appsV1.DeploymentStatus{Replicas: 4, UpdatedReplicas: 3},
+			expected: "1 old replicas are pending termination",
+		},
+		{
+			status:   appsV1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 2},
+			expected: "2 of 3 updated replicas are available",
+		},
+		{
+			status:   appsV1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3},
+			expected: "successfully rolled out",
+			done:     true,
+		},
+	}
+
+	for i, tc := range testCases {
+		deployment := &appsV1.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Generation: tc.generation},
+			Spec:       appsV1.DeploymentSpec{Replicas: &three},
+			Status:     tc.status,
+		}
+		status, done := rolloutStatus(deployment)
+		if status != tc.expected || done != tc.done {
+			t.Fatalf("test case %d: expected (%q, %t), got (%q, %t)", i, tc.expected, tc.done, status, done)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type metricsOptions struct {
+	namespace string
+	obfuscate bool
+}
+
+func newMetricsOptions() *metricsOptions {
+	return &metricsOptions{
+		namespace: "default",
+		obfuscate: false,
+	}
+}
+
+// podMetrics are the proxy metrics of a pod.
+type podMetrics struct {
+	pod     string
+	metrics []byte
+}
+
+// ipPattern matches the IPv4 addresses of the metrics, and the candidates for
+// IPv6 addresses, which are checked with net.ParseIP before they're obfuscated.
+var ipPattern = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b|[0-9A-Fa-f]*:[0-9A-Fa-f:]*:[0-9A-Fa-f.]*`)
+
+func newCmdMetrics() *cobra.Command {
+	options := newMetricsOptions()
+
+	cmd := &cobra.Command{
+		Use:   "metrics [flags] (RESOURCE)",
+		Short: "Fetch the proxy metrics of a pod, deployment or daemonset",
+		Long: `Fetch the proxy metrics of a pod, deployment or daemonset.
+
+The RESOURCE argument is a pod, deployment or daemonset, as TYPE/NAME or
+TYPE NAME; a bare NAME is a pod. The metrics of a deployment or daemonset are
+those of its running, meshed pods, concatenated per metric with a pod label
+that holds the name of the pod that they come from.
+
+With --obfuscate, the IP addresses of the metrics are replaced with
+placeholders, the same address with the same placeholder, so that the output
+can be attached to a bug report.`,
+		Example: `  # Fetch the proxy metrics of a pod.
+  linkerd metrics -n emojivoto pod/web-5f86686c4d-58p7k
+
+  # Fetch the proxy metrics of all the pods of a deployment, without their IP addresses.
+  linkerd metrics -n emojivoto deploy/web --obfuscate`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resourceType, name, err := parseMetricsTarget(args)
+			if err != nil {
+				return err
+			}
+
+			kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+			if err != nil {
+				return err
+			}
+			client, err := kubeAPI.NewClient()
+			if err != nil {
+				return err
+			}
+			clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+			if err != nil {
+				return err
+			}
+
+			pods, err := metricsPods(clientset, options.namespace, resourceType, name)
+			if err != nil {
+				return err
+			}
+			get := func(namespace, pod string, port int32, path string) ([]byte, error) {
+				return kubeAPI.GetPodPort(client, namespace, pod, port, path)
+			}
+			metrics, errs := collectProxyMetrics(pods, get)
+			for _, err := range errs {
+				fmt.Fprintf(os.Stderr, "Failed to fetch the proxy metrics: %s\n", err)
+			}
+			if len(metrics) == 0 {
+				return fmt.Errorf("No proxy metrics could be fetched for %s/%s", resourceType, name)
+			}
+
+			var output []byte
+			if resourceType == k8s.Pod {
+				output = metrics[0].metrics
+			} else {
+				output = aggregateMetrics(metrics)
+			}
+			if options.obfuscate {
+				output = obfuscateMetrics(output)
+			}
+			_, err = os.Stdout.Write(output)
+			return err
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the resource")
+	cmd.PersistentFlags().BoolVar(&options.obfuscate, "obfuscate", options.obfuscate, "Replace the IP addresses of the metrics with placeholders")
+
+	return cmd
+}
+
+// parseMetricsTarget returns the canonical type and the name of the resource
+// of the arguments of `linkerd metrics`.
+func parseMetricsTarget(args []string) (string, string, error) {
+	var resourceType, name string
+	switch {
+	case len(args) == 2:
+		resourceType, name = args[0], args[1]
+	case strings.Contains(args[0], "/"):
+		parts := strings.SplitN(args[0], "/", 2)
+		resourceType, name = parts[0], parts[1]
+	default:
+		resourceType, name = k8s.Pod, args[0]
+	}
+	if name == "" {
+		return "", "", fmt.Errorf("Invalid resource '%s': the name of the resource is missing", strings.Join(args, " "))
+	}
+
+	canonicalType, err := k8s.CanonicalResourceNameFromFriendlyName(resourceType)
+	if err != nil {
+		return "", "", err
+	}
+	switch canonicalType {
+	case k8s.Pod, k8s.Deployment, k8s.DaemonSet:
+		return canonicalType, name, nil
+	default:
+		return "", "", fmt.Errorf("Invalid resource type '%s': the metrics of pods, deployments and daemonsets can be fetched", resourceType)
+	}
+}
+
+// metricsPods returns the pods of the resource whose proxy metrics are
+// fetched: the pod itself, or the running, meshed pods of a deployment or
+// daemonset.
+func metricsPods(clientset kubernetes.Interface, namespace, resourceType, name string) ([]v1.Pod, error) {
+	var selector *metaV1.LabelSelector
+	switch resourceType {
+	case k8s.Pod:
+		pod, err := clientset.CoreV1().Pods(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if !hasProxyContainer(&pod.Spec) {
+			return nil, fmt.Errorf("The pod %s/%s has no %s container", namespace, name, k8s.ProxyContainerName)
+		}
+		return []v1.Pod{*pod}, nil
+	case k8s.Deployment:
+		deploy, err := clientset.AppsV1().Deployments(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = deploy.Spec.Selector
+	case k8s.DaemonSet:
+		ds, err := clientset.AppsV1().DaemonSets(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = ds.Spec.Selector
+	default:
+		return nil, fmt.Errorf("The metrics of the %s resources can't be fetched", resourceType)
+	}
+
+	labelSelector, err := metaV1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	list, err := clientset.CoreV1().Pods(namespace).List(metaV1.ListOptions{LabelSelector: labelSelector.String()})
+	if err != nil {
+		return nil, err
+	}
+	pods := []v1.Pod{}
+	for _, pod := range sortedBundlePods(list.Items) {
+		if pod.Status.Phase == v1.PodRunning && hasProxyContainer(&pod.Spec) {
+			pods = append(pods, pod)
+		}
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("The %s %s/%s has no running, meshed pods", resourceType, namespace, name)
+	}
+	return pods, nil
+}
+
+// collectProxyMetrics returns the proxy metrics of the pods, along with the
+// errors of the pods whose metrics couldn't be fetched.
+func collectProxyMetrics(pods []v1.Pod, get podPortFunc) ([]podMetrics, []error) {
+	metrics := []podMetrics{}
+	errs := []error{}
+	for _, pod := range pods {
+		port := int32(defaultBundleProxyMetricsPort)
+		for _, container := range pod.Spec.Containers {
+			if container.Name == k8s.ProxyContainerName {
+				port = bundleProxyMetricsPort(container)
+			}
+		}
+
+		content, err := get(pod.Namespace, pod.Name, port, "/metrics")
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %s", pod.Namespace, pod.Name, err))
+			continue
+		}
+		metrics = append(metrics, podMetrics{pod: pod.Name, metrics: content})
+	}
+	return metrics, errs
+}
+
+// aggregateMetrics concatenates the metrics of the pods, with a pod label on
+// every sample. The samples are grouped per metric, after the HELP and TYPE
+// comments of their first pod, so that the output stays valid Prometheus text.
+func aggregateMetrics(metrics []podMetrics) []byte {
+	order := []string{}
+	comments := map[string][]string{}
+	samples := map[string][]string{}
+	owners := map[string]int{}
+
+	for i, m := range metrics {
+		family := ""
+		scanner := bufio.NewScanner(bytes.NewReader(m.metrics))
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if strings.HasPrefix(line, "#") {
+				fields := strings.Fields(line)
+				if len(fields) < 3 || (fields[1] != "HELP" && fields[1] != "TYPE") {
+					continue
+				}
+				family = fields[2]
+				if _, ok := owners[family]; !ok {
+					owners[family] = i
+					order = append(order, family)
+				}
+				// only the comments of the first pod of a metric are kept
+				if owners[family] == i {
+					comments[family] = append(comments[family], line)
+				}
+				continue
+			}
+			if _, ok := owners[family]; !ok {
+				owners[family] = i
+				order = append(order, family)
+			}
+			samples[family] = append(samples[family], withPodLabel(line, m.pod))
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	for _, family := range order {
+		for _, line := range comments[family] {
+			buf.WriteString(line + "\n")
+		}
+		for _, line := range samples[family] {
+			buf.WriteString(line + "\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+// withPodLabel adds a pod label to a sample line.
+func withPodLabel(line, pod string) string {
+	label := fmt.Sprintf("pod=%q", pod)
+	if i := strings.IndexAny(line, "{ "); i >= 0 {
+		if line[i] == '{' {
+			if strings.HasPrefix(line[i+1:], "}") {
+				return line[:i+1] + label + line[i+1:]
+			}
+			return line[:i+1] + label + "," + line[i+1:]
+		}
+		return line[:i] + "{" + label + "}" + line[i:]
+	}
+	return line
+}
+
+// obfuscateMetrics replaces the IP addresses of the metrics with
+// placeholders, the same address with the same placeholder.
+func obfuscateMetrics(metrics []byte) []byte {
+	placeholders := map[string]string{}
+	placeholder := func(ip string) string {
+		if p, ok := placeholders[ip]; ok {
+			return p
+		}
+		p := fmt.Sprintf("<ip-%d>", len(placeholders)+1)
+		placeholders[ip] = p
+		return p
+	}
+
+	obfuscated := ipPattern.ReplaceAllStringFunc(string(metrics), func(ip string) string {
+		if net.ParseIP(ip) == nil {
+			return ip
+		}
+		return placeholder(ip)
+	})
+	return []byte(obfuscated)
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseMetricsTarget(t *testing.T) {
+	testCases := []struct {
+		args         []string
+		resourceType string
+		name         string
+		err          bool
+	}{
+		{args: []string{"web-5f86686c4d-58p7k"}, resourceType: k8s.Pod, name: "web-5f86686c4d-58p7k"},
+		{args: []string{"po/web-5f86686c4d-58p7k"}, resourceType: k8s.Pod, name: "web-5f86686c4d-58p7k"},
+		{args: []string{"deploy/web"}, resourceType: k8s.Deployment, name: "web"},
+		{args: []string{"daemonset", "node-agent"}, resourceType: k8s.DaemonSet, name: "node-agent"},
+		{args: []string{"svc/web"}, err: true},
+		{args: []string{"deploy/"}, err: true},
+		{args: []string{"nonsense/web"}, err: true},
+	}
+
+	for _, tc := range testCases {
+		resourceType, name, err := parseMetricsTarget(tc.args)
+		if tc.err {
+			if err == nil {
+				t.Fatalf("Expected an error for %v", tc.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error for %v: %s", tc.args, err)
+		}
+		if resourceType != tc.resourceType || name != tc.name {
+			t.Fatalf("Expected %s/%s for %v, got %s/%s", tc.resourceType, tc.name, tc.args, resourceType, name)
+		}
+	}
+}
+
+func TestCollectProxyMetrics(t *testing.T) {
+	pods := []v1.Pod{
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web-1", Namespace: "emojivoto"},
+			Spec: v1.PodSpec{Containers: []v1.Container{
+				{Name: k8s.ProxyContainerName, Ports: []v1.ContainerPort{{Name: "linkerd-metrics", ContainerPort: 9998}}},
+			}},
+		},
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web-2", Namespace: "emojivoto"},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: k8s.ProxyContainerName}}},
+		},
+	}
+
+	ports := map[string]int32{}
+	get := func(namespace, pod string, port int32, path string) ([]byte, error) {
+		ports[pod] = port
+		if pod == "web-2" {
+			return nil, errors.New("connection refused")
+		}
+		return []byte("metrics of " + pod), nil
+	}
+
+	metrics, errs := collectProxyMetrics(pods, get)
+	if !reflect.DeepEqual(metrics, []podMetrics{{pod: "web-1", metrics: []byte("metrics of web-1")}}) {
+		t.Fatalf("Unexpected metrics: %+v", metrics)
+	}
+	if len(errs) != 1 || errs[0].Error() != "emojivoto/web-2: connection refused" {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	if ports["web-1"] != 9998 || ports["web-2"] != defaultBundleProxyMetricsPort {
+		t.Fatalf("Unexpected metrics ports: %v", ports)
+	}
+}
+
+func TestAggregateMetrics(t *testing.T) {
+	metrics := []podMetrics{
+		{pod: "web-1", metrics: []byte(`# HELP request_total Total count of HTTP requests.
+# TYPE request_total counter
+request_total{direction="inbound",authority="10.1.2.3:8080"} 5
+# HELP process_start_time_seconds Time that the process started.
+# TYPE process_start_time_seconds gauge
+process_start_time_seconds 1546300800
+`)},
+		{pod: "web-2", metrics: []byte(`# HELP request_total Total count of HTTP requests.
+# TYPE request_total counter
+request_total{} 7
+# HELP process_start_time_seconds Time that the process started.
+# TYPE process_start_time_seconds gauge
+process_start_time_seconds 1546300900
+`)},
+	}
+
+	expected := `# HELP request_total Total count of HTTP requests.
+# TYPE request_total counter
+request_total{pod="web-1",direction="inbound",authority="10.1.2.3:8080"} 5
+request_total{pod="web-2"} 7
+# HELP process_start_time_seconds Time that the process started.
+# TYPE process_start_time_seconds gauge
+process_start_time_seconds{pod="web-1"} 1546300800
+process_start_time_seconds{pod="web-2"} 1546300900
+`
+	if output := string(aggregateMetrics(metrics)); output != expected {
+		t.Fatalf("Expected output:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+func TestObfuscateMetrics(t *testing.T) {
+	metrics := `tcp_open_total{peer="src",client="10.1.2.3:43210",server="10.1.2.4:8080"} 1
+tcp_open_total{peer="dst",client="10.1.2.3:43211",server="[fd00::1]:8080"} 2
+tcp_close_total{le="0.005",time="12:30:45"} 3
+`
+	expected := `tcp_open_total{peer="src",client="<ip-1>:43210",server="<ip-2>:8080"} 1
+tcp_open_total{peer="dst",client="<ip-1>:43211",server="[<ip-3>]:8080"} 2
+tcp_close_total{le="0.005",time="12:30:45"} 3
+`
+	if output := string(obfuscateMetrics([]byte(metrics))); output != expected {
+		t.Fatalf("Expected output:\n%s\ngot:\n%s", expected, output)
+	}
+}
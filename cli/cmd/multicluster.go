@@ -0,0 +1,545 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/linkerd/linkerd2/cli/install"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// defaultRemoteAccessServiceAccount is the service account that the service
+// mirrors of other clusters watch the services of a cluster with.
+const defaultRemoteAccessServiceAccount = "linkerd-service-mirror-remote-access"
+
+type remoteAccessConfig struct {
+	Namespace           string
+	ServiceAccountName  string
+	CreatedByAnnotation string
+	CliVersion          string
+}
+
+type linkConfig struct {
+	Namespace                string
+	ClusterName              string
+	ClusterDomain            string
+	CredentialsSecret        string
+	CredentialsKey           string
+	Kubeconfig               string
+	GatewayAddress           string
+	GatewayPort              uint
+	GatewayIdentity          string
+	ProbePath                string
+	ProbePort                uint
+	ProbePeriod              string
+	Selector                 map[string]string
+	ServiceMirrorName        string
+	ControllerImage          string
+	ImagePullPolicy          string
+	ControllerLogLevel       string
+	ControllerUID            int64
+	ControllerComponentLabel string
+	RemoteClusterNameLabel   string
+	CreatedByAnnotation      string
+	CliVersion               string
+}
+
+type linkOptions struct {
+	clusterName        string
+	clusterDomain      string
+	apiServerAddress   string
+	serviceAccountName string
+	gatewayAddress     string
+	gatewayPort        uint
+	gatewayIdentity    string
+	probePath          string
+	probePort          uint
+	probePeriod        time.Duration
+	selector           string
+	controllerLogLevel string
+	*proxyConfigOptions
+}
+
+func newLinkOptions() *linkOptions {
+	return &linkOptions{
+		clusterName:        "",
+		clusterDomain:      "cluster.local",
+		apiServerAddress:   "",
+		serviceAccountName: defaultRemoteAccessServiceAccount,
+		gatewayAddress:     "",
+		gatewayPort:        4143,
+		gatewayIdentity:    "",
+		probePath:          "/health",
+		probePort:          4181,
+		probePeriod:        3 * time.Second,
+		selector:           k8s.DefaultExportedServiceSelector,
+		controllerLogLevel: "info",
+		proxyConfigOptions: newProxyConfigOptions(),
+	}
+}
+
+func newCmdMulticluster() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "multicluster [flags]",
+		Short: "Mirror the services of other clusters into this one",
+		Long: `Mirror the services of other clusters into this one.
+
+A remote cluster is linked to this cluster by a Link resource, which holds the
+credentials that a service mirror watches the remote services with, and the
+address of the gateway of the remote cluster. Each selected remote service is
+mirrored as a service named after it and the remote cluster, e.g. "web-east"
+for the "web" service of the "east" cluster, in the same namespace if it exists
+in this cluster. The endpoints of the mirrored service are the gateway, which
+forwards the traffic to the remote service.`,
+	}
+
+	cmd.AddCommand(newCmdMulticlusterAllow())
+	cmd.AddCommand(newCmdMulticlusterLink())
+	cmd.AddCommand(newCmdMulticlusterUnlink())
+	cmd.AddCommand(newCmdMulticlusterGateways())
+
+	return cmd
+}
+
+func newCmdMulticlusterAllow() *cobra.Command {
+	serviceAccountName := defaultRemoteAccessServiceAccount
+
+	cmd := &cobra.Command{
+		Use:   "allow [flags]",
+		Short: "Output the service account that other clusters mirror the services of this cluster with",
+		Long: `Output the service account that other clusters mirror the services of this cluster with.
+
+The service account can only list and watch services. It's applied to the
+cluster whose services are mirrored, before linking it to other clusters with
+"linkerd multicluster link".`,
+		Example: `  # Allow other clusters to mirror the services of the east cluster.
+  linkerd --context=east multicluster allow | kubectl --context=east apply -f -`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if errs := validation.IsDNS1123Subdomain(serviceAccountName); len(errs) > 0 {
+				return fmt.Errorf("Invalid value '%s' for --service-account-name flag: %s", serviceAccountName, errs[0])
+			}
+			return renderRemoteAccess(os.Stdout, remoteAccessConfig{
+				Namespace:           controlPlaneNamespace,
+				ServiceAccountName:  serviceAccountName,
+				CreatedByAnnotation: k8s.CreatedByAnnotation,
+				CliVersion:          k8s.CreatedByAnnotationValue(),
+			})
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&serviceAccountName, "service-account-name", serviceAccountName, "Name of the service account in the control plane namespace")
+
+	return cmd
+}
+
+func newCmdMulticlusterLink() *cobra.Command {
+	options := newLinkOptions()
+
+	cmd := &cobra.Command{
+		Use:   "link [flags]",
+		Short: "Output the resources that link the current cluster to another one",
+		Long: `Output the resources that link the current cluster to another one.
+
+The command runs against the cluster whose services are mirrored, and reads the
+token of the service account created by "linkerd multicluster allow" to build
+the credentials of the service mirror. Its output is applied to the cluster
+that the services are mirrored into: the Link, the secret with the credentials,
+and the service mirror with its RBAC resources.
+
+The link takes effect when the service mirror starts, and changes to it when the
+service mirror restarts. Linkerd doesn't deploy the gateway of the remote
+cluster, whose address is given with --gateway-address.`,
+		Example: `  # Mirror the services of the east cluster into the west cluster.
+  linkerd --context=east multicluster link --cluster-name=east --gateway-address=203.0.113.10 | kubectl --context=west apply -f -`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			selector, err := options.validate()
+			if err != nil {
+				return err
+			}
+
+			kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+			if err != nil {
+				return err
+			}
+			clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+			if err != nil {
+				return err
+			}
+
+			server := options.apiServerAddress
+			if server == "" {
+				server = kubeAPI.Config.Host
+			}
+			kubeconfig, err := remoteKubeconfig(clientset, controlPlaneNamespace, options.serviceAccountName, options.clusterName, server)
+			if err != nil {
+				return err
+			}
+
+			return renderLink(os.Stdout, linkConfig{
+				Namespace:                controlPlaneNamespace,
+				ClusterName:              options.clusterName,
+				ClusterDomain:            options.clusterDomain,
+				CredentialsSecret:        clusterCredentialsSecret(options.clusterName),
+				CredentialsKey:           k8s.ClusterCredentialsKey,
+				Kubeconfig:               base64.StdEncoding.EncodeToString(kubeconfig),
+				GatewayAddress:           options.gatewayAddress,
+				GatewayPort:              options.gatewayPort,
+				GatewayIdentity:          options.gatewayIdentity,
+				ProbePath:                options.probePath,
+				ProbePort:                options.probePort,
+				ProbePeriod:              options.probePeriod.String(),
+				Selector:                 selector,
+				ServiceMirrorName:        serviceMirrorName(options.clusterName),
+				ControllerImage:          fmt.Sprintf("%s/controller:%s", options.dockerRegistry, options.linkerdVersion),
+				ImagePullPolicy:          options.imagePullPolicy,
+				ControllerLogLevel:       options.controllerLogLevel,
+				ControllerUID:            newInstallOptions().controllerUID,
+				ControllerComponentLabel: k8s.ControllerComponentLabel,
+				RemoteClusterNameLabel:   k8s.RemoteClusterNameLabel,
+				CreatedByAnnotation:      k8s.CreatedByAnnotation,
+				CliVersion:               k8s.CreatedByAnnotationValue(),
+			})
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&options.clusterName, "cluster-name", options.clusterName, "Name of the linked cluster, which suffixes the names of its mirrored services (required)")
+	cmd.PersistentFlags().StringVar(&options.clusterDomain, "cluster-domain", options.clusterDomain, "DNS domain of the linked cluster")
+	cmd.PersistentFlags().StringVar(&options.apiServerAddress, "api-server-address", options.apiServerAddress, "Address of the Kubernetes API of the linked cluster, as reachable from the other cluster (defaults to the address in the kubeconfig)")
+	cmd.PersistentFlags().StringVar(&options.serviceAccountName, "service-account-name", options.serviceAccountName, "Name of the service account, created by \"linkerd multicluster allow\", that the service mirror watches the linked cluster with")
+	cmd.PersistentFlags().StringVar(&options.gatewayAddress, "gateway-address", options.gatewayAddress, "Hostname or IP address of the gateway of the linked cluster (required)")
+	cmd.PersistentFlags().UintVar(&options.gatewayPort, "gateway-port", options.gatewayPort, "Port that the gateway of the linked cluster accepts the traffic of the mirrored services on")
+	cmd.PersistentFlags().StringVar(&options.gatewayIdentity, "gateway-identity", options.gatewayIdentity, "TLS identity of the gateway of the linked cluster, if it has one")
+	cmd.PersistentFlags().StringVar(&options.probePath, "gateway-probe-path", options.probePath, "Path of the health endpoint of the gateway of the linked cluster, which the service mirror probes")
+	cmd.PersistentFlags().UintVar(&options.probePort, "gateway-probe-port", options.probePort, "Port of the health endpoint of the gateway of the linked cluster")
+	cmd.PersistentFlags().DurationVar(&options.probePeriod, "gateway-probe-period", options.probePeriod, "Interval between the probes of the gateway of the linked cluster")
+	cmd.PersistentFlags().StringVar(&options.selector, "selector", options.selector, "Label selector of the services of the linked cluster to mirror, of the form key=value[,key=value]")
+	cmd.PersistentFlags().StringVar(&options.controllerLogLevel, "controller-log-level", options.controllerLogLevel, "Log level for the service mirror")
+	cmd.PersistentFlags().StringVarP(&options.linkerdVersion, "linkerd-version", "v", options.linkerdVersion, "Tag to be used for the service mirror image")
+	cmd.PersistentFlags().StringVar(&options.dockerRegistry, "registry", options.dockerRegistry, "Docker registry to pull the service mirror image from")
+	cmd.PersistentFlags().StringVar(&options.imagePullPolicy, "image-pull-policy", options.imagePullPolicy, "Docker image pull policy")
+
+	return cmd
+}
+
+func newCmdMulticlusterUnlink() *cobra.Command {
+	clusterName := ""
+
+	cmd := &cobra.Command{
+		Use:   "unlink [flags]",
+		Short: "Output the resources to delete to unlink a cluster from the current one",
+		Long: `Output the resources to delete to unlink a cluster from the current one.
+
+The resources are output in the order in which they can be safely deleted: the
+service mirror first, so that it stops mirroring the services, then the
+mirrored services, and the Link, its credentials and the RBAC resources of the
+service mirror last.`,
+		Example: `  # Unlink the east cluster from the west cluster.
+  linkerd --context=west multicluster unlink --cluster-name=east | kubectl --context=west delete -f -`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if clusterName == "" {
+				return fmt.Errorf("The --cluster-name flag is required")
+			}
+
+			kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+			if err != nil {
+				return err
+			}
+			client, err := kubeAPI.NewClient()
+			if err != nil {
+				return err
+			}
+			clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+			if err != nil {
+				return err
+			}
+
+			exists := func(resource uninstallResource) (bool, error) {
+				r, err := kubeAPI.GetResource(client, resource.APIVersion, resource.Kind, resource.Metadata.Namespace, resource.Metadata.Name)
+				return r != nil, err
+			}
+			resources, err := unlinkResources(clientset, clusterName, exists)
+			if err != nil {
+				return err
+			}
+			return renderUninstall(os.Stdout, resources)
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&clusterName, "cluster-name", clusterName, "Name of the linked cluster (required)")
+
+	return cmd
+}
+
+func newCmdMulticlusterGateways() *cobra.Command {
+	req := &pb.GatewaysRequest{TimeWindow: "1m"}
+
+	cmd := &cobra.Command{
+		Use:   "gateways [flags]",
+		Short: "Display the gateways of the clusters linked to the current one",
+		Long: `Display the gateways of the clusters linked to the current one.
+
+For each linked cluster, the command displays whether its gateway is alive, the
+number of its services that are mirrored in the current cluster, and the
+latency of the probes that the service mirror sends to the gateway. A gateway
+is alive if the last probe succeeded; the latencies are those of the probes in
+the stat window, and aren't displayed when the gateway isn't alive.`,
+		Example: `  # Display the gateways of all the clusters linked to the west cluster.
+  linkerd --context=west multicluster gateways
+
+  # Display the gateway of the east cluster, with the latencies of the last 10 minutes.
+  linkerd --context=west multicluster gateways --cluster-name=east -t 10m`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, err := requestGatewaysFromAPI(cliPublicAPIClient(), req)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Print(output)
+			return err
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&req.RemoteClusterName, "cluster-name", req.RemoteClusterName, "Only display the gateway of this linked cluster")
+	cmd.PersistentFlags().StringVarP(&req.TimeWindow, "time-window", "t", req.TimeWindow, "Stat window over which the latencies of the probes are computed (for example: \"10s\", \"1m\", \"10m\", \"1h\")")
+
+	return cmd
+}
+
+func requestGatewaysFromAPI(client pb.ApiClient, req *pb.GatewaysRequest) (string, error) {
+	rsp, err := client.Gateways(context.Background(), req)
+	if err != nil {
+		return "", fmt.Errorf("Gateways API error: %v", err)
+	}
+
+	gateways := rsp.GetGateways()
+	if len(gateways) == 0 {
+		return "No clusters are linked to this cluster.\n", nil
+	}
+
+	var buffer bytes.Buffer
+	w := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(w, strings.Join([]string{"CLUSTER", "ADDRESS", "ALIVE", "PAIRED_SERVICES", "LATENCY_P50", "LATENCY_P95", "LATENCY_P99"}, "\t"))
+	for _, gateway := range gateways {
+		alive := "no"
+		p50, p95, p99 := "-", "-", "-"
+		if gateway.GetAlive() {
+			alive = "yes"
+			p50 = fmt.Sprintf("%dms", gateway.GetLatencyMsP50())
+			p95 = fmt.Sprintf("%dms", gateway.GetLatencyMsP95())
+			p99 = fmt.Sprintf("%dms", gateway.GetLatencyMsP99())
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n",
+			gateway.GetClusterName(),
+			gateway.GetAddress(),
+			alive,
+			gateway.GetPairedServices(),
+			p50,
+			p95,
+			p99,
+		)
+	}
+	w.Flush()
+
+	return buffer.String(), nil
+}
+
+// validate checks the options, and returns the labels of the selector.
+func (options *linkOptions) validate() (map[string]string, error) {
+	if options.clusterName == "" {
+		return nil, fmt.Errorf("The --cluster-name flag is required")
+	}
+	// the cluster name suffixes the names of services
+	if errs := validation.IsDNS1035Label(options.clusterName); len(errs) > 0 {
+		return nil, fmt.Errorf("Invalid value '%s' for --cluster-name flag: %s", options.clusterName, errs[0])
+	}
+	if errs := validation.IsDNS1123Subdomain(options.clusterDomain); len(errs) > 0 {
+		return nil, fmt.Errorf("Invalid value '%s' for --cluster-domain flag: %s", options.clusterDomain, errs[0])
+	}
+	if options.gatewayAddress == "" {
+		return nil, fmt.Errorf("The --gateway-address flag is required")
+	}
+	if options.gatewayPort == 0 || options.gatewayPort > 65535 {
+		return nil, fmt.Errorf("Invalid value '%d' for --gateway-port flag: must be between 1 and 65535", options.gatewayPort)
+	}
+	if !strings.HasPrefix(options.probePath, "/") {
+		return nil, fmt.Errorf("Invalid value '%s' for --gateway-probe-path flag: must be an absolute path", options.probePath)
+	}
+	if options.probePort == 0 || options.probePort > 65535 {
+		return nil, fmt.Errorf("Invalid value '%d' for --gateway-probe-port flag: must be between 1 and 65535", options.probePort)
+	}
+	if options.probePeriod < time.Second {
+		return nil, fmt.Errorf("Invalid value '%s' for --gateway-probe-period flag: must be at least 1s", options.probePeriod)
+	}
+	if errs := validation.IsDNS1123Subdomain(options.serviceAccountName); len(errs) > 0 {
+		return nil, fmt.Errorf("Invalid value '%s' for --service-account-name flag: %s", options.serviceAccountName, errs[0])
+	}
+
+	selector, err := labels.ConvertSelectorToLabelsMap(options.selector)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid value '%s' for --selector flag: %s", options.selector, err)
+	}
+	for key, value := range selector {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("Invalid value '%s' for --selector flag: %s", options.selector, errs[0])
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return nil, fmt.Errorf("Invalid value '%s' for --selector flag: %s", options.selector, errs[0])
+		}
+	}
+
+	if _, err := log.ParseLevel(options.controllerLogLevel); err != nil {
+		return nil, fmt.Errorf("--controller-log-level must be one of: panic, fatal, error, warn, info, debug")
+	}
+	return selector, options.proxyConfigOptions.validate()
+}
+
+// remoteKubeconfig returns a kubeconfig that authenticates to the cluster with
+// the token of the service account.
+func remoteKubeconfig(clientset kubernetes.Interface, namespace, serviceAccountName, clusterName, server string) ([]byte, error) {
+	serviceAccount, err := clientset.CoreV1().ServiceAccounts(namespace).Get(serviceAccountName, metaV1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("The %s service account doesn't exist in the %s namespace of the linked cluster; create it with \"linkerd multicluster allow\"", serviceAccountName, namespace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range serviceAccount.Secrets {
+		secret, err := clientset.CoreV1().Secrets(namespace).Get(ref.Name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if secret.Type != v1.SecretTypeServiceAccountToken {
+			continue
+		}
+
+		config := clientcmdapi.NewConfig()
+		config.Clusters[clusterName] = &clientcmdapi.Cluster{
+			Server:                   server,
+			CertificateAuthorityData: secret.Data[v1.ServiceAccountRootCAKey],
+		}
+		config.AuthInfos[serviceAccountName] = &clientcmdapi.AuthInfo{
+			Token: string(secret.Data[v1.ServiceAccountTokenKey]),
+		}
+		config.Contexts[clusterName] = &clientcmdapi.Context{
+			Cluster:  clusterName,
+			AuthInfo: serviceAccountName,
+		}
+		config.CurrentContext = clusterName
+		return clientcmd.Write(*config)
+	}
+
+	return nil, fmt.Errorf("The %s service account has no token yet", serviceAccountName)
+}
+
+// unlinkResources returns the resources of the link to the cluster that exist,
+// in the order in which to delete them.
+func unlinkResources(clientset kubernetes.Interface, clusterName string, exists uninstallResourceFunc) ([]uninstallResource, error) {
+	name := serviceMirrorName(clusterName)
+
+	resources := []uninstallResource{}
+	deployment := newUninstallResource("apps/v1", "Deployment", controlPlaneNamespace, name)
+	ok, err := exists(deployment)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get %s: %s", deployment, err)
+	}
+	if ok {
+		resources = append(resources, deployment)
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{
+		k8s.MirroredServiceLabel:   "true",
+		k8s.RemoteClusterNameLabel: clusterName,
+	})
+	services, err := clientset.CoreV1().Services("").List(metaV1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	mirrored := []uninstallResource{}
+	for _, service := range services.Items {
+		mirrored = append(mirrored, newUninstallResource("v1", "Service", service.Namespace, service.Name))
+	}
+	sort.Slice(mirrored, func(i, j int) bool {
+		if mirrored[i].Metadata.Namespace != mirrored[j].Metadata.Namespace {
+			return mirrored[i].Metadata.Namespace < mirrored[j].Metadata.Namespace
+		}
+		return mirrored[i].Metadata.Name < mirrored[j].Metadata.Name
+	})
+	resources = append(resources, mirrored...)
+
+	for _, resource := range []uninstallResource{
+		newUninstallResource("linkerd.io/v1alpha1", "Link", controlPlaneNamespace, clusterName),
+		newUninstallResource("v1", "Secret", controlPlaneNamespace, clusterCredentialsSecret(clusterName)),
+		newUninstallResource("rbac.authorization.k8s.io/v1beta1", "RoleBinding", controlPlaneNamespace, name),
+		newUninstallResource("rbac.authorization.k8s.io/v1beta1", "Role", controlPlaneNamespace, name),
+		newUninstallResource("rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding", "", fmt.Sprintf("linkerd-%s-%s", controlPlaneNamespace, name)),
+		newUninstallResource("rbac.authorization.k8s.io/v1beta1", "ClusterRole", "", fmt.Sprintf("linkerd-%s-%s", controlPlaneNamespace, name)),
+		newUninstallResource("v1", "ServiceAccount", controlPlaneNamespace, name),
+	} {
+		ok, err := exists(resource)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get %s: %s", resource, err)
+		}
+		if ok {
+			resources = append(resources, resource)
+		}
+	}
+
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("The %s cluster isn't linked to this cluster", clusterName)
+	}
+	return resources, nil
+}
+
+func renderRemoteAccess(w io.Writer, config remoteAccessConfig) error {
+	return renderTemplate(w, "remote-access", install.RemoteAccessTemplate, config)
+}
+
+func renderLink(w io.Writer, config linkConfig) error {
+	return renderTemplate(w, "link", install.LinkTemplate, config)
+}
+
+func renderTemplate(w io.Writer, name, text string, config interface{}) error {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, config); err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func serviceMirrorName(clusterName string) string {
+	return "linkerd-service-mirror-" + clusterName
+}
+
+func clusterCredentialsSecret(clusterName string) string {
+	return "cluster-credentials-" + clusterName
+}
@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/linkerd/linkerd2/controller/api/public"
+	"github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestLinkOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		title   string
+		options func(*linkOptions)
+		err     string
+	}{
+		{
+			title:   "accepts the required flags",
+			options: func(*linkOptions) {},
+		},
+		{
+			title:   "requires a cluster name",
+			options: func(o *linkOptions) { o.clusterName = "" },
+			err:     "The --cluster-name flag is required",
+		},
+		{
+			title:   "rejects cluster names that can't suffix service names",
+			options: func(o *linkOptions) { o.clusterName = "East.1" },
+			err:     "Invalid value 'East.1' for --cluster-name flag: a DNS-1035 label must consist of lower case alphanumeric characters or '-', start with an alphabetic character, and end with an alphanumeric character (e.g. 'my-name',  or 'abc-123', regex used for validation is '[a-z]([-a-z0-9]*[a-z0-9])?')",
+		},
+		{
+			title:   "requires a gateway address",
+			options: func(o *linkOptions) { o.gatewayAddress = "" },
+			err:     "The --gateway-address flag is required",
+		},
+		{
+			title:   "rejects invalid gateway ports",
+			options: func(o *linkOptions) { o.gatewayPort = 70000 },
+			err:     "Invalid value '70000' for --gateway-port flag: must be between 1 and 65535",
+		},
+		{
+			title:   "rejects relative probe paths",
+			options: func(o *linkOptions) { o.probePath = "health" },
+			err:     "Invalid value 'health' for --gateway-probe-path flag: must be an absolute path",
+		},
+		{
+			title:   "rejects probe periods shorter than a second",
+			options: func(o *linkOptions) { o.probePeriod = 500 * time.Millisecond },
+			err:     "Invalid value '500ms' for --gateway-probe-period flag: must be at least 1s",
+		},
+		{
+			title:   "rejects selectors other than label equalities",
+			options: func(o *linkOptions) { o.selector = "env in (prod)" },
+			err:     "Invalid value 'env in (prod)' for --selector flag: invalid selector: [env in (prod)]",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			options := newLinkOptions()
+			options.clusterName = "east"
+			options.gatewayAddress = "203.0.113.10"
+			tc.options(options)
+
+			selector, err := options.validate()
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				expected := map[string]string{"mirror.linkerd.io/exported": "true"}
+				if !reflect.DeepEqual(selector, expected) {
+					t.Fatalf("Expected selector %v, got %v", expected, selector)
+				}
+			} else if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error %q, got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestRemoteKubeconfig(t *testing.T) {
+	serviceAccount := &v1.ServiceAccount{
+		ObjectMeta: metaV1.ObjectMeta{Name: defaultRemoteAccessServiceAccount, Namespace: "linkerd"},
+		Secrets: []v1.ObjectReference{
+			{Name: "linkerd-service-mirror-remote-access-dockercfg"},
+			{Name: "linkerd-service-mirror-remote-access-token-x7k2p"},
+		},
+	}
+	secrets := []*v1.Secret{
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "linkerd-service-mirror-remote-access-dockercfg", Namespace: "linkerd"},
+			Type:       v1.SecretTypeDockercfg,
+		},
+		{
+			ObjectMeta: metaV1.ObjectMeta{Name: "linkerd-service-mirror-remote-access-token-x7k2p", Namespace: "linkerd"},
+			Type:       v1.SecretTypeServiceAccountToken,
+			Data: map[string][]byte{
+				v1.ServiceAccountTokenKey:  []byte("token"),
+				v1.ServiceAccountRootCAKey: []byte("ca"),
+			},
+		},
+	}
+
+	t.Run("Builds a kubeconfig with the token of the service account", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(serviceAccount, secrets[0], secrets[1])
+
+		out, err := remoteKubeconfig(clientset, "linkerd", defaultRemoteAccessServiceAccount, "east", "https://east.example.com:6443")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		config, err := clientcmd.Load(out)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if config.CurrentContext != "east" {
+			t.Fatalf("Unexpected current context: %s", config.CurrentContext)
+		}
+		cluster := config.Clusters["east"]
+		if cluster == nil || cluster.Server != "https://east.example.com:6443" || string(cluster.CertificateAuthorityData) != "ca" {
+			t.Fatalf("Unexpected cluster: %+v", cluster)
+		}
+		authInfo := config.AuthInfos[defaultRemoteAccessServiceAccount]
+		if authInfo == nil || authInfo.Token != "token" {
+			t.Fatalf("Unexpected credentials: %+v", authInfo)
+		}
+	})
+
+	t.Run("Fails without the service account", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset()
+
+		_, err := remoteKubeconfig(clientset, "linkerd", defaultRemoteAccessServiceAccount, "east", "https://east.example.com:6443")
+		expected := "The linkerd-service-mirror-remote-access service account doesn't exist in the linkerd namespace of the linked cluster; create it with \"linkerd multicluster allow\""
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q, got: %v", expected, err)
+		}
+	})
+
+	t.Run("Fails without a token", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(serviceAccount, secrets[0])
+
+		_, err := remoteKubeconfig(clientset, "linkerd", defaultRemoteAccessServiceAccount, "east", "https://east.example.com:6443")
+		if err == nil {
+			t.Fatal("Expected an error without the token secret")
+		}
+	})
+}
+
+func TestRenderLink(t *testing.T) {
+	options := newLinkOptions()
+	config := linkConfig{
+		Namespace:                "linkerd",
+		ClusterName:              "east",
+		ClusterDomain:            "cluster.local",
+		CredentialsSecret:        clusterCredentialsSecret("east"),
+		CredentialsKey:           k8s.ClusterCredentialsKey,
+		Kubeconfig:               base64.StdEncoding.EncodeToString([]byte("kubeconfig")),
+		GatewayAddress:           "gateway.east.example.com",
+		GatewayPort:              4143,
+		GatewayIdentity:          "linkerd-gateway.linkerd-multicluster.serviceaccount.identity.linkerd.cluster.local",
+		ProbePath:                options.probePath,
+		ProbePort:                options.probePort,
+		ProbePeriod:              options.probePeriod.String(),
+		Selector:                 map[string]string{"mirror.linkerd.io/exported": "true", "team": "web"},
+		ServiceMirrorName:        serviceMirrorName("east"),
+		ControllerImage:          "gcr.io/linkerd-io/controller:dev",
+		ImagePullPolicy:          options.imagePullPolicy,
+		ControllerLogLevel:       options.controllerLogLevel,
+		ControllerUID:            2103,
+		ControllerComponentLabel: k8s.ControllerComponentLabel,
+		RemoteClusterNameLabel:   k8s.RemoteClusterNameLabel,
+		CreatedByAnnotation:      k8s.CreatedByAnnotation,
+		CliVersion:               "linkerd/cli dev",
+	}
+
+	buf := &bytes.Buffer{}
+	if err := renderLink(buf, config); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	kinds := []string{}
+	var link v1alpha1.Link
+	var secret v1.Secret
+	for _, doc := range splitYAMLDocuments(buf.String()) {
+		var meta metaV1.TypeMeta
+		if err := yaml.Unmarshal([]byte(doc), &meta); err != nil {
+			t.Fatalf("Invalid document %q: %s", doc, err)
+		}
+		if meta.Kind == "" {
+			continue
+		}
+		kinds = append(kinds, meta.Kind)
+
+		switch meta.Kind {
+		case "Link":
+			if err := yaml.Unmarshal([]byte(doc), &link); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		case "Secret":
+			if err := yaml.Unmarshal([]byte(doc), &secret); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		}
+	}
+
+	expectedKinds := []string{"Secret", "Link", "ServiceAccount", "ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding", "Deployment"}
+	if !reflect.DeepEqual(kinds, expectedKinds) {
+		t.Fatalf("Expected kinds %v, got %v", expectedKinds, kinds)
+	}
+
+	expectedSpec := v1alpha1.LinkSpec{
+		TargetClusterName:        "east",
+		TargetClusterDomain:      "cluster.local",
+		ClusterCredentialsSecret: "cluster-credentials-east",
+		GatewayAddress:           "gateway.east.example.com",
+		GatewayPort:              4143,
+		GatewayIdentity:          config.GatewayIdentity,
+		Selector: metaV1.LabelSelector{
+			MatchLabels: map[string]string{"mirror.linkerd.io/exported": "true", "team": "web"},
+		},
+		ProbeSpec: v1alpha1.ProbeSpec{
+			Path:   "/health",
+			Port:   4181,
+			Period: "3s",
+		},
+	}
+	if !reflect.DeepEqual(link.Spec, expectedSpec) {
+		t.Fatalf("Expected link spec %+v, got %+v", expectedSpec, link.Spec)
+	}
+	if string(secret.Data[k8s.ClusterCredentialsKey]) != "kubeconfig" {
+		t.Fatalf("Unexpected credentials: %q", secret.Data[k8s.ClusterCredentialsKey])
+	}
+}
+
+func TestUnlinkResources(t *testing.T) {
+	linked := map[string]bool{
+		"deployment/linkerd-service-mirror-east":                    true,
+		"link/east":                                                 true,
+		"secret/cluster-credentials-east":                           true,
+		"clusterrole/linkerd-linkerd-linkerd-service-mirror-east":   true,
+		"serviceaccount/linkerd-service-mirror-east":                true,
+		"clusterrole/linkerd-linkerd-linkerd-service-mirror-west":   true,
+		"deployment/linkerd-service-mirror-west":                    true,
+		"clusterrolebinding/linkerd-linkerd-linkerd-service-mirror": true,
+	}
+	exists := func(resource uninstallResource) (bool, error) {
+		return linked[resource.String()], nil
+	}
+	mirror := func(namespace, name, cluster string) *v1.Service {
+		return &v1.Service{ObjectMeta: metaV1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				k8s.MirroredServiceLabel:   "true",
+				k8s.RemoteClusterNameLabel: cluster,
+			},
+		}}
+	}
+
+	t.Run("Outputs the resources of the link in order", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(
+			mirror("emojivoto", "web-east", "east"),
+			mirror("books", "webapp-east", "east"),
+			mirror("emojivoto", "web-west", "west"),
+			&v1.Service{ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "emojivoto"}},
+		)
+
+		resources, err := unlinkResources(clientset, "east", exists)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		names := []string{}
+		for _, resource := range resources {
+			names = append(names, resource.Metadata.Namespace+"/"+resource.String())
+		}
+		expected := []string{
+			"linkerd/deployment/linkerd-service-mirror-east",
+			"books/service/webapp-east",
+			"emojivoto/service/web-east",
+			"linkerd/link/east",
+			"linkerd/secret/cluster-credentials-east",
+			"/clusterrole/linkerd-linkerd-linkerd-service-mirror-east",
+			"linkerd/serviceaccount/linkerd-service-mirror-east",
+		}
+		if !reflect.DeepEqual(names, expected) {
+			t.Fatalf("Expected resources %v, got %v", expected, names)
+		}
+	})
+
+	t.Run("Fails when the cluster isn't linked", func(t *testing.T) {
+		_, err := unlinkResources(fake.NewSimpleClientset(), "north", exists)
+		expected := "The north cluster isn't linked to this cluster"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q, got: %v", expected, err)
+		}
+	})
+}
+
+func TestRequestGatewaysFromAPI(t *testing.T) {
+	t.Run("Renders the returned gateways", func(t *testing.T) {
+		mockClient := &public.MockAPIClient{
+			GatewaysResponseToReturn: &pb.GatewaysResponse{
+				Gateways: []*pb.GatewaysResponse_Gateway{
+					{
+						ClusterName:    "east",
+						Address:        "203.0.113.10:4143",
+						Alive:          true,
+						PairedServices: 2,
+						LatencyMsP50:   2,
+						LatencyMsP95:   8,
+						LatencyMsP99:   12,
+					},
+					{
+						ClusterName:    "west",
+						Address:        "gateway.west.example.com:4143",
+						PairedServices: 1,
+						LatencyMsP50:   5,
+					},
+				},
+			},
+		}
+
+		output, err := requestGatewaysFromAPI(mockClient, &pb.GatewaysRequest{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := `CLUSTER   ADDRESS                         ALIVE   PAIRED_SERVICES   LATENCY_P50   LATENCY_P95   LATENCY_P99
+east      203.0.113.10:4143               yes     2                 2ms           8ms           12ms
+west      gateway.west.example.com:4143   no      1                 -             -             -
+`
+		if output != expected {
+			t.Fatalf("Expected:\n%s\nGot:\n%s", expected, output)
+		}
+	})
+
+	t.Run("Reports that no clusters are linked", func(t *testing.T) {
+		mockClient := &public.MockAPIClient{GatewaysResponseToReturn: &pb.GatewaysResponse{}}
+
+		output, err := requestGatewaysFromAPI(mockClient, &pb.GatewaysRequest{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if output != "No clusters are linked to this cluster.\n" {
+			t.Fatalf("Unexpected output: %s", output)
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// pluginPrefix is the prefix of the executables on the PATH that are run as
+// subcommands of the CLI, e.g. `linkerd foo` runs linkerd-foo.
+const pluginPrefix = "linkerd-"
+
+// plugin is an executable on the PATH that is a subcommand of the CLI.
+type plugin struct {
+	name string
+	path string
+
+	// warnings are the reasons why the plugin can't be run as `linkerd <name>`
+	warnings []string
+}
+
+func newCmdPlugin() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin [flags]",
+		Short: "Manage the plugins of the CLI",
+		Long: `Manage the plugins of the CLI.
+
+Any executable on the PATH whose name starts with "linkerd-" is a plugin, which
+"linkerd <name>" runs with the rest of the arguments, for example "linkerd foo
+bar" runs "linkerd-foo bar". The global flags given before the plugin name are
+passed to it in the environment: --kubeconfig as KUBECONFIG, --context as
+LINKERD_CONTEXT and --linkerd-namespace as LINKERD_NAMESPACE.`,
+	}
+
+	cmd.AddCommand(newCmdPluginList())
+
+	return cmd
+}
+
+func newCmdPluginList() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list [flags]",
+		Short: "List the plugins on the PATH",
+		Long: `List the plugins on the PATH.
+
+The plugins that can't be run, because a built-in command or a plugin earlier
+on the PATH has the same name, are listed with a warning.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plugins := findPlugins(filepath.SplitList(os.Getenv("PATH")), RootCmd)
+			return renderPlugins(os.Stdout, plugins)
+		},
+	}
+
+	return cmd
+}
+
+// findPlugins returns the plugins in the dirs, in the order in which they're
+// looked up, with warnings for those that can't be run because a built-in
+// command of root, or an earlier plugin, shadows them.
+func findPlugins(dirs []string, root *cobra.Command) []plugin {
+	plugins := []plugin{}
+	found := map[string]string{}
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			name, ok := pluginName(file)
+			if !ok {
+				continue
+			}
+
+			p := plugin{name: name, path: filepath.Join(dir, file.Name())}
+			if isBuiltinCommand(root, name) {
+				p.warnings = append(p.warnings, fmt.Sprintf("the built-in command \"linkerd %s\" shadows this plugin", name))
+			}
+			if shadowing, ok := found[name]; ok {
+				p.warnings = append(p.warnings, fmt.Sprintf("%s shadows this plugin, as it's earlier on the PATH", shadowing))
+			} else {
+				found[name] = p.path
+			}
+			plugins = append(plugins, p)
+		}
+	}
+	return plugins
+}
+
+// pluginName returns the name of the plugin that the file is, if it's one.
+func pluginName(file os.FileInfo) (string, bool) {
+	name := file.Name()
+	if file.IsDir() || !strings.HasPrefix(name, pluginPrefix) {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext != ".exe" && ext != ".bat" && ext != ".cmd" {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	} else if file.Mode()&0111 == 0 {
+		return "", false
+	}
+
+	name = strings.TrimPrefix(name, pluginPrefix)
+	return name, name != ""
+}
+
+func isBuiltinCommand(root *cobra.Command, name string) bool {
+	if name == "help" {
+		return true
+	}
+	for _, cmd := range root.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func renderPlugins(w io.Writer, plugins []plugin) error {
+	if len(plugins) == 0 {
+		return fmt.Errorf("No plugins found on the PATH: plugins are executables named %s<name>", pluginPrefix)
+	}
+
+	fmt.Fprintln(w, "The following plugins are available:")
+	fmt.Fprintln(w)
+	for _, p := range plugins {
+		fmt.Fprintf(w, "%s\n", p.path)
+		for _, warning := range p.warnings {
+			fmt.Fprintf(w, "  - warning: %s\n", warning)
+		}
+	}
+	return nil
+}
+
+// RunPlugin runs the plugin that the args of the CLI name, if any, with the
+// rest of the args, and returns true along with its exit code. It returns
+// false if the args name a built-in command, or no plugin.
+func RunPlugin(args []string) (bool, int) {
+	name, pluginArgs, env, ok := parsePluginArgs(args, RootCmd)
+	if !ok {
+		return false, 0
+	}
+	path, err := exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return false, 0
+	}
+
+	plugin := exec.Command(path, pluginArgs...)
+	plugin.Stdin = os.Stdin
+	plugin.Stdout = os.Stdout
+	plugin.Stderr = os.Stderr
+	plugin.Env = append(os.Environ(), env...)
+	if err := plugin.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+				return true, status.ExitStatus()
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Error running the %s plugin: %s\n", path, err)
+		return true, 1
+	}
+	return true, 0
+}
+
+// parsePluginArgs splits the args of the CLI into the name of the plugin that
+// they run, the args of the plugin, and the environment that passes the
+// global flags given before the name on to the plugin. It returns false if
+// the args don't run a plugin, which is left to cobra to report.
+func parsePluginArgs(args []string, root *cobra.Command) (string, []string, []string, bool) {
+	globalFlags := pflag.NewFlagSet(root.Name(), pflag.ContinueOnError)
+	globalFlags.SetInterspersed(false)
+	globalFlags.SetOutput(ioutil.Discard)
+	root.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		// the flags are copied, so that parsing them leaves the CLI's untouched
+		copied := *flag
+		copied.Value = newPluginFlagValue(flag)
+		globalFlags.AddFlag(&copied)
+	})
+	if err := globalFlags.Parse(args); err != nil {
+		return "", nil, nil, false
+	}
+
+	rest := globalFlags.Args()
+	if len(rest) == 0 || strings.HasPrefix(rest[0], "-") || isBuiltinCommand(root, rest[0]) {
+		return "", nil, nil, false
+	}
+
+	env := []string{}
+	for flag, variable := range map[string]string{
+		"kubeconfig":        "KUBECONFIG",
+		"context":           "LINKERD_CONTEXT",
+		"linkerd-namespace": "LINKERD_NAMESPACE",
+	} {
+		if globalFlags.Changed(flag) {
+			env = append(env, fmt.Sprintf("%s=%s", variable, globalFlags.Lookup(flag).Value.String()))
+		}
+	}
+	sort.Strings(env)
+	return rest[0], rest[1:], env, true
+}
+
+// pluginFlagValue holds the value of a global flag given to a plugin, without
+// setting the CLI's own.
+type pluginFlagValue struct {
+	value     string
+	valueType string
+}
+
+func newPluginFlagValue(flag *pflag.Flag) *pluginFlagValue {
+	return &pluginFlagValue{value: flag.DefValue, valueType: flag.Value.Type()}
+}
+
+func (v *pluginFlagValue) String() string { return v.value }
+
+func (v *pluginFlagValue) Set(value string) error {
+	v.value = value
+	return nil
+}
+
+func (v *pluginFlagValue) Type() string { return v.valueType }
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestFindPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are found by their file extension on Windows")
+	}
+
+	tmp, err := ioutil.TempDir("", "linkerd-plugins")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	first, second := filepath.Join(tmp, "first"), filepath.Join(tmp, "second")
+	for file, mode := range map[string]os.FileMode{
+		filepath.Join(first, "linkerd-foo"):     0755,
+		filepath.Join(first, "linkerd-version"): 0755,
+		filepath.Join(first, "linkerd-notes"):   0644,
+		filepath.Join(first, "kubectl-foo"):     0755,
+		filepath.Join(second, "linkerd-foo"):    0755,
+		filepath.Join(second, "linkerd-bar"):    0755,
+	} {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := ioutil.WriteFile(file, []byte("#!/bin/sh\n"), mode); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	plugins := findPlugins([]string{first, "", filepath.Join(tmp, "missing"), second}, RootCmd)
+	expected := []plugin{
+		{name: "foo", path: filepath.Join(first, "linkerd-foo")},
+		{name: "version", path: filepath.Join(first, "linkerd-version"), warnings: []string{"the built-in command \"linkerd version\" shadows this plugin"}},
+		{name: "bar", path: filepath.Join(second, "linkerd-bar")},
+		{name: "foo", path: filepath.Join(second, "linkerd-foo"), warnings: []string{filepath.Join(first, "linkerd-foo") + " shadows this plugin, as it's earlier on the PATH"}},
+	}
+	if !reflect.DeepEqual(plugins, expected) {
+		t.Fatalf("Expected plugins %+v, got %+v", expected, plugins)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := renderPlugins(buf, plugins[1:2]); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expectedOutput := "The following plugins are available:\n\n" + filepath.Join(first, "linkerd-version") + "\n  - warning: the built-in command \"linkerd version\" shadows this plugin\n"
+	if buf.String() != expectedOutput {
+		t.Fatalf("Expected output:\n%s\ngot:\n%s", expectedOutput, buf.String())
+	}
+
+	if err := renderPlugins(buf, []plugin{}); err == nil {
+		t.Fatal("Expected an error without plugins")
+	}
+}
+
+func TestParsePluginArgs(t *testing.T) {
+	testCases := []struct {
+		args       []string
+		ok         bool
+		name       string
+		pluginArgs []string
+		env        []string
+	}{
+		{
+			args:       []string{"foo", "bar", "--context=ignored"},
+			ok:         true,
+			name:       "foo",
+			pluginArgs: []string{"bar", "--context=ignored"},
+			env:        []string{},
+		},
+		{
+			args:       []string{"--kubeconfig", "/tmp/config", "-l", "linkerd-edge", "--context=prod", "--verbose", "foo", "-o", "json"},
+			ok:         true,
+			name:       "foo",
+			pluginArgs: []string{"-o", "json"},
+			env:        []string{"KUBECONFIG=/tmp/config", "LINKERD_CONTEXT=prod", "LINKERD_NAMESPACE=linkerd-edge"},
+		},
+		{args: []string{"version", "--client"}},
+		{args: []string{"help", "foo"}},
+		{args: []string{"--unknown-flag", "foo"}},
+		{args: []string{"--verbose"}},
+		{args: []string{}},
+	}
+
+	for _, tc := range testCases {
+		name, pluginArgs, env, ok := parsePluginArgs(tc.args, RootCmd)
+		if ok != tc.ok {
+			t.Fatalf("Expected %v to run a plugin: %v, got %v", tc.args, tc.ok, ok)
+		}
+		if !ok {
+			continue
+		}
+		if name != tc.name || !reflect.DeepEqual(pluginArgs, tc.pluginArgs) || !reflect.DeepEqual(env, tc.env) {
+			t.Fatalf("Unexpected plugin for %v: %s %v %v", tc.args, name, pluginArgs, env)
+		}
+	}
+
+	if controlPlaneNamespace != defaultNamespace || kubeconfigPath != "" {
+		t.Fatal("Expected the global flags of the CLI to be left untouched")
+	}
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linkerd/linkerd2/cli/install"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+type probeConfig struct {
+	Namespace           string
+	ProbeName           string
+	ProberName          string
+	URLs                string
+	Period              string
+	ControllerImage     string
+	ImagePullPolicy     string
+	ControllerLogLevel  string
+	ControllerUID       int64
+	ProbeNameLabel      string
+	CreatedByAnnotation string
+	CliVersion          string
+}
+
+type probeOptions struct {
+	namespace          string
+	service            string
+	port               uint
+	paths              []string
+	period             time.Duration
+	clusterDomain      string
+	controllerLogLevel string
+	*proxyConfigOptions
+}
+
+func newProbeOptions() *probeOptions {
+	return &probeOptions{
+		namespace:          "default",
+		service:            "",
+		port:               80,
+		paths:              []string{"/"},
+		period:             10 * time.Second,
+		clusterDomain:      "cluster.local",
+		controllerLogLevel: "info",
+		proxyConfigOptions: newProxyConfigOptions(),
+	}
+}
+
+func newCmdProbe() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "probe [flags]",
+		Short: "Send synthetic traffic to services",
+		Long: `Send synthetic traffic to services.
+
+A synthetic probe is a meshed prober deployment that sends low-rate requests to
+the routes of a service, so that its availability and latency are known even
+when it has little organic traffic. The golden metrics of the synthetic
+requests are recorded apart from the organic ones, as the outbound traffic of
+the prober deployment, named "linkerd-probe-" after the probe.`,
+	}
+
+	cmd.AddCommand(newCmdProbeCreate())
+
+	return cmd
+}
+
+func newCmdProbeCreate() *cobra.Command {
+	options := newProbeOptions()
+
+	cmd := &cobra.Command{
+		Use:   "create [flags] NAME",
+		Short: "Output the prober deployment of a synthetic probe",
+		Long: `Output the prober deployment of a synthetic probe.
+
+The prober sends a GET request to each of the paths of the service every
+period. Only server errors count as failures, as for the other traffic of the
+mesh. The deployment is injected with "linkerd inject" before it's applied, so
+that its proxy records the golden metrics of the synthetic requests, which are
+shown by e.g. "linkerd stat" with "--from deploy/linkerd-probe-NAME", or by
+"linkerd routes deploy/linkerd-probe-NAME --to svc/SERVICE" for each route.
+
+The probe is deleted by deleting its deployment, e.g. by piping the same output
+to "kubectl delete -f -".`,
+		Example: `  # Probe the /api/list route of the web service in the emojivoto namespace every 10 seconds.
+  linkerd probe create web -n emojivoto --service web-svc --port 80 --path /api/list | linkerd inject - | kubectl apply -f -
+
+  # Show the golden metrics of the synthetic requests.
+  linkerd routes deploy/linkerd-probe-web -n emojivoto --to svc/web-svc`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			urls, err := options.validate(args[0])
+			if err != nil {
+				return err
+			}
+
+			return renderProbe(os.Stdout, probeConfig{
+				Namespace:           options.namespace,
+				ProbeName:           args[0],
+				ProberName:          proberName(args[0]),
+				URLs:                strings.Join(urls, ","),
+				Period:              options.period.String(),
+				ControllerImage:     fmt.Sprintf("%s/controller:%s", options.dockerRegistry, options.linkerdVersion),
+				ImagePullPolicy:     options.imagePullPolicy,
+				ControllerLogLevel:  options.controllerLogLevel,
+				ControllerUID:       newInstallOptions().controllerUID,
+				ProbeNameLabel:      k8s.ProbeNameLabel,
+				CreatedByAnnotation: k8s.CreatedByAnnotation,
+				CliVersion:          k8s.CreatedByAnnotationValue(),
+			})
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the probed service, which the prober is deployed to")
+	cmd.PersistentFlags().StringVar(&options.service, "service", options.service, "Name of the probed service (required)")
+	cmd.PersistentFlags().UintVar(&options.port, "port", options.port, "Port of the probed service")
+	cmd.PersistentFlags().StringArrayVar(&options.paths, "path", options.paths, "Path of a probed route of the service; can be repeated")
+	cmd.PersistentFlags().DurationVar(&options.period, "period", options.period, "Interval between the synthetic requests to each route")
+	cmd.PersistentFlags().StringVar(&options.clusterDomain, "cluster-domain", options.clusterDomain, "DNS domain of the cluster")
+	cmd.PersistentFlags().StringVar(&options.controllerLogLevel, "controller-log-level", options.controllerLogLevel, "Log level for the prober")
+	cmd.PersistentFlags().StringVarP(&options.linkerdVersion, "linkerd-version", "v", options.linkerdVersion, "Tag to be used for the prober image")
+	cmd.PersistentFlags().StringVar(&options.dockerRegistry, "registry", options.dockerRegistry, "Docker registry to pull the prober image from")
+	cmd.PersistentFlags().StringVar(&options.imagePullPolicy, "image-pull-policy", options.imagePullPolicy, "Docker image pull policy")
+
+	return cmd
+}
+
+// validate checks the options and the name of the probe, and returns the
+// URLs of the probed routes.
+func (options *probeOptions) validate(name string) ([]string, error) {
+	// the name of the probe suffixes the name of the prober deployment
+	if errs := validation.IsDNS1123Label(proberName(name)); len(errs) > 0 {
+		return nil, fmt.Errorf("Invalid probe name '%s': %s", name, errs[0])
+	}
+	if errs := validation.IsDNS1123Label(options.namespace); len(errs) > 0 {
+		return nil, fmt.Errorf("Invalid value '%s' for --namespace flag: %s", options.namespace, errs[0])
+	}
+	if options.service == "" {
+		return nil, fmt.Errorf("The --service flag is required")
+	}
+	if errs := validation.IsDNS1035Label(options.service); len(errs) > 0 {
+		return nil, fmt.Errorf("Invalid value '%s' for --service flag: %s", options.service, errs[0])
+	}
+	if options.port == 0 || options.port > 65535 {
+		return nil, fmt.Errorf("Invalid value '%d' for --port flag: must be between 1 and 65535", options.port)
+	}
+	if len(options.paths) == 0 {
+		return nil, fmt.Errorf("The --path flag is required")
+	}
+	// the prober's flag separates the URLs with commas
+	for _, path := range options.paths {
+		if !strings.HasPrefix(path, "/") || strings.Contains(path, ",") {
+			return nil, fmt.Errorf("Invalid value '%s' for --path flag: must be an absolute path without commas", path)
+		}
+	}
+	if options.period < time.Second {
+		return nil, fmt.Errorf("Invalid value '%s' for --period flag: must be at least 1s", options.period)
+	}
+	if errs := validation.IsDNS1123Subdomain(options.clusterDomain); len(errs) > 0 {
+		return nil, fmt.Errorf("Invalid value '%s' for --cluster-domain flag: %s", options.clusterDomain, errs[0])
+	}
+	if _, err := log.ParseLevel(options.controllerLogLevel); err != nil {
+		return nil, fmt.Errorf("--controller-log-level must be one of: panic, fatal, error, warn, info, debug")
+	}
+	if err := options.proxyConfigOptions.validate(); err != nil {
+		return nil, err
+	}
+
+	host := net.JoinHostPort(
+		fmt.Sprintf("%s.%s.svc.%s", options.service, options.namespace, options.clusterDomain),
+		strconv.FormatUint(uint64(options.port), 10),
+	)
+	urls := []string{}
+	for _, path := range options.paths {
+		urls = append(urls, fmt.Sprintf("http://%s%s", host, path))
+	}
+	return urls, nil
+}
+
+func renderProbe(w io.Writer, config probeConfig) error {
+	return renderTemplate(w, "probe", install.ProbeTemplate, config)
+}
+
+func proberName(probeName string) string {
+	return "linkerd-probe-" + probeName
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	appsV1 "k8s.io/api/apps/v1"
+)
+
+func TestProbeOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		title   string
+		name    string
+		options func(*probeOptions)
+		urls    []string
+		err     string
+	}{
+		{
+			title:   "builds the URLs of the paths of the service",
+			name:    "web",
+			options: func(o *probeOptions) { o.paths = []string{"/api/list", "/api/vote"} },
+			urls: []string{
+				"http://web-svc.emojivoto.svc.cluster.local:80/api/list",
+				"http://web-svc.emojivoto.svc.cluster.local:80/api/vote",
+			},
+		},
+		{
+			title:   "rejects probe names that can't suffix deployment names",
+			name:    "Web",
+			options: func(*probeOptions) {},
+			err:     "Invalid probe name 'Web': a DNS-1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')",
+		},
+		{
+			title:   "requires a service",
+			name:    "web",
+			options: func(o *probeOptions) { o.service = "" },
+			err:     "The --service flag is required",
+		},
+		{
+			title:   "rejects invalid ports",
+			name:    "web",
+			options: func(o *probeOptions) { o.port = 0 },
+			err:     "Invalid value '0' for --port flag: must be between 1 and 65535",
+		},
+		{
+			title:   "rejects relative paths",
+			name:    "web",
+			options: func(o *probeOptions) { o.paths = []string{"api/list"} },
+			err:     "Invalid value 'api/list' for --path flag: must be an absolute path without commas",
+		},
+		{
+			title:   "rejects paths with commas",
+			name:    "web",
+			options: func(o *probeOptions) { o.paths = []string{"/api/list,vote"} },
+			err:     "Invalid value '/api/list,vote' for --path flag: must be an absolute path without commas",
+		},
+		{
+			title:   "rejects periods shorter than a second",
+			name:    "web",
+			options: func(o *probeOptions) { o.period = 500 * time.Millisecond },
+			err:     "Invalid value '500ms' for --period flag: must be at least 1s",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			options := newProbeOptions()
+			options.namespace = "emojivoto"
+			options.service = "web-svc"
+			tc.options(options)
+
+			urls, err := options.validate(tc.name)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				if !reflect.DeepEqual(urls, tc.urls) {
+					t.Fatalf("Expected URLs %v, got %v", tc.urls, urls)
+				}
+			} else if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error %q, got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestRenderProbe(t *testing.T) {
+	options := newProbeOptions()
+	config := probeConfig{
+		Namespace:           "emojivoto",
+		ProbeName:           "web",
+		ProberName:          proberName("web"),
+		URLs:                "http://web-svc.emojivoto.svc.cluster.local:80/api/list",
+		Period:              options.period.String(),
+		ControllerImage:     "gcr.io/linkerd-io/controller:dev",
+		ImagePullPolicy:     options.imagePullPolicy,
+		ControllerLogLevel:  options.controllerLogLevel,
+		ControllerUID:       2103,
+		ProbeNameLabel:      k8s.ProbeNameLabel,
+		CreatedByAnnotation: k8s.CreatedByAnnotation,
+		CliVersion:          "linkerd/cli dev",
+	}
+
+	buf := &bytes.Buffer{}
+	if err := renderProbe(buf, config); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	var deployment appsV1.Deployment
+	for _, doc := range splitYAMLDocuments(buf.String()) {
+		if err := yaml.Unmarshal([]byte(doc), &deployment); err != nil {
+			t.Fatalf("Invalid document %q: %s", doc, err)
+		}
+	}
+	if deployment.Name != "linkerd-probe-web" || deployment.Namespace != "emojivoto" {
+		t.Fatalf("Unexpected deployment %s/%s", deployment.Namespace, deployment.Name)
+	}
+	if deployment.Spec.Template.Labels[k8s.ProbeNameLabel] != "web" {
+		t.Fatalf("Expected the pods to be labeled with the probe name, got %v", deployment.Spec.Template.Labels)
+	}
+
+	expectedArgs := []string{
+		"prober",
+		"-probe-name=web",
+		"-urls=http://web-svc.emojivoto.svc.cluster.local:80/api/list",
+		"-period=10s",
+		"-log-level=info",
+	}
+	if args := deployment.Spec.Template.Spec.Containers[0].Args; !reflect.DeepEqual(args, expectedArgs) {
+		t.Fatalf("Expected args %v, got %v", expectedArgs, args)
+	}
+}
@@ -5,13 +5,10 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
-	"regexp"
-	"sort"
+	"time"
 
 	"github.com/ghodss/yaml"
-	"github.com/go-openapi/spec"
 	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
 	"github.com/linkerd/linkerd2/pkg/profiles"
 	"github.com/spf13/cobra"
@@ -26,21 +23,33 @@ type templateConfig struct {
 	ClusterZone           string
 }
 
-var pathParamRegex = regexp.MustCompile(`\\{[^\}]*\\}`)
-
 type profileOptions struct {
-	name      string
-	namespace string
-	template  bool
-	openAPI   string
+	name       string
+	namespace  string
+	template   bool
+	openAPI    string
+	proto      string
+	protoPaths []string
+
+	tap           string
+	tapDuration   time.Duration
+	tapRouteLimit uint
+	tapMaxRps     float32
 }
 
 func newProfileOptions() *profileOptions {
 	return &profileOptions{
-		name:      "",
-		namespace: "default",
-		template:  false,
-		openAPI:   "",
+		name:       "",
+		namespace:  "default",
+		template:   false,
+		openAPI:    "",
+		proto:      "",
+		protoPaths: []string{},
+
+		tap:           "",
+		tapDuration:   5 * time.Second,
+		tapRouteLimit: 20,
+		tapMaxRps:     100.0,
 	}
 }
 
@@ -52,8 +61,24 @@ func (options *profileOptions) validate() error {
 	if options.openAPI != "" {
 		outputs++
 	}
+	if options.proto != "" {
+		outputs++
+	}
+	if options.tap != "" {
+		outputs++
+	}
 	if outputs != 1 {
-		return errors.New("You must specify exactly one of --template or --open-api")
+		return errors.New("You must specify exactly one of --template, --open-api, --proto or --tap")
+	}
+
+	if options.tapDuration <= 0 {
+		return fmt.Errorf("invalid --tap-duration %s: must be positive", options.tapDuration)
+	}
+	if options.tapRouteLimit == 0 {
+		return errors.New("invalid --tap-route-limit 0: must be positive")
+	}
+	if options.tapMaxRps <= 0 {
+		return fmt.Errorf("invalid --tap-max-rps %v: must be positive", options.tapMaxRps)
 	}
 
 	// a DNS-1035 label must consist of lower case alphanumeric characters or '-',
@@ -76,7 +101,7 @@ func newCmdProfile() *cobra.Command {
 	options := newProfileOptions()
 
 	cmd := &cobra.Command{
-		Use:   "profile [flags] (--template | --open-api file) (SERVICE)",
+		Use:   "profile [flags] (--template | --open-api file | --proto file | --tap resource) (SERVICE)",
 		Short: "Output service profile config for Kubernetes",
 		Long: `Output service profile config for Kubernetes.
 
@@ -91,11 +116,37 @@ Example:
   # (edit web-svc-profile.yaml manually)
   kubectl apply -f web-svc-profile.yaml
 
-If the --open-api flag is specified, it reads the given OpenAPI
-specification file and outputs a corresponding service profile.
+If the --open-api flag is specified, it reads the given OpenAPI 3 or Swagger 2
+specification file and outputs a corresponding service profile, with a route
+for each operation of each path. The paths of the routes are prefixed with the
+base paths of the servers of the specification, and their parameters match a
+single path segment, or only digits for integer parameters. The responses of
+each operation are classified by their status codes or ranges of codes, e.g.
+"5XX", and the server errors are failures.
+
+Example:
+  linkerd profile -n emojivoto --open-api web-svc.swagger web-svc | kubectl apply -f -
+
+If the --proto flag is specified, it reads the given protobuf file and outputs a
+service profile with a route for each RPC of its services, and of the services
+of the files it imports that are found in the --proto-path directories (by
+default, the directory of the file). The unary RPCs whose idempotency_level
+option is NO_SIDE_EFFECTS or IDEMPOTENT are retryable; the streaming RPCs never
+are.
 
 Example:
-  linkerd profile -n emojivoto --open-api web-svc.swagger web-svc | kubectl apply -f -`,
+  linkerd profile -n emojivoto --proto Emoji.proto emoji-svc | kubectl apply -f -
+
+If the --tap flag is specified, it taps the requests received by the given
+resource for --tap-duration, sampling at most --tap-max-rps requests per
+second, and outputs a service profile with a route for each of the
+--tap-route-limit most requested paths and methods. The path segments that are
+numbers, UUIDs or long hexadecimal IDs are collapsed into parameters, so that
+e.g. the "/books/1" and "/books/2" requests make up a single "/books/{id}"
+route.
+
+Example:
+  linkerd profile -n emojivoto --tap deploy/web --tap-duration 10s web-svc | kubectl apply -f -`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			options.name = args[0]
@@ -109,6 +160,10 @@ Example:
 				return profiles.RenderProfileTemplate(options.namespace, options.name, controlPlaneNamespace, os.Stdout)
 			} else if options.openAPI != "" {
 				return renderOpenAPI(options, os.Stdout)
+			} else if options.proto != "" {
+				return renderProto(options, os.Stdout)
+			} else if options.tap != "" {
+				return renderTapProfile(options, os.Stdout)
 			}
 
 			// we should never get here
@@ -118,6 +173,12 @@ Example:
 
 	cmd.PersistentFlags().BoolVar(&options.template, "template", options.template, "Output a service profile template")
 	cmd.PersistentFlags().StringVar(&options.openAPI, "open-api", options.openAPI, "Output a service profile based on the given OpenAPI spec file")
+	cmd.PersistentFlags().StringVar(&options.proto, "proto", options.proto, "Output a service profile based on the given protobuf spec file")
+	cmd.PersistentFlags().StringArrayVar(&options.protoPaths, "proto-path", options.protoPaths, "Directory in which to search for the imports of the protobuf spec file; can be repeated")
+	cmd.PersistentFlags().StringVar(&options.tap, "tap", options.tap, "Output a service profile based on the requests received by the given resource, e.g. deploy/web")
+	cmd.PersistentFlags().DurationVar(&options.tapDuration, "tap-duration", options.tapDuration, "Duration of the tap of the resource given with --tap")
+	cmd.PersistentFlags().UintVar(&options.tapRouteLimit, "tap-route-limit", options.tapRouteLimit, "Maximum number of routes of the profile generated with --tap; the most requested routes are kept")
+	cmd.PersistentFlags().Float32Var(&options.tapMaxRps, "tap-max-rps", options.tapMaxRps, "Maximum requests per second to sample when tapping the resource given with --tap")
 	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the service")
 
 	return cmd
@@ -139,17 +200,20 @@ func renderOpenAPI(options *profileOptions, w io.Writer) error {
 	if err != nil {
 		return fmt.Errorf("Error reading file: %s", err)
 	}
-	json, err := yaml.YAMLToJSON(bytes)
+	doc, err := yaml.YAMLToJSON(bytes)
 	if err != nil {
 		return fmt.Errorf("Error parsing yaml: %s", err)
 	}
 
-	swagger := spec.Swagger{}
-	err = swagger.UnmarshalJSON(json)
+	routes, err := openAPIRoutes(doc)
 	if err != nil {
-		return fmt.Errorf("Error parsing OpenAPI spec: %s", err)
+		return err
 	}
 
+	return renderProfile(options, routes, w)
+}
+
+func renderProfile(options *profileOptions, routes []*sp.RouteSpec, w io.Writer) error {
 	profile := sp.ServiceProfile{
 		ObjectMeta: meta_v1.ObjectMeta{
 			Name:      fmt.Sprintf("%s.%s.svc.cluster.local", options.name, options.namespace),
@@ -161,49 +225,6 @@ func renderOpenAPI(options *profileOptions, w io.Writer) error {
 		},
 	}
 
-	routes := make([]*sp.RouteSpec, 0)
-
-	paths := make([]string, 0)
-	if swagger.Paths != nil {
-		for path := range swagger.Paths.Paths {
-			paths = append(paths, path)
-		}
-		sort.Strings(paths)
-	}
-
-	for _, path := range paths {
-		item := swagger.Paths.Paths[path]
-		pathRegex := pathToRegex(path)
-		if item.Delete != nil {
-			spec := mkRouteSpec(path, pathRegex, http.MethodDelete, item.Delete.Responses)
-			routes = append(routes, spec)
-		}
-		if item.Get != nil {
-			spec := mkRouteSpec(path, pathRegex, http.MethodGet, item.Get.Responses)
-			routes = append(routes, spec)
-		}
-		if item.Head != nil {
-			spec := mkRouteSpec(path, pathRegex, http.MethodHead, item.Head.Responses)
-			routes = append(routes, spec)
-		}
-		if item.Options != nil {
-			spec := mkRouteSpec(path, pathRegex, http.MethodOptions, item.Options.Responses)
-			routes = append(routes, spec)
-		}
-		if item.Patch != nil {
-			spec := mkRouteSpec(path, pathRegex, http.MethodPatch, item.Patch.Responses)
-			routes = append(routes, spec)
-		}
-		if item.Post != nil {
-			spec := mkRouteSpec(path, pathRegex, http.MethodPost, item.Post.Responses)
-			routes = append(routes, spec)
-		}
-		if item.Put != nil {
-			spec := mkRouteSpec(path, pathRegex, http.MethodPut, item.Put.Responses)
-			routes = append(routes, spec)
-		}
-	}
-
 	profile.Spec.Routes = routes
 	output, err := yaml.Marshal(profile)
 	if err != nil {
@@ -213,50 +234,3 @@ func renderOpenAPI(options *profileOptions, w io.Writer) error {
 
 	return nil
 }
-
-func mkRouteSpec(path, pathRegex string, method string, responses *spec.Responses) *sp.RouteSpec {
-	return &sp.RouteSpec{
-		Name:            fmt.Sprintf("%s %s", method, path),
-		Condition:       toReqMatch(pathRegex, method),
-		ResponseClasses: toRspClasses(responses),
-	}
-}
-
-func pathToRegex(path string) string {
-	escaped := regexp.QuoteMeta(path)
-	return pathParamRegex.ReplaceAllLiteralString(escaped, "[^/]*")
-}
-
-func toReqMatch(path string, method string) *sp.RequestMatch {
-	return &sp.RequestMatch{
-		PathRegex: path,
-		Method:    method,
-	}
-}
-
-func toRspClasses(responses *spec.Responses) []*sp.ResponseClass {
-	if responses == nil {
-		return nil
-	}
-	classes := make([]*sp.ResponseClass, 0)
-
-	statuses := make([]int, 0)
-	for status := range responses.StatusCodeResponses {
-		statuses = append(statuses, status)
-	}
-	sort.Ints(statuses)
-
-	for _, status := range statuses {
-		cond := &sp.ResponseMatch{
-			Status: &sp.Range{
-				Min: uint32(status),
-				Max: uint32(status),
-			},
-		}
-		classes = append(classes, &sp.ResponseClass{
-			Condition: cond,
-			IsFailure: status >= 500,
-		})
-	}
-	return classes
-}
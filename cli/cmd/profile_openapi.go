@@ -0,0 +1,379 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+)
+
+// pathParamRegex matches the parameters of the path templates and server URL
+// templates of OpenAPI specifications, e.g. "{id}" in "/books/{id}", and
+// captures their name.
+var pathParamRegex = regexp.MustCompile(`\{([^{}/]*)\}`)
+
+// openAPIOperation is an operation of a path of an OpenAPI specification,
+// whichever its version.
+type openAPIOperation struct {
+	method string
+
+	// paramTypes are the types of the path parameters of the operation, by
+	// name
+	paramTypes map[string]string
+
+	// statuses are the status codes of the responses of the operation, as
+	// ranges that only have one code unless they were given as e.g. "5XX"
+	statuses []*sp.Range
+}
+
+// openAPI3Spec is the part of an OpenAPI 3 specification that the routes of
+// service profiles are generated from.
+type openAPI3Spec struct {
+	OpenAPI    string                      `json:"openapi"`
+	Servers    []openAPI3Server            `json:"servers"`
+	Paths      map[string]openAPI3PathItem `json:"paths"`
+	Components struct {
+		Parameters map[string]openAPI3Parameter `json:"parameters"`
+	} `json:"components"`
+}
+
+type openAPI3Server struct {
+	URL       string `json:"url"`
+	Variables map[string]struct {
+		Default string `json:"default"`
+	} `json:"variables"`
+}
+
+type openAPI3PathItem struct {
+	Parameters []openAPI3Parameter `json:"parameters"`
+	Delete     *openAPI3Operation  `json:"delete"`
+	Get        *openAPI3Operation  `json:"get"`
+	Head       *openAPI3Operation  `json:"head"`
+	Options    *openAPI3Operation  `json:"options"`
+	Patch      *openAPI3Operation  `json:"patch"`
+	Post       *openAPI3Operation  `json:"post"`
+	Put        *openAPI3Operation  `json:"put"`
+	Trace      *openAPI3Operation  `json:"trace"`
+}
+
+type openAPI3Operation struct {
+	Parameters []openAPI3Parameter `json:"parameters"`
+	// the responses are only keyed by their status codes here
+	Responses map[string]json.RawMessage `json:"responses"`
+}
+
+type openAPI3Parameter struct {
+	Ref    string `json:"$ref"`
+	Name   string `json:"name"`
+	In     string `json:"in"`
+	Schema *struct {
+		Type string `json:"type"`
+	} `json:"schema"`
+}
+
+// openAPIRoutes returns the routes of the operations of the given OpenAPI 3
+// or Swagger 2 specification, in JSON.
+func openAPIRoutes(doc []byte) ([]*sp.RouteSpec, error) {
+	var version struct {
+		OpenAPI string `json:"openapi"`
+	}
+	if err := json.Unmarshal(doc, &version); err != nil {
+		return nil, fmt.Errorf("Error parsing OpenAPI spec: %s", err)
+	}
+
+	if strings.HasPrefix(version.OpenAPI, "3.") {
+		return openAPI3Routes(doc)
+	}
+	return swaggerRoutes(doc)
+}
+
+func openAPI3Routes(doc []byte) ([]*sp.RouteSpec, error) {
+	openAPI := openAPI3Spec{}
+	if err := json.Unmarshal(doc, &openAPI); err != nil {
+		return nil, fmt.Errorf("Error parsing OpenAPI spec: %s", err)
+	}
+
+	basePaths, err := openAPI3BasePaths(openAPI.Servers)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0)
+	for path := range openAPI.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	routes := make([]*sp.RouteSpec, 0)
+	for _, path := range paths {
+		item := openAPI.Paths[path]
+		operations := []struct {
+			method string
+			op     *openAPI3Operation
+		}{
+			{http.MethodDelete, item.Delete},
+			{http.MethodGet, item.Get},
+			{http.MethodHead, item.Head},
+			{http.MethodOptions, item.Options},
+			{http.MethodPatch, item.Patch},
+			{http.MethodPost, item.Post},
+			{http.MethodPut, item.Put},
+			{http.MethodTrace, item.Trace},
+		}
+
+		for _, operation := range operations {
+			if operation.op == nil {
+				continue
+			}
+
+			// the parameters of an operation override those of its path
+			paramTypes := openAPI3ParamTypes(&openAPI, item.Parameters)
+			for name, paramType := range openAPI3ParamTypes(&openAPI, operation.op.Parameters) {
+				paramTypes[name] = paramType
+			}
+
+			statuses, err := openAPI3Statuses(operation.op.Responses)
+			if err != nil {
+				return nil, fmt.Errorf("Error parsing the responses of %s %s: %s", operation.method, path, err)
+			}
+
+			routes = append(routes, mkRouteSpec(path, basePaths, openAPIOperation{
+				method:     operation.method,
+				paramTypes: paramTypes,
+				statuses:   statuses,
+			}))
+		}
+	}
+	return routes, nil
+}
+
+// openAPI3BasePaths returns the paths of the URLs of the servers, which
+// prefix the paths of the operations. The variables of the URLs are
+// substituted with their default value.
+func openAPI3BasePaths(servers []openAPI3Server) ([]string, error) {
+	// without servers, the operations are served from the root
+	if len(servers) == 0 {
+		return []string{""}, nil
+	}
+
+	paths := map[string]struct{}{}
+	for _, server := range servers {
+		rawURL := pathParamRegex.ReplaceAllStringFunc(server.URL, func(variable string) string {
+			return server.Variables[variable[1:len(variable)-1]].Default
+		})
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid server URL '%s': %s", server.URL, err)
+		}
+		paths[strings.TrimSuffix(u.Path, "/")] = struct{}{}
+	}
+
+	basePaths := make([]string, 0)
+	for path := range paths {
+		basePaths = append(basePaths, path)
+	}
+	sort.Strings(basePaths)
+	return basePaths, nil
+}
+
+func openAPI3ParamTypes(openAPI *openAPI3Spec, params []openAPI3Parameter) map[string]string {
+	paramTypes := map[string]string{}
+	for _, param := range params {
+		if param.Ref != "" {
+			param = openAPI.Components.Parameters[strings.TrimPrefix(param.Ref, "#/components/parameters/")]
+		}
+		if param.In != "path" {
+			continue
+		}
+		paramTypes[param.Name] = ""
+		if param.Schema != nil {
+			paramTypes[param.Name] = param.Schema.Type
+		}
+	}
+	return paramTypes
+}
+
+// openAPI3Statuses returns the status codes of the responses, which are
+// either codes or ranges of codes such as "5XX". The default response doesn't
+// have a status code.
+func openAPI3Statuses(responses map[string]json.RawMessage) ([]*sp.Range, error) {
+	if responses == nil {
+		return nil, nil
+	}
+
+	statuses := make([]*sp.Range, 0)
+	for code := range responses {
+		if code == "default" {
+			continue
+		}
+
+		if status, err := strconv.ParseUint(code, 10, 32); err == nil && status >= 100 && status <= 599 {
+			statuses = append(statuses, &sp.Range{Min: uint32(status), Max: uint32(status)})
+			continue
+		}
+		if len(code) == 3 && code[0] >= '1' && code[0] <= '5' && strings.ToUpper(code[1:]) == "XX" {
+			min := uint32(code[0]-'0') * 100
+			statuses = append(statuses, &sp.Range{Min: min, Max: min + 99})
+			continue
+		}
+		return nil, fmt.Errorf("invalid response code '%s'", code)
+	}
+	return statuses, nil
+}
+
+func swaggerRoutes(doc []byte) ([]*sp.RouteSpec, error) {
+	swagger := spec.Swagger{}
+	err := swagger.UnmarshalJSON(doc)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing OpenAPI spec: %s", err)
+	}
+
+	routes := make([]*sp.RouteSpec, 0)
+
+	paths := make([]string, 0)
+	if swagger.Paths != nil {
+		for path := range swagger.Paths.Paths {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+	}
+
+	basePaths := []string{strings.TrimSuffix(swagger.BasePath, "/")}
+	for _, path := range paths {
+		item := swagger.Paths.Paths[path]
+		operations := []struct {
+			method string
+			op     *spec.Operation
+		}{
+			{http.MethodDelete, item.Delete},
+			{http.MethodGet, item.Get},
+			{http.MethodHead, item.Head},
+			{http.MethodOptions, item.Options},
+			{http.MethodPatch, item.Patch},
+			{http.MethodPost, item.Post},
+			{http.MethodPut, item.Put},
+		}
+
+		for _, operation := range operations {
+			if operation.op == nil {
+				continue
+			}
+
+			// the parameters of an operation override those of its path
+			paramTypes := swaggerParamTypes(&swagger, item.Parameters)
+			for name, paramType := range swaggerParamTypes(&swagger, operation.op.Parameters) {
+				paramTypes[name] = paramType
+			}
+
+			routes = append(routes, mkRouteSpec(path, basePaths, openAPIOperation{
+				method:     operation.method,
+				paramTypes: paramTypes,
+				statuses:   swaggerStatuses(operation.op.Responses),
+			}))
+		}
+	}
+	return routes, nil
+}
+
+func swaggerParamTypes(swagger *spec.Swagger, params []spec.Parameter) map[string]string {
+	paramTypes := map[string]string{}
+	for _, param := range params {
+		if ref := param.Ref.String(); ref != "" {
+			param = swagger.Parameters[strings.TrimPrefix(ref, "#/parameters/")]
+		}
+		if param.In == "path" {
+			paramTypes[param.Name] = param.Type
+		}
+	}
+	return paramTypes
+}
+
+func swaggerStatuses(responses *spec.Responses) []*sp.Range {
+	if responses == nil {
+		return nil
+	}
+
+	statuses := make([]*sp.Range, 0)
+	for status := range responses.StatusCodeResponses {
+		statuses = append(statuses, &sp.Range{Min: uint32(status), Max: uint32(status)})
+	}
+	return statuses
+}
+
+// mkRouteSpec returns the route of an operation of the path. The path is
+// prefixed with the base path of the servers if they share one, and with an
+// alternation of their base paths otherwise.
+func mkRouteSpec(path string, basePaths []string, operation openAPIOperation) *sp.RouteSpec {
+	pathRegex := pathToRegex(path, operation.paramTypes)
+	name := path
+	if len(basePaths) == 1 {
+		pathRegex = regexp.QuoteMeta(basePaths[0]) + pathRegex
+		name = basePaths[0] + path
+	} else {
+		quoted := make([]string, len(basePaths))
+		for i, basePath := range basePaths {
+			quoted[i] = regexp.QuoteMeta(basePath)
+		}
+		pathRegex = fmt.Sprintf("(?:%s)%s", strings.Join(quoted, "|"), pathRegex)
+	}
+
+	return &sp.RouteSpec{
+		Name:            fmt.Sprintf("%s %s", operation.method, name),
+		Condition:       toReqMatch(pathRegex, operation.method),
+		ResponseClasses: toRspClasses(operation.statuses),
+	}
+}
+
+// pathToRegex returns the regex of the paths matching the path template. The
+// parameters match a single non-empty path segment, or only digits if they're
+// integers.
+func pathToRegex(path string, paramTypes map[string]string) string {
+	pathRegex := ""
+	last := 0
+	for _, match := range pathParamRegex.FindAllStringSubmatchIndex(path, -1) {
+		pathRegex += regexp.QuoteMeta(path[last:match[0]])
+		if paramTypes[path[match[2]:match[3]]] == "integer" {
+			pathRegex += `\d+`
+		} else {
+			pathRegex += "[^/]+"
+		}
+		last = match[1]
+	}
+	return pathRegex + regexp.QuoteMeta(path[last:])
+}
+
+func toReqMatch(path string, method string) *sp.RequestMatch {
+	return &sp.RequestMatch{
+		PathRegex: path,
+		Method:    method,
+	}
+}
+
+func toRspClasses(statuses []*sp.Range) []*sp.ResponseClass {
+	if statuses == nil {
+		return nil
+	}
+	classes := make([]*sp.ResponseClass, 0)
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Min != statuses[j].Min {
+			return statuses[i].Min < statuses[j].Min
+		}
+		return statuses[i].Max < statuses[j].Max
+	})
+
+	for _, status := range statuses {
+		classes = append(classes, &sp.ResponseClass{
+			Condition: &sp.ResponseMatch{Status: status},
+			IsFailure: status.Min >= 500,
+		})
+	}
+	return classes
+}
@@ -0,0 +1,371 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+)
+
+// protoFile is the part of a protobuf file that the routes of service
+// profiles are generated from.
+type protoFile struct {
+	pkg      string
+	imports  []string
+	services []protoService
+}
+
+type protoService struct {
+	name    string
+	methods []protoMethod
+}
+
+type protoMethod struct {
+	name            string
+	clientStreaming bool
+	serverStreaming bool
+
+	// idempotent is true if the idempotency_level option of the method is
+	// NO_SIDE_EFFECTS or IDEMPOTENT
+	idempotent bool
+}
+
+func renderProto(options *profileOptions, w io.Writer) error {
+	files, err := loadProtoFiles(options.proto, options.protoPaths)
+	if err != nil {
+		return err
+	}
+
+	return renderProfile(options, protoRoutes(files), w)
+}
+
+// loadProtoFiles parses the protobuf file and the files it imports,
+// transitively, that are found in the import paths. The imports that aren't
+// found, such as the well-known types that come with protoc, are skipped.
+func loadProtoFiles(path string, importPaths []string) ([]*protoFile, error) {
+	var src []byte
+	var err error
+	if path == "-" {
+		src, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		src, err = ioutil.ReadFile(path)
+		if len(importPaths) == 0 {
+			importPaths = []string{filepath.Dir(path)}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading file: %s", err)
+	}
+
+	files := []*protoFile{}
+	loaded := map[string]bool{filepath.Clean(path): true}
+
+	var load func(name string, src []byte) error
+	load = func(name string, src []byte) error {
+		file, err := parseProto(string(src))
+		if err != nil {
+			return fmt.Errorf("Error parsing %s: %s", name, err)
+		}
+		files = append(files, file)
+
+		for _, imp := range file.imports {
+			found := ""
+			for _, dir := range importPaths {
+				candidate := filepath.Clean(filepath.Join(dir, imp))
+				if _, err := os.Stat(candidate); err == nil {
+					found = candidate
+					break
+				}
+			}
+			if found == "" || loaded[found] {
+				continue
+			}
+			loaded[found] = true
+
+			src, err := ioutil.ReadFile(found)
+			if err != nil {
+				return fmt.Errorf("Error reading file: %s", err)
+			}
+			if err := load(imp, src); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := load(path, src); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// protoRoutes returns a route for each method of the services of the files.
+// Only the unary methods can be retried, if they're idempotent.
+func protoRoutes(files []*protoFile) []*sp.RouteSpec {
+	routes := make([]*sp.RouteSpec, 0)
+	for _, file := range files {
+		for _, service := range file.services {
+			name := service.name
+			if file.pkg != "" {
+				name = file.pkg + "." + service.name
+			}
+
+			for _, method := range service.methods {
+				path := fmt.Sprintf("/%s/%s", name, method.name)
+				routes = append(routes, &sp.RouteSpec{
+					Name:        path,
+					Condition:   toReqMatch(regexp.QuoteMeta(path), http.MethodPost),
+					IsRetryable: method.idempotent && !method.clientStreaming && !method.serverStreaming,
+				})
+			}
+		}
+	}
+	return routes
+}
+
+// protoParser parses the package, the imports and the services of protobuf
+// files. The other definitions are skipped.
+type protoParser struct {
+	tokens []string
+	pos    int
+}
+
+func parseProto(src string) (*protoFile, error) {
+	tokens, err := tokenizeProto(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &protoParser{tokens: tokens}
+
+	file := &protoFile{}
+	for p.pos < len(p.tokens) {
+		switch p.next() {
+		case ";":
+		case "package":
+			file.pkg = p.next()
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		case "import":
+			imp := p.next()
+			if imp == "public" || imp == "weak" {
+				imp = p.next()
+			}
+			path, err := strconv.Unquote(imp)
+			if err != nil {
+				return nil, fmt.Errorf("invalid import %s", imp)
+			}
+			file.imports = append(file.imports, path)
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		case "service":
+			service, err := p.parseService()
+			if err != nil {
+				return nil, err
+			}
+			file.services = append(file.services, service)
+		default:
+			p.skipStatement()
+		}
+	}
+	return file, nil
+}
+
+func (p *protoParser) parseService() (protoService, error) {
+	service := protoService{name: p.next()}
+	if err := p.expect("{"); err != nil {
+		return service, err
+	}
+
+	for {
+		switch p.next() {
+		case "}":
+			return service, nil
+		case "":
+			return service, fmt.Errorf("unexpected end of file in service %s", service.name)
+		case ";":
+		case "rpc":
+			method, err := p.parseMethod()
+			if err != nil {
+				return service, err
+			}
+			service.methods = append(service.methods, method)
+		default:
+			p.skipStatement()
+		}
+	}
+}
+
+func (p *protoParser) parseMethod() (protoMethod, error) {
+	method := protoMethod{name: p.next()}
+
+	var err error
+	if method.clientStreaming, err = p.parseMethodType(); err != nil {
+		return method, err
+	}
+	if err := p.expect("returns"); err != nil {
+		return method, err
+	}
+	if method.serverStreaming, err = p.parseMethodType(); err != nil {
+		return method, err
+	}
+
+	switch tok := p.next(); tok {
+	case ";":
+		return method, nil
+	case "{":
+	default:
+		return method, fmt.Errorf("unexpected %q after rpc %s", tok, method.name)
+	}
+
+	for {
+		switch p.next() {
+		case "}":
+			return method, nil
+		case "":
+			return method, fmt.Errorf("unexpected end of file in rpc %s", method.name)
+		case "option":
+			if p.peek() == "idempotency_level" {
+				p.next()
+				if err := p.expect("="); err != nil {
+					return method, err
+				}
+				level := p.next()
+				method.idempotent = level == "NO_SIDE_EFFECTS" || level == "IDEMPOTENT"
+			}
+			p.skipStatement()
+		case ";":
+		default:
+			p.skipStatement()
+		}
+	}
+}
+
+// parseMethodType parses the request or response type of a method, and
+// returns true if it's a stream.
+func (p *protoParser) parseMethodType() (bool, error) {
+	if err := p.expect("("); err != nil {
+		return false, err
+	}
+	stream := false
+	if p.peek() == "stream" {
+		p.next()
+		stream = true
+	}
+	p.next()
+	return stream, p.expect(")")
+}
+
+func (p *protoParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// next returns the next token, or an empty string at the end of the file.
+func (p *protoParser) next() string {
+	tok := p.peek()
+	if tok != "" {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *protoParser) expect(expected string) error {
+	if tok := p.next(); tok != expected {
+		if tok == "" {
+			return fmt.Errorf("expected %q, got the end of the file", expected)
+		}
+		return fmt.Errorf("expected %q, got %q", expected, tok)
+	}
+	return nil
+}
+
+// skipStatement skips the rest of the current statement, which ends with a
+// semicolon or a block.
+func (p *protoParser) skipStatement() {
+	depth := 0
+	if p.pos > 0 && p.tokens[p.pos-1] == "{" {
+		depth = 1
+	}
+	for {
+		switch p.next() {
+		case "":
+			return
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth <= 0 {
+				return
+			}
+		case ";":
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+// tokenizeProto splits protobuf source into identifiers (including their
+// dots), numbers, quoted strings and symbols, without the comments.
+func tokenizeProto(src string) ([]string, error) {
+	tokens := []string{}
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end + 1
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for ; j < len(src) && rune(src[j]) != c; j++ {
+				if src[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			// single-quoted strings are unquoted as double-quoted ones
+			tokens = append(tokens, `"`+src[i+1:j]+`"`)
+			i = j + 1
+		case isProtoIdentChar(c):
+			j := i
+			for j < len(src) && isProtoIdentChar(rune(src[j])) {
+				j++
+			}
+			tokens = append(tokens, src[i:j])
+			i = j
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isProtoIdentChar(c rune) bool {
+	return c == '_' || c == '.' || c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c))
+}
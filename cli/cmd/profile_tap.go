@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/linkerd/linkerd2/controller/api/util"
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// tapPathParams are the path segments that are collapsed into parameters,
+// as they're likely IDs, with the regex of the parameter.
+var tapPathParams = []struct {
+	segment *regexp.Regexp
+	regex   string
+}{
+	{regexp.MustCompile(`^\d+$`), `\d+`},
+	// UUIDs
+	{regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`), "[^/]+"},
+	// hashes and other hexadecimal IDs
+	{regexp.MustCompile(`^[0-9a-fA-F]{16,}$`), "[^/]+"},
+}
+
+// tapRoute is a route of the requests observed by `linkerd profile --tap`.
+type tapRoute struct {
+	method    string
+	name      string
+	pathRegex string
+}
+
+func renderTapProfile(options *profileOptions, w io.Writer) error {
+	req, err := util.BuildTapByResourceRequest(util.TapRequestParams{
+		Resource:  options.tap,
+		Namespace: options.namespace,
+		MaxRps:    options.tapMaxRps,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), options.tapDuration)
+	defer cancel()
+	tapClient, err := cliPublicAPIClient().TapByResource(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	counts, err := collectTapRoutes(tapClient)
+	if err != nil {
+		return err
+	}
+
+	routes, dropped := tapRoutes(counts, options.tapRouteLimit)
+	if dropped > 0 {
+		fmt.Fprintf(os.Stderr, "The %d least requested routes are left out of the profile, as it's limited to %d routes by --tap-route-limit\n", dropped, options.tapRouteLimit)
+	}
+	return renderProfile(options, routes, w)
+}
+
+// collectTapRoutes counts the requests of each route received by the tapped
+// resource, until the tap ends or times out.
+func collectTapRoutes(tapClient pb.Api_TapByResourceClient) (map[tapRoute]uint, error) {
+	counts := map[tapRoute]uint{}
+	for {
+		event, err := tapClient.Recv()
+		if err == io.EOF || status.Code(err) == codes.DeadlineExceeded {
+			return counts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// the outbound requests of the tapped resource are sent to other
+		// services
+		if event.GetProxyDirection() != pb.TapEvent_INBOUND {
+			continue
+		}
+		init := event.GetHttp().GetRequestInit()
+		if init == nil {
+			continue
+		}
+
+		method := init.GetMethod().GetUnregistered()
+		if method == "" {
+			method = init.GetMethod().GetRegistered().String()
+		}
+		counts[newTapRoute(method, init.GetPath())]++
+	}
+}
+
+// newTapRoute returns the route of the requests with the method and path, in
+// which the segments that are likely IDs are collapsed into parameters.
+func newTapRoute(method, path string) tapRoute {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+
+	segments := strings.Split(path, "/")
+	names := make([]string, len(segments))
+	regexes := make([]string, len(segments))
+	for i, segment := range segments {
+		names[i] = segment
+		regexes[i] = regexp.QuoteMeta(segment)
+		for _, param := range tapPathParams {
+			if param.segment.MatchString(segment) {
+				names[i] = "{id}"
+				regexes[i] = param.regex
+				break
+			}
+		}
+	}
+
+	return tapRoute{
+		method:    method,
+		name:      fmt.Sprintf("%s %s", method, strings.Join(names, "/")),
+		pathRegex: strings.Join(regexes, "/"),
+	}
+}
+
+// tapRoutes returns the limit most requested routes, sorted by name, and the
+// number of routes left out.
+func tapRoutes(counts map[tapRoute]uint, limit uint) ([]*sp.RouteSpec, int) {
+	routes := make([]tapRoute, 0)
+	for route := range counts {
+		routes = append(routes, route)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if counts[routes[i]] != counts[routes[j]] {
+			return counts[routes[i]] > counts[routes[j]]
+		}
+		return routes[i].name < routes[j].name
+	})
+
+	dropped := 0
+	if uint(len(routes)) > limit {
+		dropped = len(routes) - int(limit)
+		routes = routes[:limit]
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].name < routes[j].name
+	})
+
+	specs := make([]*sp.RouteSpec, 0)
+	for _, route := range routes {
+		specs = append(specs, &sp.RouteSpec{
+			Name:      route.name,
+			Condition: toReqMatch(route.pathRegex, route.method),
+		})
+	}
+	return specs, dropped
+}
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/ghodss/yaml"
+	"github.com/linkerd/linkerd2/controller/api/public"
 	"github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/profiles"
 )
 
@@ -35,7 +38,7 @@ func TestParseProfile(t *testing.T) {
 
 func TestValidateOptions(t *testing.T) {
 	options := newProfileOptions()
-	exp := errors.New("You must specify exactly one of --template or --open-api")
+	exp := errors.New("You must specify exactly one of --template, --open-api, --proto or --tap")
 	err := options.validate()
 	if err == nil || err.Error() != exp.Error() {
 		t.Fatalf("validateOptions returned unexpected error: %s (expected: %s) for options: %+v", err, exp, options)
@@ -44,7 +47,7 @@ func TestValidateOptions(t *testing.T) {
 	options = newProfileOptions()
 	options.template = true
 	options.openAPI = "openAPI"
-	exp = errors.New("You must specify exactly one of --template or --open-api")
+	exp = errors.New("You must specify exactly one of --template, --open-api, --proto or --tap")
 	err = options.validate()
 	if err == nil || err.Error() != exp.Error() {
 		t.Fatalf("validateOptions returned unexpected error: %s (expected: %s) for options: %+v", err, exp, options)
@@ -83,6 +86,24 @@ func TestValidateOptions(t *testing.T) {
 		t.Fatalf("validateOptions returned unexpected error (%s) for options: %+v", err, options)
 	}
 
+	options = newProfileOptions()
+	options.proto = "proto"
+	options.name = "proto-name"
+	err = options.validate()
+	if err != nil {
+		t.Fatalf("validateOptions returned unexpected error (%s) for options: %+v", err, options)
+	}
+
+	options = newProfileOptions()
+	options.tap = "deploy/web"
+	options.name = "tap-name"
+	options.tapRouteLimit = 0
+	exp = errors.New("invalid --tap-route-limit 0: must be positive")
+	err = options.validate()
+	if err == nil || err.Error() != exp.Error() {
+		t.Fatalf("validateOptions returned unexpected error: %s (expected: %s) for options: %+v", err, exp, options)
+	}
+
 	options = newProfileOptions()
 	options.template = true
 	options.name = "service.name"
@@ -139,3 +160,271 @@ func TestValidateOptions(t *testing.T) {
 		t.Fatalf("validateOptions returned unexpected error: %s (expected: %s) for options: %+v", err, exp, options)
 	}
 }
+
+func openAPITestRoute(method, name, pathRegex string, statuses ...*v1alpha1.Range) *v1alpha1.RouteSpec {
+	route := &v1alpha1.RouteSpec{
+		Name:            fmt.Sprintf("%s %s", method, name),
+		Condition:       &v1alpha1.RequestMatch{PathRegex: pathRegex, Method: method},
+		ResponseClasses: []*v1alpha1.ResponseClass{},
+	}
+	for _, status := range statuses {
+		route.ResponseClasses = append(route.ResponseClasses, &v1alpha1.ResponseClass{
+			Condition: &v1alpha1.ResponseMatch{Status: status},
+			IsFailure: status.Min >= 500,
+		})
+	}
+	return route
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	testCases := []struct {
+		title  string
+		spec   string
+		routes []*v1alpha1.RouteSpec
+		err    string
+	}{
+		{
+			title: "OpenAPI 3 specifications",
+			spec: `openapi: 3.0.1
+servers:
+- url: https://{environment}.example.com/{version}/
+  variables:
+    environment:
+      default: api
+    version:
+      default: v1
+components:
+  parameters:
+    bookId:
+      name: id
+      in: path
+      required: true
+      schema:
+        type: integer
+paths:
+  /books/{id}:
+    parameters:
+    - $ref: '#/components/parameters/bookId'
+    get:
+      responses:
+        200:
+          description: the book
+        4XX:
+          description: the book isn't available
+        default:
+          description: unexpected error
+    delete:
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+      responses:
+        204:
+          description: deleted
+        5XX:
+          description: not deleted
+  /files/{name}.{ext}:
+    get:
+      responses:
+        200:
+          description: the file`,
+			routes: []*v1alpha1.RouteSpec{
+				openAPITestRoute("DELETE", "/v1/books/{id}", "/v1/books/[^/]+", &v1alpha1.Range{Min: 204, Max: 204}, &v1alpha1.Range{Min: 500, Max: 599}),
+				openAPITestRoute("GET", "/v1/books/{id}", `/v1/books/\d+`, &v1alpha1.Range{Min: 200, Max: 200}, &v1alpha1.Range{Min: 400, Max: 499}),
+				openAPITestRoute("GET", "/v1/files/{name}.{ext}", `/v1/files/[^/]+\.[^/]+`, &v1alpha1.Range{Min: 200, Max: 200}),
+			},
+		},
+		{
+			title: "OpenAPI 3 specifications with several base paths",
+			spec: `openapi: 3.0.1
+servers:
+- url: /v1
+- url: https://api.example.com/v2
+paths:
+  /books:
+    get:
+      responses:
+        200:
+          description: the books`,
+			routes: []*v1alpha1.RouteSpec{
+				openAPITestRoute("GET", "/books", "(?:/v1|/v2)/books", &v1alpha1.Range{Min: 200, Max: 200}),
+			},
+		},
+		{
+			title: "OpenAPI 3 specifications with invalid response codes",
+			spec: `openapi: 3.0.1
+paths:
+  /books:
+    get:
+      responses:
+        2YY:
+          description: the books`,
+			err: "Error parsing the responses of GET /books: invalid response code '2YY'",
+		},
+		{
+			title: "Swagger 2 specifications",
+			spec: `swagger: "2.0"
+basePath: /api/
+paths:
+  /authors/{id}:
+    get:
+      parameters:
+      - name: id
+        in: path
+        required: true
+        type: integer
+      responses:
+        200:
+          description: the author
+        503:
+          description: unavailable`,
+			routes: []*v1alpha1.RouteSpec{
+				openAPITestRoute("GET", "/api/authors/{id}", `/api/authors/\d+`, &v1alpha1.Range{Min: 200, Max: 200}, &v1alpha1.Range{Min: 503, Max: 503}),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			doc, err := yaml.YAMLToJSON([]byte(tc.spec))
+			if err != nil {
+				t.Fatalf("Invalid specification: %s", err)
+			}
+
+			routes, err := openAPIRoutes(doc)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("Expected error %q, got: %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(routes, tc.routes) {
+				actual, _ := yaml.Marshal(routes)
+				expected, _ := yaml.Marshal(tc.routes)
+				t.Fatalf("Expected routes:\n%s\ngot:\n%s", expected, actual)
+			}
+		})
+	}
+}
+
+func TestProtoRoutes(t *testing.T) {
+	protoRoute := func(path, pathRegex string, retryable bool) *v1alpha1.RouteSpec {
+		return &v1alpha1.RouteSpec{
+			Name:        path,
+			Condition:   &v1alpha1.RequestMatch{PathRegex: pathRegex, Method: "POST"},
+			IsRetryable: retryable,
+		}
+	}
+
+	files, err := loadProtoFiles("testdata/profile_proto/emoji.proto", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := []*v1alpha1.RouteSpec{
+		protoRoute("/emojivoto.v1.EmojiService/ListAll", `/emojivoto\.v1\.EmojiService/ListAll`, true),
+		protoRoute("/emojivoto.v1.EmojiService/FindByShortcode", `/emojivoto\.v1\.EmojiService/FindByShortcode`, false),
+		protoRoute("/emojivoto.v1.EmojiService/Watch", `/emojivoto\.v1\.EmojiService/Watch`, false),
+		protoRoute("/emojivoto.v1.admin.Admin/Delete", `/emojivoto\.v1\.admin\.Admin/Delete`, false),
+		protoRoute("/emojivoto.v1.admin.Admin/Purge", `/emojivoto\.v1\.admin\.Admin/Purge`, true),
+	}
+	routes := protoRoutes(files)
+	if !reflect.DeepEqual(routes, expected) {
+		actual, _ := yaml.Marshal(routes)
+		exp, _ := yaml.Marshal(expected)
+		t.Fatalf("Expected routes:\n%s\ngot:\n%s", exp, actual)
+	}
+}
+
+func TestParseProtoErrors(t *testing.T) {
+	testCases := []struct {
+		src string
+		err string
+	}{
+		{`package foo`, `expected ";", got the end of the file`},
+		{`import foo;`, "invalid import foo"},
+		{`service Foo { rpc Bar (Req) returns (Rsp) }`, `unexpected "}" after rpc Bar`},
+		{`service Foo { rpc Bar (Req) returns (Rsp);`, "unexpected end of file in service Foo"},
+		{`/* package foo;`, "unterminated comment"},
+	}
+
+	for _, tc := range testCases {
+		_, err := parseProto(tc.src)
+		if err == nil || err.Error() != tc.err {
+			t.Fatalf("Expected error %q for %q, got: %v", tc.err, tc.src, err)
+		}
+	}
+}
+
+func TestNewTapRoute(t *testing.T) {
+	testCases := []struct {
+		method    string
+		path      string
+		name      string
+		pathRegex string
+	}{
+		{"GET", "/api/list", "GET /api/list", "/api/list"},
+		{"GET", "/books/42?format=json", "GET /books/{id}", `/books/\d+`},
+		{"PUT", "/users/0b6c8f3e-5a2d-4c1b-9f3e-8a7d6c5b4a39/avatar.png", "PUT /users/{id}/avatar.png", `/users/[^/]+/avatar\.png`},
+		{"GET", "/blobs/4f2a9c1e7b3d8e6f", "GET /blobs/{id}", "/blobs/[^/]+"},
+		{"GET", "/v2/feed", "GET /v2/feed", "/v2/feed"},
+	}
+
+	for _, tc := range testCases {
+		route := newTapRoute(tc.method, tc.path)
+		if route.name != tc.name || route.pathRegex != tc.pathRegex {
+			t.Fatalf("Expected route %q (%s) for %s %s, got %q (%s)", tc.name, tc.pathRegex, tc.method, tc.path, route.name, route.pathRegex)
+		}
+	}
+}
+
+func TestTapRoutes(t *testing.T) {
+	request := func(direction pb.TapEvent_ProxyDirection, method pb.HttpMethod_Registered, path string) pb.TapEvent {
+		return pb.TapEvent{
+			ProxyDirection: direction,
+			Event: &pb.TapEvent_Http_{Http: &pb.TapEvent_Http{
+				Event: &pb.TapEvent_Http_RequestInit_{RequestInit: &pb.TapEvent_Http_RequestInit{
+					Method: &pb.HttpMethod{Type: &pb.HttpMethod_Registered_{Registered: method}},
+					Path:   path,
+				}},
+			}},
+		}
+	}
+
+	tapClient := &public.MockAPITapByResourceClient{
+		TapEventsToReturn: []pb.TapEvent{
+			request(pb.TapEvent_INBOUND, pb.HttpMethod_GET, "/books/1"),
+			request(pb.TapEvent_INBOUND, pb.HttpMethod_GET, "/books/2"),
+			request(pb.TapEvent_INBOUND, pb.HttpMethod_GET, "/books/3"),
+			request(pb.TapEvent_INBOUND, pb.HttpMethod_POST, "/books"),
+			request(pb.TapEvent_INBOUND, pb.HttpMethod_POST, "/books"),
+			request(pb.TapEvent_INBOUND, pb.HttpMethod_GET, "/health"),
+			request(pb.TapEvent_OUTBOUND, pb.HttpMethod_GET, "/authors/1"),
+		},
+	}
+
+	counts, err := collectTapRoutes(tapClient)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	routes, dropped := tapRoutes(counts, 2)
+	expected := []*v1alpha1.RouteSpec{
+		{Name: "GET /books/{id}", Condition: &v1alpha1.RequestMatch{PathRegex: `/books/\d+`, Method: "GET"}},
+		{Name: "POST /books", Condition: &v1alpha1.RequestMatch{PathRegex: "/books", Method: "POST"}},
+	}
+	if !reflect.DeepEqual(routes, expected) {
+		actual, _ := yaml.Marshal(routes)
+		exp, _ := yaml.Marshal(expected)
+		t.Fatalf("Expected routes:\n%s\ngot:\n%s", exp, actual)
+	}
+	if dropped != 1 {
+		t.Fatalf("Expected 1 route to be dropped, got %d", dropped)
+	}
+}
@@ -2,15 +2,20 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/linkerd/linkerd2/controller/api/public"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/util"
 	"github.com/linkerd/linkerd2/pkg/version"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -28,8 +33,11 @@ var failStatus = color.New(color.FgRed, color.Bold).SprintFunc()("\u2718")    //
 
 var controlPlaneNamespace string
 var apiAddr string // An empty value means "use the Kubernetes configuration"
+var apiTLS bool
 var kubeconfigPath string
 var kubeContext string
+var impersonate string
+var impersonateGroup []string
 var verbose bool
 
 var (
@@ -62,6 +70,11 @@ var RootCmd = &cobra.Command{
 			return fmt.Errorf("%s is not a valid namespace", controlPlaneNamespace)
 		}
 
+		// Kubernetes only impersonates groups on behalf of a user
+		if len(impersonateGroup) > 0 && impersonate == "" {
+			return errors.New("--as-group requires --as to be set")
+		}
+
 		return nil
 	},
 }
@@ -70,23 +83,42 @@ func init() {
 	RootCmd.PersistentFlags().StringVarP(&controlPlaneNamespace, "linkerd-namespace", "l", defaultNamespace, "Namespace in which Linkerd is installed [$LINKERD_NAMESPACE]")
 	RootCmd.PersistentFlags().StringVar(&kubeconfigPath, "kubeconfig", "", "Path to the kubeconfig file to use for CLI requests")
 	RootCmd.PersistentFlags().StringVar(&kubeContext, "context", "", "Name of the kubeconfig context to use")
+	RootCmd.PersistentFlags().StringVar(&impersonate, "as", "", "Username to impersonate for Kubernetes operations")
+	RootCmd.PersistentFlags().StringArrayVar(&impersonateGroup, "as-group", []string{}, "Group to impersonate for Kubernetes operations, can be repeated to specify multiple groups")
 	RootCmd.PersistentFlags().StringVar(&apiAddr, "api-addr", "", "Override kubeconfig and communicate directly with the control plane at host:port (mostly for testing)")
+	RootCmd.PersistentFlags().BoolVar(&apiTLS, "api-tls", false, "Connect to the public API over mutual TLS, through a port-forward, with the certificate of the API client identity (requires a control plane installed with --api-auth=tls)")
 	RootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Turn on debug logging")
 
+	RootCmd.AddCommand(newCmdAlpha())
+	RootCmd.AddCommand(newCmdBackup())
 	RootCmd.AddCommand(newCmdCheck())
 	RootCmd.AddCommand(newCmdCompletion())
+	RootCmd.AddCommand(newCmdConfig())
 	RootCmd.AddCommand(newCmdDashboard())
+	RootCmd.AddCommand(newCmdDiagnostics())
+	RootCmd.AddCommand(newCmdEndpoints())
+	RootCmd.AddCommand(newCmdEdges())
 	RootCmd.AddCommand(newCmdGet())
+	RootCmd.AddCommand(newCmdIdentity())
 	RootCmd.AddCommand(newCmdInject())
 	RootCmd.AddCommand(newCmdInstall())
 	RootCmd.AddCommand(newCmdLogs())
+	RootCmd.AddCommand(newCmdMesh())
+	RootCmd.AddCommand(newCmdMetrics())
+	RootCmd.AddCommand(newCmdMulticluster())
+	RootCmd.AddCommand(newCmdPlugin())
+	RootCmd.AddCommand(newCmdProbe())
 	RootCmd.AddCommand(newCmdProfile())
+	RootCmd.AddCommand(newCmdRestore())
 	RootCmd.AddCommand(newCmdRoutes())
 	RootCmd.AddCommand(newCmdStat())
 	RootCmd.AddCommand(newCmdTap())
 	RootCmd.AddCommand(newCmdTop())
 	RootCmd.AddCommand(newCmdUninject())
+	RootCmd.AddCommand(newCmdUninstall())
+	RootCmd.AddCommand(newCmdUpgrade())
 	RootCmd.AddCommand(newCmdVersion())
+	RootCmd.AddCommand(newCmdCompleteResources())
 }
 
 // cliPublicAPIClient builds a new public API client and executes default status
@@ -96,6 +128,29 @@ func cliPublicAPIClient() pb.ApiClient {
 	return validatedPublicAPIClient(time.Time{}, false)
 }
 
+// cliTapAPIClient builds the public API client of cliPublicAPIClient, whose
+// taps go through the tap APIService of the Kubernetes API when the control
+// plane serves it, so that the Kubernetes RBAC rules apply to them. With
+// --api-addr, the taps go to the public API at that address.
+func cliTapAPIClient() pb.ApiClient {
+	client := cliPublicAPIClient()
+	if apiAddr != "" {
+		return client
+	}
+
+	kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot connect to Kubernetes: %s\n", err)
+		os.Exit(1)
+	}
+	tapClient, err := public.NewTapAPIClient(kubeAPI, client)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot connect to Kubernetes: %s\n", err)
+		os.Exit(1)
+	}
+	return tapClient
+}
+
 // validatedPublicAPIClient builds a new public API client and executes status
 // checks to determine if the client can successfully connect to the API. If the
 // checks fail, then CLI will print an error and exit. If the retryDeadline
@@ -114,7 +169,10 @@ func validatedPublicAPIClient(retryDeadline time.Time, apiChecks bool) pb.ApiCli
 		ControlPlaneNamespace: controlPlaneNamespace,
 		KubeConfig:            kubeconfigPath,
 		KubeContext:           kubeContext,
+		Impersonate:           impersonate,
+		ImpersonateGroup:      impersonateGroup,
 		APIAddr:               apiAddr,
+		MutualTLS:             apiTLS,
 		RetryDeadline:         retryDeadline,
 	})
 
@@ -154,6 +212,9 @@ type statOptionsBase struct {
 	namespace    string
 	timeWindow   string
 	outputFormat string
+
+	// grpc also shows the gRPC responses by grpc-status
+	grpc bool
 }
 
 func newStatOptionsBase() *statOptionsBase {
@@ -215,16 +276,85 @@ func getPercentTLS(stats *pb.BasicStats) float64 {
 	return float64(stats.TlsRequestCount) / float64(reqTotal)
 }
 
+// grpcStatusNames are the names of the gRPC status codes, indexed by code.
+var grpcStatusNames = []string{
+	"OK",
+	"CANCELLED",
+	"UNKNOWN",
+	"INVALID_ARGUMENT",
+	"DEADLINE_EXCEEDED",
+	"NOT_FOUND",
+	"ALREADY_EXISTS",
+	"PERMISSION_DENIED",
+	"RESOURCE_EXHAUSTED",
+	"FAILED_PRECONDITION",
+	"ABORTED",
+	"OUT_OF_RANGE",
+	"UNIMPLEMENTED",
+	"INTERNAL",
+	"UNAVAILABLE",
+	"DATA_LOSS",
+	"UNAUTHENTICATED",
+}
+
+func grpcStatusName(code uint32) string {
+	if int(code) < len(grpcStatusNames) {
+		return grpcStatusNames[code]
+	}
+	return strconv.FormatUint(uint64(code), 10)
+}
+
+// getGrpcStatusRates calculates the share of the gRPC responses of each
+// grpc-status, keyed by status name, from Public API BasicStats.
+func getGrpcStatusRates(stats *pb.BasicStats) map[string]float64 {
+	total := grpcResponseCount(stats)
+	rates := make(map[string]float64)
+	if total == 0 {
+		return rates
+	}
+	for _, count := range stats.GetGrpcStatusCounts() {
+		rates[grpcStatusName(count.GetCode())] = float64(count.GetCount()) / float64(total)
+	}
+	return rates
+}
+
+// formatGrpcStatuses renders the share of the gRPC responses of each
+// grpc-status, ordered by code, e.g. "OK=98.00%,UNAVAILABLE=2.00%", or "-" if
+// there were no gRPC responses.
+func formatGrpcStatuses(stats *pb.BasicStats) string {
+	total := grpcResponseCount(stats)
+	if total == 0 {
+		return "-"
+	}
+	statuses := []string{}
+	for _, count := range stats.GetGrpcStatusCounts() {
+		rate := float64(count.GetCount()) / float64(total)
+		statuses = append(statuses, fmt.Sprintf("%s=%.2f%%", grpcStatusName(count.GetCode()), rate*100))
+	}
+	return strings.Join(statuses, ",")
+}
+
+func grpcResponseCount(stats *pb.BasicStats) uint64 {
+	total := uint64(0)
+	for _, count := range stats.GetGrpcStatusCounts() {
+		total += count.GetCount()
+	}
+	return total
+}
+
 type proxyConfigOptions struct {
 	linkerdVersion          string
 	proxyImage              string
 	initImage               string
+	debugImage              string
 	dockerRegistry          string
+	imageDigestsFile        string
+	imageDigests            map[string]string
 	imagePullPolicy         string
 	inboundPort             uint
 	outboundPort            uint
-	ignoreInboundPorts      []uint
-	ignoreOutboundPorts     []uint
+	ignoreInboundPorts      []string
+	ignoreOutboundPorts     []string
 	proxyUID                int64
 	proxyLogLevel           string
 	proxyBindTimeout        string
@@ -248,7 +378,10 @@ func newProxyConfigOptions() *proxyConfigOptions {
 		linkerdVersion:          version.Version,
 		proxyImage:              defaultDockerRegistry + "/proxy",
 		initImage:               defaultDockerRegistry + "/proxy-init",
+		debugImage:              defaultDockerRegistry + "/debug",
 		dockerRegistry:          defaultDockerRegistry,
+		imageDigestsFile:        "",
+		imageDigests:            map[string]string{},
 		imagePullPolicy:         "IfNotPresent",
 		inboundPort:             4143,
 		outboundPort:            4140,
@@ -277,6 +410,16 @@ func (options *proxyConfigOptions) validate() error {
 		return fmt.Errorf("%s is not a valid Docker registry. The url can contain only letters, numbers, dash, dot, slash and colon", options.dockerRegistry)
 	}
 
+	// the digests are read here, so that every command that renders images
+	// pins them
+	if options.imageDigestsFile != "" {
+		digests, err := readImageDigests(options.imageDigestsFile)
+		if err != nil {
+			return err
+		}
+		options.imageDigests = digests
+	}
+
 	if options.imagePullPolicy != "Always" && options.imagePullPolicy != "IfNotPresent" && options.imagePullPolicy != "Never" {
 		return fmt.Errorf("--image-pull-policy must be one of: Always, IfNotPresent, Never")
 	}
@@ -297,6 +440,18 @@ func (options *proxyConfigOptions) validate() error {
 		}
 	}
 
+	for _, p := range options.ignoreInboundPorts {
+		if _, err := util.ParsePortRange(p); err != nil {
+			return fmt.Errorf("Invalid value '%s' for --skip-inbound-ports flag: %s", p, err)
+		}
+	}
+
+	for _, p := range options.ignoreOutboundPorts {
+		if _, err := util.ParsePortRange(p); err != nil {
+			return fmt.Errorf("Invalid value '%s' for --skip-outbound-ports flag: %s", p, err)
+		}
+	}
+
 	if options.tls != "" && options.tls != optionalTLS {
 		return fmt.Errorf("--tls must be blank or set to \"%s\"", optionalTLS)
 	}
@@ -310,19 +465,26 @@ func (options *proxyConfigOptions) enableTLS() bool {
 
 func (options *proxyConfigOptions) taggedProxyImage() string {
 	image := strings.Replace(options.proxyImage, defaultDockerRegistry, options.dockerRegistry, 1)
-	return fmt.Sprintf("%s:%s", image, options.linkerdVersion)
+	return options.imageRef(image, options.linkerdVersion)
 }
 
 func (options *proxyConfigOptions) taggedProxyInitImage() string {
 	image := strings.Replace(options.initImage, defaultDockerRegistry, options.dockerRegistry, 1)
-	return fmt.Sprintf("%s:%s", image, options.linkerdVersion)
+	return options.imageRef(image, options.linkerdVersion)
+}
+
+func (options *proxyConfigOptions) taggedDebugImage() string {
+	image := strings.Replace(options.debugImage, defaultDockerRegistry, options.dockerRegistry, 1)
+	return options.imageRef(image, options.linkerdVersion)
 }
 
 func addProxyConfigFlags(cmd *cobra.Command, options *proxyConfigOptions) {
 	cmd.PersistentFlags().StringVarP(&options.linkerdVersion, "linkerd-version", "v", options.linkerdVersion, "Tag to be used for Linkerd images")
 	cmd.PersistentFlags().StringVar(&options.initImage, "init-image", options.initImage, "Linkerd init container image name")
 	cmd.PersistentFlags().StringVar(&options.proxyImage, "proxy-image", options.proxyImage, "Linkerd proxy container image name")
-	cmd.PersistentFlags().StringVar(&options.dockerRegistry, "registry", options.dockerRegistry, "Docker registry to pull images from")
+	cmd.PersistentFlags().StringVar(&options.debugImage, "debug-image", options.debugImage, "Linkerd debug container image name")
+	cmd.PersistentFlags().StringVar(&options.dockerRegistry, "registry", options.dockerRegistry, "Docker registry to pull images from, including the third-party images of the control plane, which must be mirrored to it")
+	cmd.PersistentFlags().StringVar(&options.imageDigestsFile, "image-digests-file", options.imageDigestsFile, "Path to a YAML file that maps image repositories, with or without their tags, to the digests to pin them to (e.g. \"gcr.io/linkerd-io/proxy:stable-2.2.1: sha256:...\")")
 	cmd.PersistentFlags().StringVar(&options.imagePullPolicy, "image-pull-policy", options.imagePullPolicy, "Docker image pull policy")
 	cmd.PersistentFlags().Int64Var(&options.proxyUID, "proxy-uid", options.proxyUID, "Run the proxy under this user ID")
 	cmd.PersistentFlags().StringVar(&options.proxyLogLevel, "proxy-log-level", options.proxyLogLevel, "Log level for the proxy")
@@ -335,7 +497,7 @@ func addProxyConfigFlags(cmd *cobra.Command, options *proxyConfigOptions) {
 	cmd.PersistentFlags().StringVar(&options.tls, "tls", options.tls, "Enable TLS; valid settings: \"optional\"")
 	cmd.PersistentFlags().StringVar(&options.proxyCPURequest, "proxy-cpu", options.proxyCPURequest, "Amount of CPU units that the proxy sidecar requests")
 	cmd.PersistentFlags().StringVar(&options.proxyMemoryRequest, "proxy-memory", options.proxyMemoryRequest, "Amount of Memory that the proxy sidecar requests")
-	cmd.PersistentFlags().UintSliceVar(&options.ignoreInboundPorts, "skip-inbound-ports", options.ignoreInboundPorts, "Ports that should skip the proxy and send directly to the application")
-	cmd.PersistentFlags().UintSliceVar(&options.ignoreOutboundPorts, "skip-outbound-ports", options.ignoreOutboundPorts, "Outbound ports that should skip the proxy")
+	cmd.PersistentFlags().StringSliceVar(&options.ignoreInboundPorts, "skip-inbound-ports", options.ignoreInboundPorts, "Ports and port ranges (e.g. 4000-4100) that should skip the proxy and send directly to the application")
+	cmd.PersistentFlags().StringSliceVar(&options.ignoreOutboundPorts, "skip-outbound-ports", options.ignoreOutboundPorts, "Outbound ports and port ranges (e.g. 4000-4100) that should skip the proxy")
 	cmd.PersistentFlags().BoolVar(&options.disableExternalProfiles, "disable-external-profiles", options.disableExternalProfiles, "Disables service profiles for non-Kubernetes services")
 }
@@ -28,6 +28,7 @@ type routeRowStats struct {
 	rowStats
 	actualRequestRate float64
 	actualSuccessRate float64
+	retryRatio        float64
 }
 
 const defaultRoute = "[UNKNOWN]"
@@ -48,12 +49,27 @@ func newCmdRoutes() *cobra.Command {
 		Short: "Display route stats",
 		Long: `Display route stats.
 
-This command will only display traffic which is sent to a service that has a Service Profile defined.`,
+This command will only display traffic which is sent to a service that has a Service Profile defined.
+
+With --to, the wide output shows both the effective stats of the requests of the
+application, and the actual stats of the requests sent by the proxy, which
+include the retries. The RETRIES column is the share of the actual requests
+that were retries, which a high effective success rate can hide.
+
+The routes of the Service Profiles generated from protobuf files are the methods
+of their gRPC services. With --grpc, the share of the responses of each route
+with each grpc-status (e.g. OK, DEADLINE_EXCEEDED or UNAVAILABLE) is also shown.`,
 		Example: `  # Routes for the webapp service in the test namespace.
   linkerd routes service/webapp -n test
 
   # Routes for calls from from the traffic deployment to the webapp service in the test namespace.
-  linkerd routes deploy/traffic -n test --to svc/webapp`,
+  linkerd routes deploy/traffic -n test --to svc/webapp
+
+  # Effective and actual stats of those calls, to see how much the retries mask failures.
+  linkerd routes deploy/traffic -n test --to svc/webapp -o wide
+
+  # Methods of the gRPC emoji service in the emojivoto namespace, by grpc-status.
+  linkerd routes svc/emoji-svc -n emojivoto --grpc`,
 		Args:      cobra.ExactArgs(1),
 		ValidArgs: util.ValidTargets,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -78,6 +94,7 @@ This command will only display traffic which is sent to a service that has a Ser
 	cmd.PersistentFlags().StringVar(&options.toResource, "to", options.toResource, "If present, shows outbound stats to the specified resource")
 	cmd.PersistentFlags().StringVar(&options.toNamespace, "to-namespace", options.toNamespace, "Sets the namespace used to lookup the \"--to\" resource; by default the current \"--namespace\" is used")
 	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; currently only \"table\" (default), \"wide\", and \"json\" are supported")
+	cmd.PersistentFlags().BoolVar(&options.grpc, "grpc", options.grpc, "If present, also shows the share of the gRPC responses of each grpc-status")
 
 	return cmd
 }
@@ -114,7 +131,7 @@ func writeRouteStatsToBuffer(resp *pb.TopRoutesResponse, w *tabwriter.Writer, op
 		for _, r := range resourceTable.GetRows() {
 			if r.Stats != nil {
 				route := r.GetRoute()
-				table = append(table, &routeRowStats{
+				row := &routeRowStats{
 					rowStats: rowStats{
 						route:       route,
 						dst:         r.GetAuthority(),
@@ -126,7 +143,13 @@ func writeRouteStatsToBuffer(resp *pb.TopRoutesResponse, w *tabwriter.Writer, op
 					},
 					actualRequestRate: getRequestRate(r.Stats.GetActualSuccessCount(), r.Stats.GetActualFailureCount(), r.TimeWindow),
 					actualSuccessRate: getSuccessRate(r.Stats.GetActualSuccessCount(), r.Stats.GetActualFailureCount()),
-				})
+					retryRatio:        getRetryRatio(r.Stats),
+				}
+				if options.grpc {
+					row.grpcStatuses = formatGrpcStatuses(r.Stats)
+					row.grpcStatusRates = getGrpcStatusRates(r.Stats)
+				}
+				table = append(table, row)
 			}
 		}
 
@@ -177,6 +200,7 @@ func printRouteTable(stats []*routeRowStats, w *tabwriter.Writer, options *route
 			"EFFECTIVE_RPS",
 			"ACTUAL_SUCCESS",
 			"ACTUAL_RPS",
+			"RETRIES",
 		}...)
 	} else {
 		headers = append(headers, []string{
@@ -188,19 +212,28 @@ func printRouteTable(stats []*routeRowStats, w *tabwriter.Writer, options *route
 	headers = append(headers, []string{
 		"LATENCY_P50",
 		"LATENCY_P95",
-		"LATENCY_P99\t", // trailing \t is required to format last column
+		"LATENCY_P99",
 	}...)
+	if options.grpc {
+		headers = append(headers, "GRPC_STATUSES")
+	}
+	headers[len(headers)-1] += "\t" // trailing \t is required to format last column
 
 	fmt.Fprintln(w, strings.Join(headers, "\t"))
 
 	// route, success rate, rps
 	templateString := routeTemplate + "\t%s\t%.2f%%\t%.1frps\t"
 	if outputActual {
-		// actual success rate, actual rps
-		templateString = templateString + "%.2f%%\t%.1frps\t"
+		// actual success rate, actual rps, retries
+		templateString = templateString + "%.2f%%\t%.1frps\t%.2f%%\t"
 	}
 	// p50, p95, p99
-	templateString = templateString + "%dms\t%dms\t%dms\t\n"
+	templateString = templateString + "%dms\t%dms\t%dms\t"
+	if options.grpc {
+		// grpc statuses
+		templateString = templateString + "%s\t"
+	}
+	templateString = templateString + "\n"
 
 	for _, row := range stats {
 
@@ -214,6 +247,7 @@ func printRouteTable(stats []*routeRowStats, w *tabwriter.Writer, options *route
 			values = append(values, []interface{}{
 				row.actualSuccessRate * 100,
 				row.actualRequestRate,
+				row.retryRatio * 100,
 			}...)
 		}
 		values = append(values, []interface{}{
@@ -221,6 +255,9 @@ func printRouteTable(stats []*routeRowStats, w *tabwriter.Writer, options *route
 			row.latencyP95,
 			row.latencyP99,
 		}...)
+		if options.grpc {
+			values = append(values, row.grpcStatuses)
+		}
 
 		fmt.Fprintf(w, templateString, values...)
 	}
@@ -228,17 +265,19 @@ func printRouteTable(stats []*routeRowStats, w *tabwriter.Writer, options *route
 
 // Using pointers there where the value is NA and the corresponding json is null
 type jsonRouteStats struct {
-	Route            string   `json:"route"`
-	Authority        string   `json:"authority"`
-	Success          *float64 `json:"success,omitempty"`
-	Rps              *float64 `json:"rps,omitempty"`
-	EffectiveSuccess *float64 `json:"effective_success,omitempty"`
-	EffectiveRps     *float64 `json:"effective_rps,omitempty"`
-	ActualSuccess    *float64 `json:"actual_success,omitempty"`
-	ActualRps        *float64 `json:"actual_rps,omitempty"`
-	LatencyMSp50     *uint64  `json:"latency_ms_p50"`
-	LatencyMSp95     *uint64  `json:"latency_ms_p95"`
-	LatencyMSp99     *uint64  `json:"latency_ms_p99"`
+	Route            string             `json:"route"`
+	Authority        string             `json:"authority"`
+	Success          *float64           `json:"success,omitempty"`
+	Rps              *float64           `json:"rps,omitempty"`
+	EffectiveSuccess *float64           `json:"effective_success,omitempty"`
+	EffectiveRps     *float64           `json:"effective_rps,omitempty"`
+	ActualSuccess    *float64           `json:"actual_success,omitempty"`
+	ActualRps        *float64           `json:"actual_rps,omitempty"`
+	Retries          *float64           `json:"retries,omitempty"`
+	LatencyMSp50     *uint64            `json:"latency_ms_p50"`
+	LatencyMSp95     *uint64            `json:"latency_ms_p95"`
+	LatencyMSp99     *uint64            `json:"latency_ms_p99"`
+	GrpcStatuses     map[string]float64 `json:"grpc_statuses,omitempty"`
 }
 
 func printRouteJSON(tables map[string][]*routeRowStats, w *tabwriter.Writer, options *routesOptions) {
@@ -257,6 +296,7 @@ func printRouteJSON(tables map[string][]*routeRowStats, w *tabwriter.Writer, opt
 				entry.EffectiveRps = &row.requestRate
 				entry.ActualSuccess = &row.actualSuccessRate
 				entry.ActualRps = &row.actualRequestRate
+				entry.Retries = &row.retryRatio
 			} else {
 				entry.Success = &row.successRate
 				entry.Rps = &row.requestRate
@@ -264,6 +304,9 @@ func printRouteJSON(tables map[string][]*routeRowStats, w *tabwriter.Writer, opt
 			entry.LatencyMSp50 = &row.latencyP50
 			entry.LatencyMSp95 = &row.latencyP95
 			entry.LatencyMSp99 = &row.latencyP99
+			if options.grpc {
+				entry.GrpcStatuses = row.grpcStatusRates
+			}
 
 			entries[resource] = append(entries[resource], entry)
 		}
@@ -307,6 +350,7 @@ func buildTopRoutesRequest(resource string, options *routesOptions) (*pb.TopRout
 			ResourceName: target.Name,
 			ResourceType: target.Type,
 			Namespace:    options.namespace,
+			GrpcStats:    options.grpc,
 		},
 	}
 
@@ -331,6 +375,19 @@ func buildTopRoutesRequest(resource string, options *routesOptions) (*pb.TopRout
 	return util.BuildTopRoutesRequest(requestParams)
 }
 
+// getRetryRatio calculates the share of the actual requests of a route that
+// were retries, from Public API BasicStats: the actual requests include the
+// retries, while the effective requests are those of the application.
+func getRetryRatio(stats *pb.BasicStats) float64 {
+	actual := stats.GetActualSuccessCount() + stats.GetActualFailureCount()
+	effective := stats.GetSuccessCount() + stats.GetFailureCount()
+	// the counts are queried separately, so they may be slightly off
+	if actual <= effective {
+		return 0.0
+	}
+	return float64(actual-effective) / float64(actual)
+}
+
 // returns the length of the longest route name
 func routeWidth(stats []*routeRowStats) int {
 	maxLength := 0
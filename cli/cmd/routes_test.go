@@ -4,13 +4,19 @@ import (
 	"testing"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
 )
 
 type routesParamsExp struct {
 	options *routesOptions
 	routes  []string
 	counts  []uint64
-	file    string
+	// grpcStatuses are the gRPC responses by grpc-status of each route
+	grpcStatuses map[string][]*pb.GrpcStatusCount
+	// actualFailures are the failed actual requests of each route, i.e. the
+	// retried ones
+	actualFailures map[string]uint64
+	file           string
 }
 
 func TestRoutes(t *testing.T) {
@@ -33,12 +39,45 @@ func TestRoutes(t *testing.T) {
 			file:    "routes_one_output_json.golden",
 		}, t)
 	})
+
+	options = newRoutesOptions()
+	options.grpc = true
+	t.Run("Returns route stats with the grpc statuses", func(t *testing.T) {
+		testRoutesCall(routesParamsExp{
+			routes: []string{"/a", "/b", "/c"},
+			counts: []uint64{90, 60, 0, 30},
+			grpcStatuses: map[string][]*pb.GrpcStatusCount{
+				"/a":        {{Code: 0, Count: 90}},
+				"/b":        {{Code: 0, Count: 54}, {Code: 14, Count: 6}},
+				"[DEFAULT]": {{Code: 0, Count: 30}},
+			},
+			options: options,
+			file:    "routes_grpc_output.golden",
+		}, t)
+	})
+
+	options = newRoutesOptions()
+	options.toResource = "deploy/bar"
+	options.outputFormat = "wide"
+	t.Run("Returns the effective and actual route stats (wide)", func(t *testing.T) {
+		testRoutesCall(routesParamsExp{
+			routes:         []string{"/a", "/b", "/c"},
+			counts:         []uint64{90, 60, 0, 30},
+			actualFailures: map[string]uint64{"/a": 30},
+			options:        options,
+			file:           "routes_wide_output.golden",
+		}, t)
+	})
 }
 
 func testRoutesCall(exp routesParamsExp, t *testing.T) {
 	mockClient := &public.MockAPIClient{}
 
 	response := public.GenTopRoutesResponse(exp.routes, exp.counts, exp.options.toResource != "", "foobar")
+	for _, row := range response.GetOk().GetRoutes()[0].GetRows() {
+		row.Stats.GrpcStatusCounts = exp.grpcStatuses[row.GetRoute()]
+		row.Stats.ActualFailureCount = exp.actualFailures[row.GetRoute()]
+	}
 
 	mockClient.TopRoutesResponseToReturn = &response
 
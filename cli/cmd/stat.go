@@ -10,6 +10,7 @@ import (
 	"strings"
 	"text/tabwriter"
 
+	"github.com/linkerd/linkerd2/controller/api/public"
 	"github.com/linkerd/linkerd2/controller/api/util"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
@@ -24,12 +25,16 @@ type statOptions struct {
 	fromNamespace string
 	fromResource  string
 	allNamespaces bool
+	metricLabels  []string
+	authority     bool
+	routes        bool
 }
 
 type indexedResults struct {
-	ix   int
-	rows []*pb.StatTable_PodGroup_Row
-	err  error
+	ix      int
+	rows    []*pb.StatTable_PodGroup_Row
+	partial bool
+	err     error
 }
 
 func newStatOptions() *statOptions {
@@ -40,6 +45,9 @@ func newStatOptions() *statOptions {
 		fromNamespace:   "",
 		fromResource:    "",
 		allNamespaces:   false,
+		metricLabels:    []string{},
+		authority:       false,
+		routes:          false,
 	}
 }
 
@@ -63,23 +71,45 @@ func newCmdStat() *cobra.Command {
   * ns/my-ns
   * authority
   * au/my-authority
+  * ts/my-traffic-split
   * po/mypod1 rc/my-replication-controller
   * po mypod1 mypod2
   * deploy/ po/
   * all
 
   Valid resource types include:
+  * cronjobs
   * deployments
+  * jobs
   * namespaces
   * pods
+  * replicasets
   * replicationcontrollers
   * authorities (not supported in --from)
+  * trafficsplits (not supported in --to)
   * services (only supported if a --from is also specified, or as a --to)
-  * jobs (only supported as a --from or --to)
   * all (all resource types, not supported in --from or --to)
 
 This command will hide resources that have completed, such as pods that are in the Succeeded or Failed phases.
-If no resource name is specified, displays stats about all resources of the specified RESOURCETYPE`,
+If no resource name is specified, displays stats about all resources of the specified RESOURCETYPE
+
+The stats of a traffic split are shown for each of its backends, with their weight: they're
+the stats of the requests sent to the apex service of the split that were routed to the backend.
+
+Resources can declare a target success rate with the linkerd.io/slo-success-rate
+annotation, e.g. "99.9" for 99.9% of their requests. It's shown in an SLO column,
+flagged with ✘ when the success rate of the resource is below it.
+
+The names of the --to and --from resources can contain "*" wildcards, such as
+deploy/web-*, and several resources of the same type can be comma-separated, such
+as deploy/web,deploy/voting.
+
+With --authority or --routes, the stats of each resource are broken down by the
+authority of its requests, or by the route of their service profile, on a line of
+their own; the requests that match none of the routes are shown under the
+[DEFAULT] route. The resources whose requests can't be broken down, such as those
+without service profiles, are shown with their stats under "-". The breakdowns
+aren't supported for authorities and traffic splits, nor by route with --to or --from.`,
 		Example: `  # Get all deployments in the test namespace.
   linkerd stat deployments -n test
 
@@ -98,6 +128,10 @@ If no resource name is specified, displays stats about all resources of the spec
   # Getl all inbound stats to the pod1 pod and the web deployment
   linkerd stat po/pod1 deploy/web
 
+  # Get all pods in the test namespace, with their proxy status and container
+  # restarts, to find the pods that bring down the stats of their deployment.
+  linkerd stat pods -n test
+
   # Get all pods in all namespaces that call the hello1 deployment in the test namesapce.
   linkerd stat pods --to deploy/hello1 --to-namespace test --all-namespaces
 
@@ -107,11 +141,40 @@ If no resource name is specified, displays stats about all resources of the spec
   # Get all services in all namespaces that receive calls from hello1 deployment in the test namespace.
   linkerd stat services --from deploy/hello1 --from-namespace test --all-namespaces
 
+  # Get all deployments in the test namespace that call the web or voting deployments.
+  linkerd stat deploy -n test --to deploy/web,deploy/voting
+
+  # Get all services in the test namespace that receive calls from any deployment.
+  linkerd stat services -n test --from 'deploy/*'
+
   # Get all namespaces that receive traffic from the default namespace.
   linkerd stat namespaces --from ns/default
 
   # Get all inbound stats to the test namespace.
-  linkerd stat ns/test`,
+  linkerd stat ns/test
+
+  # Get the stats of the backends of all traffic splits in the test namespace.
+  linkerd stat ts -n test
+
+  # Get all deployments in the test namespace, with the rate of requests they
+  # receive from unmeshed clients.
+  linkerd stat deploy -n test -o wide
+
+  # Get all deployments in the test namespace, with the share of their gRPC
+  # responses of each grpc-status, e.g. OK and UNAVAILABLE.
+  linkerd stat deploy -n test --grpc
+
+  # Get all inbound stats to the pods labeled version=v2 in the test namespace.
+  # The version label must be in the --metric-pod-labels of the control plane.
+  linkerd stat deploy -n test --metric-label version=v2
+
+  # Get the stats of the web deployment in the test namespace for each authority
+  # of the requests it sends to the voting deployment.
+  linkerd stat deploy/web -n test --to deploy/voting --authority
+
+  # Get the stats of the web deployment in the test namespace for each route of
+  # its service profile.
+  linkerd stat deploy/web -n test --routes`,
 		Args:      cobra.MinimumNArgs(1),
 		ValidArgs: util.ValidTargets,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -128,17 +191,19 @@ If no resource name is specified, displays stats about all resources of the spec
 				go func(num int, req *pb.StatSummaryRequest) {
 					resp, err := requestStatsFromAPI(client, req, options)
 					rows := respToRows(resp)
-					c <- indexedResults{num, rows, err}
+					c <- indexedResults{num, rows, resp.GetOk().GetPartial(), err}
 				}(num, req)
 			}
 
 			totalRows := make([]*pb.StatTable_PodGroup_Row, 0)
+			partial := false
 			i := 0
 			for res := range c {
 				if res.err != nil {
 					return res.err
 				}
 				totalRows = append(totalRows, res.rows...)
+				partial = partial || res.partial
 				if i++; i == len(reqs) {
 					close(c)
 				}
@@ -147,18 +212,26 @@ If no resource name is specified, displays stats about all resources of the spec
 			output := renderStatStats(totalRows, options)
 			_, err = fmt.Print(output)
 
+			if partial {
+				fmt.Fprintln(os.Stderr, "Warning: some stats are missing or incomplete, as the Prometheus query budget of the request was exceeded")
+			}
+
 			return err
 		},
 	}
 
 	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the specified resource")
 	cmd.PersistentFlags().StringVarP(&options.timeWindow, "time-window", "t", options.timeWindow, "Stat window (for example: \"10s\", \"1m\", \"10m\", \"1h\")")
-	cmd.PersistentFlags().StringVar(&options.toResource, "to", options.toResource, "If present, restricts outbound stats to the specified resource name")
+	cmd.PersistentFlags().StringVar(&options.toResource, "to", options.toResource, "If present, restricts outbound stats to the specified resource name; names can contain \"*\" wildcards, and several resources of the same type can be comma-separated")
 	cmd.PersistentFlags().StringVar(&options.toNamespace, "to-namespace", options.toNamespace, "Sets the namespace used to lookup the \"--to\" resource; by default the current \"--namespace\" is used")
-	cmd.PersistentFlags().StringVar(&options.fromResource, "from", options.fromResource, "If present, restricts outbound stats from the specified resource name")
+	cmd.PersistentFlags().StringVar(&options.fromResource, "from", options.fromResource, "If present, restricts outbound stats from the specified resource name; names can contain \"*\" wildcards, and several resources of the same type can be comma-separated")
 	cmd.PersistentFlags().StringVar(&options.fromNamespace, "from-namespace", options.fromNamespace, "Sets the namespace used from lookup the \"--from\" resource; by default the current \"--namespace\" is used")
 	cmd.PersistentFlags().BoolVar(&options.allNamespaces, "all-namespaces", options.allNamespaces, "If present, returns stats across all namespaces, ignoring the \"--namespace\" flag")
-	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; currently only \"table\" (default) and \"json\" are supported")
+	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; currently only \"table\" (default), \"wide\", and \"json\" are supported")
+	cmd.PersistentFlags().BoolVar(&options.grpc, "grpc", options.grpc, "If present, also shows the share of the gRPC responses of each grpc-status")
+	cmd.PersistentFlags().StringArrayVar(&options.metricLabels, "metric-label", options.metricLabels, "Restricts stats to the pods with the given label, as \"key=value\"; the label must be in the --metric-pod-labels of the control plane")
+	cmd.PersistentFlags().BoolVar(&options.authority, "authority", options.authority, "If present, breaks the stats of each resource down by the authority of the requests")
+	cmd.PersistentFlags().BoolVar(&options.routes, "routes", options.routes, "If present, breaks the stats of each resource down by the route of the service profiles of the requests; not supported with --to or --from")
 
 	return cmd
 }
@@ -174,7 +247,7 @@ func respToRows(resp *pb.StatSummaryResponse) []*pb.StatTable_PodGroup_Row {
 }
 
 func requestStatsFromAPI(client pb.ApiClient, req *pb.StatSummaryRequest, options *statOptions) (*pb.StatSummaryResponse, error) {
-	resp, err := client.StatSummary(context.Background(), req)
+	resp, err := public.StatSummaryAll(context.Background(), client, req)
 	if err != nil {
 		return nil, fmt.Errorf("StatSummary API error: %v", err)
 	}
@@ -196,6 +269,10 @@ func renderStatStats(rows []*pb.StatTable_PodGroup_Row, options *statOptions) st
 
 const padding = 3
 
+// sloViolation flags the SLOs that are violated. Unlike failStatus, it isn't
+// colored, since the escape codes would misalign the columns of the tables.
+const sloViolation = "\u2718" // ✘
+
 type rowStats struct {
 	route       string
 	dst         string
@@ -205,13 +282,85 @@ type rowStats struct {
 	latencyP50  uint64
 	latencyP95  uint64
 	latencyP99  uint64
+
+	// rate of the inbound requests from clients without an identity
+	unmeshedRate float64
+
+	// grpcStatuses and grpcStatusRates are the share of the gRPC responses
+	// by grpc-status, only set with --grpc
+	grpcStatuses    string
+	grpcStatusRates map[string]float64
 }
 
 type row struct {
 	meshed string
+
+	// proxy and restarts are only shown for pods, to spot the pods that bring
+	// down the stats of their deployment
+	proxy    string
+	restarts uint64
+
+	// sloSuccessRate is the target success rate of the resource, or 0 if it
+	// has none
+	sloSuccessRate float64
+
+	// tsStats are the apex, leaf and weight of the backend of a traffic
+	// split, only set for traffic splits
+	tsStats *tsStats
+
+	// groups are the stats of the resource broken down by authority or route,
+	// only set with --authority or --routes
+	groups []*groupRow
+
 	*rowStats
 }
 
+// groupRow is a line of the table of a resource whose stats are broken down
+// by authority or route.
+type groupRow struct {
+	group string
+
+	*rowStats
+}
+
+type tsStats struct {
+	apex   string
+	leaf   string
+	weight string
+}
+
+// sloViolated returns true if the resource had requests in the time window,
+// and a success rate below its target.
+func (r *row) sloViolated() bool {
+	return r.sloSuccessRate > 0 && r.rowStats != nil && r.requestRate > 0 && r.successRate < r.sloSuccessRate
+}
+
+// lines returns the lines of the resource in the table: one for each of its
+// groups if the stats are grouped and it has any, or else one with the stats
+// of the resource, under "-" if the stats are grouped.
+func (r *row) lines(grouped bool) []*groupRow {
+	if !grouped {
+		return []*groupRow{{rowStats: r.rowStats}}
+	}
+	if len(r.groups) == 0 {
+		return []*groupRow{{group: "-", rowStats: r.rowStats}}
+	}
+	return r.groups
+}
+
+// slo returns the target success rate of the resource, flagged if it was
+// violated, or "-" if it has none.
+func (r *row) slo() string {
+	if r.sloSuccessRate == 0 {
+		return "-"
+	}
+	slo := fmt.Sprintf("%.2f%%", r.sloSuccessRate*100)
+	if r.sloViolated() {
+		slo = sloViolation + " " + slo
+	}
+	return slo
+}
+
 var (
 	nameHeader      = "NAME"
 	namespaceHeader = "NAMESPACE"
@@ -241,6 +390,10 @@ func writeStatsToBuffer(rows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer,
 		namespace := r.Resource.Namespace
 		key := fmt.Sprintf("%s/%s", namespace, name)
 		resourceKey := r.Resource.Type
+		// a traffic split has a row for each of its backends
+		if r.TsStats != nil {
+			key = fmt.Sprintf("%s/%s", key, r.TsStats.Leaf)
+		}
 
 		if _, ok := statTables[resourceKey]; !ok {
 			statTables[resourceKey] = make(map[string]*row)
@@ -255,22 +408,39 @@ func writeStatsToBuffer(rows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer,
 		}
 
 		meshedCount := fmt.Sprintf("%d/%d", r.MeshedPodCount, r.RunningPodCount)
-		if resourceKey == k8s.Authority {
+		if resourceKey == k8s.Authority || resourceKey == k8s.TrafficSplit {
 			meshedCount = "-"
 		}
+		proxy := "-"
+		if r.MeshedPodCount > 0 {
+			proxy = "not ready"
+			if r.ProxyReadyPodCount > 0 {
+				proxy = "ready"
+			}
+		}
 		statTables[resourceKey][key] = &row{
-			meshed: meshedCount,
+			meshed:         meshedCount,
+			proxy:          proxy,
+			restarts:       r.RestartCount,
+			sloSuccessRate: r.SloSuccessRate,
+		}
+		if r.TsStats != nil {
+			statTables[resourceKey][key].tsStats = &tsStats{
+				apex:   r.TsStats.Apex,
+				leaf:   r.TsStats.Leaf,
+				weight: r.TsStats.Weight,
+			}
 		}
 
 		if r.Stats != nil {
-			statTables[resourceKey][key].rowStats = &rowStats{
-				requestRate: getRequestRate(r.Stats.GetSuccessCount(), r.Stats.GetFailureCount(), r.TimeWindow),
-				successRate: getSuccessRate(r.Stats.GetSuccessCount(), r.Stats.GetFailureCount()),
-				tlsPercent:  getPercentTLS(r.Stats),
-				latencyP50:  r.Stats.LatencyMsP50,
-				latencyP95:  r.Stats.LatencyMsP95,
-				latencyP99:  r.Stats.LatencyMsP99,
+			statTables[resourceKey][key].rowStats = newRowStats(r.Stats, r.TimeWindow, options)
+		}
+		for _, g := range r.GroupStats {
+			group := &groupRow{group: g.Group}
+			if g.Stats != nil {
+				group.rowStats = newRowStats(g.Stats, r.TimeWindow, options)
 			}
+			statTables[resourceKey][key].groups = append(statTables[resourceKey][key].groups, group)
 		}
 	}
 
@@ -282,8 +452,25 @@ func writeStatsToBuffer(rows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer,
 		}
 		printStatTables(statTables, w, maxNameLength, maxNamespaceLength, options)
 	case "json":
-		printStatJSON(statTables, w)
+		printStatJSON(statTables, w, options)
+	}
+}
+
+func newRowStats(stats *pb.BasicStats, timeWindow string, options *statOptions) *rowStats {
+	rs := &rowStats{
+		requestRate:  getRequestRate(stats.GetSuccessCount(), stats.GetFailureCount(), timeWindow),
+		successRate:  getSuccessRate(stats.GetSuccessCount(), stats.GetFailureCount()),
+		tlsPercent:   getPercentTLS(stats),
+		latencyP50:   stats.GetLatencyMsP50(),
+		latencyP95:   stats.GetLatencyMsP95(),
+		latencyP99:   stats.GetLatencyMsP99(),
+		unmeshedRate: getRequestRate(stats.GetUnmeshedRequestCount(), 0, timeWindow),
+	}
+	if options.grpc {
+		rs.grpcStatuses = formatGrpcStatuses(stats)
+		rs.grpcStatusRates = getGrpcStatusRates(stats)
 	}
+	return rs
 }
 
 func printStatTables(statTables map[string]map[string]*row, w *tabwriter.Writer, maxNameLength int, maxNamespaceLength int, options *statOptions) {
@@ -303,27 +490,57 @@ func printStatTables(statTables map[string]map[string]*row, w *tabwriter.Writer,
 			if !usePrefix {
 				resourceTypeLabel = ""
 			}
-			printSingleStatTable(stats, resourceTypeLabel, w, maxNameLength, maxNamespaceLength, options)
+			if resourceType == k8s.TrafficSplit {
+				printTrafficSplitTable(stats, resourceTypeLabel, w, maxNameLength, maxNamespaceLength, options)
+			} else {
+				printSingleStatTable(stats, resourceTypeLabel, resourceType == k8s.Pod, w, maxNameLength, maxNamespaceLength, options)
+			}
 		}
 	}
 }
 
-func printSingleStatTable(stats map[string]*row, resourceType string, w *tabwriter.Writer, maxNameLength int, maxNamespaceLength int, options *statOptions) {
+func printSingleStatTable(stats map[string]*row, resourceType string, showPodStatus bool, w *tabwriter.Writer, maxNameLength int, maxNamespaceLength int, options *statOptions) {
 	headers := make([]string, 0)
 	if options.allNamespaces {
 		headers = append(headers,
 			namespaceHeader+strings.Repeat(" ", maxNamespaceLength-len(namespaceHeader)))
 	}
-	headers = append(headers, []string{
-		nameHeader + strings.Repeat(" ", maxNameLength-len(nameHeader)),
+	headers = append(headers,
+		nameHeader+strings.Repeat(" ", maxNameLength-len(nameHeader)),
 		"MESHED",
+	)
+	groupHeader := strings.ToUpper(options.groupBy())
+	if groupHeader != "" {
+		headers = append(headers, groupHeader)
+	}
+	headers = append(headers, []string{
 		"SUCCESS",
 		"RPS",
 		"LATENCY_P50",
 		"LATENCY_P95",
 		"LATENCY_P99",
-		"TLS\t", // trailing \t is required to format last column
+		"TLS",
 	}...)
+	if options.outputFormat == "wide" {
+		headers = append(headers, "UNMESHED_RPS")
+	}
+	if options.grpc {
+		headers = append(headers, "GRPC_STATUSES")
+	}
+	// the SLO column is only shown for the resources that have SLOs
+	showSLO := false
+	for _, r := range stats {
+		if r.sloSuccessRate > 0 {
+			showSLO = true
+		}
+	}
+	if showSLO {
+		headers = append(headers, "SLO")
+	}
+	if showPodStatus {
+		headers = append(headers, "PROXY", "RESTARTS")
+	}
+	headers[len(headers)-1] += "\t" // trailing \t is required to format last column
 
 	fmt.Fprintln(w, strings.Join(headers, "\t"))
 
@@ -331,14 +548,35 @@ func printSingleStatTable(stats map[string]*row, resourceType string, w *tabwrit
 	for _, key := range sortedKeys {
 		namespace, name := namespaceName(resourceType, key)
 		values := make([]interface{}, 0)
-		templateString := "%s\t%s\t%.2f%%\t%.1frps\t%dms\t%dms\t%dms\t%.f%%\t\n"
-		templateStringEmpty := "%s\t%s\t-\t-\t-\t-\t-\t-\t\n"
+		prefixTemplate := "%s\t%s\t"
+		templateString := "%.2f%%\t%.1frps\t%dms\t%dms\t%dms\t%.f%%\t"
+		templateStringEmpty := "-\t-\t-\t-\t-\t-\t"
+		if options.outputFormat == "wide" {
+			templateString += "%.1frps\t"
+			templateStringEmpty += "-\t"
+		}
+		if options.grpc {
+			templateString += "%s\t"
+			templateStringEmpty += "-\t"
+		}
+		status := []interface{}{}
+		if showSLO {
+			templateString += "%s\t"
+			templateStringEmpty += "%s\t"
+			status = append(status, stats[key].slo())
+		}
+		if showPodStatus {
+			templateString += "%s\t%d\t"
+			templateStringEmpty += "%s\t%d\t"
+			status = append(status, stats[key].proxy, stats[key].restarts)
+		}
+		templateString += "\n"
+		templateStringEmpty += "\n"
 
 		if options.allNamespaces {
 			values = append(values,
 				namespace+strings.Repeat(" ", maxNamespaceLength-len(namespace)))
-			templateString = "%s\t" + templateString
-			templateStringEmpty = "%s\t" + templateStringEmpty
+			prefixTemplate = "%s\t" + prefixTemplate
 		}
 		padding := 0
 		if maxNameLength > len(name) {
@@ -348,6 +586,86 @@ func printSingleStatTable(stats map[string]*row, resourceType string, w *tabwrit
 			name + strings.Repeat(" ", padding),
 			stats[key].meshed,
 		}...)
+		if groupHeader != "" {
+			prefixTemplate += "%s\t"
+		}
+
+		// the resource has a line for each of its groups
+		for _, line := range stats[key].lines(groupHeader != "") {
+			lineValues := append([]interface{}{}, values...)
+			if groupHeader != "" {
+				lineValues = append(lineValues, line.group)
+			}
+
+			if line.rowStats != nil {
+				lineValues = append(lineValues, []interface{}{
+					line.successRate * 100,
+					line.requestRate,
+					line.latencyP50,
+					line.latencyP95,
+					line.latencyP99,
+					line.tlsPercent * 100,
+				}...)
+				if options.outputFormat == "wide" {
+					lineValues = append(lineValues, line.unmeshedRate)
+				}
+				if options.grpc {
+					lineValues = append(lineValues, line.grpcStatuses)
+				}
+
+				fmt.Fprintf(w, prefixTemplate+templateString, append(lineValues, status...)...)
+			} else {
+				fmt.Fprintf(w, prefixTemplate+templateStringEmpty, append(lineValues, status...)...)
+			}
+		}
+	}
+}
+
+// printTrafficSplitTable prints a row for each backend of the traffic splits,
+// in place of the pod columns of the other resources.
+func printTrafficSplitTable(stats map[string]*row, resourceType string, w *tabwriter.Writer, maxNameLength int, maxNamespaceLength int, options *statOptions) {
+	headers := make([]string, 0)
+	if options.allNamespaces {
+		headers = append(headers,
+			namespaceHeader+strings.Repeat(" ", maxNamespaceLength-len(namespaceHeader)))
+	}
+	headers = append(headers, []string{
+		nameHeader + strings.Repeat(" ", maxNameLength-len(nameHeader)),
+		"APEX",
+		"LEAF",
+		"WEIGHT",
+		"SUCCESS",
+		"RPS",
+		"LATENCY_P50",
+		"LATENCY_P95",
+		"LATENCY_P99\t", // trailing \t is required to format last column
+	}...)
+
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	sortedKeys := sortStatsKeys(stats)
+	for _, key := range sortedKeys {
+		namespace, name := namespaceName(resourceType, key)
+		values := make([]interface{}, 0)
+		templateString := "%s\t%s\t%s\t%s\t%.2f%%\t%.1frps\t%dms\t%dms\t%dms\t\n"
+		templateStringEmpty := "%s\t%s\t%s\t%s\t-\t-\t-\t-\t-\t\n"
+
+		if options.allNamespaces {
+			values = append(values,
+				namespace+strings.Repeat(" ", maxNamespaceLength-len(namespace)))
+			templateString = "%s\t" + templateString
+			templateStringEmpty = "%s\t" + templateStringEmpty
+		}
+		padding := 0
+		if maxNameLength > len(name) {
+			padding = maxNameLength - len(name)
+		}
+		values = append(values, name+strings.Repeat(" ", padding))
+		if ts := stats[key].tsStats; ts != nil {
+			values = append(values, ts.apex, ts.leaf, ts.weight)
+		} else {
+			values = append(values, "-", "-", "-")
+		}
 
 		if stats[key].rowStats != nil {
 			values = append(values, []interface{}{
@@ -356,9 +674,7 @@ func printSingleStatTable(stats map[string]*row, resourceType string, w *tabwrit
 				stats[key].latencyP50,
 				stats[key].latencyP95,
 				stats[key].latencyP99,
-				stats[key].tlsPercent * 100,
 			}...)
-
 			fmt.Fprintf(w, templateString, values...)
 		} else {
 			fmt.Fprintf(w, templateStringEmpty, values...)
@@ -376,19 +692,30 @@ func namespaceName(resourceType string, key string) (string, string) {
 
 // Using pointers there where the value is NA and the corresponding json is null
 type jsonStats struct {
-	Namespace    string   `json:"namespace"`
-	Kind         string   `json:"kind"`
-	Name         string   `json:"name"`
-	Meshed       string   `json:"meshed"`
-	Success      *float64 `json:"success"`
-	Rps          *float64 `json:"rps"`
-	LatencyMSp50 *uint64  `json:"latency_ms_p50"`
-	LatencyMSp95 *uint64  `json:"latency_ms_p95"`
-	LatencyMSp99 *uint64  `json:"latency_ms_p99"`
-	TLS          *float64 `json:"tls"`
+	Namespace    string             `json:"namespace"`
+	Kind         string             `json:"kind"`
+	Name         string             `json:"name"`
+	Meshed       string             `json:"meshed"`
+	Success      *float64           `json:"success"`
+	Rps          *float64           `json:"rps"`
+	LatencyMSp50 *uint64            `json:"latency_ms_p50"`
+	LatencyMSp95 *uint64            `json:"latency_ms_p95"`
+	LatencyMSp99 *uint64            `json:"latency_ms_p99"`
+	TLS          *float64           `json:"tls"`
+	UnmeshedRps  *float64           `json:"unmeshed_rps"`
+	Proxy        string             `json:"proxy,omitempty"`
+	Restarts     *uint64            `json:"restarts,omitempty"`
+	SLOSuccess   *float64           `json:"slo_success,omitempty"`
+	SLOViolated  *bool              `json:"slo_violated,omitempty"`
+	GrpcStatuses map[string]float64 `json:"grpc_statuses,omitempty"`
+	Apex         string             `json:"apex,omitempty"`
+	Leaf         string             `json:"leaf,omitempty"`
+	Weight       string             `json:"weight,omitempty"`
+	Authority    string             `json:"authority,omitempty"`
+	Route        string             `json:"route,omitempty"`
 }
 
-func printStatJSON(statTables map[string]map[string]*row, w *tabwriter.Writer) {
+func printStatJSON(statTables map[string]map[string]*row, w *tabwriter.Writer, options *statOptions) {
 	// avoid nil initialization so that if there are not stats it gets marshalled as an empty array vs null
 	entries := []*jsonStats{}
 	for _, resourceType := range k8s.AllResources {
@@ -396,22 +723,51 @@ func printStatJSON(statTables map[string]map[string]*row, w *tabwriter.Writer) {
 			sortedKeys := sortStatsKeys(stats)
 			for _, key := range sortedKeys {
 				namespace, name := namespaceName("", key)
-				entry := &jsonStats{
-					Namespace: namespace,
-					Kind:      resourceType,
-					Name:      name,
-					Meshed:    stats[key].meshed,
+				// the resource has an entry for each of its groups
+				for _, line := range stats[key].lines(options.groupBy() != "") {
+					entry := &jsonStats{
+						Namespace: namespace,
+						Kind:      resourceType,
+						Name:      name,
+						Meshed:    stats[key].meshed,
+					}
+					if line.group != "-" {
+						switch options.groupBy() {
+						case groupByAuthority:
+							entry.Authority = line.group
+						case groupByRoute:
+							entry.Route = line.group
+						}
+					}
+					if resourceType == k8s.Pod {
+						entry.Proxy = stats[key].proxy
+						entry.Restarts = &stats[key].restarts
+					}
+					if ts := stats[key].tsStats; ts != nil {
+						entry.Apex = ts.apex
+						entry.Leaf = ts.leaf
+						entry.Weight = ts.weight
+					}
+					if stats[key].sloSuccessRate > 0 {
+						violated := stats[key].sloViolated()
+						entry.SLOSuccess = &stats[key].sloSuccessRate
+						entry.SLOViolated = &violated
+					}
+					if line.rowStats != nil {
+						entry.Success = &line.successRate
+						entry.Rps = &line.requestRate
+						entry.LatencyMSp50 = &line.latencyP50
+						entry.LatencyMSp95 = &line.latencyP95
+						entry.LatencyMSp99 = &line.latencyP99
+						entry.TLS = &line.tlsPercent
+						entry.UnmeshedRps = &line.unmeshedRate
+						if options.grpc {
+							entry.GrpcStatuses = line.grpcStatusRates
+						}
+					}
+
+					entries = append(entries, entry)
 				}
-				if stats[key].rowStats != nil {
-					entry.Success = &stats[key].successRate
-					entry.Rps = &stats[key].requestRate
-					entry.LatencyMSp50 = &stats[key].latencyP50
-					entry.LatencyMSp95 = &stats[key].latencyP95
-					entry.LatencyMSp99 = &stats[key].latencyP99
-					entry.TLS = &stats[key].tlsPercent
-				}
-
-				entries = append(entries, entry)
 			}
 		}
 	}
@@ -440,18 +796,23 @@ func buildStatSummaryRequests(resources []string, options *statOptions) ([]*pb.S
 
 	var toRes, fromRes pb.Resource
 	if options.toResource != "" {
-		toRes, err = util.BuildResource(options.toNamespace, options.toResource)
+		toRes, err = util.BuildResourcePattern(options.toNamespace, options.toResource)
 		if err != nil {
 			return nil, err
 		}
 	}
 	if options.fromResource != "" {
-		fromRes, err = util.BuildResource(options.fromNamespace, options.fromResource)
+		fromRes, err = util.BuildResourcePattern(options.fromNamespace, options.fromResource)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	metricLabels, err := parseMetricLabels(options.metricLabels)
+	if err != nil {
+		return nil, err
+	}
+
 	requests := make([]*pb.StatSummaryRequest, 0)
 	for _, target := range targets {
 		err = options.validate(target.Type)
@@ -466,6 +827,7 @@ func buildStatSummaryRequests(resources []string, options *statOptions) ([]*pb.S
 				ResourceType:  target.Type,
 				Namespace:     options.namespace,
 				AllNamespaces: options.allNamespaces,
+				GrpcStats:     options.grpc,
 			},
 			ToName:        toRes.Name,
 			ToType:        toRes.Type,
@@ -473,6 +835,8 @@ func buildStatSummaryRequests(resources []string, options *statOptions) ([]*pb.S
 			FromName:      fromRes.Name,
 			FromType:      fromRes.Type,
 			FromNamespace: options.fromNamespace,
+			MetricLabels:  metricLabels,
+			GroupBy:       options.groupBy(),
 		}
 
 		req, err := util.BuildStatSummaryRequest(requestParams)
@@ -484,6 +848,19 @@ func buildStatSummaryRequests(resources []string, options *statOptions) ([]*pb.S
 	return requests, nil
 }
 
+// parseMetricLabels parses the "key=value" labels of the --metric-label flag.
+func parseMetricLabels(labels []string) (map[string]string, error) {
+	metricLabels := map[string]string{}
+	for _, label := range labels {
+		kv := strings.SplitN(label, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid --metric-label \"%s\", expected \"key=value\"", label)
+		}
+		metricLabels[kv[0]] = kv[1]
+	}
+	return metricLabels, nil
+}
+
 func sortStatsKeys(stats map[string]*row) []string {
 	var sortedKeys []string
 	for key := range stats {
@@ -511,6 +888,17 @@ func (o *statOptions) validate(resourceType string) error {
 	return o.validateOutputFormat()
 }
 
+// validateOutputFormat also accepts the wide output, which adds the rate of
+// requests from unmeshed clients to the table.
+func (o *statOptions) validateOutputFormat() error {
+	switch o.outputFormat {
+	case "table", "wide", "json", "":
+		return nil
+	default:
+		return fmt.Errorf("--output currently only supports table, wide, and json")
+	}
+}
+
 // validateConflictingFlags validates that the options do not contain mutually
 // exclusive flags.
 func (o *statOptions) validateConflictingFlags() error {
@@ -522,9 +910,34 @@ func (o *statOptions) validateConflictingFlags() error {
 		return fmt.Errorf("--to-namespace and --from-namespace flags are mutually exclusive")
 	}
 
+	if o.authority && o.routes {
+		return fmt.Errorf("--authority and --routes flags are mutually exclusive")
+	}
+
+	if o.routes && (o.toResource != "" || o.fromResource != "") {
+		return fmt.Errorf("--routes flag is incompatible with the --to and --from flags")
+	}
+
 	return nil
 }
 
+const (
+	groupByAuthority = "authority"
+	groupByRoute     = "route"
+)
+
+// groupBy returns the group_by of the StatSummary requests: what the stats of
+// each resource are broken down by, if anything.
+func (o *statOptions) groupBy() string {
+	switch {
+	case o.authority:
+		return groupByAuthority
+	case o.routes:
+		return groupByRoute
+	}
+	return ""
+}
+
 // validateNamespaceFlags performs additional validation for options when the target
 // resource type is a namespace.
 func (o *statOptions) validateNamespaceFlags() error {
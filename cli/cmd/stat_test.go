@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 )
 
@@ -11,7 +13,11 @@ type paramsExp struct {
 	counts  *public.PodCounts
 	options *statOptions
 	resNs   []string
+	resType string
 	file    string
+
+	// slo is the SLO success rate of the resources, if they have one
+	slo float64
 }
 
 func TestStat(t *testing.T) {
@@ -43,6 +49,20 @@ func TestStat(t *testing.T) {
 		}, t)
 	})
 
+	options.outputFormat = "wide"
+	t.Run("Returns namespace stats with the unmeshed request rate (wide)", func(t *testing.T) {
+		testStatCall(paramsExp{
+			counts: &public.PodCounts{
+				MeshedPods:  1,
+				RunningPods: 2,
+				FailedPods:  0,
+			},
+			options: options,
+			resNs:   []string{"emojivoto1"},
+			file:    "stat_one_output_wide.golden",
+		}, t)
+	})
+
 	options = newStatOptions()
 	options.allNamespaces = true
 	t.Run("Returns all namespace stats", func(t *testing.T) {
@@ -72,6 +92,154 @@ func TestStat(t *testing.T) {
 		}, t)
 	})
 
+	options = newStatOptions()
+	t.Run("Returns pod stats with the proxy status and restarts", func(t *testing.T) {
+		testStatCall(paramsExp{
+			counts: &public.PodCounts{
+				MeshedPods:     1,
+				RunningPods:    1,
+				FailedPods:     0,
+				ProxyReadyPods: 0,
+				Restarts:       3,
+			},
+			options: options,
+			resNs:   []string{"emojivoto1"},
+			resType: k8s.Pod,
+			file:    "stat_pods_output.golden",
+		}, t)
+	})
+
+	options.outputFormat = "json"
+	t.Run("Returns pod stats with the proxy status and restarts (json)", func(t *testing.T) {
+		testStatCall(paramsExp{
+			counts: &public.PodCounts{
+				MeshedPods:     1,
+				RunningPods:    1,
+				FailedPods:     0,
+				ProxyReadyPods: 1,
+				Restarts:       0,
+			},
+			options: options,
+			resNs:   []string{"emojivoto1"},
+			resType: k8s.Pod,
+			file:    "stat_pods_output_json.golden",
+		}, t)
+	})
+
+	options = newStatOptions()
+	t.Run("Returns stats with the SLOs of the resources", func(t *testing.T) {
+		testStatCall(paramsExp{
+			counts: &public.PodCounts{
+				MeshedPods:  1,
+				RunningPods: 1,
+				FailedPods:  0,
+			},
+			options: options,
+			resNs:   []string{"emojivoto1"},
+			resType: k8s.Deployment,
+			file:    "stat_slo_output.golden",
+			slo:     0.995,
+		}, t)
+	})
+
+	t.Run("Returns a row for each backend of traffic splits", func(t *testing.T) {
+		rows := []*pb.StatTable_PodGroup_Row{
+			&pb.StatTable_PodGroup_Row{
+				Resource:   &pb.Resource{Namespace: "emojivoto1", Type: k8s.TrafficSplit, Name: "web-split"},
+				TimeWindow: "1m",
+				Stats: &pb.BasicStats{
+					SuccessCount:    123,
+					LatencyMsP50:    123,
+					LatencyMsP95:    123,
+					LatencyMsP99:    123,
+					TlsRequestCount: 123,
+				},
+				TsStats: &pb.TrafficSplitStats{Apex: "web", Leaf: "web-v1", Weight: "900m"},
+			},
+			&pb.StatTable_PodGroup_Row{
+				Resource:   &pb.Resource{Namespace: "emojivoto1", Type: k8s.TrafficSplit, Name: "web-split"},
+				TimeWindow: "1m",
+				TsStats:    &pb.TrafficSplitStats{Apex: "web", Leaf: "web-v2", Weight: "100m"},
+			},
+		}
+
+		output := renderStatStats(rows, newStatOptions())
+		diffCompareFile(t, output, "stat_ts_output.golden")
+	})
+
+	t.Run("Returns a line for each authority of the resources with --authority", func(t *testing.T) {
+		rows := []*pb.StatTable_PodGroup_Row{
+			&pb.StatTable_PodGroup_Row{
+				Resource:        &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: "web"},
+				TimeWindow:      "1m",
+				MeshedPodCount:  1,
+				RunningPodCount: 1,
+				Stats: &pb.BasicStats{
+					SuccessCount:    174,
+					FailureCount:    6,
+					LatencyMsP50:    10,
+					LatencyMsP95:    30,
+					LatencyMsP99:    50,
+					TlsRequestCount: 180,
+				},
+				GroupStats: []*pb.GroupStats{
+					&pb.GroupStats{
+						Group: "emoji-svc.emojivoto:8080",
+						Stats: &pb.BasicStats{
+							SuccessCount:    120,
+							LatencyMsP50:    5,
+							LatencyMsP95:    10,
+							LatencyMsP99:    15,
+							TlsRequestCount: 120,
+						},
+					},
+					&pb.GroupStats{
+						Group: "voting-svc.emojivoto:8080",
+						Stats: &pb.BasicStats{
+							SuccessCount:    54,
+							FailureCount:    6,
+							LatencyMsP50:    20,
+							LatencyMsP95:    40,
+							LatencyMsP99:    60,
+							TlsRequestCount: 60,
+						},
+					},
+				},
+			},
+			&pb.StatTable_PodGroup_Row{
+				Resource:        &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: "vote-bot"},
+				TimeWindow:      "1m",
+				MeshedPodCount:  1,
+				RunningPodCount: 1,
+			},
+		}
+
+		options := newStatOptions()
+		options.authority = true
+		output := renderStatStats(rows, options)
+		diffCompareFile(t, output, "stat_authority_output.golden")
+	})
+
+	t.Run("Flags the violated SLOs", func(t *testing.T) {
+		testCases := []struct {
+			row      row
+			expected string
+		}{
+			{row{}, "-"},
+			{row{sloSuccessRate: 0.995, rowStats: &rowStats{requestRate: 2, successRate: 0.999}}, "99.50%"},
+			{row{sloSuccessRate: 0.995, rowStats: &rowStats{requestRate: 2, successRate: 0.9}}, sloViolation + " 99.50%"},
+			// without traffic, the SLO can't be violated
+			{row{sloSuccessRate: 0.995, rowStats: &rowStats{}}, "99.50%"},
+			{row{sloSuccessRate: 0.995}, "99.50%"},
+		}
+
+		for i, tc := range testCases {
+			if slo := tc.row.slo(); slo != tc.expected {
+				t.Fatalf("Test case %d: expected %q, got %q", i, tc.expected, slo)
+			}
+		}
+	})
+
 	t.Run("Returns an error for named resource queries with the --all-namespaces flag", func(t *testing.T) {
 		options := newStatOptions()
 		options.allNamespaces = true
@@ -133,16 +301,90 @@ func TestStat(t *testing.T) {
 			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
 		}
 	})
+
+	t.Run("Converts --metric-label flags to metric labels", func(t *testing.T) {
+		options := newStatOptions()
+		options.metricLabels = []string{"app.kubernetes.io/version=v2", "team=emoji"}
+		args := []string{"deploy"}
+		expectedLabels := map[string]string{"app_kubernetes_io_version": "v2", "team": "emoji"}
+
+		reqs, err := buildStatSummaryRequests(args, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(reqs[0].MetricLabels, expectedLabels) {
+			t.Fatalf("Expected metric labels [%v] instead got [%v]", expectedLabels, reqs[0].MetricLabels)
+		}
+	})
+
+	t.Run("Requests the stats broken down by route with --routes", func(t *testing.T) {
+		options := newStatOptions()
+		options.routes = true
+		args := []string{"deploy/web"}
+
+		reqs, err := buildStatSummaryRequests(args, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if reqs[0].GroupBy != "route" {
+			t.Fatalf("Expected group_by [route] instead got [%s]", reqs[0].GroupBy)
+		}
+	})
+
+	t.Run("Rejects commands with both --authority and --routes flags", func(t *testing.T) {
+		options := newStatOptions()
+		options.authority = true
+		options.routes = true
+		args := []string{"deploy"}
+		expectedError := "--authority and --routes flags are mutually exclusive"
+
+		_, err := buildStatSummaryRequests(args, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects --routes flag with the --to flag", func(t *testing.T) {
+		options := newStatOptions()
+		options.routes = true
+		options.toResource = "deploy/voting"
+		args := []string{"deploy"}
+		expectedError := "--routes flag is incompatible with the --to and --from flags"
+
+		_, err := buildStatSummaryRequests(args, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects invalid --metric-label flags", func(t *testing.T) {
+		options := newStatOptions()
+		options.metricLabels = []string{"version"}
+		args := []string{"deploy"}
+		expectedError := "invalid --metric-label \"version\", expected \"key=value\""
+
+		_, err := buildStatSummaryRequests(args, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
 }
 
 func testStatCall(exp paramsExp, t *testing.T) {
 	mockClient := &public.MockAPIClient{}
 
-	response := public.GenStatSummaryResponse("emoji", k8s.Namespace, exp.resNs, exp.counts, true)
+	resType := exp.resType
+	if resType == "" {
+		resType = k8s.Namespace
+	}
+	response := public.GenStatSummaryResponse("emoji", resType, exp.resNs, exp.counts, true)
+	for _, r := range response.GetOk().StatTables[0].GetPodGroup().Rows {
+		r.SloSuccessRate = exp.slo
+	}
 
 	mockClient.StatSummaryResponseToReturn = &response
 
-	args := []string{"ns"}
+	args := []string{resType}
 	reqs, err := buildStatSummaryRequests(args, exp.options)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
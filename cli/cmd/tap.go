@@ -66,7 +66,13 @@ func newCmdTap() *cobra.Command {
   * pods
   * replicationcontrollers
   * services (only supported as a --to resource)
-  * jobs (only supported as a --to resource)`,
+  * jobs (only supported as a --to resource)
+
+  When the control plane serves the tap APIService ("linkerd install
+  --tap-api-service"), the taps go through the Kubernetes API, and require the
+  "watch" verb on the tap subresource of the target in the tap.linkerd.io API
+  group, such as deployments/tap, which the linkerd-<namespace>-tap-admin
+  ClusterRole grants.`,
 		Example: `  # tap the web deployment in the default namespace
   linkerd tap deploy/web
 
@@ -106,7 +112,7 @@ func newCmdTap() *cobra.Command {
 				return fmt.Errorf("output format \"%s\" not recognized", options.output)
 			}
 
-			return requestTapByResourceFromAPI(os.Stdout, cliPublicAPIClient(), req, wide)
+			return requestTapByResourceFromAPI(os.Stdout, cliTapAPIClient(), req, wide)
 		},
 	}
 
@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"text/tabwriter"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
@@ -15,6 +18,7 @@ import (
 	"github.com/linkerd/linkerd2/controller/api/util"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/addr"
+	"github.com/linkerd/linkerd2/pkg/k8s"
 	runewidth "github.com/mattn/go-runewidth"
 	termbox "github.com/nsf/termbox-go"
 	log "github.com/sirupsen/logrus"
@@ -32,6 +36,14 @@ type topOptions struct {
 	path        string
 	hideSources bool
 	routes      bool
+
+	allNamespaces bool
+	maxTaps       uint
+
+	sortBy   string
+	columns  []string
+	once     bool
+	duration time.Duration
 }
 
 type topRequest struct {
@@ -78,8 +90,15 @@ type tableRow struct {
 	last        time.Duration
 	successes   int
 	failures    int
+	// the latencies of the last maxLatencySamples requests, which the p99
+	// latency is computed from
+	latencies []time.Duration
 }
 
+// maxLatencySamples bounds the latencies kept by each row, so that the memory
+// of long sessions doesn't grow with the number of requests.
+const maxLatencySamples = 1000
+
 func (r tableRow) merge(other tableRow) tableRow {
 	r.count += other.count
 	if other.best.Nanoseconds() < r.best.Nanoseconds() {
@@ -91,9 +110,27 @@ func (r tableRow) merge(other tableRow) tableRow {
 	r.last = other.last
 	r.successes += other.successes
 	r.failures += other.failures
+	r.latencies = append(r.latencies, other.latencies...)
+	if len(r.latencies) > maxLatencySamples {
+		r.latencies = r.latencies[len(r.latencies)-maxLatencySamples:]
+	}
 	return r
 }
 
+// p99 returns the 99th percentile latency of the row's requests.
+func (r tableRow) p99() time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration{}, r.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[(len(sorted)*99+99)/100-1]
+}
+
+func (r tableRow) successRate() float32 {
+	return float32(r.successes) / float32(r.successes+r.failures)
+}
+
 type column int
 
 const (
@@ -103,21 +140,46 @@ const (
 	pathColumn
 	routeColumn
 	countColumn
+	rpsColumn
 	bestColumn
 	worstColumn
 	lastColumn
+	p99Column
 	successRateColumn
 
 	columnCount
 )
 
+// topColumnNames are the names of the columns, in their order, that --columns
+// selects them by.
+var topColumnNames = [columnCount]string{
+	"source", "destination", "method", "path", "route", "count", "rps", "best", "worst", "last", "p99", "success-rate",
+}
+
+// topSortOrders are the orders of the rows that --sort-by selects, by their
+// name. The busiest rows come first by default.
+var topSortOrders = map[string]func(t *topTable, i, j int) bool{
+	"rps": func(t *topTable, i, j int) bool { return t.rows[i].count > t.rows[j].count },
+	// the slowest rows come first
+	"latency": func(t *topTable, i, j int) bool { return t.rows[i].p99() > t.rows[j].p99() },
+	// the least successful rows come first
+	"success": func(t *topTable, i, j int) bool { return t.rows[i].successRate() < t.rows[j].successRate() },
+}
+
 type topTable struct {
 	columns [columnCount]tableColumn
 	rows    []tableRow
+	sortBy  string
+	// start is when the table started receiving requests, which the request
+	// rates are computed since
+	start time.Time
 }
 
 func newTopTable() *topTable {
-	table := topTable{}
+	table := topTable{
+		sortBy: "rps",
+		start:  time.Now(),
+	}
 
 	table.columns[sourceColumn] =
 		tableColumn{
@@ -192,6 +254,19 @@ func newTopTable() *topTable {
 			},
 		}
 
+	table.columns[rpsColumn] =
+		tableColumn{
+			header:     "RPS",
+			width:      6,
+			key:        false,
+			display:    false,
+			flexible:   false,
+			rightAlign: true,
+			value: func(r tableRow) string {
+				return fmt.Sprintf("%.1f", table.rps(r))
+			},
+		}
+
 	table.columns[bestColumn] =
 		tableColumn{
 			header:     "Best",
@@ -231,6 +306,19 @@ func newTopTable() *topTable {
 			},
 		}
 
+	table.columns[p99Column] =
+		tableColumn{
+			header:     "P99",
+			width:      6,
+			key:        false,
+			display:    false,
+			flexible:   false,
+			rightAlign: true,
+			value: func(r tableRow) string {
+				return formatDuration(r.p99())
+			},
+		}
+
 	table.columns[successRateColumn] =
 		tableColumn{
 			header:     "Success Rate",
@@ -240,13 +328,79 @@ func newTopTable() *topTable {
 			flexible:   false,
 			rightAlign: true,
 			value: func(r tableRow) string {
-				return fmt.Sprintf("%.2f%%", 100.0*float32(r.successes)/float32(r.successes+r.failures))
+				return fmt.Sprintf("%.2f%%", 100.0*r.successRate())
 			},
 		}
 
 	return &table
 }
 
+// rps returns the request rate of the row since the table started.
+func (t *topTable) rps(r tableRow) float64 {
+	elapsed := time.Since(t.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(r.count) / elapsed
+}
+
+// selectColumns displays the named columns only. The rows are keyed by the
+// displayed columns of sources, destinations, methods, paths and routes, so
+// that the rows that only differ in the hidden ones are merged.
+func (t *topTable) selectColumns(names []string) error {
+	selected := map[string]bool{}
+	for _, name := range names {
+		found := false
+		for _, columnName := range topColumnNames {
+			if name == columnName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("invalid column \"%s\", must be one of: %s", name, strings.Join(topColumnNames[:], ", "))
+		}
+		selected[name] = true
+	}
+
+	for i := range t.columns {
+		t.columns[i].display = selected[topColumnNames[i]]
+		if column(i) <= routeColumn {
+			t.columns[i].key = t.columns[i].display
+		}
+	}
+	return nil
+}
+
+func (t *topTable) sortRows() {
+	less := topSortOrders[t.sortBy]
+	sort.SliceStable(t.rows, func(i, j int) bool { return less(t, i, j) })
+}
+
+// write writes a snapshot of the table to w, for --once.
+func (t *topTable) write(w io.Writer) error {
+	t.sortRows()
+
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', 0)
+	headers := []string{}
+	for _, col := range t.columns {
+		if col.display {
+			headers = append(headers, strings.ToUpper(col.header))
+		}
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range t.rows {
+		values := []string{}
+		for _, col := range t.columns {
+			if col.display {
+				values = append(values, col.value(row))
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
+
 const (
 	headerHeight  = 3
 	columnSpacing = 2
@@ -264,16 +418,46 @@ func newTopOptions() *topOptions {
 		path:        "",
 		hideSources: false,
 		routes:      false,
+
+		allNamespaces: false,
+		maxTaps:       10,
+
+		sortBy:   "rps",
+		columns:  []string{},
+		once:     false,
+		duration: 10 * time.Second,
 	}
 }
 
+func (o *topOptions) validate() error {
+	if o.maxTaps == 0 {
+		return errors.New("--max-taps must be greater than 0")
+	}
+	if _, ok := topSortOrders[o.sortBy]; !ok {
+		return fmt.Errorf("--sort-by must be one of: %s", strings.Join(topSortOrderNames(), ", "))
+	}
+	if o.once && o.duration <= 0 {
+		return errors.New("--duration must be greater than 0")
+	}
+	return nil
+}
+
+func topSortOrderNames() []string {
+	names := make([]string, 0, len(topSortOrders))
+	for name := range topSortOrders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func newCmdTop() *cobra.Command {
 	options := newTopOptions()
 
 	table := newTopTable()
 
 	cmd := &cobra.Command{
-		Use:   "top [flags] (RESOURCE)",
+		Use:   "top [flags] [RESOURCE]",
 		Short: "Display sorted information about live traffic",
 		Long: `Display sorted information about live traffic.
 
@@ -292,26 +476,51 @@ func newCmdTop() *cobra.Command {
   * pods
   * replicationcontrollers
   * services (only supported as a --to resource)
-  * jobs (only supported as a --to resource)`,
+  * jobs (only supported as a --to resource)
+
+  A namespace is tapped through each of its meshed deployments, and with
+  --all-namespaces, each meshed resource of the given type (namespaces by
+  default) is tapped across the cluster. Each tapped resource gets its own
+  --max-rps budget, and only the --max-taps busiest resources are tapped.
+
+  The rows are sorted by --sort-by: the busiest first by default, the slowest
+  first with "latency", which sorts them by their p99 latency over their last
+  requests, and the least successful first with "success". --columns selects
+  the columns to display, and the rows are merged across the hidden source,
+  destination, method, path and route columns.
+
+  With --once, the traffic is tapped for --duration, and the table is printed
+  to stdout instead of being displayed interactively, for scripts.`,
 		Example: `  # display traffic for the web deployment in the default namespace
   linkerd top deploy/web
 
   # display traffic for the web-dlbvj pod in the default namespace
-  linkerd top pod/web-dlbvj`,
-		Args:      cobra.RangeArgs(1, 2),
+  linkerd top pod/web-dlbvj
+
+  # display traffic for each deployment in the emojivoto namespace
+  linkerd top ns/emojivoto
+
+  # display traffic for each namespace in the cluster
+  linkerd top --all-namespaces
+
+  # display traffic for the 20 busiest deployments in the cluster
+  linkerd top deploy --all-namespaces --max-taps 20
+
+  # display the slowest routes to the web deployment, with their request rate and p99 latency
+  linkerd top deploy/web --sort-by latency --columns destination,route,rps,p99,success-rate
+
+  # print the traffic of the emojivoto namespace over 30 seconds, and exit
+  linkerd top ns/emojivoto --once --duration 30s`,
+		Args:      cobra.RangeArgs(0, 2),
 		ValidArgs: util.ValidTargets,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			requestParams := util.TapRequestParams{
-				Resource:    strings.Join(args, "/"),
-				Namespace:   options.namespace,
-				ToResource:  options.toResource,
-				ToNamespace: options.toNamespace,
-				MaxRps:      options.maxRps,
-				Scheme:      options.scheme,
-				Method:      options.method,
-				Authority:   options.authority,
-				Path:        options.path,
+			if len(args) == 0 && !options.allNamespaces {
+				return errors.New("please specify a resource, or use --all-namespaces")
+			}
+			if err := options.validate(); err != nil {
+				return err
 			}
+			table.sortBy = options.sortBy
 
 			if options.hideSources {
 				table.columns[sourceColumn].key = false
@@ -327,12 +536,19 @@ func newCmdTop() *cobra.Command {
 				table.columns[routeColumn].display = true
 			}
 
-			req, err := util.BuildTapByResourceRequest(requestParams)
+			if len(options.columns) != 0 {
+				if err := table.selectColumns(options.columns); err != nil {
+					return err
+				}
+			}
+
+			client := cliTapAPIClient()
+			reqs, err := buildTopRequests(client, strings.Join(args, "/"), options)
 			if err != nil {
 				return err
 			}
 
-			return getTrafficByResourceFromAPI(os.Stdout, cliPublicAPIClient(), req, table)
+			return getTrafficByResourceFromAPI(os.Stdout, client, reqs, table, options)
 		},
 	}
 
@@ -354,45 +570,194 @@ func newCmdTop() *cobra.Command {
 		"Display requests with paths that start with this prefix")
 	cmd.PersistentFlags().BoolVar(&options.hideSources, "hide-sources", options.hideSources, "Hide the source column")
 	cmd.PersistentFlags().BoolVar(&options.routes, "routes", options.routes, "Display data per route instead of per path")
+	cmd.PersistentFlags().BoolVar(&options.allNamespaces, "all-namespaces", options.allNamespaces,
+		"If present, displays traffic across all namespaces, ignoring the \"--namespace\" flag")
+	cmd.PersistentFlags().UintVar(&options.maxTaps, "max-taps", options.maxTaps,
+		"Maximum number of resources to tap at once when tapping a namespace or all namespaces; the busiest resources are tapped first")
+	cmd.PersistentFlags().StringVar(&options.sortBy, "sort-by", options.sortBy,
+		fmt.Sprintf("Order of the rows (one of: %s)", strings.Join(topSortOrderNames(), ", ")))
+	cmd.PersistentFlags().StringSliceVar(&options.columns, "columns", options.columns,
+		fmt.Sprintf("Columns to display, overriding --hide-sources and --routes (any of: %s)", strings.Join(topColumnNames[:], ", ")))
+	cmd.PersistentFlags().BoolVar(&options.once, "once", options.once,
+		"Print the traffic tapped for --duration and exit, instead of displaying it interactively")
+	cmd.PersistentFlags().DurationVar(&options.duration, "duration", options.duration,
+		"How long to tap the traffic for with --once")
 
 	return cmd
 }
 
-func getTrafficByResourceFromAPI(w io.Writer, client pb.ApiClient, req *pb.TapByResourceRequest, table *topTable) error {
-	rsp, err := client.TapByResource(context.Background(), req)
+// buildTopRequests returns a tap request for each of the resources returned
+// by topTargets.
+func buildTopRequests(client pb.ApiClient, resource string, options *topOptions) ([]*pb.TapByResourceRequest, error) {
+	targets, err := topTargets(client, resource, options)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = termbox.Init()
+	reqs := []*pb.TapByResourceRequest{}
+	for _, target := range targets {
+		req, err := util.BuildTapByResourceRequest(util.TapRequestParams{
+			Resource:    fmt.Sprintf("%s/%s", target.Type, target.Name),
+			Namespace:   target.Namespace,
+			ToResource:  options.toResource,
+			ToNamespace: options.toNamespace,
+			MaxRps:      options.maxRps,
+			Scheme:      options.scheme,
+			Method:      options.method,
+			Authority:   options.authority,
+			Path:        options.path,
+		})
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+// topTargets returns the resources to tap. A namespace is tapped through each
+// of its meshed deployments, and with --all-namespaces, each meshed resource
+// of the given type (namespaces by default) is tapped, so that busy resources
+// don't use up the --max-rps budget of the others. Only the --max-taps
+// resources with the highest request rates are tapped. Any other resource is
+// tapped on its own.
+func topTargets(client pb.ApiClient, resource string, options *topOptions) ([]*pb.Resource, error) {
+	params := util.StatsSummaryRequestParams{
+		StatsBaseRequestParams: util.StatsBaseRequestParams{
+			TimeWindow: "1m",
+		},
+	}
+
+	if options.allNamespaces {
+		if resource == "" {
+			resource = k8s.Namespace
+		}
+		target, err := util.BuildResource("", resource)
+		if err != nil {
+			return nil, fmt.Errorf("target resource invalid: %s", err)
+		}
+		if target.Name != "" {
+			return nil, errors.New("a resource cannot be tapped by name across all namespaces")
+		}
+		params.ResourceType = target.Type
+		params.AllNamespaces = true
+	} else {
+		target, err := util.BuildResource(options.namespace, resource)
+		if err != nil {
+			return nil, fmt.Errorf("target resource invalid: %s", err)
+		}
+		if target.Type != k8s.Namespace || target.Name == "" {
+			return []*pb.Resource{&target}, nil
+		}
+		params.ResourceType = k8s.Deployment
+		params.Namespace = target.Name
+	}
+
+	req, err := util.BuildStatSummaryRequest(params)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := public.StatSummaryAll(context.Background(), client, req)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("StatSummary API error: %v", err)
+	}
+	if e := rsp.GetError(); e != nil {
+		return nil, fmt.Errorf("StatSummary API response error: %v", e.Error)
+	}
+
+	rows := []*pb.StatTable_PodGroup_Row{}
+	for _, table := range rsp.GetOk().GetStatTables() {
+		for _, row := range table.GetPodGroup().GetRows() {
+			if row.GetMeshedPodCount() > 0 {
+				rows = append(rows, row)
+			}
+		}
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no meshed %s resources found to tap", params.ResourceType)
+	}
+
+	requests := func(row *pb.StatTable_PodGroup_Row) uint64 {
+		return row.GetStats().GetSuccessCount() + row.GetStats().GetFailureCount()
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return requests(rows[i]) > requests(rows[j])
+	})
+	if uint(len(rows)) > options.maxTaps {
+		rows = rows[:options.maxTaps]
+	}
+
+	targets := []*pb.Resource{}
+	for _, row := range rows {
+		targets = append(targets, row.GetResource())
+	}
+	return targets, nil
+}
+
+func getTrafficByResourceFromAPI(w io.Writer, client pb.ApiClient, reqs []*pb.TapByResourceRequest, table *topTable, options *topOptions) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	streams := []pb.Api_TapByResourceClient{}
+	for _, req := range reqs {
+		rsp, err := client.TapByResource(ctx, req)
+		if err != nil {
+			return err
+		}
+		streams = append(streams, rsp)
+	}
+
+	if !options.once {
+		err := termbox.Init()
+		if err != nil {
+			return err
+		}
+		defer termbox.Close()
 	}
-	defer termbox.Close()
 
 	requestCh := make(chan topRequest, 100)
 	done := make(chan struct{})
+	var closeDone sync.Once
+	stop := func() { closeDone.Do(func() { close(done) }) }
+
+	// the events of all the streams are aggregated in the same table, until
+	// all of the streams terminate
+	var wg sync.WaitGroup
+	for _, stream := range streams {
+		wg.Add(1)
+		go func(stream pb.Api_TapByResourceClient) {
+			defer wg.Done()
+			recvEvents(stream, requestCh, done)
+		}(stream)
+	}
+	go func() {
+		wg.Wait()
+		stop()
+	}()
+
+	table.start = time.Now()
+	if options.once {
+		collectTable(table, requestCh, done, time.After(options.duration))
+		stop()
+		return table.write(w)
+	}
 
-	go recvEvents(rsp, requestCh, done)
-	go pollInput(done)
-
+	go pollInput(stop)
 	renderTable(table, requestCh, done)
 
 	return nil
 }
 
-func recvEvents(tapClient pb.Api_TapByResourceClient, requestCh chan<- topRequest, done chan<- struct{}) {
+func recvEvents(tapClient pb.Api_TapByResourceClient, requestCh chan<- topRequest, done <-chan struct{}) {
 	outstandingRequests := make(map[topRequestID]topRequest)
 	for {
 		event, err := tapClient.Recv()
 		if err == io.EOF {
 			fmt.Println("Tap stream terminated")
-			close(done)
 			return
 		}
 		if err != nil {
 			fmt.Println(err.Error())
-			close(done)
 			return
 		}
 		id := topRequestID{
@@ -419,8 +784,13 @@ func recvEvents(tapClient pb.Api_TapByResourceClient, requestCh chan<- topReques
 		case *pb.TapEvent_Http_ResponseEnd_:
 			id.stream = ev.ResponseEnd.GetId().Stream
 			if req, ok := outstandingRequests[id]; ok {
+				delete(outstandingRequests, id)
 				req.rspEnd = ev.ResponseEnd
-				requestCh <- req
+				select {
+				case requestCh <- req:
+				case <-done:
+					return
+				}
 			} else {
 				log.Warnf("Got ResponseEnd for unknown stream: %s", id)
 			}
@@ -428,12 +798,12 @@ func recvEvents(tapClient pb.Api_TapByResourceClient, requestCh chan<- topReques
 	}
 }
 
-func pollInput(done chan<- struct{}) {
+func pollInput(stop func()) {
 	for {
 		switch ev := termbox.PollEvent(); ev.Type {
 		case termbox.EventKey:
 			if ev.Ch == 'q' || ev.Key == termbox.KeyCtrlC {
-				close(done)
+				stop()
 				return
 			}
 		}
@@ -459,6 +829,21 @@ func renderTable(table *topTable, requestCh <-chan topRequest, done <-chan struc
 	}
 }
 
+// collectTable inserts the requests in the table until timeout, or until all
+// of the streams terminate.
+func collectTable(table *topTable, requestCh <-chan topRequest, done <-chan struct{}, timeout <-chan time.Time) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-timeout:
+			return
+		case req := <-requestCh:
+			table.insert(req)
+		}
+	}
+}
+
 func newRow(req topRequest) (tableRow, error) {
 	path := req.reqInit.GetPath()
 	route := req.event.GetRouteMeta().GetLabels()["route"]
@@ -512,6 +897,7 @@ func newRow(req topRequest) (tableRow, error) {
 		count:       1,
 		successes:   successes,
 		failures:    failures,
+		latencies:   []time.Duration{latency},
 	}, nil
 }
 
@@ -521,7 +907,10 @@ func (t *topTable) insert(req topRequest) {
 		log.Error(err.Error())
 		return
 	}
+	t.insertRow(insert)
+}
 
+func (t *topTable) insertRow(insert tableRow) {
 	found := false
 	// Search for a matching row
 	for i, row := range t.rows {
@@ -589,9 +978,7 @@ func (t *topTable) adjustColumnWidths() {
 }
 
 func (t *topTable) renderBody() {
-	sort.SliceStable(t.rows, func(i, j int) bool {
-		return t.rows[i].count > t.rows[j].count
-	})
+	t.sortRows()
 
 	for i, row := range t.rows {
 		x := 0
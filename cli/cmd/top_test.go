@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+func genTopStatSummaryResponse(resources []*pb.Resource, requests []uint64, meshed []uint64) *pb.StatSummaryResponse {
+	rows := []*pb.StatTable_PodGroup_Row{}
+	for i, resource := range resources {
+		rows = append(rows, &pb.StatTable_PodGroup_Row{
+			Resource:       resource,
+			MeshedPodCount: meshed[i],
+			Stats:          &pb.BasicStats{SuccessCount: requests[i]},
+		})
+	}
+
+	return &pb.StatSummaryResponse{
+		Response: &pb.StatSummaryResponse_Ok_{
+			Ok: &pb.StatSummaryResponse_Ok{
+				StatTables: []*pb.StatTable{
+					{
+						Table: &pb.StatTable_PodGroup_{
+							PodGroup: &pb.StatTable_PodGroup{Rows: rows},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestTopTargets(t *testing.T) {
+	web := &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: "web"}
+	emoji := &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: "emoji"}
+	voting := &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: "voting"}
+	vote := &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: "vote-bot"}
+
+	client := &public.MockAPIClient{
+		StatSummaryResponseToReturn: genTopStatSummaryResponse(
+			[]*pb.Resource{web, emoji, voting, vote},
+			[]uint64{10, 30, 20, 40},
+			[]uint64{1, 1, 1, 0},
+		),
+	}
+
+	t.Run("Taps the meshed deployments of a namespace, busiest first", func(t *testing.T) {
+		options := newTopOptions()
+
+		targets, err := topTargets(client, "ns/emojivoto", options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := []*pb.Resource{emoji, voting, web}
+		if !reflect.DeepEqual(targets, expected) {
+			t.Fatalf("Expected targets %v, got %v", expected, targets)
+		}
+	})
+
+	t.Run("Taps at most --max-taps resources", func(t *testing.T) {
+		options := newTopOptions()
+		options.maxTaps = 2
+		options.allNamespaces = true
+
+		targets, err := topTargets(client, "deploy", options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := []*pb.Resource{emoji, voting}
+		if !reflect.DeepEqual(targets, expected) {
+			t.Fatalf("Expected targets %v, got %v", expected, targets)
+		}
+	})
+
+	t.Run("Taps other resources on their own", func(t *testing.T) {
+		options := newTopOptions()
+		options.namespace = "emojivoto"
+
+		targets, err := topTargets(client, "deploy/web", options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := []*pb.Resource{web}
+		if !reflect.DeepEqual(targets, expected) {
+			t.Fatalf("Expected targets %v, got %v", expected, targets)
+		}
+	})
+
+	t.Run("Rejects resource names across all namespaces", func(t *testing.T) {
+		options := newTopOptions()
+		options.allNamespaces = true
+
+		if _, err := topTargets(client, "deploy/web", options); err == nil {
+			t.Fatal("Expected an error, got none")
+		}
+	})
+
+	t.Run("Returns an error when there's nothing to tap", func(t *testing.T) {
+		options := newTopOptions()
+		options.allNamespaces = true
+		client := &public.MockAPIClient{
+			StatSummaryResponseToReturn: genTopStatSummaryResponse(nil, nil, nil),
+		}
+
+		_, err := topTargets(client, "", options)
+		expected := "no meshed namespace resources found to tap"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q, got %v", expected, err)
+		}
+	})
+}
+
+func TestTopTable(t *testing.T) {
+	genTable := func() *topTable {
+		table := newTopTable()
+		table.start = time.Now().Add(-10 * time.Second)
+		table.rows = []tableRow{
+			{source: "web", destination: "emoji", method: "POST", path: "/list", count: 10, successes: 10, latencies: []time.Duration{time.Millisecond}},
+			{source: "web", destination: "voting", method: "POST", path: "/vote", count: 30, successes: 27, failures: 3, latencies: []time.Duration{2 * time.Millisecond}},
+			{source: "vote-bot", destination: "web", method: "GET", path: "/api/vote", count: 20, successes: 10, failures: 10, latencies: []time.Duration{5 * time.Millisecond, 50 * time.Millisecond}},
+		}
+		return table
+	}
+	destinations := func(table *topTable) []string {
+		names := []string{}
+		for _, row := range table.rows {
+			names = append(names, row.destination)
+		}
+		return names
+	}
+
+	testCases := []struct {
+		sortBy   string
+		expected []string
+	}{
+		{"rps", []string{"voting", "web", "emoji"}},
+		{"latency", []string{"web", "voting", "emoji"}},
+		{"success", []string{"web", "voting", "emoji"}},
+	}
+	for _, tc := range testCases {
+		t.Run("Sorts the rows by "+tc.sortBy, func(t *testing.T) {
+			table := genTable()
+			table.sortBy = tc.sortBy
+			table.sortRows()
+			if actual := destinations(table); !reflect.DeepEqual(actual, tc.expected) {
+				t.Fatalf("Expected rows %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+
+	t.Run("Merges the rows across the hidden key columns", func(t *testing.T) {
+		table := newTopTable()
+		if err := table.selectColumns([]string{"destination", "count"}); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		for _, row := range genTable().rows {
+			row.destination = "web"
+			table.insertRow(row)
+		}
+		if len(table.rows) != 1 || table.rows[0].count != 60 {
+			t.Fatalf("Expected a single row of 60 requests, got %+v", table.rows)
+		}
+	})
+
+	t.Run("Rejects invalid columns", func(t *testing.T) {
+		expected := "invalid column \"latency\", must be one of: source, destination, method, path, route, count, rps, best, worst, last, p99, success-rate"
+		if err := newTopTable().selectColumns([]string{"latency"}); err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q, got %v", expected, err)
+		}
+	})
+
+	t.Run("Writes a snapshot of the table", func(t *testing.T) {
+		table := genTable()
+		table.sortBy = "latency"
+		if err := table.selectColumns([]string{"destination", "path", "count", "p99", "success-rate"}); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		buffer := &bytes.Buffer{}
+		if err := table.write(buffer); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := `DESTINATION   PATH        COUNT   P99    SUCCESS RATE
+web           /api/vote   20      50ms   50.00%
+voting        /vote       30      2ms    90.00%
+emoji         /list       10      1ms    100.00%
+`
+		if buffer.String() != expected {
+			t.Fatalf("Expected:\n%s\nGot:\n%s", expected, buffer.String())
+		}
+	})
+}
+
+func TestTopOptionsValidate(t *testing.T) {
+	options := newTopOptions()
+	options.sortBy = "count"
+	expected := "--sort-by must be one of: latency, rps, success"
+	if err := options.validate(); err == nil || err.Error() != expected {
+		t.Fatalf("Expected error %q, got %v", expected, err)
+	}
+
+	options = newTopOptions()
+	options.once = true
+	options.duration = 0
+	expected = "--duration must be greater than 0"
+	if err := options.validate(); err == nil || err.Error() != expected {
+		t.Fatalf("Expected error %q, got %v", expected, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/ghodss/yaml"
@@ -13,17 +14,76 @@ import (
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-type resourceTransformerUninject struct{}
+// uninjectContainers are the containers that can be selected with --only, by
+// the names they're selected by.
+var uninjectContainers = map[string]string{
+	"proxy":      k8s.ProxyContainerName,
+	"proxy-init": k8s.InitContainerName,
+	"debug":      k8s.DebugContainerName,
+}
+
+type uninjectOptions struct {
+	keepAnnotations bool
+	only            []string
+}
+
+type resourceTransformerUninject struct {
+	// options are nil for the default options, which remove the whole proxy
+	options *uninjectOptions
+}
 
 type resourceTransformerUninjectSilent struct{}
 
+func newUninjectOptions() *uninjectOptions {
+	return &uninjectOptions{
+		keepAnnotations: false,
+		only:            []string{},
+	}
+}
+
+func (options *uninjectOptions) validate() error {
+	for _, name := range options.only {
+		if _, ok := uninjectContainers[name]; !ok {
+			return fmt.Errorf("Invalid --only value \"%s\", must be one of: %s", name, strings.Join(uninjectContainerNames(), ", "))
+		}
+	}
+	// the traffic of the pods would still be redirected to the removed proxy
+	if options.removes(k8s.ProxyContainerName) && !options.removes(k8s.InitContainerName) {
+		return fmt.Errorf("--only proxy requires proxy-init, without which the traffic of the pods is redirected to the removed proxy")
+	}
+	return nil
+}
+
+// removes returns true if the container is uninjected: all the Linkerd
+// containers are, unless --only selects some of them.
+func (options *uninjectOptions) removes(container string) bool {
+	if options == nil || len(options.only) == 0 {
+		return true
+	}
+	for _, name := range options.only {
+		if uninjectContainers[name] == container {
+			return true
+		}
+	}
+	return false
+}
+
+func uninjectContainerNames() []string {
+	names := make([]string, 0, len(uninjectContainers))
+	for name := range uninjectContainers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // UninjectYAML processes resource definitions and outputs them after uninjection in out
 func UninjectYAML(in io.Reader, out io.Writer, report io.Writer, options *injectOptions) error {
 	return ProcessYAML(in, out, report, options, resourceTransformerUninject{})
 }
 
-func runUninjectCmd(inputs []io.Reader, errWriter, outWriter io.Writer, options *injectOptions) int {
-	return transformInput(inputs, errWriter, outWriter, options, resourceTransformerUninject{})
+func runUninjectCmd(inputs []io.Reader, errWriter, outWriter io.Writer, options *uninjectOptions) int {
+	return transformInput(inputs, errWriter, outWriter, nil, resourceTransformerUninject{options: options})
 }
 
 func runUninjectSilentCmd(inputs []io.Reader, errWriter, outWriter io.Writer, options *injectOptions) int {
@@ -31,13 +91,23 @@ func runUninjectSilentCmd(inputs []io.Reader, errWriter, outWriter io.Writer, op
 }
 
 func newCmdUninject() *cobra.Command {
+	options := newUninjectOptions()
+
 	cmd := &cobra.Command{
 		Use:   "uninject [flags] CONFIG-FILE",
 		Short: "Remove the Linkerd proxy from a Kubernetes config",
 		Long: `Remove the Linkerd proxy from a Kubernetes config.
 
 You can uninject resources contained in a single file, inside a folder and its
-sub-folders, or coming from stdin.`,
+sub-folders, or coming from stdin. All the workloads that can be injected can be
+uninjected, including CronJobs and Argo Rollouts.
+
+The Linkerd annotations and labels of the pod templates are removed along with
+the proxy, unless --keep-annotations is set; the config.linkerd.io annotations
+and the linkerd.io/auto-inject label are always kept, so that the proxy
+injector can inject the workloads again. --only removes some of the Linkerd
+containers and leaves the others, such as the debug container with
+--only debug.`,
 		Example: `  # Uninject all the deployments in the default namespace.
   kubectl get deploy -o yaml | linkerd uninject - | kubectl apply -f -
 
@@ -45,24 +115,36 @@ sub-folders, or coming from stdin.`,
   curl http://url.to/yml | linkerd uninject - | kubectl apply -f -
 
   # Uninject all the resources inside a folder and its sub-folders.
-  linkerd uninject <folder> | kubectl apply -f -`,
+  linkerd uninject <folder> | kubectl apply -f -
+
+  # Remove the debug container of the deployments, and keep their proxy.
+  kubectl get deploy -o yaml | linkerd uninject --only debug - | kubectl apply -f -`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 
 			if len(args) < 1 {
 				return fmt.Errorf("please specify a kubernetes resource file")
 			}
 
+			if err := options.validate(); err != nil {
+				return err
+			}
+
 			in, err := read(args[0])
 			if err != nil {
 				return err
 			}
 
-			exitCode := runUninjectCmd(in, os.Stderr, os.Stdout, nil)
+			exitCode := runUninjectCmd(in, os.Stderr, os.Stdout, options)
 			os.Exit(exitCode)
 			return nil
 		},
 	}
 
+	cmd.PersistentFlags().BoolVar(&options.keepAnnotations, "keep-annotations", options.keepAnnotations,
+		"Keep the Linkerd annotations of the pod templates, such as linkerd.io/proxy-version")
+	cmd.PersistentFlags().StringSliceVar(&options.only, "only", options.only,
+		fmt.Sprintf("Only remove these Linkerd containers (any of: %s); the proxy volumes and labels are removed with the proxy", strings.Join(uninjectContainerNames(), ", ")))
+
 	return cmd
 }
 
@@ -83,8 +165,8 @@ func (rt resourceTransformerUninject) transform(bytes []byte, options *injectOpt
 	// serialization of the modified object.
 	output = bytes
 	if conf.podSpec != nil {
-		uninjectPodSpec(conf.podSpec, &report)
-		uninjectObjectMeta(conf.objectMeta)
+		uninjectPodSpec(conf.podSpec, rt.options, &report)
+		uninjectObjectMeta(conf.objectMeta, rt.options)
 		var err error
 		output, err = yaml.Marshal(conf.obj)
 		if err != nil {
@@ -121,26 +203,33 @@ func (resourceTransformerUninjectSilent) generateReport(uninjectReports []inject
 }
 
 // Given a PodSpec, update the PodSpec in place with the sidecar
-// and init-container uninjected
-func uninjectPodSpec(t *v1.PodSpec, report *injectReport) {
+// and init-container uninjected, or only with the containers selected by the
+// options
+func uninjectPodSpec(t *v1.PodSpec, options *uninjectOptions, report *injectReport) {
 	initContainers := []v1.Container{}
 	for _, container := range t.InitContainers {
-		if container.Name != k8s.InitContainerName {
-			initContainers = append(initContainers, container)
-		} else {
+		if container.Name == k8s.InitContainerName && options.removes(container.Name) {
 			report.sidecar = true
+		} else {
+			initContainers = append(initContainers, container)
 		}
 	}
 	t.InitContainers = initContainers
 
 	containers := []v1.Container{}
 	for _, container := range t.Containers {
-		if container.Name != k8s.ProxyContainerName {
+		if (container.Name == k8s.ProxyContainerName || container.Name == k8s.DebugContainerName) && options.removes(container.Name) {
+			report.sidecar = true
+		} else {
 			containers = append(containers, container)
 		}
 	}
 	t.Containers = containers
 
+	// the volumes are mounted by the proxy
+	if !options.removes(k8s.ProxyContainerName) {
+		return
+	}
 	volumes := []v1.Volume{}
 	for _, volume := range t.Volumes {
 		// TODO: move those strings to constants
@@ -151,14 +240,23 @@ func uninjectPodSpec(t *v1.PodSpec, report *injectReport) {
 	t.Volumes = volumes
 }
 
-func uninjectObjectMeta(t *metaV1.ObjectMeta) {
-	newAnnotations := make(map[string]string)
-	for key, val := range t.Annotations {
-		if key != k8s.CreatedByAnnotation && key != k8s.ProxyVersionAnnotation {
-			newAnnotations[key] = val
+// uninjectObjectMeta removes the annotations and labels that describe the
+// proxy, which are kept if it isn't removed. The annotations are also kept
+// with --keep-annotations.
+func uninjectObjectMeta(t *metaV1.ObjectMeta, options *uninjectOptions) {
+	if !options.removes(k8s.ProxyContainerName) {
+		return
+	}
+
+	if options == nil || !options.keepAnnotations {
+		newAnnotations := make(map[string]string)
+		for key, val := range t.Annotations {
+			if key != k8s.CreatedByAnnotation && key != k8s.ProxyVersionAnnotation {
+				newAnnotations[key] = val
+			}
 		}
+		t.Annotations = newAnnotations
 	}
-	t.Annotations = newAnnotations
 
 	labels := make(map[string]string)
 	for key, val := range t.Labels {
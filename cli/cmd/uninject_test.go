@@ -44,6 +44,16 @@ func TestUninjectYAML(t *testing.T) {
 			goldenFileName: "inject_emojivoto_statefulset.input.yml",
 			reportFileName: "inject_emojivoto_statefulset_uninject.report",
 		},
+		{
+			inputFileName:  "inject_emojivoto_cronjob.golden.yml",
+			goldenFileName: "inject_emojivoto_cronjob.input.yml",
+			reportFileName: "inject_emojivoto_cronjob_uninject.report",
+		},
+		{
+			inputFileName:  "inject_emojivoto_rollout.golden.yml",
+			goldenFileName: "inject_emojivoto_rollout.input.yml",
+			reportFileName: "inject_emojivoto_rollout_uninject.report",
+		},
 		{
 			inputFileName:  "inject_emojivoto_pod.golden.yml",
 			goldenFileName: "inject_emojivoto_pod.input.yml",
@@ -64,6 +74,11 @@ func TestUninjectYAML(t *testing.T) {
 			goldenFileName: "inject_emojivoto_pod.input.yml",
 			reportFileName: "inject_emojivoto_pod_uninject.report",
 		},
+		{
+			inputFileName:  "inject_emojivoto_deployment_debug.golden.yml",
+			goldenFileName: "inject_emojivoto_deployment.input.yml",
+			reportFileName: "inject_emojivoto_deployment_uninject.report",
+		},
 		{
 			inputFileName:  "inject_emojivoto_deployment_udp.golden.yml",
 			goldenFileName: "inject_emojivoto_deployment_udp.input.yml",
@@ -118,3 +133,85 @@ func TestUninjectYAML(t *testing.T) {
 func stripDashes(str string) string {
 	return strings.Trim(str, "-\n")
 }
+
+func TestUninjectOptions(t *testing.T) {
+	testCases := []struct {
+		inputFileName string
+		options       *uninjectOptions
+		expected      []string
+		unexpected    []string
+	}{
+		{
+			inputFileName: "inject_emojivoto_deployment_debug.golden.yml",
+			options:       &uninjectOptions{only: []string{"debug"}},
+			expected:      []string{"name: linkerd-proxy", "name: linkerd-init", "linkerd.io/created-by", "linkerd.io/control-plane-ns"},
+			unexpected:    []string{"name: linkerd-debug"},
+		},
+		{
+			inputFileName: "inject_emojivoto_deployment_debug.golden.yml",
+			options:       &uninjectOptions{keepAnnotations: true},
+			expected:      []string{"linkerd.io/created-by", "linkerd.io/proxy-version"},
+			unexpected:    []string{"name: linkerd-proxy", "name: linkerd-init", "name: linkerd-debug", "linkerd.io/control-plane-ns"},
+		},
+		{
+			inputFileName: "inject_emojivoto_cronjob.golden.yml",
+			options:       &uninjectOptions{only: []string{"proxy", "proxy-init"}},
+			expected:      []string{"kind: CronJob"},
+			unexpected:    []string{"name: linkerd-proxy", "name: linkerd-init", "linkerd.io/created-by"},
+		},
+	}
+
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("%d: %s", i, tc.inputFileName), func(t *testing.T) {
+			if err := tc.options.validate(); err != nil {
+				t.Fatalf("Unexpected error validating options: %s", err)
+			}
+
+			output := new(bytes.Buffer)
+			report := new(bytes.Buffer)
+			in := strings.NewReader(readOptionalTestFile(t, tc.inputFileName))
+			if err := ProcessYAML(in, output, report, nil, resourceTransformerUninject{options: tc.options}); err != nil {
+				t.Fatalf("Unexpected error uninjecting YAML: %s", err)
+			}
+
+			for _, s := range tc.expected {
+				if !strings.Contains(output.String(), s) {
+					t.Errorf("Expected the output to contain \"%s\", got:\n%s", s, output.String())
+				}
+			}
+			for _, s := range tc.unexpected {
+				if strings.Contains(output.String(), s) {
+					t.Errorf("Expected the output not to contain \"%s\", got:\n%s", s, output.String())
+				}
+			}
+			if !strings.Contains(report.String(), "uninjected") {
+				t.Errorf("Expected the resource to be reported as uninjected, got: %s", report.String())
+			}
+		})
+	}
+}
+
+func TestUninjectOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		only []string
+		err  string
+	}{
+		{[]string{"debug"}, ""},
+		{[]string{"proxy", "proxy-init", "debug"}, ""},
+		{[]string{"sidecar"}, "Invalid --only value \"sidecar\", must be one of: debug, proxy, proxy-init"},
+		{[]string{"proxy"}, "--only proxy requires proxy-init, without which the traffic of the pods is redirected to the removed proxy"},
+	}
+
+	for _, tc := range testCases {
+		options := newUninjectOptions()
+		options.only = tc.only
+		err := options.validate()
+		if tc.err == "" && err != nil {
+			t.Errorf("Unexpected error for --only %v: %s", tc.only, err)
+		}
+		if tc.err != "" && (err == nil || err.Error() != tc.err) {
+			t.Errorf("Expected error \"%s\" for --only %v, got: %v", tc.err, tc.only, err)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+)
+
+// uninstallKindOrder is the order in which the kinds of resources of the
+// control plane are deleted: the webhooks first, so that no pod creation waits
+// for a webhook that's going away, and the tap APIService, so that the
+// Kubernetes API stops proxying to the tap server, then the workloads, so that
+// they stop before the configuration and permissions they depend on, and the
+// custom resource definitions last, since deleting them deletes all their
+// objects.
+var uninstallKindOrder = []string{
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
+	"APIService",
+	"Deployment",
+	"PodDisruptionBudget",
+	"Service",
+	"NetworkPolicy",
+	"ConfigMap",
+	"Secret",
+	"RoleBinding",
+	"Role",
+	"ServiceAccount",
+	"Namespace",
+	"ClusterRoleBinding",
+	"ClusterRole",
+	"CustomResourceDefinition",
+}
+
+type uninstallOptions struct {
+	force bool
+}
+
+func newUninstallOptions() *uninstallOptions {
+	return &uninstallOptions{
+		force: false,
+	}
+}
+
+// uninstallResource identifies a control plane resource to delete.
+type uninstallResource struct {
+	metaV1.TypeMeta `json:",inline"`
+	Metadata        struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace,omitempty"`
+	} `json:"metadata"`
+}
+
+func (r uninstallResource) String() string {
+	return fmt.Sprintf("%s/%s", strings.ToLower(r.Kind), r.Metadata.Name)
+}
+
+// uninstallResourceFunc returns whether a control plane resource exists in
+// the cluster.
+type uninstallResourceFunc func(resource uninstallResource) (bool, error)
+
+func newCmdUninstall() *cobra.Command {
+	options := newUninstallOptions()
+
+	cmd := &cobra.Command{
+		Use:   "uninstall [flags]",
+		Short: "Output Kubernetes resources to uninstall the Linkerd control plane",
+		Long: `Output Kubernetes resources to uninstall the Linkerd control plane.
+
+The control plane resources that exist in the cluster are output in the order in
+which they can be safely deleted, from the proxy injector's webhooks to the
+custom resource definitions, whose deletion also deletes all the service
+profiles. They include the cluster-wide RBAC resources, and the trust anchors
+and certificates that the CA distributed to the namespaces of meshed pods, as
+well as the network policies that the proxy injector created there.
+
+The control plane isn't uninstalled while any pod outside of its namespace is
+still injected, since its proxy would lose its connection to the control plane.`,
+		Example: `  # Uninstall the control plane.
+  linkerd uninstall | kubectl delete -f -
+
+  # Uninstall the control plane directly.
+  linkerd uninstall --force`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+			if err != nil {
+				return err
+			}
+			client, err := kubeAPI.NewClient()
+			if err != nil {
+				return err
+			}
+			clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+			if err != nil {
+				return err
+			}
+
+			exists := func(resource uninstallResource) (bool, error) {
+				r, err := kubeAPI.GetResource(client, resource.APIVersion, resource.Kind, resource.Metadata.Namespace, resource.Metadata.Name)
+				return r != nil, err
+			}
+			resources, err := uninstallResources(clientset, exists)
+			if err != nil {
+				return err
+			}
+
+			if !options.force {
+				return renderUninstall(os.Stdout, resources)
+			}
+			for _, resource := range resources {
+				deleted, err := kubeAPI.DeleteResource(client, resource.APIVersion, resource.Kind, resource.Metadata.Namespace, resource.Metadata.Name)
+				if err != nil {
+					return fmt.Errorf("Failed to delete %s: %s", resource, err)
+				}
+				if deleted {
+					fmt.Printf("%s deleted\n", resource)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Args = cobra.NoArgs
+	cmd.PersistentFlags().BoolVar(&options.force, "force", options.force, "Delete the control plane resources from the cluster, instead of outputting them")
+
+	return cmd
+}
+
+// uninstallResources returns the control plane resources that exist in the
+// cluster, in the order in which to delete them. It fails if any pod outside
+// of the control plane namespace is still injected.
+func uninstallResources(clientset kubernetes.Interface, exists uninstallResourceFunc) ([]uninstallResource, error) {
+	pods, err := clientset.CoreV1().Pods("").List(metaV1.ListOptions{LabelSelector: k8s.ControllerNSLabel + "=" + controlPlaneNamespace})
+	if err != nil {
+		return nil, err
+	}
+	injected := []string{}
+	for _, pod := range pods.Items {
+		if pod.Namespace != controlPlaneNamespace {
+			injected = append(injected, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		}
+	}
+	if len(injected) > 0 {
+		sort.Strings(injected)
+		return nil, fmt.Errorf("The control plane can't be uninstalled while %d pods are still injected, such as %s; remove their proxies first with \"linkerd uninject\"",
+			len(injected), injected[0])
+	}
+
+	rendered, err := renderedControlPlaneResources()
+	if err != nil {
+		return nil, err
+	}
+	resources := []uninstallResource{}
+	for _, resource := range rendered {
+		ok, err := exists(resource)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get %s: %s", resource, err)
+		}
+		if ok {
+			resources = append(resources, resource)
+		}
+	}
+
+	// the CA creates the trust anchors and the certificates of meshed pods in
+	// their namespaces
+	configMaps, err := clientset.CoreV1().ConfigMaps("").List(metaV1.ListOptions{FieldSelector: "metadata.name=" + k8s.TLSTrustAnchorConfigMapName})
+	if err != nil {
+		return nil, err
+	}
+	for _, configMap := range configMaps.Items {
+		if configMap.Name == k8s.TLSTrustAnchorConfigMapName && configMap.Namespace != controlPlaneNamespace {
+			resources = append(resources, newUninstallResource("v1", "ConfigMap", configMap.Namespace, configMap.Name))
+		}
+	}
+	secrets, err := clientset.CoreV1().Secrets("").List(metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secrets.Items {
+		if secret.Namespace != controlPlaneNamespace && strings.HasSuffix(secret.Name, "-tls-linkerd-io") {
+			resources = append(resources, newUninstallResource("v1", "Secret", secret.Namespace, secret.Name))
+		}
+	}
+
+	// with --proxy-network-policies, the proxy injector creates a network
+	// policy in the namespaces of the pods it injects
+	policies, err := clientset.NetworkingV1().NetworkPolicies("").List(metaV1.ListOptions{LabelSelector: k8s.ControllerNSLabel + "=" + controlPlaneNamespace})
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies.Items {
+		if policy.Namespace != controlPlaneNamespace {
+			resources = append(resources, newUninstallResource("networking.k8s.io/v1", "NetworkPolicy", policy.Namespace, policy.Name))
+		}
+	}
+
+	rank := map[string]int{}
+	for i, kind := range uninstallKindOrder {
+		rank[kind] = i
+	}
+	sort.SliceStable(resources, func(i, j int) bool {
+		return rank[resources[i].Kind] < rank[resources[j].Kind]
+	})
+	return resources, nil
+}
+
+// renderedControlPlaneResources returns all the resources that `linkerd
+// install` can render, with every optional component enabled, along with the
+// proxy injector's webhook configurations, which it creates when it starts.
+func renderedControlPlaneResources() ([]uninstallResource, error) {
+	options := newInstallOptions()
+	options.tls = optionalTLS
+	options.tlsIssuerSecret = defaultTLSIssuerSecret
+	options.proxyAutoInject = true
+	options.highAvailability = true
+	options.networkPolicies = true
+	options.proxyNetworkPolicies = true
+	options.tapAPIService = true
+	for _, enabled := range options.addOns {
+		*enabled = true
+	}
+	config, err := validateAndBuildConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err := render(*config, buf, options); err != nil {
+		return nil, err
+	}
+
+	resources := []uninstallResource{
+		newUninstallResource("admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", "", k8s.ProxyInjectorWebhookConfig),
+		newUninstallResource("admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration", "", k8s.SPValidatorWebhookConfig),
+	}
+	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(buf, 4096))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return resources, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var resource uninstallResource
+		if err := yaml.Unmarshal(doc, &resource); err != nil {
+			return nil, err
+		}
+		if resource.Kind != "" {
+			resources = append(resources, resource)
+		}
+	}
+}
+
+func newUninstallResource(apiVersion, kind, namespace, name string) uninstallResource {
+	resource := uninstallResource{TypeMeta: metaV1.TypeMeta{APIVersion: apiVersion, Kind: kind}}
+	resource.Metadata.Name = name
+	resource.Metadata.Namespace = namespace
+	return resource
+}
+
+// renderUninstall writes the resources to delete to w, in a YAML stream that
+// `kubectl delete -f -` deletes in order.
+func renderUninstall(w io.Writer, resources []uninstallResource) error {
+	for _, resource := range resources {
+		out, err := yaml.Marshal(resource)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
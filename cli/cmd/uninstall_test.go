@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestUninstallResources(t *testing.T) {
+	installed := map[string]bool{
+		"customresourcedefinition/serviceprofiles.linkerd.io":                true,
+		"clusterrole/linkerd-linkerd-controller":                             true,
+		"clusterrolebinding/linkerd-linkerd-controller":                      true,
+		"namespace/linkerd":                                                  true,
+		"serviceaccount/linkerd-controller":                                  true,
+		"deployment/linkerd-controller":                                      true,
+		"mutatingwebhookconfiguration/linkerd-proxy-injector-webhook-config": true,
+		"validatingwebhookconfiguration/linkerd-sp-validator-webhook-config": true,
+		"apiservice/v1alpha1.tap.linkerd.io":                                 true,
+	}
+	exists := func(resource uninstallResource) (bool, error) {
+		return installed[resource.String()], nil
+	}
+
+	clientset := fake.NewSimpleClientset(
+		&v1.Pod{ObjectMeta: metaV1.ObjectMeta{
+			Name:      "linkerd-controller-1",
+			Namespace: "linkerd",
+			Labels:    map[string]string{k8s.ControllerNSLabel: "linkerd"},
+		}},
+		&v1.ConfigMap{ObjectMeta: metaV1.ObjectMeta{Name: k8s.TLSTrustAnchorConfigMapName, Namespace: "emojivoto"}},
+		&v1.ConfigMap{ObjectMeta: metaV1.ObjectMeta{Name: "other", Namespace: "emojivoto"}},
+		&v1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "web-deployment-tls-linkerd-io", Namespace: "emojivoto"}},
+		&v1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: "other", Namespace: "emojivoto"}},
+		&networkingv1.NetworkPolicy{ObjectMeta: metaV1.ObjectMeta{
+			Name:      "linkerd-proxy",
+			Namespace: "emojivoto",
+			Labels:    map[string]string{k8s.ControllerNSLabel: "linkerd"},
+		}},
+		&networkingv1.NetworkPolicy{ObjectMeta: metaV1.ObjectMeta{Name: "other", Namespace: "emojivoto"}},
+	)
+
+	resources, err := uninstallResources(clientset, exists)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	output := &bytes.Buffer{}
+	if err := renderUninstall(output, resources); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := `---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: MutatingWebhookConfiguration
+metadata:
+  name: linkerd-proxy-injector-webhook-config
+---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: linkerd-sp-validator-webhook-config
+---
+apiVersion: apiregistration.k8s.io/v1beta1
+kind: APIService
+metadata:
+  name: v1alpha1.tap.linkerd.io
+---
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: linkerd-controller
+  namespace: linkerd
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: linkerd-proxy
+  namespace: emojivoto
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: linkerd-ca-bundle
+  namespace: emojivoto
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: web-deployment-tls-linkerd-io
+  namespace: emojivoto
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: linkerd-controller
+  namespace: linkerd
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: linkerd
+---
+apiVersion: rbac.authorization.k8s.io/v1beta1
+kind: ClusterRoleBinding
+metadata:
+  name: linkerd-linkerd-controller
+---
+apiVersion: rbac.authorization.k8s.io/v1beta1
+kind: ClusterRole
+metadata:
+  name: linkerd-linkerd-controller
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: serviceprofiles.linkerd.io
+  namespace: linkerd
+`
+	diffCompare(t, output.String(), expected)
+}
+
+func TestUninstallResourcesWithInjectedPods(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Pod{ObjectMeta: metaV1.ObjectMeta{
+			Name:      "web-1",
+			Namespace: "emojivoto",
+			Labels:    map[string]string{k8s.ControllerNSLabel: "linkerd"},
+		}},
+		&v1.Pod{ObjectMeta: metaV1.ObjectMeta{
+			Name:      "other-1",
+			Namespace: "emojivoto",
+			Labels:    map[string]string{k8s.ControllerNSLabel: "other-linkerd"},
+		}},
+	)
+	exists := func(uninstallResource) (bool, error) { return true, nil }
+
+	_, err := uninstallResources(clientset, exists)
+	expected := "The control plane can't be uninstalled while 1 pods are still injected, such as emojivoto/web-1; remove their proxies first with \"linkerd uninject\""
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected error %q, got: %v", expected, err)
+	}
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	"github.com/linkerd/linkerd2/cli/install"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type upgradeOptions struct {
+	crds          bool
+	skipChecks    bool
+	diff          bool
+	fromManifests string
+	*installOptions
+}
+
+func newUpgradeOptions() *upgradeOptions {
+	return &upgradeOptions{
+		crds:           false,
+		skipChecks:     false,
+		diff:           false,
+		fromManifests:  "",
+		installOptions: newInstallOptions(),
+	}
+}
+
+// crdStoredVersionsFunc returns the versions in which the objects of a custom
+// resource definition are stored in the cluster, or nil if it doesn't exist.
+type crdStoredVersionsFunc func(name string) ([]string, error)
+
+func newCmdUpgrade() *cobra.Command {
+	options := newUpgradeOptions()
+
+	cmd := &cobra.Command{
+		Use:   "upgrade [flags]",
+		Short: "Output Kubernetes configs to upgrade an existing Linkerd control plane",
+		Long: `Output Kubernetes configs to upgrade an existing Linkerd control plane.
+
+The upgrade is done in stages, so that the custom resource definitions, which
+tools such as GitOps controllers refuse to change implicitly, have their own
+explicit step. Only the --crds stage is currently supported: it outputs the
+custom resource definitions, after checking that the objects already stored in
+the cluster remain readable with them. The rest of the control plane is then
+upgraded with "linkerd install --skip-crds".
+
+With --diff, nothing is output to apply: instead, the resources in the cluster
+are compared with the upgraded ones, rendered with the same flags as "linkerd
+install", and the changes are printed as a unified diff, followed by a summary
+on stderr. With --crds, only the custom resource definitions are compared. The
+values of secrets are replaced by their digests, and resources that the upgrade
+no longer renders aren't listed.
+
+With --from-manifests, the control plane is read from manifests exported from
+the cluster, e.g. with "kubectl get -o yaml", or from the output of the previous
+"linkerd install", instead of the cluster, so that upgrades can be rendered in
+air-gapped or GitOps-only environments. The flags that the control plane was
+installed with are read from its linkerd-config ConfigMap, and the flags given
+on the command line take precedence over them. The custom resource definitions
+are checked against the versions stored according to the manifests, and --diff
+compares the upgrade with them.`,
+		Example: `  # Preview the changes to the control plane.
+  linkerd upgrade --diff --ha
+
+  # Upgrade the custom resource definitions first.
+  linkerd upgrade --crds | kubectl apply -f -
+
+  # Then upgrade the rest of the control plane.
+  linkerd install --skip-crds --ha | kubectl apply -f -
+
+  # Preview the upgrade of the control plane exported to manifests.
+  linkerd upgrade --diff --from-manifests linkerd.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyInstallValues(cmd.PersistentFlags(), options.installOptions); err != nil {
+				return err
+			}
+			options.recordedFlags = recordedInstallFlags(cmd.PersistentFlags())
+			if !options.diff && !options.crds {
+				return errors.New("You must specify the stage to upgrade; only --crds is currently supported, or preview the upgrade with --diff")
+			}
+			if options.fromManifests != "" {
+				return runUpgradeFromManifests(os.Stdout, os.Stderr, options, cmd.PersistentFlags())
+			}
+			if options.diff {
+				return runUpgradeDiff(options)
+			}
+
+			var storedVersions crdStoredVersionsFunc
+			if !options.skipChecks {
+				kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+				if err != nil {
+					return err
+				}
+				client, err := kubeAPI.NewClient()
+				if err != nil {
+					return err
+				}
+				storedVersions = func(name string) ([]string, error) {
+					return kubeAPI.GetCRDStoredVersions(client, name)
+				}
+			}
+
+			return renderCRDs(os.Stdout, storedVersions)
+		},
+	}
+
+	cmd.Args = cobra.NoArgs
+	addInstallFlags(cmd, options.installOptions)
+	cmd.PersistentFlags().BoolVar(&options.crds, "crds", options.crds, "Output the custom resource definitions of the control plane")
+	cmd.PersistentFlags().BoolVar(&options.skipChecks, "skip-checks", options.skipChecks, "Don't check the custom resource definitions installed in the cluster, e.g. to render them without access to it")
+	cmd.PersistentFlags().BoolVar(&options.diff, "diff", options.diff, "Print the changes that the upgrade would make to the control plane in the cluster, instead of the configs to apply")
+	cmd.PersistentFlags().StringVar(&options.fromManifests, "from-manifests", options.fromManifests, "Read the installed control plane from the manifests in this file (\"-\" for stdin) instead of the cluster")
+
+	return cmd
+}
+
+func runUpgradeDiff(options *upgradeOptions) error {
+	kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
+	if err != nil {
+		return err
+	}
+	client, err := kubeAPI.NewClient()
+	if err != nil {
+		return err
+	}
+
+	var storedVersions crdStoredVersionsFunc
+	if !options.skipChecks {
+		storedVersions = func(name string) ([]string, error) {
+			return kubeAPI.GetCRDStoredVersions(client, name)
+		}
+	}
+	rendered := &bytes.Buffer{}
+	if err := renderUpgrade(rendered, options, storedVersions); err != nil {
+		return err
+	}
+
+	live := func(apiVersion, kind, namespace, name string) ([]byte, error) {
+		return kubeAPI.GetResource(client, apiVersion, kind, namespace, name)
+	}
+	return renderUpgradeDiff(os.Stdout, os.Stderr, rendered, live)
+}
+
+// runUpgradeFromManifests renders the upgrade of the control plane read from
+// the manifests of options.fromManifests, with the flags that it was installed
+// with overridden by the install flags in flags, or the diff of the upgrade
+// with --diff.
+func runUpgradeFromManifests(w, summary io.Writer, options *upgradeOptions, flags *pflag.FlagSet) error {
+	manifests, err := readUpgradeManifests(options.fromManifests)
+	if err != nil {
+		return fmt.Errorf("Failed to read the manifests: %s", err)
+	}
+	options.installOptions, err = manifests.installOptions(flags)
+	if err != nil {
+		return err
+	}
+
+	var storedVersions crdStoredVersionsFunc
+	if !options.skipChecks {
+		storedVersions = manifests.storedVersions
+	}
+	if !options.diff {
+		return renderCRDs(w, storedVersions)
+	}
+
+	rendered := &bytes.Buffer{}
+	if err := renderUpgrade(rendered, options, storedVersions); err != nil {
+		return err
+	}
+	return renderUpgradeDiff(w, summary, rendered, manifests.live)
+}
+
+// renderUpgrade writes the resources that --diff compares with the installed
+// ones to w: the custom resource definitions with --crds, or else the control
+// plane.
+func renderUpgrade(w io.Writer, options *upgradeOptions, storedVersions crdStoredVersionsFunc) error {
+	if options.crds {
+		return renderCRDs(w, storedVersions)
+	}
+	config, err := validateAndBuildConfig(options.installOptions)
+	if err != nil {
+		return err
+	}
+	return render(*config, w, options.installOptions)
+}
+
+// renderCRDs writes the custom resource definitions of the control plane to
+// w. If storedVersions is set, it first checks that every version in which the
+// cluster stores objects of each definition is still served by it, since
+// Kubernetes would refuse to apply a definition that drops one of them, and the
+// objects would become unreadable.
+func renderCRDs(w io.Writer, storedVersions crdStoredVersionsFunc) error {
+	tmpl, err := template.New("crds").Parse(install.CRDTemplate)
+	if err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, installConfig{
+		Namespace:           controlPlaneNamespace,
+		CreatedByAnnotation: k8s.CreatedByAnnotation,
+		CliVersion:          k8s.CreatedByAnnotationValue(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if storedVersions != nil {
+		for _, doc := range splitYAMLDocuments(buf.String()) {
+			if err := checkCRDCompatibility(doc, storedVersions); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = fmt.Fprintln(w, buf.String())
+	return err
+}
+
+func checkCRDCompatibility(doc string, storedVersions crdStoredVersionsFunc) error {
+	var crd struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			Version  string `json:"version"`
+			Versions []struct {
+				Name string `json:"name"`
+			} `json:"versions"`
+		} `json:"spec"`
+	}
+	if err := yaml.Unmarshal([]byte(doc), &crd); err != nil {
+		return err
+	}
+	if crd.Kind != "CustomResourceDefinition" {
+		return nil
+	}
+
+	served := map[string]struct{}{}
+	if crd.Spec.Version != "" {
+		served[crd.Spec.Version] = struct{}{}
+	}
+	for _, version := range crd.Spec.Versions {
+		served[version.Name] = struct{}{}
+	}
+
+	stored, err := storedVersions(crd.Metadata.Name)
+	if err != nil {
+		return fmt.Errorf("Failed to get the %s custom resource definition: %s", crd.Metadata.Name, err)
+	}
+	for _, version := range stored {
+		if _, ok := served[version]; !ok {
+			versions := []string{}
+			for v := range served {
+				versions = append(versions, v)
+			}
+			sort.Strings(versions)
+			return fmt.Errorf("The cluster stores %s objects in version %s, which this version of Linkerd no longer serves; migrate them to %s before upgrading",
+				crd.Metadata.Name, version, strings.Join(versions, ", "))
+		}
+	}
+	return nil
+}
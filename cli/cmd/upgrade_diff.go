@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ghodss/yaml"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// lastAppliedConfigAnnotation is set by `kubectl apply` to the configuration
+// that it last applied to a resource.
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// liveResourceFunc returns the JSON representation of the resource with the
+// given apiVersion, kind, namespace and name in the cluster, or nil if it
+// doesn't exist.
+type liveResourceFunc func(apiVersion, kind, namespace, name string) ([]byte, error)
+
+// renderUpgradeDiff writes a unified diff between each resource in the
+// cluster and its rendered upgrade to w, and a summary of the changes to
+// summary. The live resources are compared by the configuration that `kubectl
+// apply` last applied to them, if any, so that the fields defaulted or managed
+// by Kubernetes don't show up as changes.
+func renderUpgradeDiff(w, summary io.Writer, rendered io.Reader, live liveResourceFunc) error {
+	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(rendered, 4096))
+
+	changed, added, unchanged := 0, 0, 0
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		var upgraded map[string]interface{}
+		if err := yaml.Unmarshal(doc, &upgraded); err != nil {
+			return err
+		}
+		if upgraded == nil {
+			continue
+		}
+		var resource installResource
+		if err := yaml.Unmarshal(doc, &resource); err != nil {
+			return err
+		}
+		name := resourceName(string(doc))
+
+		upgradedYAML, err := normalizeUpgradeResource(upgraded)
+		if err != nil {
+			return err
+		}
+
+		current, err := live(resource.APIVersion, resource.Kind, resource.Namespace, resource.Name)
+		if err != nil {
+			return fmt.Errorf("Failed to get %s: %s", name, err)
+		}
+		if current == nil {
+			added++
+			fmt.Fprint(w, unifiedDiff("/dev/null", "b/"+name, "", upgradedYAML))
+			continue
+		}
+
+		currentResource, err := appliedConfiguration(current)
+		if err != nil {
+			return fmt.Errorf("Failed to parse %s: %s", name, err)
+		}
+		currentYAML, err := normalizeUpgradeResource(currentResource)
+		if err != nil {
+			return err
+		}
+
+		diff := unifiedDiff("a/"+name, "b/"+name, currentYAML, upgradedYAML)
+		if diff == "" {
+			unchanged++
+			continue
+		}
+		changed++
+		fmt.Fprint(w, diff)
+	}
+
+	fmt.Fprintf(summary, "%d resources changed, %d added, %d unchanged\n", changed, added, unchanged)
+	return nil
+}
+
+// appliedConfiguration returns the configuration that was last applied to the
+// live resource, or the resource itself without the fields set by Kubernetes if
+// it wasn't created with `kubectl apply`.
+func appliedConfiguration(live []byte) (map[string]interface{}, error) {
+	var resource map[string]interface{}
+	if err := json.Unmarshal(live, &resource); err != nil {
+		return nil, err
+	}
+	metadata, _ := resource["metadata"].(map[string]interface{})
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+
+	if applied, ok := annotations[lastAppliedConfigAnnotation].(string); ok {
+		var config map[string]interface{}
+		if err := json.Unmarshal([]byte(applied), &config); err != nil {
+			return nil, err
+		}
+		return config, nil
+	}
+
+	for _, field := range []string{"uid", "resourceVersion", "selfLink", "generation"} {
+		delete(metadata, field)
+	}
+	return resource, nil
+}
+
+// normalizeUpgradeResource serializes a resource for diffing, without its
+// status and the creation timestamps of the resource and its pod template,
+// which aren't part of its configuration, and with the values of secrets
+// replaced by their digests.
+func normalizeUpgradeResource(resource map[string]interface{}) (string, error) {
+	delete(resource, "status")
+	if metadata, ok := resource["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, lastAppliedConfigAnnotation)
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+	if spec, ok := resource["spec"].(map[string]interface{}); ok {
+		if template, ok := spec["template"].(map[string]interface{}); ok {
+			if metadata, ok := template["metadata"].(map[string]interface{}); ok {
+				delete(metadata, "creationTimestamp")
+			}
+		}
+	}
+
+	if resource["kind"] == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			values, ok := resource[field].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for key, value := range values {
+				digest := sha256.Sum256([]byte(fmt.Sprint(value)))
+				values[key] = fmt.Sprintf("<redacted, sha256 %x>", digest[:8])
+			}
+		}
+	}
+
+	out, err := yaml.Marshal(resource)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
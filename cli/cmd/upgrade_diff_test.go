@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRenderUpgradeDiff(t *testing.T) {
+	rendered := `### Namespace ###
+---
+kind: Namespace
+apiVersion: v1
+metadata:
+  name: linkerd
+---
+kind: Deployment
+apiVersion: extensions/v1beta1
+metadata:
+  name: linkerd-controller
+  namespace: linkerd
+  creationTimestamp: null
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: public-api
+        image: gcr.io/linkerd-io/controller:stable-2.1.0
+status: {}
+---
+kind: Secret
+apiVersion: v1
+metadata:
+  name: linkerd-ca-issuer
+  namespace: linkerd
+data:
+  tls.crt: bmV3
+---
+kind: ServiceAccount
+apiVersion: v1
+metadata:
+  name: linkerd-web
+  namespace: linkerd
+`
+
+	liveResources := map[string]string{
+		"v1/Namespace//linkerd": `{"kind":"Namespace","apiVersion":"v1","metadata":{"name":"linkerd","uid":"1234","annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{\"apiVersion\":\"v1\",\"kind\":\"Namespace\",\"metadata\":{\"annotations\":{},\"name\":\"linkerd\"}}\n"}},"status":{"phase":"Active"}}`,
+		"extensions/v1beta1/Deployment/linkerd/linkerd-controller": `{"kind":"Deployment","apiVersion":"extensions/v1beta1","metadata":{"name":"linkerd-controller","namespace":"linkerd","uid":"5678","resourceVersion":"42","generation":3,"creationTimestamp":"2018-12-01T00:00:00Z"},"spec":{"replicas":1,"template":{"spec":{"containers":[{"name":"public-api","image":"gcr.io/linkerd-io/controller:stable-2.0.0"}]}}},"status":{"replicas":1}}`,
+		"v1/Secret/linkerd/linkerd-ca-issuer":                      `{"kind":"Secret","apiVersion":"v1","metadata":{"name":"linkerd-ca-issuer","namespace":"linkerd"},"data":{"tls.crt":"b2xk"}}`,
+	}
+	live := func(apiVersion, kind, namespace, name string) ([]byte, error) {
+		if resource, ok := liveResources[strings.Join([]string{apiVersion, kind, namespace, name}, "/")]; ok {
+			return []byte(resource), nil
+		}
+		return nil, nil
+	}
+
+	out := &bytes.Buffer{}
+	summary := &bytes.Buffer{}
+	if err := renderUpgradeDiff(out, summary, strings.NewReader(rendered), live); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := `--- a/deployment/linkerd-controller
++++ b/deployment/linkerd-controller
+@@ -4,9 +4,9 @@
+   name: linkerd-controller
+   namespace: linkerd
+ spec:
+-  replicas: 1
++  replicas: 3
+   template:
+     spec:
+       containers:
+-      - image: gcr.io/linkerd-io/controller:stable-2.0.0
++      - image: gcr.io/linkerd-io/controller:stable-2.1.0
+         name: public-api
+--- a/secret/linkerd-ca-issuer
++++ b/secret/linkerd-ca-issuer
+@@ -1,6 +1,6 @@
+ apiVersion: v1
+ data:
+-  tls.crt: <redacted, sha256 0c2c6b90f0098654>
++  tls.crt: <redacted, sha256 fd9bd588247ac76e>
+ kind: Secret
+ metadata:
+   name: linkerd-ca-issuer
+--- /dev/null
++++ b/serviceaccount/linkerd-web
+@@ -0,0 +1,5 @@
++apiVersion: v1
++kind: ServiceAccount
++metadata:
++  name: linkerd-web
++  namespace: linkerd
+`
+	diffCompare(t, out.String(), expected)
+	diffCompare(t, summary.String(), "2 resources changed, 1 added, 1 unchanged\n")
+}
+
+func TestRenderUpgradeDiffErrors(t *testing.T) {
+	live := func(apiVersion, kind, namespace, name string) ([]byte, error) {
+		return nil, errors.New("connection refused")
+	}
+	err := renderUpgradeDiff(&bytes.Buffer{}, &bytes.Buffer{}, strings.NewReader("kind: Namespace\napiVersion: v1\nmetadata:\n  name: linkerd\n"), live)
+	if err == nil || err.Error() != "Failed to get namespace/linkerd: connection refused" {
+		t.Fatalf("Expected a connection error, got: %v", err)
+	}
+}
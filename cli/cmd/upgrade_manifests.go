@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/pflag"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// upgradeManifests are the resources of an installed control plane, exported
+// to manifests, that `linkerd upgrade --from-manifests` reads instead of
+// querying the cluster, so that upgrades can be rendered without access to it.
+type upgradeManifests struct {
+	// resources are the JSON representations of the resources, by apiVersion,
+	// kind, namespace and name
+	resources map[string][]byte
+
+	// crds are the JSON representations of the custom resource definitions,
+	// by name
+	crds map[string][]byte
+
+	// config is the linkerdConfig of the linkerd-config ConfigMap
+	config *linkerdConfig
+}
+
+// readUpgradeManifests reads the manifests at path, or from stdin if path is
+// "-".
+func readUpgradeManifests(path string) (*upgradeManifests, error) {
+	if path == "-" {
+		return parseUpgradeManifests(os.Stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseUpgradeManifests(f)
+}
+
+// parseUpgradeManifests reads the resources of the YAML documents of r, such
+// as the output of `linkerd install` or of `kubectl get -o yaml`, whose lists
+// are read item by item.
+func parseUpgradeManifests(r io.Reader) (*upgradeManifests, error) {
+	manifests := &upgradeManifests{
+		resources: map[string][]byte{},
+		crds:      map[string][]byte{},
+	}
+
+	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(r, 4096))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		resource, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return nil, err
+		}
+		if err := manifests.add(resource); err != nil {
+			return nil, err
+		}
+	}
+
+	if manifests.config == nil {
+		return nil, fmt.Errorf("The manifests have no %s ConfigMap; they must be exported from a control plane installed or upgraded with this version of the CLI", linkerdConfigMap)
+	}
+	return manifests, nil
+}
+
+func (m *upgradeManifests) add(resource []byte) error {
+	var meta struct {
+		metaV1.TypeMeta   `json:",inline"`
+		metaV1.ObjectMeta `json:"metadata,omitempty"`
+		Data              map[string]string `json:"data"`
+		Items             []json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(resource, &meta); err != nil {
+		return err
+	}
+
+	switch {
+	case meta.Kind == "":
+		return nil
+	case strings.HasSuffix(meta.Kind, "List"):
+		for _, item := range meta.Items {
+			if err := m.add(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case meta.Kind == "CustomResourceDefinition":
+		m.crds[meta.Name] = resource
+	case meta.Kind == "ConfigMap" && meta.Name == linkerdConfigMap:
+		config := &linkerdConfig{}
+		if err := json.Unmarshal([]byte(meta.Data[linkerdConfigKey]), config); err != nil {
+			return fmt.Errorf("Invalid %s ConfigMap: %s", linkerdConfigMap, err)
+		}
+		m.config = config
+	}
+
+	m.resources[manifestResourceKey(meta.APIVersion, meta.Kind, meta.Namespace, meta.Name)] = resource
+	return nil
+}
+
+func manifestResourceKey(apiVersion, kind, namespace, name string) string {
+	return strings.Join([]string{apiVersion, kind, namespace, name}, "/")
+}
+
+// live is the liveResourceFunc of the manifests.
+func (m *upgradeManifests) live(apiVersion, kind, namespace, name string) ([]byte, error) {
+	return m.resources[manifestResourceKey(apiVersion, kind, namespace, name)], nil
+}
+
+// storedVersions is the crdStoredVersionsFunc of the manifests. The stored
+// versions are read from the status of the definitions exported from the
+// cluster; the definitions rendered by `linkerd install` have no status, and
+// their storage version is used instead.
+func (m *upgradeManifests) storedVersions(name string) ([]string, error) {
+	resource, ok := m.crds[name]
+	if !ok {
+		return nil, nil
+	}
+
+	var crd struct {
+		Spec struct {
+			Version  string `json:"version"`
+			Versions []struct {
+				Name    string `json:"name"`
+				Storage bool   `json:"storage"`
+			} `json:"versions"`
+		} `json:"spec"`
+		Status struct {
+			StoredVersions []string `json:"storedVersions"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(resource, &crd); err != nil {
+		return nil, err
+	}
+
+	if len(crd.Status.StoredVersions) != 0 {
+		return crd.Status.StoredVersions, nil
+	}
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return []string{version.Name}, nil
+		}
+	}
+	if crd.Spec.Version != "" {
+		return []string{crd.Spec.Version}, nil
+	}
+	return nil, nil
+}
+
+// installOptions returns the install options of the flags stored in the
+// linkerdConfig, with the install flags set in flags taking precedence over
+// the stored ones of the same name.
+func (m *upgradeManifests) installOptions(flags *pflag.FlagSet) (*installOptions, error) {
+	overrides := recordedInstallFlags(flags)
+	overridden := map[string]struct{}{}
+	for _, arg := range overrides {
+		overridden[flagArgName(arg)] = struct{}{}
+	}
+
+	args := []string{}
+	for _, arg := range m.config.Flags {
+		if _, ok := overridden[flagArgName(arg)]; !ok {
+			args = append(args, arg)
+		}
+	}
+	args = append(args, overrides...)
+
+	options, merged, err := parseLinkerdConfig(&linkerdConfig{Version: m.config.Version, Flags: args})
+	if err != nil {
+		return nil, err
+	}
+	options.recordedFlags = recordedInstallFlags(merged)
+	return options, nil
+}
+
+// flagArgName returns the name of the flag of an argument of the form
+// "--name" or "--name=value".
+func flagArgName(arg string) string {
+	return strings.TrimPrefix(strings.SplitN(arg, "=", 2)[0], "--")
+}
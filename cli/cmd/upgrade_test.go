@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRenderCRDs(t *testing.T) {
+	testCases := []struct {
+		title          string
+		storedVersions crdStoredVersionsFunc
+		err            string
+	}{
+		{
+			title: "renders without checks",
+		},
+		{
+			title:          "renders when the definitions aren't installed",
+			storedVersions: func(string) ([]string, error) { return nil, nil },
+		},
+		{
+			title:          "renders when the stored versions are served",
+			storedVersions: func(string) ([]string, error) { return []string{"v1alpha1"}, nil },
+		},
+		{
+			title:          "rejects definitions that drop a stored version",
+			storedVersions: func(string) ([]string, error) { return []string{"v1alpha1", "v1alpha0"}, nil },
+			err:            "The cluster stores serviceprofiles.linkerd.io objects in version v1alpha0, which this version of Linkerd no longer serves; migrate them to v1alpha1 before upgrading",
+		},
+		{
+			title:          "fails when the cluster can't be checked",
+			storedVersions: func(string) ([]string, error) { return nil, errors.New("connection refused") },
+			err:            "Failed to get the serviceprofiles.linkerd.io custom resource definition: connection refused",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := renderCRDs(buf, tc.storedVersions)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("Expected error %q, got: %v", tc.err, err)
+				}
+				if buf.Len() != 0 {
+					t.Fatalf("Expected no output, got:\n%s", buf.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !strings.Contains(buf.String(), "kind: CustomResourceDefinition\nmetadata:\n  name: serviceprofiles.linkerd.io\n") {
+				t.Fatalf("Expected the ServiceProfile CRD, got:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestUpgradeManifests(t *testing.T) {
+	exported := `apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: linkerd-config
+    namespace: linkerd
+  data:
+    config: '{"version":"stable-2.2.1","flags":["--ha","--proxy-log-level=warn"]}'
+- apiVersion: apiextensions.k8s.io/v1beta1
+  kind: CustomResourceDefinition
+  metadata:
+    name: serviceprofiles.linkerd.io
+  spec:
+    version: v1alpha1
+  status:
+    storedVersions:
+    - v1alpha0
+    - v1alpha1
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: linkerd-web
+  namespace: linkerd
+`
+
+	manifests, err := parseUpgradeManifests(strings.NewReader(exported))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	t.Run("Reads the resources of lists and documents", func(t *testing.T) {
+		for _, key := range [][]string{
+			{"v1", "ConfigMap", "linkerd", "linkerd-config"},
+			{"apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "", "serviceprofiles.linkerd.io"},
+			{"v1", "ServiceAccount", "linkerd", "linkerd-web"},
+		} {
+			resource, err := manifests.live(key[0], key[1], key[2], key[3])
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if resource == nil {
+				t.Fatalf("Expected the %s resource", strings.Join(key, "/"))
+			}
+		}
+		if resource, _ := manifests.live("v1", "ServiceAccount", "linkerd", "linkerd-grafana"); resource != nil {
+			t.Fatalf("Expected no linkerd-grafana ServiceAccount, got %s", resource)
+		}
+	})
+
+	t.Run("Checks the definitions against their stored versions", func(t *testing.T) {
+		err := renderCRDs(&bytes.Buffer{}, manifests.storedVersions)
+		expected := "The cluster stores serviceprofiles.linkerd.io objects in version v1alpha0, which this version of Linkerd no longer serves; migrate them to v1alpha1 before upgrading"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q, got: %v", expected, err)
+		}
+	})
+
+	t.Run("Uses the storage version of the rendered definitions", func(t *testing.T) {
+		manifests, err := parseUpgradeManifests(strings.NewReader(`apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: serviceprofiles.linkerd.io
+spec:
+  versions:
+  - name: v1alpha0
+    storage: false
+  - name: v1alpha1
+    storage: true
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: linkerd-config
+  namespace: linkerd
+data:
+  config: '{"version":"stable-2.2.1","flags":[]}'
+`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		versions, err := manifests.storedVersions("serviceprofiles.linkerd.io")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if strings.Join(versions, ",") != "v1alpha1" {
+			t.Fatalf("Expected the v1alpha1 storage version, got %v", versions)
+		}
+	})
+
+	t.Run("Overrides the stored flags with the given ones", func(t *testing.T) {
+		flags := newCmdUpgrade().PersistentFlags()
+		for name, value := range map[string]string{"proxy-log-level": "debug", "diff": "true"} {
+			if err := flags.Set(name, value); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		}
+
+		options, err := manifests.installOptions(flags)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := []string{"--ha", "--proxy-log-level=debug"}
+		if strings.Join(options.recordedFlags, " ") != strings.Join(expected, " ") {
+			t.Fatalf("Expected flags %v, got %v", expected, options.recordedFlags)
+		}
+	})
+
+	t.Run("Requires the linkerd-config ConfigMap", func(t *testing.T) {
+		_, err := parseUpgradeManifests(strings.NewReader("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: linkerd\n"))
+		expected := "The manifests have no linkerd-config ConfigMap; they must be exported from a control plane installed or upgraded with this version of the CLI"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q, got: %v", expected, err)
+		}
+	})
+}
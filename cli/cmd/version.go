@@ -3,10 +3,13 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/client"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/pkg/version"
 	"github.com/spf13/cobra"
@@ -33,11 +36,10 @@ func newCmdVersion() *cobra.Command {
 		Use:   "version",
 		Short: "Print the client and server version information",
 		Run: func(cmd *cobra.Command, args []string) {
-			clientVersion := version.Version
 			if options.shortVersion {
-				fmt.Println(clientVersion)
+				fmt.Println(version.Version)
 			} else {
-				fmt.Printf("Client version: %s\n", clientVersion)
+				printVersionInfo(os.Stdout, "Client", version.Info())
 			}
 
 			client, err := newVersionClient()
@@ -47,11 +49,11 @@ func newCmdVersion() *cobra.Command {
 			}
 
 			if !options.onlyClientVersion {
-				serverVersion := getServerVersion(client)
+				serverVersion := getServerVersionInfo(client)
 				if options.shortVersion {
-					fmt.Println(serverVersion)
+					fmt.Println(serverVersion.GetReleaseVersion())
 				} else {
-					fmt.Printf("Server version: %s\n", serverVersion)
+					printVersionInfo(os.Stdout, "Server", serverVersion)
 				}
 			}
 		},
@@ -65,12 +67,42 @@ func newCmdVersion() *cobra.Command {
 }
 
 func getServerVersion(client pb.ApiClient) string {
+	return getServerVersionInfo(client).GetReleaseVersion()
+}
+
+// getServerVersionInfo returns the version and the build info of the public
+// API, or only the unavailable version if it can't be queried.
+func getServerVersionInfo(client pb.ApiClient) *pb.VersionInfo {
 	resp, err := client.Version(context.Background(), &pb.Empty{})
 	if err != nil {
-		return defaultVersionString
+		return &pb.VersionInfo{ReleaseVersion: defaultVersionString}
 	}
 
-	return resp.GetReleaseVersion()
+	return resp
+}
+
+// printVersionInfo prints the version of the client or server, followed by
+// its build info, which identifies the build in bug reports.
+func printVersionInfo(w io.Writer, name string, info *pb.VersionInfo) {
+	fmt.Fprintf(w, "%s version: %s\n", name, info.GetReleaseVersion())
+	if info.GetReleaseVersion() == defaultVersionString {
+		return
+	}
+
+	orUnknown := func(value string) string {
+		if value == "" {
+			return "unknown"
+		}
+		return value
+	}
+	buildTags := "none"
+	if len(info.GetBuildTags()) > 0 {
+		buildTags = strings.Join(info.GetBuildTags(), ",")
+	}
+	fmt.Fprintf(w, "  Git SHA:    %s\n", orUnknown(info.GetGitSha()))
+	fmt.Fprintf(w, "  Build date: %s\n", orUnknown(info.GetBuildDate()))
+	fmt.Fprintf(w, "  Go version: %s\n", orUnknown(info.GetGoVersion()))
+	fmt.Fprintf(w, "  Build tags: %s\n", buildTags)
 }
 
 // This client does not do any validation
@@ -78,7 +110,21 @@ func newVersionClient() (pb.ApiClient, error) {
 	if apiAddr != "" {
 		return public.NewInternalClient(controlPlaneNamespace, apiAddr)
 	}
-	kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext)
+	if apiTLS {
+		options := client.NewOptions()
+		options.ControlPlaneNamespace = controlPlaneNamespace
+		options.KubeConfig = kubeconfigPath
+		options.KubeContext = kubeContext
+		options.Impersonate = impersonate
+		options.ImpersonateGroup = impersonateGroup
+		options.MutualTLS = true
+		c, err := client.New(options)
+		if err != nil {
+			return nil, err
+		}
+		return c.API(), nil
+	}
+	kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"testing"
 
@@ -37,3 +38,34 @@ func TestGetServerVersion(t *testing.T) {
 		}
 	})
 }
+
+func TestPrintVersionInfo(t *testing.T) {
+	t.Run("Prints the build info", func(t *testing.T) {
+		var buf bytes.Buffer
+		printVersionInfo(&buf, "Server", &pb.VersionInfo{
+			ReleaseVersion: "stable-2.2.0",
+			GitSha:         "2d6f90ab1293a1fb871cf149423ebb72aa7423aa",
+			GoVersion:      "go1.11.5",
+			BuildTags:      []string{"nopprof"},
+		})
+
+		expected := `Server version: stable-2.2.0
+  Git SHA:    2d6f90ab1293a1fb871cf149423ebb72aa7423aa
+  Build date: unknown
+  Go version: go1.11.5
+  Build tags: nopprof
+`
+		if buf.String() != expected {
+			t.Fatalf("Expected output:\n%s\ngot:\n%s", expected, buf.String())
+		}
+	})
+
+	t.Run("Prints only the version when unavailable", func(t *testing.T) {
+		var buf bytes.Buffer
+		printVersionInfo(&buf, "Server", &pb.VersionInfo{ReleaseVersion: defaultVersionString})
+
+		if expected := "Server version: unavailable\n"; buf.String() != expected {
+			t.Fatalf("Expected output [%s], got [%s]", expected, buf.String())
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package install
+
+// RemoteAccessTemplate provides the service account that the service mirrors
+// of other clusters watch the services of a cluster with. It's the output of
+// `linkerd multicluster allow`, applied to the cluster whose services are
+// mirrored.
+const RemoteAccessTemplate = `### Service Mirror Remote Access ###
+---
+kind: ServiceAccount
+apiVersion: v1
+metadata:
+  name: {{.ServiceAccountName}}
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+
+---
+kind: ClusterRole
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-{{.Namespace}}-{{.ServiceAccountName}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+rules:
+- apiGroups: [""]
+  resources: ["services"]
+  verbs: ["list", "get", "watch"]
+
+---
+kind: ClusterRoleBinding
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-{{.Namespace}}-{{.ServiceAccountName}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: linkerd-{{.Namespace}}-{{.ServiceAccountName}}
+subjects:
+- kind: ServiceAccount
+  name: {{.ServiceAccountName}}
+  namespace: {{.Namespace}}
+`
+
+// LinkTemplate provides the Link of a remote cluster, the secret with its
+// credentials, and the service mirror that mirrors its services. It's the
+// output of `linkerd multicluster link`, applied to the cluster that the
+// services are mirrored into.
+const LinkTemplate = `### Link ###
+---
+kind: Secret
+apiVersion: v1
+metadata:
+  name: {{.CredentialsSecret}}
+  namespace: {{.Namespace}}
+  labels:
+    {{.RemoteClusterNameLabel}}: {{.ClusterName}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+type: mirror.linkerd.io/remote-kubeconfig
+data:
+  {{.CredentialsKey}}: {{.Kubeconfig}}
+
+---
+kind: Link
+apiVersion: linkerd.io/v1alpha1
+metadata:
+  name: {{.ClusterName}}
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  targetClusterName: {{.ClusterName}}
+  targetClusterDomain: {{.ClusterDomain}}
+  clusterCredentialsSecret: {{.CredentialsSecret}}
+  gatewayAddress: {{.GatewayAddress}}
+  gatewayPort: {{.GatewayPort}}
+  {{- if .GatewayIdentity }}
+  gatewayIdentity: {{.GatewayIdentity}}
+  {{- end }}
+  selector:
+    {{- if .Selector }}
+    matchLabels:
+      {{- range $key, $value := .Selector }}
+      {{$key}}: "{{$value}}"
+      {{- end }}
+    {{- else }} {}
+    {{- end }}
+  probeSpec:
+    path: {{.ProbePath}}
+    port: {{.ProbePort}}
+    period: {{.ProbePeriod}}
+
+### Service Mirror RBAC ###
+---
+kind: ServiceAccount
+apiVersion: v1
+metadata:
+  name: {{.ServiceMirrorName}}
+  namespace: {{.Namespace}}
+  labels:
+    {{.RemoteClusterNameLabel}}: {{.ClusterName}}
+
+---
+kind: ClusterRole
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-{{.Namespace}}-{{.ServiceMirrorName}}
+  labels:
+    {{.RemoteClusterNameLabel}}: {{.ClusterName}}
+rules:
+- apiGroups: [""]
+  resources: ["services"]
+  verbs: ["list", "get", "watch", "create", "update", "delete"]
+- apiGroups: [""]
+  resources: ["endpoints"]
+  verbs: ["get", "create", "update"]
+- apiGroups: [""]
+  resources: ["namespaces"]
+  verbs: ["list", "get", "watch"]
+
+---
+kind: ClusterRoleBinding
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-{{.Namespace}}-{{.ServiceMirrorName}}
+  labels:
+    {{.RemoteClusterNameLabel}}: {{.ClusterName}}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: linkerd-{{.Namespace}}-{{.ServiceMirrorName}}
+subjects:
+- kind: ServiceAccount
+  name: {{.ServiceMirrorName}}
+  namespace: {{.Namespace}}
+
+---
+kind: Role
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: {{.ServiceMirrorName}}
+  namespace: {{.Namespace}}
+  labels:
+    {{.RemoteClusterNameLabel}}: {{.ClusterName}}
+rules:
+- apiGroups: ["linkerd.io"]
+  resources: ["links"]
+  resourceNames: ["{{.ClusterName}}"]
+  verbs: ["get"]
+- apiGroups: [""]
+  resources: ["secrets"]
+  resourceNames: ["{{.CredentialsSecret}}"]
+  verbs: ["get"]
+
+---
+kind: RoleBinding
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: {{.ServiceMirrorName}}
+  namespace: {{.Namespace}}
+  labels:
+    {{.RemoteClusterNameLabel}}: {{.ClusterName}}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: {{.ServiceMirrorName}}
+subjects:
+- kind: ServiceAccount
+  name: {{.ServiceMirrorName}}
+  namespace: {{.Namespace}}
+
+### Service Mirror ###
+---
+kind: Deployment
+apiVersion: apps/v1
+metadata:
+  name: {{.ServiceMirrorName}}
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: service-mirror
+    {{.RemoteClusterNameLabel}}: {{.ClusterName}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      {{.ControllerComponentLabel}}: service-mirror
+      {{.RemoteClusterNameLabel}}: {{.ClusterName}}
+  template:
+    metadata:
+      labels:
+        {{.ControllerComponentLabel}}: service-mirror
+        {{.RemoteClusterNameLabel}}: {{.ClusterName}}
+      annotations:
+        {{.CreatedByAnnotation}}: {{.CliVersion}}
+    spec:
+      serviceAccountName: {{.ServiceMirrorName}}
+      containers:
+      - name: service-mirror
+        ports:
+        - name: admin-http
+          containerPort: 9999
+        image: {{.ControllerImage}}
+        imagePullPolicy: {{.ImagePullPolicy}}
+        args:
+        - "service-mirror"
+        - "-controller-namespace={{.Namespace}}"
+        - "-link-name={{.ClusterName}}"
+        - "-log-level={{.ControllerLogLevel}}"
+        livenessProbe:
+          httpGet:
+            path: /ping
+            port: 9999
+          initialDelaySeconds: 10
+        readinessProbe:
+          httpGet:
+            path: /ready
+            port: 9999
+          failureThreshold: 7
+        securityContext:
+          runAsUser: {{.ControllerUID}}
+`
@@ -0,0 +1,55 @@
+package install
+
+// ProbeTemplate provides the prober deployment of a synthetic probe. It's the
+// output of `linkerd probe create`, which is injected with `linkerd inject`
+// before it's applied, so that the proxies of the prober record the golden
+// metrics of the synthetic requests.
+const ProbeTemplate = `### Synthetic Probe ###
+---
+kind: Deployment
+apiVersion: apps/v1
+metadata:
+  name: {{.ProberName}}
+  namespace: {{.Namespace}}
+  labels:
+    {{.ProbeNameLabel}}: {{.ProbeName}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      {{.ProbeNameLabel}}: {{.ProbeName}}
+  template:
+    metadata:
+      labels:
+        {{.ProbeNameLabel}}: {{.ProbeName}}
+      annotations:
+        {{.CreatedByAnnotation}}: {{.CliVersion}}
+    spec:
+      containers:
+      - name: prober
+        ports:
+        - name: admin-http
+          containerPort: 9999
+        image: {{.ControllerImage}}
+        imagePullPolicy: {{.ImagePullPolicy}}
+        args:
+        - "prober"
+        - "-probe-name={{.ProbeName}}"
+        - "-urls={{.URLs}}"
+        - "-period={{.Period}}"
+        - "-log-level={{.ControllerLogLevel}}"
+        livenessProbe:
+          httpGet:
+            path: /ping
+            port: 9999
+          initialDelaySeconds: 10
+        readinessProbe:
+          httpGet:
+            path: /ready
+            port: 9999
+          failureThreshold: 7
+        securityContext:
+          runAsUser: {{.ControllerUID}}
+`
@@ -13,6 +13,21 @@ metadata:
   {{- end }}
 
 {{ end -}}
+### Config ###
+---
+kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: linkerd-config
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: controller
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+data:
+  config: |
+    {{.LinkerdConfig}}
+
 ### Service Account Controller ###
 ---
 kind: ServiceAccount
@@ -34,6 +49,9 @@ rules:
 - apiGroups: ["extensions", "apps"]
   resources: ["deployments", "replicasets"]
   verbs: ["list", "get", "watch"]
+- apiGroups: ["batch"]
+  resources: ["jobs", "cronjobs"]
+  verbs: ["list", "get", "watch"]
 - apiGroups: [""]
   resources: ["pods", "endpoints", "services", "replicationcontrollers"{{if not .SingleNamespace}}, "namespaces"{{end}}]
   verbs: ["list", "get", "watch"]
@@ -44,9 +62,23 @@ rules:
   verbs: ["list", "get", "watch"]
 {{- else }}
 - apiGroups: ["linkerd.io"]
-  resources: ["serviceprofiles"]
+  resources: ["serviceprofiles", "links"]
+  verbs: ["list", "get", "watch"]
+- apiGroups: ["split.smi-spec.io"]
+  resources: ["trafficsplits"]
+  verbs: ["list", "get", "watch"]
+{{- end }}
+{{- if .EnableTopologyAwareRouting }}
+- apiGroups: [""]
+  resources: ["nodes"]
   verbs: ["list", "get", "watch"]
 {{- end }}
+{{- if .EnableTLS }}
+- apiGroups: [""]
+  resources: ["configmaps"]
+  resourceNames: [{{.TLSTrustAnchorConfigMapName}}]
+  verbs: ["get"]
+{{- end }}
 
 ---
 kind: {{if not .SingleNamespace}}Cluster{{end}}RoleBinding
@@ -61,10 +93,57 @@ roleRef:
   kind: {{if not .SingleNamespace}}Cluster{{end}}Role
   name: linkerd-{{.Namespace}}-controller
 subjects:
+- kind: ServiceAccount
+  name: linkerd-controller
+  namespace: {{.Namespace}}
+{{- if .TapAPIService }}
+
+### Tap API RBAC ###
+---
+# the tap server authorizes the taps with SubjectAccessReviews
+kind: ClusterRoleBinding
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-{{.Namespace}}-tap-auth-delegator
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: system:auth-delegator
+subjects:
 - kind: ServiceAccount
   name: linkerd-controller
   namespace: {{.Namespace}}
 
+---
+# the tap server authenticates the Kubernetes API aggregator with the request
+# header configuration of the kube-system namespace
+kind: RoleBinding
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-{{.Namespace}}-tap-auth-reader
+  namespace: kube-system
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: extension-apiserver-authentication-reader
+subjects:
+- kind: ServiceAccount
+  name: linkerd-controller
+  namespace: {{.Namespace}}
+
+---
+# bind to the users and groups that can tap, in a namespace with a RoleBinding
+kind: ClusterRole
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-{{.Namespace}}-tap-admin
+rules:
+- apiGroups: ["{{.TapAPIGroup}}"]
+  resources: ["*"]
+  verbs: ["watch"]
+{{- end }}
+{{- if not .PrometheusURL }}
+
 ### Service Account Prometheus ###
 ---
 kind: ServiceAccount
@@ -103,6 +182,7 @@ subjects:
 - kind: ServiceAccount
   name: linkerd-prometheus
   namespace: {{.Namespace}}
+{{- end }}
 
 ### Controller ###
 ---
@@ -120,9 +200,15 @@ spec:
   selector:
     {{.ControllerComponentLabel}}: controller
   ports:
+  {{- if .APIAuth }}
+  - name: https
+    port: {{.PublicAPITLSPort}}
+    targetPort: {{.PublicAPITLSPort}}
+  {{- else }}
   - name: http
     port: 8085
     targetPort: 8085
+  {{- end }}
 
 ---
 kind: Service
@@ -142,6 +228,47 @@ spec:
   - name: grpc
     port: {{.ProxyAPIPort}}
     targetPort: {{.ProxyAPIPort}}
+{{- if .TapAPIService }}
+
+---
+kind: Service
+apiVersion: v1
+metadata:
+  name: linkerd-tap
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: controller
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  type: ClusterIP
+  selector:
+    {{.ControllerComponentLabel}}: controller
+  ports:
+  - name: https
+    port: 443
+    targetPort: {{.TapAPIPort}}
+
+---
+kind: APIService
+apiVersion: apiregistration.k8s.io/v1beta1
+metadata:
+  name: {{.TapAPIVersion}}.{{.TapAPIGroup}}
+  labels:
+    {{.ControllerComponentLabel}}: controller
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  group: {{.TapAPIGroup}}
+  version: {{.TapAPIVersion}}
+  service:
+    name: linkerd-tap
+    namespace: {{.Namespace}}
+  # the tap server generates its serving certificate when it starts
+  insecureSkipTLSVerify: true
+  groupPriorityMinimum: 1000
+  versionPriority: 100
+{{- end }}
 
 ---
 kind: Deployment
@@ -162,22 +289,104 @@ spec:
       annotations:
         {{.CreatedByAnnotation}}: {{.CliVersion}}
     spec:
+      {{- if .ControllerAntiAffinity }}
+      affinity:
+        podAntiAffinity:
+          {{- if eq .ControllerAntiAffinity "required" }}
+          requiredDuringSchedulingIgnoredDuringExecution:
+          {{- range .ControllerTopologyKeys }}
+          - labelSelector:
+              matchLabels:
+                {{$.ControllerComponentLabel}}: controller
+            topologyKey: {{.}}
+          {{- end }}
+          {{- else }}
+          preferredDuringSchedulingIgnoredDuringExecution:
+          {{- range .ControllerTopologyKeys }}
+          - weight: 100
+            podAffinityTerm:
+              labelSelector:
+                matchLabels:
+                  {{$.ControllerComponentLabel}}: controller
+              topologyKey: {{.}}
+          {{- end }}
+          {{- end }}
+      {{- end }}
+      {{- template "placement" . }}
       serviceAccountName: linkerd-controller
+      {{- if or .PrometheusBearerTokenSecret .PrometheusCASecret .APIAuth }}
+      volumes:
+      {{- if .PrometheusBearerTokenSecret }}
+      - name: prometheus-token
+        secret:
+          secretName: {{.PrometheusBearerTokenSecret}}
+      {{- end }}
+      {{- if .PrometheusCASecret }}
+      - name: prometheus-ca
+        secret:
+          secretName: {{.PrometheusCASecret}}
+      {{- end }}
+      {{- if .APIAuth }}
+      # the public API authenticates its TLS clients with its own TLS identity
+      - name: public-api-trust-anchors
+        configMap:
+          name: {{.TLSTrustAnchorConfigMapName}}
+      - name: public-api-identity
+        secret:
+          secretName: {{.PublicAPITLSSecret}}
+      {{- end }}
+      {{- end }}
       containers:
       - name: public-api
         ports:
+        {{- if .APIAuth }}
+        - name: https
+          containerPort: {{.PublicAPITLSPort}}
+        {{- else }}
         - name: http
           containerPort: 8085
+        {{- end }}
         - name: admin-http
           containerPort: 9995
         image: {{.ControllerImage}}
         imagePullPolicy: {{.ImagePullPolicy}}
         args:
         - "public-api"
+        {{- if .PrometheusURL }}
+        - "-prometheus-url={{.PrometheusURL}}"
+        {{- else }}
         - "-prometheus-url=http://linkerd-prometheus.{{.Namespace}}.svc.cluster.local:9090"
+        {{- end }}
+        {{- if .PrometheusFederationURLs }}
+        - "-prometheus-federation-urls={{.PrometheusFederationURLs}}"
+        {{- end }}
+        {{- if .PrometheusBearerTokenSecret }}
+        - "-prometheus-bearer-token-file=/var/run/linkerd/prometheus-token/token"
+        {{- end }}
+        {{- if .PrometheusCASecret }}
+        - "-prometheus-ca-file=/var/run/linkerd/prometheus-ca/ca.crt"
+        {{- end }}
+        {{- if .APIAuth }}
+        - "-tls-addr=:{{.PublicAPITLSPort}}"
+        - "-tls-cert-file=/var/linkerd-io/identity/{{.TLSCertPEMFileName}}"
+        - "-tls-key-file=/var/linkerd-io/identity/{{.TLSPrivateKeyPEMFileName}}"
+        - "-tls-trust-anchors-file=/var/linkerd-io/trust-anchors/{{.TLSTrustAnchorFileName}}"
+        - "-tls-client-identity={{.APIClientTLSIdentity}}"
+        {{- if .EnableHA }}
+        # the taps are only routed across the replicas over mutual TLS, with
+        # the identity of the public API
+        - "-enable-tap-routing=true"
+        - "-tap-port={{.TapTLSPort}}"
+        - "-tls-identity={{.PublicAPITLSIdentity}}"
+        {{- end }}
+        {{- end }}
+        {{- if .EventWebhookURL }}
+        - "-event-webhook-url={{.EventWebhookURL}}"
+        {{- end }}
         - "-controller-namespace={{.Namespace}}"
         - "-single-namespace={{.SingleNamespace}}"
         - "-log-level={{.ControllerLogLevel}}"
+        - "-log-format={{.ControllerLogFormat}}"
         livenessProbe:
           httpGet:
             path: /ping
@@ -196,6 +405,27 @@ spec:
         {{- end }}
         securityContext:
           runAsUser: {{.ControllerUID}}
+        {{- if or .PrometheusBearerTokenSecret .PrometheusCASecret .APIAuth }}
+        volumeMounts:
+        {{- if .PrometheusBearerTokenSecret }}
+        - name: prometheus-token
+          mountPath: /var/run/linkerd/prometheus-token
+          readOnly: true
+        {{- end }}
+        {{- if .PrometheusCASecret }}
+        - name: prometheus-ca
+          mountPath: /var/run/linkerd/prometheus-ca
+          readOnly: true
+        {{- end }}
+        {{- if .APIAuth }}
+        - name: public-api-trust-anchors
+          mountPath: /var/linkerd-io/trust-anchors
+          readOnly: true
+        - name: public-api-identity
+          mountPath: /var/linkerd-io/identity
+          readOnly: true
+        {{- end }}
+        {{- end }}
       - name: proxy-api
         ports:
         - name: grpc
@@ -211,7 +441,14 @@ spec:
         - "-single-namespace={{.SingleNamespace}}"
         - "-enable-tls={{.EnableTLS}}"
         - "-enable-h2-upgrade={{.EnableH2Upgrade}}"
+        {{- if .EnableTopologyAwareRouting }}
+        - "-enable-topology-aware-routing=true"
+        {{- end }}
+        {{- if .MetricPodLabels }}
+        - "-metric-pod-labels={{.MetricPodLabels}}"
+        {{- end }}
         - "-log-level={{.ControllerLogLevel}}"
+        - "-log-format={{.ControllerLogFormat}}"
         livenessProbe:
           httpGet:
             path: /ping
@@ -234,15 +471,34 @@ spec:
         ports:
         - name: grpc
           containerPort: 8088
+        {{- if and .EnableHA .APIAuth }}
+        - name: grpc-tls
+          containerPort: {{.TapTLSPort}}
+        {{- end }}
         - name: admin-http
           containerPort: 9998
+        {{- if .TapAPIService }}
+        - name: tap-api
+          containerPort: {{.TapAPIPort}}
+        {{- end }}
         image: {{.ControllerImage}}
         imagePullPolicy: {{.ImagePullPolicy}}
         args:
         - "tap"
+        {{- if and .EnableHA .APIAuth }}
+        - "-tls-addr=:{{.TapTLSPort}}"
+        - "-tls-cert-file=/var/linkerd-io/identity/{{.TLSCertPEMFileName}}"
+        - "-tls-key-file=/var/linkerd-io/identity/{{.TLSPrivateKeyPEMFileName}}"
+        - "-tls-trust-anchors-file=/var/linkerd-io/trust-anchors/{{.TLSTrustAnchorFileName}}"
+        - "-tls-client-identity={{.PublicAPITLSIdentity}}"
+        {{- end }}
+        {{- if .TapAPIService }}
+        - "-apiserver-addr=:{{.TapAPIPort}}"
+        {{- end }}
         - "-controller-namespace={{.Namespace}}"
         - "-single-namespace={{.SingleNamespace}}"
         - "-log-level={{.ControllerLogLevel}}"
+        - "-log-format={{.ControllerLogFormat}}"
         livenessProbe:
           httpGet:
             path: /ping
@@ -261,96 +517,37 @@ spec:
         {{- end }}
         securityContext:
           runAsUser: {{.ControllerUID}}
+        {{- if and .EnableHA .APIAuth }}
+        volumeMounts:
+        - name: public-api-trust-anchors
+          mountPath: /var/linkerd-io/trust-anchors
+          readOnly: true
+        - name: public-api-identity
+          mountPath: /var/linkerd-io/identity
+          readOnly: true
+        {{- end }}
+{{- if .ControllerMaxUnavailable }}
 
-{{- if not .SingleNamespace }}
-### Service Profile CRD ###
+### Controller Pod Disruption Budget ###
 ---
-apiVersion: apiextensions.k8s.io/v1beta1
-kind: CustomResourceDefinition
+kind: PodDisruptionBudget
+apiVersion: policy/v1beta1
 metadata:
-  name: serviceprofiles.linkerd.io
+  name: linkerd-controller
   namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: controller
   annotations:
     {{.CreatedByAnnotation}}: {{.CliVersion}}
 spec:
-  group: linkerd.io
-  version: v1alpha1
-  scope: Namespaced
-  names:
-    plural: serviceprofiles
-    singular: serviceprofile
-    kind: ServiceProfile
-    shortNames:
-    - sp
-  validation:
-    openAPIV3Schema:
-      properties:
-        spec:
-          required:
-          - routes
-          properties:
-            routes:
-              type: array
-              items:
-                type: object
-                required:
-                - name
-                - condition
-                properties:
-                  name:
-                    type: string
-                  condition:
-                    type: object
-                    minProperties: 1
-                    properties:
-                      method:
-                        type: string
-                      pathRegex:
-                        type: string
-                      all:
-                        type: array
-                        items:
-                          type: object
-                      any:
-                        type: array
-                        items:
-                          type: object
-                      not:
-                        type: object
-                  responseClasses:
-                    type: array
-                    items:
-                      type: object
-                      required:
-                      - condition
-                      properties:
-                        isFailure:
-                          type: boolean
-                        condition:
-                          type: object
-                          properties:
-                            status:
-                              type: object
-                              minProperties: 1
-                              properties:
-                                min:
-                                  type: integer
-                                  minimum: 100
-                                  maximum: 599
-                                max:
-                                  type: integer
-                                  minimum: 100
-                                  maximum: 599
-                            all:
-                              type: array
-                              items:
-                                type: object
-                            any:
-                              type: array
-                              items:
-                                type: object
-                            not:
-                              type: object
+  maxUnavailable: {{.ControllerMaxUnavailable}}
+  selector:
+    matchLabels:
+      {{.ControllerComponentLabel}}: controller
+{{- end }}
+
+{{- if not (or .SingleNamespace .SkipCRDs) }}
+{{ template "crds" . }}
 {{- end }}
 
 ### Service Account Web ###
@@ -413,12 +610,30 @@ spec:
         image: {{.WebImage}}
         imagePullPolicy: {{.ImagePullPolicy}}
         args:
+        {{- if .APIAuth }}
+        - "-api-addr=linkerd-controller-api.{{.Namespace}}.svc.cluster.local:{{.PublicAPITLSPort}}"
+        - "-api-tls-cert-file=/var/linkerd-io/identity/{{.TLSCertPEMFileName}}"
+        - "-api-tls-key-file=/var/linkerd-io/identity/{{.TLSPrivateKeyPEMFileName}}"
+        - "-api-tls-trust-anchors-file=/var/linkerd-io/trust-anchors/{{.TLSTrustAnchorFileName}}"
+        - "-api-tls-server-identity={{.PublicAPITLSIdentity}}"
+        {{- else }}
         - "-api-addr=linkerd-controller-api.{{.Namespace}}.svc.cluster.local:8085"
+        {{- end }}
         - "-grafana-addr=linkerd-grafana.{{.Namespace}}.svc.cluster.local:3000"
+        {{- if .GrafanaURL }}
+        - "-grafana-url={{.GrafanaURL}}"
+        {{- end }}
+        {{- if .EnforcedHost }}
+        - {{printf "-enforced-host=%s" .EnforcedHost | printf "%q"}}
+        {{- end }}
         - "-uuid={{.UUID}}"
+        {{- if .DisableVersionCheck }}
+        - "-version-check-url="
+        {{- end }}
         - "-controller-namespace={{.Namespace}}"
         - "-single-namespace={{.SingleNamespace}}"
         - "-log-level={{.ControllerLogLevel}}"
+        - "-log-format={{.ControllerLogFormat}}"
         livenessProbe:
           httpGet:
             path: /ping
@@ -437,7 +652,29 @@ spec:
         {{- end }}
         securityContext:
           runAsUser: {{.ControllerUID}}
+        {{- if .APIAuth }}
+        volumeMounts:
+        - name: api-client-trust-anchors
+          mountPath: /var/linkerd-io/trust-anchors
+          readOnly: true
+        - name: api-client-identity
+          mountPath: /var/linkerd-io/identity
+          readOnly: true
+        {{- end }}
+      {{- template "placement" . }}
       serviceAccountName: linkerd-web
+      {{- if .APIAuth }}
+      # the dashboard authenticates to the public API with the API client
+      # identity
+      volumes:
+      - name: api-client-trust-anchors
+        configMap:
+          name: {{.TLSTrustAnchorConfigMapName}}
+      - name: api-client-identity
+        secret:
+          secretName: {{.APIClientTLSSecret}}
+      {{- end }}
+{{- if not .PrometheusURL }}
 
 ### Prometheus ###
 ---
@@ -478,6 +715,7 @@ spec:
       annotations:
         {{.CreatedByAnnotation}}: {{.CliVersion}}
     spec:
+      {{- template "placement" . }}
       serviceAccountName: linkerd-prometheus
       volumes:
       - name: {{.PrometheusVolumeName}}
@@ -485,6 +723,11 @@ spec:
       - name: prometheus-config
         configMap:
           name: linkerd-prometheus-config
+      {{- if .PrometheusRemoteWriteSecret }}
+      - name: prometheus-remote-write
+        secret:
+          secretName: {{.PrometheusRemoteWriteSecret}}
+      {{- end }}
       containers:
       - name: prometheus
         ports:
@@ -496,11 +739,16 @@ spec:
         - name: prometheus-config
           mountPath: /etc/prometheus
           readOnly: true
+        {{- if .PrometheusRemoteWriteSecret }}
+        - name: prometheus-remote-write
+          mountPath: /var/run/linkerd/prometheus-remote-write
+          readOnly: true
+        {{- end }}
         image: {{.PrometheusImage}}
         imagePullPolicy: {{.ImagePullPolicy}}
         args:
         - "--storage.tsdb.path=/{{.PrometheusVolumeName}}"
-        - "--storage.tsdb.retention=6h"
+        - "--storage.tsdb.retention={{.PrometheusRetention}}"
         - "--config.file=/etc/prometheus/prometheus.yml"
         readinessProbe:
           httpGet:
@@ -615,7 +863,31 @@ data:
       # foo=bar
       - action: labelmap
         regex: __meta_kubernetes_pod_label_linkerd_io_(.+)
+      {{- if .MetricPodLabelNames }}
+      # copy the pod labels allowed by --metric-pod-labels:
+      # __meta_kubernetes_pod_label_version=v2 =>
+      # version=v2
+      - action: labelmap
+        regex: __meta_kubernetes_pod_label_({{.MetricPodLabelNames}})
+      {{- end }}
+    {{- if .PrometheusRemoteWriteURLs }}
+
+    # ship the metrics to long-term storage
+    remote_write:
+    {{- range .PrometheusRemoteWriteURLs }}
+    - url: '{{.}}'
+      {{- if $.PrometheusRemoteWriteSecret }}
+      bearer_token_file: /var/run/linkerd/prometheus-remote-write/token
+      {{- end }}
+    {{- end }}
+    {{- end }}
+{{- end }}
+`
 
+// GrafanaTemplate provides the grafana add-on, which is installed unless
+// linkerd is installed with `--grafana=false`: the Grafana instance that serves
+// the dashboards of the web UI, from the metrics of Prometheus.
+const GrafanaTemplate = `
 ### Service Account Grafana ###
 ---
 kind: ServiceAccount
@@ -690,7 +962,7 @@ spec:
         - name: grafana-config
           mountPath: /etc/grafana
           readOnly: true
-        image: {{.GrafanaImage}}
+        image: {{.Values.image}}
         imagePullPolicy: {{.ImagePullPolicy}}
         livenessProbe:
           httpGet:
@@ -709,6 +981,7 @@ spec:
         {{- end }}
         securityContext:
           runAsUser: 472
+      {{- template "placement" . }}
       serviceAccountName: linkerd-grafana
 
 ---
@@ -748,7 +1021,7 @@ data:
       type: prometheus
       access: proxy
       orgId: 1
-      url: http://linkerd-prometheus.{{.Namespace}}.svc.cluster.local:9090
+      url: {{if .PrometheusURL}}{{.PrometheusURL}}{{else}}http://linkerd-prometheus.{{.Namespace}}.svc.cluster.local:9090{{end}}
       isDefault: true
       jsonData:
         timeInterval: "5s"
@@ -796,6 +1069,12 @@ rules:
   resources: ["configmaps"]
   resourceNames: [{{.TLSTrustAnchorConfigMapName}}]
   verbs: ["update"]
+{{- if .CALeaderElection }}
+- apiGroups: [""]
+  resources: ["configmaps"]
+  resourceNames: [linkerd-ca-leader]
+  verbs: ["get", "update"]
+{{- end }}
 - apiGroups: [""]
   resources: ["pods"]
   verbs: ["list", "get", "watch"]
@@ -805,6 +1084,11 @@ rules:
 - apiGroups: [""]
   resources: ["secrets"]
   verbs: ["create", "update"]
+{{- if not .SingleNamespace }}
+- apiGroups: [""]
+  resources: ["namespaces"]
+  verbs: ["get"]
+{{- end }}
 {{- if and .EnableTLS .ProxyAutoInjectEnabled }}
 - apiGroups: ["admissionregistration.k8s.io"]
   resources: ["mutatingwebhookconfigurations"]
@@ -827,20 +1111,64 @@ subjects:
 - kind: ServiceAccount
   name: linkerd-ca
   namespace: {{.Namespace}}
+{{- if .TLSIssuerSecret }}
 
-### CA ###
 ---
-kind: Deployment
-apiVersion: extensions/v1beta1
+kind: Role
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-ca-issuer
+  namespace: {{.Namespace}}
+rules:
+- apiGroups: [""]
+  resources: ["secrets"]
+  resourceNames: [{{.TLSIssuerSecret}}]
+  verbs: ["list", "get", "watch"]
+
+---
+kind: RoleBinding
+apiVersion: rbac.authorization.k8s.io/v1beta1
 metadata:
+  name: linkerd-ca-issuer
+  namespace: {{.Namespace}}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: linkerd-ca-issuer
+subjects:
+- kind: ServiceAccount
   name: linkerd-ca
   namespace: {{.Namespace}}
-  labels:
-    {{.ControllerComponentLabel}}: ca
+{{- if .TLSIssuerKey }}
+
+---
+kind: Secret
+apiVersion: v1
+metadata:
+  name: {{.TLSIssuerSecret}}
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+type: kubernetes.io/tls
+data:
+  tls.crt: {{.TLSIssuerCertificate}}
+  tls.key: {{.TLSIssuerKey}}
+{{- end }}
+{{- end }}
+
+### CA ###
+---
+kind: Deployment
+apiVersion: extensions/v1beta1
+metadata:
+  name: linkerd-ca
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: ca
   annotations:
     {{.CreatedByAnnotation}}: {{.CliVersion}}
 spec:
-  replicas: 1
+  replicas: {{if .CALeaderElection}}{{.ControllerReplicas}}{{else}}1{{end}}
   template:
     metadata:
       labels:
@@ -848,6 +1176,7 @@ spec:
       annotations:
         {{.CreatedByAnnotation}}: {{.CliVersion}}
     spec:
+      {{- template "placement" . }}
       serviceAccountName: linkerd-ca
       containers:
       - name: ca
@@ -863,7 +1192,27 @@ spec:
         {{- if and .EnableTLS .ProxyAutoInjectEnabled }}
         - "-proxy-auto-inject={{ .ProxyAutoInjectEnabled }}"
         {{- end }}
+        {{- if .TLSIssuerSecret }}
+        - "-issuer-secret={{.TLSIssuerSecret}}"
+        {{- end }}
+        {{- with .TLSIssuerVault }}
+        - "-vault-addr={{.Addr}}"
+        - "-vault-pki-path={{.PKIPath}}"
+        - "-vault-role={{.Role}}"
+        - "-vault-auth-path={{.AuthPath}}"
+        - "-vault-auth-role={{.AuthRole}}"
+        {{- end }}
+        {{- if .CALeaderElection }}
+        - "-enable-leader-election=true"
+        {{- end }}
+        {{- if .APIAuth }}
+        - "-issue-api-client-cert=true"
+        {{- end }}
+        {{- if .EventWebhookURL }}
+        - "-event-webhook-url={{.EventWebhookURL}}"
+        {{- end }}
         - "-log-level={{.ControllerLogLevel}}"
+        - "-log-format={{.ControllerLogFormat}}"
         livenessProbe:
           httpGet:
             path: /ping
@@ -909,6 +1258,7 @@ spec:
       annotations:
         {{.CreatedByAnnotation}}: {{.CliVersion}}
     spec:
+      {{- template "placement" . }}
       serviceAccountName: linkerd-proxy-injector
       containers:
       - name: proxy-injector
@@ -918,6 +1268,15 @@ spec:
         - "proxy-injector"
         - "-controller-namespace={{.Namespace}}"
         - "-log-level={{.ControllerLogLevel}}"
+        - "-log-format={{.ControllerLogFormat}}"
+        - "-failure-policy={{.ProxyInjectorFailurePolicy}}"
+        - "-namespace-selector={{.ProxyInjectorNamespaceSelector}}"
+        {{- if .ProxyNetworkPolicies }}
+        - "-network-policies"
+        {{- end }}
+        {{- if .EventWebhookURL }}
+        - "-event-webhook-url={{.EventWebhookURL}}"
+        {{- end }}
         ports:
         - name: proxy-injector
           containerPort: 8443
@@ -975,6 +1334,20 @@ rules:
 - apiGroups: ["admissionregistration.k8s.io"]
   resources: ["mutatingwebhookconfigurations"]
   verbs: ["create", "update", "get", "watch"]
+- apiGroups: ["admissionregistration.k8s.io"]
+  resources: ["validatingwebhookconfigurations"]
+  verbs: ["create", "update", "get"]
+- apiGroups: [""]
+  resources: ["namespaces"]
+  verbs: ["get"]
+- apiGroups: [""]
+  resources: ["events"]
+  verbs: ["create", "patch"]
+{{- if .ProxyNetworkPolicies }}
+- apiGroups: ["networking.k8s.io"]
+  resources: ["networkpolicies"]
+  verbs: ["create", "get", "update"]
+{{- end }}
 
 ---
 kind: ClusterRoleBinding
@@ -1053,7 +1426,7 @@ data:
   {{.ProxySpecFileName}}: |
     env:
     - name: LINKERD2_PROXY_LOG
-      value: warn,linkerd2_proxy=info
+      value: {{.ProxyLogLevel}}
     - name: LINKERD2_PROXY_BIND_TIMEOUT
       value: {{.ProxyBindTimeout}}
     - name: LINKERD2_PROXY_CONTROL_URL
@@ -1122,6 +1495,16 @@ data:
     - mountPath: /var/linkerd-io/identity
       name: linkerd-secrets
       readOnly: true
+  {{.DebugSpecFileName}}: |
+    image: {{.DebugImage}}
+    imagePullPolicy: IfNotPresent
+    name: {{.DebugContainerName}}
+    securityContext:
+      capabilities:
+        add:
+        - NET_ADMIN
+        - NET_RAW
+    terminationMessagePolicy: FallbackToLogsOnError
   {{.TLSTrustAnchorVolumeSpecFileName}}: |
     name: linkerd-trust-anchors
     configMap:
@@ -1133,3 +1516,841 @@ data:
       secretName: "" # this value will be computed by the webhook
       optional: true
 `
+
+// NetworkPolicyTemplate provides least-privilege NetworkPolicies for the
+// control plane namespace when linkerd is installed with
+// `--control-plane-network-policies`. Only ingress is restricted; egress is left
+// open so that components can reach the Kubernetes API and DNS. With an
+// external Prometheus, whose pods can't be selected, the metrics ports are open
+// to every namespace.
+const NetworkPolicyTemplate = `
+### Control Plane Network Policies ###
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-default-deny
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector: {}
+  policyTypes:
+  - Ingress
+
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-proxy-admin
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector:
+    matchExpressions:
+    - key: {{.ControllerComponentLabel}}
+      operator: Exists
+  policyTypes:
+  - Ingress
+  ingress:
+  # proxy metrics are scraped by prometheus
+  - from:
+    {{- if .PrometheusURL }}
+    - namespaceSelector: {}
+    {{- else }}
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: prometheus
+    {{- end }}
+    ports:
+    - protocol: TCP
+      port: {{.ProxyMetricsPort}}
+  # proxies are tapped by the controller's tap server
+  - from:
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: controller
+    ports:
+    - protocol: TCP
+      port: {{.ProxyControlPort}}
+
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-controller
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector:
+    matchLabels:
+      {{.ControllerComponentLabel}}: controller
+  policyTypes:
+  - Ingress
+  ingress:
+  # the public API is reached by the web dashboard, and by the CLI through the
+  # Kubernetes API server proxy, whose source address cannot be selected
+  - ports:
+    - protocol: TCP
+      {{- if .APIAuth }}
+      port: {{.PublicAPITLSPort}}
+      {{- else }}
+      port: 8085
+      {{- end }}
+  # the proxy API is reached by every meshed proxy in the cluster
+  - from:
+    - namespaceSelector: {}
+    ports:
+    - protocol: TCP
+      port: {{.ProxyAPIPort}}
+  {{- if and .EnableHA .APIAuth }}
+  # with several replicas, the public API taps each pod through the tap server
+  # of the replica that it's routed to
+  - from:
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: controller
+    ports:
+    - protocol: TCP
+      port: {{.TapTLSPort}}
+  {{- end }}
+  {{- if .TapAPIService }}
+  # the tap API is reached by the Kubernetes API aggregator, whose source
+  # address cannot be selected
+  - ports:
+    - protocol: TCP
+      port: {{.TapAPIPort}}
+  {{- end }}
+  - from:
+    {{- if .PrometheusURL }}
+    - namespaceSelector: {}
+    {{- else }}
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: prometheus
+    {{- end }}
+    ports:
+    - protocol: TCP
+      port: 9995
+    - protocol: TCP
+      port: 9996
+    - protocol: TCP
+      port: 9998
+
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-web
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector:
+    matchLabels:
+      {{.ControllerComponentLabel}}: web
+  policyTypes:
+  - Ingress
+  ingress:
+  # the dashboard is reached through the Kubernetes API server proxy
+  - ports:
+    - protocol: TCP
+      port: 8084
+  - from:
+    {{- if .PrometheusURL }}
+    - namespaceSelector: {}
+    {{- else }}
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: prometheus
+    {{- end }}
+    ports:
+    - protocol: TCP
+      port: 9994
+{{- if not .PrometheusURL }}
+
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-prometheus
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector:
+    matchLabels:
+      {{.ControllerComponentLabel}}: prometheus
+  policyTypes:
+  - Ingress
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: controller
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: grafana
+    ports:
+    - protocol: TCP
+      port: 9090
+{{- end }}
+{{- if index .AddOns "grafana" }}
+
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-grafana
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector:
+    matchLabels:
+      {{.ControllerComponentLabel}}: grafana
+  policyTypes:
+  - Ingress
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: web
+    {{- if .PrometheusURL }}
+    - namespaceSelector: {}
+    {{- else }}
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: prometheus
+    {{- end }}
+    ports:
+    - protocol: TCP
+      port: 3000
+{{- end }}
+{{- if .EnableTLS }}
+
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-ca
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector:
+    matchLabels:
+      {{.ControllerComponentLabel}}: ca
+  policyTypes:
+  - Ingress
+  ingress:
+  - from:
+    {{- if .PrometheusURL }}
+    - namespaceSelector: {}
+    {{- else }}
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: prometheus
+    {{- end }}
+    ports:
+    - protocol: TCP
+      port: 9997
+{{- if .ProxyAutoInjectEnabled }}
+
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-proxy-injector
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector:
+    matchLabels:
+      {{.ControllerComponentLabel}}: proxy-injector
+  policyTypes:
+  - Ingress
+  ingress:
+  # admission requests originate from the Kubernetes API server
+  - ports:
+    - protocol: TCP
+      port: 8443
+{{- end }}
+{{- end }}
+`
+
+// TracingTemplate provides the tracing add-on when linkerd is installed with
+// `--tracing`: an OpenCensus collector, which applications send their spans
+// to, and a Jaeger instance that the collector exports the spans to and that
+// shows them.
+const TracingTemplate = `
+### Service Account Collector ###
+---
+kind: ServiceAccount
+apiVersion: v1
+metadata:
+  name: linkerd-collector
+  namespace: {{.Namespace}}
+
+### Collector ###
+---
+kind: Service
+apiVersion: v1
+metadata:
+  name: linkerd-collector
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: collector
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  type: ClusterIP
+  selector:
+    {{.ControllerComponentLabel}}: collector
+  ports:
+  - name: opencensus
+    port: 55678
+    targetPort: 55678
+
+---
+kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: linkerd-collector-config
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: collector
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+data:
+  collector.yaml: |-
+    receivers:
+      opencensus:
+        port: 55678
+    queued-exporters:
+      jaeger:
+        num-workers: 4
+        queue-size: 100
+        retry-on-failure: true
+        sender-type: jaeger-thrift-http
+        jaeger-thrift-http:
+          collector-endpoint: http://linkerd-jaeger.{{.Namespace}}.svc.cluster.local:14268/api/traces
+          timeout: 5s
+
+---
+kind: Deployment
+apiVersion: extensions/v1beta1
+metadata:
+  name: linkerd-collector
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: collector
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  replicas: 1
+  template:
+    metadata:
+      labels:
+        {{.ControllerComponentLabel}}: collector
+      annotations:
+        {{.CreatedByAnnotation}}: {{.CliVersion}}
+    spec:
+      volumes:
+      - name: collector-config
+        configMap:
+          name: linkerd-collector-config
+      containers:
+      - name: collector
+        command:
+        - /occollector_linux
+        - --config=/conf/collector.yaml
+        ports:
+        - name: opencensus
+          containerPort: 55678
+        volumeMounts:
+        - name: collector-config
+          mountPath: /conf
+          readOnly: true
+        image: {{.Values.collectorImage}}
+        imagePullPolicy: {{.ImagePullPolicy}}
+        livenessProbe:
+          httpGet:
+            path: /
+            port: 13133
+        readinessProbe:
+          httpGet:
+            path: /
+            port: 13133
+      {{- template "placement" . }}
+      serviceAccountName: linkerd-collector
+
+### Service Account Jaeger ###
+---
+kind: ServiceAccount
+apiVersion: v1
+metadata:
+  name: linkerd-jaeger
+  namespace: {{.Namespace}}
+
+### Jaeger ###
+---
+kind: Service
+apiVersion: v1
+metadata:
+  name: linkerd-jaeger
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: jaeger
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  type: ClusterIP
+  selector:
+    {{.ControllerComponentLabel}}: jaeger
+  ports:
+  - name: collection
+    port: 14268
+    targetPort: 14268
+  - name: ui
+    port: 16686
+    targetPort: 16686
+
+---
+kind: Deployment
+apiVersion: extensions/v1beta1
+metadata:
+  name: linkerd-jaeger
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: jaeger
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  replicas: 1
+  template:
+    metadata:
+      labels:
+        {{.ControllerComponentLabel}}: jaeger
+      annotations:
+        {{.CreatedByAnnotation}}: {{.CliVersion}}
+    spec:
+      containers:
+      - name: jaeger
+        ports:
+        - name: collection
+          containerPort: 14268
+        - name: ui
+          containerPort: 16686
+        image: {{.Values.jaegerImage}}
+        imagePullPolicy: {{.ImagePullPolicy}}
+        readinessProbe:
+          httpGet:
+            path: /
+            port: 14269
+      {{- template "placement" . }}
+      serviceAccountName: linkerd-jaeger
+{{- if .EnableNetworkPolicies }}
+
+### Tracing Network Policies ###
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-collector
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector:
+    matchLabels:
+      {{.ControllerComponentLabel}}: collector
+  policyTypes:
+  - Ingress
+  ingress:
+  # the spans are sent by every traced proxy in the cluster
+  - from:
+    - namespaceSelector: {}
+    ports:
+    - protocol: TCP
+      port: 55678
+
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-jaeger
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector:
+    matchLabels:
+      {{.ControllerComponentLabel}}: jaeger
+  policyTypes:
+  - Ingress
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: collector
+    ports:
+    - protocol: TCP
+      port: 14268
+  # the UI is reached through "kubectl port-forward", whose source address
+  # cannot be selected
+  - ports:
+    - protocol: TCP
+      port: 16686
+{{- end }}
+`
+
+// FlaggerTemplate provides the flagger add-on when linkerd is installed with
+// `--flagger`: the Flagger operator, which shifts the traffic of Canary
+// resources to their new versions with TrafficSplits, as long as their success
+// rate and latency in Prometheus stay within their thresholds.
+const FlaggerTemplate = `
+### Service Account Flagger ###
+---
+kind: ServiceAccount
+apiVersion: v1
+metadata:
+  name: linkerd-flagger
+  namespace: {{.Namespace}}
+
+### Flagger RBAC ###
+---
+kind: ClusterRole
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-{{.Namespace}}-flagger
+rules:
+- apiGroups: [""]
+  resources: ["configmaps", "secrets", "services", "events"]
+  verbs: ["*"]
+- apiGroups: ["apps"]
+  resources: ["deployments"]
+  verbs: ["*"]
+- apiGroups: ["autoscaling"]
+  resources: ["horizontalpodautoscalers"]
+  verbs: ["*"]
+- apiGroups: ["flagger.app"]
+  resources: ["canaries", "canaries/status"]
+  verbs: ["*"]
+- apiGroups: ["split.smi-spec.io"]
+  resources: ["trafficsplits"]
+  verbs: ["*"]
+
+---
+kind: ClusterRoleBinding
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-{{.Namespace}}-flagger
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: linkerd-{{.Namespace}}-flagger
+subjects:
+- kind: ServiceAccount
+  name: linkerd-flagger
+  namespace: {{.Namespace}}
+
+### Flagger ###
+---
+kind: Deployment
+apiVersion: extensions/v1beta1
+metadata:
+  name: linkerd-flagger
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: flagger
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  replicas: 1
+  template:
+    metadata:
+      labels:
+        {{.ControllerComponentLabel}}: flagger
+      annotations:
+        {{.CreatedByAnnotation}}: {{.CliVersion}}
+    spec:
+      containers:
+      - name: flagger
+        ports:
+        - name: http
+          containerPort: 8080
+        image: {{.Values.image}}
+        imagePullPolicy: {{.ImagePullPolicy}}
+        args:
+        - "-log-level={{.Values.logLevel}}"
+        - "-mesh-provider=linkerd"
+        - "-metrics-server={{if .PrometheusURL}}{{.PrometheusURL}}{{else}}http://linkerd-prometheus.{{.Namespace}}.svc.cluster.local:9090{{end}}"
+        livenessProbe:
+          httpGet:
+            path: /healthz
+            port: 8080
+        readinessProbe:
+          httpGet:
+            path: /healthz
+            port: 8080
+      {{- template "placement" . }}
+      serviceAccountName: linkerd-flagger
+
+### Canary CRD ###
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: canaries.flagger.app
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  group: flagger.app
+  version: v1alpha3
+  scope: Namespaced
+  names:
+    plural: canaries
+    singular: canary
+    kind: Canary
+  subresources:
+    status: {}
+`
+
+// PlacementTemplate provides the node selector, the tolerations and the
+// priority class of the control plane pods, which are set with the
+// --control-plane-* flags of `linkerd install`. It's included in the spec of
+// each pod template, as "placement".
+const PlacementTemplate = `
+{{- if .NodeSelector }}
+      nodeSelector:
+      {{- range $key, $value := .NodeSelector }}
+        {{$key}}: "{{$value}}"
+      {{- end }}
+{{- end }}
+{{- if .Tolerations }}
+      tolerations:
+      {{- range .Tolerations }}
+      - key: {{.Key}}
+        operator: {{.Operator}}
+        {{- if .Value }}
+        value: "{{.Value}}"
+        {{- end }}
+        {{- if .Effect }}
+        effect: {{.Effect}}
+        {{- end }}
+      {{- end }}
+{{- end }}
+{{- if .PriorityClassName }}
+      priorityClassName: {{.PriorityClassName}}
+{{- end }}`
+
+// CRDTemplate provides the custom resource definitions of Linkerd. They're
+// part of the output of `linkerd install`, unless --skip-crds is set, and are
+// also the output of `linkerd upgrade --crds`, so that they can be managed on
+// their own.
+const CRDTemplate = `### Service Profile CRD ###
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: serviceprofiles.linkerd.io
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  group: linkerd.io
+  version: v1alpha1
+  scope: Namespaced
+  names:
+    plural: serviceprofiles
+    singular: serviceprofile
+    kind: ServiceProfile
+    shortNames:
+    - sp
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          required:
+          - routes
+          properties:
+            routes:
+              type: array
+              items:
+                type: object
+                required:
+                - name
+                - condition
+                properties:
+                  name:
+                    type: string
+                  condition:
+                    type: object
+                    minProperties: 1
+                    properties:
+                      method:
+                        type: string
+                      pathRegex:
+                        type: string
+                      all:
+                        type: array
+                        items:
+                          type: object
+                      any:
+                        type: array
+                        items:
+                          type: object
+                      not:
+                        type: object
+                  responseClasses:
+                    type: array
+                    items:
+                      type: object
+                      required:
+                      - condition
+                      properties:
+                        isFailure:
+                          type: boolean
+                        condition:
+                          type: object
+                          properties:
+                            status:
+                              type: object
+                              minProperties: 1
+                              properties:
+                                min:
+                                  type: integer
+                                  minimum: 100
+                                  maximum: 599
+                                max:
+                                  type: integer
+                                  minimum: 100
+                                  maximum: 599
+                            all:
+                              type: array
+                              items:
+                                type: object
+                            any:
+                              type: array
+                              items:
+                                type: object
+                            not:
+                              type: object
+                  isRetryable:
+                    type: boolean
+            retryBudget:
+              type: object
+              required:
+              - retryRatio
+              - minRetriesPerSecond
+              properties:
+                retryRatio:
+                  type: number
+                  minimum: 0
+                  maximum: 1
+                minRetriesPerSecond:
+                  type: integer
+                  minimum: 0
+                  maximum: 100
+                ttl:
+                  type: string
+
+### Link CRD ###
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: links.linkerd.io
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  group: linkerd.io
+  version: v1alpha1
+  scope: Namespaced
+  names:
+    plural: links
+    singular: link
+    kind: Link
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          required:
+          - targetClusterName
+          - targetClusterDomain
+          - clusterCredentialsSecret
+          - gatewayAddress
+          - gatewayPort
+          properties:
+            targetClusterName:
+              type: string
+            targetClusterDomain:
+              type: string
+            clusterCredentialsSecret:
+              type: string
+            gatewayAddress:
+              type: string
+            gatewayPort:
+              type: integer
+              minimum: 1
+              maximum: 65535
+            gatewayIdentity:
+              type: string
+            selector:
+              type: object
+            probeSpec:
+              type: object
+              properties:
+                path:
+                  type: string
+                port:
+                  type: integer
+                  minimum: 1
+                  maximum: 65535
+                period:
+                  type: string
+
+### TrafficSplit CRD ###
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: trafficsplits.split.smi-spec.io
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  group: split.smi-spec.io
+  version: v1alpha1
+  scope: Namespaced
+  names:
+    plural: trafficsplits
+    singular: trafficsplit
+    kind: TrafficSplit
+    shortNames:
+    - ts
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          required:
+          - service
+          - backends
+          properties:
+            service:
+              type: string
+            backends:
+              type: array
+              items:
+                type: object
+                required:
+                - service
+                - weight
+                properties:
+                  service:
+                    type: string`
@@ -7,6 +7,9 @@ import (
 )
 
 func main() {
+	if ok, code := cmd.RunPlugin(os.Args[1:]); ok {
+		os.Exit(code)
+	}
 	if err := cmd.RootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
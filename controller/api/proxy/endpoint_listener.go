@@ -7,6 +7,7 @@ import (
 	net "github.com/linkerd/linkerd2-proxy-api/go/net"
 	"github.com/linkerd/linkerd2/pkg/addr"
 	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/util"
 	log "github.com/sirupsen/logrus"
 	coreV1 "k8s.io/api/core/v1"
 )
@@ -22,13 +23,23 @@ type endpointUpdateListener interface {
 
 type ownerKindAndNameFn func(*coreV1.Pod) (string, string)
 
-// updateAddress is a pairing of TCP address to Kubernetes pod object
+// updateAddress is a pairing of TCP address to Kubernetes pod object. The pod
+// is nil for addresses that are not backed by a pod, such as the resolved
+// targets of ExternalName services. The hostname is only set for endpoints
+// that are addressable by name, such as the pods of a StatefulSet. The weight
+// is only set for the endpoints of the backends of a traffic split; otherwise
+// all the endpoints have the same weight.
 type updateAddress struct {
-	address *net.TcpAddress
-	pod     *coreV1.Pod
+	address  *net.TcpAddress
+	pod      *coreV1.Pod
+	hostname string
+	weight   uint32
 }
 
 func (ua updateAddress) String() string {
+	if ua.pod == nil {
+		return fmt.Sprintf("{address:%v}", addr.ProxyAddressToString(ua.address))
+	}
 	return fmt.Sprintf("{address:%v, pod:%s.%s}", addr.ProxyAddressToString(ua.address), ua.pod.Namespace, ua.pod.Name)
 }
 
@@ -70,6 +81,7 @@ type endpointListener struct {
 	stream           pb.Destination_GetServer
 	ownerKindAndName ownerKindAndNameFn
 	labels           map[string]string
+	metricPodLabels  []string
 	enableH2Upgrade  bool
 	enableTLS        bool
 	stopCh           chan struct{}
@@ -78,12 +90,14 @@ type endpointListener struct {
 func newEndpointListener(
 	stream pb.Destination_GetServer,
 	ownerKindAndName ownerKindAndNameFn,
+	metricPodLabels []string,
 	enableTLS, enableH2Upgrade bool,
 ) *endpointListener {
 	return &endpointListener{
 		stream:           stream,
 		ownerKindAndName: ownerKindAndName,
 		labels:           make(map[string]string),
+		metricPodLabels:  metricPodLabels,
 		enableH2Upgrade:  enableH2Upgrade,
 		enableTLS:        enableTLS,
 		stopCh:           make(chan struct{}),
@@ -160,11 +174,23 @@ func (l *endpointListener) toWeightedAddrSet(addresses []*updateAddress) *pb.Wei
 }
 
 func (l *endpointListener) toWeightedAddr(address *updateAddress) *pb.WeightedAddr {
-	labels, hint, tlsIdentity := l.getAddrMetadata(address.pod)
+	weight := uint32(1)
+	if address.weight > 0 {
+		weight = address.weight
+	}
+
+	if address.pod == nil {
+		return &pb.WeightedAddr{
+			Addr:   address.address,
+			Weight: weight,
+		}
+	}
+
+	labels, hint, tlsIdentity := l.getAddrMetadata(address.pod, address.address.GetPort())
 
 	return &pb.WeightedAddr{
 		Addr:         address.address,
-		Weight:       1,
+		Weight:       weight,
 		MetricLabels: labels,
 		TlsIdentity:  tlsIdentity,
 		ProtocolHint: hint,
@@ -179,11 +205,19 @@ func (l *endpointListener) toAddrSet(addresses []*updateAddress) *pb.AddrSet {
 	return &pb.AddrSet{Addrs: addrs}
 }
 
-func (l *endpointListener) getAddrMetadata(pod *coreV1.Pod) (map[string]string, *pb.ProtocolHint, *pb.TlsIdentity) {
+func (l *endpointListener) getAddrMetadata(pod *coreV1.Pod, port uint32) (map[string]string, *pb.ProtocolHint, *pb.TlsIdentity) {
 	controllerNs := pod.Labels[pkgK8s.ControllerNSLabel]
 	ownerKind, ownerName := l.ownerKindAndName(pod)
 	labels := pkgK8s.GetPodLabels(ownerKind, ownerName, pod)
 
+	// The pod's labels in the metric labels allow-list never override the
+	// labels Linkerd sets.
+	for name, value := range pkgK8s.GetPodMetricLabels(pod, l.metricPodLabels) {
+		if _, ok := labels[name]; !ok {
+			labels[name] = value
+		}
+	}
+
 	var hint *pb.ProtocolHint
 
 	// If the pod is controlled by us, then it can be hinted that this destination
@@ -191,7 +225,11 @@ func (l *endpointListener) getAddrMetadata(pod *coreV1.Pod) (map[string]string,
 	// does not verify that the pod's control plane matches the control plane
 	// where the destination service is running; all pods injected for all control
 	// planes are considered valid for providing the H2 hint.
-	if l.enableH2Upgrade && controllerNs != "" {
+	//
+	// Opaque ports are never hinted, as upgrading their traffic to H2 would
+	// require the proxy to detect its protocol. The TLS identity is still
+	// provided for them.
+	if l.enableH2Upgrade && controllerNs != "" && !isOpaquePort(pod, port) {
 		hint = &pb.ProtocolHint{
 			Protocol: &pb.ProtocolHint_H2_{
 				H2: &pb.ProtocolHint_H2{},
@@ -219,3 +257,25 @@ func (l *endpointListener) getAddrMetadata(pod *coreV1.Pod) (map[string]string,
 		},
 	}
 }
+
+// isOpaquePort returns true if the pod's ProxyOpaquePortsAnnotation includes
+// the given port.
+func isOpaquePort(pod *coreV1.Pod, port uint32) bool {
+	annotation, ok := pod.Annotations[pkgK8s.ProxyOpaquePortsAnnotation]
+	if !ok {
+		return false
+	}
+
+	ranges, err := util.ParsePortRanges(annotation)
+	if err != nil {
+		log.Warnf("invalid %s annotation on pod %s.%s: %s", pkgK8s.ProxyOpaquePortsAnnotation, pod.Name, pod.Namespace, err)
+		return false
+	}
+
+	for _, pr := range ranges {
+		if pr.Contains(port) {
+			return true
+		}
+	}
+	return false
+}
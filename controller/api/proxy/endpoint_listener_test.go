@@ -289,6 +289,92 @@ func TestEndpointListener(t *testing.T) {
 			t.Fatalf("Expected no TlsIdentity to be sent, but got [%v]", addrs[0].TlsIdentity)
 		}
 	})
+
+	t.Run("Does not send the H2 hint for opaque ports", func(t *testing.T) {
+		opaquePod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod1",
+				Namespace: "this-namespace",
+				Labels: map[string]string{
+					pkgK8s.ControllerNSLabel:    "linkerd-namespace",
+					pkgK8s.ProxyDeploymentLabel: "pod-deployment",
+				},
+				Annotations: map[string]string{
+					pkgK8s.ProxyOpaquePortsAnnotation: "2-10",
+				},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+			},
+		}
+
+		mockGetServer := &mockDestinationGetServer{updatesReceived: []*pb.Update{}}
+		listener := &endpointListener{
+			ownerKindAndName: defaultOwnerKindAndName,
+			stream:           mockGetServer,
+			enableTLS:        true,
+			enableH2Upgrade:  true,
+		}
+
+		add := []*updateAddress{
+			&updateAddress{address: addedAddress1, pod: opaquePod},
+			&updateAddress{address: addedAddress2, pod: opaquePod},
+		}
+		listener.Update(add, nil)
+
+		addrs := mockGetServer.updatesReceived[0].GetAdd().GetAddrs()
+		if len(addrs) != 2 {
+			t.Fatalf("Expected [2] addresses returned, got %v", addrs)
+		}
+
+		if addrs[0].GetProtocolHint().GetH2() == nil {
+			t.Fatalf("Expected the H2 hint to be sent for port 1, but got [%v]", addrs[0].GetProtocolHint())
+		}
+		if addrs[1].GetProtocolHint() != nil {
+			t.Fatalf("Expected no hint to be sent for opaque port 2, but got [%v]", addrs[1].GetProtocolHint())
+		}
+		if addrs[1].GetTlsIdentity() == nil {
+			t.Fatal("Expected TlsIdentity to be sent for opaque port 2")
+		}
+	})
+
+	t.Run("Sends the pod labels in the metric labels allow-list", func(t *testing.T) {
+		labeledPod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod1",
+				Namespace: "this-namespace",
+				Labels: map[string]string{
+					"team":                      "emoji",
+					"app.kubernetes.io/version": "v2",
+					"tier":                      "backend",
+					"pod":                       "not-pod1",
+				},
+			},
+		}
+
+		mockGetServer := &mockDestinationGetServer{updatesReceived: []*pb.Update{}}
+		listener := &endpointListener{
+			ownerKindAndName: func(pod *v1.Pod) (string, string) { return "deployment", "pod-deployment" },
+			metricPodLabels:  []string{"team", "app.kubernetes.io/version", "pod"},
+			stream:           mockGetServer,
+		}
+
+		add := []*updateAddress{
+			&updateAddress{address: addedAddress1, pod: labeledPod},
+		}
+		listener.Update(add, nil)
+
+		actualMetricLabels := mockGetServer.updatesReceived[0].GetAdd().Addrs[0].MetricLabels
+		expectedMetricLabels := map[string]string{
+			"pod":                       "pod1",
+			"deployment":                "pod-deployment",
+			"team":                      "emoji",
+			"app_kubernetes_io_version": "v2",
+		}
+		if !reflect.DeepEqual(actualMetricLabels, expectedMetricLabels) {
+			t.Fatalf("Expected metric labels sent to be [%v] but was [%v]", expectedMetricLabels, actualMetricLabels)
+		}
+	})
 }
 
 func checkAddress(t *testing.T, addr *pb.WeightedAddr, expectedAddress *net.TcpAddress) {
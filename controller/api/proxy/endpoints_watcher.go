@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	net "github.com/linkerd/linkerd2-proxy-api/go/net"
 	"github.com/linkerd/linkerd2/controller/k8s"
@@ -25,10 +26,21 @@ const (
 // cluster.  Listeners can subscribe to a particular service and port and
 // endpointsWatcher will publish the address set and all future changes for
 // that service:port.
+//
+// Endpoints changes are not published as they arrive. Instead, each
+// servicePort snapshots the latest endpoints at most once per debounce
+// interval and publishes a single batched diff to its listeners, so that the
+// work done per listener is bounded regardless of how often the endpoints of a
+// large service change.
 type endpointsWatcher struct {
 	serviceLister  corelisters.ServiceLister
 	endpointLister corelisters.EndpointsLister
 	podLister      corelisters.PodLister
+	// used to resolve the targets of ExternalName services
+	lookupHost      lookupHostFn
+	externalNameTTL time.Duration
+	// the minimum interval between two endpoints updates for a service port
+	debounce time.Duration
 	// a map of service -> service port -> servicePort
 	servicePorts map[serviceID]map[uint32]*servicePort
 	// This mutex protects the servicePorts data structure (nested map) itself
@@ -37,13 +49,15 @@ type endpointsWatcher struct {
 	mutex sync.RWMutex
 }
 
-func newEndpointsWatcher(k8sAPI *k8s.API) *endpointsWatcher {
+func newEndpointsWatcher(k8sAPI *k8s.API, externalNameTTL, debounce time.Duration) *endpointsWatcher {
 	watcher := &endpointsWatcher{
-		serviceLister:  k8sAPI.Svc().Lister(),
-		endpointLister: k8sAPI.Endpoint().Lister(),
-		podLister:      k8sAPI.Pod().Lister(),
-		servicePorts:   make(map[serviceID]map[uint32]*servicePort),
-		mutex:          sync.RWMutex{},
+		serviceLister:   k8sAPI.Svc().Lister(),
+		endpointLister:  k8sAPI.Endpoint().Lister(),
+		podLister:       k8sAPI.Pod().Lister(),
+		externalNameTTL: externalNameTTL,
+		debounce:        debounce,
+		servicePorts:    make(map[serviceID]map[uint32]*servicePort),
+		mutex:           sync.RWMutex{},
 	}
 
 	k8sAPI.Svc().Informer().AddEventHandler(
@@ -105,20 +119,13 @@ func (e *endpointsWatcher) subscribe(service *serviceID, port uint32, listener e
 			log.Errorf("Error getting endpoints: %s", err)
 			return err
 		}
-		svcPort = newServicePort(svc, endpoints, port, e.podLister)
+		svcPort = newServicePort(svc, endpoints, port, e.podLister, e.lookupHost, e.externalNameTTL, e.debounce)
 		svcPorts[port] = svcPort
 	}
 
-	exists := true
-	if svc == nil || svc.Spec.Type == v1.ServiceTypeExternalName {
-		// XXX: The proxy will use DNS to discover the service if it is told
-		// the service doesn't exist. An external service is represented in DNS
-		// as a CNAME, which the proxy will correctly resolve. Thus, there's no
-		// benefit (yet) to distinguishing between "the service exists but it
-		// is an ExternalName service so use DNS anyway" and "the service does
-		// not exist."
-		exists = false
-	}
+	// ExternalName services are considered to exist; their addresses are
+	// resolved via DNS by the servicePort and pushed to the listener.
+	exists := svc != nil
 
 	svcPort.subscribe(exists, listener)
 	return nil
@@ -249,6 +256,10 @@ func (e *endpointsWatcher) updateEndpoints(oldObj, newObj interface{}) {
 // listeners may be subscribed to a servicePort.  servicePort maintains the
 // current state of the address set and publishes diffs to all listeners when
 // updates come from either the endpoints API or the service API.
+//
+// Updates from the endpoints API are debounced: the first change schedules a
+// flush after the debounce interval, and all changes received until then are
+// coalesced into the snapshot taken by that flush.
 type servicePort struct {
 	// these values are immutable properties of the servicePort
 	service serviceID
@@ -259,13 +270,34 @@ type servicePort struct {
 	targetPort intstr.IntOrString
 	addresses  []*updateAddress
 	podLister  corelisters.PodLister
+	// externalName is only set for ExternalName services, whose addresses are
+	// resolved via DNS rather than the endpoints API.
+	externalName         string
+	externalNameResolver *externalNameResolver
+	lookupHost           lookupHostFn
+	externalNameTTL      time.Duration
+	// headless is set for headless services, whose clients connect to the
+	// addresses of the endpoints directly, on the service port.
+	headless bool
+	// debounce is the minimum interval between two flushes of endpoints
+	// updates; if it is zero, updates are published immediately.
+	debounce   time.Duration
+	flushTimer *time.Timer
 	// This mutex protects against concurrent modification of the listeners slice
 	// as well as prevents updates for occuring while the listeners slice is being
 	// modified.
 	mutex sync.Mutex
 }
 
-func newServicePort(service *v1.Service, endpoints *v1.Endpoints, port uint32, podLister corelisters.PodLister) *servicePort {
+func newServicePort(
+	service *v1.Service,
+	endpoints *v1.Endpoints,
+	port uint32,
+	podLister corelisters.PodLister,
+	lookupHost lookupHostFn,
+	externalNameTTL time.Duration,
+	debounce time.Duration,
+) *servicePort {
 	id := serviceID{}
 	if service != nil {
 		id.namespace = service.Namespace
@@ -275,17 +307,25 @@ func newServicePort(service *v1.Service, endpoints *v1.Endpoints, port uint32, p
 	targetPort := getTargetPort(service, port)
 
 	sp := &servicePort{
-		service:    id,
-		listeners:  make([]endpointUpdateListener, 0),
-		port:       port,
-		endpoints:  endpoints,
-		targetPort: targetPort,
-		podLister:  podLister,
-		mutex:      sync.Mutex{},
+		service:         id,
+		listeners:       make([]endpointUpdateListener, 0),
+		port:            port,
+		endpoints:       endpoints,
+		targetPort:      targetPort,
+		podLister:       podLister,
+		lookupHost:      lookupHost,
+		externalNameTTL: externalNameTTL,
+		headless:        isHeadless(service),
+		debounce:        debounce,
+		mutex:           sync.Mutex{},
+	}
+
+	if name := getExternalName(service); name != "" {
+		sp.addresses = sp.startExternalName(name)
+	} else {
+		sp.addresses = sp.endpointsToAddresses(endpoints, targetPort)
 	}
 
-	sp.addresses = sp.endpointsToAddresses(endpoints, targetPort)
-
 	return sp
 }
 
@@ -293,14 +333,57 @@ func (sp *servicePort) updateEndpoints(newEndpoints *v1.Endpoints) {
 	sp.mutex.Lock()
 	defer sp.mutex.Unlock()
 
-	sp.updateAddresses(newEndpoints, sp.targetPort)
 	sp.endpoints = newEndpoints
+	if sp.externalName != "" {
+		return
+	}
+
+	if sp.debounce <= 0 {
+		sp.updateAddresses(newEndpoints, sp.targetPort)
+		return
+	}
+	if sp.flushTimer == nil {
+		sp.flushTimer = time.AfterFunc(sp.debounce, sp.flush)
+	}
+}
+
+// flush publishes a snapshot of the latest endpoints to all listeners.
+func (sp *servicePort) flush() {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	if sp.flushTimer == nil {
+		// the pending update was canceled while waiting on the lock
+		return
+	}
+	sp.flushTimer = nil
+
+	if sp.externalName != "" {
+		return
+	}
+	sp.updateAddresses(sp.endpoints, sp.targetPort)
+}
+
+// cancelFlush discards a pending endpoints update. It must be called with the
+// mutex held, whenever the pending update is superseded.
+func (sp *servicePort) cancelFlush() {
+	if sp.flushTimer != nil {
+		sp.flushTimer.Stop()
+		sp.flushTimer = nil
+	}
 }
 
 func (sp *servicePort) deleteEndpoints() {
 	sp.mutex.Lock()
 	defer sp.mutex.Unlock()
 
+	sp.cancelFlush()
+
+	if sp.externalName != "" {
+		sp.endpoints = &v1.Endpoints{}
+		return
+	}
+
 	log.Debugf("Deleting %s:%d", sp.service, sp.port)
 
 	for _, listener := range sp.listeners {
@@ -315,14 +398,34 @@ func (sp *servicePort) updateService(newService *v1.Service) {
 	defer sp.mutex.Unlock()
 
 	newTargetPort := getTargetPort(newService, sp.port)
-	if newTargetPort != sp.targetPort {
+	newExternalName := getExternalName(newService)
+	newHeadless := isHeadless(newService)
+	headlessChanged := newHeadless != sp.headless
+	sp.headless = newHeadless
+
+	switch {
+	case newExternalName != "" && newExternalName != sp.externalName:
+		sp.cancelFlush()
+		sp.stopExternalName()
+		sp.publishAddresses(sp.startExternalName(newExternalName))
+	case newExternalName == "" && sp.externalName != "":
+		sp.stopExternalName()
+		sp.updateAddresses(sp.endpoints, newTargetPort)
+	case newExternalName == "" && (newTargetPort != sp.targetPort || headlessChanged):
+		// this also publishes any pending endpoints update
+		sp.cancelFlush()
 		sp.updateAddresses(sp.endpoints, newTargetPort)
-		sp.targetPort = newTargetPort
 	}
+	sp.targetPort = newTargetPort
 }
 
 func (sp *servicePort) updateAddresses(endpoints *v1.Endpoints, port intstr.IntOrString) {
-	newAddresses := sp.endpointsToAddresses(endpoints, port)
+	sp.publishAddresses(sp.endpointsToAddresses(endpoints, port))
+}
+
+// publishAddresses sends the difference between the current and the new
+// address set to all listeners, and records the new address set as current.
+func (sp *servicePort) publishAddresses(newAddresses []*updateAddress) {
 	if log.GetLevel() >= log.DebugLevel {
 		var s []string
 		for _, v := range newAddresses {
@@ -374,6 +477,11 @@ func (sp *servicePort) unsubscribe(listener endpointUpdateListener) (bool, int)
 			sp.listeners[i] = sp.listeners[len(sp.listeners)-1]
 			sp.listeners[len(sp.listeners)-1] = nil
 			sp.listeners = sp.listeners[:len(sp.listeners)-1]
+			if len(sp.listeners) == 0 {
+				// the servicePort is about to be discarded
+				sp.cancelFlush()
+				sp.stopExternalName()
+			}
 			return true, len(sp.listeners)
 		}
 	}
@@ -389,12 +497,54 @@ func (sp *servicePort) unsubscribeAll() {
 	for _, listener := range sp.listeners {
 		listener.Stop()
 	}
+	sp.cancelFlush()
+	sp.stopExternalName()
+}
+
+// startExternalName begins resolving the given DNS name on behalf of an
+// ExternalName service, and returns the initially resolved addresses. It must
+// be called with the mutex held, or before the servicePort is shared.
+func (sp *servicePort) startExternalName(name string) []*updateAddress {
+	log.Debugf("Resolving %s:%d via ExternalName %s", sp.service, sp.port, name)
+
+	var resolver *externalNameResolver
+	resolver = newExternalNameResolver(name, sp.externalNameTTL, sp.lookupHost, func(ips []string) {
+		sp.mutex.Lock()
+		defer sp.mutex.Unlock()
+
+		// the resolver may have been replaced or stopped while this update was
+		// waiting on the lock
+		if sp.externalNameResolver != resolver || resolver.stopped() {
+			return
+		}
+		sp.publishAddresses(sp.ipsToAddresses(ips))
+	})
+
+	sp.externalName = name
+	sp.externalNameResolver = resolver
+
+	addresses := sp.ipsToAddresses(resolver.resolve())
+	resolver.start()
+	return addresses
+}
+
+// stopExternalName stops DNS resolution for an ExternalName service, if it is
+// in progress.
+func (sp *servicePort) stopExternalName() {
+	if sp.externalNameResolver != nil {
+		sp.externalNameResolver.stop()
+	}
+	sp.externalName = ""
+	sp.externalNameResolver = nil
 }
 
 /// helpers ///
 
 func (sp *servicePort) endpointsToAddresses(endpoints *v1.Endpoints, targetPort intstr.IntOrString) []*updateAddress {
 	addrs := make([]*updateAddress, 0)
+	// the addresses of a pod that is listed in several subsets, such as the
+	// pods of headless services, are only published once
+	seen := make(map[string]struct{})
 
 	for _, subset := range endpoints.Subsets {
 		var portNum uint32
@@ -415,8 +565,25 @@ func (sp *servicePort) endpointsToAddresses(endpoints *v1.Endpoints, targetPort
 		}
 
 		for _, address := range subset.Addresses {
+			key := fmt.Sprintf("%s:%d", address.IP, portNum)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+
 			target := address.TargetRef
 			if target == nil {
+				// the endpoints of headless services without selectors are
+				// managed manually, and aren't necessarily backed by pods
+				if sp.headless {
+					if ip, err := addr.ParseProxyIPV4(address.IP); err == nil {
+						seen[key] = struct{}{}
+						addrs = append(addrs, &updateAddress{
+							address:  &net.TcpAddress{Ip: ip, Port: portNum},
+							hostname: address.Hostname,
+						})
+						continue
+					}
+				}
 				log.Errorf("Target not found for endpoint %v", address)
 				continue
 			}
@@ -435,17 +602,54 @@ func (sp *servicePort) endpointsToAddresses(endpoints *v1.Endpoints, targetPort
 				continue
 			}
 
+			seen[key] = struct{}{}
 			addrs = append(addrs, &updateAddress{
-				address: &net.TcpAddress{Ip: ip, Port: portNum},
-				pod:     pod,
+				address:  &net.TcpAddress{Ip: ip, Port: portNum},
+				pod:      pod,
+				hostname: address.Hostname,
 			})
 		}
 	}
 	return addrs
 }
 
+// ipsToAddresses converts the IPs resolved for an ExternalName service into
+// addresses on the service port. These addresses are not backed by pods.
+func (sp *servicePort) ipsToAddresses(ips []string) []*updateAddress {
+	addrs := make([]*updateAddress, 0)
+
+	for _, ipStr := range ips {
+		ip, err := addr.ParseProxyIPV4(ipStr)
+		if err != nil {
+			log.Debugf("[%s] skipping ExternalName address: not a valid IPV4 address", ipStr)
+			continue
+		}
+		addrs = append(addrs, &updateAddress{
+			address: &net.TcpAddress{Ip: ip, Port: sp.port},
+		})
+	}
+	return addrs
+}
+
+// getExternalName returns the DNS name targeted by the service if it is an
+// ExternalName service, and an empty string otherwise.
+func getExternalName(service *v1.Service) string {
+	if service == nil || service.Spec.Type != v1.ServiceTypeExternalName {
+		return ""
+	}
+	return service.Spec.ExternalName
+}
+
+// isHeadless returns true if the service is a headless service, i.e. one
+// without a cluster IP, whose DNS name resolves to the IPs of its endpoints.
+func isHeadless(service *v1.Service) bool {
+	return service != nil && service.Spec.ClusterIP == v1.ClusterIPNone
+}
+
 // getTargetPort returns the port specified as an argument if no service is
-// present. If the service is present and it has a port spec matching the
+// present, or if the service is headless, since the clients of headless
+// services connect to the IPs of the endpoints directly, without any port
+// translation. If the service is present and it has a port spec matching the
 // specified port and a target port configured, it returns the name of the
 // service's port (not the name of the target pod port), so that it can be
 // looked up in the the endpoints API response, which uses service port names.
@@ -453,7 +657,7 @@ func getTargetPort(service *v1.Service, port uint32) intstr.IntOrString {
 	// Use the specified port as the target port by default
 	targetPort := intstr.FromInt(int(port))
 
-	if service == nil {
+	if service == nil || isHeadless(service) {
 		return targetPort
 	}
 
@@ -1,14 +1,31 @@
 package proxy
 
 import (
+	"context"
+	"fmt"
 	"reflect"
 	"sort"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/addr"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
+func fakeLookupHost(host string) ([]string, error) {
+	switch host {
+	case "foo":
+		return []string{"10.10.10.2", "10.10.10.1", "fe80::1"}, nil
+	default:
+		return nil, fmt.Errorf("no such host: %s", host)
+	}
+}
+
 func TestEndpointsWatcher(t *testing.T) {
 	for _, tt := range []struct {
 		serviceType                      string
@@ -207,6 +224,111 @@ status:
 			expectedNoEndpoints:              false,
 			expectedNoEndpointsServiceExists: false,
 		},
+		{
+			serviceType: "headless services",
+			k8sConfigs: []string{`
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  namespace: ns
+spec:
+  clusterIP: None
+  ports:
+  - name: sql
+    port: 5432
+    targetPort: 15432`,
+				`
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: db
+  namespace: ns
+subsets:
+- addresses:
+  - ip: 172.17.0.30
+    hostname: db-0
+    targetRef:
+      kind: Pod
+      name: db-0
+      namespace: ns
+  ports:
+  - name: sql
+    port: 15432
+- addresses:
+  - ip: 172.17.0.30
+    hostname: db-0
+    targetRef:
+      kind: Pod
+      name: db-0
+      namespace: ns
+  - ip: 172.17.0.31
+    hostname: db-1
+    targetRef:
+      kind: Pod
+      name: db-1
+      namespace: ns
+  ports:
+  - name: sql
+    port: 15433`,
+				`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: db-0
+  namespace: ns
+status:
+  phase: Running
+  podIP: 172.17.0.30`,
+				`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: db-1
+  namespace: ns
+status:
+  phase: Running
+  podIP: 172.17.0.31`,
+			},
+			service: &serviceID{namespace: "ns", name: "db"},
+			port:    uint32(5432),
+			expectedAddresses: []string{
+				"172.17.0.30:5432",
+				"172.17.0.31:5432",
+			},
+			expectedNoEndpoints:              false,
+			expectedNoEndpointsServiceExists: false,
+		},
+		{
+			serviceType: "headless services without selectors",
+			k8sConfigs: []string{`
+apiVersion: v1
+kind: Service
+metadata:
+  name: legacy
+  namespace: ns
+spec:
+  clusterIP: None`,
+				`
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: legacy
+  namespace: ns
+subsets:
+- addresses:
+  - ip: 10.0.0.5
+  - ip: 10.0.0.6`,
+			},
+			service: &serviceID{namespace: "ns", name: "legacy"},
+			port:    uint32(9090),
+			expectedAddresses: []string{
+				"10.0.0.5:9090",
+				"10.0.0.6:9090",
+			},
+			expectedNoEndpoints:              false,
+			expectedNoEndpointsServiceExists: false,
+		},
 		{
 			serviceType: "local services with missing pods",
 			k8sConfigs: []string{`
@@ -293,11 +415,32 @@ spec:
   type: ExternalName
   externalName: foo`,
 			},
-			service:                          &serviceID{namespace: "ns", name: "name3"},
+			service: &serviceID{namespace: "ns", name: "name3"},
+			port:    uint32(6969),
+			expectedAddresses: []string{
+				"10.10.10.1:6969",
+				"10.10.10.2:6969",
+			},
+			expectedNoEndpoints:              false,
+			expectedNoEndpointsServiceExists: false,
+		},
+		{
+			serviceType: "external name services that do not resolve",
+			k8sConfigs: []string{`
+apiVersion: v1
+kind: Service
+metadata:
+  name: name5
+  namespace: ns
+spec:
+  type: ExternalName
+  externalName: bar`,
+			},
+			service:                          &serviceID{namespace: "ns", name: "name5"},
 			port:                             uint32(6969),
 			expectedAddresses:                []string{},
 			expectedNoEndpoints:              true,
-			expectedNoEndpointsServiceExists: false,
+			expectedNoEndpointsServiceExists: true,
 		},
 		{
 			serviceType:                      "services that do not yet exist",
@@ -315,7 +458,8 @@ spec:
 				t.Fatalf("NewFakeAPI returned an error: %s", err)
 			}
 
-			watcher := newEndpointsWatcher(k8sAPI)
+			watcher := newEndpointsWatcher(k8sAPI, time.Minute, 0)
+			watcher.lookupHost = fakeLookupHost
 
 			k8sAPI.Sync()
 
@@ -349,3 +493,164 @@ spec:
 		})
 	}
 }
+
+// countingListener counts the updates it receives; it's safe for concurrent use.
+type countingListener struct {
+	collectListener
+	updates uint64
+	mutex   sync.Mutex
+}
+
+func (c *countingListener) Update(add, remove []*updateAddress) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.updates++
+}
+
+func (c *countingListener) NoEndpoints(exists bool) {}
+
+func (c *countingListener) SetServiceID(id *serviceID) {}
+
+func (c *countingListener) count() uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.updates
+}
+
+func newCountingListener() *countingListener {
+	return &countingListener{collectListener: collectListener{context: context.Background(), stopCh: make(chan struct{})}}
+}
+
+// genLargeService builds a pod lister and an endpoints object with n ready
+// addresses for the service ns/name.
+func genLargeService(n int) (corelisters.PodLister, *v1.Endpoints) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	addresses := make([]v1.EndpointAddress, n)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("name-%d", i)
+		indexer.Add(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"}})
+		addresses[i] = v1.EndpointAddress{
+			IP:        fmt.Sprintf("10.%d.%d.%d", (i>>16)&0xff, (i>>8)&0xff, i&0xff),
+			TargetRef: &v1.ObjectReference{Kind: "Pod", Name: name, Namespace: "ns"},
+		}
+	}
+
+	endpoints := &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "ns"},
+		Subsets:    []v1.EndpointSubset{{Addresses: addresses}},
+	}
+	return corelisters.NewPodLister(indexer), endpoints
+}
+
+// churn returns a copy of endpoints without its i-th address, simulating a
+// pod being replaced.
+func churn(endpoints *v1.Endpoints, i int) *v1.Endpoints {
+	addresses := endpoints.Subsets[0].Addresses
+	i = i % len(addresses)
+	updated := make([]v1.EndpointAddress, 0, len(addresses)-1)
+	updated = append(updated, addresses[:i]...)
+	updated = append(updated, addresses[i+1:]...)
+	return &v1.Endpoints{
+		ObjectMeta: endpoints.ObjectMeta,
+		Subsets:    []v1.EndpointSubset{{Addresses: updated}},
+	}
+}
+
+func TestServicePortDebounce(t *testing.T) {
+	podLister, endpoints := genLargeService(100)
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "ns"}}
+
+	t.Run("Publishes every endpoints update without debouncing", func(t *testing.T) {
+		sp := newServicePort(service, endpoints, 8080, podLister, nil, 0, 0)
+		listener := newCountingListener()
+		sp.subscribe(true, listener)
+
+		for i := 0; i < 10; i++ {
+			sp.updateEndpoints(churn(endpoints, i))
+		}
+
+		// one update on subscription, and one per endpoints update
+		if listener.count() != 11 {
+			t.Fatalf("Expected 11 updates, got %d", listener.count())
+		}
+	})
+
+	t.Run("Coalesces endpoints updates into a single snapshot", func(t *testing.T) {
+		// the flush is triggered manually, so that the timer never fires
+		sp := newServicePort(service, endpoints, 8080, podLister, nil, 0, time.Hour)
+		listener, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		sp.subscribe(true, listener)
+
+		for i := 0; i < 10; i++ {
+			sp.updateEndpoints(churn(endpoints, i))
+		}
+		if len(listener.removed) != 0 {
+			t.Fatalf("Expected no updates before the flush, got %d removals", len(listener.removed))
+		}
+
+		sp.flush()
+
+		if len(listener.removed) != 1 || addr.ProxyAddressToString(listener.removed[0].address) != "10.0.0.9:8080" {
+			t.Fatalf("Expected only the latest snapshot to be published, got removals %v", listener.removed)
+		}
+		if len(listener.added) != 100 {
+			t.Fatalf("Expected only the initial 100 additions, got %d", len(listener.added))
+		}
+	})
+
+	t.Run("Discards pending updates when the endpoints are deleted", func(t *testing.T) {
+		sp := newServicePort(service, endpoints, 8080, podLister, nil, 0, time.Hour)
+		listener, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		sp.subscribe(true, listener)
+
+		sp.updateEndpoints(churn(endpoints, 0))
+		sp.deleteEndpoints()
+		sp.flush()
+
+		if !listener.noEndpointsCalled || listener.noEndpointsExists {
+			t.Fatalf("Expected NoEndpoints(false) to be called")
+		}
+		if len(listener.removed) != 0 {
+			t.Fatalf("Expected the pending update to be discarded, got removals %v", listener.removed)
+		}
+	})
+}
+
+// benchmarkServicePortUpdates measures the cost of handling endpoints events
+// for a service with 10k endpoints and 100 subscribed proxies, and reports
+// the number of updates each proxy received.
+func benchmarkServicePortUpdates(b *testing.B, debounce time.Duration) {
+	podLister, endpoints := genLargeService(10000)
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "ns"}}
+	sp := newServicePort(service, endpoints, 8080, podLister, nil, 0, debounce)
+
+	listeners := make([]*countingListener, 100)
+	for i := range listeners {
+		listeners[i] = newCountingListener()
+		sp.subscribe(true, listeners[i])
+	}
+
+	updates := make([]*v1.Endpoints, 100)
+	for i := range updates {
+		updates[i] = churn(endpoints, i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sp.updateEndpoints(updates[i%len(updates)])
+	}
+	b.StopTimer()
+
+	sp.unsubscribeAll()
+	b.Logf("%d endpoints events resulted in %d updates per listener", b.N, listeners[0].count()-1)
+}
+
+func BenchmarkServicePortUpdatesImmediate(b *testing.B) {
+	benchmarkServicePortUpdates(b, 0)
+}
+
+func BenchmarkServicePortUpdatesDebounced(b *testing.B) {
+	benchmarkServicePortUpdates(b, 100*time.Millisecond)
+}
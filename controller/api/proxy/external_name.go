@@ -0,0 +1,104 @@
+package proxy
+
+import (
+	"net"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultExternalNameTTL = 30 * time.Second
+
+// lookupHostFn resolves a DNS name to the list of IP addresses it points to.
+type lookupHostFn func(host string) ([]string, error)
+
+// externalNameResolver periodically resolves the DNS name targeted by an
+// ExternalName service. The Go resolver does not expose record TTLs, so
+// resolved addresses are treated as valid for a fixed TTL, after which the
+// name is resolved again and onUpdate is invoked if the address set changed.
+type externalNameResolver struct {
+	host       string
+	ttl        time.Duration
+	lookupHost lookupHostFn
+	onUpdate   func(ips []string)
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	lastIPs  []string
+}
+
+func newExternalNameResolver(
+	host string,
+	ttl time.Duration,
+	lookupHost lookupHostFn,
+	onUpdate func(ips []string),
+) *externalNameResolver {
+	if ttl <= 0 {
+		ttl = defaultExternalNameTTL
+	}
+	if lookupHost == nil {
+		lookupHost = net.LookupHost
+	}
+
+	return &externalNameResolver{
+		host:       host,
+		ttl:        ttl,
+		lookupHost: lookupHost,
+		onUpdate:   onUpdate,
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// resolve performs a synchronous lookup of the host and returns the sorted
+// list of resolved IPs. Lookup failures are logged and result in an empty
+// address set.
+func (r *externalNameResolver) resolve() []string {
+	ips, err := r.lookupHost(r.host)
+	if err != nil {
+		log.Warnf("Failed to resolve ExternalName %s: %s", r.host, err)
+		return []string{}
+	}
+	sort.Strings(ips)
+	r.lastIPs = ips
+	return ips
+}
+
+// start re-resolves the host every TTL until stop is called.
+func (r *externalNameResolver) start() {
+	go func() {
+		ticker := time.NewTicker(r.ttl)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				previous := r.lastIPs
+				ips := r.resolve()
+				if !reflect.DeepEqual(previous, ips) {
+					log.Debugf("ExternalName %s resolved to %v", r.host, ips)
+					r.onUpdate(ips)
+				}
+			case <-r.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+func (r *externalNameResolver) stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+	})
+}
+
+func (r *externalNameResolver) stopped() bool {
+	select {
+	case <-r.stopCh:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,70 @@
+package proxy
+
+import (
+	"github.com/linkerd/linkerd2/pkg/addr"
+)
+
+// hostnameListener wraps an endpointUpdateListener and only forwards updates
+// for endpoints with a given hostname. It's used to resolve destinations that
+// address an individual pod of a service, e.g.
+// "pod-0.service.namespace.svc.cluster.local" for StatefulSet pods.
+type hostnameListener struct {
+	endpointUpdateListener
+	hostname string
+	// the addresses matching the hostname that have been sent to the listener
+	matched     map[string]struct{}
+	initialized bool
+}
+
+func newHostnameListener(listener endpointUpdateListener, hostname string) *hostnameListener {
+	return &hostnameListener{
+		endpointUpdateListener: listener,
+		hostname:               hostname,
+		matched:                make(map[string]struct{}),
+	}
+}
+
+func (l *hostnameListener) Update(add, remove []*updateAddress) {
+	filteredAdd := make([]*updateAddress, 0)
+	for _, a := range add {
+		if a.hostname == l.hostname {
+			filteredAdd = append(filteredAdd, a)
+		}
+	}
+
+	filteredRemove := make([]*updateAddress, 0)
+	for _, a := range remove {
+		if _, ok := l.matched[addr.ProxyAddressToString(a.address)]; ok {
+			filteredRemove = append(filteredRemove, a)
+		}
+	}
+
+	for _, a := range filteredRemove {
+		delete(l.matched, addr.ProxyAddressToString(a.address))
+	}
+	for _, a := range filteredAdd {
+		l.matched[addr.ProxyAddressToString(a.address)] = struct{}{}
+	}
+
+	wasInitialized := l.initialized
+	l.initialized = true
+
+	if len(l.matched) == 0 {
+		// The service has endpoints, but none of them match the hostname. Only
+		// notify the listener if this changes what it was previously told.
+		if !wasInitialized || len(filteredRemove) > 0 {
+			l.endpointUpdateListener.NoEndpoints(true)
+		}
+		return
+	}
+
+	if len(filteredAdd) > 0 || len(filteredRemove) > 0 {
+		l.endpointUpdateListener.Update(filteredAdd, filteredRemove)
+	}
+}
+
+func (l *hostnameListener) NoEndpoints(exists bool) {
+	l.matched = make(map[string]struct{})
+	l.initialized = true
+	l.endpointUpdateListener.NoEndpoints(exists)
+}
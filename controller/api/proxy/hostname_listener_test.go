@@ -0,0 +1,63 @@
+package proxy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/linkerd/linkerd2-proxy-api/go/net"
+	"github.com/linkerd/linkerd2/pkg/addr"
+)
+
+func TestHostnameListener(t *testing.T) {
+	pod0 := &updateAddress{
+		address:  &net.TcpAddress{Ip: addr.ProxyIPV4(10, 0, 0, 1), Port: 9092},
+		hostname: "kafka-0",
+	}
+	pod1 := &updateAddress{
+		address:  &net.TcpAddress{Ip: addr.ProxyIPV4(10, 0, 0, 2), Port: 9092},
+		hostname: "kafka-1",
+	}
+
+	t.Run("Only forwards the endpoint matching the hostname", func(t *testing.T) {
+		collector, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		listener := newHostnameListener(collector, "kafka-1")
+
+		listener.Update([]*updateAddress{pod0, pod1}, nil)
+
+		if !reflect.DeepEqual(collector.added, []*updateAddress{pod1}) {
+			t.Fatalf("Expected only %s to be added, got %v", pod1, collector.added)
+		}
+		if collector.noEndpointsCalled {
+			t.Fatalf("Expected NoEndpoints to not be called")
+		}
+
+		listener.Update(nil, []*updateAddress{pod0})
+		if len(collector.removed) != 0 {
+			t.Fatalf("Expected nothing to be removed, got %v", collector.removed)
+		}
+
+		listener.Update(nil, []*updateAddress{pod1})
+		if len(collector.removed) != 0 {
+			t.Fatalf("Expected the last matching endpoint to be reported as NoEndpoints, got removal of %v", collector.removed)
+		}
+		if !collector.noEndpointsCalled || !collector.noEndpointsExists {
+			t.Fatalf("Expected NoEndpoints(true) to be called")
+		}
+	})
+
+	t.Run("Reports no endpoints if no endpoint matches the hostname", func(t *testing.T) {
+		collector, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		listener := newHostnameListener(collector, "kafka-2")
+
+		listener.Update([]*updateAddress{pod0, pod1}, nil)
+
+		if len(collector.added) != 0 {
+			t.Fatalf("Expected nothing to be added, got %v", collector.added)
+		}
+		if !collector.noEndpointsCalled || !collector.noEndpointsExists {
+			t.Fatalf("Expected NoEndpoints(true) to be called")
+		}
+	})
+}
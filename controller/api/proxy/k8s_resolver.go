@@ -3,6 +3,7 @@ package proxy
 import (
 	"errors"
 	"fmt"
+	"net"
 	"reflect"
 	"regexp"
 	"strings"
@@ -19,6 +20,8 @@ type k8sResolver struct {
 	controllerNamespace string
 	endpointsWatcher    *endpointsWatcher
 	profileWatcher      *profileWatcher
+	podIPWatcher        *podIPWatcher
+	trafficSplitWatcher *trafficSplitWatcher
 }
 
 func newK8sResolver(
@@ -26,12 +29,16 @@ func newK8sResolver(
 	controllerNamespace string,
 	ew *endpointsWatcher,
 	pw *profileWatcher,
+	piw *podIPWatcher,
+	tsw *trafficSplitWatcher,
 ) *k8sResolver {
 	return &k8sResolver{
 		k8sDNSZoneLabels:    k8sDNSZoneLabels,
 		controllerNamespace: controllerNamespace,
 		endpointsWatcher:    ew,
 		profileWatcher:      pw,
+		podIPWatcher:        piw,
+		trafficSplitWatcher: tsw,
 	}
 }
 
@@ -45,7 +52,11 @@ func (s serviceID) String() string {
 }
 
 func (k *k8sResolver) canResolve(host string, port int) (bool, error) {
-	id, err := k.localKubernetesServiceIDFromDNSName(host)
+	if isIPV4(host) {
+		return k.podIPWatcher != nil, nil
+	}
+
+	id, _, err := k.localKubernetesServiceIDFromDNSName(host)
 	if err != nil {
 		return false, err
 	}
@@ -54,7 +65,11 @@ func (k *k8sResolver) canResolve(host string, port int) (bool, error) {
 }
 
 func (k *k8sResolver) streamResolution(host string, port int, listener endpointUpdateListener) error {
-	id, err := k.localKubernetesServiceIDFromDNSName(host)
+	if isIPV4(host) && k.podIPWatcher != nil {
+		return k.resolvePodIP(host, port, listener)
+	}
+
+	id, hostname, err := k.localKubernetesServiceIDFromDNSName(host)
 	if err != nil {
 		log.Error(err)
 		return err
@@ -68,6 +83,13 @@ func (k *k8sResolver) streamResolution(host string, port int, listener endpointU
 
 	listener.SetServiceID(id)
 
+	if hostname != "" {
+		listener = newHostnameListener(listener, hostname)
+	} else if k.trafficSplitWatcher != nil {
+		// the individual pods of a service are never split
+		return k.resolveTrafficSplit(id, port, listener)
+	}
+
 	return k.resolveKubernetesService(id, port, listener)
 }
 
@@ -105,6 +127,12 @@ func (k *k8sResolver) stop() {
 	if k.profileWatcher != nil {
 		k.profileWatcher.stop()
 	}
+	if k.podIPWatcher != nil {
+		k.podIPWatcher.stop()
+	}
+	if k.trafficSplitWatcher != nil {
+		k.trafficSplitWatcher.stop()
+	}
 }
 
 func (k *k8sResolver) resolveKubernetesService(id *serviceID, port int, listener endpointUpdateListener) error {
@@ -118,14 +146,62 @@ func (k *k8sResolver) resolveKubernetesService(id *serviceID, port int, listener
 	}
 }
 
+// resolveTrafficSplit resolves the service to the endpoints of the backends of
+// its traffic split, weighted by their weights in the split, and follows the
+// changes of the split. If the service has no split, it resolves to its own
+// endpoints.
+func (k *k8sResolver) resolveTrafficSplit(id *serviceID, port int, listener endpointUpdateListener) error {
+	splitListener := newTrafficSplitListener(*id, uint32(port), k.endpointsWatcher, listener)
+	if err := k.trafficSplitWatcher.subscribe(*id, splitListener); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	select {
+	case <-listener.ClientClose():
+		err := k.trafficSplitWatcher.unsubscribe(*id, splitListener)
+		if unsubscribeErr := splitListener.unsubscribeAll(); unsubscribeErr != nil {
+			err = unsubscribeErr
+		}
+		return err
+	case <-listener.ServerClose():
+		return nil
+	}
+}
+
+func (k *k8sResolver) resolvePodIP(ip string, port int, listener endpointUpdateListener) error {
+	err := k.podIPWatcher.subscribe(ip, uint32(port), listener)
+	if err != nil {
+		log.Error(err)
+		k.podIPWatcher.unsubscribe(ip, listener)
+		return err
+	}
+
+	select {
+	case <-listener.ClientClose():
+		return k.podIPWatcher.unsubscribe(ip, listener)
+	case <-listener.ServerClose():
+		return nil
+	}
+}
+
+// isIPV4 returns true if host is an IPv4 address, such as the IP of a pod that
+// a client connects to directly.
+func isIPV4(host string) bool {
+	ip := net.ParseIP(host)
+	return ip != nil && ip.To4() != nil
+}
+
 // localKubernetesServiceIDFromDNSName returns the name of the service in
 // "namespace-name/service-name" form if `host` is a DNS name in a form used
 // for local Kubernetes services. It returns nil if `host` isn't in such a
-// form.
-func (k *k8sResolver) localKubernetesServiceIDFromDNSName(host string) (*serviceID, error) {
+// form. If `host` addresses an individual pod of the service, such as
+// "pod-0.service.namespace.svc.cluster.local" for StatefulSet pods, the pod's
+// hostname is returned as well.
+func (k *k8sResolver) localKubernetesServiceIDFromDNSName(host string) (*serviceID, string, error) {
 	hostLabels, err := splitDNSName(host)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// Verify that `host` ends with ".svc.$zone", ".svc.cluster.local," or ".svc".
@@ -154,20 +230,26 @@ func (k *k8sResolver) localKubernetesServiceIDFromDNSName(host string) (*service
 	// workaround until the proxies are configured to know "$zone."
 	hostLabels, matched = maybeStripSuffixLabels(hostLabels, []string{"svc"})
 	if !matched {
-		return nil, nil
+		return nil, "", nil
 	}
 
-	// Extract the service name and namespace. TODO: Federated services have
-	// *three* components before "svc"; see
+	// Extract the service name and namespace, and the pod hostname if present.
+	// TODO: Federated services also have *three* components before "svc"; see
 	// https://github.com/linkerd/linkerd2/issues/156.
-	if len(hostLabels) != 2 {
-		return nil, fmt.Errorf("not a service: %s", host)
+	switch len(hostLabels) {
+	case 2:
+		return &serviceID{
+			namespace: hostLabels[1],
+			name:      hostLabels[0],
+		}, "", nil
+	case 3:
+		return &serviceID{
+			namespace: hostLabels[2],
+			name:      hostLabels[1],
+		}, hostLabels[0], nil
+	default:
+		return nil, "", fmt.Errorf("not a service: %s", host)
 	}
-
-	return &serviceID{
-		namespace: hostLabels[1],
-		name:      hostLabels[0],
-	}, nil
 }
 
 func splitDNSName(dnsName string) ([]string, error) {
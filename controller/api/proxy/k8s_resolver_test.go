@@ -47,6 +47,26 @@ func TestK8sResolver(t *testing.T) {
 		}
 	})
 
+	t.Run("can resolve pod IPs when watching them", func(t *testing.T) {
+		resolver := k8sResolver{k8sDNSZoneLabels: someKubernetesDNSZone, podIPWatcher: &podIPWatcher{}}
+
+		canResolve, err := resolver.canResolve("10.0.0.1", 8080)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !canResolve {
+			t.Fatalf("Expected k8s resolver to resolve pod IP [10.0.0.1] but it didnt")
+		}
+
+		resolver.podIPWatcher = nil
+		canResolve, err = resolver.canResolve("10.0.0.1", 8080)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if canResolve {
+			t.Fatalf("Expected k8s resolver to NOT resolve pod IP [10.0.0.1] without a pod IP watcher but it did")
+		}
+	})
 }
 
 func TestLocalKubernetesServiceIdFromDNSName(t *testing.T) {
@@ -84,7 +104,7 @@ func TestLocalKubernetesServiceIdFromDNSName(t *testing.T) {
 	t.Run("Accepts 'cluster.local' as an alias for '$zone'", func(t *testing.T) {
 		resolver := &k8sResolver{k8sDNSZoneLabels: someKubernetesDNSZone}
 		nameWithClusterLocal := "name.ns.svc.cluster.local"
-		resolvedNameWithClusterLocal, err := resolver.localKubernetesServiceIDFromDNSName(nameWithClusterLocal)
+		resolvedNameWithClusterLocal, _, err := resolver.localKubernetesServiceIDFromDNSName(nameWithClusterLocal)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -94,7 +114,7 @@ func TestLocalKubernetesServiceIdFromDNSName(t *testing.T) {
 		}
 
 		nameWithZone := fmt.Sprintf("name.ns.svc.%s", strings.Join(someKubernetesDNSZone, "."))
-		resolvedNameWithZone, err := resolver.localKubernetesServiceIDFromDNSName(nameWithZone)
+		resolvedNameWithZone, _, err := resolver.localKubernetesServiceIDFromDNSName(nameWithZone)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -124,15 +144,34 @@ func TestLocalKubernetesServiceIdFromDNSName(t *testing.T) {
 		assertIsResolved(t, resolver, resolvableServiceNames)
 	})
 
-	t.Run("Resolves names of services only if three labels in it", func(t *testing.T) {
+	t.Run("Resolves names of services only if three or four labels in it", func(t *testing.T) {
 		resolver := &k8sResolver{k8sDNSZoneLabels: someKubernetesDNSZone}
-		validServiceNames := map[string]string{"name.ns.svc": "name.ns"}
+		validServiceNames := map[string]string{"name.ns.svc": "name.ns", "pod-0.name.ns.svc": "name.ns"}
 		assertIsResolved(t, resolver, validServiceNames)
 
-		invalidServiceNames := []string{"", "something.name.ns.svc.cluster.local", "a.svc", "svc", "a.b.c.svc", "a.b.c.d.svc"}
+		invalidServiceNames := []string{"", "something.else.name.ns.svc.cluster.local", "a.svc", "svc", "a.b.c.d.svc"}
 		assertReturnError(t, resolver, invalidServiceNames)
 	})
 
+	t.Run("Resolves the hostname of individual pods of a service", func(t *testing.T) {
+		resolver := &k8sResolver{k8sDNSZoneLabels: someKubernetesDNSZone}
+		names := map[string]string{
+			"name.ns.svc.cluster.local":       "",
+			"pod-0.name.ns.svc.cluster.local": "pod-0",
+			"kafka-2.kafka.ns.svc":            "kafka-2",
+		}
+
+		for name, expectedHostname := range names {
+			_, hostname, err := resolver.localKubernetesServiceIDFromDNSName(name)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if hostname != expectedHostname {
+				t.Fatalf("Expected name [%s] to have hostname [%s], but got [%s]", name, expectedHostname, hostname)
+			}
+		}
+	})
+
 }
 
 func TestSplitDNSName(t *testing.T) {
@@ -216,7 +255,7 @@ func TestMaybeStripSuffixLabels(t *testing.T) {
 
 func assertReturnError(t *testing.T, resolver *k8sResolver, nameToExpectedError []string) {
 	for _, name := range nameToExpectedError {
-		resolvedName, err := resolver.localKubernetesServiceIDFromDNSName(name)
+		resolvedName, _, err := resolver.localKubernetesServiceIDFromDNSName(name)
 		if err == nil {
 			t.Fatalf("Expecting error, got resovled name [%s]", *resolvedName)
 		}
@@ -225,7 +264,7 @@ func assertReturnError(t *testing.T, resolver *k8sResolver, nameToExpectedError
 
 func assertIsResolved(t *testing.T, resolver *k8sResolver, nameToExpectedResolved map[string]string) {
 	for name, expectedResolvedName := range nameToExpectedResolved {
-		resolvedName, err := resolver.localKubernetesServiceIDFromDNSName(name)
+		resolvedName, _, err := resolver.localKubernetesServiceIDFromDNSName(name)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -242,7 +281,7 @@ func assertIsResolved(t *testing.T, resolver *k8sResolver, nameToExpectedResolve
 
 func assertIsntResolved(t *testing.T, resolver *k8sResolver, nameToExpectedNotResolved []string) {
 	for _, name := range nameToExpectedNotResolved {
-		resolvedName, err := resolver.localKubernetesServiceIDFromDNSName(name)
+		resolvedName, _, err := resolver.localKubernetesServiceIDFromDNSName(name)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
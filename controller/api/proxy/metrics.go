@@ -0,0 +1,47 @@
+package proxy
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// subscriptionsCollector exports the numbers of watches of the destination
+// service, and of the proxy streams subscribed to them, by kind of watch. They
+// are read from the watchers on each scrape, like the /state endpoint of the
+// admin server.
+type subscriptionsCollector struct {
+	state       func() destinationState
+	watches     *prometheus.Desc
+	subscribers *prometheus.Desc
+}
+
+func newSubscriptionsCollector(state func() destinationState) *subscriptionsCollector {
+	return &subscriptionsCollector{
+		state: state,
+		watches: prometheus.NewDesc(
+			"destination_watches",
+			"The number of services and service profiles that the destination service watches, by kind.",
+			[]string{"kind"}, nil,
+		),
+		subscribers: prometheus.NewDesc(
+			"destination_subscribers",
+			"The number of proxy streams subscribed to the watches of the destination service, by kind.",
+			[]string{"kind"}, nil,
+		),
+	}
+}
+
+func (c *subscriptionsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.watches
+	ch <- c.subscribers
+}
+
+func (c *subscriptionsCollector) Collect(ch chan<- prometheus.Metric) {
+	state := c.state()
+	ch <- prometheus.MustNewConstMetric(c.watches, prometheus.GaugeValue, float64(state.Endpoints.Watches), "endpoints")
+	ch <- prometheus.MustNewConstMetric(c.subscribers, prometheus.GaugeValue, float64(state.Endpoints.Subscribers), "endpoints")
+	// there are no profile watches in single-namespace installs
+	if state.Profiles != nil {
+		ch <- prometheus.MustNewConstMetric(c.watches, prometheus.GaugeValue, float64(state.Profiles.Watches), "profiles")
+		ch <- prometheus.MustNewConstMetric(c.subscribers, prometheus.GaugeValue, float64(state.Profiles.Subscribers), "profiles")
+	}
+}
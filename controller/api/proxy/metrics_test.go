@@ -0,0 +1,60 @@
+package proxy
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestSubscriptionsCollector(t *testing.T) {
+	for _, tc := range []struct {
+		state    destinationState
+		expected []string
+	}{
+		{
+			state: destinationState{
+				Endpoints: endpointsState{Watches: 2, Subscribers: 3},
+				Profiles:  &profilesState{Watches: 1, Subscribers: 4},
+			},
+			expected: []string{
+				"destination_subscribers{kind=endpoints} 3",
+				"destination_subscribers{kind=profiles} 4",
+				"destination_watches{kind=endpoints} 2",
+				"destination_watches{kind=profiles} 1",
+			},
+		},
+		{
+			// single-namespace installs don't watch profiles
+			state: destinationState{Endpoints: endpointsState{Watches: 1, Subscribers: 1}},
+			expected: []string{
+				"destination_subscribers{kind=endpoints} 1",
+				"destination_watches{kind=endpoints} 1",
+			},
+		},
+	} {
+		registry := prometheus.NewRegistry()
+		state := tc.state
+		if err := registry.Register(newSubscriptionsCollector(func() destinationState { return state })); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		metrics := []string{}
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				label := metric.GetLabel()[0]
+				metrics = append(metrics, fmt.Sprintf("%s{%s=%s} %v", family.GetName(), label.GetName(), label.GetValue(), metric.GetGauge().GetValue()))
+			}
+		}
+		sort.Strings(metrics)
+
+		if fmt.Sprint(metrics) != fmt.Sprint(tc.expected) {
+			t.Fatalf("Expected metrics %v, got %v", tc.expected, metrics)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package proxy
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	net "github.com/linkerd/linkerd2-proxy-api/go/net"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/addr"
+	log "github.com/sirupsen/logrus"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const podIPIndex = "ip"
+
+// addPodIPIndex indexes the pods of the k8sAPI by IP, if they aren't yet, and
+// returns their indexer. It must be called before the k8sAPI is synced.
+func addPodIPIndex(k8sAPI *k8s.API) (cache.Indexer, error) {
+	informer := k8sAPI.Pod().Informer()
+	if _, ok := informer.GetIndexer().GetIndexers()[podIPIndex]; !ok {
+		if err := informer.AddIndexers(cache.Indexers{podIPIndex: indexPodByIP}); err != nil {
+			return nil, err
+		}
+	}
+	return informer.GetIndexer(), nil
+}
+
+func indexPodByIP(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*coreV1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("object is not a pod: %v", obj)
+	}
+	if pod.Status.PodIP == "" || pod.Spec.HostNetwork {
+		return []string{}, nil
+	}
+	return []string{pod.Status.PodIP}, nil
+}
+
+// podIPWatcher resolves pod IPs to the pods that have them, so that the
+// traffic that clients send directly to pod IPs, such as with client-side
+// service discovery, still gets the identity and metric labels of the pod.
+// Pods on the host network are not resolved, since they share their node's
+// IP.
+type podIPWatcher struct {
+	podIndexer cache.Indexer
+
+	// subscriptions are keyed by IP
+	subscriptions map[string]map[endpointUpdateListener]*podIPSubscription
+	sync.Mutex
+}
+
+// podIPSubscription is the port a listener subscribed to, and the pod it was
+// last sent, if any.
+type podIPSubscription struct {
+	port uint32
+	pod  *coreV1.Pod
+}
+
+func newPodIPWatcher(k8sAPI *k8s.API) (*podIPWatcher, error) {
+	podIndexer, err := addPodIPIndex(k8sAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &podIPWatcher{
+		podIndexer:    podIndexer,
+		subscriptions: make(map[string]map[endpointUpdateListener]*podIPSubscription),
+	}
+
+	k8sAPI.Pod().Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    w.handlePod,
+			DeleteFunc: w.handlePod,
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				w.handlePod(oldObj)
+				w.handlePod(newObj)
+			},
+		},
+	)
+
+	return w, nil
+}
+
+func (w *podIPWatcher) subscribe(ip string, port uint32, listener endpointUpdateListener) error {
+	log.Infof("Establishing pod IP watch on %s:%d", ip, port)
+	w.Lock()
+	defer w.Unlock()
+
+	listeners, ok := w.subscriptions[ip]
+	if !ok {
+		listeners = make(map[endpointUpdateListener]*podIPSubscription)
+		w.subscriptions[ip] = listeners
+	}
+	subscription := &podIPSubscription{port: port}
+	listeners[listener] = subscription
+
+	return w.send(ip, listener, subscription, w.podForIP(ip), true)
+}
+
+func (w *podIPWatcher) unsubscribe(ip string, listener endpointUpdateListener) error {
+	log.Infof("Stopping pod IP watch on %s", ip)
+	w.Lock()
+	defer w.Unlock()
+
+	listeners, ok := w.subscriptions[ip]
+	if !ok {
+		return fmt.Errorf("cannot unsubscribe from unknown pod IP %s", ip)
+	}
+	delete(listeners, listener)
+	if len(listeners) == 0 {
+		delete(w.subscriptions, ip)
+	}
+	return nil
+}
+
+// stop stops all the listeners.
+func (w *podIPWatcher) stop() {
+	w.Lock()
+	defer w.Unlock()
+
+	for _, listeners := range w.subscriptions {
+		for listener := range listeners {
+			listener.Stop()
+		}
+	}
+	w.subscriptions = make(map[string]map[endpointUpdateListener]*podIPSubscription)
+}
+
+func (w *podIPWatcher) handlePod(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pod, ok := obj.(*coreV1.Pod)
+	if !ok || pod.Status.PodIP == "" {
+		return
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	ip := pod.Status.PodIP
+	listeners, ok := w.subscriptions[ip]
+	if !ok {
+		return
+	}
+	current := w.podForIP(ip)
+	for listener, subscription := range listeners {
+		if err := w.send(ip, listener, subscription, current, false); err != nil {
+			log.Errorf("Failed to update pod IP watch on %s: %s", ip, err)
+		}
+	}
+}
+
+// send tells the listener that the IP belongs to the pod, or to no pod if pod
+// is nil, unless it did already. Listeners are only told about the pod's
+// address; they aren't told when an unchanged pod is updated.
+func (w *podIPWatcher) send(ip string, listener endpointUpdateListener, subscription *podIPSubscription, pod *coreV1.Pod, initial bool) error {
+	if !initial && samePodEndpoint(subscription.pod, pod) {
+		return nil
+	}
+	subscription.pod = pod
+
+	if pod == nil {
+		// the IP doesn't belong to any pod, so the proxy connects to it
+		// directly, as it would without resolving it
+		listener.NoEndpoints(false)
+		return nil
+	}
+
+	proxyIP, err := addr.ParseProxyIPV4(ip)
+	if err != nil {
+		return err
+	}
+	listener.Update([]*updateAddress{
+		{
+			address: &net.TcpAddress{Ip: proxyIP, Port: subscription.port},
+			pod:     pod,
+		},
+	}, nil)
+	return nil
+}
+
+// podForIP returns the running or pending pod with the given IP, or nil if
+// there's none. Pods that have terminated may keep their IP after it's been
+// reassigned to another pod, so they are ignored.
+func (w *podIPWatcher) podForIP(ip string) *coreV1.Pod {
+	objs, err := w.podIndexer.ByIndex(podIPIndex, ip)
+	if err != nil {
+		log.Errorf("Failed to look up the pod with IP %s: %s", ip, err)
+		return nil
+	}
+
+	var found *coreV1.Pod
+	for _, obj := range objs {
+		pod := obj.(*coreV1.Pod)
+		if pod.Status.Phase == coreV1.PodSucceeded || pod.Status.Phase == coreV1.PodFailed {
+			continue
+		}
+		if found != nil {
+			log.Warnf("Pods %s.%s and %s.%s both have IP %s", found.Name, found.Namespace, pod.Name, pod.Namespace, ip)
+			return nil
+		}
+		found = pod
+	}
+	return found
+}
+
+// samePodEndpoint returns true if the pods have the same identity and metric
+// labels, so that a listener that was sent one doesn't need to be sent the
+// other.
+func samePodEndpoint(a, b *coreV1.Pod) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.UID == b.UID &&
+		reflect.DeepEqual(a.Labels, b.Labels) &&
+		reflect.DeepEqual(a.OwnerReferences, b.OwnerReferences)
+}
@@ -0,0 +1,131 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/addr"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+func TestPodIPWatcher(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("", `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web-1
+  namespace: ns
+  uid: web-1-uid
+  labels:
+    app: web
+status:
+  phase: Running
+  podIP: 10.0.0.1`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: job-1
+  namespace: ns
+status:
+  phase: Succeeded
+  podIP: 10.0.0.2`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: host-1
+  namespace: ns
+spec:
+  hostNetwork: true
+status:
+  phase: Running
+  podIP: 10.0.0.3`,
+	)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+
+	watcher, err := newPodIPWatcher(k8sAPI)
+	if err != nil {
+		t.Fatalf("newPodIPWatcher returned an error: %s", err)
+	}
+
+	k8sAPI.Sync()
+
+	t.Run("resolves the IP of a running pod to the pod", func(t *testing.T) {
+		listener, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+
+		if err := watcher.subscribe("10.0.0.1", 8080, listener); err != nil {
+			t.Fatalf("subscribe returned an error: %s", err)
+		}
+		defer watcher.unsubscribe("10.0.0.1", listener)
+
+		if len(listener.added) != 1 {
+			t.Fatalf("Expected 1 address, got %v", listener.added)
+		}
+		added := listener.added[0]
+		if addr.ProxyAddressToString(added.address) != "10.0.0.1:8080" {
+			t.Fatalf("Expected address 10.0.0.1:8080, got %s", addr.ProxyAddressToString(added.address))
+		}
+		if added.pod == nil || added.pod.Name != "web-1" {
+			t.Fatalf("Expected pod web-1, got %v", added.pod)
+		}
+	})
+
+	t.Run("doesn't resolve the IPs of terminated or host network pods", func(t *testing.T) {
+		for _, ip := range []string{"10.0.0.2", "10.0.0.3", "10.0.0.4"} {
+			listener, cancelFn := newCollectUpdateListener()
+
+			if err := watcher.subscribe(ip, 8080, listener); err != nil {
+				t.Fatalf("subscribe returned an error: %s", err)
+			}
+			watcher.unsubscribe(ip, listener)
+			cancelFn()
+
+			if len(listener.added) != 0 {
+				t.Fatalf("Expected no addresses for %s, got %v", ip, listener.added)
+			}
+			if !listener.noEndpointsCalled || listener.noEndpointsExists {
+				t.Fatalf("Expected NoEndpoints(false) for %s", ip)
+			}
+		}
+	})
+
+	t.Run("updates the listener when the pod changes", func(t *testing.T) {
+		listener, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+
+		if err := watcher.subscribe("10.0.0.1", 8080, listener); err != nil {
+			t.Fatalf("subscribe returned an error: %s", err)
+		}
+		defer watcher.unsubscribe("10.0.0.1", listener)
+
+		obj, exists, err := watcher.podIndexer.GetByKey("ns/web-1")
+		if err != nil || !exists {
+			t.Fatalf("Expected pod web-1 to be indexed: %v", err)
+		}
+		pod := obj.(*coreV1.Pod).DeepCopy()
+
+		// status updates don't change the pod's identity or labels
+		pod.Status.Message = "updated"
+		watcher.podIndexer.Update(pod)
+		watcher.handlePod(pod)
+		if len(listener.added) != 1 {
+			t.Fatalf("Expected 1 address, got %v", listener.added)
+		}
+
+		relabeled := pod.DeepCopy()
+		relabeled.Labels = map[string]string{"app": "web", "version": "v2"}
+		watcher.podIndexer.Update(relabeled)
+		watcher.handlePod(relabeled)
+		if len(listener.added) != 2 || listener.added[1].pod.Labels["version"] != "v2" {
+			t.Fatalf("Expected the relabeled pod to be sent, got %v", listener.added)
+		}
+
+		watcher.podIndexer.Delete(relabeled)
+		watcher.handlePod(relabeled)
+		if !listener.noEndpointsCalled || listener.noEndpointsExists {
+			t.Fatalf("Expected NoEndpoints(false) after the pod was deleted")
+		}
+	})
+}
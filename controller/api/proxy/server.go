@@ -5,10 +5,13 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	pb "github.com/linkerd/linkerd2-proxy-api/go/destination"
 	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/admin"
 	"github.com/linkerd/linkerd2/pkg/prometheus"
+	promclient "github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 )
@@ -18,6 +21,9 @@ type server struct {
 	resolver        streamingDestinationResolver
 	enableH2Upgrade bool
 	enableTLS       bool
+	metricPodLabels []string
+	zones           *zoneResolver
+	minSameZone     int
 }
 
 // NewServer returns a new instance of the proxy-api server.
@@ -30,16 +36,38 @@ type server struct {
 // If the port is omitted, 80 is used as a default.  If the namespace is
 // omitted, "default" is used as a default.append
 //
+// Destinations can also be pod IPs, of the form <ip>:<port>, which resolve to
+// that pod, so that traffic sent directly to pods gets their TLS identity and
+// metric labels.
+//
 // Addresses for the given destination are fetched from the Kubernetes Endpoints
-// API.
+// API. Addresses for ExternalName services are resolved via DNS, and refreshed
+// every externalNameTTL. Endpoints updates for a service are coalesced and
+// sent to proxies at most once per endpointsDebounce.
+//
+// Services that are the apex service of a TrafficSplit resolve to the
+// endpoints of the backend services of the split, weighted so that each
+// backend receives its share of the traffic. Traffic splits aren't supported
+// in single namespace mode.
+//
+// If topology-aware routing is enabled, proxies whose zone can be determined
+// are preferably sent the endpoints in their own zone.
+//
+// The metric labels of each endpoint include the pod labels whose keys are in
+// metricPodLabels, so that proxies add them to their outbound metrics. The
+// numbers of watches and subscribers are exported with the registerer.
 func NewServer(
 	addr, k8sDNSZone string,
 	controllerNamespace string,
 	enableTLS, enableH2Upgrade, singleNamespace bool,
+	externalNameTTL, endpointsDebounce time.Duration,
+	metricPodLabels []string,
+	topology TopologyConfig,
 	k8sAPI *k8s.API,
+	registerer promclient.Registerer,
 	done chan struct{},
 ) (*grpc.Server, net.Listener, error) {
-	resolver, err := buildResolver(k8sDNSZone, controllerNamespace, k8sAPI, singleNamespace)
+	resolver, err := buildResolver(k8sDNSZone, controllerNamespace, k8sAPI, singleNamespace, externalNameTTL, endpointsDebounce, registerer)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -49,6 +77,15 @@ func NewServer(
 		resolver:        resolver,
 		enableH2Upgrade: enableH2Upgrade,
 		enableTLS:       enableTLS,
+		metricPodLabels: metricPodLabels,
+	}
+
+	if topology.Enabled {
+		srv.zones, err = newZoneResolver(k8sAPI, topology.ZoneLabel)
+		if err != nil {
+			return nil, nil, err
+		}
+		srv.minSameZone = topology.MinSameZoneEndpoints
 	}
 
 	lis, err := net.Listen("tcp", addr)
@@ -58,6 +95,7 @@ func NewServer(
 
 	s := prometheus.NewGrpcServer()
 	pb.RegisterDestinationServer(s, &srv)
+	admin.RegisterHealthServer(s, "io.linkerd.proxy.destination.Destination")
 
 	go func() {
 		<-done
@@ -94,7 +132,15 @@ func (s *server) GetProfile(dest *pb.GetDestination, stream pb.Destination_GetPr
 }
 
 func (s *server) streamResolution(host string, port int, stream pb.Destination_GetServer) error {
-	listener := newEndpointListener(stream, s.k8sAPI.GetOwnerKindAndName, s.enableTLS, s.enableH2Upgrade)
+	var listener endpointUpdateListener
+	listener = newEndpointListener(stream, s.k8sAPI.GetOwnerKindAndName, s.metricPodLabels, s.enableTLS, s.enableH2Upgrade)
+
+	if s.zones != nil {
+		if zone := s.zones.clientZone(stream.Context()); zone != "" {
+			log.Debugf("Preferring endpoints in zone %s for %s:%d", zone, host, port)
+			listener = newZoneListener(listener, zone, s.minSameZone, s.zones.zoneForPod)
+		}
+	}
 
 	resolverCanResolve, err := s.resolver.canResolve(host, port)
 	if err != nil {
@@ -136,6 +182,8 @@ func buildResolver(
 	k8sDNSZone, controllerNamespace string,
 	k8sAPI *k8s.API,
 	singleNamespace bool,
+	externalNameTTL, endpointsDebounce time.Duration,
+	registerer promclient.Registerer,
 ) (streamingDestinationResolver, error) {
 	var k8sDNSZoneLabels []string
 	if k8sDNSZone == "" {
@@ -149,11 +197,29 @@ func buildResolver(
 	}
 
 	var pw *profileWatcher
+	var tsw *trafficSplitWatcher
 	if !singleNamespace {
 		pw = newProfileWatcher(k8sAPI)
+		tsw = newTrafficSplitWatcher(k8sAPI)
+	}
+
+	piw, err := newPodIPWatcher(k8sAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	ew := newEndpointsWatcher(k8sAPI, externalNameTTL, endpointsDebounce)
+	admin.RegisterState("destination", func() interface{} {
+		return newDestinationState(ew, pw)
+	})
+	err = registerer.Register(newSubscriptionsCollector(func() destinationState {
+		return newDestinationState(ew, pw)
+	}))
+	if err != nil {
+		return nil, err
 	}
 
-	k8sResolver := newK8sResolver(k8sDNSZoneLabels, controllerNamespace, newEndpointsWatcher(k8sAPI), pw)
+	k8sResolver := newK8sResolver(k8sDNSZoneLabels, controllerNamespace, ew, pw, piw, tsw)
 
 	log.Infof("Built k8s name resolver")
 
@@ -0,0 +1,87 @@
+package proxy
+
+import (
+	"fmt"
+	"sort"
+)
+
+// destinationState is a snapshot of the subscriptions of the destination
+// service, which the admin server reports on its /state endpoint.
+type destinationState struct {
+	Endpoints endpointsState `json:"endpoints"`
+	Profiles  *profilesState `json:"profiles,omitempty"`
+}
+
+type endpointsState struct {
+	Watches     int              `json:"watches"`
+	Subscribers int              `json:"subscribers"`
+	Authorities []authorityState `json:"authorities"`
+}
+
+type authorityState struct {
+	Authority    string `json:"authority"`
+	Subscribers  int    `json:"subscribers"`
+	Addresses    int    `json:"addresses"`
+	ExternalName string `json:"externalName,omitempty"`
+}
+
+type profilesState struct {
+	Watches     int      `json:"watches"`
+	Subscribers int      `json:"subscribers"`
+	Profiles    []string `json:"profiles"`
+}
+
+// newDestinationState returns the state of the endpoints watcher, and of the
+// profile watcher unless it's nil, as in single-namespace installs.
+func newDestinationState(endpoints *endpointsWatcher, profiles *profileWatcher) destinationState {
+	state := destinationState{Endpoints: endpoints.state()}
+	if profiles != nil {
+		s := profiles.state()
+		state.Profiles = &s
+	}
+	return state
+}
+
+func (e *endpointsWatcher) state() endpointsState {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	state := endpointsState{Authorities: []authorityState{}}
+	for service, ports := range e.servicePorts {
+		for port, sp := range ports {
+			sp.mutex.Lock()
+			authority := authorityState{
+				Authority:    fmt.Sprintf("%s:%d", service, port),
+				Subscribers:  len(sp.listeners),
+				Addresses:    len(sp.addresses),
+				ExternalName: sp.externalName,
+			}
+			sp.mutex.Unlock()
+
+			state.Watches++
+			state.Subscribers += authority.Subscribers
+			state.Authorities = append(state.Authorities, authority)
+		}
+	}
+	sort.Slice(state.Authorities, func(i, j int) bool {
+		return state.Authorities[i].Authority < state.Authorities[j].Authority
+	})
+	return state
+}
+
+func (p *profileWatcher) state() profilesState {
+	p.profilesLock.RLock()
+	defer p.profilesLock.RUnlock()
+
+	state := profilesState{Profiles: []string{}}
+	for id, entry := range p.profiles {
+		entry.mutex.Lock()
+		state.Subscribers += len(entry.listeners)
+		entry.mutex.Unlock()
+
+		state.Watches++
+		state.Profiles = append(state.Profiles, id.String())
+	}
+	sort.Strings(state.Profiles)
+	return state
+}
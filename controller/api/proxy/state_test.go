@@ -0,0 +1,89 @@
+package proxy
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+)
+
+func TestDestinationState(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: emojivoto
+spec:
+  type: ClusterIP
+  ports:
+  - port: 80`, `
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: web
+  namespace: emojivoto
+subsets:
+- addresses:
+  - ip: 10.1.0.1
+    targetRef:
+      kind: Pod
+      name: web-1
+      namespace: emojivoto
+  ports:
+  - port: 80`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web-1
+  namespace: emojivoto
+status:
+  phase: Running
+  podIP: 10.1.0.1`)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+
+	endpoints := newEndpointsWatcher(k8sAPI, time.Minute, 0)
+	profiles := newProfileWatcher(k8sAPI)
+	k8sAPI.Sync()
+
+	for _, port := range []uint32{80, 80, 8080} {
+		listener, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		if err := endpoints.subscribe(&serviceID{namespace: "emojivoto", name: "web"}, port, listener); err != nil {
+			t.Fatalf("subscribe returned an error: %s", err)
+		}
+	}
+	listener, cancelFn := newCollectProfileListener()
+	defer cancelFn()
+	if err := profiles.subscribeToProfile(profileID{namespace: "emojivoto", name: "web.emojivoto.svc.cluster.local"}, listener); err != nil {
+		t.Fatalf("subscribeToProfile returned an error: %s", err)
+	}
+
+	expected := destinationState{
+		Endpoints: endpointsState{
+			Watches:     2,
+			Subscribers: 3,
+			Authorities: []authorityState{
+				{Authority: "web.emojivoto:80", Subscribers: 2, Addresses: 1},
+				{Authority: "web.emojivoto:8080", Subscribers: 1, Addresses: 1},
+			},
+		},
+		Profiles: &profilesState{
+			Watches:     1,
+			Subscribers: 1,
+			Profiles:    []string{"emojivoto/web.emojivoto.svc.cluster.local"},
+		},
+	}
+
+	state := newDestinationState(endpoints, profiles)
+	if !reflect.DeepEqual(state, expected) {
+		t.Fatalf("Expected state %+v, got %+v", expected, state)
+	}
+
+	if state := newDestinationState(endpoints, nil); state.Profiles != nil {
+		t.Fatalf("Unexpected profiles state: %+v", state.Profiles)
+	}
+}
@@ -0,0 +1,186 @@
+package proxy
+
+import (
+	"context"
+	"net"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgAddr "github.com/linkerd/linkerd2/pkg/addr"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/peer"
+	coreV1 "k8s.io/api/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// DefaultZoneLabel is the well-known node label that holds the node's
+	// availability zone.
+	DefaultZoneLabel = "failure-domain.beta.kubernetes.io/zone"
+)
+
+// TopologyConfig configures zone-aware endpoint filtering in the destination
+// service. When enabled, proxies are preferably sent the endpoints that are in
+// the same zone as the proxy itself.
+type TopologyConfig struct {
+	// Enabled turns on zone-aware endpoint filtering.
+	Enabled bool
+
+	// ZoneLabel is the node label that holds the node's zone.
+	ZoneLabel string
+
+	// MinSameZoneEndpoints is the number of same-zone endpoints below which
+	// endpoints from all zones are sent ("spillover").
+	MinSameZoneEndpoints int
+}
+
+// zoneResolver determines the zone of pods, based on the labels of the nodes
+// they are scheduled on. Kubernetes 1.11 has no EndpointSlice API, so node
+// labels are the only source of topology information.
+type zoneResolver struct {
+	podIndexer cache.Indexer
+	nodeLister corelisters.NodeLister
+	zoneLabel  string
+}
+
+func newZoneResolver(k8sAPI *k8s.API, zoneLabel string) (*zoneResolver, error) {
+	if zoneLabel == "" {
+		zoneLabel = DefaultZoneLabel
+	}
+
+	podIndexer, err := addPodIPIndex(k8sAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	return &zoneResolver{
+		podIndexer: podIndexer,
+		nodeLister: k8sAPI.Node().Lister(),
+		zoneLabel:  zoneLabel,
+	}, nil
+}
+
+// zoneForPod returns the zone of the node the pod is running on, or an empty
+// string if it can't be determined.
+func (z *zoneResolver) zoneForPod(pod *coreV1.Pod) string {
+	if pod == nil || pod.Spec.NodeName == "" {
+		return ""
+	}
+	node, err := z.nodeLister.Get(pod.Spec.NodeName)
+	if err != nil {
+		log.Debugf("Failed to get node %s of pod %s.%s: %s", pod.Spec.NodeName, pod.Name, pod.Namespace, err)
+		return ""
+	}
+	return node.Labels[z.zoneLabel]
+}
+
+// clientZone returns the zone of the pod that opened the given stream, or an
+// empty string if it can't be determined. The client pod is identified by the
+// stream's peer address; if the connection was forwarded by a local proxy, the
+// peer is the loopback address and no zone is returned.
+func (z *zoneResolver) clientZone(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return ""
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() {
+		return ""
+	}
+
+	objs, err := z.podIndexer.ByIndex(podIPIndex, ip.String())
+	if err != nil || len(objs) != 1 {
+		return ""
+	}
+	return z.zoneForPod(objs[0].(*coreV1.Pod))
+}
+
+// zoneListener wraps an endpointUpdateListener and only forwards the
+// endpoints in the listener's zone, unless fewer than minSameZone of them are
+// available, in which case endpoints from all zones are forwarded.
+type zoneListener struct {
+	endpointUpdateListener
+	zone        string
+	minSameZone int
+	zoneForPod  func(*coreV1.Pod) string
+
+	// all known endpoints, and the ones that have been sent to the listener
+	all  map[string]*updateAddress
+	sent map[string]*updateAddress
+}
+
+func newZoneListener(
+	listener endpointUpdateListener,
+	zone string,
+	minSameZone int,
+	zoneForPod func(*coreV1.Pod) string,
+) *zoneListener {
+	if minSameZone < 1 {
+		minSameZone = 1
+	}
+	return &zoneListener{
+		endpointUpdateListener: listener,
+		zone:                   zone,
+		minSameZone:            minSameZone,
+		zoneForPod:             zoneForPod,
+		all:                    make(map[string]*updateAddress),
+		sent:                   make(map[string]*updateAddress),
+	}
+}
+
+func (l *zoneListener) Update(add, remove []*updateAddress) {
+	for _, a := range remove {
+		delete(l.all, pkgAddr.ProxyAddressToString(a.address))
+	}
+	for _, a := range add {
+		l.all[pkgAddr.ProxyAddressToString(a.address)] = a
+	}
+
+	desired := make(map[string]*updateAddress)
+	for key, a := range l.all {
+		if l.zoneForPod(a.pod) == l.zone {
+			desired[key] = a
+		}
+	}
+	if len(desired) < l.minSameZone {
+		desired = l.all
+	}
+
+	if len(desired) == 0 {
+		l.sent = make(map[string]*updateAddress)
+		l.endpointUpdateListener.NoEndpoints(true)
+		return
+	}
+
+	toAdd := make([]*updateAddress, 0)
+	for key, a := range desired {
+		if _, ok := l.sent[key]; !ok {
+			toAdd = append(toAdd, a)
+		}
+	}
+	toRemove := make([]*updateAddress, 0)
+	for key, a := range l.sent {
+		if _, ok := desired[key]; !ok {
+			toRemove = append(toRemove, a)
+		}
+	}
+
+	l.sent = make(map[string]*updateAddress, len(desired))
+	for key, a := range desired {
+		l.sent[key] = a
+	}
+
+	if len(toAdd) > 0 || len(toRemove) > 0 {
+		l.endpointUpdateListener.Update(toAdd, toRemove)
+	}
+}
+
+func (l *zoneListener) NoEndpoints(exists bool) {
+	l.all = make(map[string]*updateAddress)
+	l.sent = make(map[string]*updateAddress)
+	l.endpointUpdateListener.NoEndpoints(exists)
+}
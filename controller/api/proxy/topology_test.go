@@ -0,0 +1,133 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/linkerd/linkerd2-proxy-api/go/net"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/addr"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func podOnNode(name, node string) *coreV1.Pod {
+	return &coreV1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+		Spec:       coreV1.PodSpec{NodeName: node},
+	}
+}
+
+func TestZoneResolver(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("", `
+apiVersion: v1
+kind: Node
+metadata:
+  name: node-a
+  labels:
+    failure-domain.beta.kubernetes.io/zone: zone-a`, `
+apiVersion: v1
+kind: Node
+metadata:
+  name: node-unlabeled`,
+	)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+
+	zones, err := newZoneResolver(k8sAPI, "")
+	if err != nil {
+		t.Fatalf("newZoneResolver returned an error: %s", err)
+	}
+
+	k8sAPI.Sync()
+
+	testCases := []struct {
+		pod  *coreV1.Pod
+		zone string
+	}{
+		{podOnNode("pod-a", "node-a"), "zone-a"},
+		{podOnNode("pod-b", "node-unlabeled"), ""},
+		{podOnNode("pod-c", "node-missing"), ""},
+		{podOnNode("pod-d", ""), ""},
+		{nil, ""},
+	}
+
+	for _, tc := range testCases {
+		zone := zones.zoneForPod(tc.pod)
+		if zone != tc.zone {
+			t.Fatalf("Expected zone [%s] for pod %v, got [%s]", tc.zone, tc.pod, zone)
+		}
+	}
+}
+
+func TestZoneListener(t *testing.T) {
+	nodeZones := map[string]string{"node-a": "zone-a", "node-b": "zone-b"}
+	zoneForPod := func(pod *coreV1.Pod) string {
+		return nodeZones[pod.Spec.NodeName]
+	}
+
+	local1 := &updateAddress{
+		address: &net.TcpAddress{Ip: addr.ProxyIPV4(10, 0, 0, 1), Port: 8080},
+		pod:     podOnNode("local-1", "node-a"),
+	}
+	local2 := &updateAddress{
+		address: &net.TcpAddress{Ip: addr.ProxyIPV4(10, 0, 0, 2), Port: 8080},
+		pod:     podOnNode("local-2", "node-a"),
+	}
+	remote := &updateAddress{
+		address: &net.TcpAddress{Ip: addr.ProxyIPV4(10, 0, 1, 1), Port: 8080},
+		pod:     podOnNode("remote", "node-b"),
+	}
+
+	t.Run("Only forwards same-zone endpoints", func(t *testing.T) {
+		collector, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		listener := newZoneListener(collector, "zone-a", 1, zoneForPod)
+
+		listener.Update([]*updateAddress{local1, remote}, nil)
+
+		if len(collector.added) != 1 || collector.added[0] != local1 {
+			t.Fatalf("Expected only %s to be added, got %v", local1, collector.added)
+		}
+	})
+
+	t.Run("Spills over to other zones when too few same-zone endpoints exist", func(t *testing.T) {
+		collector, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		listener := newZoneListener(collector, "zone-a", 2, zoneForPod)
+
+		listener.Update([]*updateAddress{local1, remote}, nil)
+		if len(collector.added) != 2 {
+			t.Fatalf("Expected all endpoints to be added, got %v", collector.added)
+		}
+
+		listener.Update([]*updateAddress{local2}, nil)
+		if len(collector.removed) != 1 || collector.removed[0] != remote {
+			t.Fatalf("Expected %s to be removed once enough same-zone endpoints exist, got %v", remote, collector.removed)
+		}
+		if len(collector.added) != 3 || collector.added[2] != local2 {
+			t.Fatalf("Expected %s to be added, got %v", local2, collector.added)
+		}
+	})
+
+	t.Run("Falls back to remote endpoints when the last same-zone endpoint is removed", func(t *testing.T) {
+		collector, cancelFn := newCollectUpdateListener()
+		defer cancelFn()
+		listener := newZoneListener(collector, "zone-a", 1, zoneForPod)
+
+		listener.Update([]*updateAddress{local1, remote}, nil)
+		listener.Update(nil, []*updateAddress{local1})
+
+		if len(collector.removed) != 1 || collector.removed[0] != local1 {
+			t.Fatalf("Expected %s to be removed, got %v", local1, collector.removed)
+		}
+		if len(collector.added) != 2 || collector.added[1] != remote {
+			t.Fatalf("Expected %s to be added, got %v", remote, collector.added)
+		}
+
+		listener.Update(nil, []*updateAddress{remote})
+		if !collector.noEndpointsCalled || !collector.noEndpointsExists {
+			t.Fatalf("Expected NoEndpoints(true) to be called")
+		}
+	})
+}
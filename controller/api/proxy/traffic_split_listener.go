@@ -0,0 +1,343 @@
+package proxy
+
+import (
+	"sync"
+
+	ts "github.com/linkerd/linkerd2/controller/gen/apis/split/v1alpha1"
+	"github.com/linkerd/linkerd2/pkg/addr"
+	log "github.com/sirupsen/logrus"
+)
+
+// splitWeightScale is the sum of the weights of all the endpoints of a traffic
+// split, which are shared between the backends according to their weights in
+// the split, and then evenly between the endpoints of each backend.
+const splitWeightScale = 1000000
+
+// trafficSplitListener resolves an apex service to the endpoints of the
+// backend services of its traffic split, weighted so that the share of the
+// traffic that each backend receives from the proxy's load balancer is its
+// weight in the split. If the apex service has no traffic split, it resolves
+// to the endpoints of the apex service itself, without weights.
+//
+// It implements the trafficSplitUpdateListener interface, and subscribes to
+// the endpoints of each backend, called a leaf, through a leafListener.
+type trafficSplitListener struct {
+	endpointUpdateListener
+	apex      serviceID
+	port      uint32
+	endpoints *endpointsWatcher
+
+	// leavesMutex protects the leaves, i.e. the subscriptions to the
+	// endpoints of the backends. It's held while subscribing and
+	// unsubscribing, so it must never be acquired while holding the mutex.
+	leavesMutex sync.Mutex
+	leaves      map[serviceID]*leafListener
+
+	// mutex protects the state below, and serializes the calls to the
+	// wrapped listener, which the leaves update concurrently.
+	mutex sync.Mutex
+	// the milli-weights of the leaves, and their sum; the sum is zero if the
+	// apex service has no traffic split
+	weights     map[serviceID]int64
+	totalWeight int64
+	// the addresses of each leaf, keyed by address
+	addresses map[serviceID]map[string]*updateAddress
+	// whether each leaf's service exists
+	exists   map[serviceID]bool
+	stopOnce sync.Once
+}
+
+func newTrafficSplitListener(apex serviceID, port uint32, endpoints *endpointsWatcher, listener endpointUpdateListener) *trafficSplitListener {
+	return &trafficSplitListener{
+		endpointUpdateListener: listener,
+		apex:                   apex,
+		port:                   port,
+		endpoints:              endpoints,
+		leaves:                 make(map[serviceID]*leafListener),
+		weights:                make(map[serviceID]int64),
+		addresses:              make(map[serviceID]map[string]*updateAddress),
+		exists:                 make(map[serviceID]bool),
+	}
+}
+
+// Stop stops the wrapped listener once, although each leaf is stopped on
+// shutdown.
+func (l *trafficSplitListener) Stop() {
+	l.stopOnce.Do(l.endpointUpdateListener.Stop)
+}
+
+// UpdateTrafficSplit subscribes to the endpoints of the backends of the
+// split, and unsubscribes from the endpoints of the former backends. The new
+// backends are subscribed to first, so that the proxy isn't left without
+// endpoints in between.
+func (l *trafficSplitListener) UpdateTrafficSplit(split *ts.TrafficSplit) {
+	l.leavesMutex.Lock()
+	defer l.leavesMutex.Unlock()
+
+	weights := l.leafWeights(split)
+	l.setWeights(weights)
+
+	for id := range weights {
+		if _, ok := l.leaves[id]; ok {
+			continue
+		}
+		leaf := &leafListener{parent: l, id: id}
+		if err := l.endpoints.subscribe(&leaf.id, l.port, leaf); err != nil {
+			log.Errorf("Failed to subscribe to %s:%d for %s: %s", id, l.port, l.apex, err)
+			continue
+		}
+		l.leaves[id] = leaf
+	}
+
+	for id, leaf := range l.leaves {
+		if _, ok := weights[id]; ok {
+			continue
+		}
+		if err := l.endpoints.unsubscribe(&leaf.id, l.port, leaf); err != nil {
+			log.Errorf("Failed to unsubscribe from %s:%d for %s: %s", id, l.port, l.apex, err)
+		}
+		delete(l.leaves, id)
+		l.removeLeaf(id)
+	}
+}
+
+// unsubscribeAll unsubscribes from the endpoints of all the leaves, once the
+// client is gone.
+func (l *trafficSplitListener) unsubscribeAll() error {
+	l.leavesMutex.Lock()
+	defer l.leavesMutex.Unlock()
+
+	var lastErr error
+	for id, leaf := range l.leaves {
+		if err := l.endpoints.unsubscribe(&leaf.id, l.port, leaf); err != nil {
+			lastErr = err
+		}
+		delete(l.leaves, id)
+	}
+	return lastErr
+}
+
+// leafWeights returns the milli-weights of the backends of the split that
+// receive traffic, or the apex service with a zero weight if there are none.
+func (l *trafficSplitListener) leafWeights(split *ts.TrafficSplit) map[serviceID]int64 {
+	weights := make(map[serviceID]int64)
+	if split != nil {
+		for _, backend := range split.Spec.Backends {
+			if backend.Weight == nil || backend.Weight.MilliValue() <= 0 {
+				continue
+			}
+			id := serviceID{namespace: l.apex.namespace, name: backend.Service}
+			weights[id] += backend.Weight.MilliValue()
+		}
+	}
+	if len(weights) == 0 {
+		weights[l.apex] = 0
+	}
+	return weights
+}
+
+// setWeights records the weights of the leaves, and republishes the addresses
+// of the current leaves with their new weights.
+func (l *trafficSplitListener) setWeights(weights map[serviceID]int64) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	var total int64
+	for _, weight := range weights {
+		total += weight
+	}
+	if total == l.totalWeight && equalWeights(weights, l.weights) {
+		return
+	}
+	l.weights = weights
+	l.totalWeight = total
+
+	// the leaves that are no longer backends are about to be removed
+	add := make([]*updateAddress, 0)
+	for id := range l.addresses {
+		if _, ok := weights[id]; ok {
+			add = append(add, l.weighted(id)...)
+		}
+	}
+	if len(add) > 0 {
+		l.endpointUpdateListener.Update(add, nil)
+	}
+}
+
+// update records the added and removed addresses of a leaf, and publishes
+// them. When the number of endpoints of a weighted leaf changes, the weights
+// of all of its endpoints change, so they're all republished.
+func (l *trafficSplitListener) update(id serviceID, add, remove []*updateAddress) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.exists[id] = true
+	set, ok := l.addresses[id]
+	if !ok {
+		set = make(map[string]*updateAddress)
+		l.addresses[id] = set
+	}
+	count := len(set)
+	for _, a := range remove {
+		delete(set, addr.ProxyAddressToString(a.address))
+	}
+	for _, a := range add {
+		set[addr.ProxyAddressToString(a.address)] = a
+	}
+
+	if l.totalWeight > 0 && l.weights[id] > 0 {
+		if len(set) != count {
+			add = l.weighted(id)
+		} else {
+			add = l.withWeight(add, l.endpointWeight(id))
+		}
+	}
+	l.publish(add, remove)
+}
+
+// noEndpoints removes all the addresses of a leaf.
+func (l *trafficSplitListener) noEndpoints(id serviceID, exists bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.exists[id] = exists
+	l.publish(nil, l.clear(id))
+}
+
+// removeLeaf removes all the addresses of a leaf that's no longer a backend.
+func (l *trafficSplitListener) removeLeaf(id serviceID) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.exists, id)
+	l.publish(nil, l.clear(id))
+}
+
+// clear forgets the addresses of a leaf, and returns them. It must be called
+// with the mutex held.
+func (l *trafficSplitListener) clear(id serviceID) []*updateAddress {
+	removed := make([]*updateAddress, 0)
+	for _, a := range l.addresses[id] {
+		removed = append(removed, a)
+	}
+	delete(l.addresses, id)
+	return removed
+}
+
+// publish sends an update to the wrapped listener, or tells it that there are
+// no endpoints if no leaf has any. The removed addresses that are still
+// addresses of another leaf aren't removed. It must be called with the mutex
+// held.
+func (l *trafficSplitListener) publish(add, remove []*updateAddress) {
+	filteredRemove := make([]*updateAddress, 0, len(remove))
+	for _, a := range remove {
+		key := addr.ProxyAddressToString(a.address)
+		found := false
+		for _, set := range l.addresses {
+			if _, ok := set[key]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			filteredRemove = append(filteredRemove, a)
+		}
+	}
+	remove = filteredRemove
+
+	for _, set := range l.addresses {
+		if len(set) > 0 {
+			if len(add) > 0 || len(remove) > 0 {
+				l.endpointUpdateListener.Update(add, remove)
+			}
+			return
+		}
+	}
+
+	exists := false
+	for _, e := range l.exists {
+		exists = exists || e
+	}
+	l.endpointUpdateListener.NoEndpoints(exists)
+}
+
+// weighted returns the addresses of a leaf with their weights. It must be
+// called with the mutex held.
+func (l *trafficSplitListener) weighted(id serviceID) []*updateAddress {
+	addresses := make([]*updateAddress, 0, len(l.addresses[id]))
+	for _, a := range l.addresses[id] {
+		addresses = append(addresses, a)
+	}
+	return l.withWeight(addresses, l.endpointWeight(id))
+}
+
+// endpointWeight returns the weight of each endpoint of a leaf, or zero if
+// the apex service has no traffic split. It must be called with the mutex
+// held.
+func (l *trafficSplitListener) endpointWeight(id serviceID) uint32 {
+	count := int64(len(l.addresses[id]))
+	if l.totalWeight == 0 || l.weights[id] == 0 || count == 0 {
+		return 0
+	}
+	weight := l.weights[id] * splitWeightScale / l.totalWeight / count
+	if weight < 1 {
+		weight = 1
+	}
+	return uint32(weight)
+}
+
+// withWeight returns copies of the addresses with the weight, since the
+// addresses are shared with the other listeners of the leaf.
+func (l *trafficSplitListener) withWeight(addresses []*updateAddress, weight uint32) []*updateAddress {
+	if weight == 0 {
+		return addresses
+	}
+	weighted := make([]*updateAddress, len(addresses))
+	for i, a := range addresses {
+		weightedAddr := *a
+		weightedAddr.weight = weight
+		weighted[i] = &weightedAddr
+	}
+	return weighted
+}
+
+func equalWeights(a, b map[serviceID]int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, weight := range a {
+		if other, ok := b[id]; !ok || other != weight {
+			return false
+		}
+	}
+	return true
+}
+
+// leafListener implements the endpointUpdateListener interface for a backend
+// of a traffic split, forwarding its updates to the trafficSplitListener.
+type leafListener struct {
+	parent *trafficSplitListener
+	id     serviceID
+}
+
+func (l *leafListener) Update(add, remove []*updateAddress) {
+	l.parent.update(l.id, add, remove)
+}
+
+func (l *leafListener) NoEndpoints(exists bool) {
+	l.parent.noEndpoints(l.id, exists)
+}
+
+func (l *leafListener) ClientClose() <-chan struct{} {
+	return l.parent.ClientClose()
+}
+
+func (l *leafListener) ServerClose() <-chan struct{} {
+	return l.parent.ServerClose()
+}
+
+func (l *leafListener) SetServiceID(id *serviceID) {}
+
+func (l *leafListener) Stop() {
+	l.parent.Stop()
+}
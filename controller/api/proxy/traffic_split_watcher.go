@@ -0,0 +1,170 @@
+package proxy
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	ts "github.com/linkerd/linkerd2/controller/gen/apis/split/v1alpha1"
+	tslisters "github.com/linkerd/linkerd2/controller/gen/client/listers/split/v1alpha1"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+type trafficSplitUpdateListener interface {
+	// UpdateTrafficSplit is called with the traffic split of the apex service
+	// that the listener subscribed to, or nil if it has none.
+	UpdateTrafficSplit(split *ts.TrafficSplit)
+	Stop()
+}
+
+// trafficSplitWatcher watches all traffic splits in the Kubernetes cluster.
+// Listeners can subscribe to an apex service and trafficSplitWatcher will
+// publish its traffic split and all future changes of it.
+type trafficSplitWatcher struct {
+	splitLister tslisters.TrafficSplitLister
+	// a map of apex service -> listeners
+	listeners map[serviceID]map[trafficSplitUpdateListener]struct{}
+	mutex     sync.RWMutex
+}
+
+func newTrafficSplitWatcher(k8sAPI *k8s.API) *trafficSplitWatcher {
+	watcher := &trafficSplitWatcher{
+		splitLister: k8sAPI.TS().Lister(),
+		listeners:   make(map[serviceID]map[trafficSplitUpdateListener]struct{}),
+		mutex:       sync.RWMutex{},
+	}
+
+	k8sAPI.TS().Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    watcher.addSplit,
+			UpdateFunc: watcher.updateSplit,
+			DeleteFunc: watcher.deleteSplit,
+		},
+	)
+
+	return watcher
+}
+
+// Close all open streams on shutdown
+func (w *trafficSplitWatcher) stop() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, listeners := range w.listeners {
+		for listener := range listeners {
+			listener.Stop()
+		}
+	}
+	w.listeners = make(map[serviceID]map[trafficSplitUpdateListener]struct{})
+}
+
+func (w *trafficSplitWatcher) subscribe(apex serviceID, listener trafficSplitUpdateListener) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	split, err := w.getSplit(apex)
+	if err != nil {
+		log.Errorf("Error getting traffic split: %s", err)
+		return err
+	}
+
+	listeners, ok := w.listeners[apex]
+	if !ok {
+		listeners = make(map[trafficSplitUpdateListener]struct{})
+		w.listeners[apex] = listeners
+	}
+	listeners[listener] = struct{}{}
+	listener.UpdateTrafficSplit(split)
+	return nil
+}
+
+func (w *trafficSplitWatcher) unsubscribe(apex serviceID, listener trafficSplitUpdateListener) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	listeners, ok := w.listeners[apex]
+	if !ok {
+		return fmt.Errorf("Cannot unsubscribe from traffic split of %s: not subscribed", apex)
+	}
+	if _, ok := listeners[listener]; !ok {
+		return fmt.Errorf("Cannot unsubscribe from traffic split of %s: not subscribed", apex)
+	}
+	delete(listeners, listener)
+	if len(listeners) == 0 {
+		delete(w.listeners, apex)
+	}
+	return nil
+}
+
+// getSplit returns the traffic split of the apex service, or nil if it has
+// none. If several splits share the apex service, the first one by name is
+// used, so that all the proxies pick the same one.
+func (w *trafficSplitWatcher) getSplit(apex serviceID) (*ts.TrafficSplit, error) {
+	splits, err := w.splitLister.TrafficSplits(apex.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]*ts.TrafficSplit, 0)
+	for _, split := range splits {
+		if split.Spec.Service == apex.name {
+			matching = append(matching, split)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, nil
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].Name < matching[j].Name
+	})
+	return matching[0], nil
+}
+
+func (w *trafficSplitWatcher) addSplit(obj interface{}) {
+	split := obj.(*ts.TrafficSplit)
+	w.publish(serviceID{namespace: split.Namespace, name: split.Spec.Service})
+}
+
+func (w *trafficSplitWatcher) updateSplit(oldObj, newObj interface{}) {
+	oldSplit := oldObj.(*ts.TrafficSplit)
+	newSplit := newObj.(*ts.TrafficSplit)
+	if oldSplit.Spec.Service != newSplit.Spec.Service {
+		// the split moved to another apex service
+		w.publish(serviceID{namespace: oldSplit.Namespace, name: oldSplit.Spec.Service})
+	}
+	w.publish(serviceID{namespace: newSplit.Namespace, name: newSplit.Spec.Service})
+}
+
+func (w *trafficSplitWatcher) deleteSplit(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	split, ok := obj.(*ts.TrafficSplit)
+	if !ok {
+		return
+	}
+	w.publish(serviceID{namespace: split.Namespace, name: split.Spec.Service})
+}
+
+// publish sends the current traffic split of the apex service to its
+// listeners.
+func (w *trafficSplitWatcher) publish(apex serviceID) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	listeners, ok := w.listeners[apex]
+	if !ok {
+		return
+	}
+	split, err := w.getSplit(apex)
+	if err != nil {
+		log.Errorf("Error getting traffic split of %s: %s", apex, err)
+		return
+	}
+	for listener := range listeners {
+		listener.UpdateTrafficSplit(split)
+	}
+}
@@ -0,0 +1,224 @@
+package proxy
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/addr"
+)
+
+func splitTestConfigs() []string {
+	configs := []string{`
+apiVersion: split.smi-spec.io/v1alpha1
+kind: TrafficSplit
+metadata:
+  name: web-split
+  namespace: ns
+spec:
+  service: web
+  backends:
+  - service: web-v1
+    weight: 900m
+  - service: web-v2
+    weight: 100m
+  - service: web-v3
+    weight: 0`,
+	}
+
+	services := map[string][]string{
+		"web":    {"172.17.0.1", "172.17.0.2", "172.17.0.3"},
+		"web-v1": {"172.17.0.1", "172.17.0.2"},
+		"web-v2": {"172.17.0.3"},
+	}
+	for name, ips := range services {
+		addresses := ""
+		for _, ip := range ips {
+			addresses += fmt.Sprintf(`
+  - ip: %s
+    targetRef:
+      kind: Pod
+      name: pod-%s
+      namespace: ns`, ip, ip)
+		}
+		configs = append(configs, fmt.Sprintf(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: %s
+  namespace: ns
+spec:
+  type: ClusterIP
+  ports:
+  - port: 8080`, name), fmt.Sprintf(`
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: %s
+  namespace: ns
+subsets:
+- addresses:%s
+  ports:
+  - port: 8080`, name, addresses))
+	}
+
+	for _, ip := range services["web"] {
+		configs = append(configs, fmt.Sprintf(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod-%s
+  namespace: ns
+status:
+  phase: Running
+  podIP: %s`, ip, ip))
+	}
+	return configs
+}
+
+// applyUpdates applies the updates that the listener collected to the weights
+// of the addresses, and clears them.
+func applyUpdates(weights map[string]uint32, listener *collectUpdateListener) map[string]uint32 {
+	for _, a := range listener.added {
+		weights[addr.ProxyAddressToString(a.address)] = a.weight
+	}
+	for _, a := range listener.removed {
+		delete(weights, addr.ProxyAddressToString(a.address))
+	}
+	listener.added = nil
+	listener.removed = nil
+	return weights
+}
+
+func TestTrafficSplitListener(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("", splitTestConfigs()...)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+
+	endpoints := newEndpointsWatcher(k8sAPI, time.Minute, 0)
+	splits := newTrafficSplitWatcher(k8sAPI)
+	k8sAPI.Sync()
+
+	apex := serviceID{namespace: "ns", name: "web"}
+	listener, cancelFn := newCollectUpdateListener()
+	defer cancelFn()
+	splitListener := newTrafficSplitListener(apex, 8080, endpoints, listener)
+	actual := make(map[string]uint32)
+
+	t.Run("Resolves the apex service to the weighted endpoints of its backends", func(t *testing.T) {
+		if err := splits.subscribe(apex, splitListener); err != nil {
+			t.Fatalf("subscribe returned an error: %s", err)
+		}
+
+		// web-v1's weight is shared by its two endpoints
+		expected := map[string]uint32{
+			"172.17.0.1:8080": 450000,
+			"172.17.0.2:8080": 450000,
+			"172.17.0.3:8080": 100000,
+		}
+		if applyUpdates(actual, listener); !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("Expected weights %v, got %v", expected, actual)
+		}
+	})
+
+	t.Run("Republishes the endpoints when the weights change", func(t *testing.T) {
+		split, err := splits.getSplit(apex)
+		if err != nil || split == nil {
+			t.Fatalf("Expected the split of %s, got %v (%v)", apex, split, err)
+		}
+		split = split.DeepCopy()
+		split.Spec.Backends = split.Spec.Backends[:1]
+		splitListener.UpdateTrafficSplit(split)
+
+		expected := map[string]uint32{
+			"172.17.0.1:8080": 500000,
+			"172.17.0.2:8080": 500000,
+		}
+		if applyUpdates(actual, listener); !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("Expected weights %v, got %v", expected, actual)
+		}
+	})
+
+	t.Run("Resolves the apex service to its own endpoints without a split", func(t *testing.T) {
+		splitListener.UpdateTrafficSplit(nil)
+
+		expected := map[string]uint32{
+			"172.17.0.1:8080": 0,
+			"172.17.0.2:8080": 0,
+			"172.17.0.3:8080": 0,
+		}
+		if applyUpdates(actual, listener); !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("Expected weights %v, got %v", expected, actual)
+		}
+		if listener.noEndpointsCalled {
+			t.Fatal("Expected the endpoints to never be missing")
+		}
+	})
+
+	if err := splits.unsubscribe(apex, splitListener); err != nil {
+		t.Fatalf("unsubscribe returned an error: %s", err)
+	}
+	if err := splitListener.unsubscribeAll(); err != nil {
+		t.Fatalf("unsubscribeAll returned an error: %s", err)
+	}
+}
+
+func TestTrafficSplitWatcher(t *testing.T) {
+	t.Run("Picks the first split of an apex service by name", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI("", `
+apiVersion: split.smi-spec.io/v1alpha1
+kind: TrafficSplit
+metadata:
+  name: web-split-b
+  namespace: ns
+spec:
+  service: web
+  backends:
+  - service: web-v2
+    weight: 1`, `
+apiVersion: split.smi-spec.io/v1alpha1
+kind: TrafficSplit
+metadata:
+  name: web-split-a
+  namespace: ns
+spec:
+  service: web
+  backends:
+  - service: web-v1
+    weight: 1`, `
+apiVersion: split.smi-spec.io/v1alpha1
+kind: TrafficSplit
+metadata:
+  name: api-split
+  namespace: ns
+spec:
+  service: api
+  backends:
+  - service: api-v1
+    weight: 1`)
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+		watcher := newTrafficSplitWatcher(k8sAPI)
+		k8sAPI.Sync()
+
+		split, err := watcher.getSplit(serviceID{namespace: "ns", name: "web"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if split == nil || split.Name != "web-split-a" {
+			t.Fatalf("Expected the web-split-a split, got %v", split)
+		}
+
+		split, err = watcher.getSplit(serviceID{namespace: "ns", name: "emoji"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if split != nil {
+			t.Fatalf("Expected no split, got %v", split)
+		}
+	})
+}
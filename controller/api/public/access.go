@@ -0,0 +1,273 @@
+package public
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+// AccessConfig configures the rate limits and the audit log of the clients of
+// the public API, so that the dashboards and CLIs of a shared cluster can't
+// overload Prometheus and the Kubernetes API.
+type AccessConfig struct {
+	// RequestRate is the number of requests per second that each client can
+	// make, with bursts of RequestBurst. 0 disables the limit.
+	RequestRate  float64
+	RequestBurst int
+
+	// AuditLog logs each request with its client, method and parameters.
+	AuditLog bool
+}
+
+// auditRequests returns the requests of the methods of the API, to decode the
+// parameters of the requests for the audit log.
+var auditRequests = map[string]func() proto.Message{
+	statSummaryPath:   func() proto.Message { return &pb.StatSummaryRequest{} },
+	topRoutesPath:     func() proto.Message { return &pb.TopRoutesRequest{} },
+	edgesPath:         func() proto.Message { return &pb.EdgesRequest{} },
+	watchEdgesPath:    func() proto.Message { return &pb.EdgesRequest{} },
+	versionPath:       func() proto.Message { return &pb.Empty{} },
+	listPodsPath:      func() proto.Message { return &pb.ListPodsRequest{} },
+	listServicesPath:  func() proto.Message { return &pb.ListServicesRequest{} },
+	tapByResourcePath: func() proto.Message { return &pb.TapByResourceRequest{} },
+	selfCheckPath:     func() proto.Message { return &healthcheckPb.SelfCheckRequest{} },
+	trustBundlePath:   func() proto.Message { return &pb.Empty{} },
+	gatewaysPath:      func() proto.Message { return &pb.GatewaysRequest{} },
+}
+
+// accessHandler rate limits the requests of each client of the API, and logs
+// them to the audit log.
+type accessHandler struct {
+	next http.Handler
+
+	// limiter is nil if the requests aren't rate limited
+	limiter *clientLimiter
+	audit   bool
+	now     func() time.Time
+}
+
+func newAccessHandler(next http.Handler, config AccessConfig) *accessHandler {
+	h := &accessHandler{next: next, audit: config.AuditLog, now: time.Now}
+	if config.RequestRate > 0 {
+		h.limiter = newClientLimiter(config.RequestRate, config.RequestBurst)
+	}
+	return h
+}
+
+func (h *accessHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	client := requestClient(req)
+	start := h.now()
+
+	var params string
+	if h.audit {
+		params = auditParams(req)
+	}
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+	if delay := h.limiter.reserve(client, start); delay > 0 {
+		recorder.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+		err := httpError{
+			Code:         http.StatusTooManyRequests,
+			WrappedError: fmt.Errorf("too many requests from %s, retry in %s", client, delay.Round(time.Millisecond)),
+		}
+		if isJSONRequest(req) {
+			writeJSONErrorToHTTPResponse(recorder, err)
+		} else {
+			writeErrorToHTTPResponse(recorder, err)
+		}
+	} else {
+		h.next.ServeHTTP(recorder, req)
+	}
+
+	if h.audit {
+		log.WithFields(log.Fields{
+			"client":   client,
+			"method":   strings.TrimPrefix(req.URL.Path, apiRoot+apiPrefix),
+			"params":   params,
+			"status":   recorder.status,
+			"error":    recorder.Header().Get(errorHeader),
+			"duration": h.now().Sub(start),
+		}).Info("public API request")
+	}
+}
+
+// localClient identifies the clients that connect over the loopback
+// interface, through the pod's proxy or a port-forward, without a
+// clientIDHeader. They share a single rate limit, so when the public API is
+// meshed, the clients that the proxy can't identify, because they don't
+// connect to it over TLS, are limited together.
+const localClient = "local"
+
+// clientIDHeader is the header that the proxy sets on the inbound requests of
+// the clients that connect to it over TLS, to their identity. The proxy
+// replaces the header when the clients set it themselves, so it's only
+// trusted on the requests that come from the loopback interface.
+const clientIDHeader = "l5d-client-id"
+
+// requestClient identifies the client of a request by the identity that the
+// pod's proxy sets in its clientIDHeader, or else by its address. On
+// -tls-addr, all the clients present the certificate of the API client
+// identity, so its name is combined with their address, and so the
+// port-forwarded clients on -tls-addr share a limit too. The X-Forwarded-For
+// header is set by the clients themselves, so it's ignored.
+func requestClient(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		if id := req.Header.Get(clientIDHeader); id != "" {
+			return id
+		}
+		host = localClient
+	}
+
+	if req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+		cert := req.TLS.PeerCertificates[0]
+		if len(cert.DNSNames) > 0 {
+			return cert.DNSNames[0] + "@" + host
+		}
+		if cert.Subject.CommonName != "" {
+			return cert.Subject.CommonName + "@" + host
+		}
+	}
+	return host
+}
+
+// auditParams returns the parameters of a request as JSON, and restores its
+// body for the handler. The requests of unknown methods, or that can't be
+// decoded, have no parameters; the handler reports their errors.
+func auditParams(req *http.Request) string {
+	newRequest, ok := auditRequests[req.URL.Path]
+	if !ok || req.Body == nil {
+		return ""
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	msg := newRequest()
+	if isJSONRequest(req) {
+		if len(bytes.TrimSpace(body)) > 0 {
+			err = jsonpb.Unmarshal(bytes.NewReader(body), msg)
+		}
+	} else {
+		err = proto.Unmarshal(body, msg)
+	}
+	if err != nil {
+		return ""
+	}
+
+	params, err := (&jsonpb.Marshaler{}).MarshalToString(msg)
+	if err != nil {
+		return ""
+	}
+	return params
+}
+
+// statusRecorder records the status of a response for the audit log. It
+// flushes the responses of the streaming methods.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// clientLimiter rate limits the requests of each client. The nil limiter
+// doesn't limit them.
+type clientLimiter struct {
+	limit rate.Limit
+	burst int
+
+	// clients are the limiters of the clients. The limiters that have been
+	// idle long enough to refill are pruned at nextPrune, since they're then
+	// the same as new ones.
+	clients   map[string]*clientLimiterEntry
+	refill    time.Duration
+	nextPrune time.Time
+	mu        sync.Mutex
+}
+
+type clientLimiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newClientLimiter(requestRate float64, burst int) *clientLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &clientLimiter{
+		limit:   rate.Limit(requestRate),
+		burst:   burst,
+		clients: make(map[string]*clientLimiterEntry),
+		refill:  time.Duration(float64(burst) / requestRate * float64(time.Second)),
+	}
+}
+
+// reserve reserves a request of the client at now. It returns zero if the
+// request can be served right away, or otherwise how long to wait before
+// retrying, in which case nothing is reserved.
+func (l *clientLimiter) reserve(client string, now time.Time) time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune(now)
+	entry, ok := l.clients[client]
+	if !ok {
+		entry = &clientLimiterEntry{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[client] = entry
+	}
+	entry.lastSeen = now
+
+	reservation := entry.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return delay
+	}
+	return 0
+}
+
+// prune removes the client limiters that have been idle for long enough to
+// be full again.
+func (l *clientLimiter) prune(now time.Time) {
+	if now.Before(l.nextPrune) {
+		return
+	}
+	for client, entry := range l.clients {
+		if now.Sub(entry.lastSeen) >= l.refill {
+			delete(l.clients, client)
+		}
+	}
+	l.nextPrune = now.Add(l.refill)
+}
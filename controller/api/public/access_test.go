@@ -0,0 +1,174 @@
+package public
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+)
+
+func TestClientLimiter(t *testing.T) {
+	limiter := newClientLimiter(2, 2)
+	now := time.Now()
+
+	testCases := []struct {
+		client string
+		at     time.Duration
+		delay  time.Duration
+	}{
+		{"10.0.0.1", 0, 0},
+		{"10.0.0.1", 0, 0},
+		{"10.0.0.1", 0, 500 * time.Millisecond},
+		{"10.0.0.2", 0, 0},
+		{"10.0.0.1", 500 * time.Millisecond, 0},
+	}
+
+	for i, tc := range testCases {
+		if delay := limiter.reserve(tc.client, now.Add(tc.at)); delay != tc.delay {
+			t.Fatalf("%d: Expected reserve(%s) to return %s, got %s", i, tc.client, tc.delay, delay)
+		}
+	}
+
+	t.Run("Prunes the idle clients", func(t *testing.T) {
+		limiter.reserve("10.0.0.1", now.Add(time.Minute))
+		if len(limiter.clients) != 1 {
+			t.Fatalf("Expected 1 client limiter, got %d", len(limiter.clients))
+		}
+	})
+
+	t.Run("Doesn't limit the requests without a limiter", func(t *testing.T) {
+		var limiter *clientLimiter
+		if delay := limiter.reserve("10.0.0.1", now); delay != 0 {
+			t.Fatalf("Expected no delay, got %s", delay)
+		}
+	})
+}
+
+func TestRequestClient(t *testing.T) {
+	t.Run("Identifies the client by its certificate and its address", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, statSummaryPath, nil)
+		req.RemoteAddr = "10.0.0.1:51234"
+		req.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{DNSNames: []string{"linkerd-web.linkerd.serviceaccount.identity.linkerd.cluster.local"}}},
+		}
+		if client := requestClient(req); client != "linkerd-web.linkerd.serviceaccount.identity.linkerd.cluster.local@10.0.0.1" {
+			t.Fatalf("Unexpected client: %s", client)
+		}
+
+		req.RemoteAddr = "127.0.0.1:51234"
+		req.TLS.PeerCertificates = []*x509.Certificate{{Subject: pkix.Name{CommonName: "dashboard"}}}
+		if client := requestClient(req); client != "dashboard@"+localClient {
+			t.Fatalf("Unexpected client: %s", client)
+		}
+	})
+
+	t.Run("Identifies the client by the identity that the proxy sets", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, statSummaryPath, nil)
+		req.RemoteAddr = "127.0.0.1:51234"
+		req.Header.Set(clientIDHeader, "default.emojivoto.serviceaccount.identity.linkerd.cluster.local")
+		if client := requestClient(req); client != "default.emojivoto.serviceaccount.identity.linkerd.cluster.local" {
+			t.Fatalf("Unexpected client: %s", client)
+		}
+	})
+
+	t.Run("Ignores the client identity of the requests that bypass the proxy", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, statSummaryPath, nil)
+		req.RemoteAddr = "10.0.0.1:51234"
+		req.Header.Set(clientIDHeader, "default.emojivoto.serviceaccount.identity.linkerd.cluster.local")
+		if client := requestClient(req); client != "10.0.0.1" {
+			t.Fatalf("Unexpected client: %s", client)
+		}
+	})
+
+	t.Run("Identifies the client by its address", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, statSummaryPath, nil)
+		req.RemoteAddr = "10.0.0.1:51234"
+		if client := requestClient(req); client != "10.0.0.1" {
+			t.Fatalf("Unexpected client: %s", client)
+		}
+	})
+
+	t.Run("Ignores the forwarded address", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, statSummaryPath, nil)
+		req.RemoteAddr = "10.0.0.1:51234"
+		req.Header.Set("X-Forwarded-For", "192.168.1.10")
+		if client := requestClient(req); client != "10.0.0.1" {
+			t.Fatalf("Unexpected client: %s", client)
+		}
+	})
+
+	t.Run("Identifies the loopback clients together", func(t *testing.T) {
+		for _, addr := range []string{"127.0.0.1:51234", "[::1]:51234"} {
+			req := httptest.NewRequest(http.MethodPost, statSummaryPath, nil)
+			req.RemoteAddr = addr
+			if client := requestClient(req); client != localClient {
+				t.Fatalf("Expected %s to be identified as %s, got %s", addr, localClient, client)
+			}
+		}
+	})
+}
+
+func TestAccessHandler(t *testing.T) {
+	mockGrpcServer := &mockGrpcServer{ResponseToReturn: &pb.VersionInfo{ReleaseVersion: "stable-2.1.0"}}
+	h := newAccessHandler(&handler{grpcServer: mockGrpcServer}, AccessConfig{RequestRate: 1, RequestBurst: 1, AuditLog: true})
+
+	serve := func(contentType string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, versionPath, strings.NewReader(""))
+		req.Header.Set("Content-Type", contentType)
+		req.RemoteAddr = "10.0.0.1:51234"
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, req)
+		return rsp
+	}
+
+	if rsp := serve("application/json"); rsp.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rsp.Code, rsp.Body.String())
+	}
+	if !proto.Equal(mockGrpcServer.LastRequestReceived, &pb.Empty{}) {
+		t.Fatalf("Expected the request to reach the API, got %v", mockGrpcServer.LastRequestReceived)
+	}
+
+	t.Run("Rejects the JSON requests past the rate limit", func(t *testing.T) {
+		rsp := serve("application/json")
+		if rsp.Code != http.StatusTooManyRequests {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusTooManyRequests, rsp.Code, rsp.Body.String())
+		}
+		if retryAfter := rsp.Header().Get("Retry-After"); retryAfter != "1" {
+			t.Fatalf("Expected Retry-After 1, got %s", retryAfter)
+		}
+	})
+
+	t.Run("Rejects the protobuf requests past the rate limit", func(t *testing.T) {
+		rsp := serve("application/octet-stream")
+		if errorMsg := rsp.Header().Get(errorHeader); errorMsg != http.StatusText(http.StatusTooManyRequests) {
+			t.Fatalf("Expected the %s header to be %s, got %s", errorHeader, http.StatusText(http.StatusTooManyRequests), errorMsg)
+		}
+	})
+}
+
+func TestAuditParams(t *testing.T) {
+	body, err := proto.Marshal(&pb.ListPodsRequest{Namespace: "emojivoto"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, listPodsPath, strings.NewReader(string(body)))
+
+	if params := auditParams(req); params != `{"namespace":"emojivoto"}` {
+		t.Fatalf("Unexpected params: %s", params)
+	}
+
+	var restored pb.ListPodsRequest
+	if err := httpRequestToProto(req, &restored); err != nil {
+		t.Fatalf("Expected the body to be restored, got error: %s", err)
+	}
+	if restored.Namespace != "emojivoto" {
+		t.Fatalf("Expected the body to be restored, got %v", restored)
+	}
+}
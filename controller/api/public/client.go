@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -44,12 +45,51 @@ func (c *grpcOverHTTPClient) TopRoutes(ctx context.Context, req *pb.TopRoutesReq
 	return &msg, err
 }
 
+func (c *grpcOverHTTPClient) Edges(ctx context.Context, req *pb.EdgesRequest, _ ...grpc.CallOption) (*pb.EdgesResponse, error) {
+	var msg pb.EdgesResponse
+	err := c.apiRequest(ctx, "Edges", req, &msg)
+	return &msg, err
+}
+
+func (c *grpcOverHTTPClient) WatchEdges(ctx context.Context, req *pb.EdgesRequest, _ ...grpc.CallOption) (pb.Api_WatchEdgesClient, error) {
+	url := c.endpointNameToPublicAPIURL("WatchEdges")
+	httpRsp, err := c.post(ctx, url, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkIfResponseHasError(httpRsp); err != nil {
+		httpRsp.Body.Close()
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		log.Debug("Closing response body after context marked as done")
+		httpRsp.Body.Close()
+	}()
+
+	return &watchEdgesClient{ctx: ctx, reader: bufio.NewReader(httpRsp.Body)}, nil
+}
+
 func (c *grpcOverHTTPClient) Version(ctx context.Context, req *pb.Empty, _ ...grpc.CallOption) (*pb.VersionInfo, error) {
 	var msg pb.VersionInfo
 	err := c.apiRequest(ctx, "Version", req, &msg)
 	return &msg, err
 }
 
+func (c *grpcOverHTTPClient) TrustBundle(ctx context.Context, req *pb.Empty, _ ...grpc.CallOption) (*pb.TrustBundleResponse, error) {
+	var msg pb.TrustBundleResponse
+	err := c.apiRequest(ctx, "TrustBundle", req, &msg)
+	return &msg, err
+}
+
+func (c *grpcOverHTTPClient) Gateways(ctx context.Context, req *pb.GatewaysRequest, _ ...grpc.CallOption) (*pb.GatewaysResponse, error) {
+	var msg pb.GatewaysResponse
+	err := c.apiRequest(ctx, "Gateways", req, &msg)
+	return &msg, err
+}
+
 func (c *grpcOverHTTPClient) SelfCheck(ctx context.Context, req *healthcheckPb.SelfCheckRequest, _ ...grpc.CallOption) (*healthcheckPb.SelfCheckResponse, error) {
 	var msg healthcheckPb.SelfCheckResponse
 	err := c.apiRequest(ctx, "SelfCheck", req, &msg)
@@ -160,6 +200,25 @@ func (c tapClient) Context() context.Context     { return c.ctx }
 func (c tapClient) SendMsg(interface{}) error    { return nil }
 func (c tapClient) RecvMsg(interface{}) error    { return nil }
 
+type watchEdgesClient struct {
+	ctx    context.Context
+	reader *bufio.Reader
+}
+
+func (c watchEdgesClient) Recv() (*pb.EdgeEvent, error) {
+	var msg pb.EdgeEvent
+	err := fromByteStreamToProtocolBuffers(c.reader, &msg)
+	return &msg, err
+}
+
+// satisfy the pb.Api_WatchEdgesClient interface
+func (c watchEdgesClient) Header() (metadata.MD, error) { return nil, nil }
+func (c watchEdgesClient) Trailer() metadata.MD         { return nil }
+func (c watchEdgesClient) CloseSend() error             { return nil }
+func (c watchEdgesClient) Context() context.Context     { return c.ctx }
+func (c watchEdgesClient) SendMsg(interface{}) error    { return nil }
+func (c watchEdgesClient) RecvMsg(interface{}) error    { return nil }
+
 func fromByteStreamToProtocolBuffers(byteStreamContainingMessage *bufio.Reader, out proto.Message) error {
 	messageAsBytes, err := deserializePayloadFromReader(byteStreamContainingMessage)
 	if err != nil {
@@ -201,6 +260,21 @@ func NewInternalClient(controlPlaneNamespace string, kubeAPIHost string) (pb.Api
 	return newClient(apiURL, http.DefaultClient, controlPlaneNamespace)
 }
 
+// NewMutualTLSClient creates a new Public API client that connects to the TLS
+// port of the public API at host:port addr, such as through a port-forward,
+// authenticating with the client certificate of tlsConfig.
+func NewMutualTLSClient(controlPlaneNamespace string, addr string, tlsConfig *tls.Config) (pb.ApiClient, error) {
+	apiURL, err := url.Parse(fmt.Sprintf("https://%s/", addr))
+	if err != nil {
+		return nil, err
+	}
+
+	httpClientToUse := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+	return newClient(apiURL, httpClientToUse, controlPlaneNamespace)
+}
+
 // NewExternalClient creates a new Public API client intended to run from
 // outside a Kubernetes cluster.
 func NewExternalClient(controlPlaneNamespace string, kubeAPI *k8s.KubernetesAPI) (pb.ApiClient, error) {
@@ -0,0 +1,451 @@
+package public
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/util"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/common/model"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	edgesQuery = "sum(increase(response_total%s[%s])) by (%s, tls, no_tls_reason)"
+
+	// unmeshedEdgesQuery counts the inbound requests of the selected
+	// resources from clients that didn't present an identity.
+	unmeshedEdgesQuery = "sum(increase(response_total%s[%s])) by (%s, no_tls_reason)"
+
+	// edgesLastSeenQuery returns the time of the latest metrics of each edge,
+	// if they were scraped within Prometheus' lookback window.
+	edgesLastSeenQuery = "max(timestamp(response_total%s)) by (%s)"
+
+	defaultEdgesTimeWindow = "1m"
+
+	unmeshedSrcMsg = "source not meshed"
+	partialTLSMsg  = "not all requests secured"
+)
+
+// noTLSReasonMsgs explains why the requests of an edge weren't secured, from
+// the no_tls_reason label of its metrics.
+var noTLSReasonMsgs = map[model.LabelValue]string{
+	"disabled":                          "TLS disabled",
+	"loopback":                          "loopback traffic",
+	"not_provided_by_service_discovery": "destination not meshed",
+	noIdentityReason:                    unmeshedSrcMsg,
+}
+
+// edgesWatchInterval is how often WatchEdges re-queries Prometheus for changes.
+var edgesWatchInterval = 10 * time.Second
+
+type edgeKey struct {
+	src rKey
+	dst rKey
+}
+
+type edgeCounts struct {
+	total uint64
+	tls   uint64
+	// the no_tls_reason of the plaintext requests, if any
+	noTLSReason model.LabelValue
+}
+
+func (s *grpcServer) Edges(ctx context.Context, req *pb.EdgesRequest) (*pb.EdgesResponse, error) {
+	if err := validateEdgesRequest(req); err != nil {
+		return edgesError(req, err.Error()), nil
+	}
+
+	edges, err := s.getEdges(ctx, req)
+	if err != nil {
+		return nil, util.GRPCError(err)
+	}
+
+	return &pb.EdgesResponse{
+		Response: &pb.EdgesResponse_Ok_{
+			Ok: &pb.EdgesResponse_Ok{
+				Edges: edges,
+			},
+		},
+	}, nil
+}
+
+func (s *grpcServer) WatchEdges(req *pb.EdgesRequest, stream pb.Api_WatchEdgesServer) error {
+	if err := validateEdgesRequest(req); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ticker := time.NewTicker(edgesWatchInterval)
+	defer ticker.Stop()
+
+	known := make(map[edgeKey]*pb.Edge)
+	for {
+		edges, err := s.getEdges(stream.Context(), req)
+		if err != nil {
+			return util.GRPCError(err)
+		}
+
+		for _, event := range diffEdges(known, edges) {
+			if err := stream.Send(event); err != nil {
+				log.Debugf("Failed to send edge event: %s", err)
+				return err
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func validateEdgesRequest(req *pb.EdgesRequest) error {
+	resource := req.GetSelector().GetResource()
+	if resource == nil {
+		return fmt.Errorf("Edges request missing Selector Resource")
+	}
+
+	switch resource.GetType() {
+	case k8s.All, k8s.Authority, k8s.Service, "":
+		return fmt.Errorf("resource type '%s' is not supported for edges", resource.GetType())
+	}
+
+	return nil
+}
+
+func edgesError(req *pb.EdgesRequest, message string) *pb.EdgesResponse {
+	return &pb.EdgesResponse{
+		Response: &pb.EdgesResponse_Error{
+			Error: &pb.ResourceError{
+				Resource: req.GetSelector().GetResource(),
+				Error:    message,
+			},
+		},
+	}
+}
+
+// getEdges returns the edges over which the selected resources sent or
+// received traffic in the request's time window, sorted by source and
+// destination.
+func (s *grpcServer) getEdges(ctx context.Context, req *pb.EdgesRequest) ([]*pb.Edge, error) {
+	resource := req.GetSelector().GetResource()
+
+	timeWindow := req.GetTimeWindow()
+	if timeWindow == "" {
+		timeWindow = defaultEdgesTimeWindow
+	}
+
+	groupBy := append(promGroupByLabelNames(resource), promDstGroupByLabelNames(resource)...)
+
+	// Edges are built from the outbound metrics of the source, so that
+	// both traffic sent by the selected resources and traffic they receive
+	// from meshed clients is included.
+	labelSets := []model.LabelSet{
+		promQueryLabels(resource).Merge(promDirectionLabels("outbound")),
+		promEdgeDstQueryLabels(resource).Merge(promDirectionLabels("outbound")),
+	}
+	if labelSets[0].Equal(labelSets[1]) {
+		labelSets = labelSets[:1]
+	}
+
+	counts := make(map[edgeKey]*edgeCounts)
+	lastSeen := make(map[edgeKey]int64)
+	for _, labels := range labelSets {
+		query := fmt.Sprintf(edgesQuery, labels.String(), timeWindow, groupBy.String())
+		vec, err := s.queryProm(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		// the same series may be returned by both queries; don't count it twice
+		queryCounts := processEdgeMetrics(resource, vec)
+		for key, c := range queryCounts {
+			if _, ok := counts[key]; !ok {
+				counts[key] = c
+			}
+		}
+
+		query = fmt.Sprintf(edgesLastSeenQuery, labels.String(), groupBy.String())
+		vec, err = s.queryProm(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, sample := range vec {
+			key, ok := sampleEdgeKey(resource, sample)
+			if ok && int64(sample.Value) > lastSeen[key] {
+				lastSeen[key] = int64(sample.Value)
+			}
+		}
+	}
+
+	edges := make([]*pb.Edge, 0, len(counts))
+	for key, c := range counts {
+		edge := &pb.Edge{
+			Src:      keyToResource(key.src),
+			Dst:      keyToResource(key.dst),
+			Tls:      c.total > 0 && c.tls == c.total,
+			LastSeen: lastSeen[key],
+		}
+		if edge.Tls {
+			edge.ClientId, edge.ServerId, edge.NoIdentityMsg = s.edgeIdentities(key)
+		} else {
+			edge.NoIdentityMsg = c.noIdentityMsg()
+		}
+		edges = append(edges, edge)
+	}
+
+	// Unmeshed clients have no outbound metrics, so their edges can only be
+	// found from the inbound metrics of the selected resources.
+	unmeshedLabels := promQueryLabels(resource).
+		Merge(promDirectionLabels("inbound")).
+		Merge(model.LabelSet{noTLSReasonLabel: noIdentityReason})
+	query := fmt.Sprintf(unmeshedEdgesQuery, unmeshedLabels.String(), timeWindow, promGroupByLabelNames(resource).String())
+	vec, err := s.queryProm(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	dsts := processUnmeshedEdgeMetrics(resource, vec)
+
+	unmeshedLastSeen := make(map[rKey]int64)
+	if len(dsts) > 0 {
+		query = fmt.Sprintf(edgesLastSeenQuery, unmeshedLabels.String(), promGroupByLabelNames(resource).String())
+		vec, err = s.queryProm(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, sample := range vec {
+			key, ok := sampleResourceKey(resource, sample, namespaceLabel, promResourceType(resource))
+			if ok && int64(sample.Value) > unmeshedLastSeen[key] {
+				unmeshedLastSeen[key] = int64(sample.Value)
+			}
+		}
+	}
+
+	for _, dst := range dsts {
+		edges = append(edges, &pb.Edge{
+			Src:           &pb.Resource{Type: resource.GetType()},
+			Dst:           keyToResource(dst),
+			UnmeshedSrc:   true,
+			NoIdentityMsg: unmeshedSrcMsg,
+			LastSeen:      unmeshedLastSeen[dst],
+		})
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		return edgeString(edges[i]) < edgeString(edges[j])
+	})
+
+	return edges, nil
+}
+
+func processEdgeMetrics(resource *pb.Resource, vec model.Vector) map[edgeKey]*edgeCounts {
+	counts := make(map[edgeKey]*edgeCounts)
+
+	for _, sample := range vec {
+		key, ok := sampleEdgeKey(resource, sample)
+		if !ok {
+			continue
+		}
+
+		if counts[key] == nil {
+			counts[key] = &edgeCounts{}
+		}
+
+		value := extractSampleValue(sample)
+		counts[key].total += value
+		if string(sample.Metric[model.LabelName("tls")]) == "true" {
+			counts[key].tls += value
+		} else if reason := sample.Metric[noTLSReasonLabel]; reason != "" && value > 0 {
+			counts[key].noTLSReason = reason
+		}
+	}
+
+	return counts
+}
+
+// sampleEdgeKey returns the edge of an outbound sample, or false if its
+// destination is outside of the mesh, in which case it carries no dst_ labels.
+func sampleEdgeKey(resource *pb.Resource, sample *model.Sample) (edgeKey, bool) {
+	srcLabel := promResourceType(resource)
+	dstLabel := model.LabelName("dst_" + srcLabel)
+
+	src, srcOk := sampleResourceKey(resource, sample, namespaceLabel, srcLabel)
+	dst, dstOk := sampleResourceKey(resource, sample, dstNamespaceLabel, dstLabel)
+	return edgeKey{src: src, dst: dst}, srcOk && dstOk
+}
+
+// sampleResourceKey returns the resource of a sample from the given labels,
+// or false if the sample has no name for it.
+func sampleResourceKey(resource *pb.Resource, sample *model.Sample, nsLabel, nameLabel model.LabelName) (rKey, bool) {
+	key := rKey{
+		Type:      resource.GetType(),
+		Namespace: string(sample.Metric[nsLabel]),
+		Name:      string(sample.Metric[nameLabel]),
+	}
+	if resource.GetType() == k8s.Namespace {
+		key.Namespace = ""
+	}
+	return key, key.Name != ""
+}
+
+// noIdentityMsg explains why an edge that isn't secured by TLS has no
+// identities.
+func (c *edgeCounts) noIdentityMsg() string {
+	if c.tls > 0 {
+		return partialTLSMsg
+	}
+	if msg, ok := noTLSReasonMsgs[c.noTLSReason]; ok {
+		return msg
+	}
+	if c.noTLSReason == "" {
+		return "TLS status unknown"
+	}
+	return strings.Replace(string(c.noTLSReason), "_", " ", -1)
+}
+
+// edgeIdentities returns the TLS identities of the client and the server of
+// a secured edge, or why they're unknown.
+func (s *grpcServer) edgeIdentities(key edgeKey) (string, string, string) {
+	clientID, err := s.resourceIdentity(key.src)
+	if err != nil {
+		return "", "", err.Error()
+	}
+	serverID, err := s.resourceIdentity(key.dst)
+	if err != nil {
+		return "", "", err.Error()
+	}
+	return clientID, serverID, ""
+}
+
+// resourceIdentity returns the TLS identity of the proxies of a resource. A
+// pod has the identity of its owner, and a namespace has none, since its pods
+// have different identities.
+func (s *grpcServer) resourceIdentity(key rKey) (string, error) {
+	switch key.Type {
+	case k8s.Namespace:
+		return "", fmt.Errorf("no single identity for namespaces")
+	case k8s.Pod:
+		pod, err := s.k8sAPI.Pod().Lister().Pods(key.Namespace).Get(key.Name)
+		if err != nil {
+			return "", fmt.Errorf("identity of pod %s unknown", key.Name)
+		}
+		kind, name := s.k8sAPI.GetOwnerKindAndName(pod)
+		return k8s.TLSIdentity{
+			Name:                name,
+			Kind:                kind,
+			Namespace:           pod.Namespace,
+			ControllerNamespace: pod.Labels[k8s.ControllerNSLabel],
+		}.ToDNSName(), nil
+	default:
+		return k8s.TLSIdentity{
+			Name:                key.Name,
+			Kind:                key.Type,
+			Namespace:           key.Namespace,
+			ControllerNamespace: s.controllerNamespace,
+		}.ToDNSName(), nil
+	}
+}
+
+// processUnmeshedEdgeMetrics returns the resources that received requests
+// from unmeshed clients.
+func processUnmeshedEdgeMetrics(resource *pb.Resource, vec model.Vector) []rKey {
+	dsts := make([]rKey, 0)
+
+	label := promResourceType(resource)
+	for _, sample := range vec {
+		if sample.Metric[noTLSReasonLabel] != noIdentityReason || extractSampleValue(sample) == 0 {
+			continue
+		}
+
+		if key, ok := sampleResourceKey(resource, sample, namespaceLabel, label); ok {
+			dsts = append(dsts, key)
+		}
+	}
+
+	return dsts
+}
+
+// promEdgeDstQueryLabels selects the traffic sent to the given resource.
+// Unlike promDstQueryLabels, the destination namespace is also used when no
+// name is given, so that all edges into a namespace are returned.
+func promEdgeDstQueryLabels(resource *pb.Resource) model.LabelSet {
+	set := model.LabelSet{}
+	if resource.Name != "" {
+		set["dst_"+promResourceType(resource)] = model.LabelValue(resource.Name)
+	}
+	if shouldAddNamespaceLabel(resource) {
+		set[dstNamespaceLabel] = model.LabelValue(resource.Namespace)
+	}
+	return set
+}
+
+// diffEdges compares the current edges with the known ones and returns the
+// events required to go from one to the other. known is updated in place.
+func diffEdges(known map[edgeKey]*pb.Edge, current []*pb.Edge) []*pb.EdgeEvent {
+	events := make([]*pb.EdgeEvent, 0)
+
+	seen := make(map[edgeKey]struct{}, len(current))
+	for _, edge := range current {
+		key := toEdgeKey(edge)
+		seen[key] = struct{}{}
+
+		previous, ok := known[key]
+		switch {
+		case !ok:
+			events = append(events, &pb.EdgeEvent{Type: pb.EdgeEvent_ADD, Edge: edge})
+		case previous.Tls != edge.Tls ||
+			previous.ClientId != edge.ClientId ||
+			previous.ServerId != edge.ServerId ||
+			previous.NoIdentityMsg != edge.NoIdentityMsg:
+			events = append(events, &pb.EdgeEvent{Type: pb.EdgeEvent_UPDATE, Edge: edge})
+		}
+		known[key] = edge
+	}
+
+	removed := make([]*pb.Edge, 0)
+	for key, edge := range known {
+		if _, ok := seen[key]; !ok {
+			removed = append(removed, edge)
+			delete(known, key)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		return edgeString(removed[i]) < edgeString(removed[j])
+	})
+	for _, edge := range removed {
+		events = append(events, &pb.EdgeEvent{Type: pb.EdgeEvent_REMOVE, Edge: edge})
+	}
+
+	return events
+}
+
+func toEdgeKey(edge *pb.Edge) edgeKey {
+	return edgeKey{
+		src: rKey{Type: edge.GetSrc().GetType(), Namespace: edge.GetSrc().GetNamespace(), Name: edge.GetSrc().GetName()},
+		dst: rKey{Type: edge.GetDst().GetType(), Namespace: edge.GetDst().GetNamespace(), Name: edge.GetDst().GetName()},
+	}
+}
+
+func keyToResource(key rKey) *pb.Resource {
+	return &pb.Resource{
+		Type:      key.Type,
+		Namespace: key.Namespace,
+		Name:      key.Name,
+	}
+}
+
+func edgeString(edge *pb.Edge) string {
+	if edge.GetUnmeshedSrc() {
+		return fmt.Sprintf("(unmeshed) -> %s/%s", edge.GetDst().GetNamespace(), edge.GetDst().GetName())
+	}
+	return fmt.Sprintf("%s/%s -> %s/%s",
+		edge.GetSrc().GetNamespace(), edge.GetSrc().GetName(),
+		edge.GetDst().GetNamespace(), edge.GetDst().GetName())
+}
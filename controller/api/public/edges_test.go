@@ -0,0 +1,202 @@
+package public
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/common/model"
+)
+
+func genEdgeSample(src, dst, tls string, value model.SampleValue) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"namespace":      "emojivoto",
+			"deployment":     model.LabelValue(src),
+			"dst_namespace":  "emojivoto",
+			"dst_deployment": model.LabelValue(dst),
+			"tls":            model.LabelValue(tls),
+		},
+		Value:     value,
+		Timestamp: 456,
+	}
+}
+
+func genUnmeshedSample(dst string, value model.SampleValue) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"namespace":     "emojivoto",
+			"deployment":    model.LabelValue(dst),
+			"no_tls_reason": "not_provided_by_remote",
+		},
+		Value:     value,
+		Timestamp: 456,
+	}
+}
+
+func genEdge(src, dst string, tls bool) *pb.Edge {
+	return &pb.Edge{
+		Src: &pb.Resource{Namespace: "emojivoto", Type: pkgK8s.Deployment, Name: src},
+		Dst: &pb.Resource{Namespace: "emojivoto", Type: pkgK8s.Deployment, Name: dst},
+		Tls: tls,
+	}
+}
+
+func identity(name string) string {
+	return name + ".deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local"
+}
+
+func TestEdges(t *testing.T) {
+	t.Run("Successfully performs an edges query", func(t *testing.T) {
+		// the mock returns the same samples for all queries, so their values
+		// are also the last-seen times of the edges
+		plaintext := genEdgeSample("emoji", "voting", "", 2)
+		plaintext.Metric["no_tls_reason"] = "not_provided_by_service_discovery"
+		exp := expectedStatRPC{
+			mockPromResponse: model.Vector{
+				genEdgeSample("web", "emoji", "true", 10),
+				genEdgeSample("web", "voting", "true", 5),
+				genEdgeSample("web", "voting", "", 1),
+				genEdgeSample("vote-bot", "", "", 3),
+				plaintext,
+				genUnmeshedSample("web", 7),
+				genUnmeshedSample("voting", 0),
+			},
+			expectedPrometheusQueries: []string{
+				`sum(increase(response_total{direction="outbound", dst_namespace="emojivoto"}[1m])) by (namespace, deployment, dst_namespace, dst_deployment, tls, no_tls_reason)`,
+				`sum(increase(response_total{direction="outbound", namespace="emojivoto"}[1m])) by (namespace, deployment, dst_namespace, dst_deployment, tls, no_tls_reason)`,
+				`max(timestamp(response_total{direction="outbound", dst_namespace="emojivoto"})) by (namespace, deployment, dst_namespace, dst_deployment)`,
+				`max(timestamp(response_total{direction="outbound", namespace="emojivoto"})) by (namespace, deployment, dst_namespace, dst_deployment)`,
+				`sum(increase(response_total{direction="inbound", namespace="emojivoto", no_tls_reason="not_provided_by_remote"}[1m])) by (namespace, deployment, no_tls_reason)`,
+				`max(timestamp(response_total{direction="inbound", namespace="emojivoto", no_tls_reason="not_provided_by_remote"})) by (namespace, deployment)`,
+			},
+		}
+
+		mockProm, fakeGrpcServer, err := newMockGrpcServer(exp)
+		if err != nil {
+			t.Fatalf("Error creating mock grpc server: %s", err)
+		}
+
+		rsp, err := fakeGrpcServer.Edges(context.TODO(), &pb.EdgesRequest{
+			Selector: &pb.ResourceSelection{
+				Resource: &pb.Resource{
+					Namespace: "emojivoto",
+					Type:      pkgK8s.Deployment,
+				},
+			},
+			TimeWindow: "1m",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		err = exp.verifyPromQueries(mockProm)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		webToEmoji := genEdge("web", "emoji", true)
+		webToEmoji.ClientId = identity("web")
+		webToEmoji.ServerId = identity("emoji")
+		webToEmoji.LastSeen = 10
+		webToVoting := genEdge("web", "voting", false)
+		webToVoting.NoIdentityMsg = "not all requests secured"
+		webToVoting.LastSeen = 5
+		emojiToVoting := genEdge("emoji", "voting", false)
+		emojiToVoting.NoIdentityMsg = "destination not meshed"
+		emojiToVoting.LastSeen = 2
+
+		expectedEdges := []*pb.Edge{
+			{
+				Src:           &pb.Resource{Type: pkgK8s.Deployment},
+				Dst:           &pb.Resource{Namespace: "emojivoto", Type: pkgK8s.Deployment, Name: "web"},
+				UnmeshedSrc:   true,
+				NoIdentityMsg: "source not meshed",
+				LastSeen:      10,
+			},
+			emojiToVoting,
+			webToEmoji,
+			webToVoting,
+		}
+		edges := rsp.GetOk().GetEdges()
+		if len(edges) != len(expectedEdges) {
+			t.Fatalf("Expected [%d] edges, got [%d]: %v", len(expectedEdges), len(edges), edges)
+		}
+		for i, edge := range edges {
+			if !proto.Equal(edge, expectedEdges[i]) {
+				t.Fatalf("Expected: %+v\n Got: %+v", expectedEdges[i], edge)
+			}
+		}
+	})
+
+	t.Run("Returns an error for unsupported resource types", func(t *testing.T) {
+		_, fakeGrpcServer, err := newMockGrpcServer(expectedStatRPC{})
+		if err != nil {
+			t.Fatalf("Error creating mock grpc server: %s", err)
+		}
+
+		rsp, err := fakeGrpcServer.Edges(context.TODO(), &pb.EdgesRequest{
+			Selector: &pb.ResourceSelection{
+				Resource: &pb.Resource{Type: pkgK8s.Authority},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expectedMsg := "resource type 'authority' is not supported for edges"
+		if rsp.GetError().GetError() != expectedMsg {
+			t.Fatalf("Expected error [%s], got [%s]", expectedMsg, rsp.GetError().GetError())
+		}
+	})
+}
+
+func TestDiffEdges(t *testing.T) {
+	known := make(map[edgeKey]*pb.Edge)
+
+	events := diffEdges(known, []*pb.Edge{genEdge("web", "emoji", false), genEdge("web", "voting", true)})
+	expected := []*pb.EdgeEvent{
+		{Type: pb.EdgeEvent_ADD, Edge: genEdge("web", "emoji", false)},
+		{Type: pb.EdgeEvent_ADD, Edge: genEdge("web", "voting", true)},
+	}
+	assertEdgeEvents(t, expected, events)
+
+	events = diffEdges(known, []*pb.Edge{genEdge("web", "emoji", true)})
+	expected = []*pb.EdgeEvent{
+		{Type: pb.EdgeEvent_UPDATE, Edge: genEdge("web", "emoji", true)},
+		{Type: pb.EdgeEvent_REMOVE, Edge: genEdge("web", "voting", true)},
+	}
+	assertEdgeEvents(t, expected, events)
+
+	events = diffEdges(known, []*pb.Edge{genEdge("web", "emoji", true)})
+	assertEdgeEvents(t, []*pb.EdgeEvent{}, events)
+
+	secured := genEdge("web", "emoji", true)
+	secured.ClientId = identity("web")
+	secured.ServerId = identity("emoji")
+	secured.LastSeen = 10
+	events = diffEdges(known, []*pb.Edge{secured})
+	expected = []*pb.EdgeEvent{
+		{Type: pb.EdgeEvent_UPDATE, Edge: secured},
+	}
+	assertEdgeEvents(t, expected, events)
+
+	// the last-seen time alone doesn't update an edge
+	seenLater := proto.Clone(secured).(*pb.Edge)
+	seenLater.LastSeen = 20
+	events = diffEdges(known, []*pb.Edge{seenLater})
+	assertEdgeEvents(t, []*pb.EdgeEvent{}, events)
+}
+
+func assertEdgeEvents(t *testing.T, expected, actual []*pb.EdgeEvent) {
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected [%d] events, got [%d]: %v", len(expected), len(actual), actual)
+	}
+	for i, event := range actual {
+		if !proto.Equal(event, expected[i]) {
+			t.Fatalf("Expected: %+v\n Got: %+v", expected[i], event)
+		}
+	}
+}
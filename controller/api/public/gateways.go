@@ -0,0 +1,129 @@
+package public
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/linkerd/linkerd2/controller/api/util"
+	"github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/common/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// The service mirror of each Link exports the results of its probes of
+	// the remote cluster's gateway, labeled with the name of that cluster.
+	gatewayAliveQuery   = "max(linkerd_gateway_alive%s) by (target_cluster_name)"
+	gatewayLatencyQuery = "histogram_quantile(%s, sum(irate(linkerd_gateway_probe_latency_ms_bucket%s[%s])) by (le, target_cluster_name))"
+
+	targetClusterNameLabel = model.LabelName("target_cluster_name")
+
+	defaultGatewaysTimeWindow = "1m"
+)
+
+// Gateways returns the gateways of the clusters that are linked to this one,
+// sorted by cluster name. A gateway is alive if the last probe of its service
+// mirror succeeded; the latencies are those of the probes in the request's
+// time window.
+func (s *grpcServer) Gateways(ctx context.Context, req *pb.GatewaysRequest) (*pb.GatewaysResponse, error) {
+	links, err := s.k8sAPI.Link().Lister().Links(s.controllerNamespace).List(labels.Everything())
+	if err != nil {
+		return nil, util.GRPCError(err)
+	}
+
+	cluster := req.GetRemoteClusterName()
+	if cluster != "" {
+		filtered := []*v1alpha1.Link{}
+		for _, link := range links {
+			if link.Spec.TargetClusterName == cluster {
+				filtered = append(filtered, link)
+			}
+		}
+		if len(filtered) == 0 {
+			return nil, status.Errorf(codes.NotFound, "No cluster named \"%s\" is linked to the %s namespace", cluster, s.controllerNamespace)
+		}
+		links = filtered
+	}
+
+	timeWindow := req.GetTimeWindow()
+	if timeWindow == "" {
+		timeWindow = defaultGatewaysTimeWindow
+	}
+
+	promLabels := model.LabelSet{}
+	if cluster != "" {
+		promLabels[targetClusterNameLabel] = model.LabelValue(cluster)
+	}
+
+	alive, err := s.queryGatewayMetric(ctx, fmt.Sprintf(gatewayAliveQuery, promLabels))
+	if err != nil {
+		return nil, util.GRPCError(err)
+	}
+	latencies := make(map[promType]map[string]uint64)
+	for _, quantile := range []promType{promLatencyP50, promLatencyP95, promLatencyP99} {
+		latencies[quantile], err = s.queryGatewayMetric(ctx, fmt.Sprintf(gatewayLatencyQuery, quantile, promLabels, timeWindow))
+		if err != nil {
+			return nil, util.GRPCError(err)
+		}
+	}
+
+	pairedServices, err := s.countMirroredServices()
+	if err != nil {
+		return nil, util.GRPCError(err)
+	}
+
+	gateways := make([]*pb.GatewaysResponse_Gateway, 0, len(links))
+	for _, link := range links {
+		name := link.Spec.TargetClusterName
+		gateways = append(gateways, &pb.GatewaysResponse_Gateway{
+			ClusterName:    name,
+			Address:        net.JoinHostPort(link.Spec.GatewayAddress, strconv.FormatUint(uint64(link.Spec.GatewayPort), 10)),
+			Alive:          alive[name] == 1,
+			PairedServices: pairedServices[name],
+			LatencyMsP50:   latencies[promLatencyP50][name],
+			LatencyMsP95:   latencies[promLatencyP95][name],
+			LatencyMsP99:   latencies[promLatencyP99][name],
+		})
+	}
+	sort.Slice(gateways, func(i, j int) bool { return gateways[i].ClusterName < gateways[j].ClusterName })
+
+	return &pb.GatewaysResponse{Gateways: gateways}, nil
+}
+
+// queryGatewayMetric runs a query whose result is grouped by
+// target_cluster_name, and returns its values keyed by cluster name.
+func (s *grpcServer) queryGatewayMetric(ctx context.Context, query string) (map[string]uint64, error) {
+	vec, err := s.queryProm(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]uint64)
+	for _, sample := range vec {
+		values[string(sample.Metric[targetClusterNameLabel])] = extractSampleValue(sample)
+	}
+	return values, nil
+}
+
+// countMirroredServices returns the number of services that the service
+// mirrors created in this cluster, keyed by the name of the remote cluster.
+func (s *grpcServer) countMirroredServices() (map[string]uint64, error) {
+	selector := labels.SelectorFromSet(labels.Set{pkgK8s.MirroredServiceLabel: "true"})
+	services, err := s.k8sAPI.Svc().Lister().List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]uint64)
+	for _, svc := range services {
+		counts[svc.Labels[pkgK8s.RemoteClusterNameLabel]]++
+	}
+	return counts, nil
+}
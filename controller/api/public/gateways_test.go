@@ -0,0 +1,144 @@
+package public
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/prometheus/common/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func linkConfig(cluster string) string {
+	return fmt.Sprintf(`
+apiVersion: linkerd.io/v1alpha1
+kind: Link
+metadata:
+  name: %s
+  namespace: linkerd
+spec:
+  targetClusterName: %s
+  gatewayAddress: 203.0.113.10
+  gatewayPort: 4143`, cluster, cluster)
+}
+
+func mirroredServiceConfig(name, cluster string) string {
+	return fmt.Sprintf(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: %s-%s
+  namespace: emojivoto
+  labels:
+    mirror.linkerd.io/mirrored-service: "true"
+    mirror.linkerd.io/cluster-name: %s`, name, cluster, cluster)
+}
+
+func TestGateways(t *testing.T) {
+	k8sConfigs := []string{
+		linkConfig("west"),
+		linkConfig("east"),
+		mirroredServiceConfig("web", "east"),
+		mirroredServiceConfig("voting", "east"),
+		mirroredServiceConfig("web", "west"),
+	}
+
+	t.Run("Returns the gateways of all the linked clusters", func(t *testing.T) {
+		exp := expectedStatRPC{
+			k8sConfigs: k8sConfigs,
+			// the mock returns this vector for every query, so the latencies of
+			// the gateway of east are all 1ms
+			mockPromResponse: model.Vector{
+				&model.Sample{
+					Metric: model.Metric{"target_cluster_name": "east"},
+					Value:  1,
+				},
+			},
+			expectedPrometheusQueries: []string{
+				`max(linkerd_gateway_alive{}) by (target_cluster_name)`,
+				`histogram_quantile(0.5, sum(irate(linkerd_gateway_probe_latency_ms_bucket{}[1m])) by (le, target_cluster_name))`,
+				`histogram_quantile(0.95, sum(irate(linkerd_gateway_probe_latency_ms_bucket{}[1m])) by (le, target_cluster_name))`,
+				`histogram_quantile(0.99, sum(irate(linkerd_gateway_probe_latency_ms_bucket{}[1m])) by (le, target_cluster_name))`,
+			},
+		}
+
+		mockProm, fakeGrpcServer, err := newMockGrpcServer(exp)
+		if err != nil {
+			t.Fatalf("Error creating mock grpc server: %s", err)
+		}
+
+		rsp, err := fakeGrpcServer.Gateways(context.TODO(), &pb.GatewaysRequest{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := exp.verifyPromQueries(mockProm); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := &pb.GatewaysResponse{
+			Gateways: []*pb.GatewaysResponse_Gateway{
+				{
+					ClusterName:    "east",
+					Address:        "203.0.113.10:4143",
+					Alive:          true,
+					PairedServices: 2,
+					LatencyMsP50:   1,
+					LatencyMsP95:   1,
+					LatencyMsP99:   1,
+				},
+				{
+					ClusterName:    "west",
+					Address:        "203.0.113.10:4143",
+					PairedServices: 1,
+				},
+			},
+		}
+		if !proto.Equal(rsp, expected) {
+			t.Fatalf("Expected:\n%+v\nGot:\n%+v", expected, rsp)
+		}
+	})
+
+	t.Run("Only queries the metrics of the requested cluster", func(t *testing.T) {
+		exp := expectedStatRPC{
+			k8sConfigs:       k8sConfigs,
+			mockPromResponse: model.Vector{},
+			expectedPrometheusQueries: []string{
+				`max(linkerd_gateway_alive{target_cluster_name="west"}) by (target_cluster_name)`,
+				`histogram_quantile(0.5, sum(irate(linkerd_gateway_probe_latency_ms_bucket{target_cluster_name="west"}[5m])) by (le, target_cluster_name))`,
+				`histogram_quantile(0.95, sum(irate(linkerd_gateway_probe_latency_ms_bucket{target_cluster_name="west"}[5m])) by (le, target_cluster_name))`,
+				`histogram_quantile(0.99, sum(irate(linkerd_gateway_probe_latency_ms_bucket{target_cluster_name="west"}[5m])) by (le, target_cluster_name))`,
+			},
+		}
+
+		mockProm, fakeGrpcServer, err := newMockGrpcServer(exp)
+		if err != nil {
+			t.Fatalf("Error creating mock grpc server: %s", err)
+		}
+
+		rsp, err := fakeGrpcServer.Gateways(context.TODO(), &pb.GatewaysRequest{RemoteClusterName: "west", TimeWindow: "5m"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := exp.verifyPromQueries(mockProm); err != nil {
+			t.Fatal(err)
+		}
+		if len(rsp.GetGateways()) != 1 || rsp.GetGateways()[0].GetClusterName() != "west" || rsp.GetGateways()[0].GetAlive() {
+			t.Fatalf("Expected the gateway of west only, got: %+v", rsp)
+		}
+	})
+
+	t.Run("Returns NotFound for a cluster that isn't linked", func(t *testing.T) {
+		_, fakeGrpcServer, err := newMockGrpcServer(expectedStatRPC{k8sConfigs: k8sConfigs})
+		if err != nil {
+			t.Fatalf("Error creating mock grpc server: %s", err)
+		}
+
+		_, err = fakeGrpcServer.Gateways(context.TODO(), &pb.GatewaysRequest{RemoteClusterName: "north"})
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("Expected a NotFound error, got: %v", err)
+		}
+	})
+}
@@ -3,7 +3,8 @@ package public
 import (
 	"context"
 	"fmt"
-	"runtime"
+	"net"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,13 +13,20 @@ import (
 	tapPb "github.com/linkerd/linkerd2/controller/gen/controller/tap"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/admin"
 	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/prometheus"
+	"github.com/linkerd/linkerd2/pkg/tls"
 	"github.com/linkerd/linkerd2/pkg/version"
 	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 	k8sV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
@@ -29,6 +37,8 @@ type (
 		k8sAPI              *k8s.API
 		controllerNamespace string
 		ignoredNamespaces   []string
+		queryLimits         QueryLimits
+		breaker             *queryBreaker
 	}
 )
 
@@ -43,6 +53,10 @@ const (
 	k8sClientCheckDescription  = "control plane can talk to Kubernetes"
 	promClientSubsystemName    = "prometheus"
 	promClientCheckDescription = "control plane can talk to Prometheus"
+
+	// trustBundleRefreshHint is how often the consumers of the trust bundle
+	// are told to fetch it again, to pick up the CA's new trust anchors.
+	trustBundleRefreshHint = 5 * time.Minute
 )
 
 func newGrpcServer(
@@ -51,23 +65,121 @@ func newGrpcServer(
 	k8sAPI *k8s.API,
 	controllerNamespace string,
 	ignoredNamespaces []string,
+	queryLimits QueryLimits,
 ) *grpcServer {
-	return &grpcServer{
+	s := &grpcServer{
 		prometheusAPI:       promAPI,
 		tapClient:           tapClient,
 		k8sAPI:              k8sAPI,
 		controllerNamespace: controllerNamespace,
 		ignoredNamespaces:   ignoredNamespaces,
+		queryLimits:         queryLimits,
+	}
+	if queryLimits.BreakerThreshold > 0 {
+		s.breaker = newQueryBreaker(queryLimits.BreakerThreshold, queryLimits.BreakerCooldown)
+	}
+	return s
+}
+
+// NewGrpcServer creates a gRPC server that serves the Public API natively,
+// rather than as protobuf over HTTP like NewServer. If enableReflection is
+// set, it also serves the gRPC server reflection service, so that clients such
+// as grpcurl can discover and call the API without its generated stubs.
+func NewGrpcServer(
+	addr string,
+	promAPI promv1.API,
+	tapClient tapPb.TapClient,
+	k8sAPI *k8s.API,
+	controllerNamespace string,
+	ignoredNamespaces []string,
+	queryLimits QueryLimits,
+	enableReflection bool,
+) (*grpc.Server, net.Listener, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s := prometheus.NewGrpcServer()
+	pb.RegisterApiServer(s, newGrpcServer(
+		promAPI,
+		tapClient,
+		k8sAPI,
+		controllerNamespace,
+		ignoredNamespaces,
+		queryLimits,
+	))
+	admin.RegisterHealthServer(s, "linkerd2.public.Api")
+	if enableReflection {
+		reflection.Register(s)
 	}
+
+	return s, lis, nil
 }
 
 func (*grpcServer) Version(ctx context.Context, req *pb.Empty) (*pb.VersionInfo, error) {
-	return &pb.VersionInfo{GoVersion: runtime.Version(), ReleaseVersion: version.Version, BuildDate: "1970-01-01T00:00:00Z"}, nil
+	return version.Info(), nil
+}
+
+// TrustBundle returns the trust anchors that the CA publishes in the
+// controller namespace, in the SPIFFE trust bundle format.
+func (s *grpcServer) TrustBundle(ctx context.Context, req *pb.Empty) (*pb.TrustBundleResponse, error) {
+	configMap, err := s.k8sAPI.Client.CoreV1().ConfigMaps(s.controllerNamespace).Get(pkgK8s.TLSTrustAnchorConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, status.Errorf(codes.NotFound, "No trust anchors found in the %s namespace; TLS must be enabled with --tls=optional", s.controllerNamespace)
+		}
+		return nil, err
+	}
+
+	trustAnchors, err := tls.DecodePEMCerts([]byte(configMap.Data[pkgK8s.TLSTrustAnchorFileName]))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid trust anchors in the %s config map: %s", pkgK8s.TLSTrustAnchorConfigMapName, err)
+	}
+
+	bundle, err := tls.EncodeSPIFFEBundle(trustAnchors, trustBundleRefreshHint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.TrustBundleResponse{Bundle: string(bundle)}, nil
 }
 
 func (s *grpcServer) ListPods(ctx context.Context, req *pb.ListPodsRequest) (*pb.ListPodsResponse, error) {
 	log.Debugf("ListPods request: %+v", req)
 
+	var pods []*k8sV1.Pod
+	var err error
+	namespace := req.GetNamespace()
+	if namespace != "" {
+		pods, err = s.k8sAPI.Pod().Lister().Pods(namespace).List(labels.Everything())
+	} else {
+		pods, err = s.k8sAPI.Pod().Lister().List(labels.Everything())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make([]*k8sV1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if !s.shouldIgnore(pod) {
+			listed = append(listed, pod)
+		}
+	}
+	sort.Slice(listed, func(i, j int) bool {
+		if listed[i].Namespace != listed[j].Namespace {
+			return listed[i].Namespace < listed[j].Namespace
+		}
+		return listed[i].Name < listed[j].Name
+	})
+	start, end, continueToken, err := paginate(len(listed), func(i int) (string, string) {
+		return listed[i].Namespace, listed[i].Name
+	}, req.GetLimit(), req.GetContinueToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	// Reports is a map from instance name to the absolute time of the most recent
 	// report from that instance and its process start time
 	reports := make(map[string]podReport)
@@ -93,24 +205,9 @@ func (s *grpcServer) ListPods(ctx context.Context, req *pb.ListPodsRequest) (*pb
 		}
 	}
 
-	var pods []*k8sV1.Pod
-	namespace := req.GetNamespace()
-	if namespace != "" {
-		pods, err = s.k8sAPI.Pod().Lister().Pods(namespace).List(labels.Everything())
-	} else {
-		pods, err = s.k8sAPI.Pod().Lister().List(labels.Everything())
-	}
-
-	if err != nil {
-		return nil, err
-	}
 	podList := make([]*pb.Pod, 0)
 
-	for _, pod := range pods {
-		if s.shouldIgnore(pod) {
-			continue
-		}
-
+	for _, pod := range listed[start:end] {
 		updated, added := reports[pod.Name]
 
 		status := string(pod.Status.Phase)
@@ -131,7 +228,8 @@ func (s *grpcServer) ListPods(ctx context.Context, req *pb.ListPodsRequest) (*pb
 		proxyVersion := ""
 		for _, container := range pod.Spec.Containers {
 			if container.Name == pkgK8s.ProxyContainerName {
-				parts := strings.Split(container.Image, ":")
+				// the images pinned to a digest keep their tag
+				parts := strings.Split(strings.SplitN(container.Image, "@", 2)[0], ":")
 				proxyVersion = parts[1]
 			}
 		}
@@ -181,7 +279,7 @@ func (s *grpcServer) ListPods(ctx context.Context, req *pb.ListPodsRequest) (*pb
 		podList = append(podList, item)
 	}
 
-	rsp := pb.ListPodsResponse{Pods: podList}
+	rsp := pb.ListPodsResponse{Pods: podList, ContinueToken: continueToken}
 
 	log.Debugf("ListPods response: %+v", rsp)
 
@@ -2,14 +2,30 @@ package public
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/duration"
 	tap "github.com/linkerd/linkerd2/controller/gen/controller/tap"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/tls"
 	"github.com/prometheus/common/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionPb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
 )
 
 type listPodsExpected struct {
@@ -172,6 +188,7 @@ spec:
 				k8sAPI,
 				"linkerd",
 				[]string{},
+				QueryLimits{},
 			)
 
 			k8sAPI.Sync()
@@ -253,6 +270,7 @@ metadata:
 				k8sAPI,
 				"linkerd",
 				[]string{},
+				QueryLimits{},
 			)
 
 			k8sAPI.Sync()
@@ -268,3 +286,143 @@ metadata:
 		}
 	})
 }
+
+func TestTrustBundle(t *testing.T) {
+	key, err := tls.GenerateECDSAKey()
+	if err != nil {
+		t.Fatalf("GenerateECDSAKey returned an error: %s", err)
+	}
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Cluster-local Managed Pod CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate returned an error: %s", err)
+	}
+	certPEM, err := tls.PEMEncodeCert(der)
+	if err != nil {
+		t.Fatalf("PEMEncodeCert returned an error: %s", err)
+	}
+
+	t.Run("Returns the trust anchors as a SPIFFE bundle", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI("", fmt.Sprintf(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s
+  namespace: linkerd
+data:
+  %s: |
+    %s`, pkgK8s.TLSTrustAnchorConfigMapName, pkgK8s.TLSTrustAnchorFileName,
+			strings.Replace(strings.TrimSpace(string(certPEM)), "\n", "\n    ", -1)))
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+		fakeGrpcServer := newGrpcServer(&mockProm{}, tap.NewTapClient(nil), k8sAPI, "linkerd", []string{}, QueryLimits{})
+
+		rsp, err := fakeGrpcServer.TrustBundle(context.TODO(), &pb.Empty{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		var bundle struct {
+			Keys []struct {
+				Use string   `json:"use"`
+				Kty string   `json:"kty"`
+				X5c []string `json:"x5c"`
+			} `json:"keys"`
+			RefreshHint int64 `json:"spiffe_refresh_hint"`
+		}
+		if err := json.Unmarshal([]byte(rsp.Bundle), &bundle); err != nil {
+			t.Fatalf("Invalid bundle %s: %s", rsp.Bundle, err)
+		}
+		if len(bundle.Keys) != 1 || bundle.Keys[0].Use != "x509-svid" || bundle.Keys[0].Kty != "EC" {
+			t.Fatalf("Unexpected bundle keys: %+v", bundle.Keys)
+		}
+		if bundle.Keys[0].X5c[0] != base64.StdEncoding.EncodeToString(der) {
+			t.Fatalf("Expected the bundle to contain the trust anchor, got %s", bundle.Keys[0].X5c[0])
+		}
+		if bundle.RefreshHint != 300 {
+			t.Fatalf("Expected a refresh hint of 300s, got %d", bundle.RefreshHint)
+		}
+	})
+
+	t.Run("Fails if TLS isn't enabled", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI("")
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+		fakeGrpcServer := newGrpcServer(&mockProm{}, tap.NewTapClient(nil), k8sAPI, "linkerd", []string{}, QueryLimits{})
+
+		_, err = fakeGrpcServer.TrustBundle(context.TODO(), &pb.Empty{})
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("Expected a NotFound error, got: %v", err)
+		}
+	})
+}
+
+func TestGrpcServerReflection(t *testing.T) {
+	listServices := func(enableReflection bool) ([]string, error) {
+		k8sAPI, err := k8s.NewFakeAPI("")
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+		server, lis, err := NewGrpcServer("127.0.0.1:0", nil, tap.NewTapClient(nil), k8sAPI, "linkerd", []string{}, QueryLimits{}, enableReflection)
+		if err != nil {
+			t.Fatalf("NewGrpcServer returned an error: %s", err)
+		}
+		go server.Serve(lis)
+		defer server.Stop()
+
+		conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		defer conn.Close()
+
+		stream, err := reflectionPb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		err = stream.Send(&reflectionPb.ServerReflectionRequest{
+			MessageRequest: &reflectionPb.ServerReflectionRequest_ListServices{},
+		})
+		if err != nil {
+			return nil, err
+		}
+		rsp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+
+		services := []string{}
+		for _, service := range rsp.GetListServicesResponse().GetService() {
+			services = append(services, service.Name)
+		}
+		sort.Strings(services)
+		return services, nil
+	}
+
+	t.Run("Lists the services with reflection enabled", func(t *testing.T) {
+		services, err := listServices(true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := []string{"grpc.health.v1.Health", "grpc.reflection.v1alpha.ServerReflection", "linkerd2.public.Api"}
+		if !reflect.DeepEqual(services, expected) {
+			t.Fatalf("Expected services %v, got %v", expected, services)
+		}
+	})
+
+	t.Run("Doesn't serve reflection by default", func(t *testing.T) {
+		_, err := listServices(false)
+		if status.Code(err) != codes.Unimplemented {
+			t.Fatalf("Expected an Unimplemented error, got: %v", err)
+		}
+	})
+}
@@ -10,7 +10,6 @@ import (
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/prometheus"
-	promApi "github.com/prometheus/client_golang/api"
 	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/metadata"
@@ -19,11 +18,15 @@ import (
 var (
 	statSummaryPath   = fullURLPathFor("StatSummary")
 	topRoutesPath     = fullURLPathFor("TopRoutes")
+	edgesPath         = fullURLPathFor("Edges")
+	watchEdgesPath    = fullURLPathFor("WatchEdges")
 	versionPath       = fullURLPathFor("Version")
 	listPodsPath      = fullURLPathFor("ListPods")
 	listServicesPath  = fullURLPathFor("ListServices")
 	tapByResourcePath = fullURLPathFor("TapByResource")
 	selfCheckPath     = fullURLPathFor("SelfCheck")
+	trustBundlePath   = fullURLPathFor("TrustBundle")
+	gatewaysPath      = fullURLPathFor("Gateways")
 )
 
 type handler struct {
@@ -34,6 +37,19 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	log.WithFields(log.Fields{
 		"req.Method": req.Method, "req.URL": req.URL, "req.Form": req.Form,
 	}).Debugf("Serving %s %s", req.Method, req.URL.Path)
+	// The trust bundle can also be fetched as plain JSON, the way SPIFFE
+	// implementations fetch the bundles of the trust domains they federate
+	// with.
+	if req.Method == http.MethodGet && req.URL.Path == trustBundlePath {
+		h.handleTrustBundleJSON(w, req)
+		return
+	}
+
+	if isJSONRequest(req) {
+		h.serveJSON(w, req)
+		return
+	}
+
 	// Validate request method
 	if req.Method != http.MethodPost {
 		writeErrorToHTTPResponse(w, fmt.Errorf("POST required"))
@@ -46,6 +62,10 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		h.handleStatSummary(w, req)
 	case topRoutesPath:
 		h.handleTopRoutes(w, req)
+	case edgesPath:
+		h.handleEdges(w, req)
+	case watchEdgesPath:
+		h.handleWatchEdges(w, req)
 	case versionPath:
 		h.handleVersion(w, req)
 	case listPodsPath:
@@ -56,6 +76,10 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		h.handleTapByResource(w, req)
 	case selfCheckPath:
 		h.handleSelfCheck(w, req)
+	case trustBundlePath:
+		h.handleTrustBundle(w, req)
+	case gatewaysPath:
+		h.handleGateways(w, req)
 	default:
 		http.NotFound(w, req)
 	}
@@ -104,6 +128,49 @@ func (h *handler) handleTopRoutes(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+func (h *handler) handleEdges(w http.ResponseWriter, req *http.Request) {
+	var protoRequest pb.EdgesRequest
+
+	err := httpRequestToProto(req, &protoRequest)
+	if err != nil {
+		writeErrorToHTTPResponse(w, err)
+		return
+	}
+
+	rsp, err := h.grpcServer.Edges(req.Context(), &protoRequest)
+	if err != nil {
+		writeErrorToHTTPResponse(w, err)
+		return
+	}
+	err = writeProtoToHTTPResponse(w, rsp)
+	if err != nil {
+		writeErrorToHTTPResponse(w, err)
+		return
+	}
+}
+
+func (h *handler) handleWatchEdges(w http.ResponseWriter, req *http.Request) {
+	flushableWriter, err := newStreamingWriter(w)
+	if err != nil {
+		writeErrorToHTTPResponse(w, err)
+		return
+	}
+
+	var protoRequest pb.EdgesRequest
+	err = httpRequestToProto(req, &protoRequest)
+	if err != nil {
+		writeErrorToHTTPResponse(w, err)
+		return
+	}
+
+	server := watchEdgesServer{w: flushableWriter, req: req}
+	err = h.grpcServer.WatchEdges(&protoRequest, server)
+	if err != nil {
+		writeErrorToHTTPResponse(w, err)
+		return
+	}
+}
+
 func (h *handler) handleVersion(w http.ResponseWriter, req *http.Request) {
 	var protoRequest pb.Empty
 	err := httpRequestToProto(req, &protoRequest)
@@ -125,6 +192,59 @@ func (h *handler) handleVersion(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+func (h *handler) handleTrustBundle(w http.ResponseWriter, req *http.Request) {
+	var protoRequest pb.Empty
+	err := httpRequestToProto(req, &protoRequest)
+	if err != nil {
+		writeErrorToHTTPResponse(w, err)
+		return
+	}
+
+	rsp, err := h.grpcServer.TrustBundle(req.Context(), &protoRequest)
+	if err != nil {
+		writeErrorToHTTPResponse(w, err)
+		return
+	}
+
+	err = writeProtoToHTTPResponse(w, rsp)
+	if err != nil {
+		writeErrorToHTTPResponse(w, err)
+		return
+	}
+}
+
+func (h *handler) handleTrustBundleJSON(w http.ResponseWriter, req *http.Request) {
+	rsp, err := h.grpcServer.TrustBundle(req.Context(), &pb.Empty{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(rsp.GetBundle()))
+}
+
+func (h *handler) handleGateways(w http.ResponseWriter, req *http.Request) {
+	var protoRequest pb.GatewaysRequest
+	err := httpRequestToProto(req, &protoRequest)
+	if err != nil {
+		writeErrorToHTTPResponse(w, err)
+		return
+	}
+
+	rsp, err := h.grpcServer.Gateways(req.Context(), &protoRequest)
+	if err != nil {
+		writeErrorToHTTPResponse(w, err)
+		return
+	}
+
+	err = writeProtoToHTTPResponse(w, rsp)
+	if err != nil {
+		writeErrorToHTTPResponse(w, err)
+		return
+	}
+}
+
 func (h *handler) handleSelfCheck(w http.ResponseWriter, req *http.Request) {
 	var protoRequest healthcheckPb.SelfCheckRequest
 	err := httpRequestToProto(req, &protoRequest)
@@ -190,6 +310,14 @@ func (h *handler) handleListServices(w http.ResponseWriter, req *http.Request) {
 }
 
 func (h *handler) handleTapByResource(w http.ResponseWriter, req *http.Request) {
+	ServeTapByResource(w, req, h.grpcServer.TapByResource)
+}
+
+// ServeTapByResource serves a TapByResource request over HTTP the way the
+// public API does, streaming the events that tap sends to the response. The
+// tap server's aggregated API serves its taps with it, so that the same
+// clients can read them.
+func ServeTapByResource(w http.ResponseWriter, req *http.Request, tap func(*pb.TapByResourceRequest, pb.Api_TapByResourceServer) error) {
 	flushableWriter, err := newStreamingWriter(w)
 	if err != nil {
 		writeErrorToHTTPResponse(w, err)
@@ -204,13 +332,19 @@ func (h *handler) handleTapByResource(w http.ResponseWriter, req *http.Request)
 	}
 
 	server := tapServer{w: flushableWriter, req: req}
-	err = h.grpcServer.TapByResource(&protoRequest, server)
+	err = tap(&protoRequest, server)
 	if err != nil {
 		writeErrorToHTTPResponse(w, err)
 		return
 	}
 }
 
+// WriteHTTPError writes the error to the response the way the public API
+// does, with an HTTP status of code.
+func WriteHTTPError(w http.ResponseWriter, code int, err error) {
+	writeErrorToHTTPResponse(w, httpError{Code: code, WrappedError: err})
+}
+
 type tapServer struct {
 	w   flushableResponseWriter
 	req *http.Request
@@ -235,30 +369,58 @@ func (s tapServer) Context() context.Context     { return s.req.Context() }
 func (s tapServer) SendMsg(interface{}) error    { return nil }
 func (s tapServer) RecvMsg(interface{}) error    { return nil }
 
+type watchEdgesServer struct {
+	w   flushableResponseWriter
+	req *http.Request
+}
+
+func (s watchEdgesServer) Send(msg *pb.EdgeEvent) error {
+	err := writeProtoToHTTPResponse(s.w, msg)
+	if err != nil {
+		writeErrorToHTTPResponse(s.w, err)
+		return err
+	}
+
+	s.w.Flush()
+	return nil
+}
+
+// satisfy the pb.Api_WatchEdgesServer interface
+func (s watchEdgesServer) SetHeader(metadata.MD) error  { return nil }
+func (s watchEdgesServer) SendHeader(metadata.MD) error { return nil }
+func (s watchEdgesServer) SetTrailer(metadata.MD)       {}
+func (s watchEdgesServer) Context() context.Context     { return s.req.Context() }
+func (s watchEdgesServer) SendMsg(interface{}) error    { return nil }
+func (s watchEdgesServer) RecvMsg(interface{}) error    { return nil }
+
 func fullURLPathFor(method string) string {
 	return apiRoot + apiPrefix + method
 }
 
-// NewServer creates a Public API HTTP server.
+// NewServer creates a Public API HTTP server, which rate limits and audits
+// its clients with accessConfig.
 func NewServer(
 	addr string,
-	prometheusClient promApi.Client,
+	promAPI promv1.API,
 	tapClient tapPb.TapClient,
 	k8sAPI *k8s.API,
 	controllerNamespace string,
 	ignoredNamespaces []string,
+	queryLimits QueryLimits,
+	accessConfig AccessConfig,
 ) *http.Server {
 	baseHandler := &handler{
 		grpcServer: newGrpcServer(
-			promv1.NewAPI(prometheusClient),
+			promAPI,
 			tapClient,
 			k8sAPI,
 			controllerNamespace,
 			ignoredNamespaces,
+			queryLimits,
 		),
 	}
 
-	instrumentedHandler := prometheus.WithTelemetry(baseHandler)
+	instrumentedHandler := prometheus.WithTelemetry(newAccessHandler(baseHandler, accessConfig))
 
 	return &http.Server{
 		Addr:    addr,
@@ -16,6 +16,7 @@ type mockGrpcServer struct {
 	LastRequestReceived proto.Message
 	ResponseToReturn    proto.Message
 	TapStreamsToReturn  []*pb.TapEvent
+	EdgeEventsToReturn  []*pb.EdgeEvent
 	ErrorToReturn       error
 }
 
@@ -29,11 +30,37 @@ func (m *mockGrpcServer) TopRoutes(ctx context.Context, req *pb.TopRoutesRequest
 	return m.ResponseToReturn.(*pb.TopRoutesResponse), m.ErrorToReturn
 }
 
+func (m *mockGrpcServer) Edges(ctx context.Context, req *pb.EdgesRequest) (*pb.EdgesResponse, error) {
+	m.LastRequestReceived = req
+	return m.ResponseToReturn.(*pb.EdgesResponse), m.ErrorToReturn
+}
+
+func (m *mockGrpcServer) WatchEdges(req *pb.EdgesRequest, edgesServer pb.Api_WatchEdgesServer) error {
+	m.LastRequestReceived = req
+	if m.ErrorToReturn == nil {
+		for _, msg := range m.EdgeEventsToReturn {
+			edgesServer.Send(msg)
+		}
+	}
+
+	return m.ErrorToReturn
+}
+
 func (m *mockGrpcServer) Version(ctx context.Context, req *pb.Empty) (*pb.VersionInfo, error) {
 	m.LastRequestReceived = req
 	return m.ResponseToReturn.(*pb.VersionInfo), m.ErrorToReturn
 }
 
+func (m *mockGrpcServer) TrustBundle(ctx context.Context, req *pb.Empty) (*pb.TrustBundleResponse, error) {
+	m.LastRequestReceived = req
+	return m.ResponseToReturn.(*pb.TrustBundleResponse), m.ErrorToReturn
+}
+
+func (m *mockGrpcServer) Gateways(ctx context.Context, req *pb.GatewaysRequest) (*pb.GatewaysResponse, error) {
+	m.LastRequestReceived = req
+	return m.ResponseToReturn.(*pb.GatewaysResponse), m.ErrorToReturn
+}
+
 func (m *mockGrpcServer) ListPods(ctx context.Context, req *pb.ListPodsRequest) (*pb.ListPodsResponse, error) {
 	m.LastRequestReceived = req
 	return m.ResponseToReturn.(*pb.ListPodsResponse), m.ErrorToReturn
@@ -119,6 +146,13 @@ func TestServer(t *testing.T) {
 			functionCall:     func() (proto.Message, error) { return client.StatSummary(context.TODO(), statSummaryReq) },
 		}
 
+		edgesReq := &pb.EdgesRequest{}
+		testEdges := grpcCallTestCase{
+			expectedRequest:  edgesReq,
+			expectedResponse: &pb.EdgesResponse{},
+			functionCall:     func() (proto.Message, error) { return client.Edges(context.TODO(), edgesReq) },
+		}
+
 		versionReq := &pb.Empty{}
 		testVersion := grpcCallTestCase{
 			expectedRequest: versionReq,
@@ -128,7 +162,27 @@ func TestServer(t *testing.T) {
 			functionCall: func() (proto.Message, error) { return client.Version(context.TODO(), versionReq) },
 		}
 
-		for _, testCase := range []grpcCallTestCase{testListPods, testStatSummary, testVersion} {
+		trustBundleReq := &pb.Empty{}
+		testTrustBundle := grpcCallTestCase{
+			expectedRequest: trustBundleReq,
+			expectedResponse: &pb.TrustBundleResponse{
+				Bundle: `{"keys": []}`,
+			},
+			functionCall: func() (proto.Message, error) { return client.TrustBundle(context.TODO(), trustBundleReq) },
+		}
+
+		gatewaysReq := &pb.GatewaysRequest{RemoteClusterName: "east"}
+		testGateways := grpcCallTestCase{
+			expectedRequest: gatewaysReq,
+			expectedResponse: &pb.GatewaysResponse{
+				Gateways: []*pb.GatewaysResponse_Gateway{
+					{ClusterName: "east", Alive: true, PairedServices: 3},
+				},
+			},
+			functionCall: func() (proto.Message, error) { return client.Gateways(context.TODO(), gatewaysReq) },
+		}
+
+		for _, testCase := range []grpcCallTestCase{testListPods, testStatSummary, testEdges, testVersion, testTrustBundle, testGateways} {
 			assertCallWasForwarded(t, mockGrpcServer, testCase.expectedRequest, testCase.expectedResponse, testCase.functionCall)
 		}
 	})
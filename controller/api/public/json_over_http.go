@@ -0,0 +1,135 @@
+package public
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const jsonContentType = "application/json"
+
+// jsonMarshaler renders the responses with the JSON names of the fields of
+// their protobuf messages, including the fields with default values, so that
+// scripts don't have to know which fields may be omitted.
+var jsonMarshaler = jsonpb.Marshaler{EmitDefaults: true}
+
+// isJSONRequest returns true if the body of the request is JSON rather than
+// protobuf, in which case the response is JSON too.
+func isJSONRequest(req *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get(contentTypeHeader))
+	return err == nil && mediaType == jsonContentType
+}
+
+// httpJSONRequestToProto unmarshals the JSON body of a request. An empty body
+// is the same as an empty JSON object.
+func httpJSONRequestToProto(req *http.Request, protoRequestOut proto.Message) error {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return httpError{
+			Code:         http.StatusBadRequest,
+			WrappedError: err,
+		}
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	err = jsonpb.Unmarshal(bytes.NewReader(body), protoRequestOut)
+	if err != nil {
+		return httpError{
+			Code:         http.StatusBadRequest,
+			WrappedError: err,
+		}
+	}
+
+	return nil
+}
+
+func writeJSONToHTTPResponse(w http.ResponseWriter, msg proto.Message) error {
+	w.Header().Set(contentTypeHeader, jsonContentType)
+	return jsonMarshaler.Marshal(w, msg)
+}
+
+// writeJSONErrorToHTTPResponse writes an error as a JSON ApiError, with the
+// HTTP status that best matches it, since JSON clients can't read the error
+// header of the protobuf responses.
+func writeJSONErrorToHTTPResponse(w http.ResponseWriter, errorObtained error) {
+	statusCode := defaultHTTPErrorStatusCode
+	errorMessageToReturn := errorObtained.Error()
+
+	if httpErr, ok := errorObtained.(httpError); ok {
+		statusCode = httpErr.Code
+		errorMessageToReturn = httpErr.WrappedError.Error()
+	} else if grpcError, ok := status.FromError(errorObtained); ok {
+		errorMessageToReturn = grpcError.Message()
+		switch grpcError.Code() {
+		case codes.InvalidArgument:
+			statusCode = http.StatusBadRequest
+		case codes.NotFound:
+			statusCode = http.StatusNotFound
+		case codes.Unavailable:
+			statusCode = http.StatusServiceUnavailable
+		}
+	}
+
+	w.Header().Set(errorHeader, http.StatusText(statusCode))
+	w.Header().Set(contentTypeHeader, jsonContentType)
+	w.WriteHeader(statusCode)
+
+	err := jsonMarshaler.Marshal(w, &pb.ApiError{Error: errorMessageToReturn})
+	if err != nil {
+		log.Errorf("Error writing error to http response: %v", err)
+	}
+}
+
+// serveJSON serves the methods of the API that can be called with JSON, for
+// the scripts and tools that can't use the generated protobuf clients.
+func (h *handler) serveJSON(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSONErrorToHTTPResponse(w, httpError{
+			Code:         http.StatusMethodNotAllowed,
+			WrappedError: fmt.Errorf("POST required"),
+		})
+		return
+	}
+
+	var rsp proto.Message
+	var err error
+
+	switch req.URL.Path {
+	case statSummaryPath:
+		var protoRequest pb.StatSummaryRequest
+		if err = httpJSONRequestToProto(req, &protoRequest); err == nil {
+			rsp, err = h.grpcServer.StatSummary(req.Context(), &protoRequest)
+		}
+	case topRoutesPath:
+		var protoRequest pb.TopRoutesRequest
+		if err = httpJSONRequestToProto(req, &protoRequest); err == nil {
+			rsp, err = h.grpcServer.TopRoutes(req.Context(), &protoRequest)
+		}
+	case versionPath:
+		var protoRequest pb.Empty
+		if err = httpJSONRequestToProto(req, &protoRequest); err == nil {
+			rsp, err = h.grpcServer.Version(req.Context(), &protoRequest)
+		}
+	default:
+		err = status.Errorf(codes.NotFound, "%s can't be called with JSON", req.URL.Path)
+	}
+
+	if err != nil {
+		writeJSONErrorToHTTPResponse(w, err)
+		return
+	}
+	if err := writeJSONToHTTPResponse(w, rsp); err != nil {
+		writeJSONErrorToHTTPResponse(w, err)
+	}
+}
@@ -0,0 +1,82 @@
+package public
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+)
+
+func TestServeJSON(t *testing.T) {
+	testCases := []struct {
+		title           string
+		path            string
+		body            string
+		response        proto.Message
+		expectedRequest proto.Message
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			title:    "calls StatSummary",
+			path:     statSummaryPath,
+			body:     `{"selector": {"resource": {"namespace": "emojivoto", "type": "deployment"}}, "timeWindow": "1m"}`,
+			response: &pb.StatSummaryResponse{},
+			expectedRequest: &pb.StatSummaryRequest{
+				Selector:   &pb.ResourceSelection{Resource: &pb.Resource{Namespace: "emojivoto", Type: "deployment"}},
+				TimeWindow: "1m",
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			title:           "calls Version without a body",
+			path:            versionPath,
+			response:        &pb.VersionInfo{GoVersion: "go1.10.3", BuildDate: "02/21/1983", ReleaseVersion: "stable-2.1.0"},
+			expectedRequest: &pb.Empty{},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    `{"goVersion":"go1.10.3","buildDate":"02/21/1983","releaseVersion":"stable-2.1.0","gitSha":"","buildTags":[]}`,
+		},
+		{
+			title:          "rejects invalid JSON",
+			path:           topRoutesPath,
+			body:           `{"timeWindow": 1}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			title:          "rejects the methods that can't be called with JSON",
+			path:           listPodsPath,
+			body:           `{}`,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"/api/v1/ListPods can't be called with JSON"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			mockGrpcServer := &mockGrpcServer{ResponseToReturn: tc.response}
+			h := &handler{grpcServer: mockGrpcServer}
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json; charset=utf-8")
+			rsp := httptest.NewRecorder()
+			h.ServeHTTP(rsp, req)
+
+			if rsp.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expectedStatus, rsp.Code, rsp.Body.String())
+			}
+			if contentType := rsp.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Fatalf("Expected a JSON response, got %s", contentType)
+			}
+			if tc.expectedBody != "" && rsp.Body.String() != tc.expectedBody {
+				t.Fatalf("Expected body %s, got %s", tc.expectedBody, rsp.Body.String())
+			}
+			if tc.expectedRequest != nil && !proto.Equal(mockGrpcServer.LastRequestReceived, tc.expectedRequest) {
+				t.Fatalf("Expected request %v, got %v", tc.expectedRequest, mockGrpcServer.LastRequestReceived)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package public
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	proto "github.com/golang/protobuf/proto"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+// DefaultPageSize is the number of pods or rows that ListAllPods and
+// StatSummaryAll request per page.
+const DefaultPageSize = 500
+
+// encodeContinueToken returns an opaque token for the item with the given
+// namespace and name, the last one of a page.
+func encodeContinueToken(namespace, name string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(namespace + "/" + name))
+}
+
+// decodeContinueToken returns the namespace and name of the last item of the
+// page that token was returned with.
+func decodeContinueToken(token string) (string, string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	parts := strings.SplitN(string(decoded), "/", 2)
+	if err != nil || len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid continue token: %s", token)
+	}
+	return parts[0], parts[1], nil
+}
+
+// paginate returns the bounds of the page of the n items, ordered by the
+// namespace and name returned by key, that starts after the item of token
+// and has at most limit items, along with the token of the next page, if
+// there are more items. All the items are in the page if limit is 0.
+func paginate(n int, key func(i int) (string, string), limit uint32, token string) (int, int, string, error) {
+	start := 0
+	if token != "" {
+		namespace, name, err := decodeContinueToken(token)
+		if err != nil {
+			return 0, 0, "", err
+		}
+		start = sort.Search(n, func(i int) bool {
+			ns, nm := key(i)
+			return ns > namespace || (ns == namespace && nm > name)
+		})
+	}
+
+	if limit == 0 || n-start <= int(limit) {
+		return start, n, "", nil
+	}
+	end := start + int(limit)
+	return start, end, encodeContinueToken(key(end - 1)), nil
+}
+
+// ListAllPods returns the pods of all the pages of req, requested
+// DefaultPageSize pods at a time.
+func ListAllPods(ctx context.Context, client pb.ApiClient, req *pb.ListPodsRequest) ([]*pb.Pod, error) {
+	pageReq := proto.Clone(req).(*pb.ListPodsRequest)
+	pageReq.Limit = DefaultPageSize
+
+	pods := make([]*pb.Pod, 0)
+	for {
+		rsp, err := client.ListPods(ctx, pageReq)
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, rsp.GetPods()...)
+		if rsp.GetContinueToken() == "" {
+			return pods, nil
+		}
+		pageReq.ContinueToken = rsp.GetContinueToken()
+	}
+}
+
+// StatSummaryAll returns the rows of all the pages of req, requested
+// DefaultPageSize rows at a time, in a single response. Requests for the
+// "all" resource type, which can't be paginated, are sent as is.
+func StatSummaryAll(ctx context.Context, client pb.ApiClient, req *pb.StatSummaryRequest) (*pb.StatSummaryResponse, error) {
+	if req.GetSelector().GetResource().GetType() == k8s.All {
+		return client.StatSummary(ctx, req)
+	}
+
+	pageReq := proto.Clone(req).(*pb.StatSummaryRequest)
+	pageReq.Limit = DefaultPageSize
+
+	rows := make([]*pb.StatTable_PodGroup_Row, 0)
+	partial := false
+	for {
+		rsp, err := client.StatSummary(ctx, pageReq)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.GetError() != nil {
+			return rsp, nil
+		}
+		for _, table := range rsp.GetOk().GetStatTables() {
+			rows = append(rows, table.GetPodGroup().GetRows()...)
+		}
+		partial = partial || rsp.GetOk().GetPartial()
+		if rsp.GetOk().GetContinueToken() == "" {
+			break
+		}
+		pageReq.ContinueToken = rsp.GetOk().GetContinueToken()
+	}
+
+	return &pb.StatSummaryResponse{
+		Response: &pb.StatSummaryResponse_Ok_{
+			Ok: &pb.StatSummaryResponse_Ok{
+				StatTables: []*pb.StatTable{
+					{
+						Table: &pb.StatTable_PodGroup_{
+							PodGroup: &pb.StatTable_PodGroup{Rows: rows},
+						},
+					},
+				},
+				Partial: partial,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,199 @@
+package public
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/common/model"
+	"google.golang.org/grpc"
+)
+
+// serverClient sends the ListPods and StatSummary requests of a client to a
+// grpcServer.
+type serverClient struct {
+	pb.ApiClient
+	server   *grpcServer
+	requests int
+}
+
+func (c *serverClient) ListPods(ctx context.Context, req *pb.ListPodsRequest, _ ...grpc.CallOption) (*pb.ListPodsResponse, error) {
+	c.requests++
+	return c.server.ListPods(ctx, req)
+}
+
+func (c *serverClient) StatSummary(ctx context.Context, req *pb.StatSummaryRequest, _ ...grpc.CallOption) (*pb.StatSummaryResponse, error) {
+	c.requests++
+	return c.server.StatSummary(ctx, req)
+}
+
+func paginationPods(namespaces []string, count int) []string {
+	pods := []string{}
+	for _, ns := range namespaces {
+		for i := 0; i < count; i++ {
+			pods = append(pods, fmt.Sprintf(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod-%d
+  namespace: %s
+  labels:
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running
+`, i, ns))
+		}
+	}
+	return pods
+}
+
+func TestPaginate(t *testing.T) {
+	items := [][2]string{{"a", "x"}, {"a", "y"}, {"b", "x"}, {"c", "z"}}
+	key := func(i int) (string, string) { return items[i][0], items[i][1] }
+
+	testCases := []struct {
+		limit      uint32
+		token      string
+		start, end int
+		next       string
+	}{
+		{0, "", 0, 4, ""},
+		{2, "", 0, 2, encodeContinueToken("a", "y")},
+		{2, encodeContinueToken("a", "y"), 2, 4, ""},
+		{1, encodeContinueToken("b", "x"), 3, 4, ""},
+		// the items after the last one of the previous page, even if it's gone
+		{2, encodeContinueToken("a", "z"), 2, 4, ""},
+		{2, encodeContinueToken("d", "a"), 4, 4, ""},
+	}
+
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("%d: limit %d", i, tc.limit), func(t *testing.T) {
+			start, end, next, err := paginate(len(items), key, tc.limit, tc.token)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if start != tc.start || end != tc.end || next != tc.next {
+				t.Fatalf("Expected [%d:%d] and token %q, got [%d:%d] and token %q", tc.start, tc.end, tc.next, start, end, next)
+			}
+		})
+	}
+
+	t.Run("rejects invalid tokens", func(t *testing.T) {
+		_, _, _, err := paginate(len(items), key, 1, "not a token")
+		if err == nil || err.Error() != "invalid continue token: not a token" {
+			t.Fatalf("Expected an invalid token error, got: %v", err)
+		}
+	})
+}
+
+func TestListAllPods(t *testing.T) {
+	_, server, err := newMockGrpcServer(expectedStatRPC{
+		k8sConfigs:       paginationPods([]string{"emojivoto", "books"}, 3),
+		mockPromResponse: model.Vector{},
+	})
+	if err != nil {
+		t.Fatalf("Error creating mock grpc server: %s", err)
+	}
+
+	t.Run("returns a page of pods ordered by namespace and name", func(t *testing.T) {
+		rsp, err := server.ListPods(context.TODO(), &pb.ListPodsRequest{Limit: 4})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		names := []string{}
+		for _, pod := range rsp.GetPods() {
+			names = append(names, pod.Name)
+		}
+		expected := []string{"books/pod-0", "books/pod-1", "books/pod-2", "emojivoto/pod-0"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Fatalf("Expected pods %v, got %v", expected, names)
+		}
+		if rsp.GetContinueToken() != encodeContinueToken("emojivoto", "pod-0") {
+			t.Fatalf("Unexpected continue token: %s", rsp.GetContinueToken())
+		}
+	})
+
+	t.Run("rejects invalid tokens", func(t *testing.T) {
+		_, err := server.ListPods(context.TODO(), &pb.ListPodsRequest{ContinueToken: "not a token"})
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+	})
+
+	t.Run("requests all the pages", func(t *testing.T) {
+		client := &serverClient{server: server}
+		pods, err := ListAllPods(context.TODO(), client, &pb.ListPodsRequest{Namespace: "emojivoto"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(pods) != 3 || client.requests != 1 {
+			t.Fatalf("Expected 3 pods in 1 request, got %d pods in %d requests", len(pods), client.requests)
+		}
+	})
+}
+
+func TestStatSummaryAll(t *testing.T) {
+	pods := paginationPods([]string{"emojivoto"}, DefaultPageSize+1)
+	_, server, err := newMockGrpcServer(expectedStatRPC{
+		k8sConfigs:       pods,
+		mockPromResponse: model.Vector{},
+	})
+	if err != nil {
+		t.Fatalf("Error creating mock grpc server: %s", err)
+	}
+	req := &pb.StatSummaryRequest{
+		Selector: &pb.ResourceSelection{
+			Resource: &pb.Resource{Namespace: "emojivoto", Type: pkgK8s.Pod},
+		},
+		TimeWindow: "1m",
+		SkipStats:  true,
+	}
+
+	t.Run("merges the rows of all the pages", func(t *testing.T) {
+		client := &serverClient{server: server}
+		rsp, err := StatSummaryAll(context.TODO(), client, req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		rows := rsp.GetOk().GetStatTables()[0].GetPodGroup().GetRows()
+		if len(rows) != len(pods) || client.requests != 2 {
+			t.Fatalf("Expected %d rows in 2 requests, got %d rows in %d requests", len(pods), len(rows), client.requests)
+		}
+		for _, row := range rows {
+			if row.MeshedPodCount != 1 {
+				t.Fatalf("Expected 1 meshed pod for %s, got %d", row.Resource.Name, row.MeshedPodCount)
+			}
+		}
+	})
+
+	t.Run("rejects pagination for resource type 'all'", func(t *testing.T) {
+		allReq := *req
+		allReq.Selector = &pb.ResourceSelection{
+			Resource: &pb.Resource{Namespace: "emojivoto", Type: pkgK8s.All},
+		}
+		allReq.Limit = 1
+		rsp, err := server.StatSummary(context.TODO(), &allReq)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if rsp.GetError().GetError() != "pagination is not supported for resource type 'all'" {
+			t.Fatalf("Expected a pagination error, got: %v", rsp)
+		}
+	})
+
+	t.Run("rejects invalid tokens", func(t *testing.T) {
+		tokenReq := *req
+		tokenReq.ContinueToken = "not a token"
+		rsp, err := server.StatSummary(context.TODO(), &tokenReq)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if rsp.GetError().GetError() != "invalid continue token: not a token" {
+			t.Fatalf("Expected an invalid token error, got: %v", rsp)
+		}
+	})
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
@@ -22,12 +26,23 @@ type promResult struct {
 const (
 	promRequests       = promType("QUERY_REQUESTS")
 	promActualRequests = promType("QUERY_ACTUAL_REQUESTS")
+	promGrpcStatuses   = promType("QUERY_GRPC_STATUSES")
 	promLatencyP50     = promType("0.5")
 	promLatencyP95     = promType("0.95")
 	promLatencyP99     = promType("0.99")
 
 	namespaceLabel    = model.LabelName("namespace")
 	dstNamespaceLabel = model.LabelName("dst_namespace")
+
+	// The proxy sets no_tls_reason on the inbound metrics of plaintext
+	// requests; noIdentityReason means the client didn't present an identity
+	// and so isn't meshed. Without TLS, there is no such reason.
+	noTLSReasonLabel = model.LabelName("no_tls_reason")
+	noIdentityReason = model.LabelValue("not_provided_by_remote")
+
+	// The proxy sets grpc_status on the metrics of gRPC responses only, to the
+	// code of their grpc-status trailer.
+	grpcStatusLabel = model.LabelName("grpc_status")
 )
 
 func extractSampleValue(sample *model.Sample) uint64 {
@@ -38,15 +53,67 @@ func extractSampleValue(sample *model.Sample) uint64 {
 	return value
 }
 
+// addGrpcStatusCount adds the value of a sample grouped by grpc_status to the
+// count of its code in stats, keeping the counts ordered by code. Samples of
+// responses that aren't gRPC have no code and are ignored.
+func addGrpcStatusCount(stats *pb.BasicStats, sample *model.Sample) {
+	code, err := strconv.ParseUint(string(sample.Metric[grpcStatusLabel]), 10, 32)
+	if err != nil {
+		return
+	}
+	value := extractSampleValue(sample)
+
+	counts := stats.GrpcStatusCounts
+	i := sort.Search(len(counts), func(i int) bool { return counts[i].Code >= uint32(code) })
+	if i < len(counts) && counts[i].Code == uint32(code) {
+		counts[i].Count += value
+		return
+	}
+	counts = append(counts, nil)
+	copy(counts[i+1:], counts[i:])
+	counts[i] = &pb.GrpcStatusCount{Code: uint32(code), Count: value}
+	stats.GrpcStatusCounts = counts
+}
+
 func (s *grpcServer) queryProm(ctx context.Context, query string) (model.Vector, error) {
 	log.Debugf("Query request:\n\t%+v", query)
 
+	if !s.breaker.allow() {
+		return nil, errPrometheusUnavailable
+	}
+
+	parent := ctx
+	budget := queryBudgetFromContext(ctx)
+	if budget != nil {
+		release, err := budget.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		if budget.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, budget.timeout)
+			defer cancel()
+		}
+	}
+
 	// single data point (aka summary) query
 	res, err := s.prometheusAPI.Query(ctx, query, time.Time{})
 	if err != nil {
 		log.Errorf("Query(%+v) failed with: %+v", query, err)
+
+		// failures caused by the request being cancelled or running out of
+		// budget don't say anything about the health of Prometheus
+		if parent.Err() == nil {
+			s.breaker.record(err)
+		}
+		if budget != nil && ctx.Err() == context.DeadlineExceeded {
+			return nil, errQueryBudgetExceeded
+		}
 		return nil, err
 	}
+	s.breaker.record(nil)
 	log.Debugf("Query response:\n\t%+v", res)
 
 	if res.Type() != model.ValVector {
@@ -113,6 +180,66 @@ func promDstQueryLabels(resource *pb.Resource) model.LabelSet {
 	return set
 }
 
+// query for the metric labels added from pod labels, which are prefixed with
+// "dst_" in outbound metrics
+func promMetricLabels(metricLabels map[string]string, dst bool) model.LabelSet {
+	set := model.LabelSet{}
+	for name, value := range metricLabels {
+		if dst {
+			name = "dst_" + name
+		}
+		set[model.LabelName(name)] = model.LabelValue(value)
+	}
+	return set
+}
+
+// promLabelMatchers renders the labels as the label matchers of a query, like
+// LabelSet.String, except that the values that are name patterns are matched
+// as regexes.
+func promLabelMatchers(labels model.LabelSet) string {
+	return fmt.Sprintf("{%s}", strings.Join(promLabelPairs(labels), ", "))
+}
+
+// promLabelPairs returns the sorted label matchers of the labels.
+func promLabelPairs(labels model.LabelSet) []string {
+	pairs := make([]string, 0, len(labels))
+	for name, value := range labels {
+		if isNamePattern(string(value)) {
+			pairs = append(pairs, fmt.Sprintf("%s=~%q", name, promNameRegex(string(value))))
+		} else {
+			pairs = append(pairs, fmt.Sprintf("%s=%q", name, value))
+		}
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// isNamePattern returns whether the name of a --to or --from resource is a
+// pattern: comma-separated names, or names with * wildcards. Resource names
+// and label values can't contain either character.
+func isNamePattern(name string) bool {
+	return strings.ContainsAny(name, ",*")
+}
+
+// promNameRegex returns the regex that matches the names of a pattern. A bare
+// wildcard matches any name, but not the metrics without the label, such as
+// those of the pods of other resource types.
+func promNameRegex(pattern string) string {
+	alternatives := []string{}
+	for _, name := range strings.Split(pattern, ",") {
+		if strings.Trim(name, "*") == "" {
+			alternatives = append(alternatives, ".+")
+			continue
+		}
+		parts := strings.Split(name, "*")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		alternatives = append(alternatives, strings.Join(parts, ".*"))
+	}
+	return strings.Join(alternatives, "|")
+}
+
 // determine if we should add "namespace=<namespace>" to a named query
 func shouldAddNamespaceLabel(resource *pb.Resource) bool {
 	return resource.Type != k8s.Namespace && resource.Namespace != ""
@@ -170,7 +297,9 @@ func (s *grpcServer) getPrometheusMetrics(ctx context.Context, requestQueryTempl
 		result := <-resultChan
 		if result.err != nil {
 			log.Errorf("queryProm failed with: %s", result.err)
-			err = result.err
+			if e := dropIfBudgetExceeded(ctx, result.err); e != nil {
+				err = e
+			}
 		} else {
 			results = append(results, result)
 		}
@@ -0,0 +1,139 @@
+package public
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+)
+
+// Results of the cache lookups, used as the values of the result label of the
+// prometheus_query_cache_requests_total counter.
+const (
+	cacheHit  = "hit"
+	cacheMiss = "miss"
+)
+
+// cachingPromAPI caches the results of instant queries for a short TTL, so
+// that the dashboards that several users auto-refresh every few seconds don't
+// each send the same queries to Prometheus. Concurrent identical queries are
+// sent once, and failed queries aren't cached.
+//
+// Only the instant queries evaluated at the current time are cached, since
+// their results are at most the TTL old. The range queries, and the queries
+// evaluated at a given time, are passed through.
+type cachingPromAPI struct {
+	promv1.API
+	ttl      time.Duration
+	now      func() time.Time
+	requests *prometheus.CounterVec
+
+	mutex   sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// cacheEntry is the result of a query, which is pending until done is closed.
+type cacheEntry struct {
+	done    chan struct{}
+	value   model.Value
+	err     error
+	expires time.Time
+}
+
+// NewCachingPrometheusAPI returns a Prometheus API that caches the results of
+// api's instant queries for ttl, and registers its hit and miss counter with
+// the given registerer. If ttl isn't positive, it returns api.
+func NewCachingPrometheusAPI(api promv1.API, ttl time.Duration, registerer prometheus.Registerer) (promv1.API, error) {
+	if ttl <= 0 {
+		return api, nil
+	}
+
+	c := newCachingPromAPI(api, ttl)
+	if err := registerer.Register(c.requests); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func newCachingPromAPI(api promv1.API, ttl time.Duration) *cachingPromAPI {
+	return &cachingPromAPI{
+		API: api,
+		ttl: ttl,
+		now: time.Now,
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "prometheus_query_cache_requests_total",
+				Help: "The number of Prometheus queries served from the query cache (hit) or sent to Prometheus (miss).",
+			},
+			[]string{"result"},
+		),
+		entries: make(map[string]*cacheEntry),
+	}
+}
+
+func (c *cachingPromAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, error) {
+	if !ts.IsZero() {
+		return c.API.Query(ctx, query, ts)
+	}
+
+	key := normalizeQuery(query)
+	c.mutex.Lock()
+	now := c.now()
+	if entry, ok := c.entries[key]; ok && (now.Before(entry.expires) || entry.expires.IsZero()) {
+		c.mutex.Unlock()
+		c.requests.WithLabelValues(cacheHit).Inc()
+		return entry.wait(ctx)
+	}
+	c.evictExpired(now)
+	entry := &cacheEntry{done: make(chan struct{})}
+	c.entries[key] = entry
+	c.mutex.Unlock()
+	c.requests.WithLabelValues(cacheMiss).Inc()
+
+	// the identical queries waiting for the result share its error if it's
+	// cancelled, since it isn't cached
+	entry.value, entry.err = c.API.Query(ctx, query, ts)
+
+	c.mutex.Lock()
+	if entry.err != nil {
+		delete(c.entries, key)
+	} else {
+		entry.expires = c.now().Add(c.ttl)
+	}
+	close(entry.done)
+	c.mutex.Unlock()
+
+	return entry.value, entry.err
+}
+
+// evictExpired removes the expired results. It must be called with the mutex
+// held.
+func (c *cachingPromAPI) evictExpired(now time.Time) {
+	for key, entry := range c.entries {
+		if !entry.expires.IsZero() && !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// wait returns the result of the query once it's done, or the error of ctx if
+// it's done first.
+func (e *cacheEntry) wait(ctx context.Context) (model.Value, error) {
+	select {
+	case <-e.done:
+		return e.value, e.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// normalizeQuery collapses the whitespace of a query, so that the queries that
+// only differ by their formatting share their results. The time window of a
+// query is part of its text, such as the "1m" of "[1m]".
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
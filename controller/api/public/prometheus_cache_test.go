@@ -0,0 +1,110 @@
+package public
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/model"
+)
+
+func cacheRequests(c *cachingPromAPI, result string) float64 {
+	m := &dto.Metric{}
+	if err := c.requests.WithLabelValues(result).Write(m); err != nil {
+		return 0
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestCachingPromAPI(t *testing.T) {
+	query := `sum(increase(response_total{direction="inbound"}[1m])) by (deployment)`
+	now := time.Unix(1000, 0)
+	newCache := func(prom *mockProm) *cachingPromAPI {
+		c := newCachingPromAPI(prom, 5*time.Second)
+		c.now = func() time.Time { return now }
+		return c
+	}
+
+	t.Run("Serves identical queries from the cache until they expire", func(t *testing.T) {
+		prom := &mockProm{Res: model.Vector{deploySample("emoji", 10)}}
+		c := newCache(prom)
+
+		for _, q := range []string{query, "  " + query, `sum(increase(response_total{direction="inbound"}[1m]))  by (deployment)`} {
+			value, err := c.Query(context.Background(), q, time.Time{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if value.String() != prom.Res.String() {
+				t.Fatalf("Expected %s, got %s", prom.Res, value)
+			}
+		}
+		if len(prom.QueriesExecuted) != 1 {
+			t.Fatalf("Expected 1 query to Prometheus, got %d", len(prom.QueriesExecuted))
+		}
+		if hits, misses := cacheRequests(c, cacheHit), cacheRequests(c, cacheMiss); hits != 2 || misses != 1 {
+			t.Fatalf("Expected 2 hits and 1 miss, got %f hits and %f misses", hits, misses)
+		}
+
+		now = now.Add(5 * time.Second)
+		if _, err := c.Query(context.Background(), query, time.Time{}); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(prom.QueriesExecuted) != 2 {
+			t.Fatalf("Expected the expired result to be queried again, got %d queries", len(prom.QueriesExecuted))
+		}
+	})
+
+	t.Run("Keys the results by query", func(t *testing.T) {
+		prom := &mockProm{Res: model.Vector{}}
+		c := newCache(prom)
+
+		for _, q := range []string{query, `sum(increase(response_total{direction="inbound"}[10m])) by (deployment)`} {
+			if _, err := c.Query(context.Background(), q, time.Time{}); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		}
+		if len(prom.QueriesExecuted) != 2 {
+			t.Fatalf("Expected the queries of different windows to be sent, got %d queries", len(prom.QueriesExecuted))
+		}
+	})
+
+	t.Run("Doesn't cache the queries evaluated at a given time", func(t *testing.T) {
+		prom := &mockProm{Res: model.Vector{}}
+		c := newCache(prom)
+
+		for i := 0; i < 2; i++ {
+			if _, err := c.Query(context.Background(), query, now); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		}
+		if len(prom.QueriesExecuted) != 2 {
+			t.Fatalf("Expected 2 queries to Prometheus, got %d", len(prom.QueriesExecuted))
+		}
+	})
+
+	t.Run("Doesn't cache failed queries", func(t *testing.T) {
+		c := newCachingPromAPI(&failingProm{}, 5*time.Second)
+		for i := 0; i < 2; i++ {
+			if _, err := c.Query(context.Background(), query, time.Time{}); err == nil {
+				t.Fatal("Expected the error of Prometheus")
+			}
+		}
+		if misses := cacheRequests(c, cacheMiss); misses != 2 {
+			t.Fatalf("Expected 2 misses, got %f", misses)
+		}
+	})
+}
+
+func TestNewCachingPrometheusAPI(t *testing.T) {
+	t.Run("Doesn't cache with a zero TTL", func(t *testing.T) {
+		prom := &mockProm{}
+		api, err := NewCachingPrometheusAPI(prom, 0, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if api != prom {
+			t.Fatalf("Expected the Prometheus API to be returned, got %#v", api)
+		}
+	})
+}
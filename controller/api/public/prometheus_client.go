@@ -0,0 +1,84 @@
+package public
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	promApi "github.com/prometheus/client_golang/api"
+)
+
+// PrometheusConfig configures how the public API connects to Prometheus.
+type PrometheusConfig struct {
+	// URL is the address of Prometheus.
+	URL string
+
+	// BearerTokenFile is the path of a file with the token to authenticate
+	// to Prometheus with. It's read for each query, so that the token can be
+	// rotated without restarting the public API.
+	BearerTokenFile string
+
+	// CAFile is the path of a PEM-encoded CA bundle to verify the TLS
+	// certificate of Prometheus with, instead of the system's.
+	CAFile string
+}
+
+// NewPrometheusClient returns a Prometheus API client configured by config.
+func NewPrometheusClient(config PrometheusConfig) (promApi.Client, error) {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+
+	if config.CAFile != "" {
+		pem, err := ioutil.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", config.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: roots}
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if config.BearerTokenFile != "" {
+		roundTripper = &bearerTokenRoundTripper{tokenFile: config.BearerTokenFile, next: transport}
+	}
+
+	return promApi.NewClient(promApi.Config{Address: config.URL, RoundTripper: roundTripper})
+}
+
+// bearerTokenRoundTripper authenticates each request with the token in
+// tokenFile.
+type bearerTokenRoundTripper struct {
+	tokenFile string
+	next      http.RoundTripper
+}
+
+func (rt *bearerTokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := ioutil.ReadFile(rt.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Prometheus bearer token: %s", err)
+	}
+
+	// the request must not be modified, as per the RoundTripper contract
+	authenticated := new(http.Request)
+	*authenticated = *req
+	authenticated.Header = make(http.Header, len(req.Header)+1)
+	for key, values := range req.Header {
+		authenticated.Header[key] = values
+	}
+	authenticated.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	return rt.next.RoundTrip(authenticated)
+}
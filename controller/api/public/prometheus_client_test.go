@@ -0,0 +1,97 @@
+package public
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+func TestNewPrometheusClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"status":"error","errorType":"unauthorized","error":"invalid token"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "prometheus-client")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	caFile := filepath.Join(dir, "ca.crt")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := ioutil.WriteFile(caFile, ca, 0600); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	tokenFile := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		title  string
+		config PrometheusConfig
+		err    string
+	}{
+		{
+			title:  "authenticates with the bearer token over TLS",
+			config: PrometheusConfig{URL: server.URL, BearerTokenFile: tokenFile, CAFile: caFile},
+		},
+		{
+			title:  "fails without the bearer token",
+			config: PrometheusConfig{URL: server.URL, CAFile: caFile},
+			err:    "bad response code 401",
+		},
+		{
+			title:  "fails without the CA",
+			config: PrometheusConfig{URL: server.URL, BearerTokenFile: tokenFile},
+			err:    "x509: certificate signed by unknown authority",
+		},
+		{
+			title:  "fails when the bearer token can't be read",
+			config: PrometheusConfig{URL: server.URL, BearerTokenFile: filepath.Join(dir, "missing"), CAFile: caFile},
+			err:    "failed to read the Prometheus bearer token",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			client, err := NewPrometheusClient(tc.config)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			_, err = v1.NewAPI(client).Query(context.Background(), "up", time.Now())
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("Expected error containing %q, got: %v", tc.err, err)
+			}
+		})
+	}
+
+	t.Run("rejects CA files without certificates", func(t *testing.T) {
+		_, err := NewPrometheusClient(PrometheusConfig{URL: server.URL, CAFile: tokenFile})
+		if err == nil || err.Error() != fmt.Sprintf("no certificates found in %s", tokenFile) {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+}
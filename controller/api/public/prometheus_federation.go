@@ -0,0 +1,286 @@
+package public
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// federatedPromAPI queries several Prometheus instances, each scraping a part
+// of the mesh, such as a zone or a shard, and merges their results, so that the
+// public API isn't limited by the capacity of a single Prometheus.
+//
+// The series that several instances return are merged according to the
+// outermost aggregation of the query: the maximum of the values for max() and
+// histogram_quantile(), the minimum for min(), and their sum otherwise. The
+// latency percentiles of a resource whose pods are scraped by several
+// instances are thus the highest of their percentiles, an upper bound of the
+// actual ones.
+type federatedPromAPI struct {
+	apis []promv1.API
+}
+
+type mergeFunc func(a, b model.SampleValue) model.SampleValue
+
+// NewPrometheusAPI returns a Prometheus API that queries the instances
+// configured by configs, merging their results if there are several.
+func NewPrometheusAPI(configs []PrometheusConfig) (promv1.API, error) {
+	if len(configs) == 0 {
+		return nil, errors.New("no Prometheus configured")
+	}
+
+	apis := make([]promv1.API, len(configs))
+	for i, config := range configs {
+		client, err := NewPrometheusClient(config)
+		if err != nil {
+			return nil, err
+		}
+		apis[i] = promv1.NewAPI(client)
+	}
+	if len(apis) == 1 {
+		return apis[0], nil
+	}
+	return &federatedPromAPI{apis: apis}, nil
+}
+
+func (f *federatedPromAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, error) {
+	values, err := f.fanOut(func(api promv1.API) (model.Value, error) {
+		return api.Query(ctx, query, ts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mergeValues(values, queryMergeFunc(query)), nil
+}
+
+func (f *federatedPromAPI) QueryRange(ctx context.Context, query string, r promv1.Range) (model.Value, error) {
+	values, err := f.fanOut(func(api promv1.API) (model.Value, error) {
+		return api.QueryRange(ctx, query, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mergeValues(values, queryMergeFunc(query)), nil
+}
+
+func (f *federatedPromAPI) LabelValues(ctx context.Context, label string) (model.LabelValues, error) {
+	var mutex sync.Mutex
+	seen := make(map[model.LabelValue]struct{})
+	err := f.each(func(api promv1.API) error {
+		values, err := api.LabelValues(ctx, label)
+		if err != nil {
+			return err
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, value := range values {
+			seen[value] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(model.LabelValues, 0, len(seen))
+	for value := range seen {
+		merged = append(merged, value)
+	}
+	sort.Sort(merged)
+	return merged, nil
+}
+
+func (f *federatedPromAPI) Series(ctx context.Context, matches []string, startTime time.Time, endTime time.Time) ([]model.LabelSet, error) {
+	var mutex sync.Mutex
+	seen := make(map[model.Fingerprint]model.LabelSet)
+	err := f.each(func(api promv1.API) error {
+		series, err := api.Series(ctx, matches, startTime, endTime)
+		if err != nil {
+			return err
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, labels := range series {
+			seen[labels.Fingerprint()] = labels
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make([]model.LabelSet, 0, len(seen))
+	for _, labels := range seen {
+		merged = append(merged, labels)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Before(merged[j])
+	})
+	return merged, nil
+}
+
+// fanOut runs the query against all the instances concurrently, and returns
+// their results in the order of the instances, or the first error.
+func (f *federatedPromAPI) fanOut(query func(promv1.API) (model.Value, error)) ([]model.Value, error) {
+	values := make([]model.Value, len(f.apis))
+	err := f.eachIndexed(func(i int, api promv1.API) error {
+		value, err := query(api)
+		values[i] = value
+		return err
+	})
+	return values, err
+}
+
+func (f *federatedPromAPI) each(call func(promv1.API) error) error {
+	return f.eachIndexed(func(_ int, api promv1.API) error {
+		return call(api)
+	})
+}
+
+// eachIndexed calls all the instances concurrently, and returns the first
+// error. A partial result would be misleading, since the series of the failed
+// instances would be missing, or undercounted.
+func (f *federatedPromAPI) eachIndexed(call func(int, promv1.API) error) error {
+	errs := make([]error, len(f.apis))
+	var wg sync.WaitGroup
+	for i, api := range f.apis {
+		wg.Add(1)
+		go func(i int, api promv1.API) {
+			defer wg.Done()
+			errs[i] = call(i, api)
+		}(i, api)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryMergeFunc returns how the values of a series returned by several
+// instances are merged, from the outermost aggregation of the query.
+func queryMergeFunc(query string) mergeFunc {
+	query = strings.TrimSpace(query)
+	switch {
+	case strings.HasPrefix(query, "max(") || strings.HasPrefix(query, "histogram_quantile("):
+		return func(a, b model.SampleValue) model.SampleValue {
+			if b > a {
+				return b
+			}
+			return a
+		}
+	case strings.HasPrefix(query, "min("):
+		return func(a, b model.SampleValue) model.SampleValue {
+			if b < a {
+				return b
+			}
+			return a
+		}
+	default:
+		return func(a, b model.SampleValue) model.SampleValue {
+			return a + b
+		}
+	}
+}
+
+// mergeValues merges the results of the instances, which all have the type of
+// the query's result. The series of a vector or a matrix are merged by their
+// labels, and the samples of the series of a matrix by their timestamps.
+func mergeValues(values []model.Value, merge mergeFunc) model.Value {
+	var merged model.Value
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		if merged == nil {
+			merged = value
+			continue
+		}
+
+		switch typed := value.(type) {
+		case model.Vector:
+			if vec, ok := merged.(model.Vector); ok {
+				merged = mergeVectors(vec, typed, merge)
+			}
+		case model.Matrix:
+			if matrix, ok := merged.(model.Matrix); ok {
+				merged = mergeMatrices(matrix, typed, merge)
+			}
+		case *model.Scalar:
+			if scalar, ok := merged.(*model.Scalar); ok {
+				merged = &model.Scalar{Value: merge(scalar.Value, typed.Value), Timestamp: scalar.Timestamp}
+			}
+		}
+	}
+	return merged
+}
+
+func mergeVectors(a, b model.Vector, merge mergeFunc) model.Vector {
+	merged := make(model.Vector, 0, len(a)+len(b))
+	index := make(map[model.Fingerprint]int, len(a))
+	for _, sample := range a {
+		index[sample.Metric.Fingerprint()] = len(merged)
+		copied := *sample
+		merged = append(merged, &copied)
+	}
+	for _, sample := range b {
+		if i, ok := index[sample.Metric.Fingerprint()]; ok {
+			merged[i].Value = merge(merged[i].Value, sample.Value)
+			continue
+		}
+		index[sample.Metric.Fingerprint()] = len(merged)
+		copied := *sample
+		merged = append(merged, &copied)
+	}
+	return merged
+}
+
+func mergeMatrices(a, b model.Matrix, merge mergeFunc) model.Matrix {
+	merged := make(model.Matrix, 0, len(a)+len(b))
+	index := make(map[model.Fingerprint]int, len(a))
+	for _, stream := range a {
+		index[stream.Metric.Fingerprint()] = len(merged)
+		merged = append(merged, &model.SampleStream{Metric: stream.Metric, Values: stream.Values})
+	}
+	for _, stream := range b {
+		i, ok := index[stream.Metric.Fingerprint()]
+		if !ok {
+			index[stream.Metric.Fingerprint()] = len(merged)
+			merged = append(merged, &model.SampleStream{Metric: stream.Metric, Values: stream.Values})
+			continue
+		}
+		merged[i].Values = mergeSamplePairs(merged[i].Values, stream.Values, merge)
+	}
+	return merged
+}
+
+// mergeSamplePairs merges two series of samples sorted by timestamp.
+func mergeSamplePairs(a, b []model.SamplePair, merge mergeFunc) []model.SamplePair {
+	merged := make([]model.SamplePair, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i].Timestamp.Before(b[j].Timestamp):
+			merged = append(merged, a[i])
+			i++
+		case b[j].Timestamp.Before(a[i].Timestamp):
+			merged = append(merged, b[j])
+			j++
+		default:
+			merged = append(merged, model.SamplePair{Timestamp: a[i].Timestamp, Value: merge(a[i].Value, b[j].Value)})
+			i++
+			j++
+		}
+	}
+	merged = append(merged, a[i:]...)
+	return append(merged, b[j:]...)
+}
@@ -0,0 +1,113 @@
+package public
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+type failingProm struct {
+	mockProm
+}
+
+func (m *failingProm) Query(ctx context.Context, query string, ts time.Time) (model.Value, error) {
+	return nil, errors.New("prometheus-zone-b is unavailable")
+}
+
+func deploySample(name string, value model.SampleValue) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{"deployment": model.LabelValue(name)},
+		Value:  value,
+	}
+}
+
+func TestFederatedPromAPI(t *testing.T) {
+	zoneA := model.Vector{deploySample("emoji", 10), deploySample("web", 4)}
+	zoneB := model.Vector{deploySample("emoji", 5), deploySample("voting", 2)}
+	federated := &federatedPromAPI{apis: []promv1.API{&mockProm{Res: zoneA}, &mockProm{Res: zoneB}}}
+
+	t.Run("Merges vectors by their labels", func(t *testing.T) {
+		for _, tc := range []struct {
+			query    string
+			expected model.Vector
+		}{
+			{
+				`sum(increase(response_total{direction="inbound"}[1m])) by (deployment)`,
+				model.Vector{deploySample("emoji", 15), deploySample("web", 4), deploySample("voting", 2)},
+			},
+			{
+				`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound"}[1m])) by (le, deployment))`,
+				model.Vector{deploySample("emoji", 10), deploySample("web", 4), deploySample("voting", 2)},
+			},
+			{
+				`min(process_start_time_seconds) by (deployment)`,
+				model.Vector{deploySample("emoji", 5), deploySample("web", 4), deploySample("voting", 2)},
+			},
+		} {
+			value, err := federated.Query(context.Background(), tc.query, time.Time{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(value, tc.expected) {
+				t.Fatalf("Expected %s for [%s], got %s", tc.expected, tc.query, value)
+			}
+		}
+	})
+
+	t.Run("Doesn't modify the results of the instances", func(t *testing.T) {
+		if zoneA[0].Value != 10 {
+			t.Fatalf("Expected the sample of the first instance to be unchanged, got %s", zoneA[0])
+		}
+	})
+
+	t.Run("Merges matrices by their labels and timestamps", func(t *testing.T) {
+		metric := model.Metric{"deployment": "emoji"}
+		matrixA := model.Matrix{&model.SampleStream{Metric: metric, Values: []model.SamplePair{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 2}}}}
+		matrixB := model.Matrix{&model.SampleStream{Metric: metric, Values: []model.SamplePair{{Timestamp: 2000, Value: 3}, {Timestamp: 3000, Value: 4}}}}
+		federated := &federatedPromAPI{apis: []promv1.API{&mockProm{Res: matrixA}, &mockProm{Res: matrixB}}}
+
+		value, err := federated.QueryRange(context.Background(), "sum(request_total) by (deployment)", promv1.Range{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := model.Matrix{&model.SampleStream{Metric: metric, Values: []model.SamplePair{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 5}, {Timestamp: 3000, Value: 4}}}}
+		if !reflect.DeepEqual(value, expected) {
+			t.Fatalf("Expected %s, got %s", expected, value)
+		}
+	})
+
+	t.Run("Fails if any instance fails", func(t *testing.T) {
+		federated := &federatedPromAPI{apis: []promv1.API{&mockProm{Res: zoneA}, &failingProm{}}}
+		_, err := federated.Query(context.Background(), "sum(request_total) by (deployment)", time.Time{})
+		if err == nil || err.Error() != "prometheus-zone-b is unavailable" {
+			t.Fatalf("Expected the error of the failed instance, got %v", err)
+		}
+	})
+}
+
+func TestNewPrometheusAPI(t *testing.T) {
+	t.Run("Queries a single Prometheus directly", func(t *testing.T) {
+		api, err := NewPrometheusAPI([]PrometheusConfig{{URL: "http://prometheus:9090"}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if _, ok := api.(*federatedPromAPI); ok {
+			t.Fatal("Expected a single Prometheus not to be federated")
+		}
+	})
+
+	t.Run("Federates several Prometheus", func(t *testing.T) {
+		api, err := NewPrometheusAPI([]PrometheusConfig{{URL: "http://prometheus-zone-a:9090"}, {URL: "http://prometheus-zone-b:9090"}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if federated, ok := api.(*federatedPromAPI); !ok || len(federated.apis) != 2 {
+			t.Fatalf("Expected a federation of 2 instances, got %#v", api)
+		}
+	})
+}
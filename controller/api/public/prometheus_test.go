@@ -0,0 +1,45 @@
+package public
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+)
+
+func TestPromLabelMatchers(t *testing.T) {
+	testCases := []struct {
+		labels   model.LabelSet
+		matchers string
+	}{
+		{
+			model.LabelSet{"direction": "outbound", "deployment": "web"},
+			`{deployment="web", direction="outbound"}`,
+		},
+		{
+			model.LabelSet{"deployment": "*", "namespace": "emojivoto"},
+			`{deployment=~".+", namespace="emojivoto"}`,
+		},
+		{
+			model.LabelSet{"deployment": "web,voting"},
+			`{deployment=~"web|voting"}`,
+		},
+		{
+			model.LabelSet{"authority": "web.emojivoto.svc.cluster.local:*"},
+			`{authority=~"web\\.emojivoto\\.svc\\.cluster\\.local:.*"}`,
+		},
+		{
+			model.LabelSet{},
+			`{}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.matchers, func(t *testing.T) {
+			matchers := promLabelMatchers(tc.labels)
+			if matchers != tc.matchers {
+				t.Fatalf("Expected matchers %s, got %s", tc.matchers, matchers)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package public
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// QueryLimits bounds the load that public API requests put on Prometheus. A
+// zero value disables the corresponding limit.
+type QueryLimits struct {
+	// MaxConcurrentQueries is the maximum number of Prometheus queries a single
+	// request can have in flight at once.
+	MaxConcurrentQueries int
+
+	// QueryTimeout is the maximum duration of a single Prometheus query.
+	QueryTimeout time.Duration
+
+	// RequestBudget is the maximum total duration of the Prometheus queries of
+	// a single request. Queries that don't complete within the budget are
+	// dropped, and the response is flagged as partial.
+	RequestBudget time.Duration
+
+	// BreakerThreshold is the number of consecutive failed Prometheus queries
+	// after which queries are rejected without being sent to Prometheus, for
+	// BreakerCooldown.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+var (
+	errQueryBudgetExceeded   = errors.New("Prometheus query budget exceeded")
+	errPrometheusUnavailable = status.Error(codes.Unavailable, "Prometheus is unavailable: too many queries failed, retry later")
+)
+
+type queryBudgetKey struct{}
+
+// queryBudget tracks the Prometheus queries of a single request.
+type queryBudget struct {
+	sem     chan struct{}
+	timeout time.Duration
+
+	sync.Mutex
+	partial bool
+}
+
+// withQueryBudget returns a context that applies the server's QueryLimits to
+// all the Prometheus queries made with it, and the budget tracking them.
+func (s *grpcServer) withQueryBudget(ctx context.Context) (context.Context, context.CancelFunc, *queryBudget) {
+	budget := &queryBudget{timeout: s.queryLimits.QueryTimeout}
+	if s.queryLimits.MaxConcurrentQueries > 0 {
+		budget.sem = make(chan struct{}, s.queryLimits.MaxConcurrentQueries)
+	}
+
+	cancel := func() {}
+	if s.queryLimits.RequestBudget > 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.queryLimits.RequestBudget)
+	}
+
+	return context.WithValue(ctx, queryBudgetKey{}, budget), cancel, budget
+}
+
+func queryBudgetFromContext(ctx context.Context) *queryBudget {
+	budget, _ := ctx.Value(queryBudgetKey{}).(*queryBudget)
+	return budget
+}
+
+// acquire waits for one of the budget's query slots to be available, and
+// returns a function releasing it.
+func (b *queryBudget) acquire(ctx context.Context) (func(), error) {
+	if b.sem == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case b.sem <- struct{}{}:
+		return func() { <-b.sem }, nil
+	case <-ctx.Done():
+		return nil, errQueryBudgetExceeded
+	}
+}
+
+func (b *queryBudget) markPartial() {
+	b.Lock()
+	defer b.Unlock()
+	b.partial = true
+}
+
+func (b *queryBudget) isPartial() bool {
+	b.Lock()
+	defer b.Unlock()
+	return b.partial
+}
+
+// dropIfBudgetExceeded returns nil if err is due to the request's query
+// budget being exceeded, flagging the request as partial. Otherwise, it
+// returns err.
+func dropIfBudgetExceeded(ctx context.Context, err error) error {
+	budget := queryBudgetFromContext(ctx)
+	if budget == nil || err != errQueryBudgetExceeded {
+		return err
+	}
+
+	budget.markPartial()
+	return nil
+}
+
+// queryBreaker rejects Prometheus queries for a cooldown period after too
+// many consecutive queries failed, so that an overloaded Prometheus gets a
+// chance to recover.
+type queryBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	sync.Mutex
+	failures  int
+	openUntil time.Time
+	now       func() time.Time
+}
+
+func newQueryBreaker(threshold int, cooldown time.Duration) *queryBreaker {
+	return &queryBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+func (b *queryBreaker) allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	return !b.now().Before(b.openUntil)
+}
+
+func (b *queryBreaker) record(err error) {
+	if b == nil {
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if err == nil {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
@@ -0,0 +1,221 @@
+package public
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tap "github.com/linkerd/linkerd2/controller/gen/controller/tap"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/common/model"
+)
+
+// slowProm is a mock Prometheus that blocks latency queries until their
+// context is done, and tracks the number of queries in flight.
+type slowProm struct {
+	mockProm
+	err error
+
+	sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (m *slowProm) Query(ctx context.Context, query string, ts time.Time) (model.Value, error) {
+	m.Lock()
+	m.inFlight++
+	if m.inFlight > m.maxInFlight {
+		m.maxInFlight = m.inFlight
+	}
+	m.Unlock()
+
+	defer func() {
+		m.Lock()
+		m.inFlight--
+		m.Unlock()
+	}()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if strings.HasPrefix(query, "histogram_quantile") {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	// give concurrent queries a chance to overlap
+	time.Sleep(time.Millisecond)
+	return m.mockProm.Query(ctx, query, ts)
+}
+
+func newBudgetGrpcServer(t *testing.T, prom *slowProm, limits QueryLimits) *grpcServer {
+	k8sAPI, err := k8s.NewFakeAPI("", `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-1
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running
+`)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+
+	server := newGrpcServer(
+		prom,
+		tap.NewTapClient(nil),
+		k8sAPI,
+		"linkerd",
+		[]string{},
+		limits,
+	)
+
+	k8sAPI.Sync()
+
+	return server
+}
+
+var budgetStatSummaryReq = &pb.StatSummaryRequest{
+	Selector: &pb.ResourceSelection{
+		Resource: &pb.Resource{
+			Namespace: "emojivoto",
+			Type:      pkgK8s.Pod,
+		},
+	},
+	TimeWindow: "1m",
+}
+
+func TestQueryBudget(t *testing.T) {
+	t.Run("Flags the response as partial when queries exceed their timeout", func(t *testing.T) {
+		prom := &slowProm{mockProm: mockProm{Res: prometheusMetric("emojivoto-1", "pod", "emojivoto", "success", false)}}
+		server := newBudgetGrpcServer(t, prom, QueryLimits{QueryTimeout: 10 * time.Millisecond})
+
+		rsp, err := server.StatSummary(context.Background(), budgetStatSummaryReq)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if !rsp.GetOk().GetPartial() {
+			t.Fatalf("Expected the response to be partial, got: %+v", rsp)
+		}
+
+		rows := rsp.GetOk().GetStatTables()[0].GetPodGroup().GetRows()
+		if len(rows) != 1 {
+			t.Fatalf("Expected 1 row, got: %+v", rows)
+		}
+		stats := rows[0].GetStats()
+		if stats.GetSuccessCount() != 123 || stats.GetLatencyMsP50() != 0 {
+			t.Fatalf("Expected request counts without latencies, got: %+v", stats)
+		}
+	})
+
+	t.Run("Flags the response as partial when the request exceeds its budget", func(t *testing.T) {
+		prom := &slowProm{mockProm: mockProm{Res: prometheusMetric("emojivoto-1", "pod", "emojivoto", "success", false)}}
+		server := newBudgetGrpcServer(t, prom, QueryLimits{RequestBudget: 10 * time.Millisecond})
+
+		rsp, err := server.StatSummary(context.Background(), budgetStatSummaryReq)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if !rsp.GetOk().GetPartial() {
+			t.Fatalf("Expected the response to be partial, got: %+v", rsp)
+		}
+	})
+
+	t.Run("Limits the number of concurrent queries", func(t *testing.T) {
+		prom := &slowProm{mockProm: mockProm{Res: prometheusMetric("emojivoto-1", "pod", "emojivoto", "success", false)}}
+		server := newBudgetGrpcServer(t, prom, QueryLimits{
+			MaxConcurrentQueries: 1,
+			QueryTimeout:         10 * time.Millisecond,
+		})
+
+		_, err := server.StatSummary(context.Background(), budgetStatSummaryReq)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if prom.maxInFlight != 1 {
+			t.Fatalf("Expected at most 1 query in flight, got %d", prom.maxInFlight)
+		}
+	})
+
+	t.Run("Returns an error when queries fail", func(t *testing.T) {
+		prom := &slowProm{err: errors.New("prometheus is down")}
+		server := newBudgetGrpcServer(t, prom, QueryLimits{QueryTimeout: 10 * time.Millisecond})
+
+		_, err := server.StatSummary(context.Background(), budgetStatSummaryReq)
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+	})
+
+	t.Run("Rejects queries while the breaker is open", func(t *testing.T) {
+		prom := &slowProm{err: errors.New("prometheus is down")}
+		server := newBudgetGrpcServer(t, prom, QueryLimits{
+			BreakerThreshold: 1,
+			BreakerCooldown:  time.Minute,
+		})
+
+		_, err := server.StatSummary(context.Background(), budgetStatSummaryReq)
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		prom.err = nil
+		_, err = server.StatSummary(context.Background(), budgetStatSummaryReq)
+		if err == nil || !strings.Contains(err.Error(), "Prometheus is unavailable") {
+			t.Fatalf("Expected the breaker to reject the request, got: %v", err)
+		}
+	})
+}
+
+func TestQueryBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	breaker := newQueryBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	failure := errors.New("query failed")
+
+	breaker.record(failure)
+	if !breaker.allow() {
+		t.Fatal("Expected the breaker to allow queries below its threshold")
+	}
+
+	breaker.record(nil)
+	breaker.record(failure)
+	if !breaker.allow() {
+		t.Fatal("Expected a successful query to reset the breaker's failures")
+	}
+
+	breaker.record(failure)
+	if breaker.allow() {
+		t.Fatal("Expected the breaker to reject queries once its threshold is reached")
+	}
+
+	now = now.Add(59 * time.Second)
+	if breaker.allow() {
+		t.Fatal("Expected the breaker to reject queries during its cooldown")
+	}
+
+	now = now.Add(time.Second)
+	if !breaker.allow() {
+		t.Fatal("Expected the breaker to allow queries after its cooldown")
+	}
+
+	var disabled *queryBreaker
+	disabled.record(failure)
+	if !disabled.allow() {
+		t.Fatal("Expected a nil breaker to allow all queries")
+	}
+}
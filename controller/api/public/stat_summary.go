@@ -2,12 +2,17 @@ package public
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 
 	proto "github.com/golang/protobuf/proto"
 	"github.com/linkerd/linkerd2/controller/api/util"
+	ts "github.com/linkerd/linkerd2/controller/gen/apis/split/v1alpha1"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/prometheus/common/model"
+	log "github.com/sirupsen/logrus"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,13 +20,14 @@ import (
 )
 
 type resourceResult struct {
-	res *pb.StatTable
-	err error
+	res           *pb.StatTable
+	continueToken string
+	err           error
 }
 
 type k8sStat struct {
-	object   metav1.Object
-	podStats *podStats
+	object     metav1.Object
+	runtimeObj runtime.Object
 }
 
 type rKey struct {
@@ -31,15 +37,34 @@ type rKey struct {
 }
 
 const (
-	reqQuery             = "sum(increase(response_total%s[%s])) by (%s, classification, tls)"
+	reqQuery             = "sum(increase(response_total%s[%s])) by (%s, classification, tls, no_tls_reason)"
+	grpcStatusQuery      = "sum(increase(response_total%s[%s])) by (%s, grpc_status)"
 	latencyQuantileQuery = "histogram_quantile(%s, sum(irate(response_latency_ms_bucket%s[%s])) by (le, %s))"
+
+	// the requests sent to the apex service of a traffic split are split
+	// between its backends, which are told apart by their dst_service label
+	apexAuthorityLabel = `authority=~"(%s)(:\\d+)?"`
+	dstServiceLabel    = model.LabelName("dst_service")
+
+	// the stats of the rows can be broken down by the authority of the
+	// requests, with the queries above, or by the route of their service
+	// profile, with the queries of the route metrics
+	groupByAuthority               = "authority"
+	groupByRoute                   = "route"
+	authorityLabel                 = model.LabelName("authority")
+	routeLabel                     = model.LabelName("rt_route")
+	routeGroupReqQuery             = "sum(increase(route_response_total%s[%s])) by (%s, classification)"
+	routeGroupGrpcStatusQuery      = "sum(increase(route_response_total%s[%s])) by (%s, grpc_status)"
+	routeGroupLatencyQuantileQuery = "histogram_quantile(%s, sum(irate(route_response_latency_ms_bucket%s[%s])) by (le, %s))"
 )
 
 type podStats struct {
-	inMesh uint64
-	total  uint64
-	failed uint64
-	errors map[string]*pb.PodErrors
+	inMesh     uint64
+	total      uint64
+	failed     uint64
+	proxyReady uint64
+	restarts   uint64
+	errors     map[string]*pb.PodErrors
 }
 
 func (s *grpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryRequest) (*pb.StatSummaryResponse, error) {
@@ -56,6 +81,9 @@ func (s *grpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryRequest
 
 	switch req.Outbound.(type) {
 	case *pb.StatSummaryRequest_ToResource:
+		if req.Selector.Resource.Type == k8s.TrafficSplit {
+			return statSummaryError(req, "traffic splits are not supported as a target on 'to' queries"), nil
+		}
 		if req.Outbound.(*pb.StatSummaryRequest_ToResource).ToResource.Type == k8s.All {
 			return statSummaryError(req, "resource type 'all' is not supported as a filter"), nil
 		}
@@ -65,7 +93,32 @@ func (s *grpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryRequest
 		}
 	}
 
+	for name := range req.GetMetricLabels() {
+		if !model.LabelName(name).IsValid() {
+			return statSummaryError(req, fmt.Sprintf("invalid metric label name: %s", name)), nil
+		}
+	}
+
+	if err := validateGroupBy(req); err != nil {
+		return statSummaryError(req, err.Error()), nil
+	}
+
+	if req.GetLimit() > 0 || req.GetContinueToken() != "" {
+		if req.Selector.Resource.Type == k8s.All {
+			return statSummaryError(req, "pagination is not supported for resource type 'all'"), nil
+		}
+		if req.GetContinueToken() != "" {
+			if _, _, err := decodeContinueToken(req.GetContinueToken()); err != nil {
+				return statSummaryError(req, err.Error()), nil
+			}
+		}
+	}
+
+	ctx, cancel, budget := s.withQueryBudget(ctx)
+	defer cancel()
+
 	statTables := make([]*pb.StatTable, 0)
+	continueToken := ""
 
 	var resourcesToQuery []string
 	if req.Selector.Resource.Type == k8s.All {
@@ -84,6 +137,8 @@ func (s *grpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryRequest
 		go func() {
 			if isNonK8sResourceQuery(statReq.GetSelector().GetResource().GetType()) {
 				resultChan <- s.nonK8sResourceQuery(ctx, statReq)
+			} else if statReq.GetSelector().GetResource().GetType() == k8s.TrafficSplit {
+				resultChan <- s.trafficSplitResourceQuery(ctx, statReq)
 			} else {
 				resultChan <- s.k8sResourceQuery(ctx, statReq)
 			}
@@ -93,15 +148,23 @@ func (s *grpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryRequest
 	for i := 0; i < len(resourcesToQuery); i++ {
 		result := <-resultChan
 		if result.err != nil {
-			return nil, util.GRPCError(result.err)
+			if err := dropIfBudgetExceeded(ctx, result.err); err != nil {
+				return nil, util.GRPCError(err)
+			}
+			continue
 		}
 		statTables = append(statTables, result.res)
+		if result.continueToken != "" {
+			continueToken = result.continueToken
+		}
 	}
 
 	rsp := pb.StatSummaryResponse{
 		Response: &pb.StatSummaryResponse_Ok_{ // https://github.com/golang/protobuf/issues/205
 			Ok: &pb.StatSummaryResponse_Ok{
-				StatTables: statTables,
+				StatTables:    statTables,
+				Partial:       budget.isPartial(),
+				ContinueToken: continueToken,
 			},
 		},
 	}
@@ -109,6 +172,30 @@ func (s *grpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryRequest
 	return &rsp, nil
 }
 
+// validateGroupBy checks that the stats of the requested resources can be
+// broken down by the group_by of req. The route metrics only have the labels
+// of the pods that serve the routes, so there's no route breakdown of the
+// 'to' and 'from' queries.
+func validateGroupBy(req *pb.StatSummaryRequest) error {
+	switch req.GetGroupBy() {
+	case "":
+		return nil
+	case groupByAuthority:
+	case groupByRoute:
+		if req.GetOutbound() != nil && req.GetNone() == nil {
+			return fmt.Errorf("route breakdowns are not supported on 'to' or 'from' queries")
+		}
+	default:
+		return fmt.Errorf("invalid group_by: %s, must be one of: %s, %s", req.GetGroupBy(), groupByAuthority, groupByRoute)
+	}
+
+	switch resourceType := req.GetSelector().GetResource().GetType(); resourceType {
+	case k8s.Authority, k8s.TrafficSplit:
+		return fmt.Errorf("breakdowns by %s are not supported for resource type '%s'", req.GetGroupBy(), resourceType)
+	}
+	return nil
+}
+
 func isInvalidServiceRequest(selector *pb.ResourceSelection, fromResource *pb.Resource) bool {
 	if fromResource != nil {
 		return fromResource.Type == k8s.Service
@@ -149,14 +236,9 @@ func (s *grpcServer) getKubernetesObjectStats(req *pb.StatSummaryRequest) (map[r
 			Type:      requestedResource.GetType(),
 		}
 
-		podStats, err := s.getPodStats(object)
-		if err != nil {
-			return nil, err
-		}
-
 		objectMap[key] = k8sStat{
-			object:   metaObj,
-			podStats: podStats,
+			object:     metaObj,
+			runtimeObj: object,
 		}
 	}
 	return objectMap, nil
@@ -169,20 +251,39 @@ func (s *grpcServer) k8sResourceQuery(ctx context.Context, req *pb.StatSummaryRe
 	}
 
 	var requestMetrics map[rKey]*pb.BasicStats
+	var groupMetrics map[rKey][]*pb.GroupStats
 	if !req.SkipStats {
 		requestMetrics, err = s.getStatMetrics(ctx, req, req.TimeWindow)
 		if err != nil {
 			return resourceResult{res: nil, err: err}
 		}
+		if req.GetGroupBy() != "" {
+			groupMetrics, err = s.getGroupStatMetrics(ctx, req, req.TimeWindow)
+			if err != nil {
+				return resourceResult{res: nil, err: err}
+			}
+		}
 	}
 
 	rows := make([]*pb.StatTable_PodGroup_Row, 0)
-	keys := getResultKeys(req, k8sObjects, requestMetrics)
+	keys := []rKey{}
+	for _, key := range getResultKeys(req, k8sObjects, requestMetrics) {
+		if _, ok := k8sObjects[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	keys, continueToken, err := pageKeys(req, keys)
+	if err != nil {
+		return resourceResult{res: nil, err: err}
+	}
 
+	// the pods are only looked up for the rows of the page, since there can be
+	// thousands of them in the other rows
 	for _, key := range keys {
-		objInfo, ok := k8sObjects[key]
-		if !ok {
-			continue
+		objInfo := k8sObjects[key]
+		podStat, err := s.getPodStats(objInfo.runtimeObj)
+		if err != nil {
+			return resourceResult{res: nil, err: err}
 		}
 		k8sResource := objInfo.object
 		row := pb.StatTable_PodGroup_Row{
@@ -193,14 +294,21 @@ func (s *grpcServer) k8sResourceQuery(ctx context.Context, req *pb.StatSummaryRe
 			},
 			TimeWindow: req.TimeWindow,
 			Stats:      requestMetrics[key],
+			GroupStats: groupMetrics[key],
 		}
 
-		podStat := objInfo.podStats
 		row.MeshedPodCount = podStat.inMesh
 		row.RunningPodCount = podStat.total
 		row.FailedPodCount = podStat.failed
+		row.ProxyReadyPodCount = podStat.proxyReady
+		row.RestartCount = podStat.restarts
 		row.ErrorsByPod = podStat.errors
 
+		row.SloSuccessRate, err = k8s.GetSLOSuccessRate(k8sResource.GetAnnotations())
+		if err != nil {
+			log.Warnf("ignoring the SLO of %s %s/%s: %s", row.Resource.Type, row.Resource.Namespace, row.Resource.Name, err)
+		}
+
 		rows = append(rows, &row)
 	}
 
@@ -212,7 +320,7 @@ func (s *grpcServer) k8sResourceQuery(ctx context.Context, req *pb.StatSummaryRe
 		},
 	}
 
-	return resourceResult{res: &rsp, err: nil}
+	return resourceResult{res: &rsp, continueToken: continueToken, err: nil}
 }
 
 func (s *grpcServer) nonK8sResourceQuery(ctx context.Context, req *pb.StatSummaryRequest) resourceResult {
@@ -225,8 +333,17 @@ func (s *grpcServer) nonK8sResourceQuery(ctx context.Context, req *pb.StatSummar
 		}
 	}
 	rows := make([]*pb.StatTable_PodGroup_Row, 0)
+	keys := make([]rKey, 0, len(requestMetrics))
+	for key := range requestMetrics {
+		keys = append(keys, key)
+	}
+	keys, continueToken, err := pageKeys(req, keys)
+	if err != nil {
+		return resourceResult{res: nil, err: err}
+	}
 
-	for rkey, metrics := range requestMetrics {
+	for _, rkey := range keys {
+		metrics := requestMetrics[rkey]
 		rkey.Type = req.GetSelector().GetResource().GetType()
 
 		row := pb.StatTable_PodGroup_Row{
@@ -248,7 +365,120 @@ func (s *grpcServer) nonK8sResourceQuery(ctx context.Context, req *pb.StatSummar
 			},
 		},
 	}
-	return resourceResult{res: &rsp, err: nil}
+	return resourceResult{res: &rsp, continueToken: continueToken, err: nil}
+}
+
+// trafficSplitResourceQuery returns a row for each backend of the requested
+// traffic splits, with the stats of the requests sent to the apex service of
+// the split that were routed to that backend.
+func (s *grpcServer) trafficSplitResourceQuery(ctx context.Context, req *pb.StatSummaryRequest) resourceResult {
+	requestedResource := req.GetSelector().GetResource()
+	splits, err := s.k8sAPI.GetTrafficSplits(requestedResource.Namespace, requestedResource.Name)
+	if err != nil {
+		return resourceResult{res: nil, err: err}
+	}
+
+	splitsByKey := make(map[rKey]*ts.TrafficSplit)
+	keys := make([]rKey, 0, len(splits))
+	for _, split := range splits {
+		key := rKey{Namespace: split.Namespace, Type: k8s.TrafficSplit, Name: split.Name}
+		splitsByKey[key] = split
+		keys = append(keys, key)
+	}
+	keys, continueToken, err := pageKeys(req, keys)
+	if err != nil {
+		return resourceResult{res: nil, err: err}
+	}
+
+	rows := make([]*pb.StatTable_PodGroup_Row, 0)
+	for _, key := range keys {
+		split := splitsByKey[key]
+
+		var requestMetrics map[rKey]*pb.BasicStats
+		if !req.SkipStats {
+			requestMetrics, err = s.getTrafficSplitMetrics(ctx, req, split)
+			if err != nil {
+				return resourceResult{res: nil, err: err}
+			}
+		}
+
+		for _, backend := range split.Spec.Backends {
+			weight := ""
+			if backend.Weight != nil {
+				weight = backend.Weight.String()
+			}
+			rows = append(rows, &pb.StatTable_PodGroup_Row{
+				Resource: &pb.Resource{
+					Name:      split.Name,
+					Namespace: split.Namespace,
+					Type:      k8s.TrafficSplit,
+				},
+				TimeWindow: req.TimeWindow,
+				Stats:      requestMetrics[rKey{Type: k8s.TrafficSplit, Name: backend.Service}],
+				TsStats: &pb.TrafficSplitStats{
+					Apex:   split.Spec.Service,
+					Leaf:   backend.Service,
+					Weight: weight,
+				},
+			})
+		}
+	}
+
+	rsp := pb.StatTable{
+		Table: &pb.StatTable_PodGroup_{
+			PodGroup: &pb.StatTable_PodGroup{
+				Rows: rows,
+			},
+		},
+	}
+	return resourceResult{res: &rsp, continueToken: continueToken, err: nil}
+}
+
+// getTrafficSplitMetrics returns the stats of the outbound requests sent to the
+// apex service of the split, keyed by the backend service they were routed
+// to. With --from, only the requests of the --from resource are counted.
+func (s *grpcServer) getTrafficSplitMetrics(ctx context.Context, req *pb.StatSummaryRequest, split *ts.TrafficSplit) (map[rKey]*pb.BasicStats, error) {
+	labels := promDirectionLabels("outbound")
+	labels[dstNamespaceLabel] = model.LabelValue(split.Namespace)
+	if from := req.GetFromResource(); from != nil {
+		labels = labels.Merge(promQueryLabels(from))
+	}
+
+	pairs := []string{fmt.Sprintf(apexAuthorityLabel, fmt.Sprintf("%s.%s.svc.cluster.local", split.Spec.Service, split.Namespace))}
+	pairs = append(pairs, promLabelPairs(labels)...)
+	sort.Strings(pairs)
+
+	groupBy := model.LabelNames{dstServiceLabel}
+	queries := map[promType]string{
+		promRequests: reqQuery,
+	}
+	if req.GetGrpcStats() {
+		queries[promGrpcStatuses] = grpcStatusQuery
+	}
+	results, err := s.getPrometheusMetrics(ctx, queries, latencyQuantileQuery, fmt.Sprintf("{%s}", strings.Join(pairs, ", ")), req.TimeWindow, groupBy.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return processPrometheusMetrics(req, results, groupBy), nil
+}
+
+// pageKeys sorts keys by namespace and name, and returns the page of them
+// requested by req, along with the token of the next page.
+func pageKeys(req *pb.StatSummaryRequest, keys []rKey) ([]rKey, string, error) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		return keys[i].Name < keys[j].Name
+	})
+	start, end, continueToken, err := paginate(len(keys), func(i int) (string, string) {
+		return keys[i].Namespace, keys[i].Name
+	}, req.GetLimit(), req.GetContinueToken())
+	if err != nil {
+		return nil, "", err
+	}
+	return keys[start:end], continueToken, nil
 }
 
 func isNonK8sResourceQuery(resourceType string) bool {
@@ -304,12 +534,23 @@ func buildRequestLabels(req *pb.StatSummaryRequest) (labels model.LabelSet, labe
 		labels = labels.Merge(promDirectionLabels("inbound"))
 	}
 
+	// the metric labels apply to the selected resource, which is the
+	// destination of the outbound metrics of the --from resource
+	_, dst := req.Outbound.(*pb.StatSummaryRequest_FromResource)
+	labels = labels.Merge(promMetricLabels(req.MetricLabels, dst))
+
 	return
 }
 
 func (s *grpcServer) getStatMetrics(ctx context.Context, req *pb.StatSummaryRequest, timeWindow string) (map[rKey]*pb.BasicStats, error) {
 	reqLabels, groupBy := buildRequestLabels(req)
-	results, err := s.getPrometheusMetrics(ctx, map[promType]string{promRequests: reqQuery}, latencyQuantileQuery, reqLabels.String(), timeWindow, groupBy.String())
+	queries := map[promType]string{
+		promRequests: reqQuery,
+	}
+	if req.GetGrpcStats() {
+		queries[promGrpcStatuses] = grpcStatusQuery
+	}
+	results, err := s.getPrometheusMetrics(ctx, queries, latencyQuantileQuery, promLabelMatchers(reqLabels), timeWindow, groupBy.String())
 
 	if err != nil {
 		return nil, err
@@ -318,9 +559,74 @@ func (s *grpcServer) getStatMetrics(ctx context.Context, req *pb.StatSummaryRequ
 	return processPrometheusMetrics(req, results, groupBy), nil
 }
 
+// getGroupStatMetrics returns the stats of the requests of each resource,
+// broken down by the authority or the route of the requests, as requested by
+// the group_by of req. The requests that match none of the routes of the
+// service profiles are grouped under DefaultRouteName.
+func (s *grpcServer) getGroupStatMetrics(ctx context.Context, req *pb.StatSummaryRequest, timeWindow string) (map[rKey][]*pb.GroupStats, error) {
+	reqLabels, groupBy := buildRequestLabels(req)
+
+	groupLabel := authorityLabel
+	queries := map[promType]string{
+		promRequests: reqQuery,
+	}
+	latencyQuery := latencyQuantileQuery
+	if req.GetGrpcStats() {
+		queries[promGrpcStatuses] = grpcStatusQuery
+	}
+	if req.GetGroupBy() == groupByRoute {
+		groupLabel = routeLabel
+		queries[promRequests] = routeGroupReqQuery
+		latencyQuery = routeGroupLatencyQuantileQuery
+		if req.GetGrpcStats() {
+			queries[promGrpcStatuses] = routeGroupGrpcStatusQuery
+		}
+	}
+
+	queryGroupBy := append(model.LabelNames{groupLabel}, groupBy...)
+	results, err := s.getPrometheusMetrics(ctx, queries, latencyQuery, promLabelMatchers(reqLabels), timeWindow, queryGroupBy.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// the samples of each group are processed as the results of a query of
+	// their own, so that their stats are keyed by resource
+	resultsByGroup := make(map[string][]promResult)
+	for _, result := range results {
+		vecs := make(map[string]model.Vector)
+		for _, sample := range result.vec {
+			group := string(sample.Metric[groupLabel])
+			if group == "" && groupLabel == routeLabel {
+				group = DefaultRouteName
+			}
+			vecs[group] = append(vecs[group], sample)
+		}
+		for group, vec := range vecs {
+			resultsByGroup[group] = append(resultsByGroup[group], promResult{prom: result.prom, vec: vec})
+		}
+	}
+
+	groups := make([]string, 0, len(resultsByGroup))
+	for group := range resultsByGroup {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	groupStats := make(map[rKey][]*pb.GroupStats)
+	for _, group := range groups {
+		for key, stats := range processPrometheusMetrics(req, resultsByGroup[group], groupBy) {
+			groupStats[key] = append(groupStats[key], &pb.GroupStats{Group: group, Stats: stats})
+		}
+	}
+	return groupStats, nil
+}
+
 func processPrometheusMetrics(req *pb.StatSummaryRequest, results []promResult, groupBy model.LabelNames) map[rKey]*pb.BasicStats {
 	basicStats := make(map[rKey]*pb.BasicStats)
 
+	// only the inbound metrics say whether the client is meshed
+	inbound := req.GetOutbound() == nil || req.GetNone() != nil
+
 	for _, result := range results {
 		for _, sample := range result.vec {
 			resource := metricToKey(req, sample.Metric, groupBy)
@@ -343,6 +649,11 @@ func processPrometheusMetrics(req *pb.StatSummaryRequest, results []promResult,
 				case "true":
 					basicStats[resource].TlsRequestCount += value
 				}
+				if inbound && sample.Metric[noTLSReasonLabel] == noIdentityReason {
+					basicStats[resource].UnmeshedRequestCount += value
+				}
+			case promGrpcStatuses:
+				addGrpcStatusCount(basicStats[resource], sample)
 			case promLatencyP50:
 				basicStats[resource].LatencyMsP50 = value
 			case promLatencyP95:
@@ -388,6 +699,15 @@ func (s *grpcServer) getPodStats(obj runtime.Object) (*podStats, error) {
 			if k8s.IsMeshed(pod, s.controllerNamespace) {
 				meshCount.inMesh++
 			}
+			for _, st := range pod.Status.ContainerStatuses {
+				if st.Name == k8s.ProxyContainerName && st.Ready {
+					meshCount.proxyReady++
+				}
+			}
+		}
+
+		for _, st := range pod.Status.ContainerStatuses {
+			meshCount.restarts += uint64(st.RestartCount)
 		}
 
 		errors := checkContainerErrors(pod.Status.ContainerStatuses, k8s.ProxyContainerName)
@@ -232,7 +232,7 @@ status:
 						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
 						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
 						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
-						`sum(increase(response_total{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (namespace, pod, classification, tls)`,
+						`sum(increase(response_total{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (namespace, pod, classification, tls, no_tls_reason)`,
 					},
 				},
 				req: pb.StatSummaryRequest{
@@ -256,6 +256,295 @@ status:
 		testStatSummary(t, expectations)
 	})
 
+	t.Run("Aggregates the pods of the jobs of a cron job", func(t *testing.T) {
+		expectations := []statSumExpected{
+			statSumExpected{
+				expectedStatRPC: expectedStatRPC{
+					err: nil,
+					k8sConfigs: []string{`
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: slow-cooker
+  namespace: emojivoto
+`, `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: slow-cooker-1541462400
+  namespace: emojivoto
+  ownerReferences:
+  - apiVersion: batch/v1beta1
+    kind: CronJob
+    name: slow-cooker
+    controller: true
+spec:
+  selector:
+    matchLabels:
+      job-name: slow-cooker-1541462400
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: slow-cooker-1541462400-bxtnq
+  namespace: emojivoto
+  labels:
+    job-name: slow-cooker-1541462400
+    linkerd.io/control-plane-ns: linkerd
+  ownerReferences:
+  - apiVersion: batch/v1
+    kind: Job
+    name: slow-cooker-1541462400
+    controller: true
+status:
+  phase: Running
+`,
+					},
+					mockPromResponse: prometheusMetric("slow-cooker", "cronjob", "emojivoto", "success", false),
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{cronjob="slow-cooker", direction="inbound", namespace="emojivoto"}[1m])) by (le, namespace, cronjob))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{cronjob="slow-cooker", direction="inbound", namespace="emojivoto"}[1m])) by (le, namespace, cronjob))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{cronjob="slow-cooker", direction="inbound", namespace="emojivoto"}[1m])) by (le, namespace, cronjob))`,
+						`sum(increase(response_total{cronjob="slow-cooker", direction="inbound", namespace="emojivoto"}[1m])) by (namespace, cronjob, classification, tls, no_tls_reason)`,
+					},
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Name:      "slow-cooker",
+							Namespace: "emojivoto",
+							Type:      pkgK8s.CronJob,
+						},
+					},
+					TimeWindow: "1m",
+				},
+				expectedResponse: GenStatSummaryResponse("slow-cooker", pkgK8s.CronJob, []string{"emojivoto"}, &PodCounts{
+					MeshedPods:  1,
+					RunningPods: 1,
+					FailedPods:  0,
+				}, true),
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
+	t.Run("Queries prometheus for the grpc statuses if requested", func(t *testing.T) {
+		expectations := []statSumExpected{
+			statSumExpected{
+				expectedStatRPC: expectedStatRPC{
+					err: nil,
+					k8sConfigs: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-1
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running
+`,
+					},
+					mockPromResponse: prometheusMetric("emojivoto-1", "pod", "emojivoto", "success", false),
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`sum(increase(response_total{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (namespace, pod, classification, tls, no_tls_reason)`,
+						`sum(increase(response_total{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (namespace, pod, grpc_status)`,
+					},
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Name:      "emojivoto-1",
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Pod,
+						},
+					},
+					TimeWindow: "1m",
+					GrpcStats:  true,
+				},
+				expectedResponse: GenStatSummaryResponse("emojivoto-1", pkgK8s.Pod, []string{"emojivoto"}, &PodCounts{
+					MeshedPods:  1,
+					RunningPods: 1,
+					FailedPods:  0,
+				}, true),
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
+	t.Run("Queries prometheus for the route breakdown if requested", func(t *testing.T) {
+		expectedResponse := GenStatSummaryResponse("emojivoto-1", pkgK8s.Pod, []string{"emojivoto"}, &PodCounts{
+			MeshedPods:  1,
+			RunningPods: 1,
+			FailedPods:  0,
+		}, true)
+		row := expectedResponse.GetOk().StatTables[0].GetPodGroup().Rows[0]
+		// the samples have no rt_route label, as the requests that match no route
+		row.GroupStats = []*pb.GroupStats{
+			&pb.GroupStats{Group: DefaultRouteName, Stats: row.Stats},
+		}
+
+		expectations := []statSumExpected{
+			statSumExpected{
+				expectedStatRPC: expectedStatRPC{
+					err: nil,
+					k8sConfigs: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-1
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running
+`,
+					},
+					mockPromResponse: prometheusMetric("emojivoto-1", "pod", "emojivoto", "success", false),
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`sum(increase(response_total{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (namespace, pod, classification, tls, no_tls_reason)`,
+						`histogram_quantile(0.5, sum(irate(route_response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, rt_route, namespace, pod))`,
+						`histogram_quantile(0.95, sum(irate(route_response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, rt_route, namespace, pod))`,
+						`histogram_quantile(0.99, sum(irate(route_response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, rt_route, namespace, pod))`,
+						`sum(increase(route_response_total{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (rt_route, namespace, pod, classification)`,
+					},
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Name:      "emojivoto-1",
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Pod,
+						},
+					},
+					TimeWindow: "1m",
+					GroupBy:    "route",
+				},
+				expectedResponse: expectedResponse,
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
+	t.Run("Reports the proxy status and container restarts of pods", func(t *testing.T) {
+		expectations := []statSumExpected{
+			statSumExpected{
+				expectedStatRPC: expectedStatRPC{
+					err: nil,
+					k8sConfigs: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-1
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running
+  containerStatuses:
+  - name: emoji-svc
+    ready: true
+    restartCount: 4
+  - name: linkerd-proxy
+    ready: true
+    restartCount: 1
+`,
+					},
+					mockPromResponse: prometheusMetric("emojivoto-1", "pod", "emojivoto", "success", false),
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`sum(increase(response_total{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (namespace, pod, classification, tls, no_tls_reason)`,
+					},
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Name:      "emojivoto-1",
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Pod,
+						},
+					},
+					TimeWindow: "1m",
+				},
+				expectedResponse: GenStatSummaryResponse("emojivoto-1", pkgK8s.Pod, []string{"emojivoto"}, &PodCounts{
+					MeshedPods:     1,
+					RunningPods:    1,
+					FailedPods:     0,
+					ProxyReadyPods: 1,
+					Restarts:       5,
+				}, true),
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
+	t.Run("Reports the SLO success rate of annotated resources", func(t *testing.T) {
+		expectedResponse := GenStatSummaryResponse("emojivoto-1", pkgK8s.Pod, []string{"emojivoto"}, &PodCounts{
+			MeshedPods:  1,
+			RunningPods: 1,
+			FailedPods:  0,
+		}, true)
+		expectedResponse.GetOk().StatTables[0].GetPodGroup().Rows[0].SloSuccessRate = 0.995
+
+		expectations := []statSumExpected{
+			statSumExpected{
+				expectedStatRPC: expectedStatRPC{
+					err: nil,
+					k8sConfigs: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-1
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: linkerd
+  annotations:
+    linkerd.io/slo-success-rate: "99.5"
+status:
+  phase: Running
+`,
+					},
+					mockPromResponse: prometheusMetric("emojivoto-1", "pod", "emojivoto", "success", false),
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+						`sum(increase(response_total{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (namespace, pod, classification, tls, no_tls_reason)`,
+					},
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Name:      "emojivoto-1",
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Pod,
+						},
+					},
+					TimeWindow: "1m",
+				},
+				expectedResponse: expectedResponse,
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
 	t.Run("Queries prometheus for outbound metrics if from resource is specified, ignores resource name", func(t *testing.T) {
 		expectations := []statSumExpected{
 			statSumExpected{
@@ -279,7 +568,7 @@ status:
 						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-1", namespace="emojivoto", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
 						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-1", namespace="emojivoto", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
 						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-1", namespace="emojivoto", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
-						`sum(increase(response_total{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-1", namespace="emojivoto", pod="emojivoto-2"}[1m])) by (dst_namespace, dst_pod, classification, tls)`,
+						`sum(increase(response_total{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-1", namespace="emojivoto", pod="emojivoto-2"}[1m])) by (dst_namespace, dst_pod, classification, tls, no_tls_reason)`,
 					},
 				},
 				req: pb.StatSummaryRequest{
@@ -331,7 +620,7 @@ status:
 						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-2", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
 						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-2", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
 						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-2", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
-						`sum(increase(response_total{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-2", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (namespace, pod, classification, tls)`,
+						`sum(increase(response_total{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-2", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (namespace, pod, classification, tls, no_tls_reason)`,
 					},
 				},
 				req: pb.StatSummaryRequest{
@@ -387,7 +676,7 @@ status:
 						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="totallydifferent", dst_pod="emojivoto-2", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
 						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="totallydifferent", dst_pod="emojivoto-2", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
 						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="totallydifferent", dst_pod="emojivoto-2", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
-						`sum(increase(response_total{direction="outbound", dst_namespace="totallydifferent", dst_pod="emojivoto-2", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (namespace, pod, classification, tls)`,
+						`sum(increase(response_total{direction="outbound", dst_namespace="totallydifferent", dst_pod="emojivoto-2", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (namespace, pod, classification, tls, no_tls_reason)`,
 					},
 				},
 				req: pb.StatSummaryRequest{
@@ -454,7 +743,7 @@ status:
 						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="outbound", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
 						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="outbound", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
 						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="outbound", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
-						`sum(increase(response_total{direction="outbound", pod="emojivoto-2"}[1m])) by (dst_namespace, dst_pod, classification, tls)`,
+						`sum(increase(response_total{direction="outbound", pod="emojivoto-2"}[1m])) by (dst_namespace, dst_pod, classification, tls, no_tls_reason)`,
 					},
 				},
 				req: pb.StatSummaryRequest{
@@ -485,6 +774,62 @@ status:
 		testStatSummary(t, expectations)
 	})
 
+	t.Run("Queries prometheus with regex matchers if the --from resource is a name pattern", func(t *testing.T) {
+		expectations := []statSumExpected{
+			statSumExpected{
+				expectedStatRPC: expectedStatRPC{
+					err: nil,
+					k8sConfigs: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-1
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running
+`,
+					},
+					mockPromResponse: model.Vector{
+						genPromSample("emojivoto-1", "pod", "emojivoto", "success", true),
+					},
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="outbound", pod=~"vote-bot-.*|web"}[1m])) by (le, dst_namespace, dst_pod))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="outbound", pod=~"vote-bot-.*|web"}[1m])) by (le, dst_namespace, dst_pod))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="outbound", pod=~"vote-bot-.*|web"}[1m])) by (le, dst_namespace, dst_pod))`,
+						`sum(increase(response_total{direction="outbound", pod=~"vote-bot-.*|web"}[1m])) by (dst_namespace, dst_pod, classification, tls, no_tls_reason)`,
+					},
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Name:      "",
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Pod,
+						},
+					},
+					TimeWindow: "1m",
+					Outbound: &pb.StatSummaryRequest_FromResource{
+						FromResource: &pb.Resource{
+							Name:      "vote-bot-*,web",
+							Namespace: "",
+							Type:      pkgK8s.Pod,
+						},
+					},
+				},
+				expectedResponse: GenStatSummaryResponse("emojivoto-1", pkgK8s.Pod, []string{"emojivoto"}, &PodCounts{
+					MeshedPods:  1,
+					RunningPods: 1,
+					FailedPods:  0,
+				}, true),
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
 	t.Run("Queries prometheus for outbound metrics if --from resource is specified and --from-namespace is different from the resource namespace", func(t *testing.T) {
 		expectations := []statSumExpected{
 			statSumExpected{
@@ -521,7 +866,7 @@ status:
 						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-1", namespace="totallydifferent", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
 						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-1", namespace="totallydifferent", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
 						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-1", namespace="totallydifferent", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
-						`sum(increase(response_total{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-1", namespace="totallydifferent", pod="emojivoto-2"}[1m])) by (dst_namespace, dst_pod, classification, tls)`,
+						`sum(increase(response_total{direction="outbound", dst_namespace="emojivoto", dst_pod="emojivoto-1", namespace="totallydifferent", pod="emojivoto-2"}[1m])) by (dst_namespace, dst_pod, classification, tls, no_tls_reason)`,
 					},
 				},
 				req: pb.StatSummaryRequest{
@@ -552,6 +897,92 @@ status:
 		testStatSummary(t, expectations)
 	})
 
+	t.Run("Queries prometheus for the metric labels if specified", func(t *testing.T) {
+		k8sConfigs := []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-1
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running
+`,
+		}
+
+		expectations := []statSumExpected{
+			statSumExpected{
+				expectedStatRPC: expectedStatRPC{
+					err:              nil,
+					k8sConfigs:       k8sConfigs,
+					mockPromResponse: prometheusMetric("emojivoto-1", "pod", "emojivoto", "success", false),
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1", team="emoji", version="v2"}[1m])) by (le, namespace, pod))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1", team="emoji", version="v2"}[1m])) by (le, namespace, pod))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1", team="emoji", version="v2"}[1m])) by (le, namespace, pod))`,
+						`sum(increase(response_total{direction="inbound", namespace="emojivoto", pod="emojivoto-1", team="emoji", version="v2"}[1m])) by (namespace, pod, classification, tls, no_tls_reason)`,
+					},
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Name:      "emojivoto-1",
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Pod,
+						},
+					},
+					TimeWindow:   "1m",
+					MetricLabels: map[string]string{"version": "v2", "team": "emoji"},
+				},
+				expectedResponse: GenStatSummaryResponse("emojivoto-1", pkgK8s.Pod, []string{"emojivoto"}, &PodCounts{
+					MeshedPods:  1,
+					RunningPods: 1,
+					FailedPods:  0,
+				}, true),
+			},
+			statSumExpected{
+				expectedStatRPC: expectedStatRPC{
+					err:        nil,
+					k8sConfigs: k8sConfigs,
+					mockPromResponse: model.Vector{
+						genPromSample("emojivoto-1", "pod", "emojivoto", "success", true),
+					},
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="outbound", dst_version="v2", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="outbound", dst_version="v2", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="outbound", dst_version="v2", pod="emojivoto-2"}[1m])) by (le, dst_namespace, dst_pod))`,
+						`sum(increase(response_total{direction="outbound", dst_version="v2", pod="emojivoto-2"}[1m])) by (dst_namespace, dst_pod, classification, tls, no_tls_reason)`,
+					},
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Pod,
+						},
+					},
+					TimeWindow: "1m",
+					Outbound: &pb.StatSummaryRequest_FromResource{
+						FromResource: &pb.Resource{
+							Name: "emojivoto-2",
+							Type: pkgK8s.Pod,
+						},
+					},
+					MetricLabels: map[string]string{"version": "v2"},
+				},
+				expectedResponse: GenStatSummaryResponse("emojivoto-1", pkgK8s.Pod, []string{"emojivoto"}, &PodCounts{
+					MeshedPods:  1,
+					RunningPods: 1,
+					FailedPods:  0,
+				}, true),
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
 	t.Run("Successfully queries for resource type 'all'", func(t *testing.T) {
 		expectations := []statSumExpected{
 			statSumExpected{
@@ -776,6 +1207,7 @@ status:
 				k8sAPI,
 				"linkerd",
 				[]string{},
+				QueryLimits{},
 			)
 
 			_, err := fakeGrpcServer.StatSummary(context.TODO(), &exp.req)
@@ -800,6 +1232,7 @@ status:
 			k8sAPI,
 			"linkerd",
 			[]string{},
+			QueryLimits{},
 		)
 
 		invalidRequests := []statSumExpected{
@@ -843,6 +1276,65 @@ status:
 					},
 				},
 			},
+			statSumExpected{
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Type: pkgK8s.Pod,
+						},
+					},
+					MetricLabels: map[string]string{"app.kubernetes.io/version": "v2"},
+				},
+			},
+			statSumExpected{
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Type: pkgK8s.TrafficSplit,
+						},
+					},
+					Outbound: &pb.StatSummaryRequest_ToResource{
+						ToResource: &pb.Resource{
+							Type: pkgK8s.Deployment,
+						},
+					},
+				},
+			},
+			statSumExpected{
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Type: pkgK8s.Deployment,
+						},
+					},
+					GroupBy: "path",
+				},
+			},
+			statSumExpected{
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Type: pkgK8s.Deployment,
+						},
+					},
+					Outbound: &pb.StatSummaryRequest_ToResource{
+						ToResource: &pb.Resource{
+							Type: pkgK8s.Deployment,
+						},
+					},
+					GroupBy: "route",
+				},
+			},
+			statSumExpected{
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Type: pkgK8s.TrafficSplit,
+						},
+					},
+					GroupBy: "authority",
+				},
+			},
 		}
 
 		for _, invalid := range invalidRequests {
@@ -927,7 +1419,7 @@ status:
 							`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto"}[])) by (le, namespace, pod))`,
 							`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto"}[])) by (le, namespace, pod))`,
 							`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto"}[])) by (le, namespace, pod))`,
-							`sum(increase(response_total{direction="inbound", namespace="emojivoto"}[])) by (namespace, pod, classification, tls)`,
+							`sum(increase(response_total{direction="inbound", namespace="emojivoto"}[])) by (namespace, pod, classification, tls, no_tls_reason)`,
 						},
 					},
 					req: pb.StatSummaryRequest{
@@ -1057,7 +1549,7 @@ status:
 						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="linkerd"}[1m])) by (le, namespace, authority))`,
 						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="linkerd"}[1m])) by (le, namespace, authority))`,
 						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="linkerd"}[1m])) by (le, namespace, authority))`,
-						`sum(increase(response_total{direction="inbound", namespace="linkerd"}[1m])) by (namespace, authority, classification, tls)`,
+						`sum(increase(response_total{direction="inbound", namespace="linkerd"}[1m])) by (namespace, authority, classification, tls, no_tls_reason)`,
 					},
 				},
 				req: pb.StatSummaryRequest{
@@ -1101,7 +1593,7 @@ status:
 						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{deployment="emojivoto", direction="outbound"}[1m])) by (le, dst_namespace, authority))`,
 						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{deployment="emojivoto", direction="outbound"}[1m])) by (le, dst_namespace, authority))`,
 						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{deployment="emojivoto", direction="outbound"}[1m])) by (le, dst_namespace, authority))`,
-						`sum(increase(response_total{deployment="emojivoto", direction="outbound"}[1m])) by (dst_namespace, authority, classification, tls)`,
+						`sum(increase(response_total{deployment="emojivoto", direction="outbound"}[1m])) by (dst_namespace, authority, classification, tls, no_tls_reason)`,
 					},
 				},
 				req: pb.StatSummaryRequest{
@@ -1152,7 +1644,7 @@ status:
 						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{authority="10.1.1.239:9995", direction="inbound", namespace="linkerd"}[1m])) by (le, namespace, authority))`,
 						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{authority="10.1.1.239:9995", direction="inbound", namespace="linkerd"}[1m])) by (le, namespace, authority))`,
 						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{authority="10.1.1.239:9995", direction="inbound", namespace="linkerd"}[1m])) by (le, namespace, authority))`,
-						`sum(increase(response_total{authority="10.1.1.239:9995", direction="inbound", namespace="linkerd"}[1m])) by (namespace, authority, classification, tls)`,
+						`sum(increase(response_total{authority="10.1.1.239:9995", direction="inbound", namespace="linkerd"}[1m])) by (namespace, authority, classification, tls, no_tls_reason)`,
 					},
 				},
 				req: pb.StatSummaryRequest{
@@ -1172,6 +1664,100 @@ status:
 		testStatSummary(t, expectations)
 	})
 
+	t.Run("Queries prometheus for the backends of traffic splits", func(t *testing.T) {
+		expectations := []statSumExpected{
+			statSumExpected{
+				expectedStatRPC: expectedStatRPC{
+					err: nil,
+					k8sConfigs: []string{`
+apiVersion: split.smi-spec.io/v1alpha1
+kind: TrafficSplit
+metadata:
+  name: authors-split
+  namespace: default
+spec:
+  service: authors
+  backends:
+  - service: authors-v1
+    weight: 900m
+  - service: authors-v2
+    weight: 100m
+`,
+					},
+					mockPromResponse: model.Vector{
+						genPromSample("authors-v1", "service", "default", "success", true),
+					},
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{authority=~"(authors.default.svc.cluster.local)(:\\d+)?", direction="outbound", dst_namespace="default"}[1m])) by (le, dst_service))`,
+						`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{authority=~"(authors.default.svc.cluster.local)(:\\d+)?", direction="outbound", dst_namespace="default"}[1m])) by (le, dst_service))`,
+						`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{authority=~"(authors.default.svc.cluster.local)(:\\d+)?", direction="outbound", dst_namespace="default"}[1m])) by (le, dst_service))`,
+						`sum(increase(response_total{authority=~"(authors.default.svc.cluster.local)(:\\d+)?", direction="outbound", dst_namespace="default"}[1m])) by (dst_service, classification, tls, no_tls_reason)`,
+					},
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Namespace: "default",
+							Type:      pkgK8s.TrafficSplit,
+						},
+					},
+					TimeWindow: "1m",
+				},
+				expectedResponse: pb.StatSummaryResponse{
+					Response: &pb.StatSummaryResponse_Ok_{
+						Ok: &pb.StatSummaryResponse_Ok{
+							StatTables: []*pb.StatTable{
+								&pb.StatTable{
+									Table: &pb.StatTable_PodGroup_{
+										PodGroup: &pb.StatTable_PodGroup{
+											Rows: []*pb.StatTable_PodGroup_Row{
+												&pb.StatTable_PodGroup_Row{
+													Resource: &pb.Resource{
+														Namespace: "default",
+														Type:      pkgK8s.TrafficSplit,
+														Name:      "authors-split",
+													},
+													TimeWindow: "1m",
+													Stats: &pb.BasicStats{
+														SuccessCount:    123,
+														LatencyMsP50:    123,
+														LatencyMsP95:    123,
+														LatencyMsP99:    123,
+														TlsRequestCount: 123,
+													},
+													TsStats: &pb.TrafficSplitStats{
+														Apex:   "authors",
+														Leaf:   "authors-v1",
+														Weight: "900m",
+													},
+												},
+												&pb.StatTable_PodGroup_Row{
+													Resource: &pb.Resource{
+														Namespace: "default",
+														Type:      pkgK8s.TrafficSplit,
+														Name:      "authors-split",
+													},
+													TimeWindow: "1m",
+													TsStats: &pb.TrafficSplitStats{
+														Apex:   "authors",
+														Leaf:   "authors-v2",
+														Weight: "100m",
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
 	t.Run("Stats returned are nil when SkipStats is true", func(t *testing.T) {
 		expectations := []statSumExpected{
 			statSumExpected{
@@ -1214,3 +1800,135 @@ status:
 		testStatSummary(t, expectations)
 	})
 }
+
+func TestProcessPrometheusMetrics(t *testing.T) {
+	t.Run("Counts inbound requests from clients without an identity as unmeshed", func(t *testing.T) {
+		unmeshed := func(classification string) *model.Sample {
+			sample := genPromSample("web", "deployment", "emojivoto", classification, false)
+			sample.Metric["tls"] = "false"
+			sample.Metric["no_tls_reason"] = "not_provided_by_remote"
+			return sample
+		}
+		results := []promResult{
+			{
+				prom: promRequests,
+				vec: model.Vector{
+					genPromSample("web", "deployment", "emojivoto", "success", false),
+					unmeshed("success"),
+					unmeshed("failure"),
+				},
+			},
+		}
+		key := rKey{Namespace: "emojivoto", Type: pkgK8s.Deployment, Name: "web"}
+
+		req := &pb.StatSummaryRequest{
+			Selector: &pb.ResourceSelection{
+				Resource: &pb.Resource{Namespace: "emojivoto", Type: pkgK8s.Deployment},
+			},
+		}
+		stats := processPrometheusMetrics(req, results, model.LabelNames{"namespace", "deployment"})
+		if stats[key].UnmeshedRequestCount != 246 {
+			t.Fatalf("Expected 246 unmeshed requests, got %d", stats[key].UnmeshedRequestCount)
+		}
+
+		// outbound metrics are about the clients of the destination, not the selected resource
+		req.Outbound = &pb.StatSummaryRequest_ToResource{ToResource: &pb.Resource{Type: pkgK8s.Deployment, Name: "voting"}}
+		stats = processPrometheusMetrics(req, results, model.LabelNames{"namespace", "deployment"})
+		if stats[key].UnmeshedRequestCount != 0 {
+			t.Fatalf("Expected no unmeshed requests for outbound metrics, got %d", stats[key].UnmeshedRequestCount)
+		}
+	})
+	t.Run("Counts gRPC responses by grpc-status", func(t *testing.T) {
+		grpcStatus := func(code string, value model.SampleValue) *model.Sample {
+			sample := genPromSample("web", "deployment", "emojivoto", "", false)
+			if code != "" {
+				sample.Metric["grpc_status"] = model.LabelValue(code)
+			}
+			sample.Value = value
+			return sample
+		}
+		req := &pb.StatSummaryRequest{
+			Selector: &pb.ResourceSelection{
+				Resource: &pb.Resource{Namespace: "emojivoto", Type: pkgK8s.Deployment},
+			},
+			GrpcStats: true,
+		}
+		results := []promResult{
+			{
+				prom: promGrpcStatuses,
+				vec: model.Vector{
+					grpcStatus("14", 3),
+					grpcStatus("0", 90),
+					grpcStatus("", 40),
+					grpcStatus("4", 7),
+				},
+			},
+		}
+
+		stats := processPrometheusMetrics(req, results, model.LabelNames{"namespace", "deployment"})
+
+		expected := []*pb.GrpcStatusCount{
+			{Code: 0, Count: 90},
+			{Code: 4, Count: 7},
+			{Code: 14, Count: 3},
+		}
+		actual := stats[rKey{Namespace: "emojivoto", Type: pkgK8s.Deployment, Name: "web"}].GrpcStatusCounts
+		if len(actual) != len(expected) {
+			t.Fatalf("Expected grpc status counts %v, got %v", expected, actual)
+		}
+		for i := range expected {
+			if !proto.Equal(actual[i], expected[i]) {
+				t.Fatalf("Expected grpc status counts %v, got %v", expected, actual)
+			}
+		}
+	})
+}
+
+func TestGroupStatMetrics(t *testing.T) {
+	t.Run("Breaks the stats of each resource down by authority", func(t *testing.T) {
+		sample := func(name, authority string) *model.Sample {
+			sample := genPromSample(name, "deployment", "emojivoto", "success", false)
+			sample.Metric["authority"] = model.LabelValue(authority)
+			return sample
+		}
+		mockProm := &mockProm{Res: model.Vector{
+			sample("web", "voting-svc.emojivoto:8080"),
+			sample("web", "emoji-svc.emojivoto:8080"),
+			sample("vote-bot", "web-svc.emojivoto:80"),
+		}}
+		s := &grpcServer{prometheusAPI: mockProm}
+
+		req := &pb.StatSummaryRequest{
+			Selector: &pb.ResourceSelection{
+				Resource: &pb.Resource{Namespace: "emojivoto", Type: pkgK8s.Deployment},
+			},
+			TimeWindow: "1m",
+			GroupBy:    "authority",
+		}
+		groupStats, err := s.getGroupStatMetrics(context.TODO(), req, req.TimeWindow)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := map[rKey][]string{
+			rKey{Namespace: "emojivoto", Type: pkgK8s.Deployment, Name: "web"}:      []string{"emoji-svc.emojivoto:8080", "voting-svc.emojivoto:8080"},
+			rKey{Namespace: "emojivoto", Type: pkgK8s.Deployment, Name: "vote-bot"}: []string{"web-svc.emojivoto:80"},
+		}
+		if len(groupStats) != len(expected) {
+			t.Fatalf("Expected the stats of %d resources, got %v", len(expected), groupStats)
+		}
+		for key, groups := range expected {
+			if len(groupStats[key]) != len(groups) {
+				t.Fatalf("Expected the groups %v of %s, got %v", groups, key.Name, groupStats[key])
+			}
+			for i, group := range groups {
+				if groupStats[key][i].Group != group {
+					t.Fatalf("Expected the groups %v of %s, got %v", groups, key.Name, groupStats[key])
+				}
+				if groupStats[key][i].Stats.SuccessCount != 123 {
+					t.Fatalf("Expected 123 successes for %s of %s, got %d", group, key.Name, groupStats[key][i].Stats.SuccessCount)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package public
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// tapAPIClient is a public API client whose taps go through the tap API that
+// the Kubernetes API aggregates, and the other requests through the public
+// API client that it embeds.
+type tapAPIClient struct {
+	pb.ApiClient
+	kubeAPIHost string
+	httpClient  *http.Client
+}
+
+// TapByResource taps through the tap API, so that the Kubernetes credentials,
+// audit logging and RBAC apply to the tap. When the tap API isn't registered
+// or available, such as in control planes that were installed without it, it
+// taps through the public API instead.
+func (c *tapAPIClient) TapByResource(ctx context.Context, req *pb.TapByResourceRequest, opts ...grpc.CallOption) (pb.Api_TapByResourceClient, error) {
+	resource := req.GetTarget().GetResource()
+	path := k8s.TapAPIPath(resource.GetNamespace(), resource.GetType(), resource.GetName())
+
+	reqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, c.kubeAPIHost+path, bytes.NewReader(reqBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	httpRsp, err := c.httpClient.Do(httpReq.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if httpRsp.Header.Get(errorHeader) == "" &&
+		(httpRsp.StatusCode == http.StatusNotFound || httpRsp.StatusCode == http.StatusServiceUnavailable) {
+		httpRsp.Body.Close()
+		log.Debugf("The tap API responded to [%s] with [%s], tapping through the public API", path, httpRsp.Status)
+		return c.ApiClient.TapByResource(ctx, req, opts...)
+	}
+
+	if err := checkIfResponseHasError(httpRsp); err != nil {
+		httpRsp.Body.Close()
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		log.Debug("Closing response body after context marked as done")
+		httpRsp.Body.Close()
+	}()
+
+	return &tapClient{ctx: ctx, reader: bufio.NewReader(httpRsp.Body)}, nil
+}
+
+func newTapAPIClient(kubeAPIHost string, httpClient *http.Client, fallback pb.ApiClient) pb.ApiClient {
+	return &tapAPIClient{
+		ApiClient:   fallback,
+		kubeAPIHost: kubeAPIHost,
+		httpClient:  httpClient,
+	}
+}
+
+// NewTapAPIClient creates a new Public API client whose taps go through the
+// tap API of the Kubernetes API, falling back to the client when the tap API
+// isn't available, and whose other requests go to the client.
+func NewTapAPIClient(kubeAPI *k8s.KubernetesAPI, client pb.ApiClient) (pb.ApiClient, error) {
+	httpClient, err := kubeAPI.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return newTapAPIClient(kubeAPI.Host, httpClient, client), nil
+}
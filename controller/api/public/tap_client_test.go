@@ -0,0 +1,98 @@
+package public
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+func TestTapAPIClient(t *testing.T) {
+	req := &pb.TapByResourceRequest{
+		Target: &pb.ResourceSelection{
+			Resource: &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: "web"},
+		},
+	}
+	event := &pb.TapEvent{ProxyDirection: pb.TapEvent_INBOUND}
+
+	t.Run("Taps through the tap API", func(t *testing.T) {
+		mockTransport := &mockTransport{
+			responseToReturn: &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(bufferedReader(t, event)),
+			},
+		}
+		fallback := &MockAPIClient{}
+		client := newTapAPIClient("https://kubernetes:6443", &http.Client{Transport: mockTransport}, fallback)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream, err := client.TapByResource(ctx, req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expectedURL := "https://kubernetes:6443/apis/tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deployments/web/tap"
+		if mockTransport.requestSent.URL.String() != expectedURL {
+			t.Fatalf("Expected request to URL [%s], got [%s]", expectedURL, mockTransport.requestSent.URL)
+		}
+		if mockTransport.requestSent.Method != http.MethodPost {
+			t.Fatalf("Expected a POST request, got %s", mockTransport.requestSent.Method)
+		}
+
+		received, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if received.ProxyDirection != event.ProxyDirection {
+			t.Fatalf("Expected event %+v, got %+v", event, received)
+		}
+	})
+
+	t.Run("Falls back to the public API when the tap API isn't available", func(t *testing.T) {
+		for _, code := range []int{http.StatusNotFound, http.StatusServiceUnavailable} {
+			mockTransport := &mockTransport{
+				responseToReturn: &http.Response{
+					StatusCode: code,
+					Header:     http.Header{},
+					Body:       ioutil.NopCloser(strings.NewReader(`{"kind":"Status"}`)),
+				},
+			}
+			expectedStream := &MockAPITapByResourceClient{}
+			fallback := &MockAPIClient{APITapByResourceClientToReturn: expectedStream}
+			client := newTapAPIClient("https://kubernetes:6443", &http.Client{Transport: mockTransport}, fallback)
+
+			stream, err := client.TapByResource(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if stream != expectedStream {
+				t.Fatalf("Expected the stream of the public API for status %d, got %+v", code, stream)
+			}
+		}
+	})
+
+	t.Run("Returns the errors of the tap API", func(t *testing.T) {
+		header := http.Header{}
+		header.Set(errorHeader, http.StatusText(http.StatusForbidden))
+		mockTransport := &mockTransport{
+			responseToReturn: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     header,
+				Body:       ioutil.NopCloser(bufferedReader(t, &pb.ApiError{Error: "tapping is forbidden"})),
+			},
+		}
+		fallback := &MockAPIClient{APITapByResourceClientToReturn: &MockAPITapByResourceClient{}}
+		client := newTapAPIClient("https://kubernetes:6443", &http.Client{Transport: mockTransport}, fallback)
+
+		_, err := client.TapByResource(context.Background(), req)
+		if err == nil || err.Error() != "tapping is forbidden" {
+			t.Fatalf("Expected the error of the tap API, got %v", err)
+		}
+	})
+}
@@ -22,13 +22,17 @@ import (
 type MockAPIClient struct {
 	ErrorToReturn                  error
 	VersionInfoToReturn            *pb.VersionInfo
+	TrustBundleResponseToReturn    *pb.TrustBundleResponse
 	ListPodsResponseToReturn       *pb.ListPodsResponse
 	ListServicesResponseToReturn   *pb.ListServicesResponse
 	StatSummaryResponseToReturn    *pb.StatSummaryResponse
 	TopRoutesResponseToReturn      *pb.TopRoutesResponse
+	EdgesResponseToReturn          *pb.EdgesResponse
+	GatewaysResponseToReturn       *pb.GatewaysResponse
 	SelfCheckResponseToReturn      *healthcheckPb.SelfCheckResponse
 	APITapClientToReturn           pb.Api_TapClient
 	APITapByResourceClientToReturn pb.Api_TapByResourceClient
+	APIWatchEdgesClientToReturn    pb.Api_WatchEdgesClient
 }
 
 // StatSummary provides a mock of a Public API method.
@@ -41,11 +45,31 @@ func (c *MockAPIClient) TopRoutes(ctx context.Context, in *pb.TopRoutesRequest,
 	return c.TopRoutesResponseToReturn, c.ErrorToReturn
 }
 
+// Edges provides a mock of a Public API method.
+func (c *MockAPIClient) Edges(ctx context.Context, in *pb.EdgesRequest, opts ...grpc.CallOption) (*pb.EdgesResponse, error) {
+	return c.EdgesResponseToReturn, c.ErrorToReturn
+}
+
+// WatchEdges provides a mock of a Public API method.
+func (c *MockAPIClient) WatchEdges(ctx context.Context, in *pb.EdgesRequest, opts ...grpc.CallOption) (pb.Api_WatchEdgesClient, error) {
+	return c.APIWatchEdgesClientToReturn, c.ErrorToReturn
+}
+
 // Version provides a mock of a Public API method.
 func (c *MockAPIClient) Version(ctx context.Context, in *pb.Empty, opts ...grpc.CallOption) (*pb.VersionInfo, error) {
 	return c.VersionInfoToReturn, c.ErrorToReturn
 }
 
+// TrustBundle provides a mock of a Public API method.
+func (c *MockAPIClient) TrustBundle(ctx context.Context, in *pb.Empty, opts ...grpc.CallOption) (*pb.TrustBundleResponse, error) {
+	return c.TrustBundleResponseToReturn, c.ErrorToReturn
+}
+
+// Gateways provides a mock of a Public API method.
+func (c *MockAPIClient) Gateways(ctx context.Context, in *pb.GatewaysRequest, opts ...grpc.CallOption) (*pb.GatewaysResponse, error) {
+	return c.GatewaysResponseToReturn, c.ErrorToReturn
+}
+
 // ListPods provides a mock of a Public API method.
 func (c *MockAPIClient) ListPods(ctx context.Context, in *pb.ListPodsRequest, opts ...grpc.CallOption) (*pb.ListPodsResponse, error) {
 	return c.ListPodsResponseToReturn, c.ErrorToReturn
@@ -117,6 +141,35 @@ func (a *MockAPITapByResourceClient) Recv() (*pb.TapEvent, error) {
 	return &eventPopped, errorPopped
 }
 
+// CloseSend satisfies the TapByResourceClient.CloseSend() gRPC method.
+func (a *MockAPITapByResourceClient) CloseSend() error {
+	return nil
+}
+
+// MockAPIWatchEdgesClient satisfies the WatchEdgesClient gRPC interface.
+type MockAPIWatchEdgesClient struct {
+	EdgeEventsToReturn []pb.EdgeEvent
+	ErrorsToReturn     []error
+	grpc.ClientStream
+}
+
+// Recv satisfies the WatchEdgesClient.Recv() gRPC method.
+func (a *MockAPIWatchEdgesClient) Recv() (*pb.EdgeEvent, error) {
+	var eventPopped pb.EdgeEvent
+	var errorPopped error
+	if len(a.EdgeEventsToReturn) == 0 && len(a.ErrorsToReturn) == 0 {
+		return nil, io.EOF
+	}
+	if len(a.EdgeEventsToReturn) != 0 {
+		eventPopped, a.EdgeEventsToReturn = a.EdgeEventsToReturn[0], a.EdgeEventsToReturn[1:]
+	}
+	if len(a.ErrorsToReturn) != 0 {
+		errorPopped, a.ErrorsToReturn = a.ErrorsToReturn[0], a.ErrorsToReturn[1:]
+	}
+
+	return &eventPopped, errorPopped
+}
+
 //
 // Prometheus client
 //
@@ -130,9 +183,11 @@ type mockProm struct {
 // PodCounts is a test helper struct that is used for representing data in a
 // StatTable.PodGroup.Row.
 type PodCounts struct {
-	MeshedPods  uint64
-	RunningPods uint64
-	FailedPods  uint64
+	MeshedPods     uint64
+	RunningPods    uint64
+	FailedPods     uint64
+	ProxyReadyPods uint64
+	Restarts       uint64
 }
 
 func (m *mockProm) Query(ctx context.Context, query string, ts time.Time) (model.Value, error) {
@@ -183,6 +238,8 @@ func GenStatSummaryResponse(resName, resType string, resNs []string, counts *Pod
 			statTableRow.MeshedPodCount = counts.MeshedPods
 			statTableRow.RunningPodCount = counts.RunningPods
 			statTableRow.FailedPodCount = counts.FailedPods
+			statTableRow.ProxyReadyPodCount = counts.ProxyReadyPods
+			statTableRow.RestartCount = counts.Restarts
 		}
 
 		rows = append(rows, statTableRow)
@@ -281,6 +338,7 @@ func newMockGrpcServer(exp expectedStatRPC) (*mockProm, *grpcServer, error) {
 		k8sAPI,
 		"linkerd",
 		[]string{},
+		QueryLimits{},
 	)
 
 	k8sAPI.Sync()
@@ -21,6 +21,7 @@ import (
 const (
 	routeReqQuery             = "sum(increase(route_response_total%s[%s])) by (%s, dst, classification)"
 	actualRouteReqQuery       = "sum(increase(route_actual_response_total%s[%s])) by (%s, dst, classification)"
+	routeGrpcStatusQuery      = "sum(increase(route_response_total%s[%s])) by (%s, dst, grpc_status)"
 	routeLatencyQuantileQuery = "histogram_quantile(%s, sum(irate(route_response_latency_ms_bucket%s[%s])) by (le, dst, %s))"
 	dstLabel                  = `dst=~"(%s)(:\\d+)?"`
 	// DefaultRouteName is the name to display for requests that don't match any routes.
@@ -277,6 +278,10 @@ func (s *grpcServer) getRouteMetrics(ctx context.Context, req *pb.TopRoutesReque
 		queries[promActualRequests] = actualRouteReqQuery
 	}
 
+	if req.GetGrpcStats() {
+		queries[promGrpcStatuses] = routeGrpcStatusQuery
+	}
+
 	results, err := s.getPrometheusMetrics(ctx, queries, routeLatencyQuantileQuery, reqLabels, timeWindow, groupBy)
 	if err != nil {
 		return nil, err
@@ -378,6 +383,8 @@ func processRouteMetrics(results []promResult, timeWindow string, table indexedT
 				case "failure":
 					table[key].Stats.ActualFailureCount += value
 				}
+			case promGrpcStatuses:
+				addGrpcStatusCount(table[key].Stats, sample)
 			case promLatencyP50:
 				table[key].Stats.LatencyMsP50 = value
 			case promLatencyP95:
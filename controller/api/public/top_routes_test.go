@@ -199,6 +199,53 @@ func TestTopRoutes(t *testing.T) {
 		testTopRoutes(t, expectations)
 	})
 
+	t.Run("Successfully performs a routes query with grpc statuses", func(t *testing.T) {
+		routes := []string{"/a"}
+		counts := []uint64{123}
+		samples := routesMetric([]string{"/a"})
+		for _, sample := range samples {
+			sample.Metric["grpc_status"] = "14"
+		}
+		expectedResponse := GenTopRoutesResponse(routes, counts, false, "books")
+		for _, row := range expectedResponse.GetOk().GetRoutes()[0].GetRows() {
+			row.Stats.GrpcStatusCounts = []*pb.GrpcStatusCount{{Code: 14, Count: 123}}
+		}
+
+		expectations := []topRoutesExpected{
+			topRoutesExpected{
+				expectedStatRPC: expectedStatRPC{
+					err:              nil,
+					mockPromResponse: samples,
+					expectedPrometheusQueries: []string{
+						`histogram_quantile(0.5, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`histogram_quantile(0.95, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`histogram_quantile(0.99, sum(irate(route_response_latency_ms_bucket{deployment="books", direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (le, dst, rt_route))`,
+						`sum(increase(route_response_total{deployment="books", direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, classification)`,
+						`sum(increase(route_response_total{deployment="books", direction="inbound", dst=~"(books.default.svc.cluster.local)(:\\d+)?", namespace="default"}[1m])) by (rt_route, dst, grpc_status)`,
+					},
+					k8sConfigs: booksConfig,
+				},
+				req: pb.TopRoutesRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Namespace: "default",
+							Type:      pkgK8s.Deployment,
+							Name:      "books",
+						},
+					},
+					TimeWindow: "1m",
+					Outbound: &pb.TopRoutesRequest_None{
+						None: &pb.Empty{},
+					},
+					GrpcStats: true,
+				},
+				expectedResponse: expectedResponse,
+			},
+		}
+
+		testTopRoutes(t, expectations)
+	})
+
 	t.Run("Successfully performs a routes query for a service", func(t *testing.T) {
 		routes := []string{"/a"}
 		counts := []uint64{123}
@@ -54,6 +54,10 @@ type StatsBaseRequestParams struct {
 	ResourceType  string
 	ResourceName  string
 	AllNamespaces bool
+
+	// GrpcStats also requests the counts of the gRPC responses by
+	// grpc-status
+	GrpcStats bool
 }
 
 // StatsSummaryRequestParams contains parameters that are used to build
@@ -67,6 +71,14 @@ type StatsSummaryRequestParams struct {
 	FromType      string
 	FromName      string
 	SkipStats     bool
+
+	// MetricLabels are pod label keys and values that the stats are restricted
+	// to; the keys must be in the control plane's metric pod labels
+	MetricLabels map[string]string
+
+	// GroupBy also requests the stats of each resource broken down by the
+	// "authority" or the "route" of the requests
+	GroupBy string
 }
 
 // TopRoutesRequestParams contains parameters that are used to build TopRoutes
@@ -162,6 +174,15 @@ func BuildStatSummaryRequest(p StatsSummaryRequestParams) (*pb.StatSummaryReques
 		},
 		TimeWindow: window,
 		SkipStats:  p.SkipStats,
+		GrpcStats:  p.GrpcStats,
+		GroupBy:    p.GroupBy,
+	}
+
+	if len(p.MetricLabels) > 0 {
+		statRequest.MetricLabels = map[string]string{}
+		for key, value := range p.MetricLabels {
+			statRequest.MetricLabels[k8s.ToMetricLabelName(key)] = value
+		}
 	}
 
 	if p.ToName != "" || p.ToType != "" || p.ToNamespace != "" {
@@ -250,6 +271,7 @@ func BuildTopRoutesRequest(p TopRoutesRequestParams) (*pb.TopRoutesRequest, erro
 			},
 		},
 		TimeWindow: window,
+		GrpcStats:  p.GrpcStats,
 	}
 
 	if p.ToName != "" || p.ToType != "" || p.ToNamespace != "" {
@@ -282,6 +304,42 @@ func BuildTopRoutesRequest(p TopRoutesRequestParams) (*pb.TopRoutesRequest, erro
 	return topRoutesRequest, nil
 }
 
+// BuildEdgesRequest builds a Public API EdgesRequest from a
+// StatsBaseRequestParams.
+func BuildEdgesRequest(p StatsBaseRequestParams) (*pb.EdgesRequest, error) {
+	window := defaultMetricTimeWindow
+	if p.TimeWindow != "" {
+		_, err := time.ParseDuration(p.TimeWindow)
+		if err != nil {
+			return nil, err
+		}
+		window = p.TimeWindow
+	}
+
+	targetNamespace := p.Namespace
+	if p.AllNamespaces {
+		targetNamespace = ""
+	} else if p.Namespace == "" {
+		targetNamespace = v1.NamespaceDefault
+	}
+
+	resourceType, err := k8s.CanonicalResourceNameFromFriendlyName(p.ResourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.EdgesRequest{
+		Selector: &pb.ResourceSelection{
+			Resource: &pb.Resource{
+				Namespace: targetNamespace,
+				Name:      p.ResourceName,
+				Type:      resourceType,
+			},
+		},
+		TimeWindow: window,
+	}, nil
+}
+
 // An authority can only receive traffic, not send it, so it can't be a --from
 func validateFromResourceType(resourceType string) (string, error) {
 	name, err := k8s.CanonicalResourceNameFromFriendlyName(resourceType)
@@ -306,6 +364,40 @@ func BuildResource(namespace, arg string) (pb.Resource, error) {
 	return res[0], err
 }
 
+// BuildResourcePattern is the same as BuildResource, but it also admits a
+// comma-separated list of resources of the same type, such as
+// "deploy/web,deploy/voting", which it returns as a resource whose name is the
+// comma-separated list of the names. Names can also contain "*" wildcards.
+func BuildResourcePattern(namespace, arg string) (pb.Resource, error) {
+	if !strings.Contains(arg, ",") {
+		return BuildResource(namespace, arg)
+	}
+
+	args := strings.Split(arg, ",")
+	if err := validateResources("", args); err != nil {
+		return pb.Resource{}, err
+	}
+	var pattern pb.Resource
+	names := make([]string, 0)
+	for _, elem := range args {
+		res, err := parseResource(namespace, "", elem)
+		if err != nil {
+			return pb.Resource{}, err
+		}
+		if res.Name == "" {
+			return pb.Resource{}, fmt.Errorf("a name is required for each resource of a list: %s", arg)
+		}
+		if pattern.Type != "" && pattern.Type != res.Type {
+			return pb.Resource{}, fmt.Errorf("the resources of a list must be of the same type: %s", arg)
+		}
+		pattern = res
+		names = append(names, res.Name)
+	}
+	pattern.Name = strings.Join(names, ",")
+
+	return pattern, nil
+}
+
 // BuildResources parses input strings, typically from CLI flags, to build a
 // slice of Resource objects for use in the protobuf API.
 // It's the same as BuildResource but it admits any number of args and returns multiple resources
@@ -277,6 +277,55 @@ func TestBuildResource(t *testing.T) {
 	})
 }
 
+func TestBuildResourcePattern(t *testing.T) {
+	t.Run("Returns expected errors on invalid input", func(t *testing.T) {
+		expectations := map[string]string{
+			"deploy/web,deploy/web":  "cannot supply duplicate resources",
+			"deploy/web,deploy":      "a name is required for each resource of a list: deploy/web,deploy",
+			"deploy/web,po/voting-1": "the resources of a list must be of the same type: deploy/web,po/voting-1",
+			"deploy/web,invalid/foo": "cannot find Kubernetes canonical name from friendly name [invalid]",
+		}
+
+		for arg, msg := range expectations {
+			_, err := BuildResourcePattern("test-ns", arg)
+			if err == nil || err.Error() != msg {
+				t.Fatalf("BuildResourcePattern(%s) should have returned: %s but got: %v", arg, msg, err)
+			}
+		}
+	})
+
+	t.Run("Correctly parses resource patterns from the command line", func(t *testing.T) {
+		expectations := map[string]pb.Resource{
+			"deploy/web": pb.Resource{
+				Namespace: "test-ns",
+				Type:      k8s.Deployment,
+				Name:      "web",
+			},
+			"deploy/*": pb.Resource{
+				Namespace: "test-ns",
+				Type:      k8s.Deployment,
+				Name:      "*",
+			},
+			"po/web-*,pods/voting-1": pb.Resource{
+				Namespace: "test-ns",
+				Type:      k8s.Pod,
+				Name:      "web-*,voting-1",
+			},
+		}
+
+		for arg, exp := range expectations {
+			res, err := BuildResourcePattern("test-ns", arg)
+			if err != nil {
+				t.Fatalf("Unexpected error from BuildResourcePattern(%s) => %s", arg, err)
+			}
+
+			if !reflect.DeepEqual(exp, res) {
+				t.Fatalf("Expected resource to be [%+v] but was [%+v]", exp, res)
+			}
+		}
+	})
+}
+
 func TestBuildResources(t *testing.T) {
 	type resourceExp struct {
 		namespace string
@@ -2,13 +2,15 @@ package ca
 
 import (
 	"crypto/ecdsa"
-	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"math"
 	"math/big"
 	"time"
+
+	"github.com/linkerd/linkerd2/pkg/tls"
 )
 
 // CA provides a certificate authority for TLS-enabled installs.
@@ -46,6 +48,10 @@ type CA struct {
 	// For now we do not attempt to meet CABForum requirements (e.g. regarding
 	// randomness).
 	nextSerialNumber uint64
+
+	// vault signs the certificates instead of privateKey, which is nil then,
+	// when the CA forwards the certificate signing requests to Vault.
+	vault *vaultSigner
 }
 
 // CertificateAndPrivateKey encapsulates a certificate / private key pair.
@@ -69,7 +75,7 @@ func NewCA() (*CA, error) {
 	// for clock skew, there is somewhat of an over-correction.
 	clockSkewAllocance := 12 * time.Hour
 
-	privateKey, err := generateKeyPair()
+	privateKey, err := tls.GenerateECDSAKey()
 	if err != nil {
 		return nil, err
 	}
@@ -81,7 +87,7 @@ func NewCA() (*CA, error) {
 		nextSerialNumber:   1,
 	}
 
-	template := ca.createTemplate(&ca.privateKey.PublicKey)
+	template := ca.createTemplate(&ca.privateKey.PublicKey, ca.validity)
 
 	template.Subject = pkix.Name{CommonName: "Cluster-local Managed Pod CA"}
 
@@ -106,6 +112,40 @@ func NewCA() (*CA, error) {
 	return &ca, nil
 }
 
+// NewCAFromKeyPair creates a CA that signs certificates with an existing
+// PEM-encoded CA certificate and ECDSA P-256 private key, such as the ones
+// issued by cert-manager. The certificate is used as the trust anchor, so
+// that the proxies trust exactly the certificates it signs.
+func NewCAFromKeyPair(certPEM, keyPEM []byte) (*CA, error) {
+	root, err := tls.DecodePEMCert(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := tls.DecodePEMECDSAKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	if err := tls.ValidateECDSAIssuer(root, privateKey); err != nil {
+		return nil, err
+	}
+
+	// The CA outlives this process, so serial numbers are randomized to not
+	// be reused after a restart.
+	serialNumber, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64/2))
+	if err != nil {
+		return nil, err
+	}
+
+	return &CA{
+		validity:           (24 * 365) * time.Hour,
+		clockSkewAllocance: 12 * time.Hour,
+		privateKey:         privateKey,
+		root:               root,
+		rootPEM:            string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Raw})),
+		nextSerialNumber:   serialNumber.Uint64() + 1,
+	}, nil
+}
+
 // TrustAnchorPEM returns the PEM-encoded X.509 certificate of the trust anchor
 // (root CA).
 func (ca *CA) TrustAnchorPEM() string {
@@ -113,9 +153,11 @@ func (ca *CA) TrustAnchorPEM() string {
 }
 
 // IssueEndEntityCertificate creates a new certificate that is valid for the
-// given DNS name, generating a new keypair for it.
-func (ca *CA) IssueEndEntityCertificate(dnsName string) (*CertificateAndPrivateKey, error) {
-	privateKey, err := generateKeyPair()
+// given DNS name and aliases, generating a new keypair for it. The certificate
+// is valid for the given lifetime, or for the CA's validity if lifetime is zero
+// or longer.
+func (ca *CA) IssueEndEntityCertificate(dnsName string, lifetime time.Duration, aliases ...string) (*CertificateAndPrivateKey, error) {
+	privateKey, err := tls.GenerateECDSAKey()
 	if err != nil {
 		return nil, err
 	}
@@ -124,8 +166,24 @@ func (ca *CA) IssueEndEntityCertificate(dnsName string) (*CertificateAndPrivateK
 		return nil, err
 	}
 
-	template := ca.createTemplate(&privateKey.PublicKey)
-	template.DNSNames = []string{dnsName}
+	validity := ca.validity
+	if lifetime > 0 && lifetime < validity {
+		validity = lifetime
+	}
+
+	if ca.vault != nil {
+		crt, err := ca.vault.sign(privateKey, append([]string{dnsName}, aliases...), validity)
+		if err != nil {
+			return nil, err
+		}
+		return &CertificateAndPrivateKey{
+			Certificate: crt,
+			PrivateKey:  p8,
+		}, nil
+	}
+
+	template := ca.createTemplate(&privateKey.PublicKey, validity)
+	template.DNSNames = append([]string{dnsName}, aliases...)
 	crt, err := x509.CreateCertificate(rand.Reader, &template, ca.root, &privateKey.PublicKey, ca.privateKey)
 	if err != nil {
 		return nil, err
@@ -139,7 +197,7 @@ func (ca *CA) IssueEndEntityCertificate(dnsName string) (*CertificateAndPrivateK
 // createTemplate returns a certificate template for a non-CA certificate with
 // no subject name, no subjectAltNames. The template can then be modified into
 // a (root) CA template or an end-entity template by the caller.
-func (ca *CA) createTemplate(publicKey *ecdsa.PublicKey) x509.Certificate {
+func (ca *CA) createTemplate(publicKey *ecdsa.PublicKey, validity time.Duration) x509.Certificate {
 	// ECDSA is used instead of RSA because ECDSA key generation is
 	// straightforward and fast whereas RSA key generation is extremely slow
 	// and error-prone.
@@ -156,17 +214,26 @@ func (ca *CA) createTemplate(publicKey *ecdsa.PublicKey) x509.Certificate {
 	serialNumber := big.NewInt(int64(ca.nextSerialNumber))
 	ca.nextSerialNumber++
 
+	// the clock skew allowance would otherwise make short-lived certificates
+	// valid for much longer than their validity
+	clockSkewAllowance := ca.clockSkewAllocance
+	if clockSkewAllowance > validity {
+		clockSkewAllowance = validity
+	}
+
 	notBefore := time.Now()
+	notAfter := notBefore.Add(validity).Add(clockSkewAllowance)
+
+	// issued certificates can't outlive a CA that isn't self-signed
+	if ca.root != nil && notAfter.After(ca.root.NotAfter) {
+		notAfter = ca.root.NotAfter
+	}
 
 	return x509.Certificate{
 		SerialNumber:       serialNumber,
 		SignatureAlgorithm: SignatureAlgorithm,
-		NotBefore:          notBefore.Add(-ca.clockSkewAllocance),
-		NotAfter:           notBefore.Add(ca.validity).Add(ca.clockSkewAllocance),
+		NotBefore:          notBefore.Add(-clockSkewAllowance),
+		NotAfter:           notAfter,
 		PublicKey:          publicKey,
 	}
 }
-
-func generateKeyPair() (*ecdsa.PrivateKey, error) {
-	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
-}
@@ -1,30 +1,91 @@
 package ca
 
 import (
+	"encoding/pem"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/events"
 	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/api/admissionregistration/v1beta1"
 	"k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	k8sRuntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 )
 
+// minIssuanceLifetime is the shortest certificate lifetime that pods can
+// request with the IdentityIssuanceLifetimeAnnotation. Certificates are
+// reissued whenever the pod informer resyncs, so they must remain valid well
+// after that.
+const minIssuanceLifetime = time.Hour
+
+// issuance is how the certificates of a pod owner are issued, as requested
+// with the annotations of its pods.
+type issuance struct {
+	// lifetime is the requested certificate lifetime, or zero for the CA's
+	// default.
+	lifetime time.Duration
+
+	// alias is the legacy identity that the certificates are also valid for,
+	// until aliasExpiry, or nil.
+	alias       *pkgK8s.TLSIdentity
+	aliasExpiry time.Time
+}
+
 // CertificateController listens for added and updated meshed pods, and then
 // provides certificates in the form of secrets.
 type CertificateController struct {
-	namespace   string
-	k8sAPI      *k8s.API
-	ca          *CA
-	syncHandler func(key string) error
+	namespace       string
+	k8sAPI          *k8s.API
+	proxyAutoInject bool
+	syncHandler     func(key string) error
+
+	// ca is replaced when the issuer secret is rotated, so it's only accessed
+	// through getCA and setCA.
+	ca   *CA
+	caMu sync.Mutex
+
+	// issuerSecret is the name of the kubernetes.io/tls secret in the
+	// controller namespace that holds the CA's signing credentials, or an
+	// empty string if the CA generates its own.
+	issuerSecret          string
+	issuerInformer        cache.Controller
+	issuerResourceVersion string
+
+	// issuances are how the pods of each owner requested their certificates
+	// to be issued, keyed like the queue's secret items. Owners that don't
+	// request anything aren't in the map.
+	issuances   map[string]issuance
+	issuancesMu sync.Mutex
+
+	metrics *metrics
+
+	// limiter defers the issuances that exceed the global or per-owner rate
+	// limits, by requeuing their items after the limits allow them.
+	limiter *issuanceLimiter
+
+	// events receives a CertificateRotated event whenever the issuer secret
+	// is reloaded.
+	events events.Sink
+
+	// issueAPIClient is set when the CA also issues the certificate of the
+	// API client identity, for the clients of the public API to authenticate
+	// with.
+	issueAPIClient bool
 
 	// The queue is keyed on a string. If the string doesn't contain any dots
 	// then it is a namespace name and the task is to create the CA bundle
@@ -35,21 +96,45 @@ type CertificateController struct {
 }
 
 // NewCertificateController initializes a CertificateController and its
-// internal Certificate Authority.
-func NewCertificateController(controllerNamespace string, k8sAPI *k8s.API, proxyAutoInject bool) (*CertificateController, error) {
-	ca, err := NewCA()
-	if err != nil {
-		return nil, err
-	}
-
+// Certificate Authority. If issuerSecret is set, the CA signs certificates
+// with the credentials of that kubernetes.io/tls secret in the controller
+// namespace, and is reloaded whenever the secret changes. Otherwise, it
+// generates its own credentials.
+func NewCertificateController(controllerNamespace string, k8sAPI *k8s.API, proxyAutoInject bool, issuerSecret string) (*CertificateController, error) {
 	c := &CertificateController{
-		namespace: controllerNamespace,
-		k8sAPI:    k8sAPI,
-		ca:        ca,
+		namespace:       controllerNamespace,
+		k8sAPI:          k8sAPI,
+		proxyAutoInject: proxyAutoInject,
+		issuerSecret:    issuerSecret,
+		issuances:       make(map[string]issuance),
+		limiter:         newIssuanceLimiter(defaultIssuanceRate, defaultIssuanceBurst, defaultOwnerIssuanceInterval, defaultOwnerIssuanceBurst),
+		events:          events.NopSink{},
 		queue: workqueue.NewNamedRateLimitingQueue(
 			workqueue.DefaultControllerRateLimiter(), "certificates"),
 	}
 
+	c.metrics = newMetrics(c)
+
+	if issuerSecret == "" {
+		ca, err := NewCA()
+		if err != nil {
+			return nil, err
+		}
+		c.ca = ca
+	} else {
+		secret, err := k8sAPI.Client.CoreV1().Secrets(controllerNamespace).Get(issuerSecret, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		ca, err := newCAFromSecret(secret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid issuer secret %s: %s", issuerSecret, err)
+		}
+		c.ca = ca
+		c.issuerResourceVersion = secret.ResourceVersion
+		c.issuerInformer = c.newIssuerInformer()
+	}
+
 	k8sAPI.Pod().Informer().AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
 			AddFunc:    c.handlePodAdd,
@@ -71,6 +156,37 @@ func NewCertificateController(controllerNamespace string, k8sAPI *k8s.API, proxy
 	return c, nil
 }
 
+// LimitIssuance replaces the default issuance rate limits: the CA issues at
+// most issuanceRate certificates per second across all pod owners, with bursts
+// of issuanceBurst, and reissues the certificates of each pod owner at most
+// once per ownerInterval, with bursts of ownerBurst. The issuances that exceed
+// the limits are retried once they're allowed. A zero rate or interval
+// disables the corresponding limit. It must be called before Run.
+func (c *CertificateController) LimitIssuance(issuanceRate float64, issuanceBurst int, ownerInterval time.Duration, ownerBurst int) {
+	c.limiter = newIssuanceLimiter(issuanceRate, issuanceBurst, ownerInterval, ownerBurst)
+}
+
+// SetEventSink replaces the sink that the CA posts its CertificateRotated
+// events to, which discards them by default. It must be called before Run.
+func (c *CertificateController) SetEventSink(sink events.Sink) {
+	c.events = sink
+}
+
+// IssueAPIClientCertificate makes the CA also issue the certificate of the API
+// client identity, in a secret of the controller namespace, and reissue it
+// whenever the issuer secret is rotated. The clients of the public API that
+// can read the secret authenticate with it to the TLS port of the public API.
+// It must be called before Run.
+func (c *CertificateController) IssueAPIClientCertificate() {
+	c.issueAPIClient = true
+}
+
+// RegisterMetrics registers the CertificateController's metrics with the given
+// registerer.
+func (c *CertificateController) RegisterMetrics(registerer prometheus.Registerer) error {
+	return c.metrics.register(registerer)
+}
+
 // Run kicks off CertificateController queue processing.
 func (c *CertificateController) Run(stopCh <-chan struct{}) {
 	defer runtime.HandleCrash()
@@ -79,6 +195,10 @@ func (c *CertificateController) Run(stopCh <-chan struct{}) {
 	log.Info("starting certificate controller")
 	defer log.Info("shutting down certificate controller")
 
+	if c.issuerInformer != nil {
+		go c.issuerInformer.Run(stopCh)
+	}
+	c.enqueueAPIClient()
 	go wait.Until(c.worker, time.Second, stopCh)
 
 	<-stopCh
@@ -120,7 +240,7 @@ func (c *CertificateController) syncNamespace(ns string) error {
 	configMap := &v1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{Name: pkgK8s.TLSTrustAnchorConfigMapName},
 		Data: map[string]string{
-			pkgK8s.TLSTrustAnchorFileName: c.ca.TrustAnchorPEM(),
+			pkgK8s.TLSTrustAnchorFileName: c.getCA().TrustAnchorPEM(),
 		},
 	}
 
@@ -136,9 +256,11 @@ func (c *CertificateController) syncNamespace(ns string) error {
 
 func (c *CertificateController) syncSecret(key string) error {
 	log.Debugf("syncSecret(%s)", key)
+	c.metrics.requests.Inc()
 	parts := strings.Split(key, ".")
 	if len(parts) != 3 {
 		log.Errorf("Failed to parse secret sync request %s", key)
+		c.metrics.rejected.WithLabelValues(rejectInvalidRequest).Inc()
 		return nil // TODO
 	}
 	identity := pkgK8s.TLSIdentity{
@@ -149,17 +271,45 @@ func (c *CertificateController) syncSecret(key string) error {
 	}
 
 	dnsName := identity.ToDNSName()
+	if delay, reason := c.limiter.reserve(key, time.Now()); delay > 0 {
+		log.Debugf("deferring the certificate of %s by %s: %s", dnsName, delay, reason)
+		c.metrics.rejected.WithLabelValues(reason).Inc()
+		c.queue.AddAfter(key, delay)
+		return nil
+	}
+
 	secretName := identity.ToSecretName()
-	certAndPrivateKey, err := c.ca.IssueEndEntityCertificate(dnsName)
+	issuance := c.getIssuance(key)
+	lifetime := issuance.lifetime
+	aliases := []string{}
+	if issuance.alias != nil {
+		if remaining := time.Until(issuance.aliasExpiry); remaining > 0 && c.aliasAllowed(identity, *issuance.alias) {
+			aliases = append(aliases, issuance.alias.ToDNSName())
+			// the certificate only outlives the alias by the CA's
+			// clock skew allowance, and is reissued without it when
+			// the alias expires
+			if lifetime == 0 || remaining < lifetime {
+				lifetime = remaining
+			}
+			c.queue.AddAfter(key, remaining)
+		}
+	}
+	start := time.Now()
+	certAndPrivateKey, err := c.getCA().IssueEndEntityCertificate(dnsName, lifetime, aliases...)
+	c.metrics.issuanceDuration.Observe(time.Since(start).Seconds())
 	if err != nil {
 		log.Errorf("Failed to issue certificate for %s", dnsName)
+		c.metrics.rejected.WithLabelValues(rejectIssuanceError).Inc()
 		return err
 	}
+	c.metrics.issued.Inc()
 	secret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{Name: secretName},
 		Data: map[string][]byte{
-			pkgK8s.TLSCertFileName:       certAndPrivateKey.Certificate,
-			pkgK8s.TLSPrivateKeyFileName: certAndPrivateKey.PrivateKey,
+			pkgK8s.TLSCertFileName:          certAndPrivateKey.Certificate,
+			pkgK8s.TLSPrivateKeyFileName:    certAndPrivateKey.PrivateKey,
+			pkgK8s.TLSCertPEMFileName:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certAndPrivateKey.Certificate}),
+			pkgK8s.TLSPrivateKeyPEMFileName: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: certAndPrivateKey.PrivateKey}),
 		},
 	}
 	_, err = c.k8sAPI.Client.CoreV1().Secrets(identity.Namespace).Create(secret)
@@ -178,11 +328,115 @@ func (c *CertificateController) handlePodAdd(obj interface{}) {
 
 		ownerKind, ownerName := c.k8sAPI.GetOwnerKindAndName(pod)
 		item := fmt.Sprintf("%s.%s.%s", ownerName, ownerKind, pod.Namespace)
+		c.setIssuance(item, c.issuanceFor(pod, ownerKind, ownerName))
 		log.Debugf("enqueuing secret write for %s", item)
 		c.queue.Add(item)
 	}
 }
 
+// enqueueAPIClient enqueues the secret write of the API client identity, if
+// the CA issues it.
+func (c *CertificateController) enqueueAPIClient() {
+	if !c.issueAPIClient {
+		return
+	}
+	identity := pkgK8s.TLSIdentity{ControllerNamespace: c.namespace}.ToAPIClientIdentity()
+	item := fmt.Sprintf("%s.%s.%s", identity.Name, identity.Kind, identity.Namespace)
+	log.Debugf("enqueuing secret write for %s", item)
+	c.queue.Add(item)
+}
+
+// issuanceFor returns how the certificates of the pod's owner are issued,
+// according to the pod's annotations.
+func (c *CertificateController) issuanceFor(pod *v1.Pod, ownerKind, ownerName string) issuance {
+	issuance := issuance{lifetime: issuanceLifetime(pod)}
+	alias, expiry, ok := c.identityAlias(pod)
+	if ok && (alias.Name != ownerName || alias.Kind != ownerKind || alias.Namespace != pod.Namespace) {
+		issuance.alias = alias
+		issuance.aliasExpiry = expiry
+	}
+	return issuance
+}
+
+// identityAlias returns the legacy identity requested by the pod's
+// IdentityAliasAnnotation and its expiry, and false if it doesn't request a
+// valid one. The identities of the control plane can't be aliased.
+func (c *CertificateController) identityAlias(pod *v1.Pod) (*pkgK8s.TLSIdentity, time.Time, bool) {
+	value, ok := pod.Annotations[pkgK8s.IdentityAliasAnnotation]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+
+	parts := strings.Split(value, ".")
+	valid := len(parts) == 3 && parts[1] != pkgK8s.Service && parts[2] != c.namespace
+	for _, part := range parts {
+		valid = valid && len(validation.IsDNS1123Label(part)) == 0
+	}
+	if !valid {
+		log.Warnf("ignoring invalid value \"%s\" for the %s annotation of pod %s.%s: must be of the form <owner-name>.<owner-kind>.<namespace>, outside of the control plane namespace",
+			value, pkgK8s.IdentityAliasAnnotation, pod.Name, pod.Namespace)
+		return nil, time.Time{}, false
+	}
+
+	expiryValue := pod.Annotations[pkgK8s.IdentityAliasExpiryAnnotation]
+	expiry, err := time.Parse(time.RFC3339, expiryValue)
+	if err != nil {
+		log.Warnf("ignoring the %s annotation of pod %s.%s: invalid value \"%s\" for the %s annotation: must be an RFC 3339 time",
+			pkgK8s.IdentityAliasAnnotation, pod.Name, pod.Namespace, expiryValue, pkgK8s.IdentityAliasExpiryAnnotation)
+		return nil, time.Time{}, false
+	}
+
+	return &pkgK8s.TLSIdentity{
+		Name:                parts[0],
+		Kind:                parts[1],
+		Namespace:           parts[2],
+		ControllerNamespace: c.namespace,
+	}, expiry, true
+}
+
+// aliasAllowed returns true if the identity may be issued certificates for
+// the alias: aliases in the identity's own namespace are always allowed, since
+// the pods of a namespace can already use any of its pod owners' identities,
+// while the alias's namespace must allow the others with its
+// IdentityAliasNamespacesAnnotation.
+func (c *CertificateController) aliasAllowed(identity, alias pkgK8s.TLSIdentity) bool {
+	if alias.Namespace == identity.Namespace {
+		return true
+	}
+
+	ns, err := c.k8sAPI.Client.CoreV1().Namespaces().Get(alias.Namespace, metav1.GetOptions{})
+	if err != nil {
+		log.Warnf("ignoring alias %s of %s: failed to get namespace %s: %s", alias.ToDNSName(), identity.ToDNSName(), alias.Namespace, err)
+		return false
+	}
+	for _, allowed := range strings.Split(ns.Annotations[pkgK8s.IdentityAliasNamespacesAnnotation], ",") {
+		if strings.TrimSpace(allowed) == identity.Namespace {
+			return true
+		}
+	}
+	log.Warnf("ignoring alias %s of %s: namespace %s doesn't allow it with the %s annotation",
+		alias.ToDNSName(), identity.ToDNSName(), alias.Namespace, pkgK8s.IdentityAliasNamespacesAnnotation)
+	return false
+}
+
+// issuanceLifetime returns the certificate lifetime requested by the pod's
+// IdentityIssuanceLifetimeAnnotation, or zero if it doesn't request a valid
+// one.
+func issuanceLifetime(pod *v1.Pod) time.Duration {
+	value, ok := pod.Annotations[pkgK8s.IdentityIssuanceLifetimeAnnotation]
+	if !ok {
+		return 0
+	}
+
+	lifetime, err := time.ParseDuration(value)
+	if err != nil || lifetime < minIssuanceLifetime {
+		log.Warnf("ignoring invalid value \"%s\" for the %s annotation of pod %s.%s: must be a duration of at least %s",
+			value, pkgK8s.IdentityIssuanceLifetimeAnnotation, pod.Name, pod.Namespace, minIssuanceLifetime)
+		return 0
+	}
+	return lifetime
+}
+
 func (c *CertificateController) handlePodUpdate(oldObj, newObj interface{}) {
 	c.handlePodAdd(newObj)
 }
@@ -200,3 +454,117 @@ func (c *CertificateController) handleMWCAdd(obj interface{}) {
 func (c *CertificateController) handleMWCUpdate(oldObj, newObj interface{}) {
 	c.handleMWCAdd(newObj)
 }
+
+func (c *CertificateController) getCA() *CA {
+	c.caMu.Lock()
+	defer c.caMu.Unlock()
+	return c.ca
+}
+
+func (c *CertificateController) setCA(ca *CA) {
+	c.caMu.Lock()
+	defer c.caMu.Unlock()
+	c.ca = ca
+}
+
+func (c *CertificateController) getIssuance(item string) issuance {
+	c.issuancesMu.Lock()
+	defer c.issuancesMu.Unlock()
+	return c.issuances[item]
+}
+
+func (c *CertificateController) setIssuance(item string, i issuance) {
+	c.issuancesMu.Lock()
+	defer c.issuancesMu.Unlock()
+	if i == (issuance{}) {
+		delete(c.issuances, item)
+	} else {
+		c.issuances[item] = i
+	}
+}
+
+// newIssuerInformer returns an informer that only watches the issuer secret,
+// since the CA isn't allowed to read the other secrets of the controller
+// namespace.
+func (c *CertificateController) newIssuerInformer() cache.Controller {
+	secrets := c.k8sAPI.Client.CoreV1().Secrets(c.namespace)
+	selector := fields.OneTermEqualSelector("metadata.name", c.issuerSecret).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (k8sRuntime.Object, error) {
+			options.FieldSelector = selector
+			return secrets.List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return secrets.Watch(options)
+		},
+	}
+
+	_, informer := cache.NewInformer(lw, &v1.Secret{}, 10*time.Minute,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handleIssuerSecretAdd,
+			UpdateFunc: c.handleIssuerSecretUpdate,
+		},
+	)
+	return informer
+}
+
+func (c *CertificateController) handleIssuerSecretAdd(obj interface{}) {
+	secret := obj.(*v1.Secret)
+	if secret.Name != c.issuerSecret || secret.ResourceVersion == c.issuerResourceVersion {
+		return
+	}
+
+	ca, err := newCAFromSecret(secret)
+	if err != nil {
+		// keep signing with the previous credentials until the secret is fixed
+		log.Errorf("failed to reload the issuer secret %s: %s", c.issuerSecret, err)
+		return
+	}
+	log.Infof("reloaded the issuer secret %s", c.issuerSecret)
+	c.setCA(ca)
+	c.issuerResourceVersion = secret.ResourceVersion
+	c.events.Post(events.Event{
+		Type: events.CertificateRotated,
+		Attributes: map[string]string{
+			"issuerSecret": c.issuerSecret,
+			"subject":      ca.root.Subject.CommonName,
+			"notAfter":     ca.root.NotAfter.UTC().Format(time.RFC3339),
+		},
+	})
+
+	// distribute the new trust anchor, and reissue all the certificates with
+	// the new credentials
+	pods, err := c.k8sAPI.Pod().Lister().List(labels.Everything())
+	if err != nil {
+		log.Errorf("failed to list pods: %s", err)
+		return
+	}
+	for _, pod := range pods {
+		c.handlePodAdd(pod)
+	}
+	c.enqueueAPIClient()
+	if c.proxyAutoInject {
+		mwcs, err := c.k8sAPI.MWC().Lister().List(labels.Everything())
+		if err != nil {
+			log.Errorf("failed to list mutating webhook configurations: %s", err)
+			return
+		}
+		for _, mwc := range mwcs {
+			c.handleMWCAdd(mwc)
+		}
+	}
+}
+
+func (c *CertificateController) handleIssuerSecretUpdate(oldObj, newObj interface{}) {
+	c.handleIssuerSecretAdd(newObj)
+}
+
+// newCAFromSecret creates a CA from the certificate and private key of a
+// kubernetes.io/tls secret.
+func newCAFromSecret(secret *v1.Secret) (*CA, error) {
+	if secret.Type != v1.SecretTypeTLS {
+		return nil, fmt.Errorf("secret type is %s, must be %s", secret.Type, v1.SecretTypeTLS)
+	}
+	return NewCAFromKeyPair(secret.Data[v1.TLSCertKey], secret.Data[v1.TLSPrivateKeyKey])
+}
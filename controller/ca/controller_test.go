@@ -1,11 +1,15 @@
 package ca
 
 import (
+	"crypto/x509"
+	"encoding/base64"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/events"
 	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
 	"k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -60,13 +64,306 @@ func TestCertificateController(t *testing.T) {
 	})
 }
 
+func TestCertificateControllerIssuerSecret(t *testing.T) {
+	issuerSecret := func(resourceVersion string) (*v1.Secret, string) {
+		certPEM, keyPEM, err := genIssuerKeyPair()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		secret := &v1.Secret{
+			ObjectMeta: meta.ObjectMeta{Name: "linkerd-issuer", Namespace: controllerNS, ResourceVersion: resourceVersion},
+			Type:       v1.SecretTypeTLS,
+			Data:       map[string][]byte{v1.TLSCertKey: certPEM, v1.TLSPrivateKeyKey: keyPEM},
+		}
+		return secret, string(certPEM)
+	}
+
+	secret, trustAnchor := issuerSecret("1")
+	k8sAPI, err := k8s.NewFakeAPI("", fmt.Sprintf(`
+apiVersion: v1
+kind: Secret
+type: kubernetes.io/tls
+metadata:
+  name: linkerd-issuer
+  namespace: %s
+  resourceVersion: "1"
+data:
+  tls.crt: %s
+  tls.key: %s`, controllerNS,
+		base64.StdEncoding.EncodeToString(secret.Data[v1.TLSCertKey]),
+		base64.StdEncoding.EncodeToString(secret.Data[v1.TLSPrivateKeyKey])))
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+
+	controller, err := NewCertificateController(controllerNS, k8sAPI, false, "linkerd-issuer")
+	if err != nil {
+		t.Fatalf("NewCertificateController returned an error: %s", err)
+	}
+	sink := &recordingSink{}
+	controller.SetEventSink(sink)
+
+	t.Run("signs with the issuer secret", func(t *testing.T) {
+		if actual := controller.getCA().TrustAnchorPEM(); actual != trustAnchor {
+			t.Fatalf("Expected trust anchor:\n%s\ngot:\n%s", trustAnchor, actual)
+		}
+	})
+
+	t.Run("reloads the issuer secret when it's rotated", func(t *testing.T) {
+		rotated, rotatedTrustAnchor := issuerSecret("2")
+		controller.handleIssuerSecretUpdate(secret, rotated)
+
+		if actual := controller.getCA().TrustAnchorPEM(); actual != rotatedTrustAnchor {
+			t.Fatalf("Expected trust anchor:\n%s\ngot:\n%s", rotatedTrustAnchor, actual)
+		}
+
+		if len(sink.events) != 1 {
+			t.Fatalf("Expected 1 event, got %+v", sink.events)
+		}
+		event := sink.events[0]
+		notAfter := controller.getCA().root.NotAfter.UTC().Format(time.RFC3339)
+		if event.Type != events.CertificateRotated || event.Attributes["issuerSecret"] != "linkerd-issuer" || event.Attributes["notAfter"] != notAfter {
+			t.Fatalf("Unexpected event: %+v", event)
+		}
+	})
+
+	t.Run("keeps the previous issuer when the secret is invalid", func(t *testing.T) {
+		before := controller.getCA()
+		invalid, _ := issuerSecret("3")
+		invalid.Data[v1.TLSPrivateKeyKey] = []byte("not a key")
+		controller.handleIssuerSecretUpdate(secret, invalid)
+
+		if controller.getCA() != before {
+			t.Fatal("Expected the invalid issuer secret to be ignored")
+		}
+		if len(sink.events) != 1 {
+			t.Fatalf("Expected no event for the invalid issuer secret, got %+v", sink.events)
+		}
+	})
+}
+
+type recordingSink struct {
+	events []events.Event
+}
+
+func (s *recordingSink) Post(event events.Event) {
+	s.events = append(s.events, event)
+}
+
+func TestCertificateControllerIssuanceLifetime(t *testing.T) {
+	testCases := []struct {
+		title       string
+		annotation  string
+		maxNotAfter time.Duration
+	}{
+		{"defaults to the CA's validity", "", 400 * 24 * time.Hour},
+		{"honors the requested lifetime", "2h", 5 * time.Hour},
+		{"ignores lifetimes that are too short", "10m", 400 * 24 * time.Hour},
+		{"ignores invalid lifetimes", "forever", 400 * 24 * time.Hour},
+	}
+
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			k8sAPI, err := k8s.NewFakeAPI("", injectedNSConfig)
+			if err != nil {
+				t.Fatalf("NewFakeAPI returned an error: %s", err)
+			}
+			controller, err := NewCertificateController(controllerNS, k8sAPI, false, "")
+			if err != nil {
+				t.Fatalf("NewCertificateController returned an error: %s", err)
+			}
+
+			pod := &v1.Pod{
+				ObjectMeta: meta.ObjectMeta{
+					Name:        fmt.Sprintf("job-%d", i),
+					Namespace:   injectedNS,
+					Labels:      map[string]string{pkgK8s.ControllerNSLabel: controllerNS},
+					Annotations: map[string]string{},
+				},
+			}
+			if tc.annotation != "" {
+				pod.Annotations[pkgK8s.IdentityIssuanceLifetimeAnnotation] = tc.annotation
+			}
+			controller.handlePodAdd(pod)
+
+			item := fmt.Sprintf("%s.pod.%s", pod.Name, injectedNS)
+			if err := controller.syncSecret(item); err != nil {
+				t.Fatalf("syncSecret returned an error: %s", err)
+			}
+
+			identity := pkgK8s.TLSIdentity{Name: pod.Name, Kind: "pod", Namespace: injectedNS}
+			secret, err := k8sAPI.Client.CoreV1().Secrets(injectedNS).Get(identity.ToSecretName(), meta.GetOptions{})
+			if err != nil {
+				t.Fatalf("Expected the secret to be created: %s", err)
+			}
+			cert, err := x509.ParseCertificate(secret.Data[pkgK8s.TLSCertFileName])
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			if cert.NotAfter.After(time.Now().Add(tc.maxNotAfter)) {
+				t.Fatalf("Expected the certificate to expire within %s, got %s", tc.maxNotAfter, cert.NotAfter)
+			}
+			if tc.maxNotAfter > 24*time.Hour && cert.NotAfter.Before(time.Now().Add(300*24*time.Hour)) {
+				t.Fatalf("Expected the certificate to be valid for the CA's default, got %s", cert.NotAfter)
+			}
+		})
+	}
+}
+
+func TestCertificateControllerIdentityAlias(t *testing.T) {
+	legacyNSConfig := fmt.Sprintf(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: legacy
+  annotations:
+    %s: other, %s`, pkgK8s.IdentityAliasNamespacesAnnotation, injectedNS)
+	privateNSConfig := `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: private`
+
+	future := time.Now().Add(48 * time.Hour).Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+
+	testCases := []struct {
+		title    string
+		alias    string
+		expiry   string
+		dnsNames []string
+	}{
+		{
+			title:    "includes aliases in the same namespace",
+			alias:    "web.deployment." + injectedNS,
+			expiry:   future,
+			dnsNames: []string{"web.deployment." + injectedNS + ".linkerd-managed." + controllerNS + ".svc.cluster.local"},
+		},
+		{
+			title:    "includes aliases that the legacy namespace allows",
+			alias:    "web.deployment.legacy",
+			expiry:   future,
+			dnsNames: []string{"web.deployment.legacy.linkerd-managed." + controllerNS + ".svc.cluster.local"},
+		},
+		{
+			title:  "ignores aliases that the legacy namespace doesn't allow",
+			alias:  "web.deployment.private",
+			expiry: future,
+		},
+		{
+			title:  "ignores aliases in the control plane namespace",
+			alias:  "controller.deployment." + controllerNS,
+			expiry: future,
+		},
+		{
+			title:  "ignores expired aliases",
+			alias:  "web.deployment.legacy",
+			expiry: past,
+		},
+		{
+			title: "ignores aliases without an expiry",
+			alias: "web.deployment.legacy",
+		},
+		{
+			title:  "ignores invalid aliases",
+			alias:  "web.legacy",
+			expiry: future,
+		},
+	}
+
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			k8sAPI, err := k8s.NewFakeAPI("", injectedNSConfig, legacyNSConfig, privateNSConfig)
+			if err != nil {
+				t.Fatalf("NewFakeAPI returned an error: %s", err)
+			}
+			controller, err := NewCertificateController(controllerNS, k8sAPI, false, "")
+			if err != nil {
+				t.Fatalf("NewCertificateController returned an error: %s", err)
+			}
+
+			pod := &v1.Pod{
+				ObjectMeta: meta.ObjectMeta{
+					Name:      fmt.Sprintf("job-%d", i),
+					Namespace: injectedNS,
+					Labels:    map[string]string{pkgK8s.ControllerNSLabel: controllerNS},
+					Annotations: map[string]string{
+						pkgK8s.IdentityAliasAnnotation: tc.alias,
+					},
+				},
+			}
+			if tc.expiry != "" {
+				pod.Annotations[pkgK8s.IdentityAliasExpiryAnnotation] = tc.expiry
+			}
+			controller.handlePodAdd(pod)
+
+			item := fmt.Sprintf("%s.pod.%s", pod.Name, injectedNS)
+			if err := controller.syncSecret(item); err != nil {
+				t.Fatalf("syncSecret returned an error: %s", err)
+			}
+
+			identity := pkgK8s.TLSIdentity{Name: pod.Name, Kind: "pod", Namespace: injectedNS, ControllerNamespace: controllerNS}
+			secret, err := k8sAPI.Client.CoreV1().Secrets(injectedNS).Get(identity.ToSecretName(), meta.GetOptions{})
+			if err != nil {
+				t.Fatalf("Expected the secret to be created: %s", err)
+			}
+			cert, err := x509.ParseCertificate(secret.Data[pkgK8s.TLSCertFileName])
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			expected := append([]string{identity.ToDNSName()}, tc.dnsNames...)
+			if !reflect.DeepEqual(cert.DNSNames, expected) {
+				t.Fatalf("Expected DNS names %v, got %v", expected, cert.DNSNames)
+			}
+			if len(tc.dnsNames) > 0 && cert.NotAfter.After(time.Now().Add(61*time.Hour)) {
+				t.Fatalf("Expected the certificate to expire with the alias, got %s", cert.NotAfter)
+			}
+		})
+	}
+}
+
+func TestCertificateControllerAPIClient(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("")
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+	controller, err := NewCertificateController(controllerNS, k8sAPI, false, "")
+	if err != nil {
+		t.Fatalf("NewCertificateController returned an error: %s", err)
+	}
+	controller.IssueAPIClientCertificate()
+
+	controller.enqueueAPIClient()
+	item, _ := controller.queue.Get()
+	if err := controller.syncSecret(item.(string)); err != nil {
+		t.Fatalf("syncSecret returned an error: %s", err)
+	}
+
+	identity := pkgK8s.TLSIdentity{ControllerNamespace: controllerNS}.ToAPIClientIdentity()
+	secret, err := k8sAPI.Client.CoreV1().Secrets(controllerNS).Get(identity.ToSecretName(), meta.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected the secret to be created: %s", err)
+	}
+	cert, err := x509.ParseCertificate(secret.Data[pkgK8s.TLSCertFileName])
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(cert.DNSNames, []string{identity.ToDNSName()}) {
+		t.Fatalf("Expected DNS names %v, got %v", []string{identity.ToDNSName()}, cert.DNSNames)
+	}
+}
+
 func new(fixtures ...string) (*CertificateController, chan bool, chan struct{}, error) {
 	k8sAPI, err := k8s.NewFakeAPI("", fixtures...)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("NewFakeAPI returned an error: %s", err)
 	}
 
-	controller, err := NewCertificateController(controllerNS, k8sAPI, false)
+	controller, err := NewCertificateController(controllerNS, k8sAPI, false, "")
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("NewCertificateController returned an error: %s", err)
 	}
@@ -0,0 +1,90 @@
+package ca
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Reasons for which the CA rejects certificate requests, used as the values of
+// the reason label of the ca_certificates_rejected_total counter.
+const (
+	rejectInvalidRequest = "invalid_request"
+	rejectIssuanceError  = "issuance_error"
+
+	// the requests that exceed the issuance rate limits are retried later
+	rejectOwnerRateLimited  = "owner_rate_limited"
+	rejectGlobalRateLimited = "global_rate_limited"
+)
+
+// metrics are the Prometheus metrics of a CertificateController, so that
+// operators can alert before its certificates lapse.
+type metrics struct {
+	issuerExpiry      prometheus.GaugeFunc
+	trustAnchorExpiry prometheus.GaugeFunc
+	requests          prometheus.Counter
+	issued            prometheus.Counter
+	rejected          *prometheus.CounterVec
+	issuanceDuration  prometheus.Histogram
+
+	// started is when the counters started counting, to report issuance rates
+	started time.Time
+}
+
+func newMetrics(c *CertificateController) *metrics {
+	// the issuer and trust anchor are currently the same certificate, but
+	// they're exported separately so that alerts don't have to change if the
+	// CA ever signs with an intermediate issuer
+	return &metrics{
+		issuerExpiry: prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Name: "ca_issuer_expiry_timestamp_seconds",
+				Help: "The time at which the certificate that the CA signs certificates with expires, in seconds since the epoch.",
+			},
+			func() float64 { return float64(c.getCA().root.NotAfter.Unix()) },
+		),
+		trustAnchorExpiry: prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Name: "ca_trust_anchor_expiry_timestamp_seconds",
+				Help: "The time at which the trust anchor that the proxies verify certificates with expires, in seconds since the epoch.",
+			},
+			func() float64 { return float64(c.getCA().root.NotAfter.Unix()) },
+		),
+		requests: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "ca_certificate_requests_total",
+				Help: "The number of certificate requests that the CA processed, whether it fulfilled them or not.",
+			},
+		),
+		issued: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "ca_certificates_issued_total",
+				Help: "The number of certificates issued by the CA.",
+			},
+		),
+		rejected: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "ca_certificates_rejected_total",
+				Help: "The number of certificate requests that the CA failed to fulfill or deferred, by reason.",
+			},
+			[]string{"reason"},
+		),
+		issuanceDuration: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "ca_certificate_issuance_duration_seconds",
+				Help:    "How long the CA took to generate and sign a certificate.",
+				Buckets: []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+			},
+		),
+		started: time.Now(),
+	}
+}
+
+func (m *metrics) register(registerer prometheus.Registerer) error {
+	for _, collector := range []prometheus.Collector{m.issuerExpiry, m.trustAnchorExpiry, m.requests, m.issued, m.rejected, m.issuanceDuration} {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
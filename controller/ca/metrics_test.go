@@ -0,0 +1,68 @@
+package ca
+
+import (
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func gatherMetrics(t *testing.T, registry *prometheus.Registry) map[string]float64 {
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			name := family.GetName()
+			for _, label := range metric.GetLabel() {
+				name += "/" + label.GetValue()
+			}
+			switch {
+			case metric.Gauge != nil:
+				values[name] = metric.GetGauge().GetValue()
+			case metric.Counter != nil:
+				values[name] = metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return values
+}
+
+func TestCertificateControllerMetrics(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("", injectedNSConfig)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+	controller, err := NewCertificateController(controllerNS, k8sAPI, false, "")
+	if err != nil {
+		t.Fatalf("NewCertificateController returned an error: %s", err)
+	}
+	registry := prometheus.NewRegistry()
+	if err := controller.RegisterMetrics(registry); err != nil {
+		t.Fatalf("RegisterMetrics returned an error: %s", err)
+	}
+
+	if err := controller.syncSecret("web.deployment." + injectedNS); err != nil {
+		t.Fatalf("syncSecret returned an error: %s", err)
+	}
+	if err := controller.syncSecret("invalid"); err != nil {
+		t.Fatalf("syncSecret returned an error: %s", err)
+	}
+
+	values := gatherMetrics(t, registry)
+	expiry := float64(controller.getCA().root.NotAfter.Unix())
+	expected := map[string]float64{
+		"ca_issuer_expiry_timestamp_seconds":                     expiry,
+		"ca_trust_anchor_expiry_timestamp_seconds":               expiry,
+		"ca_certificates_issued_total":                           1,
+		"ca_certificates_rejected_total/" + rejectInvalidRequest: 1,
+	}
+	for name, value := range expected {
+		if values[name] != value {
+			t.Errorf("Expected %s to be %f, got %f", name, value, values[name])
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package ca
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// defaultIssuanceRate and defaultIssuanceBurst limit how many certificates
+	// the CA issues per second across all the pod owners.
+	defaultIssuanceRate  = 20
+	defaultIssuanceBurst = 100
+
+	// defaultOwnerIssuanceInterval and defaultOwnerIssuanceBurst limit how
+	// often the certificates of a single pod owner are reissued, so that a
+	// crash-looping deployment with many replicas, whose pods are updated all
+	// the time, can't starve the rest of the mesh.
+	defaultOwnerIssuanceInterval = 30 * time.Second
+	defaultOwnerIssuanceBurst    = 3
+)
+
+// issuanceLimiter rate limits the issuance of certificates, both globally and
+// for each pod owner.
+type issuanceLimiter struct {
+	global *rate.Limiter
+
+	ownerLimit rate.Limit
+	ownerBurst int
+
+	// owners are the limiters of the pod owners, keyed like the queue's
+	// secret items. The limiters that have been idle long enough to refill
+	// are pruned at nextPrune, since they're then the same as new ones.
+	owners    map[string]*ownerLimiter
+	refill    time.Duration
+	nextPrune time.Time
+	mu        sync.Mutex
+}
+
+type ownerLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// newIssuanceLimiter returns an issuanceLimiter that allows issuanceRate
+// certificates per second with bursts of issuanceBurst, and a certificate per
+// ownerInterval for each pod owner with bursts of ownerBurst. A zero rate or
+// interval disables the corresponding limit.
+func newIssuanceLimiter(issuanceRate float64, issuanceBurst int, ownerInterval time.Duration, ownerBurst int) *issuanceLimiter {
+	globalLimit := rate.Inf
+	if issuanceRate > 0 {
+		globalLimit = rate.Limit(issuanceRate)
+	}
+	return &issuanceLimiter{
+		global:     rate.NewLimiter(globalLimit, issuanceBurst),
+		ownerLimit: rate.Every(ownerInterval),
+		ownerBurst: ownerBurst,
+		owners:     make(map[string]*ownerLimiter),
+		refill:     ownerInterval * time.Duration(ownerBurst),
+	}
+}
+
+// reserve reserves the issuance of a certificate for the pod owner at now. It
+// returns zero if the certificate can be issued right away, or otherwise how
+// long to wait before retrying, along with the reason to report in the
+// ca_certificates_rejected_total counter. Nothing is reserved in that case.
+func (l *issuanceLimiter) reserve(item string, now time.Time) (time.Duration, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune(now)
+	owner, ok := l.owners[item]
+	if !ok {
+		owner = &ownerLimiter{limiter: rate.NewLimiter(l.ownerLimit, l.ownerBurst)}
+		l.owners[item] = owner
+	}
+	owner.lastSeen = now
+
+	ownerReservation := owner.limiter.ReserveN(now, 1)
+	if delay := ownerReservation.DelayFrom(now); delay > 0 {
+		ownerReservation.CancelAt(now)
+		return delay, rejectOwnerRateLimited
+	}
+
+	globalReservation := l.global.ReserveN(now, 1)
+	if delay := globalReservation.DelayFrom(now); delay > 0 {
+		globalReservation.CancelAt(now)
+		ownerReservation.CancelAt(now)
+		return delay, rejectGlobalRateLimited
+	}
+
+	return 0, ""
+}
+
+// prune removes the owner limiters that have been idle for long enough to be
+// full again.
+func (l *issuanceLimiter) prune(now time.Time) {
+	if now.Before(l.nextPrune) {
+		return
+	}
+	for item, owner := range l.owners {
+		if now.Sub(owner.lastSeen) >= l.refill {
+			delete(l.owners, item)
+		}
+	}
+	l.nextPrune = now.Add(l.refill)
+}
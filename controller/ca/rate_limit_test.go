@@ -0,0 +1,79 @@
+package ca
+
+import (
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+)
+
+func TestIssuanceLimiter(t *testing.T) {
+	limiter := newIssuanceLimiter(2, 2, time.Minute, 1)
+	now := time.Now()
+
+	testCases := []struct {
+		item   string
+		at     time.Duration
+		delay  time.Duration
+		reason string
+	}{
+		{"web.deployment.emojivoto", 0, 0, ""},
+		{"web.deployment.emojivoto", 0, time.Minute, rejectOwnerRateLimited},
+		{"voting.deployment.emojivoto", 0, 0, ""},
+		{"emoji.deployment.emojivoto", 0, 500 * time.Millisecond, rejectGlobalRateLimited},
+		{"emoji.deployment.emojivoto", 500 * time.Millisecond, 0, ""},
+		{"web.deployment.emojivoto", time.Minute, 0, ""},
+	}
+
+	for i, tc := range testCases {
+		delay, reason := limiter.reserve(tc.item, now.Add(tc.at))
+		if delay != tc.delay || reason != tc.reason {
+			t.Fatalf("%d: Expected reserve(%s) to return (%s, %q), got (%s, %q)", i, tc.item, tc.delay, tc.reason, delay, reason)
+		}
+	}
+
+	t.Run("Prunes the idle owners", func(t *testing.T) {
+		limiter.reserve("web.deployment.emojivoto", now.Add(3*time.Minute))
+		if len(limiter.owners) != 1 {
+			t.Fatalf("Expected 1 owner limiter, got %d", len(limiter.owners))
+		}
+	})
+
+	t.Run("Disables the limits", func(t *testing.T) {
+		limiter := newIssuanceLimiter(0, 1, 0, 1)
+		for i := 0; i < 10; i++ {
+			if delay, reason := limiter.reserve("web.deployment.emojivoto", now); delay != 0 {
+				t.Fatalf("Expected no delay, got %s (%s)", delay, reason)
+			}
+		}
+	})
+}
+
+func TestCertificateControllerIssuanceRateLimit(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("", injectedNSConfig)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+	controller, err := NewCertificateController(controllerNS, k8sAPI, false, "")
+	if err != nil {
+		t.Fatalf("NewCertificateController returned an error: %s", err)
+	}
+	controller.LimitIssuance(0, 1, time.Hour, 1)
+
+	item := "web.deployment." + injectedNS
+	for i := 0; i < 3; i++ {
+		if err := controller.syncSecret(item); err != nil {
+			t.Fatalf("syncSecret returned an error: %s", err)
+		}
+	}
+
+	if requests := counterValue(controller.metrics.requests); requests != 3 {
+		t.Fatalf("Expected 3 certificate requests, got %f", requests)
+	}
+	if issued := counterValue(controller.metrics.issued); issued != 1 {
+		t.Fatalf("Expected 1 issued certificate, got %f", issued)
+	}
+	if deferred := counterValue(controller.metrics.rejected.WithLabelValues(rejectOwnerRateLimited)); deferred != 2 {
+		t.Fatalf("Expected 2 deferred certificates, got %f", deferred)
+	}
+}
@@ -0,0 +1,59 @@
+package ca
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// caState is a snapshot of the work of a CertificateController, which the
+// admin server reports on its /state endpoint.
+type caState struct {
+	Issued          float64            `json:"issued"`
+	IssuedPerMinute float64            `json:"issuedPerMinute"`
+	Rejected        map[string]float64 `json:"rejected"`
+	QueueDepth      int                `json:"queueDepth"`
+	Issuances       int                `json:"issuances"`
+	IssuerExpiry    time.Time          `json:"issuerExpiry"`
+	UptimeSeconds   float64            `json:"uptimeSeconds"`
+}
+
+// State returns the number of certificates that the CertificateController
+// issued and rejected, its average issuance rate since it started, and the
+// depth of its queue, for the admin server's /state endpoint.
+func (c *CertificateController) State() interface{} {
+	return c.state(time.Now())
+}
+
+func (c *CertificateController) state(now time.Time) caState {
+	uptime := now.Sub(c.metrics.started)
+
+	state := caState{
+		Issued:        counterValue(c.metrics.issued),
+		Rejected:      map[string]float64{},
+		QueueDepth:    c.queue.Len(),
+		IssuerExpiry:  c.getCA().root.NotAfter.UTC(),
+		UptimeSeconds: uptime.Seconds(),
+	}
+	for _, reason := range []string{rejectInvalidRequest, rejectIssuanceError, rejectOwnerRateLimited, rejectGlobalRateLimited} {
+		state.Rejected[reason] = counterValue(c.metrics.rejected.WithLabelValues(reason))
+	}
+	if uptime > 0 {
+		state.IssuedPerMinute = state.Issued / uptime.Minutes()
+	}
+
+	c.issuancesMu.Lock()
+	state.Issuances = len(c.issuances)
+	c.issuancesMu.Unlock()
+
+	return state
+}
+
+func counterValue(counter prometheus.Counter) float64 {
+	m := &dto.Metric{}
+	if err := counter.Write(m); err != nil {
+		return 0
+	}
+	return m.GetCounter().GetValue()
+}
@@ -0,0 +1,46 @@
+package ca
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+)
+
+func TestCertificateControllerState(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("", injectedNSConfig)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+	controller, err := NewCertificateController(controllerNS, k8sAPI, false, "")
+	if err != nil {
+		t.Fatalf("NewCertificateController returned an error: %s", err)
+	}
+
+	for _, item := range []string{"web.deployment." + injectedNS, "voting.deployment." + injectedNS, "invalid"} {
+		if err := controller.syncSecret(item); err != nil {
+			t.Fatalf("syncSecret returned an error: %s", err)
+		}
+	}
+	controller.queue.Add(injectedNS)
+
+	state := controller.state(controller.metrics.started.Add(4 * time.Minute))
+	expected := caState{
+		Issued:          2,
+		IssuedPerMinute: 0.5,
+		Rejected: map[string]float64{
+			rejectInvalidRequest:    1,
+			rejectIssuanceError:     0,
+			rejectOwnerRateLimited:  0,
+			rejectGlobalRateLimited: 0,
+		},
+		QueueDepth:    1,
+		Issuances:     0,
+		IssuerExpiry:  controller.getCA().root.NotAfter.UTC(),
+		UptimeSeconds: 240,
+	}
+	if !reflect.DeepEqual(state, expected) {
+		t.Fatalf("Expected state %+v, got %+v", expected, state)
+	}
+}
@@ -0,0 +1,261 @@
+package ca
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultServiceAccountTokenPath is where the token of the CA's service
+	// account is mounted, which the CA logs into Vault with.
+	defaultServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	// vaultTokenRenewalMargin is how long before its lease expires that a
+	// Vault token is replaced, so that no request is made with an expired
+	// token.
+	vaultTokenRenewalMargin = time.Minute
+
+	vaultRequestTimeout = 10 * time.Second
+)
+
+// VaultConfig configures a CA that forwards the certificate signing requests
+// of the pods to the PKI secrets engine of HashiCorp Vault, instead of signing
+// them itself.
+type VaultConfig struct {
+	// Addr is the address of the Vault server, such as
+	// "https://vault.vault.svc.cluster.local:8200".
+	Addr string
+
+	// PKIPath is the path where the PKI secrets engine is mounted, such as
+	// "pki".
+	PKIPath string
+
+	// Role is the PKI role that signs the certificates. It must allow the
+	// DNS names of the pod identities as common names and subject alternative
+	// names.
+	Role string
+
+	// AuthPath is the path where the Kubernetes auth method is mounted, such
+	// as "kubernetes".
+	AuthPath string
+
+	// AuthRole is the role of the Kubernetes auth method that the CA logs in
+	// with its service account token.
+	AuthRole string
+
+	// TokenPath is the path of the service account token, which defaults to
+	// the token mounted in the CA's pod.
+	TokenPath string
+}
+
+// vaultSigner signs certificate signing requests with the PKI secrets engine
+// of Vault, logging in with the Kubernetes auth method.
+type vaultSigner struct {
+	config VaultConfig
+	client *http.Client
+
+	// token is the Vault token of the CA, which is replaced when it expires,
+	// so it's only accessed with tokenMu held.
+	token       string
+	tokenExpiry time.Time
+	tokenMu     sync.Mutex
+}
+
+// NewVaultCA creates a CA that forwards the certificate signing requests to
+// Vault. The certificate of the PKI secrets engine is used as the trust
+// anchor, since the proxy secrets only hold the leaf certificate and not a
+// chain, so the engine must issue the certificates directly.
+func NewVaultCA(config VaultConfig) (*CA, error) {
+	if config.Addr == "" || config.PKIPath == "" || config.Role == "" || config.AuthPath == "" || config.AuthRole == "" {
+		return nil, errors.New("the Vault address, PKI path, PKI role, auth path and auth role must be set")
+	}
+	if config.TokenPath == "" {
+		config.TokenPath = defaultServiceAccountTokenPath
+	}
+
+	signer := &vaultSigner{
+		config: config,
+		client: &http.Client{Timeout: vaultRequestTimeout},
+	}
+
+	// the CA certificate can be read without a token
+	rsp, err := signer.client.Get(signer.url(config.PKIPath, "ca", "pem"))
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	certPEM, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read the CA certificate of %s: %s", signer.url(config.PKIPath), rsp.Status)
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM certificate found at %s", signer.url(config.PKIPath, "ca", "pem"))
+	}
+	root, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CA{
+		validity:           (24 * 365) * time.Hour,
+		clockSkewAllocance: 12 * time.Hour,
+		root:               root,
+		rootPEM:            string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Raw})),
+		vault:              signer,
+	}, nil
+}
+
+// UseVault makes the controller forward the certificate signing requests to
+// Vault, instead of signing them with its own credentials.
+func (c *CertificateController) UseVault(config VaultConfig) error {
+	ca, err := NewVaultCA(config)
+	if err != nil {
+		return err
+	}
+	c.setCA(ca)
+	return nil
+}
+
+func (s *vaultSigner) url(path ...string) string {
+	return strings.TrimSuffix(s.config.Addr, "/") + "/v1/" + strings.Join(path, "/")
+}
+
+// sign creates a certificate signing request for the private key and the
+// DNS names, and has Vault sign it. It returns the DER-encoded certificate.
+func (s *vaultSigner) sign(privateKey *ecdsa.PrivateKey, dnsNames []string, validity time.Duration) ([]byte, error) {
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:            pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:           dnsNames,
+		SignatureAlgorithm: x509.ECDSAWithSHA256,
+	}, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	request := map[string]string{
+		"csr":         string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})),
+		"common_name": dnsNames[0],
+		"alt_names":   strings.Join(dnsNames, ","),
+		"ttl":         validity.String(),
+		"format":      "pem",
+	}
+	var response struct {
+		Data struct {
+			Certificate string `json:"certificate"`
+		} `json:"data"`
+	}
+	if err := s.do(s.url(s.config.PKIPath, "sign", s.config.Role), request, &response, true); err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode([]byte(response.Data.Certificate))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("Vault didn't return a PEM certificate")
+	}
+	return block.Bytes, nil
+}
+
+// login logs into Vault with the service account token, unless the current
+// Vault token is still valid, and returns the Vault token.
+func (s *vaultSigner) login() (string, error) {
+	s.tokenMu.Lock()
+	defer s.tokenMu.Unlock()
+
+	if s.token != "" && time.Now().Add(vaultTokenRenewalMargin).Before(s.tokenExpiry) {
+		return s.token, nil
+	}
+
+	jwt, err := ioutil.ReadFile(s.config.TokenPath)
+	if err != nil {
+		return "", err
+	}
+	request := map[string]string{
+		"role": s.config.AuthRole,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	}
+	var response struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int64  `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := s.do(s.url("auth", s.config.AuthPath, "login"), request, &response, false); err != nil {
+		return "", fmt.Errorf("failed to log into Vault: %s", err)
+	}
+	if response.Auth.ClientToken == "" {
+		return "", errors.New("failed to log into Vault: no client token returned")
+	}
+
+	s.token = response.Auth.ClientToken
+	s.tokenExpiry = time.Now().Add(time.Duration(response.Auth.LeaseDuration) * time.Second)
+	return s.token, nil
+}
+
+// do posts the JSON request to url, authenticated with the Vault token if
+// authenticated is set, and decodes the JSON response. A token that Vault
+// rejects is discarded, so that the CA logs in again on the next request.
+func (s *vaultSigner) do(url string, request, response interface{}, authenticated bool) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var token string
+	if authenticated {
+		if token, err = s.login(); err != nil {
+			return err
+		}
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	rsp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		json.NewDecoder(rsp.Body).Decode(&vaultErr)
+		if authenticated && rsp.StatusCode == http.StatusForbidden {
+			s.discardToken(token)
+		}
+		if len(vaultErr.Errors) > 0 {
+			return fmt.Errorf("%s: %s", rsp.Status, strings.Join(vaultErr.Errors, "; "))
+		}
+		return errors.New(rsp.Status)
+	}
+	return json.NewDecoder(rsp.Body).Decode(response)
+}
+
+func (s *vaultSigner) discardToken(token string) {
+	s.tokenMu.Lock()
+	defer s.tokenMu.Unlock()
+	if s.token == token {
+		s.token = ""
+	}
+}
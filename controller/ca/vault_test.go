@@ -0,0 +1,161 @@
+package ca
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// fakeVault serves the PKI secrets engine and Kubernetes auth method
+// endpoints of Vault, signing with a CA of its own.
+type fakeVault struct {
+	ca       *CA
+	logins   int
+	rejected bool
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/v1/pki/ca/pem":
+		w.Write([]byte(v.ca.TrustAnchorPEM()))
+
+	case "/v1/auth/kubernetes/login":
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["role"] != "linkerd-ca" || req["jwt"] != "service-account-token" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusBadRequest)
+			return
+		}
+		v.logins++
+		w.Write([]byte(`{"auth":{"client_token":"vault-token","lease_duration":3600}}`))
+
+	case "/v1/pki/sign/linkerd":
+		if r.Header.Get("X-Vault-Token") != "vault-token" || v.rejected {
+			v.rejected = false
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		block, _ := pem.Decode([]byte(req["csr"]))
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		template := x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(time.Hour),
+			DNSNames:     csr.DNSNames,
+		}
+		crt, err := x509.CreateCertificate(rand.Reader, &template, v.ca.root, csr.PublicKey, v.ca.privateKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rsp, _ := json.Marshal(map[string]interface{}{
+			"data": map[string]string{
+				"certificate": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: crt})),
+			},
+		})
+		w.Write(rsp)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestNewVaultCA(t *testing.T) {
+	issuer, err := NewCA()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	vault := &fakeVault{ca: issuer}
+	server := httptest.NewServer(vault)
+	defer server.Close()
+
+	tokenFile, err := ioutil.TempFile("", "linkerd-ca-token")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.Remove(tokenFile.Name())
+	tokenFile.WriteString("service-account-token\n")
+	tokenFile.Close()
+
+	config := VaultConfig{
+		Addr:      server.URL,
+		PKIPath:   "pki",
+		Role:      "linkerd",
+		AuthPath:  "kubernetes",
+		AuthRole:  "linkerd-ca",
+		TokenPath: tokenFile.Name(),
+	}
+	ca, err := NewVaultCA(config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if ca.TrustAnchorPEM() != issuer.TrustAnchorPEM() {
+		t.Fatalf("Expected the trust anchor to be the certificate of the PKI secrets engine")
+	}
+
+	dnsName := "web.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local"
+	issue := func() {
+		issued, err := ca.IssueEndEntityCertificate(dnsName, 0)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		cert, err := x509.ParseCertificate(issued.Certificate)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		roots := x509.NewCertPool()
+		roots.AppendCertsFromPEM([]byte(ca.TrustAnchorPEM()))
+		if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: dnsName}); err != nil {
+			t.Fatalf("Expected the certificate to be verified by the trust anchor: %s", err)
+		}
+		if _, err := x509.ParsePKCS8PrivateKey(issued.PrivateKey); err != nil {
+			t.Fatalf("Expected a PKCS#8 private key: %s", err)
+		}
+	}
+
+	t.Run("issues certificates signed by Vault", func(t *testing.T) {
+		issue()
+		issue()
+		if vault.logins != 1 {
+			t.Fatalf("Expected the Vault token to be reused, got %d logins", vault.logins)
+		}
+	})
+
+	t.Run("logs in again when the token is rejected", func(t *testing.T) {
+		vault.rejected = true
+		if _, err := ca.IssueEndEntityCertificate(dnsName, 0); err == nil || err.Error() != "403 Forbidden: permission denied" {
+			t.Fatalf("Expected the rejection of the token, got %v", err)
+		}
+		issue()
+		if vault.logins != 2 {
+			t.Fatalf("Expected a new login, got %d logins", vault.logins)
+		}
+	})
+
+	t.Run("reports failed logins", func(t *testing.T) {
+		config := config
+		config.AuthRole = "web"
+		ca, err := NewVaultCA(config)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if _, err := ca.IssueEndEntityCertificate(dnsName, 0); err == nil || err.Error() != "failed to log into Vault: 400 Bad Request: permission denied" {
+			t.Fatalf("Expected a login error, got %v", err)
+		}
+	})
+}
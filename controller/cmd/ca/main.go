@@ -5,22 +5,56 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/linkerd/linkerd2/controller/ca"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/admin"
+	"github.com/linkerd/linkerd2/pkg/events"
 	"github.com/linkerd/linkerd2/pkg/flags"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 )
 
+// caLeaderLock is the config map that the replicas of the CA hold a lease on,
+// when leader election is enabled.
+const caLeaderLock = "linkerd-ca-leader"
+
 func main() {
 	metricsAddr := flag.String("metrics-addr", ":9997", "address to serve scrapable metrics on")
 	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
 	singleNamespace := flag.Bool("single-namespace", false, "only operate in the controller namespace")
 	kubeConfigPath := flag.String("kubeconfig", "", "path to kube config")
 	proxyAutoInject := flag.Bool("proxy-auto-inject", false, "if true, watch for the add and update events of mutating webhook configurations")
+	leaderElection := flag.Bool("enable-leader-election", false, "only sign certificates in the replica that holds the linkerd-ca-leader lease, so that several replicas can be run with the same issuer secret")
+	issuerSecret := flag.String("issuer-secret", "", "name of a kubernetes.io/tls secret in the controller namespace with the CA certificate and key to sign certificates with; the CA generates its own if empty")
+	vaultAddr := flag.String("vault-addr", "", "address of a Vault server whose PKI secrets engine signs the certificates, instead of the CA; the CA logs in with the Kubernetes auth method")
+	vaultPKIPath := flag.String("vault-pki-path", "pki", "path where the PKI secrets engine is mounted in Vault")
+	vaultRole := flag.String("vault-role", "", "PKI role that signs the certificates in Vault")
+	vaultAuthPath := flag.String("vault-auth-path", "kubernetes", "path where the Kubernetes auth method is mounted in Vault")
+	vaultAuthRole := flag.String("vault-auth-role", "", "role of the Kubernetes auth method that the CA logs into Vault with")
+	issuanceRate := flag.Float64("issuance-rate", 20, "maximum number of certificates issued per second across all pod owners, or 0 for no limit")
+	issuanceBurst := flag.Int("issuance-burst", 100, "maximum number of certificates issued at once across all pod owners")
+	ownerIssuanceInterval := flag.Duration("owner-issuance-interval", 30*time.Second, "minimum interval between the certificates issued for each pod owner, or 0 for no limit")
+	ownerIssuanceBurst := flag.Int("owner-issuance-burst", 3, "maximum number of certificates issued at once for each pod owner")
+	eventWebhookURL := flag.String("event-webhook-url", "", "URL to post the CertificateRotated events to as JSON (disabled if empty)")
+	issueAPIClientCert := flag.Bool("issue-api-client-cert", false, "also issue the certificate that the clients of the public API authenticate with to its TLS port")
 	flags.ConfigureAndParse()
 
+	if *vaultAddr != "" && *issuerSecret != "" {
+		log.Fatal("-vault-addr and -issuer-secret can't both be set")
+	}
+
+	// replicas that generate their own credentials would each sign with a
+	// different CA
+	if *leaderElection && *issuerSecret == "" && *vaultAddr == "" {
+		log.Fatal("-enable-leader-election requires -issuer-secret or -vault-addr")
+	}
+
+	if *issuanceBurst < 1 || *ownerIssuanceBurst < 1 {
+		log.Fatal("-issuance-burst and -owner-issuance-burst must be at least 1")
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
@@ -41,19 +75,61 @@ func main() {
 		k8sAPI = k8s.NewAPI(k8sClient, nil, restrictToNamespace, k8s.Pod, k8s.RS)
 	}
 
-	controller, err := ca.NewCertificateController(*controllerNamespace, k8sAPI, *proxyAutoInject)
+	// before the workqueues are created
+	if err := k8s.RegisterWorkqueueMetrics(prometheus.DefaultRegisterer); err != nil {
+		log.Fatalf("Failed to register workqueue metrics: %v", err)
+	}
+	controller, err := ca.NewCertificateController(*controllerNamespace, k8sAPI, *proxyAutoInject, *issuerSecret)
 	if err != nil {
 		log.Fatalf("Failed to create CertificateController: %v", err)
 	}
+	if *vaultAddr != "" {
+		err := controller.UseVault(ca.VaultConfig{
+			Addr:     *vaultAddr,
+			PKIPath:  *vaultPKIPath,
+			Role:     *vaultRole,
+			AuthPath: *vaultAuthPath,
+			AuthRole: *vaultAuthRole,
+		})
+		if err != nil {
+			log.Fatalf("Failed to use Vault: %v", err)
+		}
+	}
+	controller.LimitIssuance(*issuanceRate, *issuanceBurst, *ownerIssuanceInterval, *ownerIssuanceBurst)
+	if *issueAPIClientCert {
+		controller.IssueAPIClientCertificate()
+	}
+	if err := controller.RegisterMetrics(prometheus.DefaultRegisterer); err != nil {
+		log.Fatalf("Failed to register CertificateController metrics: %v", err)
+	}
+	admin.RegisterState("identity", controller.State)
 
 	stopCh := make(chan struct{})
 
+	eventSink, err := events.NewSink(*eventWebhookURL, "ca", *controllerNamespace, stopCh)
+	if err != nil {
+		log.Fatalf("Failed to initialize the event webhook: %v", err)
+	}
+	controller.SetEventSink(eventSink)
+
 	k8sAPI.Sync() // blocks until caches are synced
 
-	go func() {
-		log.Info("starting CA")
-		controller.Run(stopCh)
-	}()
+	if *leaderElection {
+		go func() {
+			err := k8s.RunAsLeader(k8sClient, *controllerNamespace, caLeaderLock, func(<-chan struct{}) {
+				log.Info("starting CA")
+				controller.Run(stopCh)
+			})
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+		}()
+	} else {
+		go func() {
+			log.Info("starting CA")
+			controller.Run(stopCh)
+		}()
+	}
 
 	go admin.StartServer(*metricsAddr)
 
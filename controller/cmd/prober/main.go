@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/linkerd/linkerd2/controller/prober"
+	"github.com/linkerd/linkerd2/pkg/admin"
+	"github.com/linkerd/linkerd2/pkg/flags"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	metricsAddr := flag.String("metrics-addr", ":9999", "address to serve scrapable metrics on")
+	probeName := flag.String("probe-name", "", "name of the synthetic probe")
+	urls := flag.String("urls", "", "comma-separated list of the http:// URLs to send synthetic requests to")
+	period := flag.Duration("period", 0, "interval between the synthetic requests to each URL")
+	flags.ConfigureAndParse()
+
+	if *probeName == "" {
+		log.Fatal("-probe-name is required")
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	p, err := prober.NewProber(*probeName, strings.Split(*urls, ","), *period)
+	if err != nil {
+		log.Fatalf("Failed to create Prober: %v", err)
+	}
+	if err := p.RegisterMetrics(prometheus.DefaultRegisterer); err != nil {
+		log.Fatalf("Failed to register Prober metrics: %v", err)
+	}
+
+	stopCh := make(chan struct{})
+	go p.Run(stopCh)
+
+	go admin.StartServer(*metricsAddr)
+
+	<-stop
+
+	log.Info("shutting down")
+	close(stopCh)
+}
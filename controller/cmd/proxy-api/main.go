@@ -4,12 +4,15 @@ import (
 	"flag"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/linkerd/linkerd2/controller/api/proxy"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/admin"
 	"github.com/linkerd/linkerd2/pkg/flags"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -22,11 +25,21 @@ func main() {
 	enableTLS := flag.Bool("enable-tls", false, "Enable TLS connections among pods in the service mesh")
 	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
 	singleNamespace := flag.Bool("single-namespace", false, "only operate in the controller namespace")
+	externalNameTTL := flag.Duration("external-name-ttl", 30*time.Second, "how long addresses resolved for ExternalName services are cached before being resolved again")
+	endpointsDebounce := flag.Duration("endpoints-debounce", 100*time.Millisecond, "minimum interval between two endpoints updates sent to proxies for a service; changes received in between are coalesced")
+	enableTopology := flag.Bool("enable-topology-aware-routing", false, "prefer endpoints in the same zone as the requesting proxy")
+	zoneLabel := flag.String("topology-zone-label", proxy.DefaultZoneLabel, "node label that holds the node's zone")
+	minSameZone := flag.Int("zone-spillover-min-endpoints", 1, "minimum number of same-zone endpoints; below this, endpoints from all zones are used")
+	metricPodLabelsFlag := flag.String("metric-pod-labels", "", "comma separated list of pod label keys to include in the metric labels of endpoints")
 	flags.ConfigureAndParse()
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
+	if *enableTopology && *singleNamespace {
+		log.Fatal("topology-aware routing requires access to nodes and cannot be used with -single-namespace")
+	}
+
 	k8sClient, err := k8s.NewClientSet(*kubeConfigPath)
 	if err != nil {
 		log.Fatal(err.Error())
@@ -48,21 +61,34 @@ func main() {
 		if err != nil {
 			log.Fatal(err.Error())
 		}
+		resources := []k8s.APIResource{k8s.Endpoint, k8s.Pod, k8s.RS, k8s.Svc, k8s.SP, k8s.TS}
+		if *enableTopology {
+			resources = append(resources, k8s.Node)
+		}
 		k8sAPI = k8s.NewAPI(
 			k8sClient,
 			spClient,
 			"",
-			k8s.Endpoint,
-			k8s.Pod,
-			k8s.RS,
-			k8s.Svc,
-			k8s.SP,
+			resources...,
 		)
 	}
 
+	topology := proxy.TopologyConfig{
+		Enabled:              *enableTopology,
+		ZoneLabel:            *zoneLabel,
+		MinSameZoneEndpoints: *minSameZone,
+	}
+
+	metricPodLabels := []string{}
+	for _, key := range strings.Split(*metricPodLabelsFlag, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			metricPodLabels = append(metricPodLabels, key)
+		}
+	}
+
 	done := make(chan struct{})
 
-	server, lis, err := proxy.NewServer(*addr, *k8sDNSZone, *controllerNamespace, *enableTLS, *enableH2Upgrade, *singleNamespace, k8sAPI, done)
+	server, lis, err := proxy.NewServer(*addr, *k8sDNSZone, *controllerNamespace, *enableTLS, *enableH2Upgrade, *singleNamespace, *externalNameTTL, *endpointsDebounce, metricPodLabels, topology, k8sAPI, prometheus.DefaultRegisterer, done)
 	if err != nil {
 		log.Fatal(err)
 	}
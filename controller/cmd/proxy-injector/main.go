@@ -11,8 +11,10 @@ import (
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/controller/proxy-injector"
 	"github.com/linkerd/linkerd2/pkg/admin"
+	"github.com/linkerd/linkerd2/pkg/events"
 	"github.com/linkerd/linkerd2/pkg/flags"
 	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
@@ -24,6 +26,11 @@ func main() {
 	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
 	volumeMountsWaitTime := flag.Duration("volume-mounts-wait", 3*time.Minute, "maximum wait time for the secret volumes to mount before the timeout expires")
 	webhookServiceName := flag.String("webhook-service", "linkerd-proxy-injector.linkerd.io", "name of the admission webhook")
+	failurePolicy := flag.String("failure-policy", "Ignore", "what happens to pod creation when the webhook fails: Ignore or Fail")
+	namespaceSelector := flag.String("namespace-selector", k8sPkg.ProxyInjectorNamespaceSelectorOptOut, "which namespaces the webhook injects: opt-out (all but those labeled linkerd.io/auto-inject=disabled) or opt-in (only those labeled linkerd.io/auto-inject=enabled)")
+	networkPolicies := flag.Bool("network-policies", false, "create a NetworkPolicy in the namespaces of the injected pods that lets their proxies reach the control plane")
+	injectTimeout := flag.Duration("inject-timeout", injector.DefaultInjectTimeout, "maximum time to compute the patch of a pod, after which the pod is admitted without the proxy instead of waiting on the webhook's deadline")
+	eventWebhookURL := flag.String("event-webhook-url", "", "URL to post the ProxyInjected and PolicyDenialSpike events to as JSON (disabled if empty)")
 	flags.ConfigureAndParse()
 
 	stop := make(chan os.Signal, 1)
@@ -40,9 +47,9 @@ func main() {
 		log.Fatalf("failed to mount the ca bundle: %s", err)
 	}
 
-	webhookConfig, err := injector.NewWebhookConfig(k8sClient, *controllerNamespace, *webhookServiceName, k8sPkg.MountPathTLSTrustAnchor)
+	webhookConfig, err := injector.NewWebhookConfig(k8sClient, *controllerNamespace, *webhookServiceName, k8sPkg.MountPathTLSTrustAnchor, *failurePolicy, *namespaceSelector)
 	if err != nil {
-		log.Fatalf("failed to read the trust anchor file: %s", err)
+		log.Fatalf("failed to initialize the mutating webhook configuration: %s", err)
 	}
 
 	mwc, err := webhookConfig.CreateOrUpdate()
@@ -51,6 +58,12 @@ func main() {
 	}
 	log.Infof("created or updated mutating webhook configuration: %s", mwc.ObjectMeta.SelfLink)
 
+	vwc, err := webhookConfig.CreateOrUpdateValidator()
+	if err != nil {
+		log.Fatalf("failed to create the validating webhook configurations resource: %s", err)
+	}
+	log.Infof("created or updated validating webhook configuration: %s", vwc.ObjectMeta.SelfLink)
+
 	var (
 		certFile = k8sPkg.MountPathTLSIdentityCert
 		keyFile  = k8sPkg.MountPathTLSIdentityKey
@@ -63,6 +76,7 @@ func main() {
 	resources := &injector.WebhookResources{
 		FileProxySpec:                k8sPkg.MountPathConfigProxySpec,
 		FileProxyInitSpec:            k8sPkg.MountPathConfigProxyInitSpec,
+		FileDebugSpec:                k8sPkg.MountPathConfigDebugSpec,
 		FileTLSTrustAnchorVolumeSpec: k8sPkg.MountPathTLSTrustAnchorVolumeSpec,
 		FileTLSIdentityVolumeSpec:    k8sPkg.MountPathTLSIdentityVolumeSpec,
 	}
@@ -70,6 +84,22 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed to initialize the webhook server: %s", err)
 	}
+	if err := s.RegisterMetrics(prometheus.DefaultRegisterer); err != nil {
+		log.Fatalf("failed to register the webhook metrics: %s", err)
+	}
+	if *networkPolicies {
+		s.EnableNetworkPolicies()
+	}
+	s.SetInjectTimeout(*injectTimeout)
+	admin.RegisterState("proxy-injector", s.State)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	eventSink, err := events.NewSink(*eventWebhookURL, "proxy-injector", *controllerNamespace, stopCh)
+	if err != nil {
+		log.Fatalf("failed to initialize the event webhook: %s", err)
+	}
+	s.SetEventSink(eventSink)
 
 	go func() {
 		log.Infof("listening at %s", *addr)
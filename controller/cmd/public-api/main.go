@@ -3,31 +3,80 @@ package main
 import (
 	"context"
 	"flag"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
 	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/controller/rollout"
 	"github.com/linkerd/linkerd2/controller/tap"
 	"github.com/linkerd/linkerd2/pkg/admin"
+	"github.com/linkerd/linkerd2/pkg/events"
 	"github.com/linkerd/linkerd2/pkg/flags"
-	promApi "github.com/prometheus/client_golang/api"
+	"github.com/linkerd/linkerd2/pkg/tls"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 )
 
 func main() {
 	addr := flag.String("addr", ":8085", "address to serve on")
+	grpcAddr := flag.String("grpc-addr", "", "address to serve the public API over native gRPC on, in addition to protobuf over HTTP (disabled if empty)")
+	grpcReflection := flag.Bool("enable-grpc-reflection", false, "serve the gRPC server reflection service on -grpc-addr, so that clients such as grpcurl can explore and call the API")
 	kubeConfigPath := flag.String("kubeconfig", "", "path to kube config")
 	prometheusURL := flag.String("prometheus-url", "http://127.0.0.1:9090", "prometheus url")
+	prometheusBearerTokenFile := flag.String("prometheus-bearer-token-file", "", "path to a file with the bearer token to authenticate to prometheus with")
+	prometheusCAFile := flag.String("prometheus-ca-file", "", "path to a PEM-encoded CA bundle to verify the TLS certificate of prometheus with, instead of the system's")
+	prometheusFederationURLs := flag.String("prometheus-federation-urls", "", "comma separated list of prometheus urls to query in addition to -prometheus-url, each scraping a part of the mesh, and whose results are merged")
 	metricsAddr := flag.String("metrics-addr", ":9995", "address to serve scrapable metrics on")
+	healthAddr := flag.String("health-addr", ":9990", "address to serve the gRPC health checking service on")
 	tapAddr := flag.String("tap-addr", "127.0.0.1:8088", "address of tap service")
+	tapRouting := flag.Bool("enable-tap-routing", false, "tap each pod through the tap service of one of the controller replicas, so that taps are spread across them and resumed when a replica goes away; requires -tls-addr, whose certificate authenticates the public API to the replicas")
+	tapPort := flag.Uint("tap-port", 8089, "port of the mutual TLS tap service of the controller replicas, when tap routing is enabled")
 	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
 	singleNamespace := flag.Bool("single-namespace", false, "only operate in the controller namespace")
 	ignoredNamespaces := flag.String("ignore-namespaces", "kube-system", "comma separated list of namespaces to not list pods from")
+	maxConcurrentQueries := flag.Int("prometheus-max-concurrent-queries", 16, "maximum number of concurrent Prometheus queries per request (0 for no limit)")
+	queryTimeout := flag.Duration("prometheus-query-timeout", 10*time.Second, "maximum duration of a single Prometheus query (0 for no limit)")
+	requestBudget := flag.Duration("prometheus-request-budget", 30*time.Second, "maximum duration of all the Prometheus queries of a request, after which a partial response is returned (0 for no limit)")
+	breakerThreshold := flag.Int("prometheus-breaker-threshold", 10, "number of consecutive failed Prometheus queries after which queries are rejected (0 to disable)")
+	cacheTTL := flag.Duration("prometheus-cache-ttl", 5*time.Second, "duration for which the results of identical Prometheus queries are shared, such as those of auto-refreshing dashboards (0 to disable)")
+	breakerCooldown := flag.Duration("prometheus-breaker-cooldown", 30*time.Second, "duration for which Prometheus queries are rejected once the breaker threshold is reached")
+	eventWebhookURL := flag.String("event-webhook-url", "", "URL to post the ControlPlaneUpgraded events to as JSON (disabled if empty)")
+	tlsAddr := flag.String("tls-addr", "", "address to serve the public API over mutual TLS on, instead of -addr, to the clients that present a certificate for -tls-client-identity (disabled if empty)")
+	tlsCertFile := flag.String("tls-cert-file", "", "path to the PEM certificate that the public API presents on -tls-addr")
+	tlsKeyFile := flag.String("tls-key-file", "", "path to the PEM private key of -tls-cert-file")
+	tlsTrustAnchorsFile := flag.String("tls-trust-anchors-file", "", "path to the PEM trust anchors that the client certificates must be issued by")
+	tlsClientIdentity := flag.String("tls-client-identity", "", "DNS name that the client certificates must be issued for")
+	tlsIdentity := flag.String("tls-identity", "", "DNS name that -tls-cert-file is issued for, which the tap services of the controller replicas present too, when tap routing is enabled")
+	clientRequestRate := flag.Float64("client-request-rate", 0, "number of requests per second that each client of the public API can make, identified by the identity that the proxy sets in the l5d-client-id header or else by its address, with the clients on the loopback interface that the proxy can't identify sharing a limit (0 for no limit)")
+	clientRequestBurst := flag.Int("client-request-burst", 20, "number of requests that each client of the public API can make in a burst, when -client-request-rate is set")
+	auditLog := flag.Bool("audit-log", false, "log each request to the public API with its client, method and parameters")
 	flags.ConfigureAndParse()
 
+	if *tlsAddr != "" && (*tlsCertFile == "" || *tlsKeyFile == "" || *tlsTrustAnchorsFile == "" || *tlsClientIdentity == "") {
+		log.Fatal("-tls-addr requires -tls-cert-file, -tls-key-file, -tls-trust-anchors-file and -tls-client-identity")
+	}
+
+	if *tlsAddr != "" && *grpcAddr != "" {
+		log.Fatal("-grpc-addr can't be used with -tls-addr, since the gRPC server doesn't authenticate its clients")
+	}
+
+	if *grpcReflection && *grpcAddr == "" {
+		log.Fatal("-enable-grpc-reflection requires -grpc-addr")
+	}
+
+	// the tap services of the replicas are reachable from the pod network, so
+	// they're only routed to over mutual TLS
+	if *tapRouting && (*tlsAddr == "" || *tlsIdentity == "") {
+		log.Fatal("-enable-tap-routing requires -tls-addr and -tls-identity")
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
@@ -46,46 +95,158 @@ func main() {
 		log.Fatal(err.Error())
 	}
 	restrictToNamespace := ""
+	resources := []k8s.APIResource{k8s.CJ, k8s.Deploy, k8s.Job, k8s.Link, k8s.Pod, k8s.RC, k8s.RS, k8s.SP, k8s.Svc, k8s.TS}
 	if *singleNamespace {
 		restrictToNamespace = *controllerNamespace
+	} else {
+		// namespaces can't be listed in single-namespace mode, so they're only
+		// cached when the control plane can read all of them
+		resources = append(resources, k8s.NS)
 	}
 	k8sAPI := k8s.NewAPI(
 		k8sClient,
 		spClient,
 		restrictToNamespace,
-		k8s.Deploy,
-		k8s.Pod,
-		k8s.RC,
-		k8s.RS,
-		k8s.SP,
-		k8s.Svc,
+		resources...,
 	)
 
-	prometheusClient, err := promApi.NewClient(promApi.Config{Address: *prometheusURL})
+	if *tapRouting {
+		// the replicas authenticate the public API by the certificate that it
+		// serves -tls-addr with, and present that same certificate
+		tlsConfig, err := tls.NewMutualTLSClientFileConfig(*tlsCertFile, *tlsKeyFile, *tlsTrustAnchorsFile, *tlsIdentity)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		router := tap.NewRouter(k8sAPI, *controllerNamespace, *tapPort, tlsConfig, tapClient)
+		defer router.Close()
+		tapClient = router
+	}
+
+	prometheusURLs := []string{*prometheusURL}
+	if *prometheusFederationURLs != "" {
+		prometheusURLs = append(prometheusURLs, strings.Split(*prometheusFederationURLs, ",")...)
+	}
+	prometheusConfigs := make([]public.PrometheusConfig, len(prometheusURLs))
+	for i, url := range prometheusURLs {
+		prometheusConfigs[i] = public.PrometheusConfig{
+			URL:             url,
+			BearerTokenFile: *prometheusBearerTokenFile,
+			CAFile:          *prometheusCAFile,
+		}
+	}
+	prometheusAPI, err := public.NewPrometheusAPI(prometheusConfigs)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	prometheusAPI, err = public.NewCachingPrometheusAPI(prometheusAPI, *cacheTTL, prometheus.DefaultRegisterer)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
+	queryLimits := public.QueryLimits{
+		MaxConcurrentQueries: *maxConcurrentQueries,
+		QueryTimeout:         *queryTimeout,
+		RequestBudget:        *requestBudget,
+		BreakerThreshold:     *breakerThreshold,
+		BreakerCooldown:      *breakerCooldown,
+	}
 	server := public.NewServer(
 		*addr,
-		prometheusClient,
+		prometheusAPI,
 		tapClient,
 		k8sAPI,
 		*controllerNamespace,
 		strings.Split(*ignoredNamespaces, ","),
+		queryLimits,
+		public.AccessConfig{
+			RequestRate:  *clientRequestRate,
+			RequestBurst: *clientRequestBurst,
+			AuditLog:     *auditLog,
+		},
 	)
 
+	var tlsServer *http.Server
+	if *tlsAddr != "" {
+		tlsConfig, err := tls.NewMutualTLSServerConfig(*tlsCertFile, *tlsKeyFile, *tlsTrustAnchorsFile, *tlsClientIdentity)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		tlsServer = &http.Server{
+			Addr:      *tlsAddr,
+			Handler:   server.Handler,
+			TLSConfig: tlsConfig,
+		}
+	}
+
+	var grpcServer *grpc.Server
+	var grpcListener net.Listener
+	if *grpcAddr != "" {
+		grpcServer, grpcListener, err = public.NewGrpcServer(
+			*grpcAddr,
+			prometheusAPI,
+			tapClient,
+			k8sAPI,
+			*controllerNamespace,
+			strings.Split(*ignoredNamespaces, ","),
+			queryLimits,
+			*grpcReflection,
+		)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
+	// export deployment rollouts, for display as annotations in grafana
+	_, err = rollout.NewExporter(k8sAPI, prometheus.DefaultRegisterer)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	eventSink, err := events.NewSink(*eventWebhookURL, "public-api", *controllerNamespace, stopCh)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	rollout.NewUpgradeWatcher(*controllerNamespace, k8sAPI, eventSink)
+
 	k8sAPI.Sync() // blocks until caches are synced
 
-	go func() {
-		log.Infof("starting HTTP server on %+v", *addr)
-		server.ListenAndServe()
-	}()
+	// the API isn't served over plaintext along with mutual TLS, so that its
+	// clients can't skip the authentication
+	if tlsServer != nil {
+		go func() {
+			log.Infof("starting HTTPS server on %+v", *tlsAddr)
+			tlsServer.ListenAndServeTLS("", "")
+		}()
+	} else {
+		go func() {
+			log.Infof("starting HTTP server on %+v", *addr)
+			server.ListenAndServe()
+		}()
+	}
+
+	if grpcServer != nil {
+		go func() {
+			log.Infof("starting gRPC server on %+v", *grpcAddr)
+			grpcServer.Serve(grpcListener)
+		}()
+	}
 
 	go admin.StartServer(*metricsAddr)
+	go admin.StartHealthServer(*healthAddr, "linkerd2.public.Api")
 
 	<-stop
 
-	log.Infof("shutting down HTTP server on %+v", *addr)
-	server.Shutdown(context.Background())
+	if tlsServer != nil {
+		log.Infof("shutting down HTTPS server on %+v", *tlsAddr)
+		tlsServer.Shutdown(context.Background())
+	} else {
+		log.Infof("shutting down HTTP server on %+v", *addr)
+		server.Shutdown(context.Background())
+	}
+	if grpcServer != nil {
+		log.Infof("shutting down gRPC server on %+v", *grpcAddr)
+		grpcServer.GracefulStop()
+	}
 }
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	servicemirror "github.com/linkerd/linkerd2/controller/service-mirror"
+	"github.com/linkerd/linkerd2/pkg/admin"
+	"github.com/linkerd/linkerd2/pkg/flags"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func main() {
+	metricsAddr := flag.String("metrics-addr", ":9999", "address to serve scrapable metrics on")
+	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
+	kubeConfigPath := flag.String("kubeconfig", "", "path to kube config")
+	linkName := flag.String("link-name", "", "name of the Link in the controller namespace whose remote services are mirrored")
+	flags.ConfigureAndParse()
+
+	if *linkName == "" {
+		log.Fatal("-link-name is required")
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	k8sClient, err := k8s.NewClientSet(*kubeConfigPath)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	spClient, err := k8s.NewSpClientSet(*kubeConfigPath)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	// changes to the link or its credentials take effect when the service
+	// mirror restarts
+	link, err := spClient.LinkerdV1alpha1().Links(*controllerNamespace).Get(*linkName, metav1.GetOptions{})
+	if err != nil {
+		log.Fatalf("Failed to get link %s: %s", *linkName, err)
+	}
+	secret, err := k8sClient.CoreV1().Secrets(*controllerNamespace).Get(link.Spec.ClusterCredentialsSecret, metav1.GetOptions{})
+	if err != nil {
+		log.Fatalf("Failed to get the credentials of cluster %s: %s", link.Spec.TargetClusterName, err)
+	}
+	remoteConfig, err := clientcmd.RESTConfigFromKubeConfig(secret.Data[pkgK8s.ClusterCredentialsKey])
+	if err != nil {
+		log.Fatalf("Invalid credentials for cluster %s in secret %s: %s", link.Spec.TargetClusterName, secret.Name, err)
+	}
+	remoteClient, err := kubernetes.NewForConfig(remoteConfig)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	remoteAPI := k8s.NewAPI(remoteClient, nil, "", k8s.Svc)
+	localAPI := k8s.NewAPI(k8sClient, nil, "", k8s.Svc, k8s.NS)
+
+	// before the workqueues are created
+	if err := k8s.RegisterWorkqueueMetrics(prometheus.DefaultRegisterer); err != nil {
+		log.Fatalf("Failed to register workqueue metrics: %v", err)
+	}
+	watcher, err := servicemirror.NewRemoteClusterServiceWatcher(link, remoteAPI, localAPI)
+	if err != nil {
+		log.Fatalf("Failed to create RemoteClusterServiceWatcher: %v", err)
+	}
+
+	// links created before gateway probes existed have no probe spec
+	probeWorker, err := servicemirror.NewProbeWorker(link)
+	if err != nil {
+		log.Warnf("Not probing the gateway of cluster %s: %s", link.Spec.TargetClusterName, err)
+	} else if err := probeWorker.RegisterMetrics(prometheus.DefaultRegisterer); err != nil {
+		log.Fatalf("Failed to register ProbeWorker metrics: %v", err)
+	}
+
+	stopCh := make(chan struct{})
+
+	remoteAPI.Sync() // blocks until caches are synced
+	localAPI.Sync()
+
+	go watcher.Run(stopCh)
+	if probeWorker != nil {
+		go probeWorker.Run(stopCh)
+	}
+
+	go admin.StartServer(*metricsAddr)
+
+	<-stop
+
+	log.Info("shutting down")
+	close(stopCh)
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -10,7 +13,9 @@ import (
 	"github.com/linkerd/linkerd2/controller/tap"
 	"github.com/linkerd/linkerd2/pkg/admin"
 	"github.com/linkerd/linkerd2/pkg/flags"
+	"github.com/linkerd/linkerd2/pkg/tls"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -20,8 +25,18 @@ func main() {
 	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
 	singleNamespace := flag.Bool("single-namespace", false, "only operate in the controller namespace")
 	tapPort := flag.Uint("tap-port", 4190, "proxy tap port to connect to")
+	apiServerAddr := flag.String("apiserver-addr", "", "address to serve the tap API that the Kubernetes API aggregates on; disabled if empty")
+	tlsAddr := flag.String("tls-addr", "", "address to serve the other controller replicas over mutual TLS on, to the clients that present a certificate for -tls-client-identity (disabled if empty)")
+	tlsCertFile := flag.String("tls-cert-file", "", "path to the PEM certificate that the tap server presents on -tls-addr")
+	tlsKeyFile := flag.String("tls-key-file", "", "path to the PEM private key of -tls-cert-file")
+	tlsTrustAnchorsFile := flag.String("tls-trust-anchors-file", "", "path to the PEM trust anchors that the client certificates must be issued by")
+	tlsClientIdentity := flag.String("tls-client-identity", "", "DNS name that the client certificates must be issued for")
 	flags.ConfigureAndParse()
 
+	if *tlsAddr != "" && (*tlsCertFile == "" || *tlsKeyFile == "" || *tlsTrustAnchorsFile == "" || *tlsClientIdentity == "") {
+		log.Fatal("-tls-addr requires -tls-cert-file, -tls-key-file, -tls-trust-anchors-file and -tls-client-identity")
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
@@ -37,7 +52,9 @@ func main() {
 		k8sClient,
 		nil,
 		restrictToNamespace,
+		k8s.CJ,
 		k8s.Deploy,
+		k8s.Job,
 		k8s.Pod,
 		k8s.RC,
 		k8s.Svc,
@@ -49,6 +66,27 @@ func main() {
 		log.Fatal(err.Error())
 	}
 
+	var tlsServer *grpc.Server
+	var tlsListener net.Listener
+	if *tlsAddr != "" {
+		tlsConfig, err := tls.NewMutualTLSServerConfig(*tlsCertFile, *tlsKeyFile, *tlsTrustAnchorsFile, *tlsClientIdentity)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		tlsServer, tlsListener, err = tap.NewTLSServer(*tlsAddr, tlsConfig, *tapPort, *controllerNamespace, k8sAPI)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
+	var apiServer *http.Server
+	if *apiServerAddr != "" {
+		apiServer, err = tap.NewAPIServer(*apiServerAddr, *tapPort, *controllerNamespace, k8sAPI)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
 	k8sAPI.Sync() // blocks until caches are synced
 
 	go func() {
@@ -56,10 +94,32 @@ func main() {
 		server.Serve(lis)
 	}()
 
+	if tlsServer != nil {
+		go func() {
+			log.Println("starting gRPC TLS server on", *tlsAddr)
+			tlsServer.Serve(tlsListener)
+		}()
+	}
+
+	if apiServer != nil {
+		go func() {
+			log.Println("starting tap API server on", *apiServerAddr)
+			apiServer.ListenAndServeTLS("", "")
+		}()
+	}
+
 	go admin.StartServer(*metricsAddr)
 
 	<-stop
 
 	log.Println("shutting down gRPC server on", *addr)
 	server.GracefulStop()
+	if tlsServer != nil {
+		log.Println("shutting down gRPC TLS server on", *tlsAddr)
+		tlsServer.GracefulStop()
+	}
+	if apiServer != nil {
+		log.Println("shutting down tap API server on", *apiServerAddr)
+		apiServer.Shutdown(context.Background())
+	}
 }
@@ -40,6 +40,8 @@ var (
 // Adds the list of known types to Scheme.
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
+		&Link{},
+		&LinkList{},
 		&ServiceProfile{},
 		&ServiceProfileList{},
 	)
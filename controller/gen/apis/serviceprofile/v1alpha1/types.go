@@ -86,3 +86,68 @@ type ServiceProfileList struct {
 
 	Items []ServiceProfile `json:"items"`
 }
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Link describes a remote cluster whose services are mirrored into this
+// cluster by the service mirror, and the gateway that the traffic to the
+// mirrored services is sent through.
+type Link struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the custom resource spec
+	Spec LinkSpec `json:"spec"`
+}
+
+// LinkSpec specifies a Link resource.
+type LinkSpec struct {
+	// TargetClusterName is the name of the remote cluster, which suffixes the
+	// names of the mirrored services, e.g. "web-east" for the "web" service of
+	// the "east" cluster.
+	TargetClusterName string `json:"targetClusterName"`
+	// TargetClusterDomain is the DNS domain of the remote cluster, e.g.
+	// "cluster.local".
+	TargetClusterDomain string `json:"targetClusterDomain"`
+	// ClusterCredentialsSecret is the name of the secret in the Link's
+	// namespace with the kubeconfig that the service mirror watches the
+	// remote cluster with, under the "kubeconfig" key.
+	ClusterCredentialsSecret string `json:"clusterCredentialsSecret"`
+	// GatewayAddress is the hostname or IP address of the remote cluster's
+	// gateway, which the mirrored services' endpoints point to.
+	GatewayAddress string `json:"gatewayAddress"`
+	// GatewayPort is the port that the gateway accepts the traffic of all the
+	// mirrored services on.
+	GatewayPort uint32 `json:"gatewayPort"`
+	// GatewayIdentity is the TLS identity of the gateway, if it has one.
+	GatewayIdentity string `json:"gatewayIdentity,omitempty"`
+	// Selector selects the remote services that are mirrored.
+	Selector metav1.LabelSelector `json:"selector"`
+	// ProbeSpec configures the probes that the service mirror sends to the
+	// gateway to measure its reachability and latency.
+	ProbeSpec ProbeSpec `json:"probeSpec"`
+}
+
+// ProbeSpec specifies the HTTP probes of a remote cluster's gateway.
+type ProbeSpec struct {
+	// Path is the path of the gateway's health endpoint, e.g. "/health".
+	Path string `json:"path"`
+	// Port is the port of the gateway's health endpoint.
+	Port uint32 `json:"port"`
+	// Period is the interval between probes, as a duration, e.g. "3s".
+	Period string `json:"period"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LinkList is a list of Link resources.
+type LinkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Link `json:"items"`
+}
@@ -0,0 +1,5 @@
+package split
+
+// GroupName identifies the API Group Name for a TrafficSplit of the Service
+// Mesh Interface.
+const GroupName = "split.smi-spec.io"
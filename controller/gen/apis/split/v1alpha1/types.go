@@ -0,0 +1,48 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TrafficSplit is the Service Mesh Interface resource that splits the
+// traffic sent to an apex service between backend services, e.g. during a
+// canary rollout.
+type TrafficSplit struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the custom resource spec
+	Spec TrafficSplitSpec `json:"spec"`
+}
+
+// TrafficSplitSpec specifies a TrafficSplit resource.
+type TrafficSplitSpec struct {
+	// Service is the apex service, which clients send the split traffic to.
+	Service string `json:"service"`
+	// Backends are the services that the traffic is split between.
+	Backends []TrafficSplitBackend `json:"backends"`
+}
+
+// TrafficSplitBackend is a backend service of a TrafficSplit, with the
+// weight of the share of the traffic that it receives.
+type TrafficSplitBackend struct {
+	Service string             `json:"service"`
+	Weight  *resource.Quantity `json:"weight"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TrafficSplitList is a list of TrafficSplit resources.
+type TrafficSplitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TrafficSplit `json:"items"`
+}
@@ -20,6 +20,7 @@ package versioned
 
 import (
 	linkerdv1alpha1 "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned/typed/serviceprofile/v1alpha1"
+	splitv1alpha1 "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned/typed/split/v1alpha1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -30,6 +31,9 @@ type Interface interface {
 	LinkerdV1alpha1() linkerdv1alpha1.LinkerdV1alpha1Interface
 	// Deprecated: please explicitly pick a version if possible.
 	Linkerd() linkerdv1alpha1.LinkerdV1alpha1Interface
+	SplitV1alpha1() splitv1alpha1.SplitV1alpha1Interface
+	// Deprecated: please explicitly pick a version if possible.
+	Split() splitv1alpha1.SplitV1alpha1Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
@@ -37,6 +41,7 @@ type Interface interface {
 type Clientset struct {
 	*discovery.DiscoveryClient
 	linkerdV1alpha1 *linkerdv1alpha1.LinkerdV1alpha1Client
+	splitV1alpha1   *splitv1alpha1.SplitV1alpha1Client
 }
 
 // LinkerdV1alpha1 retrieves the LinkerdV1alpha1Client
@@ -50,6 +55,17 @@ func (c *Clientset) Linkerd() linkerdv1alpha1.LinkerdV1alpha1Interface {
 	return c.linkerdV1alpha1
 }
 
+// SplitV1alpha1 retrieves the SplitV1alpha1Client
+func (c *Clientset) SplitV1alpha1() splitv1alpha1.SplitV1alpha1Interface {
+	return c.splitV1alpha1
+}
+
+// Deprecated: Split retrieves the default version of SplitClient.
+// Please explicitly pick a version.
+func (c *Clientset) Split() splitv1alpha1.SplitV1alpha1Interface {
+	return c.splitV1alpha1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -70,6 +86,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.splitV1alpha1, err = splitv1alpha1.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(&configShallowCopy)
 	if err != nil {
@@ -83,6 +103,7 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
 	cs.linkerdV1alpha1 = linkerdv1alpha1.NewForConfigOrDie(c)
+	cs.splitV1alpha1 = splitv1alpha1.NewForConfigOrDie(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
 	return &cs
@@ -92,6 +113,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.linkerdV1alpha1 = linkerdv1alpha1.New(c)
+	cs.splitV1alpha1 = splitv1alpha1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
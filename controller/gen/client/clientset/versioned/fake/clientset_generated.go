@@ -22,6 +22,8 @@ import (
 	clientset "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
 	linkerdv1alpha1 "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned/typed/serviceprofile/v1alpha1"
 	fakelinkerdv1alpha1 "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned/typed/serviceprofile/v1alpha1/fake"
+	splitv1alpha1 "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned/typed/split/v1alpha1"
+	fakesplitv1alpha1 "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned/typed/split/v1alpha1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -80,3 +82,13 @@ func (c *Clientset) LinkerdV1alpha1() linkerdv1alpha1.LinkerdV1alpha1Interface {
 func (c *Clientset) Linkerd() linkerdv1alpha1.LinkerdV1alpha1Interface {
 	return &fakelinkerdv1alpha1.FakeLinkerdV1alpha1{Fake: &c.Fake}
 }
+
+// SplitV1alpha1 retrieves the SplitV1alpha1Client
+func (c *Clientset) SplitV1alpha1() splitv1alpha1.SplitV1alpha1Interface {
+	return &fakesplitv1alpha1.FakeSplitV1alpha1{Fake: &c.Fake}
+}
+
+// Split retrieves the SplitV1alpha1Client
+func (c *Clientset) Split() splitv1alpha1.SplitV1alpha1Interface {
+	return &fakesplitv1alpha1.FakeSplitV1alpha1{Fake: &c.Fake}
+}
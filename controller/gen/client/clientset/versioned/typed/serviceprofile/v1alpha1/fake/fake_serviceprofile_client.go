@@ -28,6 +28,10 @@ type FakeLinkerdV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeLinkerdV1alpha1) Links(namespace string) v1alpha1.LinkInterface {
+	return &FakeLinks{c, namespace}
+}
+
 func (c *FakeLinkerdV1alpha1) ServiceProfiles(namespace string) v1alpha1.ServiceProfileInterface {
 	return &FakeServiceProfiles{c, namespace}
 }
@@ -26,6 +26,7 @@ import (
 	versioned "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
 	internalinterfaces "github.com/linkerd/linkerd2/controller/gen/client/informers/externalversions/internalinterfaces"
 	serviceprofile "github.com/linkerd/linkerd2/controller/gen/client/informers/externalversions/serviceprofile"
+	split "github.com/linkerd/linkerd2/controller/gen/client/informers/externalversions/split"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -173,8 +174,13 @@ type SharedInformerFactory interface {
 	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
 
 	Linkerd() serviceprofile.Interface
+	Split() split.Interface
 }
 
 func (f *sharedInformerFactory) Linkerd() serviceprofile.Interface {
 	return serviceprofile.New(f, f.namespace, f.tweakListOptions)
 }
+
+func (f *sharedInformerFactory) Split() split.Interface {
+	return split.New(f, f.namespace, f.tweakListOptions)
+}
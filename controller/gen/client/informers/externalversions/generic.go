@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	v1alpha1 "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	splitv1alpha1 "github.com/linkerd/linkerd2/controller/gen/apis/split/v1alpha1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -53,9 +54,15 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=linkerd.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("links"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Linkerd().V1alpha1().Links().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("serviceprofiles"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Linkerd().V1alpha1().ServiceProfiles().Informer()}, nil
 
+		// Group=split.smi-spec.io, Version=v1alpha1
+	case splitv1alpha1.SchemeGroupVersion.WithResource("trafficsplits"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Split().V1alpha1().TrafficSplits().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// Links returns a LinkInformer.
+	Links() LinkInformer
 	// ServiceProfiles returns a ServiceProfileInformer.
 	ServiceProfiles() ServiceProfileInformer
 }
@@ -39,6 +41,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// Links returns a LinkInformer.
+func (v *version) Links() LinkInformer {
+	return &linkInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // ServiceProfiles returns a ServiceProfileInformer.
 func (v *version) ServiceProfiles() ServiceProfileInformer {
 	return &serviceProfileInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
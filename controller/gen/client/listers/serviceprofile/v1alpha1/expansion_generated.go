@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1alpha1
 
+// LinkListerExpansion allows custom methods to be added to
+// LinkLister.
+type LinkListerExpansion interface{}
+
+// LinkNamespaceListerExpansion allows custom methods to be added to
+// LinkNamespaceLister.
+type LinkNamespaceListerExpansion interface{}
+
 // ServiceProfileListerExpansion allows custom methods to be added to
 // ServiceProfileLister.
 type ServiceProfileListerExpansion interface{}
@@ -66,7 +66,7 @@ func (x HttpMethod_Registered) String() string {
 	return proto.EnumName(HttpMethod_Registered_name, int32(x))
 }
 func (HttpMethod_Registered) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{10, 0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{11, 0}
 }
 
 type Scheme_Registered int32
@@ -89,7 +89,7 @@ func (x Scheme_Registered) String() string {
 	return proto.EnumName(Scheme_Registered_name, int32(x))
 }
 func (Scheme_Registered) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{11, 0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{12, 0}
 }
 
 type TapEvent_ProxyDirection int32
@@ -115,7 +115,34 @@ func (x TapEvent_ProxyDirection) String() string {
 	return proto.EnumName(TapEvent_ProxyDirection_name, int32(x))
 }
 func (TapEvent_ProxyDirection) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{16, 0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{17, 0}
+}
+
+type EdgeEvent_Type int32
+
+const (
+	EdgeEvent_ADD    EdgeEvent_Type = 0
+	EdgeEvent_REMOVE EdgeEvent_Type = 1
+	// The TLS status or the identities of an existing edge changed.
+	EdgeEvent_UPDATE EdgeEvent_Type = 2
+)
+
+var EdgeEvent_Type_name = map[int32]string{
+	0: "ADD",
+	1: "REMOVE",
+	2: "UPDATE",
+}
+var EdgeEvent_Type_value = map[string]int32{
+	"ADD":    0,
+	"REMOVE": 1,
+	"UPDATE": 2,
+}
+
+func (x EdgeEvent_Type) String() string {
+	return proto.EnumName(EdgeEvent_Type_name, int32(x))
+}
+func (EdgeEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_public_c5f56ff279f0282e, []int{33, 0}
 }
 
 type Empty struct {
@@ -128,7 +155,7 @@ func (m *Empty) Reset()         { *m = Empty{} }
 func (m *Empty) String() string { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()    {}
 func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{0}
 }
 func (m *Empty) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Empty.Unmarshal(m, b)
@@ -149,9 +176,13 @@ func (m *Empty) XXX_DiscardUnknown() {
 var xxx_messageInfo_Empty proto.InternalMessageInfo
 
 type VersionInfo struct {
-	GoVersion            string   `protobuf:"bytes,1,opt,name=goVersion,proto3" json:"goVersion,omitempty"`
-	BuildDate            string   `protobuf:"bytes,2,opt,name=buildDate,proto3" json:"buildDate,omitempty"`
-	ReleaseVersion       string   `protobuf:"bytes,3,opt,name=releaseVersion,proto3" json:"releaseVersion,omitempty"`
+	GoVersion      string `protobuf:"bytes,1,opt,name=goVersion,proto3" json:"goVersion,omitempty"`
+	BuildDate      string `protobuf:"bytes,2,opt,name=buildDate,proto3" json:"buildDate,omitempty"`
+	ReleaseVersion string `protobuf:"bytes,3,opt,name=releaseVersion,proto3" json:"releaseVersion,omitempty"`
+	// The git commit that the build was made from.
+	GitSha string `protobuf:"bytes,4,opt,name=gitSha,proto3" json:"gitSha,omitempty"`
+	// The build tags that the build was made with.
+	BuildTags            []string `protobuf:"bytes,5,rep,name=buildTags,proto3" json:"buildTags,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -161,7 +192,7 @@ func (m *VersionInfo) Reset()         { *m = VersionInfo{} }
 func (m *VersionInfo) String() string { return proto.CompactTextString(m) }
 func (*VersionInfo) ProtoMessage()    {}
 func (*VersionInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{1}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{1}
 }
 func (m *VersionInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VersionInfo.Unmarshal(m, b)
@@ -202,6 +233,60 @@ func (m *VersionInfo) GetReleaseVersion() string {
 	return ""
 }
 
+func (m *VersionInfo) GetGitSha() string {
+	if m != nil {
+		return m.GitSha
+	}
+	return ""
+}
+
+func (m *VersionInfo) GetBuildTags() []string {
+	if m != nil {
+		return m.BuildTags
+	}
+	return nil
+}
+
+type TrustBundleResponse struct {
+	// The trust anchors of the mesh in the SPIFFE trust bundle format, a JSON
+	// JWK set with an "x509-svid" key for each trust anchor.
+	Bundle               string   `protobuf:"bytes,1,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TrustBundleResponse) Reset()         { *m = TrustBundleResponse{} }
+func (m *TrustBundleResponse) String() string { return proto.CompactTextString(m) }
+func (*TrustBundleResponse) ProtoMessage()    {}
+func (*TrustBundleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_c5f56ff279f0282e, []int{2}
+}
+func (m *TrustBundleResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrustBundleResponse.Unmarshal(m, b)
+}
+func (m *TrustBundleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrustBundleResponse.Marshal(b, m, deterministic)
+}
+func (dst *TrustBundleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrustBundleResponse.Merge(dst, src)
+}
+func (m *TrustBundleResponse) XXX_Size() int {
+	return xxx_messageInfo_TrustBundleResponse.Size(m)
+}
+func (m *TrustBundleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrustBundleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrustBundleResponse proto.InternalMessageInfo
+
+func (m *TrustBundleResponse) GetBundle() string {
+	if m != nil {
+		return m.Bundle
+	}
+	return ""
+}
+
 type ListServicesRequest struct {
 	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -213,7 +298,7 @@ func (m *ListServicesRequest) Reset()         { *m = ListServicesRequest{} }
 func (m *ListServicesRequest) String() string { return proto.CompactTextString(m) }
 func (*ListServicesRequest) ProtoMessage()    {}
 func (*ListServicesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{2}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{3}
 }
 func (m *ListServicesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListServicesRequest.Unmarshal(m, b)
@@ -251,7 +336,7 @@ func (m *ListServicesResponse) Reset()         { *m = ListServicesResponse{} }
 func (m *ListServicesResponse) String() string { return proto.CompactTextString(m) }
 func (*ListServicesResponse) ProtoMessage()    {}
 func (*ListServicesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{3}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{4}
 }
 func (m *ListServicesResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListServicesResponse.Unmarshal(m, b)
@@ -290,7 +375,7 @@ func (m *Service) Reset()         { *m = Service{} }
 func (m *Service) String() string { return proto.CompactTextString(m) }
 func (*Service) ProtoMessage()    {}
 func (*Service) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{4}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{5}
 }
 func (m *Service) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Service.Unmarshal(m, b)
@@ -325,7 +410,12 @@ func (m *Service) GetNamespace() string {
 }
 
 type ListPodsRequest struct {
-	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// maximum number of pods to return, all of them if 0; the pods are ordered
+	// by namespace and name
+	Limit uint32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// continue_token of the previous page, to return the pods after it
+	ContinueToken        string   `protobuf:"bytes,3,opt,name=continue_token,json=continueToken,proto3" json:"continue_token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -335,7 +425,7 @@ func (m *ListPodsRequest) Reset()         { *m = ListPodsRequest{} }
 func (m *ListPodsRequest) String() string { return proto.CompactTextString(m) }
 func (*ListPodsRequest) ProtoMessage()    {}
 func (*ListPodsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{5}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{6}
 }
 func (m *ListPodsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListPodsRequest.Unmarshal(m, b)
@@ -362,8 +452,25 @@ func (m *ListPodsRequest) GetNamespace() string {
 	return ""
 }
 
+func (m *ListPodsRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListPodsRequest) GetContinueToken() string {
+	if m != nil {
+		return m.ContinueToken
+	}
+	return ""
+}
+
 type ListPodsResponse struct {
-	Pods                 []*Pod   `protobuf:"bytes,1,rep,name=pods,proto3" json:"pods,omitempty"`
+	Pods []*Pod `protobuf:"bytes,1,rep,name=pods,proto3" json:"pods,omitempty"`
+	// set if there are more pods, to request them with the same request and
+	// this continue_token
+	ContinueToken        string   `protobuf:"bytes,2,opt,name=continue_token,json=continueToken,proto3" json:"continue_token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -373,7 +480,7 @@ func (m *ListPodsResponse) Reset()         { *m = ListPodsResponse{} }
 func (m *ListPodsResponse) String() string { return proto.CompactTextString(m) }
 func (*ListPodsResponse) ProtoMessage()    {}
 func (*ListPodsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{6}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{7}
 }
 func (m *ListPodsResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListPodsResponse.Unmarshal(m, b)
@@ -400,6 +507,13 @@ func (m *ListPodsResponse) GetPods() []*Pod {
 	return nil
 }
 
+func (m *ListPodsResponse) GetContinueToken() string {
+	if m != nil {
+		return m.ContinueToken
+	}
+	return ""
+}
+
 type Pod struct {
 	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	PodIP string `protobuf:"bytes,2,opt,name=podIP,proto3" json:"podIP,omitempty"`
@@ -428,7 +542,7 @@ func (m *Pod) Reset()         { *m = Pod{} }
 func (m *Pod) String() string { return proto.CompactTextString(m) }
 func (*Pod) ProtoMessage()    {}
 func (*Pod) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{7}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{8}
 }
 func (m *Pod) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Pod.Unmarshal(m, b)
@@ -758,7 +872,7 @@ func (m *TapRequest) Reset()         { *m = TapRequest{} }
 func (m *TapRequest) String() string { return proto.CompactTextString(m) }
 func (*TapRequest) ProtoMessage()    {}
 func (*TapRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{8}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{9}
 }
 func (m *TapRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapRequest.Unmarshal(m, b)
@@ -961,7 +1075,7 @@ func (m *TapByResourceRequest) Reset()         { *m = TapByResourceRequest{} }
 func (m *TapByResourceRequest) String() string { return proto.CompactTextString(m) }
 func (*TapByResourceRequest) ProtoMessage()    {}
 func (*TapByResourceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{9}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{10}
 }
 func (m *TapByResourceRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapByResourceRequest.Unmarshal(m, b)
@@ -1019,7 +1133,7 @@ func (m *TapByResourceRequest_Match) Reset()         { *m = TapByResourceRequest
 func (m *TapByResourceRequest_Match) String() string { return proto.CompactTextString(m) }
 func (*TapByResourceRequest_Match) ProtoMessage()    {}
 func (*TapByResourceRequest_Match) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{9, 0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{10, 0}
 }
 func (m *TapByResourceRequest_Match) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapByResourceRequest_Match.Unmarshal(m, b)
@@ -1257,7 +1371,7 @@ func (m *TapByResourceRequest_Match_Seq) Reset()         { *m = TapByResourceReq
 func (m *TapByResourceRequest_Match_Seq) String() string { return proto.CompactTextString(m) }
 func (*TapByResourceRequest_Match_Seq) ProtoMessage()    {}
 func (*TapByResourceRequest_Match_Seq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{9, 0, 0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{10, 0, 0}
 }
 func (m *TapByResourceRequest_Match_Seq) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapByResourceRequest_Match_Seq.Unmarshal(m, b)
@@ -1300,7 +1414,7 @@ func (m *TapByResourceRequest_Match_Http) Reset()         { *m = TapByResourceRe
 func (m *TapByResourceRequest_Match_Http) String() string { return proto.CompactTextString(m) }
 func (*TapByResourceRequest_Match_Http) ProtoMessage()    {}
 func (*TapByResourceRequest_Match_Http) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{9, 0, 1}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{10, 0, 1}
 }
 func (m *TapByResourceRequest_Match_Http) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapByResourceRequest_Match_Http.Unmarshal(m, b)
@@ -1493,7 +1607,7 @@ func (m *HttpMethod) Reset()         { *m = HttpMethod{} }
 func (m *HttpMethod) String() string { return proto.CompactTextString(m) }
 func (*HttpMethod) ProtoMessage()    {}
 func (*HttpMethod) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{10}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{11}
 }
 func (m *HttpMethod) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_HttpMethod.Unmarshal(m, b)
@@ -1629,7 +1743,7 @@ func (m *Scheme) Reset()         { *m = Scheme{} }
 func (m *Scheme) String() string { return proto.CompactTextString(m) }
 func (*Scheme) ProtoMessage()    {}
 func (*Scheme) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{11}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{12}
 }
 func (m *Scheme) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Scheme.Unmarshal(m, b)
@@ -1765,7 +1879,7 @@ func (m *IPAddress) Reset()         { *m = IPAddress{} }
 func (m *IPAddress) String() string { return proto.CompactTextString(m) }
 func (*IPAddress) ProtoMessage()    {}
 func (*IPAddress) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{12}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{13}
 }
 func (m *IPAddress) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IPAddress.Unmarshal(m, b)
@@ -1903,7 +2017,7 @@ func (m *IPv6) Reset()         { *m = IPv6{} }
 func (m *IPv6) String() string { return proto.CompactTextString(m) }
 func (*IPv6) ProtoMessage()    {}
 func (*IPv6) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{13}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{14}
 }
 func (m *IPv6) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IPv6.Unmarshal(m, b)
@@ -1949,7 +2063,7 @@ func (m *TcpAddress) Reset()         { *m = TcpAddress{} }
 func (m *TcpAddress) String() string { return proto.CompactTextString(m) }
 func (*TcpAddress) ProtoMessage()    {}
 func (*TcpAddress) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{14}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{15}
 }
 func (m *TcpAddress) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TcpAddress.Unmarshal(m, b)
@@ -1997,7 +2111,7 @@ func (m *Eos) Reset()         { *m = Eos{} }
 func (m *Eos) String() string { return proto.CompactTextString(m) }
 func (*Eos) ProtoMessage()    {}
 func (*Eos) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{15}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{16}
 }
 func (m *Eos) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Eos.Unmarshal(m, b)
@@ -2137,7 +2251,7 @@ func (m *TapEvent) Reset()         { *m = TapEvent{} }
 func (m *TapEvent) String() string { return proto.CompactTextString(m) }
 func (*TapEvent) ProtoMessage()    {}
 func (*TapEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{16}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{17}
 }
 func (m *TapEvent) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent.Unmarshal(m, b)
@@ -2289,7 +2403,7 @@ func (m *TapEvent_EndpointMeta) Reset()         { *m = TapEvent_EndpointMeta{} }
 func (m *TapEvent_EndpointMeta) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_EndpointMeta) ProtoMessage()    {}
 func (*TapEvent_EndpointMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{16, 0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{17, 0}
 }
 func (m *TapEvent_EndpointMeta) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_EndpointMeta.Unmarshal(m, b)
@@ -2327,7 +2441,7 @@ func (m *TapEvent_RouteMeta) Reset()         { *m = TapEvent_RouteMeta{} }
 func (m *TapEvent_RouteMeta) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_RouteMeta) ProtoMessage()    {}
 func (*TapEvent_RouteMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{16, 1}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{17, 1}
 }
 func (m *TapEvent_RouteMeta) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_RouteMeta.Unmarshal(m, b)
@@ -2369,7 +2483,7 @@ func (m *TapEvent_Http) Reset()         { *m = TapEvent_Http{} }
 func (m *TapEvent_Http) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http) ProtoMessage()    {}
 func (*TapEvent_Http) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{16, 2}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{17, 2}
 }
 func (m *TapEvent_Http) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http.Unmarshal(m, b)
@@ -2546,7 +2660,7 @@ func (m *TapEvent_Http_StreamId) Reset()         { *m = TapEvent_Http_StreamId{}
 func (m *TapEvent_Http_StreamId) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http_StreamId) ProtoMessage()    {}
 func (*TapEvent_Http_StreamId) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{16, 2, 0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{17, 2, 0}
 }
 func (m *TapEvent_Http_StreamId) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http_StreamId.Unmarshal(m, b)
@@ -2595,7 +2709,7 @@ func (m *TapEvent_Http_RequestInit) Reset()         { *m = TapEvent_Http_Request
 func (m *TapEvent_Http_RequestInit) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http_RequestInit) ProtoMessage()    {}
 func (*TapEvent_Http_RequestInit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{16, 2, 1}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{17, 2, 1}
 }
 func (m *TapEvent_Http_RequestInit) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http_RequestInit.Unmarshal(m, b)
@@ -2663,7 +2777,7 @@ func (m *TapEvent_Http_ResponseInit) Reset()         { *m = TapEvent_Http_Respon
 func (m *TapEvent_Http_ResponseInit) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http_ResponseInit) ProtoMessage()    {}
 func (*TapEvent_Http_ResponseInit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{16, 2, 2}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{17, 2, 2}
 }
 func (m *TapEvent_Http_ResponseInit) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http_ResponseInit.Unmarshal(m, b)
@@ -2719,7 +2833,7 @@ func (m *TapEvent_Http_ResponseEnd) Reset()         { *m = TapEvent_Http_Respons
 func (m *TapEvent_Http_ResponseEnd) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http_ResponseEnd) ProtoMessage()    {}
 func (*TapEvent_Http_ResponseEnd) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{16, 2, 3}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{17, 2, 3}
 }
 func (m *TapEvent_Http_ResponseEnd) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http_ResponseEnd.Unmarshal(m, b)
@@ -2785,7 +2899,7 @@ func (m *ApiError) Reset()         { *m = ApiError{} }
 func (m *ApiError) String() string { return proto.CompactTextString(m) }
 func (*ApiError) ProtoMessage()    {}
 func (*ApiError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{17}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{18}
 }
 func (m *ApiError) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ApiError.Unmarshal(m, b)
@@ -2823,7 +2937,7 @@ func (m *PodErrors) Reset()         { *m = PodErrors{} }
 func (m *PodErrors) String() string { return proto.CompactTextString(m) }
 func (*PodErrors) ProtoMessage()    {}
 func (*PodErrors) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{18}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{19}
 }
 func (m *PodErrors) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PodErrors.Unmarshal(m, b)
@@ -2863,7 +2977,7 @@ func (m *PodErrors_PodError) Reset()         { *m = PodErrors_PodError{} }
 func (m *PodErrors_PodError) String() string { return proto.CompactTextString(m) }
 func (*PodErrors_PodError) ProtoMessage()    {}
 func (*PodErrors_PodError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{18, 0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{19, 0}
 }
 func (m *PodErrors_PodError) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PodErrors_PodError.Unmarshal(m, b)
@@ -2977,7 +3091,7 @@ func (m *PodErrors_PodError_ContainerError) Reset()         { *m = PodErrors_Pod
 func (m *PodErrors_PodError_ContainerError) String() string { return proto.CompactTextString(m) }
 func (*PodErrors_PodError_ContainerError) ProtoMessage()    {}
 func (*PodErrors_PodError_ContainerError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{18, 0, 0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{19, 0, 0}
 }
 func (m *PodErrors_PodError_ContainerError) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PodErrors_PodError_ContainerError.Unmarshal(m, b)
@@ -3048,7 +3162,7 @@ func (m *Resource) Reset()         { *m = Resource{} }
 func (m *Resource) String() string { return proto.CompactTextString(m) }
 func (*Resource) ProtoMessage()    {}
 func (*Resource) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{19}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{20}
 }
 func (m *Resource) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Resource.Unmarshal(m, b)
@@ -3107,7 +3221,7 @@ func (m *ResourceSelection) Reset()         { *m = ResourceSelection{} }
 func (m *ResourceSelection) String() string { return proto.CompactTextString(m) }
 func (*ResourceSelection) ProtoMessage()    {}
 func (*ResourceSelection) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{20}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{21}
 }
 func (m *ResourceSelection) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ResourceSelection.Unmarshal(m, b)
@@ -3153,7 +3267,7 @@ func (m *ResourceError) Reset()         { *m = ResourceError{} }
 func (m *ResourceError) String() string { return proto.CompactTextString(m) }
 func (*ResourceError) ProtoMessage()    {}
 func (*ResourceError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{21}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{22}
 }
 func (m *ResourceError) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ResourceError.Unmarshal(m, b)
@@ -3194,18 +3308,33 @@ type StatSummaryRequest struct {
 	//	*StatSummaryRequest_None
 	//	*StatSummaryRequest_ToResource
 	//	*StatSummaryRequest_FromResource
-	Outbound             isStatSummaryRequest_Outbound `protobuf_oneof:"outbound"`
-	SkipStats            bool                          `protobuf:"varint,6,opt,name=skip_stats,json=skipStats,proto3" json:"skip_stats,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
-	XXX_unrecognized     []byte                        `json:"-"`
-	XXX_sizecache        int32                         `json:"-"`
+	Outbound  isStatSummaryRequest_Outbound `protobuf_oneof:"outbound"`
+	SkipStats bool                          `protobuf:"varint,6,opt,name=skip_stats,json=skipStats,proto3" json:"skip_stats,omitempty"`
+	// only include the metrics with these labels, as added by the proxy-api's
+	// metric pod labels allow-list; keys are Prometheus label names
+	MetricLabels map[string]string `protobuf:"bytes,7,rep,name=metric_labels,json=metricLabels,proto3" json:"metric_labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// maximum number of rows to return, all of them if 0; the rows are ordered
+	// by namespace and name. Not supported for the "all" resource type.
+	Limit uint32 `protobuf:"varint,8,opt,name=limit,proto3" json:"limit,omitempty"`
+	// continue_token of the previous page, to return the rows after it
+	ContinueToken string `protobuf:"bytes,9,opt,name=continue_token,json=continueToken,proto3" json:"continue_token,omitempty"`
+	// also count the gRPC responses by grpc-status
+	GrpcStats bool `protobuf:"varint,10,opt,name=grpc_stats,json=grpcStats,proto3" json:"grpc_stats,omitempty"`
+	// also break the stats of each row down by "authority", the authority of
+	// the requests, or by "route", the route of their service profile, in the
+	// group_stats of the rows. Not supported for authorities and traffic
+	// splits, nor by route on 'to' and 'from' queries.
+	GroupBy              string   `protobuf:"bytes,11,opt,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *StatSummaryRequest) Reset()         { *m = StatSummaryRequest{} }
 func (m *StatSummaryRequest) String() string { return proto.CompactTextString(m) }
 func (*StatSummaryRequest) ProtoMessage()    {}
 func (*StatSummaryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{22}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{23}
 }
 func (m *StatSummaryRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatSummaryRequest.Unmarshal(m, b)
@@ -3296,6 +3425,41 @@ func (m *StatSummaryRequest) GetSkipStats() bool {
 	return false
 }
 
+func (m *StatSummaryRequest) GetMetricLabels() map[string]string {
+	if m != nil {
+		return m.MetricLabels
+	}
+	return nil
+}
+
+func (m *StatSummaryRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *StatSummaryRequest) GetContinueToken() string {
+	if m != nil {
+		return m.ContinueToken
+	}
+	return ""
+}
+
+func (m *StatSummaryRequest) GetGrpcStats() bool {
+	if m != nil {
+		return m.GrpcStats
+	}
+	return false
+}
+
+func (m *StatSummaryRequest) GetGroupBy() string {
+	if m != nil {
+		return m.GroupBy
+	}
+	return ""
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*StatSummaryRequest) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _StatSummaryRequest_OneofMarshaler, _StatSummaryRequest_OneofUnmarshaler, _StatSummaryRequest_OneofSizer, []interface{}{
@@ -3403,7 +3567,7 @@ func (m *StatSummaryResponse) Reset()         { *m = StatSummaryResponse{} }
 func (m *StatSummaryResponse) String() string { return proto.CompactTextString(m) }
 func (*StatSummaryResponse) ProtoMessage()    {}
 func (*StatSummaryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{23}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{24}
 }
 func (m *StatSummaryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatSummaryResponse.Unmarshal(m, b)
@@ -3535,17 +3699,23 @@ func _StatSummaryResponse_OneofSizer(msg proto.Message) (n int) {
 }
 
 type StatSummaryResponse_Ok struct {
-	StatTables           []*StatTable `protobuf:"bytes,1,rep,name=stat_tables,json=statTables,proto3" json:"stat_tables,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	StatTables []*StatTable `protobuf:"bytes,1,rep,name=stat_tables,json=statTables,proto3" json:"stat_tables,omitempty"`
+	// set if some of the stats couldn't be computed within the Prometheus
+	// query budget of the request, in which case they're missing or zero
+	Partial bool `protobuf:"varint,2,opt,name=partial,proto3" json:"partial,omitempty"`
+	// set if there are more rows, to request them with the same request and
+	// this continue_token
+	ContinueToken        string   `protobuf:"bytes,3,opt,name=continue_token,json=continueToken,proto3" json:"continue_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *StatSummaryResponse_Ok) Reset()         { *m = StatSummaryResponse_Ok{} }
 func (m *StatSummaryResponse_Ok) String() string { return proto.CompactTextString(m) }
 func (*StatSummaryResponse_Ok) ProtoMessage()    {}
 func (*StatSummaryResponse_Ok) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{23, 0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{24, 0}
 }
 func (m *StatSummaryResponse_Ok) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatSummaryResponse_Ok.Unmarshal(m, b)
@@ -3572,25 +3742,45 @@ func (m *StatSummaryResponse_Ok) GetStatTables() []*StatTable {
 	return nil
 }
 
+func (m *StatSummaryResponse_Ok) GetPartial() bool {
+	if m != nil {
+		return m.Partial
+	}
+	return false
+}
+
+func (m *StatSummaryResponse_Ok) GetContinueToken() string {
+	if m != nil {
+		return m.ContinueToken
+	}
+	return ""
+}
+
 type BasicStats struct {
-	SuccessCount         uint64   `protobuf:"varint,1,opt,name=success_count,json=successCount,proto3" json:"success_count,omitempty"`
-	FailureCount         uint64   `protobuf:"varint,2,opt,name=failure_count,json=failureCount,proto3" json:"failure_count,omitempty"`
-	LatencyMsP50         uint64   `protobuf:"varint,3,opt,name=latency_ms_p50,json=latencyMsP50,proto3" json:"latency_ms_p50,omitempty"`
-	LatencyMsP95         uint64   `protobuf:"varint,4,opt,name=latency_ms_p95,json=latencyMsP95,proto3" json:"latency_ms_p95,omitempty"`
-	LatencyMsP99         uint64   `protobuf:"varint,5,opt,name=latency_ms_p99,json=latencyMsP99,proto3" json:"latency_ms_p99,omitempty"`
-	TlsRequestCount      uint64   `protobuf:"varint,6,opt,name=tls_request_count,json=tlsRequestCount,proto3" json:"tls_request_count,omitempty"`
-	ActualSuccessCount   uint64   `protobuf:"varint,7,opt,name=actual_success_count,json=actualSuccessCount,proto3" json:"actual_success_count,omitempty"`
-	ActualFailureCount   uint64   `protobuf:"varint,8,opt,name=actual_failure_count,json=actualFailureCount,proto3" json:"actual_failure_count,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	SuccessCount       uint64 `protobuf:"varint,1,opt,name=success_count,json=successCount,proto3" json:"success_count,omitempty"`
+	FailureCount       uint64 `protobuf:"varint,2,opt,name=failure_count,json=failureCount,proto3" json:"failure_count,omitempty"`
+	LatencyMsP50       uint64 `protobuf:"varint,3,opt,name=latency_ms_p50,json=latencyMsP50,proto3" json:"latency_ms_p50,omitempty"`
+	LatencyMsP95       uint64 `protobuf:"varint,4,opt,name=latency_ms_p95,json=latencyMsP95,proto3" json:"latency_ms_p95,omitempty"`
+	LatencyMsP99       uint64 `protobuf:"varint,5,opt,name=latency_ms_p99,json=latencyMsP99,proto3" json:"latency_ms_p99,omitempty"`
+	TlsRequestCount    uint64 `protobuf:"varint,6,opt,name=tls_request_count,json=tlsRequestCount,proto3" json:"tls_request_count,omitempty"`
+	ActualSuccessCount uint64 `protobuf:"varint,7,opt,name=actual_success_count,json=actualSuccessCount,proto3" json:"actual_success_count,omitempty"`
+	ActualFailureCount uint64 `protobuf:"varint,8,opt,name=actual_failure_count,json=actualFailureCount,proto3" json:"actual_failure_count,omitempty"`
+	// inbound requests from clients that didn't present an identity, i.e. that
+	// aren't meshed; only counted for inbound stats when TLS is enabled
+	UnmeshedRequestCount uint64 `protobuf:"varint,10,opt,name=unmeshed_request_count,json=unmeshedRequestCount,proto3" json:"unmeshed_request_count,omitempty"`
+	// number of gRPC responses by grpc-status, ordered by code; only set if
+	// the request asked for grpc_stats
+	GrpcStatusCounts     []*GrpcStatusCount `protobuf:"bytes,11,rep,name=grpc_status_counts,json=grpcStatusCounts,proto3" json:"grpc_status_counts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *BasicStats) Reset()         { *m = BasicStats{} }
 func (m *BasicStats) String() string { return proto.CompactTextString(m) }
 func (*BasicStats) ProtoMessage()    {}
 func (*BasicStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{24}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{25}
 }
 func (m *BasicStats) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BasicStats.Unmarshal(m, b)
@@ -3666,6 +3856,20 @@ func (m *BasicStats) GetActualFailureCount() uint64 {
 	return 0
 }
 
+func (m *BasicStats) GetUnmeshedRequestCount() uint64 {
+	if m != nil {
+		return m.UnmeshedRequestCount
+	}
+	return 0
+}
+
+func (m *BasicStats) GetGrpcStatusCounts() []*GrpcStatusCount {
+	if m != nil {
+		return m.GrpcStatusCounts
+	}
+	return nil
+}
+
 type StatTable struct {
 	// Types that are valid to be assigned to Table:
 	//	*StatTable_PodGroup_
@@ -3679,7 +3883,7 @@ func (m *StatTable) Reset()         { *m = StatTable{} }
 func (m *StatTable) String() string { return proto.CompactTextString(m) }
 func (*StatTable) ProtoMessage()    {}
 func (*StatTable) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{25}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{26}
 }
 func (m *StatTable) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatTable.Unmarshal(m, b)
@@ -3789,7 +3993,7 @@ func (m *StatTable_PodGroup) Reset()         { *m = StatTable_PodGroup{} }
 func (m *StatTable_PodGroup) String() string { return proto.CompactTextString(m) }
 func (*StatTable_PodGroup) ProtoMessage()    {}
 func (*StatTable_PodGroup) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{25, 0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{26, 0}
 }
 func (m *StatTable_PodGroup) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatTable_PodGroup.Unmarshal(m, b)
@@ -3827,17 +4031,30 @@ type StatTable_PodGroup_Row struct {
 	FailedPodCount uint64      `protobuf:"varint,6,opt,name=failed_pod_count,json=failedPodCount,proto3" json:"failed_pod_count,omitempty"`
 	Stats          *BasicStats `protobuf:"bytes,5,opt,name=stats,proto3" json:"stats,omitempty"`
 	// Stores a set of errors for each pod name. If a pod has no errors, it may be omitted.
-	ErrorsByPod          map[string]*PodErrors `protobuf:"bytes,7,rep,name=errors_by_pod,json=errorsByPod,proto3" json:"errors_by_pod,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	ErrorsByPod map[string]*PodErrors `protobuf:"bytes,7,rep,name=errors_by_pod,json=errorsByPod,proto3" json:"errors_by_pod,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// number of container restarts, summed over the pods in this resource
+	RestartCount uint64 `protobuf:"varint,8,opt,name=restart_count,json=restartCount,proto3" json:"restart_count,omitempty"`
+	// number of pending or running pods in this resource whose proxy container is ready
+	ProxyReadyPodCount uint64 `protobuf:"varint,9,opt,name=proxy_ready_pod_count,json=proxyReadyPodCount,proto3" json:"proxy_ready_pod_count,omitempty"`
+	// target success rate of this resource, between 0 and 1, from its
+	// linkerd.io/slo-success-rate annotation; 0 if it has none
+	SloSuccessRate float64 `protobuf:"fixed64,10,opt,name=slo_success_rate,json=sloSuccessRate,proto3" json:"slo_success_rate,omitempty"`
+	// apex service, leaf service and weight of the backend of the traffic
+	// split that this row is about, for trafficsplit rows only
+	TsStats *TrafficSplitStats `protobuf:"bytes,11,opt,name=ts_stats,json=tsStats,proto3" json:"ts_stats,omitempty"`
+	// stats of each authority or route of the requests, ordered by them,
+	// when the request sets group_by
+	GroupStats           []*GroupStats `protobuf:"bytes,12,rep,name=group_stats,json=groupStats,proto3" json:"group_stats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *StatTable_PodGroup_Row) Reset()         { *m = StatTable_PodGroup_Row{} }
 func (m *StatTable_PodGroup_Row) String() string { return proto.CompactTextString(m) }
 func (*StatTable_PodGroup_Row) ProtoMessage()    {}
 func (*StatTable_PodGroup_Row) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{25, 0, 0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{26, 0, 0}
 }
 func (m *StatTable_PodGroup_Row) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatTable_PodGroup_Row.Unmarshal(m, b)
@@ -3906,23 +4123,60 @@ func (m *StatTable_PodGroup_Row) GetErrorsByPod() map[string]*PodErrors {
 	return nil
 }
 
+func (m *StatTable_PodGroup_Row) GetRestartCount() uint64 {
+	if m != nil {
+		return m.RestartCount
+	}
+	return 0
+}
+
+func (m *StatTable_PodGroup_Row) GetProxyReadyPodCount() uint64 {
+	if m != nil {
+		return m.ProxyReadyPodCount
+	}
+	return 0
+}
+
+func (m *StatTable_PodGroup_Row) GetSloSuccessRate() float64 {
+	if m != nil {
+		return m.SloSuccessRate
+	}
+	return 0
+}
+
+func (m *StatTable_PodGroup_Row) GetTsStats() *TrafficSplitStats {
+	if m != nil {
+		return m.TsStats
+	}
+	return nil
+}
+
+func (m *StatTable_PodGroup_Row) GetGroupStats() []*GroupStats {
+	if m != nil {
+		return m.GroupStats
+	}
+	return nil
+}
+
 type TopRoutesRequest struct {
 	Selector   *ResourceSelection `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
 	TimeWindow string             `protobuf:"bytes,2,opt,name=time_window,json=timeWindow,proto3" json:"time_window,omitempty"`
 	// Types that are valid to be assigned to Outbound:
 	//	*TopRoutesRequest_None
 	//	*TopRoutesRequest_ToResource
-	Outbound             isTopRoutesRequest_Outbound `protobuf_oneof:"outbound"`
-	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
-	XXX_unrecognized     []byte                      `json:"-"`
-	XXX_sizecache        int32                       `json:"-"`
+	Outbound isTopRoutesRequest_Outbound `protobuf_oneof:"outbound"`
+	// also count the gRPC responses by grpc-status
+	GrpcStats            bool     `protobuf:"varint,8,opt,name=grpc_stats,json=grpcStats,proto3" json:"grpc_stats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *TopRoutesRequest) Reset()         { *m = TopRoutesRequest{} }
 func (m *TopRoutesRequest) String() string { return proto.CompactTextString(m) }
 func (*TopRoutesRequest) ProtoMessage()    {}
 func (*TopRoutesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{26}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{27}
 }
 func (m *TopRoutesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TopRoutesRequest.Unmarshal(m, b)
@@ -3993,6 +4247,13 @@ func (m *TopRoutesRequest) GetToResource() *Resource {
 	return nil
 }
 
+func (m *TopRoutesRequest) GetGrpcStats() bool {
+	if m != nil {
+		return m.GrpcStats
+	}
+	return false
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*TopRoutesRequest) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _TopRoutesRequest_OneofMarshaler, _TopRoutesRequest_OneofUnmarshaler, _TopRoutesRequest_OneofSizer, []interface{}{
@@ -4081,7 +4342,7 @@ func (m *TopRoutesResponse) Reset()         { *m = TopRoutesResponse{} }
 func (m *TopRoutesResponse) String() string { return proto.CompactTextString(m) }
 func (*TopRoutesResponse) ProtoMessage()    {}
 func (*TopRoutesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{27}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{28}
 }
 func (m *TopRoutesResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TopRoutesResponse.Unmarshal(m, b)
@@ -4223,7 +4484,7 @@ func (m *TopRoutesResponse_Ok) Reset()         { *m = TopRoutesResponse_Ok{} }
 func (m *TopRoutesResponse_Ok) String() string { return proto.CompactTextString(m) }
 func (*TopRoutesResponse_Ok) ProtoMessage()    {}
 func (*TopRoutesResponse_Ok) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{27, 0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{28, 0}
 }
 func (m *TopRoutesResponse_Ok) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TopRoutesResponse_Ok.Unmarshal(m, b)
@@ -4262,7 +4523,7 @@ func (m *RouteTable) Reset()         { *m = RouteTable{} }
 func (m *RouteTable) String() string { return proto.CompactTextString(m) }
 func (*RouteTable) ProtoMessage()    {}
 func (*RouteTable) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{28}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{29}
 }
 func (m *RouteTable) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RouteTable.Unmarshal(m, b)
@@ -4310,7 +4571,7 @@ func (m *RouteTable_Row) Reset()         { *m = RouteTable_Row{} }
 func (m *RouteTable_Row) String() string { return proto.CompactTextString(m) }
 func (*RouteTable_Row) ProtoMessage()    {}
 func (*RouteTable_Row) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_38b36360ab7d5fc2, []int{28, 0}
+	return fileDescriptor_public_c5f56ff279f0282e, []int{29, 0}
 }
 func (m *RouteTable_Row) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RouteTable_Row.Unmarshal(m, b)
@@ -4358,97 +4619,824 @@ func (m *RouteTable_Row) GetStats() *BasicStats {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*Empty)(nil), "linkerd2.public.Empty")
-	proto.RegisterType((*VersionInfo)(nil), "linkerd2.public.VersionInfo")
-	proto.RegisterType((*ListServicesRequest)(nil), "linkerd2.public.ListServicesRequest")
-	proto.RegisterType((*ListServicesResponse)(nil), "linkerd2.public.ListServicesResponse")
-	proto.RegisterType((*Service)(nil), "linkerd2.public.Service")
-	proto.RegisterType((*ListPodsRequest)(nil), "linkerd2.public.ListPodsRequest")
-	proto.RegisterType((*ListPodsResponse)(nil), "linkerd2.public.ListPodsResponse")
-	proto.RegisterType((*Pod)(nil), "linkerd2.public.Pod")
-	proto.RegisterType((*TapRequest)(nil), "linkerd2.public.TapRequest")
-	proto.RegisterType((*TapByResourceRequest)(nil), "linkerd2.public.TapByResourceRequest")
-	proto.RegisterType((*TapByResourceRequest_Match)(nil), "linkerd2.public.TapByResourceRequest.Match")
-	proto.RegisterType((*TapByResourceRequest_Match_Seq)(nil), "linkerd2.public.TapByResourceRequest.Match.Seq")
-	proto.RegisterType((*TapByResourceRequest_Match_Http)(nil), "linkerd2.public.TapByResourceRequest.Match.Http")
-	proto.RegisterType((*HttpMethod)(nil), "linkerd2.public.HttpMethod")
-	proto.RegisterType((*Scheme)(nil), "linkerd2.public.Scheme")
-	proto.RegisterType((*IPAddress)(nil), "linkerd2.public.IPAddress")
-	proto.RegisterType((*IPv6)(nil), "linkerd2.public.IPv6")
-	proto.RegisterType((*TcpAddress)(nil), "linkerd2.public.TcpAddress")
-	proto.RegisterType((*Eos)(nil), "linkerd2.public.Eos")
-	proto.RegisterType((*TapEvent)(nil), "linkerd2.public.TapEvent")
-	proto.RegisterType((*TapEvent_EndpointMeta)(nil), "linkerd2.public.TapEvent.EndpointMeta")
-	proto.RegisterMapType((map[string]string)(nil), "linkerd2.public.TapEvent.EndpointMeta.LabelsEntry")
-	proto.RegisterType((*TapEvent_RouteMeta)(nil), "linkerd2.public.TapEvent.RouteMeta")
-	proto.RegisterMapType((map[string]string)(nil), "linkerd2.public.TapEvent.RouteMeta.LabelsEntry")
-	proto.RegisterType((*TapEvent_Http)(nil), "linkerd2.public.TapEvent.Http")
-	proto.RegisterType((*TapEvent_Http_StreamId)(nil), "linkerd2.public.TapEvent.Http.StreamId")
-	proto.RegisterType((*TapEvent_Http_RequestInit)(nil), "linkerd2.public.TapEvent.Http.RequestInit")
-	proto.RegisterType((*TapEvent_Http_ResponseInit)(nil), "linkerd2.public.TapEvent.Http.ResponseInit")
-	proto.RegisterType((*TapEvent_Http_ResponseEnd)(nil), "linkerd2.public.TapEvent.Http.ResponseEnd")
-	proto.RegisterType((*ApiError)(nil), "linkerd2.public.ApiError")
-	proto.RegisterType((*PodErrors)(nil), "linkerd2.public.PodErrors")
-	proto.RegisterType((*PodErrors_PodError)(nil), "linkerd2.public.PodErrors.PodError")
-	proto.RegisterType((*PodErrors_PodError_ContainerError)(nil), "linkerd2.public.PodErrors.PodError.ContainerError")
-	proto.RegisterType((*Resource)(nil), "linkerd2.public.Resource")
-	proto.RegisterType((*ResourceSelection)(nil), "linkerd2.public.ResourceSelection")
-	proto.RegisterType((*ResourceError)(nil), "linkerd2.public.ResourceError")
-	proto.RegisterType((*StatSummaryRequest)(nil), "linkerd2.public.StatSummaryRequest")
-	proto.RegisterType((*StatSummaryResponse)(nil), "linkerd2.public.StatSummaryResponse")
-	proto.RegisterType((*StatSummaryResponse_Ok)(nil), "linkerd2.public.StatSummaryResponse.Ok")
-	proto.RegisterType((*BasicStats)(nil), "linkerd2.public.BasicStats")
-	proto.RegisterType((*StatTable)(nil), "linkerd2.public.StatTable")
-	proto.RegisterType((*StatTable_PodGroup)(nil), "linkerd2.public.StatTable.PodGroup")
-	proto.RegisterType((*StatTable_PodGroup_Row)(nil), "linkerd2.public.StatTable.PodGroup.Row")
-	proto.RegisterMapType((map[string]*PodErrors)(nil), "linkerd2.public.StatTable.PodGroup.Row.ErrorsByPodEntry")
-	proto.RegisterType((*TopRoutesRequest)(nil), "linkerd2.public.TopRoutesRequest")
-	proto.RegisterType((*TopRoutesResponse)(nil), "linkerd2.public.TopRoutesResponse")
-	proto.RegisterType((*TopRoutesResponse_Ok)(nil), "linkerd2.public.TopRoutesResponse.Ok")
-	proto.RegisterType((*RouteTable)(nil), "linkerd2.public.RouteTable")
-	proto.RegisterType((*RouteTable_Row)(nil), "linkerd2.public.RouteTable.Row")
-	proto.RegisterEnum("linkerd2.public.HttpMethod_Registered", HttpMethod_Registered_name, HttpMethod_Registered_value)
-	proto.RegisterEnum("linkerd2.public.Scheme_Registered", Scheme_Registered_name, Scheme_Registered_value)
-	proto.RegisterEnum("linkerd2.public.TapEvent_ProxyDirection", TapEvent_ProxyDirection_name, TapEvent_ProxyDirection_value)
+type EdgesRequest struct {
+	// Selects the resources whose inbound and outbound edges are reported. The
+	// resource type is required; the namespace and name are optional.
+	Selector             *ResourceSelection `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	TimeWindow           string             `protobuf:"bytes,2,opt,name=time_window,json=timeWindow,proto3" json:"time_window,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *EdgesRequest) Reset()         { *m = EdgesRequest{} }
+func (m *EdgesRequest) String() string { return proto.CompactTextString(m) }
+func (*EdgesRequest) ProtoMessage()    {}
+func (*EdgesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_c5f56ff279f0282e, []int{30}
+}
+func (m *EdgesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EdgesRequest.Unmarshal(m, b)
+}
+func (m *EdgesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EdgesRequest.Marshal(b, m, deterministic)
+}
+func (dst *EdgesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EdgesRequest.Merge(dst, src)
+}
+func (m *EdgesRequest) XXX_Size() int {
+	return xxx_messageInfo_EdgesRequest.Size(m)
+}
+func (m *EdgesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EdgesRequest.DiscardUnknown(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_EdgesRequest proto.InternalMessageInfo
 
-// ApiClient is the client API for Api service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ApiClient interface {
-	StatSummary(ctx context.Context, in *StatSummaryRequest, opts ...grpc.CallOption) (*StatSummaryResponse, error)
-	TopRoutes(ctx context.Context, in *TopRoutesRequest, opts ...grpc.CallOption) (*TopRoutesResponse, error)
-	ListPods(ctx context.Context, in *ListPodsRequest, opts ...grpc.CallOption) (*ListPodsResponse, error)
-	ListServices(ctx context.Context, in *ListServicesRequest, opts ...grpc.CallOption) (*ListServicesResponse, error)
-	// Superceded by `TapByResource`.
-	Tap(ctx context.Context, in *TapRequest, opts ...grpc.CallOption) (Api_TapClient, error)
-	// Executes tapping over Kubernetes resources.
-	TapByResource(ctx context.Context, in *TapByResourceRequest, opts ...grpc.CallOption) (Api_TapByResourceClient, error)
-	Version(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*VersionInfo, error)
-	SelfCheck(ctx context.Context, in *healthcheck.SelfCheckRequest, opts ...grpc.CallOption) (*healthcheck.SelfCheckResponse, error)
+func (m *EdgesRequest) GetSelector() *ResourceSelection {
+	if m != nil {
+		return m.Selector
+	}
+	return nil
 }
 
-type apiClient struct {
-	cc *grpc.ClientConn
+func (m *EdgesRequest) GetTimeWindow() string {
+	if m != nil {
+		return m.TimeWindow
+	}
+	return ""
 }
 
-func NewApiClient(cc *grpc.ClientConn) ApiClient {
-	return &apiClient{cc}
+type EdgesResponse struct {
+	// Types that are valid to be assigned to Response:
+	//	*EdgesResponse_Ok_
+	//	*EdgesResponse_Error
+	Response             isEdgesResponse_Response `protobuf_oneof:"response"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
 }
 
-func (c *apiClient) StatSummary(ctx context.Context, in *StatSummaryRequest, opts ...grpc.CallOption) (*StatSummaryResponse, error) {
-	out := new(StatSummaryResponse)
-	err := c.cc.Invoke(ctx, "/linkerd2.public.Api/StatSummary", in, out, opts...)
-	if err != nil {
+func (m *EdgesResponse) Reset()         { *m = EdgesResponse{} }
+func (m *EdgesResponse) String() string { return proto.CompactTextString(m) }
+func (*EdgesResponse) ProtoMessage()    {}
+func (*EdgesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_c5f56ff279f0282e, []int{31}
+}
+func (m *EdgesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EdgesResponse.Unmarshal(m, b)
+}
+func (m *EdgesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EdgesResponse.Marshal(b, m, deterministic)
+}
+func (dst *EdgesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EdgesResponse.Merge(dst, src)
+}
+func (m *EdgesResponse) XXX_Size() int {
+	return xxx_messageInfo_EdgesResponse.Size(m)
+}
+func (m *EdgesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EdgesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EdgesResponse proto.InternalMessageInfo
+
+type isEdgesResponse_Response interface {
+	isEdgesResponse_Response()
+}
+
+type EdgesResponse_Ok_ struct {
+	Ok *EdgesResponse_Ok `protobuf:"bytes,1,opt,name=ok,proto3,oneof"`
+}
+
+type EdgesResponse_Error struct {
+	Error *ResourceError `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*EdgesResponse_Ok_) isEdgesResponse_Response() {}
+
+func (*EdgesResponse_Error) isEdgesResponse_Response() {}
+
+func (m *EdgesResponse) GetResponse() isEdgesResponse_Response {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+func (m *EdgesResponse) GetOk() *EdgesResponse_Ok {
+	if x, ok := m.GetResponse().(*EdgesResponse_Ok_); ok {
+		return x.Ok
+	}
+	return nil
+}
+
+func (m *EdgesResponse) GetError() *ResourceError {
+	if x, ok := m.GetResponse().(*EdgesResponse_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*EdgesResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _EdgesResponse_OneofMarshaler, _EdgesResponse_OneofUnmarshaler, _EdgesResponse_OneofSizer, []interface{}{
+		(*EdgesResponse_Ok_)(nil),
+		(*EdgesResponse_Error)(nil),
+	}
+}
+
+func _EdgesResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*EdgesResponse)
+	// response
+	switch x := m.Response.(type) {
+	case *EdgesResponse_Ok_:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Ok); err != nil {
+			return err
+		}
+	case *EdgesResponse_Error:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Error); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("EdgesResponse.Response has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _EdgesResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*EdgesResponse)
+	switch tag {
+	case 1: // response.ok
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(EdgesResponse_Ok)
+		err := b.DecodeMessage(msg)
+		m.Response = &EdgesResponse_Ok_{msg}
+		return true, err
+	case 2: // response.error
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ResourceError)
+		err := b.DecodeMessage(msg)
+		m.Response = &EdgesResponse_Error{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _EdgesResponse_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*EdgesResponse)
+	// response
+	switch x := m.Response.(type) {
+	case *EdgesResponse_Ok_:
+		s := proto.Size(x.Ok)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *EdgesResponse_Error:
+		s := proto.Size(x.Error)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+type EdgesResponse_Ok struct {
+	Edges                []*Edge  `protobuf:"bytes,1,rep,name=edges,proto3" json:"edges,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EdgesResponse_Ok) Reset()         { *m = EdgesResponse_Ok{} }
+func (m *EdgesResponse_Ok) String() string { return proto.CompactTextString(m) }
+func (*EdgesResponse_Ok) ProtoMessage()    {}
+func (*EdgesResponse_Ok) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_c5f56ff279f0282e, []int{31, 0}
+}
+func (m *EdgesResponse_Ok) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EdgesResponse_Ok.Unmarshal(m, b)
+}
+func (m *EdgesResponse_Ok) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EdgesResponse_Ok.Marshal(b, m, deterministic)
+}
+func (dst *EdgesResponse_Ok) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EdgesResponse_Ok.Merge(dst, src)
+}
+func (m *EdgesResponse_Ok) XXX_Size() int {
+	return xxx_messageInfo_EdgesResponse_Ok.Size(m)
+}
+func (m *EdgesResponse_Ok) XXX_DiscardUnknown() {
+	xxx_messageInfo_EdgesResponse_Ok.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EdgesResponse_Ok proto.InternalMessageInfo
+
+func (m *EdgesResponse_Ok) GetEdges() []*Edge {
+	if m != nil {
+		return m.Edges
+	}
+	return nil
+}
+
+// An edge is a pair of resources between which traffic has been observed.
+type Edge struct {
+	Src *Resource `protobuf:"bytes,1,opt,name=src,proto3" json:"src,omitempty"`
+	Dst *Resource `protobuf:"bytes,2,opt,name=dst,proto3" json:"dst,omitempty"`
+	// true if all of the requests observed on this edge were sent over TLS.
+	Tls bool `protobuf:"varint,3,opt,name=tls,proto3" json:"tls,omitempty"`
+	// true if src is the unmeshed clients of dst rather than a resource; only
+	// the type of src is set.
+	UnmeshedSrc bool `protobuf:"varint,4,opt,name=unmeshed_src,json=unmeshedSrc,proto3" json:"unmeshed_src,omitempty"`
+	// The TLS identities of the proxies of src and dst, if the edge is secured
+	// by TLS and they have a single identity.
+	ClientId string `protobuf:"bytes,5,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ServerId string `protobuf:"bytes,6,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	// Explains why client_id and server_id aren't set, e.g. "source not meshed".
+	NoIdentityMsg string `protobuf:"bytes,7,opt,name=no_identity_msg,json=noIdentityMsg,proto3" json:"no_identity_msg,omitempty"`
+	// The Unix time in seconds of the latest metrics reported for this edge.
+	LastSeen             int64    `protobuf:"varint,8,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Edge) Reset()         { *m = Edge{} }
+func (m *Edge) String() string { return proto.CompactTextString(m) }
+func (*Edge) ProtoMessage()    {}
+func (*Edge) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_c5f56ff279f0282e, []int{32}
+}
+func (m *Edge) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Edge.Unmarshal(m, b)
+}
+func (m *Edge) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Edge.Marshal(b, m, deterministic)
+}
+func (dst *Edge) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Edge.Merge(dst, src)
+}
+func (m *Edge) XXX_Size() int {
+	return xxx_messageInfo_Edge.Size(m)
+}
+func (m *Edge) XXX_DiscardUnknown() {
+	xxx_messageInfo_Edge.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Edge proto.InternalMessageInfo
+
+func (m *Edge) GetSrc() *Resource {
+	if m != nil {
+		return m.Src
+	}
+	return nil
+}
+
+func (m *Edge) GetDst() *Resource {
+	if m != nil {
+		return m.Dst
+	}
+	return nil
+}
+
+func (m *Edge) GetTls() bool {
+	if m != nil {
+		return m.Tls
+	}
+	return false
+}
+
+func (m *Edge) GetUnmeshedSrc() bool {
+	if m != nil {
+		return m.UnmeshedSrc
+	}
+	return false
+}
+
+func (m *Edge) GetClientId() string {
+	if m != nil {
+		return m.ClientId
+	}
+	return ""
+}
+
+func (m *Edge) GetServerId() string {
+	if m != nil {
+		return m.ServerId
+	}
+	return ""
+}
+
+func (m *Edge) GetNoIdentityMsg() string {
+	if m != nil {
+		return m.NoIdentityMsg
+	}
+	return ""
+}
+
+func (m *Edge) GetLastSeen() int64 {
+	if m != nil {
+		return m.LastSeen
+	}
+	return 0
+}
+
+type EdgeEvent struct {
+	Type                 EdgeEvent_Type `protobuf:"varint,1,opt,name=type,proto3,enum=linkerd2.public.EdgeEvent_Type" json:"type,omitempty"`
+	Edge                 *Edge          `protobuf:"bytes,2,opt,name=edge,proto3" json:"edge,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *EdgeEvent) Reset()         { *m = EdgeEvent{} }
+func (m *EdgeEvent) String() string { return proto.CompactTextString(m) }
+func (*EdgeEvent) ProtoMessage()    {}
+func (*EdgeEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_c5f56ff279f0282e, []int{33}
+}
+func (m *EdgeEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EdgeEvent.Unmarshal(m, b)
+}
+func (m *EdgeEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EdgeEvent.Marshal(b, m, deterministic)
+}
+func (dst *EdgeEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EdgeEvent.Merge(dst, src)
+}
+func (m *EdgeEvent) XXX_Size() int {
+	return xxx_messageInfo_EdgeEvent.Size(m)
+}
+func (m *EdgeEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_EdgeEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EdgeEvent proto.InternalMessageInfo
+
+func (m *EdgeEvent) GetType() EdgeEvent_Type {
+	if m != nil {
+		return m.Type
+	}
+	return EdgeEvent_ADD
+}
+
+func (m *EdgeEvent) GetEdge() *Edge {
+	if m != nil {
+		return m.Edge
+	}
+	return nil
+}
+
+type GatewaysRequest struct {
+	// Only reports the gateway of this remote cluster, if set.
+	RemoteClusterName    string   `protobuf:"bytes,1,opt,name=remote_cluster_name,json=remoteClusterName,proto3" json:"remote_cluster_name,omitempty"`
+	TimeWindow           string   `protobuf:"bytes,2,opt,name=time_window,json=timeWindow,proto3" json:"time_window,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GatewaysRequest) Reset()         { *m = GatewaysRequest{} }
+func (m *GatewaysRequest) String() string { return proto.CompactTextString(m) }
+func (*GatewaysRequest) ProtoMessage()    {}
+func (*GatewaysRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_c5f56ff279f0282e, []int{34}
+}
+func (m *GatewaysRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GatewaysRequest.Unmarshal(m, b)
+}
+func (m *GatewaysRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GatewaysRequest.Marshal(b, m, deterministic)
+}
+func (dst *GatewaysRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GatewaysRequest.Merge(dst, src)
+}
+func (m *GatewaysRequest) XXX_Size() int {
+	return xxx_messageInfo_GatewaysRequest.Size(m)
+}
+func (m *GatewaysRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GatewaysRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GatewaysRequest proto.InternalMessageInfo
+
+func (m *GatewaysRequest) GetRemoteClusterName() string {
+	if m != nil {
+		return m.RemoteClusterName
+	}
+	return ""
+}
+
+func (m *GatewaysRequest) GetTimeWindow() string {
+	if m != nil {
+		return m.TimeWindow
+	}
+	return ""
+}
+
+type GatewaysResponse struct {
+	Gateways             []*GatewaysResponse_Gateway `protobuf:"bytes,1,rep,name=gateways,proto3" json:"gateways,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
+}
+
+func (m *GatewaysResponse) Reset()         { *m = GatewaysResponse{} }
+func (m *GatewaysResponse) String() string { return proto.CompactTextString(m) }
+func (*GatewaysResponse) ProtoMessage()    {}
+func (*GatewaysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_c5f56ff279f0282e, []int{35}
+}
+func (m *GatewaysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GatewaysResponse.Unmarshal(m, b)
+}
+func (m *GatewaysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GatewaysResponse.Marshal(b, m, deterministic)
+}
+func (dst *GatewaysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GatewaysResponse.Merge(dst, src)
+}
+func (m *GatewaysResponse) XXX_Size() int {
+	return xxx_messageInfo_GatewaysResponse.Size(m)
+}
+func (m *GatewaysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GatewaysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GatewaysResponse proto.InternalMessageInfo
+
+func (m *GatewaysResponse) GetGateways() []*GatewaysResponse_Gateway {
+	if m != nil {
+		return m.Gateways
+	}
+	return nil
+}
+
+// The gateway of a remote cluster that is linked to this one.
+type GatewaysResponse_Gateway struct {
+	ClusterName string `protobuf:"bytes,1,opt,name=cluster_name,json=clusterName,proto3" json:"cluster_name,omitempty"`
+	// The address and port of the gateway, as configured in the Link.
+	Address string `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	// true if the service mirror's last probe of the gateway succeeded.
+	Alive bool `protobuf:"varint,3,opt,name=alive,proto3" json:"alive,omitempty"`
+	// The number of remote services that are mirrored in this cluster.
+	PairedServices       uint64   `protobuf:"varint,4,opt,name=paired_services,json=pairedServices,proto3" json:"paired_services,omitempty"`
+	LatencyMsP50         uint64   `protobuf:"varint,5,opt,name=latency_ms_p50,json=latencyMsP50,proto3" json:"latency_ms_p50,omitempty"`
+	LatencyMsP95         uint64   `protobuf:"varint,6,opt,name=latency_ms_p95,json=latencyMsP95,proto3" json:"latency_ms_p95,omitempty"`
+	LatencyMsP99         uint64   `protobuf:"varint,7,opt,name=latency_ms_p99,json=latencyMsP99,proto3" json:"latency_ms_p99,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GatewaysResponse_Gateway) Reset()         { *m = GatewaysResponse_Gateway{} }
+func (m *GatewaysResponse_Gateway) String() string { return proto.CompactTextString(m) }
+func (*GatewaysResponse_Gateway) ProtoMessage()    {}
+func (*GatewaysResponse_Gateway) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_c5f56ff279f0282e, []int{35, 0}
+}
+func (m *GatewaysResponse_Gateway) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GatewaysResponse_Gateway.Unmarshal(m, b)
+}
+func (m *GatewaysResponse_Gateway) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GatewaysResponse_Gateway.Marshal(b, m, deterministic)
+}
+func (dst *GatewaysResponse_Gateway) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GatewaysResponse_Gateway.Merge(dst, src)
+}
+func (m *GatewaysResponse_Gateway) XXX_Size() int {
+	return xxx_messageInfo_GatewaysResponse_Gateway.Size(m)
+}
+func (m *GatewaysResponse_Gateway) XXX_DiscardUnknown() {
+	xxx_messageInfo_GatewaysResponse_Gateway.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GatewaysResponse_Gateway proto.InternalMessageInfo
+
+func (m *GatewaysResponse_Gateway) GetClusterName() string {
+	if m != nil {
+		return m.ClusterName
+	}
+	return ""
+}
+
+func (m *GatewaysResponse_Gateway) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *GatewaysResponse_Gateway) GetAlive() bool {
+	if m != nil {
+		return m.Alive
+	}
+	return false
+}
+
+func (m *GatewaysResponse_Gateway) GetPairedServices() uint64 {
+	if m != nil {
+		return m.PairedServices
+	}
+	return 0
+}
+
+func (m *GatewaysResponse_Gateway) GetLatencyMsP50() uint64 {
+	if m != nil {
+		return m.LatencyMsP50
+	}
+	return 0
+}
+
+func (m *GatewaysResponse_Gateway) GetLatencyMsP95() uint64 {
+	if m != nil {
+		return m.LatencyMsP95
+	}
+	return 0
+}
+
+func (m *GatewaysResponse_Gateway) GetLatencyMsP99() uint64 {
+	if m != nil {
+		return m.LatencyMsP99
+	}
+	return 0
+}
+
+type GrpcStatusCount struct {
+	// the grpc-status code, e.g. 0 for OK and 14 for UNAVAILABLE
+	Code                 uint32   `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Count                uint64   `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GrpcStatusCount) Reset()         { *m = GrpcStatusCount{} }
+func (m *GrpcStatusCount) String() string { return proto.CompactTextString(m) }
+func (*GrpcStatusCount) ProtoMessage()    {}
+func (*GrpcStatusCount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_c5f56ff279f0282e, []int{36}
+}
+func (m *GrpcStatusCount) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GrpcStatusCount.Unmarshal(m, b)
+}
+func (m *GrpcStatusCount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GrpcStatusCount.Marshal(b, m, deterministic)
+}
+func (dst *GrpcStatusCount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GrpcStatusCount.Merge(dst, src)
+}
+func (m *GrpcStatusCount) XXX_Size() int {
+	return xxx_messageInfo_GrpcStatusCount.Size(m)
+}
+func (m *GrpcStatusCount) XXX_DiscardUnknown() {
+	xxx_messageInfo_GrpcStatusCount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GrpcStatusCount proto.InternalMessageInfo
+
+func (m *GrpcStatusCount) GetCode() uint32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *GrpcStatusCount) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type TrafficSplitStats struct {
+	Apex string `protobuf:"bytes,1,opt,name=apex,proto3" json:"apex,omitempty"`
+	Leaf string `protobuf:"bytes,2,opt,name=leaf,proto3" json:"leaf,omitempty"`
+	// weight of the leaf, as given in the TrafficSplit, e.g. "500m"
+	Weight               string   `protobuf:"bytes,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TrafficSplitStats) Reset()         { *m = TrafficSplitStats{} }
+func (m *TrafficSplitStats) String() string { return proto.CompactTextString(m) }
+func (*TrafficSplitStats) ProtoMessage()    {}
+func (*TrafficSplitStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_c5f56ff279f0282e, []int{37}
+}
+func (m *TrafficSplitStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrafficSplitStats.Unmarshal(m, b)
+}
+func (m *TrafficSplitStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrafficSplitStats.Marshal(b, m, deterministic)
+}
+func (dst *TrafficSplitStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrafficSplitStats.Merge(dst, src)
+}
+func (m *TrafficSplitStats) XXX_Size() int {
+	return xxx_messageInfo_TrafficSplitStats.Size(m)
+}
+func (m *TrafficSplitStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrafficSplitStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrafficSplitStats proto.InternalMessageInfo
+
+func (m *TrafficSplitStats) GetApex() string {
+	if m != nil {
+		return m.Apex
+	}
+	return ""
+}
+
+func (m *TrafficSplitStats) GetLeaf() string {
+	if m != nil {
+		return m.Leaf
+	}
+	return ""
+}
+
+func (m *TrafficSplitStats) GetWeight() string {
+	if m != nil {
+		return m.Weight
+	}
+	return ""
+}
+
+type GroupStats struct {
+	// the authority or route that the stats are about
+	Group                string      `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Stats                *BasicStats `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *GroupStats) Reset()         { *m = GroupStats{} }
+func (m *GroupStats) String() string { return proto.CompactTextString(m) }
+func (*GroupStats) ProtoMessage()    {}
+func (*GroupStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_public_c5f56ff279f0282e, []int{38}
+}
+func (m *GroupStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupStats.Unmarshal(m, b)
+}
+func (m *GroupStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupStats.Marshal(b, m, deterministic)
+}
+func (dst *GroupStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupStats.Merge(dst, src)
+}
+func (m *GroupStats) XXX_Size() int {
+	return xxx_messageInfo_GroupStats.Size(m)
+}
+func (m *GroupStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupStats proto.InternalMessageInfo
+
+func (m *GroupStats) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *GroupStats) GetStats() *BasicStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Empty)(nil), "linkerd2.public.Empty")
+	proto.RegisterType((*VersionInfo)(nil), "linkerd2.public.VersionInfo")
+	proto.RegisterType((*TrustBundleResponse)(nil), "linkerd2.public.TrustBundleResponse")
+	proto.RegisterType((*ListServicesRequest)(nil), "linkerd2.public.ListServicesRequest")
+	proto.RegisterType((*ListServicesResponse)(nil), "linkerd2.public.ListServicesResponse")
+	proto.RegisterType((*Service)(nil), "linkerd2.public.Service")
+	proto.RegisterType((*ListPodsRequest)(nil), "linkerd2.public.ListPodsRequest")
+	proto.RegisterType((*ListPodsResponse)(nil), "linkerd2.public.ListPodsResponse")
+	proto.RegisterType((*Pod)(nil), "linkerd2.public.Pod")
+	proto.RegisterType((*TapRequest)(nil), "linkerd2.public.TapRequest")
+	proto.RegisterType((*TapByResourceRequest)(nil), "linkerd2.public.TapByResourceRequest")
+	proto.RegisterType((*TapByResourceRequest_Match)(nil), "linkerd2.public.TapByResourceRequest.Match")
+	proto.RegisterType((*TapByResourceRequest_Match_Seq)(nil), "linkerd2.public.TapByResourceRequest.Match.Seq")
+	proto.RegisterType((*TapByResourceRequest_Match_Http)(nil), "linkerd2.public.TapByResourceRequest.Match.Http")
+	proto.RegisterType((*HttpMethod)(nil), "linkerd2.public.HttpMethod")
+	proto.RegisterType((*Scheme)(nil), "linkerd2.public.Scheme")
+	proto.RegisterType((*IPAddress)(nil), "linkerd2.public.IPAddress")
+	proto.RegisterType((*IPv6)(nil), "linkerd2.public.IPv6")
+	proto.RegisterType((*TcpAddress)(nil), "linkerd2.public.TcpAddress")
+	proto.RegisterType((*Eos)(nil), "linkerd2.public.Eos")
+	proto.RegisterType((*TapEvent)(nil), "linkerd2.public.TapEvent")
+	proto.RegisterType((*TapEvent_EndpointMeta)(nil), "linkerd2.public.TapEvent.EndpointMeta")
+	proto.RegisterMapType((map[string]string)(nil), "linkerd2.public.TapEvent.EndpointMeta.LabelsEntry")
+	proto.RegisterType((*TapEvent_RouteMeta)(nil), "linkerd2.public.TapEvent.RouteMeta")
+	proto.RegisterMapType((map[string]string)(nil), "linkerd2.public.TapEvent.RouteMeta.LabelsEntry")
+	proto.RegisterType((*TapEvent_Http)(nil), "linkerd2.public.TapEvent.Http")
+	proto.RegisterType((*TapEvent_Http_StreamId)(nil), "linkerd2.public.TapEvent.Http.StreamId")
+	proto.RegisterType((*TapEvent_Http_RequestInit)(nil), "linkerd2.public.TapEvent.Http.RequestInit")
+	proto.RegisterType((*TapEvent_Http_ResponseInit)(nil), "linkerd2.public.TapEvent.Http.ResponseInit")
+	proto.RegisterType((*TapEvent_Http_ResponseEnd)(nil), "linkerd2.public.TapEvent.Http.ResponseEnd")
+	proto.RegisterType((*ApiError)(nil), "linkerd2.public.ApiError")
+	proto.RegisterType((*PodErrors)(nil), "linkerd2.public.PodErrors")
+	proto.RegisterType((*PodErrors_PodError)(nil), "linkerd2.public.PodErrors.PodError")
+	proto.RegisterType((*PodErrors_PodError_ContainerError)(nil), "linkerd2.public.PodErrors.PodError.ContainerError")
+	proto.RegisterType((*Resource)(nil), "linkerd2.public.Resource")
+	proto.RegisterType((*ResourceSelection)(nil), "linkerd2.public.ResourceSelection")
+	proto.RegisterType((*ResourceError)(nil), "linkerd2.public.ResourceError")
+	proto.RegisterType((*StatSummaryRequest)(nil), "linkerd2.public.StatSummaryRequest")
+	proto.RegisterMapType((map[string]string)(nil), "linkerd2.public.StatSummaryRequest.MetricLabelsEntry")
+	proto.RegisterType((*StatSummaryResponse)(nil), "linkerd2.public.StatSummaryResponse")
+	proto.RegisterType((*StatSummaryResponse_Ok)(nil), "linkerd2.public.StatSummaryResponse.Ok")
+	proto.RegisterType((*BasicStats)(nil), "linkerd2.public.BasicStats")
+	proto.RegisterType((*StatTable)(nil), "linkerd2.public.StatTable")
+	proto.RegisterType((*StatTable_PodGroup)(nil), "linkerd2.public.StatTable.PodGroup")
+	proto.RegisterType((*StatTable_PodGroup_Row)(nil), "linkerd2.public.StatTable.PodGroup.Row")
+	proto.RegisterMapType((map[string]*PodErrors)(nil), "linkerd2.public.StatTable.PodGroup.Row.ErrorsByPodEntry")
+	proto.RegisterType((*TopRoutesRequest)(nil), "linkerd2.public.TopRoutesRequest")
+	proto.RegisterType((*TopRoutesResponse)(nil), "linkerd2.public.TopRoutesResponse")
+	proto.RegisterType((*TopRoutesResponse_Ok)(nil), "linkerd2.public.TopRoutesResponse.Ok")
+	proto.RegisterType((*RouteTable)(nil), "linkerd2.public.RouteTable")
+	proto.RegisterType((*RouteTable_Row)(nil), "linkerd2.public.RouteTable.Row")
+	proto.RegisterType((*EdgesRequest)(nil), "linkerd2.public.EdgesRequest")
+	proto.RegisterType((*EdgesResponse)(nil), "linkerd2.public.EdgesResponse")
+	proto.RegisterType((*EdgesResponse_Ok)(nil), "linkerd2.public.EdgesResponse.Ok")
+	proto.RegisterType((*Edge)(nil), "linkerd2.public.Edge")
+	proto.RegisterType((*EdgeEvent)(nil), "linkerd2.public.EdgeEvent")
+	proto.RegisterType((*GatewaysRequest)(nil), "linkerd2.public.GatewaysRequest")
+	proto.RegisterType((*GatewaysResponse)(nil), "linkerd2.public.GatewaysResponse")
+	proto.RegisterType((*GatewaysResponse_Gateway)(nil), "linkerd2.public.GatewaysResponse.Gateway")
+	proto.RegisterType((*GrpcStatusCount)(nil), "linkerd2.public.GrpcStatusCount")
+	proto.RegisterType((*TrafficSplitStats)(nil), "linkerd2.public.TrafficSplitStats")
+	proto.RegisterType((*GroupStats)(nil), "linkerd2.public.GroupStats")
+	proto.RegisterEnum("linkerd2.public.HttpMethod_Registered", HttpMethod_Registered_name, HttpMethod_Registered_value)
+	proto.RegisterEnum("linkerd2.public.Scheme_Registered", Scheme_Registered_name, Scheme_Registered_value)
+	proto.RegisterEnum("linkerd2.public.TapEvent_ProxyDirection", TapEvent_ProxyDirection_name, TapEvent_ProxyDirection_value)
+	proto.RegisterEnum("linkerd2.public.EdgeEvent_Type", EdgeEvent_Type_name, EdgeEvent_Type_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ApiClient is the client API for Api service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ApiClient interface {
+	StatSummary(ctx context.Context, in *StatSummaryRequest, opts ...grpc.CallOption) (*StatSummaryResponse, error)
+	TopRoutes(ctx context.Context, in *TopRoutesRequest, opts ...grpc.CallOption) (*TopRoutesResponse, error)
+	Edges(ctx context.Context, in *EdgesRequest, opts ...grpc.CallOption) (*EdgesResponse, error)
+	// Streams changes to the edges of the selected resources, starting with an
+	// ADD event for every edge that currently exists.
+	WatchEdges(ctx context.Context, in *EdgesRequest, opts ...grpc.CallOption) (Api_WatchEdgesClient, error)
+	ListPods(ctx context.Context, in *ListPodsRequest, opts ...grpc.CallOption) (*ListPodsResponse, error)
+	ListServices(ctx context.Context, in *ListServicesRequest, opts ...grpc.CallOption) (*ListServicesResponse, error)
+	// Superceded by `TapByResource`.
+	Tap(ctx context.Context, in *TapRequest, opts ...grpc.CallOption) (Api_TapClient, error)
+	// Executes tapping over Kubernetes resources.
+	TapByResource(ctx context.Context, in *TapByResourceRequest, opts ...grpc.CallOption) (Api_TapByResourceClient, error)
+	Version(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*VersionInfo, error)
+	// Returns the trust anchors of the mesh, so that other trust domains can
+	// federate with it.
+	TrustBundle(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TrustBundleResponse, error)
+	SelfCheck(ctx context.Context, in *healthcheck.SelfCheckRequest, opts ...grpc.CallOption) (*healthcheck.SelfCheckResponse, error)
+	// Returns the gateways of the clusters linked to this one, with the latency
+	// of the service mirrors' probes.
+	Gateways(ctx context.Context, in *GatewaysRequest, opts ...grpc.CallOption) (*GatewaysResponse, error)
+}
+
+type apiClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewApiClient(cc *grpc.ClientConn) ApiClient {
+	return &apiClient{cc}
+}
+
+func (c *apiClient) StatSummary(ctx context.Context, in *StatSummaryRequest, opts ...grpc.CallOption) (*StatSummaryResponse, error) {
+	out := new(StatSummaryResponse)
+	err := c.cc.Invoke(ctx, "/linkerd2.public.Api/StatSummary", in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
 	return out, nil
@@ -4463,6 +5451,47 @@ func (c *apiClient) TopRoutes(ctx context.Context, in *TopRoutesRequest, opts ..
 	return out, nil
 }
 
+func (c *apiClient) Edges(ctx context.Context, in *EdgesRequest, opts ...grpc.CallOption) (*EdgesResponse, error) {
+	out := new(EdgesResponse)
+	err := c.cc.Invoke(ctx, "/linkerd2.public.Api/Edges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiClient) WatchEdges(ctx context.Context, in *EdgesRequest, opts ...grpc.CallOption) (Api_WatchEdgesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Api_serviceDesc.Streams[0], "/linkerd2.public.Api/WatchEdges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &apiWatchEdgesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Api_WatchEdgesClient interface {
+	Recv() (*EdgeEvent, error)
+	grpc.ClientStream
+}
+
+type apiWatchEdgesClient struct {
+	grpc.ClientStream
+}
+
+func (x *apiWatchEdgesClient) Recv() (*EdgeEvent, error) {
+	m := new(EdgeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *apiClient) ListPods(ctx context.Context, in *ListPodsRequest, opts ...grpc.CallOption) (*ListPodsResponse, error) {
 	out := new(ListPodsResponse)
 	err := c.cc.Invoke(ctx, "/linkerd2.public.Api/ListPods", in, out, opts...)
@@ -4483,7 +5512,7 @@ func (c *apiClient) ListServices(ctx context.Context, in *ListServicesRequest, o
 
 // Deprecated: Do not use.
 func (c *apiClient) Tap(ctx context.Context, in *TapRequest, opts ...grpc.CallOption) (Api_TapClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Api_serviceDesc.Streams[0], "/linkerd2.public.Api/Tap", opts...)
+	stream, err := c.cc.NewStream(ctx, &_Api_serviceDesc.Streams[1], "/linkerd2.public.Api/Tap", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4515,7 +5544,7 @@ func (x *apiTapClient) Recv() (*TapEvent, error) {
 }
 
 func (c *apiClient) TapByResource(ctx context.Context, in *TapByResourceRequest, opts ...grpc.CallOption) (Api_TapByResourceClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Api_serviceDesc.Streams[1], "/linkerd2.public.Api/TapByResource", opts...)
+	stream, err := c.cc.NewStream(ctx, &_Api_serviceDesc.Streams[2], "/linkerd2.public.Api/TapByResource", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -4555,6 +5584,15 @@ func (c *apiClient) Version(ctx context.Context, in *Empty, opts ...grpc.CallOpt
 	return out, nil
 }
 
+func (c *apiClient) TrustBundle(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TrustBundleResponse, error) {
+	out := new(TrustBundleResponse)
+	err := c.cc.Invoke(ctx, "/linkerd2.public.Api/TrustBundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *apiClient) SelfCheck(ctx context.Context, in *healthcheck.SelfCheckRequest, opts ...grpc.CallOption) (*healthcheck.SelfCheckResponse, error) {
 	out := new(healthcheck.SelfCheckResponse)
 	err := c.cc.Invoke(ctx, "/linkerd2.public.Api/SelfCheck", in, out, opts...)
@@ -4564,10 +5602,23 @@ func (c *apiClient) SelfCheck(ctx context.Context, in *healthcheck.SelfCheckRequ
 	return out, nil
 }
 
+func (c *apiClient) Gateways(ctx context.Context, in *GatewaysRequest, opts ...grpc.CallOption) (*GatewaysResponse, error) {
+	out := new(GatewaysResponse)
+	err := c.cc.Invoke(ctx, "/linkerd2.public.Api/Gateways", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ApiServer is the server API for Api service.
 type ApiServer interface {
 	StatSummary(context.Context, *StatSummaryRequest) (*StatSummaryResponse, error)
 	TopRoutes(context.Context, *TopRoutesRequest) (*TopRoutesResponse, error)
+	Edges(context.Context, *EdgesRequest) (*EdgesResponse, error)
+	// Streams changes to the edges of the selected resources, starting with an
+	// ADD event for every edge that currently exists.
+	WatchEdges(*EdgesRequest, Api_WatchEdgesServer) error
 	ListPods(context.Context, *ListPodsRequest) (*ListPodsResponse, error)
 	ListServices(context.Context, *ListServicesRequest) (*ListServicesResponse, error)
 	// Superceded by `TapByResource`.
@@ -4575,7 +5626,13 @@ type ApiServer interface {
 	// Executes tapping over Kubernetes resources.
 	TapByResource(*TapByResourceRequest, Api_TapByResourceServer) error
 	Version(context.Context, *Empty) (*VersionInfo, error)
+	// Returns the trust anchors of the mesh, so that other trust domains can
+	// federate with it.
+	TrustBundle(context.Context, *Empty) (*TrustBundleResponse, error)
 	SelfCheck(context.Context, *healthcheck.SelfCheckRequest) (*healthcheck.SelfCheckResponse, error)
+	// Returns the gateways of the clusters linked to this one, with the latency
+	// of the service mirrors' probes.
+	Gateways(context.Context, *GatewaysRequest) (*GatewaysResponse, error)
 }
 
 func RegisterApiServer(s *grpc.Server, srv ApiServer) {
@@ -4618,6 +5675,45 @@ func _Api_TopRoutes_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Api_Edges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EdgesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).Edges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkerd2.public.Api/Edges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).Edges(ctx, req.(*EdgesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Api_WatchEdges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EdgesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ApiServer).WatchEdges(m, &apiWatchEdgesServer{stream})
+}
+
+type Api_WatchEdgesServer interface {
+	Send(*EdgeEvent) error
+	grpc.ServerStream
+}
+
+type apiWatchEdgesServer struct {
+	grpc.ServerStream
+}
+
+func (x *apiWatchEdgesServer) Send(m *EdgeEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Api_ListPods_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListPodsRequest)
 	if err := dec(in); err != nil {
@@ -4714,6 +5810,24 @@ func _Api_Version_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Api_TrustBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).TrustBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkerd2.public.Api/TrustBundle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).TrustBundle(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Api_SelfCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(healthcheck.SelfCheckRequest)
 	if err := dec(in); err != nil {
@@ -4732,6 +5846,24 @@ func _Api_SelfCheck_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Api_Gateways_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GatewaysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServer).Gateways(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/linkerd2.public.Api/Gateways",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServer).Gateways(ctx, req.(*GatewaysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Api_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "linkerd2.public.Api",
 	HandlerType: (*ApiServer)(nil),
@@ -4744,6 +5876,10 @@ var _Api_serviceDesc = grpc.ServiceDesc{
 			MethodName: "TopRoutes",
 			Handler:    _Api_TopRoutes_Handler,
 		},
+		{
+			MethodName: "Edges",
+			Handler:    _Api_Edges_Handler,
+		},
 		{
 			MethodName: "ListPods",
 			Handler:    _Api_ListPods_Handler,
@@ -4756,12 +5892,25 @@ var _Api_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Version",
 			Handler:    _Api_Version_Handler,
 		},
+		{
+			MethodName: "TrustBundle",
+			Handler:    _Api_TrustBundle_Handler,
+		},
 		{
 			MethodName: "SelfCheck",
 			Handler:    _Api_SelfCheck_Handler,
 		},
+		{
+			MethodName: "Gateways",
+			Handler:    _Api_Gateways_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEdges",
+			Handler:       _Api_WatchEdges_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "Tap",
 			Handler:       _Api_Tap_Handler,
@@ -4776,183 +5925,233 @@ var _Api_serviceDesc = grpc.ServiceDesc{
 	Metadata: "public.proto",
 }
 
-func init() { proto.RegisterFile("public.proto", fileDescriptor_public_38b36360ab7d5fc2) }
-
-var fileDescriptor_public_38b36360ab7d5fc2 = []byte{
-	// 2789 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x19, 0x4d, 0x73, 0x1b, 0x49,
-	0x55, 0x1f, 0xa3, 0xaf, 0x27, 0xd9, 0x56, 0x3a, 0xde, 0xa0, 0x9d, 0x5d, 0xb2, 0xce, 0xe4, 0x63,
-	0x5d, 0x59, 0x90, 0xbd, 0xce, 0x26, 0xbb, 0xde, 0x2c, 0x1f, 0x96, 0xad, 0x8d, 0x0d, 0x8e, 0xad,
-	0x6d, 0x29, 0x6c, 0x55, 0x6a, 0x29, 0xd5, 0x58, 0xd3, 0xb6, 0x07, 0x8f, 0xa6, 0x27, 0x33, 0xad,
-	0x64, 0xf5, 0x0f, 0xb8, 0x50, 0x5c, 0xe0, 0xcc, 0x19, 0x6e, 0x5c, 0xe0, 0x3f, 0x40, 0x15, 0xc5,
-	0x85, 0x82, 0x13, 0xdc, 0xb8, 0x50, 0x14, 0x17, 0xce, 0x14, 0xd5, 0x5f, 0xa3, 0x19, 0x4b, 0xfe,
-	0x0a, 0x1c, 0xe0, 0xa4, 0x7e, 0xaf, 0xdf, 0x7b, 0xfd, 0xfa, 0x7d, 0xf6, 0x1b, 0x41, 0x2d, 0x18,
-	0x1d, 0x78, 0xee, 0xa0, 0x19, 0x84, 0x94, 0x51, 0xb4, 0xe0, 0xb9, 0xfe, 0x09, 0x09, 0x9d, 0xb5,
-	0xa6, 0x44, 0x9b, 0x37, 0x8f, 0x28, 0x3d, 0xf2, 0xc8, 0x8a, 0xd8, 0x3e, 0x18, 0x1d, 0xae, 0x38,
-	0xa3, 0xd0, 0x66, 0x2e, 0xf5, 0x25, 0x83, 0xd9, 0x18, 0xd0, 0xe1, 0x90, 0xfa, 0x2b, 0xc7, 0xc4,
-	0xf6, 0xd8, 0xf1, 0xe0, 0x98, 0x0c, 0x4e, 0xe4, 0x8e, 0x55, 0x82, 0x42, 0x7b, 0x18, 0xb0, 0xb1,
-	0xf5, 0x02, 0xaa, 0xdf, 0x23, 0x61, 0xe4, 0x52, 0x7f, 0xc7, 0x3f, 0xa4, 0xe8, 0x6d, 0xa8, 0x1c,
-	0x51, 0x85, 0x68, 0x64, 0x97, 0xb2, 0xcb, 0x15, 0x3c, 0x41, 0xf0, 0xdd, 0x83, 0x91, 0xeb, 0x39,
-	0x5b, 0x36, 0x23, 0x8d, 0x9c, 0xdc, 0x8d, 0x11, 0xe8, 0x1e, 0xcc, 0x87, 0xc4, 0x23, 0x76, 0x44,
-	0xb4, 0x80, 0xbc, 0x20, 0x39, 0x85, 0xb5, 0x1e, 0xc0, 0xf5, 0x5d, 0x37, 0x62, 0x5d, 0x12, 0xbe,
-	0x74, 0x07, 0x24, 0xc2, 0xe4, 0xc5, 0x88, 0x44, 0x8c, 0x0b, 0xf7, 0xed, 0x21, 0x89, 0x02, 0x7b,
-	0x40, 0xf4, 0xd1, 0x31, 0xc2, 0xda, 0x85, 0xc5, 0x34, 0x53, 0x14, 0x50, 0x3f, 0x22, 0xe8, 0x03,
-	0x28, 0x47, 0x0a, 0xd7, 0xc8, 0x2e, 0xe5, 0x97, 0xab, 0x6b, 0x8d, 0xe6, 0x29, 0x33, 0x35, 0x15,
-	0x13, 0x8e, 0x29, 0xad, 0xc7, 0x50, 0x52, 0x48, 0x84, 0xc0, 0xe0, 0xa7, 0xa8, 0x13, 0xc5, 0x3a,
-	0xad, 0x4a, 0xee, 0xb4, 0x2a, 0x2b, 0xb0, 0xc0, 0x55, 0xe9, 0x50, 0xe7, 0x92, 0xba, 0x7f, 0x02,
-	0xf5, 0x09, 0x83, 0xd2, 0x7b, 0x19, 0x8c, 0x80, 0x3a, 0x5a, 0xe7, 0xc5, 0x29, 0x9d, 0x3b, 0xd4,
-	0xc1, 0x82, 0xc2, 0xfa, 0xbd, 0x01, 0xf9, 0x0e, 0x75, 0x66, 0x2a, 0xba, 0x08, 0x85, 0x80, 0x3a,
-	0x3b, 0x1d, 0xa5, 0xa4, 0x04, 0xd0, 0x12, 0x80, 0x43, 0x02, 0x8f, 0x8e, 0x87, 0xc4, 0x67, 0xd2,
-	0x09, 0xdb, 0x19, 0x9c, 0xc0, 0xa1, 0x5b, 0x50, 0x0d, 0x49, 0xe0, 0xb9, 0x03, 0xbb, 0x1f, 0x11,
-	0xd6, 0x00, 0x4d, 0xa2, 0x90, 0x5d, 0xc2, 0xd0, 0x87, 0x70, 0x43, 0x41, 0x3c, 0xa0, 0xfa, 0x03,
-	0xea, 0xb3, 0x90, 0x7a, 0x1e, 0x09, 0x1b, 0x55, 0x45, 0xfd, 0x46, 0x62, 0x7f, 0x33, 0xde, 0x46,
-	0xb7, 0xa1, 0x16, 0x31, 0x9b, 0x91, 0xc3, 0x91, 0x27, 0x84, 0xd7, 0x14, 0x79, 0x55, 0x63, 0xb9,
-	0xf4, 0x77, 0x00, 0x1c, 0x9b, 0x0c, 0xa9, 0x2f, 0x48, 0xe6, 0x14, 0x49, 0x45, 0xe2, 0x38, 0x01,
-	0x82, 0xfc, 0x0f, 0xe8, 0x41, 0x63, 0x5e, 0xed, 0x70, 0x00, 0xdd, 0x80, 0x22, 0x97, 0x31, 0x8a,
-	0x1a, 0x86, 0xb8, 0xae, 0x82, 0xb8, 0x15, 0x6c, 0xc7, 0x21, 0x4e, 0xa3, 0xb0, 0x94, 0x5d, 0x2e,
-	0x63, 0x09, 0xa0, 0x4d, 0x58, 0x88, 0x5c, 0x7f, 0x40, 0x76, 0xed, 0x88, 0x61, 0x12, 0xd0, 0x90,
-	0x35, 0x8a, 0x4b, 0xd9, 0xe5, 0xea, 0xda, 0x9b, 0x4d, 0x99, 0x36, 0x4d, 0x9d, 0x36, 0xcd, 0x2d,
-	0x95, 0x36, 0xf8, 0x34, 0x07, 0x5a, 0x85, 0xeb, 0x93, 0x9b, 0xef, 0xc5, 0x2e, 0x2e, 0x89, 0xf3,
-	0x67, 0x6d, 0x21, 0x0b, 0x6a, 0x0a, 0xdd, 0xf1, 0x6c, 0x9f, 0x34, 0xca, 0x42, 0xa7, 0x14, 0x0e,
-	0xbd, 0x0f, 0xc5, 0x51, 0xc0, 0xdc, 0x21, 0x69, 0x54, 0x2e, 0xd2, 0x48, 0x11, 0xa2, 0x9b, 0x00,
-	0x41, 0x48, 0xbf, 0x1c, 0x63, 0x62, 0x3b, 0xe3, 0xc6, 0x82, 0x10, 0x9a, 0xc0, 0xf0, 0x63, 0x05,
-	0xa4, 0x53, 0xaf, 0x2e, 0x34, 0x4c, 0xe1, 0x5a, 0x25, 0x28, 0xd0, 0x57, 0x3e, 0x09, 0xad, 0x5f,
-	0xe4, 0x00, 0x7a, 0x76, 0xa0, 0xa3, 0x17, 0x41, 0x3e, 0xa0, 0x8e, 0x0c, 0x2c, 0x6e, 0xeb, 0x80,
-	0x3a, 0xa7, 0x62, 0x28, 0x37, 0x23, 0x86, 0x6e, 0x40, 0x71, 0x68, 0x7f, 0x89, 0x83, 0x48, 0x44,
-	0x58, 0x0e, 0x2b, 0x88, 0xe3, 0x19, 0xed, 0x70, 0x73, 0x73, 0x2f, 0xcd, 0x61, 0x05, 0xf1, 0xf8,
-	0x65, 0x74, 0xa7, 0x23, 0x9c, 0x54, 0xc1, 0x62, 0x8d, 0x4c, 0x28, 0x1f, 0x86, 0x74, 0xd8, 0xd1,
-	0xce, 0x99, 0xc3, 0x31, 0xcc, 0xe5, 0xf0, 0xf5, 0x4e, 0x47, 0x59, 0x5b, 0x41, 0x22, 0x0a, 0x06,
-	0xc7, 0x64, 0x28, 0x4d, 0xcb, 0xa3, 0x40, 0x40, 0x42, 0x1f, 0xc2, 0x8e, 0xa9, 0x23, 0x8c, 0x5a,
-	0xc1, 0x0a, 0xe2, 0xb9, 0x69, 0x8f, 0xd8, 0x31, 0x0d, 0x5d, 0x36, 0x96, 0x91, 0x8e, 0x27, 0x08,
-	0xae, 0x55, 0x60, 0xb3, 0x63, 0x19, 0xd4, 0x58, 0xac, 0x3f, 0xce, 0x35, 0xb2, 0xad, 0x32, 0x14,
-	0x99, 0x1d, 0x1e, 0x11, 0x66, 0xfd, 0xb5, 0x00, 0x8b, 0x3d, 0x3b, 0x68, 0x8d, 0x31, 0x89, 0xe8,
-	0x28, 0x1c, 0x10, 0x6d, 0xb6, 0x8f, 0x35, 0x89, 0xb0, 0x5c, 0x75, 0xcd, 0x9a, 0x4a, 0x62, 0xcd,
-	0xd1, 0x25, 0x1e, 0x19, 0x48, 0x77, 0x4a, 0x0e, 0xb4, 0x01, 0x85, 0xa1, 0xcd, 0x06, 0xc7, 0xc2,
-	0xb2, 0xd5, 0xb5, 0xf7, 0xa6, 0x58, 0x67, 0x9d, 0xd8, 0x7c, 0xca, 0x59, 0xb0, 0xe4, 0x3c, 0xcb,
-	0xfe, 0xe6, 0xaf, 0x0c, 0x28, 0x08, 0x42, 0xb4, 0x09, 0x79, 0xdb, 0xf3, 0x94, 0x76, 0x2b, 0x57,
-	0x38, 0xa2, 0xd9, 0x25, 0x2f, 0x78, 0x20, 0xd8, 0x9e, 0x27, 0x84, 0xf8, 0x63, 0xa5, 0xe7, 0x6b,
-	0x09, 0xf1, 0xc7, 0xe8, 0x5b, 0x90, 0xf7, 0xa9, 0x2c, 0x45, 0x57, 0xbb, 0x2c, 0x17, 0xe0, 0x53,
-	0x86, 0xb6, 0xa1, 0xe6, 0x90, 0x88, 0xb9, 0xbe, 0xc8, 0x0a, 0x59, 0x00, 0x2e, 0x65, 0xf1, 0xed,
-	0x0c, 0x4e, 0x71, 0xa2, 0x4f, 0xc1, 0x38, 0x66, 0x2c, 0x10, 0x61, 0x58, 0x5d, 0x5b, 0xbd, 0xca,
-	0x85, 0xb6, 0x19, 0x0b, 0xb6, 0x33, 0x58, 0xf0, 0x9b, 0xbb, 0x90, 0xef, 0x92, 0x17, 0xa8, 0x0d,
-	0x25, 0xe1, 0x8e, 0xb8, 0xfd, 0x5c, 0xc9, 0x95, 0x9a, 0xd7, 0x1c, 0x83, 0xc1, 0xa5, 0xa3, 0x46,
-	0x1c, 0xdc, 0x3a, 0x1b, 0x75, 0x78, 0x37, 0xe2, 0xf0, 0xd6, 0xc9, 0xa8, 0x03, 0xfc, 0x66, 0x32,
-	0xc0, 0x75, 0xb5, 0x4f, 0x84, 0xf8, 0xa2, 0x0a, 0x71, 0x43, 0x6d, 0x09, 0x88, 0x17, 0x03, 0x71,
-	0x78, 0xbc, 0xb0, 0xfe, 0x99, 0x05, 0xe0, 0x4a, 0x3c, 0x95, 0x62, 0xb7, 0x01, 0x42, 0x72, 0xe4,
-	0x46, 0x8c, 0x84, 0x44, 0x16, 0x87, 0xf9, 0xb5, 0x7b, 0x53, 0x97, 0x9b, 0x30, 0x34, 0x71, 0x4c,
-	0x2d, 0x5b, 0x89, 0x86, 0xd0, 0x1d, 0xa8, 0x8d, 0xfc, 0x84, 0x2c, 0x7d, 0x81, 0x14, 0xd6, 0xf2,
-	0x01, 0x26, 0x12, 0x50, 0x09, 0xf2, 0x4f, 0xda, 0xbd, 0x7a, 0x06, 0x95, 0xc1, 0xe8, 0xec, 0x77,
-	0x7b, 0xf5, 0x2c, 0x47, 0x75, 0x9e, 0xf5, 0xea, 0x39, 0x04, 0x50, 0xdc, 0x6a, 0xef, 0xb6, 0x7b,
-	0xed, 0x7a, 0x1e, 0x55, 0xa0, 0xd0, 0xd9, 0xe8, 0x6d, 0x6e, 0xd7, 0x0d, 0x54, 0x85, 0xd2, 0x7e,
-	0xa7, 0xb7, 0xb3, 0xbf, 0xd7, 0xad, 0x17, 0x38, 0xb0, 0xb9, 0xbf, 0xb7, 0xd7, 0xde, 0xec, 0xd5,
-	0x8b, 0x5c, 0xc6, 0x76, 0x7b, 0x63, 0xab, 0x5e, 0xe2, 0xe4, 0x3d, 0xbc, 0xb1, 0xd9, 0xae, 0x97,
-	0x5b, 0x45, 0x30, 0xd8, 0x38, 0x20, 0xd6, 0xcf, 0xb2, 0x50, 0xec, 0x4a, 0x1b, 0x6f, 0xcd, 0xb8,
-	0xf2, 0x74, 0x8c, 0x49, 0xe2, 0xff, 0xf4, 0xba, 0xb7, 0x52, 0xd7, 0xe5, 0x1a, 0xf6, 0x7a, 0x9d,
-	0x7a, 0x86, 0x6b, 0xc8, 0x57, 0xdd, 0x7a, 0x36, 0xd6, 0xb0, 0x07, 0x95, 0x9d, 0xce, 0x86, 0xe3,
-	0x84, 0x24, 0xe2, 0xcd, 0xce, 0x70, 0x83, 0x97, 0x1f, 0x08, 0xed, 0x4a, 0xdc, 0x9b, 0x1c, 0x42,
-	0xef, 0x09, 0xec, 0x23, 0x95, 0xa6, 0x6f, 0x4c, 0xe9, 0xbc, 0xd3, 0x79, 0xf9, 0x48, 0x11, 0x3f,
-	0x6a, 0x19, 0x90, 0x73, 0x03, 0x6b, 0x15, 0x0c, 0x8e, 0xe5, 0xdd, 0xf3, 0xd0, 0x0d, 0x23, 0x59,
-	0xc5, 0x8a, 0x58, 0x02, 0xbc, 0x2e, 0x7a, 0x76, 0x24, 0x2b, 0x7f, 0x11, 0x8b, 0xb5, 0xb5, 0x0b,
-	0xd0, 0x1b, 0x04, 0x5a, 0x91, 0xfb, 0x5c, 0x8a, 0x2a, 0x2e, 0xe6, 0x8c, 0x03, 0x15, 0x1d, 0xce,
-	0xb9, 0x81, 0xa8, 0xb2, 0xbc, 0xc6, 0xe7, 0x44, 0x8d, 0x17, 0x6b, 0xcb, 0x81, 0x7c, 0x9b, 0x72,
-	0x31, 0xf5, 0xa3, 0x30, 0x18, 0xf4, 0x65, 0x2f, 0xef, 0x0f, 0xa8, 0x23, 0x63, 0x7f, 0x6e, 0x3b,
-	0x83, 0xe7, 0xf9, 0x4e, 0x57, 0x6c, 0x6c, 0x52, 0x87, 0x70, 0xda, 0x90, 0x44, 0x84, 0xf5, 0x49,
-	0x18, 0xd2, 0x50, 0xd2, 0xe6, 0x34, 0xad, 0xd8, 0x69, 0xf3, 0x0d, 0x4e, 0xdb, 0x2a, 0x40, 0x9e,
-	0xf8, 0x8e, 0xf5, 0x87, 0x79, 0x28, 0xf7, 0xec, 0xa0, 0xfd, 0x92, 0xb7, 0xac, 0x07, 0x50, 0x94,
-	0x59, 0xa8, 0xd4, 0x7e, 0x6b, 0x3a, 0x57, 0xe3, 0xfb, 0x61, 0x45, 0x8a, 0x9e, 0x40, 0x55, 0xae,
-	0xfa, 0x43, 0xc2, 0x6c, 0x55, 0x37, 0xee, 0xcd, 0xca, 0x72, 0x71, 0x48, 0xb3, 0xed, 0x3b, 0x01,
-	0x75, 0x7d, 0xf6, 0x94, 0x30, 0x1b, 0x83, 0x64, 0xe5, 0x6b, 0xf4, 0x0d, 0xa8, 0x26, 0x2a, 0x91,
-	0x72, 0xd5, 0xb9, 0x2a, 0x24, 0xe9, 0xd1, 0x67, 0x50, 0x4f, 0x80, 0x52, 0x19, 0xe3, 0x4a, 0xca,
-	0x2c, 0x24, 0xf8, 0x85, 0x46, 0x2d, 0x80, 0x90, 0x8e, 0x98, 0xba, 0x59, 0x49, 0x08, 0xbb, 0x7d,
-	0xb6, 0x30, 0xcc, 0x69, 0x85, 0xa4, 0x4a, 0xa8, 0x97, 0xe8, 0x33, 0x58, 0x10, 0x8f, 0x8c, 0xbe,
-	0xe3, 0x86, 0xb2, 0xe4, 0x8a, 0x4e, 0x3e, 0xbf, 0xb6, 0x7c, 0xb6, 0xa0, 0x0e, 0x67, 0xd8, 0xd2,
-	0xf4, 0x78, 0x3e, 0x48, 0xc1, 0xe8, 0x03, 0x55, 0xa2, 0x65, 0xbb, 0xb8, 0x79, 0xb6, 0x9c, 0x54,
-	0x41, 0xfe, 0x69, 0x16, 0x6a, 0xc9, 0xeb, 0xa2, 0xef, 0x40, 0xd1, 0xb3, 0x0f, 0x88, 0xa7, 0x2b,
-	0xf3, 0xda, 0xe5, 0xcc, 0xd4, 0xdc, 0x15, 0x4c, 0x6d, 0x9f, 0x85, 0x63, 0xac, 0x24, 0x98, 0xeb,
-	0x50, 0x4d, 0xa0, 0x51, 0x1d, 0xf2, 0x27, 0x64, 0xac, 0x9e, 0xe2, 0x7c, 0xc9, 0xb3, 0xe8, 0xa5,
-	0xed, 0x8d, 0xf4, 0xb8, 0x20, 0x81, 0x8f, 0x73, 0x1f, 0x65, 0xcd, 0x1f, 0x67, 0xa1, 0x12, 0x5b,
-	0x0e, 0x3d, 0x39, 0xa5, 0xd4, 0xca, 0x25, 0xcc, 0xfd, 0xdf, 0xd6, 0xe8, 0x5f, 0x25, 0xd5, 0x6d,
-	0xf6, 0xa1, 0x16, 0xca, 0x7e, 0xd4, 0x77, 0x7d, 0x57, 0xbf, 0x63, 0xee, 0x9f, 0x6f, 0xf0, 0xa6,
-	0x6a, 0x61, 0x3b, 0xbe, 0xcb, 0xf8, 0xb3, 0x3e, 0x9c, 0x80, 0x08, 0xc3, 0x5c, 0xa8, 0x26, 0x1c,
-	0x29, 0xf1, 0x9c, 0xe7, 0x4d, 0x4a, 0xa2, 0xe4, 0x51, 0x22, 0x6b, 0x61, 0x02, 0x96, 0x4a, 0x2a,
-	0x99, 0xc4, 0x77, 0x54, 0x54, 0xdc, 0xbf, 0xa4, 0xc8, 0xb6, 0xef, 0x48, 0x25, 0x63, 0xd0, 0x7c,
-	0x04, 0xe5, 0x2e, 0x0b, 0x89, 0x3d, 0xdc, 0x11, 0x43, 0xd5, 0x81, 0x1d, 0xa9, 0x8a, 0x83, 0xc5,
-	0x5a, 0x8e, 0x19, 0x7c, 0x5f, 0x68, 0x6f, 0x60, 0x05, 0x99, 0x7f, 0xce, 0x42, 0x35, 0x71, 0x77,
-	0xf4, 0x21, 0xe4, 0x5c, 0x47, 0xd9, 0xec, 0xdd, 0x0b, 0xd4, 0xd1, 0x07, 0xe2, 0x9c, 0xeb, 0xf0,
-	0x32, 0x94, 0x68, 0xe5, 0xb3, 0x6a, 0xc0, 0xa4, 0xab, 0xc6, 0x5d, 0x7e, 0x25, 0x7e, 0x19, 0x48,
-	0x03, 0x7c, 0xe5, 0x8c, 0xbe, 0x14, 0x3f, 0x18, 0x52, 0xef, 0x5e, 0xe3, 0xac, 0x77, 0x6f, 0x61,
-	0xf2, 0xee, 0x35, 0x7f, 0x99, 0x85, 0x5a, 0xd2, 0x15, 0xaf, 0x7f, 0xc3, 0x27, 0x80, 0xc4, 0x24,
-	0xd5, 0x4f, 0x85, 0x57, 0xee, 0xa2, 0x61, 0xa7, 0x2e, 0x98, 0x92, 0x36, 0x7e, 0x07, 0xaa, 0x3c,
-	0xb9, 0x55, 0x77, 0x10, 0x57, 0x9f, 0xc3, 0xc0, 0x51, 0xb2, 0x2d, 0x98, 0x3f, 0xcf, 0x71, 0xa7,
-	0xc4, 0xce, 0xfd, 0x1f, 0x50, 0x79, 0x07, 0xae, 0x6b, 0x41, 0xc9, 0x4c, 0xc8, 0x5f, 0x24, 0xe9,
-	0x9a, 0x92, 0x94, 0xb0, 0xff, 0x5d, 0x98, 0x8f, 0x85, 0x1c, 0x8c, 0x19, 0x91, 0xef, 0x5e, 0x03,
-	0xc7, 0x49, 0xd6, 0xe2, 0x48, 0x74, 0x0f, 0xf2, 0x84, 0x46, 0xaa, 0x33, 0x4d, 0x7f, 0x4a, 0x68,
-	0xd3, 0x08, 0x73, 0x02, 0xfe, 0xd2, 0x23, 0xfc, 0xf6, 0xd6, 0x47, 0x30, 0x9f, 0x2e, 0xc1, 0xfc,
-	0xb9, 0xf4, 0x6c, 0xef, 0xbb, 0x7b, 0xfb, 0x9f, 0xef, 0xd5, 0x33, 0x1c, 0xd8, 0xd9, 0x6b, 0xed,
-	0x3f, 0xdb, 0xdb, 0xaa, 0x67, 0x51, 0x0d, 0xca, 0xfb, 0xcf, 0x7a, 0x12, 0xca, 0x4d, 0x44, 0x2c,
-	0x41, 0x79, 0x23, 0x70, 0x45, 0xbb, 0xe5, 0x95, 0x46, 0x34, 0x64, 0x55, 0x7d, 0x24, 0xc0, 0x87,
-	0xcc, 0x4a, 0x87, 0x3a, 0x82, 0x24, 0x42, 0x8f, 0xa1, 0x28, 0xd0, 0xba, 0xee, 0xdd, 0x9e, 0xf5,
-	0xc5, 0x43, 0xd2, 0xc6, 0x2b, 0xac, 0x58, 0xcc, 0xbf, 0x64, 0xa1, 0xac, 0x91, 0x08, 0x43, 0x85,
-	0x0f, 0xd3, 0xb6, 0xeb, 0x93, 0x50, 0x39, 0x7a, 0xed, 0x12, 0xc2, 0x9a, 0x9b, 0x9a, 0x49, 0x80,
-	0xfc, 0x89, 0x1c, 0x8b, 0x31, 0x5f, 0xc2, 0x7c, 0x7a, 0x1b, 0x35, 0xa0, 0x34, 0x24, 0x51, 0x64,
-	0x1f, 0xe9, 0x0f, 0x2e, 0x1a, 0xe4, 0x79, 0x35, 0x39, 0x5f, 0x7d, 0x1c, 0x8a, 0x11, 0xdc, 0x16,
-	0xee, 0x90, 0x73, 0xc9, 0x6f, 0x5f, 0x12, 0xe0, 0x25, 0x25, 0x24, 0x76, 0x44, 0x7d, 0xfd, 0xe5,
-	0x42, 0x42, 0xc2, 0x9c, 0xc2, 0x58, 0x1d, 0x28, 0xeb, 0x09, 0xe1, 0xfc, 0x8f, 0x49, 0x62, 0x8c,
-	0x1e, 0x07, 0xba, 0xaa, 0x8b, 0x75, 0xfc, 0x69, 0x28, 0x3f, 0xf9, 0x34, 0x64, 0xbd, 0x80, 0x6b,
-	0x53, 0xc3, 0x10, 0x7a, 0x08, 0xe5, 0x90, 0xa4, 0x9e, 0x40, 0x6f, 0x9e, 0x39, 0x42, 0xe1, 0x98,
-	0x94, 0xc7, 0xa1, 0xe8, 0x3a, 0xfd, 0x48, 0x48, 0xa2, 0xfa, 0xde, 0x73, 0x02, 0xdb, 0x55, 0x48,
-	0xeb, 0x0b, 0x98, 0xd3, 0xcc, 0xd2, 0x88, 0xaf, 0x79, 0x5c, 0x1c, 0x4f, 0xb9, 0x64, 0x3c, 0xfd,
-	0x2e, 0x07, 0x88, 0x27, 0x7d, 0x77, 0x34, 0x1c, 0xda, 0xe1, 0x58, 0x4f, 0xe1, 0xdf, 0x84, 0x72,
-	0xac, 0xd5, 0xe5, 0xe7, 0xf0, 0x98, 0x87, 0x57, 0x18, 0xe6, 0x0e, 0x49, 0xff, 0x95, 0xeb, 0x3b,
-	0xf4, 0x95, 0x3a, 0x12, 0x38, 0xea, 0x73, 0x81, 0x41, 0x5f, 0x03, 0xc3, 0xa7, 0xbe, 0x2e, 0xbb,
-	0x37, 0xa6, 0xd3, 0x6b, 0x18, 0xb0, 0x31, 0x7f, 0x85, 0x70, 0x2a, 0xf4, 0x09, 0x54, 0x19, 0xed,
-	0xc7, 0xb7, 0x36, 0x2e, 0xb8, 0x35, 0x1f, 0x1d, 0x18, 0x8d, 0x5d, 0xff, 0x6d, 0x98, 0x3b, 0x0c,
-	0xe9, 0x70, 0xc2, 0x5f, 0xb8, 0x98, 0xbf, 0xc6, 0x39, 0x62, 0x09, 0x5f, 0x05, 0x88, 0x4e, 0x5c,
-	0x59, 0x30, 0x23, 0xf1, 0x12, 0x2b, 0xe3, 0x0a, 0xc7, 0x70, 0xd3, 0x45, 0x2d, 0x80, 0x32, 0x1d,
-	0xb1, 0x03, 0x3a, 0xf2, 0x1d, 0xeb, 0x8f, 0x59, 0xb8, 0x9e, 0x32, 0xa8, 0xfa, 0x34, 0xb9, 0x0e,
-	0x39, 0x7a, 0x72, 0x66, 0x09, 0x9d, 0xc1, 0xd1, 0xdc, 0x3f, 0xd9, 0xce, 0xe0, 0x1c, 0x3d, 0x41,
-	0x8f, 0x92, 0x9e, 0x9b, 0xf5, 0x74, 0x4b, 0xc5, 0xc7, 0x76, 0x46, 0xf9, 0xd6, 0xdc, 0x80, 0xdc,
-	0xfe, 0x09, 0x7a, 0x0c, 0xe2, 0x1b, 0x61, 0x9f, 0xd9, 0x07, 0x5e, 0x3c, 0x4f, 0x9b, 0x33, 0x35,
-	0xe8, 0x71, 0x12, 0x0c, 0x91, 0x5e, 0x8a, 0x9b, 0xe9, 0xaa, 0x68, 0xfd, 0x29, 0x07, 0xd0, 0xb2,
-	0x23, 0x57, 0xcc, 0x0e, 0x11, 0xba, 0x0d, 0x73, 0xd1, 0x68, 0x30, 0x20, 0x11, 0x1f, 0x2f, 0x46,
-	0xbe, 0x7c, 0xe7, 0x18, 0xb8, 0xa6, 0x90, 0x9b, 0x1c, 0xc7, 0x89, 0x0e, 0x6d, 0xd7, 0x1b, 0x85,
-	0x44, 0x11, 0xc9, 0xe6, 0x5f, 0x53, 0x48, 0x49, 0x74, 0x87, 0x27, 0x02, 0x23, 0xfe, 0x60, 0xdc,
-	0x1f, 0x46, 0xfd, 0xe0, 0xe1, 0xaa, 0x88, 0x0a, 0x03, 0xd7, 0x14, 0xf6, 0x69, 0xd4, 0x79, 0xb8,
-	0x7a, 0x9a, 0x6a, 0xfd, 0xa1, 0x2a, 0xdb, 0x09, 0xaa, 0xf5, 0x87, 0x53, 0x54, 0xeb, 0xc2, 0xd9,
-	0x69, 0xaa, 0x75, 0x74, 0x1f, 0xae, 0x31, 0x2f, 0x8a, 0x9b, 0x92, 0x54, 0xad, 0x28, 0x08, 0x17,
-	0x98, 0xa7, 0x3f, 0x40, 0x4b, 0xed, 0x56, 0x61, 0xd1, 0x1e, 0xb0, 0x91, 0xed, 0xf5, 0xd3, 0xd7,
-	0x2d, 0x09, 0x72, 0x24, 0xf7, 0xba, 0xc9, 0x4b, 0x4f, 0x38, 0xd2, 0x77, 0x2f, 0x27, 0x39, 0x3e,
-	0x4d, 0x58, 0xc0, 0xfa, 0x87, 0x01, 0x95, 0xd8, 0x01, 0xa8, 0x05, 0x95, 0x80, 0x3a, 0xfd, 0xa3,
-	0x90, 0x8e, 0xf4, 0x28, 0x78, 0xfb, 0x6c, 0x7f, 0xf1, 0x5a, 0xfc, 0x84, 0x93, 0x6e, 0x67, 0x70,
-	0x39, 0x50, 0x6b, 0xf3, 0x27, 0x86, 0x28, 0xee, 0x02, 0x40, 0x8f, 0xc1, 0x08, 0xe9, 0x2b, 0xed,
-	0xfb, 0x77, 0x2f, 0x21, 0xab, 0x89, 0xe9, 0x2b, 0x2c, 0x98, 0xcc, 0xdf, 0xe4, 0x21, 0x8f, 0xe9,
-	0xab, 0xd7, 0x2d, 0x3b, 0x17, 0x56, 0x82, 0x65, 0xa8, 0x0f, 0x49, 0x74, 0x4c, 0x9c, 0x3e, 0xbf,
-	0xb4, 0xb4, 0x94, 0xf4, 0xff, 0xbc, 0xc4, 0x77, 0xa8, 0x23, 0xed, 0x7a, 0x1f, 0xae, 0x85, 0x23,
-	0xdf, 0x77, 0xfd, 0xa3, 0x04, 0xa9, 0x0c, 0x82, 0x05, 0xb5, 0x11, 0xd3, 0x2e, 0x43, 0x9d, 0x1b,
-	0x3f, 0x25, 0x55, 0x3a, 0x78, 0x5e, 0xe2, 0x63, 0xca, 0xf7, 0xa1, 0x20, 0xd3, 0xba, 0x70, 0xc6,
-	0xb3, 0x71, 0x12, 0xf3, 0x58, 0x52, 0xa2, 0x2f, 0x60, 0x4e, 0xf6, 0xd0, 0xfe, 0xc1, 0x98, 0xcb,
-	0x6f, 0x94, 0x84, 0x61, 0x3f, 0xba, 0xa4, 0x61, 0x9b, 0xb2, 0x89, 0xb6, 0xc6, 0xbc, 0x8b, 0x8a,
-	0xf1, 0xa3, 0x4a, 0x26, 0x18, 0xf3, 0x39, 0xd4, 0x4f, 0x13, 0xcc, 0x18, 0x44, 0x56, 0x93, 0x83,
-	0xc8, 0xac, 0x84, 0x8e, 0x9b, 0x75, 0x62, 0x48, 0xe1, 0xad, 0x51, 0xd4, 0x01, 0xeb, 0x6f, 0x59,
-	0xa8, 0xf7, 0x68, 0x20, 0xa6, 0xa1, 0xe8, 0xff, 0xa3, 0xea, 0x97, 0xae, 0x54, 0xf5, 0x53, 0x45,
-	0xf9, 0xb7, 0x59, 0xb8, 0x96, 0xb8, 0xad, 0x2a, 0xc9, 0xaf, 0x59, 0x57, 0xf9, 0x6b, 0x98, 0x9e,
-	0xa8, 0x3b, 0xdc, 0x9d, 0x7e, 0x0d, 0x9f, 0x3e, 0x27, 0x2e, 0xe4, 0xe6, 0xba, 0x28, 0xc8, 0x0f,
-	0xa0, 0x28, 0x06, 0x7d, 0x9d, 0x8f, 0xd3, 0x11, 0x27, 0xf8, 0x65, 0x31, 0x56, 0xa4, 0xa9, 0x42,
-	0xfc, 0xf7, 0x2c, 0xc0, 0x84, 0x04, 0x3d, 0x48, 0x65, 0xf7, 0x3b, 0xe7, 0x48, 0x9b, 0x64, 0x35,
-	0x32, 0x13, 0xd9, 0x2c, 0xfd, 0x14, 0xc3, 0xe6, 0x8f, 0xb2, 0x32, 0xe3, 0x17, 0xa1, 0x20, 0x4e,
-	0xd7, 0x2f, 0x50, 0x01, 0x5c, 0xec, 0xe4, 0xd4, 0x88, 0x54, 0x3c, 0x3d, 0x22, 0x5d, 0x3d, 0xdd,
-	0xd6, 0x7e, 0x5d, 0x80, 0xfc, 0x46, 0xe0, 0xa2, 0xe7, 0x50, 0x4d, 0xf4, 0x49, 0x74, 0xfb, 0xfc,
-	0x2e, 0x2a, 0x42, 0xda, 0xbc, 0x73, 0x99, 0x56, 0x6b, 0x65, 0x50, 0x0f, 0x2a, 0xb1, 0xe3, 0xd0,
-	0xad, 0xf3, 0x9c, 0x2a, 0xe5, 0x5a, 0x17, 0xfb, 0xdd, 0xca, 0xa0, 0xcf, 0xa0, 0xac, 0xff, 0xa3,
-	0x44, 0x4b, 0x53, 0x1c, 0xa7, 0xfe, 0xef, 0x34, 0x6f, 0x9d, 0x43, 0x11, 0x8b, 0xfc, 0x3e, 0xd4,
-	0x92, 0x7f, 0xd9, 0xa2, 0x3b, 0x33, 0x99, 0x4e, 0xfd, 0x0d, 0x6c, 0xde, 0xbd, 0x80, 0x2a, 0x16,
-	0xbf, 0x05, 0xf9, 0x9e, 0x1d, 0xa0, 0xb7, 0x66, 0x0d, 0x79, 0x5a, 0xd8, 0x9b, 0x67, 0x4e, 0x80,
-	0x56, 0xfe, 0x87, 0xb9, 0xec, 0x6a, 0x16, 0x3d, 0x83, 0xb9, 0xd4, 0xf7, 0x79, 0x74, 0xf7, 0x52,
-	0xdf, 0xef, 0xcf, 0x93, 0x9c, 0x59, 0xcd, 0xa2, 0x0d, 0x28, 0xe9, 0x3f, 0xcd, 0xcf, 0xa8, 0x1d,
-	0xe6, 0xdb, 0x53, 0xf8, 0xc4, 0x1f, 0xf1, 0x56, 0x06, 0x79, 0x50, 0xe9, 0x12, 0xef, 0x70, 0xf3,
-	0x98, 0x0c, 0x4e, 0xd0, 0xd7, 0x27, 0xc4, 0xf2, 0x3f, 0xfd, 0x66, 0xf2, 0x3f, 0xfd, 0x98, 0x4e,
-	0x6b, 0xd7, 0xbc, 0x2c, 0xb9, 0xb6, 0x66, 0xeb, 0xc1, 0xf3, 0xf7, 0x8f, 0x5c, 0x76, 0x3c, 0x3a,
-	0xe0, 0x0c, 0x2b, 0x8a, 0x5b, 0xff, 0xae, 0xad, 0x4c, 0xfe, 0xe9, 0x5c, 0x39, 0x22, 0xfe, 0x8a,
-	0x54, 0xf8, 0xa0, 0x28, 0xa6, 0xd8, 0x07, 0xff, 0x0e, 0x00, 0x00, 0xff, 0xff, 0x53, 0x35, 0x59,
-	0x38, 0xa7, 0x20, 0x00, 0x00,
+func init() { proto.RegisterFile("public.proto", fileDescriptor_public_c5f56ff279f0282e) }
+
+var fileDescriptor_public_c5f56ff279f0282e = []byte{
+	// 3598 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0xd5, 0x1a, 0x4d, 0x8f, 0x23, 0x57,
+	0x31, 0xfe, 0xb6, 0xcb, 0x9e, 0x19, 0xef, 0xdb, 0x0f, 0x66, 0xbd, 0xc9, 0x7e, 0xf4, 0x7e, 0x26,
+	0x21, 0x9e, 0xd9, 0xd9, 0xec, 0x92, 0x4d, 0x08, 0x61, 0x3e, 0x9c, 0xdd, 0x21, 0xbb, 0x33, 0xa6,
+	0xed, 0x4d, 0x50, 0x40, 0xb2, 0x7a, 0xec, 0x1e, 0x4f, 0x67, 0xec, 0xee, 0x4e, 0x77, 0x7b, 0x36,
+	0xbe, 0x72, 0x42, 0x48, 0x88, 0x03, 0xe1, 0xc0, 0x89, 0x23, 0x82, 0x1b, 0x17, 0x2e, 0xfc, 0x02,
+	0xc4, 0x05, 0x09, 0x71, 0xe1, 0x10, 0x24, 0x0e, 0xdc, 0xe0, 0x84, 0xc4, 0x0d, 0x51, 0xf5, 0x3e,
+	0xda, 0xdd, 0xfe, 0x98, 0xf1, 0x2c, 0x08, 0xc1, 0xc9, 0xaf, 0xea, 0x55, 0xd5, 0xab, 0x57, 0x5d,
+	0xaf, 0x3e, 0x9e, 0x1f, 0x94, 0xdc, 0xc1, 0x5e, 0xcf, 0x6a, 0x57, 0x5d, 0xcf, 0x09, 0x1c, 0xb6,
+	0xd4, 0xb3, 0xec, 0x43, 0xd3, 0xeb, 0xac, 0x55, 0x05, 0xba, 0x72, 0xb9, 0xeb, 0x38, 0xdd, 0x9e,
+	0xb9, 0xc2, 0xa7, 0xf7, 0x06, 0xfb, 0x2b, 0x9d, 0x81, 0x67, 0x04, 0x96, 0x63, 0x0b, 0x86, 0xca,
+	0x72, 0xdb, 0xe9, 0xf7, 0x1d, 0x7b, 0xe5, 0xc0, 0x34, 0x7a, 0xc1, 0x41, 0xfb, 0xc0, 0x6c, 0x1f,
+	0x8a, 0x19, 0x2d, 0x07, 0x99, 0x5a, 0xdf, 0x0d, 0x86, 0xda, 0xcf, 0x12, 0x50, 0xfc, 0xd0, 0xf4,
+	0x7c, 0x64, 0xda, 0xb6, 0xf7, 0x1d, 0xf6, 0x32, 0x14, 0xba, 0x8e, 0x44, 0x2c, 0x27, 0xae, 0x26,
+	0xee, 0x14, 0xf4, 0x11, 0x82, 0x66, 0xf7, 0x06, 0x56, 0xaf, 0xb3, 0x65, 0x04, 0xe6, 0x72, 0x52,
+	0xcc, 0x86, 0x08, 0x76, 0x0b, 0x16, 0x3d, 0xb3, 0x67, 0x1a, 0xbe, 0xa9, 0x04, 0xa4, 0x38, 0xc9,
+	0x18, 0x96, 0x5d, 0x80, 0x6c, 0xd7, 0x0a, 0x1a, 0x07, 0xc6, 0x72, 0x9a, 0xcf, 0x4b, 0x28, 0x94,
+	0xde, 0x34, 0xba, 0xfe, 0x72, 0xe6, 0x6a, 0x2a, 0x94, 0x4e, 0x08, 0xed, 0x0d, 0x38, 0xdb, 0xf4,
+	0x06, 0x7e, 0xb0, 0x31, 0xb0, 0x3b, 0x3d, 0x53, 0x37, 0x7d, 0xd7, 0xb1, 0x7d, 0x93, 0x84, 0xed,
+	0x71, 0x8c, 0xd4, 0x56, 0x42, 0xda, 0x3d, 0x38, 0xfb, 0xc4, 0xf2, 0x83, 0x86, 0xe9, 0x1d, 0x59,
+	0x6d, 0xd3, 0xd7, 0xcd, 0x4f, 0x07, 0xa6, 0x1f, 0xd0, 0x1a, 0xb6, 0xd1, 0x47, 0x66, 0xa3, 0xad,
+	0x38, 0x46, 0x08, 0xed, 0x09, 0x9c, 0x8b, 0x33, 0xc9, 0x45, 0xde, 0x84, 0xbc, 0x2f, 0x71, 0xc8,
+	0x94, 0xba, 0x53, 0x5c, 0x5b, 0xae, 0x8e, 0x7d, 0x8c, 0xaa, 0x64, 0xd2, 0x43, 0x4a, 0xed, 0x1d,
+	0xc8, 0x49, 0x24, 0x63, 0x90, 0xa6, 0x55, 0xe4, 0x8a, 0x7c, 0x1c, 0x57, 0x25, 0x39, 0xae, 0x4a,
+	0x0f, 0x96, 0x48, 0x95, 0xba, 0xd3, 0x99, 0x4f, 0x77, 0x76, 0x0e, 0x32, 0x3d, 0xab, 0x6f, 0x05,
+	0x5c, 0xd4, 0x82, 0x2e, 0x00, 0x76, 0x13, 0x16, 0xdb, 0x8e, 0x1d, 0x58, 0xf6, 0xc0, 0x6c, 0x05,
+	0xce, 0xa1, 0xa9, 0xbe, 0xc9, 0x82, 0xc2, 0x36, 0x09, 0xa9, 0xb5, 0xa1, 0x3c, 0x5a, 0x4d, 0x6e,
+	0xfa, 0x0e, 0xa4, 0x5d, 0x84, 0xe5, 0x86, 0xcf, 0x4d, 0x6c, 0x18, 0x89, 0x75, 0x4e, 0x31, 0x65,
+	0x91, 0xe4, 0xb4, 0x45, 0x7e, 0x97, 0x86, 0x14, 0x32, 0x4d, 0x35, 0x06, 0x6a, 0x8f, 0xa2, 0xb6,
+	0xeb, 0x92, 0x53, 0x00, 0xec, 0x2a, 0x40, 0xc7, 0x74, 0x7b, 0xce, 0xb0, 0x6f, 0xda, 0x81, 0xd0,
+	0xfc, 0xf1, 0x4b, 0x7a, 0x04, 0xc7, 0xae, 0x41, 0xd1, 0x43, 0xc8, 0x6a, 0x1b, 0x2d, 0xdf, 0x0c,
+	0x96, 0x41, 0x91, 0x48, 0x64, 0xc3, 0x0c, 0xd8, 0x57, 0xe0, 0x82, 0x84, 0xe8, 0x68, 0xb4, 0x48,
+	0x27, 0xcf, 0xe9, 0xf5, 0x4c, 0x6f, 0xb9, 0x28, 0xa9, 0xcf, 0x47, 0xe6, 0x37, 0xc3, 0x69, 0x76,
+	0x1d, 0x4a, 0x7e, 0x80, 0x8e, 0xbd, 0x3f, 0xe8, 0x71, 0xe1, 0x25, 0x49, 0x5e, 0x54, 0x58, 0x92,
+	0x7e, 0x05, 0x55, 0x34, 0x4c, 0x3c, 0x65, 0x9c, 0x64, 0x41, 0x92, 0x14, 0x04, 0x8e, 0x08, 0x18,
+	0xa4, 0x3e, 0x71, 0xf6, 0x96, 0x17, 0xe5, 0x0c, 0x01, 0xe4, 0xb4, 0x24, 0x63, 0xe0, 0xab, 0x13,
+	0x20, 0x20, 0xb2, 0x82, 0xd1, 0xe9, 0x98, 0x1d, 0xf4, 0xfe, 0xc4, 0x9d, 0xbc, 0x2e, 0x00, 0xb6,
+	0x09, 0x4b, 0xbe, 0x65, 0xb7, 0xcd, 0x27, 0x86, 0x1f, 0xe8, 0xa6, 0xeb, 0x78, 0xc1, 0x72, 0x16,
+	0xe7, 0x8b, 0x6b, 0x17, 0xab, 0x22, 0x00, 0x54, 0x55, 0x00, 0xa8, 0x6e, 0xc9, 0x00, 0xa0, 0x8f,
+	0x73, 0xb0, 0x55, 0x38, 0x3b, 0xda, 0xf9, 0x4e, 0xe8, 0x46, 0x39, 0xbe, 0xfe, 0xb4, 0x29, 0xa6,
+	0x41, 0x49, 0xa2, 0xeb, 0x3d, 0xc3, 0x36, 0x97, 0xf3, 0x5c, 0xa7, 0x18, 0x8e, 0xdd, 0x85, 0xec,
+	0xc0, 0x0d, 0x2c, 0xfc, 0x98, 0x85, 0x93, 0x34, 0x92, 0x84, 0xec, 0x32, 0x00, 0x4e, 0x7e, 0x36,
+	0xd4, 0x4d, 0xa3, 0x33, 0x5c, 0x5e, 0xe2, 0x42, 0x23, 0x18, 0x5a, 0x96, 0x43, 0x2a, 0x86, 0x94,
+	0xb9, 0x86, 0x31, 0xdc, 0x06, 0x86, 0x2f, 0xe7, 0xb9, 0x6d, 0x7a, 0xda, 0x2f, 0x92, 0x00, 0x4d,
+	0xc3, 0x55, 0x27, 0x04, 0x6d, 0x8d, 0x8e, 0x23, 0x1c, 0x8b, 0x6c, 0x8d, 0xc0, 0x98, 0x0f, 0x25,
+	0xa7, 0xf8, 0x10, 0x7e, 0x8d, 0xbe, 0xf1, 0x99, 0xee, 0xfa, 0xdc, 0xc3, 0x92, 0xba, 0x84, 0x08,
+	0x1f, 0x38, 0x75, 0x32, 0x77, 0x9a, 0x1f, 0x29, 0x09, 0x91, 0xff, 0x06, 0x0e, 0xba, 0x6a, 0x46,
+	0xf8, 0x2f, 0x8d, 0x59, 0x05, 0xf2, 0xfb, 0x9e, 0xd3, 0xaf, 0xab, 0x8f, 0xb3, 0xa0, 0x87, 0x30,
+	0xc9, 0xa1, 0x31, 0x72, 0x08, 0x6b, 0x4b, 0x88, 0x7b, 0x01, 0x06, 0xe5, 0xbe, 0x30, 0x2d, 0x79,
+	0x01, 0x87, 0xb8, 0x3e, 0x66, 0x70, 0x80, 0x1b, 0x29, 0x08, 0xbc, 0x80, 0xe8, 0xfc, 0x1b, 0x03,
+	0x1c, 0x79, 0x56, 0x30, 0x14, 0x9e, 0xae, 0x8f, 0x10, 0xa4, 0x95, 0x6b, 0x04, 0x07, 0xc2, 0xa9,
+	0x75, 0x3e, 0x7e, 0x3b, 0xb9, 0x9c, 0xd8, 0xc8, 0xe3, 0x2e, 0x0c, 0xaf, 0x6b, 0x06, 0xda, 0x5f,
+	0x32, 0x70, 0x0e, 0x8d, 0xb5, 0x81, 0x86, 0xf6, 0x9d, 0x81, 0x87, 0xb1, 0x4a, 0x9a, 0xed, 0x6d,
+	0x45, 0xc2, 0x2d, 0x57, 0x5c, 0xd3, 0x26, 0xce, 0xba, 0xe2, 0x68, 0x60, 0x24, 0x6f, 0x8b, 0xcf,
+	0x29, 0x38, 0xd8, 0x3a, 0x64, 0xfa, 0x46, 0xd0, 0x3e, 0xe0, 0x96, 0x2d, 0xae, 0xbd, 0x3e, 0xc1,
+	0x3a, 0x6d, 0xc5, 0xea, 0x53, 0x62, 0xd1, 0x05, 0xe7, 0x2c, 0xfb, 0x57, 0x7e, 0x95, 0x86, 0x0c,
+	0x27, 0xc4, 0x13, 0x90, 0x32, 0x7a, 0x3d, 0xa9, 0xdd, 0xca, 0x29, 0x96, 0xc0, 0xa8, 0xfc, 0x29,
+	0x39, 0x02, 0x72, 0x73, 0x21, 0xf6, 0x50, 0xea, 0xf9, 0x42, 0x42, 0xec, 0x21, 0x7b, 0x0f, 0x52,
+	0xb6, 0x23, 0x42, 0xd1, 0xe9, 0x36, 0x4b, 0x02, 0x90, 0x93, 0x3d, 0x86, 0x52, 0x07, 0x91, 0x96,
+	0xcd, 0x4f, 0x85, 0x08, 0x00, 0x73, 0x59, 0x1c, 0x05, 0xc4, 0x38, 0xd9, 0xfb, 0x90, 0x3e, 0x08,
+	0x02, 0x97, 0xbb, 0x61, 0x71, 0x6d, 0xf5, 0x34, 0x1b, 0x7a, 0x8c, 0x7c, 0x28, 0x8f, 0xf3, 0x57,
+	0x9e, 0x40, 0x0a, 0x37, 0xc8, 0x6a, 0x90, 0xe3, 0x9f, 0x23, 0x4c, 0x71, 0xa7, 0xfa, 0x94, 0x8a,
+	0xb7, 0x32, 0x84, 0x34, 0x49, 0x67, 0xcb, 0xa1, 0x73, 0xab, 0xd3, 0xa8, 0xdc, 0x7b, 0x39, 0x74,
+	0x6f, 0x75, 0x18, 0x95, 0x83, 0x5f, 0x8e, 0x3a, 0xb8, 0x8a, 0xf6, 0x11, 0x17, 0x3f, 0x27, 0x5d,
+	0x3c, 0x2d, 0xa7, 0x38, 0x44, 0xc1, 0x80, 0x2f, 0x1e, 0x0e, 0xb4, 0xbf, 0x27, 0x00, 0x48, 0x89,
+	0xa7, 0x42, 0xec, 0x63, 0xc0, 0x74, 0xd0, 0xc5, 0xf4, 0x66, 0x7a, 0xa6, 0x08, 0x0e, 0x8b, 0x6b,
+	0xb7, 0x26, 0x36, 0x37, 0x62, 0x40, 0xdb, 0x2b, 0x6a, 0x91, 0x4a, 0x14, 0xc4, 0x6e, 0x40, 0x69,
+	0x60, 0x47, 0x64, 0xa9, 0x0d, 0xc4, 0xb0, 0x9a, 0x0d, 0x30, 0x92, 0xc0, 0x72, 0x90, 0x7a, 0x54,
+	0x6b, 0x96, 0x5f, 0x62, 0x79, 0x48, 0xd7, 0x77, 0x1b, 0xcd, 0x72, 0x82, 0x50, 0xf5, 0x67, 0xcd,
+	0x72, 0x92, 0x01, 0x64, 0xb7, 0x6a, 0x4f, 0x6a, 0xcd, 0x5a, 0x39, 0xc5, 0x0a, 0x90, 0xa9, 0xaf,
+	0x37, 0x37, 0x1f, 0x97, 0xd3, 0xac, 0x08, 0xb9, 0xdd, 0x7a, 0x73, 0x7b, 0x77, 0xa7, 0x51, 0xce,
+	0x10, 0xb0, 0xb9, 0xbb, 0xb3, 0x53, 0xdb, 0x6c, 0x96, 0xb3, 0x24, 0xe3, 0x71, 0x6d, 0x7d, 0xab,
+	0x9c, 0x23, 0xf2, 0xa6, 0xbe, 0xbe, 0x59, 0x2b, 0xe7, 0x37, 0xb2, 0x18, 0x8f, 0x86, 0xae, 0xa9,
+	0xfd, 0x34, 0x01, 0xd9, 0x86, 0xb0, 0xf1, 0xd6, 0x94, 0x2d, 0x4f, 0xfa, 0x98, 0x20, 0xfe, 0x77,
+	0xb7, 0x7b, 0x2d, 0xb6, 0x5d, 0xd2, 0xb0, 0xd9, 0xac, 0xe3, 0x7e, 0x51, 0x43, 0x1a, 0x35, 0xca,
+	0x89, 0x50, 0xc3, 0x26, 0x14, 0xb6, 0xeb, 0xeb, 0x9d, 0x8e, 0x67, 0xfa, 0x94, 0xec, 0xd2, 0x96,
+	0x7b, 0xf4, 0x26, 0xd7, 0x2e, 0x47, 0x5f, 0x93, 0x20, 0xf6, 0x3a, 0xc7, 0x3e, 0x90, 0xc7, 0xf4,
+	0xfc, 0x84, 0xce, 0xdb, 0xf5, 0xa3, 0x07, 0x92, 0xf8, 0xc1, 0x46, 0x1a, 0x92, 0x96, 0xab, 0xad,
+	0x42, 0x9a, 0xb0, 0x94, 0x3d, 0xf7, 0x2d, 0xcf, 0x17, 0x51, 0x2c, 0xab, 0x0b, 0x80, 0xe2, 0x62,
+	0x0f, 0xd3, 0x20, 0x17, 0x98, 0xd5, 0xf9, 0x18, 0xeb, 0x3c, 0x68, 0xb6, 0x5d, 0xa5, 0xc8, 0x6b,
+	0x24, 0x45, 0x06, 0x97, 0xca, 0x94, 0x05, 0x25, 0x9d, 0x8e, 0x54, 0x3c, 0xca, 0x52, 0x8c, 0x17,
+	0x45, 0x16, 0x1f, 0x6b, 0x1d, 0x48, 0xd5, 0x1c, 0x12, 0x53, 0xee, 0x7a, 0x6e, 0xbb, 0x25, 0x72,
+	0x39, 0xd6, 0x19, 0x1d, 0xe1, 0xfb, 0x0b, 0xa8, 0xee, 0x22, 0xcd, 0x34, 0xf8, 0xc4, 0x26, 0xe2,
+	0x89, 0x16, 0x45, 0x9a, 0x41, 0xcb, 0xf4, 0x3c, 0xc7, 0x13, 0xb4, 0x49, 0x45, 0xcb, 0x67, 0x6a,
+	0x34, 0x41, 0xb4, 0x1b, 0x19, 0x48, 0x99, 0x76, 0x47, 0xfb, 0xc3, 0x22, 0xe4, 0xf1, 0x00, 0xd6,
+	0x8e, 0x28, 0x65, 0xdd, 0xc3, 0xd3, 0xc5, 0x4f, 0xa1, 0x54, 0xfb, 0xd2, 0xe4, 0x59, 0x0d, 0xf7,
+	0xa7, 0x4b, 0x52, 0xf6, 0x08, 0x8a, 0x62, 0xd4, 0xc2, 0xf3, 0x66, 0xc8, 0xb8, 0x71, 0x6b, 0xda,
+	0x29, 0xe7, 0x8b, 0x54, 0x6b, 0x76, 0xc7, 0x75, 0x2c, 0x3b, 0xc0, 0x53, 0x61, 0xe8, 0x20, 0x58,
+	0x69, 0xcc, 0xde, 0x85, 0x62, 0x24, 0x12, 0xc9, 0x4f, 0x75, 0xac, 0x0a, 0x51, 0x7a, 0xf6, 0x4d,
+	0x28, 0x47, 0x40, 0xa1, 0x4c, 0xfa, 0x54, 0xca, 0x2c, 0x45, 0xf8, 0xb9, 0x46, 0x1b, 0xe8, 0xef,
+	0xce, 0x20, 0x90, 0x3b, 0xcb, 0x71, 0x61, 0xd7, 0x67, 0x0b, 0xd3, 0x89, 0x96, 0x4b, 0x2a, 0x78,
+	0x6a, 0x88, 0x6a, 0x2d, 0xf1, 0x22, 0xa3, 0xd5, 0xb1, 0x3c, 0x11, 0x72, 0x79, 0x26, 0x5f, 0x5c,
+	0xbb, 0x33, 0x5b, 0x50, 0x9d, 0x18, 0xb6, 0x14, 0xbd, 0xbe, 0xe8, 0xc6, 0x60, 0xec, 0x1b, 0x44,
+	0x88, 0x16, 0xe9, 0xe2, 0xf2, 0x6c, 0x39, 0xb1, 0x80, 0xfc, 0xe3, 0x04, 0x94, 0xa2, 0xdb, 0x65,
+	0xdf, 0x80, 0x6c, 0xcf, 0xd8, 0x33, 0x7b, 0x2a, 0x32, 0xaf, 0xcd, 0x67, 0xa6, 0xea, 0x13, 0xce,
+	0x54, 0xc3, 0x7a, 0x6d, 0xa8, 0x4b, 0x09, 0x95, 0x87, 0x50, 0x8c, 0xa0, 0x59, 0x19, 0x52, 0x87,
+	0xe6, 0x50, 0x96, 0xe2, 0x34, 0xa4, 0x53, 0x74, 0x64, 0xf4, 0x06, 0xaa, 0x25, 0x11, 0xc0, 0xdb,
+	0xc9, 0xb7, 0x12, 0x95, 0x1f, 0x26, 0xa0, 0x10, 0x5a, 0x0e, 0xbd, 0x29, 0xae, 0xd4, 0xca, 0x1c,
+	0xe6, 0xfe, 0x4f, 0x6b, 0xf4, 0xcf, 0x9c, 0xcc, 0x36, 0xbb, 0x50, 0xf2, 0x44, 0x3e, 0x6a, 0x59,
+	0xb6, 0xa5, 0xea, 0x98, 0xd7, 0x8e, 0x37, 0x78, 0x55, 0xa6, 0xb0, 0x6d, 0xe4, 0xa0, 0xb2, 0xde,
+	0x1b, 0x81, 0x4c, 0x87, 0x05, 0x4f, 0x36, 0x42, 0x42, 0xe2, 0x31, 0xe5, 0x4d, 0x4c, 0xa2, 0xe0,
+	0x91, 0x22, 0x4b, 0x5e, 0x04, 0x16, 0x4a, 0x4a, 0x99, 0x78, 0xa2, 0xa5, 0x57, 0xbc, 0x36, 0xa7,
+	0x48, 0xfc, 0xb2, 0x42, 0xc9, 0x10, 0xac, 0x3c, 0x80, 0x7c, 0x23, 0xf0, 0x4c, 0xa3, 0xbf, 0xcd,
+	0x9b, 0xaa, 0x3d, 0xec, 0xb1, 0x45, 0xc4, 0xd1, 0xf9, 0x58, 0xb4, 0x19, 0x34, 0xcf, 0xb5, 0x4f,
+	0xeb, 0x12, 0xaa, 0x7c, 0x81, 0x4d, 0x7f, 0x64, 0xef, 0xd8, 0x21, 0x25, 0xad, 0x8e, 0xb4, 0xd9,
+	0xed, 0x13, 0xd4, 0x51, 0x0b, 0x62, 0x34, 0xec, 0x50, 0x18, 0x8a, 0xa4, 0xf2, 0x69, 0x31, 0x60,
+	0x94, 0x55, 0xc3, 0x2c, 0xbf, 0x12, 0x56, 0x06, 0xc2, 0x00, 0x5f, 0x9a, 0x91, 0x97, 0xc2, 0x82,
+	0x21, 0x56, 0xf7, 0xa6, 0x67, 0xd5, 0xbd, 0x99, 0x51, 0xdd, 0x5b, 0xf9, 0x25, 0x9e, 0xa0, 0xe8,
+	0xa7, 0x78, 0xf1, 0x1d, 0x3e, 0x02, 0xc6, 0x3b, 0xa9, 0x56, 0xcc, 0xbd, 0x92, 0x27, 0x35, 0x3b,
+	0x65, 0xce, 0x14, 0xb5, 0xf1, 0x15, 0x28, 0xd2, 0xe1, 0x96, 0xd9, 0x81, 0x6f, 0x7d, 0x41, 0x07,
+	0x42, 0x89, 0xb4, 0x50, 0xf9, 0x79, 0x92, 0x3e, 0x4a, 0xf8, 0x71, 0xff, 0x07, 0x54, 0xde, 0x86,
+	0xb3, 0x4a, 0x50, 0xf4, 0x24, 0xa4, 0x4e, 0x92, 0x74, 0x46, 0x4a, 0x8a, 0xd8, 0xff, 0x26, 0x5d,
+	0x0d, 0x49, 0x21, 0x7b, 0xc3, 0xc0, 0x14, 0x75, 0x6f, 0x5a, 0x0f, 0x0f, 0xd9, 0x06, 0x21, 0xd9,
+	0x2d, 0x4c, 0x75, 0x8e, 0x2f, 0x33, 0xd3, 0xe4, 0x8d, 0x03, 0x66, 0x59, 0x9d, 0x08, 0xa8, 0xd2,
+	0x33, 0x69, 0xf7, 0xda, 0x5b, 0xb0, 0x18, 0x0f, 0xc1, 0x54, 0x2e, 0x3d, 0xdb, 0xf9, 0x60, 0x67,
+	0xf7, 0xa3, 0x1d, 0x2c, 0x41, 0x10, 0xd8, 0xde, 0xd9, 0xd8, 0x7d, 0xb6, 0xb3, 0x85, 0x55, 0x57,
+	0x09, 0xf2, 0xbb, 0xcf, 0x9a, 0x02, 0x4a, 0x8e, 0x44, 0x5c, 0x85, 0xfc, 0xba, 0x6b, 0xf1, 0x74,
+	0x4b, 0x91, 0x86, 0x27, 0x64, 0x19, 0x7d, 0x04, 0x40, 0x4d, 0x66, 0xa1, 0xee, 0x74, 0x38, 0x89,
+	0xcf, 0xde, 0x81, 0x2c, 0x47, 0xab, 0xb8, 0x77, 0x7d, 0xda, 0xc5, 0x88, 0xa0, 0x0d, 0x47, 0xba,
+	0x64, 0xa9, 0xfc, 0x29, 0x01, 0x79, 0x85, 0xc4, 0x18, 0x53, 0xa0, 0x66, 0xda, 0xb0, 0xb0, 0x93,
+	0x95, 0x1f, 0x7a, 0x6d, 0x0e, 0x61, 0xd5, 0x4d, 0xc5, 0xc4, 0x41, 0x2a, 0x91, 0x43, 0x31, 0x95,
+	0x23, 0x58, 0x8c, 0x4f, 0x63, 0xb9, 0x9d, 0xc3, 0x8e, 0xde, 0x37, 0xba, 0xea, 0xc2, 0x45, 0x81,
+	0x74, 0xae, 0x46, 0xeb, 0xcb, 0x0b, 0xa8, 0x10, 0x41, 0xb6, 0xb0, 0xfa, 0xc4, 0x25, 0x2e, 0x8c,
+	0x04, 0x40, 0x21, 0x05, 0x5d, 0xcd, 0xc7, 0xdc, 0x28, 0x6f, 0x2e, 0x04, 0xc4, 0xcd, 0xc9, 0x8d,
+	0x55, 0x87, 0xbc, 0xea, 0x10, 0x4e, 0xb8, 0xb0, 0x62, 0xa2, 0x28, 0x94, 0x2b, 0xf3, 0x71, 0x78,
+	0x35, 0x94, 0x1a, 0x5d, 0x0d, 0x69, 0x9f, 0xc2, 0x99, 0x89, 0x66, 0x88, 0xdd, 0x87, 0xbc, 0x67,
+	0xc6, 0x4a, 0xa0, 0x8b, 0x33, 0x5b, 0x28, 0x3d, 0x24, 0x25, 0x3f, 0xe4, 0x59, 0xa7, 0xe5, 0x73,
+	0x49, 0x8e, 0xda, 0xf7, 0x02, 0xc7, 0x36, 0x24, 0x52, 0xfb, 0x0e, 0x2c, 0x28, 0x66, 0x61, 0xc4,
+	0x17, 0x5c, 0x2e, 0xf4, 0xa7, 0x64, 0xd4, 0x9f, 0xfe, 0x9c, 0x06, 0x46, 0x87, 0xbe, 0x31, 0xe8,
+	0xf7, 0x0d, 0x4c, 0x84, 0xb2, 0x0b, 0xff, 0x1a, 0x5d, 0x32, 0x4a, 0xad, 0xe6, 0xef, 0xc3, 0x43,
+	0x1e, 0x8a, 0x30, 0x74, 0xc1, 0xd2, 0x7a, 0x6e, 0xd9, 0x1d, 0xe7, 0xb9, 0x5c, 0x12, 0x08, 0xf5,
+	0x11, 0xc7, 0xb0, 0x2f, 0xa3, 0x71, 0x1d, 0x5b, 0x85, 0xdd, 0x0b, 0x93, 0xc7, 0x8b, 0x6e, 0x84,
+	0xa9, 0x0a, 0x21, 0x2a, 0xf6, 0x55, 0x14, 0xe7, 0xb4, 0xc2, 0x5d, 0xa7, 0x4f, 0xd8, 0x35, 0xb5,
+	0x0e, 0x81, 0x13, 0x7e, 0xfa, 0xaf, 0xc3, 0x02, 0xdd, 0x72, 0x8c, 0xf8, 0x33, 0x27, 0xf3, 0x97,
+	0x88, 0x23, 0x94, 0xf0, 0x0a, 0x80, 0x7f, 0x68, 0x89, 0x80, 0xe9, 0xf3, 0x4a, 0x2c, 0xaf, 0x17,
+	0x08, 0x43, 0xa6, 0xf3, 0xd9, 0xc7, 0xb0, 0x80, 0xf9, 0xc4, 0xb3, 0xda, 0x2d, 0x59, 0x85, 0xe4,
+	0xf8, 0x69, 0xbc, 0x3f, 0x99, 0x4c, 0x26, 0x2c, 0x5d, 0x7d, 0xca, 0x19, 0xa3, 0xb5, 0x48, 0xa9,
+	0x1f, 0x41, 0x8d, 0xae, 0x52, 0xf3, 0xc7, 0x5f, 0xa5, 0x16, 0xa6, 0xdc, 0x72, 0x92, 0xde, 0x61,
+	0x1b, 0xe0, 0xf3, 0x6b, 0x1a, 0xd4, 0x5b, 0x95, 0xff, 0x3e, 0xbb, 0x08, 0xf9, 0x2e, 0xd6, 0x9c,
+	0x2e, 0x86, 0x41, 0x79, 0x55, 0x93, 0xe3, 0xf0, 0xc6, 0xb0, 0xf2, 0x1e, 0x9c, 0x99, 0xd0, 0xec,
+	0x34, 0xe5, 0x10, 0x16, 0xc1, 0x79, 0xac, 0xb4, 0xf6, 0x9c, 0x01, 0xb6, 0x0b, 0x3f, 0x49, 0xc2,
+	0xd9, 0xd8, 0xd6, 0xe5, 0xad, 0xee, 0x43, 0x48, 0x3a, 0x87, 0x33, 0xd3, 0xca, 0x14, 0x8e, 0xea,
+	0xee, 0x21, 0x7e, 0x1b, 0x64, 0x62, 0x0f, 0xa2, 0xde, 0x3c, 0xad, 0x9c, 0x8d, 0x9d, 0x19, 0x64,
+	0x12, 0xe4, 0x95, 0xef, 0x26, 0x20, 0xb9, 0x7b, 0x88, 0x81, 0x93, 0x5f, 0x9c, 0xb6, 0x02, 0x63,
+	0xaf, 0x17, 0x5e, 0x32, 0x54, 0xa6, 0xaa, 0xd0, 0x24, 0x12, 0x6c, 0x39, 0xd4, 0xd0, 0xa7, 0x28,
+	0xe6, 0x1a, 0x5e, 0x60, 0x19, 0x3d, 0xbe, 0x7a, 0x5e, 0x57, 0xe0, 0x9c, 0x37, 0xdc, 0x64, 0x1b,
+	0x95, 0x6b, 0xb4, 0x2f, 0x52, 0x00, 0x1b, 0x86, 0x6f, 0xc9, 0x4f, 0x72, 0x1d, 0x16, 0xfc, 0x41,
+	0xbb, 0x8d, 0x51, 0x11, 0x1b, 0xb1, 0x81, 0x2d, 0xaa, 0xc7, 0xb4, 0x5e, 0x92, 0xc8, 0x4d, 0xc2,
+	0x11, 0xd1, 0xbe, 0x61, 0xf5, 0x06, 0x9e, 0x29, 0x89, 0x44, 0x49, 0x55, 0x92, 0x48, 0x41, 0x74,
+	0x83, 0xc2, 0x4b, 0x60, 0xda, 0xed, 0x61, 0xab, 0xef, 0xb7, 0xdc, 0xfb, 0xab, 0x5c, 0x17, 0xa4,
+	0x92, 0xd8, 0xa7, 0x7e, 0xfd, 0xfe, 0xea, 0x38, 0xd5, 0xc3, 0xfb, 0x32, 0x19, 0x46, 0xa8, 0x1e,
+	0xde, 0x9f, 0xa0, 0x7a, 0xc8, 0x8f, 0x50, 0x9c, 0xea, 0x21, 0x36, 0x92, 0x67, 0x82, 0x9e, 0x1f,
+	0xa6, 0x7a, 0xa1, 0x5a, 0x96, 0x13, 0x2e, 0xe1, 0x84, 0xf4, 0x78, 0xa1, 0xdd, 0x2a, 0x9c, 0x33,
+	0xda, 0xc1, 0xc0, 0xc0, 0xe8, 0x17, 0xdb, 0x6e, 0x8e, 0x93, 0x33, 0x31, 0xd7, 0x88, 0x6e, 0x7a,
+	0xc4, 0x11, 0xdf, 0x7b, 0x3e, 0xca, 0xf1, 0x7e, 0xd4, 0x02, 0x6f, 0xc2, 0x85, 0x81, 0x8d, 0x11,
+	0xfe, 0xc0, 0xec, 0x8c, 0x29, 0x05, 0x9c, 0xe7, 0x9c, 0x9a, 0x8d, 0x69, 0xb6, 0x03, 0x2c, 0xde,
+	0x3a, 0x23, 0xd2, 0xc7, 0xe3, 0x41, 0x1e, 0x72, 0x75, 0xc2, 0x43, 0x1e, 0x45, 0x7a, 0x69, 0x24,
+	0xd4, 0xcb, 0xdd, 0x38, 0xc2, 0xd7, 0xfe, 0x96, 0x85, 0x42, 0xe8, 0x47, 0xd8, 0x1c, 0x16, 0x5c,
+	0xa7, 0xd3, 0xe2, 0xc7, 0x4c, 0x7a, 0xfe, 0xf5, 0xd9, 0x6e, 0x47, 0x79, 0xf6, 0x11, 0x91, 0xa2,
+	0x03, 0xe7, 0x5d, 0x39, 0xae, 0xfc, 0x28, 0xcb, 0x13, 0x37, 0x07, 0xd0, 0x93, 0xd3, 0x9e, 0xf3,
+	0x5c, 0xb9, 0xf0, 0xed, 0x39, 0x64, 0x61, 0x0b, 0xf4, 0x5c, 0xe7, 0x4c, 0x95, 0xdf, 0x60, 0x3f,
+	0x8f, 0xd0, 0x8b, 0xa6, 0x94, 0x13, 0xa3, 0xfc, 0x1d, 0x28, 0x4b, 0xfb, 0xd3, 0xa6, 0x85, 0xed,
+	0x85, 0x17, 0x2e, 0x0a, 0x3c, 0xea, 0x24, 0xac, 0x8e, 0xbe, 0xe3, 0x0d, 0x6c, 0xdb, 0xb2, 0xbb,
+	0x11, 0x52, 0xe1, 0x8a, 0x4b, 0x72, 0x22, 0xa4, 0x45, 0xa9, 0xe4, 0x02, 0x31, 0xa9, 0xc2, 0xcd,
+	0x16, 0x05, 0x3e, 0xa4, 0xbc, 0x0b, 0x19, 0x11, 0xfa, 0x32, 0x33, 0x5a, 0x82, 0xd1, 0xc9, 0xd3,
+	0x05, 0x25, 0xc3, 0x74, 0x2b, 0xea, 0x23, 0x0c, 0x8a, 0x24, 0x5f, 0xc6, 0xf2, 0xb7, 0xe6, 0x34,
+	0x6c, 0x55, 0x14, 0x48, 0x1b, 0x43, 0xaa, 0x90, 0x78, 0x38, 0x2f, 0x9a, 0x23, 0x0c, 0x9d, 0x5c,
+	0xb4, 0x5e, 0x80, 0xe1, 0x22, 0xe6, 0xbd, 0x25, 0x89, 0x54, 0x5a, 0x9f, 0x17, 0xcd, 0xbf, 0x47,
+	0x7f, 0x42, 0x44, 0x36, 0x59, 0x10, 0xae, 0x3e, 0xfa, 0x83, 0x22, 0x6a, 0x12, 0xbf, 0xe7, 0x84,
+	0x67, 0xc9, 0xa3, 0xff, 0x44, 0xc9, 0xc9, 0x13, 0xfa, 0x22, 0xe2, 0xe5, 0x39, 0xd2, 0xe9, 0x8f,
+	0xd1, 0x77, 0x21, 0x1f, 0xf8, 0x32, 0x21, 0x14, 0x67, 0x64, 0xf6, 0xa6, 0x67, 0xec, 0xef, 0xa3,
+	0x5d, 0xdc, 0x9e, 0x15, 0x08, 0xe3, 0xe4, 0x02, 0x5f, 0xc4, 0x27, 0xcc, 0xc4, 0x22, 0x65, 0x08,
+	0x09, 0x25, 0x6e, 0x9c, 0x4b, 0x53, 0x8e, 0x05, 0xd2, 0x08, 0x56, 0xe8, 0x86, 0xe3, 0xca, 0xc7,
+	0x50, 0x1e, 0xb7, 0xcf, 0x94, 0xa4, 0xb2, 0x1a, 0x4d, 0x2a, 0xd3, 0xc2, 0x72, 0x58, 0x87, 0x46,
+	0x13, 0x0e, 0x56, 0x7d, 0x3c, 0x9a, 0x6b, 0xdf, 0x4f, 0x42, 0xb9, 0xe9, 0xb8, 0xbc, 0xd1, 0xf7,
+	0xff, 0x3f, 0x0a, 0x9a, 0xdc, 0xe9, 0x0a, 0x9a, 0x78, 0x5a, 0xcf, 0x8f, 0xa5, 0xf5, 0x58, 0xea,
+	0xfd, 0x6d, 0x02, 0xce, 0x44, 0x8c, 0x21, 0x13, 0xef, 0x0b, 0x66, 0x4f, 0xea, 0x03, 0x31, 0x61,
+	0x8b, 0x2d, 0xde, 0x9c, 0x74, 0x9b, 0xf1, 0x75, 0xc2, 0x74, 0x5d, 0x79, 0xc8, 0xb3, 0x2e, 0xb6,
+	0xe8, 0xfc, 0x8a, 0x4b, 0x45, 0xab, 0x49, 0xbf, 0xe1, 0xfc, 0x22, 0xe3, 0x4a, 0xd2, 0x58, 0xb2,
+	0xfc, 0x6b, 0x02, 0x60, 0x44, 0x82, 0xf2, 0xa2, 0xb1, 0xef, 0xca, 0x31, 0xd2, 0x46, 0x31, 0x8f,
+	0xfe, 0x1d, 0x0b, 0xed, 0x2e, 0x3e, 0x63, 0x08, 0x57, 0x7e, 0x90, 0x10, 0xf1, 0x10, 0xcb, 0x1a,
+	0xbe, 0xba, 0xea, 0xbd, 0x38, 0x70, 0xb2, 0x0f, 0xc4, 0x2e, 0x07, 0xb2, 0xe3, 0x97, 0x03, 0xa7,
+	0x0f, 0x46, 0x9a, 0x03, 0xa5, 0x5a, 0xa7, 0xfb, 0xdf, 0xf3, 0x62, 0xed, 0xd7, 0x09, 0x58, 0x90,
+	0x2b, 0x4a, 0x57, 0xb9, 0x17, 0xa9, 0xd1, 0xae, 0x4d, 0x7a, 0x75, 0x94, 0xf6, 0xdf, 0xaf, 0xce,
+	0xee, 0x72, 0x37, 0x79, 0x1d, 0xb9, 0x49, 0xae, 0xfc, 0xae, 0xe7, 0xa7, 0xae, 0xaa, 0x0b, 0x9a,
+	0x98, 0x7b, 0x7c, 0x9e, 0x84, 0x34, 0xcd, 0xa1, 0x84, 0x94, 0xef, 0xb5, 0x4f, 0x4e, 0x65, 0x44,
+	0x45, 0xc4, 0x1d, 0x7f, 0x74, 0x29, 0x31, 0x9b, 0x18, 0xa9, 0x28, 0x5a, 0x61, 0x29, 0xc3, 0x8f,
+	0x40, 0x5e, 0xa7, 0x21, 0xbb, 0x46, 0x7f, 0x4c, 0xc8, 0x2c, 0x47, 0x8b, 0xa6, 0xf9, 0x54, 0x51,
+	0xe1, 0x1a, 0xb8, 0xc2, 0x25, 0x6c, 0x6e, 0x7b, 0x16, 0x36, 0xf8, 0x2d, 0xab, 0x23, 0xef, 0x86,
+	0xf2, 0x02, 0xb1, 0xdd, 0xa1, 0x49, 0x7a, 0xa5, 0x61, 0x7a, 0x34, 0x29, 0x9c, 0x26, 0x2f, 0x10,
+	0x38, 0x79, 0x0b, 0x96, 0x6c, 0x07, 0x27, 0x90, 0x14, 0x5d, 0x08, 0x8b, 0xaf, 0xae, 0xfc, 0xdf,
+	0x76, 0xc1, 0x76, 0xb6, 0x25, 0xf6, 0xa9, 0xdf, 0x25, 0x21, 0xf4, 0x67, 0x02, 0xb6, 0x92, 0x58,
+	0x73, 0x52, 0x40, 0x48, 0xe9, 0x79, 0x42, 0x34, 0x10, 0xd6, 0x3e, 0x4f, 0x40, 0x81, 0xcc, 0xa2,
+	0xae, 0xeb, 0x45, 0xab, 0x2b, 0xfe, 0x88, 0xb9, 0x32, 0xd5, 0xb8, 0xe2, 0x3a, 0xa7, 0x89, 0x64,
+	0xb2, 0x17, 0x7e, 0x15, 0xd2, 0x64, 0xee, 0x99, 0xff, 0x84, 0xf0, 0x2f, 0xc2, 0x49, 0xb4, 0xdb,
+	0x90, 0x26, 0x46, 0xfa, 0x63, 0x69, 0x7d, 0x6b, 0xab, 0xfc, 0x12, 0xfd, 0xb1, 0xa4, 0xd7, 0x9e,
+	0xee, 0x7e, 0x58, 0x2b, 0x27, 0x68, 0xfc, 0xac, 0xbe, 0xb5, 0xde, 0xac, 0x95, 0x93, 0xda, 0x1e,
+	0x2c, 0x3d, 0xc2, 0x8c, 0xf4, 0xdc, 0x18, 0x86, 0xfe, 0x5d, 0x85, 0xb3, 0x9e, 0xd9, 0x77, 0x02,
+	0xac, 0xed, 0x7a, 0x03, 0xfa, 0x13, 0xa7, 0x15, 0x79, 0x9c, 0x71, 0x46, 0x4c, 0x6d, 0x8a, 0x19,
+	0x7a, 0x1b, 0x70, 0xb2, 0x3f, 0xff, 0x1e, 0x73, 0xc1, 0x68, 0x11, 0xe9, 0xd2, 0x35, 0x6c, 0x7b,
+	0x24, 0x4e, 0xba, 0xd8, 0xab, 0x93, 0x09, 0x6c, 0x8c, 0x49, 0x21, 0xf4, 0x90, 0xb5, 0xf2, 0x8f,
+	0x04, 0xe4, 0x24, 0x96, 0x9c, 0x60, 0x8a, 0xc6, 0xc5, 0x76, 0x44, 0x57, 0xec, 0x1a, 0x0c, 0xf1,
+	0x0f, 0x84, 0xd4, 0x53, 0x81, 0xfc, 0xa5, 0x45, 0xcf, 0x3a, 0x32, 0xa5, 0x57, 0x09, 0x80, 0xdd,
+	0x86, 0x25, 0xd7, 0xb0, 0x3c, 0xf2, 0x2a, 0xf5, 0xdc, 0x47, 0xd4, 0x43, 0x8b, 0x02, 0xad, 0x1e,
+	0x06, 0x4d, 0x29, 0xf4, 0x33, 0x73, 0x15, 0xfa, 0xd9, 0xb9, 0x0a, 0xfd, 0xdc, 0x64, 0xa1, 0xaf,
+	0xbd, 0x83, 0x5f, 0x2e, 0x5e, 0xe6, 0xd2, 0x65, 0xc9, 0xe8, 0x4f, 0x26, 0x9d, 0x8f, 0x69, 0x5f,
+	0xd1, 0xf6, 0x44, 0x00, 0x5a, 0x03, 0x13, 0xd2, 0x78, 0x7d, 0x41, 0xec, 0x86, 0x6b, 0x7e, 0xa6,
+	0x9e, 0xe1, 0xd0, 0x98, 0xff, 0x59, 0x66, 0x1a, 0xfb, 0xea, 0x4e, 0x86, 0xc6, 0x74, 0xe5, 0xf3,
+	0xdc, 0xb4, 0xba, 0x07, 0xf2, 0x01, 0x8e, 0x2e, 0x21, 0xed, 0x19, 0xc0, 0xa8, 0xe4, 0xa0, 0x85,
+	0x47, 0x05, 0x36, 0x86, 0x6f, 0x0e, 0x8c, 0xe2, 0x6f, 0x72, 0xde, 0xf8, 0xbb, 0xf6, 0x47, 0x72,
+	0x62, 0xd7, 0x62, 0xdf, 0x82, 0x62, 0xa4, 0x1b, 0x65, 0xd7, 0xe7, 0x68, 0xec, 0x2b, 0x37, 0xe6,
+	0x69, 0x68, 0xe9, 0xde, 0x2d, 0x4c, 0x9b, 0xec, 0xda, 0x71, 0x29, 0x55, 0x48, 0xd5, 0x4e, 0xce,
+	0xba, 0xec, 0x7d, 0xc8, 0xf0, 0xb8, 0xcc, 0x5e, 0x99, 0x15, 0xaf, 0x85, 0xac, 0xcb, 0xc7, 0x87,
+	0x73, 0xb6, 0x0d, 0xf0, 0x11, 0xfd, 0x87, 0x3d, 0x97, 0xb0, 0xca, 0xec, 0x40, 0xb2, 0x9a, 0x60,
+	0xbb, 0x90, 0x57, 0x6f, 0xba, 0xd8, 0x64, 0x13, 0x35, 0xf6, 0xb8, 0xac, 0x72, 0xed, 0x18, 0x0a,
+	0xa9, 0xdb, 0xb7, 0xa1, 0x14, 0x7d, 0x1d, 0xc7, 0x6e, 0x4c, 0x65, 0x19, 0x7b, 0x71, 0x57, 0xb9,
+	0x79, 0x02, 0x95, 0x14, 0xbe, 0x05, 0xa9, 0xa6, 0xe1, 0xb2, 0x4b, 0xd3, 0x6e, 0xba, 0x95, 0xa8,
+	0x8b, 0x33, 0xaf, 0xc1, 0xb5, 0xd4, 0xf7, 0x92, 0x09, 0xdc, 0x73, 0x03, 0x16, 0x62, 0x8f, 0x14,
+	0xd8, 0xcd, 0xb9, 0x1e, 0x31, 0x1c, 0x23, 0x19, 0x85, 0xbe, 0x07, 0xb9, 0xf0, 0xe9, 0xe2, 0xf4,
+	0x1a, 0xb3, 0xf2, 0xf2, 0x04, 0x3e, 0xfa, 0xa8, 0xf2, 0x03, 0x28, 0x46, 0x9e, 0x2e, 0xce, 0x14,
+	0x72, 0x63, 0x4a, 0x53, 0x30, 0xf9, 0xe0, 0xf1, 0x13, 0x6c, 0x6d, 0xcd, 0xde, 0xfe, 0x26, 0xbd,
+	0xe6, 0x64, 0x6f, 0x8c, 0x58, 0xc4, 0x5b, 0xcf, 0x6a, 0xf4, 0xad, 0x67, 0x48, 0xa7, 0xb6, 0x59,
+	0x9d, 0x97, 0x5c, 0xae, 0x85, 0x2e, 0xa4, 0x82, 0xf2, 0x14, 0x17, 0x1a, 0xcb, 0x24, 0x53, 0x5c,
+	0x68, 0x3c, 0xa2, 0x6f, 0xdc, 0xfb, 0xf8, 0x6e, 0xd7, 0x0a, 0x0e, 0x06, 0x7b, 0xb4, 0xfe, 0x8a,
+	0x24, 0x57, 0xbf, 0x6b, 0x2b, 0xa3, 0x67, 0x68, 0x2b, 0x5d, 0xd3, 0x5e, 0x11, 0x52, 0xf6, 0xb2,
+	0xfc, 0x2f, 0x86, 0x7b, 0xff, 0x02, 0xce, 0xf7, 0x37, 0x09, 0x0e, 0x2b, 0x00, 0x00,
 }
@@ -6,14 +6,18 @@ import (
 	"strings"
 	"time"
 
+	ts "github.com/linkerd/linkerd2/controller/gen/apis/split/v1alpha1"
 	spclient "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
 	sp "github.com/linkerd/linkerd2/controller/gen/client/informers/externalversions"
 	spinformers "github.com/linkerd/linkerd2/controller/gen/client/informers/externalversions/serviceprofile/v1alpha1"
+	tsinformers "github.com/linkerd/linkerd2/controller/gen/client/informers/externalversions/split/v1alpha1"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	appsv1beta2 "k8s.io/api/apps/v1beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -21,6 +25,8 @@ import (
 	"k8s.io/client-go/informers"
 	arinformers "k8s.io/client-go/informers/admissionregistration/v1beta1"
 	appinformers "k8s.io/client-go/informers/apps/v1beta2"
+	batchinformers "k8s.io/client-go/informers/batch/v1"
+	batchbetainformers "k8s.io/client-go/informers/batch/v1beta1"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -32,30 +38,42 @@ type APIResource int
 
 // These constants enumerate Kubernetes resource types.
 const (
-	CM APIResource = iota
+	CJ APIResource = iota // cron job
+	CM
 	Deploy
 	Endpoint
-	MWC // mutating webhook configuration
+	Job
+	Link // multicluster link
+	MWC  // mutating webhook configuration
+	Node
+	NS
 	Pod
 	RC
 	RS
 	SP
 	Svc
+	TS // traffic split
 )
 
 // API provides shared informers for all Kubernetes objects
 type API struct {
 	Client kubernetes.Interface
 
+	cj       batchbetainformers.CronJobInformer
 	cm       coreinformers.ConfigMapInformer
 	deploy   appinformers.DeploymentInformer
 	endpoint coreinformers.EndpointsInformer
+	job      batchinformers.JobInformer
+	link     spinformers.LinkInformer
 	mwc      arinformers.MutatingWebhookConfigurationInformer
+	node     coreinformers.NodeInformer
+	ns       coreinformers.NamespaceInformer
 	pod      coreinformers.PodInformer
 	rc       coreinformers.ReplicationControllerInformer
 	rs       appinformers.ReplicaSetInformer
 	sp       spinformers.ServiceProfileInformer
 	svc      coreinformers.ServiceInformer
+	ts       tsinformers.TrafficSplitInformer
 
 	syncChecks        []cache.InformerSynced
 	sharedInformers   informers.SharedInformerFactory
@@ -95,6 +113,9 @@ func NewAPI(k8sClient kubernetes.Interface, spClient spclient.Interface, namespa
 
 	for _, resource := range resources {
 		switch resource {
+		case CJ:
+			api.cj = sharedInformers.Batch().V1beta1().CronJobs()
+			api.syncChecks = append(api.syncChecks, api.cj.Informer().HasSynced)
 		case CM:
 			api.cm = sharedInformers.Core().V1().ConfigMaps()
 			api.syncChecks = append(api.syncChecks, api.cm.Informer().HasSynced)
@@ -104,9 +125,21 @@ func NewAPI(k8sClient kubernetes.Interface, spClient spclient.Interface, namespa
 		case Endpoint:
 			api.endpoint = sharedInformers.Core().V1().Endpoints()
 			api.syncChecks = append(api.syncChecks, api.endpoint.Informer().HasSynced)
+		case Job:
+			api.job = sharedInformers.Batch().V1().Jobs()
+			api.syncChecks = append(api.syncChecks, api.job.Informer().HasSynced)
+		case Link:
+			api.link = spSharedInformers.Linkerd().V1alpha1().Links()
+			api.syncChecks = append(api.syncChecks, api.link.Informer().HasSynced)
 		case MWC:
 			api.mwc = sharedInformers.Admissionregistration().V1beta1().MutatingWebhookConfigurations()
 			api.syncChecks = append(api.syncChecks, api.mwc.Informer().HasSynced)
+		case Node:
+			api.node = sharedInformers.Core().V1().Nodes()
+			api.syncChecks = append(api.syncChecks, api.node.Informer().HasSynced)
+		case NS:
+			api.ns = sharedInformers.Core().V1().Namespaces()
+			api.syncChecks = append(api.syncChecks, api.ns.Informer().HasSynced)
 		case Pod:
 			api.pod = sharedInformers.Core().V1().Pods()
 			api.syncChecks = append(api.syncChecks, api.pod.Informer().HasSynced)
@@ -122,6 +155,9 @@ func NewAPI(k8sClient kubernetes.Interface, spClient spclient.Interface, namespa
 		case Svc:
 			api.svc = sharedInformers.Core().V1().Services()
 			api.syncChecks = append(api.syncChecks, api.svc.Informer().HasSynced)
+		case TS:
+			api.ts = spSharedInformers.Split().V1alpha1().TrafficSplits()
+			api.syncChecks = append(api.syncChecks, api.ts.Informer().HasSynced)
 		}
 	}
 
@@ -159,6 +195,22 @@ func (api *API) RS() appinformers.ReplicaSetInformer {
 	return api.rs
 }
 
+// Job provides access to a shared informer and lister for Jobs.
+func (api *API) Job() batchinformers.JobInformer {
+	if api.job == nil {
+		panic("Job informer not configured")
+	}
+	return api.job
+}
+
+// CJ provides access to a shared informer and lister for CronJobs.
+func (api *API) CJ() batchbetainformers.CronJobInformer {
+	if api.cj == nil {
+		panic("CJ informer not configured")
+	}
+	return api.cj
+}
+
 // Pod provides access to a shared informer and lister for Pods.
 func (api *API) Pod() coreinformers.PodInformer {
 	if api.pod == nil {
@@ -167,6 +219,22 @@ func (api *API) Pod() coreinformers.PodInformer {
 	return api.pod
 }
 
+// Node provides access to a shared informer and lister for Nodes.
+func (api *API) Node() coreinformers.NodeInformer {
+	if api.node == nil {
+		panic("Node informer not configured")
+	}
+	return api.node
+}
+
+// NS provides access to a shared informer and lister for Namespaces.
+func (api *API) NS() coreinformers.NamespaceInformer {
+	if api.ns == nil {
+		panic("NS informer not configured")
+	}
+	return api.ns
+}
+
 // RC provides access to a shared informer and lister for
 // ReplicationControllers.
 func (api *API) RC() coreinformers.ReplicationControllerInformer {
@@ -208,6 +276,22 @@ func (api *API) SP() spinformers.ServiceProfileInformer {
 	return api.sp
 }
 
+// Link provides access to a shared informer and lister for Links.
+func (api *API) Link() spinformers.LinkInformer {
+	if api.link == nil {
+		panic("Link informer not configured")
+	}
+	return api.link
+}
+
+// TS provides access to a shared informer and lister for TrafficSplits.
+func (api *API) TS() tsinformers.TrafficSplitInformer {
+	if api.ts == nil {
+		panic("TS informer not configured")
+	}
+	return api.ts
+}
+
 // MWC provides access to a shared informer and lister for MutatingWebhookConfigurations.
 func (api *API) MWC() arinformers.MutatingWebhookConfigurationInformer {
 	if api.mwc == nil {
@@ -223,16 +307,21 @@ func (api *API) GetObjects(namespace, restype, name string) ([]runtime.Object, e
 	switch restype {
 	case k8s.Namespace:
 		return api.getNamespaces(name)
+	case k8s.CronJob:
+		return api.getCronJobs(namespace, name)
 	case k8s.Deployment:
 		return api.getDeployments(namespace, name)
+	case k8s.Job:
+		return api.getJobs(namespace, name)
 	case k8s.Pod:
 		return api.getPods(namespace, name)
 	case k8s.ReplicationController:
 		return api.getRCs(namespace, name)
+	case k8s.ReplicaSet:
+		return api.getReplicaSets(namespace, name)
 	case k8s.Service:
 		return api.getServices(namespace, name)
 	default:
-		// TODO: ReplicaSet
 		return nil, status.Errorf(codes.Unimplemented, "unimplemented resource type: %s", restype)
 	}
 }
@@ -240,24 +329,52 @@ func (api *API) GetObjects(namespace, restype, name string) ([]runtime.Object, e
 // GetOwnerKindAndName returns the pod owner's kind and name, using owner
 // references from the Kubernetes API. The kind is represented as the Kubernetes
 // singular resource type (e.g. deployment, daemonset, job, etc.)
+//
+// Only the controller of the pod is considered its owner, so a bare pod is its
+// own owner. A ReplicaSet managed by a Deployment resolves to the Deployment,
+// and a Job managed by a CronJob resolves to the CronJob, if the API was
+// configured with Jobs.
 func (api *API) GetOwnerKindAndName(pod *apiv1.Pod) (string, string) {
-	if len(pod.GetOwnerReferences()) != 1 {
+	parent := controllerOf(pod)
+	if parent == nil {
 		return "pod", pod.Name
 	}
 
-	parent := pod.GetOwnerReferences()[0]
-	if parent.Kind == "ReplicaSet" {
+	switch parent.Kind {
+	case "ReplicaSet":
 		rs, err := api.RS().Lister().ReplicaSets(pod.Namespace).Get(parent.Name)
-		if err != nil || len(rs.GetOwnerReferences()) != 1 {
-			return strings.ToLower(parent.Kind), parent.Name
+		if err == nil {
+			if rsParent := controllerOf(rs); rsParent != nil {
+				return strings.ToLower(rsParent.Kind), rsParent.Name
+			}
+		}
+	case "Job":
+		if api.job == nil {
+			break
+		}
+		job, err := api.Job().Lister().Jobs(pod.Namespace).Get(parent.Name)
+		if err == nil {
+			if jobParent := controllerOf(job); jobParent != nil {
+				return strings.ToLower(jobParent.Kind), jobParent.Name
+			}
 		}
-		rsParent := rs.GetOwnerReferences()[0]
-		return strings.ToLower(rsParent.Kind), rsParent.Name
 	}
 
 	return strings.ToLower(parent.Kind), parent.Name
 }
 
+// controllerOf returns the owner reference of the controller of an object, or
+// its only owner reference if none is marked as the controller.
+func controllerOf(obj metav1.Object) *metav1.OwnerReference {
+	if ref := metav1.GetControllerOf(obj); ref != nil {
+		return ref
+	}
+	if refs := obj.GetOwnerReferences(); len(refs) == 1 {
+		return &refs[0]
+	}
+	return nil
+}
+
 // GetPodsFor returns all running and pending Pods associated with a given
 // Kubernetes object. Use includeFailed to also get failed Pods
 func (api *API) GetPodsFor(obj runtime.Object, includeFailed bool) ([]*apiv1.Pod, error) {
@@ -279,6 +396,19 @@ func (api *API) GetPodsFor(obj runtime.Object, includeFailed bool) ([]*apiv1.Pod
 		namespace = typed.Namespace
 		selector = labels.Set(typed.Spec.Selector.MatchLabels).AsSelector()
 
+	case *batchv1.Job:
+		namespace = typed.Namespace
+		selector = labels.Set(typed.Spec.Selector.MatchLabels).AsSelector()
+
+	case *batchv1beta1.CronJob:
+		// Special case for cron jobs:
+		// their pods are the pods of the jobs they created
+		namespace = typed.Namespace
+		pods, err = api.getCronJobPods(typed)
+		if err != nil {
+			return nil, err
+		}
+
 	case *apiv1.ReplicationController:
 		namespace = typed.Namespace
 		selector = labels.Set(typed.Spec.Selector).AsSelector()
@@ -301,9 +431,9 @@ func (api *API) GetPodsFor(obj runtime.Object, includeFailed bool) ([]*apiv1.Pod
 		return nil, fmt.Errorf("Cannot get object selector: %v", obj)
 	}
 
-	// if obj.(type) is Pod, we've already retrieved it and put it in pods
-	// for the other types, pods will still be empty
-	if len(pods) == 0 {
+	// if obj.(type) is Pod or CronJob, we've already retrieved its pods
+	// for the other types, the pods are listed with the selector
+	if selector != nil {
 		pods, err = api.Pod().Lister().Pods(namespace).List(selector)
 		if err != nil {
 			return nil, err
@@ -332,6 +462,12 @@ func GetNameAndNamespaceOf(obj runtime.Object) (string, string, error) {
 	case *appsv1beta2.ReplicaSet:
 		return typed.Name, typed.Namespace, nil
 
+	case *batchv1.Job:
+		return typed.Name, typed.Namespace, nil
+
+	case *batchv1beta1.CronJob:
+		return typed.Name, typed.Namespace, nil
+
 	case *apiv1.ReplicationController:
 		return typed.Name, typed.Namespace, nil
 
@@ -366,8 +502,9 @@ func GetNamespaceOf(obj runtime.Object) (string, error) {
 
 // getNamespaces returns the namespace matching the specified name. If no name
 // is given, it returns all namespaces, unless the API was configured to only
-// work with a single namespace, in which case it returns that namespace. Note
-// that namespace reads are not cached.
+// work with a single namespace, in which case it returns that namespace. The
+// namespaces are read from the NS informer if it's configured, and from the
+// Kubernetes API otherwise.
 func (api *API) getNamespaces(name string) ([]runtime.Object, error) {
 	namespaces := make([]*apiv1.Namespace, 0)
 
@@ -375,7 +512,19 @@ func (api *API) getNamespaces(name string) ([]runtime.Object, error) {
 		name = api.namespace
 	}
 
-	if name == "" {
+	if api.ns != nil {
+		var err error
+		if name == "" {
+			namespaces, err = api.NS().Lister().List(labels.Everything())
+		} else {
+			var namespace *apiv1.Namespace
+			namespace, err = api.NS().Lister().Get(name)
+			namespaces = []*apiv1.Namespace{namespace}
+		}
+		if err != nil {
+			return nil, err
+		}
+	} else if name == "" {
 		namespaceList, err := api.Client.CoreV1().Namespaces().List(metav1.ListOptions{})
 		if err != nil {
 			return nil, err
@@ -481,6 +630,107 @@ func (api *API) getRCs(namespace, name string) ([]runtime.Object, error) {
 	return objects, nil
 }
 
+func (api *API) getReplicaSets(namespace, name string) ([]runtime.Object, error) {
+	var err error
+	var replicaSets []*appsv1beta2.ReplicaSet
+
+	if namespace == "" {
+		replicaSets, err = api.RS().Lister().List(labels.Everything())
+	} else if name == "" {
+		replicaSets, err = api.RS().Lister().ReplicaSets(namespace).List(labels.Everything())
+	} else {
+		var rs *appsv1beta2.ReplicaSet
+		rs, err = api.RS().Lister().ReplicaSets(namespace).Get(name)
+		replicaSets = []*appsv1beta2.ReplicaSet{rs}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []runtime.Object{}
+	for _, rs := range replicaSets {
+		objects = append(objects, rs)
+	}
+
+	return objects, nil
+}
+
+func (api *API) getJobs(namespace, name string) ([]runtime.Object, error) {
+	var err error
+	var jobs []*batchv1.Job
+
+	if namespace == "" {
+		jobs, err = api.Job().Lister().List(labels.Everything())
+	} else if name == "" {
+		jobs, err = api.Job().Lister().Jobs(namespace).List(labels.Everything())
+	} else {
+		var job *batchv1.Job
+		job, err = api.Job().Lister().Jobs(namespace).Get(name)
+		jobs = []*batchv1.Job{job}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []runtime.Object{}
+	for _, job := range jobs {
+		objects = append(objects, job)
+	}
+
+	return objects, nil
+}
+
+func (api *API) getCronJobs(namespace, name string) ([]runtime.Object, error) {
+	var err error
+	var cronJobs []*batchv1beta1.CronJob
+
+	if namespace == "" {
+		cronJobs, err = api.CJ().Lister().List(labels.Everything())
+	} else if name == "" {
+		cronJobs, err = api.CJ().Lister().CronJobs(namespace).List(labels.Everything())
+	} else {
+		var cronJob *batchv1beta1.CronJob
+		cronJob, err = api.CJ().Lister().CronJobs(namespace).Get(name)
+		cronJobs = []*batchv1beta1.CronJob{cronJob}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []runtime.Object{}
+	for _, cronJob := range cronJobs {
+		objects = append(objects, cronJob)
+	}
+
+	return objects, nil
+}
+
+// getCronJobPods returns the pods of the jobs that the cron job controls.
+func (api *API) getCronJobPods(cronJob *batchv1beta1.CronJob) ([]*apiv1.Pod, error) {
+	jobs, err := api.Job().Lister().Jobs(cronJob.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	pods := []*apiv1.Pod{}
+	for _, job := range jobs {
+		owner := controllerOf(job)
+		if owner == nil || owner.Kind != "CronJob" || owner.Name != cronJob.Name {
+			continue
+		}
+		jobPods, err := api.Pod().Lister().Pods(job.Namespace).List(labels.Set(job.Spec.Selector.MatchLabels).AsSelector())
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, jobPods...)
+	}
+
+	return pods, nil
+}
+
 func (api *API) getServices(namespace, name string) ([]runtime.Object, error) {
 	services, err := api.GetServices(namespace, name)
 
@@ -515,6 +765,25 @@ func (api *API) GetServices(namespace, name string) ([]*apiv1.Service, error) {
 	return services, err
 }
 
+// GetTrafficSplits returns a list of TrafficSplit resources, based on input
+// namespace and name.
+func (api *API) GetTrafficSplits(namespace, name string) ([]*ts.TrafficSplit, error) {
+	var err error
+	var splits []*ts.TrafficSplit
+
+	if namespace == "" {
+		splits, err = api.TS().Lister().List(labels.Everything())
+	} else if name == "" {
+		splits, err = api.TS().Lister().TrafficSplits(namespace).List(labels.Everything())
+	} else {
+		var split *ts.TrafficSplit
+		split, err = api.TS().Lister().TrafficSplits(namespace).Get(name)
+		splits = []*ts.TrafficSplit{split}
+	}
+
+	return splits, err
+}
+
 // GetServicesFor returns all Service resources which include a pod of the given
 // resource object.  In other words, it returns all Services of which the given
 // resource object is a part of.
@@ -132,6 +132,40 @@ metadata:
   namespace: not-my-ns`,
 				},
 			},
+			getObjectsExpected{
+				err:       nil,
+				namespace: "my-ns",
+				resType:   k8s.CronJob,
+				name:      "my-cronjob",
+				k8sResResults: []string{`
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: my-cronjob
+  namespace: my-ns`,
+				},
+				k8sResMisc: []string{`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: my-cronjob-1541462400
+  namespace: my-ns`,
+				},
+			},
+			getObjectsExpected{
+				err:       nil,
+				namespace: "my-ns",
+				resType:   k8s.ReplicaSet,
+				name:      "",
+				k8sResResults: []string{`
+apiVersion: apps/v1beta2
+kind: ReplicaSet
+metadata:
+  name: my-rs
+  namespace: my-ns`,
+				},
+				k8sResMisc: []string{},
+			},
 			getObjectsExpected{
 				err:       nil,
 				namespace: "",
@@ -188,6 +222,8 @@ metadata:
 				t.Fatalf("NewFakeAPI returned an error: %s", err)
 			}
 
+			api.Sync()
+
 			namespaces, err := api.GetObjects("", k8s.Namespace, "")
 			if err != nil {
 				t.Fatalf("unexpected error: %s", err)
@@ -397,6 +433,116 @@ status:
 			getPodsForExpected{
 				err: nil,
 				k8sResInput: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: slow-cooker
+  namespace: emojivoto
+spec:
+  selector:
+    matchLabels:
+      job-name: slow-cooker`,
+				k8sResResults: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: slow-cooker-bxtnq
+  namespace: emojivoto
+  labels:
+    job-name: slow-cooker
+status:
+  phase: Running`,
+				},
+				k8sResMisc: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: slow-cooker-2-7wpnz
+  namespace: emojivoto
+  labels:
+    job-name: slow-cooker-2
+status:
+  phase: Running`,
+				},
+			},
+			getPodsForExpected{
+				err: nil,
+				k8sResInput: `
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: slow-cooker
+  namespace: emojivoto`,
+				k8sResResults: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: slow-cooker-1541462400-bxtnq
+  namespace: emojivoto
+  labels:
+    job-name: slow-cooker-1541462400
+status:
+  phase: Running`,
+				},
+				k8sResMisc: []string{`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: slow-cooker-1541462400
+  namespace: emojivoto
+  ownerReferences:
+  - apiVersion: batch/v1beta1
+    kind: CronJob
+    name: slow-cooker
+    controller: true
+spec:
+  selector:
+    matchLabels:
+      job-name: slow-cooker-1541462400`, `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: other-job
+  namespace: emojivoto
+spec:
+  selector:
+    matchLabels:
+      job-name: other-job`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: other-job-7wpnz
+  namespace: emojivoto
+  labels:
+    job-name: other-job
+status:
+  phase: Running`,
+				},
+			},
+			getPodsForExpected{
+				err: nil,
+				k8sResInput: `
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: never-scheduled
+  namespace: emojivoto`,
+				k8sResResults: []string{},
+				k8sResMisc: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-meshed
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+status:
+  phase: Running`,
+				},
+			},
+			getPodsForExpected{
+				err: nil,
+				k8sResInput: `
 apiVersion: v1
 kind: Pod
 metadata:
@@ -518,6 +664,51 @@ metadata:
   - apiVersion: batch/v1
     kind: Job
     name: slow-cooker`,
+		},
+		{
+			expectedOwnerKind: "cronjob",
+			expectedOwnerName: "slow-cooker",
+			podConfig: `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: slow-cooker-1541462400-bxtnq
+  namespace: default
+  ownerReferences:
+  - apiVersion: batch/v1
+    kind: Job
+    name: slow-cooker-1541462400
+    controller: true`,
+			extraConfigs: []string{`
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: slow-cooker-1541462400
+  namespace: default
+  ownerReferences:
+  - apiVersion: batch/v1beta1
+    kind: CronJob
+    name: slow-cooker
+    controller: true`,
+			},
+		},
+		{
+			expectedOwnerKind: "statefulset",
+			expectedOwnerName: "db",
+			podConfig: `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: db-0
+  namespace: default
+  ownerReferences:
+  - apiVersion: v1
+    kind: ConfigMap
+    name: db-config
+  - apiVersion: apps/v1
+    kind: StatefulSet
+    name: db
+    controller: true`,
 		},
 		{
 			expectedOwnerKind: "replicationcontroller",
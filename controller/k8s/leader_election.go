@@ -0,0 +1,78 @@
+package k8s
+
+import (
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// RunAsLeader elects one of the replicas of a controller to do the work that
+// must not be duplicated, such as writing resources, using a lease held on the
+// lockName config map in the given namespace. It blocks until this replica is
+// elected, and then calls run. The other replicas stand by, so that one of
+// them takes over if the leader goes away.
+//
+// A replica that loses its lease exits, since the work that run started can't
+// be stopped safely, and it restarts as a standby.
+func RunAsLeader(client kubernetes.Interface, namespace, lockName string, run func(stopCh <-chan struct{})) error {
+	identity, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+
+	// the election events are only logged, so that they don't require the
+	// permission to create events
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(log.Debugf)
+	recorder := broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: lockName})
+
+	lock := &resourcelock.ConfigMapLock{
+		ConfigMapMeta: metav1.ObjectMeta{Namespace: namespace, Name: lockName},
+		Client:        client.CoreV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity:      identity,
+			EventRecorder: recorder,
+		},
+	}
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: leaseDuration,
+		RenewDeadline: renewDeadline,
+		RetryPeriod:   retryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(stopCh <-chan struct{}) {
+				log.Infof("%s is the leader of %s/%s", identity, namespace, lockName)
+				run(stopCh)
+			},
+			OnStoppedLeading: func() {
+				log.Fatalf("%s lost the lease on %s/%s", identity, namespace, lockName)
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					log.Infof("standing by while %s is the leader of %s/%s", leader, namespace, lockName)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	elector.Run()
+	return nil
+}
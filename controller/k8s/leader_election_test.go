@@ -0,0 +1,45 @@
+package k8s
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+func TestRunAsLeader(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	elected := make(chan struct{})
+
+	go func() {
+		err := RunAsLeader(client, "linkerd", "linkerd-ca-leader", func(<-chan struct{}) {
+			close(elected)
+		})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	}()
+
+	select {
+	case <-elected:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the replica to be elected")
+	}
+
+	lock, err := client.CoreV1().ConfigMaps("linkerd").Get("linkerd-ca-leader", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	record := lock.Annotations[resourcelock.LeaderElectionRecordAnnotationKey]
+	if !strings.Contains(record, `"holderIdentity":"`+hostname+`"`) {
+		t.Fatalf("Expected the lease to be held by %s, got %s", hostname, record)
+	}
+}
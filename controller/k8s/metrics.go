@@ -0,0 +1,93 @@
+package k8s
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// workqueueMetrics are the metrics of the named workqueues that the
+// controllers process the updates of their informers with, such as the
+// "certificates" queue of the CA, by queue name. A growing depth means that a
+// controller doesn't keep up with the changes of the cluster.
+type workqueueMetrics struct {
+	depth        *prometheus.GaugeVec
+	adds         *prometheus.CounterVec
+	latency      *prometheus.SummaryVec
+	workDuration *prometheus.SummaryVec
+	retries      *prometheus.CounterVec
+}
+
+// RegisterWorkqueueMetrics registers the metrics of the workqueues with the
+// given registerer. It must be called before the workqueues are created, and
+// at most once per process, since client-go only accepts one metrics
+// provider.
+func RegisterWorkqueueMetrics(registerer prometheus.Registerer) error {
+	m := newWorkqueueMetrics()
+	for _, collector := range []prometheus.Collector{m.depth, m.adds, m.latency, m.workDuration, m.retries} {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	workqueue.SetProvider(m)
+	return nil
+}
+
+func newWorkqueueMetrics() *workqueueMetrics {
+	return &workqueueMetrics{
+		depth: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "workqueue_depth",
+				Help: "The number of items waiting to be processed in a workqueue.",
+			},
+			[]string{"name"},
+		),
+		adds: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "workqueue_adds_total",
+				Help: "The number of items added to a workqueue.",
+			},
+			[]string{"name"},
+		),
+		latency: prometheus.NewSummaryVec(
+			prometheus.SummaryOpts{
+				Name: "workqueue_queue_latency_microseconds",
+				Help: "How long the items of a workqueue waited before being processed, in microseconds.",
+			},
+			[]string{"name"},
+		),
+		workDuration: prometheus.NewSummaryVec(
+			prometheus.SummaryOpts{
+				Name: "workqueue_work_duration_microseconds",
+				Help: "How long processing the items of a workqueue took, in microseconds.",
+			},
+			[]string{"name"},
+		),
+		retries: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "workqueue_retries_total",
+				Help: "The number of items of a workqueue that were requeued after failing.",
+			},
+			[]string{"name"},
+		),
+	}
+}
+
+func (m *workqueueMetrics) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return m.depth.WithLabelValues(name)
+}
+
+func (m *workqueueMetrics) NewAddsMetric(name string) workqueue.CounterMetric {
+	return m.adds.WithLabelValues(name)
+}
+
+func (m *workqueueMetrics) NewLatencyMetric(name string) workqueue.SummaryMetric {
+	return m.latency.WithLabelValues(name)
+}
+
+func (m *workqueueMetrics) NewWorkDurationMetric(name string) workqueue.SummaryMetric {
+	return m.workDuration.WithLabelValues(name)
+}
+
+func (m *workqueueMetrics) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return m.retries.WithLabelValues(name)
+}
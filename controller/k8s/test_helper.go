@@ -27,9 +27,10 @@ func NewFakeAPI(namespace string, configs ...string) (*API, error) {
 		if err != nil {
 			return nil, err
 		}
-		if strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind) == k8s.ServiceProfile {
+		switch strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind) {
+		case k8s.ServiceProfile, "link", k8s.TrafficSplit:
 			spObjs = append(spObjs, obj)
-		} else {
+		default:
 			objs = append(objs, obj)
 		}
 	}
@@ -40,14 +41,20 @@ func NewFakeAPI(namespace string, configs ...string) (*API, error) {
 		clientSet,
 		spClientSet,
 		namespace,
+		CJ,
 		CM,
 		Deploy,
 		Endpoint,
+		Job,
+		Node,
+		NS,
 		Pod,
 		RC,
 		RS,
 		Svc,
 		SP,
+		TS,
+		Link,
 		MWC,
 	), nil
 }
@@ -0,0 +1,144 @@
+package prober
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Results of the synthetic requests, used as the values of the result label
+// of the linkerd_probe_requests_total counter.
+const (
+	probeSuccess = "success"
+	probeFailure = "failure"
+)
+
+// Prober periodically sends an HTTP GET request to each of its URLs, and
+// exports the results and the latency of the requests as Prometheus metrics,
+// labeled with the URL. The prober runs in a meshed pod, so that the proxy
+// also records the golden metrics of the synthetic requests, apart from those
+// of the organic traffic of their destinations.
+type Prober struct {
+	name   string
+	urls   []string
+	period time.Duration
+	client *http.Client
+
+	// failing is whether the last request to each URL failed, only accessed
+	// by Run, to log the changes of the URLs' state
+	failing map[string]bool
+
+	latency  *prometheus.HistogramVec
+	requests *prometheus.CounterVec
+}
+
+// NewProber initializes a Prober for the plaintext HTTP URLs, which the proxy
+// can record the requests to, and returns an error if a URL or the period is
+// invalid.
+func NewProber(name string, urls []string, period time.Duration) (*Prober, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("probe %s has no URLs", name)
+	}
+	for _, u := range urls {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL %q in probe %s: %s", u, name, err)
+		}
+		if parsed.Scheme != "http" || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid URL %q in probe %s: must be an http:// URL", u, name)
+		}
+	}
+	if period <= 0 {
+		return nil, fmt.Errorf("invalid period %s in probe %s", period, name)
+	}
+
+	labels := prometheus.Labels{"probe": name}
+	return &Prober{
+		name:   name,
+		urls:   urls,
+		period: period,
+		// a request that's still pending when the next one is due has failed
+		client:  &http.Client{Timeout: period},
+		failing: make(map[string]bool),
+		latency: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:        "linkerd_probe_latency_ms",
+				Help:        "The latency of the successful synthetic requests to the URL, in milliseconds.",
+				ConstLabels: labels,
+				Buckets:     []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000},
+			},
+			[]string{"url"},
+		),
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "linkerd_probe_requests_total",
+				Help:        "The number of synthetic requests to the URL, by result: success or failure.",
+				ConstLabels: labels,
+			},
+			[]string{"url", "result"},
+		),
+	}, nil
+}
+
+// RegisterMetrics registers the Prober's metrics with the given registerer.
+func (p *Prober) RegisterMetrics(registerer prometheus.Registerer) error {
+	for _, collector := range []prometheus.Collector{p.latency, p.requests} {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run requests the URLs every period until stopCh is closed.
+func (p *Prober) Run(stopCh <-chan struct{}) {
+	defer runtime.HandleCrash()
+
+	log.Infof("probing %v every %s", p.urls, p.period)
+	wait.Until(p.probe, p.period, stopCh)
+}
+
+func (p *Prober) probe() {
+	for _, u := range p.urls {
+		start := time.Now()
+		if err := p.get(u); err != nil {
+			if !p.failing[u] {
+				log.Warnf("synthetic request to %s failed: %s", u, err)
+			}
+			p.failing[u] = true
+			p.requests.WithLabelValues(u, probeFailure).Inc()
+			continue
+		}
+
+		if p.failing[u] {
+			log.Infof("synthetic requests to %s succeed again", u)
+		}
+		p.failing[u] = false
+		p.latency.WithLabelValues(u).Observe(float64(time.Since(start)) / float64(time.Millisecond))
+		p.requests.WithLabelValues(u, probeSuccess).Inc()
+	}
+}
+
+func (p *Prober) get(u string) error {
+	rsp, err := p.client.Get(u)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	// drain the body so that the connection can be reused
+	io.Copy(ioutil.Discard, rsp.Body)
+
+	// like the proxy, only count the server errors as failures
+	if rsp.StatusCode >= 500 {
+		return fmt.Errorf("unexpected status %s", rsp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package prober
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func gatherProbeMetrics(t *testing.T, registry *prometheus.Registry) map[string]float64 {
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			name := family.GetName()
+			for _, label := range metric.GetLabel() {
+				name += "/" + label.GetValue()
+			}
+			switch {
+			case metric.Counter != nil:
+				values[name] = metric.GetCounter().GetValue()
+			case metric.Histogram != nil:
+				values[name] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return values
+}
+
+func TestNewProber(t *testing.T) {
+	testCases := []struct {
+		urls   []string
+		period time.Duration
+		err    string
+	}{
+		{
+			urls:   []string{"http://web.emojivoto.svc.cluster.local:80/health"},
+			period: 10 * time.Second,
+		},
+		{
+			period: 10 * time.Second,
+			err:    "probe web has no URLs",
+		},
+		{
+			urls:   []string{"https://web.emojivoto.svc.cluster.local/health"},
+			period: 10 * time.Second,
+			err:    "invalid URL \"https://web.emojivoto.svc.cluster.local/health\" in probe web: must be an http:// URL",
+		},
+		{
+			urls:   []string{"/health"},
+			period: 10 * time.Second,
+			err:    "invalid URL \"/health\" in probe web: must be an http:// URL",
+		},
+		{
+			urls: []string{"http://web.emojivoto.svc.cluster.local:80/health"},
+			err:  "invalid period 0s in probe web",
+		},
+	}
+
+	for i, tc := range testCases {
+		_, err := NewProber("web", tc.urls, tc.period)
+		if tc.err == "" {
+			if err != nil {
+				t.Fatalf("test case %d: unexpected error: %s", i, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.err {
+			t.Fatalf("test case %d: expected error %q, got %v", i, tc.err, err)
+		}
+	}
+}
+
+func TestProber(t *testing.T) {
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case !healthy:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	health := server.URL + "/health"
+	missing := server.URL + "/missing"
+	p, err := NewProber("web", []string{health, missing}, 10*time.Second)
+	if err != nil {
+		t.Fatalf("NewProber returned an error: %s", err)
+	}
+	registry := prometheus.NewRegistry()
+	if err := p.RegisterMetrics(registry); err != nil {
+		t.Fatalf("RegisterMetrics returned an error: %s", err)
+	}
+
+	p.probe()
+	p.probe()
+	healthy = false
+	p.probe()
+
+	values := gatherProbeMetrics(t, registry)
+	expected := map[string]float64{
+		"linkerd_probe_latency_ms/web/" + health:              2,
+		"linkerd_probe_requests_total/web/success/" + health:  2,
+		"linkerd_probe_requests_total/web/failure/" + health:  1,
+		"linkerd_probe_latency_ms/web/" + missing:             3,
+		"linkerd_probe_requests_total/web/success/" + missing: 3,
+	}
+	for name, value := range expected {
+		if values[name] != value {
+			t.Errorf("Expected %s to be %f, got %f", name, value, values[name])
+		}
+	}
+}
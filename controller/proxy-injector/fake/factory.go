@@ -17,6 +17,7 @@ const (
 	DefaultNamespace             = "default"
 	FileProxySpec                = "fake/data/config-proxy.yaml"
 	FileProxyInitSpec            = "fake/data/config-proxy-init.yaml"
+	FileDebugSpec                = "fake/data/config-debug.yaml"
 	FileTLSTrustAnchorVolumeSpec = "fake/data/config-linkerd-trust-anchors.yaml"
 	FileTLSIdentityVolumeSpec    = "fake/data/config-linkerd-secrets.yaml"
 )
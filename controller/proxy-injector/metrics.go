@@ -0,0 +1,39 @@
+package injector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Results of the admission reviews, used as the values of the result label of
+// the proxy_injector_admission_review_duration_seconds histogram.
+const (
+	reviewInjected = "injected"
+	reviewSkipped  = "skipped"
+	reviewFailed   = "failed"
+	reviewTimedOut = "timed-out"
+)
+
+// metrics are the Prometheus metrics of a Webhook. The Kubernetes API server
+// waits on each admission review, so their latency adds to the creation time
+// of every pod.
+type metrics struct {
+	reviewDuration *prometheus.HistogramVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		reviewDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "proxy_injector_admission_review_duration_seconds",
+				Help:    "How long the proxy injector took to respond to admission reviews, by result.",
+				Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			},
+			[]string{"result"},
+		),
+	}
+}
+
+// RegisterMetrics registers the Webhook's metrics with the given registerer.
+func (w *Webhook) RegisterMetrics(registerer prometheus.Registerer) error {
+	return registerer.Register(w.metrics.reviewDuration)
+}
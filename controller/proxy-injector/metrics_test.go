@@ -0,0 +1,60 @@
+package injector
+
+import (
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/proxy-injector/fake"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// reviewCount returns the number of admission reviews with the given result
+// in the duration histogram of w, as gathered by a Prometheus registry.
+func reviewCount(t *testing.T, w *Webhook, result string) uint64 {
+	registry := prometheus.NewRegistry()
+	if err := w.RegisterMetrics(registry); err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != "proxy_injector_admission_review_duration_seconds" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "result" && label.GetValue() == result {
+					return m.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestWebhookMetrics(t *testing.T) {
+	fakeClient, err := fake.NewClient("")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	w, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	for _, file := range []string{"inject-enabled-request.json", "inject-disabled-request.json"} {
+		data, err := factory.HTTPRequestBody(file)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		w.Mutate(data)
+	}
+
+	for _, result := range []string{reviewInjected, reviewSkipped} {
+		if count := reviewCount(t, w, result); count != 1 {
+			t.Fatalf("Expected 1 %s admission review, got %d", result, count)
+		}
+	}
+}
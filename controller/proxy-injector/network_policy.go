@@ -0,0 +1,170 @@
+package injector
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// proxyNetworkPolicyName is the name of the NetworkPolicy that the webhook
+	// creates in the namespaces of the pods it injects.
+	proxyNetworkPolicyName = "linkerd-proxy"
+
+	envVarKeyProxyControlURL      = "LINKERD2_PROXY_CONTROL_URL"
+	envVarKeyProxyControlListener = "LINKERD2_PROXY_CONTROL_LISTENER"
+
+	proxyInboundPortName = "linkerd-proxy"
+	proxyMetricsPortName = "linkerd-metrics"
+)
+
+// EnableNetworkPolicies makes the webhook create a NetworkPolicy in the
+// namespace of each pod it injects, which lets the proxies of the namespace
+// reach the control plane and be scraped and tapped by it, so that the mesh
+// works in the namespaces that deny traffic by default. It must be called
+// before the webhook serves requests.
+func (w *Webhook) EnableNetworkPolicies() {
+	w.networkPolicies = true
+}
+
+// ensureNetworkPolicy creates or updates the NetworkPolicy of the injected
+// proxies in the namespace. The policy is left alone if it wasn't created by
+// the webhook, so that it can be replaced by a hand-written one.
+func (w *Webhook) ensureNetworkPolicy(ns string, proxy *corev1.Container) error {
+	policy, err := proxyNetworkPolicy(ns, w.controllerNamespace, proxy)
+	if err != nil {
+		return err
+	}
+
+	policies := w.client.NetworkingV1().NetworkPolicies(ns)
+	existing, err := policies.Get(proxyNetworkPolicyName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		log.Infof("creating the %s network policy in namespace %s", proxyNetworkPolicyName, ns)
+		_, err = policies.Create(policy)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Labels[k8sPkg.ControllerNSLabel] != w.controllerNamespace || reflect.DeepEqual(existing.Spec, policy.Spec) {
+		return nil
+	}
+	log.Infof("updating the %s network policy in namespace %s", proxyNetworkPolicyName, ns)
+	existing.Spec = policy.Spec
+	_, err = policies.Update(existing)
+	return err
+}
+
+// proxyNetworkPolicy returns the NetworkPolicy of the proxies of the control
+// plane in controllerNamespace that are injected in ns, with the ports of the
+// proxy container. The policies are additive, so it only allows the traffic of
+// the proxies themselves: the traffic of the applications, which the proxies
+// receive on the ports of the applications, is left to their own policies.
+//
+// The control plane pods are selected by their label, since the namespace of
+// the control plane has no label to select it by.
+func proxyNetworkPolicy(ns, controllerNamespace string, proxy *corev1.Container) (*networkingv1.NetworkPolicy, error) {
+	inboundPort, err := containerPort(proxy, proxyInboundPortName)
+	if err != nil {
+		return nil, err
+	}
+	metricsPort, err := containerPort(proxy, proxyMetricsPortName)
+	if err != nil {
+		return nil, err
+	}
+	controlPort, err := envVarURLPort(proxy, envVarKeyProxyControlListener)
+	if err != nil {
+		return nil, err
+	}
+	apiPort, err := envVarURLPort(proxy, envVarKeyProxyControlURL)
+	if err != nil {
+		return nil, err
+	}
+
+	tcp, udp := corev1.ProtocolTCP, corev1.ProtocolUDP
+	port := func(protocol *corev1.Protocol, number int) networkingv1.NetworkPolicyPort {
+		p := intstr.FromInt(number)
+		return networkingv1.NetworkPolicyPort{Protocol: protocol, Port: &p}
+	}
+	controlPlanePods := func(component string) networkingv1.NetworkPolicyPeer {
+		selector := &metav1.LabelSelector{}
+		if component == "" {
+			selector.MatchExpressions = []metav1.LabelSelectorRequirement{{Key: k8sPkg.ControllerComponentLabel, Operator: metav1.LabelSelectorOpExists}}
+		} else {
+			selector.MatchLabels = map[string]string{k8sPkg.ControllerComponentLabel: component}
+		}
+		return networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{}, PodSelector: selector}
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      proxyNetworkPolicyName,
+			Namespace: ns,
+			Labels:    map[string]string{k8sPkg.ControllerNSLabel: controllerNamespace},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{k8sPkg.ControllerNSLabel: controllerNamespace},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				// the meshed clients in any namespace
+				{Ports: []networkingv1.NetworkPolicyPort{port(&tcp, inboundPort)}},
+				// the metrics are scraped by Prometheus, and the proxies are tapped
+				// by the controller
+				{
+					From:  []networkingv1.NetworkPolicyPeer{controlPlanePods("")},
+					Ports: []networkingv1.NetworkPolicyPort{port(&tcp, metricsPort), port(&tcp, controlPort)},
+				},
+			},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				// the proxies resolve the address of the proxy API, and of the
+				// destinations of their clients
+				{Ports: []networkingv1.NetworkPolicyPort{port(&udp, 53), port(&tcp, 53)}},
+				{
+					To:    []networkingv1.NetworkPolicyPeer{controlPlanePods("controller")},
+					Ports: []networkingv1.NetworkPolicyPort{port(&tcp, apiPort)},
+				},
+			},
+		},
+	}, nil
+}
+
+func containerPort(container *corev1.Container, name string) (int, error) {
+	for _, port := range container.Ports {
+		if port.Name == name {
+			return int(port.ContainerPort), nil
+		}
+	}
+	return 0, fmt.Errorf("the proxy spec has no %s port", name)
+}
+
+// envVarURLPort returns the port of the URL of an env var of the container,
+// such as "tcp://0.0.0.0:4190".
+func envVarURLPort(container *corev1.Container, name string) (int, error) {
+	for _, env := range container.Env {
+		if env.Name != name {
+			continue
+		}
+		u, err := url.Parse(env.Value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value \"%s\" for the %s env var of the proxy spec: %s", env.Value, name, err)
+		}
+		port, err := strconv.Atoi(u.Port())
+		if err != nil {
+			return 0, fmt.Errorf("invalid value \"%s\" for the %s env var of the proxy spec: must have a port", env.Value, name)
+		}
+		return port, nil
+	}
+	return 0, fmt.Errorf("the proxy spec has no %s env var", name)
+}
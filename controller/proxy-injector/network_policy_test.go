@@ -0,0 +1,126 @@
+package injector
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/proxy-injector/fake"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEnsureNetworkPolicy(t *testing.T) {
+	identity := &k8s.TLSIdentity{
+		Name:                "nginx",
+		Kind:                "deployment",
+		Namespace:           "emojivoto",
+		ControllerNamespace: fake.DefaultControllerNamespace,
+	}
+	proxy, _, err := webhook.containersSpec(identity)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	ports := func(policy *networkingv1.NetworkPolicy) []int {
+		numbers := []int{}
+		for _, rule := range policy.Spec.Ingress {
+			for _, port := range rule.Ports {
+				numbers = append(numbers, port.Port.IntValue())
+			}
+		}
+		for _, rule := range policy.Spec.Egress {
+			for _, port := range rule.Ports {
+				numbers = append(numbers, port.Port.IntValue())
+			}
+		}
+		return numbers
+	}
+
+	t.Run("creates the policy with the ports of the proxy", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset()
+		w, err := NewWebhook(client, testWebhookResources, fake.DefaultControllerNamespace)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		for i := 0; i < 2; i++ {
+			if err := w.ensureNetworkPolicy("emojivoto", proxy); err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+		}
+
+		policy, err := client.NetworkingV1().NetworkPolicies("emojivoto").Get(proxyNetworkPolicyName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if selector := policy.Spec.PodSelector.MatchLabels[k8s.ControllerNSLabel]; selector != fake.DefaultControllerNamespace {
+			t.Errorf("Expected the policy to select the pods of the %s control plane, got %s", fake.DefaultControllerNamespace, selector)
+		}
+		expected := "[4143 4191 4190 53 53 8086]"
+		if actual := fmt.Sprint(ports(policy)); actual != expected {
+			t.Errorf("Ports mismatch\nExpected: %s\nActual: %s", expected, actual)
+		}
+	})
+
+	t.Run("updates the policy when the proxy's ports change", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset()
+		w, err := NewWebhook(client, testWebhookResources, fake.DefaultControllerNamespace)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if err := w.ensureNetworkPolicy("emojivoto", proxy); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		updated := proxy.DeepCopy()
+		updated.Ports = []corev1.ContainerPort{{Name: proxyInboundPortName, ContainerPort: 5143}, {Name: proxyMetricsPortName, ContainerPort: 4191}}
+		if err := w.ensureNetworkPolicy("emojivoto", updated); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		policy, err := client.NetworkingV1().NetworkPolicies("emojivoto").Get(proxyNetworkPolicyName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if port := ports(policy)[0]; port != 5143 {
+			t.Errorf("Expected the inbound port to be updated to 5143, got %d", port)
+		}
+	})
+
+	t.Run("leaves hand-written policies alone", func(t *testing.T) {
+		handWritten := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: proxyNetworkPolicyName, Namespace: "emojivoto"},
+		}
+		client := k8sfake.NewSimpleClientset(handWritten)
+		w, err := NewWebhook(client, testWebhookResources, fake.DefaultControllerNamespace)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if err := w.ensureNetworkPolicy("emojivoto", proxy); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		policy, err := client.NetworkingV1().NetworkPolicies("emojivoto").Get(proxyNetworkPolicyName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if len(ports(policy)) != 0 {
+			t.Errorf("Expected the hand-written policy to be unchanged, got %+v", policy.Spec)
+		}
+	})
+
+	t.Run("fails without the ports of the proxy", func(t *testing.T) {
+		w, err := NewWebhook(k8sfake.NewSimpleClientset(), testWebhookResources, fake.DefaultControllerNamespace)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		err = w.ensureNetworkPolicy("emojivoto", &corev1.Container{})
+		expected := "the proxy spec has no linkerd-proxy port"
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error mismatch\nExpected: %s\nActual: %v", expected, err)
+		}
+	})
+}
@@ -4,41 +4,60 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
+// The paths of the pod template fields are relative to the pod template of
+// the patched resource.
 const (
-	patchPathContainer         = "/spec/template/spec/containers/-"
-	patchPathInitContainerRoot = "/spec/template/spec/initContainers"
-	patchPathInitContainer     = "/spec/template/spec/initContainers/-"
-	patchPathVolumeRoot        = "/spec/template/spec/volumes"
-	patchPathVolume            = "/spec/template/spec/volumes/-"
+	patchPathContainer         = "/spec/containers/-"
+	patchPathShareProcessNS    = "/spec/shareProcessNamespace"
+	patchPathInitContainerRoot = "/spec/initContainers"
+	patchPathInitContainer     = "/spec/initContainers/-"
+	patchPathVolumeRoot        = "/spec/volumes"
+	patchPathVolume            = "/spec/volumes/-"
 	patchPathDeploymentLabels  = "/metadata/labels"
-	patchPathPodLabels         = "/spec/template/metadata/labels"
-	patchPathPodAnnotations    = "/spec/template/metadata/annotations"
+	patchPathPodLabels         = "/metadata/labels"
+	patchPathPodAnnotations    = "/metadata/annotations"
 )
 
 // Patch represents a RFC 6902 patch document.
 type Patch struct {
-	patchOps []*patchOp
+	templatePath string
+	patchOps     []*patchOp
 }
 
-// NewPatch returns a new instance of PodPatch.
+// NewPatch returns a new instance of PodPatch, for a deployment.
 func NewPatch() *Patch {
+	return NewTemplatePatch(templatePathDeployment)
+}
+
+// NewTemplatePatch returns a new instance of PodPatch, for a resource whose
+// pod template is at templatePath.
+func NewTemplatePatch(templatePath string) *Patch {
 	return &Patch{
-		patchOps: []*patchOp{},
+		templatePath: templatePath,
+		patchOps:     []*patchOp{},
 	}
 }
 
 func (p *Patch) addContainer(container *corev1.Container) {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathContainer,
+		Path:  p.templatePath + patchPathContainer,
 		Value: container,
 	})
 }
 
+func (p *Patch) setShareProcessNamespace() {
+	p.patchOps = append(p.patchOps, &patchOp{
+		Op:    "add",
+		Path:  p.templatePath + patchPathShareProcessNS,
+		Value: true,
+	})
+}
+
 func (p *Patch) addInitContainerRoot() {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathInitContainerRoot,
+		Path:  p.templatePath + patchPathInitContainerRoot,
 		Value: []*corev1.Container{},
 	})
 }
@@ -46,7 +65,7 @@ func (p *Patch) addInitContainerRoot() {
 func (p *Patch) addInitContainer(container *corev1.Container) {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathInitContainer,
+		Path:  p.templatePath + patchPathInitContainer,
 		Value: container,
 	})
 }
@@ -54,7 +73,7 @@ func (p *Patch) addInitContainer(container *corev1.Container) {
 func (p *Patch) addVolumeRoot() {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathVolumeRoot,
+		Path:  p.templatePath + patchPathVolumeRoot,
 		Value: []*corev1.Volume{},
 	})
 }
@@ -62,7 +81,7 @@ func (p *Patch) addVolumeRoot() {
 func (p *Patch) addVolume(volume *corev1.Volume) {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathVolume,
+		Path:  p.templatePath + patchPathVolume,
 		Value: volume,
 	})
 }
@@ -70,7 +89,7 @@ func (p *Patch) addVolume(volume *corev1.Volume) {
 func (p *Patch) addPodLabels(label map[string]string) {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathPodLabels,
+		Path:  p.templatePath + patchPathPodLabels,
 		Value: label,
 	})
 }
@@ -78,7 +97,7 @@ func (p *Patch) addPodLabels(label map[string]string) {
 func (p *Patch) addPodAnnotations(annotation map[string]string) {
 	p.patchOps = append(p.patchOps, &patchOp{
 		Op:    "add",
-		Path:  patchPathPodAnnotations,
+		Path:  p.templatePath + patchPathPodAnnotations,
 		Value: annotation,
 	})
 }
@@ -58,13 +58,13 @@ func TestPatch(t *testing.T) {
 
 	expected := NewPatch()
 	expected.patchOps = []*patchOp{
-		&patchOp{Op: "add", Path: patchPathContainer, Value: sidecar},
-		&patchOp{Op: "add", Path: patchPathInitContainerRoot, Value: []*v1.Container{}},
-		&patchOp{Op: "add", Path: patchPathInitContainer, Value: init},
-		&patchOp{Op: "add", Path: patchPathVolumeRoot, Value: []*v1.Volume{}},
-		&patchOp{Op: "add", Path: patchPathVolume, Value: trustAnchors},
-		&patchOp{Op: "add", Path: patchPathVolume, Value: secrets},
-		&patchOp{Op: "add", Path: patchPathPodLabels, Value: map[string]string{
+		&patchOp{Op: "add", Path: templatePathDeployment + patchPathContainer, Value: sidecar},
+		&patchOp{Op: "add", Path: templatePathDeployment + patchPathInitContainerRoot, Value: []*v1.Container{}},
+		&patchOp{Op: "add", Path: templatePathDeployment + patchPathInitContainer, Value: init},
+		&patchOp{Op: "add", Path: templatePathDeployment + patchPathVolumeRoot, Value: []*v1.Volume{}},
+		&patchOp{Op: "add", Path: templatePathDeployment + patchPathVolume, Value: trustAnchors},
+		&patchOp{Op: "add", Path: templatePathDeployment + patchPathVolume, Value: secrets},
+		&patchOp{Op: "add", Path: templatePathDeployment + patchPathPodLabels, Value: map[string]string{
 			k8sPkg.ControllerNSLabel:    controllerNamespace,
 			k8sPkg.ProxyAutoInjectLabel: k8sPkg.ProxyAutoInjectCompleted,
 		}},
@@ -72,7 +72,7 @@ func TestPatch(t *testing.T) {
 			k8sPkg.ControllerNSLabel:    controllerNamespace,
 			k8sPkg.ProxyAutoInjectLabel: k8sPkg.ProxyAutoInjectCompleted,
 		}},
-		&patchOp{Op: "add", Path: patchPathPodAnnotations, Value: map[string]string{k8sPkg.CreatedByAnnotation: createdBy}},
+		&patchOp{Op: "add", Path: templatePathDeployment + patchPathPodAnnotations, Value: map[string]string{k8sPkg.CreatedByAnnotation: createdBy}},
 	}
 
 	if !reflect.DeepEqual(actual, expected) {
@@ -0,0 +1,257 @@
+package injector
+
+import (
+	"fmt"
+	"strconv"
+
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// defaultSource is the source of the configuration values that come from the
+// proxy-injector's proxy and proxy-init specs, which are set at install time.
+const defaultSource = "proxy-injector default"
+
+// InjectionReport explains whether the pods of a deployment would be
+// injected, and with which proxy configuration.
+type InjectionReport struct {
+	Deployment string `json:"deployment"`
+	Namespace  string `json:"namespace"`
+	Injected   bool   `json:"injected"`
+
+	// Reason is why the pods wouldn't be injected, if they wouldn't.
+	Reason string `json:"reason,omitempty"`
+
+	// Config is the configuration of the injected proxy, if the pods would be
+	// injected.
+	Config []ConfigValue `json:"config,omitempty"`
+}
+
+// ConfigValue is a value of the proxy configuration, along with the default
+// or annotation that supplied it.
+type ConfigValue struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// Explain reports whether the pods of the deployment would be injected in the
+// given namespace, and with which proxy configuration, without injecting
+// them.
+func (w *Webhook) Explain(ns string, deployment *appsv1.Deployment) (*InjectionReport, error) {
+	if ns == "" {
+		ns = defaultNamespace
+	}
+	report := &InjectionReport{
+		Deployment: deployment.ObjectMeta.Name,
+		Namespace:  ns,
+	}
+
+	reason, err := w.namespaceIgnoreReason(ns)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		report.Reason = reason
+		return report, nil
+	}
+
+	workload := newDeploymentWorkload(deployment)
+	if reason := w.ignoreReason(workload); reason != "" {
+		report.Reason = reason
+		return report, nil
+	}
+
+	identity := &k8sPkg.TLSIdentity{
+		Name:                deployment.ObjectMeta.Name,
+		Kind:                "deployment",
+		Namespace:           ns,
+		ControllerNamespace: w.controllerNamespace,
+	}
+	proxy, proxyInit, err := w.containersSpec(identity)
+	if err != nil {
+		return nil, err
+	}
+	defaultInitArgs := append([]string{}, proxyInit.Args...)
+
+	config, sources, err := w.proxyConfigSources(ns, workload)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyProxyConfig(proxy, config); err != nil {
+		report.Reason = fmt.Sprintf("the proxy configuration is invalid: %s", err)
+		return report, nil
+	}
+	if err := applyProxyInitConfig(proxyInit, config); err != nil {
+		report.Reason = fmt.Sprintf("the proxy configuration is invalid: %s", err)
+		return report, nil
+	}
+
+	if err := w.checkConflicts(workload, proxy); err != nil {
+		report.Reason = err.Error()
+		return report, nil
+	}
+
+	enableDebug, err := debugSidecarEnabled(config)
+	if err != nil {
+		report.Reason = fmt.Sprintf("the proxy configuration is invalid: %s", err)
+		return report, nil
+	}
+	var debug *corev1.Container
+	if enableDebug {
+		if debug, err = w.debugContainerSpec(); err != nil {
+			return nil, err
+		}
+	}
+
+	report.Injected = true
+	report.Config = configValues(proxy, proxyInit, debug, defaultInitArgs, sources)
+	return report, nil
+}
+
+// namespaceIgnoreReason returns why the webhook's namespace selector excludes
+// the namespace, or an empty string if it doesn't. The selector is read from
+// the MutatingWebhookConfiguration, since it's chosen at install time; if it
+// doesn't exist yet, the default opt-out selector is assumed.
+func (w *Webhook) namespaceIgnoreReason(ns string) (string, error) {
+	var nsLabels labels.Set
+	namespace, err := w.client.CoreV1().Namespaces().Get(ns, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", err
+	}
+	if err == nil {
+		nsLabels = namespace.Labels
+	}
+
+	if nsLabels[k8sPkg.ProxyAutoInjectLabel] == k8sPkg.ProxyAutoInjectDisabled {
+		return fmt.Sprintf("the %s namespace has the %s=%s label", ns, k8sPkg.ProxyAutoInjectLabel, k8sPkg.ProxyAutoInjectDisabled), nil
+	}
+
+	mwc, err := w.client.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(k8sPkg.ProxyInjectorWebhookConfig, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	for _, webhook := range mwc.Webhooks {
+		if webhook.NamespaceSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(webhook.NamespaceSelector)
+		if err != nil {
+			return "", err
+		}
+		if !selector.Matches(nsLabels) {
+			return fmt.Sprintf("the %s namespace isn't selected by the webhook's namespace selector (%s)", ns, selector), nil
+		}
+	}
+	return "", nil
+}
+
+// configValues lists the configuration of the proxy, proxy-init and debug
+// containers, attributing each value to the annotation in sources that
+// supplied it, or to the default specs. The debug container is nil if it's
+// not enabled.
+func configValues(proxy, proxyInit, debug *corev1.Container, defaultInitArgs []string, sources map[string]string) []ConfigValue {
+	source := func(annotation string) string {
+		if s, ok := sources[annotation]; ok {
+			return s
+		}
+		return defaultSource
+	}
+
+	values := []ConfigValue{
+		{Name: "proxy image", Value: proxy.Image, Source: defaultSource},
+		{Name: "proxy-init image", Value: proxyInit.Image, Source: defaultSource},
+	}
+	if proxy.SecurityContext != nil && proxy.SecurityContext.RunAsUser != nil {
+		values = append(values, ConfigValue{Name: "proxy UID", Value: strconv.FormatInt(*proxy.SecurityContext.RunAsUser, 10), Source: defaultSource})
+	}
+
+	resources := []struct {
+		name       string
+		annotation string
+		list       corev1.ResourceList
+		resource   corev1.ResourceName
+	}{
+		{"proxy CPU request", k8sPkg.ProxyCPURequestAnnotation, proxy.Resources.Requests, corev1.ResourceCPU},
+		{"proxy memory request", k8sPkg.ProxyMemoryRequestAnnotation, proxy.Resources.Requests, corev1.ResourceMemory},
+		{"proxy CPU limit", k8sPkg.ProxyCPULimitAnnotation, proxy.Resources.Limits, corev1.ResourceCPU},
+		{"proxy memory limit", k8sPkg.ProxyMemoryLimitAnnotation, proxy.Resources.Limits, corev1.ResourceMemory},
+	}
+	for _, r := range resources {
+		value := "none"
+		if quantity, ok := r.list[r.resource]; ok {
+			value = quantity.String()
+		}
+		values = append(values, ConfigValue{Name: r.name, Value: value, Source: source(r.annotation)})
+	}
+
+	for _, env := range proxy.Env {
+		value := env.Value
+		if env.ValueFrom != nil && env.ValueFrom.FieldRef != nil {
+			value = fmt.Sprintf("<%s>", env.ValueFrom.FieldRef.FieldPath)
+		}
+
+		envSource := defaultSource
+		switch env.Name {
+		case envVarKeyProxyOpaqueInboundPorts, envVarKeyProxyOpaqueOutboundPorts:
+			envSource = source(k8sPkg.ProxyOpaquePortsAnnotation)
+		case envVarKeyProxyLog:
+			envSource = source(k8sPkg.ProxyLogLevelAnnotation)
+		}
+		values = append(values, ConfigValue{Name: env.Name, Value: value, Source: envSource})
+	}
+
+	skipPorts := []struct {
+		name       string
+		annotation string
+		flag       string
+	}{
+		{"skipped inbound ports", k8sPkg.ProxySkipInboundPortsAnnotation, "--inbound-ports-to-ignore"},
+		{"skipped outbound ports", k8sPkg.ProxySkipOutboundPortsAnnotation, "--outbound-ports-to-ignore"},
+	}
+	for _, skip := range skipPorts {
+		value := argValue(proxyInit.Args, skip.flag)
+		if value == "" {
+			value = "none"
+		}
+
+		// the annotations extend the default list of skipped ports
+		skipSource := defaultSource
+		if s, ok := sources[skip.annotation]; ok {
+			skipSource = s
+			if argValue(defaultInitArgs, skip.flag) != "" {
+				skipSource = fmt.Sprintf("%s, extended by %s", defaultSource, s)
+			}
+		}
+		values = append(values, ConfigValue{Name: skip.name, Value: value, Source: skipSource})
+	}
+
+	if debug == nil {
+		values = append(values, ConfigValue{Name: "debug sidecar", Value: "disabled", Source: source(k8sPkg.ProxyEnableDebugAnnotation)})
+	} else {
+		values = append(values,
+			ConfigValue{Name: "debug sidecar", Value: "enabled", Source: source(k8sPkg.ProxyEnableDebugAnnotation)},
+			ConfigValue{Name: "debug image", Value: debug.Image, Source: defaultSource},
+		)
+	}
+
+	return values
+}
+
+// argValue returns the value of a flag in args, or an empty string if the
+// flag isn't there.
+func argValue(args []string, flag string) string {
+	for i, arg := range args {
+		if arg == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
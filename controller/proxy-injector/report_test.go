@@ -0,0 +1,174 @@
+package injector
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/proxy-injector/fake"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	arv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestExplain(t *testing.T) {
+	namespace, err := factory.Namespace("namespace-kube-public.yaml")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	namespace.Annotations = map[string]string{
+		k8s.ProxyCPURequestAnnotation:       "100m",
+		k8s.ProxySkipInboundPortsAnnotation: "9000-9010",
+	}
+
+	w, err := NewWebhook(k8sfake.NewSimpleClientset(namespace), testWebhookResources, fake.DefaultControllerNamespace)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	t.Run("reports the proxy configuration and its sources", func(t *testing.T) {
+		deployment, err := factory.Deployment("deployment-inject-status-empty.yaml")
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		deployment.Spec.Template.Annotations[k8s.ProxyCPURequestAnnotation] = "200m"
+		deployment.Spec.Template.Annotations[k8s.ProxySkipOutboundPortsAnnotation] = "3306"
+		deployment.Spec.Template.Annotations[k8s.ProxyEnableDebugAnnotation] = "true"
+
+		report, err := w.Explain(namespace.Name, deployment)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if !report.Injected || report.Reason != "" {
+			t.Fatalf("Expected the deployment to be injected, got: %+v", report)
+		}
+
+		values := map[string]ConfigValue{}
+		for _, value := range report.Config {
+			values[value.Name] = value
+		}
+
+		expected := []ConfigValue{
+			{Name: "proxy image", Value: "gcr.io/linkerd-io/proxy:v18.8.4", Source: defaultSource},
+			{Name: "proxy CPU request", Value: "200m", Source: "annotation config.linkerd.io/proxy-cpu-request on the pod template"},
+			{Name: "proxy memory request", Value: "none", Source: defaultSource},
+			{Name: "skipped inbound ports", Value: "4190,4191,9000-9010", Source: "proxy-injector default, extended by annotation config.linkerd.io/skip-inbound-ports on namespace kube-public"},
+			{Name: "skipped outbound ports", Value: "3306", Source: "annotation config.linkerd.io/skip-outbound-ports on the pod template"},
+			{Name: "LINKERD2_PROXY_POD_NAMESPACE", Value: "<metadata.namespace>", Source: defaultSource},
+			{Name: "debug sidecar", Value: "enabled", Source: "annotation config.linkerd.io/enable-debug-sidecar on the pod template"},
+			{Name: "debug image", Value: "gcr.io/linkerd-io/debug:v18.8.4", Source: defaultSource},
+		}
+		for _, value := range expected {
+			if !reflect.DeepEqual(values[value.Name], value) {
+				t.Errorf("Config value mismatch\nExpected: %+v\nActual: %+v", value, values[value.Name])
+			}
+		}
+	})
+
+	t.Run("reports why the deployment isn't injected", func(t *testing.T) {
+		testCases := []struct {
+			filename string
+			reason   string
+		}{
+			{"deployment-inject-status-disabled.yaml", "the pod template has the linkerd.io/auto-inject=disabled label"},
+			{"deployment-inject-status-completed.yaml", "the pod template has the linkerd.io/auto-inject=completed label, so it's already injected"},
+			{"deployment-with-injected-proxy.yaml", "the pod template already has a proxy sidecar or init container"},
+			{"deployment-port-conflict.yaml", `deployment "nginx" conflicts with the proxy: `},
+		}
+
+		for _, testCase := range testCases {
+			deployment, err := factory.Deployment(testCase.filename)
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+
+			report, err := w.Explain(namespace.Name, deployment)
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+			if report.Injected {
+				t.Errorf("Expected %s not to be injected, got: %+v", testCase.filename, report)
+			}
+			if !strings.HasPrefix(report.Reason, testCase.reason) {
+				t.Errorf("Reason mismatch for %s\nExpected: %s\nActual: %s", testCase.filename, testCase.reason, report.Reason)
+			}
+		}
+	})
+
+	t.Run("reports namespaces excluded from injection", func(t *testing.T) {
+		disabled := namespace.DeepCopy()
+		disabled.Labels = map[string]string{k8s.ProxyAutoInjectLabel: k8s.ProxyAutoInjectDisabled}
+		w, err := NewWebhook(k8sfake.NewSimpleClientset(disabled), testWebhookResources, fake.DefaultControllerNamespace)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		deployment, err := factory.Deployment("deployment-inject-status-empty.yaml")
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		report, err := w.Explain(namespace.Name, deployment)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		expected := "the kube-public namespace has the linkerd.io/auto-inject=disabled label"
+		if report.Injected || report.Reason != expected {
+			t.Errorf("Expected reason %q, got: %+v", expected, report)
+		}
+	})
+
+	t.Run("reports namespaces that haven't opted in", func(t *testing.T) {
+		mwc := &arv1beta1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: k8s.ProxyInjectorWebhookConfig},
+			Webhooks: []arv1beta1.Webhook{{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{
+						Key:      k8s.ProxyAutoInjectLabel,
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   []string{k8s.ProxyAutoInjectEnabled},
+					}},
+				},
+			}},
+		}
+		w, err := NewWebhook(k8sfake.NewSimpleClientset(namespace, mwc), testWebhookResources, fake.DefaultControllerNamespace)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		deployment, err := factory.Deployment("deployment-inject-status-empty.yaml")
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		report, err := w.Explain(namespace.Name, deployment)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		expected := "the kube-public namespace isn't selected by the webhook's namespace selector (linkerd.io/auto-inject in (enabled))"
+		if report.Injected || report.Reason != expected {
+			t.Errorf("Expected reason %q, got: %+v", expected, report)
+		}
+	})
+
+	t.Run("reports invalid configurations", func(t *testing.T) {
+		deployment, err := factory.Deployment("deployment-inject-status-empty.yaml")
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		deployment.Spec.Template.Annotations[k8s.ProxyCPULimitAnnotation] = "-1"
+
+		report, err := w.Explain(namespace.Name, deployment)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		expected := `the proxy configuration is invalid: invalid value "-1" for the config.linkerd.io/proxy-cpu-limit annotation: must not be negative`
+		if report.Injected || report.Reason != expected {
+			t.Errorf("Expected reason %q, got: %+v", expected, report)
+		}
+	})
+}
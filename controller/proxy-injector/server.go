@@ -7,13 +7,24 @@ import (
 	"io/ioutil"
 	"net/http"
 
+	yaml "github.com/ghodss/yaml"
 	pem "github.com/linkerd/linkerd2/pkg/tls"
 	log "github.com/sirupsen/logrus"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+// reportPath is the path of the debug endpoint that explains whether a
+// deployment would be injected. It's served on the webhook's port, e.g.:
+//
+//	kubectl -n linkerd port-forward deploy/linkerd-proxy-injector 8443
+//	curl -k --data-binary @deployment.yml https://localhost:8443/debug/inject-report
+const reportPath = "/debug/inject-report"
+
 // WebhookServer is the webhook's HTTP server. It has an embedded webhook which
-// mutate all the requests.
+// mutate all the requests, except those of the ServiceProfiles, which it
+// validates on spValidatorPath.
 type WebhookServer struct {
 	*http.Server
 	*Webhook
@@ -37,11 +48,25 @@ func NewWebhookServer(client kubernetes.Interface, resources *WebhookResources,
 	}
 
 	ws := &WebhookServer{server, webhook}
-	ws.Handler = http.HandlerFunc(ws.serve)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", ws.serve)
+	mux.HandleFunc(spValidatorPath, ws.serveValidate)
+	mux.HandleFunc(reportPath, ws.serveReport)
+	ws.Handler = mux
 	return ws, nil
 }
 
 func (w *WebhookServer) serve(res http.ResponseWriter, req *http.Request) {
+	w.serveReview(res, req, w.Mutate)
+}
+
+func (w *WebhookServer) serveValidate(res http.ResponseWriter, req *http.Request) {
+	w.serveReview(res, req, w.ValidateProfile)
+}
+
+// serveReview responds with the admission review that review returns for the
+// admission review in the request body.
+func (w *WebhookServer) serveReview(res http.ResponseWriter, req *http.Request, review func([]byte) *admissionv1beta1.AdmissionReview) {
 	var (
 		data []byte
 		err  error
@@ -58,7 +83,7 @@ func (w *WebhookServer) serve(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	response := w.Mutate(data)
+	response := review(data)
 	responseJSON, err := json.Marshal(response)
 	if err != nil {
 		http.Error(res, err.Error(), http.StatusInternalServerError)
@@ -71,6 +96,51 @@ func (w *WebhookServer) serve(res http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// serveReport responds with the InjectionReport of the deployment posted in
+// the request body. The namespace of the deployment can be overridden with
+// the "namespace" query parameter.
+func (w *WebhookServer) serveReport(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(res, "the deployment must be posted in the request body", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var deployment appsv1.Deployment
+	if err := yaml.Unmarshal(data, &deployment); err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ns := req.URL.Query().Get("namespace")
+	if ns == "" {
+		ns = deployment.ObjectMeta.Namespace
+	}
+
+	report, err := w.Explain(ns, &deployment)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res.Header().Set("Content-Type", "application/json")
+	if _, err := res.Write(reportJSON); err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
 // Shutdown initiates a graceful shutdown of the underlying HTTP server.
 func (w *WebhookServer) Shutdown() error {
 	return w.Server.Shutdown(context.Background())
@@ -2,6 +2,7 @@ package injector
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -29,6 +30,7 @@ func init() {
 	testWebhookResources = &WebhookResources{
 		FileProxySpec:                fake.FileProxySpec,
 		FileProxyInitSpec:            fake.FileProxyInitSpec,
+		FileDebugSpec:                fake.FileDebugSpec,
 		FileTLSTrustAnchorVolumeSpec: fake.FileTLSTrustAnchorVolumeSpec,
 		FileTLSIdentityVolumeSpec:    fake.FileTLSIdentityVolumeSpec,
 	}
@@ -61,6 +63,48 @@ func TestServe(t *testing.T) {
 	})
 }
 
+func TestServeReport(t *testing.T) {
+	t.Run("with a deployment in the request body", func(t *testing.T) {
+		deployment, err := factory.HTTPRequestBody("deployment-inject-status-disabled.yaml")
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		request := httptest.NewRequest(http.MethodPost, reportPath+"?namespace=emojivoto", bytes.NewReader(deployment))
+
+		recorder := httptest.NewRecorder()
+		testServer.serveReport(recorder, request)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("HTTP response status mismatch. Expected: %d. Actual: %d", http.StatusOK, recorder.Code)
+		}
+
+		var report InjectionReport
+		if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		expected := InjectionReport{
+			Deployment: "nginx",
+			Namespace:  "emojivoto",
+			Reason:     "the pod template has the linkerd.io/auto-inject=disabled label",
+		}
+		if !reflect.DeepEqual(report, expected) {
+			t.Errorf("Report mismatch\nExpected: %+v\nActual: %+v", expected, report)
+		}
+	})
+
+	t.Run("with an invalid method", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, reportPath, nil)
+
+		recorder := httptest.NewRecorder()
+		testServer.serveReport(recorder, request)
+
+		if recorder.Code != http.StatusMethodNotAllowed {
+			t.Errorf("HTTP response status mismatch. Expected: %d. Actual: %d", http.StatusMethodNotAllowed, recorder.Code)
+		}
+	})
+}
+
 func TestShutdown(t *testing.T) {
 	server := &http.Server{Addr: ":0"}
 	testServer := WebhookServer{server, nil}
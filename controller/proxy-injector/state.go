@@ -0,0 +1,28 @@
+package injector
+
+import "sync/atomic"
+
+// webhookState is a snapshot of the admission reviews of a Webhook, which the
+// admin server reports on its /state endpoint.
+type webhookState struct {
+	InFlight int64 `json:"inFlight"`
+	Reviewed int64 `json:"reviewed"`
+	Injected int64 `json:"injected"`
+	Failed   int64 `json:"failed"`
+	TimedOut int64 `json:"timedOut"`
+}
+
+// State returns the number of admission reviews that the Webhook is mutating,
+// which is how many requests from the Kubernetes API server are waiting on it,
+// and the numbers of reviews that it mutated, injected, failed and admitted
+// without the proxy after the inject timeout, for the admin server's /state
+// endpoint.
+func (w *Webhook) State() interface{} {
+	return webhookState{
+		InFlight: atomic.LoadInt64(&w.inFlight),
+		Reviewed: atomic.LoadInt64(&w.reviewed),
+		Injected: atomic.LoadInt64(&w.injected),
+		Failed:   atomic.LoadInt64(&w.failed),
+		TimedOut: atomic.LoadInt64(&w.timedOut),
+	}
+}
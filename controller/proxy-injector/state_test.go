@@ -0,0 +1,31 @@
+package injector
+
+import (
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/proxy-injector/fake"
+)
+
+func TestWebhookState(t *testing.T) {
+	fakeClient, err := fake.NewClient("")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	w, err := NewWebhook(fakeClient, testWebhookResources, fake.DefaultControllerNamespace)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	for _, file := range []string{"inject-enabled-request.json", "inject-disabled-request.json"} {
+		data, err := factory.HTTPRequestBody(file)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		w.Mutate(data)
+	}
+
+	expected := webhookState{InFlight: 0, Reviewed: 2, Injected: 1, Failed: 0}
+	if state := w.State(); state != expected {
+		t.Fatalf("Expected state %+v, got %+v", expected, state)
+	}
+}
@@ -20,9 +20,28 @@ webhooks:
     apiGroups: ["apps", "extensions"]
     apiVersions: ["v1", "v1beta1", "v1beta2"]
     resources: ["deployments"]
+  - operations: [ "CREATE" ]
+    apiGroups: ["batch"]
+    apiVersions: ["v1"]
+    resources: ["jobs"]
+  - operations: [ "CREATE" ]
+    apiGroups: ["batch"]
+    apiVersions: ["v1beta1"]
+    resources: ["cronjobs"]
+  - operations: [ "CREATE" ]
+    apiGroups: ["argoproj.io"]
+    apiVersions: ["v1alpha1"]
+    resources: ["rollouts"]
+  failurePolicy: {{.FailurePolicy}}
   namespaceSelector:
     matchExpressions:
     - key: {{.ProxyAutoInjectLabel}}
+      {{- if .OptIn}}
+      operator: In
+      values:
+      - "{{.ProxyAutoInjectEnabled}}"
+      {{- else}}
       operator: NotIn
       values:
-      - "disabled"`
+      - "{{.ProxyAutoInjectDisabled}}"
+      {{- end}}`
@@ -0,0 +1,25 @@
+package tmpl
+
+// ValidatingWebhookConfigurationSpec provides a template for the
+// ValidatingWebhookConfiguration of the ServiceProfiles. Its failure policy is
+// Ignore, so that the ServiceProfiles can still be changed while the webhook
+// is down.
+var ValidatingWebhookConfigurationSpec = `
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: {{ .WebhookConfigName }}
+webhooks:
+- name: {{ .WebhookName }}
+  clientConfig:
+    service:
+      name: linkerd-proxy-injector
+      namespace: {{ .ControllerNamespace }}
+      path: "{{ .WebhookPath }}"
+    caBundle: {{ .CABundle }}
+  rules:
+  - operations: [ "CREATE", "UPDATE" ]
+    apiGroups: ["linkerd.io"]
+    apiVersions: ["v1alpha1"]
+    resources: ["serviceprofiles"]
+  failurePolicy: Ignore`
@@ -0,0 +1,56 @@
+package injector
+
+import (
+	"errors"
+	"fmt"
+
+	yaml "github.com/ghodss/yaml"
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	"github.com/linkerd/linkerd2/pkg/profiles"
+	log "github.com/sirupsen/logrus"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// spValidatorPath is the path on which the webhook server validates the
+// ServiceProfiles that are created or updated.
+const spValidatorPath = "/serviceprofiles"
+
+// ValidateProfile returns the admission review of the ServiceProfile in the
+// request, which denies the ServiceProfiles that fail profiles.Validate, such
+// as those with a retry budget that allows retry storms.
+func (w *Webhook) ValidateProfile(data []byte) *admissionv1beta1.AdmissionReview {
+	admissionReview, err := w.decode(data)
+	if err != nil || admissionReview.Request == nil {
+		if err == nil {
+			err = errors.New("no admission request")
+		}
+		log.Error("failed to decode data. Reason: ", err)
+		admissionReview.Response = &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result:  &metav1.Status{Message: err.Error()},
+		}
+		return admissionReview
+	}
+
+	request := admissionReview.Request
+	admissionReview.Response = &admissionv1beta1.AdmissionResponse{
+		UID:     request.UID,
+		Allowed: true,
+	}
+
+	var profile sp.ServiceProfile
+	if err := yaml.Unmarshal(request.Object.Raw, &profile); err != nil {
+		admissionReview.Response.Allowed = false
+		admissionReview.Response.Result = &metav1.Status{Message: err.Error()}
+		return admissionReview
+	}
+	if err := profiles.Validate(&profile.Spec); err != nil {
+		log.Infof("denying ServiceProfile %s/%s: %s", request.Namespace, profile.Name, err)
+		admissionReview.Response.Allowed = false
+		admissionReview.Response.Result = &metav1.Status{
+			Message: fmt.Sprintf("ServiceProfile \"%s\" is invalid: %s", profile.Name, err),
+		}
+	}
+	return admissionReview
+}
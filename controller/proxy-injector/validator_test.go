@@ -0,0 +1,65 @@
+package injector
+
+import (
+	"encoding/json"
+	"testing"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func profileReview(t *testing.T, spec sp.ServiceProfileSpec) []byte {
+	profile := sp.ServiceProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "books.emojivoto.svc.cluster.local", Namespace: "linkerd"},
+		Spec:       spec,
+	}
+	raw, err := json.Marshal(profile)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	review, err := json.Marshal(admissionv1beta1.AdmissionReview{
+		Request: &admissionv1beta1.AdmissionRequest{
+			UID:       "1",
+			Namespace: "linkerd",
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	})
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	return review
+}
+
+func TestValidateProfile(t *testing.T) {
+	route := &sp.RouteSpec{Name: "books", Condition: &sp.RequestMatch{PathRegex: "/books"}, IsRetryable: true}
+
+	t.Run("admits valid profiles", func(t *testing.T) {
+		review := webhook.ValidateProfile(profileReview(t, sp.ServiceProfileSpec{
+			Routes:      []*sp.RouteSpec{route},
+			RetryBudget: &sp.RetryBudget{RetryRatio: 0.2, MinRetriesPerSecond: 10, TTL: "10s"},
+		}))
+		if !review.Response.Allowed || review.Response.UID != "1" {
+			t.Fatalf("Expected the profile to be admitted, got %+v", review.Response)
+		}
+	})
+
+	t.Run("denies invalid profiles", func(t *testing.T) {
+		review := webhook.ValidateProfile(profileReview(t, sp.ServiceProfileSpec{
+			Routes:      []*sp.RouteSpec{route},
+			RetryBudget: &sp.RetryBudget{RetryRatio: 2},
+		}))
+		expected := "ServiceProfile \"books.emojivoto.svc.cluster.local\" is invalid: Invalid retry budget: The retry ratio must be between 0 and 1, got 2"
+		if review.Response.Allowed || review.Response.Result == nil || review.Response.Result.Message != expected {
+			t.Fatalf("Expected the profile to be denied with %q, got %+v", expected, review.Response)
+		}
+	})
+
+	t.Run("denies requests that can't be decoded", func(t *testing.T) {
+		review := webhook.ValidateProfile([]byte("{}"))
+		if review.Response.Allowed {
+			t.Fatal("Expected the request to be denied")
+		}
+	})
+}
@@ -1,37 +1,131 @@
 package injector
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	yaml "github.com/ghodss/yaml"
+	"github.com/linkerd/linkerd2/pkg/events"
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
 	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/util"
 	log "github.com/sirupsen/logrus"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sResource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/kubernetes"
+	k8sScheme "k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
 )
 
 const (
 	defaultNamespace                    = "default"
 	envVarKeyProxyTLSPodIdentity        = "LINKERD2_PROXY_TLS_POD_IDENTITY"
 	envVarKeyProxyTLSControllerIdentity = "LINKERD2_PROXY_TLS_CONTROLLER_IDENTITY"
+	envVarKeyProxyOpaqueInboundPorts    = "LINKERD2_PROXY_INBOUND_PORTS_DISABLE_PROTOCOL_DETECTION"
+	envVarKeyProxyOpaqueOutboundPorts   = "LINKERD2_PROXY_OUTBOUND_PORTS_DISABLE_PROTOCOL_DETECTION"
+	envVarKeyProxyLog                   = "LINKERD2_PROXY_LOG"
+
+	// eventReasonInjected, eventReasonInjectionSkipped and
+	// eventReasonInjectionFailed are the reasons of the events recorded on the
+	// workloads whose pods are injected, aren't injected, and whose admission
+	// requests are denied because their patch can't be computed.
+	eventReasonInjected         = "Injected"
+	eventReasonInjectionSkipped = "InjectionSkipped"
+	eventReasonInjectionFailed  = "InjectionFailed"
+
+	// eventReasonInjectionTimedOut is the reason of the events recorded on the
+	// workloads whose pods were admitted without the proxy, because their
+	// patch took longer than the inject timeout to compute.
+	eventReasonInjectionTimedOut = "InjectionTimedOut"
+
+	// DefaultInjectTimeout is how long the webhook computes the patch of an
+	// admission review for by default, well within the 30 seconds that the
+	// Kubernetes API server waits on webhooks.
+	DefaultInjectTimeout = 10 * time.Second
+
+	windowsIgnoreReason = "the pod template selects Windows nodes, which the proxy can't run on"
+
+	// denialSpikeThreshold and denialSpikeWindow are how many admission
+	// requests the webhook denies within the window before it posts a
+	// PolicyDenialSpike event.
+	denialSpikeThreshold = 10
+	denialSpikeWindow    = time.Minute
+
+	// maxOpaquePorts is how many ports the opaque ports annotation may expand
+	// to. The proxy only accepts lists of single ports, and a longer list
+	// would grow its environment towards the kernel's limit on the length of
+	// a single environment variable, past which the proxy can't start.
+	maxOpaquePorts = 1024
 )
 
+// The proxy of the Jobs and CronJobs that shut it down on completion runs with
+// `proxy-init await`, which an init container copies from the proxy-init
+// image into a volume mounted in the proxy container. The pod shares its
+// process namespace, so that await sees when the other containers are done.
+const (
+	awaitInitContainerName = "linkerd-await-init"
+	awaitVolumeName        = "linkerd-await"
+	awaitMountPath         = "/var/linkerd-io/await"
+	awaitBinary            = awaitMountPath + "/proxy-init"
+	proxyInitBinary        = "/usr/local/bin/proxy-init"
+
+	// proxyEntrypoint is the entrypoint of the proxy image, which await runs
+	// unless the proxy spec has a command of its own.
+	proxyEntrypoint = "/linkerd/linkerd2-proxy"
+
+	// awaitGracePeriod is how long the pod must have no other processes
+	// before the proxy is stopped, so that the containers that are still
+	// starting aren't left without a proxy. With the OnFailure restart
+	// policy, it also covers the kubelet's back-off of up to 5 minutes before
+	// a failed container is restarted.
+	awaitGracePeriod        = 10 * time.Second
+	awaitRestartGracePeriod = 5*time.Minute + awaitGracePeriod
+)
+
+// debugCommands are the commands of the debug sidecar, which don't keep the
+// proxy running.
+var debugCommands = []string{"tshark", "dumpcap"}
+
+// errInjectTimeout is returned when the patch of an admission review isn't
+// computed within the inject timeout.
+var errInjectTimeout = errors.New("timed out computing the patch")
+
 // Webhook is a Kubernetes mutating admission webhook that mutates pods admission
 // requests by injecting sidecar container spec into the pod spec during pod
 // creation.
 type Webhook struct {
+	// the numbers of admission reviews being and already mutated, which are
+	// only accessed atomically
+	inFlight int64
+	reviewed int64
+	injected int64
+	failed   int64
+	timedOut int64
+
+	client              kubernetes.Interface
 	deserializer        runtime.Decoder
 	controllerNamespace string
 	resources           *WebhookResources
+	recorder            record.EventRecorder
+	events              events.Sink
+	denials             *events.SpikeDetector
+	metrics             *metrics
+	networkPolicies     bool
+	injectTimeout       time.Duration
 }
 
 // NewWebhook returns a new instance of Webhook.
@@ -42,18 +136,60 @@ func NewWebhook(client kubernetes.Interface, resources *WebhookResources, contro
 	)
 
 	return &Webhook{
+		client:              client,
 		deserializer:        codecs.UniversalDeserializer(),
 		controllerNamespace: controllerNamespace,
 		resources:           resources,
+		recorder:            newEventRecorder(client),
+		events:              events.NopSink{},
+		denials:             events.NewSpikeDetector(denialSpikeThreshold, denialSpikeWindow),
+		metrics:             newMetrics(),
+		injectTimeout:       DefaultInjectTimeout,
 	}, nil
 }
 
+// SetEventSink replaces the sink that the webhook posts its ProxyInjected and
+// PolicyDenialSpike events to, which discards them by default. It must be
+// called before the webhook serves requests.
+func (w *Webhook) SetEventSink(sink events.Sink) {
+	w.events = sink
+}
+
+// SetInjectTimeout replaces how long the webhook computes the patch of an
+// admission review for, after which the pod is admitted without the proxy
+// rather than holding up its creation until the webhook's deadline, when the
+// API server would apply the webhook's failure policy. It must be called
+// before the webhook serves requests.
+func (w *Webhook) SetInjectTimeout(timeout time.Duration) {
+	w.injectTimeout = timeout
+}
+
+// newEventRecorder returns a recorder that writes the webhook's events to the
+// Kubernetes API.
+func newEventRecorder(client kubernetes.Interface) record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+	return broadcaster.NewRecorder(k8sScheme.Scheme, corev1.EventSource{Component: "linkerd-proxy-injector"})
+}
+
 // Mutate changes the given pod spec by injecting the proxy sidecar container
 // into the spec. The admission review object returns contains the original
 // request and the response with the mutated pod spec.
 func (w *Webhook) Mutate(data []byte) *admissionv1beta1.AdmissionReview {
+	atomic.AddInt64(&w.inFlight, 1)
+	defer atomic.AddInt64(&w.inFlight, -1)
+	atomic.AddInt64(&w.reviewed, 1)
+
+	start := time.Now()
+	result := reviewSkipped
+	defer func() {
+		w.metrics.reviewDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+	}()
+
 	admissionReview, err := w.decode(data)
 	if err != nil {
+		result = reviewFailed
+		atomic.AddInt64(&w.failed, 1)
 		log.Error("failed to decode data. Reason: ", err)
 		admissionReview.Response = &admissionv1beta1.AdmissionResponse{
 			UID:     admissionReview.Request.UID,
@@ -67,9 +203,24 @@ func (w *Webhook) Mutate(data []byte) *admissionv1beta1.AdmissionReview {
 	log.Infof("received admission review request %s", admissionReview.Request.UID)
 	log.Debugf("admission request: %+v", admissionReview.Request)
 
-	admissionResponse, err := w.inject(admissionReview.Request)
+	admissionResponse, err := w.injectWithTimeout(admissionReview.Request)
+	if err == errInjectTimeout {
+		result = reviewTimedOut
+		atomic.AddInt64(&w.timedOut, 1)
+		log.Warnf("admitting request %s without the proxy: %s after %s", admissionReview.Request.UID, err, w.injectTimeout)
+		w.recordTimedOut(admissionReview.Request)
+		admissionReview.Response = &admissionv1beta1.AdmissionResponse{
+			UID:     admissionReview.Request.UID,
+			Allowed: true,
+		}
+		return admissionReview
+	}
 	if err != nil {
+		result = reviewFailed
+		atomic.AddInt64(&w.failed, 1)
 		log.Error("failed to inject sidecar. Reason: ", err)
+		w.recordDenial(admissionReview.Request, err)
+		w.recordFailed(admissionReview.Request, err)
 		admissionReview.Response = &admissionv1beta1.AdmissionResponse{
 			UID:     admissionReview.Request.UID,
 			Allowed: false,
@@ -82,6 +233,8 @@ func (w *Webhook) Mutate(data []byte) *admissionv1beta1.AdmissionReview {
 	admissionReview.Response = admissionResponse
 
 	if len(admissionResponse.Patch) > 0 {
+		result = reviewInjected
+		atomic.AddInt64(&w.injected, 1)
 		log.Infof("patch generated: %s", admissionResponse.Patch)
 	}
 	log.Info("done")
@@ -95,21 +248,54 @@ func (w *Webhook) decode(data []byte) (*admissionv1beta1.AdmissionReview, error)
 	return &admissionReview, err
 }
 
-func (w *Webhook) inject(request *admissionv1beta1.AdmissionRequest) (*admissionv1beta1.AdmissionResponse, error) {
-	var deployment appsv1.Deployment
-	if err := yaml.Unmarshal(request.Object.Raw, &deployment); err != nil {
-		return nil, err
+// injectWithTimeout returns the response of inject, or errInjectTimeout if it
+// isn't computed within the inject timeout. The patch is then left to be
+// computed in the background, and discarded.
+func (w *Webhook) injectWithTimeout(request *admissionv1beta1.AdmissionRequest) (*admissionv1beta1.AdmissionResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.injectTimeout)
+	defer cancel()
+
+	type result struct {
+		response *admissionv1beta1.AdmissionResponse
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		response, err := w.inject(ctx, request)
+		done <- result{response, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.response, r.err
+	case <-ctx.Done():
+		return nil, errInjectTimeout
 	}
-	log.Infof("working on %s/%s %s..", request.Kind.Version, strings.ToLower(request.Kind.Kind), deployment.ObjectMeta.Name)
+}
 
-	ns := request.Namespace
-	if ns == "" {
-		ns = defaultNamespace
+// inject returns the response to the admission request, with the patch that
+// injects the proxy into its pod template. The patch is discarded once ctx is
+// done, so the side effects of injecting it are skipped then.
+func (w *Webhook) inject(ctx context.Context, request *admissionv1beta1.AdmissionRequest) (*admissionv1beta1.AdmissionResponse, error) {
+	workload, err := decodeWorkload(request.Kind.Kind, request.Object.Raw)
+	if err != nil {
+		return nil, err
 	}
+	log.Infof("working on %s/%s %s..", request.Kind.Version, strings.ToLower(request.Kind.Kind), workload.meta.Name)
+
+	ns := requestNamespace(request)
 	log.Infof("resource namespace: %s", ns)
 
-	if w.ignore(&deployment) {
-		log.Infof("ignoring deployment %s", deployment.ObjectMeta.Name)
+	if reason := w.ignoreReason(workload); reason != "" {
+		log.Infof("ignoring %s %s: %s", workload.kind, workload.meta.Name, reason)
+
+		// the pods of the other ignored workloads were excluded on purpose, but
+		// these would look like they should have been injected
+		eventType := corev1.EventTypeNormal
+		if reason == windowsIgnoreReason {
+			eventType = corev1.EventTypeWarning
+		}
+		w.recordSkipped(request, ns, workload, eventType, reason)
 		return &admissionv1beta1.AdmissionResponse{
 			UID:     request.UID,
 			Allowed: true,
@@ -117,7 +303,7 @@ func (w *Webhook) inject(request *admissionv1beta1.AdmissionRequest) (*admission
 	}
 
 	identity := &k8sPkg.TLSIdentity{
-		Name:                deployment.ObjectMeta.Name,
+		Name:                workload.meta.Name,
 		Kind:                strings.ToLower(request.Kind.Kind),
 		Namespace:           ns,
 		ControllerNamespace: w.controllerNamespace,
@@ -131,6 +317,44 @@ func (w *Webhook) inject(request *admissionv1beta1.AdmissionRequest) (*admission
 	log.Debugf("proxy container: %+v", proxy)
 	log.Debugf("init container: %+v", proxyInit)
 
+	config, err := w.proxyConfig(ns, workload)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyProxyConfig(proxy, config); err != nil {
+		return nil, err
+	}
+	if err := applyProxyInitConfig(proxyInit, config); err != nil {
+		return nil, err
+	}
+	log.Debugf("proxy config: %+v", config)
+
+	if err := w.checkConflicts(workload, proxy); err != nil {
+		return nil, err
+	}
+
+	enableDebug, err := debugSidecarEnabled(config)
+	if err != nil {
+		return nil, err
+	}
+	var debug *corev1.Container
+	if enableDebug {
+		if debug, err = w.debugContainerSpec(); err != nil {
+			return nil, err
+		}
+		log.Infof("debug image: %s", debug.Image)
+	}
+
+	shutdown, err := shutdownOnCompletion(config, workload)
+	if err != nil {
+		return nil, err
+	}
+	if shutdown {
+		proxy.Command = awaitCommand(workload.template, proxy, debug != nil)
+		proxy.VolumeMounts = append(proxy.VolumeMounts, awaitVolumeMount())
+		log.Infof("the proxy shuts down once the containers of %s %s exit", workload.kind, workload.meta.Name)
+	}
+
 	caBundle, tlsSecrets, err := w.volumesSpec(identity)
 	if err != nil {
 		return nil, err
@@ -138,38 +362,48 @@ func (w *Webhook) inject(request *admissionv1beta1.AdmissionRequest) (*admission
 	log.Debugf("ca bundle volume: %+v", caBundle)
 	log.Debugf("tls secrets volume: %+v", tlsSecrets)
 
-	patch := NewPatch()
+	patch := NewTemplatePatch(workload.templatePath)
 	patch.addContainer(proxy)
+	if debug != nil {
+		patch.addContainer(debug)
+	}
 
-	if len(deployment.Spec.Template.Spec.InitContainers) == 0 {
+	template := workload.template
+	if len(template.Spec.InitContainers) == 0 {
 		patch.addInitContainerRoot()
 	}
 	patch.addInitContainer(proxyInit)
 
-	if len(deployment.Spec.Template.Spec.Volumes) == 0 {
+	if len(template.Spec.Volumes) == 0 {
 		patch.addVolumeRoot()
 	}
 	patch.addVolume(caBundle)
 	patch.addVolume(tlsSecrets)
 
-	if deployment.Spec.Template.Labels == nil {
-		deployment.Spec.Template.Labels = map[string]string{}
+	if shutdown {
+		patch.addInitContainer(awaitInitContainerSpec(proxyInit))
+		patch.addVolume(awaitVolumeSpec())
+		patch.setShareProcessNamespace()
+	}
+
+	if template.Labels == nil {
+		template.Labels = map[string]string{}
 	}
 
-	deployment.Spec.Template.Labels[k8sPkg.ControllerNSLabel] = w.controllerNamespace
-	deployment.Spec.Template.Labels[k8sPkg.ProxyDeploymentLabel] = deployment.ObjectMeta.Name
-	patch.addPodLabels(deployment.Spec.Template.Labels)
+	template.Labels[k8sPkg.ControllerNSLabel] = w.controllerNamespace
+	template.Labels[workload.label] = workload.meta.Name
+	patch.addPodLabels(template.Labels)
 
-	if deployment.Labels == nil {
-		deployment.Labels = map[string]string{}
+	if workload.meta.Labels == nil {
+		workload.meta.Labels = map[string]string{}
 	}
 
-	deployment.Labels[k8sPkg.ControllerNSLabel] = w.controllerNamespace
-	deployment.Labels[k8sPkg.ProxyDeploymentLabel] = deployment.ObjectMeta.Name
-	patch.addDeploymentLabels(deployment.Labels)
+	workload.meta.Labels[k8sPkg.ControllerNSLabel] = w.controllerNamespace
+	workload.meta.Labels[workload.label] = workload.meta.Name
+	patch.addDeploymentLabels(workload.meta.Labels)
 
 	var (
-		image    = strings.Split(proxy.Image, ":")
+		image    = strings.Split(strings.SplitN(proxy.Image, "@", 2)[0], ":")
 		imageTag = ""
 	)
 
@@ -179,12 +413,12 @@ func (w *Webhook) inject(request *admissionv1beta1.AdmissionRequest) (*admission
 		imageTag = image[1]
 	}
 
-	if deployment.Spec.Template.Annotations == nil {
-		deployment.Spec.Template.Annotations = map[string]string{}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
 	}
-	deployment.Spec.Template.Annotations[k8sPkg.CreatedByAnnotation] = fmt.Sprintf("linkerd/proxy-injector %s", imageTag)
-	deployment.Spec.Template.Annotations[k8sPkg.ProxyVersionAnnotation] = imageTag
-	patch.addPodAnnotations(deployment.Spec.Template.Annotations)
+	template.Annotations[k8sPkg.CreatedByAnnotation] = fmt.Sprintf("linkerd/proxy-injector %s", imageTag)
+	template.Annotations[k8sPkg.ProxyVersionAnnotation] = imageTag
+	patch.addPodAnnotations(template.Annotations)
 
 	patchJSON, err := json.Marshal(patch.patchOps)
 	if err != nil {
@@ -199,20 +433,381 @@ func (w *Webhook) inject(request *admissionv1beta1.AdmissionRequest) (*admission
 		PatchType: &patchType,
 	}
 
+	// the pod was admitted without the proxy
+	if ctx.Err() != nil {
+		return nil, errInjectTimeout
+	}
+
+	// the pod is injected even if its proxy's traffic may be denied, as it
+	// would be without the policy
+	if w.networkPolicies {
+		if err := w.ensureNetworkPolicy(ns, proxy); err != nil {
+			log.Warnf("failed to ensure the %s network policy in namespace %s: %s", proxyNetworkPolicyName, ns, err)
+		}
+	}
+
+	w.recorder.Eventf(workloadRef(request, ns, workload), corev1.EventTypeNormal, eventReasonInjected,
+		"Linkerd injected the proxy %s into the pods of %s %s", imageTag, workload.kind, workload.meta.Name)
+	w.events.Post(events.Event{
+		Type:      events.ProxyInjected,
+		Namespace: ns,
+		Attributes: map[string]string{
+			"kind":         workload.kind,
+			"name":         workload.meta.Name,
+			"proxyVersion": imageTag,
+		},
+	})
+
 	return admissionResponse, nil
 }
 
-func (w *Webhook) ignore(deployment *appsv1.Deployment) bool {
-	labels := deployment.Spec.Template.ObjectMeta.GetLabels()
+// recordDenial counts the denied admission request, and posts a
+// PolicyDenialSpike event when too many requests were denied recently.
+func (w *Webhook) recordDenial(request *admissionv1beta1.AdmissionRequest, reason error) {
+	count, spike := w.denials.Observe(time.Now())
+	if !spike {
+		return
+	}
+
+	log.Warnf("denied %d admission requests in the last %s", count, denialSpikeWindow)
+	w.events.Post(events.Event{
+		Type:      events.PolicyDenialSpike,
+		Namespace: request.Namespace,
+		Attributes: map[string]string{
+			"denials":    strconv.Itoa(count),
+			"window":     denialSpikeWindow.String(),
+			"lastKind":   strings.ToLower(request.Kind.Kind),
+			"lastReason": reason.Error(),
+		},
+	})
+}
+
+func (w *Webhook) ignore(workload *workload) bool {
+	return w.ignoreReason(workload) != ""
+}
+
+// ignoreReason returns why the workload's pods must not be injected, or an
+// empty string if they can be.
+func (w *Webhook) ignoreReason(workload *workload) string {
+	labels := workload.template.ObjectMeta.GetLabels()
 	status, defined := labels[k8sPkg.ProxyAutoInjectLabel]
 	if defined {
 		switch status {
-		case k8sPkg.ProxyAutoInjectDisabled, k8sPkg.ProxyAutoInjectCompleted:
-			return true
+		case k8sPkg.ProxyAutoInjectDisabled:
+			return fmt.Sprintf("the pod template has the %s=%s label", k8sPkg.ProxyAutoInjectLabel, status)
+		case k8sPkg.ProxyAutoInjectCompleted:
+			return fmt.Sprintf("the pod template has the %s=%s label, so it's already injected", k8sPkg.ProxyAutoInjectLabel, status)
+		}
+	}
+
+	if healthcheck.HasExistingSidecars(&workload.template.Spec) {
+		return "the pod template already has a proxy sidecar or init container"
+	}
+
+	if k8sPkg.RequiresWindowsNodes(&workload.template.Spec) {
+		return windowsIgnoreReason
+	}
+	return ""
+}
+
+// recordSkipped records an event on the workload, explaining why its pods
+// weren't injected. The workload may not be created yet, in which case the
+// event is only matched to it by name.
+func (w *Webhook) recordSkipped(request *admissionv1beta1.AdmissionRequest, ns string, workload *workload, eventType, reason string) {
+	w.recorder.Eventf(workloadRef(request, ns, workload), eventType, eventReasonInjectionSkipped,
+		"Linkerd didn't inject the proxy into the pods of %s %s: %s", workload.kind, workload.meta.Name, reason)
+}
+
+// recordFailed records a warning event on the workload whose admission request
+// was denied, with the error that its patch couldn't be computed with.
+func (w *Webhook) recordFailed(request *admissionv1beta1.AdmissionRequest, reason error) {
+	workload, err := decodeWorkload(request.Kind.Kind, request.Object.Raw)
+	if err != nil {
+		return
+	}
+	w.recorder.Eventf(workloadRef(request, requestNamespace(request), workload), corev1.EventTypeWarning, eventReasonInjectionFailed,
+		"Linkerd failed to inject the proxy into the pods of %s %s: %s", workload.kind, workload.meta.Name, reason)
+}
+
+// recordTimedOut records a warning event on the workload whose pods were
+// admitted without the proxy because their patch took too long to compute.
+func (w *Webhook) recordTimedOut(request *admissionv1beta1.AdmissionRequest) {
+	workload, err := decodeWorkload(request.Kind.Kind, request.Object.Raw)
+	if err != nil {
+		return
+	}
+	w.recorder.Eventf(workloadRef(request, requestNamespace(request), workload), corev1.EventTypeWarning, eventReasonInjectionTimedOut,
+		"Linkerd didn't inject the proxy into the pods of %s %s: the proxy injector didn't compute the patch within %s", workload.kind, workload.meta.Name, w.injectTimeout)
+}
+
+func requestNamespace(request *admissionv1beta1.AdmissionRequest) string {
+	if request.Namespace == "" {
+		return defaultNamespace
+	}
+	return request.Namespace
+}
+
+func workloadRef(request *admissionv1beta1.AdmissionRequest, ns string, workload *workload) *corev1.ObjectReference {
+	ref := &corev1.ObjectReference{
+		APIVersion: fmt.Sprintf("%s/%s", request.Kind.Group, request.Kind.Version),
+		Kind:       request.Kind.Kind,
+		Namespace:  ns,
+		Name:       workload.meta.Name,
+		UID:        workload.meta.UID,
+	}
+	if request.Kind.Group == "" {
+		ref.APIVersion = request.Kind.Version
+	}
+	return ref
+}
+
+// proxyConfig returns the proxy configuration annotations that apply to the
+// workload's pods. The annotations of the workload's namespace are used as
+// defaults, and are overridden by the annotations of its pod template.
+func (w *Webhook) proxyConfig(ns string, workload *workload) (map[string]string, error) {
+	config, _, err := w.proxyConfigSources(ns, workload)
+	return config, err
+}
+
+// proxyConfigSources returns the same configuration as proxyConfig, along
+// with a description of where each annotation was found.
+func (w *Webhook) proxyConfigSources(ns string, workload *workload) (map[string]string, map[string]string, error) {
+	config := map[string]string{}
+	sources := map[string]string{}
+
+	namespace, err := w.client.CoreV1().Namespaces().Get(ns, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, nil, err
+	}
+	if err == nil {
+		for key, value := range namespace.Annotations {
+			if strings.HasPrefix(key, k8sPkg.ProxyConfigAnnotationsPrefix) {
+				config[key] = value
+				sources[key] = fmt.Sprintf("annotation %s on namespace %s", key, ns)
+			}
+		}
+	}
+
+	for key, value := range workload.template.Annotations {
+		if strings.HasPrefix(key, k8sPkg.ProxyConfigAnnotationsPrefix) {
+			config[key] = value
+			sources[key] = fmt.Sprintf("annotation %s on the pod template", key)
+		}
+	}
+
+	return config, sources, nil
+}
+
+// applyProxyConfig updates the proxy container spec with the given proxy
+// configuration annotations. The resource requests and limits are validated
+// against each other, so that an invalid configuration is rejected at
+// admission instead of failing when the pod is created.
+func applyProxyConfig(proxy *corev1.Container, config map[string]string) error {
+	resources := []struct {
+		annotation string
+		name       corev1.ResourceName
+		list       *corev1.ResourceList
+	}{
+		{k8sPkg.ProxyCPURequestAnnotation, corev1.ResourceCPU, &proxy.Resources.Requests},
+		{k8sPkg.ProxyMemoryRequestAnnotation, corev1.ResourceMemory, &proxy.Resources.Requests},
+		{k8sPkg.ProxyCPULimitAnnotation, corev1.ResourceCPU, &proxy.Resources.Limits},
+		{k8sPkg.ProxyMemoryLimitAnnotation, corev1.ResourceMemory, &proxy.Resources.Limits},
+	}
+
+	for _, resource := range resources {
+		value, ok := config[resource.annotation]
+		if !ok {
+			continue
+		}
+
+		quantity, err := k8sResource.ParseQuantity(value)
+		if err != nil {
+			return fmt.Errorf("invalid value \"%s\" for the %s annotation: %s", value, resource.annotation, err)
+		}
+		if quantity.Sign() < 0 {
+			return fmt.Errorf("invalid value \"%s\" for the %s annotation: must not be negative", value, resource.annotation)
+		}
+
+		if *resource.list == nil {
+			*resource.list = corev1.ResourceList{}
+		}
+		(*resource.list)[resource.name] = quantity
+	}
+
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		request, hasRequest := proxy.Resources.Requests[name]
+		limit, hasLimit := proxy.Resources.Limits[name]
+		if hasRequest && hasLimit && request.Cmp(limit) > 0 {
+			return fmt.Errorf("the proxy %s request (%s) must not exceed its limit (%s)", name, request.String(), limit.String())
+		}
+	}
+
+	if value, ok := config[k8sPkg.ProxyOpaquePortsAnnotation]; ok {
+		ranges, err := util.ParsePortRanges(value)
+		if err != nil {
+			return fmt.Errorf("invalid value \"%s\" for the %s annotation: %s", value, k8sPkg.ProxyOpaquePortsAnnotation, err)
+		}
+		count := 0
+		for _, pr := range ranges {
+			count += int(pr.UpperBound) - int(pr.LowerBound) + 1
+		}
+		if count > maxOpaquePorts {
+			return fmt.Errorf("invalid value \"%s\" for the %s annotation: it covers %d ports, more than the %d allowed", value, k8sPkg.ProxyOpaquePortsAnnotation, count, maxOpaquePorts)
+		}
+		if len(ranges) > 0 {
+			// the proxy only accepts lists of single ports. Both directions are
+			// configured, so that when the annotation is set on a namespace, the
+			// clients in that namespace don't detect the protocol either.
+			ports := []string{}
+			for _, pr := range ranges {
+				for port := int(pr.LowerBound); port <= int(pr.UpperBound); port++ {
+					ports = append(ports, strconv.Itoa(port))
+				}
+			}
+			proxy.Env = setEnvVar(proxy.Env, envVarKeyProxyOpaqueInboundPorts, strings.Join(ports, ","))
+			proxy.Env = setEnvVar(proxy.Env, envVarKeyProxyOpaqueOutboundPorts, strings.Join(ports, ","))
+		}
+	}
+
+	if value, ok := config[k8sPkg.ProxyLogLevelAnnotation]; ok {
+		if !validProxyLogLevel(value) {
+			return fmt.Errorf("invalid value \"%s\" for the %s annotation: must be a comma-separated list of levels or target=level directives, with levels among: %s", value, k8sPkg.ProxyLogLevelAnnotation, strings.Join(proxyLogLevels, ", "))
+		}
+		proxy.Env = setEnvVar(proxy.Env, envVarKeyProxyLog, value)
+	}
+
+	return nil
+}
+
+// proxyLogLevels are the log levels of the proxy.
+var proxyLogLevels = []string{"trace", "debug", "info", "warn", "error", "off"}
+
+// validProxyLogLevel returns true if level is a list of the log directives of
+// the proxy, such as "warn,linkerd2_proxy=debug", so that a typo doesn't make
+// the proxy fail to start.
+func validProxyLogLevel(level string) bool {
+	for _, directive := range strings.Split(level, ",") {
+		parts := strings.SplitN(directive, "=", 2)
+		if len(parts) == 2 && parts[0] == "" {
+			return false
+		}
+		valid := false
+		for _, l := range proxyLogLevels {
+			if parts[len(parts)-1] == l {
+				valid = true
+			}
+		}
+		if !valid {
+			return false
 		}
 	}
+	return true
+}
 
-	return healthcheck.HasExistingSidecars(&deployment.Spec.Template.Spec)
+// setEnvVar sets the value of the env var with the given name, adding it if
+// it isn't in env yet.
+func setEnvVar(env []corev1.EnvVar, name, value string) []corev1.EnvVar {
+	for i := range env {
+		if env[i].Name == name {
+			env[i].Value = value
+			env[i].ValueFrom = nil
+			return env
+		}
+	}
+	return append(env, corev1.EnvVar{Name: name, Value: value})
+}
+
+// applyProxyInitConfig updates the proxy-init container spec with the given
+// proxy configuration annotations.
+func applyProxyInitConfig(proxyInit *corev1.Container, config map[string]string) error {
+	skipPorts := []struct {
+		annotation string
+		flag       string
+	}{
+		{k8sPkg.ProxySkipInboundPortsAnnotation, "--inbound-ports-to-ignore"},
+		{k8sPkg.ProxySkipOutboundPortsAnnotation, "--outbound-ports-to-ignore"},
+	}
+
+	for _, skip := range skipPorts {
+		value, ok := config[skip.annotation]
+		if !ok {
+			continue
+		}
+
+		ranges, err := util.ParsePortRanges(value)
+		if err != nil {
+			return fmt.Errorf("invalid value \"%s\" for the %s annotation: %s", value, skip.annotation, err)
+		}
+		if len(ranges) == 0 {
+			continue
+		}
+
+		ports := make([]string, len(ranges))
+		for i, pr := range ranges {
+			ports[i] = pr.String()
+		}
+		proxyInit.Args = addToArgList(proxyInit.Args, skip.flag, strings.Join(ports, ","))
+	}
+
+	return nil
+}
+
+// addToArgList appends the given comma-separated values to the value of a
+// flag in args, adding the flag if it isn't there yet.
+func addToArgList(args []string, flag, values string) []string {
+	for i, arg := range args {
+		if arg == flag && i+1 < len(args) {
+			args[i+1] = args[i+1] + "," + values
+			return args
+		}
+	}
+	return append(args, flag, values)
+}
+
+// checkConflicts returns an error if the workload's containers use the ports
+// or the user ID of the proxy, unless the pod template has the
+// ProxyIgnoreConflictsAnnotation.
+func (w *Webhook) checkConflicts(workload *workload, proxy *corev1.Container) error {
+	if workload.template.Annotations[k8sPkg.ProxyIgnoreConflictsAnnotation] == "true" {
+		return nil
+	}
+
+	var proxyUID int64 = -1
+	if proxy.SecurityContext != nil && proxy.SecurityContext.RunAsUser != nil {
+		proxyUID = *proxy.SecurityContext.RunAsUser
+	}
+
+	conflicts := healthcheck.ProxyConflicts(&workload.template.Spec, proxyPorts(proxy), proxyUID)
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s \"%s\" conflicts with the proxy: %s (set the \"%s: true\" annotation on the pod template to inject it anyway)",
+		workload.kind, workload.meta.Name, strings.Join(conflicts, "; "), k8sPkg.ProxyIgnoreConflictsAnnotation)
+}
+
+// proxyPorts returns the ports the proxy listens on, as configured by its
+// listener environment variables.
+func proxyPorts(proxy *corev1.Container) []int32 {
+	ports := []int32{}
+	for _, env := range proxy.Env {
+		if !strings.HasPrefix(env.Name, "LINKERD2_PROXY_") || !strings.HasSuffix(env.Name, "_LISTENER") {
+			continue
+		}
+
+		_, port, err := net.SplitHostPort(strings.TrimPrefix(env.Value, "tcp://"))
+		if err != nil {
+			log.Warnf("failed to parse %s: %s", env.Name, err)
+			continue
+		}
+		p, err := strconv.ParseInt(port, 10, 32)
+		if err != nil {
+			log.Warnf("failed to parse %s: %s", env.Name, err)
+			continue
+		}
+		ports = append(ports, int32(p))
+	}
+	return ports
 }
 
 func (w *Webhook) containersSpec(identity *k8sPkg.TLSIdentity) (*corev1.Container, *corev1.Container, error) {
@@ -247,6 +842,96 @@ func (w *Webhook) containersSpec(identity *k8sPkg.TLSIdentity) (*corev1.Containe
 	return &proxy, &proxyInit, nil
 }
 
+// debugSidecarEnabled returns true if the proxy configuration annotations
+// enable the debug sidecar.
+func debugSidecarEnabled(config map[string]string) (bool, error) {
+	value, ok := config[k8sPkg.ProxyEnableDebugAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value \"%s\" for the %s annotation: must be true or false", value, k8sPkg.ProxyEnableDebugAnnotation)
+	}
+	return enabled, nil
+}
+
+// shutdownOnCompletion returns true if the proxy configuration annotations
+// make the proxy shut down once the workload's containers exit, which only
+// applies to the workloads whose pods run to completion.
+func shutdownOnCompletion(config map[string]string, workload *workload) (bool, error) {
+	value, ok := config[k8sPkg.ProxyShutdownOnCompletionAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value \"%s\" for the %s annotation: must be true or false", value, k8sPkg.ProxyShutdownOnCompletionAnnotation)
+	}
+	return enabled && workload.completes(), nil
+}
+
+// awaitCommand returns the command of the proxy, run by `proxy-init await`
+// so that it's stopped once the pod's other containers exit. The containers
+// themselves are left as is, so their images' entrypoints still apply. The
+// proxy's args are appended to the command by the kubelet.
+func awaitCommand(template *corev1.PodTemplateSpec, proxy *corev1.Container, debug bool) []string {
+	gracePeriod := awaitGracePeriod
+	if template.Spec.RestartPolicy == corev1.RestartPolicyOnFailure {
+		gracePeriod = awaitRestartGracePeriod
+	}
+
+	command := []string{awaitBinary, "await", "--grace-period", gracePeriod.String()}
+	if debug {
+		command = append(command, "--ignore-command", strings.Join(debugCommands, ","))
+	}
+	command = append(command, "--")
+	if len(proxy.Command) == 0 {
+		return append(command, proxyEntrypoint)
+	}
+	return append(command, proxy.Command...)
+}
+
+// awaitInitContainerSpec returns the init container that copies the
+// proxy-init binary, which the proxy is run with, from the proxy-init image
+// into the await volume.
+func awaitInitContainerSpec(proxyInit *corev1.Container) *corev1.Container {
+	return &corev1.Container{
+		Name:                     awaitInitContainerName,
+		Image:                    proxyInit.Image,
+		ImagePullPolicy:          proxyInit.ImagePullPolicy,
+		Command:                  []string{"cp", proxyInitBinary, awaitBinary},
+		VolumeMounts:             []corev1.VolumeMount{awaitVolumeMount()},
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+}
+
+func awaitVolumeSpec() *corev1.Volume {
+	return &corev1.Volume{
+		Name:         awaitVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}
+}
+
+func awaitVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{Name: awaitVolumeName, MountPath: awaitMountPath}
+}
+
+func (w *Webhook) debugContainerSpec() (*corev1.Container, error) {
+	debugSpec, err := ioutil.ReadFile(w.resources.FileDebugSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var debug corev1.Container
+	if err := yaml.Unmarshal(debugSpec, &debug); err != nil {
+		return nil, err
+	}
+	return &debug, nil
+}
+
 func (w *Webhook) volumesSpec(identity *k8sPkg.TLSIdentity) (*corev1.Volume, *corev1.Volume, error) {
 	trustAnchorVolumeSpec, err := ioutil.ReadFile(w.resources.FileTLSTrustAnchorVolumeSpec)
 	if err != nil {
@@ -280,6 +965,9 @@ type WebhookResources struct {
 	// FileProxyInitSpec is the path to the proxy-init spec.
 	FileProxyInitSpec string
 
+	// FileDebugSpec is the path to the debug container spec.
+	FileDebugSpec string
+
 	// FileTLSTrustAnchorVolumeSpec is the path to the trust anchor volume spec.
 	FileTLSTrustAnchorVolumeSpec string
 
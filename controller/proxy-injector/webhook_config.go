@@ -3,6 +3,7 @@ package injector
 import (
 	"bytes"
 	"encoding/base64"
+	"fmt"
 	"io/ioutil"
 	"text/template"
 
@@ -16,36 +17,59 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
-// WebhookConfig creates the MutatingWebhookConfiguration of the webhook.
+// spValidatorWebhookName is the name of the webhook of the
+// ValidatingWebhookConfiguration of the ServiceProfiles.
+const spValidatorWebhookName = "linkerd-sp-validator.linkerd.io"
+
+// WebhookConfig creates the MutatingWebhookConfiguration of the webhook, and
+// the ValidatingWebhookConfiguration of the ServiceProfiles.
 type WebhookConfig struct {
 	controllerNamespace string
 	webhookServiceName  string
 	trustAnchor         []byte
+	failurePolicy       arv1beta1.FailurePolicyType
+	namespaceSelector   string
 	configTemplate      *template.Template
+	validatorTemplate   *template.Template
 	k8sAPI              kubernetes.Interface
 }
 
-// NewWebhookConfig returns a new instance of initiator.
-func NewWebhookConfig(client kubernetes.Interface, controllerNamespace, webhookServiceName, trustAnchorFile string) (*WebhookConfig, error) {
+// NewWebhookConfig returns a new instance of initiator. The failure policy is
+// either "Ignore" or "Fail", and the namespace selector is either
+// k8sPkg.ProxyInjectorNamespaceSelectorOptOut or
+// k8sPkg.ProxyInjectorNamespaceSelectorOptIn.
+func NewWebhookConfig(client kubernetes.Interface, controllerNamespace, webhookServiceName, trustAnchorFile, failurePolicy, namespaceSelector string) (*WebhookConfig, error) {
+	policy := arv1beta1.FailurePolicyType(failurePolicy)
+	if policy != arv1beta1.Ignore && policy != arv1beta1.Fail {
+		return nil, fmt.Errorf("invalid failure policy \"%s\": must be %s or %s", failurePolicy, arv1beta1.Ignore, arv1beta1.Fail)
+	}
+	if namespaceSelector != k8sPkg.ProxyInjectorNamespaceSelectorOptOut && namespaceSelector != k8sPkg.ProxyInjectorNamespaceSelectorOptIn {
+		return nil, fmt.Errorf("invalid namespace selector \"%s\": must be %s or %s", namespaceSelector, k8sPkg.ProxyInjectorNamespaceSelectorOptOut, k8sPkg.ProxyInjectorNamespaceSelectorOptIn)
+	}
+
 	trustAnchor, err := ioutil.ReadFile(trustAnchorFile)
 	if err != nil {
 		return nil, err
 	}
 
 	t := template.New(k8sPkg.ProxyInjectorWebhookConfig)
+	v := template.New(k8sPkg.SPValidatorWebhookConfig)
 
 	return &WebhookConfig{
 		controllerNamespace: controllerNamespace,
 		webhookServiceName:  webhookServiceName,
 		trustAnchor:         trustAnchor,
+		failurePolicy:       policy,
+		namespaceSelector:   namespaceSelector,
 		configTemplate:      template.Must(t.Parse(tmpl.MutatingWebhookConfigurationSpec)),
+		validatorTemplate:   template.Must(v.Parse(tmpl.ValidatingWebhookConfigurationSpec)),
 		k8sAPI:              client,
 	}, nil
 }
 
 // CreateOrUpdate sends the request to either create or update the
-// MutatingWebhookConfiguration resource. During an update, only the CA bundle
-// is changed.
+// MutatingWebhookConfiguration resource. During an update, only the CA bundle,
+// the failure policy and the namespace selector are changed.
 func (w *WebhookConfig) CreateOrUpdate() (*arv1beta1.MutatingWebhookConfiguration, error) {
 	mwc, exist, err := w.exist()
 	if err != nil {
@@ -75,20 +99,54 @@ func (w *WebhookConfig) exist() (*arv1beta1.MutatingWebhookConfiguration, bool,
 }
 
 func (w *WebhookConfig) create() (*arv1beta1.MutatingWebhookConfiguration, error) {
+	config, err := w.render()
+	if err != nil {
+		return nil, err
+	}
+
+	return w.k8sAPI.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Create(config)
+}
+
+func (w *WebhookConfig) update(mwc *arv1beta1.MutatingWebhookConfiguration) (*arv1beta1.MutatingWebhookConfiguration, error) {
+	config, err := w.render()
+	if err != nil {
+		return nil, err
+	}
+	webhook := config.Webhooks[0]
+
+	for i := 0; i < len(mwc.Webhooks); i++ {
+		mwc.Webhooks[i].ClientConfig.CABundle = w.trustAnchor
+		mwc.Webhooks[i].FailurePolicy = webhook.FailurePolicy
+		mwc.Webhooks[i].NamespaceSelector = webhook.NamespaceSelector
+	}
+
+	return w.k8sAPI.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Update(mwc)
+}
+
+// render returns the MutatingWebhookConfiguration of the webhook.
+func (w *WebhookConfig) render() (*arv1beta1.MutatingWebhookConfiguration, error) {
 	var (
 		buf  = &bytes.Buffer{}
 		spec = struct {
-			WebhookConfigName    string
-			WebhookServiceName   string
-			ControllerNamespace  string
-			CABundle             string
-			ProxyAutoInjectLabel string
+			WebhookConfigName       string
+			WebhookServiceName      string
+			ControllerNamespace     string
+			CABundle                string
+			FailurePolicy           arv1beta1.FailurePolicyType
+			OptIn                   bool
+			ProxyAutoInjectLabel    string
+			ProxyAutoInjectEnabled  string
+			ProxyAutoInjectDisabled string
 		}{
-			WebhookConfigName:    k8sPkg.ProxyInjectorWebhookConfig,
-			WebhookServiceName:   w.webhookServiceName,
-			ControllerNamespace:  w.controllerNamespace,
-			CABundle:             base64.StdEncoding.EncodeToString(w.trustAnchor),
-			ProxyAutoInjectLabel: k8sPkg.ProxyAutoInjectLabel,
+			WebhookConfigName:       k8sPkg.ProxyInjectorWebhookConfig,
+			WebhookServiceName:      w.webhookServiceName,
+			ControllerNamespace:     w.controllerNamespace,
+			CABundle:                base64.StdEncoding.EncodeToString(w.trustAnchor),
+			FailurePolicy:           w.failurePolicy,
+			OptIn:                   w.namespaceSelector == k8sPkg.ProxyInjectorNamespaceSelectorOptIn,
+			ProxyAutoInjectLabel:    k8sPkg.ProxyAutoInjectLabel,
+			ProxyAutoInjectEnabled:  k8sPkg.ProxyAutoInjectEnabled,
+			ProxyAutoInjectDisabled: k8sPkg.ProxyAutoInjectDisabled,
 		}
 	)
 	if err := w.configTemplate.Execute(buf, spec); err != nil {
@@ -100,14 +158,60 @@ func (w *WebhookConfig) create() (*arv1beta1.MutatingWebhookConfiguration, error
 		log.Infof("failed to unmarshal mutating webhook configuration: %s\n%s\n", err, buf.String())
 		return nil, err
 	}
+	return &config, nil
+}
 
-	return w.k8sAPI.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Create(&config)
+// CreateOrUpdateValidator sends the request to either create or update the
+// ValidatingWebhookConfiguration of the ServiceProfiles. During an update,
+// only the CA bundle is changed.
+func (w *WebhookConfig) CreateOrUpdateValidator() (*arv1beta1.ValidatingWebhookConfiguration, error) {
+	config, err := w.renderValidator()
+	if err != nil {
+		return nil, err
+	}
+
+	client := w.k8sAPI.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations()
+	vwc, err := client.Get(k8sPkg.SPValidatorWebhookConfig, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return client.Create(config)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(vwc.Webhooks); i++ {
+		vwc.Webhooks[i].ClientConfig.CABundle = w.trustAnchor
+	}
+	return client.Update(vwc)
 }
 
-func (w *WebhookConfig) update(mwc *arv1beta1.MutatingWebhookConfiguration) (*arv1beta1.MutatingWebhookConfiguration, error) {
-	for i := 0; i < len(mwc.Webhooks); i++ {
-		mwc.Webhooks[i].ClientConfig.CABundle = w.trustAnchor
+// renderValidator returns the ValidatingWebhookConfiguration of the
+// ServiceProfiles.
+func (w *WebhookConfig) renderValidator() (*arv1beta1.ValidatingWebhookConfiguration, error) {
+	var (
+		buf  = &bytes.Buffer{}
+		spec = struct {
+			WebhookConfigName   string
+			WebhookName         string
+			WebhookPath         string
+			ControllerNamespace string
+			CABundle            string
+		}{
+			WebhookConfigName:   k8sPkg.SPValidatorWebhookConfig,
+			WebhookName:         spValidatorWebhookName,
+			WebhookPath:         spValidatorPath,
+			ControllerNamespace: w.controllerNamespace,
+			CABundle:            base64.StdEncoding.EncodeToString(w.trustAnchor),
+		}
+	)
+	if err := w.validatorTemplate.Execute(buf, spec); err != nil {
+		return nil, err
 	}
 
-	return w.k8sAPI.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Update(mwc)
+	var config arv1beta1.ValidatingWebhookConfiguration
+	if err := yaml.Unmarshal(buf.Bytes(), &config); err != nil {
+		log.Infof("failed to unmarshal validating webhook configuration: %s\n%s\n", err, buf.String())
+		return nil, err
+	}
+	return &config, nil
 }
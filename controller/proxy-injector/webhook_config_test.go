@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/linkerd/linkerd2/controller/proxy-injector/fake"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	arv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 )
 
 func TestCreateOrUpdate(t *testing.T) {
@@ -28,7 +30,7 @@ func TestCreateOrUpdate(t *testing.T) {
 	}
 	defer os.Remove(trustAnchorsPath)
 
-	webhookConfig, err := NewWebhookConfig(client, namespace, webhookServiceName, trustAnchorsPath)
+	webhookConfig, err := NewWebhookConfig(client, namespace, webhookServiceName, trustAnchorsPath, "Ignore", k8s.ProxyInjectorNamespaceSelectorOptOut)
 	if err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
@@ -60,4 +62,77 @@ func TestCreateOrUpdate(t *testing.T) {
 	if _, err := webhookConfig.CreateOrUpdate(); err != nil {
 		t.Fatal("Unexpected error: ", err)
 	}
+
+	// update the mutating webhook configuration to be strict, and opt-in
+	webhookConfig, err = NewWebhookConfig(client, namespace, webhookServiceName, trustAnchorsPath, "Fail", k8s.ProxyInjectorNamespaceSelectorOptIn)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	mwc, err := webhookConfig.CreateOrUpdate()
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	for _, webhook := range mwc.Webhooks {
+		if webhook.FailurePolicy == nil || *webhook.FailurePolicy != arv1beta1.Fail {
+			t.Errorf("Expected failure policy %s, got %v", arv1beta1.Fail, webhook.FailurePolicy)
+		}
+		expressions := webhook.NamespaceSelector.MatchExpressions
+		if len(expressions) != 1 || expressions[0].Operator != "In" || len(expressions[0].Values) != 1 || expressions[0].Values[0] != k8s.ProxyAutoInjectEnabled {
+			t.Errorf("Expected an opt-in namespace selector, got %+v", webhook.NamespaceSelector)
+		}
+	}
+}
+
+func TestNewWebhookConfigValidation(t *testing.T) {
+	client, err := fake.NewClient("")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	if _, err := NewWebhookConfig(client, fake.DefaultControllerNamespace, "test.linkerd.io", "", "Sometimes", k8s.ProxyInjectorNamespaceSelectorOptOut); err == nil {
+		t.Error("Expected an error for an invalid failure policy")
+	}
+	if _, err := NewWebhookConfig(client, fake.DefaultControllerNamespace, "test.linkerd.io", "", "Fail", "opt-sideways"); err == nil {
+		t.Error("Expected an error for an invalid namespace selector")
+	}
+}
+
+func TestCreateOrUpdateValidator(t *testing.T) {
+	factory := fake.NewFactory()
+	client, err := fake.NewClient("")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	trustAnchorsPath, err := factory.CATrustAnchors()
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	defer os.Remove(trustAnchorsPath)
+
+	webhookConfig, err := NewWebhookConfig(client, fake.DefaultControllerNamespace, "test.linkerd.io", trustAnchorsPath, "Ignore", k8s.ProxyInjectorNamespaceSelectorOptOut)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	// create, then update the validating webhook configuration
+	for i := 0; i < 2; i++ {
+		vwc, err := webhookConfig.CreateOrUpdateValidator()
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if vwc.Name != k8s.SPValidatorWebhookConfig || len(vwc.Webhooks) != 1 {
+			t.Fatalf("Unexpected validating webhook configuration: %+v", vwc)
+		}
+		validator := vwc.Webhooks[0]
+		if validator.ClientConfig.Service == nil || validator.ClientConfig.Service.Path == nil || *validator.ClientConfig.Service.Path != spValidatorPath {
+			t.Errorf("Expected the webhook to be served on %s, got %+v", spValidatorPath, validator.ClientConfig)
+		}
+		if len(validator.Rules) != 1 || validator.Rules[0].Resources[0] != "serviceprofiles" {
+			t.Errorf("Expected the webhook to validate the ServiceProfiles, got %+v", validator.Rules)
+		}
+		if validator.FailurePolicy == nil || *validator.FailurePolicy != arv1beta1.Ignore {
+			t.Errorf("Expected failure policy %s, got %v", arv1beta1.Ignore, validator.FailurePolicy)
+		}
+	}
 }
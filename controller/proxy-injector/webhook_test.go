@@ -1,15 +1,27 @@
 package injector
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ghodss/yaml"
 	"github.com/linkerd/linkerd2/controller/proxy-injector/fake"
+	"github.com/linkerd/linkerd2/pkg/events"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	log "github.com/sirupsen/logrus"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
 )
 
 var (
@@ -61,6 +73,302 @@ func TestMutate(t *testing.T) {
 	}
 }
 
+func TestDebugSidecar(t *testing.T) {
+	testCases := []struct {
+		title      string
+		annotation string
+		containers []string
+	}{
+		{title: "not annotated", containers: []string{k8s.ProxyContainerName}},
+		{title: "disabled", annotation: "false", containers: []string{k8s.ProxyContainerName}},
+		{title: "enabled", annotation: "true", containers: []string{k8s.ProxyContainerName, k8s.DebugContainerName}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.title, func(t *testing.T) {
+			request, err := debugSidecarRequest(testCase.annotation)
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+
+			response, err := webhook.inject(context.Background(), request)
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+
+			var patchOps []struct {
+				Path  string
+				Value json.RawMessage
+			}
+			if err := json.Unmarshal(response.Patch, &patchOps); err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+
+			containers := []string{}
+			for _, op := range patchOps {
+				if op.Path != templatePathDeployment+patchPathContainer {
+					continue
+				}
+				var container corev1.Container
+				if err := json.Unmarshal(op.Value, &container); err != nil {
+					t.Fatal("Unexpected error: ", err)
+				}
+				containers = append(containers, container.Name)
+			}
+			if !reflect.DeepEqual(containers, testCase.containers) {
+				t.Errorf("Containers mismatch\nExpected: %v\nActual: %v", testCase.containers, containers)
+			}
+		})
+	}
+
+	t.Run("invalid annotation", func(t *testing.T) {
+		request, err := debugSidecarRequest("yes please")
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		expected := `invalid value "yes please" for the config.linkerd.io/enable-debug-sidecar annotation: must be true or false`
+		if _, err := webhook.inject(context.Background(), request); err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q, got: %v", expected, err)
+		}
+	})
+}
+
+func debugSidecarRequest(annotation string) (*admissionv1beta1.AdmissionRequest, error) {
+	deployment, err := factory.Deployment("deployment-inject-status-empty.yaml")
+	if err != nil {
+		return nil, err
+	}
+	if annotation != "" {
+		deployment.Spec.Template.Annotations[k8s.ProxyEnableDebugAnnotation] = annotation
+	}
+
+	raw, err := json.Marshal(deployment)
+	if err != nil {
+		return nil, err
+	}
+	return &admissionv1beta1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		Namespace: fake.DefaultNamespace,
+		Object:    runtime.RawExtension{Raw: raw},
+	}, nil
+}
+
+func TestInjectWorkloads(t *testing.T) {
+	var testCases = []struct {
+		filename     string
+		kind         metav1.GroupVersionKind
+		templatePath string
+		label        string
+	}{
+		{
+			filename:     "job.yaml",
+			kind:         metav1.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"},
+			templatePath: "/spec/template",
+			label:        k8s.ProxyJobLabel,
+		},
+		{
+			filename:     "cronjob.yaml",
+			kind:         metav1.GroupVersionKind{Group: "batch", Version: "v1beta1", Kind: "CronJob"},
+			templatePath: "/spec/jobTemplate/spec/template",
+			label:        k8s.ProxyCronJobLabel,
+		},
+		{
+			filename:     "rollout.yaml",
+			kind:         metav1.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"},
+			templatePath: "/spec/template",
+			label:        k8s.ProxyRolloutLabel,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.kind.Kind, func(t *testing.T) {
+			body, err := factory.HTTPRequestBody(testCase.filename)
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+			raw, err := yaml.YAMLToJSON(body)
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+
+			response, err := webhook.inject(context.Background(), &admissionv1beta1.AdmissionRequest{
+				Kind:      testCase.kind,
+				Namespace: fake.DefaultNamespace,
+				Object:    runtime.RawExtension{Raw: raw},
+			})
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+
+			var patchOps []struct {
+				Path  string
+				Value json.RawMessage
+			}
+			if err := json.Unmarshal(response.Patch, &patchOps); err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+
+			paths := map[string]json.RawMessage{}
+			for _, op := range patchOps {
+				paths[op.Path] = op.Value
+			}
+			for _, path := range []string{patchPathContainer, patchPathInitContainer, patchPathVolume, patchPathPodAnnotations} {
+				if _, ok := paths[testCase.templatePath+path]; !ok {
+					t.Errorf("Expected a patch of %s%s, got: %s", testCase.templatePath, path, response.Patch)
+				}
+			}
+
+			for _, path := range []string{testCase.templatePath + patchPathPodLabels, patchPathDeploymentLabels} {
+				var labels map[string]string
+				if err := json.Unmarshal(paths[path], &labels); err != nil {
+					t.Fatalf("Unexpected error for %s: %s", path, err)
+				}
+				if labels[testCase.label] != "nginx" {
+					t.Errorf("Expected %s to have the %s=nginx label, got: %v", path, testCase.label, labels)
+				}
+			}
+		})
+	}
+}
+
+func TestShutdownOnCompletion(t *testing.T) {
+	injectJob := func(annotation string, update func(*corev1.PodTemplateSpec)) (map[string][]json.RawMessage, error) {
+		body, err := factory.HTTPRequestBody("job.yaml")
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		raw, err := yaml.YAMLToJSON(body)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		workload, err := decodeWorkload("Job", raw)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		workload.template.Annotations = map[string]string{k8s.ProxyShutdownOnCompletionAnnotation: annotation}
+		if update != nil {
+			update(workload.template)
+		}
+		if raw, err = json.Marshal(map[string]interface{}{
+			"metadata": workload.meta,
+			"spec":     map[string]interface{}{"template": workload.template},
+		}); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		response, err := webhook.inject(context.Background(), &admissionv1beta1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"},
+			Namespace: fake.DefaultNamespace,
+			Object:    runtime.RawExtension{Raw: raw},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var patchOps []struct {
+			Path  string
+			Value json.RawMessage
+		}
+		if err := json.Unmarshal(response.Patch, &patchOps); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		paths := map[string][]json.RawMessage{}
+		for _, op := range patchOps {
+			paths[op.Path] = append(paths[op.Path], op.Value)
+		}
+		return paths, nil
+	}
+
+	t.Run("runs the proxy with await", func(t *testing.T) {
+		paths, err := injectJob("true", nil)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		var proxy corev1.Container
+		if err := json.Unmarshal(paths[templatePathJob+patchPathContainer][0], &proxy); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		expected := []string{awaitBinary, "await", "--grace-period", "10s", "--", proxyEntrypoint}
+		if !reflect.DeepEqual(proxy.Command, expected) {
+			t.Errorf("Command mismatch\nExpected: %v\nActual: %v", expected, proxy.Command)
+		}
+		mounts := proxy.VolumeMounts
+		if len(mounts) == 0 || mounts[len(mounts)-1].Name != awaitVolumeName {
+			t.Errorf("Expected the await volume to be mounted in the proxy, got: %v", mounts)
+		}
+
+		initContainers := paths[templatePathJob+patchPathInitContainer]
+		var awaitInit corev1.Container
+		if err := json.Unmarshal(initContainers[len(initContainers)-1], &awaitInit); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if awaitInit.Name != awaitInitContainerName {
+			t.Errorf("Expected the %s init container, got: %s", awaitInitContainerName, awaitInit.Name)
+		}
+
+		if shared := paths[templatePathJob+patchPathShareProcessNS]; len(shared) != 1 || string(shared[0]) != "true" {
+			t.Errorf("Expected the pod to share its process namespace, got: %s", shared)
+		}
+		for path := range paths {
+			if strings.HasPrefix(path, templatePathJob+"/spec/containers/0") || strings.HasPrefix(path, templatePathJob+"/spec/containers/1") {
+				t.Errorf("Expected the containers of the job to be left as is, got a patch of %s", path)
+			}
+		}
+	})
+
+	t.Run("supports the containers without a command", func(t *testing.T) {
+		paths, err := injectJob("true", func(template *corev1.PodTemplateSpec) {
+			template.Spec.Containers[0].Command = nil
+		})
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if _, ok := paths[templatePathJob+patchPathShareProcessNS]; !ok {
+			t.Errorf("Expected the pod to share its process namespace, got: %v", paths)
+		}
+	})
+
+	t.Run("waits for the failed containers to be restarted", func(t *testing.T) {
+		paths, err := injectJob("true", func(template *corev1.PodTemplateSpec) {
+			template.Spec.RestartPolicy = corev1.RestartPolicyOnFailure
+		})
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		var proxy corev1.Container
+		if err := json.Unmarshal(paths[templatePathJob+patchPathContainer][0], &proxy); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if proxy.Command[3] != "5m10s" {
+			t.Errorf("Expected a grace period covering the restarts, got: %v", proxy.Command)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		paths, err := injectJob("false", nil)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		if _, ok := paths[templatePathJob+patchPathShareProcessNS]; ok {
+			t.Errorf("Expected the pod to keep its process namespace, got: %v", paths)
+		}
+	})
+
+	t.Run("ignored on the deployments", func(t *testing.T) {
+		deployment, err := factory.Deployment("deployment-inject-status-empty.yaml")
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		config := map[string]string{k8s.ProxyShutdownOnCompletionAnnotation: "true"}
+		if shutdown, err := shutdownOnCompletion(config, newDeploymentWorkload(deployment)); err != nil || shutdown {
+			t.Errorf("Expected the annotation to be ignored, got %t, %v", shutdown, err)
+		}
+	})
+}
+
 func TestIgnore(t *testing.T) {
 	t.Run("by checking labels", func(t *testing.T) {
 		var testCases = []struct {
@@ -80,7 +388,7 @@ func TestIgnore(t *testing.T) {
 					t.Fatal("Unexpected error: ", err)
 				}
 
-				if actual := webhook.ignore(deployment); actual != testCase.expected {
+				if actual := webhook.ignore(newDeploymentWorkload(deployment)); actual != testCase.expected {
 					t.Errorf("Boolean mismatch. Expected: %t. Actual: %t", testCase.expected, actual)
 				}
 			})
@@ -93,10 +401,401 @@ func TestIgnore(t *testing.T) {
 			t.Fatal("Unexpected error: ", err)
 		}
 
-		if !webhook.ignore(deployment) {
+		if !webhook.ignore(newDeploymentWorkload(deployment)) {
 			t.Errorf("Expected deployment with injected proxy to be ignored")
 		}
 	})
+
+	t.Run("by checking node selector", func(t *testing.T) {
+		deployment, err := factory.Deployment("deployment-inject-status-empty.yaml")
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		deployment.Spec.Template.Spec.NodeSelector = map[string]string{k8s.NodeOSLabel: k8s.WindowsOS}
+
+		if reason := webhook.ignoreReason(newDeploymentWorkload(deployment)); reason != windowsIgnoreReason {
+			t.Errorf("Expected deployment on Windows nodes to be ignored, got reason: %q", reason)
+		}
+	})
+}
+
+func TestInjectRecordsWindowsEvent(t *testing.T) {
+	w, err := NewWebhook(k8sfake.NewSimpleClientset(), testWebhookResources, fake.DefaultControllerNamespace)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	recorder := record.NewFakeRecorder(1)
+	w.recorder = recorder
+
+	deployment, err := factory.Deployment("deployment-inject-status-empty.yaml")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	deployment.Spec.Template.Spec.NodeSelector = map[string]string{k8s.NodeOSLabel: k8s.WindowsOS}
+	raw, err := json.Marshal(deployment)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	response, err := w.inject(context.Background(), &admissionv1beta1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		Namespace: fake.DefaultNamespace,
+		Object:    runtime.RawExtension{Raw: raw},
+	})
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	if !response.Allowed || len(response.Patch) != 0 {
+		t.Fatalf("Expected the deployment to be admitted without a patch, got: %+v", response)
+	}
+
+	select {
+	case event := <-recorder.Events:
+		expected := "Warning InjectionSkipped Linkerd didn't inject the proxy into the pods of deployment nginx: " + windowsIgnoreReason
+		if event != expected {
+			t.Errorf("Event mismatch\nExpected: %s\nActual: %s", expected, event)
+		}
+	default:
+		t.Error("Expected an event to be recorded")
+	}
+}
+
+func TestMutateRecordsEvents(t *testing.T) {
+	failed, err := debugSidecarRequest("yes please")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	failedReview, err := json.Marshal(admissionv1beta1.AdmissionReview{Request: failed})
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	testCases := []struct {
+		title    string
+		request  func() ([]byte, error)
+		expected string
+	}{
+		{
+			title:    "injected",
+			request:  func() ([]byte, error) { return factory.HTTPRequestBody("inject-enabled-request.json") },
+			expected: "Normal Injected Linkerd injected the proxy v18.8.4 into the pods of deployment nginx",
+		},
+		{
+			title:    "skipped",
+			request:  func() ([]byte, error) { return factory.HTTPRequestBody("inject-disabled-request.json") },
+			expected: "Normal InjectionSkipped Linkerd didn't inject the proxy into the pods of deployment nginx: the pod template has the linkerd.io/auto-inject=disabled label",
+		},
+		{
+			title:    "failed",
+			request:  func() ([]byte, error) { return failedReview, nil },
+			expected: `Warning InjectionFailed Linkerd failed to inject the proxy into the pods of deployment nginx: invalid value "yes please" for the config.linkerd.io/enable-debug-sidecar annotation: must be true or false`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			w, err := NewWebhook(k8sfake.NewSimpleClientset(), testWebhookResources, fake.DefaultControllerNamespace)
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+			recorder := record.NewFakeRecorder(1)
+			w.recorder = recorder
+
+			data, err := tc.request()
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+			w.Mutate(data)
+
+			select {
+			case event := <-recorder.Events:
+				if event != tc.expected {
+					t.Errorf("Event mismatch\nExpected: %s\nActual: %s", tc.expected, event)
+				}
+			default:
+				t.Error("Expected an event to be recorded")
+			}
+		})
+	}
+}
+
+func TestMutateTimeout(t *testing.T) {
+	// the namespace of the pod is read while computing the patch, which blocks
+	// until the test is done
+	block := make(chan struct{})
+	defer close(block)
+	client := k8sfake.NewSimpleClientset()
+	client.PrependReactor("get", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		<-block
+		return false, nil, nil
+	})
+
+	w, err := NewWebhook(client, testWebhookResources, fake.DefaultControllerNamespace)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	recorder := record.NewFakeRecorder(1)
+	w.recorder = recorder
+	w.SetInjectTimeout(10 * time.Millisecond)
+
+	data, err := factory.HTTPRequestBody("inject-enabled-request.json")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	review := w.Mutate(data)
+	if !review.Response.Allowed || len(review.Response.Patch) != 0 {
+		t.Fatalf("Expected the pod to be admitted without a patch, got: %+v", review.Response)
+	}
+
+	select {
+	case event := <-recorder.Events:
+		expected := "Warning InjectionTimedOut Linkerd didn't inject the proxy into the pods of deployment nginx: the proxy injector didn't compute the patch within 10ms"
+		if event != expected {
+			t.Errorf("Event mismatch\nExpected: %s\nActual: %s", expected, event)
+		}
+	default:
+		t.Error("Expected an event to be recorded")
+	}
+
+	expected := webhookState{InFlight: 0, Reviewed: 1, Injected: 0, Failed: 0, TimedOut: 1}
+	if state := w.State(); state != expected {
+		t.Errorf("Expected state %+v, got %+v", expected, state)
+	}
+
+	if count := reviewCount(t, w, reviewTimedOut); count != 1 {
+		t.Errorf("Expected 1 %s admission review, got %d", reviewTimedOut, count)
+	}
+}
+
+func TestCheckConflicts(t *testing.T) {
+	identity := &k8s.TLSIdentity{
+		Name:                "nginx",
+		Kind:                "deployment",
+		Namespace:           fake.DefaultNamespace,
+		ControllerNamespace: fake.DefaultControllerNamespace,
+	}
+
+	proxy, _, err := webhook.containersSpec(identity)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	var testCases = []struct {
+		filename    string
+		expectedErr string
+	}{
+		{filename: "deployment-inject-status-empty.yaml"},
+		{filename: "deployment-ignore-conflicts.yaml"},
+		{
+			filename: "deployment-port-conflict.yaml",
+			expectedErr: `deployment "nginx" conflicts with the proxy: container "nginx" uses port 4191, which is reserved for the proxy; ` +
+				`container "nginx" runs as user ID 2102, which is reserved for the proxy ` +
+				`(set the "linkerd.io/inject-ignore-conflicts: true" annotation on the pod template to inject it anyway)`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.filename, func(t *testing.T) {
+			deployment, err := factory.Deployment(testCase.filename)
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+
+			err = webhook.checkConflicts(newDeploymentWorkload(deployment), proxy)
+			if testCase.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err)
+				}
+			} else if err == nil || err.Error() != testCase.expectedErr {
+				t.Errorf("Error mismatch\nExpected: %s\nActual: %v", testCase.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestProxyConfig(t *testing.T) {
+	namespace, err := factory.Namespace("namespace-kube-public.yaml")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	namespace.Annotations = map[string]string{
+		k8s.ProxyCPURequestAnnotation:    "100m",
+		k8s.ProxyMemoryRequestAnnotation: "64Mi",
+		"other.io/annotation":            "ignored",
+	}
+
+	w, err := NewWebhook(k8sfake.NewSimpleClientset(namespace), testWebhookResources, fake.DefaultControllerNamespace)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	deployment, err := factory.Deployment("deployment-inject-status-empty.yaml")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	deployment.Spec.Template.Annotations[k8s.ProxyMemoryRequestAnnotation] = "128Mi"
+
+	t.Run("merges namespace and pod annotations", func(t *testing.T) {
+		config, err := w.proxyConfig(namespace.Name, newDeploymentWorkload(deployment))
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		expected := map[string]string{
+			k8s.ProxyCPURequestAnnotation:    "100m",
+			k8s.ProxyMemoryRequestAnnotation: "128Mi",
+		}
+		if !reflect.DeepEqual(expected, config) {
+			t.Errorf("Config mismatch\nExpected: %+v\nActual: %+v", expected, config)
+		}
+
+		proxy := &corev1.Container{}
+		if err := applyProxyConfig(proxy, config); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		if cpu := proxy.Resources.Requests[corev1.ResourceCPU]; cpu.String() != "100m" {
+			t.Errorf("Expected CPU request 100m, got %s", cpu.String())
+		}
+		if memory := proxy.Resources.Requests[corev1.ResourceMemory]; memory.String() != "128Mi" {
+			t.Errorf("Expected memory request 128Mi, got %s", memory.String())
+		}
+	})
+
+	t.Run("uses pod annotations only when the namespace doesn't exist", func(t *testing.T) {
+		config, err := w.proxyConfig("missing", newDeploymentWorkload(deployment))
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		expected := map[string]string{k8s.ProxyMemoryRequestAnnotation: "128Mi"}
+		if !reflect.DeepEqual(expected, config) {
+			t.Errorf("Config mismatch\nExpected: %+v\nActual: %+v", expected, config)
+		}
+	})
+
+	t.Run("sets resource limits", func(t *testing.T) {
+		proxy := &corev1.Container{}
+		err := applyProxyConfig(proxy, map[string]string{
+			k8s.ProxyCPURequestAnnotation:  "100m",
+			k8s.ProxyCPULimitAnnotation:    "1",
+			k8s.ProxyMemoryLimitAnnotation: "256Mi",
+		})
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		if cpu := proxy.Resources.Limits[corev1.ResourceCPU]; cpu.String() != "1" {
+			t.Errorf("Expected CPU limit 1, got %s", cpu.String())
+		}
+		if memory := proxy.Resources.Limits[corev1.ResourceMemory]; memory.String() != "256Mi" {
+			t.Errorf("Expected memory limit 256Mi, got %s", memory.String())
+		}
+		if _, ok := proxy.Resources.Requests[corev1.ResourceMemory]; ok {
+			t.Errorf("Expected no memory request, got %+v", proxy.Resources.Requests)
+		}
+	})
+
+	t.Run("rejects requests that exceed limits", func(t *testing.T) {
+		err := applyProxyConfig(&corev1.Container{}, map[string]string{
+			k8s.ProxyMemoryRequestAnnotation: "1Gi",
+			k8s.ProxyMemoryLimitAnnotation:   "512Mi",
+		})
+		expected := "the proxy memory request (1Gi) must not exceed its limit (512Mi)"
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error mismatch\nExpected: %s\nActual: %v", expected, err)
+		}
+	})
+
+	t.Run("rejects negative quantities", func(t *testing.T) {
+		err := applyProxyConfig(&corev1.Container{}, map[string]string{k8s.ProxyCPULimitAnnotation: "-1"})
+		expected := `invalid value "-1" for the config.linkerd.io/proxy-cpu-limit annotation: must not be negative`
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error mismatch\nExpected: %s\nActual: %v", expected, err)
+		}
+	})
+
+	t.Run("rejects invalid quantities", func(t *testing.T) {
+		err := applyProxyConfig(&corev1.Container{}, map[string]string{k8s.ProxyCPURequestAnnotation: "lots"})
+		expected := `invalid value "lots" for the config.linkerd.io/proxy-cpu-request annotation: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'`
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error mismatch\nExpected: %s\nActual: %v", expected, err)
+		}
+	})
+}
+
+func TestProxyOpaquePortsConfig(t *testing.T) {
+	t.Run("disables protocol detection on opaque ports", func(t *testing.T) {
+		proxy := &corev1.Container{
+			Env: []corev1.EnvVar{{Name: "LINKERD2_PROXY_LOG", Value: "info"}},
+		}
+		err := applyProxyConfig(proxy, map[string]string{k8s.ProxyOpaquePortsAnnotation: "25,3306-3308"})
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		expected := []corev1.EnvVar{
+			{Name: "LINKERD2_PROXY_LOG", Value: "info"},
+			{Name: envVarKeyProxyOpaqueInboundPorts, Value: "25,3306,3307,3308"},
+			{Name: envVarKeyProxyOpaqueOutboundPorts, Value: "25,3306,3307,3308"},
+		}
+		if !reflect.DeepEqual(expected, proxy.Env) {
+			t.Errorf("Env mismatch\nExpected: %+v\nActual: %+v", expected, proxy.Env)
+		}
+	})
+
+	t.Run("rejects invalid ports", func(t *testing.T) {
+		err := applyProxyConfig(&corev1.Container{}, map[string]string{k8s.ProxyOpaquePortsAnnotation: "mysql"})
+		expected := `invalid value "mysql" for the config.linkerd.io/opaque-ports annotation: invalid port range "mysql": "mysql" is not a valid TCP port number`
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error mismatch\nExpected: %s\nActual: %v", expected, err)
+		}
+	})
+
+	t.Run("rejects ranges that cover too many ports", func(t *testing.T) {
+		proxy := &corev1.Container{}
+		err := applyProxyConfig(proxy, map[string]string{k8s.ProxyOpaquePortsAnnotation: "25,1-65535"})
+		expected := `invalid value "25,1-65535" for the config.linkerd.io/opaque-ports annotation: it covers 65536 ports, more than the 1024 allowed`
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error mismatch\nExpected: %s\nActual: %v", expected, err)
+		}
+		if len(proxy.Env) != 0 {
+			t.Errorf("Expected no environment variables, got %+v", proxy.Env)
+		}
+	})
+}
+
+func TestProxyInitConfig(t *testing.T) {
+	t.Run("adds skipped port ranges to the init container args", func(t *testing.T) {
+		proxyInit := &corev1.Container{
+			Args: []string{"--incoming-proxy-port", "4143", "--inbound-ports-to-ignore", "4190,4191"},
+		}
+		err := applyProxyInitConfig(proxyInit, map[string]string{
+			k8s.ProxySkipInboundPortsAnnotation:  "7070",
+			k8s.ProxySkipOutboundPortsAnnotation: "25, 4000-4100",
+		})
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		expected := []string{
+			"--incoming-proxy-port", "4143",
+			"--inbound-ports-to-ignore", "4190,4191,7070",
+			"--outbound-ports-to-ignore", "25,4000-4100",
+		}
+		if !reflect.DeepEqual(expected, proxyInit.Args) {
+			t.Errorf("Args mismatch\nExpected: %+v\nActual: %+v", expected, proxyInit.Args)
+		}
+	})
+
+	t.Run("rejects invalid port ranges", func(t *testing.T) {
+		err := applyProxyInitConfig(&corev1.Container{}, map[string]string{k8s.ProxySkipOutboundPortsAnnotation: "4100-4000"})
+		expected := `invalid value "4100-4000" for the config.linkerd.io/skip-outbound-ports annotation: invalid port range "4100-4000": lower bound is greater than upper bound`
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error mismatch\nExpected: %s\nActual: %v", expected, err)
+		}
+	})
 }
 
 func TestContainersSpec(t *testing.T) {
@@ -179,3 +878,157 @@ func assertEqualAdmissionReview(t *testing.T, expected, actual *admissionv1beta1
 		t.Errorf("Response patch mismatch\nExpected: %s\nActual: %s", expected.Response.Patch, actual.Response.Patch)
 	}
 }
+
+func TestProxyLogConfig(t *testing.T) {
+	namespace, err := factory.Namespace("namespace-kube-public.yaml")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	namespace.Annotations = map[string]string{k8s.ProxyLogLevelAnnotation: "warn,linkerd2_proxy=debug"}
+
+	w, err := NewWebhook(k8sfake.NewSimpleClientset(namespace), testWebhookResources, fake.DefaultControllerNamespace)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+
+	testCases := []struct {
+		title       string
+		annotations map[string]string
+		expected    string
+	}{
+		{"uses the namespace default", map[string]string{}, "warn,linkerd2_proxy=debug"},
+		{"lets pods override the namespace default", map[string]string{k8s.ProxyLogLevelAnnotation: "trace"}, "trace"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			deployment, err := factory.Deployment("deployment-inject-status-empty.yaml")
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+			for key, value := range tc.annotations {
+				deployment.Spec.Template.Annotations[key] = value
+			}
+
+			config, err := w.proxyConfig(namespace.Name, newDeploymentWorkload(deployment))
+			if err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+			proxy := &corev1.Container{}
+			if err := applyProxyConfig(proxy, config); err != nil {
+				t.Fatal("Unexpected error: ", err)
+			}
+
+			expected := []corev1.EnvVar{{Name: envVarKeyProxyLog, Value: tc.expected}}
+			if !reflect.DeepEqual(expected, proxy.Env) {
+				t.Errorf("Env mismatch\nExpected: %+v\nActual: %+v", expected, proxy.Env)
+			}
+		})
+	}
+
+	t.Run("overrides the install-time log level", func(t *testing.T) {
+		proxy := &corev1.Container{Env: []corev1.EnvVar{{Name: envVarKeyProxyLog, Value: "warn,linkerd2_proxy=info"}}}
+		if err := applyProxyConfig(proxy, map[string]string{k8s.ProxyLogLevelAnnotation: "debug"}); err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		expected := []corev1.EnvVar{{Name: envVarKeyProxyLog, Value: "debug"}}
+		if !reflect.DeepEqual(expected, proxy.Env) {
+			t.Errorf("Env mismatch\nExpected: %+v\nActual: %+v", expected, proxy.Env)
+		}
+	})
+
+	t.Run("rejects invalid levels", func(t *testing.T) {
+		err := applyProxyConfig(&corev1.Container{}, map[string]string{k8s.ProxyLogLevelAnnotation: "linkerd2_proxy=verbose"})
+		expected := `invalid value "linkerd2_proxy=verbose" for the config.linkerd.io/proxy-log-level annotation: must be a comma-separated list of levels or target=level directives, with levels among: trace, debug, info, warn, error, off`
+		if err == nil || err.Error() != expected {
+			t.Errorf("Error mismatch\nExpected: %s\nActual: %v", expected, err)
+		}
+	})
+}
+
+type recordingSink struct {
+	events []events.Event
+}
+
+func (s *recordingSink) Post(event events.Event) {
+	s.events = append(s.events, event)
+}
+
+func TestMutatePostsEvents(t *testing.T) {
+	namespace, err := factory.Namespace("namespace-kube-public.yaml")
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	w, err := NewWebhook(k8sfake.NewSimpleClientset(namespace), testWebhookResources, fake.DefaultControllerNamespace)
+	if err != nil {
+		t.Fatal("Unexpected error: ", err)
+	}
+	sink := &recordingSink{}
+	w.SetEventSink(sink)
+
+	review := func(annotations map[string]string) []byte {
+		deployment, err := factory.Deployment("deployment-inject-status-empty.yaml")
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		for key, value := range annotations {
+			deployment.Spec.Template.Annotations[key] = value
+		}
+		raw, err := json.Marshal(deployment)
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		data, err := json.Marshal(admissionv1beta1.AdmissionReview{
+			Request: &admissionv1beta1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+				Namespace: namespace.Name,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		})
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+		return data
+	}
+
+	t.Run("posts a ProxyInjected event for each injected workload", func(t *testing.T) {
+		response := w.Mutate(review(nil))
+		if !response.Response.Allowed || len(response.Response.Patch) == 0 {
+			t.Fatalf("Expected the deployment to be injected, got %+v", response.Response)
+		}
+
+		if len(sink.events) != 1 {
+			t.Fatalf("Expected 1 event, got %+v", sink.events)
+		}
+		event := sink.events[0]
+		if event.Type != events.ProxyInjected || event.Namespace != namespace.Name || event.Attributes["kind"] != "deployment" || event.Attributes["name"] == "" {
+			t.Fatalf("Unexpected event: %+v", event)
+		}
+	})
+
+	t.Run("posts a PolicyDenialSpike event when too many requests are denied", func(t *testing.T) {
+		sink.events = nil
+		invalid := review(map[string]string{k8s.ProxyLogLevelAnnotation: "loud"})
+
+		for i := 0; i < denialSpikeThreshold+5; i++ {
+			if response := w.Mutate(invalid); response.Response.Allowed {
+				t.Fatalf("Expected the deployment to be denied, got %+v", response.Response)
+			}
+		}
+
+		if len(sink.events) != 1 {
+			t.Fatalf("Expected 1 event, got %+v", sink.events)
+		}
+		event := sink.events[0]
+		expected := map[string]string{
+			"denials":    "10",
+			"window":     "1m0s",
+			"lastKind":   "deployment",
+			"lastReason": `invalid value "loud" for the config.linkerd.io/proxy-log-level annotation: must be a comma-separated list of levels or target=level directives, with levels among: trace, debug, info, warn, error, off`,
+		}
+		if event.Type != events.PolicyDenialSpike || !reflect.DeepEqual(event.Attributes, expected) {
+			t.Fatalf("Unexpected event: %+v", event)
+		}
+	})
+}
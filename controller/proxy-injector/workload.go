@@ -0,0 +1,128 @@
+package injector
+
+import (
+	"fmt"
+
+	yaml "github.com/ghodss/yaml"
+	k8sPkg "github.com/linkerd/linkerd2/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	templatePathDeployment = "/spec/template"
+	templatePathJob        = "/spec/template"
+	templatePathCronJob    = "/spec/jobTemplate/spec/template"
+	templatePathRollout    = "/spec/template"
+)
+
+// workload is a resource whose pods are injected by the webhook: its
+// metadata, and the pod template its pods are created from.
+type workload struct {
+	// kind is the lowercase kind of the resource, e.g. "deployment".
+	kind string
+
+	meta     *metav1.ObjectMeta
+	template *corev1.PodTemplateSpec
+
+	// templatePath is the JSON pointer to the pod template in the resource,
+	// which the patch operations are relative to.
+	templatePath string
+
+	// label is the label identifying the resource on its pods.
+	label string
+}
+
+func newDeploymentWorkload(deployment *appsv1.Deployment) *workload {
+	return &workload{
+		kind:         "deployment",
+		meta:         &deployment.ObjectMeta,
+		template:     &deployment.Spec.Template,
+		templatePath: templatePathDeployment,
+		label:        k8sPkg.ProxyDeploymentLabel,
+	}
+}
+
+// completes returns true if the workload's pods run to completion, instead
+// of running until they're deleted.
+func (w *workload) completes() bool {
+	return w.kind == "job" || w.kind == "cronjob"
+}
+
+// decodeWorkload decodes a resource of the given kind. Argo Rollouts have no
+// Go type we can depend on, so only their metadata and spec.template are
+// decoded.
+func decodeWorkload(kind string, raw []byte) (*workload, error) {
+	switch kind {
+	case "Job":
+		var job batchv1.Job
+		if err := yaml.Unmarshal(raw, &job); err != nil {
+			return nil, err
+		}
+		return &workload{
+			kind:         "job",
+			meta:         &job.ObjectMeta,
+			template:     &job.Spec.Template,
+			templatePath: templatePathJob,
+			label:        k8sPkg.ProxyJobLabel,
+		}, nil
+
+	case "CronJob":
+		var cronJob batchv1beta1.CronJob
+		if err := yaml.Unmarshal(raw, &cronJob); err != nil {
+			return nil, err
+		}
+		return &workload{
+			kind:         "cronjob",
+			meta:         &cronJob.ObjectMeta,
+			template:     &cronJob.Spec.JobTemplate.Spec.Template,
+			templatePath: templatePathCronJob,
+			label:        k8sPkg.ProxyCronJobLabel,
+		}, nil
+
+	case "Rollout":
+		var rollout unstructured.Unstructured
+		if err := yaml.Unmarshal(raw, &rollout.Object); err != nil {
+			return nil, err
+		}
+
+		var meta metav1.ObjectMeta
+		if err := yaml.Unmarshal(raw, &struct {
+			Meta *metav1.ObjectMeta `json:"metadata"`
+		}{&meta}); err != nil {
+			return nil, err
+		}
+
+		tmpl, found, err := unstructured.NestedMap(rollout.Object, "spec", "template")
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf("rollout \"%s\" has no spec.template", meta.Name)
+		}
+		var template corev1.PodTemplateSpec
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(tmpl, &template); err != nil {
+			return nil, err
+		}
+
+		return &workload{
+			kind:         "rollout",
+			meta:         &meta,
+			template:     &template,
+			templatePath: templatePathRollout,
+			label:        k8sPkg.ProxyRolloutLabel,
+		}, nil
+	}
+
+	// the webhook is only registered for the kinds above and deployments
+	var deployment appsv1.Deployment
+	if err := yaml.Unmarshal(raw, &deployment); err != nil {
+		return nil, err
+	}
+	return newDeploymentWorkload(&deployment), nil
+}
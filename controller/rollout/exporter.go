@@ -0,0 +1,87 @@
+package rollout
+
+import (
+	"strconv"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	appsv1beta2 "k8s.io/api/apps/v1beta2"
+	"k8s.io/client-go/tools/cache"
+)
+
+// revisionAnnotation is set by the Kubernetes deployment controller on each
+// Deployment, and incremented every time a new rollout is started.
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// Exporter watches Deployments and exports their current rollout revision as
+// a Prometheus gauge. A change in the revision of a Deployment marks the start
+// of a rollout, which the Grafana dashboards display as an annotation.
+type Exporter struct {
+	revision *prometheus.GaugeVec
+}
+
+// NewExporter initializes an Exporter and registers its metrics with the
+// given registerer. The k8sAPI must be configured with the Deploy resource.
+func NewExporter(k8sAPI *k8s.API, registerer prometheus.Registerer) (*Exporter, error) {
+	e := &Exporter{
+		revision: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "deployment_revision",
+				Help: "The current rollout revision of a deployment.",
+			},
+			[]string{"namespace", "deployment"},
+		),
+	}
+
+	if err := registerer.Register(e.revision); err != nil {
+		return nil, err
+	}
+
+	k8sAPI.Deploy().Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    e.handleDeploymentUpdate,
+			UpdateFunc: func(_, obj interface{}) { e.handleDeploymentUpdate(obj) },
+			DeleteFunc: e.handleDeploymentDelete,
+		},
+	)
+
+	return e, nil
+}
+
+func (e *Exporter) handleDeploymentUpdate(obj interface{}) {
+	deploy, ok := obj.(*appsv1beta2.Deployment)
+	if !ok {
+		return
+	}
+
+	value, ok := deploy.Annotations[revisionAnnotation]
+	if !ok {
+		// the deployment controller hasn't processed this deployment yet
+		return
+	}
+
+	revision, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Warnf("Invalid %s annotation on deployment %s.%s: %s", revisionAnnotation, deploy.Name, deploy.Namespace, err)
+		return
+	}
+
+	e.revision.WithLabelValues(deploy.Namespace, deploy.Name).Set(revision)
+}
+
+func (e *Exporter) handleDeploymentDelete(obj interface{}) {
+	deploy, ok := obj.(*appsv1beta2.Deployment)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		deploy, ok = tombstone.Obj.(*appsv1beta2.Deployment)
+		if !ok {
+			return
+		}
+	}
+
+	e.revision.DeleteLabelValues(deploy.Namespace, deploy.Name)
+}
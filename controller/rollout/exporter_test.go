@@ -0,0 +1,88 @@
+package rollout
+
+import (
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1beta2 "k8s.io/api/apps/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func genDeployment(name, revision string) *appsv1beta2.Deployment {
+	deploy := &appsv1beta2.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "emojivoto",
+			Annotations: map[string]string{},
+		},
+	}
+	if revision != "" {
+		deploy.Annotations[revisionAnnotation] = revision
+	}
+	return deploy
+}
+
+func gatherRevisions(t *testing.T, registry *prometheus.Registry) map[string]float64 {
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	revisions := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != "deployment_revision" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			revisions[labels["deployment"]+"."+labels["namespace"]] = metric.GetGauge().GetValue()
+		}
+	}
+	return revisions
+}
+
+func TestExporter(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("")
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+
+	registry := prometheus.NewRegistry()
+	exporter, err := NewExporter(k8sAPI, registry)
+	if err != nil {
+		t.Fatalf("NewExporter returned an error: %s", err)
+	}
+
+	exporter.handleDeploymentUpdate(genDeployment("web", "1"))
+	exporter.handleDeploymentUpdate(genDeployment("emoji", "3"))
+	exporter.handleDeploymentUpdate(genDeployment("voting", ""))
+	exporter.handleDeploymentUpdate(genDeployment("vote-bot", "invalid"))
+
+	revisions := gatherRevisions(t, registry)
+	expected := map[string]float64{"web.emojivoto": 1, "emoji.emojivoto": 3}
+	if len(revisions) != len(expected) {
+		t.Fatalf("Expected revisions %v, got %v", expected, revisions)
+	}
+	for key, revision := range expected {
+		if revisions[key] != revision {
+			t.Fatalf("Expected revisions %v, got %v", expected, revisions)
+		}
+	}
+
+	exporter.handleDeploymentUpdate(genDeployment("web", "2"))
+	exporter.handleDeploymentDelete(genDeployment("emoji", "3"))
+	exporter.handleDeploymentDelete(cache.DeletedFinalStateUnknown{
+		Key: "emojivoto/voting",
+		Obj: genDeployment("voting", ""),
+	})
+
+	revisions = gatherRevisions(t, registry)
+	if len(revisions) != 1 || revisions["web.emojivoto"] != 2 {
+		t.Fatalf("Expected revisions map[web.emojivoto:2], got %v", revisions)
+	}
+}
@@ -0,0 +1,128 @@
+package rollout
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/events"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/cache"
+)
+
+// UpgradeWatcher watches the ReplicaSets of the control plane, and posts a
+// ControlPlaneUpgraded event when its components, which ran several versions
+// during an upgrade, all run the same version again. The versions are read
+// from the CreatedByAnnotation of the pod templates, and only the ReplicaSets
+// with running pods count, so that restarting a component isn't mistaken for
+// an upgrade.
+type UpgradeWatcher struct {
+	controllerNamespace string
+	k8sAPI              *k8s.API
+	events              events.Sink
+
+	// previous are the versions that ran alongside the new one since the
+	// upgrade started, or empty if no upgrade is in progress.
+	previous map[string]struct{}
+	mu       sync.Mutex
+}
+
+// NewUpgradeWatcher initializes an UpgradeWatcher that posts its events to
+// sink. The k8sAPI must be configured with the RS resource.
+func NewUpgradeWatcher(controllerNamespace string, k8sAPI *k8s.API, sink events.Sink) *UpgradeWatcher {
+	w := &UpgradeWatcher{
+		controllerNamespace: controllerNamespace,
+		k8sAPI:              k8sAPI,
+		events:              sink,
+		previous:            make(map[string]struct{}),
+	}
+
+	k8sAPI.RS().Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(interface{}) { w.update() },
+			UpdateFunc: func(_, _ interface{}) { w.update() },
+			DeleteFunc: func(interface{}) { w.update() },
+		},
+	)
+
+	return w
+}
+
+// update compares the versions that the control plane runs with those of the
+// upgrade in progress.
+func (w *UpgradeWatcher) update() {
+	versions, err := w.runningVersions()
+	if err != nil {
+		log.Errorf("failed to list the control plane replica sets: %s", err)
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(versions) > 1 {
+		for _, version := range versions {
+			w.previous[version] = struct{}{}
+		}
+		return
+	}
+	if len(versions) == 0 || len(w.previous) == 0 {
+		return
+	}
+
+	version := versions[0]
+	delete(w.previous, version)
+	previous := []string{}
+	for v := range w.previous {
+		previous = append(previous, v)
+	}
+	sort.Strings(previous)
+	w.previous = make(map[string]struct{})
+	if len(previous) == 0 {
+		return
+	}
+
+	log.Infof("control plane upgraded from %s to %s", strings.Join(previous, ", "), version)
+	w.events.Post(events.Event{
+		Type:      events.ControlPlaneUpgraded,
+		Namespace: w.controllerNamespace,
+		Attributes: map[string]string{
+			"version":         version,
+			"previousVersion": strings.Join(previous, ","),
+		},
+	})
+}
+
+// runningVersions returns the sorted versions of the control plane
+// ReplicaSets that have pods.
+func (w *UpgradeWatcher) runningVersions() ([]string, error) {
+	requirement, err := labels.NewRequirement(pkgK8s.ControllerComponentLabel, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	replicaSets, err := w.k8sAPI.RS().Lister().ReplicaSets(w.controllerNamespace).List(labels.NewSelector().Add(*requirement))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+	versions := []string{}
+	for _, rs := range replicaSets {
+		createdBy, ok := rs.Spec.Template.Annotations[pkgK8s.CreatedByAnnotation]
+		if !ok || rs.Status.Replicas == 0 {
+			continue
+		}
+		// the annotation is of the form "linkerd/cli <version>"
+		version := createdBy[strings.LastIndex(createdBy, " ")+1:]
+		if _, ok := seen[version]; !ok {
+			seen[version] = struct{}{}
+			versions = append(versions, version)
+		}
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
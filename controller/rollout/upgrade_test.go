@@ -0,0 +1,100 @@
+package rollout
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/events"
+	appsv1beta2 "k8s.io/api/apps/v1beta2"
+)
+
+type recordingSink struct {
+	events []events.Event
+}
+
+func (s *recordingSink) Post(event events.Event) {
+	s.events = append(s.events, event)
+}
+
+func controlPlaneReplicaSet(name, version string, replicas int) string {
+	return fmt.Sprintf(`
+apiVersion: apps/v1beta2
+kind: ReplicaSet
+metadata:
+  name: %s
+  namespace: linkerd
+  labels:
+    linkerd.io/control-plane-component: controller
+spec:
+  template:
+    metadata:
+      annotations:
+        linkerd.io/created-by: linkerd/cli %s
+status:
+  replicas: %d`, name, version, replicas)
+}
+
+func TestUpgradeWatcher(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("",
+		controlPlaneReplicaSet("linkerd-controller-1", "stable-2.1.0", 1),
+		controlPlaneReplicaSet("linkerd-ca-1", "stable-2.1.0", 1),
+		controlPlaneReplicaSet("linkerd-ca-0", "stable-2.0.0", 0),
+	)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+	sink := &recordingSink{}
+	watcher := NewUpgradeWatcher("linkerd", k8sAPI, sink)
+	k8sAPI.Sync()
+
+	store := k8sAPI.RS().Informer().GetStore()
+	setReplicas := func(name, version string, replicas int32) {
+		rs := &appsv1beta2.ReplicaSet{}
+		rs.Name = name
+		rs.Namespace = "linkerd"
+		rs.Labels = map[string]string{"linkerd.io/control-plane-component": "controller"}
+		rs.Spec.Template.Annotations = map[string]string{"linkerd.io/created-by": "linkerd/cli " + version}
+		rs.Status.Replicas = replicas
+		if err := store.Update(rs); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		watcher.update()
+	}
+
+	t.Run("Doesn't post events when the control plane runs a single version", func(t *testing.T) {
+		watcher.update()
+		setReplicas("linkerd-controller-1", "stable-2.1.0", 2)
+
+		if len(sink.events) != 0 {
+			t.Fatalf("Expected no events, got %+v", sink.events)
+		}
+	})
+
+	t.Run("Posts an event when all the components run the new version", func(t *testing.T) {
+		setReplicas("linkerd-controller-2", "stable-2.2.0", 1)
+		setReplicas("linkerd-ca-2", "stable-2.2.0", 1)
+		setReplicas("linkerd-controller-1", "stable-2.1.0", 0)
+		if len(sink.events) != 0 {
+			t.Fatalf("Expected no events during the upgrade, got %+v", sink.events)
+		}
+
+		setReplicas("linkerd-ca-1", "stable-2.1.0", 0)
+		if len(sink.events) != 1 {
+			t.Fatalf("Expected 1 event, got %+v", sink.events)
+		}
+		event := sink.events[0]
+		if event.Type != events.ControlPlaneUpgraded || event.Attributes["version"] != "stable-2.2.0" || event.Attributes["previousVersion"] != "stable-2.1.0" {
+			t.Fatalf("Unexpected event: %+v", event)
+		}
+	})
+
+	t.Run("Doesn't post events when components restart", func(t *testing.T) {
+		setReplicas("linkerd-controller-2", "stable-2.2.0", 0)
+		setReplicas("linkerd-controller-2", "stable-2.2.0", 1)
+
+		if len(sink.events) != 1 {
+			t.Fatalf("Expected no new events, got %+v", sink.events)
+		}
+	})
+}
@@ -0,0 +1,331 @@
+package servicemirror
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// RemoteClusterServiceWatcher mirrors the services of a remote cluster that
+// match the selector of a Link into the local cluster. Each remote service is
+// mirrored in the same namespace, if it exists locally, as a service without
+// selector named after the remote service and the remote cluster, whose
+// endpoints are the remote cluster's gateway.
+type RemoteClusterServiceWatcher struct {
+	link        *v1alpha1.Link
+	selector    labels.Selector
+	remoteAPI   *k8s.API
+	localAPI    *k8s.API
+	syncHandler func(key string) error
+
+	// lookupIP resolves the gateway address when it's a hostname.
+	lookupIP func(host string) ([]net.IP, error)
+
+	// The queue is keyed on the "namespace/name" of the remote services, so
+	// that the deleted ones can still be synced, by deleting their mirrors.
+	queue workqueue.RateLimitingInterface
+}
+
+// NewRemoteClusterServiceWatcher initializes a RemoteClusterServiceWatcher for
+// the link. remoteAPI must watch the services of the remote cluster, and
+// localAPI the services and namespaces of the local cluster.
+func NewRemoteClusterServiceWatcher(link *v1alpha1.Link, remoteAPI, localAPI *k8s.API) (*RemoteClusterServiceWatcher, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&link.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector in link %s: %s", link.Name, err)
+	}
+
+	rcsw := &RemoteClusterServiceWatcher{
+		link:      link,
+		selector:  selector,
+		remoteAPI: remoteAPI,
+		localAPI:  localAPI,
+		lookupIP:  net.LookupIP,
+		queue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(), "service-mirror"),
+	}
+
+	remoteAPI.Svc().Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    rcsw.enqueue,
+			UpdateFunc: func(_, obj interface{}) { rcsw.enqueue(obj) },
+			DeleteFunc: rcsw.enqueue,
+		},
+	)
+
+	rcsw.syncHandler = rcsw.syncService
+
+	return rcsw, nil
+}
+
+// Run kicks off the mirroring of the remote services. The local mirrors of
+// the remote services that were deleted while it wasn't running are deleted
+// first.
+func (rcsw *RemoteClusterServiceWatcher) Run(stopCh <-chan struct{}) {
+	defer runtime.HandleCrash()
+	defer rcsw.queue.ShutDown()
+
+	log.Infof("starting service mirror for cluster %s", rcsw.link.Spec.TargetClusterName)
+	defer log.Infof("shutting down service mirror for cluster %s", rcsw.link.Spec.TargetClusterName)
+
+	if err := rcsw.enqueueMirrors(); err != nil {
+		log.Errorf("failed to list the mirrored services: %s", err)
+	}
+	go wait.Until(rcsw.worker, time.Second, stopCh)
+
+	<-stopCh
+}
+
+func (rcsw *RemoteClusterServiceWatcher) worker() {
+	for rcsw.processNextWorkItem() {
+	}
+}
+
+func (rcsw *RemoteClusterServiceWatcher) processNextWorkItem() bool {
+	key, quit := rcsw.queue.Get()
+	if quit {
+		return false
+	}
+	defer rcsw.queue.Done(key)
+
+	err := rcsw.syncHandler(key.(string))
+	if err != nil {
+		log.Errorf("error syncing service %s: %s", key, err)
+		rcsw.queue.AddRateLimited(key)
+		return true
+	}
+
+	rcsw.queue.Forget(key)
+	return true
+}
+
+func (rcsw *RemoteClusterServiceWatcher) enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		log.Errorf("failed to get the key of %+v: %s", obj, err)
+		return
+	}
+	rcsw.queue.Add(key)
+}
+
+// enqueueMirrors enqueues the remote services of all the local mirrors of the
+// remote cluster, so that the mirrors of the services that no longer exist are
+// deleted.
+func (rcsw *RemoteClusterServiceWatcher) enqueueMirrors() error {
+	mirrors, err := rcsw.localAPI.Svc().Lister().List(rcsw.mirrorSelector())
+	if err != nil {
+		return err
+	}
+	suffix := "-" + rcsw.link.Spec.TargetClusterName
+	for _, mirror := range mirrors {
+		rcsw.queue.Add(mirror.Namespace + "/" + strings.TrimSuffix(mirror.Name, suffix))
+	}
+	return nil
+}
+
+// syncService creates, updates or deletes the local mirror of the remote
+// service, so that it exists as long as the remote service is selected.
+func (rcsw *RemoteClusterServiceWatcher) syncService(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	remote, err := rcsw.remoteAPI.Svc().Lister().Services(namespace).Get(name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if apierrors.IsNotFound(err) || !rcsw.selector.Matches(labels.Set(remote.Labels)) || remote.Spec.Type == v1.ServiceTypeExternalName {
+		return rcsw.deleteMirror(namespace, rcsw.mirroredName(name))
+	}
+
+	if _, err := rcsw.localAPI.NS().Lister().Get(namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Debugf("skipping service %s of cluster %s: namespace %s doesn't exist locally", key, rcsw.link.Spec.TargetClusterName, namespace)
+			return nil
+		}
+		return err
+	}
+
+	addresses, err := rcsw.gatewayAddresses()
+	if err != nil {
+		return err
+	}
+	return rcsw.mirror(remote, addresses)
+}
+
+// mirror creates or updates the local service and endpoints that mirror the
+// remote service.
+func (rcsw *RemoteClusterServiceWatcher) mirror(remote *v1.Service, gatewayAddresses []v1.EndpointAddress) error {
+	name := rcsw.mirroredName(remote.Name)
+	client := rcsw.localAPI.Client.CoreV1()
+
+	service, endpoints := rcsw.mirroredResources(remote, gatewayAddresses)
+
+	existing, err := rcsw.localAPI.Svc().Lister().Services(remote.Namespace).Get(name)
+	switch {
+	case apierrors.IsNotFound(err):
+		log.Infof("mirroring service %s/%s of cluster %s as %s", remote.Namespace, remote.Name, rcsw.link.Spec.TargetClusterName, name)
+		if _, err := client.Services(remote.Namespace).Create(service); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case existing.Labels[pkgK8s.RemoteClusterNameLabel] != rcsw.link.Spec.TargetClusterName:
+		log.Warnf("not mirroring service %s/%s of cluster %s: service %s already exists and isn't mirrored from it", remote.Namespace, remote.Name, rcsw.link.Spec.TargetClusterName, name)
+		return nil
+	default:
+		updated := existing.DeepCopy()
+		updated.Labels = service.Labels
+		updated.Annotations = service.Annotations
+		updated.Spec.Ports = service.Spec.Ports
+		if _, err := client.Services(remote.Namespace).Update(updated); err != nil {
+			return err
+		}
+	}
+
+	existingEndpoints, err := client.Endpoints(remote.Namespace).Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = client.Endpoints(remote.Namespace).Create(endpoints)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	updated := existingEndpoints.DeepCopy()
+	updated.Labels = endpoints.Labels
+	updated.Annotations = endpoints.Annotations
+	updated.Subsets = endpoints.Subsets
+	_, err = client.Endpoints(remote.Namespace).Update(updated)
+	return err
+}
+
+// mirroredResources returns the local service and endpoints that mirror the
+// remote service. Every port of the service is sent to the gateway port.
+func (rcsw *RemoteClusterServiceWatcher) mirroredResources(remote *v1.Service, gatewayAddresses []v1.EndpointAddress) (*v1.Service, *v1.Endpoints) {
+	meta := metav1.ObjectMeta{
+		Name:      rcsw.mirroredName(remote.Name),
+		Namespace: remote.Namespace,
+		Labels: map[string]string{
+			pkgK8s.MirroredServiceLabel:   "true",
+			pkgK8s.RemoteClusterNameLabel: rcsw.link.Spec.TargetClusterName,
+		},
+		Annotations: map[string]string{
+			pkgK8s.RemoteServiceAnnotation:         fmt.Sprintf("%s.%s.svc.%s", remote.Name, remote.Namespace, rcsw.link.Spec.TargetClusterDomain),
+			pkgK8s.RemoteResourceVersionAnnotation: remote.ResourceVersion,
+		},
+	}
+
+	ports := []v1.ServicePort{}
+	endpointPorts := []v1.EndpointPort{}
+	for _, port := range remote.Spec.Ports {
+		ports = append(ports, v1.ServicePort{
+			Name:     port.Name,
+			Protocol: port.Protocol,
+			Port:     port.Port,
+		})
+		endpointPorts = append(endpointPorts, v1.EndpointPort{
+			Name:     port.Name,
+			Protocol: port.Protocol,
+			Port:     int32(rcsw.link.Spec.GatewayPort),
+		})
+	}
+
+	service := &v1.Service{
+		ObjectMeta: meta,
+		Spec:       v1.ServiceSpec{Ports: ports},
+	}
+
+	endpointsMeta := *meta.DeepCopy()
+	if rcsw.link.Spec.GatewayIdentity != "" {
+		endpointsMeta.Annotations[pkgK8s.RemoteGatewayIdentityAnnotation] = rcsw.link.Spec.GatewayIdentity
+	}
+	endpoints := &v1.Endpoints{
+		ObjectMeta: endpointsMeta,
+		Subsets: []v1.EndpointSubset{
+			{
+				Addresses: gatewayAddresses,
+				Ports:     endpointPorts,
+			},
+		},
+	}
+	return service, endpoints
+}
+
+// deleteMirror deletes the local mirror of a remote service, if it exists.
+// The endpoints are deleted along with it.
+func (rcsw *RemoteClusterServiceWatcher) deleteMirror(namespace, name string) error {
+	mirror, err := rcsw.localAPI.Svc().Lister().Services(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if mirror.Labels[pkgK8s.RemoteClusterNameLabel] != rcsw.link.Spec.TargetClusterName {
+		return nil
+	}
+
+	log.Infof("deleting mirrored service %s/%s of cluster %s", namespace, name, rcsw.link.Spec.TargetClusterName)
+	err = rcsw.localAPI.Client.CoreV1().Services(namespace).Delete(name, &metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// gatewayAddresses returns the IP addresses of the gateway, resolving its
+// address if it's a hostname.
+func (rcsw *RemoteClusterServiceWatcher) gatewayAddresses() ([]v1.EndpointAddress, error) {
+	address := rcsw.link.Spec.GatewayAddress
+	if net.ParseIP(address) != nil {
+		return []v1.EndpointAddress{{IP: address}}, nil
+	}
+
+	ips, err := rcsw.lookupIP(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve gateway address %s: %s", address, err)
+	}
+	resolved := []string{}
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			resolved = append(resolved, ip.String())
+		}
+	}
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("gateway address %s has no IPv4 address", address)
+	}
+	sort.Strings(resolved)
+
+	addresses := []v1.EndpointAddress{}
+	for _, ip := range resolved {
+		addresses = append(addresses, v1.EndpointAddress{IP: ip})
+	}
+	return addresses, nil
+}
+
+func (rcsw *RemoteClusterServiceWatcher) mirroredName(name string) string {
+	return fmt.Sprintf("%s-%s", name, rcsw.link.Spec.TargetClusterName)
+}
+
+func (rcsw *RemoteClusterServiceWatcher) mirrorSelector() labels.Selector {
+	return labels.SelectorFromSet(labels.Set{
+		pkgK8s.MirroredServiceLabel:   "true",
+		pkgK8s.RemoteClusterNameLabel: rcsw.link.Spec.TargetClusterName,
+	})
+}
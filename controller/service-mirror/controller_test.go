@@ -0,0 +1,250 @@
+package servicemirror
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	emojivotoNamespace = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: emojivoto`
+
+	exportedWebService = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: emojivoto
+  resourceVersion: "42"
+  labels:
+    mirror.linkerd.io/exported: "true"
+spec:
+  selector:
+    app: web
+  ports:
+  - name: http
+    port: 80
+    targetPort: 8080
+  - name: grpc
+    port: 8081`
+
+	internalVotingService = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: voting
+  namespace: emojivoto
+spec:
+  ports:
+  - name: grpc
+    port: 8080`
+
+	mirroredWebService = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web-east
+  namespace: emojivoto
+  labels:
+    mirror.linkerd.io/mirrored-service: "true"
+    mirror.linkerd.io/cluster-name: east
+spec:
+  ports:
+  - name: http
+    port: 80`
+
+	localWebService = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web-east
+  namespace: emojivoto
+spec:
+  ports:
+  - name: http
+    port: 80`
+)
+
+func newTestWatcher(t *testing.T, gatewayAddress string, remote, local []string) *RemoteClusterServiceWatcher {
+	remoteAPI, err := k8s.NewFakeAPI("", remote...)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+	localAPI, err := k8s.NewFakeAPI("", local...)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+
+	link := &v1alpha1.Link{
+		ObjectMeta: metav1.ObjectMeta{Name: "east", Namespace: "linkerd"},
+		Spec: v1alpha1.LinkSpec{
+			TargetClusterName:        "east",
+			TargetClusterDomain:      "cluster.local",
+			ClusterCredentialsSecret: "cluster-credentials-east",
+			GatewayAddress:           gatewayAddress,
+			GatewayPort:              4143,
+			GatewayIdentity:          "linkerd-gateway.linkerd-multicluster.serviceaccount.identity.linkerd.cluster.local",
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"mirror.linkerd.io/exported": "true"},
+			},
+		},
+	}
+	rcsw, err := NewRemoteClusterServiceWatcher(link, remoteAPI, localAPI)
+	if err != nil {
+		t.Fatalf("NewRemoteClusterServiceWatcher returned an error: %s", err)
+	}
+	rcsw.lookupIP = func(host string) ([]net.IP, error) {
+		if host == "gateway.east.example.com" {
+			return []net.IP{net.ParseIP("203.0.113.20"), net.ParseIP("2001:db8::1"), net.ParseIP("203.0.113.10")}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	remoteAPI.Sync()
+	localAPI.Sync()
+	return rcsw
+}
+
+func TestRemoteClusterServiceWatcher(t *testing.T) {
+	t.Run("Mirrors the selected services through the gateway", func(t *testing.T) {
+		rcsw := newTestWatcher(t, "gateway.east.example.com", []string{exportedWebService}, []string{emojivotoNamespace})
+
+		if err := rcsw.syncService("emojivoto/web"); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		client := rcsw.localAPI.Client.CoreV1()
+		service, err := client.Services("emojivoto").Get("web-east", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expectedLabels := map[string]string{
+			pkgK8s.MirroredServiceLabel:   "true",
+			pkgK8s.RemoteClusterNameLabel: "east",
+		}
+		if !reflect.DeepEqual(service.Labels, expectedLabels) {
+			t.Fatalf("Expected labels %v, got %v", expectedLabels, service.Labels)
+		}
+		if fqName := service.Annotations[pkgK8s.RemoteServiceAnnotation]; fqName != "web.emojivoto.svc.cluster.local" {
+			t.Fatalf("Unexpected remote service name: %s", fqName)
+		}
+		if version := service.Annotations[pkgK8s.RemoteResourceVersionAnnotation]; version != "42" {
+			t.Fatalf("Unexpected remote resource version: %s", version)
+		}
+		expectedPorts := []v1.ServicePort{{Name: "http", Port: 80}, {Name: "grpc", Port: 8081}}
+		if !reflect.DeepEqual(service.Spec.Ports, expectedPorts) {
+			t.Fatalf("Expected ports %+v, got %+v", expectedPorts, service.Spec.Ports)
+		}
+		if service.Spec.Selector != nil {
+			t.Fatalf("Expected no selector, got %v", service.Spec.Selector)
+		}
+
+		endpoints, err := client.Endpoints("emojivoto").Get("web-east", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expectedSubsets := []v1.EndpointSubset{
+			{
+				Addresses: []v1.EndpointAddress{{IP: "203.0.113.10"}, {IP: "203.0.113.20"}},
+				Ports:     []v1.EndpointPort{{Name: "http", Port: 4143}, {Name: "grpc", Port: 4143}},
+			},
+		}
+		if !reflect.DeepEqual(endpoints.Subsets, expectedSubsets) {
+			t.Fatalf("Expected subsets %+v, got %+v", expectedSubsets, endpoints.Subsets)
+		}
+		if identity := endpoints.Annotations[pkgK8s.RemoteGatewayIdentityAnnotation]; identity != rcsw.link.Spec.GatewayIdentity {
+			t.Fatalf("Unexpected gateway identity: %s", identity)
+		}
+	})
+
+	t.Run("Updates the existing mirrors", func(t *testing.T) {
+		rcsw := newTestWatcher(t, "203.0.113.10", []string{exportedWebService}, []string{emojivotoNamespace, mirroredWebService})
+
+		if err := rcsw.syncService("emojivoto/web"); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		service, err := rcsw.localAPI.Client.CoreV1().Services("emojivoto").Get("web-east", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(service.Spec.Ports) != 2 {
+			t.Fatalf("Expected the ports to be updated, got %+v", service.Spec.Ports)
+		}
+	})
+
+	t.Run("Skips the services that aren't selected or whose namespace doesn't exist", func(t *testing.T) {
+		rcsw := newTestWatcher(t, "203.0.113.10", []string{exportedWebService, internalVotingService}, []string{})
+
+		for _, key := range []string{"emojivoto/web", "emojivoto/voting"} {
+			if err := rcsw.syncService(key); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		}
+
+		services, err := rcsw.localAPI.Client.CoreV1().Services("emojivoto").List(metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(services.Items) != 0 {
+			t.Fatalf("Expected no mirrored services, got %+v", services.Items)
+		}
+	})
+
+	t.Run("Deletes the mirrors of the deleted services", func(t *testing.T) {
+		rcsw := newTestWatcher(t, "203.0.113.10", []string{}, []string{emojivotoNamespace, mirroredWebService})
+
+		if err := rcsw.enqueueMirrors(); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if key, _ := rcsw.queue.Get(); key != "emojivoto/web" {
+			t.Fatalf("Expected the remote service of the mirror to be enqueued, got %v", key)
+		}
+
+		if err := rcsw.syncService("emojivoto/web"); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		_, err := rcsw.localAPI.Client.CoreV1().Services("emojivoto").Get("web-east", metav1.GetOptions{})
+		if !apierrors.IsNotFound(err) {
+			t.Fatalf("Expected the mirrored service to be deleted, got: %v", err)
+		}
+	})
+
+	t.Run("Leaves the services that it doesn't mirror unchanged", func(t *testing.T) {
+		rcsw := newTestWatcher(t, "203.0.113.10", []string{exportedWebService}, []string{emojivotoNamespace, localWebService})
+
+		if err := rcsw.syncService("emojivoto/web"); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		service, err := rcsw.localAPI.Client.CoreV1().Services("emojivoto").Get("web-east", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(service.Labels) != 0 || len(service.Spec.Ports) != 1 {
+			t.Fatalf("Expected the local service to be unchanged, got %+v", service)
+		}
+	})
+
+	t.Run("Fails when the gateway can't be resolved", func(t *testing.T) {
+		rcsw := newTestWatcher(t, "gateway.unknown.example.com", []string{exportedWebService}, []string{emojivotoNamespace})
+
+		err := rcsw.syncService("emojivoto/web")
+		expected := "failed to resolve gateway address gateway.unknown.example.com: no such host"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q, got: %v", expected, err)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package servicemirror
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Results of the gateway probes, used as the values of the result label of
+// the linkerd_gateway_probes_total counter.
+const (
+	probeSuccess = "success"
+	probeFailure = "failure"
+)
+
+// ProbeWorker periodically sends an HTTP GET request to the health endpoint of
+// the gateway of a Link, and exports whether the gateway is alive and the
+// latency of the probes as Prometheus metrics, labeled with the name of the
+// remote cluster.
+type ProbeWorker struct {
+	clusterName string
+	url         string
+	period      time.Duration
+	client      *http.Client
+
+	// up is the result of the last probe, only accessed by Run, to log the
+	// changes of the gateway's state
+	up bool
+
+	alive   prometheus.Gauge
+	latency prometheus.Histogram
+	probes  *prometheus.CounterVec
+}
+
+// NewProbeWorker initializes a ProbeWorker for the gateway of the link, and
+// returns an error if the link has no valid probe spec.
+func NewProbeWorker(link *v1alpha1.Link) (*ProbeWorker, error) {
+	spec := link.Spec.ProbeSpec
+	if spec.Port == 0 {
+		return nil, fmt.Errorf("link %s has no gateway probe port", link.Name)
+	}
+	period, err := time.ParseDuration(spec.Period)
+	if err != nil || period <= 0 {
+		return nil, fmt.Errorf("invalid gateway probe period %q in link %s", spec.Period, link.Name)
+	}
+
+	labels := prometheus.Labels{"target_cluster_name": link.Spec.TargetClusterName}
+	return &ProbeWorker{
+		clusterName: link.Spec.TargetClusterName,
+		url: fmt.Sprintf("http://%s%s",
+			net.JoinHostPort(link.Spec.GatewayAddress, strconv.FormatUint(uint64(spec.Port), 10)), spec.Path),
+		period: period,
+		// assumed up, so that a gateway that's unreachable from the start
+		// is logged
+		up: true,
+		// a probe that's still pending when the next one is due has failed
+		client: &http.Client{Timeout: period},
+		alive: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "linkerd_gateway_alive",
+				Help:        "Whether the last probe of the gateway of the remote cluster succeeded (1) or not (0).",
+				ConstLabels: labels,
+			},
+		),
+		latency: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:        "linkerd_gateway_probe_latency_ms",
+				Help:        "The latency of the successful probes of the gateway of the remote cluster, in milliseconds.",
+				ConstLabels: labels,
+				Buckets:     []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000},
+			},
+		),
+		probes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "linkerd_gateway_probes_total",
+				Help:        "The number of probes of the gateway of the remote cluster, by result: success or failure.",
+				ConstLabels: labels,
+			},
+			[]string{"result"},
+		),
+	}, nil
+}
+
+// RegisterMetrics registers the ProbeWorker's metrics with the given
+// registerer.
+func (pw *ProbeWorker) RegisterMetrics(registerer prometheus.Registerer) error {
+	for _, collector := range []prometheus.Collector{pw.alive, pw.latency, pw.probes} {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run probes the gateway every period until stopCh is closed.
+func (pw *ProbeWorker) Run(stopCh <-chan struct{}) {
+	defer runtime.HandleCrash()
+
+	log.Infof("probing the gateway of cluster %s at %s every %s", pw.clusterName, pw.url, pw.period)
+	wait.Until(pw.probe, pw.period, stopCh)
+}
+
+func (pw *ProbeWorker) probe() {
+	start := time.Now()
+	err := pw.get()
+	if err != nil {
+		if pw.up {
+			log.Warnf("gateway of cluster %s is unreachable: %s", pw.clusterName, err)
+		}
+		pw.up = false
+		pw.alive.Set(0)
+		pw.probes.WithLabelValues(probeFailure).Inc()
+		return
+	}
+
+	if !pw.up {
+		log.Infof("gateway of cluster %s is alive", pw.clusterName)
+	}
+	pw.up = true
+	pw.alive.Set(1)
+	pw.latency.Observe(float64(time.Since(start)) / float64(time.Millisecond))
+	pw.probes.WithLabelValues(probeSuccess).Inc()
+}
+
+func (pw *ProbeWorker) get() error {
+	rsp, err := pw.client.Get(pw.url)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	// drain the body so that the connection can be reused
+	io.Copy(ioutil.Discard, rsp.Body)
+
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", rsp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package servicemirror
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func gatherProbeMetrics(t *testing.T, registry *prometheus.Registry) map[string]float64 {
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			name := family.GetName()
+			for _, label := range metric.GetLabel() {
+				name += "/" + label.GetValue()
+			}
+			switch {
+			case metric.Gauge != nil:
+				values[name] = metric.GetGauge().GetValue()
+			case metric.Counter != nil:
+				values[name] = metric.GetCounter().GetValue()
+			case metric.Histogram != nil:
+				values[name] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return values
+}
+
+func TestNewProbeWorker(t *testing.T) {
+	link := &v1alpha1.Link{}
+	link.Name = "east"
+	link.Spec.GatewayAddress = "203.0.113.10"
+
+	if _, err := NewProbeWorker(link); err == nil {
+		t.Fatal("Expected an error for a link without probe spec")
+	}
+
+	link.Spec.ProbeSpec = v1alpha1.ProbeSpec{Path: "/health", Port: 4181, Period: "soon"}
+	if _, err := NewProbeWorker(link); err == nil {
+		t.Fatal("Expected an error for an invalid probe period")
+	}
+
+	link.Spec.ProbeSpec.Period = "3s"
+	pw, err := NewProbeWorker(link)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if pw.url != "http://203.0.113.10:4181/health" {
+		t.Fatalf("Unexpected probe URL: %s", pw.url)
+	}
+}
+
+func TestProbeWorker(t *testing.T) {
+	healthy := true
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" || !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer gateway.Close()
+
+	host, port, err := net.SplitHostPort(gateway.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	portNumber, err := strconv.ParseUint(port, 10, 32)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	link := &v1alpha1.Link{}
+	link.Name = "east"
+	link.Spec.TargetClusterName = "east"
+	link.Spec.GatewayAddress = host
+	link.Spec.ProbeSpec = v1alpha1.ProbeSpec{Path: "/health", Port: uint32(portNumber), Period: "3s"}
+
+	pw, err := NewProbeWorker(link)
+	if err != nil {
+		t.Fatalf("NewProbeWorker returned an error: %s", err)
+	}
+	registry := prometheus.NewRegistry()
+	if err := pw.RegisterMetrics(registry); err != nil {
+		t.Fatalf("RegisterMetrics returned an error: %s", err)
+	}
+
+	pw.probe()
+	pw.probe()
+	healthy = false
+	pw.probe()
+
+	values := gatherProbeMetrics(t, registry)
+	expected := map[string]float64{
+		"linkerd_gateway_alive/east":                0,
+		"linkerd_gateway_probe_latency_ms/east":     2,
+		"linkerd_gateway_probes_total/success/east": 2,
+		"linkerd_gateway_probes_total/failure/east": 1,
+	}
+	for name, value := range expected {
+		if values[name] != value {
+			t.Errorf("Expected %s to be %f, got %f", name, value, values[name])
+		}
+	}
+
+	healthy = true
+	pw.probe()
+	if values := gatherProbeMetrics(t, registry); values["linkerd_gateway_alive/east"] != 1 {
+		t.Fatalf("Expected the gateway to be alive, got %v", values)
+	}
+}
@@ -0,0 +1,279 @@
+package tap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	publicAPI "github.com/linkerd/linkerd2/controller/api/public"
+	apiUtil "github.com/linkerd/linkerd2/controller/api/util"
+	"github.com/linkerd/linkerd2/controller/ca"
+	public "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/prometheus"
+	pkgTls "github.com/linkerd/linkerd2/pkg/tls"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	authV1 "k8s.io/api/authorization/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// requestHeaderConfigMap is the ConfigMap of the kube-system namespace
+	// that configures how the Kubernetes API aggregator authenticates to the
+	// extension API servers, and forwards the users of the requests to them.
+	requestHeaderConfigMap = "extension-apiserver-authentication"
+
+	requestHeaderClientCAKey     = "requestheader-client-ca-file"
+	requestHeaderAllowedNamesKey = "requestheader-allowed-names"
+	requestHeaderUsernameKey     = "requestheader-username-headers"
+	requestHeaderGroupKey        = "requestheader-group-headers"
+)
+
+// apiServer serves the tap API through the Kubernetes API aggregator, which
+// authenticates the users of the requests, and forwards them in headers. The
+// taps are authorized with SubjectAccessReviews, so that RBAC rules grant
+// them with the "watch" verb on the "tap" subresource of the resources.
+type apiServer struct {
+	tap             *server
+	client          kubernetes.Interface
+	allowedNames    []string
+	usernameHeaders []string
+	groupHeaders    []string
+}
+
+func (a *apiServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	log.WithFields(log.Fields{
+		"req.Method": req.Method, "req.URL": req.URL,
+	}).Debugf("Serving %s %s", req.Method, req.URL.Path)
+
+	if err := a.authenticateProxy(req); err != nil {
+		publicAPI.WriteHTTPError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	// the aggregator discovers the resources of the API without a user
+	if req.Method == http.MethodGet && req.URL.Path == pkgK8s.TapAPIRoot() {
+		a.serveDiscovery(w)
+		return
+	}
+
+	namespace, resourceType, name, err := pkgK8s.ParseTapAPIPath(req.URL.Path)
+	if err != nil {
+		http.NotFound(w, req)
+		return
+	}
+	if req.Method != http.MethodPost {
+		publicAPI.WriteHTTPError(w, http.StatusMethodNotAllowed, fmt.Errorf("POST required"))
+		return
+	}
+
+	user, groups := a.user(req)
+	if user == "" {
+		publicAPI.WriteHTTPError(w, http.StatusUnauthorized, fmt.Errorf("the request has no user"))
+		return
+	}
+	if err := a.authorize(user, groups, namespace, resourceType, name); err != nil {
+		publicAPI.WriteHTTPError(w, http.StatusForbidden, err)
+		return
+	}
+
+	publicAPI.ServeTapByResource(w, req, func(tapReq *public.TapByResourceRequest, stream public.Api_TapByResourceServer) error {
+		// the tap is authorized for the resource of the path, so the request
+		// can't target another resource
+		resource := tapReq.GetTarget().GetResource()
+		resourceNamespace := resource.GetNamespace()
+		if resource.GetType() == pkgK8s.Namespace {
+			resourceNamespace = resource.GetName()
+		}
+		if resourceNamespace != namespace || resource.GetType() != resourceType || resource.GetName() != name {
+			return status.Errorf(codes.InvalidArgument, "the target of the tap request doesn't match its path %s", req.URL.Path)
+		}
+		return a.tap.TapByResource(tapReq, stream)
+	})
+}
+
+// authenticateProxy checks that the request comes from the Kubernetes API
+// aggregator, with a client certificate issued by the request header CA, for
+// one of its allowed names.
+func (a *apiServer) authenticateProxy(req *http.Request) error {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		return fmt.Errorf("the request has no verified client certificate")
+	}
+	if len(a.allowedNames) == 0 {
+		return nil
+	}
+	commonName := req.TLS.VerifiedChains[0][0].Subject.CommonName
+	for _, name := range a.allowedNames {
+		if name == commonName {
+			return nil
+		}
+	}
+	return fmt.Errorf("the client certificate for %s isn't allowed to forward requests", commonName)
+}
+
+// user returns the user and the groups that the aggregator authenticated the
+// request for.
+func (a *apiServer) user(req *http.Request) (string, []string) {
+	user := ""
+	for _, header := range a.usernameHeaders {
+		if user = req.Header.Get(header); user != "" {
+			break
+		}
+	}
+	groups := []string{}
+	for _, header := range a.groupHeaders {
+		groups = append(groups, req.Header[http.CanonicalHeaderKey(header)]...)
+	}
+	return user, groups
+}
+
+// authorize checks that the user can watch the tap subresource of the
+// resource.
+func (a *apiServer) authorize(user string, groups []string, namespace, resourceType, name string) error {
+	review := &authV1.SubjectAccessReview{
+		Spec: authV1.SubjectAccessReviewSpec{
+			User:   user,
+			Groups: groups,
+			ResourceAttributes: &authV1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        "watch",
+				Group:       pkgK8s.TapAPIGroup,
+				Version:     pkgK8s.TapAPIVersion,
+				Resource:    pkgK8s.PluralResourceName(resourceType),
+				Subresource: pkgK8s.TapSubresource,
+				Name:        name,
+			},
+		},
+	}
+	rsp, err := a.client.AuthorizationV1().SubjectAccessReviews().Create(review)
+	if err != nil {
+		return err
+	}
+	if !rsp.Status.Allowed {
+		target := resourceType
+		if name != "" {
+			target = fmt.Sprintf("%s/%s", resourceType, name)
+		}
+		msg := fmt.Sprintf("%s can't tap %s in the %s namespace", user, target, namespace)
+		if rsp.Status.Reason != "" {
+			msg = fmt.Sprintf("%s: %s", msg, rsp.Status.Reason)
+		}
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// serveDiscovery serves the resources of the tap API, the tap subresources of
+// the resources that can be tapped.
+func (a *apiServer) serveDiscovery(w http.ResponseWriter) {
+	resources := metaV1.APIResourceList{
+		TypeMeta: metaV1.TypeMeta{
+			Kind:       "APIResourceList",
+			APIVersion: "v1",
+		},
+		GroupVersion: pkgK8s.TapAPIGroup + "/" + pkgK8s.TapAPIVersion,
+	}
+	for _, resourceType := range apiUtil.ValidTargets {
+		resources.APIResources = append(resources.APIResources, metaV1.APIResource{
+			Name:       pkgK8s.PluralResourceName(resourceType) + "/" + pkgK8s.TapSubresource,
+			Namespaced: resourceType != pkgK8s.Namespace,
+			Kind:       "Tap",
+			Verbs:      metaV1.Verbs{"watch"},
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resources); err != nil {
+		log.Errorf("Error writing the discovery document of the tap API: %s", err)
+	}
+}
+
+// NewAPIServer creates a server of the tap API, which the Kubernetes API
+// aggregator proxies to. The aggregator is authenticated with the request
+// header configuration of the kube-system namespace, and the server presents
+// a self-signed certificate, generated at startup, since the APIService
+// doesn't pin it.
+func NewAPIServer(
+	addr string,
+	tapPort uint,
+	controllerNamespace string,
+	k8sAPI *k8s.API,
+) (*http.Server, error) {
+	cm, err := k8sAPI.Client.CoreV1().ConfigMaps("kube-system").Get(requestHeaderConfigMap, metaV1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the request header configuration of the Kubernetes API aggregator: %s", err)
+	}
+	clientCAPEM := cm.Data[requestHeaderClientCAKey]
+	if clientCAPEM == "" {
+		return nil, fmt.Errorf("the kube-system/%s ConfigMap has no %s; is the Kubernetes API aggregation layer enabled?", requestHeaderConfigMap, requestHeaderClientCAKey)
+	}
+	clientCAs, err := pkgTls.DecodePEMCerts([]byte(clientCAPEM))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the %s: %s", requestHeaderClientCAKey, err)
+	}
+	clientCAPool := x509.NewCertPool()
+	for _, cert := range clientCAs {
+		clientCAPool.AddCert(cert)
+	}
+
+	handler := &apiServer{
+		tap:             newServer(tapPort, controllerNamespace, k8sAPI),
+		client:          k8sAPI.Client,
+		allowedNames:    []string{},
+		usernameHeaders: []string{"X-Remote-User"},
+		groupHeaders:    []string{"X-Remote-Group"},
+	}
+	for key, values := range map[string]*[]string{
+		requestHeaderAllowedNamesKey: &handler.allowedNames,
+		requestHeaderUsernameKey:     &handler.usernameHeaders,
+		requestHeaderGroupKey:        &handler.groupHeaders,
+	} {
+		if value := cm.Data[key]; value != "" {
+			if err := json.Unmarshal([]byte(value), values); err != nil {
+				return nil, fmt.Errorf("failed to decode the %s: %s", key, err)
+			}
+		}
+	}
+
+	cert, err := selfSignedCertificate(fmt.Sprintf("linkerd-tap.%s.svc", controllerNamespace))
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Server{
+		Addr:    addr,
+		Handler: prometheus.WithTelemetry(handler),
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			ClientAuth:   tls.VerifyClientCertIfGiven,
+			ClientCAs:    clientCAPool,
+			MinVersion:   tls.VersionTLS12,
+		},
+	}, nil
+}
+
+func selfSignedCertificate(dnsName string) (tls.Certificate, error) {
+	issuer, err := ca.NewCA()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	crt, err := issuer.IssueEndEntityCertificate(dnsName, 0)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(crt.PrivateKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{
+		Certificate: [][]byte{crt.Certificate},
+		PrivateKey:  key,
+	}, nil
+}
@@ -0,0 +1,200 @@
+package tap
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	public "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	authV1 "k8s.io/api/authorization/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func apiError(t *testing.T, rsp *httptest.ResponseRecorder) string {
+	if rsp.Header().Get("linkerd-error") == "" {
+		return ""
+	}
+	body := rsp.Body.Bytes()
+	if len(body) < 4 {
+		t.Fatalf("Expected an error message, got [%s]", body)
+	}
+	var msg public.ApiError
+	if err := proto.Unmarshal(body[4:], &msg); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return msg.Error
+}
+
+func TestAPIServer(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("")
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+
+	reviews := []*authV1.SubjectAccessReview{}
+	allowed := false
+	k8sAPI.Client.(*fake.Clientset).PrependReactor("create", "subjectaccessreviews", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8sTesting.CreateAction).GetObject().(*authV1.SubjectAccessReview)
+		reviews = append(reviews, review)
+		review.Status.Allowed = allowed
+		return true, review, nil
+	})
+
+	handler := &apiServer{
+		tap:             newServer(0, "controller-ns", k8sAPI),
+		client:          k8sAPI.Client,
+		allowedNames:    []string{"front-proxy-client"},
+		usernameHeaders: []string{"X-Remote-User"},
+		groupHeaders:    []string{"X-Remote-Group"},
+	}
+	k8sAPI.Sync()
+
+	tapPath := pkgK8s.TapAPIPath("emojivoto", pkgK8s.Deployment, "web")
+	tapRequest := func(t *testing.T, target *public.Resource) []byte {
+		body, err := proto.Marshal(&public.TapByResourceRequest{
+			Target: &public.ResourceSelection{Resource: target},
+			Match: &public.TapByResourceRequest_Match{
+				Match: &public.TapByResourceRequest_Match_All{
+					All: &public.TapByResourceRequest_Match_Seq{},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return body
+	}
+	web := &public.Resource{Namespace: "emojivoto", Type: pkgK8s.Deployment, Name: "web"}
+
+	serve := func(method, path, commonName, user string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		if commonName != "" {
+			cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+			req.TLS = &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{cert},
+				VerifiedChains:   [][]*x509.Certificate{{cert}},
+			}
+		}
+		if user != "" {
+			req.Header.Set("X-Remote-User", user)
+			req.Header.Add("X-Remote-Group", "system:authenticated")
+			req.Header.Add("X-Remote-Group", "emoji-team")
+		}
+		rsp := httptest.NewRecorder()
+		handler.ServeHTTP(rsp, req)
+		return rsp
+	}
+
+	t.Run("Rejects the requests that don't come from the aggregator", func(t *testing.T) {
+		for _, commonName := range []string{"", "kubelet"} {
+			rsp := serve(http.MethodPost, tapPath, commonName, "alice", tapRequest(t, web))
+			if rsp.Header().Get("linkerd-error") != http.StatusText(http.StatusUnauthorized) {
+				t.Fatalf("Expected an unauthorized error for [%s], got [%s]", commonName, apiError(t, rsp))
+			}
+		}
+	})
+
+	t.Run("Serves the discovery document", func(t *testing.T) {
+		rsp := serve(http.MethodGet, pkgK8s.TapAPIRoot(), "front-proxy-client", "", nil)
+		var resources metaV1.APIResourceList
+		if err := json.Unmarshal(rsp.Body.Bytes(), &resources); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if resources.GroupVersion != "tap.linkerd.io/v1alpha1" {
+			t.Fatalf("Unexpected group version [%s]", resources.GroupVersion)
+		}
+		found := false
+		for _, resource := range resources.APIResources {
+			if resource.Name == "deployments/tap" && resource.Namespaced {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("Expected a deployments/tap resource, got %+v", resources.APIResources)
+		}
+	})
+
+	t.Run("Returns 404 for other paths", func(t *testing.T) {
+		rsp := serve(http.MethodPost, "/apis/tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deploy/web/tap", "front-proxy-client", "alice", nil)
+		if rsp.Code != http.StatusNotFound {
+			t.Fatalf("Expected status 404, got %d", rsp.Code)
+		}
+	})
+
+	t.Run("Rejects the requests without a user", func(t *testing.T) {
+		rsp := serve(http.MethodPost, tapPath, "front-proxy-client", "", tapRequest(t, web))
+		if msg := apiError(t, rsp); msg != "the request has no user" {
+			t.Fatalf("Unexpected error [%s]", msg)
+		}
+	})
+
+	t.Run("Authorizes the taps with SubjectAccessReviews", func(t *testing.T) {
+		reviews = reviews[:0]
+		allowed = false
+		rsp := serve(http.MethodPost, tapPath, "front-proxy-client", "alice", tapRequest(t, web))
+		expected := "alice can't tap deployment/web in the emojivoto namespace"
+		if msg := apiError(t, rsp); msg != expected {
+			t.Fatalf("Expected error [%s], got [%s]", expected, msg)
+		}
+
+		if len(reviews) != 1 {
+			t.Fatalf("Expected 1 SubjectAccessReview, got %d", len(reviews))
+		}
+		spec := reviews[0].Spec
+		expectedAttributes := authV1.ResourceAttributes{
+			Namespace:   "emojivoto",
+			Verb:        "watch",
+			Group:       "tap.linkerd.io",
+			Version:     "v1alpha1",
+			Resource:    "deployments",
+			Subresource: "tap",
+			Name:        "web",
+		}
+		if spec.User != "alice" || strings.Join(spec.Groups, ",") != "system:authenticated,emoji-team" || *spec.ResourceAttributes != expectedAttributes {
+			t.Fatalf("Unexpected SubjectAccessReview %+v", spec)
+		}
+	})
+
+	t.Run("Rejects the taps of other resources than the path's", func(t *testing.T) {
+		allowed = true
+		other := &public.Resource{Namespace: "emojivoto", Type: pkgK8s.Deployment, Name: "voting"}
+		rsp := serve(http.MethodPost, tapPath, "front-proxy-client", "alice", tapRequest(t, other))
+		expected := "the target of the tap request doesn't match its path " + tapPath
+		if msg := apiError(t, rsp); msg != expected {
+			t.Fatalf("Expected error [%s], got [%s]", expected, msg)
+		}
+	})
+
+	t.Run("Taps the authorized resources", func(t *testing.T) {
+		allowed = true
+		rsp := serve(http.MethodPost, tapPath, "front-proxy-client", "alice", tapRequest(t, web))
+		// the deployment doesn't exist, so the tap server fails to tap it
+		if msg := apiError(t, rsp); !strings.Contains(msg, "web") {
+			t.Fatalf("Expected the error of the tap server, got [%s]", msg)
+		}
+	})
+}
+
+func TestNewAPIServer(t *testing.T) {
+	t.Run("Requires the request header configuration of the aggregator", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI("")
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+		if _, err := NewAPIServer("localhost:0", 0, "linkerd", k8sAPI); err == nil {
+			t.Fatal("Expected an error without the request header configuration")
+		}
+	})
+}
@@ -1,8 +1,11 @@
 package tap
 
 import (
+	"crypto/tls"
+
 	pb "github.com/linkerd/linkerd2/controller/gen/controller/tap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 // NewClient creates a client for the control-plane's Tap service.
@@ -14,3 +17,14 @@ func NewClient(addr string) (pb.TapClient, *grpc.ClientConn, error) {
 
 	return pb.NewTapClient(conn), conn, nil
 }
+
+// NewTLSClient creates a client for the Tap service of another controller
+// replica, which it connects to over TLS with tlsConfig.
+func NewTLSClient(addr string, tlsConfig *tls.Config) (pb.TapClient, *grpc.ClientConn, error) {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pb.NewTapClient(conn), conn, nil
+}
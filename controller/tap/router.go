@@ -0,0 +1,370 @@
+package tap
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sync"
+	"time"
+
+	apiUtil "github.com/linkerd/linkerd2/controller/api/util"
+	pb "github.com/linkerd/linkerd2/controller/gen/controller/tap"
+	public "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const defaultResumeBackoff = 1 * time.Second
+
+type (
+	// Router is a client for the Tap services of all the replicas of the
+	// controller, which taps each pod through a single replica, so that the
+	// tap server scales horizontally. The replica of a pod is chosen by
+	// rendezvous hashing, so that it only changes when that replica goes away,
+	// in which case the tap of the pod is resumed on another replica.
+	Router struct {
+		k8sAPI              *k8s.API
+		controllerNamespace string
+		port                uint
+		fallback            pb.TapClient
+		resumeBackoff       time.Duration
+		dial                func(addr string) (pb.TapClient, io.Closer, error)
+
+		sync.Mutex
+		replicas map[string]*replicaClient
+	}
+
+	replicaClient struct {
+		pb.TapClient
+		conn    io.Closer
+		streams int
+	}
+
+	// routedTapStream merges the tap streams of each pod of a TapByResource
+	// request.
+	routedTapStream struct {
+		ctx    context.Context
+		cancel context.CancelFunc
+		events chan *public.TapEvent
+
+		sync.Mutex
+		err error
+	}
+)
+
+// NewRouter returns a Router for the Tap services of the controller replicas
+// in controllerNamespace, which listen on port. The replicas are reached over
+// the pod network, so they're connected to over mutual TLS with tlsConfig,
+// whose certificate their Tap services must authenticate. The requests are
+// sent to fallback when no replica is ready, such as while the controller
+// starts.
+func NewRouter(k8sAPI *k8s.API, controllerNamespace string, port uint, tlsConfig *tls.Config, fallback pb.TapClient) *Router {
+	return &Router{
+		k8sAPI:              k8sAPI,
+		controllerNamespace: controllerNamespace,
+		port:                port,
+		fallback:            fallback,
+		resumeBackoff:       defaultResumeBackoff,
+		dial: func(addr string) (pb.TapClient, io.Closer, error) {
+			return NewTLSClient(addr, tlsConfig)
+		},
+		replicas: make(map[string]*replicaClient),
+	}
+}
+
+// Tap is deprecated, and is sent to the fallback client as is.
+func (r *Router) Tap(ctx context.Context, req *public.TapRequest, opts ...grpc.CallOption) (pb.Tap_TapClient, error) {
+	return r.fallback.Tap(ctx, req, opts...)
+}
+
+// TapByResource taps each pod of the target of req through its replica, and
+// merges their events into a single stream.
+func (r *Router) TapByResource(ctx context.Context, req *public.TapByResourceRequest, opts ...grpc.CallOption) (pb.Tap_TapByResourceClient, error) {
+	pods, err := tapTargetPods(r.k8sAPI, r.controllerNamespace, req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := makeByResourceMatch(req.Match); err != nil {
+		return nil, apiUtil.GRPCError(err)
+	}
+
+	log.Infof("Routing the tap of %d pods for target: %+v", len(pods), *req.Target.Resource)
+
+	// divide the rps evenly between all pods to tap, as the tap server does
+	rpsPerPod := req.MaxRps / float32(len(pods))
+	if rpsPerPod < 1 {
+		rpsPerPod = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	stream := &routedTapStream{
+		ctx:    ctx,
+		cancel: cancel,
+		events: make(chan *public.TapEvent),
+	}
+
+	wg := sync.WaitGroup{}
+	for _, pod := range pods {
+		podReq := &public.TapByResourceRequest{
+			Target: &public.ResourceSelection{
+				Resource: &public.Resource{
+					Namespace: pod.Namespace,
+					Type:      pkgK8s.Pod,
+					Name:      pod.Name,
+				},
+			},
+			Match:  req.Match,
+			MaxRps: rpsPerPod,
+		}
+
+		wg.Add(1)
+		go func(pod *apiv1.Pod) {
+			defer wg.Done()
+			if err := r.tapPod(ctx, pod, podReq, stream.events, opts); err != nil {
+				stream.fail(err)
+			}
+		}(pod)
+	}
+
+	go func() {
+		wg.Wait()
+		close(stream.events)
+	}()
+
+	return stream, nil
+}
+
+// Close closes the connections to the replicas.
+func (r *Router) Close() {
+	r.Lock()
+	defer r.Unlock()
+
+	for addr, replica := range r.replicas {
+		replica.conn.Close()
+		delete(r.replicas, addr)
+	}
+}
+
+// tapPod streams the events of the tap of pod into events, until ctx is done,
+// the pod is deleted, or the tap fails with an error that isn't caused by the
+// loss of its replica.
+func (r *Router) tapPod(ctx context.Context, pod *apiv1.Pod, req *public.TapByResourceRequest, events chan<- *public.TapEvent, opts []grpc.CallOption) error {
+	excluded := map[string]bool{}
+	for {
+		replica, client, release, err := r.clientFor(pod, excluded)
+		if err != nil {
+			return err
+		}
+
+		err = forwardTap(ctx, client, req, events, opts)
+		release()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !isResumable(err) {
+			return err
+		}
+		if !r.podExists(pod) {
+			log.Infof("Stopping the tap of deleted pod %s/%s", pod.Namespace, pod.Name)
+			return nil
+		}
+		if replica != "" {
+			excluded[replica] = true
+		}
+
+		log.Infof("Resuming the tap of pod %s/%s in %s after the loss of replica %q: %v", pod.Namespace, pod.Name, r.resumeBackoff, replica, err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(r.resumeBackoff):
+		}
+	}
+}
+
+func forwardTap(ctx context.Context, client pb.TapClient, req *public.TapByResourceRequest, events chan<- *public.TapEvent, opts []grpc.CallOption) error {
+	rsp, err := client.TapByResource(ctx, req, opts...)
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := rsp.Recv()
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case events <- event:
+		}
+	}
+}
+
+// isResumable returns whether a tap stream ended because its replica went
+// away, rather than because the tap failed.
+func isResumable(err error) bool {
+	if err == io.EOF {
+		return true
+	}
+	return status.Code(err) == codes.Unavailable
+}
+
+// clientFor returns the name and the client of the replica that pod is
+// tapped through, ignoring the excluded replicas unless no other replica is
+// ready, and a function to call once the client is no longer used. The
+// fallback client is returned, with an empty name, when no replica is ready.
+func (r *Router) clientFor(pod *apiv1.Pod, excluded map[string]bool) (string, pb.TapClient, func(), error) {
+	replicas, err := r.readyReplicas()
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	replica := pickReplica(replicas, pod.Namespace+"/"+pod.Name, excluded)
+	if replica == nil {
+		return "", r.fallback, func() {}, nil
+	}
+
+	addr := fmt.Sprintf("%s:%d", replica.Status.PodIP, r.port)
+	r.Lock()
+	defer r.Unlock()
+
+	client, ok := r.replicas[addr]
+	if !ok {
+		tapClient, conn, err := r.dial(addr)
+		if err != nil {
+			return "", nil, nil, status.Errorf(codes.Unavailable, "failed to connect to the tap server of replica %s: %s", replica.Name, err)
+		}
+		client = &replicaClient{TapClient: tapClient, conn: conn}
+		r.replicas[addr] = client
+	}
+
+	client.streams++
+
+	// close the unused connections to the replicas that aren't ready anymore
+	current := map[string]bool{}
+	for _, replica := range replicas {
+		current[fmt.Sprintf("%s:%d", replica.Status.PodIP, r.port)] = true
+	}
+	for addr, replica := range r.replicas {
+		if !current[addr] && replica.streams == 0 {
+			replica.conn.Close()
+			delete(r.replicas, addr)
+		}
+	}
+
+	release := func() {
+		r.Lock()
+		client.streams--
+		r.Unlock()
+	}
+	return replica.Name, client, release, nil
+}
+
+// readyReplicas returns the ready controller pods that aren't terminating.
+func (r *Router) readyReplicas() ([]*apiv1.Pod, error) {
+	selector := labels.Set{pkgK8s.ControllerComponentLabel: "controller"}.AsSelector()
+	pods, err := r.k8sAPI.Pod().Lister().Pods(r.controllerNamespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := []*apiv1.Pod{}
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil && pod.Status.PodIP != "" && isPodReady(pod) {
+			replicas = append(replicas, pod)
+		}
+	}
+	return replicas, nil
+}
+
+func (r *Router) podExists(pod *apiv1.Pod) bool {
+	current, err := r.k8sAPI.Pod().Lister().Pods(pod.Namespace).Get(pod.Name)
+	return err == nil && current.UID == pod.UID && current.DeletionTimestamp == nil
+}
+
+func isPodReady(pod *apiv1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == apiv1.PodReady {
+			return condition.Status == apiv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// pickReplica returns the replica with the highest rendezvous hash score for
+// key, among the replicas that aren't excluded, or among all of them when they
+// all are. It returns nil when there's no replica.
+func pickReplica(replicas []*apiv1.Pod, key string, excluded map[string]bool) *apiv1.Pod {
+	var picked *apiv1.Pod
+	var pickedScore uint64
+	for _, includeExcluded := range []bool{false, true} {
+		for _, replica := range replicas {
+			if excluded[replica.Name] && !includeExcluded {
+				continue
+			}
+			h := fnv.New64a()
+			h.Write([]byte(replica.Name + "\x00" + key))
+			if score := h.Sum64(); picked == nil || score > pickedScore {
+				picked, pickedScore = replica, score
+			}
+		}
+		if picked != nil {
+			return picked
+		}
+	}
+	return nil
+}
+
+// fail records the error of the first tap that failed, and stops the others.
+func (s *routedTapStream) fail(err error) {
+	s.Lock()
+	if s.err == nil {
+		s.err = err
+	}
+	s.Unlock()
+	s.cancel()
+}
+
+func (s *routedTapStream) failure() error {
+	s.Lock()
+	defer s.Unlock()
+	return s.err
+}
+
+// Recv returns the next event of the taps of the pods, io.EOF once they've
+// all ended, or the error of the first tap that failed.
+func (s *routedTapStream) Recv() (*public.TapEvent, error) {
+	select {
+	case event, ok := <-s.events:
+		if ok {
+			return event, nil
+		}
+		if err := s.failure(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	case <-s.ctx.Done():
+		if err := s.failure(); err != nil {
+			return nil, err
+		}
+		return nil, status.Error(codes.Canceled, s.ctx.Err().Error())
+	}
+}
+
+// satisfy the grpc.ClientStream interface
+func (s *routedTapStream) Header() (metadata.MD, error) { return nil, nil }
+func (s *routedTapStream) Trailer() metadata.MD         { return nil }
+func (s *routedTapStream) CloseSend() error             { return nil }
+func (s *routedTapStream) Context() context.Context     { return s.ctx }
+func (s *routedTapStream) SendMsg(interface{}) error    { return nil }
+func (s *routedTapStream) RecvMsg(interface{}) error    { return nil }
@@ -0,0 +1,334 @@
+package tap
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/linkerd/linkerd2/controller/gen/controller/tap"
+	public "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// fakeReplica is the tap server of a controller replica, which sends an event
+// for each request, and then fails the first failures requests with err.
+type fakeReplica struct {
+	name     string
+	failures int
+	err      error
+
+	sync.Mutex
+	tapped []string
+}
+
+func (f *fakeReplica) Tap(ctx context.Context, req *public.TapRequest, _ ...grpc.CallOption) (pb.Tap_TapClient, error) {
+	return nil, status.Error(codes.Unimplemented, "unexpected Tap request")
+}
+
+func (f *fakeReplica) TapByResource(ctx context.Context, req *public.TapByResourceRequest, _ ...grpc.CallOption) (pb.Tap_TapByResourceClient, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.tapped = append(f.tapped, req.Target.Resource.Name)
+	var err error
+	if f.failures > 0 {
+		f.failures--
+		err = f.err
+	}
+	return &fakeTapStream{ctx: ctx, events: []*public.TapEvent{{}}, err: err}, nil
+}
+
+func (f *fakeReplica) requests() []string {
+	f.Lock()
+	defer f.Unlock()
+	return append([]string{}, f.tapped...)
+}
+
+// fakeTapStream sends its events, and then fails with err, or blocks until
+// its context is done when err is nil.
+type fakeTapStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	events []*public.TapEvent
+	err    error
+}
+
+func (s *fakeTapStream) Recv() (*public.TapEvent, error) {
+	if len(s.events) > 0 {
+		event := s.events[0]
+		s.events = s.events[1:]
+		return event, nil
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	<-s.ctx.Done()
+	return nil, status.Error(codes.Canceled, s.ctx.Err().Error())
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+func replicaPod(name, ip string) string {
+	return fmt.Sprintf(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: %s
+  namespace: controller-ns
+  labels:
+    linkerd.io/control-plane-component: controller
+status:
+  phase: Running
+  podIP: %s
+  conditions:
+  - type: Ready
+    status: "True"
+`, name, ip)
+}
+
+func meshedPod(name string) string {
+	return fmt.Sprintf(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: %s
+  namespace: emojivoto
+  labels:
+    linkerd.io/control-plane-ns: controller-ns
+status:
+  phase: Running
+  podIP: 10.1.1.1
+`, name)
+}
+
+func newTestRouter(t *testing.T, replicas []*fakeReplica, fallback pb.TapClient, k8sRes ...string) *Router {
+	k8sAPI, err := k8s.NewFakeAPI("", k8sRes...)
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+	k8sAPI.Sync()
+
+	router := NewRouter(k8sAPI, "controller-ns", 8089, nil, fallback)
+	router.resumeBackoff = 0
+	router.dial = func(addr string) (pb.TapClient, io.Closer, error) {
+		for _, replica := range replicas {
+			if strings.HasPrefix(addr, replica.name+":") {
+				return replica, nopCloser{}, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("unexpected address %s", addr)
+	}
+	return router
+}
+
+func tapRequest(name string) *public.TapByResourceRequest {
+	return &public.TapByResourceRequest{
+		Target: &public.ResourceSelection{
+			Resource: &public.Resource{Namespace: "emojivoto", Type: pkgK8s.Pod, Name: name},
+		},
+		Match: &public.TapByResourceRequest_Match{
+			Match: &public.TapByResourceRequest_Match_All{
+				All: &public.TapByResourceRequest_Match_Seq{},
+			},
+		},
+	}
+}
+
+// receive returns the number of events received from the tap of req before
+// timeout, and the error that ended it, if any.
+func receive(t *testing.T, router *Router, req *public.TapByResourceRequest, timeout time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	stream, err := router.TapByResource(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	events := 0
+	for {
+		_, err := stream.Recv()
+		if err != nil {
+			if status.Code(err) == codes.Canceled {
+				return events, nil
+			}
+			return events, err
+		}
+		events++
+	}
+}
+
+func testPod(name, ip string) *apiv1.Pod {
+	pod := &apiv1.Pod{}
+	pod.Name = name
+	pod.Status.PodIP = ip
+	return pod
+}
+
+func TestPickReplica(t *testing.T) {
+	replicas := []*apiv1.Pod{testPod("a", "10.0.0.1"), testPod("b", "10.0.0.2"), testPod("c", "10.0.0.3")}
+
+	t.Run("picks the same replica for a key", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			key := fmt.Sprintf("emojivoto/pod-%d", i)
+			picked := pickReplica(replicas, key, nil)
+			reversed := []*apiv1.Pod{replicas[2], replicas[1], replicas[0]}
+			if again := pickReplica(reversed, key, nil); again.Name != picked.Name {
+				t.Fatalf("Expected %s to be picked for %s, got %s", picked.Name, key, again.Name)
+			}
+		}
+	})
+
+	t.Run("only moves the keys of a removed replica", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			key := fmt.Sprintf("emojivoto/pod-%d", i)
+			picked := pickReplica(replicas, key, nil)
+			if picked.Name == "b" {
+				continue
+			}
+			if again := pickReplica([]*apiv1.Pod{replicas[0], replicas[2]}, key, nil); again.Name != picked.Name {
+				t.Fatalf("Expected %s to stay on %s, got %s", key, picked.Name, again.Name)
+			}
+		}
+	})
+
+	t.Run("ignores the excluded replicas unless they all are", func(t *testing.T) {
+		picked := pickReplica(replicas, "emojivoto/pod", nil)
+		if again := pickReplica(replicas, "emojivoto/pod", map[string]bool{picked.Name: true}); again.Name == picked.Name {
+			t.Fatalf("Expected another replica than %s to be picked", picked.Name)
+		}
+		all := map[string]bool{"a": true, "b": true, "c": true}
+		if again := pickReplica(replicas, "emojivoto/pod", all); again.Name != picked.Name {
+			t.Fatalf("Expected %s to be picked, got %s", picked.Name, again.Name)
+		}
+	})
+
+	t.Run("returns nil without replicas", func(t *testing.T) {
+		if picked := pickReplica(nil, "emojivoto/pod", nil); picked != nil {
+			t.Fatalf("Expected no replica, got %s", picked.Name)
+		}
+	})
+}
+
+func TestRouterTapByResource(t *testing.T) {
+	t.Run("taps each pod through its replica", func(t *testing.T) {
+		replicas := []*fakeReplica{{name: "10.0.0.1"}, {name: "10.0.0.2"}, {name: "10.0.0.3"}}
+		k8sRes := []string{
+			replicaPod("controller-a", "10.0.0.1"),
+			replicaPod("controller-b", "10.0.0.2"),
+			replicaPod("controller-c", "10.0.0.3"),
+		}
+		for i := 0; i < 6; i++ {
+			k8sRes = append(k8sRes, meshedPod(fmt.Sprintf("pod-%d", i)))
+		}
+		router := newTestRouter(t, replicas, nil, k8sRes...)
+
+		for i := 0; i < 2; i++ {
+			events, err := receive(t, router, tapRequest(""), 100*time.Millisecond)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if events != 6 {
+				t.Fatalf("Expected 6 events, got %d", events)
+			}
+		}
+
+		pods, err := router.readyReplicas()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		for _, replica := range replicas {
+			tapped := replica.requests()
+			sort.Strings(tapped)
+			for j := 0; j < len(tapped); j += 2 {
+				if j+1 >= len(tapped) || tapped[j] != tapped[j+1] {
+					t.Fatalf("Expected replica %s to tap its pods for both requests, got %v", replica.name, tapped)
+				}
+			}
+			for _, pod := range tapped {
+				if picked := pickReplica(pods, "emojivoto/"+pod, nil); picked.Status.PodIP != replica.name {
+					t.Fatalf("Expected %s to be tapped through %s, got %s", pod, picked.Status.PodIP, replica.name)
+				}
+			}
+		}
+	})
+
+	t.Run("resumes the tap on another replica when its replica goes away", func(t *testing.T) {
+		k8sRes := []string{
+			replicaPod("controller-a", "10.0.0.1"),
+			replicaPod("controller-b", "10.0.0.2"),
+			meshedPod("pod"),
+		}
+		replicas := []*fakeReplica{{name: "10.0.0.1"}, {name: "10.0.0.2"}}
+		router := newTestRouter(t, replicas, nil, k8sRes...)
+
+		pods, err := router.readyReplicas()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		picked := pickReplica(pods, "emojivoto/pod", nil)
+		failing, other := replicas[0], replicas[1]
+		if picked.Status.PodIP != failing.name {
+			failing, other = other, failing
+		}
+		failing.failures = 1
+		failing.err = status.Error(codes.Unavailable, "transport is closing")
+
+		events, err := receive(t, router, tapRequest("pod"), 100*time.Millisecond)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if events != 2 {
+			t.Fatalf("Expected 2 events, got %d", events)
+		}
+		if len(failing.requests()) != 1 || len(other.requests()) != 1 {
+			t.Fatalf("Expected the tap to be resumed on %s, got requests %v and %v", other.name, failing.requests(), other.requests())
+		}
+	})
+
+	t.Run("fails when a tap fails", func(t *testing.T) {
+		replicas := []*fakeReplica{{name: "10.0.0.1", failures: 1, err: status.Error(codes.Internal, "tap failed")}}
+		router := newTestRouter(t, replicas, nil, replicaPod("controller-a", "10.0.0.1"), meshedPod("pod"))
+
+		events, err := receive(t, router, tapRequest("pod"), 100*time.Millisecond)
+		if err == nil || err.Error() != "rpc error: code = Internal desc = tap failed" {
+			t.Fatalf("Expected the tap's error, got: %v", err)
+		}
+		if events != 1 {
+			t.Fatalf("Expected 1 event, got %d", events)
+		}
+	})
+
+	t.Run("uses the fallback without ready replicas", func(t *testing.T) {
+		fallback := &fakeReplica{name: "fallback"}
+		router := newTestRouter(t, nil, fallback, meshedPod("pod"))
+
+		events, err := receive(t, router, tapRequest("pod"), 100*time.Millisecond)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if events != 1 || len(fallback.requests()) != 1 {
+			t.Fatalf("Expected the tap to go through the fallback, got %d events", events)
+		}
+	})
+
+	t.Run("returns NotFound without pods", func(t *testing.T) {
+		router := newTestRouter(t, nil, nil, replicaPod("controller-a", "10.0.0.1"))
+
+		_, err := receive(t, router, tapRequest(""), 100*time.Millisecond)
+		if err == nil || err.Error() != "rpc error: code = NotFound desc = no pods found for pod/" {
+			t.Fatalf("Expected a NotFound error, got: %v", err)
+		}
+	})
+}
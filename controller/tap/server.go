@@ -2,6 +2,7 @@ package tap
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
@@ -15,12 +16,14 @@ import (
 	public "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/addr"
+	"github.com/linkerd/linkerd2/pkg/admin"
 	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/pkg/prometheus"
 	"github.com/linkerd/linkerd2/pkg/util"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
@@ -46,38 +49,9 @@ func (s *server) Tap(req *public.TapRequest, stream pb.Tap_TapServer) error {
 }
 
 func (s *server) TapByResource(req *public.TapByResourceRequest, stream pb.Tap_TapByResourceServer) error {
-	if req == nil {
-		return status.Error(codes.InvalidArgument, "TapByResource received nil TapByResourceRequest")
-	}
-	if req.Target == nil {
-		return status.Error(codes.InvalidArgument, "TapByResource received nil target ResourceSelection")
-	}
-	if req.MaxRps == 0.0 {
-		req.MaxRps = defaultMaxRps
-	}
-
-	objects, err := s.k8sAPI.GetObjects(req.Target.Resource.Namespace, req.Target.Resource.Type, req.Target.Resource.Name)
+	pods, err := tapTargetPods(s.k8sAPI, s.controllerNamespace, req)
 	if err != nil {
-		return apiUtil.GRPCError(err)
-	}
-
-	pods := []*apiv1.Pod{}
-	for _, object := range objects {
-		podsFor, err := s.k8sAPI.GetPodsFor(object, false)
-		if err != nil {
-			return apiUtil.GRPCError(err)
-		}
-
-		for _, pod := range podsFor {
-			if pkgK8s.IsMeshed(pod, s.controllerNamespace) {
-				pods = append(pods, pod)
-			}
-		}
-	}
-
-	if len(pods) == 0 {
-		return status.Errorf(codes.NotFound, "no pods found for %s/%s",
-			req.GetTarget().GetResource().GetType(), req.GetTarget().GetResource().GetName())
+		return err
 	}
 
 	log.Infof("Tapping %d pods for target: %+v", len(pods), *req.Target.Resource)
@@ -114,6 +88,45 @@ func (s *server) TapByResource(req *public.TapByResourceRequest, stream pb.Tap_T
 	}
 }
 
+// tapTargetPods returns the meshed pods of the target of req, and sets the
+// default MaxRps of req if it's unset.
+func tapTargetPods(k8sAPI *k8s.API, controllerNamespace string, req *public.TapByResourceRequest) ([]*apiv1.Pod, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "TapByResource received nil TapByResourceRequest")
+	}
+	if req.Target == nil {
+		return nil, status.Error(codes.InvalidArgument, "TapByResource received nil target ResourceSelection")
+	}
+	if req.MaxRps == 0.0 {
+		req.MaxRps = defaultMaxRps
+	}
+
+	objects, err := k8sAPI.GetObjects(req.Target.Resource.Namespace, req.Target.Resource.Type, req.Target.Resource.Name)
+	if err != nil {
+		return nil, apiUtil.GRPCError(err)
+	}
+
+	pods := []*apiv1.Pod{}
+	for _, object := range objects {
+		podsFor, err := k8sAPI.GetPodsFor(object, false)
+		if err != nil {
+			return nil, apiUtil.GRPCError(err)
+		}
+
+		for _, pod := range podsFor {
+			if pkgK8s.IsMeshed(pod, controllerNamespace) {
+				pods = append(pods, pod)
+			}
+		}
+	}
+
+	if len(pods) == 0 {
+		return nil, status.Errorf(codes.NotFound, "no pods found for %s/%s",
+			req.GetTarget().GetResource().GetType(), req.GetTarget().GetResource().GetName())
+	}
+	return pods, nil
+}
+
 func makeByResourceMatch(match *public.TapByResourceRequest_Match) (*proxy.ObserveRequest_Match, error) {
 	// TODO: for now assume it's always a single, flat `All` match list
 	seq := match.GetAll()
@@ -463,22 +476,55 @@ func NewServer(
 	controllerNamespace string,
 	k8sAPI *k8s.API,
 ) (*grpc.Server, net.Listener, error) {
-	k8sAPI.Pod().Informer().AddIndexers(cache.Indexers{podIPIndex: indexPodByIP})
+	return newGrpcServer(addr, tapPort, controllerNamespace, k8sAPI)
+}
+
+// NewTLSServer creates a gRPC Tap server that serves the other controller
+// replicas over TLS with tlsConfig, which must authenticate them, since the
+// server is reachable from the pod network.
+func NewTLSServer(
+	addr string,
+	tlsConfig *tls.Config,
+	tapPort uint,
+	controllerNamespace string,
+	k8sAPI *k8s.API,
+) (*grpc.Server, net.Listener, error) {
+	return newGrpcServer(addr, tapPort, controllerNamespace, k8sAPI, grpc.Creds(credentials.NewTLS(tlsConfig)))
+}
 
+func newGrpcServer(
+	addr string,
+	tapPort uint,
+	controllerNamespace string,
+	k8sAPI *k8s.API,
+	opts ...grpc.ServerOption,
+) (*grpc.Server, net.Listener, error) {
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	s := prometheus.NewGrpcServer()
-	srv := server{
+	s := prometheus.NewGrpcServer(opts...)
+	pb.RegisterTapServer(s, newServer(tapPort, controllerNamespace, k8sAPI))
+	admin.RegisterHealthServer(s, "linkerd2.controller.tap.Tap")
+
+	return s, lis, nil
+}
+
+// newServer returns a tap server that taps the pods of k8sAPI. The pods are
+// indexed by IP once, so that the gRPC server and the API server can share the
+// informers of k8sAPI.
+func newServer(tapPort uint, controllerNamespace string, k8sAPI *k8s.API) *server {
+	indexer := k8sAPI.Pod().Informer().GetIndexer()
+	if _, ok := indexer.GetIndexers()[podIPIndex]; !ok {
+		k8sAPI.Pod().Informer().AddIndexers(cache.Indexers{podIPIndex: indexPodByIP})
+	}
+
+	return &server{
 		tapPort:             tapPort,
 		k8sAPI:              k8sAPI,
 		controllerNamespace: controllerNamespace,
 	}
-	pb.RegisterTapServer(s, &srv)
-
-	return s, lis, nil
 }
 
 func indexPodByIP(obj interface{}) ([]string, error) {
@@ -1,15 +1,40 @@
 package admin
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
 )
 
+// pprofPathPrefix is the path under which the profiling endpoints are served,
+// when enabled.
+const pprofPathPrefix = "/debug/pprof/"
+
+// pprofWriteTimeout bounds the responses of the admin server when profiling is
+// enabled, instead of the usual 10 seconds, so that CPU profiles and execution
+// traces can be captured for up to a minute.
+const pprofWriteTimeout = 90 * time.Second
+
+var pprofEnabled bool
+
 type handler struct {
-	promHandler http.Handler
+	promHandler  http.Handler
+	pprofHandler http.Handler
+}
+
+// EnablePprof serves the net/http/pprof profiling endpoints on the admin
+// servers started afterwards, under /debug/pprof/. It fails if the binary was
+// built with the nopprof tag.
+func EnablePprof() error {
+	if !pprofAvailable {
+		return errors.New("profiling isn't available in this build")
+	}
+	pprofEnabled = true
+	return nil
 }
 
 // StartServer starts an admin server listening on a given address.
@@ -19,12 +44,18 @@ func StartServer(addr string) {
 	h := &handler{
 		promHandler: promhttp.Handler(),
 	}
+	writeTimeout := 10 * time.Second
+	if pprofEnabled {
+		log.Infof("serving the profiling endpoints on %s%s", addr, pprofPathPrefix)
+		h.pprofHandler = pprofHandler()
+		writeTimeout = pprofWriteTimeout
+	}
 
 	s := &http.Server{
 		Addr:         addr,
 		Handler:      h,
 		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		WriteTimeout: writeTimeout,
 	}
 
 	log.Fatal(s.ListenAndServe())
@@ -38,7 +69,15 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		h.servePing(w, req)
 	case "/ready":
 		h.serveReady(w, req)
+	case "/state":
+		h.serveState(w, req)
+	case "/log-level":
+		h.serveLogLevel(w, req)
 	default:
+		if h.pprofHandler != nil && strings.HasPrefix(req.URL.Path, pprofPathPrefix) {
+			h.pprofHandler.ServeHTTP(w, req)
+			return
+		}
 		http.NotFound(w, req)
 	}
 }
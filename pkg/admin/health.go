@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"net"
+
+	"github.com/linkerd/linkerd2/pkg/prometheus"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// RegisterHealthServer registers the standard grpc.health.v1 Health service on
+// the given gRPC server. The server as a whole, and each of the given services,
+// are reported as serving.
+func RegisterHealthServer(s *grpc.Server, services ...string) *health.Server {
+	h := health.NewServer()
+	for _, service := range services {
+		h.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(s, h)
+	return h
+}
+
+// StartHealthServer starts a gRPC server listening on a given address, that
+// only serves the grpc.health.v1 Health service. It's used by components that
+// don't otherwise serve gRPC.
+func StartHealthServer(addr string, services ...string) {
+	log.Infof("starting gRPC health server on %s", addr)
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	s := prometheus.NewGrpcServer()
+	RegisterHealthServer(s, services...)
+
+	log.Fatal(s.Serve(lis))
+}
@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxLogLevelBody bounds the size of the body of a log level change.
+const maxLogLevelBody = 64
+
+// serveLogLevel reports the current log level on GET, and changes it to the
+// level in the body of a PUT, e.g. "debug", so that the logs of a running
+// component can be captured without restarting it.
+func (h *handler) serveLogLevel(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxLogLevelBody))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		level, err := log.ParseLevel(strings.TrimSpace(string(body)))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if level != log.GetLevel() {
+			log.Infof("changing log level from %s to %s", log.GetLevel(), level)
+			log.SetLevel(level)
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, fmt.Sprintf("method %s not allowed", req.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	fmt.Fprintln(w, log.GetLevel())
+}
@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func TestServeLogLevel(t *testing.T) {
+	defer log.SetLevel(log.GetLevel())
+	log.SetLevel(log.InfoLevel)
+
+	testCases := []struct {
+		method string
+		body   string
+		code   int
+		level  log.Level
+	}{
+		{method: "GET", code: http.StatusOK, level: log.InfoLevel},
+		{method: "PUT", body: "debug\n", code: http.StatusOK, level: log.DebugLevel},
+		{method: "PUT", body: "verbose", code: http.StatusBadRequest, level: log.DebugLevel},
+		{method: "POST", body: "info", code: http.StatusMethodNotAllowed, level: log.DebugLevel},
+		{method: "PUT", body: "WARN", code: http.StatusOK, level: log.WarnLevel},
+	}
+
+	for _, tc := range testCases {
+		rsp := httptest.NewRecorder()
+		(&handler{}).ServeHTTP(rsp, httptest.NewRequest(tc.method, "/log-level", strings.NewReader(tc.body)))
+
+		if rsp.Code != tc.code {
+			t.Fatalf("Expected status %d for %s %q, got %d", tc.code, tc.method, tc.body, rsp.Code)
+		}
+		if log.GetLevel() != tc.level {
+			t.Fatalf("Expected log level %s after %s %q, got %s", tc.level, tc.method, tc.body, log.GetLevel())
+		}
+		if tc.code == http.StatusOK && rsp.Body.String() != tc.level.String()+"\n" {
+			t.Fatalf("Expected the log level in the response, got %q", rsp.Body.String())
+		}
+	}
+}
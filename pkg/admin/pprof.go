@@ -0,0 +1,23 @@
+// +build !nopprof
+
+package admin
+
+import (
+	"net/http"
+	"net/http/pprof"
+)
+
+// pprofAvailable is whether the profiling endpoints are built in. Building
+// with the nopprof tag leaves them out.
+const pprofAvailable = true
+
+// pprofHandler serves the net/http/pprof endpoints under /debug/pprof/.
+func pprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(pprofPathPrefix, pprof.Index)
+	mux.HandleFunc(pprofPathPrefix+"cmdline", pprof.Cmdline)
+	mux.HandleFunc(pprofPathPrefix+"profile", pprof.Profile)
+	mux.HandleFunc(pprofPathPrefix+"symbol", pprof.Symbol)
+	mux.HandleFunc(pprofPathPrefix+"trace", pprof.Trace)
+	return mux
+}
@@ -0,0 +1,13 @@
+// +build nopprof
+
+package admin
+
+import "net/http"
+
+// pprofAvailable is whether the profiling endpoints are built in. Building
+// with the nopprof tag leaves them out.
+const pprofAvailable = false
+
+func pprofHandler() http.Handler {
+	return nil
+}
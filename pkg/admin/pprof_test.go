@@ -0,0 +1,31 @@
+// +build !nopprof
+
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServePprof(t *testing.T) {
+	testCases := []struct {
+		handler *handler
+		path    string
+		code    int
+	}{
+		{handler: &handler{}, path: "/debug/pprof/cmdline", code: http.StatusNotFound},
+		{handler: &handler{pprofHandler: pprofHandler()}, path: "/debug/pprof/cmdline", code: http.StatusOK},
+		{handler: &handler{pprofHandler: pprofHandler()}, path: "/debug/pprof/heap", code: http.StatusOK},
+		{handler: &handler{pprofHandler: pprofHandler()}, path: "/debug/pprofs", code: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		rsp := httptest.NewRecorder()
+		tc.handler.ServeHTTP(rsp, httptest.NewRequest("GET", tc.path, nil))
+
+		if rsp.Code != tc.code {
+			t.Fatalf("Expected status %d for %s, got %d", tc.code, tc.path, rsp.Code)
+		}
+	}
+}
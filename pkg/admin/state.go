@@ -0,0 +1,47 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// StateFunc returns a snapshot of the internal state of a component, which
+// must marshal to JSON.
+type StateFunc func() interface{}
+
+var (
+	stateFuncs = map[string]StateFunc{}
+	stateMutex sync.RWMutex
+)
+
+// RegisterState registers state to report the internal state of the named
+// component on the /state endpoint of the admin server, for debugging. A
+// component registered again replaces the previous one.
+func RegisterState(name string, state StateFunc) {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+	stateFuncs[name] = state
+}
+
+// states returns the snapshots of all the registered components, by name.
+func states() map[string]interface{} {
+	stateMutex.RLock()
+	defer stateMutex.RUnlock()
+
+	snapshots := make(map[string]interface{}, len(stateFuncs))
+	for name, state := range stateFuncs {
+		snapshots[name] = state()
+	}
+	return snapshots
+}
+
+func (h *handler) serveState(w http.ResponseWriter, req *http.Request) {
+	body, err := json.MarshalIndent(states(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(body, '\n'))
+}
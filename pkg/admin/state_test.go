@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServeState(t *testing.T) {
+	RegisterState("destination", func() interface{} {
+		return map[string]int{"watches": 1}
+	})
+	RegisterState("destination", func() interface{} {
+		return map[string]int{"watches": 2}
+	})
+	defer func() { stateFuncs = map[string]StateFunc{} }()
+
+	rsp := httptest.NewRecorder()
+	(&handler{}).ServeHTTP(rsp, httptest.NewRequest("GET", "/state", nil))
+
+	if rsp.Code != http.StatusOK {
+		t.Fatalf("Unexpected status: %d", rsp.Code)
+	}
+	if ct := rsp.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Unexpected content type: %s", ct)
+	}
+	expected := `{
+  "destination": {
+    "watches": 2
+  }
+}
+`
+	if rsp.Body.String() != expected {
+		t.Fatalf("Expected:\n%s\nGot:\n%s", expected, rsp.Body.String())
+	}
+}
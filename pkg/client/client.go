@@ -0,0 +1,374 @@
+// Package client provides a client for the public API of a Linkerd control
+// plane, for tools that consume its data outside of the linkerd CLI.
+//
+// A Client connects to the public API the same way as the CLI, through the
+// service proxy of the Kubernetes API server with the credentials of a
+// kubeconfig, and retries the requests that fail, such as while the control
+// plane is restarting:
+//
+//	c, err := client.New(client.NewOptions())
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close()
+//
+//	rows, err := c.Stat(ctx, util.StatsSummaryRequestParams{
+//		StatsBaseRequestParams: util.StatsBaseRequestParams{
+//			Namespace:    "emojivoto",
+//			ResourceType: k8s.Deployment,
+//		},
+//	})
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	"github.com/linkerd/linkerd2/controller/api/util"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	pkgTls "github.com/linkerd/linkerd2/pkg/tls"
+	log "github.com/sirupsen/logrus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DefaultControlPlaneNamespace is the namespace that `linkerd install`
+	// installs the control plane in by default.
+	DefaultControlPlaneNamespace = "linkerd"
+
+	controllerDeployment = "linkerd-controller"
+	publicAPIPort        = 8085
+	portForwardTimeout   = 30 * time.Second
+)
+
+// Options configures how a Client connects to the public API.
+type Options struct {
+	// ControlPlaneNamespace is the namespace of the control plane.
+	ControlPlaneNamespace string
+
+	// KubeConfig is the path of the kubeconfig file; when empty, the file is
+	// found the same way as kubectl does.
+	KubeConfig string
+
+	// KubeContext is the kubeconfig context to use, instead of the current
+	// one.
+	KubeContext string
+
+	// Impersonate is the user that the Kubernetes requests are made as,
+	// instead of the kubeconfig's user.
+	Impersonate string
+
+	// ImpersonateGroup is the list of groups that the Kubernetes requests are
+	// made as, along with Impersonate.
+	ImpersonateGroup []string
+
+	// APIAddr is the host:port of the public API, to connect to it directly
+	// instead of through Kubernetes, such as from inside the cluster.
+	APIAddr string
+
+	// PortForward connects to the public API through a port-forward to a
+	// controller pod, instead of through the service proxy of the Kubernetes
+	// API server, which some clusters don't allow.
+	PortForward bool
+
+	// MutualTLS connects to the TLS port of the public API through a
+	// port-forward to a controller pod, authenticating with the certificate
+	// that the CA issues to the API client identity, which the kubeconfig's
+	// user must be allowed to read. The control plane must be installed with
+	// --api-auth=tls.
+	MutualTLS bool
+
+	// Retries is the number of times a request that failed is retried.
+	Retries int
+
+	// RetryBackoff is the delay before the first retry, which doubles with
+	// each retry.
+	RetryBackoff time.Duration
+}
+
+// NewOptions returns the default Options, which connect to the control plane
+// in DefaultControlPlaneNamespace with the current kubeconfig context.
+func NewOptions() *Options {
+	return &Options{
+		ControlPlaneNamespace: DefaultControlPlaneNamespace,
+		KubeConfig:            "",
+		KubeContext:           "",
+		Impersonate:           "",
+		ImpersonateGroup:      nil,
+		APIAddr:               "",
+		PortForward:           false,
+		MutualTLS:             false,
+		Retries:               3,
+		RetryBackoff:          500 * time.Millisecond,
+	}
+}
+
+// Client is a client for the public API of a Linkerd control plane.
+type Client struct {
+	api         pb.ApiClient
+	options     Options
+	portForward *k8s.PortForward
+}
+
+// New returns a Client that connects to the public API as configured by
+// options. It must be closed once it's no longer used.
+func New(options *Options) (*Client, error) {
+	opts := *options
+	if opts.ControlPlaneNamespace == "" {
+		opts.ControlPlaneNamespace = DefaultControlPlaneNamespace
+	}
+
+	if opts.APIAddr != "" {
+		api, err := public.NewInternalClient(opts.ControlPlaneNamespace, opts.APIAddr)
+		if err != nil {
+			return nil, err
+		}
+		return newClient(api, opts), nil
+	}
+
+	if opts.PortForward || opts.MutualTLS {
+		return newPortForwardClient(opts)
+	}
+
+	kubeAPI, err := k8s.NewAPI(opts.KubeConfig, opts.KubeContext, opts.Impersonate, opts.ImpersonateGroup)
+	if err != nil {
+		return nil, err
+	}
+	api, err := public.NewExternalClient(opts.ControlPlaneNamespace, kubeAPI)
+	if err != nil {
+		return nil, err
+	}
+	return newClient(api, opts), nil
+}
+
+func newPortForwardClient(opts Options) (*Client, error) {
+	remotePort := publicAPIPort
+	var tlsConfig *tls.Config
+	if opts.MutualTLS {
+		var err error
+		tlsConfig, err = mutualTLSConfig(opts)
+		if err != nil {
+			return nil, err
+		}
+		remotePort = k8s.PublicAPITLSPort
+	}
+
+	pf, err := k8s.NewPortForward(opts.KubeConfig, opts.KubeContext, opts.Impersonate, opts.ImpersonateGroup, opts.ControlPlaneNamespace, controllerDeployment, 0, remotePort, false)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := make(chan error, 1)
+	go func() {
+		failed <- pf.Run()
+	}()
+
+	select {
+	case <-pf.Ready():
+	case err := <-failed:
+		if err == nil {
+			err = errors.New("the port-forward closed")
+		}
+		return nil, fmt.Errorf("Failed to port-forward to the %s deployment: %s", controllerDeployment, err)
+	case <-time.After(portForwardTimeout):
+		pf.Stop()
+		return nil, fmt.Errorf("Timed out port-forwarding to the %s deployment", controllerDeployment)
+	}
+
+	addr, err := url.Parse(pf.URLFor(""))
+	if err != nil {
+		pf.Stop()
+		return nil, err
+	}
+	var api pb.ApiClient
+	if tlsConfig != nil {
+		api, err = public.NewMutualTLSClient(opts.ControlPlaneNamespace, addr.Host, tlsConfig)
+	} else {
+		api, err = public.NewInternalClient(opts.ControlPlaneNamespace, addr.Host)
+	}
+	if err != nil {
+		pf.Stop()
+		return nil, err
+	}
+
+	c := newClient(api, opts)
+	c.portForward = pf
+	return c, nil
+}
+
+// mutualTLSConfig returns the TLS configuration of the clients of the TLS port
+// of the public API, with the certificate of the API client identity and the
+// trust anchors of the control plane namespace.
+func mutualTLSConfig(opts Options) (*tls.Config, error) {
+	kubeAPI, err := k8s.NewAPI(opts.KubeConfig, opts.KubeContext, opts.Impersonate, opts.ImpersonateGroup)
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+	if err != nil {
+		return nil, err
+	}
+	return readMutualTLSConfig(clientset, opts.ControlPlaneNamespace)
+}
+
+func readMutualTLSConfig(clientset kubernetes.Interface, controlPlaneNamespace string) (*tls.Config, error) {
+	identity := k8s.TLSIdentity{ControllerNamespace: controlPlaneNamespace}
+	clientIdentity := identity.ToAPIClientIdentity()
+
+	secret, err := clientset.CoreV1().Secrets(controlPlaneNamespace).Get(clientIdentity.ToSecretName(), metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("The %s namespace has no %s secret with the certificate of the API client identity; the control plane must be installed with --api-auth=tls", controlPlaneNamespace, clientIdentity.ToSecretName())
+	}
+	if err != nil {
+		return nil, err
+	}
+	configMap, err := clientset.CoreV1().ConfigMaps(controlPlaneNamespace).Get(k8s.TLSTrustAnchorConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return pkgTls.NewMutualTLSClientConfig(
+		secret.Data[k8s.TLSCertPEMFileName],
+		secret.Data[k8s.TLSPrivateKeyPEMFileName],
+		[]byte(configMap.Data[k8s.TLSTrustAnchorFileName]),
+		identity.ToPublicAPIIdentity().ToDNSName(),
+	)
+}
+
+func newClient(api pb.ApiClient, opts Options) *Client {
+	return &Client{api: api, options: opts}
+}
+
+// API returns the underlying public API client, for the requests that the
+// Client has no helpers for. Its requests aren't retried.
+func (c *Client) API() pb.ApiClient {
+	return c.api
+}
+
+// Close releases the resources of the Client, such as its port-forward.
+func (c *Client) Close() {
+	if c.portForward != nil {
+		c.portForward.Stop()
+		c.portForward = nil
+	}
+}
+
+// Version returns the version of the control plane.
+func (c *Client) Version(ctx context.Context) (*pb.VersionInfo, error) {
+	var rsp *pb.VersionInfo
+	err := c.retry(ctx, func() (err error) {
+		rsp, err = c.api.Version(ctx, &pb.Empty{})
+		return err
+	})
+	return rsp, err
+}
+
+// Stat returns the traffic stats of the resources selected by params.
+func (c *Client) Stat(ctx context.Context, params util.StatsSummaryRequestParams) ([]*pb.StatTable_PodGroup_Row, error) {
+	req, err := util.BuildStatSummaryRequest(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var rsp *pb.StatSummaryResponse
+	err = c.retry(ctx, func() (err error) {
+		rsp, err = c.api.StatSummary(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if e := rsp.GetError(); e != nil {
+		return nil, errors.New(e.Error)
+	}
+
+	rows := []*pb.StatTable_PodGroup_Row{}
+	for _, table := range rsp.GetOk().GetStatTables() {
+		rows = append(rows, table.GetPodGroup().GetRows()...)
+	}
+	return rows, nil
+}
+
+// Edges returns the connections between the resources selected by params,
+// and the identities they use.
+func (c *Client) Edges(ctx context.Context, params util.StatsBaseRequestParams) ([]*pb.Edge, error) {
+	req, err := util.BuildEdgesRequest(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var rsp *pb.EdgesResponse
+	err = c.retry(ctx, func() (err error) {
+		rsp, err = c.api.Edges(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if e := rsp.GetError(); e != nil {
+		return nil, errors.New(e.Error)
+	}
+	return rsp.GetOk().GetEdges(), nil
+}
+
+// Tap streams the requests to the resources selected by params to handle,
+// until ctx is done, the stream ends, or handle returns an error. Only
+// opening the stream is retried, since the events seen before a failure
+// can't be told apart from the ones after it.
+func (c *Client) Tap(ctx context.Context, params util.TapRequestParams, handle func(*pb.TapEvent) error) error {
+	req, err := util.BuildTapByResourceRequest(params)
+	if err != nil {
+		return err
+	}
+
+	var stream pb.Api_TapByResourceClient
+	err = c.retry(ctx, func() (err error) {
+		stream, err = c.api.TapByResource(ctx, req)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := handle(event); err != nil {
+			return err
+		}
+	}
+}
+
+// retry calls request until it succeeds, it has been retried
+// options.Retries times, or ctx is done.
+func (c *Client) retry(ctx context.Context, request func() error) error {
+	backoff := c.options.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := request()
+		if err == nil || attempt >= c.options.Retries || ctx.Err() != nil {
+			return err
+		}
+
+		log.Debugf("Retrying public API request in %s after error: %s", backoff, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
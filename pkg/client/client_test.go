@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	"github.com/linkerd/linkerd2/controller/api/util"
+	"github.com/linkerd/linkerd2/controller/ca"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"google.golang.org/grpc"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// flakyAPIClient fails the first failures requests of the mock.
+type flakyAPIClient struct {
+	*public.MockAPIClient
+	failures int
+	requests int
+}
+
+func (c *flakyAPIClient) fail() error {
+	c.requests++
+	if c.requests <= c.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (c *flakyAPIClient) StatSummary(ctx context.Context, in *pb.StatSummaryRequest, opts ...grpc.CallOption) (*pb.StatSummaryResponse, error) {
+	if err := c.fail(); err != nil {
+		return nil, err
+	}
+	return c.MockAPIClient.StatSummary(ctx, in, opts...)
+}
+
+func (c *flakyAPIClient) TapByResource(ctx context.Context, in *pb.TapByResourceRequest, opts ...grpc.CallOption) (pb.Api_TapByResourceClient, error) {
+	if err := c.fail(); err != nil {
+		return nil, err
+	}
+	return c.MockAPIClient.TapByResource(ctx, in, opts...)
+}
+
+func newTestClient(api pb.ApiClient, retries int) *Client {
+	opts := NewOptions()
+	opts.Retries = retries
+	opts.RetryBackoff = 0
+	return newClient(api, *opts)
+}
+
+func TestStat(t *testing.T) {
+	row := &pb.StatTable_PodGroup_Row{
+		Resource: &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: "web"},
+	}
+	mock := &public.MockAPIClient{
+		StatSummaryResponseToReturn: &pb.StatSummaryResponse{
+			Response: &pb.StatSummaryResponse_Ok_{
+				Ok: &pb.StatSummaryResponse_Ok{
+					StatTables: []*pb.StatTable{
+						{Table: &pb.StatTable_PodGroup_{PodGroup: &pb.StatTable_PodGroup{Rows: []*pb.StatTable_PodGroup_Row{row}}}},
+					},
+				},
+			},
+		},
+	}
+	params := util.StatsSummaryRequestParams{
+		StatsBaseRequestParams: util.StatsBaseRequestParams{Namespace: "emojivoto", ResourceType: k8s.Deployment},
+	}
+
+	t.Run("retries failed requests", func(t *testing.T) {
+		api := &flakyAPIClient{MockAPIClient: mock, failures: 2}
+		rows, err := newTestClient(api, 2).Stat(context.Background(), params)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(rows, []*pb.StatTable_PodGroup_Row{row}) {
+			t.Fatalf("Unexpected rows: %v", rows)
+		}
+		if api.requests != 3 {
+			t.Fatalf("Expected 3 requests, got %d", api.requests)
+		}
+	})
+
+	t.Run("gives up after the retries", func(t *testing.T) {
+		api := &flakyAPIClient{MockAPIClient: mock, failures: 3}
+		_, err := newTestClient(api, 2).Stat(context.Background(), params)
+		if err == nil || err.Error() != "connection refused" {
+			t.Fatalf("Expected a connection error, got: %v", err)
+		}
+		if api.requests != 3 {
+			t.Fatalf("Expected 3 requests, got %d", api.requests)
+		}
+	})
+
+	t.Run("doesn't retry once the context is done", func(t *testing.T) {
+		api := &flakyAPIClient{MockAPIClient: mock, failures: 3}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := newTestClient(api, 2).Stat(ctx, params)
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+		if api.requests != 1 {
+			t.Fatalf("Expected 1 request, got %d", api.requests)
+		}
+	})
+
+	t.Run("returns the API's errors", func(t *testing.T) {
+		api := &public.MockAPIClient{
+			StatSummaryResponseToReturn: &pb.StatSummaryResponse{
+				Response: &pb.StatSummaryResponse_Error{Error: &pb.ResourceError{Error: "no such deployment"}},
+			},
+		}
+		_, err := newTestClient(api, 0).Stat(context.Background(), params)
+		if err == nil || err.Error() != "no such deployment" {
+			t.Fatalf("Expected the API error, got: %v", err)
+		}
+	})
+
+	t.Run("rejects invalid params", func(t *testing.T) {
+		_, err := newTestClient(mock, 0).Stat(context.Background(), util.StatsSummaryRequestParams{})
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+	})
+}
+
+func TestEdges(t *testing.T) {
+	edge := &pb.Edge{
+		Src: &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: "web"},
+		Dst: &pb.Resource{Namespace: "emojivoto", Type: k8s.Deployment, Name: "voting"},
+	}
+	api := &public.MockAPIClient{
+		EdgesResponseToReturn: &pb.EdgesResponse{
+			Response: &pb.EdgesResponse_Ok_{Ok: &pb.EdgesResponse_Ok{Edges: []*pb.Edge{edge}}},
+		},
+	}
+
+	edges, err := newTestClient(api, 0).Edges(context.Background(), util.StatsBaseRequestParams{Namespace: "emojivoto", ResourceType: k8s.Deployment})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(edges, []*pb.Edge{edge}) {
+		t.Fatalf("Unexpected edges: %v", edges)
+	}
+}
+
+func TestTap(t *testing.T) {
+	events := []pb.TapEvent{
+		{ProxyDirection: pb.TapEvent_INBOUND},
+		{ProxyDirection: pb.TapEvent_OUTBOUND},
+	}
+	params := util.TapRequestParams{Resource: "deployment/web", Namespace: "emojivoto"}
+
+	t.Run("streams events and retries opening the stream", func(t *testing.T) {
+		api := &flakyAPIClient{
+			MockAPIClient: &public.MockAPIClient{
+				APITapByResourceClientToReturn: &public.MockAPITapByResourceClient{TapEventsToReturn: events},
+			},
+			failures: 1,
+		}
+
+		received := []pb.TapEvent_ProxyDirection{}
+		err := newTestClient(api, 1).Tap(context.Background(), params, func(event *pb.TapEvent) error {
+			received = append(received, event.ProxyDirection)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := []pb.TapEvent_ProxyDirection{pb.TapEvent_INBOUND, pb.TapEvent_OUTBOUND}
+		if !reflect.DeepEqual(received, expected) {
+			t.Fatalf("Expected events %v, got %v", expected, received)
+		}
+	})
+
+	t.Run("stops when the handler fails", func(t *testing.T) {
+		api := &public.MockAPIClient{
+			APITapByResourceClientToReturn: &public.MockAPITapByResourceClient{TapEventsToReturn: events},
+		}
+
+		calls := 0
+		err := newTestClient(api, 0).Tap(context.Background(), params, func(*pb.TapEvent) error {
+			calls++
+			return errors.New("done")
+		})
+		if err == nil || err.Error() != "done" || calls != 1 {
+			t.Fatalf("Expected the handler's error after 1 call, got %v after %d", err, calls)
+		}
+	})
+}
+
+func TestReadMutualTLSConfig(t *testing.T) {
+	identity := k8s.TLSIdentity{ControllerNamespace: "linkerd"}
+	clientIdentity := identity.ToAPIClientIdentity()
+
+	if _, err := readMutualTLSConfig(fake.NewSimpleClientset(), "linkerd"); err == nil {
+		t.Fatal("Expected an error without the secret of the API client identity")
+	}
+
+	issuer, err := ca.NewCA()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	creds, err := issuer.IssueEndEntityCertificate(clientIdentity.ToDNSName(), 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	clientset := fake.NewSimpleClientset(
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: clientIdentity.ToSecretName(), Namespace: "linkerd"},
+			Data: map[string][]byte{
+				k8s.TLSCertPEMFileName:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: creds.Certificate}),
+				k8s.TLSPrivateKeyPEMFileName: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: creds.PrivateKey}),
+			},
+		},
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: k8s.TLSTrustAnchorConfigMapName, Namespace: "linkerd"},
+			Data:       map[string]string{k8s.TLSTrustAnchorFileName: issuer.TrustAnchorPEM()},
+		},
+	)
+
+	config, err := readMutualTLSConfig(clientset, "linkerd")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if config.ServerName != identity.ToPublicAPIIdentity().ToDNSName() {
+		t.Fatalf("Expected the public API identity as server name, got %s", config.ServerName)
+	}
+	if len(config.Certificates) != 1 || config.RootCAs == nil {
+		t.Fatalf("Expected the client certificate and the trust anchors, got %+v", config)
+	}
+}
@@ -0,0 +1,197 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// The types of the events that the control plane posts.
+const (
+	// ProxyInjected is posted by the proxy injector when it injects the proxy
+	// into a pod.
+	ProxyInjected = "ProxyInjected"
+
+	// CertificateRotated is posted by the CA when it reloads a new issuer
+	// certificate, and starts reissuing the certificates of the mesh with it.
+	CertificateRotated = "CertificateRotated"
+
+	// PolicyDenialSpike is posted by the proxy injector when it denies more
+	// admission requests within a window than its threshold allows.
+	PolicyDenialSpike = "PolicyDenialSpike"
+
+	// ControlPlaneUpgraded is posted by the public API when all the
+	// components of the control plane run a new version.
+	ControlPlaneUpgraded = "ControlPlaneUpgraded"
+)
+
+const (
+	// queueSize is how many events wait to be posted before new ones are
+	// dropped, so that an unavailable webhook can't block the control plane.
+	queueSize = 100
+
+	postTimeout  = 10 * time.Second
+	postAttempts = 3
+	retryBackoff = time.Second
+)
+
+// Event is the JSON body that's posted to the webhook.
+type Event struct {
+	Type                  string            `json:"type"`
+	Time                  time.Time         `json:"time"`
+	Component             string            `json:"component"`
+	ControlPlaneNamespace string            `json:"controlPlaneNamespace"`
+	Namespace             string            `json:"namespace,omitempty"`
+	Attributes            map[string]string `json:"attributes,omitempty"`
+}
+
+// Sink receives the events of a control plane component.
+type Sink interface {
+	// Post queues the event for delivery, without blocking.
+	Post(event Event)
+}
+
+// NopSink discards all the events.
+type NopSink struct{}
+
+// Post discards the event.
+func (NopSink) Post(Event) {}
+
+// WebhookSink posts the events of a control plane component as JSON to a
+// webhook, such as a ChatOps integration, in the order they're posted.
+type WebhookSink struct {
+	url                 string
+	component           string
+	controllerNamespace string
+	client              *http.Client
+	backoff             time.Duration
+
+	queue  chan Event
+	events *prometheus.CounterVec
+}
+
+// NewSink returns a WebhookSink for url, or a NopSink if url is empty. The
+// events are delivered in the background until stopCh is closed.
+func NewSink(webhookURL, component, controllerNamespace string, stopCh <-chan struct{}) (Sink, error) {
+	if webhookURL == "" {
+		return NopSink{}, nil
+	}
+
+	sink, err := NewWebhookSink(webhookURL, component, controllerNamespace)
+	if err != nil {
+		return nil, err
+	}
+	if err := sink.RegisterMetrics(prometheus.DefaultRegisterer); err != nil {
+		return nil, err
+	}
+	go sink.Run(stopCh)
+	return sink, nil
+}
+
+// NewWebhookSink initializes a WebhookSink for the events of the component.
+// It doesn't deliver them until Run is called.
+func NewWebhookSink(webhookURL, component, controllerNamespace string) (*WebhookSink, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid event webhook URL %s: must be an absolute http or https URL", webhookURL)
+	}
+
+	return &WebhookSink{
+		url:                 webhookURL,
+		component:           component,
+		controllerNamespace: controllerNamespace,
+		client:              &http.Client{Timeout: postTimeout},
+		backoff:             retryBackoff,
+		queue:               make(chan Event, queueSize),
+		events: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "webhook_events_total",
+				Help: "Total number of events posted to the event webhook, by type and result: delivered, failed or dropped.",
+			},
+			[]string{"type", "result"},
+		),
+	}, nil
+}
+
+// RegisterMetrics registers the WebhookSink's metrics with the given
+// registerer.
+func (s *WebhookSink) RegisterMetrics(registerer prometheus.Registerer) error {
+	return registerer.Register(s.events)
+}
+
+// Post queues the event, or drops it if the queue is full.
+func (s *WebhookSink) Post(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	event.Component = s.component
+	event.ControlPlaneNamespace = s.controllerNamespace
+
+	select {
+	case s.queue <- event:
+	default:
+		log.Warnf("dropped %s event: the event webhook is too slow", event.Type)
+		s.events.WithLabelValues(event.Type, "dropped").Inc()
+	}
+}
+
+// Run delivers the queued events until stopCh is closed.
+func (s *WebhookSink) Run(stopCh <-chan struct{}) {
+	for {
+		select {
+		case event := <-s.queue:
+			if err := s.deliver(event, stopCh); err != nil {
+				log.Warnf("failed to post %s event to the event webhook: %s", event.Type, err)
+				s.events.WithLabelValues(event.Type, "failed").Inc()
+				continue
+			}
+			s.events.WithLabelValues(event.Type, "delivered").Inc()
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// deliver posts the event, retrying with an exponential backoff until it's
+// accepted or all the attempts failed.
+func (s *WebhookSink) deliver(event Event, stopCh <-chan struct{}) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	backoff := s.backoff
+	for attempt := 1; ; attempt++ {
+		err = s.post(body)
+		if err == nil || attempt == postAttempts {
+			return err
+		}
+		log.Debugf("retrying %s event in %s: %s", event.Type, backoff, err)
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-stopCh:
+			return err
+		}
+	}
+}
+
+func (s *WebhookSink) post(body []byte) error {
+	rsp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %s", rsp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package events
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestWebhookSink(t *testing.T) {
+	t.Run("Posts the events as JSON, retrying the failed posts", func(t *testing.T) {
+		received := make(chan Event, 2)
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Unexpected content type: %s", ct)
+			}
+			var event Event
+			if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			received <- event
+		}))
+		defer server.Close()
+
+		sink, err := NewWebhookSink(server.URL, "proxy-injector", "linkerd")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		sink.backoff = time.Millisecond
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+		go sink.Run(stopCh)
+
+		at := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+		sink.Post(Event{
+			Type:       ProxyInjected,
+			Time:       at,
+			Namespace:  "emojivoto",
+			Attributes: map[string]string{"kind": "deployment", "name": "web"},
+		})
+
+		select {
+		case event := <-received:
+			expected := Event{
+				Type:                  ProxyInjected,
+				Time:                  at,
+				Component:             "proxy-injector",
+				ControlPlaneNamespace: "linkerd",
+				Namespace:             "emojivoto",
+				Attributes:            map[string]string{"kind": "deployment", "name": "web"},
+			}
+			if !reflect.DeepEqual(event, expected) {
+				t.Fatalf("Expected event %+v, got %+v", expected, event)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for the event")
+		}
+	})
+
+	t.Run("Drops the events when the queue is full", func(t *testing.T) {
+		sink, err := NewWebhookSink("http://webhook.example.com", "ca", "linkerd")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		registry := prometheus.NewRegistry()
+		if err := sink.RegisterMetrics(registry); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		for i := 0; i < queueSize+2; i++ {
+			sink.Post(Event{Type: CertificateRotated})
+		}
+
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(families) != 1 || len(families[0].GetMetric()) != 1 {
+			t.Fatalf("Unexpected metrics: %v", families)
+		}
+		if dropped := families[0].GetMetric()[0].GetCounter().GetValue(); dropped != 2 {
+			t.Fatalf("Expected 2 dropped events, got %v", dropped)
+		}
+	})
+
+	t.Run("Rejects invalid URLs", func(t *testing.T) {
+		for _, webhookURL := range []string{"webhook.example.com", "ftp://webhook.example.com", "http://"} {
+			if _, err := NewWebhookSink(webhookURL, "ca", "linkerd"); err == nil {
+				t.Fatalf("Expected an error for %s", webhookURL)
+			}
+		}
+	})
+}
+
+func TestNewSink(t *testing.T) {
+	sink, err := NewSink("", "ca", "linkerd", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if _, ok := sink.(NopSink); !ok {
+		t.Fatalf("Expected a NopSink without a URL, got %T", sink)
+	}
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// SpikeDetector counts occurrences, such as denials, over a sliding window, and
+// reports a spike once when their count reaches a threshold. It reports the
+// next spike only after the count has fallen back below the threshold.
+type SpikeDetector struct {
+	threshold int
+	window    time.Duration
+
+	times   []time.Time
+	spiking bool
+	mu      sync.Mutex
+}
+
+// NewSpikeDetector returns a SpikeDetector for threshold occurrences within
+// window.
+func NewSpikeDetector(threshold int, window time.Duration) *SpikeDetector {
+	return &SpikeDetector{
+		threshold: threshold,
+		window:    window,
+	}
+}
+
+// Observe records an occurrence at now. It returns the number of occurrences
+// within the window, and true if they just reached the threshold.
+func (d *SpikeDetector) Observe(now time.Time) (int, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	start := now.Add(-d.window)
+	expired := 0
+	for expired < len(d.times) && !d.times[expired].After(start) {
+		expired++
+	}
+	d.times = append(d.times[expired:], now)
+
+	count := len(d.times)
+	if count < d.threshold {
+		d.spiking = false
+		return count, false
+	}
+	if d.spiking {
+		return count, false
+	}
+	d.spiking = true
+	return count, true
+}
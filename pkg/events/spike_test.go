@@ -0,0 +1,36 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpikeDetector(t *testing.T) {
+	detector := NewSpikeDetector(3, time.Minute)
+	start := time.Now()
+
+	observations := []struct {
+		offset time.Duration
+		count  int
+		spike  bool
+	}{
+		{0, 1, false},
+		{10 * time.Second, 2, false},
+		{20 * time.Second, 3, true},
+		// the spike is only reported once
+		{30 * time.Second, 4, false},
+		// the first observations leave the window
+		{75 * time.Second, 3, false},
+		// the count falls below the threshold, which rearms the detector
+		{150 * time.Second, 1, false},
+		{155 * time.Second, 2, false},
+		{160 * time.Second, 3, true},
+	}
+
+	for i, o := range observations {
+		count, spike := detector.Observe(start.Add(o.offset))
+		if count != o.count || spike != o.spike {
+			t.Fatalf("Observation %d: expected (%d, %t), got (%d, %t)", i, o.count, o.spike, count, spike)
+		}
+	}
+}
@@ -3,12 +3,28 @@ package flags
 import (
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/linkerd/linkerd2/pkg/admin"
 	"github.com/linkerd/linkerd2/pkg/version"
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	// PlainLogFormat is the default log format, for humans.
+	PlainLogFormat = "plain"
+
+	// JSONLogFormat logs an object per line, for structured log pipelines.
+	JSONLogFormat = "json"
+
+	// namespaceFile holds the namespace of the pod, in each container that
+	// mounts a service account token.
+	namespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
 // ConfigureAndParse adds flags that are common to all go processes, and
 // overrides the default flag for glog logging, which we can't disable. This
 // func calls flag.Parse(), so it should be called after all other flags have
@@ -19,11 +35,21 @@ func ConfigureAndParse() {
 
 	logLevel := flag.String("log-level", log.InfoLevel.String(),
 		"log level, must be one of: panic, fatal, error, warn, info, debug")
+	logFormat := flag.String("log-format", PlainLogFormat,
+		"log format, must be one of: plain, json")
 	printVersion := flag.Bool("version", false, "print version and exit")
+	enablePprof := flag.Bool("enable-pprof", false,
+		"serve the net/http/pprof profiling endpoints on the admin server, under /debug/pprof/")
 
 	flag.Parse()
 
 	setLogLevel(*logLevel)
+	setLogFormat(*logFormat)
+	if *enablePprof {
+		if err := admin.EnablePprof(); err != nil {
+			log.Fatalf("invalid enable-pprof: %s", err)
+		}
+	}
 	maybePrintVersionAndExit(*printVersion)
 }
 
@@ -35,6 +61,54 @@ func setLogLevel(logLevel string) {
 	log.SetLevel(level)
 }
 
+func setLogFormat(logFormat string) {
+	switch logFormat {
+	case PlainLogFormat:
+	case JSONLogFormat:
+		log.SetFormatter(&log.JSONFormatter{})
+		log.AddHook(newFieldsHook(processFields()))
+	default:
+		log.Fatalf("invalid log-format: %s", logFormat)
+	}
+}
+
+// processFields returns the fields that identify the process in each JSON log
+// entry: the component, which is the name of the binary, and the namespace and
+// name of its pod, when running in Kubernetes.
+func processFields() log.Fields {
+	fields := log.Fields{"component": filepath.Base(os.Args[0])}
+	if ns, err := ioutil.ReadFile(namespaceFile); err == nil {
+		fields["ns"] = strings.TrimSpace(string(ns))
+		// the hostname of a pod is its name
+		if pod, err := os.Hostname(); err == nil {
+			fields["pod"] = pod
+		}
+	}
+	return fields
+}
+
+// fieldsHook adds fields to all the log entries that don't already have them.
+type fieldsHook struct {
+	fields log.Fields
+}
+
+func newFieldsHook(fields log.Fields) *fieldsHook {
+	return &fieldsHook{fields: fields}
+}
+
+func (h *fieldsHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h *fieldsHook) Fire(entry *log.Entry) error {
+	for key, value := range h.fields {
+		if _, ok := entry.Data[key]; !ok {
+			entry.Data[key] = value
+		}
+	}
+	return nil
+}
+
 func maybePrintVersionAndExit(printVersion bool) {
 	if printVersion {
 		fmt.Println(version.Version)
@@ -0,0 +1,36 @@
+package flags
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func TestFieldsHook(t *testing.T) {
+	output := &bytes.Buffer{}
+	logger := log.New()
+	logger.Out = output
+	logger.Formatter = &log.JSONFormatter{}
+	logger.Hooks.Add(newFieldsHook(log.Fields{"component": "proxy-api", "ns": "linkerd", "pod": "linkerd-controller-1"}))
+
+	logger.WithField("pod", "emojivoto/web-1").Info("endpoints updated")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(output.Bytes(), &entry); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := map[string]string{
+		"component": "proxy-api",
+		"ns":        "linkerd",
+		"pod":       "emojivoto/web-1",
+		"msg":       "endpoints updated",
+		"level":     "info",
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("Expected %s to be %q, got %v", key, value, entry[key])
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package healthcheck
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	arv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// podSecurityEnforceLabel is the label of the namespaces whose pods are
+	// restricted by the Pod Security admission to one of its levels.
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+	podSecurityPrivileged   = "privileged"
+)
+
+// proxyInitCapabilities are the capabilities that the linkerd-init container
+// requires to set up the iptables rules of the meshed pods.
+var proxyInitCapabilities = []v1.Capability{"NET_ADMIN", "NET_RAW"}
+
+// checkPodSecurityPolicies returns an error if the cluster has
+// PodSecurityPolicies, and none of them grants the capabilities of the
+// linkerd-init container and lets it run as root. The cluster may not enforce
+// its policies, which can't be told from the API, so this is a warning.
+func (hc *HealthChecker) checkPodSecurityPolicies() error {
+	clientset, err := hc.kubeClientset()
+	if err != nil {
+		return err
+	}
+
+	policies, err := clientset.PolicyV1beta1().PodSecurityPolicies().List(meta_v1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	if len(policies.Items) == 0 {
+		return nil
+	}
+
+	for _, policy := range policies.Items {
+		if pspAllowsProxyInit(policy.Spec) {
+			return nil
+		}
+	}
+	return fmt.Errorf("None of the %d PodSecurityPolicies grants the %s capabilities and lets the linkerd-init containers run as root; add a policy that does, and let the service accounts of the meshed pods use it", len(policies.Items), capabilityNames(proxyInitCapabilities))
+}
+
+// pspAllowsProxyInit returns true if a PodSecurityPolicy lets the
+// linkerd-init container add its capabilities and run as root.
+func pspAllowsProxyInit(spec policyv1beta1.PodSecurityPolicySpec) bool {
+	if spec.RunAsUser.Rule == policyv1beta1.RunAsUserStrategyMustRunAsNonRoot {
+		return false
+	}
+	for _, capability := range proxyInitCapabilities {
+		allowed := false
+		for _, c := range spec.AllowedCapabilities {
+			// "*" allows all the capabilities
+			if c == capability || c == "*" {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// checkPodSecurityAdmission returns an error if the control plane namespace,
+// or any other namespace, is restricted by the Pod Security admission to a
+// level that forbids the capabilities of the linkerd-init container.
+func (hc *HealthChecker) checkPodSecurityAdmission() error {
+	clientset, err := hc.kubeClientset()
+	if err != nil {
+		return err
+	}
+
+	namespaces, err := clientset.CoreV1().Namespaces().List(meta_v1.ListOptions{LabelSelector: podSecurityEnforceLabel})
+	if err != nil {
+		return err
+	}
+
+	restricted := []string{}
+	for _, ns := range namespaces.Items {
+		level := ns.Labels[podSecurityEnforceLabel]
+		if level == podSecurityPrivileged {
+			continue
+		}
+		if ns.Name == hc.ControlPlaneNamespace {
+			return fmt.Errorf("The \"%s\" namespace enforces the %s Pod Security level, which forbids the %s capabilities of the linkerd-init containers; label it with %s=%s", ns.Name, level, capabilityNames(proxyInitCapabilities), podSecurityEnforceLabel, podSecurityPrivileged)
+		}
+		restricted = append(restricted, ns.Name)
+	}
+	if len(restricted) == 0 {
+		return nil
+	}
+
+	sort.Strings(restricted)
+	return fmt.Errorf("The pods of the namespaces %s can't be meshed, since they enforce a Pod Security level that forbids the %s capabilities of the linkerd-init containers; label them with %s=%s to mesh them", strings.Join(restricted, ", "), capabilityNames(proxyInitCapabilities), podSecurityEnforceLabel, podSecurityPrivileged)
+}
+
+// serviceWebhook is an admission webhook of the cluster that's served by a
+// service.
+type serviceWebhook struct {
+	name          string
+	service       *arv1beta1.ServiceReference
+	failurePolicy *arv1beta1.FailurePolicyType
+	rules         []arv1beta1.RuleWithOperations
+}
+
+func (hc *HealthChecker) serviceWebhooks() ([]serviceWebhook, error) {
+	clientset, err := hc.kubeClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := []serviceWebhook{}
+	add := func(configuration string, hooks []arv1beta1.Webhook) {
+		for _, hook := range hooks {
+			if hook.ClientConfig.Service == nil {
+				continue
+			}
+			webhooks = append(webhooks, serviceWebhook{
+				name:          fmt.Sprintf("%s/%s", configuration, hook.Name),
+				service:       hook.ClientConfig.Service,
+				failurePolicy: hook.FailurePolicy,
+				rules:         hook.Rules,
+			})
+		}
+	}
+
+	mutating, err := clientset.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().List(meta_v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, configuration := range mutating.Items {
+		if configuration.Name != k8s.ProxyInjectorWebhookConfig {
+			add(configuration.Name, configuration.Webhooks)
+		}
+	}
+
+	validating, err := clientset.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().List(meta_v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, configuration := range validating.Items {
+		add(configuration.Name, configuration.Webhooks)
+	}
+
+	return webhooks, nil
+}
+
+// checkBlockingWebhooks returns an error if the cluster has admission
+// webhooks that fail closed on the creation of pods, but whose service has
+// no ready endpoints, since they would reject the pods of the control plane.
+func (hc *HealthChecker) checkBlockingWebhooks() error {
+	webhooks, err := hc.serviceWebhooks()
+	if err != nil {
+		return err
+	}
+
+	blocking := []string{}
+	for _, webhook := range webhooks {
+		if webhook.failurePolicy == nil || *webhook.failurePolicy != arv1beta1.Fail || !rulesMatchPodCreation(webhook.rules) {
+			continue
+		}
+		endpoints, err := hc.clientset.CoreV1().Endpoints(webhook.service.Namespace).Get(webhook.service.Name, meta_v1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err != nil || !hasReadyAddresses(endpoints) {
+			blocking = append(blocking, fmt.Sprintf("%s (service %s/%s)", webhook.name, webhook.service.Namespace, webhook.service.Name))
+		}
+	}
+	if len(blocking) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("The admission webhooks %s reject the creation of pods, since their service has no ready endpoints and their failurePolicy is Fail; fix or remove them before installing", strings.Join(blocking, ", "))
+}
+
+func rulesMatchPodCreation(rules []arv1beta1.RuleWithOperations) bool {
+	for _, rule := range rules {
+		if containsOperation(rule.Operations, arv1beta1.Create) &&
+			containsOrWildcard(rule.APIGroups, "") &&
+			containsOrWildcard(rule.Resources, "pods") {
+			return true
+		}
+	}
+	return false
+}
+
+func containsOperation(operations []arv1beta1.OperationType, operation arv1beta1.OperationType) bool {
+	for _, op := range operations {
+		if op == operation || op == arv1beta1.OperationAll {
+			return true
+		}
+	}
+	return false
+}
+
+func hasReadyAddresses(endpoints *v1.Endpoints) bool {
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkWebhookConnectivity probes the services of the admission webhooks of
+// the cluster through the Kubernetes API server proxy, to find out whether
+// the API server can reach the webhooks on the pod network, as it must reach
+// the proxy injector. On the clusters where it can't, such as those with an
+// overlay network that the API server isn't part of, the webhooks must run
+// with hostNetwork. There's nothing to probe if the cluster has no webhooks
+// with ready endpoints.
+func (hc *HealthChecker) checkWebhookConnectivity() error {
+	webhooks, err := hc.serviceWebhooks()
+	if err != nil {
+		return err
+	}
+
+	unreachable := []string{}
+	for _, webhook := range webhooks {
+		endpoints, err := hc.clientset.CoreV1().Endpoints(webhook.service.Namespace).Get(webhook.service.Name, meta_v1.GetOptions{})
+		if err != nil || !hasReadyAddresses(endpoints) {
+			continue
+		}
+
+		path := "/"
+		if webhook.service.Path != nil {
+			path = *webhook.service.Path
+		}
+		_, err = hc.clientset.CoreV1().Services(webhook.service.Namespace).ProxyGet("https", webhook.service.Name, "443", path, nil).DoRaw()
+		if err == nil {
+			return nil
+		}
+		// any response of the webhook itself, even an error, shows that it
+		// can be reached
+		if !apierrors.IsServiceUnavailable(err) && !apierrors.IsTimeout(err) && !apierrors.IsServerTimeout(err) {
+			return nil
+		}
+		unreachable = append(unreachable, webhook.name)
+	}
+	if len(unreachable) == 0 {
+		return nil
+	}
+
+	hint := "let the API server reach the pod network, or run the proxy injector with hostNetwork"
+	if allowed, err := hc.hostNetworkAllowed(); err == nil && !allowed {
+		hint = "let the API server reach the pod network; the proxy injector can't run with hostNetwork instead, since none of the PodSecurityPolicies allows it"
+	}
+	return fmt.Errorf("The Kubernetes API server can't reach the admission webhooks %s, so it may not reach the proxy injector either; %s", strings.Join(unreachable, ", "), hint)
+}
+
+// hostNetworkAllowed returns false if the cluster has PodSecurityPolicies,
+// and none of them allows the pods on the host network.
+func (hc *HealthChecker) hostNetworkAllowed() (bool, error) {
+	policies, err := hc.clientset.PolicyV1beta1().PodSecurityPolicies().List(meta_v1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+	if len(policies.Items) == 0 {
+		return true, nil
+	}
+	for _, policy := range policies.Items {
+		if policy.Spec.HostNetwork {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func capabilityNames(capabilities []v1.Capability) string {
+	names := make([]string, len(capabilities))
+	for i, c := range capabilities {
+		names[i] = string(c)
+	}
+	return strings.Join(names, " and ")
+}
@@ -0,0 +1,155 @@
+package healthcheck
+
+import (
+	"testing"
+
+	arv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckPodSecurityPolicies(t *testing.T) {
+	restricted := &policyv1beta1.PodSecurityPolicy{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "restricted"},
+		Spec: policyv1beta1.PodSecurityPolicySpec{
+			RunAsUser: policyv1beta1.RunAsUserStrategyOptions{Rule: policyv1beta1.RunAsUserStrategyMustRunAsNonRoot},
+		},
+	}
+	netAdmin := &policyv1beta1.PodSecurityPolicy{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "linkerd-init"},
+		Spec: policyv1beta1.PodSecurityPolicySpec{
+			AllowedCapabilities: []v1.Capability{"NET_ADMIN", "NET_RAW"},
+			RunAsUser:           policyv1beta1.RunAsUserStrategyOptions{Rule: policyv1beta1.RunAsUserStrategyRunAsAny},
+		},
+	}
+
+	testCases := []struct {
+		title    string
+		objects  []runtime.Object
+		expected string
+	}{
+		{"passes without policies", nil, ""},
+		{"passes with a policy that grants the capabilities", []runtime.Object{restricted, netAdmin}, ""},
+		{
+			"fails without a policy that grants the capabilities",
+			[]runtime.Object{restricted},
+			"None of the 1 PodSecurityPolicies grants the NET_ADMIN and NET_RAW capabilities and lets the linkerd-init containers run as root; add a policy that does, and let the service accounts of the meshed pods use it",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			hc := NewHealthChecker([]CategoryID{LinkerdPreInstallCapabilityChecks}, &Options{ControlPlaneNamespace: "linkerd"})
+			hc.clientset = k8sfake.NewSimpleClientset(tc.objects...)
+
+			err := hc.checkPodSecurityPolicies()
+			if tc.expected == "" && err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if tc.expected != "" && (err == nil || err.Error() != tc.expected) {
+				t.Fatalf("Expected error [%s], got [%v]", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestCheckPodSecurityAdmission(t *testing.T) {
+	namespace := func(name, level string) runtime.Object {
+		return &v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: name, Labels: map[string]string{podSecurityEnforceLabel: level}}}
+	}
+
+	testCases := []struct {
+		title    string
+		objects  []runtime.Object
+		expected string
+	}{
+		{"passes with privileged namespaces", []runtime.Object{namespace("linkerd", "privileged")}, ""},
+		{
+			"fails when the control plane namespace is restricted",
+			[]runtime.Object{namespace("linkerd", "baseline")},
+			"The \"linkerd\" namespace enforces the baseline Pod Security level, which forbids the NET_ADMIN and NET_RAW capabilities of the linkerd-init containers; label it with pod-security.kubernetes.io/enforce=privileged",
+		},
+		{
+			"fails when other namespaces are restricted",
+			[]runtime.Object{namespace("payments", "restricted"), namespace("emojivoto", "baseline"), &v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "books"}}},
+			"The pods of the namespaces emojivoto, payments can't be meshed, since they enforce a Pod Security level that forbids the NET_ADMIN and NET_RAW capabilities of the linkerd-init containers; label them with pod-security.kubernetes.io/enforce=privileged to mesh them",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			hc := NewHealthChecker([]CategoryID{LinkerdPreInstallCapabilityChecks}, &Options{ControlPlaneNamespace: "linkerd"})
+			hc.clientset = k8sfake.NewSimpleClientset(tc.objects...)
+
+			err := hc.checkPodSecurityAdmission()
+			if tc.expected == "" && err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if tc.expected != "" && (err == nil || err.Error() != tc.expected) {
+				t.Fatalf("Expected error [%s], got [%v]", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestCheckBlockingWebhooks(t *testing.T) {
+	fail, ignore := arv1beta1.Fail, arv1beta1.Ignore
+	webhookConfig := func(failurePolicy *arv1beta1.FailurePolicyType, resource string) runtime.Object {
+		return &arv1beta1.ValidatingWebhookConfiguration{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "policy-agent"},
+			Webhooks: []arv1beta1.Webhook{
+				{
+					Name:          "validate.policy-agent.io",
+					ClientConfig:  arv1beta1.WebhookClientConfig{Service: &arv1beta1.ServiceReference{Namespace: "policy", Name: "policy-agent"}},
+					FailurePolicy: failurePolicy,
+					Rules: []arv1beta1.RuleWithOperations{
+						{
+							Operations: []arv1beta1.OperationType{arv1beta1.OperationAll},
+							Rule:       arv1beta1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{resource}},
+						},
+					},
+				},
+			},
+		}
+	}
+	readyEndpoints := &v1.Endpoints{
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: "policy", Name: "policy-agent"},
+		Subsets:    []v1.EndpointSubset{{Addresses: []v1.EndpointAddress{{IP: "10.0.0.1"}}}},
+	}
+
+	testCases := []struct {
+		title    string
+		objects  []runtime.Object
+		expected string
+	}{
+		{"passes when the webhook fails open", []runtime.Object{webhookConfig(&ignore, "pods")}, ""},
+		{"passes when the webhook doesn't intercept pods", []runtime.Object{webhookConfig(&fail, "configmaps")}, ""},
+		{"passes when the webhook has ready endpoints", []runtime.Object{webhookConfig(&fail, "pods"), readyEndpoints}, ""},
+		{
+			"fails when the webhook has no ready endpoints",
+			[]runtime.Object{webhookConfig(&fail, "pods")},
+			"The admission webhooks policy-agent/validate.policy-agent.io (service policy/policy-agent) reject the creation of pods, since their service has no ready endpoints and their failurePolicy is Fail; fix or remove them before installing",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			hc := NewHealthChecker([]CategoryID{LinkerdPreInstallCapabilityChecks}, &Options{ControlPlaneNamespace: "linkerd"})
+			hc.clientset = k8sfake.NewSimpleClientset(tc.objects...)
+
+			err := hc.checkBlockingWebhooks()
+			if tc.expected == "" && err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if tc.expected != "" && (err == nil || err.Error() != tc.expected) {
+				t.Fatalf("Expected error [%s], got [%v]", tc.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package healthcheck
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/tls"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// defaultProxyMetricsPort is the proxy's admin port, used when the proxy
+	// container doesn't name it.
+	defaultProxyMetricsPort = 4191
+
+	// proxyControlResponsesMetric counts the responses that the proxy receives
+	// from the control plane, such as destination lookups.
+	proxyControlResponsesMetric = "control_response_total"
+)
+
+// getMeshedPods returns the running pods of the data plane namespace (or of
+// all namespaces, if it isn't set) that are in the control plane's mesh.
+func (hc *HealthChecker) getMeshedPods() ([]v1.Pod, error) {
+	var pods []v1.Pod
+	var err error
+	if hc.DataPlaneNamespace != "" {
+		pods, err = hc.kubeAPI.GetPodsByNamespace(hc.httpClient, hc.DataPlaneNamespace)
+	} else {
+		pods, err = hc.kubeAPI.GetAllPods(hc.httpClient)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	meshed := []v1.Pod{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == v1.PodRunning && k8s.IsMeshed(pod, hc.ControlPlaneNamespace) && proxyContainer(pod) != nil {
+			meshed = append(meshed, *pod)
+		}
+	}
+	return meshed, nil
+}
+
+// checkDataPlaneProxyMetrics scrapes the admin endpoint of each meshed pod's
+// proxy through the Kubernetes API, and keeps the metrics for the checks that
+// inspect them.
+func (hc *HealthChecker) checkDataPlaneProxyMetrics() error {
+	pods, err := hc.getMeshedPods()
+	if err != nil {
+		return err
+	}
+
+	hc.dataPlaneMetrics = make(map[string]map[string]*dto.MetricFamily)
+	for i := range pods {
+		pod := &pods[i]
+		proxy := proxyContainer(pod)
+		body, err := hc.kubeAPI.GetPodPort(hc.httpClient, pod.Namespace, pod.Name, proxyMetricsPort(proxy), "/metrics")
+		if err != nil {
+			return fmt.Errorf("The \"%s\" pod's proxy admin endpoint is unreachable: %s", pod.Name, err)
+		}
+		families, err := ParseProxyMetrics(body)
+		if err != nil {
+			return fmt.Errorf("The \"%s\" pod's proxy served invalid metrics: %s", pod.Name, err)
+		}
+		hc.dataPlaneMetrics[pod.Namespace+"/"+pod.Name] = families
+	}
+	return nil
+}
+
+// checkDataPlaneProxyCertificates verifies the certificate that each meshed
+// pod's proxy serves TLS with, if it has one, against the trust anchors of
+// the control plane.
+func (hc *HealthChecker) checkDataPlaneProxyCertificates() error {
+	pods, err := hc.getMeshedPods()
+	if err != nil {
+		return err
+	}
+
+	if hc.clientset == nil {
+		hc.clientset, err = kubernetes.NewForConfig(hc.kubeAPI.Config)
+		if err != nil {
+			return err
+		}
+	}
+
+	var roots *x509.CertPool
+	for i := range pods {
+		pod := &pods[i]
+		secretName := proxyCertificateSecret(pod)
+		if secretName == "" {
+			continue
+		}
+
+		if roots == nil {
+			roots, err = hc.getTrustAnchors()
+			if err != nil {
+				return err
+			}
+		}
+
+		secret, err := hc.clientset.CoreV1().Secrets(pod.Namespace).Get(secretName, meta_v1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("The \"%s\" pod's certificate is unavailable: %s", pod.Name, err)
+		}
+		err = validateProxyCertificate(secret.Data[k8s.TLSCertFileName], proxyEnv(proxyContainer(pod), "LINKERD2_PROXY_TLS_POD_IDENTITY"), roots, time.Now())
+		if err != nil {
+			return fmt.Errorf("The \"%s\" pod's certificate is invalid: %s", pod.Name, err)
+		}
+	}
+	return nil
+}
+
+func (hc *HealthChecker) getTrustAnchors() (*x509.CertPool, error) {
+	configMap, err := hc.clientset.CoreV1().ConfigMaps(hc.ControlPlaneNamespace).Get(k8s.TLSTrustAnchorConfigMapName, meta_v1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("The trust anchors are unavailable: %s", err)
+	}
+	certs, err := tls.DecodePEMCerts([]byte(configMap.Data[k8s.TLSTrustAnchorFileName]))
+	if err != nil {
+		return nil, fmt.Errorf("The trust anchors are invalid: %s", err)
+	}
+
+	roots := x509.NewCertPool()
+	for _, cert := range certs {
+		roots.AddCert(cert)
+	}
+	return roots, nil
+}
+
+// validateDataPlaneProxyInit checks that the init container of each pod set
+// up the iptables rules that route its traffic through the proxy.
+func validateDataPlaneProxyInit(pods []v1.Pod) error {
+	for _, pod := range pods {
+		var status *v1.ContainerStatus
+		for i := range pod.Status.InitContainerStatuses {
+			if pod.Status.InitContainerStatuses[i].Name == k8s.InitContainerName {
+				status = &pod.Status.InitContainerStatuses[i]
+			}
+		}
+
+		if status == nil {
+			return fmt.Errorf("The \"%s\" pod has no \"%s\" container, so its traffic isn't routed through the proxy",
+				pod.Name, k8s.InitContainerName)
+		}
+
+		terminated := status.State.Terminated
+		if terminated == nil {
+			return fmt.Errorf("The \"%s\" container in the \"%s\" pod hasn't completed", k8s.InitContainerName, pod.Name)
+		}
+		if terminated.ExitCode != 0 {
+			return fmt.Errorf("The \"%s\" container in the \"%s\" pod failed to set up iptables rules (exit code %d)",
+				k8s.InitContainerName, pod.Name, terminated.ExitCode)
+		}
+	}
+
+	return nil
+}
+
+// checkWindowsPods lists the nodes and the pods of the data plane namespace
+// (or of all namespaces, if it isn't set), to report the pods that can't be
+// meshed because they run on Windows nodes.
+func (hc *HealthChecker) checkWindowsPods() error {
+	clientset, err := hc.kubeClientset()
+	if err != nil {
+		return err
+	}
+
+	nodes, err := clientset.CoreV1().Nodes().List(meta_v1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	pods, err := clientset.CoreV1().Pods(hc.DataPlaneNamespace).List(meta_v1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	return validateWindowsPods(nodes.Items, pods.Items)
+}
+
+// validateWindowsPods checks that none of the pods that haven't terminated
+// runs on, or can only be scheduled on, a Windows node. The proxy only runs
+// on Linux, so the injector skips these pods.
+func validateWindowsPods(nodes []v1.Node, pods []v1.Pod) error {
+	windowsNodes := map[string]bool{}
+	for i := range nodes {
+		if k8s.IsWindowsNode(&nodes[i]) {
+			windowsNodes[nodes[i].Name] = true
+		}
+	}
+
+	windowsPods := []string{}
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if windowsNodes[pod.Spec.NodeName] || k8s.RequiresWindowsNodes(&pod.Spec) {
+			windowsPods = append(windowsPods, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		}
+	}
+
+	if len(windowsPods) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d pods can't be meshed because they run on Windows nodes: %s",
+		len(windowsPods), strings.Join(windowsPods, ", "))
+}
+
+// validateDataPlaneDestination checks that none of the proxies whose metrics
+// were scraped has only failed to get responses from the control plane. The
+// proxies that haven't looked up any destinations yet are skipped.
+func validateDataPlaneDestination(metrics map[string]map[string]*dto.MetricFamily) error {
+	for pod, families := range metrics {
+		family, ok := families[proxyControlResponsesMetric]
+		if !ok {
+			continue
+		}
+
+		var successes, failures float64
+		for _, metric := range family.GetMetric() {
+			classification := ""
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "classification" {
+					classification = label.GetValue()
+				}
+			}
+			switch classification {
+			case "success":
+				successes += metric.GetCounter().GetValue()
+			case "failure":
+				failures += metric.GetCounter().GetValue()
+			}
+		}
+
+		if successes == 0 && failures > 0 {
+			return fmt.Errorf("The \"%s\" pod's proxy can't reach the destination service (%.0f failed requests)", pod, failures)
+		}
+	}
+
+	return nil
+}
+
+// validateProxyCertificate checks that the DER certificate is valid for
+// identity at the given time, and was issued by one of the roots.
+func validateProxyCertificate(certDER []byte, identity string, roots *x509.CertPool, now time.Time) error {
+	if len(certDER) == 0 {
+		return fmt.Errorf("no certificate has been issued yet")
+	}
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return err
+	}
+
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName:     identity,
+		Roots:       roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	return err
+}
+
+// ParseProxyMetrics parses the metrics that a proxy or a controller serves in
+// the Prometheus text format, keyed by metric family name.
+func ParseProxyMetrics(body []byte) (map[string]*dto.MetricFamily, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if len(families) == 0 {
+		return nil, fmt.Errorf("no metrics found")
+	}
+	return families, nil
+}
+
+func proxyContainer(pod *v1.Pod) *v1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == k8s.ProxyContainerName {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+func proxyMetricsPort(proxy *v1.Container) int32 {
+	for _, port := range proxy.Ports {
+		if port.Name == "linkerd-metrics" {
+			return port.ContainerPort
+		}
+	}
+	return defaultProxyMetricsPort
+}
+
+func proxyEnv(proxy *v1.Container, name string) string {
+	for _, env := range proxy.Env {
+		if env.Name == name {
+			return env.Value
+		}
+	}
+	return ""
+}
+
+// proxyCertificateSecret returns the name of the secret that the pod's proxy
+// reads its certificate from, or "" if the proxy doesn't use TLS.
+func proxyCertificateSecret(pod *v1.Pod) string {
+	if proxyEnv(proxyContainer(pod), "LINKERD2_PROXY_TLS_CERT") == "" {
+		return ""
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == "linkerd-secrets" && volume.Secret != nil {
+			return volume.Secret.SecretName
+		}
+	}
+	return ""
+}
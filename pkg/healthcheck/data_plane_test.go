@@ -0,0 +1,252 @@
+package healthcheck
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/tls"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func podWithInit(name string, state v1.ContainerState) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name},
+		Status: v1.PodStatus{
+			InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "linkerd-init", State: state},
+			},
+		},
+	}
+}
+
+func TestValidateDataPlaneProxyInit(t *testing.T) {
+	completed := v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0}}
+
+	testCases := []struct {
+		title string
+		pods  []v1.Pod
+		err   string
+	}{
+		{
+			title: "returns nil if all init containers completed",
+			pods:  []v1.Pod{podWithInit("web-1", completed), podWithInit("web-2", completed)},
+		},
+		{
+			title: "returns an error if the init container is missing",
+			pods:  []v1.Pod{podWithInit("web-1", completed), {ObjectMeta: meta_v1.ObjectMeta{Name: "web-2"}}},
+			err:   "The \"web-2\" pod has no \"linkerd-init\" container, so its traffic isn't routed through the proxy",
+		},
+		{
+			title: "returns an error if the init container is still running",
+			pods:  []v1.Pod{podWithInit("web-1", v1.ContainerState{Running: &v1.ContainerStateRunning{}})},
+			err:   "The \"linkerd-init\" container in the \"web-1\" pod hasn't completed",
+		},
+		{
+			title: "returns an error if the init container failed",
+			pods:  []v1.Pod{podWithInit("web-1", v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 1}})},
+			err:   "The \"linkerd-init\" container in the \"web-1\" pod failed to set up iptables rules (exit code 1)",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			err := validateDataPlaneProxyInit(tc.pods)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+			} else if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error %q, got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateDataPlaneDestination(t *testing.T) {
+	testCases := []struct {
+		title   string
+		metrics string
+		err     string
+	}{
+		{
+			title: "returns nil if the proxy got responses from the control plane",
+			metrics: `# TYPE control_response_total counter
+control_response_total{addr="linkerd-proxy-api.linkerd.svc.cluster.local:8086",classification="success"} 3
+control_response_total{addr="linkerd-proxy-api.linkerd.svc.cluster.local:8086",classification="failure"} 1
+`,
+		},
+		{
+			title:   "returns nil if the proxy hasn't looked up any destinations",
+			metrics: "process_start_time_seconds 1\n",
+		},
+		{
+			title: "returns an error if all the control plane requests failed",
+			metrics: `# TYPE control_response_total counter
+control_response_total{addr="linkerd-proxy-api.linkerd.svc.cluster.local:8086",classification="failure"} 2
+`,
+			err: "The \"emojivoto/web-1\" pod's proxy can't reach the destination service (2 failed requests)",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			families, err := ParseProxyMetrics([]byte(tc.metrics))
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			err = validateDataPlaneDestination(map[string]map[string]*dto.MetricFamily{"emojivoto/web-1": families})
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+			} else if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error %q, got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateWindowsPods(t *testing.T) {
+	nodes := []v1.Node{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "linux-1", Labels: map[string]string{"beta.kubernetes.io/os": "linux"}}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "windows-1", Labels: map[string]string{"beta.kubernetes.io/os": "windows"}}},
+	}
+	pod := func(name, node string, phase v1.PodPhase, nodeSelector map[string]string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: meta_v1.ObjectMeta{Namespace: "emojivoto", Name: name},
+			Spec:       v1.PodSpec{NodeName: node, NodeSelector: nodeSelector},
+			Status:     v1.PodStatus{Phase: phase},
+		}
+	}
+
+	testCases := []struct {
+		title string
+		pods  []v1.Pod
+		err   string
+	}{
+		{
+			title: "returns nil if all pods run on Linux nodes",
+			pods:  []v1.Pod{pod("web-1", "linux-1", v1.PodRunning, nil)},
+		},
+		{
+			title: "skips the pods that terminated",
+			pods:  []v1.Pod{pod("job-1", "windows-1", v1.PodSucceeded, nil)},
+		},
+		{
+			title: "returns an error for the pods on Windows nodes",
+			pods: []v1.Pod{
+				pod("web-1", "linux-1", v1.PodRunning, nil),
+				pod("iis-1", "windows-1", v1.PodRunning, nil),
+				pod("iis-2", "", v1.PodPending, map[string]string{"kubernetes.io/os": "windows"}),
+			},
+			err: "2 pods can't be meshed because they run on Windows nodes: emojivoto/iis-1, emojivoto/iis-2",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			err := validateWindowsPods(nodes, tc.pods)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+			} else if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error %q, got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestParseProxyMetrics(t *testing.T) {
+	if _, err := ParseProxyMetrics([]byte("")); err == nil || err.Error() != "no metrics found" {
+		t.Fatalf("Expected an error for empty metrics, got: %v", err)
+	}
+	if _, err := ParseProxyMetrics([]byte("not metrics")); err == nil {
+		t.Fatalf("Expected an error for invalid metrics")
+	}
+}
+
+func TestValidateProxyCertificate(t *testing.T) {
+	rootKey, err := tls.GenerateECDSAKey()
+	if err != nil {
+		t.Fatalf("GenerateECDSAKey returned an error: %s", err)
+	}
+	now := time.Now()
+	rootTemplate := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Cluster-local Managed Pod CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, &rootTemplate, &rootTemplate, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate returned an error: %s", err)
+	}
+	root, err := x509.ParseCertificate(rootDER)
+	if err != nil {
+		t.Fatalf("ParseCertificate returned an error: %s", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+
+	leafKey, err := tls.GenerateECDSAKey()
+	if err != nil {
+		t.Fatalf("GenerateECDSAKey returned an error: %s", err)
+	}
+	identity := "web.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local"
+	leafTemplate := x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		DNSNames:     []string{identity},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, &leafTemplate, root, &leafKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate returned an error: %s", err)
+	}
+
+	testCases := []struct {
+		title    string
+		cert     []byte
+		identity string
+		roots    *x509.CertPool
+		now      time.Time
+		err      string
+	}{
+		{title: "accepts valid certificates", cert: leafDER, identity: identity, now: now},
+		{title: "rejects missing certificates", identity: identity, now: now, err: "no certificate has been issued yet"},
+		{title: "rejects certificates for other identities", cert: leafDER, identity: "voting.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local", now: now, err: "x509: certificate is valid for"},
+		{title: "rejects expired certificates", cert: leafDER, identity: identity, now: now.Add(2 * time.Hour), err: "x509: certificate has expired or is not yet valid"},
+		{title: "rejects certificates from other CAs", cert: leafDER, identity: identity, roots: x509.NewCertPool(), now: now, err: "x509: certificate signed by unknown authority"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			if tc.roots == nil {
+				tc.roots = roots
+			}
+			err := validateProxyCertificate(tc.cert, tc.identity, tc.roots, tc.now)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+			} else if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+				t.Fatalf("Expected error starting with %q, got: %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package healthcheck
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// maxDriftReported is the number of drifted fields listed in the result of the
+// drift check; the others are only counted.
+const maxDriftReported = 20
+
+// liveResourceFunc returns the JSON representation of the resource with the
+// given apiVersion, kind, namespace and name in the cluster, or nil if it
+// doesn't exist.
+type liveResourceFunc func(apiVersion, kind, namespace, name string) ([]byte, error)
+
+type driftResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+}
+
+func (hc *HealthChecker) checkDrift() error {
+	if hc.liveResource == nil {
+		hc.liveResource = func(apiVersion, kind, namespace, name string) ([]byte, error) {
+			return hc.kubeAPI.GetResource(hc.httpClient, apiVersion, kind, namespace, name)
+		}
+	}
+
+	drifts, err := findDrift(bytes.NewReader(hc.DriftManifests), hc.liveResource)
+	if err != nil {
+		return err
+	}
+	if len(drifts) == 0 {
+		return nil
+	}
+
+	reported := drifts
+	if len(reported) > maxDriftReported {
+		reported = reported[:maxDriftReported]
+	}
+	msg := fmt.Sprintf("The control plane resources have %d changes from their manifests:\n    * %s", len(drifts), strings.Join(reported, "\n    * "))
+	if len(drifts) > len(reported) {
+		msg += fmt.Sprintf("\n    * and %d more", len(drifts)-len(reported))
+	}
+	return errors.New(msg)
+}
+
+// findDrift returns a description of each difference between the resources in
+// manifests and the same resources in the cluster. Since Kubernetes defaults
+// many fields of the resources it stores, only the fields set in the manifests
+// are compared: a field that a manifest doesn't set is ignored, even if it was
+// added out of band. The values of secrets aren't included in the
+// descriptions.
+func findDrift(manifests io.Reader, live liveResourceFunc) ([]string, error) {
+	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(manifests, 4096))
+
+	drifts := []string{}
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var expected map[string]interface{}
+		if err := yaml.Unmarshal(doc, &expected); err != nil {
+			return nil, err
+		}
+		if expected == nil {
+			continue
+		}
+		var resource driftResource
+		if err := yaml.Unmarshal(doc, &resource); err != nil {
+			return nil, err
+		}
+		name := strings.ToLower(resource.Kind) + "/" + resource.Metadata.Name
+		if resource.Metadata.Namespace != "" {
+			name = resource.Metadata.Namespace + "/" + name
+		}
+
+		current, err := live(resource.APIVersion, resource.Kind, resource.Metadata.Namespace, resource.Metadata.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get %s: %s", name, err)
+		}
+		if current == nil {
+			drifts = append(drifts, fmt.Sprintf("%s: not found", name))
+			continue
+		}
+		var actual map[string]interface{}
+		if err := json.Unmarshal(current, &actual); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %s", name, err)
+		}
+
+		delete(expected, "status")
+		if metadata, ok := expected["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "creationTimestamp")
+		}
+		redact := resource.Kind == "Secret"
+		for _, drift := range diffFields("", expected, actual, redact) {
+			drifts = append(drifts, fmt.Sprintf("%s: %s", name, drift))
+		}
+	}
+
+	return drifts, nil
+}
+
+// diffFields returns a description of each field of expected that actual
+// doesn't have the same value for, with the values omitted if redact is set.
+func diffFields(path string, expected, actual interface{}, redact bool) []string {
+	switch expected := expected.(type) {
+	case map[string]interface{}:
+		if len(expected) == 0 {
+			return nil
+		}
+		actual, ok := actual.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s was removed or changed", path)}
+		}
+
+		keys := make([]string, 0, len(expected))
+		for key := range expected {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		drifts := []string{}
+		for _, key := range keys {
+			field := key
+			if path != "" {
+				field = path + "." + key
+			}
+			value, ok := actual[key]
+			if !ok {
+				if !isEmptyField(expected[key]) {
+					drifts = append(drifts, fmt.Sprintf("%s was removed", field))
+				}
+				continue
+			}
+			drifts = append(drifts, diffFields(field, expected[key], value, redact)...)
+		}
+		return drifts
+
+	case []interface{}:
+		actual, ok := actual.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s was removed or changed", path)}
+		}
+		if len(actual) != len(expected) {
+			return []string{fmt.Sprintf("%s has %d items, expected %d", path, len(actual), len(expected))}
+		}
+
+		drifts := []string{}
+		for i := range expected {
+			drifts = append(drifts, diffFields(fmt.Sprintf("%s[%d]", path, i), expected[i], actual[i], redact)...)
+		}
+		return drifts
+
+	default:
+		// scalars are compared by their string representation, since quantities
+		// such as "1" and 1 are equivalent
+		if isEmptyField(expected) && isEmptyField(actual) {
+			return nil
+		}
+		if fmt.Sprint(expected) == fmt.Sprint(actual) {
+			return nil
+		}
+		if redact {
+			return []string{fmt.Sprintf("%s was changed", path)}
+		}
+		return []string{fmt.Sprintf("%s is %s, expected %s", path, jsonValue(actual), jsonValue(expected))}
+	}
+}
+
+// isEmptyField returns whether value is a zero value, which Kubernetes omits
+// from many of the fields it stores.
+func isEmptyField(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case string:
+		return value == ""
+	case bool:
+		return !value
+	case float64:
+		return value == 0
+	case map[string]interface{}:
+		return len(value) == 0
+	case []interface{}:
+		return len(value) == 0
+	}
+	return false
+}
+
+func jsonValue(value interface{}) string {
+	out, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(out)
+}
@@ -0,0 +1,147 @@
+package healthcheck
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const driftManifests = `
+kind: Deployment
+apiVersion: extensions/v1beta1
+metadata:
+  name: linkerd-controller
+  namespace: linkerd
+  creationTimestamp: null
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: public-api
+        args:
+        - "public-api"
+        - "-log-level=info"
+        resources: {}
+status: {}
+---
+kind: Secret
+apiVersion: v1
+metadata:
+  name: linkerd-ca-issuer
+  namespace: linkerd
+data:
+  tls.key: a2V5
+---
+kind: Namespace
+apiVersion: v1
+metadata:
+  name: linkerd
+`
+
+func TestFindDrift(t *testing.T) {
+	deployment := `{
+  "kind": "Deployment",
+  "apiVersion": "extensions/v1beta1",
+  "metadata": {"name": "linkerd-controller", "namespace": "linkerd", "uid": "1234"},
+  "spec": {
+    "replicas": %s,
+    "template": {"spec": {"containers": [
+      {"name": "public-api", "args": [%s], "imagePullPolicy": "IfNotPresent"}
+    ]}}
+  },
+  "status": {"replicas": 3}
+}`
+	secret := `{"kind": "Secret", "apiVersion": "v1", "metadata": {"name": "linkerd-ca-issuer", "namespace": "linkerd"}, "data": {"tls.key": "%s"}}`
+	namespace := `{"kind": "Namespace", "apiVersion": "v1", "metadata": {"name": "linkerd", "labels": {"linkerd.io/is-control-plane": "true"}}}`
+
+	testCases := []struct {
+		title    string
+		live     map[string]string
+		expected []string
+	}{
+		{
+			"ignores the fields set by Kubernetes",
+			map[string]string{
+				"Deployment": strings.Replace(strings.Replace(deployment, "%s", "1", 1), "%s", `"public-api", "-log-level=info"`, 1),
+				"Secret":     strings.Replace(secret, "%s", "a2V5", 1),
+				"Namespace":  namespace,
+			},
+			[]string{},
+		},
+		{
+			"reports the changed fields",
+			map[string]string{
+				"Deployment": strings.Replace(strings.Replace(deployment, "%s", "3", 1), "%s", `"public-api", "-log-level=debug"`, 1),
+				"Secret":     strings.Replace(secret, "%s", "b3RoZXI=", 1),
+				"Namespace":  namespace,
+			},
+			[]string{
+				`linkerd/deployment/linkerd-controller: spec.replicas is 3, expected 1`,
+				`linkerd/deployment/linkerd-controller: spec.template.spec.containers[0].args[1] is "-log-level=debug", expected "-log-level=info"`,
+				`linkerd/secret/linkerd-ca-issuer: data.tls.key was changed`,
+			},
+		},
+		{
+			"reports the removed fields and resources",
+			map[string]string{
+				"Deployment": strings.Replace(strings.Replace(deployment, "%s", "1", 1), "%s", `"public-api"`, 1),
+				"Secret":     `{"kind": "Secret", "apiVersion": "v1", "metadata": {"name": "linkerd-ca-issuer", "namespace": "linkerd"}}`,
+			},
+			[]string{
+				`linkerd/deployment/linkerd-controller: spec.template.spec.containers[0].args has 1 items, expected 2`,
+				`linkerd/secret/linkerd-ca-issuer: data was removed`,
+				`namespace/linkerd: not found`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			live := func(apiVersion, kind, namespace, name string) ([]byte, error) {
+				if resource, ok := tc.live[kind]; ok {
+					return []byte(resource), nil
+				}
+				return nil, nil
+			}
+
+			drifts, err := findDrift(strings.NewReader(driftManifests), live)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(drifts, tc.expected) {
+				t.Fatalf("Expected drifts:\n%s\nGot:\n%s", strings.Join(tc.expected, "\n"), strings.Join(drifts, "\n"))
+			}
+		})
+	}
+}
+
+func TestDriftChecks(t *testing.T) {
+	newChecker := func(live liveResourceFunc) *HealthChecker {
+		hc := NewHealthChecker([]CategoryID{LinkerdDriftChecks}, &Options{
+			ControlPlaneNamespace: "linkerd",
+			DriftManifests:        []byte(driftManifests),
+		})
+		hc.liveResource = live
+		return hc
+	}
+
+	t.Run("fails when resources are missing", func(t *testing.T) {
+		var result *CheckResult
+		hc := newChecker(func(apiVersion, kind, namespace, name string) ([]byte, error) {
+			return nil, nil
+		})
+
+		if hc.RunChecks(func(r *CheckResult) { result = r }) {
+			t.Fatal("Expected the drift check to fail")
+		}
+		expected := "The control plane resources have 3 changes from their manifests:\n" +
+			"    * linkerd/deployment/linkerd-controller: not found\n" +
+			"    * linkerd/secret/linkerd-ca-issuer: not found\n" +
+			"    * namespace/linkerd: not found"
+		if result.Err == nil || result.Err.Error() != expected {
+			t.Fatalf("Expected error:\n%s\nGot:\n%v", expected, result.Err)
+		}
+	})
+}
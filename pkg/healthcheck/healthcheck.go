@@ -9,12 +9,16 @@ import (
 	"time"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
+	"github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
 	spclient "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
 	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/client"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/pkg/profiles"
 	"github.com/linkerd/linkerd2/pkg/version"
+	dto "github.com/prometheus/client_model/go"
+	arv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	authorizationapi "k8s.io/api/authorization/v1beta1"
 	"k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -61,6 +65,15 @@ const (
 	// checks must be added first.
 	LinkerdPreInstallChecks CategoryID = "pre-kubernetes-setup"
 
+	// LinkerdPreInstallCapabilityChecks adds checks to validate that the
+	// policies and admission webhooks of the cluster let the control plane and
+	// the proxies run, including the NET_ADMIN and NET_RAW capabilities of the
+	// linkerd-init containers. This check only runs as part of the set of
+	// pre-install checks.
+	// This check is dependent on the output of KubernetesAPIChecks, so those
+	// checks must be added first.
+	LinkerdPreInstallCapabilityChecks CategoryID = "pre-kubernetes-capability"
+
 	// LinkerdControlPlaneExistenceChecks adds a series of checks to validate that
 	// the control plane namespace and controller pod exist.
 	// These checks are dependent on the output of KubernetesAPIChecks, so those
@@ -79,6 +92,38 @@ const (
 	// checks must be added first.
 	LinkerdServiceProfileChecks CategoryID = "linkerd-service-profile"
 
+	// LinkerdProxyInjectorChecks adds a series of checks to validate that the
+	// proxy injector, when installed, is configured to match the control plane.
+	// Each of these checks can be fixed with the remediation it describes.
+	// These checks are dependent on the output of KubernetesAPIChecks, so those
+	// checks must be added first.
+	LinkerdProxyInjectorChecks CategoryID = "linkerd-proxy-injector"
+
+	// LinkerdDriftChecks adds a check to validate that the control plane
+	// resources in the cluster still match the manifests they were installed
+	// from, which are provided in Options.DriftManifests, to detect the changes
+	// made to them out of band.
+	// These checks are dependent on the output of KubernetesAPIChecks, so those
+	// checks must be added first.
+	LinkerdDriftChecks CategoryID = "linkerd-drift"
+
+	// LinkerdMulticlusterChecks adds a series of checks to validate the Links
+	// of the control plane namespace: their credentials, the identity and
+	// reachability of their gateways, as probed by their service mirrors, and
+	// the freshness of their mirrored services.
+	// These checks are dependent on the output of KubernetesAPIChecks, so those
+	// checks must be added first.
+	LinkerdMulticlusterChecks CategoryID = "linkerd-multicluster"
+
+	// LinkerdGrafanaChecks, LinkerdTracingChecks and LinkerdFlaggerChecks add a
+	// check to validate that the pods of the add-on of the same name, installed
+	// with `linkerd install`, are ready.
+	// These checks are dependent on the output of KubernetesAPIChecks, so those
+	// checks must be added first.
+	LinkerdGrafanaChecks CategoryID = "linkerd-grafana"
+	LinkerdTracingChecks CategoryID = "linkerd-tracing"
+	LinkerdFlaggerChecks CategoryID = "linkerd-flagger"
+
 	// LinkerdVersionChecks adds a series of checks to query for the latest
 	// version, and validate the the CLI is up to date.
 	LinkerdVersionChecks CategoryID = "linkerd-version"
@@ -92,7 +137,10 @@ const (
 
 	// LinkerdDataPlaneChecks adds data plane checks to validate that the data
 	// plane namespace exists, and that the the proxy containers are in a ready
-	// state and running the latest available version.
+	// state and running the latest available version. The proxies of each
+	// meshed pod are also verified directly: their iptables setup, admin
+	// endpoint, destination lookups and TLS certificate. The pods that run on
+	// Windows nodes, which can't be meshed, are reported too.
 	// These checks are dependent on the output of KubernetesAPIChecks,
 	// `apiClient` from LinkerdControlPlaneExistenceChecks, and `latestVersion`
 	// from LinkerdVersionChecks, so those checks must be added first.
@@ -134,6 +182,15 @@ type checker struct {
 	// check using the SelfCheck gRPC endpoint; check status is based on the value
 	// of the gRPC response
 	checkRPC func() (*healthcheckPb.SelfCheckResponse, error)
+
+	// remediation describes the change that fix makes to the cluster; it's
+	// shown to the user before the fix is applied
+	remediation string
+
+	// fix is an optional function that's called to remediate a failed check,
+	// when fixes are enabled and the user confirms the remediation; the check is
+	// executed again after a successful fix
+	fix func() error
 }
 
 // CheckResult encapsulates a check's identifying information and output
@@ -144,6 +201,9 @@ type CheckResult struct {
 	Retry       bool
 	Warning     bool
 	Err         error
+
+	// Fixed is the remediation that was applied to fix the check, if any.
+	Fixed string
 }
 
 type checkObserver func(*CheckResult)
@@ -160,9 +220,26 @@ type Options struct {
 	DataPlaneNamespace    string
 	KubeConfig            string
 	KubeContext           string
+	Impersonate           string
+	ImpersonateGroup      []string
 	APIAddr               string
 	VersionOverride       string
 	RetryDeadline         time.Time
+
+	// MutualTLS connects to the public API over mutual TLS, as
+	// client.Options.MutualTLS does.
+	MutualTLS bool
+
+	// Fix enables the remediation of failed checks that can be fixed
+	// deterministically. ConfirmFix is called with the description of each
+	// check, and the remediation that would fix it; the remediation is only
+	// applied if it returns true.
+	Fix        bool
+	ConfirmFix func(description, remediation string) bool
+
+	// DriftManifests are the manifests that the control plane was installed
+	// from, as rendered by `linkerd install`, for the LinkerdDriftChecks.
+	DriftManifests []byte
 }
 
 // HealthChecker encapsulates all health check checkers, and clients required to
@@ -174,12 +251,18 @@ type HealthChecker struct {
 	// these fields are set in the process of running checks
 	kubeAPI          *k8s.KubernetesAPI
 	httpClient       *http.Client
-	clientset        *kubernetes.Clientset
-	spClientset      *spclient.Clientset
+	clientset        kubernetes.Interface
+	spClientset      spclient.Interface
 	kubeVersion      *k8sVersion.Info
 	controlPlanePods []v1.Pod
 	apiClient        pb.ApiClient
 	latestVersion    string
+	webhookConfig    *arv1beta1.MutatingWebhookConfiguration
+	dataPlaneMetrics map[string]map[string]*dto.MetricFamily
+	liveResource     liveResourceFunc
+	links            []v1alpha1.Link
+	remoteClientset  remoteClientsetFunc
+	remoteClientsets map[string]kubernetes.Interface
 }
 
 // NewHealthChecker returns an initialized HealthChecker
@@ -216,7 +299,7 @@ func (hc *HealthChecker) allCategories() []category {
 					description: "can initialize the client",
 					fatal:       true,
 					check: func() (err error) {
-						hc.kubeAPI, err = k8s.NewAPI(hc.KubeConfig, hc.KubeContext)
+						hc.kubeAPI, err = k8s.NewAPI(hc.KubeConfig, hc.KubeContext, hc.Impersonate, hc.ImpersonateGroup)
 						return
 					},
 				},
@@ -336,6 +419,29 @@ func (hc *HealthChecker) allCategories() []category {
 				},
 			},
 		},
+		{
+			id: LinkerdPreInstallCapabilityChecks,
+			checkers: []checker{
+				{
+					description: "pod security policies grant the NET_ADMIN and NET_RAW capabilities",
+					warning:     true,
+					check:       hc.checkPodSecurityPolicies,
+				},
+				{
+					description: "namespaces allow the NET_ADMIN and NET_RAW capabilities",
+					check:       hc.checkPodSecurityAdmission,
+				},
+				{
+					description: "no admission webhooks block the creation of pods",
+					check:       hc.checkBlockingWebhooks,
+				},
+				{
+					description: "Kubernetes API server can reach admission webhooks",
+					warning:     true,
+					check:       hc.checkWebhookConnectivity,
+				},
+			},
+		},
 		{
 			id: LinkerdControlPlaneExistenceChecks,
 			checkers: []checker{
@@ -363,9 +469,12 @@ func (hc *HealthChecker) allCategories() []category {
 					description: "can initialize the client",
 					fatal:       true,
 					check: func() (err error) {
-						if hc.APIAddr != "" {
+						switch {
+						case hc.APIAddr != "":
 							hc.apiClient, err = public.NewInternalClient(hc.ControlPlaneNamespace, hc.APIAddr)
-						} else {
+						case hc.MutualTLS:
+							hc.apiClient, err = hc.newMutualTLSClient()
+						default:
 							hc.apiClient, err = public.NewExternalClient(hc.ControlPlaneNamespace, hc.kubeAPI)
 						}
 						return
@@ -422,6 +531,97 @@ func (hc *HealthChecker) allCategories() []category {
 				},
 			},
 		},
+		{
+			id: LinkerdProxyInjectorChecks,
+			checkers: []checker{
+				{
+					description: "proxy-injector webhook has the control plane trust anchors",
+					remediation: fmt.Sprintf("update the CA bundle of the %s MutatingWebhookConfiguration", k8s.ProxyInjectorWebhookConfig),
+					check:       hc.checkWebhookCABundle,
+					fix:         hc.fixWebhookCABundle,
+				},
+				{
+					description: "control plane namespace is excluded from auto-injection",
+					remediation: fmt.Sprintf("add the %s=%s label to the %s namespace", k8s.ProxyAutoInjectLabel, k8s.ProxyAutoInjectDisabled, hc.ControlPlaneNamespace),
+					check:       hc.checkControlPlaneNamespaceLabel,
+					fix:         hc.fixControlPlaneNamespaceLabel,
+				},
+				{
+					description: "proxy-injector has the required RBAC permissions",
+					remediation: fmt.Sprintf("add the missing rules to the %s ClusterRole", proxyInjectorClusterRoleName(hc.ControlPlaneNamespace)),
+					check:       hc.checkProxyInjectorRBAC,
+					fix:         hc.fixProxyInjectorRBAC,
+				},
+			},
+		},
+		{
+			id: LinkerdDriftChecks,
+			checkers: []checker{
+				{
+					description: "control plane resources match their manifests",
+					check:       hc.checkDrift,
+				},
+			},
+		},
+		{
+			id: LinkerdMulticlusterChecks,
+			checkers: []checker{
+				{
+					description: "clusters are linked",
+					fatal:       true,
+					check:       hc.checkLinks,
+				},
+				{
+					description: "linked cluster credentials are valid",
+					fatal:       true,
+					check:       hc.checkLinkCredentials,
+				},
+				{
+					description: "gateway identities are valid",
+					check:       hc.checkGatewayIdentities,
+				},
+				{
+					description:   "gateways are alive",
+					retryDeadline: hc.RetryDeadline,
+					check:         hc.checkGateways,
+				},
+				{
+					description: "mirrored services are up-to-date",
+					warning:     true,
+					check:       hc.checkMirrors,
+				},
+			},
+		},
+		{
+			id: LinkerdGrafanaChecks,
+			checkers: []checker{
+				{
+					description:   "grafana add-on pods are ready",
+					retryDeadline: hc.RetryDeadline,
+					check:         hc.checkAddOnPods("grafana"),
+				},
+			},
+		},
+		{
+			id: LinkerdTracingChecks,
+			checkers: []checker{
+				{
+					description:   "tracing add-on pods are ready",
+					retryDeadline: hc.RetryDeadline,
+					check:         hc.checkAddOnPods("collector", "jaeger"),
+				},
+			},
+		},
+		{
+			id: LinkerdFlaggerChecks,
+			checkers: []checker{
+				{
+					description:   "flagger add-on pods are ready",
+					retryDeadline: hc.RetryDeadline,
+					check:         hc.checkAddOnPods("flagger"),
+				},
+			},
+		},
 		{
 			id: LinkerdVersionChecks,
 			checkers: []checker{
@@ -484,6 +684,11 @@ func (hc *HealthChecker) allCategories() []category {
 						return hc.checkNamespace(hc.DataPlaneNamespace, true)
 					},
 				},
+				{
+					description: "no unmeshable Windows pods",
+					warning:     true,
+					check:       hc.checkWindowsPods,
+				},
 				{
 					description:   "data plane proxies are ready",
 					retryDeadline: hc.RetryDeadline,
@@ -509,10 +714,47 @@ func (hc *HealthChecker) allCategories() []category {
 						return validateDataPlanePodReporting(pods)
 					},
 				},
+				{
+					description: "data plane proxies have set up iptables rules",
+					check: func() error {
+						pods, err := hc.getMeshedPods()
+						if err != nil {
+							return err
+						}
+
+						return validateDataPlaneProxyInit(pods)
+					},
+				},
+				{
+					description:   "data plane proxy admin endpoints are serving metrics",
+					retryDeadline: hc.RetryDeadline,
+					check: func() error {
+						return hc.checkDataPlaneProxyMetrics()
+					},
+				},
+				{
+					description: "data plane proxies can reach the destination service",
+					warning:     true,
+					check: func() error {
+						return validateDataPlaneDestination(hc.dataPlaneMetrics)
+					},
+				},
+				{
+					description:   "data plane proxy certificates are valid",
+					retryDeadline: hc.RetryDeadline,
+					check: func() error {
+						return hc.checkDataPlaneProxyCertificates()
+					},
+				},
 				{
 					description: "data plane is up-to-date",
 					warning:     true,
 					check: func() error {
+						if hc.latestVersion == "" {
+							// LinkerdVersionChecks didn't run, as version checks are disabled
+							return nil
+						}
+
 						pods, err := hc.getDataPlanePods()
 						if err != nil {
 							return err
@@ -613,9 +855,29 @@ func (hc *HealthChecker) runCheck(categoryID CategoryID, c *checker, observer ch
 			continue
 		}
 
+		if err != nil && hc.canFix(c) {
+			checkResult.Err = hc.runFix(c, err)
+			if checkResult.Err == nil {
+				checkResult.Fixed = c.remediation
+			}
+		}
+
 		observer(checkResult)
-		return err == nil
+		return checkResult.Err == nil
+	}
+}
+
+func (hc *HealthChecker) canFix(c *checker) bool {
+	return hc.Fix && c.fix != nil && hc.ConfirmFix != nil && hc.ConfirmFix(c.description, c.remediation)
+}
+
+// runFix applies the remediation of a check that failed with err, and executes
+// the check again. It returns the error of the check after the fix.
+func (hc *HealthChecker) runFix(c *checker, err error) error {
+	if fixErr := c.fix(); fixErr != nil {
+		return fmt.Errorf("%s (failed to fix: %s)", err, fixErr)
 	}
+	return c.check()
 }
 
 func (hc *HealthChecker) runCheckRPC(categoryID CategoryID, c *checker, observer checkObserver) bool {
@@ -657,6 +919,25 @@ func (hc *HealthChecker) PublicAPIClient() pb.ApiClient {
 	return hc.apiClient
 }
 
+// newMutualTLSClient returns a public API client that connects to the TLS port
+// of the public API through a port-forward, which stays open for the lifetime
+// of the process.
+func (hc *HealthChecker) newMutualTLSClient() (pb.ApiClient, error) {
+	options := client.NewOptions()
+	options.ControlPlaneNamespace = hc.ControlPlaneNamespace
+	options.KubeConfig = hc.KubeConfig
+	options.KubeContext = hc.KubeContext
+	options.Impersonate = hc.Impersonate
+	options.ImpersonateGroup = hc.ImpersonateGroup
+	options.MutualTLS = true
+
+	c, err := client.New(options)
+	if err != nil {
+		return nil, err
+	}
+	return c.API(), nil
+}
+
 func (hc *HealthChecker) checkNamespace(namespace string, shouldExist bool) error {
 	exists, err := hc.kubeAPI.NamespaceExists(hc.httpClient, namespace)
 	if err != nil {
@@ -677,13 +958,13 @@ func (hc *HealthChecker) getDataPlanePods() ([]*pb.Pod, error) {
 		req.Namespace = hc.DataPlaneNamespace
 	}
 
-	resp, err := hc.apiClient.ListPods(context.Background(), req)
+	dataPlanePods, err := public.ListAllPods(context.Background(), hc.apiClient, req)
 	if err != nil {
 		return nil, err
 	}
 
 	pods := make([]*pb.Pod, 0)
-	for _, pod := range resp.GetPods() {
+	for _, pod := range dataPlanePods {
 		if pod.ControllerNamespace == hc.ControlPlaneNamespace {
 			pods = append(pods, pod)
 		}
@@ -767,26 +1048,8 @@ func (hc *HealthChecker) validateServiceProfiles() error {
 		if err != nil {
 			return fmt.Errorf("ServiceProfile \"%s\" has unknown service: %s", p.Name, err)
 		}
-		for _, route := range p.Spec.Routes {
-			if route.Name == "" {
-				return fmt.Errorf("ServiceProfile \"%s\" has a route with no name", p.Name)
-			}
-			if route.Condition == nil {
-				return fmt.Errorf("ServiceProfile \"%s\" has a route with no condition", p.Name)
-			}
-			err = profiles.ValidateRequestMatch(route.Condition)
-			if err != nil {
-				return fmt.Errorf("ServiceProfile \"%s\" has a route with an invalid condition: %s", p.Name, err)
-			}
-			for _, rc := range route.ResponseClasses {
-				if rc.Condition == nil {
-					return fmt.Errorf("ServiceProfile \"%s\" has a response class with no condition", p.Name)
-				}
-				err = profiles.ValidateResponseMatch(rc.Condition)
-				if err != nil {
-					return fmt.Errorf("ServiceProfile \"%s\" has a response class with an invalid condition: %s", p.Name, err)
-				}
-			}
+		if err := profiles.Validate(&p.Spec); err != nil {
+			return fmt.Errorf("ServiceProfile \"%s\" is invalid: %s", p.Name, err)
 		}
 	}
 	return nil
@@ -812,14 +1075,36 @@ func getPodStatuses(pods []v1.Pod) map[string][]v1.ContainerStatus {
 func validateControlPlanePods(pods []v1.Pod) error {
 	statuses := getPodStatuses(pods)
 
-	names := []string{"controller", "prometheus", "web", "grafana"}
-	if _, found := statuses["ca"]; found {
-		names = append(names, "ca")
+	names := []string{"controller", "web"}
+	if usesBundledPrometheus(pods) {
+		names = append(names, "prometheus")
+	}
+	// the optional components are only checked if they're installed
+	for _, name := range []string{"ca", "proxy-injector", "grafana"} {
+		if _, found := statuses[name]; found {
+			names = append(names, name)
+		}
 	}
-	if _, found := statuses["proxy-injector"]; found {
-		names = append(names, "proxy-injector")
+
+	return validateComponentPods(statuses, names)
+}
+
+// checkAddOnPods returns a check that the pods of the given components of an
+// add-on are ready. The pods are fetched on each attempt, since the check is
+// retried while they start.
+func (hc *HealthChecker) checkAddOnPods(components ...string) func() error {
+	return func() error {
+		pods, err := hc.kubeAPI.GetPodsByNamespace(hc.httpClient, hc.ControlPlaneNamespace)
+		if err != nil {
+			return err
+		}
+		return validateComponentPods(getPodStatuses(pods), components)
 	}
+}
 
+// validateComponentPods checks that each of the named control plane
+// components has running pods, whose containers are all ready.
+func validateComponentPods(statuses map[string][]v1.ContainerStatus, names []string) error {
 	for _, name := range names {
 		containers, found := statuses[name]
 		if !found {
@@ -836,6 +1121,24 @@ func validateControlPlanePods(pods []v1.Pod) error {
 	return nil
 }
 
+// usesBundledPrometheus returns false if the public API is configured to query
+// a Prometheus other than linkerd-prometheus, which then isn't installed.
+func usesBundledPrometheus(pods []v1.Pod) bool {
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			if container.Name != "public-api" {
+				continue
+			}
+			for _, arg := range container.Args {
+				if strings.HasPrefix(arg, "-prometheus-url=") && !strings.Contains(arg, "//linkerd-prometheus.") {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
 func checkControllerRunning(pods []v1.Pod) error {
 	statuses := getPodStatuses(pods)
 	if _, ok := statuses["controller"]; !ok {
@@ -288,6 +288,99 @@ func TestHealthChecker(t *testing.T) {
 			t.Fatalf("Expected results %v, but got %v", expectedResults, observedResults)
 		}
 	})
+
+	t.Run("Fixes checks if fixes are enabled and confirmed", func(t *testing.T) {
+		for _, tc := range []struct {
+			fix             bool
+			confirm         bool
+			fixErr          error
+			expectedResults []string
+		}{
+			{
+				fix:             false,
+				confirm:         true,
+				expectedResults: []string{"cat8 desc8 fixed=: broken"},
+			},
+			{
+				fix:             true,
+				confirm:         false,
+				expectedResults: []string{"cat8 desc8 fixed=: broken"},
+			},
+			{
+				fix:             true,
+				confirm:         true,
+				expectedResults: []string{"cat8 desc8 fixed=repair it"},
+			},
+			{
+				fix:             true,
+				confirm:         true,
+				fixErr:          fmt.Errorf("forbidden"),
+				expectedResults: []string{"cat8 desc8 fixed=: broken (failed to fix: forbidden)"},
+			},
+		} {
+			tc := tc // pin
+			broken := true
+			fixableCheck := category{
+				id: "cat8",
+				checkers: []checker{
+					checker{
+						description: "desc8",
+						remediation: "repair it",
+						check: func() error {
+							if broken {
+								return fmt.Errorf("broken")
+							}
+							return nil
+						},
+						fix: func() error {
+							if tc.fixErr != nil {
+								return tc.fixErr
+							}
+							broken = false
+							return nil
+						},
+					},
+				},
+			}
+
+			confirmed := []string{}
+			hc := NewHealthChecker(
+				[]CategoryID{},
+				&Options{
+					Fix: tc.fix,
+					ConfirmFix: func(description, remediation string) bool {
+						confirmed = append(confirmed, fmt.Sprintf("%s: %s", description, remediation))
+						return tc.confirm
+					},
+				},
+			)
+			hc.addCategory(fixableCheck)
+
+			observedResults := make([]string, 0)
+			observer := func(result *CheckResult) {
+				res := fmt.Sprintf("%s %s fixed=%s", result.Category, result.Description, result.Fixed)
+				if result.Err != nil {
+					res += fmt.Sprintf(": %s", result.Err)
+				}
+				observedResults = append(observedResults, res)
+			}
+
+			success := hc.RunChecks(observer)
+
+			if !reflect.DeepEqual(observedResults, tc.expectedResults) {
+				t.Fatalf("Expected results %v, but got %v", tc.expectedResults, observedResults)
+			}
+			if success != (tc.fix && tc.confirm && tc.fixErr == nil) {
+				t.Fatalf("Unexpected success [%t] for %+v", success, tc)
+			}
+			if tc.fix && !reflect.DeepEqual(confirmed, []string{"desc8: repair it"}) {
+				t.Fatalf("Expected a single confirmation, got %v", confirmed)
+			}
+			if !tc.fix && len(confirmed) != 0 {
+				t.Fatalf("Expected no confirmation with fixes disabled, got %v", confirmed)
+			}
+		}
+	})
 }
 
 func TestValidateControlPlanePods(t *testing.T) {
@@ -353,6 +446,36 @@ func TestValidateControlPlanePods(t *testing.T) {
 			t.Fatalf("Unexpected error: %s", err)
 		}
 	})
+
+	t.Run("Doesn't require the prometheus pods with an external Prometheus", func(t *testing.T) {
+		controller := pod("linkerd-controller-6f78cbd47-bc557", v1.PodRunning, true)
+		controller.Spec.Containers = []v1.Container{
+			{Name: "public-api", Args: []string{"public-api", "-prometheus-url=https://prometheus.monitoring.svc.cluster.local:9090"}},
+		}
+		pods := []v1.Pod{
+			controller,
+			pod("linkerd-grafana-5b7d796646-hh46d", v1.PodRunning, true),
+			pod("linkerd-web-98c9ddbcd-7b5lh", v1.PodRunning, true),
+		}
+
+		err := validateControlPlanePods(pods)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Doesn't require the pods of the grafana add-on", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("linkerd-controller-6f78cbd47-bc557", v1.PodRunning, true),
+			pod("linkerd-prometheus-74d6879cd6-bbdk6", v1.PodRunning, true),
+			pod("linkerd-web-98c9ddbcd-7b5lh", v1.PodRunning, true),
+		}
+
+		err := validateControlPlanePods(pods)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
 }
 
 func TestValidateDataPlanePods(t *testing.T) {
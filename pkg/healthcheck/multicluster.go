@@ -0,0 +1,235 @@
+package healthcheck
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	spclient "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// serviceMirrorAdminPort is the port that the service mirrors serve their
+// metrics on, including those of the gateway probes.
+const serviceMirrorAdminPort = 9999
+
+// remoteClientsetFunc returns a clientset for the remote cluster that the
+// kubeconfig of a Link's credentials authenticates to.
+type remoteClientsetFunc func(kubeconfig []byte) (kubernetes.Interface, error)
+
+func newRemoteClientset(kubeconfig []byte) (kubernetes.Interface, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+// checkLinks fetches the Links of the control plane namespace, and fails if
+// there are none, in which case the other multicluster checks can't run.
+func (hc *HealthChecker) checkLinks() error {
+	if hc.spClientset == nil {
+		var err error
+		hc.spClientset, err = spclient.NewForConfig(hc.kubeAPI.Config)
+		if err != nil {
+			return err
+		}
+	}
+
+	links, err := hc.spClientset.LinkerdV1alpha1().Links(hc.ControlPlaneNamespace).List(meta_v1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	if len(links.Items) == 0 {
+		return fmt.Errorf("No clusters are linked to the \"%s\" namespace; link them with \"linkerd multicluster link\"", hc.ControlPlaneNamespace)
+	}
+
+	hc.links = links.Items
+	sort.Slice(hc.links, func(i, j int) bool { return hc.links[i].Name < hc.links[j].Name })
+	return nil
+}
+
+// checkLinkCredentials verifies that the credentials secret of each Link holds
+// a kubeconfig that the remote cluster's API accepts.
+func (hc *HealthChecker) checkLinkCredentials() error {
+	clientset, err := hc.kubeClientset()
+	if err != nil {
+		return err
+	}
+	if hc.remoteClientset == nil {
+		hc.remoteClientset = newRemoteClientset
+	}
+
+	hc.remoteClientsets = make(map[string]kubernetes.Interface)
+	for _, link := range hc.links {
+		cluster := link.Spec.TargetClusterName
+		secret, err := clientset.CoreV1().Secrets(link.Namespace).Get(link.Spec.ClusterCredentialsSecret, meta_v1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("The credentials secret \"%s\" of cluster \"%s\" doesn't exist", link.Spec.ClusterCredentialsSecret, cluster)
+		}
+		if err != nil {
+			return err
+		}
+		kubeconfig, ok := secret.Data[k8s.ClusterCredentialsKey]
+		if !ok {
+			return fmt.Errorf("The credentials secret \"%s\" of cluster \"%s\" has no \"%s\" key", secret.Name, cluster, k8s.ClusterCredentialsKey)
+		}
+
+		remote, err := hc.remoteClientset(kubeconfig)
+		if err != nil {
+			return fmt.Errorf("The credentials secret \"%s\" of cluster \"%s\" has an invalid kubeconfig: %s", secret.Name, cluster, err)
+		}
+		// the service mirror only needs to watch services
+		if _, err := remote.CoreV1().Services("").List(meta_v1.ListOptions{Limit: 1}); err != nil {
+			return fmt.Errorf("The credentials of cluster \"%s\" can't list its services: %s", cluster, err)
+		}
+		hc.remoteClientsets[cluster] = remote
+	}
+	return nil
+}
+
+// checkGatewayIdentities verifies that the gateway identity of each Link is a
+// valid DNS name, and that the endpoints of its mirrored services are
+// annotated with it, which they aren't until the service mirror restarts
+// after the Link changes.
+func (hc *HealthChecker) checkGatewayIdentities() error {
+	clientset, err := hc.kubeClientset()
+	if err != nil {
+		return err
+	}
+
+	for _, link := range hc.links {
+		cluster := link.Spec.TargetClusterName
+		identity := link.Spec.GatewayIdentity
+		if identity != "" {
+			if errs := validation.IsDNS1123Subdomain(identity); len(errs) > 0 {
+				return fmt.Errorf("The gateway identity \"%s\" of cluster \"%s\" is invalid: %s", identity, cluster, errs[0])
+			}
+		}
+
+		endpoints, err := clientset.CoreV1().Endpoints("").List(meta_v1.ListOptions{LabelSelector: mirrorSelector(cluster).String()})
+		if err != nil {
+			return err
+		}
+		for _, e := range endpoints.Items {
+			if actual := e.Annotations[k8s.RemoteGatewayIdentityAnnotation]; actual != identity {
+				return fmt.Errorf("The endpoints of the mirrored service \"%s/%s\" have the gateway identity \"%s\", but cluster \"%s\" has \"%s\"; restart the %s service mirror to update them",
+					e.Namespace, e.Name, actual, cluster, identity, cluster)
+			}
+		}
+	}
+	return nil
+}
+
+// checkGateways scrapes the metrics of the service mirror of each Link, and
+// verifies that its last probe of the gateway succeeded.
+func (hc *HealthChecker) checkGateways() error {
+	clientset, err := hc.kubeClientset()
+	if err != nil {
+		return err
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{k8s.ControllerComponentLabel: "service-mirror"})
+	pods, err := clientset.CoreV1().Pods(hc.ControlPlaneNamespace).List(meta_v1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+
+	metrics := make(map[string]map[string]*dto.MetricFamily)
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		body, err := hc.kubeAPI.GetPodPort(hc.httpClient, pod.Namespace, pod.Name, serviceMirrorAdminPort, "/metrics")
+		if err != nil {
+			return fmt.Errorf("The \"%s\" service mirror's admin endpoint is unreachable: %s", pod.Name, err)
+		}
+		families, err := ParseProxyMetrics(body)
+		if err != nil {
+			return fmt.Errorf("The \"%s\" service mirror served invalid metrics: %s", pod.Name, err)
+		}
+		metrics[pod.Labels[k8s.RemoteClusterNameLabel]] = families
+	}
+
+	return validateGateways(hc.links, metrics)
+}
+
+// validateGateways checks the linkerd_gateway_alive metric of each Link's
+// service mirror, whose metrics are keyed by the name of the remote cluster.
+func validateGateways(links []v1alpha1.Link, metrics map[string]map[string]*dto.MetricFamily) error {
+	for _, link := range links {
+		cluster := link.Spec.TargetClusterName
+		families, ok := metrics[cluster]
+		if !ok {
+			return fmt.Errorf("The service mirror of cluster \"%s\" isn't running", cluster)
+		}
+		alive, ok := families["linkerd_gateway_alive"]
+		if !ok || len(alive.GetMetric()) == 0 {
+			return fmt.Errorf("The service mirror of cluster \"%s\" doesn't probe its gateway; link the cluster again to configure the probes", cluster)
+		}
+		if alive.GetMetric()[0].GetGauge().GetValue() != 1 {
+			return fmt.Errorf("The gateway of cluster \"%s\" at %s is unreachable", cluster, link.Spec.GatewayAddress)
+		}
+	}
+	return nil
+}
+
+// checkMirrors verifies that the mirrored services of each Link are up to
+// date with the remote services they mirror, according to their
+// RemoteResourceVersionAnnotation.
+func (hc *HealthChecker) checkMirrors() error {
+	clientset, err := hc.kubeClientset()
+	if err != nil {
+		return err
+	}
+
+	problems := []string{}
+	for _, link := range hc.links {
+		cluster := link.Spec.TargetClusterName
+		remote, ok := hc.remoteClientsets[cluster]
+		if !ok {
+			continue
+		}
+
+		mirrors, err := clientset.CoreV1().Services("").List(meta_v1.ListOptions{LabelSelector: mirrorSelector(cluster).String()})
+		if err != nil {
+			return err
+		}
+		for _, mirror := range mirrors.Items {
+			name := strings.TrimSuffix(mirror.Name, "-"+cluster)
+			service, err := remote.CoreV1().Services(mirror.Namespace).Get(name, meta_v1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				problems = append(problems, fmt.Sprintf("%s/%s mirrors %s/%s, which doesn't exist in cluster \"%s\"", mirror.Namespace, mirror.Name, mirror.Namespace, name, cluster))
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if version := mirror.Annotations[k8s.RemoteResourceVersionAnnotation]; version != service.ResourceVersion {
+				problems = append(problems, fmt.Sprintf("%s/%s mirrors version %s of %s/%s in cluster \"%s\", which is at version %s", mirror.Namespace, mirror.Name, version, mirror.Namespace, name, cluster, service.ResourceVersion))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("%d mirrored services are out of date:\n    * %s", len(problems), strings.Join(problems, "\n    * "))
+	}
+	return nil
+}
+
+func mirrorSelector(cluster string) labels.Selector {
+	return labels.SelectorFromSet(labels.Set{
+		k8s.MirroredServiceLabel:   "true",
+		k8s.RemoteClusterNameLabel: cluster,
+	})
+}
@@ -0,0 +1,199 @@
+package healthcheck
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	spfake "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned/fake"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func testLink(identity string) *v1alpha1.Link {
+	return &v1alpha1.Link{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "east", Namespace: "linkerd"},
+		Spec: v1alpha1.LinkSpec{
+			TargetClusterName:        "east",
+			TargetClusterDomain:      "cluster.local",
+			ClusterCredentialsSecret: "cluster-credentials-east",
+			GatewayAddress:           "203.0.113.10",
+			GatewayPort:              4143,
+			GatewayIdentity:          identity,
+		},
+	}
+}
+
+func mirroredService(name, version, identity string) []runtime.Object {
+	meta := meta_v1.ObjectMeta{
+		Name:      name + "-east",
+		Namespace: "emojivoto",
+		Labels: map[string]string{
+			k8s.MirroredServiceLabel:   "true",
+			k8s.RemoteClusterNameLabel: "east",
+		},
+		Annotations: map[string]string{
+			k8s.RemoteResourceVersionAnnotation: version,
+			k8s.RemoteGatewayIdentityAnnotation: identity,
+		},
+	}
+	return []runtime.Object{
+		&v1.Service{ObjectMeta: meta},
+		&v1.Endpoints{ObjectMeta: meta},
+	}
+}
+
+func remoteService(name, version string) *v1.Service {
+	return &v1.Service{ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "emojivoto", ResourceVersion: version}}
+}
+
+func TestMulticlusterChecks(t *testing.T) {
+	identity := "linkerd-gateway.linkerd-multicluster.serviceaccount.identity.linkerd.cluster.local"
+	secret := &v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "cluster-credentials-east", Namespace: "linkerd"},
+		Data:       map[string][]byte{k8s.ClusterCredentialsKey: []byte("kubeconfig")},
+	}
+
+	newChecker := func(link *v1alpha1.Link, local []runtime.Object, remote kubernetes.Interface) *HealthChecker {
+		hc := NewHealthChecker([]CategoryID{}, &Options{ControlPlaneNamespace: "linkerd"})
+		if link != nil {
+			hc.spClientset = spfake.NewSimpleClientset(link)
+		} else {
+			hc.spClientset = spfake.NewSimpleClientset()
+		}
+		hc.clientset = k8sfake.NewSimpleClientset(local...)
+		hc.remoteClientset = func(kubeconfig []byte) (kubernetes.Interface, error) {
+			if string(kubeconfig) != "kubeconfig" {
+				return nil, errors.New("invalid kubeconfig")
+			}
+			return remote, nil
+		}
+		return hc
+	}
+
+	t.Run("Fails without links", func(t *testing.T) {
+		hc := newChecker(nil, nil, nil)
+		err := hc.checkLinks()
+		expected := "No clusters are linked to the \"linkerd\" namespace; link them with \"linkerd multicluster link\""
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q, got: %v", expected, err)
+		}
+	})
+
+	t.Run("Fails without the credentials secret", func(t *testing.T) {
+		hc := newChecker(testLink(identity), nil, k8sfake.NewSimpleClientset())
+		if err := hc.checkLinks(); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		err := hc.checkLinkCredentials()
+		expected := "The credentials secret \"cluster-credentials-east\" of cluster \"east\" doesn't exist"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q, got: %v", expected, err)
+		}
+	})
+
+	t.Run("Passes with up-to-date mirrors", func(t *testing.T) {
+		local := append([]runtime.Object{secret}, mirroredService("web", "42", identity)...)
+		remote := k8sfake.NewSimpleClientset(remoteService("web", "42"))
+		hc := newChecker(testLink(identity), local, remote)
+
+		for _, check := range []func() error{hc.checkLinks, hc.checkLinkCredentials, hc.checkGatewayIdentities, hc.checkMirrors} {
+			if err := check(); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		}
+	})
+
+	t.Run("Fails when the mirrors have another gateway identity", func(t *testing.T) {
+		local := append([]runtime.Object{secret}, mirroredService("web", "42", "old.identity")...)
+		hc := newChecker(testLink(identity), local, k8sfake.NewSimpleClientset())
+		if err := hc.checkLinks(); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		err := hc.checkGatewayIdentities()
+		if err == nil || !strings.Contains(err.Error(), "have the gateway identity \"old.identity\"") {
+			t.Fatalf("Expected a gateway identity error, got: %v", err)
+		}
+	})
+
+	t.Run("Reports stale and orphaned mirrors", func(t *testing.T) {
+		local := append([]runtime.Object{secret}, mirroredService("web", "42", identity)...)
+		local = append(local, mirroredService("voting", "7", identity)...)
+		remote := k8sfake.NewSimpleClientset(remoteService("web", "43"))
+		hc := newChecker(testLink(identity), local, remote)
+		if err := hc.checkLinks(); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := hc.checkLinkCredentials(); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		err := hc.checkMirrors()
+		expected := `2 mirrored services are out of date:
+    * emojivoto/voting-east mirrors emojivoto/voting, which doesn't exist in cluster "east"
+    * emojivoto/web-east mirrors version 42 of emojivoto/web in cluster "east", which is at version 43`
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q, got: %v", expected, err)
+		}
+	})
+}
+
+func TestValidateGateways(t *testing.T) {
+	links := []v1alpha1.Link{*testLink("")}
+
+	testCases := []struct {
+		title   string
+		metrics map[string]string
+		err     string
+	}{
+		{
+			title:   "returns nil if the gateway is alive",
+			metrics: map[string]string{"east": "# TYPE linkerd_gateway_alive gauge\nlinkerd_gateway_alive{target_cluster_name=\"east\"} 1\n"},
+		},
+		{
+			title:   "returns an error if the gateway is unreachable",
+			metrics: map[string]string{"east": "# TYPE linkerd_gateway_alive gauge\nlinkerd_gateway_alive{target_cluster_name=\"east\"} 0\n"},
+			err:     "The gateway of cluster \"east\" at 203.0.113.10 is unreachable",
+		},
+		{
+			title:   "returns an error if the gateway isn't probed",
+			metrics: map[string]string{"east": "process_start_time_seconds 1\n"},
+			err:     "The service mirror of cluster \"east\" doesn't probe its gateway; link the cluster again to configure the probes",
+		},
+		{
+			title:   "returns an error if the service mirror isn't running",
+			metrics: map[string]string{},
+			err:     "The service mirror of cluster \"east\" isn't running",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			metrics := make(map[string]map[string]*dto.MetricFamily)
+			for cluster, body := range tc.metrics {
+				families, err := ParseProxyMetrics([]byte(body))
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				metrics[cluster] = families
+			}
+
+			err := validateGateways(links, metrics)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+			} else if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error %q, got: %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package healthcheck
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// proxyInjectorRules are the RBAC rules the proxy injector requires. They
+// must be kept in sync with its ClusterRole in the install template.
+var proxyInjectorRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{"admissionregistration.k8s.io"},
+		Resources: []string{"mutatingwebhookconfigurations"},
+		Verbs:     []string{"create", "update", "get", "watch"},
+	},
+	{
+		APIGroups: []string{"admissionregistration.k8s.io"},
+		Resources: []string{"validatingwebhookconfigurations"},
+		Verbs:     []string{"create", "update", "get"},
+	},
+	{
+		APIGroups: []string{""},
+		Resources: []string{"namespaces"},
+		Verbs:     []string{"get"},
+	},
+	{
+		APIGroups: []string{""},
+		Resources: []string{"events"},
+		Verbs:     []string{"create", "patch"},
+	},
+}
+
+func proxyInjectorClusterRoleName(controlPlaneNamespace string) string {
+	return fmt.Sprintf("linkerd-%s-proxy-injector", controlPlaneNamespace)
+}
+
+func (hc *HealthChecker) kubeClientset() (kubernetes.Interface, error) {
+	if hc.clientset == nil {
+		var err error
+		hc.clientset, err = kubernetes.NewForConfig(hc.kubeAPI.Config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return hc.clientset, nil
+}
+
+// proxyInjectorInstalled fetches the proxy injector's webhook configuration,
+// and returns false if it doesn't exist, in which case the proxy injector
+// checks pass trivially.
+func (hc *HealthChecker) proxyInjectorInstalled() (bool, error) {
+	clientset, err := hc.kubeClientset()
+	if err != nil {
+		return false, err
+	}
+
+	hc.webhookConfig, err = clientset.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(k8s.ProxyInjectorWebhookConfig, meta_v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		hc.webhookConfig = nil
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (hc *HealthChecker) trustAnchors() ([]byte, error) {
+	configMap, err := hc.clientset.CoreV1().ConfigMaps(hc.ControlPlaneNamespace).Get(k8s.TLSTrustAnchorConfigMapName, meta_v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	trustAnchors, ok := configMap.Data[k8s.TLSTrustAnchorFileName]
+	if !ok {
+		return nil, fmt.Errorf("The %s ConfigMap has no %s entry", k8s.TLSTrustAnchorConfigMapName, k8s.TLSTrustAnchorFileName)
+	}
+	return []byte(trustAnchors), nil
+}
+
+func (hc *HealthChecker) checkWebhookCABundle() error {
+	installed, err := hc.proxyInjectorInstalled()
+	if err != nil || !installed {
+		return err
+	}
+
+	trustAnchors, err := hc.trustAnchors()
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range hc.webhookConfig.Webhooks {
+		if !bytes.Equal(webhook.ClientConfig.CABundle, trustAnchors) {
+			return fmt.Errorf("The CA bundle of the %s webhook doesn't match the trust anchors in the %s ConfigMap", webhook.Name, k8s.TLSTrustAnchorConfigMapName)
+		}
+	}
+	return nil
+}
+
+func (hc *HealthChecker) fixWebhookCABundle() error {
+	trustAnchors, err := hc.trustAnchors()
+	if err != nil {
+		return err
+	}
+
+	for i := range hc.webhookConfig.Webhooks {
+		hc.webhookConfig.Webhooks[i].ClientConfig.CABundle = trustAnchors
+	}
+
+	_, err = hc.clientset.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Update(hc.webhookConfig)
+	return err
+}
+
+func (hc *HealthChecker) checkControlPlaneNamespaceLabel() error {
+	if hc.webhookConfig == nil {
+		return nil
+	}
+
+	ns, err := hc.clientset.CoreV1().Namespaces().Get(hc.ControlPlaneNamespace, meta_v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if ns.Labels[k8s.ProxyAutoInjectLabel] != k8s.ProxyAutoInjectDisabled {
+		return fmt.Errorf("The \"%s\" namespace doesn't have the %s=%s label, so the proxy injector may inject the control plane", hc.ControlPlaneNamespace, k8s.ProxyAutoInjectLabel, k8s.ProxyAutoInjectDisabled)
+	}
+	return nil
+}
+
+func (hc *HealthChecker) fixControlPlaneNamespaceLabel() error {
+	ns, err := hc.clientset.CoreV1().Namespaces().Get(hc.ControlPlaneNamespace, meta_v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if ns.Labels == nil {
+		ns.Labels = map[string]string{}
+	}
+	ns.Labels[k8s.ProxyAutoInjectLabel] = k8s.ProxyAutoInjectDisabled
+
+	_, err = hc.clientset.CoreV1().Namespaces().Update(ns)
+	return err
+}
+
+func (hc *HealthChecker) checkProxyInjectorRBAC() error {
+	if hc.webhookConfig == nil {
+		return nil
+	}
+
+	name := proxyInjectorClusterRoleName(hc.ControlPlaneNamespace)
+	role, err := hc.clientset.RbacV1().ClusterRoles().Get(name, meta_v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	missing := missingRules(role.Rules, proxyInjectorRules)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	permissions := []string{}
+	for _, rule := range missing {
+		permissions = append(permissions, fmt.Sprintf("%s %s", strings.Join(rule.Verbs, "/"), rule.Resources[0]))
+	}
+	return fmt.Errorf("The %s ClusterRole is missing permissions to %s", name, strings.Join(permissions, ", "))
+}
+
+func (hc *HealthChecker) fixProxyInjectorRBAC() error {
+	role, err := hc.clientset.RbacV1().ClusterRoles().Get(proxyInjectorClusterRoleName(hc.ControlPlaneNamespace), meta_v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	role.Rules = append(role.Rules, missingRules(role.Rules, proxyInjectorRules)...)
+
+	_, err = hc.clientset.RbacV1().ClusterRoles().Update(role)
+	return err
+}
+
+// missingRules returns, for each of the required rules, a rule with the verbs
+// that the existing rules don't grant. Each required rule must have a single
+// API group and resource.
+func missingRules(existing, required []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	missing := []rbacv1.PolicyRule{}
+	for _, rule := range required {
+		verbs := []string{}
+		for _, verb := range rule.Verbs {
+			if !rulesAllow(existing, rule.APIGroups[0], rule.Resources[0], verb) {
+				verbs = append(verbs, verb)
+			}
+		}
+
+		if len(verbs) > 0 {
+			missing = append(missing, rbacv1.PolicyRule{
+				APIGroups: rule.APIGroups,
+				Resources: rule.Resources,
+				Verbs:     verbs,
+			})
+		}
+	}
+	return missing
+}
+
+func rulesAllow(rules []rbacv1.PolicyRule, group, resource, verb string) bool {
+	for _, rule := range rules {
+		if len(rule.ResourceNames) == 0 &&
+			containsOrWildcard(rule.APIGroups, group) &&
+			containsOrWildcard(rule.Resources, resource) &&
+			containsOrWildcard(rule.Verbs, verb) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsOrWildcard(values []string, value string) bool {
+	for _, v := range values {
+		if v == value || v == rbacv1.ResourceAll {
+			return true
+		}
+	}
+	return false
+}
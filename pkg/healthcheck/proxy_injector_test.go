@@ -0,0 +1,199 @@
+package healthcheck
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	arv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+const testTrustAnchors = "-----BEGIN CERTIFICATE-----\ntrust anchors\n-----END CERTIFICATE-----\n"
+
+func proxyInjectorObjects(caBundle string, nsLabels map[string]string, rules []rbacv1.PolicyRule) []runtime.Object {
+	return []runtime.Object{
+		&arv1beta1.MutatingWebhookConfiguration{
+			ObjectMeta: meta_v1.ObjectMeta{Name: k8s.ProxyInjectorWebhookConfig},
+			Webhooks: []arv1beta1.Webhook{
+				{
+					Name:         "linkerd-proxy-injector.linkerd.io",
+					ClientConfig: arv1beta1.WebhookClientConfig{CABundle: []byte(caBundle)},
+				},
+			},
+		},
+		&v1.ConfigMap{
+			ObjectMeta: meta_v1.ObjectMeta{Name: k8s.TLSTrustAnchorConfigMapName, Namespace: "linkerd"},
+			Data:       map[string]string{k8s.TLSTrustAnchorFileName: testTrustAnchors},
+		},
+		&v1.Namespace{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "linkerd", Labels: nsLabels},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "linkerd-linkerd-proxy-injector"},
+			Rules:      rules,
+		},
+	}
+}
+
+func TestProxyInjectorChecks(t *testing.T) {
+	nullObserver := func(_ *CheckResult) {}
+	disabled := map[string]string{k8s.ProxyAutoInjectLabel: k8s.ProxyAutoInjectDisabled}
+
+	t.Run("Pass when the proxy injector isn't installed", func(t *testing.T) {
+		hc := NewHealthChecker([]CategoryID{LinkerdProxyInjectorChecks}, &Options{ControlPlaneNamespace: "linkerd"})
+		hc.clientset = k8sfake.NewSimpleClientset()
+
+		if !hc.RunChecks(nullObserver) {
+			t.Fatal("Expected the checks to pass without a proxy injector")
+		}
+	})
+
+	t.Run("Pass when the proxy injector matches the control plane", func(t *testing.T) {
+		hc := NewHealthChecker([]CategoryID{LinkerdProxyInjectorChecks}, &Options{ControlPlaneNamespace: "linkerd"})
+		hc.clientset = k8sfake.NewSimpleClientset(proxyInjectorObjects(testTrustAnchors, disabled, proxyInjectorRules)...)
+
+		if !hc.RunChecks(nullObserver) {
+			t.Fatal("Expected the checks to pass")
+		}
+	})
+
+	t.Run("Fix the proxy injector configuration after confirmation", func(t *testing.T) {
+		var fixed []string
+		hc := NewHealthChecker(
+			[]CategoryID{LinkerdProxyInjectorChecks},
+			&Options{
+				ControlPlaneNamespace: "linkerd",
+				Fix:                   true,
+				ConfirmFix:            func(string, string) bool { return true },
+			},
+		)
+		clientset := k8sfake.NewSimpleClientset(proxyInjectorObjects("stale", nil, []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"admissionregistration.k8s.io"},
+				Resources: []string{"mutatingwebhookconfigurations"},
+				Verbs:     []string{"get", "watch"},
+			},
+		})...)
+		hc.clientset = clientset
+
+		observer := func(result *CheckResult) {
+			if result.Err != nil {
+				t.Fatalf("Unexpected error for \"%s\": %s", result.Description, result.Err)
+			}
+			if result.Fixed != "" {
+				fixed = append(fixed, result.Fixed)
+			}
+		}
+		if !hc.RunChecks(observer) {
+			t.Fatal("Expected the checks to pass after being fixed")
+		}
+		if len(fixed) != 3 {
+			t.Fatalf("Expected 3 fixes, got: %v", fixed)
+		}
+
+		webhookConfig, err := clientset.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(k8s.ProxyInjectorWebhookConfig, meta_v1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if string(webhookConfig.Webhooks[0].ClientConfig.CABundle) != testTrustAnchors {
+			t.Fatalf("Expected the CA bundle to be updated, got: %s", webhookConfig.Webhooks[0].ClientConfig.CABundle)
+		}
+
+		ns, err := clientset.CoreV1().Namespaces().Get("linkerd", meta_v1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(ns.Labels, disabled) {
+			t.Fatalf("Expected the namespace to be labeled, got: %v", ns.Labels)
+		}
+
+		role, err := clientset.RbacV1().ClusterRoles().Get("linkerd-linkerd-proxy-injector", meta_v1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if missing := missingRules(role.Rules, proxyInjectorRules); len(missing) != 0 {
+			t.Fatalf("Expected the ClusterRole to have all rules, missing: %v", missing)
+		}
+	})
+
+	t.Run("Leave the cluster unchanged without confirmation", func(t *testing.T) {
+		hc := NewHealthChecker(
+			[]CategoryID{LinkerdProxyInjectorChecks},
+			&Options{
+				ControlPlaneNamespace: "linkerd",
+				Fix:                   true,
+				ConfirmFix:            func(string, string) bool { return false },
+			},
+		)
+		clientset := k8sfake.NewSimpleClientset(proxyInjectorObjects("stale", nil, nil)...)
+		hc.clientset = clientset
+
+		failures := 0
+		observer := func(result *CheckResult) {
+			if result.Err != nil {
+				failures++
+			}
+		}
+		if hc.RunChecks(observer) {
+			t.Fatal("Expected the checks to fail")
+		}
+		if failures != 3 {
+			t.Fatalf("Expected 3 failed checks, got %d", failures)
+		}
+
+		for _, action := range clientset.Actions() {
+			if action.GetVerb() != "get" {
+				t.Fatalf("Expected no changes to the cluster, got: %+v", action)
+			}
+		}
+	})
+}
+
+func TestMissingRules(t *testing.T) {
+	existing := []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{"admissionregistration.k8s.io"},
+			Resources: []string{"*"},
+			Verbs:     []string{"get", "create"},
+		},
+		{
+			APIGroups:     []string{""},
+			Resources:     []string{"namespaces"},
+			Verbs:         []string{"get"},
+			ResourceNames: []string{"linkerd"},
+		},
+	}
+
+	expected := []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{"admissionregistration.k8s.io"},
+			Resources: []string{"mutatingwebhookconfigurations"},
+			Verbs:     []string{"update", "watch"},
+		},
+		{
+			APIGroups: []string{"admissionregistration.k8s.io"},
+			Resources: []string{"validatingwebhookconfigurations"},
+			Verbs:     []string{"update"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"namespaces"},
+			Verbs:     []string{"get"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"events"},
+			Verbs:     []string{"create", "patch"},
+		},
+	}
+
+	missing := missingRules(existing, proxyInjectorRules)
+	if !reflect.DeepEqual(missing, expected) {
+		t.Fatalf("Expected missing rules %+v, got %+v", expected, missing)
+	}
+}
@@ -1,6 +1,7 @@
 package healthcheck
 
 import (
+	"fmt"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -35,3 +36,35 @@ func HasExistingSidecars(podSpec *corev1.PodSpec) bool {
 
 	return false
 }
+
+// ProxyConflicts returns a description of each conflict between the
+// containers of the pod spec and a proxy listening on proxyPorts and running
+// as proxyUID. A container that listens on one of the proxy's ports prevents
+// the proxy from starting, and a container that runs as the proxy's user ID
+// bypasses the proxy entirely.
+func ProxyConflicts(podSpec *corev1.PodSpec, proxyPorts []int32, proxyUID int64) []string {
+	conflicts := []string{}
+
+	podSecurityContext := podSpec.SecurityContext
+	for _, container := range podSpec.Containers {
+		for _, port := range container.Ports {
+			for _, proxyPort := range proxyPorts {
+				if port.ContainerPort == proxyPort {
+					conflicts = append(conflicts, fmt.Sprintf("container \"%s\" uses port %d, which is reserved for the proxy", container.Name, proxyPort))
+				}
+			}
+		}
+
+		var uid *int64
+		if container.SecurityContext != nil && container.SecurityContext.RunAsUser != nil {
+			uid = container.SecurityContext.RunAsUser
+		} else if podSecurityContext != nil {
+			uid = podSecurityContext.RunAsUser
+		}
+		if uid != nil && *uid == proxyUID {
+			conflicts = append(conflicts, fmt.Sprintf("container \"%s\" runs as user ID %d, which is reserved for the proxy", container.Name, proxyUID))
+		}
+	}
+
+	return conflicts
+}
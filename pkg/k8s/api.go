@@ -1,12 +1,15 @@
 package k8s
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"k8s.io/api/core/v1"
@@ -102,6 +105,78 @@ func (kubeAPI *KubernetesAPI) GetPodsByNamespace(client *http.Client, namespace
 	return kubeAPI.getPods(client, "/api/v1/namespaces/"+namespace+"/pods")
 }
 
+// GetAllPods returns all pods in the cluster
+func (kubeAPI *KubernetesAPI) GetAllPods(client *http.Client) ([]v1.Pod, error) {
+	return kubeAPI.getPods(client, "/api/v1/pods")
+}
+
+// GetPodPort returns the body of the response to a GET request for path on
+// the given port of a pod, which the Kubernetes API proxies to the pod.
+func (kubeAPI *KubernetesAPI) GetPodPort(client *http.Client, namespace, pod string, port int32, path string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rsp, err := kubeAPI.getRequest(ctx, client, fmt.Sprintf("/api/v1/namespaces/%s/pods/%s:%d/proxy%s", namespace, pod, port, path))
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected Kubernetes API response: %s", rsp.Status)
+	}
+
+	return ioutil.ReadAll(rsp.Body)
+}
+
+// GetPodLogs returns the last tailLines lines of the logs of a container of a
+// pod, or all of them if tailLines is zero.
+func (kubeAPI *KubernetesAPI) GetPodLogs(client *http.Client, namespace, pod, container string, tailLines int64) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/log?container=%s", namespace, pod, url.QueryEscape(container))
+	if tailLines > 0 {
+		path += fmt.Sprintf("&tailLines=%d", tailLines)
+	}
+	rsp, err := kubeAPI.getRequest(ctx, client, path)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected Kubernetes API response: %s", rsp.Status)
+	}
+
+	return ioutil.ReadAll(rsp.Body)
+}
+
+// PutPodPort sends body in a PUT request for path on the given port of a pod,
+// which the Kubernetes API proxies to the pod, and returns the body of the
+// response.
+func (kubeAPI *KubernetesAPI) PutPodPort(client *http.Client, namespace, pod string, port int32, path string, body []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rsp, err := kubeAPI.request(ctx, client, "PUT", fmt.Sprintf("/api/v1/namespaces/%s/pods/%s:%d/proxy%s", namespace, pod, port, path), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		// the admin servers explain why a request was rejected in the body
+		msg, _ := ioutil.ReadAll(rsp.Body)
+		if len(bytes.TrimSpace(msg)) > 0 {
+			return nil, fmt.Errorf("Unexpected Kubernetes API response: %s: %s", rsp.Status, bytes.TrimSpace(msg))
+		}
+		return nil, fmt.Errorf("Unexpected Kubernetes API response: %s", rsp.Status)
+	}
+
+	return ioutil.ReadAll(rsp.Body)
+}
+
 func (kubeAPI *KubernetesAPI) getPods(client *http.Client, path string) ([]v1.Pod, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -130,18 +205,158 @@ func (kubeAPI *KubernetesAPI) getPods(client *http.Client, path string) ([]v1.Po
 	return podList.Items, nil
 }
 
+// GetCRDStoredVersions returns the versions in which the objects of the
+// custom resource definition with the given name are stored, or nil if the
+// definition doesn't exist.
+func (kubeAPI *KubernetesAPI) GetCRDStoredVersions(client *http.Client, name string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rsp, err := kubeAPI.getRequest(ctx, client, "/apis/apiextensions.k8s.io/v1beta1/customresourcedefinitions/"+name)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected Kubernetes API response: %s", rsp.Status)
+	}
+
+	bytes, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var crd struct {
+		Status struct {
+			StoredVersions []string `json:"storedVersions"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(bytes, &crd); err != nil {
+		return nil, err
+	}
+	if crd.Status.StoredVersions == nil {
+		return []string{}, nil
+	}
+	return crd.Status.StoredVersions, nil
+}
+
+// GetResource returns the JSON representation of the resource with the given
+// apiVersion, kind and name, or nil if it doesn't exist. The namespace is empty
+// for cluster-wide resources.
+func (kubeAPI *KubernetesAPI) GetResource(client *http.Client, apiVersion, kind, namespace, name string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rsp, err := kubeAPI.getRequest(ctx, client, resourcePath(apiVersion, kind, namespace, name))
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected Kubernetes API response: %s", rsp.Status)
+	}
+
+	return ioutil.ReadAll(rsp.Body)
+}
+
+// DeleteResource deletes the resource with the given apiVersion, kind and
+// name, along with the resources it owns, such as the replica sets of a
+// deployment. It returns false if the resource doesn't exist.
+func (kubeAPI *KubernetesAPI) DeleteResource(client *http.Client, apiVersion, kind, namespace, name string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	endpoint, err := url.Parse(kubeAPI.Host + resourcePath(apiVersion, kind, namespace, name))
+	if err != nil {
+		return false, err
+	}
+	// the older API groups orphan the owned resources by default
+	body := strings.NewReader(`{"kind":"DeleteOptions","apiVersion":"v1","propagationPolicy":"Background"}`)
+	req, err := http.NewRequest("DELETE", endpoint.String(), body)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	defer rsp.Body.Close()
+
+	switch rsp.StatusCode {
+	case http.StatusOK, http.StatusAccepted:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("Unexpected Kubernetes API response: %s", rsp.Status)
+	}
+}
+
+// clusterScopedKinds are the kinds of the cluster-wide resources of the
+// control plane, which can be rendered with a namespace regardless.
+var clusterScopedKinds = map[string]struct{}{
+	"APIService":                     {},
+	"ClusterRole":                    {},
+	"ClusterRoleBinding":             {},
+	"CustomResourceDefinition":       {},
+	"MutatingWebhookConfiguration":   {},
+	"Namespace":                      {},
+	"ValidatingWebhookConfiguration": {},
+}
+
+// resourcePath returns the API path of a resource. The plural name of the
+// resource is derived from its kind, which holds for all the kinds of the
+// control plane.
+func resourcePath(apiVersion, kind, namespace, name string) string {
+	path := "/apis/" + apiVersion
+	if apiVersion == "v1" {
+		path = "/api/v1"
+	}
+	if _, ok := clusterScopedKinds[kind]; ok {
+		namespace = ""
+	}
+	if namespace != "" {
+		path += "/namespaces/" + namespace
+	}
+
+	resource := strings.ToLower(kind)
+	switch {
+	case strings.HasSuffix(resource, "y"):
+		resource = strings.TrimSuffix(resource, "y") + "ies"
+	case strings.HasSuffix(resource, "s"):
+		resource += "es"
+	default:
+		resource += "s"
+	}
+	return fmt.Sprintf("%s/%s/%s", path, resource, name)
+}
+
 // URLFor generates a URL based on the Kubernetes config.
 func (kubeAPI *KubernetesAPI) URLFor(namespace string, extraPathStartingWithSlash string) (*url.URL, error) {
 	return generateKubernetesAPIBaseURLFor(kubeAPI.Host, namespace, extraPathStartingWithSlash)
 }
 
 func (kubeAPI *KubernetesAPI) getRequest(ctx context.Context, client *http.Client, path string) (*http.Response, error) {
+	return kubeAPI.request(ctx, client, "GET", path, nil)
+}
+
+func (kubeAPI *KubernetesAPI) request(ctx context.Context, client *http.Client, method, path string, body io.Reader) (*http.Response, error) {
 	endpoint, err := url.Parse(kubeAPI.Host + path)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("GET", endpoint.String(), nil)
+	req, err := http.NewRequest(method, endpoint.String(), body)
 	if err != nil {
 		return nil, err
 	}
@@ -150,12 +365,19 @@ func (kubeAPI *KubernetesAPI) getRequest(ctx context.Context, client *http.Clien
 }
 
 // NewAPI validates a Kubernetes config and returns a client for accessing the
-// configured cluster
-func NewAPI(configPath, kubeContext string) (*KubernetesAPI, error) {
+// configured cluster. If impersonate is set, the requests are made as that
+// user, and as the members of impersonateGroup.
+func NewAPI(configPath, kubeContext, impersonate string, impersonateGroup []string) (*KubernetesAPI, error) {
 	config, err := GetConfig(configPath, kubeContext)
 	if err != nil {
 		return nil, fmt.Errorf("error configuring Kubernetes API client: %v", err)
 	}
+	if impersonate != "" {
+		config.Impersonate = rest.ImpersonationConfig{
+			UserName: impersonate,
+			Groups:   impersonateGroup,
+		}
+	}
 
 	return &KubernetesAPI{Config: config}, nil
 }
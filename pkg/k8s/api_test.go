@@ -2,7 +2,14 @@ package k8s
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+
+	"k8s.io/client-go/rest"
 )
 
 func TestKubernetesApiUrlFor(t *testing.T) {
@@ -11,7 +18,7 @@ func TestKubernetesApiUrlFor(t *testing.T) {
 
 	t.Run("Returns base config containing k8s endpoint listed in config.test", func(t *testing.T) {
 		expected := fmt.Sprintf("https://55.197.171.239/api/v1/namespaces/%s%s", namespace, extraPath)
-		api, err := NewAPI("testdata/config.test", "")
+		api, err := NewAPI("testdata/config.test", "", "", nil)
 		if err != nil {
 			t.Fatalf("Unexpected error creating Kubernetes API: %+v", err)
 		}
@@ -24,3 +31,265 @@ func TestKubernetesApiUrlFor(t *testing.T) {
 		}
 	})
 }
+
+func TestNewAPIImpersonation(t *testing.T) {
+	t.Run("Doesn't impersonate by default", func(t *testing.T) {
+		api, err := NewAPI("testdata/config.test", "", "", []string{"ops"})
+		if err != nil {
+			t.Fatalf("Unexpected error creating Kubernetes API: %+v", err)
+		}
+		if !reflect.DeepEqual(api.Config.Impersonate, rest.ImpersonationConfig{}) {
+			t.Fatalf("Expected no impersonation, got %+v", api.Config.Impersonate)
+		}
+	})
+
+	t.Run("Sends the impersonation headers", func(t *testing.T) {
+		headers := make(chan http.Header, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			headers <- r.Header
+		}))
+		defer server.Close()
+
+		api, err := NewAPI("testdata/config-token.test", "", "jane", []string{"ops", "dev"})
+		if err != nil {
+			t.Fatalf("Unexpected error creating Kubernetes API: %+v", err)
+		}
+		api.Config.Host = server.URL
+		api.Config.TLSClientConfig = rest.TLSClientConfig{}
+
+		client, err := api.NewClient()
+		if err != nil {
+			t.Fatalf("Unexpected error creating client: %+v", err)
+		}
+		rsp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		rsp.Body.Close()
+
+		header := <-headers
+		if user := header.Get("Impersonate-User"); user != "jane" {
+			t.Fatalf("Expected to impersonate jane, got %q", user)
+		}
+		if groups := header["Impersonate-Group"]; !reflect.DeepEqual(groups, []string{"ops", "dev"}) {
+			t.Fatalf("Expected to impersonate the ops and dev groups, got %v", groups)
+		}
+	})
+}
+
+func TestGetCRDStoredVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/apis/apiextensions.k8s.io/v1beta1/customresourcedefinitions/serviceprofiles.linkerd.io":
+			fmt.Fprint(w, `{"kind":"CustomResourceDefinition","status":{"storedVersions":["v1alpha1"]}}`)
+		case "/apis/apiextensions.k8s.io/v1beta1/customresourcedefinitions/new.linkerd.io":
+			fmt.Fprint(w, `{"kind":"CustomResourceDefinition","status":{}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+	testCases := []struct {
+		name     string
+		expected []string
+	}{
+		{"serviceprofiles.linkerd.io", []string{"v1alpha1"}},
+		{"new.linkerd.io", []string{}},
+		{"missing.linkerd.io", nil},
+	}
+
+	for _, tc := range testCases {
+		versions, err := api.GetCRDStoredVersions(server.Client(), tc.name)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %s", tc.name, err)
+		}
+		if !reflect.DeepEqual(versions, tc.expected) {
+			t.Fatalf("Expected stored versions %v for %s, got %v", tc.expected, tc.name, versions)
+		}
+	}
+}
+
+func TestGetPodPort(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/namespaces/emojivoto/pods/web-1:4191/proxy/metrics":
+			fmt.Fprint(w, "process_start_time_seconds 1")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+	body, err := api.GetPodPort(server.Client(), "emojivoto", "web-1", 4191, "/metrics")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if string(body) != "process_start_time_seconds 1" {
+		t.Fatalf("Unexpected response body: %s", body)
+	}
+
+	_, err = api.GetPodPort(server.Client(), "emojivoto", "web-2", 4191, "/metrics")
+	if err == nil || err.Error() != "Unexpected Kubernetes API response: 404 Not Found" {
+		t.Fatalf("Expected a 404 error, got: %v", err)
+	}
+}
+
+func TestPutPodPort(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/api/v1/namespaces/linkerd/pods/linkerd-ca-1:9997/proxy/log-level" {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != "debug" {
+			http.Error(w, "not a valid logrus Level: \""+string(body)+"\"", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, "debug")
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+	body, err := api.PutPodPort(server.Client(), "linkerd", "linkerd-ca-1", 9997, "/log-level", []byte("debug"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if string(body) != "debug\n" {
+		t.Fatalf("Unexpected response body: %s", body)
+	}
+
+	_, err = api.PutPodPort(server.Client(), "linkerd", "linkerd-ca-1", 9997, "/log-level", []byte("verbose"))
+	expected := "Unexpected Kubernetes API response: 400 Bad Request: not a valid logrus Level: \"verbose\""
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected error %q, got: %v", expected, err)
+	}
+}
+
+func TestGetPodLogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/linkerd/pods/linkerd-ca-1/log" || r.URL.Query().Get("container") != "ca" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "tailLines=%s", r.URL.Query().Get("tailLines"))
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+	body, err := api.GetPodLogs(server.Client(), "linkerd", "linkerd-ca-1", "ca", 100)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if string(body) != "tailLines=100" {
+		t.Fatalf("Unexpected response body: %s", body)
+	}
+
+	body, err = api.GetPodLogs(server.Client(), "linkerd", "linkerd-ca-1", "ca", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if string(body) != "tailLines=" {
+		t.Fatalf("Unexpected response body: %s", body)
+	}
+
+	_, err = api.GetPodLogs(server.Client(), "linkerd", "linkerd-ca-1", "linkerd-proxy", 0)
+	if err == nil || err.Error() != "Unexpected Kubernetes API response: 404 Not Found" {
+		t.Fatalf("Expected a 404 error, got: %v", err)
+	}
+}
+
+func TestGetResource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/namespaces/linkerd/services/linkerd-controller-api":
+			fmt.Fprint(w, `{"kind":"Service"}`)
+		case "/apis/rbac.authorization.k8s.io/v1/clusterroles/linkerd-linkerd-controller":
+			fmt.Fprint(w, `{"kind":"ClusterRole"}`)
+		case "/apis/networking.k8s.io/v1/namespaces/linkerd/networkpolicies/linkerd-controller":
+			fmt.Fprint(w, `{"kind":"NetworkPolicy"}`)
+		case "/api/v1/namespaces/linkerd/secrets/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+	testCases := []struct {
+		apiVersion string
+		kind       string
+		namespace  string
+		name       string
+		expected   string
+	}{
+		{"v1", "Service", "linkerd", "linkerd-controller-api", `{"kind":"Service"}`},
+		{"rbac.authorization.k8s.io/v1", "ClusterRole", "", "linkerd-linkerd-controller", `{"kind":"ClusterRole"}`},
+		{"rbac.authorization.k8s.io/v1", "ClusterRole", "linkerd", "linkerd-linkerd-controller", `{"kind":"ClusterRole"}`},
+		{"networking.k8s.io/v1", "NetworkPolicy", "linkerd", "linkerd-controller", `{"kind":"NetworkPolicy"}`},
+		{"v1", "Service", "linkerd", "missing", ""},
+	}
+
+	for _, tc := range testCases {
+		resource, err := api.GetResource(server.Client(), tc.apiVersion, tc.kind, tc.namespace, tc.name)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s/%s: %s", tc.kind, tc.name, err)
+		}
+		if string(resource) != tc.expected {
+			t.Fatalf("Expected %q for %s/%s, got %q", tc.expected, tc.kind, tc.name, resource)
+		}
+	}
+
+	_, err := api.GetResource(server.Client(), "v1", "Secret", "linkerd", "forbidden")
+	if err == nil || err.Error() != "Unexpected Kubernetes API response: 403 Forbidden" {
+		t.Fatalf("Expected a 403 error, got: %v", err)
+	}
+}
+
+func TestDeleteResource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/apis/extensions/v1beta1/namespaces/linkerd/deployments/linkerd-controller":
+			if !strings.Contains(string(body), `"propagationPolicy":"Background"`) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"kind":"Status","status":"Success"}`)
+		case "/api/v1/namespaces/linkerd":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+	deleted, err := api.DeleteResource(server.Client(), "extensions/v1beta1", "Deployment", "linkerd", "linkerd-controller")
+	if err != nil || !deleted {
+		t.Fatalf("Expected the deployment to be deleted, got: %t, %v", deleted, err)
+	}
+
+	deleted, err = api.DeleteResource(server.Client(), "v1", "Service", "linkerd", "missing")
+	if err != nil || deleted {
+		t.Fatalf("Expected the missing service not to be deleted, got: %t, %v", deleted, err)
+	}
+
+	_, err = api.DeleteResource(server.Client(), "v1", "Namespace", "", "linkerd")
+	if err == nil || err.Error() != "Unexpected Kubernetes API response: 403 Forbidden" {
+		t.Fatalf("Expected a 403 error, got: %v", err)
+	}
+}
@@ -12,6 +12,7 @@ import (
 const (
 	All                   = "all"
 	Authority             = "authority"
+	CronJob               = "cronjob"
 	DaemonSet             = "daemonset"
 	Deployment            = "deployment"
 	Job                   = "job"
@@ -22,6 +23,7 @@ const (
 	Service               = "service"
 	ServiceProfile        = "serviceprofile"
 	StatefulSet           = "statefulset"
+	TrafficSplit          = "trafficsplit"
 
 	// special case k8s job label, to not conflict with Prometheus' job label
 	l5dJob = "k8s_job"
@@ -30,6 +32,7 @@ const (
 // AllResources is a sorted list of all resources defined as constants above.
 var AllResources = []string{
 	Authority,
+	CronJob,
 	DaemonSet,
 	Deployment,
 	Job,
@@ -40,6 +43,7 @@ var AllResources = []string{
 	Service,
 	ServiceProfile,
 	StatefulSet,
+	TrafficSplit,
 }
 
 // StatAllResourceTypes represents the resources to query in StatSummary when Resource.Type is "all"
@@ -102,6 +106,8 @@ func CanonicalResourceNameFromFriendlyName(friendlyName string) (string, error)
 	switch friendlyName {
 	case "au", "authority", "authorities":
 		return Authority, nil
+	case "cj", "cronjob", "cronjobs":
+		return CronJob, nil
 	case "ds", "daemonset", "daemonsets":
 		return DaemonSet, nil
 	case "deploy", "deployment", "deployments":
@@ -122,6 +128,8 @@ func CanonicalResourceNameFromFriendlyName(friendlyName string) (string, error)
 		return ServiceProfile, nil
 	case "sts", "statefulset", "statefulsets":
 		return StatefulSet, nil
+	case "ts", "trafficsplit", "trafficsplits":
+		return TrafficSplit, nil
 	case "all":
 		return All, nil
 	}
@@ -135,6 +143,8 @@ func ShortNameFromCanonicalResourceName(canonicalName string) string {
 	switch canonicalName {
 	case Authority:
 		return "au"
+	case CronJob:
+		return "cj"
 	case DaemonSet:
 		return "ds"
 	case Deployment:
@@ -155,6 +165,8 @@ func ShortNameFromCanonicalResourceName(canonicalName string) string {
 		return "sp"
 	case StatefulSet:
 		return "sts"
+	case TrafficSplit:
+		return "ts"
 	default:
 		return ""
 	}
@@ -88,6 +88,8 @@ func TestCanonicalResourceNameFromFriendlyName(t *testing.T) {
 			"deployments": Deployment,
 			"au":          Authority,
 			"authorities": Authority,
+			"cj":          CronJob,
+			"cronjobs":    CronJob,
 		}
 
 		for input, expectedName := range expectations {
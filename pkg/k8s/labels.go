@@ -7,6 +7,8 @@ package k8s
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 
 	"github.com/linkerd/linkerd2/pkg/version"
 	appsV1 "k8s.io/api/apps/v1"
@@ -50,6 +52,14 @@ const (
 	// StatefulSet that this proxy belongs to.
 	ProxyStatefulSetLabel = "linkerd.io/proxy-statefulset"
 
+	// ProxyCronJobLabel is injected into mesh-enabled apps, identifying the
+	// CronJob that this proxy belongs to.
+	ProxyCronJobLabel = "linkerd.io/proxy-cronjob"
+
+	// ProxyRolloutLabel is injected into mesh-enabled apps, identifying the
+	// Argo Rollout that this proxy belongs to.
+	ProxyRolloutLabel = "linkerd.io/proxy-rollout"
+
 	/*
 	 * Annotations
 	 */
@@ -62,6 +72,97 @@ const (
 	// (e.g. v0.1.3).
 	ProxyVersionAnnotation = "linkerd.io/proxy-version"
 
+	// ProxyIgnoreConflictsAnnotation can be set to "true" on a pod template to
+	// inject the proxy even if the pod's containers use the proxy's ports or
+	// its user ID.
+	ProxyIgnoreConflictsAnnotation = "linkerd.io/inject-ignore-conflicts"
+
+	// SLOSuccessRateAnnotation can be set on a workload to the percentage of
+	// its requests that are expected to succeed (e.g. 99.9). The dashboard and
+	// `linkerd stat` flag the workloads whose success rate is below it.
+	SLOSuccessRateAnnotation = "linkerd.io/slo-success-rate"
+
+	// ProxyConfigAnnotationsPrefix is the prefix of all the annotations that
+	// configure the injected proxy. When set on a namespace, they are used as
+	// defaults for all the pods injected in that namespace.
+	ProxyConfigAnnotationsPrefix = "config.linkerd.io/"
+
+	// ProxyCPURequestAnnotation sets the CPU request of the proxy container.
+	ProxyCPURequestAnnotation = ProxyConfigAnnotationsPrefix + "proxy-cpu-request"
+
+	// ProxyMemoryRequestAnnotation sets the memory request of the proxy
+	// container.
+	ProxyMemoryRequestAnnotation = ProxyConfigAnnotationsPrefix + "proxy-memory-request"
+
+	// ProxyCPULimitAnnotation sets the CPU limit of the proxy container.
+	ProxyCPULimitAnnotation = ProxyConfigAnnotationsPrefix + "proxy-cpu-limit"
+
+	// ProxyMemoryLimitAnnotation sets the memory limit of the proxy container.
+	ProxyMemoryLimitAnnotation = ProxyConfigAnnotationsPrefix + "proxy-memory-limit"
+
+	// ProxySkipInboundPortsAnnotation is a comma-separated list of ports and
+	// port ranges, such as "25,4000-4100", on which inbound traffic skips the
+	// proxy, in addition to the ports configured at install time.
+	ProxySkipInboundPortsAnnotation = ProxyConfigAnnotationsPrefix + "skip-inbound-ports"
+
+	// ProxySkipOutboundPortsAnnotation is a comma-separated list of ports and
+	// port ranges, such as "25,4000-4100", on which outbound traffic skips the
+	// proxy, in addition to the ports configured at install time.
+	ProxySkipOutboundPortsAnnotation = ProxyConfigAnnotationsPrefix + "skip-outbound-ports"
+
+	// ProxyOpaquePortsAnnotation is a comma-separated list of ports and port
+	// ranges on which the proxy doesn't detect the protocol of the traffic,
+	// such as the ports of server-speaks-first protocols like MySQL or SMTP.
+	// The traffic on these ports is still proxied, and secured with TLS when
+	// enabled. The ranges may cover at most 1024 ports in total.
+	ProxyOpaquePortsAnnotation = ProxyConfigAnnotationsPrefix + "opaque-ports"
+
+	// ProxyLogLevelAnnotation is the log level of the proxy, such as
+	// "warn,linkerd2_proxy=debug", instead of the level set at install time,
+	// so that the debug logs of a single workload can be turned on. Set on a
+	// namespace, it's the default of all the pods in the namespace.
+	ProxyLogLevelAnnotation = ProxyConfigAnnotationsPrefix + "proxy-log-level"
+
+	// IdentityIssuanceLifetimeAnnotation is the lifetime, such as "2h", of the
+	// TLS certificates that the CA issues to the pod's owner, instead of the
+	// CA's default of one year. Unlike the other configuration annotations, it
+	// is read by the CA from the pods, so it can't be set on their namespace.
+	IdentityIssuanceLifetimeAnnotation = ProxyConfigAnnotationsPrefix + "identity-issuance-lifetime"
+
+	// IdentityAliasAnnotation is a legacy identity of the pod's owner, of the
+	// form "<owner-name>.<owner-kind>.<namespace>", that the CA also includes
+	// in the owner's TLS certificates, so that clients expecting the legacy
+	// identity keep working while the owner is renamed or moved to another
+	// namespace. Like the IdentityIssuanceLifetimeAnnotation, it is read by
+	// the CA from the pods. Aliases in another namespace must be allowed by
+	// its IdentityAliasNamespacesAnnotation.
+	IdentityAliasAnnotation = ProxyConfigAnnotationsPrefix + "identity-alias"
+
+	// IdentityAliasExpiryAnnotation is the time, in RFC 3339 format, until
+	// which the IdentityAliasAnnotation is honored. It is required, so that
+	// aliases are only used during migrations.
+	IdentityAliasExpiryAnnotation = ProxyConfigAnnotationsPrefix + "identity-alias-expiry"
+
+	// IdentityAliasNamespacesAnnotation is set on a namespace to the
+	// comma-separated list of the namespaces whose pods may alias the
+	// identities of this namespace's pod owners.
+	IdentityAliasNamespacesAnnotation = ProxyConfigAnnotationsPrefix + "identity-alias-namespaces"
+
+	// ProxyEnableDebugAnnotation can be set to "true" to inject a debug
+	// container alongside the proxy, with tools such as tshark, iproute2 and
+	// curl to troubleshoot the pod's network.
+	ProxyEnableDebugAnnotation = ProxyConfigAnnotationsPrefix + "enable-debug-sidecar"
+
+	// ProxyShutdownOnCompletionAnnotation can be set to "true" on the pods of
+	// Jobs and CronJobs, so that the proxy shuts down once their containers
+	// exit, and the pods complete instead of running as long as the proxy
+	// does. The injector runs the proxy with `proxy-init await` and makes the
+	// pod share its process namespace, which requires Kubernetes 1.12 or the
+	// PodShareProcessNamespace feature gate. Set on a namespace, it's the
+	// default of all the Jobs and CronJobs in the namespace; it's ignored on
+	// the other workloads.
+	ProxyShutdownOnCompletionAnnotation = ProxyConfigAnnotationsPrefix + "shutdown-on-completion"
+
 	// ProxyAutoInjectLabel indicates if sidecar auto-inject should be performed
 	// on the pod. Supported values are "enabled", "disabled" or "completed".
 	ProxyAutoInjectLabel = "linkerd.io/auto-inject"
@@ -78,6 +179,57 @@ const (
 	// indicate that the sidecar auto-inject is completed for a particular resource.
 	ProxyAutoInjectCompleted = "completed"
 
+	// ProxyInjectorNamespaceSelectorOptOut makes the proxy injector inject all
+	// the namespaces, except for those with the ProxyAutoInjectLabel label set
+	// to ProxyAutoInjectDisabled.
+	ProxyInjectorNamespaceSelectorOptOut = "opt-out"
+
+	// ProxyInjectorNamespaceSelectorOptIn makes the proxy injector only inject
+	// the namespaces with the ProxyAutoInjectLabel label set to
+	// ProxyAutoInjectEnabled.
+	ProxyInjectorNamespaceSelectorOptIn = "opt-in"
+
+	/*
+	 * Multicluster
+	 */
+
+	// MirroredServiceLabel is set to "true" on the services and endpoints
+	// that the service mirror creates for the services of remote clusters.
+	MirroredServiceLabel = "mirror.linkerd.io/mirrored-service"
+
+	// RemoteClusterNameLabel identifies the remote cluster, as named by its
+	// Link, that a mirrored service was mirrored from.
+	RemoteClusterNameLabel = "mirror.linkerd.io/cluster-name"
+
+	// RemoteServiceAnnotation is the fully qualified name of the remote
+	// service that a mirrored service mirrors, e.g.
+	// "web.emojivoto.svc.cluster.local".
+	RemoteServiceAnnotation = "mirror.linkerd.io/remote-svc-fq-name"
+
+	// RemoteResourceVersionAnnotation is the resource version of the remote
+	// service when it was last mirrored.
+	RemoteResourceVersionAnnotation = "mirror.linkerd.io/remote-resource-version"
+
+	// RemoteGatewayIdentityAnnotation is the TLS identity of the gateway that
+	// the endpoints of a mirrored service point to.
+	RemoteGatewayIdentityAnnotation = "mirror.linkerd.io/remote-gateway-identity"
+
+	// DefaultExportedServiceSelector selects the remote services that are
+	// mirrored, unless a Link specifies another selector.
+	DefaultExportedServiceSelector = "mirror.linkerd.io/exported=true"
+
+	// ClusterCredentialsKey is the key of the kubeconfig of a remote cluster
+	// in the secret that a Link refers to.
+	ClusterCredentialsKey = "kubeconfig"
+
+	/*
+	 * Synthetic probes
+	 */
+
+	// ProbeNameLabel identifies the synthetic probe, as named by `linkerd
+	// probe create`, that a prober deployment and its pods belong to.
+	ProbeNameLabel = "linkerd.io/probe-name"
+
 	/*
 	 * Component Names
 	 */
@@ -88,6 +240,9 @@ const (
 	// ProxyContainerName is the name assigned to the injected proxy container.
 	ProxyContainerName = "linkerd-proxy"
 
+	// DebugContainerName is the name assigned to the injected debug container.
+	DebugContainerName = "linkerd-debug"
+
 	// ProxyInjectorTLSSecret is the name assigned to the secret containing the
 	// TLS cert and key used by the proxy-injector webhook.
 	ProxyInjectorTLSSecret = "linkerd-proxy-injector-service-tls-linkerd-io"
@@ -96,6 +251,11 @@ const (
 	// configuration resource of the proxy-injector webhook.
 	ProxyInjectorWebhookConfig = "linkerd-proxy-injector-webhook-config"
 
+	// SPValidatorWebhookConfig is the name of the validating webhook
+	// configuration resource of the ServiceProfiles, which the proxy-injector
+	// webhook also serves.
+	SPValidatorWebhookConfig = "linkerd-sp-validator-webhook-config"
+
 	// ProxySpecFileName is the name (key) within the proxy-injector ConfigMap
 	// that contains the proxy container spec.
 	ProxySpecFileName = "proxy.yaml"
@@ -104,6 +264,10 @@ const (
 	// proxy-injector ConfigMap that contains the proxy-init container spec.
 	ProxyInitSpecFileName = "proxy-init.yaml"
 
+	// DebugSpecFileName is the name (key) within the proxy-injector ConfigMap
+	// that contains the debug container spec.
+	DebugSpecFileName = "debug.yaml"
+
 	// TLSTrustAnchorVolumeSpecFileName is the name (key) within the
 	// proxy-injector ConfigMap that contains the trust anchors volume spec.
 	TLSTrustAnchorVolumeSpecFileName = "linkerd-trust-anchors.yaml"
@@ -128,6 +292,29 @@ const (
 	// that contains the TLS private key.
 	TLSPrivateKeyFileName = "private-key.p8"
 
+	// TLSCertPEMFileName is the name (key) within the TLS identity secrets
+	// that contains the TLS certificate in PEM format, for clients such as
+	// Prometheus that can't read DER.
+	TLSCertPEMFileName = "certificate.pem"
+
+	// TLSPrivateKeyPEMFileName is the name (key) within the TLS identity
+	// secrets that contains the TLS private key in PEM format.
+	TLSPrivateKeyPEMFileName = "private-key.pem"
+
+	// APIAuthTLS requires the clients of the public API to authenticate with
+	// the TLS client certificate that the CA issues to the API client
+	// identity, over the TLS port of the public API.
+	APIAuthTLS = "tls"
+
+	// PublicAPITLSPort is the port that the public API serves its clients over
+	// mutual TLS on, when the control plane is installed with APIAuthTLS.
+	PublicAPITLSPort = 8086
+
+	// TapTLSPort is the port that the tap server serves the public API of the
+	// other controller replicas over mutual TLS on, when the control plane is
+	// installed with both HA and APIAuthTLS.
+	TapTLSPort = 8089
+
 	/*
 	 * Mount paths
 	 */
@@ -138,7 +325,8 @@ const (
 
 // InjectedLabels contains the list of label keys subjected to be injected by Linkerd into resource definitions
 var InjectedLabels = []string{ControllerNSLabel, ProxyDeploymentLabel, ProxyReplicationControllerLabel,
-	ProxyReplicaSetLabel, ProxyJobLabel, ProxyDaemonSetLabel, ProxyStatefulSetLabel}
+	ProxyReplicaSetLabel, ProxyJobLabel, ProxyDaemonSetLabel, ProxyStatefulSetLabel, ProxyCronJobLabel,
+	ProxyRolloutLabel}
 
 var (
 	// MountPathTLSTrustAnchor is the path at which the trust anchor file is
@@ -161,6 +349,10 @@ var (
 	// spec is mounted to the proxy-injector
 	MountPathConfigProxyInitSpec = MountPathBase + "/config/" + ProxyInitSpecFileName
 
+	// MountPathConfigDebugSpec is the path at which the debug container spec
+	// is mounted to the proxy-injector
+	MountPathConfigDebugSpec = MountPathBase + "/config/" + DebugSpecFileName
+
 	// MountPathTLSTrustAnchorVolumeSpec is the path at which the trust anchor
 	// volume spec is mounted to the proxy-injector
 	MountPathTLSTrustAnchorVolumeSpec = MountPathBase + "/config/" + TLSTrustAnchorVolumeSpecFileName
@@ -194,6 +386,29 @@ func GetPodLabels(ownerKind, ownerName string, pod *coreV1.Pod) map[string]strin
 	return labels
 }
 
+// invalidMetricLabelChars matches the characters that aren't valid in a
+// Prometheus label name.
+var invalidMetricLabelChars = regexp.MustCompile("[^a-zA-Z0-9_]")
+
+// ToMetricLabelName converts a Kubernetes label key to a Prometheus label name,
+// the same way Prometheus does for its __meta_kubernetes_pod_label_<name> meta
+// labels: "app.kubernetes.io/version" becomes "app_kubernetes_io_version".
+func ToMetricLabelName(labelKey string) string {
+	return invalidMetricLabelChars.ReplaceAllString(labelKey, "_")
+}
+
+// GetPodMetricLabels returns the pod's labels whose keys are in the given
+// allow-list, keyed by their Prometheus label names.
+func GetPodMetricLabels(pod *coreV1.Pod, allowList []string) map[string]string {
+	labels := map[string]string{}
+	for _, key := range allowList {
+		if value, ok := pod.Labels[key]; ok {
+			labels[ToMetricLabelName(key)] = value
+		}
+	}
+	return labels
+}
+
 // IsMeshed returns whether a given Pod is in a given controller's service mesh.
 func IsMeshed(pod *coreV1.Pod, controllerNS string) bool {
 	return pod.Labels[ControllerNSLabel] == controllerNS
@@ -241,3 +456,41 @@ func (i TLSIdentity) ToControllerIdentity() TLSIdentity {
 		ControllerNamespace: i.ControllerNamespace,
 	}
 }
+
+// ToPublicAPIIdentity returns the TLSIdentity of the public API, i.e. of the
+// pods of the linkerd-controller deployment, given an arbitrary TLSIdentity.
+func (i TLSIdentity) ToPublicAPIIdentity() TLSIdentity {
+	return TLSIdentity{
+		Name:                "linkerd-controller",
+		Kind:                "deployment",
+		Namespace:           i.ControllerNamespace,
+		ControllerNamespace: i.ControllerNamespace,
+	}
+}
+
+// ToAPIClientIdentity returns the TLSIdentity that the CA issues to the
+// clients of the public API, such as the CLI, given an arbitrary TLSIdentity.
+// No pod owner has it, since "client" isn't a kind of Kubernetes resource.
+func (i TLSIdentity) ToAPIClientIdentity() TLSIdentity {
+	return TLSIdentity{
+		Name:                "linkerd-api",
+		Kind:                "client",
+		Namespace:           i.ControllerNamespace,
+		ControllerNamespace: i.ControllerNamespace,
+	}
+}
+
+// GetSLOSuccessRate returns the target success rate that the
+// SLOSuccessRateAnnotation of the annotations sets, as a fraction between 0
+// and 1, or 0 if it isn't set.
+func GetSLOSuccessRate(annotations map[string]string) (float64, error) {
+	value, ok := annotations[SLOSuccessRateAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil || percent <= 0 || percent > 100 {
+		return 0, fmt.Errorf("invalid %s annotation %q: must be a percentage greater than 0 and at most 100", SLOSuccessRateAnnotation, value)
+	}
+	return percent / 100, nil
+}
@@ -38,3 +38,56 @@ func TestGetPodLabels(t *testing.T) {
 		}
 	})
 }
+
+func TestGetPodMetricLabels(t *testing.T) {
+	pod := &coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "test-pod",
+			Labels: map[string]string{
+				"team":                      "emoji",
+				"app.kubernetes.io/version": "v2",
+				"tier":                      "backend",
+			},
+		},
+	}
+
+	expectedLabels := map[string]string{
+		"team":                      "emoji",
+		"app_kubernetes_io_version": "v2",
+	}
+
+	metricLabels := GetPodMetricLabels(pod, []string{"team", "app.kubernetes.io/version", "missing"})
+
+	if !reflect.DeepEqual(metricLabels, expectedLabels) {
+		t.Fatalf("Expected metric labels [%v] but got [%v]", expectedLabels, metricLabels)
+	}
+}
+
+func TestGetSLOSuccessRate(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected float64
+		err      bool
+	}{
+		{"99.5", 0.995, false},
+		{"100", 1, false},
+		{"0", 0, true},
+		{"101", 0, true},
+		{"high", 0, true},
+	}
+
+	for _, tc := range testCases {
+		rate, err := GetSLOSuccessRate(map[string]string{SLOSuccessRateAnnotation: tc.value})
+		if (err != nil) != tc.err {
+			t.Fatalf("Unexpected error for %q: %v", tc.value, err)
+		}
+		if rate != tc.expected {
+			t.Fatalf("Expected %f for %q, got %f", tc.expected, tc.value, rate)
+		}
+	}
+
+	rate, err := GetSLOSuccessRate(map[string]string{})
+	if err != nil || rate != 0 {
+		t.Fatalf("Expected no SLO without the annotation, got %f, %v", rate, err)
+	}
+}
@@ -2,14 +2,17 @@ package k8s
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
-	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/portforward"
@@ -19,68 +22,160 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 )
 
+const (
+	// portForwardInitialBackoff is how long a PortForward waits before it
+	// first tries to reconnect to a healthy pod, after losing its connection.
+	portForwardInitialBackoff = 500 * time.Millisecond
+
+	// portForwardMaxBackoff caps the exponential backoff between the attempts
+	// to reconnect.
+	portForwardMaxBackoff = 30 * time.Second
+)
+
+// PortForwardEventType is the type of a PortForwardEvent.
+type PortForwardEventType int
+
+// These constants enumerate the changes of a port-forward connection that
+// are reported to the handler set with OnEvent.
+const (
+	// PortForwardDisconnected is reported when the connection to the pod is
+	// lost, e.g. because the pod restarted.
+	PortForwardDisconnected PortForwardEventType = iota
+	// PortForwardRetrying is reported when no healthy pod could be reconnected
+	// to, before waiting for the next attempt.
+	PortForwardRetrying
+	// PortForwardReconnected is reported when the connection is established
+	// again, possibly to another pod.
+	PortForwardReconnected
+)
+
+// PortForwardEvent describes a change of a port-forward connection.
+type PortForwardEvent struct {
+	Type PortForwardEventType
+	// Pod is the name of the pod that the connection was lost to, or
+	// reconnected to.
+	Pod string
+	// Err is the reason for the disconnection or failed attempt, if known.
+	Err error
+	// Backoff is how long the next attempt to reconnect is delayed.
+	Backoff time.Duration
+}
+
 // PortForward provides a port-forward connection into a Kubernetes cluster.
+// When the connection to the pod is lost, e.g. because the pod restarted, it
+// reconnects to a healthy pod of the same deployment on the same local port,
+// with exponential backoff, until it's stopped.
 type PortForward struct {
-	method     string
-	url        *url.URL
+	namespace  string
+	deployName string
+	podName    string
+	// podOnly is set when deployName is the name of the only pod to connect
+	// to, rather than the prefix of the names of the pods of a deployment
+	podOnly    bool
 	localPort  int
 	remotePort int
 	emitLogs   bool
 	stopCh     chan struct{}
 	readyCh    chan struct{}
 	config     *rest.Config
+	clientset  kubernetes.Interface
+	onEvent    func(PortForwardEvent)
+
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	// forward forwards the ports to the pod until the connection is lost or
+	// stopCh is closed, and closes readyCh once the local port listens.
+	forward func(podName string, readyCh chan struct{}) error
+
+	// relay, if set, listens on the address given to NewPortForwardOnAddress,
+	// and its connections are relayed to the local port
+	relay        net.Listener
+	relayAddress string
 }
 
 // NewPortForward returns an instance of the PortForward struct that can be used
 // to establish a port-forward connection to a pod in the deployment that's
 // specified by namespace and deployName. If localPort is 0, it will use a
-// random ephemeral port.
+// random ephemeral port. The kubeconfig and impersonation arguments are the
+// same as NewAPI's.
 func NewPortForward(
-	configPath, kubeContext, namespace, deployName string,
+	configPath, kubeContext, impersonate string,
+	impersonateGroup []string,
+	namespace, deployName string,
 	localPort, remotePort int,
 	emitLogs bool,
 ) (*PortForward, error) {
-	config, err := GetConfig(configPath, kubeContext)
+	kubeAPI, err := NewAPI(configPath, kubeContext, impersonate, impersonateGroup)
 	if err != nil {
 		return nil, err
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
+	clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
 	if err != nil {
 		return nil, err
 	}
 
-	kubeAPI := &KubernetesAPI{Config: config}
-	client, err := kubeAPI.NewClient()
+	if localPort == 0 {
+		localPort, err = getLocalPort()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newPortForward(kubeAPI.Config, clientset, namespace, deployName, localPort, remotePort, emitLogs)
+}
+
+// NewPortForwardOnAddress is NewPortForward, with the port-forward listening
+// on the local address instead of the loopback interfaces, e.g. on 0.0.0.0 to
+// be reachable from other hosts. As the client-go port-forward only listens
+// on the loopback interfaces, the connections accepted on the address are
+// relayed to a random loopback port.
+func NewPortForwardOnAddress(
+	configPath, kubeContext, impersonate string,
+	impersonateGroup []string,
+	namespace, deployName, address string,
+	localPort, remotePort int,
+	emitLogs bool,
+) (*PortForward, error) {
+	if address == "" || address == "localhost" {
+		return NewPortForward(configPath, kubeContext, impersonate, impersonateGroup, namespace, deployName, localPort, remotePort, emitLogs)
+	}
+
+	relay, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(localPort)))
 	if err != nil {
 		return nil, err
 	}
-
-	pods, err := kubeAPI.GetPodsByNamespace(client, namespace)
+	pf, err := NewPortForward(configPath, kubeContext, impersonate, impersonateGroup, namespace, deployName, 0, remotePort, emitLogs)
 	if err != nil {
+		relay.Close()
 		return nil, err
 	}
+	pf.relay = relay
+	pf.relayAddress = address
+	return pf, nil
+}
 
-	podName := ""
-	for _, pod := range pods {
-		if pod.Status.Phase == v1.PodRunning {
-			if strings.HasPrefix(pod.Name, deployName) {
-				podName = pod.Name
-				break
-			}
-		}
+// NewPodPortForward is NewPortForward, with the port-forward connected to the
+// pod podName only, e.g. to reach the proxy of a given pod. It reconnects to
+// the pod when it's healthy again, but never to another pod.
+func NewPodPortForward(
+	configPath, kubeContext, impersonate string,
+	impersonateGroup []string,
+	namespace, podName string,
+	localPort, remotePort int,
+	emitLogs bool,
+) (*PortForward, error) {
+	kubeAPI, err := NewAPI(configPath, kubeContext, impersonate, impersonateGroup)
+	if err != nil {
+		return nil, err
 	}
 
-	if podName == "" {
-		return nil, fmt.Errorf("no running pods found for %s", deployName)
+	clientset, err := kubernetes.NewForConfig(kubeAPI.Config)
+	if err != nil {
+		return nil, err
 	}
 
-	req := clientset.CoreV1().RESTClient().Post().
-		Resource("pods").
-		Namespace(namespace).
-		Name(podName).
-		SubResource("portforward")
-
 	if localPort == 0 {
 		localPort, err = getLocalPort()
 		if err != nil {
@@ -88,20 +183,167 @@ func NewPortForward(
 		}
 	}
 
-	return &PortForward{
-		method:     "POST",
-		url:        req.URL(),
-		localPort:  localPort,
-		remotePort: remotePort,
-		emitLogs:   emitLogs,
-		stopCh:     make(chan struct{}, 1),
-		readyCh:    make(chan struct{}),
-		config:     config,
-	}, nil
+	return newPortForwardTo(kubeAPI.Config, clientset, namespace, podName, true, localPort, remotePort, emitLogs)
+}
+
+func newPortForward(
+	config *rest.Config,
+	clientset kubernetes.Interface,
+	namespace, deployName string,
+	localPort, remotePort int,
+	emitLogs bool,
+) (*PortForward, error) {
+	return newPortForwardTo(config, clientset, namespace, deployName, false, localPort, remotePort, emitLogs)
+}
+
+func newPortForwardTo(
+	config *rest.Config,
+	clientset kubernetes.Interface,
+	namespace, name string,
+	podOnly bool,
+	localPort, remotePort int,
+	emitLogs bool,
+) (*PortForward, error) {
+	pf := &PortForward{
+		namespace:      namespace,
+		deployName:     name,
+		podOnly:        podOnly,
+		localPort:      localPort,
+		remotePort:     remotePort,
+		emitLogs:       emitLogs,
+		stopCh:         make(chan struct{}, 1),
+		readyCh:        make(chan struct{}),
+		config:         config,
+		clientset:      clientset,
+		onEvent:        func(PortForwardEvent) {},
+		initialBackoff: portForwardInitialBackoff,
+		maxBackoff:     portForwardMaxBackoff,
+	}
+	pf.forward = pf.forwardPorts
+
+	podName, err := pf.healthyPod()
+	if err != nil {
+		return nil, err
+	}
+	pf.podName = podName
+
+	return pf, nil
 }
 
-// Run creates and runs the port-forward connection.
+// OnEvent sets the handler that is called when the connection is lost and
+// re-established. It must be called before Run.
+func (pf *PortForward) OnEvent(handler func(PortForwardEvent)) {
+	pf.onEvent = handler
+}
+
+// Run creates and runs the port-forward connection, and reconnects when the
+// connection is lost, until Stop is called. It returns an error if the first
+// connection can't be established.
 func (pf *PortForward) Run() error {
+	if pf.relay != nil {
+		go pf.relayConnections()
+	}
+
+	connected := false
+	for {
+		attemptReadyCh := make(chan struct{})
+		doneCh := make(chan struct{})
+		go func(reconnecting bool, podName string) {
+			select {
+			case <-attemptReadyCh:
+			case <-doneCh:
+				// the connection may be lost right after it's ready
+				if !isClosed(attemptReadyCh) {
+					return
+				}
+			}
+			if reconnecting {
+				pf.onEvent(PortForwardEvent{Type: PortForwardReconnected, Pod: podName})
+			} else {
+				close(pf.readyCh)
+			}
+		}(connected, pf.podName)
+
+		err := pf.forward(pf.podName, attemptReadyCh)
+		close(doneCh)
+		if pf.stopped() {
+			return nil
+		}
+		// the first connection must succeed, to report misconfigurations
+		if !connected && !isClosed(attemptReadyCh) {
+			if err == nil {
+				err = fmt.Errorf("port-forward to %s closed before it was ready", pf.podName)
+			}
+			return err
+		}
+		connected = true
+
+		pf.onEvent(PortForwardEvent{Type: PortForwardDisconnected, Pod: pf.podName, Err: err, Backoff: pf.initialBackoff})
+		if !pf.reconnect() {
+			return nil
+		}
+	}
+}
+
+// reconnect waits for a healthy pod with exponential backoff, and returns
+// false if the PortForward is stopped meanwhile.
+func (pf *PortForward) reconnect() bool {
+	backoff := pf.initialBackoff
+	for {
+		select {
+		case <-pf.stopCh:
+			return false
+		case <-time.After(backoff):
+		}
+
+		podName, err := pf.healthyPod()
+		if err == nil {
+			pf.podName = podName
+			return true
+		}
+
+		backoff *= 2
+		if backoff > pf.maxBackoff {
+			backoff = pf.maxBackoff
+		}
+		pf.onEvent(PortForwardEvent{Type: PortForwardRetrying, Err: err, Backoff: backoff})
+	}
+}
+
+// healthyPod returns the name of a running and ready pod of the deployment,
+// that isn't being deleted.
+func (pf *PortForward) healthyPod() (string, error) {
+	pods, err := pf.clientset.CoreV1().Pods(pf.namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods.Items {
+		matches := strings.HasPrefix(pod.Name, pf.deployName)
+		if pf.podOnly {
+			matches = pod.Name == pf.deployName
+		}
+		if matches && isHealthy(pod) {
+			return pod.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("no running pods found for %s", pf.deployName)
+}
+
+func isHealthy(pod v1.Pod) bool {
+	if pod.Status.Phase != v1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return true
+}
+
+func (pf *PortForward) forwardPorts(podName string, readyCh chan struct{}) error {
 	transport, upgrader, err := spdy.RoundTripperFor(pf.config)
 	if err != nil {
 		return err
@@ -114,10 +356,17 @@ func (pf *PortForward) Run() error {
 		errOut = os.Stderr
 	}
 
+	url := pf.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pf.namespace).
+		Name(podName).
+		SubResource("portforward").
+		URL()
+
 	ports := []string{fmt.Sprintf("%d:%d", pf.localPort, pf.remotePort)}
-	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, pf.method, pf.url)
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", url)
 
-	fw, err := portforward.New(dialer, ports, pf.stopCh, pf.readyCh, out, errOut)
+	fw, err := portforward.New(dialer, ports, pf.stopCh, readyCh, out, errOut)
 	if err != nil {
 		return err
 	}
@@ -137,9 +386,66 @@ func (pf *PortForward) Stop() {
 	close(pf.stopCh)
 }
 
+func (pf *PortForward) stopped() bool {
+	return isClosed(pf.stopCh)
+}
+
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// relayConnections relays the connections accepted by the relay listener to
+// the local port, until the PortForward is stopped.
+func (pf *PortForward) relayConnections() {
+	go func() {
+		<-pf.stopCh
+		pf.relay.Close()
+	}()
+
+	for {
+		conn, err := pf.relay.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			upstream, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", pf.localPort))
+			if err != nil {
+				return
+			}
+			defer upstream.Close()
+
+			done := make(chan struct{}, 2)
+			go func() {
+				io.Copy(upstream, conn)
+				done <- struct{}{}
+			}()
+			go func() {
+				io.Copy(conn, upstream)
+				done <- struct{}{}
+			}()
+			<-done
+		}()
+	}
+}
+
 // URLFor returns the URL for the port-forward connection.
 func (pf *PortForward) URLFor(path string) string {
-	return fmt.Sprintf("http://127.0.0.1:%d%s", pf.localPort, path)
+	if pf.relay == nil {
+		return fmt.Sprintf("http://127.0.0.1:%d%s", pf.localPort, path)
+	}
+
+	host := pf.relayAddress
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	port := pf.relay.Addr().(*net.TCPAddr).Port
+	return fmt.Sprintf("http://%s%s", net.JoinHostPort(host, strconv.Itoa(port)), path)
 }
 
 // getLocalPort binds to a free ephemeral port and returns the port number.
@@ -0,0 +1,229 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func webPod(name string, phase v1.PodPhase, ready bool) *v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "linkerd"},
+		Status: v1.PodStatus{
+			Phase:      phase,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestNewPortForward(t *testing.T) {
+	terminating := webPod("linkerd-web-1", v1.PodRunning, true)
+	terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	testCases := []struct {
+		pods     []runtime.Object
+		expected string
+	}{
+		{
+			pods: []runtime.Object{
+				webPod("linkerd-controller-1", v1.PodRunning, true),
+				webPod("linkerd-web-1", v1.PodPending, false),
+				webPod("linkerd-web-2", v1.PodRunning, true),
+			},
+			expected: "linkerd-web-2",
+		},
+		{
+			pods: []runtime.Object{
+				webPod("linkerd-web-1", v1.PodRunning, false),
+				webPod("linkerd-web-2", v1.PodRunning, true),
+			},
+			expected: "linkerd-web-2",
+		},
+		{
+			pods:     []runtime.Object{terminating, webPod("linkerd-web-2", v1.PodRunning, true)},
+			expected: "linkerd-web-2",
+		},
+		{
+			pods: []runtime.Object{webPod("linkerd-web-1", v1.PodFailed, false)},
+		},
+	}
+
+	for i, tc := range testCases {
+		pf, err := newPortForward(nil, fake.NewSimpleClientset(tc.pods...), "linkerd", "linkerd-web", 8080, 8084, false)
+		if tc.expected == "" {
+			if err == nil {
+				t.Fatalf("test case %d: expected an error, got pod %s", i, pf.podName)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test case %d: unexpected error: %s", i, err)
+		}
+		if pf.podName != tc.expected {
+			t.Fatalf("test case %d: expected pod %s, got %s", i, tc.expected, pf.podName)
+		}
+	}
+}
+
+func TestNewPodPortForward(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		webPod("linkerd-web-10", v1.PodRunning, true),
+		webPod("linkerd-web-1", v1.PodRunning, true),
+		webPod("linkerd-web-2", v1.PodPending, false),
+	)
+
+	pf, err := newPortForwardTo(nil, clientset, "linkerd", "linkerd-web-1", true, 8080, 4191, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if pf.podName != "linkerd-web-1" {
+		t.Fatalf("Expected pod linkerd-web-1, got %s", pf.podName)
+	}
+
+	if pf, err := newPortForwardTo(nil, clientset, "linkerd", "linkerd-web-2", true, 8080, 4191, false); err == nil {
+		t.Fatalf("Expected an error for a pending pod, got pod %s", pf.podName)
+	}
+}
+
+func TestPortForwardRun(t *testing.T) {
+	t.Run("Reconnects to a healthy pod when the connection is lost", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(webPod("linkerd-web-1", v1.PodRunning, true))
+		pf, err := newPortForward(nil, clientset, "linkerd", "linkerd-web", 8080, 8084, false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		pf.initialBackoff = time.Millisecond
+
+		events := make(chan PortForwardEvent, 10)
+		pf.OnEvent(func(event PortForwardEvent) {
+			// the new pod starts after the first attempt to reconnect
+			if event.Type == PortForwardRetrying && event.Backoff == 2*time.Millisecond {
+				clientset.CoreV1().Pods("linkerd").Create(webPod("linkerd-web-2", v1.PodRunning, true))
+			}
+			events <- event
+		})
+
+		forwarded := []string{}
+		pf.forward = func(podName string, readyCh chan struct{}) error {
+			forwarded = append(forwarded, podName)
+			close(readyCh)
+			if podName == "linkerd-web-1" {
+				// the pod restarts
+				clientset.CoreV1().Pods("linkerd").Delete(podName, &metav1.DeleteOptions{})
+				return nil
+			}
+			<-pf.stopCh
+			return nil
+		}
+
+		errCh := make(chan error)
+		go func() { errCh <- pf.Run() }()
+		<-pf.Ready()
+
+		expected := []PortForwardEventType{PortForwardDisconnected, PortForwardRetrying, PortForwardReconnected}
+		for _, eventType := range expected {
+			select {
+			case event := <-events:
+				if event.Type != eventType {
+					t.Fatalf("Expected event %d, got %+v", eventType, event)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Timed out waiting for event %d", eventType)
+			}
+		}
+
+		pf.Stop()
+		if err := <-errCh; err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(forwarded) != 2 || forwarded[0] != "linkerd-web-1" || forwarded[1] != "linkerd-web-2" {
+			t.Fatalf("Expected the ports to be forwarded to linkerd-web-1 then linkerd-web-2, got %v", forwarded)
+		}
+	})
+
+	t.Run("Returns an error if the first connection fails", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(webPod("linkerd-web-1", v1.PodRunning, true))
+		pf, err := newPortForward(nil, clientset, "linkerd", "linkerd-web", 8080, 8084, false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		pf.forward = func(podName string, readyCh chan struct{}) error {
+			return errors.New("unable to listen on any of the requested ports")
+		}
+
+		if err := pf.Run(); err == nil || err.Error() != "unable to listen on any of the requested ports" {
+			t.Fatalf("Expected the forwarding error, got: %v", err)
+		}
+	})
+	t.Run("Relays the connections on the address to the local port", func(t *testing.T) {
+		upstream, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		defer upstream.Close()
+		go func() {
+			conn, err := upstream.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			io.Copy(conn, conn)
+		}()
+
+		relay, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		clientset := fake.NewSimpleClientset(webPod("linkerd-web-1", v1.PodRunning, true))
+		pf, err := newPortForward(nil, clientset, "linkerd", "linkerd-web", upstream.Addr().(*net.TCPAddr).Port, 8084, false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		pf.relay = relay
+		pf.relayAddress = "0.0.0.0"
+		pf.forward = func(podName string, readyCh chan struct{}) error {
+			close(readyCh)
+			<-pf.stopCh
+			return nil
+		}
+
+		relayPort := relay.Addr().(*net.TCPAddr).Port
+		if url := pf.URLFor("/grafana"); url != fmt.Sprintf("http://127.0.0.1:%d/grafana", relayPort) {
+			t.Fatalf("Unexpected URL: %s", url)
+		}
+
+		errCh := make(chan error)
+		go func() { errCh <- pf.Run() }()
+		<-pf.Ready()
+
+		conn, err := net.Dial("tcp", relay.Addr().String())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		defer conn.Close()
+		if _, err := conn.Write([]byte("ping")); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+			t.Fatalf("Expected the connection to be relayed, got %q: %v", buf, err)
+		}
+
+		pf.Stop()
+		if err := <-errCh; err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+}
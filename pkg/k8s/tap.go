@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// TapAPIGroup is the group of the tap API, which the tap server serves
+	// through the Kubernetes API as an aggregated APIService.
+	TapAPIGroup = "tap.linkerd.io"
+
+	// TapAPIVersion is the version of the tap API.
+	TapAPIVersion = "v1alpha1"
+
+	// TapAPIPort is the port of the tap server that the Kubernetes API
+	// aggregator proxies the tap API to.
+	TapAPIPort = 8089
+
+	// TapAPIServiceName is the name of the APIService of the tap API.
+	TapAPIServiceName = TapAPIVersion + "." + TapAPIGroup
+
+	// TapSubresource is the subresource of the tappable resources, which RBAC
+	// rules grant the "watch" verb on to allow tapping them.
+	TapSubresource = "tap"
+
+	tapAPIPrefix = "/apis/" + TapAPIGroup + "/" + TapAPIVersion
+)
+
+// TapAPIRoot returns the path of the discovery document of the tap API.
+func TapAPIRoot() string {
+	return tapAPIPrefix
+}
+
+// TapAPIPath returns the path of the tap API that taps the resource, such as
+// /apis/tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deployments/web/tap.
+// The resources of a namespace are tapped through the namespace's own path.
+func TapAPIPath(namespace, resourceType, name string) string {
+	if resourceType == Namespace {
+		return fmt.Sprintf("%s/watch/namespaces/%s/%s", tapAPIPrefix, name, TapSubresource)
+	}
+	if name == "" {
+		return fmt.Sprintf("%s/watch/namespaces/%s/%s/%s", tapAPIPrefix, namespace, PluralResourceName(resourceType), TapSubresource)
+	}
+	return fmt.Sprintf("%s/watch/namespaces/%s/%s/%s/%s", tapAPIPrefix, namespace, PluralResourceName(resourceType), name, TapSubresource)
+}
+
+// ParseTapAPIPath returns the namespace, the canonical type and the name of
+// the resource of a path of the tap API, the reverse of TapAPIPath.
+func ParseTapAPIPath(path string) (string, string, string, error) {
+	invalid := fmt.Errorf("invalid tap API path [%s]", path)
+	if !strings.HasPrefix(path, tapAPIPrefix+"/watch/namespaces/") {
+		return "", "", "", invalid
+	}
+	segments := strings.Split(strings.TrimPrefix(path, tapAPIPrefix+"/watch/"), "/")
+	if segments[len(segments)-1] != TapSubresource {
+		return "", "", "", invalid
+	}
+	for _, segment := range segments {
+		if segment == "" {
+			return "", "", "", invalid
+		}
+	}
+
+	switch len(segments) {
+	case 3:
+		return segments[1], Namespace, segments[1], nil
+	case 4, 5:
+		resourceType, err := CanonicalResourceNameFromFriendlyName(segments[2])
+		if err != nil || PluralResourceName(resourceType) != segments[2] {
+			return "", "", "", invalid
+		}
+		name := ""
+		if len(segments) == 5 {
+			name = segments[3]
+		}
+		return segments[1], resourceType, name, nil
+	default:
+		return "", "", "", invalid
+	}
+}
+
+// PluralResourceName returns the plural of a canonical resource name, which
+// the Kubernetes API and RBAC rules name the resources with.
+func PluralResourceName(canonicalName string) string {
+	if canonicalName == Authority {
+		return "authorities"
+	}
+	return canonicalName + "s"
+}
@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"testing"
+)
+
+func TestTapAPIPath(t *testing.T) {
+	testCases := []struct {
+		namespace    string
+		resourceType string
+		name         string
+		path         string
+	}{
+		{"emojivoto", Deployment, "web", "/apis/tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deployments/web/tap"},
+		{"emojivoto", Pod, "", "/apis/tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/pods/tap"},
+		{"", Namespace, "emojivoto", "/apis/tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/tap"},
+		{"emojivoto", Authority, "web.emojivoto.svc.cluster.local", "/apis/tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/authorities/web.emojivoto.svc.cluster.local/tap"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.path, func(t *testing.T) {
+			path := TapAPIPath(tc.namespace, tc.resourceType, tc.name)
+			if path != tc.path {
+				t.Fatalf("Expected path [%s], got [%s]", tc.path, path)
+			}
+
+			namespace, resourceType, name, err := ParseTapAPIPath(path)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			expectedNamespace := tc.namespace
+			if tc.resourceType == Namespace {
+				expectedNamespace = tc.name
+			}
+			if namespace != expectedNamespace || resourceType != tc.resourceType || name != tc.name {
+				t.Fatalf("Expected %s/%s/%s, got %s/%s/%s", expectedNamespace, tc.resourceType, tc.name, namespace, resourceType, name)
+			}
+		})
+	}
+
+	t.Run("Rejects invalid paths", func(t *testing.T) {
+		for _, path := range []string{
+			"/apis/tap.linkerd.io/v1alpha1",
+			"/apis/tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto",
+			"/apis/tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deploy/web/tap",
+			"/apis/tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deployments/web/logs",
+			"/apis/tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deployments//tap",
+			"/apis/tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deployments/web/extra/tap",
+		} {
+			if _, _, _, err := ParseTapAPIPath(path); err == nil {
+				t.Fatalf("Expected an error for [%s]", path)
+			}
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package k8s
+
+import (
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+)
+
+const (
+	// NodeOSLabel is the label with the operating system of a node, which pods
+	// select to run on Windows or Linux nodes.
+	NodeOSLabel = "beta.kubernetes.io/os"
+
+	// NodeOSLabelGA replaces NodeOSLabel as of Kubernetes 1.14; nodes have both
+	// labels until the beta one is removed.
+	NodeOSLabelGA = "kubernetes.io/os"
+
+	// WindowsOS is the value of the node OS labels on Windows nodes.
+	WindowsOS = "windows"
+)
+
+var nodeOSLabels = []string{NodeOSLabel, NodeOSLabelGA}
+
+// IsWindowsNode returns true if the node runs Windows.
+func IsWindowsNode(node *coreV1.Node) bool {
+	for _, label := range nodeOSLabels {
+		if strings.EqualFold(node.Labels[label], WindowsOS) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresWindowsNodes returns true if the pods of the pod spec can only be
+// scheduled on Windows nodes, through either their node selector or their
+// required node affinity. The proxy and proxy-init only run on Linux, so these
+// pods can't be meshed.
+func RequiresWindowsNodes(podSpec *coreV1.PodSpec) bool {
+	for _, label := range nodeOSLabels {
+		if strings.EqualFold(podSpec.NodeSelector[label], WindowsOS) {
+			return true
+		}
+	}
+
+	if podSpec.Affinity == nil || podSpec.Affinity.NodeAffinity == nil {
+		return false
+	}
+	required := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		return false
+	}
+
+	// the terms are ORed, so each of them must only select Windows nodes
+	for _, term := range required.NodeSelectorTerms {
+		if !selectsOnlyWindows(term) {
+			return false
+		}
+	}
+	return true
+}
+
+// selectsOnlyWindows returns true if the node selector term has a requirement
+// that only Windows nodes meet.
+func selectsOnlyWindows(term coreV1.NodeSelectorTerm) bool {
+	for _, req := range term.MatchExpressions {
+		if req.Key != NodeOSLabel && req.Key != NodeOSLabelGA {
+			continue
+		}
+		if req.Operator != coreV1.NodeSelectorOpIn || len(req.Values) == 0 {
+			continue
+		}
+
+		windows := true
+		for _, value := range req.Values {
+			if !strings.EqualFold(value, WindowsOS) {
+				windows = false
+			}
+		}
+		if windows {
+			return true
+		}
+	}
+	return false
+}
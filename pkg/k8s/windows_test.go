@@ -0,0 +1,95 @@
+package k8s
+
+import (
+	"testing"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsWindowsNode(t *testing.T) {
+	testCases := []struct {
+		labels   map[string]string
+		expected bool
+	}{
+		{map[string]string{NodeOSLabel: "windows"}, true},
+		{map[string]string{NodeOSLabelGA: "windows", NodeOSLabel: "windows"}, true},
+		{map[string]string{NodeOSLabel: "linux"}, false},
+		{nil, false},
+	}
+
+	for _, tc := range testCases {
+		node := &coreV1.Node{ObjectMeta: metaV1.ObjectMeta{Labels: tc.labels}}
+		if IsWindowsNode(node) != tc.expected {
+			t.Errorf("Expected IsWindowsNode to be %t for labels %v", tc.expected, tc.labels)
+		}
+	}
+}
+
+func TestRequiresWindowsNodes(t *testing.T) {
+	affinity := func(terms ...coreV1.NodeSelectorTerm) *coreV1.Affinity {
+		return &coreV1.Affinity{
+			NodeAffinity: &coreV1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &coreV1.NodeSelector{
+					NodeSelectorTerms: terms,
+				},
+			},
+		}
+	}
+	osTerm := func(key string, operator coreV1.NodeSelectorOperator, values ...string) coreV1.NodeSelectorTerm {
+		return coreV1.NodeSelectorTerm{
+			MatchExpressions: []coreV1.NodeSelectorRequirement{
+				{Key: key, Operator: operator, Values: values},
+			},
+		}
+	}
+
+	testCases := []struct {
+		title    string
+		podSpec  coreV1.PodSpec
+		expected bool
+	}{
+		{
+			title:    "no node selector or affinity",
+			podSpec:  coreV1.PodSpec{},
+			expected: false,
+		},
+		{
+			title:    "node selector on Windows",
+			podSpec:  coreV1.PodSpec{NodeSelector: map[string]string{NodeOSLabel: "windows"}},
+			expected: true,
+		},
+		{
+			title:    "node selector on Linux",
+			podSpec:  coreV1.PodSpec{NodeSelector: map[string]string{NodeOSLabelGA: "linux"}},
+			expected: false,
+		},
+		{
+			title:    "required affinity to Windows",
+			podSpec:  coreV1.PodSpec{Affinity: affinity(osTerm(NodeOSLabelGA, coreV1.NodeSelectorOpIn, "windows"))},
+			expected: true,
+		},
+		{
+			title: "required affinity to Windows or Linux",
+			podSpec: coreV1.PodSpec{Affinity: affinity(
+				osTerm(NodeOSLabel, coreV1.NodeSelectorOpIn, "windows"),
+				osTerm(NodeOSLabel, coreV1.NodeSelectorOpIn, "linux"),
+			)},
+			expected: false,
+		},
+		{
+			title:    "required affinity away from Windows",
+			podSpec:  coreV1.PodSpec{Affinity: affinity(osTerm(NodeOSLabel, coreV1.NodeSelectorOpNotIn, "windows"))},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			if RequiresWindowsNodes(&tc.podSpec) != tc.expected {
+				t.Fatalf("Expected RequiresWindowsNodes to be %t", tc.expected)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package profiles
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"text/template"
 	"time"
@@ -29,8 +30,23 @@ var DefaultRetryBudget = pb.RetryBudget{
 	},
 }
 
+const (
+	// maxMinRetriesPerSecond caps the retries that a retry budget allows
+	// regardless of the request volume, as they would otherwise multiply the
+	// load of a service that is already failing.
+	maxMinRetriesPerSecond = 100
+
+	minRetryBudgetTTL = time.Second
+	maxRetryBudgetTTL = time.Minute
+)
+
 // ToServiceProfile returns a Proxy API DestinationProfile, given a
-// ServiceProfile.
+// ServiceProfile. A retry budget without a ttl gets the ttl of the
+// DefaultRetryBudget.
+//
+// The ServiceProfile isn't validated with Validate, since the profiles that
+// were created before their validation still need their routes; the new ones
+// are validated by the admission webhook.
 func ToServiceProfile(profile *sp.ServiceProfileSpec) (*pb.DestinationProfile, error) {
 	routes := make([]*pb.Route, 0)
 	for _, route := range profile.Routes {
@@ -44,13 +60,15 @@ func ToServiceProfile(profile *sp.ServiceProfileSpec) (*pb.DestinationProfile, e
 	if profile.RetryBudget != nil {
 		budget.MinRetriesPerSecond = profile.RetryBudget.MinRetriesPerSecond
 		budget.RetryRatio = profile.RetryBudget.RetryRatio
-		ttl, err := time.ParseDuration(profile.RetryBudget.TTL)
-		if err != nil {
-			return nil, err
-		}
-		budget.Ttl = &duration.Duration{
-			Seconds: int64(ttl / time.Second),
-			Nanos:   int32(ttl % time.Second),
+		if profile.RetryBudget.TTL != "" {
+			ttl, err := time.ParseDuration(profile.RetryBudget.TTL)
+			if err != nil {
+				return nil, err
+			}
+			budget.Ttl = &duration.Duration{
+				Seconds: int64(ttl / time.Second),
+				Nanos:   int32(ttl % time.Second),
+			}
 		}
 	}
 	return &pb.DestinationProfile{
@@ -363,6 +381,73 @@ func ValidateResponseMatch(rspMatch *sp.ResponseMatch) error {
 	return nil
 }
 
+// Validate validates the routes and the retry budget of a ServiceProfile, as
+// the admission webhook and `linkerd check` do.
+func Validate(profile *sp.ServiceProfileSpec) error {
+	if profile.RetryBudget != nil {
+		if err := ValidateRetryBudget(profile.RetryBudget); err != nil {
+			return fmt.Errorf("Invalid retry budget: %s", err)
+		}
+	}
+	for _, route := range profile.Routes {
+		if route.Name == "" {
+			return errors.New("A route has no name")
+		}
+		if route.Condition == nil {
+			return fmt.Errorf("The route %s has no condition", route.Name)
+		}
+		if err := ValidateRequestMatch(route.Condition); err != nil {
+			return fmt.Errorf("The route %s has an invalid condition: %s", route.Name, err)
+		}
+		if err := ValidateRouteRetries(route, profile.RetryBudget); err != nil {
+			return fmt.Errorf("The route %s has invalid retries: %s", route.Name, err)
+		}
+		for _, rc := range route.ResponseClasses {
+			if rc.Condition == nil {
+				return fmt.Errorf("The route %s has a response class with no condition", route.Name)
+			}
+			if err := ValidateResponseMatch(rc.Condition); err != nil {
+				return fmt.Errorf("The route %s has a response class with an invalid condition: %s", route.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateRetryBudget validates the retry budget of a ServiceProfile: the
+// retry ratio must be between 0 and 1, so that retries can't outnumber the
+// original requests, the minimum retries per second must be at most 100, and
+// the ttl, if any, must be between 1s and 60s.
+func ValidateRetryBudget(budget *sp.RetryBudget) error {
+	if budget.RetryRatio < 0 || budget.RetryRatio > 1 {
+		return fmt.Errorf("The retry ratio must be between 0 and 1, got %v", budget.RetryRatio)
+	}
+	if budget.MinRetriesPerSecond > maxMinRetriesPerSecond {
+		return fmt.Errorf("The minimum retries per second must be at most %d, got %d", maxMinRetriesPerSecond, budget.MinRetriesPerSecond)
+	}
+	if budget.TTL == "" {
+		return nil
+	}
+	ttl, err := time.ParseDuration(budget.TTL)
+	if err != nil {
+		return fmt.Errorf("Invalid ttl \"%s\" (must be a duration such as \"10s\")", budget.TTL)
+	}
+	if ttl < minRetryBudgetTTL || ttl > maxRetryBudgetTTL {
+		return fmt.Errorf("The ttl must be between %s and %s, got %s", minRetryBudgetTTL, maxRetryBudgetTTL, budget.TTL)
+	}
+	return nil
+}
+
+// ValidateRouteRetries validates the retries of a route given the retry
+// budget of its ServiceProfile, if any: it can only be retryable if the budget
+// allows retries.
+func ValidateRouteRetries(route *sp.RouteSpec, budget *sp.RetryBudget) error {
+	if route.IsRetryable && budget != nil && budget.RetryRatio == 0 && budget.MinRetriesPerSecond == 0 {
+		return errors.New("The route is retryable, but the retry budget doesn't allow any retries")
+	}
+	return nil
+}
+
 func buildConfig(namespace, service, controlPlaneNamespace string) *profileTemplateConfig {
 	return &profileTemplateConfig{
 		ControlPlaneNamespace: controlPlaneNamespace,
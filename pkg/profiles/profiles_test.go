@@ -0,0 +1,207 @@
+package profiles
+
+import (
+	"testing"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+)
+
+func TestValidateRetryBudget(t *testing.T) {
+	testCases := []struct {
+		title  string
+		budget *sp.RetryBudget
+		err    string
+	}{
+		{
+			title:  "accepts a budget",
+			budget: &sp.RetryBudget{RetryRatio: 0.2, MinRetriesPerSecond: 10, TTL: "10s"},
+		},
+		{
+			title:  "accepts a budget without a ttl",
+			budget: &sp.RetryBudget{RetryRatio: 0.2, MinRetriesPerSecond: 10},
+		},
+		{
+			title:  "rejects retry ratios above 1",
+			budget: &sp.RetryBudget{RetryRatio: 1.5, TTL: "10s"},
+			err:    "The retry ratio must be between 0 and 1, got 1.5",
+		},
+		{
+			title:  "rejects negative retry ratios",
+			budget: &sp.RetryBudget{RetryRatio: -0.1, TTL: "10s"},
+			err:    "The retry ratio must be between 0 and 1, got -0.1",
+		},
+		{
+			title:  "rejects too many minimum retries per second",
+			budget: &sp.RetryBudget{RetryRatio: 0.2, MinRetriesPerSecond: 1000, TTL: "10s"},
+			err:    "The minimum retries per second must be at most 100, got 1000",
+		},
+		{
+			title:  "rejects invalid ttls",
+			budget: &sp.RetryBudget{RetryRatio: 0.2, TTL: "10"},
+			err:    "Invalid ttl \"10\" (must be a duration such as \"10s\")",
+		},
+		{
+			title:  "rejects ttls out of range",
+			budget: &sp.RetryBudget{RetryRatio: 0.2, TTL: "10m"},
+			err:    "The ttl must be between 1s and 1m0s, got 10m",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			err := ValidateRetryBudget(tc.budget)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error %q, got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateRouteRetries(t *testing.T) {
+	testCases := []struct {
+		title  string
+		route  *sp.RouteSpec
+		budget *sp.RetryBudget
+		err    string
+	}{
+		{
+			title: "accepts a retryable route without a budget",
+			route: &sp.RouteSpec{IsRetryable: true},
+		},
+		{
+			title:  "accepts a retryable route with a budget",
+			route:  &sp.RouteSpec{IsRetryable: true},
+			budget: &sp.RetryBudget{MinRetriesPerSecond: 1},
+		},
+		{
+			title:  "accepts routes that aren't retryable with a budget that allows no retries",
+			route:  &sp.RouteSpec{},
+			budget: &sp.RetryBudget{},
+		},
+		{
+			title:  "rejects retryable routes with a budget that allows no retries",
+			route:  &sp.RouteSpec{IsRetryable: true},
+			budget: &sp.RetryBudget{TTL: "10s"},
+			err:    "The route is retryable, but the retry budget doesn't allow any retries",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			err := ValidateRouteRetries(tc.route, tc.budget)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error %q, got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	condition := &sp.RequestMatch{PathRegex: "/books"}
+	testCases := []struct {
+		title   string
+		profile *sp.ServiceProfileSpec
+		err     string
+	}{
+		{
+			title: "accepts a profile",
+			profile: &sp.ServiceProfileSpec{
+				Routes: []*sp.RouteSpec{{
+					Name:            "books",
+					Condition:       condition,
+					ResponseClasses: []*sp.ResponseClass{{Condition: &sp.ResponseMatch{Status: &sp.Range{Min: 500}}, IsFailure: true}},
+					IsRetryable:     true,
+				}},
+				RetryBudget: &sp.RetryBudget{RetryRatio: 0.2, MinRetriesPerSecond: 10, TTL: "10s"},
+			},
+		},
+		{
+			title:   "rejects invalid retry budgets",
+			profile: &sp.ServiceProfileSpec{RetryBudget: &sp.RetryBudget{RetryRatio: 2}},
+			err:     "Invalid retry budget: The retry ratio must be between 0 and 1, got 2",
+		},
+		{
+			title:   "rejects routes without a name",
+			profile: &sp.ServiceProfileSpec{Routes: []*sp.RouteSpec{{Condition: condition}}},
+			err:     "A route has no name",
+		},
+		{
+			title:   "rejects routes without a condition",
+			profile: &sp.ServiceProfileSpec{Routes: []*sp.RouteSpec{{Name: "books"}}},
+			err:     "The route books has no condition",
+		},
+		{
+			title: "rejects retryable routes that the budget doesn't allow to retry",
+			profile: &sp.ServiceProfileSpec{
+				Routes:      []*sp.RouteSpec{{Name: "books", Condition: condition, IsRetryable: true}},
+				RetryBudget: &sp.RetryBudget{},
+			},
+			err: "The route books has invalid retries: The route is retryable, but the retry budget doesn't allow any retries",
+		},
+		{
+			title: "rejects response classes without a condition",
+			profile: &sp.ServiceProfileSpec{
+				Routes: []*sp.RouteSpec{{Name: "books", Condition: condition, ResponseClasses: []*sp.ResponseClass{{IsFailure: true}}}},
+			},
+			err: "The route books has a response class with no condition",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			err := Validate(tc.profile)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error %q, got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestToServiceProfileRetryBudget(t *testing.T) {
+	t.Run("Defaults the ttl", func(t *testing.T) {
+		profile, err := ToServiceProfile(&sp.ServiceProfileSpec{
+			RetryBudget: &sp.RetryBudget{RetryRatio: 0.1, MinRetriesPerSecond: 5},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		budget := profile.RetryBudget
+		if budget.RetryRatio != 0.1 || budget.MinRetriesPerSecond != 5 || budget.Ttl.Seconds != DefaultRetryBudget.Ttl.Seconds {
+			t.Fatalf("Unexpected retry budget: %v", budget)
+		}
+	})
+
+	t.Run("Keeps the profiles created before their validation", func(t *testing.T) {
+		profile, err := ToServiceProfile(&sp.ServiceProfileSpec{
+			Routes:      []*sp.RouteSpec{{Name: "books", Condition: &sp.RequestMatch{PathRegex: "/books"}, IsRetryable: true}},
+			RetryBudget: &sp.RetryBudget{RetryRatio: 0, MinRetriesPerSecond: 1000, TTL: "10s"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(profile.Routes) != 1 || profile.RetryBudget.MinRetriesPerSecond != 1000 {
+			t.Fatalf("Unexpected profile: %v", profile)
+		}
+	})
+}
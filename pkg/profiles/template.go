@@ -79,16 +79,17 @@ spec:
   # retryBudget:
   #   The retryRatio is the maximum ratio of retries requests to original
   #   requests.  A retryRatio of 0.2 means that retries may add at most an
-  #   additional 20% to the request load.
+  #   additional 20% to the request load, and it can be at most 1.
   #   retryRatio: 0.2
 
   #   This is an allowance of retries per second in addition to those allowed
   #   by the retryRatio.  This allows retries to be performed, when the request
-  #   rate is very low.
+  #   rate is very low.  It can be at most 100.
   #   minRetriesPerSecond: 10
 
   #   This duration indicates for how long requests should be considered for the
   #   purposes of calculating the retryRatio.  A higher value considers a larger
-  #   window and therefore allows burstier retries.
+  #   window and therefore allows burstier retries.  It must be between 1s
+  #   and 60s, and defaults to 10s.
   #   ttl: 10s
 `
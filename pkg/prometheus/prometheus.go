@@ -9,12 +9,13 @@ import (
 	"google.golang.org/grpc"
 )
 
-// NewGrpcServer returns a grpc server pre-configured with prometheus interceptors
-func NewGrpcServer() *grpc.Server {
-	server := grpc.NewServer(
+// NewGrpcServer returns a grpc server pre-configured with prometheus
+// interceptors, and with the additional opts
+func NewGrpcServer(opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(append([]grpc.ServerOption{
 		grpc.UnaryInterceptor(grpc_prometheus.UnaryServerInterceptor),
 		grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
-	)
+	}, opts...)...)
 
 	grpc_prometheus.EnableHandlingTimeHistogram()
 	grpc_prometheus.Register(server)
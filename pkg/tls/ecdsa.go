@@ -0,0 +1,118 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GenerateECDSAKey generates an ECDSA P-256 private key, which is the key type
+// of the issuer and of the certificates it issues.
+func GenerateECDSAKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+// EncodeECDSAKey returns the PEM encoding of key, in the SEC 1 format.
+func EncodeECDSAKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return PEMEncodeKey(der, KeyTypeECDSA)
+}
+
+// DecodePEMCert decodes the first certificate of certPEM.
+func DecodePEMCert(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// DecodePEMECDSAKey decodes a PEM-encoded ECDSA P-256 private key, in either
+// the SEC 1 or the PKCS#8 format.
+func DecodePEMECDSAKey(keyPEM []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM private key found")
+	}
+
+	var key *ecdsa.PrivateKey
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		ecKey, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key = ecKey
+	case "PRIVATE KEY":
+		p8Key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		ecKey, ok := p8Key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("the private key must be an ECDSA key")
+		}
+		key = ecKey
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type \"%s\" for the private key (must be an ECDSA key)", block.Type)
+	}
+
+	if key.Curve != elliptic.P256() {
+		return nil, errors.New("the private key must use the P-256 curve")
+	}
+	return key, nil
+}
+
+// ValidateECDSAIssuer checks that cert and key can issue certificates: cert
+// must be a currently valid CA certificate, and key its ECDSA P-256 private
+// key.
+func ValidateECDSAIssuer(cert *x509.Certificate, key *ecdsa.PrivateKey) error {
+	if !cert.IsCA {
+		return fmt.Errorf("certificate \"%s\" is not a CA certificate", cert.Subject.CommonName)
+	}
+	if now := time.Now(); now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return fmt.Errorf("certificate \"%s\" is only valid from %s to %s", cert.Subject.CommonName,
+			cert.NotBefore.Format(time.RFC3339), cert.NotAfter.Format(time.RFC3339))
+	}
+	if key.Curve != elliptic.P256() {
+		return errors.New("the private key must use the P-256 curve")
+	}
+	publicKey, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok || publicKey.X.Cmp(key.X) != 0 || publicKey.Y.Cmp(key.Y) != 0 {
+		return errors.New("the private key doesn't match the certificate")
+	}
+	return nil
+}
+
+// SignCSR issues a certificate for the ECDSA P-256 public key of the DER
+// certificate signing request csrDER, with the issuer's cert and key. The
+// certificate is built from template, and gets the DNS names of the request if
+// the template has none. It returns the DER certificate.
+func SignCSR(csrDER []byte, template, cert *x509.Certificate, key *ecdsa.PrivateKey) ([]byte, error) {
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return nil, err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid certificate signing request signature: %s", err)
+	}
+	publicKey, ok := csr.PublicKey.(*ecdsa.PublicKey)
+	if !ok || publicKey.Curve != elliptic.P256() {
+		return nil, errors.New("the certificate signing request must be for an ECDSA P-256 key")
+	}
+
+	leaf := *template
+	leaf.PublicKey = publicKey
+	if len(leaf.DNSNames) == 0 {
+		leaf.DNSNames = csr.DNSNames
+	}
+	return x509.CreateCertificate(rand.Reader, &leaf, cert, publicKey, key)
+}
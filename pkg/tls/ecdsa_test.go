@@ -0,0 +1,185 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func genIssuer(t *testing.T, isCA bool, notAfter time.Time) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := GenerateECDSAKey()
+	if err != nil {
+		t.Fatalf("GenerateECDSAKey returned an error: %s", err)
+	}
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "issuer"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate returned an error: %s", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate returned an error: %s", err)
+	}
+	return cert, key
+}
+
+func TestDecodePEMECDSAKey(t *testing.T) {
+	key, err := GenerateECDSAKey()
+	if err != nil {
+		t.Fatalf("GenerateECDSAKey returned an error: %s", err)
+	}
+	sec1PEM, err := EncodeECDSAKey(key)
+	if err != nil {
+		t.Fatalf("EncodeECDSAKey returned an error: %s", err)
+	}
+	p8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey returned an error: %s", err)
+	}
+	p8PEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: p8})
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey returned an error: %s", err)
+	}
+	p384DER, err := x509.MarshalECPrivateKey(p384Key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey returned an error: %s", err)
+	}
+	p384PEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: p384DER})
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("GenerateKey returned an error: %s", err)
+	}
+	rsaPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+	testCases := []struct {
+		title string
+		pem   []byte
+		err   string
+	}{
+		{title: "decodes SEC 1 keys", pem: sec1PEM},
+		{title: "decodes PKCS#8 keys", pem: p8PEM},
+		{title: "rejects missing keys", pem: []byte("not a key"), err: "no PEM private key found"},
+		{title: "rejects RSA keys", pem: rsaPEM, err: "unsupported PEM block type \"RSA PRIVATE KEY\" for the private key (must be an ECDSA key)"},
+		{title: "rejects other curves", pem: p384PEM, err: "the private key must use the P-256 curve"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			decoded, err := DecodePEMECDSAKey(tc.pem)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("Expected error %q, got: %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if decoded.D.Cmp(key.D) != 0 {
+				t.Fatalf("Decoded key doesn't match the encoded one")
+			}
+		})
+	}
+}
+
+func TestValidateECDSAIssuer(t *testing.T) {
+	cert, key := genIssuer(t, true, time.Now().Add(time.Hour))
+	leafCert, leafKey := genIssuer(t, false, time.Now().Add(time.Hour))
+	expiredCert, expiredKey := genIssuer(t, true, time.Now().Add(-time.Minute))
+
+	testCases := []struct {
+		title string
+		cert  *x509.Certificate
+		key   *ecdsa.PrivateKey
+		err   string
+	}{
+		{title: "accepts CA certificates", cert: cert, key: key},
+		{title: "rejects non-CA certificates", cert: leafCert, key: leafKey, err: "certificate \"issuer\" is not a CA certificate"},
+		{title: "rejects expired certificates", cert: expiredCert, key: expiredKey, err: "certificate \"issuer\" is only valid from "},
+		{title: "rejects mismatched keys", cert: cert, key: leafKey, err: "the private key doesn't match the certificate"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			err := ValidateECDSAIssuer(tc.cert, tc.key)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+			} else if err == nil || !strings.HasPrefix(err.Error(), tc.err) {
+				t.Fatalf("Expected error starting with %q, got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestSignCSR(t *testing.T) {
+	cert, key := genIssuer(t, true, time.Now().Add(time.Hour))
+
+	leafKey, err := GenerateECDSAKey()
+	if err != nil {
+		t.Fatalf("GenerateECDSAKey returned an error: %s", err)
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		DNSNames: []string{"web.emojivoto.pod.cluster.local"},
+	}, leafKey)
+	if err != nil {
+		t.Fatalf("CreateCertificateRequest returned an error: %s", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := SignCSR(csr, &template, cert, key)
+	if err != nil {
+		t.Fatalf("SignCSR returned an error: %s", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate returned an error: %s", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	_, err = leaf.Verify(x509.VerifyOptions{
+		DNSName: "web.emojivoto.pod.cluster.local",
+		Roots:   roots,
+	})
+	if err != nil {
+		t.Fatalf("Issued certificate doesn't verify: %s", err)
+	}
+
+	t.Run("rejects tampered requests", func(t *testing.T) {
+		tampered := make([]byte, len(csr))
+		copy(tampered, csr)
+		tampered[len(tampered)-1] ^= 0xff
+		if _, err := SignCSR(tampered, &template, cert, key); err == nil {
+			t.Fatalf("Expected an error for a tampered request")
+		}
+	})
+}
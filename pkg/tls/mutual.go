@@ -0,0 +1,131 @@
+package tls
+
+import (
+	cryptoTls "crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+)
+
+// NewMutualTLSServerConfig returns the TLS configuration of a server that
+// presents the PEM certificate and key of certFile and keyFile, and requires
+// its clients to present a certificate for the DNS name clientName, issued by
+// one of the PEM trust anchors of trustAnchorsFile. The files are read again
+// on each handshake, so that the server picks up the certificates that the CA
+// reissues without restarting.
+func NewMutualTLSServerConfig(certFile, keyFile, trustAnchorsFile, clientName string) (*cryptoTls.Config, error) {
+	load := func() (*cryptoTls.Config, error) {
+		cert, err := cryptoTls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		trustAnchorsPEM, err := ioutil.ReadFile(trustAnchorsFile)
+		if err != nil {
+			return nil, err
+		}
+		trustAnchors, err := trustAnchorPool(trustAnchorsPEM)
+		if err != nil {
+			return nil, err
+		}
+		return &cryptoTls.Config{
+			Certificates:          []cryptoTls.Certificate{cert},
+			ClientAuth:            cryptoTls.RequireAndVerifyClientCert,
+			ClientCAs:             trustAnchors,
+			MinVersion:            cryptoTls.VersionTLS12,
+			VerifyPeerCertificate: verifyPeerName(clientName),
+		}, nil
+	}
+
+	// fail early on missing or invalid files
+	if _, err := load(); err != nil {
+		return nil, err
+	}
+	return &cryptoTls.Config{
+		GetConfigForClient: func(*cryptoTls.ClientHelloInfo) (*cryptoTls.Config, error) {
+			return load()
+		},
+		// unused, since GetConfigForClient replaces the config, but
+		// http.Server.ListenAndServeTLS requires a certificate
+		GetCertificate: func(*cryptoTls.ClientHelloInfo) (*cryptoTls.Certificate, error) {
+			cert, err := cryptoTls.LoadX509KeyPair(certFile, keyFile)
+			return &cert, err
+		},
+	}, nil
+}
+
+// NewMutualTLSClientConfig returns the TLS configuration of a client that
+// presents the PEM certificate and key certPEM and keyPEM, and requires the
+// server to present a certificate for the DNS name serverName, issued by one
+// of the PEM trust anchors of trustAnchorsPEM.
+func NewMutualTLSClientConfig(certPEM, keyPEM, trustAnchorsPEM []byte, serverName string) (*cryptoTls.Config, error) {
+	cert, err := cryptoTls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	trustAnchors, err := trustAnchorPool(trustAnchorsPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &cryptoTls.Config{
+		Certificates: []cryptoTls.Certificate{cert},
+		RootCAs:      trustAnchors,
+		ServerName:   serverName,
+		MinVersion:   cryptoTls.VersionTLS12,
+	}, nil
+}
+
+// NewMutualTLSClientFileConfig returns the TLS configuration of a client that
+// presents the PEM certificate and key of certFile and keyFile, as
+// NewMutualTLSClientConfig does. The certificate is read again on each
+// handshake, so that the client picks up the certificates that the CA
+// reissues without restarting.
+func NewMutualTLSClientFileConfig(certFile, keyFile, trustAnchorsFile, serverName string) (*cryptoTls.Config, error) {
+	certPEM, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	trustAnchorsPEM, err := ioutil.ReadFile(trustAnchorsFile)
+	if err != nil {
+		return nil, err
+	}
+	config, err := NewMutualTLSClientConfig(certPEM, keyPEM, trustAnchorsPEM, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Certificates = nil
+	config.GetClientCertificate = func(*cryptoTls.CertificateRequestInfo) (*cryptoTls.Certificate, error) {
+		cert, err := cryptoTls.LoadX509KeyPair(certFile, keyFile)
+		return &cert, err
+	}
+	return config, nil
+}
+
+func trustAnchorPool(trustAnchorsPEM []byte) (*x509.CertPool, error) {
+	certs, err := DecodePEMCerts(trustAnchorsPEM)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+	return pool, nil
+}
+
+// verifyPeerName returns a VerifyPeerCertificate function that only accepts
+// the verified peer certificates for the DNS name.
+func verifyPeerName(name string) func([][]byte, [][]*x509.Certificate) error {
+	return func(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+		for _, chain := range verifiedChains {
+			if len(chain) > 0 && chain[0].VerifyHostname(name) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("the client certificate isn't valid for %s", name)
+	}
+}
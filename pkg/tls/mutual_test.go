@@ -0,0 +1,159 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	cryptoTls "crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func genLeaf(t *testing.T, issuer *x509.Certificate, issuerKey *ecdsa.PrivateKey, name string) ([]byte, []byte) {
+	key, err := GenerateECDSAKey()
+	if err != nil {
+		t.Fatalf("GenerateECDSAKey returned an error: %s", err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, issuer, &key.PublicKey, issuerKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate returned an error: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey returned an error: %s", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestMutualTLSConfig(t *testing.T) {
+	issuer, issuerKey := genIssuer(t, true, time.Now().Add(time.Hour))
+	trustAnchorsPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: issuer.Raw})
+	otherIssuer, otherIssuerKey := genIssuer(t, true, time.Now().Add(time.Hour))
+
+	tmp, err := ioutil.TempDir("", "mutual-tls")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	serverCert, serverKey := genLeaf(t, issuer, issuerKey, "server.linkerd")
+	files := map[string][]byte{"cert.pem": serverCert, "key.pem": serverKey, "trust-anchors.pem": trustAnchorsPEM}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(tmp, name), content, 0600); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	serverConfig, err := NewMutualTLSServerConfig(filepath.Join(tmp, "cert.pem"), filepath.Join(tmp, "key.pem"), filepath.Join(tmp, "trust-anchors.pem"), "client.linkerd")
+	if err != nil {
+		t.Fatalf("NewMutualTLSServerConfig returned an error: %s", err)
+	}
+	if _, err := NewMutualTLSServerConfig(filepath.Join(tmp, "missing.pem"), filepath.Join(tmp, "key.pem"), filepath.Join(tmp, "trust-anchors.pem"), "client.linkerd"); err == nil {
+		t.Fatal("Expected an error for a missing certificate")
+	}
+
+	handshake := func(clientConfig *cryptoTls.Config) error {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		defer listener.Close()
+
+		serverErr := make(chan error, 1)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				serverErr <- err
+				return
+			}
+			server := cryptoTls.Server(conn, serverConfig)
+			err = server.Handshake()
+			if err == nil {
+				// with TLS 1.3, the client certificate is only rejected
+				// once the client reads from the connection
+				_, err = server.Write([]byte("ok"))
+			}
+			serverErr <- err
+			server.Close()
+		}()
+
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		client := cryptoTls.Client(conn, clientConfig)
+		defer client.Close()
+		clientErr := client.Handshake()
+		if clientErr == nil {
+			_, clientErr = ioutil.ReadAll(client)
+		}
+		if err := <-serverErr; err != nil {
+			return err
+		}
+		return clientErr
+	}
+
+	testCases := []struct {
+		title      string
+		clientName string
+		issuer     *x509.Certificate
+		issuerKey  *ecdsa.PrivateKey
+		serverName string
+		ok         bool
+	}{
+		{"accepts the client identity", "client.linkerd", issuer, issuerKey, "server.linkerd", true},
+		{"rejects other identities", "web.emojivoto", issuer, issuerKey, "server.linkerd", false},
+		{"rejects other issuers", "client.linkerd", otherIssuer, otherIssuerKey, "server.linkerd", false},
+		{"verifies the server identity", "client.linkerd", issuer, issuerKey, "other.linkerd", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			certPEM, keyPEM := genLeaf(t, tc.issuer, tc.issuerKey, tc.clientName)
+			clientConfig, err := NewMutualTLSClientConfig(certPEM, keyPEM, trustAnchorsPEM, tc.serverName)
+			if err != nil {
+				t.Fatalf("NewMutualTLSClientConfig returned an error: %s", err)
+			}
+			err = handshake(clientConfig)
+			if tc.ok && err != nil {
+				t.Fatalf("Unexpected handshake error: %s", err)
+			}
+			if !tc.ok && err == nil {
+				t.Fatal("Expected the handshake to fail")
+			}
+		})
+	}
+	t.Run("reads the client certificate from files", func(t *testing.T) {
+		certPEM, keyPEM := genLeaf(t, issuer, issuerKey, "client.linkerd")
+		if err := ioutil.WriteFile(filepath.Join(tmp, "client-cert.pem"), certPEM, 0600); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(tmp, "client-key.pem"), keyPEM, 0600); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		clientConfig, err := NewMutualTLSClientFileConfig(filepath.Join(tmp, "client-cert.pem"), filepath.Join(tmp, "client-key.pem"), filepath.Join(tmp, "trust-anchors.pem"), "server.linkerd")
+		if err != nil {
+			t.Fatalf("NewMutualTLSClientFileConfig returned an error: %s", err)
+		}
+		if err := handshake(clientConfig); err != nil {
+			t.Fatalf("Unexpected handshake error: %s", err)
+		}
+	})
+}
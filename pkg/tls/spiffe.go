@@ -0,0 +1,101 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// spiffeBundle is a SPIFFE trust bundle, as specified by
+// https://github.com/spiffe/spiffe/blob/master/standards/SPIFFE_Trust_Domain_and_Bundle.md
+type spiffeBundle struct {
+	Keys        []spiffeKey `json:"keys"`
+	RefreshHint int64       `json:"spiffe_refresh_hint,omitempty"`
+}
+
+type spiffeKey struct {
+	Use string   `json:"use"`
+	Kty string   `json:"kty"`
+	Crv string   `json:"crv,omitempty"`
+	X   string   `json:"x,omitempty"`
+	Y   string   `json:"y,omitempty"`
+	N   string   `json:"n,omitempty"`
+	E   string   `json:"e,omitempty"`
+	X5c []string `json:"x5c"`
+}
+
+// DecodePEMCerts decodes all the certificates of certsPEM, such as a bundle of
+// trust anchors.
+func DecodePEMCerts(certsPEM []byte) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	for {
+		var block *pem.Block
+		block, certsPEM = pem.Decode(certsPEM)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM certificate found")
+	}
+	return certs, nil
+}
+
+// EncodeSPIFFEBundle returns the SPIFFE trust bundle, in JSON, of the given
+// trust anchors, with an X.509 SVID key for each of them. Consumers are told
+// to refresh the bundle after refreshHint, if it's set.
+func EncodeSPIFFEBundle(trustAnchors []*x509.Certificate, refreshHint time.Duration) ([]byte, error) {
+	bundle := spiffeBundle{
+		Keys:        []spiffeKey{},
+		RefreshHint: int64(refreshHint / time.Second),
+	}
+
+	for _, cert := range trustAnchors {
+		key := spiffeKey{
+			Use: "x509-svid",
+			X5c: []string{base64.StdEncoding.EncodeToString(cert.Raw)},
+		}
+		switch publicKey := cert.PublicKey.(type) {
+		case *ecdsa.PublicKey:
+			size := (publicKey.Curve.Params().BitSize + 7) / 8
+			key.Kty = "EC"
+			key.Crv = publicKey.Curve.Params().Name
+			key.X = encodeJWKInt(publicKey.X, size)
+			key.Y = encodeJWKInt(publicKey.Y, size)
+		case *rsa.PublicKey:
+			key.Kty = "RSA"
+			key.N = encodeJWKInt(publicKey.N, 0)
+			key.E = encodeJWKInt(big.NewInt(int64(publicKey.E)), 0)
+		default:
+			return nil, fmt.Errorf("unsupported public key type %T for trust anchor \"%s\"", cert.PublicKey, cert.Subject.CommonName)
+		}
+		bundle.Keys = append(bundle.Keys, key)
+	}
+
+	return json.MarshalIndent(bundle, "", "  ")
+}
+
+// encodeJWKInt encodes n as the unpadded base64url string of its big-endian
+// bytes, left-padded with zeros to size bytes, as JWKs require.
+func encodeJWKInt(n *big.Int, size int) string {
+	b := n.Bytes()
+	if len(b) < size {
+		b = append(make([]byte, size-len(b)), b...)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,91 @@
+package tls
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestEncodeSPIFFEBundle(t *testing.T) {
+	ecCert, _ := genIssuer(t, true, time.Now().Add(time.Hour))
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("GenerateKey returned an error: %s", err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "rsa"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &rsaKey.PublicKey, rsaKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate returned an error: %s", err)
+	}
+	rsaCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate returned an error: %s", err)
+	}
+
+	encoded, err := EncodeSPIFFEBundle([]*x509.Certificate{ecCert, rsaCert}, time.Minute)
+	if err != nil {
+		t.Fatalf("EncodeSPIFFEBundle returned an error: %s", err)
+	}
+	var bundle spiffeBundle
+	if err := json.Unmarshal(encoded, &bundle); err != nil {
+		t.Fatalf("Invalid bundle %s: %s", encoded, err)
+	}
+
+	if bundle.RefreshHint != 60 {
+		t.Fatalf("Expected a refresh hint of 60s, got %d", bundle.RefreshHint)
+	}
+	if len(bundle.Keys) != 2 {
+		t.Fatalf("Expected 2 keys, got %d", len(bundle.Keys))
+	}
+
+	ecKey := bundle.Keys[0]
+	if ecKey.Use != "x509-svid" || ecKey.Kty != "EC" || ecKey.Crv != "P-256" {
+		t.Fatalf("Unexpected EC key: %+v", ecKey)
+	}
+	for _, coordinate := range []string{ecKey.X, ecKey.Y} {
+		if b, err := base64.RawURLEncoding.DecodeString(coordinate); err != nil || len(b) != 32 {
+			t.Fatalf("Expected a 32 byte coordinate, got %s", coordinate)
+		}
+	}
+	if ecKey.X5c[0] != base64.StdEncoding.EncodeToString(ecCert.Raw) {
+		t.Fatalf("Expected the EC key to contain its certificate")
+	}
+
+	rsaJWK := bundle.Keys[1]
+	if rsaJWK.Kty != "RSA" || rsaJWK.E != "AQAB" || rsaJWK.N == "" {
+		t.Fatalf("Unexpected RSA key: %+v", rsaJWK)
+	}
+}
+
+func TestDecodePEMCerts(t *testing.T) {
+	cert, _ := genIssuer(t, true, time.Now().Add(time.Hour))
+	certPEM, err := PEMEncodeCert(cert.Raw)
+	if err != nil {
+		t.Fatalf("PEMEncodeCert returned an error: %s", err)
+	}
+
+	certs, err := DecodePEMCerts(append(certPEM, certPEM...))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(certs) != 2 {
+		t.Fatalf("Expected 2 certificates, got %d", len(certs))
+	}
+
+	if _, err := DecodePEMCerts([]byte("not a certificate")); err == nil || err.Error() != "no PEM certificate found" {
+		t.Fatalf("Expected an error for a bundle without certificates, got: %v", err)
+	}
+}
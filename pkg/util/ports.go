@@ -0,0 +1,76 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PortRange is an inclusive range of TCP ports. A single port is represented
+// by a range with equal bounds.
+type PortRange struct {
+	LowerBound uint16
+	UpperBound uint16
+}
+
+// ParsePortRange parses a single port, such as "4000", or a range of ports,
+// such as "4000-4100".
+func ParsePortRange(spec string) (PortRange, error) {
+	bounds := strings.SplitN(strings.TrimSpace(spec), "-", 2)
+
+	lower, err := parsePort(bounds[0])
+	if err != nil {
+		return PortRange{}, fmt.Errorf("invalid port range \"%s\": %s", spec, err)
+	}
+	if len(bounds) == 1 {
+		return PortRange{LowerBound: lower, UpperBound: lower}, nil
+	}
+
+	upper, err := parsePort(bounds[1])
+	if err != nil {
+		return PortRange{}, fmt.Errorf("invalid port range \"%s\": %s", spec, err)
+	}
+	if lower > upper {
+		return PortRange{}, fmt.Errorf("invalid port range \"%s\": lower bound is greater than upper bound", spec)
+	}
+
+	return PortRange{LowerBound: lower, UpperBound: upper}, nil
+}
+
+// ParsePortRanges parses a comma-separated list of ports and port ranges, such
+// as "25,587,4000-4100".
+func ParsePortRanges(spec string) ([]PortRange, error) {
+	ranges := []PortRange{}
+	for _, s := range strings.Split(spec, ",") {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		pr, err := ParsePortRange(s)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, pr)
+	}
+	return ranges, nil
+}
+
+// Contains returns true if the given port is in the range.
+func (pr PortRange) Contains(port uint32) bool {
+	return port >= uint32(pr.LowerBound) && port <= uint32(pr.UpperBound)
+}
+
+// String returns the port range in the format accepted by ParsePortRange.
+func (pr PortRange) String() string {
+	if pr.LowerBound == pr.UpperBound {
+		return strconv.Itoa(int(pr.LowerBound))
+	}
+	return fmt.Sprintf("%d-%d", pr.LowerBound, pr.UpperBound)
+}
+
+func parsePort(port string) (uint16, error) {
+	p, err := strconv.ParseUint(strings.TrimSpace(port), 10, 16)
+	if err != nil || p == 0 {
+		return 0, fmt.Errorf("\"%s\" is not a valid TCP port number", port)
+	}
+	return uint16(p), nil
+}
@@ -0,0 +1,114 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// CheckURL is the endpoint queried by the CLI and the dashboard for the
+// latest Linkerd versions. The queries report the running version, the
+// install's UUID and the source of the query, so downstream distributions may
+// want to point it at their own endpoint, or set it to an empty string to
+// disable version checks entirely. It can be set at link time with:
+//
+//	-ldflags "-X github.com/linkerd/linkerd2/pkg/version.CheckURL=<url>"
+var CheckURL = "https://versioncheck.linkerd.io/version.json"
+
+// ErrCheckDisabled is returned by version checks when they were disabled at
+// build time.
+var ErrCheckDisabled = errors.New("version checks are disabled")
+
+// Checker looks up the latest Linkerd versions.
+type Checker interface {
+	// LatestVersions returns the latest version of each release channel,
+	// keyed by channel name.
+	LatestVersions(ctx context.Context, uuid, source string) (map[string]string, error)
+}
+
+// DefaultChecker is the Checker used by GetLatestVersion. It queries CheckURL,
+// and can be replaced by distributions that report to a different sink.
+var DefaultChecker = NewChecker(CheckURL)
+
+// CheckEnabled returns true unless version checks were disabled at build
+// time, by setting CheckURL to an empty string.
+func CheckEnabled() bool {
+	_, disabled := DefaultChecker.(disabledChecker)
+	return !disabled
+}
+
+// NewChecker returns a Checker querying the given endpoint, or one that always
+// fails with ErrCheckDisabled if the endpoint is empty.
+func NewChecker(endpoint string) Checker {
+	if endpoint == "" {
+		return disabledChecker{}
+	}
+	return &httpChecker{endpoint: endpoint, client: http.DefaultClient}
+}
+
+type disabledChecker struct{}
+
+func (disabledChecker) LatestVersions(context.Context, string, string) (map[string]string, error) {
+	return nil, ErrCheckDisabled
+}
+
+type httpChecker struct {
+	endpoint string
+	client   *http.Client
+}
+
+// CheckRequestURL returns the URL that a version check queries endpoint with,
+// which carries all the data that the check sends: the running version, the
+// install's UUID and the source of the query.
+func CheckRequestURL(endpoint, version, uuid, source string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("Invalid versioncheck URL %s: %s", endpoint, err)
+	}
+
+	query := u.Query()
+	query.Set("version", version)
+	query.Set("uuid", uuid)
+	query.Set("source", source)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+func (c *httpChecker) LatestVersions(ctx context.Context, uuid, source string) (map[string]string, error) {
+	checkURL, err := CheckRequestURL(c.endpoint, Version, uuid, source)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", checkURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != 200 {
+		return nil, fmt.Errorf("Unexpected versioncheck response: %s", rsp.Status)
+	}
+
+	bytes, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions map[string]string
+	err = json.Unmarshal(bytes, &versions)
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
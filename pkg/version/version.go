@@ -2,11 +2,9 @@ package version
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"os"
+	"runtime"
 	"strings"
 	"time"
 
@@ -18,9 +16,20 @@ import (
 // DO NOT EDIT
 var Version = undefinedVersion
 
-const (
-	undefinedVersion = "undefined"
-	versionCheckURL  = "https://versioncheck.linkerd.io/version.json?version=%s&uuid=%s&source=%s"
+const undefinedVersion = "undefined"
+
+// GitSHA, BuildDate and BuildTags identify the build, so that bug reports
+// can tell the builds of the same version apart. They're set at link time
+// along with Version, e.g.:
+//
+//	-ldflags "-X github.com/linkerd/linkerd2/pkg/version.GitSHA=$(git rev-parse HEAD)"
+//
+// BuildDate is in RFC 3339 format, and BuildTags is the comma separated list
+// of the build tags, such as nopprof.
+var (
+	GitSHA    = ""
+	BuildDate = ""
+	BuildTags = ""
 )
 
 func init() {
@@ -35,6 +44,29 @@ func init() {
 			Version = override
 		}
 	}
+	// the build info of the containers is bound the same way
+	if GitSHA == "" {
+		GitSHA = os.Getenv("LINKERD_CONTAINER_GIT_SHA_OVERRIDE")
+	}
+	if BuildDate == "" {
+		BuildDate = os.Getenv("LINKERD_CONTAINER_BUILD_DATE_OVERRIDE")
+	}
+}
+
+// Info returns the version and the build info of the running binary.
+func Info() *pb.VersionInfo {
+	info := &pb.VersionInfo{
+		GoVersion:      runtime.Version(),
+		BuildDate:      BuildDate,
+		ReleaseVersion: Version,
+		GitSha:         GitSHA,
+	}
+	for _, tag := range strings.Split(BuildTags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			info.BuildTags = append(info.BuildTags, tag)
+		}
+	}
+	return info
 }
 
 // CheckClientVersion validates whether the Linkerd Public API client's version
@@ -75,35 +107,13 @@ func CheckServerVersion(apiClient pb.ApiClient, expectedVersion string) error {
 	return nil
 }
 
-// GetLatestVersion performs an online request to check for the latest Linkerd
-// version.
+// GetLatestVersion queries DefaultChecker for the latest Linkerd version in
+// the release channel of the running version.
 func GetLatestVersion(uuid string, source string) (string, error) {
-	url := fmt.Sprintf(versionCheckURL, Version, uuid, source)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return "", err
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	rsp, err := http.DefaultClient.Do(req.WithContext(ctx))
-	if err != nil {
-		return "", err
-	}
-	defer rsp.Body.Close()
-
-	if rsp.StatusCode != 200 {
-		return "", fmt.Errorf("Unexpected versioncheck response: %s", rsp.Status)
-	}
-
-	bytes, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	var versionRsp map[string]string
-	err = json.Unmarshal(bytes, &versionRsp)
+	versions, err := DefaultChecker.LatestVersions(ctx, uuid, source)
 	if err != nil {
 		return "", err
 	}
@@ -113,7 +123,7 @@ func GetLatestVersion(uuid string, source string) (string, error) {
 		return "", fmt.Errorf("Unsupported version format: %s", Version)
 	}
 
-	version, ok := versionRsp[channel]
+	version, ok := versions[channel]
 	if !ok {
 		return "", fmt.Errorf("Unsupported version channel: %s", channel)
 	}
@@ -1,6 +1,11 @@
 package version_test
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
 	"testing"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
@@ -49,3 +54,51 @@ func createMockPublicAPI(version string) *public.MockAPIClient {
 		},
 	}
 }
+
+func TestInfo(t *testing.T) {
+	defer func(sha, tags string) { version.GitSHA, version.BuildTags = sha, tags }(version.GitSHA, version.BuildTags)
+	version.GitSHA = "2d6f90ab1293a1fb871cf149423ebb72aa7423aa"
+	version.BuildTags = "nopprof, netgo"
+
+	info := version.Info()
+	if info.GetReleaseVersion() != version.Version || info.GetGitSha() != version.GitSHA || info.GetGoVersion() == "" {
+		t.Fatalf("Unexpected version info: %v", info)
+	}
+	if expected := []string{"nopprof", "netgo"}; !reflect.DeepEqual(info.GetBuildTags(), expected) {
+		t.Fatalf("Expected build tags %v, got %v", expected, info.GetBuildTags())
+	}
+}
+
+func TestChecker(t *testing.T) {
+	t.Run("Queries the configured endpoint", func(t *testing.T) {
+		var query url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			query = req.URL.Query()
+			w.Write([]byte(`{"edge":"edge-1.2.3","stable":"stable-2.1.0"}`))
+		}))
+		defer server.Close()
+
+		versions, err := version.NewChecker(server.URL+"/version.json?distro=test").LatestVersions(context.Background(), "fake-uuid", "cli")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := map[string]string{"edge": "edge-1.2.3", "stable": "stable-2.1.0"}
+		if !reflect.DeepEqual(versions, expected) {
+			t.Fatalf("Expected versions %v, got %v", expected, versions)
+		}
+
+		for param, value := range map[string]string{"version": version.Version, "uuid": "fake-uuid", "source": "cli", "distro": "test"} {
+			if query.Get(param) != value {
+				t.Fatalf("Expected query parameter %s=%s, got %v", param, value, query)
+			}
+		}
+	})
+
+	t.Run("Fails when the endpoint is empty", func(t *testing.T) {
+		_, err := version.NewChecker("").LatestVersions(context.Background(), "fake-uuid", "cli")
+		if err != version.ErrCheckDisabled {
+			t.Fatalf("Expected error %s, got %v", version.ErrCheckDisabled, err)
+		}
+	})
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type awaitOptions struct {
+	procDir        string
+	pollInterval   time.Duration
+	gracePeriod    time.Duration
+	ignoreCommands []string
+}
+
+func newAwaitOptions() *awaitOptions {
+	return &awaitOptions{
+		procDir:        "/proc",
+		pollInterval:   time.Second,
+		gracePeriod:    10 * time.Second,
+		ignoreCommands: make([]string, 0),
+	}
+}
+
+// newCmdAwait returns the `proxy-init await` command, which the proxy
+// injector runs the proxy of Jobs and CronJobs with, so that their pods
+// complete once the other containers exit.
+func newCmdAwait() *cobra.Command {
+	options := newAwaitOptions()
+
+	cmd := &cobra.Command{
+		Use:   "await [flags] -- PROXY [ARGS...]",
+		Short: "Run the proxy, and stop it once the pod's other processes are done",
+		Long: `Run the proxy, and stop it once the pod's other processes are done.
+
+The pod must share its process namespace, so that the processes of its other
+containers are listed in /proc. Once they've all exited, and none has been
+started again during the grace period, the proxy is terminated and await exits
+successfully, so that the pod completes. Otherwise the exit code is the
+proxy's.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			code, err := runAwait(options, args)
+			if err != nil {
+				return err
+			}
+			os.Exit(code)
+			return nil
+		},
+	}
+
+	// the flags of the proxy are its own
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().DurationVar(&options.gracePeriod, "grace-period", options.gracePeriod, "How long the pod must have no other processes before the proxy is stopped, which must cover the restarts of failed containers")
+	cmd.Flags().StringSliceVar(&options.ignoreCommands, "ignore-command", options.ignoreCommands, "Names of the commands, such as those of a debug sidecar, that don't keep the proxy running")
+
+	return cmd
+}
+
+// runAwait runs the proxy, forwarding the termination signals to it, and
+// terminates it once the pod's other processes are done. It returns 0 if the
+// proxy was terminated by await, and the exit code of the proxy otherwise.
+func runAwait(options *awaitOptions, args []string) (int, error) {
+	proxy := exec.Command(args[0], args[1:]...)
+	proxy.Stdin = os.Stdin
+	proxy.Stdout = os.Stdout
+	proxy.Stderr = os.Stderr
+
+	if err := proxy.Start(); err != nil {
+		return 0, err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		for sig := range signals {
+			proxy.Process.Signal(sig)
+		}
+	}()
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- proxy.Wait()
+	}()
+
+	ticker := time.NewTicker(options.pollInterval)
+	defer ticker.Stop()
+
+	// the proxy may start before the other containers, so it's only stopped
+	// once their processes have been seen
+	var lastSeen time.Time
+	for {
+		select {
+		case err := <-exited:
+			return proxyExitCode(err)
+
+		case now := <-ticker.C:
+			processes, err := podProcesses(options.procDir, os.Getpid(), options.ignoreCommands)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed to list the pod's processes: %s\n", err)
+				continue
+			}
+			if processes > 0 {
+				lastSeen = now
+				continue
+			}
+			if lastSeen.IsZero() || now.Sub(lastSeen) < options.gracePeriod {
+				continue
+			}
+
+			proxy.Process.Signal(syscall.SIGTERM)
+			<-exited
+			return 0, nil
+		}
+	}
+}
+
+// procStat holds the fields of /proc/<pid>/stat that await relies on.
+type procStat struct {
+	command string
+	state   string
+	ppid    int
+}
+
+// podProcesses returns the number of running processes in procDir that are
+// neither the pod's infrastructure process (pid 1), await and its
+// descendants, nor ignored commands.
+func podProcesses(procDir string, self int, ignoreCommands []string) (int, error) {
+	entries, err := ioutil.ReadDir(procDir)
+	if err != nil {
+		return 0, err
+	}
+
+	stats := map[int]procStat{}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		stat, err := readProcStat(filepath.Join(procDir, entry.Name(), "stat"))
+		if err != nil {
+			// the process exited since the directory was read
+			continue
+		}
+		stats[pid] = stat
+	}
+
+	ignored := map[string]bool{}
+	for _, command := range ignoreCommands {
+		ignored[command] = true
+	}
+
+	processes := 0
+	for pid, stat := range stats {
+		if pid == 1 || stat.state == "Z" || ignored[stat.command] || descends(stats, pid, self) {
+			continue
+		}
+		processes++
+	}
+	return processes, nil
+}
+
+// descends returns true if pid is ancestor or one of its descendants.
+func descends(stats map[int]procStat, pid, ancestor int) bool {
+	// the parents are bounded by the number of processes, in case of a cycle
+	// in a torn read of /proc
+	for i := 0; i <= len(stats); i++ {
+		if pid == ancestor {
+			return true
+		}
+		stat, ok := stats[pid]
+		if !ok || pid <= 1 {
+			return false
+		}
+		pid = stat.ppid
+	}
+	return false
+}
+
+// readProcStat parses /proc/<pid>/stat, whose second field is the command
+// between parentheses, which may itself contain spaces and parentheses.
+func readProcStat(path string) (procStat, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return procStat{}, err
+	}
+
+	stat := string(content)
+	start := strings.Index(stat, "(")
+	end := strings.LastIndex(stat, ")")
+	if start < 0 || end < start {
+		return procStat{}, fmt.Errorf("invalid %s: %q", path, stat)
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 2 {
+		return procStat{}, fmt.Errorf("invalid %s: %q", path, stat)
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return procStat{}, fmt.Errorf("invalid %s: %q", path, stat)
+	}
+
+	return procStat{
+		command: stat[start+1 : end],
+		state:   fields[0],
+		ppid:    ppid,
+	}, nil
+}
+
+// proxyExitCode returns the exit code of the proxy, following the shells'
+// convention of 128 plus the signal when it's killed by a signal.
+func proxyExitCode(err error) (int, error) {
+	if err == nil {
+		return 0, nil
+	}
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		return 0, err
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	if !ok {
+		return 1, nil
+	}
+	if status.Signaled() {
+		return 128 + int(status.Signal()), nil
+	}
+	return status.ExitStatus(), nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newFakeProc returns a directory laid out like /proc, with the given stat
+// lines keyed by pid.
+func newFakeProc(t *testing.T, stats map[int]string) string {
+	dir, err := ioutil.TempDir("", "linkerd-await-proc")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for pid, stat := range stats {
+		pidDir := filepath.Join(dir, fmt.Sprintf("%d", pid))
+		if err := os.Mkdir(pidDir, 0755); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(pidDir, "stat"), []byte(stat), 0644); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+	return dir
+}
+
+func TestPodProcesses(t *testing.T) {
+	procDir := newFakeProc(t, map[int]string{
+		1:  "1 (pause) S 0 1 1 0 -1",
+		10: "10 (nginx) S 1 10 10 0 -1",
+		11: "11 (nginx) S 10 10 10 0 -1",
+		12: "12 (upload (v2)) R 1 12 12 0 -1",
+		20: "20 (proxy-init) S 1 20 20 0 -1",
+		21: "21 (linkerd2-proxy) S 20 20 20 0 -1",
+		30: "30 (tshark) S 1 30 30 0 -1",
+		31: "31 (dumpcap) S 30 30 30 0 -1",
+		40: "40 (sh) Z 1 40 40 0 -1",
+	})
+	defer os.RemoveAll(procDir)
+	os.Mkdir(filepath.Join(procDir, "self"), 0755)
+
+	processes, err := podProcesses(procDir, 20, []string{"tshark", "dumpcap"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if processes != 3 {
+		t.Fatalf("Expected the nginx and upload processes to be counted, got %d processes", processes)
+	}
+}
+
+func TestRunAwait(t *testing.T) {
+	newOptions := func(procDir string) *awaitOptions {
+		options := newAwaitOptions()
+		options.procDir = procDir
+		options.pollInterval = 10 * time.Millisecond
+		options.gracePeriod = 50 * time.Millisecond
+		return options
+	}
+
+	t.Run("stops the proxy once the other processes are done", func(t *testing.T) {
+		procDir := newFakeProc(t, map[int]string{
+			1:  "1 (pause) S 0 1 1 0 -1",
+			10: "10 (job) S 1 10 10 0 -1",
+		})
+		defer os.RemoveAll(procDir)
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			os.RemoveAll(filepath.Join(procDir, "10"))
+		}()
+
+		start := time.Now()
+		code, err := runAwait(newOptions(procDir), []string{"sleep", "30"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if code != 0 {
+			t.Fatalf("Expected exit code 0, got %d", code)
+		}
+		if elapsed := time.Since(start); elapsed < 150*time.Millisecond || elapsed > 10*time.Second {
+			t.Fatalf("Expected the proxy to be stopped after the grace period, got %s", elapsed)
+		}
+	})
+
+	t.Run("keeps the proxy running until the other processes start", func(t *testing.T) {
+		procDir := newFakeProc(t, map[int]string{
+			1: "1 (pause) S 0 1 1 0 -1",
+		})
+		defer os.RemoveAll(procDir)
+
+		code, err := runAwait(newOptions(procDir), []string{"sh", "-c", "sleep 0.2; exit 3"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if code != 3 {
+			t.Fatalf("Expected the exit code of the proxy, got %d", code)
+		}
+	})
+
+	t.Run("fails when the proxy can't start", func(t *testing.T) {
+		if _, err := runAwait(newOptions(os.TempDir()), []string{"/nonexistent/proxy"}); err == nil {
+			t.Fatal("Expected an error")
+		}
+	})
+}
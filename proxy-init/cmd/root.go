@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/linkerd/linkerd2/proxy-init/iptables"
 	"github.com/spf13/cobra"
@@ -12,8 +14,8 @@ type rootOptions struct {
 	outgoingProxyPort     int
 	proxyUserID           int
 	portsToRedirect       []int
-	inboundPortsToIgnore  []int
-	outboundPortsToIgnore []int
+	inboundPortsToIgnore  []string
+	outboundPortsToIgnore []string
 	simulateOnly          bool
 }
 
@@ -23,8 +25,8 @@ func newRootOptions() *rootOptions {
 		outgoingProxyPort:     -1,
 		proxyUserID:           -1,
 		portsToRedirect:       make([]int, 0),
-		inboundPortsToIgnore:  make([]int, 0),
-		outboundPortsToIgnore: make([]int, 0),
+		inboundPortsToIgnore:  make([]string, 0),
+		outboundPortsToIgnore: make([]string, 0),
 		simulateOnly:          false,
 	}
 }
@@ -51,10 +53,12 @@ func NewRootCmd() *cobra.Command {
 	cmd.PersistentFlags().IntVarP(&options.outgoingProxyPort, "outgoing-proxy-port", "o", options.outgoingProxyPort, "Port to redirect outgoing traffic")
 	cmd.PersistentFlags().IntVarP(&options.proxyUserID, "proxy-uid", "u", options.proxyUserID, "User ID that the proxy is running under. Any traffic coming from this user will be ignored to avoid infinite redirection loops.")
 	cmd.PersistentFlags().IntSliceVarP(&options.portsToRedirect, "ports-to-redirect", "r", options.portsToRedirect, "Port to redirect to proxy, if no port is specified then ALL ports are redirected")
-	cmd.PersistentFlags().IntSliceVar(&options.inboundPortsToIgnore, "inbound-ports-to-ignore", options.inboundPortsToIgnore, "Inbound ports to ignore and not redirect to proxy. This has higher precedence than any other parameters.")
-	cmd.PersistentFlags().IntSliceVar(&options.outboundPortsToIgnore, "outbound-ports-to-ignore", options.outboundPortsToIgnore, "Outbound ports to ignore and not redirect to proxy. This has higher precedence than any other parameters.")
+	cmd.PersistentFlags().StringSliceVar(&options.inboundPortsToIgnore, "inbound-ports-to-ignore", options.inboundPortsToIgnore, "Inbound ports and port ranges (e.g. 4000-4100) to ignore and not redirect to proxy. This has higher precedence than any other parameters.")
+	cmd.PersistentFlags().StringSliceVar(&options.outboundPortsToIgnore, "outbound-ports-to-ignore", options.outboundPortsToIgnore, "Outbound ports and port ranges (e.g. 4000-4100) to ignore and not redirect to proxy. This has higher precedence than any other parameters.")
 	cmd.PersistentFlags().BoolVar(&options.simulateOnly, "simulate", options.simulateOnly, "Don't execute any command, just print what would be executed")
 
+	cmd.AddCommand(newCmdAwait())
+
 	return cmd
 }
 
@@ -67,13 +71,23 @@ func buildFirewallConfiguration(options *rootOptions) (*iptables.FirewallConfigu
 		return nil, fmt.Errorf("--outgoing-proxy-port must be a valid TCP port number")
 	}
 
+	inboundPortsToIgnore, err := parsePortRanges(options.inboundPortsToIgnore)
+	if err != nil {
+		return nil, fmt.Errorf("--inbound-ports-to-ignore must only contain valid TCP port numbers and port ranges")
+	}
+
+	outboundPortsToIgnore, err := parsePortRanges(options.outboundPortsToIgnore)
+	if err != nil {
+		return nil, fmt.Errorf("--outbound-ports-to-ignore must only contain valid TCP port numbers and port ranges")
+	}
+
 	firewallConfiguration := &iptables.FirewallConfiguration{
 		ProxyInboundPort:       options.incomingProxyPort,
 		ProxyOutgoingPort:      options.outgoingProxyPort,
 		ProxyUID:               options.proxyUserID,
 		PortsToRedirectInbound: options.portsToRedirect,
-		InboundPortsToIgnore:   options.inboundPortsToIgnore,
-		OutboundPortsToIgnore:  options.outboundPortsToIgnore,
+		InboundPortsToIgnore:   inboundPortsToIgnore,
+		OutboundPortsToIgnore:  outboundPortsToIgnore,
 		SimulateOnly:           options.simulateOnly,
 	}
 
@@ -85,3 +99,41 @@ func buildFirewallConfiguration(options *rootOptions) (*iptables.FirewallConfigu
 
 	return firewallConfiguration, nil
 }
+
+// parsePortRanges parses ports, such as "4000", and port ranges, such as
+// "4000-4100".
+func parsePortRanges(specs []string) ([]iptables.PortRange, error) {
+	ranges := make([]iptables.PortRange, 0)
+	for _, spec := range specs {
+		bounds := strings.SplitN(spec, "-", 2)
+
+		lower, err := parsePort(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		upper := lower
+		if len(bounds) == 2 {
+			upper, err = parsePort(bounds[1])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if lower > upper {
+			return nil, fmt.Errorf("invalid port range %s", spec)
+		}
+		ranges = append(ranges, iptables.PortRange{LowerBound: lower, UpperBound: upper})
+	}
+	return ranges, nil
+}
+
+func parsePort(port string) (int, error) {
+	p, err := strconv.Atoi(strings.TrimSpace(port))
+	if err != nil {
+		return 0, err
+	}
+	if p < 1 || p > 65535 {
+		return 0, fmt.Errorf("invalid port %d", p)
+	}
+	return p, nil
+}
@@ -15,8 +15,8 @@ func TestBuildFirewallConfiguration(t *testing.T) {
 		expectedConfig := &iptables.FirewallConfiguration{
 			Mode:                   iptables.RedirectAllMode,
 			PortsToRedirectInbound: make([]int, 0),
-			InboundPortsToIgnore:   make([]int, 0),
-			OutboundPortsToIgnore:  make([]int, 0),
+			InboundPortsToIgnore:   make([]iptables.PortRange, 0),
+			OutboundPortsToIgnore:  make([]iptables.PortRange, 0),
 			ProxyInboundPort:       expectedIncomingProxyPort,
 			ProxyOutgoingPort:      expectedOutgoingProxyPort,
 			ProxyUID:               expectedProxyUserID,
@@ -38,6 +38,29 @@ func TestBuildFirewallConfiguration(t *testing.T) {
 		}
 	})
 
+	t.Run("It parses ports and port ranges to ignore", func(t *testing.T) {
+		options := newRootOptions()
+		options.incomingProxyPort = 1234
+		options.outgoingProxyPort = 2345
+		options.inboundPortsToIgnore = []string{"4190", "4191"}
+		options.outboundPortsToIgnore = []string{"25", "4000-4100"}
+
+		config, err := buildFirewallConfiguration(options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expectedInbound := []iptables.PortRange{{LowerBound: 4190, UpperBound: 4190}, {LowerBound: 4191, UpperBound: 4191}}
+		if !reflect.DeepEqual(config.InboundPortsToIgnore, expectedInbound) {
+			t.Fatalf("Expected inbound ports to ignore [%v] but got [%v]", expectedInbound, config.InboundPortsToIgnore)
+		}
+
+		expectedOutbound := []iptables.PortRange{{LowerBound: 25, UpperBound: 25}, {LowerBound: 4000, UpperBound: 4100}}
+		if !reflect.DeepEqual(config.OutboundPortsToIgnore, expectedOutbound) {
+			t.Fatalf("Expected outbound ports to ignore [%v] but got [%v]", expectedOutbound, config.OutboundPortsToIgnore)
+		}
+	})
+
 	t.Run("It rejects invalid config options", func(t *testing.T) {
 		for _, tt := range []struct {
 			options      *rootOptions
@@ -71,6 +94,30 @@ func TestBuildFirewallConfiguration(t *testing.T) {
 				},
 				errorMessage: "--outgoing-proxy-port must be a valid TCP port number",
 			},
+			{
+				options: &rootOptions{
+					incomingProxyPort:    1234,
+					outgoingProxyPort:    2345,
+					inboundPortsToIgnore: []string{"4190", "http"},
+				},
+				errorMessage: "--inbound-ports-to-ignore must only contain valid TCP port numbers and port ranges",
+			},
+			{
+				options: &rootOptions{
+					incomingProxyPort:     1234,
+					outgoingProxyPort:     2345,
+					outboundPortsToIgnore: []string{"4100-4000"},
+				},
+				errorMessage: "--outbound-ports-to-ignore must only contain valid TCP port numbers and port ranges",
+			},
+			{
+				options: &rootOptions{
+					incomingProxyPort:     1234,
+					outgoingProxyPort:     2345,
+					outboundPortsToIgnore: []string{"4000-100000"},
+				},
+				errorMessage: "--outbound-ports-to-ignore must only contain valid TCP port numbers and port ranges",
+			},
 		} {
 			_, err := buildFirewallConfiguration(tt.options)
 			if err == nil {
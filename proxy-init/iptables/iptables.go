@@ -29,12 +29,28 @@ var (
 	ExecutionTraceID = strconv.Itoa(int(time.Now().Unix()))
 )
 
+// PortRange is an inclusive range of ports. A single port is represented by a
+// range with equal bounds.
+type PortRange struct {
+	LowerBound int
+	UpperBound int
+}
+
+// String returns the port range in the format of iptables' --destination-port
+// option.
+func (pr PortRange) String() string {
+	if pr.LowerBound == pr.UpperBound {
+		return strconv.Itoa(pr.LowerBound)
+	}
+	return fmt.Sprintf("%d:%d", pr.LowerBound, pr.UpperBound)
+}
+
 // FirewallConfiguration specifies how to configure a pod's iptables.
 type FirewallConfiguration struct {
 	Mode                   string
 	PortsToRedirectInbound []int
-	InboundPortsToIgnore   []int
-	OutboundPortsToIgnore  []int
+	InboundPortsToIgnore   []PortRange
+	OutboundPortsToIgnore  []PortRange
 	ProxyInboundPort       int
 	ProxyOutgoingPort      int
 	ProxyUID               int
@@ -147,11 +163,11 @@ func addRulesForInboundPortRedirect(firewallConfiguration FirewallConfiguration,
 	return commands
 }
 
-func addRulesForIgnoredPorts(portsToIgnore []int, chainName string, commands []*exec.Cmd) []*exec.Cmd {
+func addRulesForIgnoredPorts(portsToIgnore []PortRange, chainName string, commands []*exec.Cmd) []*exec.Cmd {
 	for _, ignoredPort := range portsToIgnore {
-		log.Printf("Will ignore port %d on chain %s", ignoredPort, chainName)
+		log.Printf("Will ignore port %s on chain %s", ignoredPort, chainName)
 
-		commands = append(commands, makeIgnorePort(chainName, ignoredPort, fmt.Sprintf("ignore-port-%d", ignoredPort)))
+		commands = append(commands, makeIgnorePort(chainName, ignoredPort, fmt.Sprintf("ignore-port-%s", ignoredPort)))
 	}
 	return commands
 }
@@ -212,12 +228,12 @@ func makeRedirectChainToPort(chainName string, portToRedirect int, comment strin
 		"--comment", formatComment(comment))
 }
 
-func makeIgnorePort(chainName string, portToIgnore int, comment string) *exec.Cmd {
+func makeIgnorePort(chainName string, portToIgnore PortRange, comment string) *exec.Cmd {
 	return exec.Command("iptables",
 		"-t", "nat",
 		"-A", chainName,
 		"-p", "tcp",
-		"--destination-port", strconv.Itoa(portToIgnore),
+		"--destination-port", portToIgnore.String(),
 		"-j", "RETURN",
 		"-m", "comment",
 		"--comment", formatComment(comment))
@@ -2,10 +2,27 @@ import PropTypes from 'prop-types';
 import React from 'react';
 import { grafanaIcon } from './util/SvgWrappers.jsx';
 
-const GrafanaLink = ({PrefixedLink, name, namespace, resource}) => {
+// links to the dashboard of the resource in the bundled Grafana, proxied by
+// the web server, or in the existing Grafana at grafanaUrl, in which the
+// dashboards are found by their UID
+const GrafanaLink = ({PrefixedLink, name, namespace, resource, grafanaUrl, grafanaUidPrefix}) => {
+  let vars = `var-namespace=${namespace}&var-${resource}=${name}`;
+
+  if (grafanaUrl) {
+    return (
+      <a
+        href={`${grafanaUrl}/d/${grafanaUidPrefix}${resource}?${vars}`}
+        target="_blank"
+        rel="noopener noreferrer">
+        &nbsp;&nbsp;
+        {grafanaIcon}
+      </a>
+    );
+  }
+
   return (
     <PrefixedLink
-      to={`/grafana/dashboard/db/linkerd-${resource}?var-namespace=${namespace}&var-${resource}=${name}`}
+      to={`/grafana/dashboard/db/linkerd-${resource}?${vars}`}
       targetBlank={true}>
       &nbsp;&nbsp;
       {grafanaIcon}
@@ -14,10 +31,17 @@ const GrafanaLink = ({PrefixedLink, name, namespace, resource}) => {
 };
 
 GrafanaLink.propTypes = {
+  grafanaUidPrefix: PropTypes.string,
+  grafanaUrl: PropTypes.string,
   name: PropTypes.string.isRequired,
   namespace: PropTypes.string.isRequired,
   PrefixedLink: PropTypes.func.isRequired,
   resource: PropTypes.string.isRequired,
 };
 
+GrafanaLink.defaultProps = {
+  grafanaUidPrefix: "",
+  grafanaUrl: "",
+};
+
 export default GrafanaLink;
@@ -26,4 +26,21 @@ describe('GrafanaLink', () => {
     expect(href).toContain(expectedNsStr);
     expect(href).toContain(expectedVarNameStr);
   });
+
+  it('links to the dashboard of an existing Grafana by its UID', () => {
+    let api = ApiHelpers('');
+    let linkProps = {
+      resource: "deployment",
+      name: "web",
+      namespace: "emojivoto",
+      grafanaUrl: "https://grafana.example.com",
+      grafanaUidPrefix: "linkerd-",
+      PrefixedLink: api.PrefixedLink
+    };
+    let component = mount(routerWrap(GrafanaLink, linkProps));
+
+    const href = component.find('a').props().href;
+
+    expect(href).toEqual("https://grafana.example.com/d/linkerd-deployment?var-namespace=emojivoto&var-deployment=web");
+  });
 });
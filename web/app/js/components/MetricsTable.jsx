@@ -15,7 +15,7 @@ import _isNil from 'lodash/isNil';
 import { processedMetricsPropType } from './util/MetricUtils.jsx';
 import { withContext } from './util/AppContext.jsx';
 
-const columnDefinitions = (resource, showNamespaceColumn, PrefixedLink) => {
+const columnDefinitions = (resource, showNamespaceColumn, PrefixedLink, grafanaUrl, grafanaUidPrefix) => {
   let isAuthorityTable = resource === "authority";
 
   let nsColumn = [
@@ -68,7 +68,7 @@ const columnDefinitions = (resource, showNamespaceColumn, PrefixedLink) => {
       title: "Success Rate",
       dataIndex: "successRate",
       isNumeric: true,
-      render: d => <SuccessRateMiniChart sr={d.successRate} />,
+      render: d => <SuccessRateMiniChart sr={d.successRate} slo={d.sloSuccessRate} />,
       sorter: (a, b) => numericSort(a.successRate, b.successRate)
     },
     {
@@ -108,6 +108,13 @@ const columnDefinitions = (resource, showNamespaceColumn, PrefixedLink) => {
         a.tlsRequestPercent ? a.tlsRequestPercent.get() : -1,
         b.tlsRequestPercent ? b.tlsRequestPercent.get() : -1)
     },
+    {
+      title: "Unmeshed RPS",
+      dataIndex: "unmeshedRequestRate",
+      isNumeric: true,
+      render: d => metricToFormatter["NO_UNIT"](d.unmeshedRequestRate),
+      sorter: (a, b) => numericSort(a.unmeshedRequestRate, b.unmeshedRequestRate)
+    },
     {
       title: "Grafana",
       key: "grafanaDashboard",
@@ -122,6 +129,8 @@ const columnDefinitions = (resource, showNamespaceColumn, PrefixedLink) => {
             name={row.name}
             namespace={row.namespace}
             resource={resource}
+            grafanaUrl={grafanaUrl}
+            grafanaUidPrefix={grafanaUidPrefix}
             PrefixedLink={PrefixedLink} />
         );
       }
@@ -158,22 +167,26 @@ class MetricsTable extends React.Component {
     api: PropTypes.shape({
       PrefixedLink: PropTypes.func.isRequired,
     }).isRequired,
+    grafanaUidPrefix: PropTypes.string,
+    grafanaUrl: PropTypes.string,
     metrics: PropTypes.arrayOf(processedMetricsPropType),
     resource: PropTypes.string.isRequired,
     showNamespaceColumn: PropTypes.bool
   };
 
   static defaultProps = {
+    grafanaUidPrefix: "",
+    grafanaUrl: "",
     showNamespaceColumn: true,
     metrics: []
   };
 
   render() {
-    const {  metrics, resource, showNamespaceColumn, api } = this.props;
+    const {  metrics, resource, showNamespaceColumn, api, grafanaUrl, grafanaUidPrefix } = this.props;
 
     let showNsColumn = resource === "namespace" ? false : showNamespaceColumn;
 
-    let columns = columnDefinitions(resource, showNsColumn, api.PrefixedLink);
+    let columns = columnDefinitions(resource, showNsColumn, api.PrefixedLink, grafanaUrl, grafanaUidPrefix);
     let rows = preprocessMetrics(metrics);
     return (
       <BaseTable
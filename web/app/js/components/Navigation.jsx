@@ -149,7 +149,13 @@ class NavigationBase extends React.Component {
   }
 
   fetchVersion() {
-    let versionUrl = `https://versioncheck.linkerd.io/version.json?version=${this.props.releaseVersion}&uuid=${this.props.uuid}&source=web`;
+    // version checks are disabled when the web server isn't configured with
+    // a version check URL
+    if (!this.props.versionCheckUrl) {
+      return;
+    }
+
+    let versionUrl = `${this.props.versionCheckUrl}?version=${this.props.releaseVersion}&uuid=${this.props.uuid}&source=web`;
     this.versionPromise = fetch(versionUrl, { credentials: 'include' })
       .then(rsp => rsp.json())
       .then(versionRsp => {
@@ -278,6 +284,7 @@ class NavigationBase extends React.Component {
               latestVersion={this.state.latestVersion}
               releaseVersion={this.props.releaseVersion}
               error={this.state.error}
+              versionCheckDisabled={!this.props.versionCheckUrl}
               uuid={this.props.uuid} />
           }
         </Drawer>
@@ -300,6 +307,11 @@ NavigationBase.propTypes = {
   releaseVersion: PropTypes.string.isRequired,
   theme: PropTypes.shape({}).isRequired,
   uuid: PropTypes.string.isRequired,
+  versionCheckUrl: PropTypes.string,
+};
+
+NavigationBase.defaultProps = {
+  versionCheckUrl: '',
 };
 
 export default withContext(withStyles(styles, { withTheme: true })(NavigationBase));
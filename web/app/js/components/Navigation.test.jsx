@@ -58,7 +58,8 @@ describe('Navigation', () => {
           api={apiHelpers}
           releaseVersion={curVer}
           pathPrefix=""
-          uuid="fakeuuid" />
+          uuid="fakeuuid"
+          versionCheckUrl="https://versioncheck.linkerd.io/version.json" />
       </BrowserRouter>
     );
 
@@ -85,7 +86,8 @@ describe('Navigation', () => {
           api={apiHelpers}
           releaseVersion={curVer}
           pathPrefix=""
-          uuid="fakeuuid" />
+          uuid="fakeuuid"
+          versionCheckUrl="https://versioncheck.linkerd.io/version.json" />
       </BrowserRouter>
     );
 
@@ -110,7 +112,8 @@ describe('Navigation', () => {
           api={apiHelpers}
           releaseVersion={curVer}
           pathPrefix=""
-          uuid="fakeuuid" />
+          uuid="fakeuuid"
+          versionCheckUrl="https://versioncheck.linkerd.io/version.json" />
       </BrowserRouter>
     );
 
@@ -140,7 +143,8 @@ describe('Navigation', () => {
           api={apiHelpers}
           releaseVersion={curVer}
           pathPrefix=""
-          uuid="fakeuuid" />
+          uuid="fakeuuid"
+          versionCheckUrl="https://versioncheck.linkerd.io/version.json" />
       </BrowserRouter>
     );
 
@@ -149,4 +153,25 @@ describe('Navigation', () => {
       expect(component).toIncludeText(errMsg);
     });
   });
+
+  it('does not check the version when version checks are disabled', () => {
+    component = mount(
+      <BrowserRouter>
+        <Navigation
+          ChildComponent={childComponent}
+          classes={{}}
+          theme={{}}
+          location={loc}
+          api={apiHelpers}
+          releaseVersion={curVer}
+          pathPrefix=""
+          uuid="fakeuuid"
+          versionCheckUrl="" />
+      </BrowserRouter>
+    );
+
+    expect(fetchStub.called).toBe(false);
+    expect(component).toIncludeText("1.2.3 (edge).");
+    expect(component).not.toIncludeText("Version check failed");
+  });
 });
@@ -1,7 +1,16 @@
 import { UrlQueryParamTypes, addUrlProps } from 'react-url-query';
-import { emptyTapQuery, processTapEvent, setMaxRps, wsCloseCodes } from './util/TapUtils.jsx';
-
+import {
+  emptyTapQuery,
+  processTapEvent,
+  setMaxRps,
+  tapResultsToHar,
+  tapResultsToJSON,
+  wsCloseCodes
+} from './util/TapUtils.jsx';
+
+import Button from '@material-ui/core/Button';
 import ErrorBanner from './ErrorBanner.jsx';
+import Grid from '@material-ui/core/Grid';
 import PropTypes from 'prop-types';
 import React from 'react';
 import TapEventTable from './TapEventTable.jsx';
@@ -26,11 +35,13 @@ class Tap extends React.Component {
       PrefixedLink: PropTypes.func.isRequired,
     }).isRequired,
     autostart: PropTypes.string,
-    pathPrefix: PropTypes.string.isRequired
+    pathPrefix: PropTypes.string.isRequired,
+    releaseVersion: PropTypes.string
   }
 
   static defaultProps = {
-    autostart: ""
+    autostart: "",
+    releaseVersion: ""
   }
 
   constructor(props) {
@@ -59,6 +70,7 @@ class Tap extends React.Component {
       maxLinesToDisplay: 40,
       tapRequestInProgress: false,
       tapIsClosing: false,
+      tapIsPaused: false,
       pollingInterval: 10000,
       pendingRequests: false
     };
@@ -136,7 +148,7 @@ class Tap extends React.Component {
         this.deleteOldestTapResult(resultIndex);
       }
 
-      resultIndex[d.id] = {};
+      resultIndex[d.id] = { startedAt: Date.now() };
     }
     resultIndex[d.id][d.eventType] = d;
     // assumption: requests of a given id all share the same high level metadata
@@ -146,6 +158,10 @@ class Tap extends React.Component {
   }
 
   updateTapResults = () => {
+    if (this.state.tapIsPaused) {
+      // keep displaying the snapshot taken when the tap was paused
+      return;
+    }
     this.setState({
       tapResultsById: this.tapResultsById
     });
@@ -180,6 +196,7 @@ class Tap extends React.Component {
 
     this.setState({
       tapRequestInProgress: true,
+      tapIsPaused: false,
       tapResultsById: this.tapResultsById
     });
 
@@ -200,7 +217,9 @@ class Tap extends React.Component {
 
     this.setState({
       tapRequestInProgress: false,
-      tapIsClosing: false
+      tapIsClosing: false,
+      tapIsPaused: false,
+      tapResultsById: this.tapResultsById
     });
   }
 
@@ -214,6 +233,37 @@ class Tap extends React.Component {
     this.setState({ tapIsClosing: true });
   }
 
+  // pausing freezes the displayed results so that they can be inspected,
+  // while the tap keeps collecting new results in the background
+  handleTapPause = () => {
+    this.setState({
+      tapIsPaused: true,
+      tapResultsById: _cloneDeep(this.tapResultsById)
+    });
+  }
+
+  handleTapResume = () => {
+    this.setState({
+      tapIsPaused: false,
+      tapResultsById: this.tapResultsById
+    });
+  }
+
+  handleExport = format => () => {
+    let results = _orderBy(_values(this.state.tapResultsById), r => r.startedAt, "asc");
+    let contents = format === "har" ?
+      tapResultsToHar(results, this.props.releaseVersion) :
+      tapResultsToJSON(results);
+
+    let link = document.createElement("a");
+    link.href = window.URL.createObjectURL(new Blob([contents], { type: "application/json" }));
+    link.download = `tap.${format}`;
+    document.body.appendChild(link);
+    link.click();
+    document.body.removeChild(link);
+    window.URL.revokeObjectURL(link.href);
+  }
+
   handleTapClear = () => {
     this.resetTapResults();
   }
@@ -266,6 +316,30 @@ class Tap extends React.Component {
     });
   }
 
+  renderTapControls = hasResults => {
+    return (
+      <Grid container spacing={8} justify="flex-end">
+        <Grid item>
+          {this.state.tapIsPaused ?
+            <Button className="tap-resume" onClick={this.handleTapResume}>Resume</Button> :
+            <Button
+              className="tap-pause"
+              disabled={!this.state.tapRequestInProgress}
+              onClick={this.handleTapPause}>
+              Pause
+            </Button>
+          }
+        </Grid>
+        <Grid item>
+          <Button className="tap-export-json" disabled={!hasResults} onClick={this.handleExport("json")}>Export JSON</Button>
+        </Grid>
+        <Grid item>
+          <Button className="tap-export-har" disabled={!hasResults} onClick={this.handleExport("har")}>Export HAR</Button>
+        </Grid>
+      </Grid>
+    );
+  }
+
   render() {
     let tableRows = _orderBy(_values(this.state.tapResultsById), r => r.lastUpdated, "desc");
 
@@ -286,6 +360,8 @@ class Tap extends React.Component {
           updateQuery={this.updateQuery}
           query={this.state.query} />
 
+        {this.renderTapControls(tableRows.length > 0)}
+
         <TapEventTable
           resource={this.state.query.resource}
           tableRows={tableRows} />
@@ -1,4 +1,4 @@
-import { directionColumn, srcDstColumn } from './util/TapUtils.jsx';
+import { directionColumn, srcDstColumn, tapPeerDetails } from './util/TapUtils.jsx';
 import { formatLatencySec, formatWithComma } from './util/Utils.js';
 
 import Card from '@material-ui/core/Card';
@@ -167,16 +167,47 @@ const responseEndSection = d => _isEmpty(d.responseEnd) ? null : (
     <List dense>
       {itemDisplay("GRPC Status", _isNull(_get(d, "responseEnd.http.responseEnd.eos")) ? "N/A" : grpcStatusCodes[_get(d, "responseEnd.http.responseEnd.eos.grpcStatusCode")])}
       {itemDisplay("Latency", formatTapLatency(_get(d, "responseEnd.http.responseEnd.sinceResponseInit")))}
+      {itemDisplay("Total Latency", formatTapLatency(_get(d, "responseEnd.http.responseEnd.sinceRequestInit")))}
       {itemDisplay("Response Length (B)", formatWithComma(_get(d, "responseEnd.http.responseEnd.responseBytes")))}
     </List>
   </React.Fragment>
 );
 
+const peerSection = (d, peer) => {
+  let details = tapPeerDetails(d.base, peer);
+  return (
+    <React.Fragment>
+      <Typography variant="subtitle2">{peer === "source" ? "Source" : "Destination"}</Typography>
+      <br />
+      <List dense>
+        {itemDisplay("Address", details.address)}
+        {itemDisplay("Pod", details.pod || "---")}
+        {itemDisplay("Owner", details.owner || "---")}
+        {itemDisplay("Namespace", details.namespace || "---")}
+        {itemDisplay("TLS", details.tls || "---")}
+      </List>
+    </React.Fragment>
+  );
+};
 
 // hide verbose information
 const expandedRowRender = d => {
+  if (_isEmpty(d)) {
+    return null;
+  }
+
   return (
     <Grid container spacing={16} className="tap-more-info">
+      <Grid item xs={6}>
+        <Card>
+          <CardContent>{peerSection(d, "source")}</CardContent>
+        </Card>
+      </Grid>
+      <Grid item xs={6}>
+        <Card>
+          <CardContent>{peerSection(d, "destination")}</CardContent>
+        </Card>
+      </Grid>
       <Grid item xs={4}>
         <Card>
           <CardContent>{requestInitSection(d)}</CardContent>
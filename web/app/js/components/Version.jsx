@@ -22,7 +22,8 @@ class Version extends React.Component {
   static defaultProps = {
     error: null,
     latestVersion: '',
-    productName: 'controller'
+    productName: 'controller',
+    versionCheckDisabled: false
   }
 
   static propTypes = {
@@ -32,6 +33,7 @@ class Version extends React.Component {
     latestVersion: PropTypes.string,
     productName: PropTypes.string,
     releaseVersion: PropTypes.string.isRequired,
+    versionCheckDisabled: PropTypes.bool,
   }
 
   numericVersion = version => {
@@ -51,7 +53,11 @@ class Version extends React.Component {
   }
 
   renderVersionCheck = () => {
-    const {classes, latestVersion, error, isLatest} = this.props;
+    const {classes, latestVersion, error, isLatest, versionCheckDisabled} = this.props;
+
+    if (versionCheckDisabled) {
+      return null;
+    }
 
     if (!latestVersion) {
       return (
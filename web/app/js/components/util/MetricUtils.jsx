@@ -25,6 +25,17 @@ export const getSuccessRateClassification = (rate, successRateLabels = srArcClas
   }
 };
 
+// getSloClassification classifies the success rate of a resource that has a
+// target success rate, set with its linkerd.io/slo-success-rate annotation,
+// as poor if it's below the target, and good otherwise.
+export const getSloClassification = (rate, sloSuccessRate, successRateLabels = srArcClassLabels) => {
+  if (_isNull(rate)) {
+    return successRateLabels.default;
+  }
+
+  return rate < sloSuccessRate ? successRateLabels.poor : successRateLabels.good;
+};
+
 const srArcClassLabels = {
   good: "good",
   warning: "warning",
@@ -32,6 +43,12 @@ const srArcClassLabels = {
   default: "default"
 };
 
+// the SLO success rate is 0 for the resources that don't have one
+const getSloSuccessRate = row => {
+  let slo = _get(row, "sloSuccessRate", 0);
+  return slo > 0 ? slo : null;
+};
+
 const getTotalRequests = row => {
   let success = parseInt(_get(row, ["stats", "successCount"], 0), 10);
   let failure = parseInt(_get(row, ["stats", "failureCount"], 0), 10);
@@ -39,22 +56,42 @@ const getTotalRequests = row => {
   return success + failure;
 };
 
+const getTimeWindowSeconds = timeWindow => {
+  let seconds = 0;
+
+  if (timeWindow === "10s") { seconds = 10; }
+  if (timeWindow === "1m") { seconds = 60; }
+  if (timeWindow === "10m") { seconds = 600; }
+  if (timeWindow === "1h") { seconds = 3600; }
+
+  return seconds;
+};
+
 const getRequestRate = row => {
   if (_isEmpty(row.stats)) {
     return null;
   }
 
-  let seconds = 0;
+  let seconds = getTimeWindowSeconds(row.timeWindow);
+  if (seconds === 0) {
+    return null;
+  } else {
+    return getTotalRequests(row) / seconds;
+  }
+};
 
-  if (row.timeWindow === "10s") { seconds = 10; }
-  if (row.timeWindow === "1m") { seconds = 60; }
-  if (row.timeWindow === "10m") { seconds = 600; }
-  if (row.timeWindow === "1h") { seconds = 3600; }
+// the rate of inbound requests from clients without an identity, i.e. that
+// aren't meshed; these are only counted when TLS is enabled
+const getUnmeshedRequestRate = row => {
+  if (_isEmpty(row.stats)) {
+    return null;
+  }
 
+  let seconds = getTimeWindowSeconds(row.timeWindow);
   if (seconds === 0) {
     return null;
   } else {
-    return getTotalRequests(row) / seconds;
+    return parseInt(_get(row, ["stats", "unmeshedRequestCount"], 0), 10) / seconds;
   }
 };
 
@@ -105,8 +142,10 @@ const processStatTable = table => {
       totalRequests: getTotalRequests(row),
       requestRate: getRequestRate(row),
       successRate: getSuccessRate(row),
+      sloSuccessRate: getSloSuccessRate(row),
       latency: getLatency(row),
       tlsRequestPercent: getTlsRequestPercentage(row),
+      unmeshedRequestRate: getUnmeshedRequestRate(row),
       added: runningPodCount > 0 && meshedPodCount > 0,
       pods: {
         totalPods: row.runningPodCount,
@@ -216,6 +255,7 @@ export const emptyMetric = {
   successRate: null,
   latency: null,
   tlsRequestPercent: null,
+  unmeshedRequestRate: null,
   added: false,
   pods: {
     totalPods: null,
@@ -244,6 +284,7 @@ export const metricsPropType = PropTypes.shape({
             latencyMsP99: PropTypes.string,
             tlsRequestCount: PropTypes.string,
             successCount: PropTypes.string,
+            unmeshedRequestCount: PropTypes.string,
           }),
           timeWindow: PropTypes.string,
         }).isRequired),
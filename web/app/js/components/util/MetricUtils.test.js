@@ -3,6 +3,7 @@ import multiDeployRollupFixtures from '../../../test/fixtures/multiDeployRollup.
 import multiResourceRollupFixtures from '../../../test/fixtures/allRollup.json';
 import Percentage from './Percentage';
 import {
+  getSloClassification,
   processMultiResourceRollup,
   processSingleResourceRollup
 } from './MetricUtils.jsx';
@@ -19,8 +20,10 @@ describe('MetricUtils', () => {
           key: "emojivoto-deployment-voting",
           requestRate: 2.5,
           successRate: 0.9,
+          sloSuccessRate: null,
           totalRequests: 150,
           tlsRequestPercent: new Percentage(100, 150),
+          unmeshedRequestRate: 0.5,
           latency: {
             P50: 1,
             P95: 2,
@@ -50,6 +53,22 @@ describe('MetricUtils', () => {
     });
   });
 
+  describe('getSloClassification', () => {
+    it('Classifies success rates against the SLO', () => {
+      expect(getSloClassification(0.998, 0.999)).toEqual("poor");
+      expect(getSloClassification(0.999, 0.999)).toEqual("good");
+      expect(getSloClassification(1, 0.9)).toEqual("good");
+      expect(getSloClassification(null, 0.999)).toEqual("default");
+    });
+
+    it('Extracts the SLO success rate of the resources', () => {
+      let fixtures = JSON.parse(JSON.stringify(deployRollupFixtures));
+      fixtures.ok.statTables[0].podGroup.rows[0].sloSuccessRate = 0.95;
+      let result = processSingleResourceRollup(fixtures);
+      expect(result[0].sloSuccessRate).toEqual(0.95);
+    });
+  });
+
   describe('processMultiResourceRollup', () => {
     it('Extracts metrics and groups them by resource type', () => {
       let result = processMultiResourceRollup(multiResourceRollupFixtures);
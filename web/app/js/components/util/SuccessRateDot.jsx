@@ -1,8 +1,9 @@
 import PropTypes from 'prop-types';
 import React from 'react';
+import _isNil from 'lodash/isNil';
 import _merge from 'lodash/merge';
 import classNames from 'classnames';
-import { getSuccessRateClassification } from './MetricUtils.jsx';
+import { getSloClassification, getSuccessRateClassification } from './MetricUtils.jsx';
 import { statusClassNames } from './theme.js';
 import { withStyles } from '@material-ui/core/styles';
 
@@ -17,20 +18,23 @@ const styles = theme => _merge({}, statusClassNames(theme), {
 
 class SuccessRateDot extends React.Component {
   render() {
-    const { sr, classes } = this.props;
+    const { sr, slo, classes } = this.props;
+    const classification = _isNil(slo) ? getSuccessRateClassification(sr) : getSloClassification(sr, slo);
 
     return (
-      <div className={classNames(classes.successRateDot, classes[getSuccessRateClassification(sr)])} />
+      <div className={classNames(classes.successRateDot, classes[classification])} />
     );
   }
 }
 
 SuccessRateDot.propTypes = {
   classes: PropTypes.shape({}).isRequired,
+  slo: PropTypes.number,
   sr: PropTypes.number,
 };
 
 SuccessRateDot.defaultProps = {
+  slo: null,
   sr: null
 };
 
@@ -2,30 +2,36 @@ import Grid from '@material-ui/core/Grid';
 import PropTypes from 'prop-types';
 import React from 'react';
 import SuccessRateDot from "./SuccessRateDot.jsx";
+import Tooltip from '@material-ui/core/Tooltip';
 import _isNil from 'lodash/isNil';
 import { metricToFormatter } from './Utils.js';
 
 class SuccessRateMiniChart extends React.Component {
   render() {
-    const { sr } = this.props;
+    const { sr, slo } = this.props;
+
+    let dot = _isNil(sr) ? null : <SuccessRateDot sr={sr} slo={slo} />;
+    if (!_isNil(dot) && !_isNil(slo)) {
+      let title = `${sr < slo ? "Below" : "Meets"} the SLO of ${metricToFormatter["SUCCESS_RATE"](slo)}`;
+      dot = <Tooltip placement="top" title={title}><div>{dot}</div></Tooltip>;
+    }
 
     return (
       <Grid container justify="flex-end" alignItems="center" spacing={8}>
         <Grid item>{metricToFormatter["SUCCESS_RATE"](sr)}</Grid>
-        <Grid item>{_isNil(sr) ? null :
-        <SuccessRateDot sr={sr} />
-        }
-        </Grid>
+        <Grid item>{dot}</Grid>
       </Grid>
     );
   }
 }
 
 SuccessRateMiniChart.propTypes = {
+  slo: PropTypes.number,
   sr: PropTypes.number,
 };
 
 SuccessRateMiniChart.defaultProps = {
+  slo: null,
   sr: null
 };
 
@@ -16,8 +16,10 @@ import _isEmpty from 'lodash/isEmpty';
 import _isNil from 'lodash/isNil';
 import _map from 'lodash/map';
 import _merge from 'lodash/merge';
+import _pick from 'lodash/pick';
 import _size from 'lodash/size';
 import _take from 'lodash/take';
+import _toLower from 'lodash/toLower';
 
 export const httpMethods = ["GET", "HEAD", "POST", "PUT", "DELETE", "CONNECT", "OPTIONS", "TRACE", "PATCH"];
 
@@ -383,3 +385,89 @@ export const tapLink = (d, resourceType, PrefixedLink) => {
       PrefixedLink={PrefixedLink} />
   );
 };
+
+/*
+  tap durations are JSON-encoded protobuf durations, such as "0.001234s"
+*/
+const durationToMs = duration => {
+  return _isNil(duration) ? -1 : parseFloat(duration.replace("s", "")) * 1000;
+};
+
+/*
+  the details of the source or destination of a processed tap event
+*/
+export const tapPeerDetails = (d, peer) => ({
+  address: `${_get(d, [peer, "str"])}:${_get(d, [peer, "port"])}`,
+  pod: _get(d, [peer, "pod"]),
+  owner: _get(d, [peer, "owner"]),
+  namespace: _get(d, [peer, "namespace"]),
+  tls: _get(d, [`${peer}Meta`, "labels", "tls"], "")
+});
+
+/*
+  Export the raw events of each tap result, as received from the tap websocket
+*/
+export const tapResultsToJSON = tapResults => {
+  return JSON.stringify(_map(tapResults, r => _pick(r, ["requestInit", "responseInit", "responseEnd"])), null, 2);
+};
+
+/*
+  Convert tap results to an HTTP Archive (HAR 1.2), so that they can be
+  inspected with other HTTP tools. Tap events don't include the headers and
+  bodies of requests, so those are left empty, and the proxy-specific fields
+  are kept in the custom _linkerd field of each entry.
+*/
+export const tapResultsToHar = (tapResults, version) => {
+  let entries = _map(tapResults, r => {
+    let req = _get(r, "requestInit.http.requestInit", {});
+    let rspInit = _get(r, "responseInit.http.responseInit", {});
+    let rspEnd = _get(r, "responseEnd.http.responseEnd", {});
+    let wait = durationToMs(rspInit.sinceRequestInit);
+    let receive = durationToMs(rspEnd.sinceResponseInit);
+    let scheme = _toLower(_get(req, "scheme.registered", "http"));
+    let responseBytes = _isNil(rspEnd.responseBytes) ? -1 : parseInt(rspEnd.responseBytes, 10);
+
+    return {
+      startedDateTime: new Date(r.startedAt).toISOString(),
+      time: Math.max(wait, 0) + Math.max(receive, 0),
+      request: {
+        method: _get(req, "method.registered", ""),
+        url: `${scheme}://${req.authority}${req.path}`,
+        httpVersion: "",
+        cookies: [],
+        headers: [],
+        queryString: [],
+        headersSize: -1,
+        bodySize: -1
+      },
+      response: {
+        status: rspInit.httpStatus || 0,
+        statusText: "",
+        httpVersion: "",
+        cookies: [],
+        headers: [],
+        content: { size: Math.max(responseBytes, 0), mimeType: "" },
+        redirectURL: "",
+        headersSize: -1,
+        bodySize: responseBytes
+      },
+      cache: {},
+      timings: { send: 0, wait, receive },
+      serverIPAddress: _get(r, "base.destination.str", ""),
+      _linkerd: {
+        direction: _get(r, "base.proxyDirection"),
+        source: tapPeerDetails(r.base, "source"),
+        destination: tapPeerDetails(r.base, "destination"),
+        grpcStatusCode: _get(rspEnd, "eos.grpcStatusCode", null)
+      }
+    };
+  });
+
+  return JSON.stringify({
+    log: {
+      version: "1.2",
+      creator: { name: "linkerd", version },
+      entries
+    }
+  }, null, 2);
+};
@@ -0,0 +1,85 @@
+import { tapResultsToHar, tapResultsToJSON } from './TapUtils.jsx';
+
+const tapResult = {
+  key: "10.1.1.1,10.1.1.2,1",
+  startedAt: Date.UTC(2019, 0, 2, 3, 4, 5),
+  base: {
+    proxyDirection: "OUTBOUND",
+    source: { str: "10.1.1.1", port: 43210, pod: "web-1", owner: "deployment/web", namespace: "emojivoto" },
+    destination: { str: "10.1.1.2", port: 8080, pod: "voting-1", owner: "deployment/voting", namespace: "emojivoto" },
+    sourceMeta: { labels: {} },
+    destinationMeta: { labels: { tls: "true" } }
+  },
+  requestInit: {
+    http: {
+      requestInit: {
+        method: { registered: "POST" },
+        scheme: { registered: "HTTP" },
+        authority: "voting-svc.emojivoto:8080",
+        path: "/emojivoto.v1.VotingService/VoteDoughnut"
+      }
+    }
+  },
+  responseInit: {
+    http: { responseInit: { httpStatus: 200, sinceRequestInit: "0.002s" } }
+  },
+  responseEnd: {
+    http: {
+      responseEnd: {
+        sinceRequestInit: "0.003s",
+        sinceResponseInit: "0.001s",
+        responseBytes: "5",
+        eos: { grpcStatusCode: 0 }
+      }
+    }
+  }
+};
+
+describe('TapUtils', () => {
+  describe('tapResultsToJSON', () => {
+    it('exports the raw events of each result', () => {
+      let exported = JSON.parse(tapResultsToJSON([tapResult]));
+      expect(exported).toEqual([{
+        requestInit: tapResult.requestInit,
+        responseInit: tapResult.responseInit,
+        responseEnd: tapResult.responseEnd
+      }]);
+    });
+  });
+
+  describe('tapResultsToHar', () => {
+    it('converts results to HAR entries', () => {
+      let har = JSON.parse(tapResultsToHar([tapResult], "edge-19.1.1"));
+      expect(har.log.version).toEqual("1.2");
+      expect(har.log.creator).toEqual({ name: "linkerd", version: "edge-19.1.1" });
+      expect(har.log.entries).toHaveLength(1);
+
+      let entry = har.log.entries[0];
+      expect(entry.startedDateTime).toEqual("2019-01-02T03:04:05.000Z");
+      expect(entry.time).toEqual(3);
+      expect(entry.request.method).toEqual("POST");
+      expect(entry.request.url).toEqual("http://voting-svc.emojivoto:8080/emojivoto.v1.VotingService/VoteDoughnut");
+      expect(entry.response.status).toEqual(200);
+      expect(entry.response.bodySize).toEqual(5);
+      expect(entry.timings).toEqual({ send: 0, wait: 2, receive: 1 });
+      expect(entry.serverIPAddress).toEqual("10.1.1.2");
+      expect(entry._linkerd.destination).toEqual({
+        address: "10.1.1.2:8080",
+        pod: "voting-1",
+        owner: "deployment/voting",
+        namespace: "emojivoto",
+        tls: "true"
+      });
+      expect(entry._linkerd.grpcStatusCode).toEqual(0);
+    });
+
+    it('leaves the response empty for requests without responses', () => {
+      let result = { ...tapResult, responseInit: undefined, responseEnd: undefined };
+      let entry = JSON.parse(tapResultsToHar([result], "")).log.entries[0];
+      expect(entry.response.status).toEqual(0);
+      expect(entry.response.bodySize).toEqual(-1);
+      expect(entry.timings).toEqual({ send: 0, wait: -1, receive: -1 });
+      expect(entry.time).toEqual(0);
+    });
+  });
+});
@@ -2,16 +2,26 @@ package main
 
 import (
 	"context"
+	cryptoTls "crypto/tls"
 	"flag"
+	"fmt"
+	"io/ioutil"
 	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"regexp"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/admin"
 	"github.com/linkerd/linkerd2/pkg/flags"
+	"github.com/linkerd/linkerd2/pkg/tls"
+	"github.com/linkerd/linkerd2/pkg/version"
 	"github.com/linkerd/linkerd2/web/srv"
 	log "github.com/sirupsen/logrus"
 )
@@ -20,20 +30,66 @@ func main() {
 	addr := flag.String("addr", ":8084", "address to serve on")
 	metricsAddr := flag.String("metrics-addr", ":9994", "address to serve scrapable metrics on")
 	apiAddr := flag.String("api-addr", "127.0.0.1:8085", "address of the linkerd-controller-api service")
+	apiTLSCertFile := flag.String("api-tls-cert-file", "", "path to the PEM client certificate to connect to the public API over mutual TLS with, along with -api-tls-key-file, -api-tls-trust-anchors-file and -api-tls-server-identity; empty to connect over plaintext")
+	apiTLSKeyFile := flag.String("api-tls-key-file", "", "path to the PEM private key of -api-tls-cert-file")
+	apiTLSTrustAnchorsFile := flag.String("api-tls-trust-anchors-file", "", "path to the PEM trust anchors that the certificate of the public API must be issued by")
+	apiTLSServerIdentity := flag.String("api-tls-server-identity", "", "DNS name that the certificate of the public API must be issued for")
 	grafanaAddr := flag.String("grafana-addr", "127.0.0.1:3000", "address of the linkerd-grafana service")
+	grafanaURL := flag.String("grafana-url", "", "base URL of an existing Grafana that the dashboard links to, instead of proxying linkerd-grafana under /grafana")
+	grafanaDashboardUIDPrefix := flag.String("grafana-dashboard-uid-prefix", "linkerd-", "prefix of the UIDs of the Linkerd dashboards in the Grafana of -grafana-url, which are followed by the resource type, e.g. \"deployment\"")
 	templateDir := flag.String("template-dir", "templates", "directory to search for template files")
 	staticDir := flag.String("static-dir", "app/dist", "directory to search for static files")
 	uuid := flag.String("uuid", "", "unique linkerd install id")
+	versionCheckURL := flag.String("version-check-url", version.CheckURL, "endpoint queried by the dashboard for the latest Linkerd version; empty to disable version checks")
 	reload := flag.Bool("reload", true, "reloading set to true or false")
 	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
 	singleNamespace := flag.Bool("single-namespace", false, "only operate in the controller namespace")
+	enforcedHost := flag.String("enforced-host", "", "regexp of the hosts that the dashboard can be reached with, to protect it against DNS rebinding; empty to only allow localhost and the linkerd-web service of the controller namespace")
+	tlsCertFile := flag.String("tls-cert-file", "", "file with the TLS certificate to serve HTTPS with, along with -tls-key-file; empty to serve HTTP")
+	tlsKeyFile := flag.String("tls-key-file", "", "file with the private key of -tls-cert-file")
+	oidcIssuerURL := flag.String("oidc-issuer-url", "", "URL of an OpenID Connect provider that the users must log in with; empty to disable the login")
+	oidcClientID := flag.String("oidc-client-id", "", "client ID of the dashboard at the OpenID Connect provider")
+	oidcClientSecretFile := flag.String("oidc-client-secret-file", "", "file with the client secret of the dashboard at the OpenID Connect provider")
+	oidcRedirectURL := flag.String("oidc-redirect-url", "", "URL of the dashboard that the OpenID Connect provider redirects the users to once they're logged in, e.g. https://dashboard.example.com/oidc/callback")
+	oidcGroupsClaim := flag.String("oidc-groups-claim", "groups", "claim of the ID tokens that lists the groups of the users")
+	oidcAllowedGroups := flag.String("oidc-allowed-groups", "", "comma-separated groups whose members can log in; empty to allow all the users of the OpenID Connect provider")
+	websocketConfig := srv.DefaultWebsocketConfig()
+	flag.IntVar(&websocketConfig.MaxConnections, "tap-max-connections", websocketConfig.MaxConnections, "maximum number of open websockets of the tap and top views, past which the browsers are told to try again later; 0 for no limit")
+	flag.IntVar(&websocketConfig.SendBuffer, "tap-send-buffer", websocketConfig.SendBuffer, "number of tap events buffered for each websocket of the tap and top views")
+	flag.StringVar(&websocketConfig.SlowClientPolicy, "tap-slow-client-policy", websocketConfig.SlowClientPolicy, "what's done with the websockets whose buffer is full because their browser is too slow: drop (their oldest tap events) or close")
+	flag.DurationVar(&websocketConfig.PingInterval, "tap-ping-interval", websocketConfig.PingInterval, "interval of the pings of the websockets of the tap and top views; the ones whose browser doesn't answer within two intervals are closed")
 	flags.ConfigureAndParse()
 
 	_, _, err := net.SplitHostPort(*apiAddr) // Verify apiAddr is of the form host:port.
 	if err != nil {
 		log.Fatalf("failed to parse API server address: %s", *apiAddr)
 	}
-	client, err := public.NewInternalClient(*controllerNamespace, *apiAddr)
+	if *grafanaURL != "" {
+		u, err := url.Parse(*grafanaURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			log.Fatalf("invalid Grafana URL %s: must be an absolute http or https URL", *grafanaURL)
+		}
+	}
+	if *enforcedHost == "" {
+		*enforcedHost = fmt.Sprintf(`^(localhost|127\.0\.0\.1|\[::1\]|linkerd-web\.%s\.svc(\.cluster\.local)?)(:\d+)?$`, regexp.QuoteMeta(*controllerNamespace))
+	}
+	if (*tlsCertFile == "") != (*tlsKeyFile == "") {
+		log.Fatal("-tls-cert-file and -tls-key-file must be set together")
+	}
+	var client pb.ApiClient
+	if *apiTLSCertFile != "" {
+		if *apiTLSKeyFile == "" || *apiTLSTrustAnchorsFile == "" || *apiTLSServerIdentity == "" {
+			log.Fatal("-api-tls-cert-file requires -api-tls-key-file, -api-tls-trust-anchors-file and -api-tls-server-identity")
+		}
+		var tlsConfig *cryptoTls.Config
+		tlsConfig, err = tls.NewMutualTLSClientFileConfig(*apiTLSCertFile, *apiTLSKeyFile, *apiTLSTrustAnchorsFile, *apiTLSServerIdentity)
+		if err != nil {
+			log.Fatalf("failed to configure the TLS client of the public API: %s", err)
+		}
+		client, err = public.NewMutualTLSClient(*controllerNamespace, *apiAddr, tlsConfig)
+	} else {
+		client, err = public.NewInternalClient(*controllerNamespace, *apiAddr)
+	}
 	if err != nil {
 		log.Fatalf("failed to construct client for API server URL %s", *apiAddr)
 	}
@@ -41,11 +97,41 @@ func main() {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
-	server := srv.NewServer(*addr, *grafanaAddr, *templateDir, *staticDir, *uuid, *controllerNamespace, *singleNamespace, *reload, client)
+	var oidcConfig *srv.OIDCConfig
+	if *oidcIssuerURL != "" {
+		secret, err := ioutil.ReadFile(*oidcClientSecretFile)
+		if err != nil {
+			log.Fatalf("failed to read the OIDC client secret: %s", err)
+		}
+		oidcConfig = &srv.OIDCConfig{
+			IssuerURL:    *oidcIssuerURL,
+			ClientID:     *oidcClientID,
+			ClientSecret: strings.TrimSpace(string(secret)),
+			RedirectURL:  *oidcRedirectURL,
+			GroupsClaim:  *oidcGroupsClaim,
+		}
+		if *oidcAllowedGroups != "" {
+			oidcConfig.AllowedGroups = strings.Split(*oidcAllowedGroups, ",")
+		}
+	}
+
+	server, err := srv.NewServer(*addr, *grafanaAddr, *grafanaURL, *grafanaDashboardUIDPrefix, *templateDir, *staticDir, *uuid, *versionCheckURL, *controllerNamespace, *singleNamespace, *reload, *enforcedHost, oidcConfig, websocketConfig, client)
+	if err != nil {
+		log.Fatalf("failed to configure the web server: %s", err)
+	}
 
 	go func() {
-		log.Infof("starting HTTP server on %+v", *addr)
-		server.ListenAndServe()
+		var err error
+		if *tlsCertFile != "" {
+			log.Infof("starting HTTPS server on %+v", *addr)
+			err = server.ListenAndServeTLS(*tlsCertFile, *tlsKeyFile)
+		} else {
+			log.Infof("starting HTTP server on %+v", *addr)
+			err = server.ListenAndServe()
+		}
+		if err != http.ErrServerClosed {
+			log.Fatalf("failed to serve: %s", err)
+		}
 	}()
 
 	go admin.StartServer(*metricsAddr)
@@ -2,7 +2,9 @@ package srv
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"time"
@@ -168,10 +170,20 @@ func (h *handler) handleAPITopRoutes(w http.ResponseWriter, req *http.Request, p
 func websocketError(ws *websocket.Conn, wsError int, msg string) {
 	ws.WriteControl(websocket.CloseMessage,
 		websocket.FormatCloseMessage(wsError, msg),
-		time.Time{})
+		time.Now().Add(websocketWriteTimeout))
 }
 
+// handleAPITap streams the tap events of the request sent on the websocket
+// to it, until the browser closes it. The events are written from the buffer
+// of the websocket, so that a slow browser doesn't hold up the tap stream.
 func (h *handler) handleAPITap(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	if !h.websockets.acquire() {
+		w.Header().Set("Retry-After", "10")
+		renderJSONError(w, fmt.Errorf("too many open tap websockets, the dashboard serves at most %d", h.websocketConfig.MaxConnections), http.StatusServiceUnavailable)
+		return
+	}
+	defer h.websockets.release()
+
 	ws, err := websocketUpgrader.Upgrade(w, req, nil)
 	if err != nil {
 		renderJSONError(w, err, http.StatusInternalServerError)
@@ -179,6 +191,9 @@ func (h *handler) handleAPITap(w http.ResponseWriter, req *http.Request, p httpr
 	}
 	defer ws.Close()
 
+	stream := newWebsocketStream(ws, h.websocketConfig)
+	defer stream.close()
+
 	messageType, message, err := ws.ReadMessage()
 	if err != nil {
 		websocketError(ws, websocket.CloseInternalServerErr, err.Error())
@@ -203,10 +218,15 @@ func (h *handler) handleAPITap(w http.ResponseWriter, req *http.Request, p httpr
 		return
 	}
 
+	// the tap stream is cancelled once the websocket is closed
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	go stream.writeLoop()
 	go func() {
-		tapClient, err := h.apiClient.TapByResource(req.Context(), tapReq)
+		tapClient, err := h.apiClient.TapByResource(ctx, tapReq)
 		if err != nil {
-			websocketError(ws, websocket.CloseInternalServerErr, err.Error())
+			stream.closeWithError(websocket.CloseInternalServerErr, err.Error())
 			return
 		}
 		defer tapClient.CloseSend()
@@ -217,28 +237,31 @@ func (h *handler) handleAPITap(w http.ResponseWriter, req *http.Request, p httpr
 				break
 			}
 			if err != nil {
-				websocketError(ws, websocket.CloseInternalServerErr, err.Error())
+				stream.closeWithError(websocket.CloseInternalServerErr, err.Error())
 				break
 			}
 
 			buf := new(bytes.Buffer)
 			err = pbMarshaler.Marshal(buf, rsp)
 			if err != nil {
-				websocketError(ws, websocket.CloseInternalServerErr, err.Error())
+				stream.closeWithError(websocket.CloseInternalServerErr, err.Error())
 				break
 			}
 
-			if err := ws.WriteMessage(websocket.TextMessage, []byte(buf.String())); err != nil {
-				if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure) {
-					log.Error(err)
-				}
+			if !stream.enqueue(buf.Bytes()) {
 				break
 			}
 		}
 	}()
 
+	defer func() {
+		if dropped := stream.droppedCount(); dropped > 0 {
+			log.Warnf("dropped %d tap events of the slow websocket client %s", dropped, req.RemoteAddr)
+		}
+	}()
 	for {
 		_, _, err := ws.ReadMessage()
+		stream.extendReadDeadline()
 		if err != nil {
 			log.Debugf("Received close frame: %v", err)
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure) {
@@ -21,9 +21,14 @@ type (
 		render              renderTemplate
 		apiClient           pb.ApiClient
 		uuid                string
+		versionCheckURL     string
 		controllerNamespace string
 		singleNamespace     bool
+		grafanaURL          string
+		grafanaUIDPrefix    string
 		grafanaProxy        *grafanaProxy
+		websocketConfig     WebsocketConfig
+		websockets          *websocketLimiter
 	}
 )
 
@@ -36,8 +41,11 @@ func (h *handler) handleIndex(w http.ResponseWriter, req *http.Request, p httpro
 
 	params := appParams{
 		UUID:                h.uuid,
+		VersionCheckURL:     h.versionCheckURL,
 		ControllerNamespace: h.controllerNamespace,
 		SingleNamespace:     h.singleNamespace,
+		GrafanaURL:          h.grafanaURL,
+		GrafanaUIDPrefix:    h.grafanaUIDPrefix,
 		PathPrefix:          pathPfx,
 	}
 
@@ -27,8 +27,11 @@ func TestHandleIndex(t *testing.T) {
 	server := FakeServer()
 
 	handler := &handler{
-		render:    server.RenderTemplate,
-		apiClient: mockAPIClient,
+		render:           server.RenderTemplate,
+		apiClient:        mockAPIClient,
+		versionCheckURL:  "https://versioncheck.example.com/version.json",
+		grafanaURL:       "https://grafana.example.com",
+		grafanaUIDPrefix: "linkerd-",
 	}
 
 	recorder := httptest.NewRecorder()
@@ -56,6 +59,9 @@ func TestHandleIndex(t *testing.T) {
 		"data-go-version=\"the best one\"",
 		"data-controller-namespace=\"\"",
 		"data-uuid=\"\"",
+		"data-version-check-url=\"https://versioncheck.example.com/version.json\"",
+		"data-grafana-url=\"https://grafana.example.com\"",
+		"data-grafana-uid-prefix=\"linkerd-\"",
 	}
 	for _, expectedSubstring := range expectedSubstrings {
 		if !strings.Contains(actualBody, expectedSubstring) {
@@ -0,0 +1,505 @@
+package srv
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	oidcSessionCookie = "linkerd-dashboard-session"
+	oidcStateCookie   = "linkerd-dashboard-oidc-state"
+
+	// oidcLoginTimeout is how long the users have to log in with the provider
+	oidcLoginTimeout = 10 * time.Minute
+)
+
+// OIDCConfig configures the login of the dashboard users with an OpenID
+// Connect provider.
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+
+	// RedirectURL is the URL of the dashboard that the provider redirects the
+	// users to once they're logged in; its path is handled by the dashboard
+	RedirectURL string
+
+	// GroupsClaim is the claim of the ID tokens that lists the groups of the
+	// users; when AllowedGroups is set, only their members can log in
+	GroupsClaim   string
+	AllowedGroups []string
+}
+
+// oidcProvider is the part of the discovery document of an OpenID Connect
+// provider that the login uses.
+type oidcProvider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcSession struct {
+	Subject string `json:"sub"`
+	Expiry  int64  `json:"exp"`
+}
+
+type oidcState struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	ReturnTo string `json:"returnTo"`
+	Expiry   int64  `json:"exp"`
+}
+
+// oidcAuth is an HTTP middleware that only lets the users with a session pass
+// through to the dashboard. The users without one are redirected to the
+// provider to log in, with the authorization code flow, and get a session
+// once their ID token is verified. The sessions are kept in cookies signed
+// with a key derived from the client secret, so that they're valid across the
+// replicas of the web server.
+type oidcAuth struct {
+	config       OIDCConfig
+	next         http.Handler
+	client       *http.Client
+	key          []byte
+	callbackPath string
+	secure       bool
+
+	// these fields are fetched from the provider on first use
+	mu       sync.Mutex
+	provider *oidcProvider
+	keys     map[string]crypto.PublicKey
+}
+
+func newOIDCAuth(config OIDCConfig, next http.Handler) (*oidcAuth, error) {
+	redirectURL, err := url.Parse(config.RedirectURL)
+	if err != nil || redirectURL.Host == "" || redirectURL.Path == "" {
+		return nil, fmt.Errorf("invalid OIDC redirect URL %s: must be an absolute URL with a path", config.RedirectURL)
+	}
+	if config.IssuerURL == "" || config.ClientID == "" || config.ClientSecret == "" {
+		return nil, errors.New("the OIDC issuer URL, client ID and client secret are required")
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+
+	key := hmac.New(sha256.New, []byte(config.ClientSecret))
+	key.Write([]byte("linkerd-dashboard-session"))
+
+	return &oidcAuth{
+		config:       config,
+		next:         next,
+		client:       &http.Client{Timeout: timeout},
+		key:          key.Sum(nil),
+		callbackPath: redirectURL.Path,
+		secure:       redirectURL.Scheme == "https",
+	}, nil
+}
+
+func (a *oidcAuth) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == a.callbackPath {
+		a.handleCallback(w, req)
+		return
+	}
+
+	if cookie, err := req.Cookie(oidcSessionCookie); err == nil {
+		var session oidcSession
+		if err := a.verifyCookie(cookie.Value, &session); err == nil && time.Now().Unix() < session.Expiry {
+			a.next.ServeHTTP(w, req)
+			return
+		}
+	}
+
+	// the API calls of the dashboard can't follow the login redirects
+	if req.Method != http.MethodGet || strings.HasPrefix(req.URL.Path, "/api/") {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	a.login(w, req)
+}
+
+// login redirects the user to the provider, with a state that's kept in a
+// cookie to be checked on the way back.
+func (a *oidcAuth) login(w http.ResponseWriter, req *http.Request) {
+	provider, err := a.getProvider()
+	if err != nil {
+		log.Errorf("failed to discover the OIDC provider: %s", err)
+		http.Error(w, "the OIDC provider is unavailable", http.StatusBadGateway)
+		return
+	}
+
+	state := oidcState{
+		State:    randomToken(),
+		Nonce:    randomToken(),
+		ReturnTo: req.URL.RequestURI(),
+		Expiry:   time.Now().Add(oidcLoginTimeout).Unix(),
+	}
+	value, err := a.signCookie(state)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	a.setCookie(w, oidcStateCookie, value, int(oidcLoginTimeout.Seconds()))
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", a.config.ClientID)
+	params.Set("redirect_uri", a.config.RedirectURL)
+	params.Set("scope", "openid profile email")
+	params.Set("state", state.State)
+	params.Set("nonce", state.Nonce)
+	separator := "?"
+	if strings.Contains(provider.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	http.Redirect(w, req, provider.AuthorizationEndpoint+separator+params.Encode(), http.StatusFound)
+}
+
+// handleCallback exchanges the authorization code that the provider
+// redirected the user with for an ID token, and starts a session once the
+// token is verified.
+func (a *oidcAuth) handleCallback(w http.ResponseWriter, req *http.Request) {
+	if errCode := req.FormValue("error"); errCode != "" {
+		http.Error(w, fmt.Sprintf("login failed: %s %s", errCode, req.FormValue("error_description")), http.StatusForbidden)
+		return
+	}
+
+	var state oidcState
+	cookie, err := req.Cookie(oidcStateCookie)
+	if err == nil {
+		err = a.verifyCookie(cookie.Value, &state)
+	}
+	if err != nil || state.State != req.FormValue("state") || time.Now().Unix() >= state.Expiry {
+		http.Error(w, "invalid or expired login state, please retry", http.StatusBadRequest)
+		return
+	}
+
+	claims, err := a.exchange(req.FormValue("code"), state.Nonce)
+	if err != nil {
+		log.Errorf("OIDC login failed: %s", err)
+		http.Error(w, "login failed", http.StatusForbidden)
+		return
+	}
+	subject, _ := claims["sub"].(string)
+	if !a.allowed(claims) {
+		log.Infof("OIDC login denied for %s: not a member of the allowed groups", subject)
+		http.Error(w, "you're not allowed to access the dashboard", http.StatusForbidden)
+		return
+	}
+
+	expiry, _ := claims["exp"].(float64)
+	session, err := a.signCookie(oidcSession{Subject: subject, Expiry: int64(expiry)})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	a.setCookie(w, oidcSessionCookie, session, int(time.Until(time.Unix(int64(expiry), 0)).Seconds()))
+	a.setCookie(w, oidcStateCookie, "", -1)
+
+	// only redirect to the dashboard itself
+	returnTo := state.ReturnTo
+	if !strings.HasPrefix(returnTo, "/") || strings.HasPrefix(returnTo, "//") {
+		returnTo = "/"
+	}
+	http.Redirect(w, req, returnTo, http.StatusFound)
+}
+
+// exchange redeems the authorization code at the token endpoint, and returns
+// the claims of the ID token.
+func (a *oidcAuth) exchange(code, nonce string) (map[string]interface{}, error) {
+	provider, err := a.getProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", a.config.RedirectURL)
+	tokenReq, err := http.NewRequest(http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	tokenReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	tokenReq.SetBasicAuth(url.QueryEscape(a.config.ClientID), url.QueryEscape(a.config.ClientSecret))
+
+	rsp, err := a.client.Do(tokenReq)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned %s", rsp.Status)
+	}
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.NewDecoder(rsp.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("token endpoint didn't return an ID token")
+	}
+	return a.verifyIDToken(provider, token.IDToken, nonce)
+}
+
+// verifyIDToken checks the signature of the ID token with the keys of the
+// provider, and that it was issued by the provider to the dashboard for this
+// login, and returns its claims.
+func (a *oidcAuth) verifyIDToken(provider *oidcProvider, raw, nonce string) (map[string]interface{}, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed ID token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed ID token header: %s", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed ID token signature: %s", err)
+	}
+
+	key, err := a.getKey(provider, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if header.Alg != "RS256" {
+			return nil, fmt.Errorf("unsupported ID token algorithm %s", header.Alg)
+		}
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature); err != nil {
+			return nil, errors.New("invalid ID token signature")
+		}
+	case *ecdsa.PublicKey:
+		if header.Alg != "ES256" || len(signature) != 64 {
+			return nil, fmt.Errorf("unsupported ID token algorithm %s", header.Alg)
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(k, digest[:], r, s) {
+			return nil, errors.New("invalid ID token signature")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported ID token key %s", header.Kid)
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed ID token claims: %s", err)
+	}
+	if claims["iss"] != provider.Issuer {
+		return nil, fmt.Errorf("ID token issued by %v instead of %s", claims["iss"], provider.Issuer)
+	}
+	if !containsString(stringsClaim(claims["aud"]), a.config.ClientID) {
+		return nil, fmt.Errorf("ID token not issued for client %s", a.config.ClientID)
+	}
+	if exp, ok := claims["exp"].(float64); !ok || time.Now().Unix() >= int64(exp) {
+		return nil, errors.New("ID token expired")
+	}
+	if claims["nonce"] != nonce {
+		return nil, errors.New("ID token issued for another login")
+	}
+	return claims, nil
+}
+
+// allowed returns true if the user is a member of one of the allowed groups,
+// or if the login isn't restricted to groups.
+func (a *oidcAuth) allowed(claims map[string]interface{}) bool {
+	if len(a.config.AllowedGroups) == 0 {
+		return true
+	}
+	groups := stringsClaim(claims[a.config.GroupsClaim])
+	for _, group := range a.config.AllowedGroups {
+		if containsString(groups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *oidcAuth) getProvider() (*oidcProvider, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.provider != nil {
+		return a.provider, nil
+	}
+
+	var provider oidcProvider
+	discoveryURL := strings.TrimSuffix(a.config.IssuerURL, "/") + "/.well-known/openid-configuration"
+	if err := a.getJSON(discoveryURL, &provider); err != nil {
+		return nil, err
+	}
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "" {
+		return nil, fmt.Errorf("incomplete discovery document at %s", discoveryURL)
+	}
+	a.provider = &provider
+	return a.provider, nil
+}
+
+// getKey returns the key of the provider with the given ID. The keys are
+// fetched again when the ID is unknown, since providers rotate their keys.
+func (a *oidcAuth) getKey(provider *oidcProvider, kid string) (crypto.PublicKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if key, ok := a.keys[kid]; ok {
+		return key, nil
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := a.getJSON(provider.JWKSURI, &jwks); err != nil {
+		return nil, err
+	}
+
+	a.keys = map[string]crypto.PublicKey{}
+	for _, k := range jwks.Keys {
+		switch {
+		case k.Kty == "RSA":
+			n, errN := base64.RawURLEncoding.DecodeString(k.N)
+			e, errE := base64.RawURLEncoding.DecodeString(k.E)
+			if errN != nil || errE != nil {
+				continue
+			}
+			a.keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case k.Kty == "EC" && k.Crv == "P-256":
+			x, errX := base64.RawURLEncoding.DecodeString(k.X)
+			y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+			if errX != nil || errY != nil {
+				continue
+			}
+			a.keys[k.Kid] = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		}
+	}
+
+	key, ok := a.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown ID token key %s", kid)
+	}
+	return key, nil
+}
+
+func (a *oidcAuth) getJSON(url string, v interface{}) error {
+	rsp, err := a.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, rsp.Status)
+	}
+	return json.NewDecoder(rsp.Body).Decode(v)
+}
+
+// signCookie encodes v as a cookie value, with an HMAC of the value.
+func (a *oidcAuth) signCookie(v interface{}) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(encoded))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func (a *oidcAuth) verifyCookie(value string, v interface{}) error {
+	parts := strings.Split(value, ".")
+	if len(parts) != 2 {
+		return errors.New("malformed cookie")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(parts[0]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return errors.New("invalid cookie signature")
+	}
+	return decodeSegment(parts[0], v)
+}
+
+func (a *oidcAuth) setCookie(w http.ResponseWriter, name, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   a.secure,
+		HttpOnly: true,
+	})
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	payload, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(payload, v)
+}
+
+// stringsClaim returns the values of a claim that's either a string or a
+// list of strings, such as "aud".
+func stringsClaim(claim interface{}) []string {
+	switch c := claim.(type) {
+	case string:
+		return []string{c}
+	case []interface{}:
+		values := []string{}
+		for _, v := range c {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
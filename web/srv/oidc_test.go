@@ -0,0 +1,183 @@
+package srv
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeOIDCProvider issues ID tokens signed with an RSA key, for the nonce of
+// the last authorization request.
+type fakeOIDCProvider struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	nonce  string
+	groups []string
+}
+
+func newFakeOIDCProvider(t *testing.T) *fakeOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	p := &fakeOIDCProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcProvider{
+			Issuer:                p.URL,
+			AuthorizationEndpoint: p.URL + "/authorize",
+			TokenEndpoint:         p.URL + "/token",
+			JWKSURI:               p.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, ok := r.BasicAuth(); !ok || id != "dashboard" || secret != "s3cr3t" {
+			http.Error(w, "invalid client", http.StatusUnauthorized)
+			return
+		}
+		if r.FormValue("code") != "the-code" {
+			http.Error(w, "invalid code", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": p.idToken(t)})
+	})
+	p.Server = httptest.NewServer(mux)
+	return p
+}
+
+func (p *fakeOIDCProvider) idToken(t *testing.T) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "key-1"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":    p.URL,
+		"aud":    "dashboard",
+		"sub":    "alice",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"nonce":  p.nonce,
+		"groups": p.groups,
+	})
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestOIDCAuth(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	defer provider.Close()
+
+	newAuth := func(allowedGroups ...string) *oidcAuth {
+		auth, err := newOIDCAuth(OIDCConfig{
+			IssuerURL:     provider.URL,
+			ClientID:      "dashboard",
+			ClientSecret:  "s3cr3t",
+			RedirectURL:   "https://dashboard.example.com/oidc/callback",
+			AllowedGroups: allowedGroups,
+		}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("dashboard"))
+		}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return auth
+	}
+
+	// login goes through the login flow for the given path, and returns the
+	// response to the callback
+	login := func(auth *oidcAuth, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		auth.ServeHTTP(recorder, httptest.NewRequest("GET", path, nil))
+		if recorder.Code != http.StatusFound {
+			t.Fatalf("Expected a redirect to the provider, got %d", recorder.Code)
+		}
+		location, err := url.Parse(recorder.Header().Get("Location"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !strings.HasPrefix(location.String(), provider.URL+"/authorize?") {
+			t.Fatalf("Expected a redirect to the provider, got %s", location)
+		}
+		provider.nonce = location.Query().Get("nonce")
+
+		callback := httptest.NewRequest("GET", "/oidc/callback?code=the-code&state="+location.Query().Get("state"), nil)
+		for _, cookie := range recorder.Result().Cookies() {
+			callback.AddCookie(cookie)
+		}
+		recorder = httptest.NewRecorder()
+		auth.ServeHTTP(recorder, callback)
+		return recorder
+	}
+
+	t.Run("Logs the users in and lets them through with their session", func(t *testing.T) {
+		auth := newAuth()
+		recorder := login(auth, "/namespaces?ns=emojivoto")
+		if recorder.Code != http.StatusFound || recorder.Header().Get("Location") != "/namespaces?ns=emojivoto" {
+			t.Fatalf("Expected a redirect to the requested page, got %d to %s", recorder.Code, recorder.Header().Get("Location"))
+		}
+
+		req := httptest.NewRequest("GET", "/api/tps-reports", nil)
+		for _, cookie := range recorder.Result().Cookies() {
+			if cookie.Name == oidcSessionCookie {
+				req.AddCookie(cookie)
+			}
+		}
+		recorder = httptest.NewRecorder()
+		auth.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusOK || recorder.Body.String() != "dashboard" {
+			t.Fatalf("Expected the request to be let through, got %d: %s", recorder.Code, recorder.Body.String())
+		}
+	})
+
+	t.Run("Only logs the members of the allowed groups in", func(t *testing.T) {
+		provider.groups = []string{"developers"}
+		defer func() { provider.groups = nil }()
+
+		if recorder := login(newAuth("operators"), "/"); recorder.Code != http.StatusForbidden {
+			t.Fatalf("Expected the login to be denied, got %d", recorder.Code)
+		}
+		if recorder := login(newAuth("operators", "developers"), "/"); recorder.Code != http.StatusFound {
+			t.Fatalf("Expected the login to succeed, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("Rejects the API requests without a session", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/tps-reports", nil)
+		req.AddCookie(&http.Cookie{Name: oidcSessionCookie, Value: "forged.session"})
+		recorder := httptest.NewRecorder()
+		newAuth().ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected the request to be rejected, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("Rejects callbacks without the login state", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		newAuth().ServeHTTP(recorder, httptest.NewRequest("GET", "/oidc/callback?code=the-code&state=guessed", nil))
+		if recorder.Code != http.StatusBadRequest {
+			t.Fatalf("Expected the callback to be rejected, got %d", recorder.Code)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package srv
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// hstsMaxAge is a year, in seconds
+const hstsMaxAge = 365 * 24 * 60 * 60
+
+// securityHandler is an HTTP middleware that sets the security headers of the
+// dashboard's responses, and rejects the state-changing requests that could be
+// forged by other sites.
+//
+// It also rejects the requests whose Host header doesn't match enforcedHost,
+// so that other sites can't reach the dashboard through DNS rebinding, by
+// resolving their own host to the dashboard's address.
+//
+// The state-changing requests must come from the dashboard's origin. The
+// dashboard's own API only serves safe methods, so these are the requests
+// proxied to Grafana.
+type securityHandler struct {
+	next         http.Handler
+	enforcedHost *regexp.Regexp
+
+	// connectSrc are the origins, besides the dashboard's, that the dashboard's
+	// scripts connect to
+	connectSrc []string
+}
+
+func newSecurityHandler(enforcedHost *regexp.Regexp, versionCheckURL string, next http.Handler) *securityHandler {
+	handler := &securityHandler{next: next, enforcedHost: enforcedHost}
+	if u, err := url.Parse(versionCheckURL); err == nil && u.Host != "" {
+		handler.connectSrc = append(handler.connectSrc, fmt.Sprintf("%s://%s", u.Scheme, u.Host))
+	}
+	return handler
+}
+
+func (h *securityHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !h.enforcedHost.MatchString(req.Host) {
+		http.Error(w, fmt.Sprintf("The host '%s' doesn't match /%s/ and has been denied for security reasons; the dashboard's web server must be started with an -enforced-host regexp that matches it", req.Host, h.enforcedHost), http.StatusBadRequest)
+		return
+	}
+
+	header := w.Header()
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("X-Frame-Options", "SAMEORIGIN")
+	header.Set("Referrer-Policy", "same-origin")
+	if req.TLS != nil {
+		header.Set("Strict-Transport-Security", fmt.Sprintf("max-age=%d", hstsMaxAge))
+	}
+	// Grafana's pages have inline scripts, and come with their own policy
+	if !isGrafanaPath(req.URL.Path) {
+		header.Set("Content-Security-Policy", h.contentSecurityPolicy(req))
+	}
+
+	if !isSafeMethod(req.Method) && !isSameOrigin(req) {
+		http.Error(w, "cross-origin requests can't change the dashboard's state", http.StatusForbidden)
+		return
+	}
+
+	h.next.ServeHTTP(w, req)
+}
+
+func (h *securityHandler) contentSecurityPolicy(req *http.Request) string {
+	// the tap websockets aren't covered by 'self' in all the browsers
+	connectSrc := append([]string{"'self'", "ws://" + req.Host, "wss://" + req.Host}, h.connectSrc...)
+	return strings.Join([]string{
+		"default-src 'self'",
+		"script-src 'self'",
+		"style-src 'self' 'unsafe-inline' https://fonts.googleapis.com",
+		"font-src 'self' https://fonts.gstatic.com",
+		"img-src 'self' data:",
+		"connect-src " + strings.Join(connectSrc, " "),
+		"frame-ancestors 'self'",
+		"object-src 'none'",
+		"base-uri 'self'",
+	}, "; ")
+}
+
+// isSameOrigin returns true if the Origin header of req, or its Referer if
+// the browser didn't send one, is the dashboard's.
+func isSameOrigin(req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		origin = req.Header.Get("Referer")
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host != "" && u.Host == req.Host
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+func isGrafanaPath(path string) bool {
+	return path == "/grafana" || strings.HasPrefix(path, "/grafana/")
+}
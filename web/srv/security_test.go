@@ -0,0 +1,89 @@
+package srv
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSecurityHandler(t *testing.T) {
+	handler := newSecurityHandler(regexp.MustCompile(`^dashboard\.example\.com$`), "https://versioncheck.example.com/version.json", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("dashboard"))
+	}))
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("Sets the security headers", func(t *testing.T) {
+		recorder := serve(httptest.NewRequest("GET", "http://dashboard.example.com/namespaces", nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected the request to be let through, got %d", recorder.Code)
+		}
+
+		header := recorder.Header()
+		if header.Get("X-Frame-Options") != "SAMEORIGIN" || header.Get("X-Content-Type-Options") != "nosniff" {
+			t.Fatalf("Unexpected headers: %+v", header)
+		}
+		csp := header.Get("Content-Security-Policy")
+		for _, directive := range []string{
+			"script-src 'self'",
+			"connect-src 'self' ws://dashboard.example.com wss://dashboard.example.com https://versioncheck.example.com",
+			"frame-ancestors 'self'",
+		} {
+			if !strings.Contains(csp, directive) {
+				t.Fatalf("Expected the Content-Security-Policy to contain %q, got %q", directive, csp)
+			}
+		}
+		if header.Get("Strict-Transport-Security") != "" {
+			t.Fatalf("Expected no HSTS header over HTTP, got %q", header.Get("Strict-Transport-Security"))
+		}
+	})
+
+	t.Run("Sets the HSTS header over HTTPS", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "https://dashboard.example.com/", nil)
+		req.TLS = &tls.ConnectionState{}
+		if hsts := serve(req).Header().Get("Strict-Transport-Security"); hsts != "max-age=31536000" {
+			t.Fatalf("Unexpected HSTS header: %q", hsts)
+		}
+	})
+
+	t.Run("Leaves the Content-Security-Policy of Grafana to Grafana", func(t *testing.T) {
+		recorder := serve(httptest.NewRequest("GET", "http://dashboard.example.com/grafana/d/linkerd-deployment", nil))
+		if csp := recorder.Header().Get("Content-Security-Policy"); csp != "" {
+			t.Fatalf("Expected no Content-Security-Policy, got %q", csp)
+		}
+	})
+
+	t.Run("Checks the origin of the state-changing requests", func(t *testing.T) {
+		newReq := func(origin string) *http.Request {
+			req := httptest.NewRequest("POST", "http://dashboard.example.com/grafana/api/frontend-metrics", nil)
+			req.Header.Set("Origin", origin)
+			return req
+		}
+
+		if code := serve(newReq("https://evil.example.com")).Code; code != http.StatusForbidden {
+			t.Fatalf("Expected the cross-origin request to be rejected, got %d", code)
+		}
+		if code := serve(newReq("http://dashboard.example.com")).Code; code != http.StatusOK {
+			t.Fatalf("Expected the same-origin request to be let through, got %d", code)
+		}
+
+		req := newReq("")
+		req.Header.Set("Referer", "http://dashboard.example.com/grafana/d/linkerd-deployment")
+		if code := serve(req).Code; code != http.StatusOK {
+			t.Fatalf("Expected the request referred by the dashboard to be let through, got %d", code)
+		}
+	})
+
+	t.Run("Rejects the requests for other hosts", func(t *testing.T) {
+		if code := serve(httptest.NewRequest("GET", "http://rebound.example.com/namespaces", nil)).Code; code != http.StatusBadRequest {
+			t.Fatalf("Expected the request to be rejected, got %d", code)
+		}
+	})
+}
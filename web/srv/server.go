@@ -1,10 +1,13 @@
 package srv
 
 import (
+	"fmt"
 	"html/template"
 	"net/http"
 	"path"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/julienschmidt/httprouter"
@@ -33,8 +36,11 @@ type (
 	appParams struct {
 		Data                pb.VersionInfo
 		UUID                string
+		VersionCheckURL     string
 		ControllerNamespace string
 		SingleNamespace     bool
+		GrafanaURL          string
+		GrafanaUIDPrefix    string
 		Error               bool
 		ErrorMessage        string
 		PathPrefix          string
@@ -48,18 +54,39 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 
 // NewServer returns an initialized `http.Server`, configured to listen on an
 // address, render templates, and serve static assets, for a given Linkerd
-// control plane.
+// control plane. The dashboard links to the Grafana at grafanaURL if it's set,
+// and otherwise to the one at grafanaAddr, which it proxies under /grafana.
+// When oidcConfig is set, the users must log in with its OpenID Connect
+// provider. The responses get the dashboard's security headers, and the
+// state-changing requests must come from the dashboard's origin. The requests
+// whose Host header doesn't match the enforcedHost regexp are rejected. The
+// websockets of the tap and top views are bounded and buffered by
+// websocketConfig.
 func NewServer(
 	addr string,
 	grafanaAddr string,
+	grafanaURL string,
+	grafanaUIDPrefix string,
 	templateDir string,
 	staticDir string,
 	uuid string,
+	versionCheckURL string,
 	controllerNamespace string,
 	singleNamespace bool,
 	reload bool,
+	enforcedHost string,
+	oidcConfig *OIDCConfig,
+	websocketConfig WebsocketConfig,
 	apiClient pb.ApiClient,
-) *http.Server {
+) (*http.Server, error) {
+	enforcedHostRegexp, err := regexp.Compile(enforcedHost)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enforced host regexp %s: %s", enforcedHost, err)
+	}
+	if err := websocketConfig.validate(); err != nil {
+		return nil, err
+	}
+
 	server := &Server{
 		templateDir: templateDir,
 		reload:      reload,
@@ -76,9 +103,16 @@ func NewServer(
 		apiClient:           apiClient,
 		render:              server.RenderTemplate,
 		uuid:                uuid,
+		versionCheckURL:     versionCheckURL,
 		controllerNamespace: controllerNamespace,
 		singleNamespace:     singleNamespace,
-		grafanaProxy:        newGrafanaProxy(grafanaAddr),
+		grafanaURL:          strings.TrimSuffix(grafanaURL, "/"),
+		grafanaUIDPrefix:    grafanaUIDPrefix,
+		websocketConfig:     websocketConfig,
+		websockets:          newWebsocketLimiter(websocketConfig.MaxConnections),
+	}
+	if grafanaURL == "" {
+		handler.grafanaProxy = newGrafanaProxy(grafanaAddr)
 	}
 
 	httpServer := &http.Server{
@@ -87,6 +121,14 @@ func NewServer(
 		WriteTimeout: timeout,
 		Handler:      wrappedServer,
 	}
+	if oidcConfig != nil {
+		auth, err := newOIDCAuth(*oidcConfig, wrappedServer)
+		if err != nil {
+			return nil, err
+		}
+		httpServer.Handler = auth
+	}
+	httpServer.Handler = newSecurityHandler(enforcedHostRegexp, versionCheckURL, httpServer.Handler)
 
 	// webapp routes
 	server.router.GET("/", handler.handleIndex)
@@ -119,16 +161,18 @@ func NewServer(
 	server.router.GET("/api/tap", handler.handleAPITap)
 	server.router.GET("/api/routes", handler.handleAPITopRoutes)
 
-	// grafana proxy
-	server.router.DELETE("/grafana/*grafanapath", handler.handleGrafana)
-	server.router.GET("/grafana/*grafanapath", handler.handleGrafana)
-	server.router.HEAD("/grafana/*grafanapath", handler.handleGrafana)
-	server.router.OPTIONS("/grafana/*grafanapath", handler.handleGrafana)
-	server.router.PATCH("/grafana/*grafanapath", handler.handleGrafana)
-	server.router.POST("/grafana/*grafanapath", handler.handleGrafana)
-	server.router.PUT("/grafana/*grafanapath", handler.handleGrafana)
+	// grafana proxy, unless the dashboard links to an existing Grafana
+	if handler.grafanaProxy != nil {
+		server.router.DELETE("/grafana/*grafanapath", handler.handleGrafana)
+		server.router.GET("/grafana/*grafanapath", handler.handleGrafana)
+		server.router.HEAD("/grafana/*grafanapath", handler.handleGrafana)
+		server.router.OPTIONS("/grafana/*grafanapath", handler.handleGrafana)
+		server.router.PATCH("/grafana/*grafanapath", handler.handleGrafana)
+		server.router.POST("/grafana/*grafanapath", handler.handleGrafana)
+		server.router.PUT("/grafana/*grafanapath", handler.handleGrafana)
+	}
 
-	return httpServer
+	return httpServer, nil
 }
 
 // RenderTemplate writes a rendered template into a buffer, given an HTTP
@@ -0,0 +1,221 @@
+package srv
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// SlowClientDrop drops the oldest buffered messages of the websockets
+	// whose browser doesn't keep up with their stream.
+	SlowClientDrop = "drop"
+	// SlowClientClose closes the websockets whose browser doesn't keep up
+	// with their stream.
+	SlowClientClose = "close"
+
+	// websocketWriteTimeout bounds each write to a websocket, so that the
+	// browsers that stop reading are found out even when nothing is dropped
+	websocketWriteTimeout = 10 * time.Second
+)
+
+// WebsocketConfig configures the websockets of the tap and top views of the
+// dashboard, which stream the tap events to the browsers.
+type WebsocketConfig struct {
+	// MaxConnections bounds the number of open websockets; the browsers that
+	// open more are told to try again later. 0 means no bound
+	MaxConnections int
+
+	// SendBuffer is the number of messages buffered for each websocket, so
+	// that a slow browser holds up its own stream and not the tap pipeline
+	SendBuffer int
+
+	// SlowClientPolicy is what's done with the websockets whose buffer is
+	// full: SlowClientDrop or SlowClientClose
+	SlowClientPolicy string
+
+	// PingInterval is the interval of the pings sent on the websockets; the
+	// ones whose browser doesn't answer within two intervals are closed
+	PingInterval time.Duration
+}
+
+// DefaultWebsocketConfig returns the configuration of the websockets of the
+// dashboard when its flags aren't set.
+func DefaultWebsocketConfig() WebsocketConfig {
+	return WebsocketConfig{
+		MaxConnections:   100,
+		SendBuffer:       256,
+		SlowClientPolicy: SlowClientDrop,
+		PingInterval:     30 * time.Second,
+	}
+}
+
+func (c WebsocketConfig) validate() error {
+	if c.MaxConnections < 0 {
+		return fmt.Errorf("invalid websocket max connections %d: must not be negative", c.MaxConnections)
+	}
+	if c.SendBuffer < 1 {
+		return fmt.Errorf("invalid websocket send buffer %d: must be at least 1", c.SendBuffer)
+	}
+	if c.SlowClientPolicy != SlowClientDrop && c.SlowClientPolicy != SlowClientClose {
+		return fmt.Errorf("invalid websocket slow client policy %s: must be %s or %s", c.SlowClientPolicy, SlowClientDrop, SlowClientClose)
+	}
+	if c.PingInterval <= 0 {
+		return fmt.Errorf("invalid websocket ping interval %s: must be positive", c.PingInterval)
+	}
+	return nil
+}
+
+// websocketLimiter bounds the number of open websockets. The nil limiter
+// doesn't bound them.
+type websocketLimiter struct {
+	slots chan struct{}
+}
+
+func newWebsocketLimiter(max int) *websocketLimiter {
+	if max == 0 {
+		return nil
+	}
+	return &websocketLimiter{slots: make(chan struct{}, max)}
+}
+
+// acquire returns false if all the websockets are taken, and otherwise takes
+// one, to be released once it's closed.
+func (l *websocketLimiter) acquire() bool {
+	if l == nil {
+		return true
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *websocketLimiter) release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
+
+// websocketStream writes the messages of a stream to a websocket from a
+// buffer of its own, and keeps the websocket alive with pings. The messages
+// are enqueued by a single producer, which never waits on the browser: when
+// the buffer is full, the slow client policy drops the oldest messages or
+// closes the websocket.
+type websocketStream struct {
+	ws     *websocket.Conn
+	config WebsocketConfig
+	send   chan []byte
+
+	// done is closed once the websocket is closed
+	done      chan struct{}
+	closeOnce sync.Once
+
+	dropped uint64
+}
+
+func newWebsocketStream(ws *websocket.Conn, config WebsocketConfig) *websocketStream {
+	s := &websocketStream{
+		ws:     ws,
+		config: config,
+		send:   make(chan []byte, config.SendBuffer),
+		done:   make(chan struct{}),
+	}
+	if ws != nil {
+		ws.SetReadLimit(int64(maxMessageSize))
+		s.extendReadDeadline()
+		ws.SetPongHandler(func(string) error {
+			s.extendReadDeadline()
+			return nil
+		})
+	}
+	return s
+}
+
+// extendReadDeadline gives the browser two ping intervals to answer the next
+// ping, or to send its next message.
+func (s *websocketStream) extendReadDeadline() {
+	s.ws.SetReadDeadline(time.Now().Add(2 * s.config.PingInterval))
+}
+
+// enqueue buffers msg to be written to the websocket. It returns false if the
+// websocket is closed, or was closed by the slow client policy.
+func (s *websocketStream) enqueue(msg []byte) bool {
+	for {
+		select {
+		case <-s.done:
+			return false
+		case s.send <- msg:
+			return true
+		default:
+		}
+
+		if s.config.SlowClientPolicy == SlowClientClose {
+			s.closeWithError(websocket.ClosePolicyViolation, "the client doesn't read the stream fast enough")
+			return false
+		}
+		select {
+		case <-s.send:
+			atomic.AddUint64(&s.dropped, 1)
+		default:
+		}
+	}
+}
+
+// writeLoop writes the buffered messages and the pings to the websocket,
+// until it's closed or a write fails.
+func (s *websocketStream) writeLoop() {
+	ticker := time.NewTicker(s.config.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case msg := <-s.send:
+			s.ws.SetWriteDeadline(time.Now().Add(websocketWriteTimeout))
+			if err := s.ws.WriteMessage(websocket.TextMessage, msg); err != nil {
+				if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure) {
+					log.Errorf("failed to write to the websocket: %s", err)
+				}
+				s.close()
+				return
+			}
+		case <-ticker.C:
+			if err := s.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(websocketWriteTimeout)); err != nil {
+				log.Debugf("failed to ping the websocket: %s", err)
+				s.close()
+				return
+			}
+		}
+	}
+}
+
+// closeWithError sends a close frame with the code and message to the
+// browser, then closes the websocket.
+func (s *websocketStream) closeWithError(code int, msg string) {
+	websocketError(s.ws, code, msg)
+	s.close()
+}
+
+// close closes the websocket, which stops the write loop and fails the reads
+// of the handler.
+func (s *websocketStream) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		if s.ws != nil {
+			s.ws.Close()
+		}
+	})
+}
+
+func (s *websocketStream) droppedCount() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
@@ -0,0 +1,151 @@
+package srv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/linkerd/linkerd2/controller/api/public"
+	"github.com/linkerd/linkerd2/controller/api/util"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+)
+
+func newTapServer(config WebsocketConfig, events []pb.TapEvent) *httptest.Server {
+	h := &handler{
+		apiClient: &public.MockAPIClient{
+			APITapByResourceClientToReturn: &public.MockAPITapByResourceClient{TapEventsToReturn: events},
+		},
+		websocketConfig: config,
+		websockets:      newWebsocketLimiter(config.MaxConnections),
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		h.handleAPITap(w, req, nil)
+	}))
+}
+
+func websocketURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestHandleAPITap(t *testing.T) {
+	t.Run("Streams the tap events to the websocket", func(t *testing.T) {
+		server := newTapServer(DefaultWebsocketConfig(), []pb.TapEvent{pb.TapEvent{}, pb.TapEvent{}})
+		defer server.Close()
+
+		ws, _, err := websocket.DefaultDialer.Dial(websocketURL(server), nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		defer ws.Close()
+
+		if err := ws.WriteJSON(util.TapRequestParams{Resource: "deploy/web", Namespace: "emojivoto"}); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+		for i := 0; i < 2; i++ {
+			messageType, _, err := ws.ReadMessage()
+			if err != nil {
+				t.Fatalf("Expected 2 tap events, got %d: %s", i, err)
+			}
+			if messageType != websocket.TextMessage {
+				t.Fatalf("Expected a text message, got type %d", messageType)
+			}
+		}
+	})
+
+	t.Run("Rejects the websockets past the max connections", func(t *testing.T) {
+		config := DefaultWebsocketConfig()
+		config.MaxConnections = 1
+		server := newTapServer(config, nil)
+		defer server.Close()
+
+		ws, _, err := websocket.DefaultDialer.Dial(websocketURL(server), nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		defer ws.Close()
+
+		_, rsp, err := websocket.DefaultDialer.Dial(websocketURL(server), nil)
+		if err == nil {
+			t.Fatal("Expected the second websocket to be rejected")
+		}
+		if rsp == nil || rsp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("Expected status %d, got %v", http.StatusServiceUnavailable, rsp)
+		}
+	})
+}
+
+func TestWebsocketStream(t *testing.T) {
+	t.Run("Drops the oldest messages when the buffer is full", func(t *testing.T) {
+		config := DefaultWebsocketConfig()
+		config.SendBuffer = 2
+		stream := newWebsocketStream(nil, config)
+
+		for _, msg := range []string{"1", "2", "3"} {
+			if !stream.enqueue([]byte(msg)) {
+				t.Fatalf("Expected message %s to be enqueued", msg)
+			}
+		}
+
+		if dropped := stream.droppedCount(); dropped != 1 {
+			t.Fatalf("Expected 1 dropped message, got %d", dropped)
+		}
+		for _, expected := range []string{"2", "3"} {
+			if msg := string(<-stream.send); msg != expected {
+				t.Fatalf("Expected message %s, got %s", expected, msg)
+			}
+		}
+	})
+
+	t.Run("Closes the websocket when the buffer is full with the close policy", func(t *testing.T) {
+		config := DefaultWebsocketConfig()
+		config.SendBuffer = 1
+		config.SlowClientPolicy = SlowClientClose
+
+		streams := make(chan *websocketStream, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ws, err := websocketUpgrader.Upgrade(w, req, nil)
+			if err != nil {
+				return
+			}
+			streams <- newWebsocketStream(ws, config)
+		}))
+		defer server.Close()
+
+		ws, _, err := websocket.DefaultDialer.Dial(websocketURL(server), nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		defer ws.Close()
+		stream := <-streams
+
+		if !stream.enqueue([]byte("1")) {
+			t.Fatal("Expected the first message to be enqueued")
+		}
+		if stream.enqueue([]byte("2")) {
+			t.Fatal("Expected the websocket to be closed")
+		}
+
+		ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, _, err = ws.ReadMessage()
+		if !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+			t.Fatalf("Expected a policy violation close error, got %v", err)
+		}
+	})
+}
+
+func TestWebsocketConfigValidate(t *testing.T) {
+	config := DefaultWebsocketConfig()
+	if err := config.validate(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	config.SlowClientPolicy = "block"
+	expectedError := "invalid websocket slow client policy block: must be drop or close"
+	if err := config.validate(); err == nil || err.Error() != expectedError {
+		t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+	}
+}